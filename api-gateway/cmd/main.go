@@ -4,11 +4,17 @@ import (
 	"api-gateway/config"
 	"api-gateway/internal/domain"
 	"api-gateway/internal/handler"
+	"api-gateway/internal/middleware"
 	"api-gateway/internal/repository"
+	"api-gateway/internal/repository/kafka"
 	"api-gateway/internal/router"
 	"api-gateway/internal/service"
+	"api-gateway/pkg/circuitbreaker"
+	"api-gateway/pkg/errorreport"
 	"api-gateway/pkg/logger"
 	"api-gateway/pkg/redis"
+	"api-gateway/pkg/stats"
+	"api-gateway/pkg/validation"
 	"context"
 	"fmt"
 	"log"
@@ -64,9 +70,31 @@ func main() {
 
 	appLogger.Info("Starting API Gateway...")
 
+	// Report recovered panics to Sentry (disabled without a DSN)
+	if err := errorreport.Init(&cfg.Sentry, "api-gateway"); err != nil {
+		appLogger.Warn("Failed to initialize error reporting, panics are only logged", zap.Error(err))
+	}
+	defer errorreport.Flush(2 * time.Second)
+
+	// Field-level binding errors and the vnphone/slug rules
+	if err := validation.Register(); err != nil {
+		appLogger.Fatal("Failed to register request validators", zap.Error(err))
+	}
+
+	// CORS and security headers, reloaded when config.yaml changes
+	httpPolicy := middleware.NewHTTPPolicy(cfg)
+	config.WatchConfig(func(newCfg *config.Config) {
+		httpPolicy.Update(newCfg)
+		appLogger.Info("HTTP policy reloaded",
+			zap.String("environment", newCfg.Server.Environment),
+			zap.Strings("allowed_origins", httpPolicy.AllowedOrigins()),
+		)
+	})
+
 	// Debug: Log CORS configuration
 	appLogger.Info("CORS Configuration",
-		zap.Strings("allowed_origins", cfg.CORS.AllowedOrigins),
+		zap.String("environment", cfg.Server.Environment),
+		zap.Strings("allowed_origins", httpPolicy.AllowedOrigins()),
 		zap.Strings("allowed_methods", cfg.CORS.AllowedMethods),
 		zap.Strings("allowed_headers", cfg.CORS.AllowedHeaders),
 		zap.Strings("expose_headers", cfg.CORS.ExposeHeaders),
@@ -114,16 +142,47 @@ func main() {
 		BaseURL:         baseURL,
 		HealthCheckPath: productServiceConfig.HealthCheckPath,
 		Routes: []domain.Route{
-			{Path: "/api/v1/products", Methods: []string{"GET", "POST"}, RequireAuth: false},
+			{Path: "/api/v1/products", Methods: []string{"GET"}, RequireAuth: false},
+			{Path: "/api/v1/products", Methods: []string{"POST"}, RequireAuth: true},
 			{Path: "/api/v1/products/:id", Methods: []string{"GET"}, RequireAuth: false},
 			{Path: "/api/v1/products/:id", Methods: []string{"PUT", "DELETE"}, RequireAuth: true},
 			{Path: "/api/v1/products/search", Methods: []string{"GET"}, RequireAuth: false},
+			{Path: "/api/v1/products/slug/:slug", Methods: []string{"GET"}, RequireAuth: false},
+			{Path: "/api/v1/products/batch", Methods: []string{"GET"}, RequireAuth: false},
 			{Path: "/api/v1/products/:id/inventory", Methods: []string{"PATCH"}, RequireAuth: true},
 			{Path: "/api/v1/categories", Methods: []string{"GET", "POST"}, RequireAuth: false},
 			{Path: "/api/v1/categories/:id", Methods: []string{"GET", "PUT", "DELETE"}, RequireAuth: false},
 			{Path: "/api/v1/categories/slug/:slug", Methods: []string{"GET"}, RequireAuth: false},
+			{Path: "/api/v1/categories/batch", Methods: []string{"GET"}, RequireAuth: false},
 			{Path: "/api/v1/categories/:id/children", Methods: []string{"GET"}, RequireAuth: false},
 			{Path: "/api/v1/categories/:id/products", Methods: []string{"GET"}, RequireAuth: false},
+			{Path: "/api/v1/content/home", Methods: []string{"GET"}, RequireAuth: false},
+			{Path: "/api/v1/admin/banners", Methods: []string{"GET", "POST"}, RequireAuth: true},
+			{Path: "/api/v1/admin/banners/:id", Methods: []string{"PUT", "DELETE"}, RequireAuth: true},
+			{Path: "/api/v1/admin/campaigns", Methods: []string{"GET", "POST"}, RequireAuth: true},
+			{Path: "/api/v1/admin/campaigns/:id", Methods: []string{"PUT", "DELETE"}, RequireAuth: true},
+			{Path: "/api/v1/feeds/products", Methods: []string{"GET"}, RequireAuth: false},
+			{Path: "/api/v1/admin/jobs/product/*path", Methods: []string{"GET", "POST", "DELETE"}, RequireAuth: true},
+			{Path: "/api/v1/admin/log-level/product", Methods: []string{"GET", "PUT"}, RequireAuth: true},
+			{Path: "/api/v1/admin/tasks/product", Methods: []string{"GET"}, RequireAuth: true},
+			{Path: "/api/v1/admin/events/product", Methods: []string{"GET"}, RequireAuth: true},
+			{Path: "/api/v1/admin/events/product/flush", Methods: []string{"POST"}, RequireAuth: true},
+			{Path: "/api/v1/admin/inventory/reconciliation", Methods: []string{"GET"}, RequireAuth: true},
+			{Path: "/api/v1/admin/inventory/reconciliation/run", Methods: []string{"POST"}, RequireAuth: true},
+			{Path: "/api/v1/admin/catalog-quality", Methods: []string{"GET"}, RequireAuth: true},
+			{Path: "/api/v1/admin/catalog-quality/shops", Methods: []string{"GET"}, RequireAuth: true},
+			{Path: "/api/v1/admin/catalog-quality/shops/:shop_id", Methods: []string{"GET"}, RequireAuth: true},
+			{Path: "/api/v1/admin/catalog-quality/run", Methods: []string{"POST"}, RequireAuth: true},
+			{Path: "/api/v1/admin/products/search", Methods: []string{"GET"}, RequireAuth: true},
+			{Path: "/api/v1/admin/products/moderate", Methods: []string{"POST"}, RequireAuth: true},
+			{Path: "/api/v1/seller/inventory/export", Methods: []string{"GET"}, RequireAuth: true},
+			{Path: "/api/v1/seller/inventory/import", Methods: []string{"POST"}, RequireAuth: true},
+			{Path: "/api/v1/seller/inventory/import/:id/commit", Methods: []string{"POST"}, RequireAuth: true},
+			{Path: "/api/v1/products/:id/reviews", Methods: []string{"GET"}, RequireAuth: false},
+			{Path: "/api/v1/reviews", Methods: []string{"POST"}, RequireAuth: true},
+			{Path: "/api/v1/reviews/:id", Methods: []string{"PUT", "DELETE"}, RequireAuth: true},
+			{Path: "/api/v1/shops/:id/reviews", Methods: []string{"GET"}, RequireAuth: false},
+			{Path: "/api/v1/shops/:id/rating", Methods: []string{"GET"}, RequireAuth: false},
 		},
 	}
 
@@ -165,9 +224,33 @@ func main() {
 				{Path: "/api/v1/auth/login", Methods: []string{"POST"}, RequireAuth: false},
 				{Path: "/api/v1/users/profile", Methods: []string{"GET", "PUT"}, RequireAuth: true},
 				{Path: "/api/v1/users/password", Methods: []string{"PUT"}, RequireAuth: true},
+				{Path: "/api/v1/users/me/notification-preferences", Methods: []string{"GET", "PUT"}, RequireAuth: true},
+				{Path: "/api/v1/users/me/devices", Methods: []string{"GET", "POST"}, RequireAuth: true},
+				{Path: "/api/v1/users/me/devices/:device_id", Methods: []string{"DELETE"}, RequireAuth: true},
 				{Path: "/api/v1/addresses", Methods: []string{"GET", "POST"}, RequireAuth: true},
 				{Path: "/api/v1/addresses/:id", Methods: []string{"GET", "PUT", "DELETE"}, RequireAuth: true},
 				{Path: "/api/v1/addresses/:id/default", Methods: []string{"PUT"}, RequireAuth: true},
+				{Path: "/api/v1/shops", Methods: []string{"GET"}, RequireAuth: false},
+				{Path: "/api/v1/admin/settings", Methods: []string{"GET"}, RequireAuth: true},
+				{Path: "/api/v1/admin/settings/:scope/:key", Methods: []string{"GET", "PUT"}, RequireAuth: true},
+				{Path: "/api/v1/admin/settings/:scope/:key/audit", Methods: []string{"GET"}, RequireAuth: true},
+				{Path: "/api/v1/admin/feature-flags", Methods: []string{"GET"}, RequireAuth: true},
+				{Path: "/api/v1/admin/feature-flags/:key", Methods: []string{"GET", "PUT", "DELETE"}, RequireAuth: true},
+				{Path: "/api/v1/admin/security-events", Methods: []string{"GET"}, RequireAuth: true},
+				{Path: "/api/v1/admin/email-templates", Methods: []string{"GET"}, RequireAuth: true},
+				{Path: "/api/v1/admin/email-templates/preview", Methods: []string{"POST"}, RequireAuth: true},
+				{Path: "/api/v1/admin/email-templates/:key/:locale", Methods: []string{"GET", "PUT"}, RequireAuth: true},
+				{Path: "/api/v1/admin/email-templates/:key/:locale/versions", Methods: []string{"GET"}, RequireAuth: true},
+				{Path: "/api/v1/admin/email-templates/:key/:locale/versions/:version/activate", Methods: []string{"POST"}, RequireAuth: true},
+				{Path: "/api/v1/admin/impersonations", Methods: []string{"GET", "POST"}, RequireAuth: true},
+				{Path: "/api/v1/admin/impersonations/:id", Methods: []string{"DELETE"}, RequireAuth: true},
+				{Path: "/api/v1/admin/users/export", Methods: []string{"GET"}, RequireAuth: true},
+				{Path: "/api/v1/admin/shops/export", Methods: []string{"GET"}, RequireAuth: true},
+				{Path: "/api/v1/feature-flags", Methods: []string{"GET"}, RequireAuth: true},
+				{Path: "/api/v1/admin/jobs/identity/*path", Methods: []string{"GET", "POST", "DELETE"}, RequireAuth: true},
+				{Path: "/api/v1/admin/log-level/identity", Methods: []string{"GET", "PUT"}, RequireAuth: true},
+				{Path: "/api/v1/shops/slug/:slug", Methods: []string{"GET"}, RequireAuth: false},
+				{Path: "/api/v1/shops/:id", Methods: []string{"GET"}, RequireAuth: false},
 			},
 		}
 
@@ -199,6 +282,8 @@ func main() {
 			HealthCheckPath: searchServiceConfig.HealthCheckPath,
 			Routes: []domain.Route{
 				{Path: "/api/v1/search", Methods: []string{"GET"}, RequireAuth: false},
+				{Path: "/api/v1/search/feedback", Methods: []string{"POST"}, RequireAuth: false},
+				{Path: "/api/v1/admin/log-level/search", Methods: []string{"GET", "PUT"}, RequireAuth: true},
 			},
 		}
 
@@ -226,6 +311,25 @@ func main() {
 				{Path: "/api/v1/cart", Methods: []string{"GET", "DELETE"}, RequireAuth: false},
 				{Path: "/api/v1/cart/items", Methods: []string{"POST"}, RequireAuth: false},
 				{Path: "/api/v1/cart/items/:product_id", Methods: []string{"PUT", "DELETE"}, RequireAuth: false},
+				{Path: "/api/v1/cart/price-changes/acknowledge", Methods: []string{"POST"}, RequireAuth: false},
+				{Path: "/api/v1/notifications", Methods: []string{"GET"}, RequireAuth: true},
+				{Path: "/api/v1/notifications/unread-count", Methods: []string{"GET"}, RequireAuth: true},
+				{Path: "/api/v1/notifications/read-all", Methods: []string{"PUT"}, RequireAuth: true},
+				{Path: "/api/v1/notifications/:id/read", Methods: []string{"PUT"}, RequireAuth: true},
+				{Path: "/api/v1/disputes", Methods: []string{"GET", "POST"}, RequireAuth: true},
+				{Path: "/api/v1/disputes/:id", Methods: []string{"GET"}, RequireAuth: true},
+				{Path: "/api/v1/disputes/:id/evidence", Methods: []string{"POST"}, RequireAuth: true},
+				{Path: "/api/v1/admin/disputes", Methods: []string{"GET"}, RequireAuth: true},
+				{Path: "/api/v1/admin/disputes/:id/review", Methods: []string{"POST"}, RequireAuth: true},
+				{Path: "/api/v1/admin/disputes/:id/resolve", Methods: []string{"POST"}, RequireAuth: true},
+				{Path: "/api/v1/admin/announcements", Methods: []string{"GET", "POST"}, RequireAuth: true},
+				{Path: "/api/v1/admin/announcements/:id", Methods: []string{"GET"}, RequireAuth: true},
+				{Path: "/api/v1/admin/announcements/:id/cancel", Methods: []string{"POST"}, RequireAuth: true},
+				{Path: "/api/v1/admin/jobs/order/*path", Methods: []string{"GET", "POST", "DELETE"}, RequireAuth: true},
+				{Path: "/api/v1/admin/log-level/order", Methods: []string{"GET", "PUT"}, RequireAuth: true},
+				{Path: "/api/v1/admin/tasks/order", Methods: []string{"GET"}, RequireAuth: true},
+				{Path: "/api/v1/admin/events/order", Methods: []string{"GET"}, RequireAuth: true},
+				{Path: "/api/v1/admin/events/order/flush", Methods: []string{"POST"}, RequireAuth: true},
 			},
 		}
 
@@ -235,15 +339,78 @@ func main() {
 		appLogger.Info("Order service registered", zap.String("base_url", orderBaseURL))
 	}
 
+	// Payment Service
+	if paymentServiceConfig, ok := cfg.Services["payment_service"]; ok {
+		paymentBaseURL := paymentServiceConfig.BaseURL
+		if paymentBaseURL == "" {
+			paymentBaseURL = "http://localhost:8006"
+			appLogger.Warn("Using default base URL for payment service", zap.String("url", paymentBaseURL))
+		}
+
+		paymentService := &domain.Service{
+			Name:            "payment_service",
+			BaseURL:         paymentBaseURL,
+			HealthCheckPath: paymentServiceConfig.HealthCheckPath,
+			Routes: []domain.Route{
+				{Path: "/api/v1/payments/intents", Methods: []string{"POST"}, RequireAuth: true},
+				{Path: "/api/v1/payments/intents/:id", Methods: []string{"GET"}, RequireAuth: true},
+				{Path: "/api/v1/payments/intents/:id/confirm", Methods: []string{"POST"}, RequireAuth: true},
+				{Path: "/api/v1/payments/intents/:id/cancel", Methods: []string{"POST"}, RequireAuth: true},
+			},
+		}
+
+		if err := serviceRegistry.RegisterService(paymentService); err != nil {
+			appLogger.Fatal("Failed to register payment service", zap.Error(err))
+		}
+		appLogger.Info("Payment service registered", zap.String("base_url", paymentBaseURL))
+	}
+
 	// Initialize proxy client (use max timeout from all services)
 	maxTimeout := productServiceConfig.Timeout
 	if exists && identityServiceConfig.Timeout > maxTimeout {
 		maxTimeout = identityServiceConfig.Timeout
 	}
-	proxyClient := repository.NewProxyClient(maxTimeout)
+	// Per-service circuit breakers (GET /gateway/status) and retries of idempotent calls
+	var breakers *circuitbreaker.Set
+	if cfg.Proxy.CircuitBreaker.Enabled {
+		breakers = circuitbreaker.NewSet(circuitbreaker.Settings{
+			FailureThreshold: cfg.Proxy.CircuitBreaker.FailureThreshold,
+			OpenTimeout:      cfg.Proxy.CircuitBreaker.OpenTimeout,
+			HalfOpenRequests: cfg.Proxy.CircuitBreaker.HalfOpenRequests,
+		})
+		for name := range serviceRegistry.GetAllServices() {
+			breakers.Get(name)
+		}
+	}
+	proxyClient := repository.NewProxyClient(maxTimeout, repository.RetryPolicy{
+		MaxRetries:     cfg.Proxy.Retry.MaxRetries,
+		InitialBackoff: cfg.Proxy.Retry.InitialBackoff,
+		MaxBackoff:     cfg.Proxy.Retry.MaxBackoff,
+		Methods:        cfg.Proxy.Retry.Methods,
+	}, breakers, appLogger)
 
 	// Initialize gateway service
-	gatewayService := service.NewGatewayService(serviceRegistry, proxyClient, appLogger)
+	// Rolling per-route and per-service request statistics (GET /gateway/stats)
+	statsRecorder := stats.NewRecorder()
+	gatewayService := service.NewGatewayService(serviceRegistry, proxyClient, statsRecorder, appLogger)
+
+	// Clickstream events forwarded to Kafka (POST /api/v1/events/track)
+	var trackingService *service.TrackingService
+	if cfg.Clickstream.Enabled {
+		eventPublisher := kafka.NewEventPublisher(kafka.PublisherOptions{
+			Brokers:      cfg.Kafka.Brokers,
+			Topics:       kafka.Topics{Prefix: cfg.Kafka.TopicPrefix, Overrides: cfg.Kafka.Topics},
+			WriteTimeout: cfg.Kafka.WriteTimeout,
+			BatchTimeout: cfg.Kafka.BatchTimeout,
+		}, appLogger)
+		defer func() {
+			if err := eventPublisher.Close(); err != nil {
+				appLogger.Warn("Failed to flush clickstream events", zap.Error(err))
+			}
+		}()
+		trackingService = service.NewTrackingService(eventPublisher, appLogger)
+		appLogger.Info("Clickstream tracking enabled", zap.Strings("kafka_brokers", cfg.Kafka.Brokers))
+	}
 
 	// Initialize handlers
 	gatewayHandler := handler.NewGatewayHandler(gatewayService, appLogger)
@@ -253,9 +420,13 @@ func main() {
 	productHandler := handler.NewProductHandler(gatewayService, appLogger)
 	categoryHandler := handler.NewCategoryHandler(gatewayService, appLogger)
 	searchHandler := handler.NewSearchHandler(gatewayService, appLogger)
+	logLevelHandler := handler.NewLogLevelHandler(logger.Level(), appLogger)
+	statsHandler := handler.NewStatsHandler(statsRecorder)
+	statusHandler := handler.NewStatusHandler(breakers)
+	trackingHandler := handler.NewTrackingHandler(trackingService, appLogger)
 
 	// Setup router
-	r := router.SetupRouter(gatewayHandler, authHandler, userHandler, addressHandler, productHandler, categoryHandler, searchHandler, cfg, appLogger, redisClient)
+	r := router.SetupRouter(gatewayHandler, authHandler, userHandler, addressHandler, productHandler, categoryHandler, searchHandler, logLevelHandler, statsHandler, statusHandler, trackingHandler, statsRecorder, httpPolicy, cfg, appLogger, redisClient)
 
 	// Create HTTP server with timeouts
 	srv := &http.Server{
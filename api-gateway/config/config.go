@@ -7,26 +7,40 @@ import (
 	"strings"
 	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 )
 
 // Config holds all configuration for the API Gateway
 type Config struct {
-	Server    ServerConfig
-	JWT       JWTConfig
-	RateLimit RateLimitConfig
-	CORS      CORSConfig
-	Services  ServicesConfig
-	Logging   LoggingConfig
-	Redis     RedisConfig
+	Server         ServerConfig
+	JWT            JWTConfig
+	Guest          GuestConfig
+	CSRF           CSRFConfig
+	RateLimit      RateLimitConfig `mapstructure:"rate_limit"`
+	CORS           CORSConfig
+	Security       SecurityHeadersConfig `mapstructure:"security_headers"`
+	Services       ServicesConfig
+	Proxy          ProxyConfig       `mapstructure:"proxy"`
+	Kafka          KafkaConfig       `mapstructure:"kafka"`
+	Clickstream    ClickstreamConfig `mapstructure:"clickstream"`
+	Logging        LoggingConfig
+	Redis          RedisConfig
+	Sentry         SentryConfig         `mapstructure:"sentry"`
+	FaultInjection FaultInjectionConfig `mapstructure:"fault_injection"`
 }
 
 // ServerConfig holds HTTP server configuration
 type ServerConfig struct {
 	Port         int
 	Mode         string
+	Environment  string `mapstructure:"environment"` // development, staging, production (selects CORS origins)
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
+
+	// Load balancers (IPs or CIDRs) whose X-Forwarded-For is trusted for the client IP;
+	// empty = clients connect directly and their connection address is used
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 // JWTConfig holds JWT authentication configuration
@@ -36,21 +50,70 @@ type JWTConfig struct {
 	Issuer     string
 }
 
+// GuestConfig holds anonymous guest token configuration
+type GuestConfig struct {
+	Secret     string        `mapstructure:"secret"`      // HMAC key signing guest tokens
+	TTL        time.Duration `mapstructure:"ttl"`         // Token lifetime, renewed while the guest is active
+	CookieName string        `mapstructure:"cookie_name"` // Cookie holding the token (X-Guest-Token header for apps)
+}
+
+// CSRFConfig holds CSRF protection configuration for cookie-based auth
+type CSRFConfig struct {
+	Enabled     bool     `mapstructure:"enabled"`      // Verify CSRF tokens on state-changing cookie-authenticated requests
+	Secret      string   `mapstructure:"secret"`       // HMAC key signing CSRF tokens
+	CookieName  string   `mapstructure:"cookie_name"`  // Cookie holding the token (readable by the frontend)
+	HeaderName  string   `mapstructure:"header_name"`  // Header the frontend echoes the token in
+	AuthCookies []string `mapstructure:"auth_cookies"` // Cookies that mark a request as cookie-authenticated
+	ExemptPaths []string `mapstructure:"exempt_paths"` // Path prefixes never checked (e.g. login)
+}
+
 // RateLimitConfig holds rate limiting configuration
+// Every client has a token bucket per route group in Redis, shared by all gateway
+// instances: signed-in users are limited per user, guests per verified guest token,
+// everything else per IP address. Requests outside the groups use the default limits
 type RateLimitConfig struct {
-	Enabled           bool
-	RequestsPerMinute int
-	Burst             int
+	Enabled               bool                   `mapstructure:"enabled"`
+	RequestsPerMinute     int                    `mapstructure:"requests_per_minute"`      // Refill rate of anonymous clients
+	Burst                 int                    `mapstructure:"burst"`                    // Bucket size of anonymous clients
+	UserRequestsPerMinute int                    `mapstructure:"user_requests_per_minute"` // Signed-in users (0 = as anonymous)
+	UserBurst             int                    `mapstructure:"user_burst"`               // Signed-in users (0 = as anonymous)
+	KeyPrefix             string                 `mapstructure:"key_prefix"`               // Redis key prefix of the buckets
+	Groups                []RateLimitGroupConfig `mapstructure:"groups"`                   // Longest matching path prefix wins
+}
+
+// RateLimitGroupConfig holds the limits of a route group, with its own buckets
+type RateLimitGroupConfig struct {
+	Name                  string `mapstructure:"name"`        // Part of the bucket key, unique
+	PathPrefix            string `mapstructure:"path_prefix"` // e.g. /api/v1/auth/
+	RequestsPerMinute     int    `mapstructure:"requests_per_minute"`
+	Burst                 int    `mapstructure:"burst"`
+	UserRequestsPerMinute int    `mapstructure:"user_requests_per_minute"` // 0 = as anonymous
+	UserBurst             int    `mapstructure:"user_burst"`               // 0 = as anonymous
 }
 
 // CORSConfig holds CORS configuration
+// Allowed origins are AllowedOrigins plus EnvironmentOrigins[server.environment];
+// unlisted origins are only reflected in development
 type CORSConfig struct {
-	AllowedOrigins   []string
-	AllowedMethods   []string
-	AllowedHeaders   []string
-	ExposeHeaders    []string
-	AllowCredentials bool
-	MaxAge           time.Duration
+	AllowedOrigins     []string            `mapstructure:"allowed_origins"`
+	EnvironmentOrigins map[string][]string `mapstructure:"environment_origins"`
+	AllowedMethods     []string            `mapstructure:"allowed_methods"`
+	AllowedHeaders     []string            `mapstructure:"allowed_headers"`
+	ExposeHeaders      []string            `mapstructure:"expose_headers"`
+	AllowCredentials   bool                `mapstructure:"allow_credentials"`
+	MaxAge             time.Duration       `mapstructure:"max_age"`
+}
+
+// SecurityHeadersConfig holds the security headers the gateway sets on every response
+// (backend services' own values are dropped)
+type SecurityHeadersConfig struct {
+	HSTSMaxAge            time.Duration `mapstructure:"hsts_max_age"`            // Strict-Transport-Security max-age, only sent over HTTPS (0 disables)
+	HSTSIncludeSubdomains bool          `mapstructure:"hsts_include_subdomains"` // Adds includeSubDomains
+	ContentTypeOptions    string        `mapstructure:"content_type_options"`    // X-Content-Type-Options ("" disables)
+	FrameOptions          string        `mapstructure:"frame_options"`           // X-Frame-Options ("" disables)
+	ReferrerPolicy        string        `mapstructure:"referrer_policy"`         // Referrer-Policy ("" disables)
+	ContentSecurityPolicy string        `mapstructure:"content_security_policy"` // Content-Security-Policy ("" disables)
+	CSPExemptPaths        []string      `mapstructure:"csp_exempt_paths"`        // Path prefixes serving HTML that the API policy would break (Swagger UI)
 }
 
 // ServiceConfig holds configuration for a single microservice
@@ -71,6 +134,69 @@ type RouteConfig struct {
 // ServicesConfig holds configuration for all microservices
 type ServicesConfig map[string]ServiceConfig
 
+// ProxyConfig holds the resilience of the calls proxied to backend services
+type ProxyConfig struct {
+	Retry          RetryConfig          `mapstructure:"retry"`
+	CircuitBreaker CircuitBreakerConfig `mapstructure:"circuit_breaker"`
+}
+
+// RetryConfig holds the retries of idempotent calls that failed to connect or got
+// 502/503/504 (timeouts are not retried)
+type RetryConfig struct {
+	MaxRetries     int           `mapstructure:"max_retries"`     // Retries after the first attempt; 0 disables
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"` // Doubled after every retry
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`
+	Methods        []string      `mapstructure:"methods"` // Only idempotent methods (GET, HEAD, PUT, DELETE, OPTIONS)
+}
+
+// CircuitBreakerConfig holds the per-service circuit breakers (GET /gateway/status)
+type CircuitBreakerConfig struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	FailureThreshold int           `mapstructure:"failure_threshold"`  // Consecutive failures (transport errors, 502/503/504) that open it
+	OpenTimeout      time.Duration `mapstructure:"open_timeout"`       // Calls answered 503 this long before probing the service
+	HalfOpenRequests int           `mapstructure:"half_open_requests"` // Probe calls that must succeed to close it
+}
+
+// KafkaConfig holds the Kafka producer configuration (clickstream events)
+type KafkaConfig struct {
+	Brokers      []string          `mapstructure:"brokers"`
+	TopicPrefix  string            `mapstructure:"topic_prefix"` // One topic per event type: <prefix>clickstream.view, ...
+	Topics       map[string]string `mapstructure:"topics"`       // Event type -> topic override
+	WriteTimeout time.Duration     `mapstructure:"write_timeout"`
+	BatchTimeout time.Duration     `mapstructure:"batch_timeout"` // Flush a partial batch after this long
+}
+
+// ClickstreamConfig holds the client event tracking endpoint (POST /api/v1/events/track)
+type ClickstreamConfig struct {
+	Enabled bool `mapstructure:"enabled"` // Disabled: the endpoint answers 503
+}
+
+// SentryConfig holds the reporting of recovered panics to Sentry
+type SentryConfig struct {
+	DSN         string  `mapstructure:"dsn"`         // empty disables reporting (panics are only logged)
+	Environment string  `mapstructure:"environment"` // e.g. production, staging
+	Release     string  `mapstructure:"release"`     // deployed version, groups issues by release
+	SampleRate  float64 `mapstructure:"sample_rate"` // share of panics reported, 0-1
+}
+
+// FaultInjectionConfig holds the opt-in injection of latency and errors, used to check
+// timeouts, retries and circuit breakers of the callers. Refused in release mode
+type FaultInjectionConfig struct {
+	Enabled bool              `mapstructure:"enabled"`
+	Headers bool              `mapstructure:"headers"` // honor X-Fault-* request headers naming this service
+	Rules   []FaultRuleConfig `mapstructure:"rules"`
+}
+
+// FaultRuleConfig injects faults into the requests matching a path prefix (and method)
+type FaultRuleConfig struct {
+	PathPrefix  string        `mapstructure:"path_prefix"`
+	Method      string        `mapstructure:"method"`       // empty matches any method
+	Latency     time.Duration `mapstructure:"latency"`      // added before the request is handled
+	Jitter      time.Duration `mapstructure:"jitter"`       // random extra latency, up to this much
+	ErrorRate   float64       `mapstructure:"error_rate"`   // share of matching requests answered with ErrorStatus, 0-1
+	ErrorStatus int           `mapstructure:"error_status"` // default 503
+}
+
 // LoggingConfig holds logging configuration
 type LoggingConfig struct {
 	Level            string
@@ -86,7 +212,13 @@ type RedisConfig struct {
 	Password     string `mapstructure:"password"`
 	DB           int    `mapstructure:"db"`
 	PoolSize     int    `mapstructure:"pool_size"`
-	MinIdleConns int    `mapstructure:"min_idle_conns"`
+	MinIdleConns int    `mapstructure:"min_idle_conns"` // At most PoolSize
+
+	// Timeouts
+	DialTimeout  time.Duration `mapstructure:"dial_timeout"`
+	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
+	WriteTimeout time.Duration `mapstructure:"write_timeout"`
+	PoolTimeout  time.Duration `mapstructure:"pool_timeout"` // Wait for a free connection when the pool is exhausted
 }
 
 // LoadConfig reads configuration from config.yaml and environment variables
@@ -122,7 +254,7 @@ func LoadConfig(configPath string) (*Config, error) {
 	services := make(ServicesConfig)
 
 	// Get all service keys
-	serviceKeys := []string{"product_service", "identity_service", "search_service", "order_service"}
+	serviceKeys := []string{"product_service", "identity_service", "search_service", "order_service", "payment_service"}
 	for _, serviceKey := range serviceKeys {
 		servicePath := fmt.Sprintf("services.%s", serviceKey)
 
@@ -161,6 +293,26 @@ func LoadConfig(configPath string) (*Config, error) {
 		config.Services = services
 	}
 
+	// Fail fast on settings that would only break under load (pool, timeouts)
+	if err := config.Server.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if err := config.Redis.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if err := config.RateLimit.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if err := config.Proxy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if err := config.Clickstream.Validate(&config.Kafka); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if err := config.FaultInjection.Validate(config.Server.Mode); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
 	return config, nil
 }
 
@@ -171,25 +323,55 @@ func setDefaults() {
 	viper.SetDefault("server.mode", "debug")
 	viper.SetDefault("server.read_timeout", "30s")
 	viper.SetDefault("server.write_timeout", "30s")
+	viper.SetDefault("server.environment", "development")
+	viper.SetDefault("server.trusted_proxies", []string{})
 
 	// JWT defaults
 	viper.SetDefault("jwt.secret", "your-secret-key-change-in-production")
 	viper.SetDefault("jwt.expiration", "24h")
 	viper.SetDefault("jwt.issuer", "api-gateway")
 
+	// Guest token defaults
+	viper.SetDefault("guest.secret", "guest-secret-change-in-production")
+	viper.SetDefault("guest.ttl", "24h")
+	viper.SetDefault("guest.cookie_name", "guest_token")
+
+	// CSRF defaults
+	viper.SetDefault("csrf.enabled", true)
+	viper.SetDefault("csrf.secret", "csrf-secret-change-in-production")
+	viper.SetDefault("csrf.cookie_name", "csrf_token")
+	viper.SetDefault("csrf.header_name", "X-CSRF-Token")
+	viper.SetDefault("csrf.auth_cookies", []string{"access_token", "refresh_token", "session_id"})
+	viper.SetDefault("csrf.exempt_paths", []string{"/api/v1/auth/login", "/api/v1/auth/register", "/api/v1/events/track"})
+
 	// Rate limit defaults
 	viper.SetDefault("rate_limit.enabled", true)
 	viper.SetDefault("rate_limit.requests_per_minute", 100)
 	viper.SetDefault("rate_limit.burst", 20)
+	viper.SetDefault("rate_limit.user_requests_per_minute", 300)
+	viper.SetDefault("rate_limit.user_burst", 50)
+	viper.SetDefault("rate_limit.key_prefix", "ratelimit:")
 
 	// CORS defaults
-	viper.SetDefault("cors.allowed_origins", []string{"http://localhost:3000", "http://localhost:5173"})
+	viper.SetDefault("cors.allowed_origins", []string{})
+	viper.SetDefault("cors.environment_origins", map[string][]string{
+		"development": {"http://localhost:3000", "http://localhost:5173"},
+	})
 	viper.SetDefault("cors.allowed_methods", []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"})
-	viper.SetDefault("cors.allowed_headers", []string{"Origin", "Content-Type", "Accept", "Authorization", "Cookie", "Set-Cookie"})
-	viper.SetDefault("cors.expose_headers", []string{"Set-Cookie"})
+	viper.SetDefault("cors.allowed_headers", []string{"Origin", "Content-Type", "Accept", "Authorization", "Cookie", "Set-Cookie", "X-Cart-Version", "X-Guest-Token", "X-CSRF-Token", "If-None-Match", "If-Modified-Since"})
+	viper.SetDefault("cors.expose_headers", []string{"Set-Cookie", "X-Guest-Token", "X-CSRF-Token", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "X-Quota-Tier", "X-Quota-Hourly-Limit", "X-Quota-Hourly-Remaining", "X-Quota-Daily-Limit", "X-Quota-Daily-Remaining", "Retry-After", "ETag", "X-Request-Id"})
 	viper.SetDefault("cors.allow_credentials", true)
 	viper.SetDefault("cors.max_age", "12h")
 
+	// Security headers defaults
+	viper.SetDefault("security_headers.hsts_max_age", "8760h")
+	viper.SetDefault("security_headers.hsts_include_subdomains", true)
+	viper.SetDefault("security_headers.content_type_options", "nosniff")
+	viper.SetDefault("security_headers.frame_options", "DENY")
+	viper.SetDefault("security_headers.referrer_policy", "strict-origin-when-cross-origin")
+	viper.SetDefault("security_headers.content_security_policy", "default-src 'none'; frame-ancestors 'none'")
+	viper.SetDefault("security_headers.csp_exempt_paths", []string{"/swagger/"})
+
 	// Redis defaults
 	viper.SetDefault("redis.host", "localhost")
 	viper.SetDefault("redis.port", 6379)
@@ -197,6 +379,27 @@ func setDefaults() {
 	viper.SetDefault("redis.db", 0)
 	viper.SetDefault("redis.pool_size", 10)
 	viper.SetDefault("redis.min_idle_conns", 5)
+	viper.SetDefault("redis.dial_timeout", "5s")
+	viper.SetDefault("redis.read_timeout", "3s")
+	viper.SetDefault("redis.write_timeout", "3s")
+	viper.SetDefault("redis.pool_timeout", "4s")
+
+	// Proxy resilience defaults
+	viper.SetDefault("proxy.retry.max_retries", 2)
+	viper.SetDefault("proxy.retry.initial_backoff", "100ms")
+	viper.SetDefault("proxy.retry.max_backoff", "1s")
+	viper.SetDefault("proxy.retry.methods", []string{"GET", "HEAD"})
+	viper.SetDefault("proxy.circuit_breaker.enabled", true)
+	viper.SetDefault("proxy.circuit_breaker.failure_threshold", 5)
+	viper.SetDefault("proxy.circuit_breaker.open_timeout", "30s")
+	viper.SetDefault("proxy.circuit_breaker.half_open_requests", 1)
+
+	// Kafka and clickstream defaults
+	viper.SetDefault("kafka.brokers", []string{"localhost:9092"})
+	viper.SetDefault("kafka.topic_prefix", "")
+	viper.SetDefault("kafka.write_timeout", "10s")
+	viper.SetDefault("kafka.batch_timeout", "50ms")
+	viper.SetDefault("clickstream.enabled", true)
 
 	// Services defaults
 	// Note: In Docker, use service name. For local dev, use localhost
@@ -204,6 +407,15 @@ func setDefaults() {
 	viper.SetDefault("services.product_service.timeout", "30s")
 	viper.SetDefault("services.product_service.health_check_path", "/health")
 
+	// Panic reporting defaults (set SENTRY_DSN to enable)
+	viper.SetDefault("sentry.dsn", "")
+	viper.SetDefault("sentry.environment", "development")
+	viper.SetDefault("sentry.sample_rate", 1.0)
+
+	// Fault injection is for resilience testing only
+	viper.SetDefault("fault_injection.enabled", false)
+	viper.SetDefault("fault_injection.headers", false)
+
 	// Logging defaults
 	viper.SetDefault("logging.level", "info")
 	viper.SetDefault("logging.encoding", "json")
@@ -211,6 +423,21 @@ func setDefaults() {
 	viper.SetDefault("logging.error_output_paths", []string{"stderr"})
 }
 
+// WatchConfig reloads the config file whenever it changes and passes the new
+// configuration to onChange. Only settings read per request (CORS, security headers)
+// take effect without a restart
+func WatchConfig(onChange func(*Config)) {
+	viper.OnConfigChange(func(e fsnotify.Event) {
+		config := &Config{}
+		if err := viper.Unmarshal(config); err != nil {
+			log.Printf("Warning: Could not reload config file %s: %v. Keeping the previous configuration.", e.Name, err)
+			return
+		}
+		onChange(config)
+	})
+	viper.WatchConfig()
+}
+
 // GetAddress returns the Redis address
 func (c *RedisConfig) GetAddress() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
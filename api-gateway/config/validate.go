@@ -0,0 +1,138 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+// Validate checks the Redis pool and timeout settings
+func (c *RedisConfig) Validate() error {
+	var errs []error
+	if c.Host == "" {
+		errs = append(errs, errors.New("redis: host is required"))
+	}
+	if c.PoolSize <= 0 {
+		errs = append(errs, fmt.Errorf("redis: pool_size must be positive, got %d", c.PoolSize))
+	}
+	if c.MinIdleConns < 0 || c.MinIdleConns > c.PoolSize {
+		errs = append(errs, fmt.Errorf("redis: min_idle_conns must be between 0 and pool_size (%d), got %d", c.PoolSize, c.MinIdleConns))
+	}
+	if c.DialTimeout <= 0 || c.ReadTimeout <= 0 || c.WriteTimeout <= 0 || c.PoolTimeout <= 0 {
+		errs = append(errs, errors.New("redis: dial_timeout, read_timeout, write_timeout and pool_timeout must be positive"))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks the default and route group limits
+func (c *RateLimitConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	var errs []error
+	if c.RequestsPerMinute <= 0 || c.Burst <= 0 {
+		errs = append(errs, fmt.Errorf("rate_limit: requests_per_minute and burst must be positive, got %d and %d", c.RequestsPerMinute, c.Burst))
+	}
+	if c.UserRequestsPerMinute < 0 || c.UserBurst < 0 {
+		errs = append(errs, errors.New("rate_limit: user_requests_per_minute and user_burst must not be negative"))
+	}
+	names := make(map[string]bool, len(c.Groups))
+	for i, group := range c.Groups {
+		if group.Name == "" || group.Name == "default" || names[group.Name] {
+			errs = append(errs, fmt.Errorf("rate_limit: groups[%d].name must be set, unique and not \"default\", got %q", i, group.Name))
+		}
+		names[group.Name] = true
+		if group.PathPrefix == "" {
+			errs = append(errs, fmt.Errorf("rate_limit: groups[%d].path_prefix is required", i))
+		}
+		if group.RequestsPerMinute <= 0 || group.Burst <= 0 {
+			errs = append(errs, fmt.Errorf("rate_limit: groups[%d] requests_per_minute and burst must be positive", i))
+		}
+		if group.UserRequestsPerMinute < 0 || group.UserBurst < 0 {
+			errs = append(errs, fmt.Errorf("rate_limit: groups[%d] user_requests_per_minute and user_burst must not be negative", i))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks the retry policy and circuit breaker settings; only idempotent methods
+// may be retried
+func (c *ProxyConfig) Validate() error {
+	var errs []error
+	if c.Retry.MaxRetries < 0 {
+		errs = append(errs, fmt.Errorf("proxy: retry.max_retries must not be negative, got %d", c.Retry.MaxRetries))
+	}
+	if c.Retry.MaxRetries > 0 && (c.Retry.InitialBackoff <= 0 || c.Retry.MaxBackoff < c.Retry.InitialBackoff) {
+		errs = append(errs, errors.New("proxy: retry.initial_backoff must be positive and at most retry.max_backoff"))
+	}
+	for _, method := range c.Retry.Methods {
+		switch method {
+		case "GET", "HEAD", "PUT", "DELETE", "OPTIONS":
+		default:
+			errs = append(errs, fmt.Errorf("proxy: retry.methods must be idempotent methods, got %q", method))
+		}
+	}
+	if c.CircuitBreaker.Enabled {
+		if c.CircuitBreaker.FailureThreshold <= 0 || c.CircuitBreaker.HalfOpenRequests <= 0 {
+			errs = append(errs, errors.New("proxy: circuit_breaker.failure_threshold and half_open_requests must be positive"))
+		}
+		if c.CircuitBreaker.OpenTimeout <= 0 {
+			errs = append(errs, errors.New("proxy: circuit_breaker.open_timeout must be positive"))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks that clickstream tracking has brokers to write to
+func (c *ClickstreamConfig) Validate(kafka *KafkaConfig) error {
+	if !c.Enabled {
+		return nil
+	}
+	if len(kafka.Brokers) == 0 {
+		return errors.New("clickstream: kafka.brokers is required when enabled")
+	}
+	if kafka.WriteTimeout <= 0 || kafka.BatchTimeout <= 0 {
+		return errors.New("clickstream: kafka.write_timeout and kafka.batch_timeout must be positive")
+	}
+	return nil
+}
+
+// Validate checks the fault injection rules; injecting faults in release mode is refused
+func (c *FaultInjectionConfig) Validate(serverMode string) error {
+	if !c.Enabled {
+		return nil
+	}
+	if serverMode == "release" {
+		return errors.New("fault_injection: must not be enabled when server.mode is release")
+	}
+	var errs []error
+	for i, rule := range c.Rules {
+		if rule.PathPrefix == "" {
+			errs = append(errs, fmt.Errorf("fault_injection: rules[%d].path_prefix is required", i))
+		}
+		if rule.Latency < 0 || rule.Jitter < 0 {
+			errs = append(errs, fmt.Errorf("fault_injection: rules[%d] latency and jitter must not be negative", i))
+		}
+		if rule.ErrorRate < 0 || rule.ErrorRate > 1 {
+			errs = append(errs, fmt.Errorf("fault_injection: rules[%d].error_rate must be between 0 and 1, got %g", i, rule.ErrorRate))
+		}
+		if rule.ErrorStatus != 0 && (rule.ErrorStatus < 400 || rule.ErrorStatus > 599) {
+			errs = append(errs, fmt.Errorf("fault_injection: rules[%d].error_status must be a 4xx or 5xx status, got %d", i, rule.ErrorStatus))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks that the trusted proxies are IP addresses or CIDRs
+func (c *ServerConfig) Validate() error {
+	var errs []error
+	for _, proxy := range c.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err == nil {
+			continue
+		}
+		if net.ParseIP(proxy) == nil {
+			errs = append(errs, fmt.Errorf("server: trusted_proxies must be IP addresses or CIDRs, got %q", proxy))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Clickstream event types tracked by clients
+const (
+	EventView      = "view"        // a product page was opened
+	EventClick     = "click"       // a product was clicked in a list (search, home, recommendations)
+	EventAddToCart = "add_to_cart" // a product was added to the cart
+	EventSearch    = "search"      // a search was submitted
+)
+
+// ClickstreamEvent is one client event as forwarded to Kafka for the recommendation and
+// analytics pipelines; the gateway adds the visitor identity and the receive time
+type ClickstreamEvent struct {
+	EventID    string            `json:"event_id"` // Assigned by the gateway, deduplicates redelivered messages
+	Type       string            `json:"type"`
+	SessionID  string            `json:"session_id"`
+	UserID     string            `json:"user_id,omitempty"`  // Signed-in user
+	GuestID    string            `json:"guest_id,omitempty"` // Guest identity, also kept after login to join the sessions
+	ProductID  uint              `json:"product_id,omitempty"`
+	ShopID     uint              `json:"shop_id,omitempty"`
+	Query      string            `json:"query,omitempty"`
+	Quantity   int               `json:"quantity,omitempty"`
+	Position   int               `json:"position,omitempty"` // 1-based rank of a clicked product in its list
+	Source     string            `json:"source,omitempty"`   // Page or widget the event happened on
+	Page       string            `json:"page,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+	UserAgent  string            `json:"user_agent,omitempty"`
+	OccurredAt time.Time         `json:"occurred_at"` // Client clock
+	ReceivedAt time.Time         `json:"received_at"` // Gateway clock
+}
+
+// EventPublisher forwards clickstream events to Kafka (one topic per event type)
+type EventPublisher interface {
+	PublishClickstream(ctx context.Context, events []*ClickstreamEvent) error
+	Close() error
+}
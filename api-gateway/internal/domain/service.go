@@ -1,5 +1,7 @@
 package domain
 
+import "context"
+
 // Service represents a backend microservice
 // This is the domain model for service routing
 type Service struct {
@@ -34,6 +36,6 @@ type ProxyResponse struct {
 // ProxyClient defines the interface for proxying requests to services
 // This abstraction allows different proxy implementations
 type ProxyClient interface {
-	ProxyRequest(service *Service, path string, method string, headers map[string]string, body []byte) (*ProxyResponse, error)
+	ProxyRequest(ctx context.Context, service *Service, path string, method string, headers map[string]string, body []byte) (*ProxyResponse, error)
 	HealthCheck(service *Service) error
 }
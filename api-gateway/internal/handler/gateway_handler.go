@@ -10,13 +10,6 @@ import (
 	"go.uber.org/zap"
 )
 
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
-
 func getHeaderKeys(m map[string]string) []string {
 	keys := make([]string, 0, len(m))
 	for k := range m {
@@ -25,12 +18,30 @@ func getHeaderKeys(m map[string]string) []string {
 	return keys
 }
 
+// headerKeys returns the names of response headers, for logs that must not contain values
+func headerKeys(h map[string][]string) []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
 // isCORSHeader checks if a header is a CORS-related header (case-insensitive)
 func isCORSHeader(key string) bool {
 	lower := strings.ToLower(key)
 	return strings.HasPrefix(lower, "access-control-")
 }
 
+// isSecurityHeader checks if a header is set by the gateway's security headers middleware
+func isSecurityHeader(key string) bool {
+	switch strings.ToLower(key) {
+	case "strict-transport-security", "x-content-type-options", "x-frame-options", "referrer-policy", "content-security-policy":
+		return true
+	}
+	return false
+}
+
 // GatewayHandler handles HTTP requests for the API Gateway
 type GatewayHandler struct {
 	gatewayService *service.GatewayService
@@ -70,12 +81,11 @@ func (h *GatewayHandler) ProxyRequest(c *gin.Context) {
 		return
 	}
 
-	// DEBUG: Log incoming request immediately
 	_, hasAuthInContext := c.Get("auth_header")
-	h.logger.Info("ProxyRequest called",
+	h.logger.Debug("ProxyRequest called",
 		zap.String("path", c.Request.URL.Path),
 		zap.String("method", c.Request.Method),
-		zap.String("auth_header_in_request", c.Request.Header.Get("Authorization")),
+		zap.Bool("auth_in_request", c.Request.Header.Get("Authorization") != ""),
 		zap.Bool("auth_in_context", hasAuthInContext),
 	)
 
@@ -100,6 +110,10 @@ func (h *GatewayHandler) ProxyRequest(c *gin.Context) {
 		if key == "Connection" || key == "Keep-Alive" || key == "Transfer-Encoding" || key == "Upgrade" {
 			continue
 		}
+		// Impersonation headers are only set by the gateway from a verified token
+		if key == "X-Impersonator-Id" || key == "X-Impersonation-Id" {
+			continue
+		}
 		// Copy all other headers including Authorization
 		if len(values) > 0 {
 			headers[key] = values[0]
@@ -114,7 +128,6 @@ func (h *GatewayHandler) ProxyRequest(c *gin.Context) {
 			authHeader = authStr
 			// Override with preserved header from middleware
 			headers["Authorization"] = authHeader
-			h.logger.Debug("Got Authorization from context", zap.String("header_preview", authStr[:min(30, len(authStr))]))
 		}
 	}
 
@@ -123,15 +136,12 @@ func (h *GatewayHandler) ProxyRequest(c *gin.Context) {
 		authHeader = c.Request.Header.Get("Authorization")
 		if authHeader != "" {
 			headers["Authorization"] = authHeader
-			h.logger.Debug("Got Authorization from Request.Header", zap.String("header_preview", authHeader[:min(30, len(authHeader))]))
 		}
 	}
 
 	// Final check: Log if Authorization is missing
 	if headers["Authorization"] == "" {
-		h.logger.Warn("No Authorization header found in handler", zap.Strings("available_headers", getHeaderKeys(headers)))
-	} else {
-		h.logger.Debug("Authorization header ready for forwarding", zap.String("header_preview", headers["Authorization"][:min(30, len(headers["Authorization"]))]))
+		h.logger.Debug("No Authorization header found in handler", zap.Strings("available_headers", getHeaderKeys(headers)))
 	}
 
 	// CRITICAL: Forward user context headers to backend microservices
@@ -144,6 +154,20 @@ func (h *GatewayHandler) ProxyRequest(c *gin.Context) {
 		}
 	}
 
+	// Client IP for per-client limits in backend services (e.g. identity brute-force protection)
+	headers["X-Forwarded-For"] = c.ClientIP()
+
+	// Admin acting as the user (impersonation token): backends log these actions distinctly
+	if impersonatorID := c.GetString("impersonator_id"); impersonatorID != "" {
+		headers["X-Impersonator-Id"] = impersonatorID
+		headers["X-Impersonation-Id"] = c.GetString("impersonation_id")
+	}
+
+	// Guest identity (set by guest middleware) keys guest carts and history before login
+	if guestID := c.GetString("guest_id"); guestID != "" {
+		headers["X-Guest-Id"] = guestID
+	}
+
 	if email, exists := c.Get("email"); exists {
 		if emailStr, ok := email.(string); ok {
 			headers["X-User-Email"] = emailStr
@@ -204,15 +228,9 @@ func (h *GatewayHandler) ProxyRequest(c *gin.Context) {
 	// CRITICAL: Forward response headers from backend to client
 	// EXCEPT CORS headers which are handled by Gateway middleware
 	// This is essential for Set-Cookie headers in authentication
-	h.logger.Info("Forwarding response headers",
+	h.logger.Debug("Forwarding response headers",
 		zap.Int("header_count", len(proxyResponse.Headers)),
-		zap.Strings("header_keys", func() []string {
-			keys := make([]string, 0, len(proxyResponse.Headers))
-			for k := range proxyResponse.Headers {
-				keys = append(keys, k)
-			}
-			return keys
-		}()),
+		zap.Strings("header_keys", headerKeys(proxyResponse.Headers)),
 	)
 
 	// FIX 2: Skip ALL CORS headers from backend (case-insensitive)
@@ -224,12 +242,12 @@ func (h *GatewayHandler) ProxyRequest(c *gin.Context) {
 			)
 			continue
 		}
+		// Skip security headers - the gateway policy replaces per-service defaults
+		if isSecurityHeader(headerKey) {
+			continue
+		}
 
 		for _, headerValue := range headerValues {
-			h.logger.Info("Setting response header",
-				zap.String("key", headerKey),
-				zap.String("value", headerValue),
-			)
 			c.Writer.Header().Add(headerKey, headerValue)
 		}
 	}
@@ -240,10 +258,9 @@ func (h *GatewayHandler) ProxyRequest(c *gin.Context) {
 		contentType = ctValues[0]
 	}
 
-	// FIX 3: Verify CORS headers are present before sending response
-	h.logger.Info("Final response headers before c.Data()",
-		zap.String("Access-Control-Allow-Origin", c.Writer.Header().Get("Access-Control-Allow-Origin")),
-		zap.String("Access-Control-Allow-Credentials", c.Writer.Header().Get("Access-Control-Allow-Credentials")),
+	// FIX 3: Header names only - values carry tokens (Set-Cookie)
+	h.logger.Debug("Final response headers before c.Data()",
+		zap.Strings("header_keys", headerKeys(c.Writer.Header())),
 		zap.Int("status_code", proxyResponse.StatusCode),
 	)
 
@@ -286,6 +303,12 @@ func (h *GatewayHandler) HealthCheck(c *gin.Context) {
 	}
 }
 
+// RequiresAuth reports whether the registered route for a request path requires an
+// authenticated user (see RouteAuthMiddleware)
+func (h *GatewayHandler) RequiresAuth(method, path string) bool {
+	return h.gatewayService.RequiresAuth(h.getServiceName(path), path, method)
+}
+
 // getServiceName maps request paths to service names
 func (h *GatewayHandler) getServiceName(path string) string {
 	// Simple path-based routing
@@ -303,15 +326,104 @@ func (h *GatewayHandler) getServiceName(path string) string {
 	if strings.HasPrefix(path, "/api/v1/categories") {
 		return "product_service"
 	}
+	if strings.HasPrefix(path, "/api/v1/product-items") {
+		return "product_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/tools") {
+		return "product_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/seller/inventory") {
+		return "product_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/content") || strings.HasPrefix(path, "/api/v1/feeds") {
+		return "product_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/admin/products") {
+		return "product_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/admin/catalog-quality") {
+		return "product_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/admin/inventory") {
+		return "product_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/admin/quotas") {
+		return "product_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/admin/tasks/product") {
+		return "product_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/admin/tasks/order") {
+		return "order_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/admin/events/product") {
+		return "product_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/admin/events/order") {
+		return "order_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/admin/log-level/product") {
+		return "product_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/admin/log-level/order") {
+		return "order_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/admin/log-level/identity") {
+		return "identity_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/admin/log-level/search") {
+		return "search_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/admin/search") {
+		return "search_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/admin/jobs/product") {
+		return "product_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/admin/jobs/order") {
+		return "order_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/admin/jobs/identity") {
+		return "identity_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/admin/disputes") || strings.HasPrefix(path, "/api/v1/admin/orders") ||
+		strings.HasPrefix(path, "/api/v1/admin/accounting") || strings.HasPrefix(path, "/api/v1/admin/announcements") {
+		return "order_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/admin/banners") || strings.HasPrefix(path, "/api/v1/admin/campaigns") {
+		return "product_service"
+	}
 	if strings.HasPrefix(path, "/api/v1/auth") {
 		return "identity_service"
 	}
+	if strings.HasPrefix(path, "/api/v1/users/me/recently-viewed") {
+		return "product_service"
+	}
 	if strings.HasPrefix(path, "/api/v1/users") {
 		return "identity_service"
 	}
+	if strings.HasPrefix(path, "/api/v1/admin/settings") || strings.HasPrefix(path, "/api/v1/admin/feature-flags") || strings.HasPrefix(path, "/api/v1/admin/security-events") || strings.HasPrefix(path, "/api/v1/admin/impersonations") || strings.HasPrefix(path, "/api/v1/admin/email-templates") ||
+		strings.HasPrefix(path, "/api/v1/admin/users") || strings.HasPrefix(path, "/api/v1/admin/shops") {
+		return "identity_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/feature-flags") {
+		return "identity_service"
+	}
 	if strings.HasPrefix(path, "/api/v1/addresses") {
 		return "identity_service"
 	}
+	if strings.HasPrefix(path, "/api/v1/shops/") && strings.Contains(path, "/collections") {
+		return "product_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/shops/") && strings.HasSuffix(path, "/bestsellers") {
+		return "product_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/shops/") && (strings.HasSuffix(path, "/reviews") || strings.HasSuffix(path, "/rating")) {
+		return "product_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/reviews") {
+		return "product_service"
+	}
 	if strings.HasPrefix(path, "/api/v1/shops") { // THÊM MỚI - Shop routes
 		return "identity_service"
 	}
@@ -321,6 +433,21 @@ func (h *GatewayHandler) getServiceName(path string) string {
 	if strings.HasPrefix(path, "/api/v1/orders") {
 		return "order_service"
 	}
+	if strings.HasPrefix(path, "/api/v1/subscriptions") {
+		return "order_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/notifications") {
+		return "order_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/disputes") {
+		return "order_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/vouchers") {
+		return "order_service"
+	}
+	if strings.HasPrefix(path, "/api/v1/payments") {
+		return "payment_service"
+	}
 	// Default to product_service for now
 	return "product_service"
 }
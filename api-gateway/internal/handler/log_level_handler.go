@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogLevelHandler handles admin HTTP requests for changing the log level at runtime
+type LogLevelHandler struct {
+	level  zap.AtomicLevel
+	logger *zap.Logger
+}
+
+// NewLogLevelHandler creates a new log level handler
+func NewLogLevelHandler(level zap.AtomicLevel, logger *zap.Logger) *LogLevelHandler {
+	return &LogLevelHandler{
+		level:  level,
+		logger: logger,
+	}
+}
+
+// SetLogLevelRequest represents the request body for changing the log level
+type SetLogLevelRequest struct {
+	Level string `json:"level" binding:"required" example:"debug"`
+}
+
+// GetLogLevel handles GET /api/v1/admin/log-level/gateway
+// @Summary Get current log level (admin)
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} map[string]string "Current log level"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Security BearerAuth
+// @Router /api/v1/admin/log-level/gateway [get]
+func (h *LogLevelHandler) GetLogLevel(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"level": h.level.String()})
+}
+
+// SetLogLevel handles PUT /api/v1/admin/log-level/gateway
+// @Summary Change log level (admin)
+// @Description Takes effect immediately and lasts until restart (debug, info, warn, error)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body SetLogLevelRequest true "New log level"
+// @Success 200 {object} map[string]string "Updated log level"
+// @Failure 400 {object} map[string]string "Invalid level"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Security BearerAuth
+// @Router /api/v1/admin/log-level/gateway [put]
+func (h *LogLevelHandler) SetLogLevel(c *gin.Context) {
+	var req SetLogLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(req.Level)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid level: " + req.Level})
+		return
+	}
+
+	previous := h.level.Level()
+	h.level.SetLevel(lvl)
+	h.logger.Warn("log level changed",
+		zap.String("from", previous.String()),
+		zap.String("to", lvl.String()),
+		zap.String("user_id", c.GetString("user_id")),
+	)
+
+	c.JSON(http.StatusOK, gin.H{"level": lvl.String()})
+}
@@ -63,6 +63,21 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 	gatewayHandler.ProxyRequest(c)
 }
 
+// GetProductBySlug handles GET /products/slug/:slug
+// @Summary Get product by slug
+// @Description Get a product by its URL slug (old slugs redirect to the current one)
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Param slug path string true "Product slug"
+// @Success 200 {object} models.Product "Product details"
+// @Failure 404 {object} models.ErrorResponse "Product not found"
+// @Router /products/slug/{slug} [get]
+func (h *ProductHandler) GetProductBySlug(c *gin.Context) {
+	gatewayHandler := NewGatewayHandler(h.gatewayService, h.logger)
+	gatewayHandler.ProxyRequest(c)
+}
+
 // CreateProduct handles POST /products
 // @Summary Create a new product
 // @Description Create a new product (requires authentication)
@@ -218,7 +233,7 @@ func (h *ProductHandler) UpdateProductItem(c *gin.Context) {
 
 // DeleteProductItem handles DELETE /products/:id/items/:item_id
 // @Summary Delete product item (SKU)
-// @Description Delete a SKU (Auth required)
+// @Description Soft-delete a SKU (marked DISCONTINUED); rejected while open orders contain it (Auth required)
 // @Tags Product Items
 // @Accept json
 // @Produce json
@@ -226,12 +241,30 @@ func (h *ProductHandler) UpdateProductItem(c *gin.Context) {
 // @Param item_id path int true "Product Item ID"
 // @Success 204 "Product item deleted successfully"
 // @Failure 404 {object} models.ErrorResponse "Product item not found"
+// @Failure 409 {object} models.ErrorResponse "Product item is in open orders"
 // @Router /products/{id}/items/{item_id} [delete]
 func (h *ProductHandler) DeleteProductItem(c *gin.Context) {
 	gatewayHandler := NewGatewayHandler(h.gatewayService, h.logger)
 	gatewayHandler.ProxyRequest(c)
 }
 
+// RestoreProductItem handles POST /products/:id/items/:item_id/restore
+// @Summary Restore deleted product item (SKU)
+// @Description Undo a SKU delete (Auth required)
+// @Tags Product Items
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param item_id path int true "Product Item ID"
+// @Success 200 {object} object "Restored product item"
+// @Failure 404 {object} models.ErrorResponse "Product item not found"
+// @Failure 409 {object} models.ErrorResponse "Product item is not deleted"
+// @Router /products/{id}/items/{item_id}/restore [post]
+func (h *ProductHandler) RestoreProductItem(c *gin.Context) {
+	gatewayHandler := NewGatewayHandler(h.gatewayService, h.logger)
+	gatewayHandler.ProxyRequest(c)
+}
+
 // GetProductVariations handles GET /products/:id/variations
 // @Summary Get product variations with options
 // @Description Get all variations (Color, Size, etc.) with their options for a product
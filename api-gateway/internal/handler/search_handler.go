@@ -53,3 +53,20 @@ func (h *SearchHandler) SearchProducts(c *gin.Context) {
 	gatewayHandler.ProxyRequest(c)
 }
 
+// SearchAll handles GET /api/v1/search/all
+// @Summary Universal search
+// @Description Search products, shops and categories in parallel for the search dropdown. Each group has its total and a next_cursor to load more with type=<group>; a group that cannot be searched in time is returned empty with unavailable=true
+// @Tags Search
+// @Produce json
+// @Param q query string true "Search keyword"
+// @Param type query string false "Only this group, to load more (products, shops, categories)"
+// @Param cursor query string false "next_cursor of the group named by type"
+// @Param limit query int false "Items per group (max 20)" default(5)
+// @Success 200 {object} map[string]interface{} "Results grouped by products, shops and categories"
+// @Failure 400 {object} models.ErrorResponse "Missing q, unknown type or invalid cursor"
+// @Failure 500 {object} models.ErrorResponse "Internal server error"
+// @Router /search/all [get]
+func (h *SearchHandler) SearchAll(c *gin.Context) {
+	gatewayHandler := NewGatewayHandler(h.gatewayService, h.logger)
+	gatewayHandler.ProxyRequest(c)
+}
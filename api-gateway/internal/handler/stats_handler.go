@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"api-gateway/pkg/stats"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatsHandler serves the gateway's rolling request statistics
+type StatsHandler struct {
+	recorder *stats.Recorder
+}
+
+// NewStatsHandler creates a new stats handler
+func NewStatsHandler(recorder *stats.Recorder) *StatsHandler {
+	return &StatsHandler{recorder: recorder}
+}
+
+// GetStats handles GET /gateway/stats
+// @Summary Gateway request statistics (admin)
+// @Description Per-route request counts, error rates and p95 latency, and per-service availability, over the last 1m, 5m and 15m. Counted by this gateway instance since its start
+// @Tags Gateway
+// @Produce json
+// @Success 200 {object} stats.Snapshot "Request statistics"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Security BearerAuth
+// @Router /gateway/stats [get]
+// @Router /api/gateway/stats [get]
+func (h *StatsHandler) GetStats(c *gin.Context) {
+	c.JSON(http.StatusOK, h.recorder.Snapshot())
+}
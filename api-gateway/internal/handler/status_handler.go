@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"api-gateway/pkg/circuitbreaker"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatusHandler serves the state of the gateway's per-service circuit breakers
+type StatusHandler struct {
+	breakers *circuitbreaker.Set // nil when circuit breaking is disabled
+}
+
+// NewStatusHandler creates a new status handler
+func NewStatusHandler(breakers *circuitbreaker.Set) *StatusHandler {
+	return &StatusHandler{breakers: breakers}
+}
+
+// GetStatus handles GET /gateway/status
+// @Summary Gateway circuit breaker states (admin)
+// @Description State of each backend service's circuit breaker on this gateway instance: closed (calls go through), open (calls answered 503 until retry_at) or half_open (probing), with consecutive failures and trips since start
+// @Tags Gateway
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Circuit breaker states"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Security BearerAuth
+// @Router /gateway/status [get]
+// @Router /api/gateway/status [get]
+func (h *StatusHandler) GetStatus(c *gin.Context) {
+	if h.breakers == nil {
+		c.JSON(http.StatusOK, gin.H{"circuit_breaker_enabled": false, "services": []circuitbreaker.Status{}})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"circuit_breaker_enabled": true, "services": h.breakers.Statuses()})
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"api-gateway/internal/service"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// maxTrackBodyBytes bounds the body of a tracking batch (50 events with properties)
+const maxTrackBodyBytes = 256 << 10
+
+// TrackingHandler accepts clickstream events from clients
+type TrackingHandler struct {
+	trackingService *service.TrackingService // nil when clickstream tracking is disabled
+	logger          *zap.Logger
+}
+
+// NewTrackingHandler creates a new tracking handler
+func NewTrackingHandler(trackingService *service.TrackingService, logger *zap.Logger) *TrackingHandler {
+	return &TrackingHandler{
+		trackingService: trackingService,
+		logger:          logger,
+	}
+}
+
+// Track handles POST /api/v1/events/track
+// @Summary Track client events
+// @Description Batch of up to 50 clickstream events (view, click, add_to_cart, search) of one session, forwarded to Kafka for recommendations and analytics. view, click and add_to_cart need product_id, add_to_cart a quantity, search a query; occurred_at must be within the last 24 hours. One invalid event rejects the batch. Signed-in users and guests are identified by the gateway
+// @Tags Events
+// @Accept json
+// @Produce json
+// @Param request body service.TrackRequest true "Events"
+// @Success 202 {object} map[string]int "Events accepted"
+// @Failure 400 {object} map[string]interface{} "Invalid events"
+// @Failure 413 {object} map[string]string "Batch too large"
+// @Failure 429 {object} map[string]interface{} "Rate limit exceeded"
+// @Failure 503 {object} map[string]string "Tracking disabled"
+// @Router /api/v1/events/track [post]
+func (h *TrackingHandler) Track(c *gin.Context) {
+	if h.trackingService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "event tracking is disabled"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxTrackBodyBytes)
+	var req service.TrackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "event batch too large"})
+			return
+		}
+		respondBindError(c, err)
+		return
+	}
+
+	visitor := service.TrackVisitor{
+		UserID:    c.GetString("user_id"),
+		GuestID:   c.GetString("guest_id"),
+		UserAgent: c.Request.UserAgent(),
+	}
+	accepted, err := h.trackingService.Track(c.Request.Context(), visitor, &req)
+	if errors.Is(err, service.ErrInvalidEvent) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("Failed to track events", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to track events"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"accepted": accepted})
+}
@@ -3,8 +3,8 @@ package middleware
 import (
 	"api-gateway/config"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"strings"
 
@@ -21,209 +21,305 @@ type SessionData struct {
 	UserID int64  `json:"user_id"`
 }
 
-// AuthMiddleware validates JWT tokens for protected routes
+// UserHeaders are the identity headers of the caller forwarded to backend services
+// Only the gateway sets them, from a verified access token; copies sent by clients are dropped
+var UserHeaders = []string{"X-User-Id", "X-User-Role", "X-User-Email", "X-Impersonator-Id", "X-Impersonation-Id"}
+
+// AuthMiddleware validates JWT tokens and their session for protected routes
 // This implements authentication for the API Gateway
 // Supports both Cookie-based (preferred) and Authorization header authentication
-func AuthMiddleware(cfg *config.JWTConfig, logger *zap.Logger) gin.HandlerFunc {
+// A request already authenticated by RouteAuthMiddleware is not verified again
+func AuthMiddleware(cfg *config.JWTConfig, redisClient *redis.Client, logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var tokenString string
+		if c.GetBool("authenticated") {
+			c.Next()
+			return
+		}
+		if !authenticate(c, cfg, redisClient, logger) {
+			return
+		}
+		c.Next()
+	}
+}
 
-		// PRIORITY 1: Try to get token from HttpOnly cookie (most secure)
-		if cookieToken, err := c.Cookie("access_token"); err == nil && cookieToken != "" {
-			tokenString = cookieToken
-			log.Printf("[AUTH] Token found in cookie")
-		} else {
-			// PRIORITY 2: Fallback to Authorization header (for compatibility)
-			authHeader := c.GetHeader("Authorization")
-			if authHeader == "" {
-				logger.Warn("Missing authorization credentials (no cookie or header)")
-				c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing authorization credentials"})
-				c.Abort()
-				return
-			}
+// RouteAuthMiddleware enforces authentication at the gateway, for every route, so backend
+// services can trust the identity headers they receive instead of verifying tokens again:
+// client copies of the UserHeaders are dropped, and requests to routes that require auth
+// (RequireAuth in the service registry, see requiresAuth) are rejected with 401 before
+// being proxied unless they carry a valid access token of a live session
+func RouteAuthMiddleware(cfg *config.JWTConfig, requiresAuth func(method, path string) bool, redisClient *redis.Client, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, header := range UserHeaders {
+			c.Request.Header.Del(header)
+		}
 
-			// Normalize Authorization header: auto-add "Bearer " prefix if missing
-			if strings.HasPrefix(authHeader, "Bearer ") {
-				tokenString = strings.TrimPrefix(authHeader, "Bearer ")
-			} else if strings.HasPrefix(authHeader, "bearer ") {
-				tokenString = strings.TrimPrefix(strings.ToLower(authHeader), "bearer ")
-			} else {
-				tokenString = strings.TrimSpace(authHeader)
+		if c.Request.Method != http.MethodOptions && requiresAuth(c.Request.Method, c.Request.URL.Path) {
+			if !authenticate(c, cfg, redisClient, logger) {
+				return
 			}
-			log.Printf("[AUTH] Token found in Authorization header")
 		}
+		c.Next()
+	}
+}
 
-		// Validate token is not empty
-		if tokenString == "" {
-			log.Printf("[AUTH] Empty token")
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization credentials"})
-			c.Abort()
-			return
-		}
+// authenticate verifies the access token of the request and its session, then stores the
+// user in the context. Responds 401 and aborts the request when the token is missing or
+// invalid, or its session was revoked (logout, password change, ended impersonation)
+func authenticate(c *gin.Context, cfg *config.JWTConfig, redisClient *redis.Client, logger *zap.Logger) bool {
+	var tokenString string
 
-		// Parse and validate the token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			// Validate the signing method
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				log.Printf("[AUTH] Invalid signing method: %v", token.Method)
-				return nil, jwt.ErrSignatureInvalid
-			}
-			log.Printf("[AUTH] Validating token with secret (len=%d)", len(cfg.Secret))
-			return []byte(cfg.Secret), nil
-		})
-
-		if err != nil {
-			preview := tokenString
-			if len(preview) > 20 {
-				preview = preview[:20] + "..."
-			}
-			log.Printf("[AUTH] Token validation failed: %v, token_preview=%s", err, preview)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token", "details": err.Error()})
+	// PRIORITY 1: Try to get token from HttpOnly cookie (most secure)
+	if cookieToken, err := c.Cookie("access_token"); err == nil && cookieToken != "" {
+		tokenString = cookieToken
+	} else {
+		// PRIORITY 2: Fallback to Authorization header (for compatibility)
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			logger.Warn("Missing authorization credentials (no cookie or header)")
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing authorization credentials"})
 			c.Abort()
-			return
+			return false
 		}
 
-		if !token.Valid {
-			log.Printf("[AUTH] Token is not valid")
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
-			c.Abort()
-			return
+		// Normalize Authorization header: auto-add "Bearer " prefix if missing
+		if strings.HasPrefix(authHeader, "Bearer ") {
+			tokenString = strings.TrimPrefix(authHeader, "Bearer ")
+		} else if strings.HasPrefix(authHeader, "bearer ") {
+			tokenString = strings.TrimPrefix(strings.ToLower(authHeader), "bearer ")
+		} else {
+			tokenString = strings.TrimSpace(authHeader)
 		}
+	}
 
-		// Extract claims and store in context
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			// Convert user_id to string for consistency
-			if userIDFloat, ok := claims["user_id"].(float64); ok {
-				userID := fmt.Sprintf("%.0f", userIDFloat)
-				c.Set("user_id", userID)
+	// Validate token is not empty
+	if tokenString == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization credentials"})
+		c.Abort()
+		return false
+	}
 
-				// Also set as uint for backend services compatibility
-				c.Set("user_id_uint", uint(userIDFloat))
-				log.Printf("[AUTH] User authenticated user_id=%s", userID)
-			}
-			if email, ok := claims["email"].(string); ok {
-				c.Set("email", email)
-			}
-			if role, ok := claims["role"].(string); ok {
-				c.Set("role", role)
-			}
-		}
+	// Parse and validate the token
+	claims, err := parseAccessToken(tokenString, cfg)
+	if err != nil {
+		logger.Debug("token validation failed", zap.Error(err), zap.String("path", c.Request.URL.Path))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token", "details": err.Error()})
+		c.Abort()
+		return false
+	}
 
-		// Store token for forwarding to backend services
-		// Create Bearer token format for header forwarding
-		bearerToken := "Bearer " + tokenString
-		c.Set("auth_header", bearerToken)
-		log.Printf("[AUTH] Authentication successful")
+	// Extract claims and store in context
+	// Convert user_id to string for consistency
+	userIDFloat, ok := claims["user_id"].(float64)
+	if !ok {
+		logger.Debug("token has no user_id claim", zap.String("path", c.Request.URL.Path))
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+		c.Abort()
+		return false
+	}
+	userID := fmt.Sprintf("%.0f", userIDFloat)
+	c.Set("user_id", userID)
 
-		c.Next()
+	// Also set as uint for backend services compatibility
+	c.Set("user_id_uint", uint(userIDFloat))
+
+	if email, ok := claims["email"].(string); ok {
+		c.Set("email", email)
 	}
+	if role, ok := claims["role"].(string); ok {
+		c.Set("role", role)
+	}
+	setImpersonation(c, claims, logger)
+
+	if !checkSession(c, userID, redisClient, logger) {
+		return false
+	}
+
+	// Store token for forwarding to backend services
+	// Create Bearer token format for header forwarding
+	bearerToken := "Bearer " + tokenString
+	c.Set("auth_header", bearerToken)
+	c.Set("authenticated", true)
+	logger.Debug("request authenticated", zap.String("user_id", userID))
+	return true
 }
 
-// SessionMiddleware validates session_id từ cookie với Redis
-// Kiểm tra user có sở hữu session này không
-func SessionMiddleware(logger *zap.Logger, redisClient *redis.Client) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Lấy user_id từ context (set bởi AuthMiddleware)
-		userIDVal, exists := c.Get("user_id")
-		if !exists {
-			log.Printf("[SESSION] user_id not found in context - auth middleware should run first")
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing user_id in context"})
-			c.Abort()
-			return
-		}
-		userID, ok := userIDVal.(string)
-		if !ok {
-			log.Printf("[SESSION] user_id in context is not string, type=%T, value=%v", userIDVal, userIDVal)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid user_id type in context"})
-			c.Abort()
-			return
-		}
+// parseAccessToken verifies an access token issued by identity-service (HS256 with the
+// shared JWT secret, expiry required) and returns its claims
+func parseAccessToken(tokenString string, cfg *config.JWTConfig) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		return []byte(cfg.Secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+	// Only access tokens authenticate requests (tokens without a type predate the claim)
+	if tokenType, ok := claims["type"].(string); ok && tokenType != "access" {
+		return nil, fmt.Errorf("%w: not an access token", jwt.ErrTokenInvalidClaims)
+	}
+	return claims, nil
+}
 
-		// Lấy session_id từ cookie
-		sessionID, err := c.Cookie("session_id")
-		if err != nil {
-			log.Printf("[SESSION] Missing session_id cookie user_id=%s err=%v", userID, err)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing session_id cookie"})
-			c.Abort()
-			return
-		}
+// checkSession validates the session of an authenticated request (see liveSession)
+// Responds 401 and aborts the request when it has none
+func checkSession(c *gin.Context, userID string, redisClient *redis.Client, logger *zap.Logger) bool {
+	sessionID, reason := liveSession(c, userID, c.GetString("impersonation_id"), redisClient, logger)
+	if reason != "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": reason})
+		c.Abort()
+		return false
+	}
+	c.Set("session_id", sessionID)
+	return true
+}
 
-		// Lấy session JSON từ Redis
-		key := fmt.Sprintf("session:%s", sessionID)
-		sessionJSON, err := redisClient.Get(c.Request.Context(), key).Result()
-		if err != nil {
-			log.Printf("[SESSION] Session not found or expired key=%s user_id=%s err=%v", key, userID, err)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired session"})
-			c.Abort()
-			return
-		}
-		log.Printf("[SESSION] Loaded session from redis key=%s value=%s", key, sessionJSON)
+// liveSession checks the session of a request against Redis: the session_id cookie
+// (impersonation tokens are bound to their own session, the sid claim) must name a live
+// session of the token's user. Revoked sessions are deleted from Redis by identity-service,
+// so their still unexpired access tokens stop working here
+// Returns the session ID, or why the request has no valid session
+func liveSession(c *gin.Context, userID, impersonationID string, redisClient *redis.Client, logger *zap.Logger) (string, string) {
+	sessionID, err := c.Cookie("session_id")
+	if impersonationID != "" {
+		sessionID, err = impersonationID, nil
+	}
+	if err != nil || sessionID == "" {
+		logger.Debug("missing session_id cookie", zap.String("user_id", userID))
+		return "", "Missing session_id cookie"
+	}
 
-		// Parse JSON -> SessionData
-		var session SessionData
-		if err := json.Unmarshal([]byte(sessionJSON), &session); err != nil {
-			log.Printf("[SESSION] Failed to unmarshal session JSON key=%s err=%v", key, err)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid session data"})
-			c.Abort()
-			return
-		}
-		sessionUserID := fmt.Sprintf("%d", session.UserID)
-
-		// Kiểm tra user_id từ token có match với session không
-		if sessionUserID != userID {
-			log.Printf("\n[SESSION ERROR] ===================================\n"+
-				"| Reason: User Mismatch\n"+
-				"| Token UID:   %s\n"+
-				"| Session UID: %s\n"+
-				"| Raw Session: %s\n"+
-				"===================================================",
-				userID, sessionUserID, sessionJSON)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session user mismatch"})
-			c.Abort()
-			return
+	key := fmt.Sprintf("session:%s", sessionID)
+	sessionJSON, err := redisClient.Get(c.Request.Context(), key).Result()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			logger.Warn("failed to load session", zap.String("user_id", userID), zap.Error(err))
 		}
+		return "", "Invalid or expired session"
+	}
 
-		// Lưu session_id vào context
-		c.Set("session_id", sessionID)
-		log.Printf("[SESSION] Session validated successfully user_id=%s session_id=%s", userID, sessionID)
+	var session SessionData
+	if err := json.Unmarshal([]byte(sessionJSON), &session); err != nil {
+		logger.Warn("invalid session data", zap.String("user_id", userID), zap.Error(err))
+		return "", "Invalid session data"
+	}
+
+	// The session must belong to the user of the token
+	if sessionUserID := fmt.Sprintf("%d", session.UserID); sessionUserID != userID {
+		logger.Warn("session user mismatch",
+			zap.String("user_id", userID),
+			zap.String("session_user_id", sessionUserID),
+		)
+		return "", "Session user mismatch"
+	}
+	return sessionID, ""
+}
 
+// AdminMiddleware only allows ADMIN users, for admin routes served by the gateway itself
+// Must run after AuthMiddleware, which stores the role claim in the context
+func AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != "ADMIN" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin permission required"})
+			return
+		}
 		c.Next()
 	}
 }
 
 // OptionalAuthMiddleware allows requests with or without authentication
-// Useful for routes that have optional authentication
-func OptionalAuthMiddleware(cfg *config.JWTConfig, logger *zap.Logger) gin.HandlerFunc {
+// Useful for routes that have optional authentication (e.g. guest carts)
+// Reads the access_token cookie first, then the Authorization header; an invalid token,
+// or one whose session was revoked, is ignored and the request continues as a guest
+func OptionalAuthMiddleware(cfg *config.JWTConfig, redisClient *redis.Client, logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
+		tokenString, claims := optionalClaims(c, cfg)
+		userIDFloat, ok := claims["user_id"].(float64)
+		if !ok {
 			c.Next()
 			return
 		}
-
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
+		userID := fmt.Sprintf("%.0f", userIDFloat)
+		_, impersonationID := impersonation(claims)
+		sessionID, reason := liveSession(c, userID, impersonationID, redisClient, logger)
+		if reason != "" {
+			logger.Debug("token without a live session, continuing as guest",
+				zap.String("user_id", userID),
+				zap.String("reason", reason),
+			)
 			c.Next()
 			return
 		}
 
-		tokenString := parts[1]
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return []byte(cfg.Secret), nil
-		})
-
-		if err == nil && token.Valid {
-			if claims, ok := token.Claims.(jwt.MapClaims); ok {
-				userID := fmt.Sprintf("%.0f", claims["user_id"].(float64))
-				c.Set("user_id", userID)
-				c.Set("email", claims["email"])
-				c.Set("role", claims["role"])
-			}
+		c.Set("user_id", userID)
+		c.Set("auth_header", "Bearer "+tokenString)
+		c.Set("session_id", sessionID)
+		if email, ok := claims["email"].(string); ok {
+			c.Set("email", email)
+		}
+		if role, ok := claims["role"].(string); ok {
+			c.Set("role", role)
 		}
+		setImpersonation(c, claims, logger)
 
 		c.Next()
 	}
 }
+
+// optionalClaims returns the access token of the request (access_token cookie, then
+// Authorization header) and its claims; nil claims without a valid token
+func optionalClaims(c *gin.Context, cfg *config.JWTConfig) (string, jwt.MapClaims) {
+	tokenString, _ := c.Cookie("access_token")
+	if tokenString == "" {
+		parts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			tokenString = parts[1]
+		}
+	}
+	if tokenString == "" {
+		return "", nil
+	}
+
+	claims, err := parseAccessToken(tokenString, cfg)
+	if err != nil {
+		return "", nil
+	}
+	return tokenString, claims
+}
+
+// setImpersonation marks requests made with an admin impersonation token (act claim, see
+// identity-service) so the session check uses the token's own session and backends can
+// log the action as performed by the admin on behalf of the user
+func setImpersonation(c *gin.Context, claims jwt.MapClaims, logger *zap.Logger) {
+	adminID, sessionID := impersonation(claims)
+	if adminID == 0 {
+		return
+	}
+
+	c.Set("impersonator_id", fmt.Sprintf("%.0f", adminID))
+	c.Set("impersonation_id", sessionID)
+	logger.Info("impersonated request",
+		zap.String("impersonator_id", fmt.Sprintf("%.0f", adminID)),
+		zap.String("user_id", c.GetString("user_id")),
+		zap.String("impersonation_id", sessionID),
+		zap.String("method", c.Request.Method),
+		zap.String("path", c.Request.URL.Path),
+	)
+}
+
+// impersonation returns the admin and session of an impersonation token (act and sid
+// claims); zero values for a regular token
+func impersonation(claims jwt.MapClaims) (float64, string) {
+	act, ok := claims["act"].(map[string]interface{})
+	if !ok {
+		return 0, ""
+	}
+	adminID, _ := act["user_id"].(float64)
+	sessionID, _ := claims["sid"].(string)
+	if adminID == 0 || sessionID == "" {
+		return 0, ""
+	}
+	return adminID, sessionID
+}
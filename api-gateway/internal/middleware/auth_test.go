@@ -0,0 +1,265 @@
+package middleware
+
+import (
+	"api-gateway/config"
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// fakeRedis is a minimal RESP server answering GET from a map (enough for the session check)
+type fakeRedis struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func startFakeRedis(t *testing.T, data map[string]string) (*fakeRedis, *redis.Client) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	srv := &fakeRedis{data: data}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go srv.serve(conn)
+		}
+	}()
+	client := redis.NewClient(&redis.Options{Addr: ln.Addr().String(), DisableIdentity: true})
+	t.Cleanup(func() {
+		client.Close()
+		ln.Close()
+	})
+	return srv, client
+}
+
+func (s *fakeRedis) delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, key)
+}
+
+func (s *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		if len(args) == 2 && strings.EqualFold(args[0], "GET") {
+			s.mu.Lock()
+			value, ok := s.data[args[1]]
+			s.mu.Unlock()
+			if !ok {
+				fmt.Fprint(conn, "$-1\r\n")
+				continue
+			}
+			fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(value), value)
+			continue
+		}
+		fmt.Fprintf(conn, "-ERR unknown command '%s'\r\n", args[0]) // HELLO: the client falls back to RESP2
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		if _, err := r.ReadString('\n'); err != nil { // $<len>
+			return nil, err
+		}
+		arg, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, strings.TrimSuffix(arg, "\r\n"))
+	}
+	return args, nil
+}
+
+func accessToken(t *testing.T, cfg *config.JWTConfig, claims jwt.MapClaims) string {
+	t.Helper()
+	claims["type"] = "access"
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(cfg.Secret))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return token
+}
+
+// TestRouteAuthMiddlewareChecksSession covers routes protected only by the service registry
+// (RequireAuth): a valid token is not enough once its session is gone from Redis
+func TestRouteAuthMiddlewareChecksSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.JWTConfig{Secret: "test-secret"}
+	store, redisClient := startFakeRedis(t, map[string]string{
+		"session:s-1":   `{"id":"s-1","user_id":42}`,
+		"session:other": `{"id":"other","user_id":7}`,
+		"session:imp-1": `{"id":"imp-1","user_id":42}`,
+	})
+
+	router := gin.New()
+	router.Use(RouteAuthMiddleware(cfg, func(method, path string) bool { return path == "/private" }, redisClient, zap.NewNop()))
+	router.Any("/*path", func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("user_id"))
+	})
+
+	userToken := accessToken(t, cfg, jwt.MapClaims{"user_id": 42})
+	impersonationToken := accessToken(t, cfg, jwt.MapClaims{
+		"user_id": 42,
+		"sid":     "imp-1",
+		"act":     map[string]interface{}{"user_id": 1},
+	})
+
+	send := func(path, token, sessionID string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		if sessionID != "" {
+			req.AddCookie(&http.Cookie{Name: "session_id", Value: sessionID})
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	tests := []struct {
+		name      string
+		path      string
+		token     string
+		sessionID string
+		want      int
+	}{
+		{"public route", "/public", "", "", http.StatusOK},
+		{"live session", "/private", userToken, "s-1", http.StatusOK},
+		{"no token", "/private", "", "s-1", http.StatusUnauthorized},
+		{"no session cookie", "/private", userToken, "", http.StatusUnauthorized},
+		{"unknown session", "/private", userToken, "s-404", http.StatusUnauthorized},
+		{"session of another user", "/private", userToken, "other", http.StatusUnauthorized},
+		{"impersonation uses its own session", "/private", impersonationToken, "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := send(tt.path, tt.token, tt.sessionID); got != tt.want {
+				t.Fatalf("status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	// Logout / ended impersonation: identity-service deletes the session from Redis
+	store.delete("session:s-1")
+	store.delete("session:imp-1")
+	if got := send("/private", userToken, "s-1"); got != http.StatusUnauthorized {
+		t.Errorf("revoked session: status = %d, want %d", got, http.StatusUnauthorized)
+	}
+	if got := send("/private", impersonationToken, ""); got != http.StatusUnauthorized {
+		t.Errorf("ended impersonation: status = %d, want %d", got, http.StatusUnauthorized)
+	}
+}
+
+// TestAuthMiddlewareSkipsAuthenticatedRequests checks that a request verified by
+// RouteAuthMiddleware is not checked again
+func TestAuthMiddlewareSkipsAuthenticatedRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.JWTConfig{Secret: "test-secret"}
+	_, redisClient := startFakeRedis(t, map[string]string{})
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) { c.Set("authenticated", true) })
+	router.GET("/private", AuthMiddleware(cfg, redisClient, zap.NewNop()), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/private", nil))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
+
+// TestOptionalAuthMiddlewareDropsRevokedSessions checks that guest-capable routes (cart,
+// search, ...) only act as the user while the token's session is live
+func TestOptionalAuthMiddlewareDropsRevokedSessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.JWTConfig{Secret: "test-secret"}
+	store, redisClient := startFakeRedis(t, map[string]string{
+		"session:s-1":   `{"id":"s-1","user_id":42}`,
+		"session:imp-1": `{"id":"imp-1","user_id":42}`,
+	})
+
+	router := gin.New()
+	router.GET("/cart", OptionalAuthMiddleware(cfg, redisClient, zap.NewNop()), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("user_id")+"|"+c.GetString("impersonator_id")+"|"+c.GetString("auth_header"))
+	})
+
+	userToken := accessToken(t, cfg, jwt.MapClaims{"user_id": 42, "role": "USER"})
+	impersonationToken := accessToken(t, cfg, jwt.MapClaims{
+		"user_id": 42,
+		"sid":     "imp-1",
+		"act":     map[string]interface{}{"user_id": 1},
+	})
+
+	send := func(token, sessionID string) string {
+		req := httptest.NewRequest(http.MethodGet, "/cart", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		if sessionID != "" {
+			req.AddCookie(&http.Cookie{Name: "session_id", Value: sessionID})
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d (optional auth never rejects)", w.Code, http.StatusOK)
+		}
+		return w.Body.String()
+	}
+
+	if got := send("", ""); got != "||" {
+		t.Errorf("guest = %q, want no identity", got)
+	}
+	if got := send(userToken, "s-1"); got != "42||Bearer "+userToken {
+		t.Errorf("live session = %q, want user 42", got)
+	}
+	if got := send(impersonationToken, ""); got != "42|1|Bearer "+impersonationToken {
+		t.Errorf("live impersonation = %q, want user 42 impersonated by 1", got)
+	}
+	if got := send(userToken, ""); got != "||" {
+		t.Errorf("no session cookie = %q, want a guest", got)
+	}
+
+	store.delete("session:s-1")
+	store.delete("session:imp-1")
+	if got := send(userToken, "s-1"); got != "||" {
+		t.Errorf("revoked session = %q, want a guest", got)
+	}
+	if got := send(impersonationToken, ""); got != "||" {
+		t.Errorf("ended impersonation = %q, want a guest", got)
+	}
+}
@@ -1,7 +1,6 @@
 package middleware
 
 import (
-	"api-gateway/config"
 	"strconv"
 	"strings"
 
@@ -10,52 +9,61 @@ import (
 )
 
 // CORSMiddleware creates a custom CORS middleware with proper credentials support
-func CORSMiddleware(cfg *config.CORSConfig, logger *zap.Logger) gin.HandlerFunc {
+// Settings come from the HTTP policy, so config changes apply without a restart.
+// Origins outside the environment's list get no CORS headers (the browser blocks the
+// response), except in development where they are reflected to ease local testing
+func CORSMiddleware(policy *HTTPPolicy, logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		origin := c.Request.Header.Get("Origin")
+		snapshot := policy.load()
+		cfg := snapshot.cors
 
 		// Set CORS headers for all requests with Origin header
 		if origin != "" {
-			allowedOrigin := getMatchedOrigin(origin, cfg.AllowedOrigins)
+			allowedOrigin := getMatchedOrigin(origin, snapshot.origins)
 
-			if allowedOrigin == "" {
+			if allowedOrigin == "" && snapshot.environment == "development" {
 				// Origin not in allowed list - use it anyway for development
 				allowedOrigin = origin
 				logger.Warn("Origin not in allowed list",
 					zap.String("origin", origin),
-					zap.Strings("allowed_origins", cfg.AllowedOrigins),
+					zap.String("environment", snapshot.environment),
+				)
+			} else if allowedOrigin == "" {
+				logger.Warn("Origin rejected by CORS policy",
+					zap.String("origin", origin),
+					zap.String("environment", snapshot.environment),
 				)
 			}
 
-			h := c.Writer.Header()
-			h.Set("Access-Control-Allow-Origin", allowedOrigin)
-
-			// Debug: log config
-			logger.Debug("CORS config",
-				zap.Strings("allowed_methods", cfg.AllowedMethods),
-				zap.Int("allowed_methods_count", len(cfg.AllowedMethods)),
-			)
+			if allowedOrigin != "" {
+				h := c.Writer.Header()
+				h.Set("Access-Control-Allow-Origin", allowedOrigin)
+				h.Add("Vary", "Origin")
 
-			// Hardcode methods if config is empty (fallback for safety)
-			allowedMethods := strings.Join(cfg.AllowedMethods, ", ")
-			if allowedMethods == "" {
-				allowedMethods = "GET, POST, PUT, PATCH, DELETE, OPTIONS"
-				logger.Warn("AllowedMethods config is empty, using default")
-			}
-			h.Set("Access-Control-Allow-Methods", allowedMethods)
+				// Hardcode methods if config is empty (fallback for safety)
+				allowedMethods := strings.Join(cfg.AllowedMethods, ", ")
+				if allowedMethods == "" {
+					allowedMethods = "GET, POST, PUT, PATCH, DELETE, OPTIONS"
+					logger.Warn("AllowedMethods config is empty, using default")
+				}
+				h.Set("Access-Control-Allow-Methods", allowedMethods)
 
-			h.Set("Access-Control-Allow-Credentials", "true")
-			h.Set("Access-Control-Expose-Headers", strings.Join(cfg.ExposeHeaders, ", "))
+				if cfg.AllowCredentials {
+					h.Set("Access-Control-Allow-Credentials", "true")
+				}
+				h.Set("Access-Control-Expose-Headers", strings.Join(cfg.ExposeHeaders, ", "))
 
-			// Use requested headers if provided, otherwise use config
-			if reqHeaders := c.Request.Header.Get("Access-Control-Request-Headers"); reqHeaders != "" {
-				h.Set("Access-Control-Allow-Headers", reqHeaders)
-			} else {
-				h.Set("Access-Control-Allow-Headers", strings.Join(cfg.AllowedHeaders, ", "))
-			}
+				// Use requested headers if provided, otherwise use config
+				if reqHeaders := c.Request.Header.Get("Access-Control-Request-Headers"); reqHeaders != "" {
+					h.Set("Access-Control-Allow-Headers", reqHeaders)
+				} else {
+					h.Set("Access-Control-Allow-Headers", strings.Join(cfg.AllowedHeaders, ", "))
+				}
 
-			if cfg.MaxAge > 0 {
-				h.Set("Access-Control-Max-Age", strconv.Itoa(int(cfg.MaxAge.Seconds())))
+				if cfg.MaxAge > 0 {
+					h.Set("Access-Control-Max-Age", strconv.Itoa(int(cfg.MaxAge.Seconds())))
+				}
 			}
 		}
 
@@ -69,12 +77,10 @@ func CORSMiddleware(cfg *config.CORSConfig, logger *zap.Logger) gin.HandlerFunc
 	}
 }
 
-// getMatchedOrigin checks if origin is in allowed list
-func getMatchedOrigin(origin string, allowedOrigins []string) string {
-	for _, allowed := range allowedOrigins {
-		if allowed == origin {
-			return origin
-		}
+// getMatchedOrigin checks if origin is in allowed list ("*" allows any origin)
+func getMatchedOrigin(origin string, allowedOrigins map[string]bool) string {
+	if allowedOrigins[origin] || allowedOrigins["*"] {
+		return origin
 	}
 	return ""
 }
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"api-gateway/config"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// CSRFMiddleware protects cookie-authenticated requests against cross-site request forgery
+// (signed double-submit cookie): every response carries a CSRF token in a cookie readable
+// by the frontend and in the CSRF header, and state-changing requests (POST, PUT, PATCH,
+// DELETE) that carry an auth cookie must send the cookie's token back in the CSRF header.
+// Token-auth API clients (Authorization header, no auth cookies) and ExemptPaths skip the check
+func CSRFMiddleware(cfg *config.CSRFConfig, logger *zap.Logger) gin.HandlerFunc {
+	if !cfg.Enabled {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	cookieName := cfg.CookieName
+	if cookieName == "" {
+		cookieName = "csrf_token"
+	}
+	headerName := cfg.HeaderName
+	if headerName == "" {
+		headerName = "X-CSRF-Token"
+	}
+	secret := []byte(cfg.Secret)
+
+	return func(c *gin.Context) {
+		cookieToken, _ := c.Cookie(cookieName)
+		valid := validCSRFToken(secret, cookieToken)
+
+		token := cookieToken
+		if !valid {
+			nonce, err := newGuestID()
+			if err != nil {
+				logger.Error("Failed to generate CSRF token", zap.Error(err))
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate CSRF token"})
+				c.Abort()
+				return
+			}
+			token = nonce + "." + signToken(secret, nonce)
+			c.SetSameSite(http.SameSiteLaxMode)
+			// Not HttpOnly: the frontend reads it to echo it in the header
+			c.SetCookie(cookieName, token, 0, "/", "", c.Request.TLS != nil, false)
+		}
+		c.Header(headerName, token)
+
+		if !csrfProtected(c, cfg) {
+			c.Next()
+			return
+		}
+
+		sent := c.GetHeader(headerName)
+		if !valid || sent == "" || subtle.ConstantTimeCompare([]byte(sent), []byte(cookieToken)) != 1 {
+			logger.Warn("CSRF token missing or invalid",
+				zap.String("path", c.Request.URL.Path),
+				zap.String("method", c.Request.Method),
+				zap.String("ip", c.ClientIP()),
+			)
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "Invalid or missing CSRF token",
+				"code":  "CSRF_TOKEN_INVALID",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// csrfProtected reports whether the request must carry a valid CSRF token:
+// a state-changing method, not exempt, and authenticated by cookie
+func csrfProtected(c *gin.Context, cfg *config.CSRFConfig) bool {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+
+	if hasPathPrefix(c.Request.URL.Path, cfg.ExemptPaths) {
+		return false
+	}
+
+	for _, name := range cfg.AuthCookies {
+		if value, err := c.Cookie(name); err == nil && value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// validCSRFToken checks the signature of a <nonce>.<hmac> CSRF token
+func validCSRFToken(secret []byte, token string) bool {
+	nonce, sig, ok := strings.Cut(token, ".")
+	if !ok || nonce == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(signToken(secret, nonce)), []byte(sig)) == 1
+}
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"api-gateway/config"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// GuestTokenHeader carries the guest token for clients without cookies (mobile apps)
+// and returns a newly issued token on the response
+const GuestTokenHeader = "X-Guest-Token"
+
+// GuestIDHeader is the guest identity forwarded to backend services
+const GuestIDHeader = "X-Guest-Id"
+
+// GuestMiddleware gives every browser/device an anonymous, signed guest identity
+// Token format: <guest_id>.<expires_unix>.<hmac>; a missing, tampered or expired
+// token is replaced by a new one, and a valid token is re-issued with the same
+// guest_id once half of its lifetime has passed, so active guests keep their identity.
+// The guest_id is stored in the context and forwarded as X-Guest-Id, which keys guest
+// carts, recently viewed products and rate limiting before login
+func GuestMiddleware(cfg *config.GuestConfig, logger *zap.Logger) gin.HandlerFunc {
+	ttl := cfg.TTL
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	cookieName := cfg.CookieName
+	if cookieName == "" {
+		cookieName = "guest_token"
+	}
+	secret := []byte(cfg.Secret)
+
+	return func(c *gin.Context) {
+		// Only the gateway may set the guest identity for backend services
+		c.Request.Header.Del(GuestIDHeader)
+
+		token := c.GetHeader(GuestTokenHeader)
+		if cookieToken, err := c.Cookie(cookieName); err == nil && cookieToken != "" {
+			token = cookieToken
+		}
+
+		guestID, expiresAt, ok := parseGuestToken(secret, token)
+		switch {
+		case !ok:
+			id, err := newGuestID()
+			if err != nil {
+				logger.Error("Failed to generate guest ID", zap.Error(err))
+				c.Next()
+				return
+			}
+			guestID = id
+			issueGuestToken(c, secret, cookieName, guestID, ttl)
+		case time.Until(expiresAt) < ttl/2:
+			issueGuestToken(c, secret, cookieName, guestID, ttl)
+			c.Set("guest_verified", true)
+		default:
+			c.Set("guest_verified", true)
+		}
+
+		c.Set("guest_id", guestID)
+		c.Next()
+	}
+}
+
+// issueGuestToken signs a token for guestID and sends it as cookie and response header
+func issueGuestToken(c *gin.Context, secret []byte, cookieName, guestID string, ttl time.Duration) {
+	expiresAt := time.Now().Add(ttl).Unix()
+	payload := fmt.Sprintf("%s.%d", guestID, expiresAt)
+	token := payload + "." + signToken(secret, payload)
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(cookieName, token, int(ttl.Seconds()), "/", "", c.Request.TLS != nil, true)
+	c.Header(GuestTokenHeader, token)
+}
+
+// parseGuestToken verifies the signature and expiry of a guest token
+func parseGuestToken(secret []byte, token string) (string, time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] == "" {
+		return "", time.Time{}, false
+	}
+
+	expected := signToken(secret, parts[0]+"."+parts[1])
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(parts[2])) != 1 {
+		return "", time.Time{}, false
+	}
+
+	expiresUnix, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	expiresAt := time.Unix(expiresUnix, 0)
+	if time.Now().After(expiresAt) {
+		return "", time.Time{}, false
+	}
+	return parts[0], expiresAt, true
+}
+
+// signToken returns the base64url HMAC-SHA256 of payload (guest and CSRF tokens)
+func signToken(secret []byte, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// newGuestID returns a random 128-bit guest ID
+func newGuestID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -1,90 +1,130 @@
 package middleware
 
 import (
+	"api-gateway/config"
+	"api-gateway/pkg/ratelimit"
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
-	"api-gateway/config"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/time/rate"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
+	"golang.org/x/time/rate"
 )
 
-// rateLimiter stores rate limiters per IP address
-type rateLimiter struct {
+// localRateLimiter keeps in-memory token buckets per group and client key, used while
+// Redis is unreachable (each gateway instance then limits on its own)
+type localRateLimiter struct {
 	limiters map[string]*rate.Limiter
 	mu       sync.Mutex
-	config   *config.RateLimitConfig
 }
 
-// newRateLimiter creates a new rate limiter
-func newRateLimiter(cfg *config.RateLimitConfig) *rateLimiter {
-	return &rateLimiter{
-		limiters: make(map[string]*rate.Limiter),
-		config:   cfg,
-	}
+// newLocalRateLimiter creates the in-memory fallback and starts dropping idle buckets
+func newLocalRateLimiter() *localRateLimiter {
+	rl := &localRateLimiter{limiters: make(map[string]*rate.Limiter)}
+	rl.cleanup()
+	return rl
 }
 
-// getLimiter returns a rate limiter for the given IP
-func (rl *rateLimiter) getLimiter(ip string) *rate.Limiter {
+// allow takes a token from the bucket of key, creating it with the given rate
+func (rl *localRateLimiter) allow(key string, r ratelimit.Rate) *ratelimit.Result {
 	rl.mu.Lock()
-	defer rl.mu.Unlock()
-
-	limiter, exists := rl.limiters[ip]
+	limiter, exists := rl.limiters[key]
 	if !exists {
-		// Create a new limiter: requests per minute converted to requests per second
-		limiter = rate.NewLimiter(
-			rate.Limit(rl.config.RequestsPerMinute)/60,
-			rl.config.Burst,
-		)
-		rl.limiters[ip] = limiter
+		// Requests per minute converted to requests per second
+		limiter = rate.NewLimiter(rate.Limit(r.RequestsPerMinute)/60, r.Burst)
+		rl.limiters[key] = limiter
 	}
-
-	return limiter
+	rl.mu.Unlock()
+
+	perToken := time.Minute / time.Duration(r.RequestsPerMinute)
+	allowed := limiter.Allow()
+	tokens := limiter.Tokens()
+	result := &ratelimit.Result{
+		Allowed:    allowed,
+		Remaining:  int(math.Max(0, tokens)),
+		ResetAfter: time.Duration((float64(r.Burst) - tokens) * float64(perToken)),
+	}
+	if !allowed {
+		result.RetryAfter = time.Duration((1 - tokens) * float64(perToken))
+	}
+	return result
 }
 
-// cleanup removes old limiters periodically
-func (rl *rateLimiter) cleanup() {
+// cleanup drops the buckets that refilled completely every hour
+func (rl *localRateLimiter) cleanup() {
 	ticker := time.NewTicker(1 * time.Hour)
 	go func() {
 		for range ticker.C {
 			rl.mu.Lock()
-			// In production, you'd want more sophisticated cleanup logic
-			// For now, we keep all limiters in memory
+			for key, limiter := range rl.limiters {
+				if limiter.Tokens() >= float64(limiter.Burst()) {
+					delete(rl.limiters, key)
+				}
+			}
 			rl.mu.Unlock()
 		}
 	}()
 }
 
-var globalRateLimiter *rateLimiter
-
-// RateLimitMiddleware implements rate limiting per IP address
+// RateLimitMiddleware implements rate limiting per client
 // This prevents abuse and ensures fair resource usage
-func RateLimitMiddleware(cfg *config.RateLimitConfig, logger *zap.Logger) gin.HandlerFunc {
+// Token buckets live in Redis so the limits hold across gateway instances; each route
+// group has its own buckets. Signed-in users are limited per user (valid access token),
+// clients presenting a valid guest token per guest (so shoppers behind a shared NAT do
+// not throttle each other); everything else is limited per IP address. Responses carry
+// X-RateLimit-Limit/Remaining/Reset, rejected requests get 429 with Retry-After.
+// Must run after GuestMiddleware
+func RateLimitMiddleware(cfg *config.RateLimitConfig, jwtCfg *config.JWTConfig, redisClient *redis.Client, logger *zap.Logger) gin.HandlerFunc {
 	if !cfg.Enabled {
 		return func(c *gin.Context) {
 			c.Next()
 		}
 	}
 
-	if globalRateLimiter == nil {
-		globalRateLimiter = newRateLimiter(cfg)
-		globalRateLimiter.cleanup()
-	}
+	limiter := ratelimit.NewLimiter(redisClient, cfg.KeyPrefix)
+	fallback := newLocalRateLimiter()
+	var lastRedisWarning atomic.Int64
 
 	return func(c *gin.Context) {
-		// Get client IP
-		ip := c.ClientIP()
+		// Get client key (user, verified guest or IP) and the limits of its route group
+		key, signedIn := rateLimitKey(c, jwtCfg)
+		group, limits := rateLimitGroup(cfg, c.Request.URL.Path, signedIn)
+		bucket := group + ":" + key
+
+		result, err := limiter.Allow(c.Request.Context(), bucket, limits)
+		if err != nil {
+			// Redis is unreachable: limit per instance rather than not at all (warned once a minute)
+			if now := time.Now().Unix(); now-lastRedisWarning.Load() >= 60 {
+				lastRedisWarning.Store(now)
+				logger.Warn("Rate limit store unavailable, using in-memory limits", zap.Error(err))
+			}
+			result = fallback.allow(bucket, limits)
+		}
 
-		// Get or create limiter for this IP
-		limiter := globalRateLimiter.getLimiter(ip)
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limits.Burst))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
 
 		// Check if request is allowed
-		if !limiter.Allow() {
-			logger.Warn("Rate limit exceeded", zap.String("ip", ip))
+		if !result.Allowed {
+			retryAfter := ceilSeconds(result.RetryAfter)
+			logger.Warn("Rate limit exceeded",
+				zap.String("key", key),
+				zap.String("group", group),
+				zap.String("ip", c.ClientIP()),
+				zap.Int("retry_after", retryAfter),
+			)
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
 			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error": "Rate limit exceeded. Please try again later.",
+				"error":       "Rate limit exceeded. Please try again later.",
+				"retry_after": retryAfter,
 			})
 			c.Abort()
 			return
@@ -94,3 +134,46 @@ func RateLimitMiddleware(cfg *config.RateLimitConfig, logger *zap.Logger) gin.Ha
 	}
 }
 
+// rateLimitKey returns the limiter key of a request and whether it is a signed-in user
+// A guest token issued on this very request is not trusted yet, otherwise dropping the
+// cookie on every request would get a fresh limiter each time
+func rateLimitKey(c *gin.Context, jwtCfg *config.JWTConfig) (string, bool) {
+	if _, claims := optionalClaims(c, jwtCfg); claims != nil {
+		if userID, ok := claims["user_id"].(float64); ok {
+			return fmt.Sprintf("user:%.0f", userID), true
+		}
+	}
+	if c.GetBool("guest_verified") {
+		return "guest:" + c.GetString("guest_id"), false
+	}
+	return "ip:" + c.ClientIP(), false
+}
+
+// rateLimitGroup returns the route group of the path (longest matching prefix, "default"
+// when none) and the limits of the client in it
+func rateLimitGroup(cfg *config.RateLimitConfig, path string, signedIn bool) (string, ratelimit.Rate) {
+	name := "default"
+	requestsPerMinute, burst := cfg.RequestsPerMinute, cfg.Burst
+	userRequestsPerMinute, userBurst := cfg.UserRequestsPerMinute, cfg.UserBurst
+	matched := 0
+	for _, group := range cfg.Groups {
+		if len(group.PathPrefix) > matched && strings.HasPrefix(path, group.PathPrefix) {
+			name, matched = group.Name, len(group.PathPrefix)
+			requestsPerMinute, burst = group.RequestsPerMinute, group.Burst
+			userRequestsPerMinute, userBurst = group.UserRequestsPerMinute, group.UserBurst
+		}
+	}
+
+	if signedIn && userRequestsPerMinute > 0 {
+		requestsPerMinute = userRequestsPerMinute
+	}
+	if signedIn && userBurst > 0 {
+		burst = userBurst
+	}
+	return name, ratelimit.Rate{RequestsPerMinute: requestsPerMinute, Burst: burst}
+}
+
+// ceilSeconds rounds a wait up to whole seconds, as Retry-After expects
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"api-gateway/pkg/errorreport"
+	"errors"
+	"net/http"
+	"runtime/debug"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Recovery recovers panics in handlers: it logs the panic with its stack trace,
+// reports it to Sentry (see pkg/errorreport) with the request context and answers
+// 500 {"error": "internal server error", "request_id": ...}.
+// It replaces gin.Recovery and must be the first middleware of the router
+func Recovery(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered) // deliberate abort, handled by net/http
+			}
+
+			// The client went away - nothing to answer and nothing to report
+			if isBrokenPipe(recovered) {
+				logger.Warn("client connection closed", zap.String("path", c.Request.URL.Path), zap.Any("error", recovered))
+				c.Abort()
+				return
+			}
+
+			requestID := c.GetHeader(RequestIDHeader)
+			logger.Error("panic recovered",
+				zap.Any("panic", recovered),
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.Request.URL.Path),
+				zap.String("route", c.FullPath()),
+				zap.String("request_id", requestID),
+				zap.String("stack", string(debug.Stack())),
+			)
+			errorreport.ReportPanic(recovered, errorreport.Request{
+				HTTP:      c.Request,
+				Route:     c.FullPath(),
+				RequestID: requestID,
+				UserID:    c.GetString("user_id"),
+			})
+
+			if c.Writer.Written() {
+				c.Abort() // part of the response is already sent
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error", "request_id": requestID})
+		}()
+		c.Next()
+	}
+}
+
+// isBrokenPipe reports whether a panic comes from writing to a closed client connection
+func isBrokenPipe(recovered interface{}) bool {
+	err, ok := recovered.(error)
+	return ok && (errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET))
+}
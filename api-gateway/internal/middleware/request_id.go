@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the ID that correlates the logs and query metrics of one
+// request across the gateway and the backend services
+const RequestIDHeader = "X-Request-Id"
+
+// maxRequestIDLength bounds client-provided request IDs (they end up in logs)
+const maxRequestIDLength = 64
+
+// RequestIDMiddleware assigns every request an ID. A well-formed X-Request-Id sent by the
+// client is kept so it can correlate its own logs; otherwise a new one is generated.
+// The ID is set on the request, so ProxyRequest forwards it to backend services, and
+// returned in the response
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		c.Request.Header.Set(RequestIDHeader, id)
+		c.Set("request_id", id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// validRequestID accepts IDs of letters, digits, '-' and '_' only
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
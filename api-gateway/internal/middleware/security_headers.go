@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"api-gateway/config"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HTTPPolicy holds the CORS and security header settings used by CORSMiddleware and
+// SecurityHeadersMiddleware; Update swaps them at runtime (config hot-reload)
+type HTTPPolicy struct {
+	current atomic.Pointer[httpPolicySnapshot]
+}
+
+// httpPolicySnapshot is one immutable version of the policy
+type httpPolicySnapshot struct {
+	environment string
+	cors        config.CORSConfig
+	origins     map[string]bool // Allowed origins of the environment ("*" = any)
+	security    config.SecurityHeadersConfig
+	hsts        string // Prebuilt Strict-Transport-Security value ("" = disabled)
+}
+
+// NewHTTPPolicy creates the policy from the loaded configuration
+func NewHTTPPolicy(cfg *config.Config) *HTTPPolicy {
+	p := &HTTPPolicy{}
+	p.Update(cfg)
+	return p
+}
+
+// Update replaces the policy with the CORS and security header settings of cfg
+func (p *HTTPPolicy) Update(cfg *config.Config) {
+	snapshot := &httpPolicySnapshot{
+		environment: cfg.Server.Environment,
+		cors:        cfg.CORS,
+		origins:     make(map[string]bool),
+		security:    cfg.Security,
+	}
+	for _, origin := range cfg.CORS.AllowedOrigins {
+		snapshot.origins[origin] = true
+	}
+	for _, origin := range cfg.CORS.EnvironmentOrigins[strings.ToLower(cfg.Server.Environment)] {
+		snapshot.origins[origin] = true
+	}
+	if cfg.Security.HSTSMaxAge > 0 {
+		snapshot.hsts = fmt.Sprintf("max-age=%d", int(cfg.Security.HSTSMaxAge.Seconds()))
+		if cfg.Security.HSTSIncludeSubdomains {
+			snapshot.hsts += "; includeSubDomains"
+		}
+	}
+	p.current.Store(snapshot)
+}
+
+// AllowedOrigins returns the origins allowed in the current environment
+func (p *HTTPPolicy) AllowedOrigins() []string {
+	snapshot := p.current.Load()
+	origins := make([]string, 0, len(snapshot.origins))
+	for origin := range snapshot.origins {
+		origins = append(origins, origin)
+	}
+	return origins
+}
+
+// load returns the current policy
+func (p *HTTPPolicy) load() *httpPolicySnapshot {
+	return p.current.Load()
+}
+
+// SecurityHeadersMiddleware sets HSTS, X-Content-Type-Options, X-Frame-Options,
+// Referrer-Policy and Content-Security-Policy on every response
+// HSTS is only sent over HTTPS (directly or behind a TLS-terminating proxy)
+func SecurityHeadersMiddleware(policy *HTTPPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		snapshot := policy.load()
+		cfg := snapshot.security
+		h := c.Writer.Header()
+
+		if snapshot.hsts != "" && (c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https") {
+			h.Set("Strict-Transport-Security", snapshot.hsts)
+		}
+		if cfg.ContentTypeOptions != "" {
+			h.Set("X-Content-Type-Options", cfg.ContentTypeOptions)
+		}
+		if cfg.FrameOptions != "" {
+			h.Set("X-Frame-Options", cfg.FrameOptions)
+		}
+		if cfg.ReferrerPolicy != "" {
+			h.Set("Referrer-Policy", cfg.ReferrerPolicy)
+		}
+		if cfg.ContentSecurityPolicy != "" && !hasPathPrefix(c.Request.URL.Path, cfg.CSPExemptPaths) {
+			h.Set("Content-Security-Policy", cfg.ContentSecurityPolicy)
+		}
+
+		c.Next()
+	}
+}
+
+// hasPathPrefix reports whether path starts with one of prefixes
+func hasPathPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -117,6 +117,7 @@ type AddressInfo struct {
 type Product struct {
 	ID          uint      `json:"id" example:"1"`
 	Name        string    `json:"name" example:"iPhone 15 Pro"`
+	Slug        string    `json:"slug" example:"iphone-15-pro"`
 	Description string    `json:"description" example:"Latest iPhone with A17 Pro chip"`
 	Price       float64   `json:"price" example:"999.99"`
 	SKU         string    `json:"sku" example:"IPH15P-001"`
@@ -0,0 +1,101 @@
+package kafka
+
+import (
+	"api-gateway/internal/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+// PublisherOptions configures the Kafka clickstream publisher
+type PublisherOptions struct {
+	Brokers      []string
+	Topics       Topics
+	WriteTimeout time.Duration
+	BatchTimeout time.Duration // Flush a partially filled batch after this long
+}
+
+// eventPublisher implements the EventPublisher interface
+// Clickstream is best-effort analytics data: the writer is asynchronous, so tracking
+// requests never wait for Kafka, and events that cannot be written are dropped and logged
+type eventPublisher struct {
+	writer *kafka.Writer
+	opts   PublisherOptions
+}
+
+// NewEventPublisher creates a new Kafka clickstream publisher
+// Each event type is written to its own topic (see Topics); messages are keyed and
+// hash-partitioned by visitor so the events of one visitor stay in order
+func NewEventPublisher(opts PublisherOptions, logger *zap.Logger) domain.EventPublisher {
+	if opts.BatchTimeout <= 0 {
+		opts.BatchTimeout = 50 * time.Millisecond
+	}
+
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(opts.Brokers...),
+		Balancer:     &kafka.Hash{}, // same key -> same partition
+		WriteTimeout: opts.WriteTimeout,
+		BatchTimeout: opts.BatchTimeout,
+		RequiredAcks: kafka.RequireOne,
+		Async:        true,
+		Completion: func(messages []kafka.Message, err error) {
+			if err != nil {
+				logger.Warn("Failed to write clickstream events, dropped",
+					zap.Int("count", len(messages)),
+					zap.Error(err),
+				)
+			}
+		},
+	}
+
+	return &eventPublisher{
+		writer: writer,
+		opts:   opts,
+	}
+}
+
+// PublishClickstream queues the events for writing; it does not wait for Kafka
+func (p *eventPublisher) PublishClickstream(ctx context.Context, events []*domain.ClickstreamEvent) error {
+	messages := make([]kafka.Message, 0, len(events))
+	for _, event := range events {
+		eventJSON, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to marshal event: %w", err)
+		}
+
+		key := event.SessionID
+		if event.UserID != "" {
+			key = "user:" + event.UserID
+		} else if event.GuestID != "" {
+			key = "guest:" + event.GuestID
+		}
+
+		messages = append(messages, kafka.Message{
+			Topic: p.opts.Topics.For("clickstream_" + event.Type),
+			Key:   []byte(key),
+			Value: eventJSON,
+			Headers: []kafka.Header{
+				{Key: "event_type", Value: []byte(event.Type)},
+				{Key: "timestamp", Value: []byte(event.OccurredAt.Format(time.RFC3339))},
+			},
+		})
+	}
+
+	if err := p.writer.WriteMessages(ctx, messages...); err != nil {
+		return fmt.Errorf("failed to queue clickstream events: %w", err)
+	}
+	return nil
+}
+
+// Close flushes the queued events and closes the Kafka writer
+// This should be called during graceful shutdown
+func (p *eventPublisher) Close() error {
+	if p.writer != nil {
+		return p.writer.Close()
+	}
+	return nil
+}
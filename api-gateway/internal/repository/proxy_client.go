@@ -2,38 +2,43 @@ package repository
 
 import (
 	"api-gateway/internal/domain"
+	"api-gateway/pkg/circuitbreaker"
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"math/rand/v2"
+	"net"
 	"net/http"
-	"strings"
+	"slices"
 	"time"
-)
-
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
 
-func getHeaderKeys(m map[string]string) []string {
-	keys := make([]string, 0, len(m))
-	for k := range m {
-		keys = append(keys, k)
-	}
-	return keys
-}
+	"go.uber.org/zap"
+)
 
 // proxyClient implements the ProxyClient interface
 // This handles HTTP proxying to backend microservices
+// Calls go through the service's circuit breaker (when enabled), so a service that keeps
+// failing is answered immediately instead of after its timeout; idempotent calls that
+// failed to connect or got 502/503/504 are retried with exponential backoff
 type proxyClient struct {
 	httpClient *http.Client
+	retry      RetryPolicy
+	breakers   *circuitbreaker.Set // nil when circuit breaking is disabled
+	logger     *zap.Logger
+}
+
+// RetryPolicy controls the retries of proxied calls
+type RetryPolicy struct {
+	MaxRetries     int           // Retries after the first attempt; 0 disables
+	InitialBackoff time.Duration // Wait before the first retry, doubled after every retry
+	MaxBackoff     time.Duration // Upper bound of the wait
+	Methods        []string      // Retried methods (idempotent only)
 }
 
 // NewProxyClient creates a new HTTP proxy client
-func NewProxyClient(timeout time.Duration) domain.ProxyClient {
+func NewProxyClient(timeout time.Duration, retry RetryPolicy, breakers *circuitbreaker.Set, logger *zap.Logger) domain.ProxyClient {
 	return &proxyClient{
 		httpClient: &http.Client{
 			Timeout: timeout,
@@ -42,11 +47,18 @@ func NewProxyClient(timeout time.Duration) domain.ProxyClient {
 				return http.ErrUseLastResponse
 			},
 		},
+		retry:    retry,
+		breakers: breakers,
+		logger:   logger,
 	}
 }
 
 // ProxyRequest proxies an HTTP request to a backend service
+// Returns an error wrapping circuitbreaker.ErrOpen when the service's breaker rejects
+// the call; after a retry was rejected, or ctx was canceled while waiting to retry, the
+// previous attempt's outcome is returned
 func (p *proxyClient) ProxyRequest(
+	ctx context.Context,
 	service *domain.Service,
 	path string,
 	method string,
@@ -56,13 +68,6 @@ func (p *proxyClient) ProxyRequest(
 	// Build the full URL
 	// Ensure base URL doesn't end with / and path starts with /
 	baseURL := service.BaseURL
-	// Debug: print which base URL is used
-	log.Printf("[PROXY DEBUG] service=%s baseURL=%s path=%s method=%s", service.Name, baseURL, path, method)
-	// Safety net: if baseURL still points to docker hostname, override to localhost for local dev
-	if strings.Contains(baseURL, "product-service") {
-		baseURL = "http://localhost:8080"
-		log.Printf("[PROXY DEBUG] overriding product-service hostname to %s", baseURL)
-	}
 	if len(baseURL) > 0 && baseURL[len(baseURL)-1] == '/' {
 		baseURL = baseURL[:len(baseURL)-1]
 	}
@@ -71,14 +76,62 @@ func (p *proxyClient) ProxyRequest(
 	}
 	url := baseURL + path
 
+	var (
+		response *domain.ProxyResponse
+		err      error
+	)
+	for attempt := 0; ; attempt++ {
+		var breaker *circuitbreaker.Breaker
+		if p.breakers != nil {
+			breaker = p.breakers.Get(service.Name)
+			if openErr := breaker.Allow(); openErr != nil {
+				if attempt > 0 {
+					return response, err
+				}
+				return nil, fmt.Errorf("service %s unavailable: %w", service.Name, openErr)
+			}
+		}
+
+		response, err = p.do(ctx, url, method, headers, body)
+		if breaker != nil {
+			breaker.Record(upstreamFailure(response, err))
+		}
+		if !p.shouldRetry(method, response, err, attempt) {
+			return response, err
+		}
+
+		if ctx.Err() != nil {
+			return response, err
+		}
+		backoff := p.backoff(attempt)
+		p.logger.Warn("retrying proxied request",
+			zap.String("service", service.Name),
+			zap.String("method", method),
+			zap.String("path", path),
+			zap.Int("attempt", attempt+1),
+			zap.Duration("backoff", backoff),
+			zap.NamedError("last_error", err),
+		)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return response, err
+		case <-timer.C:
+		}
+	}
+}
+
+// do sends one attempt of a proxied request
+func (p *proxyClient) do(ctx context.Context, url string, method string, headers map[string]string, body []byte) (*domain.ProxyResponse, error) {
 	// Create the request
 	var req *http.Request
 	var err error
 
 	if body != nil && len(body) > 0 {
-		req, err = http.NewRequest(method, url, bytes.NewReader(body))
+		req, err = http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
 	} else {
-		req, err = http.NewRequest(method, url, nil)
+		req, err = http.NewRequestWithContext(ctx, method, url, nil)
 	}
 
 	if err != nil {
@@ -94,23 +147,6 @@ func (p *proxyClient) ProxyRequest(
 		req.Header.Set(key, value)
 	}
 
-	// CRITICAL: Double-check Authorization header is set
-	// If it's in the headers map, ensure it's in the request
-	if authVal, exists := headers["Authorization"]; exists && authVal != "" {
-		// Force set it again to be absolutely sure
-		req.Header.Set("Authorization", authVal)
-		fmt.Printf("[PROXY] ✅ Set Authorization: %s...\n", authVal[:min(50, len(authVal))])
-
-		// Verify it's actually in the request
-		if finalAuth := req.Header.Get("Authorization"); finalAuth != "" {
-			fmt.Printf("[PROXY] ✅ Verified Authorization in request\n")
-		} else {
-			fmt.Printf("[PROXY] ❌ ERROR: Authorization missing after setting!\n")
-		}
-	} else {
-		fmt.Printf("[PROXY] ❌ ERROR: Authorization NOT in headers map! Keys: %v\n", getHeaderKeys(headers))
-	}
-
 	// Set content type if body exists
 	if body != nil && len(body) > 0 {
 		if req.Header.Get("Content-Type") == "" {
@@ -144,6 +180,51 @@ func (p *proxyClient) ProxyRequest(
 	}, nil
 }
 
+// shouldRetry reports whether a failed attempt is retried: idempotent methods only, on
+// connection errors and 502/503/504. A timed-out attempt is not retried, the client
+// already waited the full timeout
+func (p *proxyClient) shouldRetry(method string, response *domain.ProxyResponse, err error, attempt int) bool {
+	if attempt >= p.retry.MaxRetries || !slices.Contains(p.retry.Methods, method) {
+		return false
+	}
+	if err != nil {
+		var netErr net.Error
+		return !(errors.As(err, &netErr) && netErr.Timeout())
+	}
+	return unavailableStatus(response.StatusCode)
+}
+
+// backoff returns the wait before retry attempt+1: InitialBackoff doubled per retry, capped
+// at MaxBackoff, with up to 20% jitter so retries of many clients do not line up
+func (p *proxyClient) backoff(attempt int) time.Duration {
+	wait := p.retry.InitialBackoff << attempt
+	if wait <= 0 || (p.retry.MaxBackoff > 0 && wait > p.retry.MaxBackoff) {
+		wait = p.retry.MaxBackoff
+	}
+	return wait + time.Duration(rand.Int64N(int64(wait)/5+1))
+}
+
+// upstreamFailure returns the failure a circuit breaker counts: transport errors and
+// 502/503/504 (the service is down or overloaded); other statuses are answers. A call
+// canceled by its client (disconnected) is not held against the service
+func upstreamFailure(response *domain.ProxyResponse, err error) error {
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if unavailableStatus(response.StatusCode) {
+		return fmt.Errorf("status %d", response.StatusCode)
+	}
+	return nil
+}
+
+// unavailableStatus reports statuses meaning the service could not handle the request
+func unavailableStatus(status int) bool {
+	return status == http.StatusBadGateway || status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout
+}
+
 // HealthCheck checks if a service is healthy
 func (p *proxyClient) HealthCheck(service *domain.Service) error {
 	url := service.BaseURL + service.HealthCheckPath
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"api-gateway/internal/domain"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// flakyService answers 503 to its first failures calls, then 200
+func flakyService(t *testing.T, failures int32) (*domain.Service, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	return &domain.Service{Name: "test-service", BaseURL: srv.URL}, &calls
+}
+
+func TestProxyRequestRetriesUnavailable(t *testing.T) {
+	service, calls := flakyService(t, 2)
+	client := NewProxyClient(time.Second, RetryPolicy{
+		MaxRetries:     3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		Methods:        []string{http.MethodGet},
+	}, nil, zap.NewNop())
+
+	response, err := client.ProxyRequest(context.Background(), service, "/items", http.MethodGet, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if response.StatusCode != http.StatusOK || calls.Load() != 3 {
+		t.Fatalf("status %d after %d calls, want 200 after 3", response.StatusCode, calls.Load())
+	}
+}
+
+// TestProxyRequestStopsRetryingWhenCanceled checks that the backoff wait ends as soon
+// as the caller gives up instead of sleeping through it and retrying
+func TestProxyRequestStopsRetryingWhenCanceled(t *testing.T) {
+	service, calls := flakyService(t, 100)
+	client := NewProxyClient(time.Second, RetryPolicy{
+		MaxRetries:     3,
+		InitialBackoff: 10 * time.Second,
+		MaxBackoff:     10 * time.Second,
+		Methods:        []string{http.MethodGet},
+	}, nil, zap.NewNop())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	response, err := client.ProxyRequest(ctx, service, "/items", http.MethodGet, nil, nil)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("ProxyRequest returned after %s, want it to stop waiting when ctx is done", elapsed)
+	}
+	if err != nil || response.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("got %v / %v, want the 503 of the last attempt", response, err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("upstream called %d times, want 1", calls.Load())
+	}
+}
+
+func TestProxyRequestCanceledBeforeCall(t *testing.T) {
+	service, calls := flakyService(t, 0)
+	client := NewProxyClient(time.Second, RetryPolicy{MaxRetries: 3, Methods: []string{http.MethodGet}}, nil, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.ProxyRequest(ctx, service, "/items", http.MethodGet, nil, nil); err == nil {
+		t.Fatal("expected an error for a canceled request")
+	}
+	if calls.Load() != 0 {
+		t.Errorf("upstream called %d times, want 0", calls.Load())
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"api-gateway/config"
 	"api-gateway/internal/handler"
 	"api-gateway/internal/middleware"
+	"api-gateway/pkg/stats"
 
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
@@ -21,6 +22,12 @@ func SetupRouter(
 	productHandler *handler.ProductHandler,
 	categoryHandler *handler.CategoryHandler,
 	searchHandler *handler.SearchHandler,
+	logLevelHandler *handler.LogLevelHandler,
+	statsHandler *handler.StatsHandler,
+	statusHandler *handler.StatusHandler,
+	trackingHandler *handler.TrackingHandler,
+	statsRecorder *stats.Recorder,
+	httpPolicy *middleware.HTTPPolicy,
 	cfg *config.Config,
 	logger *zap.Logger,
 	redisClient *redis.Client,
@@ -28,11 +35,23 @@ func SetupRouter(
 	// Use gin.New() instead of gin.Default() to avoid default middlewares
 	router := gin.New()
 
-	// Add recovery middleware
-	router.Use(gin.Recovery())
+	// Client IP: X-Forwarded-For is only trusted from the configured load balancers, so
+	// clients cannot pick the IP of their rate limit buckets (validated with the config)
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logger.Fatal("Invalid trusted proxies", zap.Error(err))
+	}
+
+	// Panic recovery: logs the stack trace and reports to Sentry (see pkg/errorreport)
+	router.Use(middleware.Recovery(logger))
 
 	// CRITICAL: Custom CORS middleware MUST be first
-	router.Use(middleware.CORSMiddleware(&cfg.CORS, logger))
+	router.Use(middleware.CORSMiddleware(httpPolicy, logger))
+
+	// Security headers (HSTS, nosniff, CSP...) owned by the gateway
+	router.Use(middleware.SecurityHeadersMiddleware(httpPolicy))
+
+	// Request ID forwarded to backend services (correlates logs and query metrics)
+	router.Use(middleware.RequestIDMiddleware())
 
 	// Skip logging OPTIONS requests (CORS preflight) to reduce noise
 	router.Use(middleware.SkipOptionsLoggingMiddleware(logger))
@@ -41,8 +60,24 @@ func SetupRouter(
 	router.Use(middleware.RequestLoggingMiddleware(logger))
 	router.Use(middleware.ErrorLoggingMiddleware(logger))
 
-	// Rate limiting middleware
-	router.Use(middleware.RateLimitMiddleware(&cfg.RateLimit, logger))
+	// Per-route request counts, error rates and latency for GET /gateway/stats
+	router.Use(middleware.StatsMiddleware(statsRecorder))
+
+	// Opt-in latency/error injection for resilience testing (no-op unless enabled)
+	router.Use(middleware.FaultInjection(&cfg.FaultInjection, "api-gateway", logger))
+
+	// Anonymous guest identity (keys guest carts, recently viewed and rate limiting)
+	router.Use(middleware.GuestMiddleware(&cfg.Guest, logger))
+
+	// Drop client-sent identity headers and verify the token and session of routes registered
+	// with RequireAuth before they are proxied (X-User-Id/X-User-Role are set from its claims)
+	router.Use(middleware.RouteAuthMiddleware(&cfg.JWT, gatewayHandler.RequiresAuth, redisClient, logger))
+
+	// Rate limiting per user, guest or IP (token buckets in Redis, see pkg/ratelimit)
+	router.Use(middleware.RateLimitMiddleware(&cfg.RateLimit, &cfg.JWT, redisClient, logger))
+
+	// CSRF protection for cookie-authenticated state-changing requests
+	router.Use(middleware.CSRFMiddleware(&cfg.CSRF, logger))
 
 	// Swagger documentation
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -51,6 +86,24 @@ func SetupRouter(
 	router.GET("/health", gatewayHandler.HealthCheck)
 	router.GET("/api/gateway/health", gatewayHandler.HealthCheck)
 
+	// Request statistics of this gateway instance (admin only)
+	gatewayStats := []gin.HandlerFunc{
+		middleware.AuthMiddleware(&cfg.JWT, redisClient, logger),
+		middleware.AdminMiddleware(),
+		statsHandler.GetStats,
+	}
+	router.GET("/gateway/stats", gatewayStats...)
+	router.GET("/api/gateway/stats", gatewayStats...)
+
+	// Circuit breaker states of this gateway instance (admin only)
+	gatewayStatus := []gin.HandlerFunc{
+		middleware.AuthMiddleware(&cfg.JWT, redisClient, logger),
+		middleware.AdminMiddleware(),
+		statusHandler.GetStatus,
+	}
+	router.GET("/gateway/status", gatewayStatus...)
+	router.GET("/api/gateway/status", gatewayStatus...)
+
 	// API routes - all requests go through the gateway
 	api := router.Group("/api")
 	{
@@ -63,6 +116,8 @@ func SetupRouter(
 				products.GET("", productHandler.ListProducts)
 				products.GET("/:id", productHandler.GetProduct)
 				products.GET("/search", productHandler.SearchProducts)
+				products.GET("/slug/:slug", productHandler.GetProductBySlug)
+				products.GET("/batch", gatewayHandler.ProxyRequest) // Batch fetch by IDs (Product Service)
 
 				// Product Items (SKU) routes - Public
 				products.GET("/:id/items", productHandler.GetProductItems)
@@ -71,11 +126,17 @@ func SetupRouter(
 				// Variation routes - Public (for UI selectors)
 				products.GET("/:id/variations", productHandler.GetProductVariations)
 
-				products.POST("", productHandler.CreateProduct) // Protected in handler
+				// Size guide - Public (also embedded in product detail)
+				products.GET("/:id/size-guide", gatewayHandler.ProxyRequest)
+
+				// Reviews with rating summary - Public
+				products.GET("/:id/reviews", gatewayHandler.ProxyRequest)
+
+				products.POST("", productHandler.CreateProduct) // Protected by RouteAuthMiddleware (RequireAuth)
 
 				// Protected routes (auth required)
 				protected := products.Group("")
-				protected.Use(middleware.AuthMiddleware(&cfg.JWT, logger), middleware.SessionMiddleware(logger, redisClient))
+				protected.Use(middleware.AuthMiddleware(&cfg.JWT, redisClient, logger))
 				{
 					protected.PUT("/:id", productHandler.UpdateProduct)
 					protected.PATCH("/:id", productHandler.UpdateProduct)
@@ -86,38 +147,323 @@ func SetupRouter(
 					protected.POST("/:id/items", productHandler.CreateProductItem)
 					protected.PUT("/:id/items/:item_id", productHandler.UpdateProductItem)
 					protected.DELETE("/:id/items/:item_id", productHandler.DeleteProductItem)
+					protected.POST("/:id/items/:item_id/restore", productHandler.RestoreProductItem)
+
+					// Size guide measurements (seller)
+					protected.PUT("/:id/measurements", gatewayHandler.ProxyRequest)
+					protected.DELETE("/:id/measurements", gatewayHandler.ProxyRequest)
 				}
 			}
 
+			// Digital product code pools (seller) - Product Service
+			// Issuing codes is internal (order-service calls product-service directly)
+			productItems := v1.Group("/product-items")
+			productItems.Use(middleware.AuthMiddleware(&cfg.JWT, redisClient, logger))
+			{
+				productItems.POST("/:id/codes", gatewayHandler.ProxyRequest)
+				productItems.GET("/:id/codes/stats", gatewayHandler.ProxyRequest)
+			}
+
+			// Marketplace product import tool (seller) - Product Service
+			tools := v1.Group("/tools")
+			tools.Use(middleware.AuthMiddleware(&cfg.JWT, redisClient, logger))
+			{
+				tools.POST("/product-import", gatewayHandler.ProxyRequest)
+				tools.GET("/product-import/:id", gatewayHandler.ProxyRequest)
+			}
+
+			// Seller inventory Excel export / import - Product Service
+			sellerInventory := v1.Group("/seller/inventory")
+			sellerInventory.Use(middleware.AuthMiddleware(&cfg.JWT, redisClient, logger))
+			{
+				sellerInventory.GET("/export", gatewayHandler.ProxyRequest)
+				sellerInventory.POST("/import", gatewayHandler.ProxyRequest)
+				sellerInventory.POST("/import/:id/commit", gatewayHandler.ProxyRequest)
+			}
+
 			// Category routes (Product Service)
 			categories := v1.Group("/categories")
 			{
 				// Public routes (no auth required)
 				categories.GET("", categoryHandler.ListCategories)
+				categories.GET("/batch", gatewayHandler.ProxyRequest) // Batch fetch by IDs (Product Service)
 				categories.GET("/:id", categoryHandler.GetCategory)
 				categories.GET("/slug/:slug", categoryHandler.GetCategoryBySlug)
 				categories.GET("/:id/children", categoryHandler.GetCategoryChildren)
 				categories.GET("/:id/products", categoryHandler.GetCategoryProducts)
+				categories.GET("/:id/bestsellers", gatewayHandler.ProxyRequest) // Rolling sales ranking (Product Service)
 				categories.POST("", categoryHandler.CreateCategory)
 				categories.PUT("/:id", categoryHandler.UpdateCategory)
 				categories.DELETE("/:id", categoryHandler.DeleteCategory)
+
+				// Size chart templates (Product Service)
+				categories.GET("/:id/size-chart", gatewayHandler.ProxyRequest)
+				categories.PUT("/:id/size-chart", gatewayHandler.ProxyRequest)
+				categories.DELETE("/:id/size-chart", gatewayHandler.ProxyRequest)
 			}
 
-			// Search routes (Search Service)
+			// Search routes (Search Service) - signed-in users or guests
+			// The user or guest identity buckets the visitor into a ranking experiment variant
 			search := v1.Group("/search")
+			search.Use(middleware.OptionalAuthMiddleware(&cfg.JWT, redisClient, logger))
 			{
 				search.GET("", searchHandler.SearchProducts)
+				search.GET("/all", searchHandler.SearchAll)
+				search.POST("/feedback", gatewayHandler.ProxyRequest) // Impressions and clicks of experiment variants
+			}
+
+			// Clickstream events (forwarded to Kafka by the gateway) - signed-in users or guests
+			events := v1.Group("/events")
+			events.Use(middleware.OptionalAuthMiddleware(&cfg.JWT, redisClient, logger))
+			{
+				events.POST("/track", trackingHandler.Track)
+			}
+
+			// Homepage content (Product Service) - Public
+			v1.GET("/content/home", gatewayHandler.ProxyRequest)
+
+			// Product feed (Product Service) - Public
+			v1.GET("/feeds/products", gatewayHandler.ProxyRequest)
+
+			// Admin routes - ADMIN role is checked by the backend service
+			adminContent := v1.Group("/admin")
+			adminContent.Use(middleware.AuthMiddleware(&cfg.JWT, redisClient, logger))
+			{
+				adminContent.GET("/banners", gatewayHandler.ProxyRequest)
+				adminContent.POST("/banners", gatewayHandler.ProxyRequest)
+				adminContent.PUT("/banners/:id", gatewayHandler.ProxyRequest)
+				adminContent.DELETE("/banners/:id", gatewayHandler.ProxyRequest)
+				adminContent.GET("/campaigns", gatewayHandler.ProxyRequest)
+				adminContent.POST("/campaigns", gatewayHandler.ProxyRequest)
+				adminContent.PUT("/campaigns/:id", gatewayHandler.ProxyRequest)
+				adminContent.DELETE("/campaigns/:id", gatewayHandler.ProxyRequest)
+
+				// Global configuration (Identity Service)
+				adminContent.GET("/settings", gatewayHandler.ProxyRequest)
+				adminContent.GET("/settings/:scope/:key", gatewayHandler.ProxyRequest)
+				adminContent.PUT("/settings/:scope/:key", gatewayHandler.ProxyRequest)
+				adminContent.GET("/settings/:scope/:key/audit", gatewayHandler.ProxyRequest)
+
+				// Feature flags (Identity Service)
+				adminContent.GET("/feature-flags", gatewayHandler.ProxyRequest)
+				adminContent.GET("/feature-flags/:key", gatewayHandler.ProxyRequest)
+				adminContent.PUT("/feature-flags/:key", gatewayHandler.ProxyRequest)
+				adminContent.DELETE("/feature-flags/:key", gatewayHandler.ProxyRequest)
+
+				// Transactional email templates (Identity Service)
+				adminContent.GET("/email-templates", gatewayHandler.ProxyRequest)
+				adminContent.POST("/email-templates/preview", gatewayHandler.ProxyRequest)
+				adminContent.GET("/email-templates/:key/:locale", gatewayHandler.ProxyRequest)
+				adminContent.PUT("/email-templates/:key/:locale", gatewayHandler.ProxyRequest)
+				adminContent.GET("/email-templates/:key/:locale/versions", gatewayHandler.ProxyRequest)
+				adminContent.POST("/email-templates/:key/:locale/versions/:version/activate", gatewayHandler.ProxyRequest)
+
+				// Security audit events (Identity Service)
+				adminContent.GET("/security-events", gatewayHandler.ProxyRequest)
+
+				// Admin impersonation of users for support cases (Identity Service)
+				adminContent.POST("/impersonations", gatewayHandler.ProxyRequest)
+				adminContent.GET("/impersonations", gatewayHandler.ProxyRequest)
+				adminContent.DELETE("/impersonations/:id", gatewayHandler.ProxyRequest)
+
+				// CSV exports of users and shops for operations and marketing (Identity Service)
+				adminContent.GET("/users/export", gatewayHandler.ProxyRequest)
+				adminContent.GET("/shops/export", gatewayHandler.ProxyRequest)
+
+				// Background jobs - /admin/jobs/{product|order|identity}/... routed to the owning service
+				adminContent.GET("/jobs/*path", gatewayHandler.ProxyRequest)
+				adminContent.POST("/jobs/*path", gatewayHandler.ProxyRequest)
+				adminContent.DELETE("/jobs/*path", gatewayHandler.ProxyRequest)
+
+				// Async side effect pool stats - /admin/tasks/{product|order}
+				adminContent.GET("/tasks/product", gatewayHandler.ProxyRequest)
+				adminContent.GET("/tasks/order", gatewayHandler.ProxyRequest)
+
+				// Kafka publisher stats and manual buffer flush - /admin/events/{product|order}
+				adminContent.GET("/events/product", gatewayHandler.ProxyRequest)
+				adminContent.POST("/events/product/flush", gatewayHandler.ProxyRequest)
+				adminContent.GET("/events/order", gatewayHandler.ProxyRequest)
+				adminContent.POST("/events/order/flush", gatewayHandler.ProxyRequest)
+
+				// Runtime log level - /admin/log-level/{service}; the gateway serves its own
+				adminContent.GET("/log-level/gateway", middleware.AdminMiddleware(), logLevelHandler.GetLogLevel)
+				adminContent.PUT("/log-level/gateway", middleware.AdminMiddleware(), logLevelHandler.SetLogLevel)
+				adminContent.GET("/log-level/product", gatewayHandler.ProxyRequest)
+				adminContent.PUT("/log-level/product", gatewayHandler.ProxyRequest)
+				adminContent.GET("/log-level/order", gatewayHandler.ProxyRequest)
+				adminContent.PUT("/log-level/order", gatewayHandler.ProxyRequest)
+				adminContent.GET("/log-level/identity", gatewayHandler.ProxyRequest)
+				adminContent.PUT("/log-level/identity", gatewayHandler.ProxyRequest)
+				adminContent.GET("/log-level/search", gatewayHandler.ProxyRequest)
+				adminContent.PUT("/log-level/search", gatewayHandler.ProxyRequest)
+
+				// Search curations: pinned products and boosted shops per keyword/campaign (Search Service)
+				adminContent.GET("/search/curations", gatewayHandler.ProxyRequest)
+				adminContent.POST("/search/curations", gatewayHandler.ProxyRequest)
+				adminContent.GET("/search/curations/:id", gatewayHandler.ProxyRequest)
+				adminContent.PUT("/search/curations/:id", gatewayHandler.ProxyRequest)
+				adminContent.DELETE("/search/curations/:id", gatewayHandler.ProxyRequest)
+				adminContent.GET("/search/curations/:id/audit", gatewayHandler.ProxyRequest)
+
+				// Search ranking experiments: impressions, clicks and CTR per variant (Search Service)
+				adminContent.GET("/search/experiments", gatewayHandler.ProxyRequest)
+				adminContent.GET("/search/experiments/:id", gatewayHandler.ProxyRequest)
+
+				// Inventory reconciliation (Product Service)
+				adminContent.GET("/inventory/reconciliation", gatewayHandler.ProxyRequest)
+				adminContent.POST("/inventory/reconciliation/run", gatewayHandler.ProxyRequest)
+
+				// Catalog quality report and shop scores (Product Service)
+				adminContent.GET("/catalog-quality", gatewayHandler.ProxyRequest)
+				adminContent.GET("/catalog-quality/shops", gatewayHandler.ProxyRequest)
+				adminContent.GET("/catalog-quality/shops/:shop_id", gatewayHandler.ProxyRequest)
+				adminContent.POST("/catalog-quality/run", gatewayHandler.ProxyRequest)
+
+				// Product search across all shops and bulk moderation (Product Service)
+				adminContent.GET("/products/search", gatewayHandler.ProxyRequest)
+				adminContent.POST("/products/moderate", gatewayHandler.ProxyRequest)
+
+				// Seller API quotas: tier limits and seller tiers (Product Service)
+				adminContent.GET("/quotas/tiers", gatewayHandler.ProxyRequest)
+				adminContent.PUT("/quotas/tiers/:tier", gatewayHandler.ProxyRequest)
+				adminContent.GET("/quotas/sellers/:seller", gatewayHandler.ProxyRequest)
+				adminContent.PUT("/quotas/sellers/:seller/tier", gatewayHandler.ProxyRequest)
+
+				// Financial adjustments of orders (Order Service)
+				adminContent.POST("/orders/:id/adjustments", gatewayHandler.ProxyRequest)
+
+				// Accounting journal export for finance (Order Service)
+				adminContent.GET("/accounting/journal/export", gatewayHandler.ProxyRequest)
+
+				// Dispute resolution center (Order Service)
+				adminContent.GET("/disputes", gatewayHandler.ProxyRequest)
+				adminContent.POST("/disputes/:id/review", gatewayHandler.ProxyRequest)
+				adminContent.POST("/disputes/:id/resolve", gatewayHandler.ProxyRequest)
+
+				// Announcement broadcasts to user segments with delivery stats (Order Service)
+				adminContent.GET("/announcements", gatewayHandler.ProxyRequest)
+				adminContent.POST("/announcements", gatewayHandler.ProxyRequest)
+				adminContent.GET("/announcements/:id", gatewayHandler.ProxyRequest)
+				adminContent.POST("/announcements/:id/cancel", gatewayHandler.ProxyRequest)
 			}
 
-			// Cart routes (Order Service) - Protected routes (require authentication)
+			// Shop routes (Identity Service) - Public lookups
+			shops := v1.Group("/shops")
+			{
+				shops.GET("", gatewayHandler.ProxyRequest)
+				shops.GET("/slug/:slug", gatewayHandler.ProxyRequest)
+				shops.GET("/search", gatewayHandler.ProxyRequest)
+				shops.GET("/:id", gatewayHandler.ProxyRequest)
+
+				// Shop collections storefront navigation (Product Service)
+				shops.GET("/:id/collections", gatewayHandler.ProxyRequest)
+				shops.GET("/:id/collections/:collection_id/products", gatewayHandler.ProxyRequest)
+
+				// Shop bestsellers over the rolling sales window (Product Service)
+				shops.GET("/:id/bestsellers", gatewayHandler.ProxyRequest)
+
+				// Shop reviews and rating aggregate (Product Service)
+				shops.GET("/:id/reviews", gatewayHandler.ProxyRequest)
+				shops.GET("/:id/rating", gatewayHandler.ProxyRequest)
+			}
+
+			// Product reviews by buyers who received the product (Product Service)
+			reviews := v1.Group("/reviews")
+			reviews.Use(middleware.AuthMiddleware(&cfg.JWT, redisClient, logger))
+			{
+				reviews.POST("", gatewayHandler.ProxyRequest)
+				reviews.PUT("/:id", gatewayHandler.ProxyRequest)
+				reviews.DELETE("/:id", gatewayHandler.ProxyRequest)
+			}
+
+			// Shop collections management (seller) - Product Service
+			shopCollections := v1.Group("/shops/:id/collections")
+			shopCollections.Use(middleware.AuthMiddleware(&cfg.JWT, redisClient, logger))
+			{
+				shopCollections.POST("", gatewayHandler.ProxyRequest)
+				shopCollections.PUT("/order", gatewayHandler.ProxyRequest)
+				shopCollections.PUT("/:collection_id", gatewayHandler.ProxyRequest)
+				shopCollections.DELETE("/:collection_id", gatewayHandler.ProxyRequest)
+				shopCollections.PUT("/:collection_id/products", gatewayHandler.ProxyRequest)
+			}
+
+			// Cart routes (Order Service) - signed-in users or guests
+			// Guests get a cart keyed by their guest identity, merged into the user's cart after login
 			cart := v1.Group("/cart")
-			cart.Use(middleware.AuthMiddleware(&cfg.JWT, logger))
+			cart.Use(middleware.OptionalAuthMiddleware(&cfg.JWT, redisClient, logger))
 			{
 				cart.GET("", gatewayHandler.ProxyRequest)
 				cart.DELETE("", gatewayHandler.ProxyRequest)
 				cart.POST("/items", gatewayHandler.ProxyRequest)
 				cart.PUT("/items/:product_item_id", gatewayHandler.ProxyRequest)
 				cart.DELETE("/items/:product_item_id", gatewayHandler.ProxyRequest)
+				cart.POST("/price-changes/acknowledge", gatewayHandler.ProxyRequest)
+			}
+
+			// Recently viewed products (Product Service) - per user, or per guest before login
+			recentlyViewed := v1.Group("/users/me/recently-viewed")
+			recentlyViewed.Use(middleware.OptionalAuthMiddleware(&cfg.JWT, redisClient, logger))
+			{
+				recentlyViewed.GET("", gatewayHandler.ProxyRequest)
+				recentlyViewed.POST("", gatewayHandler.ProxyRequest)
+				recentlyViewed.DELETE("", gatewayHandler.ProxyRequest)
+			}
+
+			// Product subscriptions (Order Service) - price drop / back in stock alerts
+			subscriptions := v1.Group("/subscriptions")
+			subscriptions.Use(middleware.AuthMiddleware(&cfg.JWT, redisClient, logger))
+			{
+				subscriptions.GET("", gatewayHandler.ProxyRequest)
+				subscriptions.POST("", gatewayHandler.ProxyRequest)
+				subscriptions.DELETE("/:id", gatewayHandler.ProxyRequest)
+			}
+
+			// In-app notification inbox (Order Service) - bell icon
+			notifications := v1.Group("/notifications")
+			notifications.Use(middleware.AuthMiddleware(&cfg.JWT, redisClient, logger))
+			{
+				notifications.GET("", gatewayHandler.ProxyRequest)
+				notifications.GET("/unread-count", gatewayHandler.ProxyRequest)
+				notifications.PUT("/read-all", gatewayHandler.ProxyRequest)
+				notifications.PUT("/:id/read", gatewayHandler.ProxyRequest)
+			}
+
+			// Order status changes (Order Service) - buyers cancel, sellers confirm, ship and deliver
+			orders := v1.Group("/orders")
+			orders.Use(middleware.AuthMiddleware(&cfg.JWT, redisClient, logger))
+			{
+				orders.PUT("/:id/status", gatewayHandler.ProxyRequest)
+			}
+
+			// Order disputes (Order Service) - buyers open disputes, buyers and sellers add evidence
+			disputes := v1.Group("/disputes")
+			disputes.Use(middleware.AuthMiddleware(&cfg.JWT, redisClient, logger))
+			{
+				disputes.POST("", gatewayHandler.ProxyRequest)
+				disputes.GET("", gatewayHandler.ProxyRequest)
+				disputes.GET("/:id", gatewayHandler.ProxyRequest)
+				disputes.POST("/:id/evidence", gatewayHandler.ProxyRequest)
+			}
+
+			// Vouchers (Order Service) - sellers manage their shop's vouchers, admins platform vouchers
+			vouchers := v1.Group("/vouchers")
+			vouchers.Use(middleware.AuthMiddleware(&cfg.JWT, redisClient, logger))
+			{
+				vouchers.POST("", gatewayHandler.ProxyRequest)
+				vouchers.GET("", gatewayHandler.ProxyRequest)
+				vouchers.GET("/:id", gatewayHandler.ProxyRequest)
+				vouchers.PUT("/:id", gatewayHandler.ProxyRequest)
+				vouchers.DELETE("/:id", gatewayHandler.ProxyRequest)
+			}
+
+			// Payment intents (Payment Service) - buyers pay their pending orders online
+			payments := v1.Group("/payments/intents")
+			payments.Use(middleware.AuthMiddleware(&cfg.JWT, redisClient, logger))
+			{
+				payments.POST("", gatewayHandler.ProxyRequest)
+				payments.GET("/:id", gatewayHandler.ProxyRequest)
+				payments.POST("/:id/confirm", gatewayHandler.ProxyRequest)
+				payments.POST("/:id/cancel", gatewayHandler.ProxyRequest)
 			}
 
 			// Identity service routes - Auth
@@ -131,20 +477,29 @@ func SetupRouter(
 
 			// Logout requires auth to get user_id
 			authProtected := v1.Group("/auth")
-			authProtected.Use(middleware.AuthMiddleware(&cfg.JWT, logger), middleware.SessionMiddleware(logger, redisClient))
+			authProtected.Use(middleware.AuthMiddleware(&cfg.JWT, redisClient, logger))
 			{
 				authProtected.POST("/logout", authHandler.Logout)
 			}
 
 			// Protected identity service routes
 			protectedIdentity := v1.Group("")
-			protectedIdentity.Use(middleware.AuthMiddleware(&cfg.JWT, logger), middleware.SessionMiddleware(logger, redisClient))
+			protectedIdentity.Use(middleware.AuthMiddleware(&cfg.JWT, redisClient, logger))
 			{
 				users := protectedIdentity.Group("/users")
 				{
 					users.GET("/profile", userHandler.GetProfile)
 					users.PUT("/profile", userHandler.UpdateProfile)
 					users.PUT("/password", userHandler.ChangePassword)
+
+					// Notification preferences (Identity Service)
+					users.GET("/me/notification-preferences", gatewayHandler.ProxyRequest)
+					users.PUT("/me/notification-preferences", gatewayHandler.ProxyRequest)
+
+					// Push notification devices (Identity Service)
+					users.GET("/me/devices", gatewayHandler.ProxyRequest)
+					users.POST("/me/devices", gatewayHandler.ProxyRequest)
+					users.DELETE("/me/devices/:device_id", gatewayHandler.ProxyRequest)
 				}
 
 				addresses := protectedIdentity.Group("/addresses")
@@ -156,6 +511,9 @@ func SetupRouter(
 					addresses.DELETE("/:id", addressHandler.DeleteAddress)
 					addresses.PUT("/:id/default", addressHandler.SetDefaultAddress)
 				}
+
+				// Evaluated feature flags for the current user (frontend toggles)
+				protectedIdentity.GET("/feature-flags", gatewayHandler.ProxyRequest)
 			}
 		}
 	}
@@ -2,20 +2,29 @@ package service
 
 import (
 	"api-gateway/internal/domain"
+	"api-gateway/pkg/circuitbreaker"
+	"api-gateway/pkg/stats"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 )
 
+// ErrAuthenticationRequired is returned for a request without a user to a route that requires auth
+var ErrAuthenticationRequired = errors.New("authentication required")
+
 // GatewayService orchestrates request routing and proxying
 // This is the business logic layer for the API Gateway
 type GatewayService struct {
 	serviceRegistry domain.ServiceRegistry
 	proxyClient     domain.ProxyClient
+	stats           *stats.Recorder
 	logger          *zap.Logger
 }
 
@@ -23,11 +32,13 @@ type GatewayService struct {
 func NewGatewayService(
 	serviceRegistry domain.ServiceRegistry,
 	proxyClient domain.ProxyClient,
+	statsRecorder *stats.Recorder,
 	logger *zap.Logger,
 ) *GatewayService {
 	return &GatewayService{
 		serviceRegistry: serviceRegistry,
 		proxyClient:     proxyClient,
+		stats:           statsRecorder,
 		logger:          logger,
 	}
 }
@@ -52,12 +63,17 @@ func (s *GatewayService) RouteRequest(
 		}, fmt.Errorf("service %s not found: %w", serviceName, err)
 	}
 
-	// Note: Authentication is already validated by middleware in the router
-	// Middleware validates JWT token and sets user_id in gin.Context
-	// Handler passes user_id from gin.Context to context.Context
-	// So if we reach here, authentication is already validated
-	// We don't need to check again - just proceed with routing
-	_ = s.findRoute(service, path, method)
+	// The token is verified by middleware in the router (RouteAuthMiddleware for routes
+	// that require auth), which sets user_id in gin.Context; the handler passes it on in
+	// context.Context. Checked again here so a route that requires auth is never proxied
+	// without a user, whatever middleware its router group has
+	if route := s.findRoute(service, path, method); route != nil && route.RequireAuth && ctx.Value("user_id") == nil {
+		return &domain.ProxyResponse{
+			Body:       []byte(`{"error":"authentication required"}`),
+			StatusCode: http.StatusUnauthorized,
+			Headers:    make(map[string][]string),
+		}, ErrAuthenticationRequired
+	}
 
 	// Log the routing attempt for debugging
 	s.logger.Debug("Routing request",
@@ -67,8 +83,26 @@ func (s *GatewayService) RouteRequest(
 		zap.String("base_url", service.BaseURL),
 	)
 
-	// Proxy the request to the backend service
-	proxyResponse, err := s.proxyClient.ProxyRequest(service, path, method, headers, body)
+	// Proxy the request to the backend service; the outcome feeds the service's
+	// availability in GET /gateway/stats
+	start := time.Now()
+	proxyResponse, err := s.proxyClient.ProxyRequest(ctx, service, path, method, headers, body)
+	status := 0
+	if proxyResponse != nil {
+		status = proxyResponse.StatusCode
+	}
+	s.stats.RecordUpstream(serviceName, status, time.Since(start), err)
+	if errors.Is(err, circuitbreaker.ErrOpen) {
+		s.logger.Warn("Circuit breaker open, request rejected",
+			zap.String("service", serviceName),
+			zap.String("path", path),
+		)
+		return &domain.ProxyResponse{
+			Body:       []byte(fmt.Sprintf(`{"error":"service %s is temporarily unavailable"}`, serviceName)),
+			StatusCode: http.StatusServiceUnavailable,
+			Headers:    make(map[string][]string),
+		}, err
+	}
 	if err != nil {
 		s.logger.Error("Failed to proxy request",
 			zap.String("service", serviceName),
@@ -86,8 +120,20 @@ func (s *GatewayService) RouteRequest(
 	return proxyResponse, nil
 }
 
-// findRoute finds a matching route for the given path and method
+// RequiresAuth reports whether the registered route of a service matching the path and
+// method requires an authenticated user; unknown services and routes do not
+func (s *GatewayService) RequiresAuth(serviceName, path, method string) bool {
+	service, err := s.serviceRegistry.GetService(serviceName)
+	if err != nil {
+		return false
+	}
+	route := s.findRoute(service, path, method)
+	return route != nil && route.RequireAuth
+}
+
+// findRoute finds a matching route for the given path (query string ignored) and method
 func (s *GatewayService) findRoute(service *domain.Service, path string, method string) *domain.Route {
+	path, _, _ = strings.Cut(path, "?")
 	for _, route := range service.Routes {
 		// Simple path matching - in production, use a proper router
 		if s.pathMatches(route.Path, path) && s.methodMatches(route.Methods, method) {
@@ -109,6 +155,11 @@ func (s *GatewayService) pathMatches(pattern string, path string) bool {
 	patternParts := s.splitPath(pattern)
 	pathParts := s.splitPath(path)
 
+	// A trailing *wildcard part matches the rest of the path (e.g. /admin/jobs/product/*path)
+	if n := len(patternParts); n > 0 && patternParts[n-1][0] == '*' && len(pathParts) >= n {
+		patternParts, pathParts = patternParts[:n-1], pathParts[:n-1]
+	}
+
 	if len(patternParts) != len(pathParts) {
 		return false
 	}
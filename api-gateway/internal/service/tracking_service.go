@@ -0,0 +1,146 @@
+package service
+
+import (
+	"api-gateway/internal/domain"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Clickstream limits on the client clock: older events are stale (e.g. a queue flushed
+// days later), future ones come from a wrong clock
+const (
+	maxEventAge  = 24 * time.Hour
+	maxEventSkew = 5 * time.Minute
+)
+
+// ErrInvalidEvent is returned for a tracked event that does not fit its type
+var ErrInvalidEvent = errors.New("invalid event")
+
+// TrackingService validates batches of clickstream events, adds the visitor identity and
+// forwards them to Kafka for the recommendation and analytics pipelines
+type TrackingService struct {
+	publisher domain.EventPublisher
+	logger    *zap.Logger
+}
+
+// NewTrackingService creates a new clickstream tracking service
+func NewTrackingService(publisher domain.EventPublisher, logger *zap.Logger) *TrackingService {
+	return &TrackingService{
+		publisher: publisher,
+		logger:    logger,
+	}
+}
+
+// TrackRequest is a batch of events a client collected in one session
+type TrackRequest struct {
+	SessionID string       `json:"session_id" binding:"required,max=64"`
+	Events    []TrackEvent `json:"events" binding:"required,min=1,max=50,dive"`
+}
+
+// TrackEvent is one client event; which fields are required depends on its type
+// (view, click, add_to_cart: product_id; add_to_cart: quantity; search: query)
+type TrackEvent struct {
+	Type       string            `json:"type" binding:"required,oneof=view click add_to_cart search" example:"click"`
+	ProductID  uint              `json:"product_id" example:"12"`
+	ShopID     uint              `json:"shop_id" example:"3"`
+	Query      string            `json:"query" binding:"max=200"`
+	Quantity   int               `json:"quantity" binding:"min=0,max=1000"`
+	Position   int               `json:"position" binding:"min=0,max=1000"`
+	Source     string            `json:"source" binding:"max=50" example:"search"`
+	Page       string            `json:"page" binding:"max=500"`
+	Properties map[string]string `json:"properties" binding:"max=20,dive,keys,max=50,endkeys,max=500"`
+	OccurredAt time.Time         `json:"occurred_at" binding:"required"`
+}
+
+// TrackVisitor is who sent the events, as identified by the gateway
+type TrackVisitor struct {
+	UserID    string
+	GuestID   string
+	UserAgent string
+}
+
+// Track validates the batch and forwards it; one invalid event rejects the whole batch
+// Returns the number of events accepted
+func (s *TrackingService) Track(ctx context.Context, visitor TrackVisitor, req *TrackRequest) (int, error) {
+	now := time.Now().UTC()
+	events := make([]*domain.ClickstreamEvent, 0, len(req.Events))
+	for i := range req.Events {
+		event := &req.Events[i]
+		if err := validateTrackEvent(event, now); err != nil {
+			return 0, fmt.Errorf("%w: events[%d]: %s", ErrInvalidEvent, i, err.Error())
+		}
+
+		eventID, err := newEventID()
+		if err != nil {
+			return 0, fmt.Errorf("failed to generate event ID: %w", err)
+		}
+		events = append(events, &domain.ClickstreamEvent{
+			EventID:    eventID,
+			Type:       event.Type,
+			SessionID:  req.SessionID,
+			UserID:     visitor.UserID,
+			GuestID:    visitor.GuestID,
+			ProductID:  event.ProductID,
+			ShopID:     event.ShopID,
+			Query:      strings.TrimSpace(event.Query),
+			Quantity:   event.Quantity,
+			Position:   event.Position,
+			Source:     event.Source,
+			Page:       event.Page,
+			Properties: event.Properties,
+			UserAgent:  visitor.UserAgent,
+			OccurredAt: event.OccurredAt.UTC(),
+			ReceivedAt: now,
+		})
+	}
+
+	if err := s.publisher.PublishClickstream(ctx, events); err != nil {
+		return 0, fmt.Errorf("failed to publish clickstream events: %w", err)
+	}
+
+	s.logger.Debug("Clickstream events tracked",
+		zap.Int("count", len(events)),
+		zap.String("session_id", req.SessionID),
+		zap.String("user_id", visitor.UserID),
+	)
+	return len(events), nil
+}
+
+// validateTrackEvent checks the fields the event type requires and the client clock
+func validateTrackEvent(event *TrackEvent, now time.Time) error {
+	switch event.Type {
+	case domain.EventView, domain.EventClick:
+		if event.ProductID == 0 {
+			return fmt.Errorf("%s needs product_id", event.Type)
+		}
+	case domain.EventAddToCart:
+		if event.ProductID == 0 || event.Quantity < 1 {
+			return errors.New("add_to_cart needs product_id and a quantity of at least 1")
+		}
+	case domain.EventSearch:
+		if strings.TrimSpace(event.Query) == "" {
+			return errors.New("search needs query")
+		}
+	}
+
+	if event.OccurredAt.Before(now.Add(-maxEventAge)) || event.OccurredAt.After(now.Add(maxEventSkew)) {
+		return fmt.Errorf("occurred_at must be within the last %s", maxEventAge)
+	}
+	return nil
+}
+
+// newEventID returns a random 128-bit hex event ID
+func newEventID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
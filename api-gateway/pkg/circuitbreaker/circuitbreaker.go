@@ -0,0 +1,177 @@
+// Package circuitbreaker stops the gateway from calling a backend service that keeps failing.
+//
+// Every service has its own breaker. A closed breaker lets calls through and counts
+// consecutive failures; at FailureThreshold it opens and rejects calls immediately
+// (ErrOpen) instead of letting them wait for the service's timeout. After OpenTimeout
+// it is half open: up to HalfOpenRequests probe calls go through, and it closes once
+// all of them succeed or opens again on the first failure. Breakers live in memory,
+// so each gateway instance trips on the failures it sees itself.
+package circuitbreaker
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned for calls rejected by an open breaker
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State of a breaker
+type State string
+
+const (
+	StateClosed   State = "closed"
+	StateOpen     State = "open"
+	StateHalfOpen State = "half_open"
+)
+
+// Settings are shared by the breakers of all services
+type Settings struct {
+	FailureThreshold int           // Consecutive failures that open the breaker
+	OpenTimeout      time.Duration // Calls are rejected this long before probing
+	HalfOpenRequests int           // Probe calls that must all succeed to close
+}
+
+// Status is the state of one service's breaker, as reported by GET /gateway/status
+type Status struct {
+	Service             string     `json:"service"`
+	State               State      `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"` // Last time the breaker opened
+	RetryAt             *time.Time `json:"retry_at,omitempty"`  // Open: when probing starts
+	Trips               int64      `json:"trips"`               // Times opened since the gateway started
+	LastError           string     `json:"last_error,omitempty"`
+}
+
+// Breaker guards the calls to one service
+type Breaker struct {
+	mu        sync.Mutex
+	name      string
+	settings  Settings
+	state     State
+	failures  int
+	probes    int // Half open: probe calls let through
+	successes int // Half open: probe calls that succeeded
+	openedAt  time.Time
+	trips     int64
+	lastError string
+}
+
+// Allow reports whether a call may go through: ErrOpen while the breaker is open or
+// all half-open probes are taken. Every allowed call must be followed by Record
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen {
+		if time.Since(b.openedAt) < b.settings.OpenTimeout {
+			return ErrOpen
+		}
+		b.state, b.probes, b.successes = StateHalfOpen, 0, 0
+	}
+	if b.state == StateHalfOpen {
+		if b.probes >= b.settings.HalfOpenRequests {
+			return ErrOpen
+		}
+		b.probes++
+	}
+	return nil
+}
+
+// Record counts the outcome of an allowed call; err nil is a success
+func (b *Breaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		b.failures = 0
+		if b.state == StateHalfOpen {
+			b.successes++
+			if b.successes >= b.settings.HalfOpenRequests {
+				b.state = StateClosed
+			}
+		}
+		return
+	}
+
+	b.failures++
+	b.lastError = err.Error()
+	if b.state == StateHalfOpen || (b.state == StateClosed && b.failures >= b.settings.FailureThreshold) {
+		b.state = StateOpen
+		b.openedAt = time.Now()
+		b.trips++
+	}
+}
+
+// status returns a copy of the breaker's state
+func (b *Breaker) status() Status {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := Status{
+		Service:             b.name,
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		Trips:               b.trips,
+		LastError:           b.lastError,
+	}
+	if !b.openedAt.IsZero() {
+		openedAt := b.openedAt
+		status.OpenedAt = &openedAt
+	}
+	if b.state == StateOpen {
+		retryAt := b.openedAt.Add(b.settings.OpenTimeout)
+		status.RetryAt = &retryAt
+	}
+	return status
+}
+
+// Set holds the breakers of all services, created on first use
+type Set struct {
+	mu       sync.Mutex
+	settings Settings
+	breakers map[string]*Breaker
+}
+
+// NewSet creates the breakers of the gateway's backend services
+func NewSet(settings Settings) *Set {
+	if settings.FailureThreshold < 1 {
+		settings.FailureThreshold = 1
+	}
+	if settings.HalfOpenRequests < 1 {
+		settings.HalfOpenRequests = 1
+	}
+	return &Set{settings: settings, breakers: make(map[string]*Breaker)}
+}
+
+// Get returns the breaker of a service
+func (s *Set) Get(service string) *Breaker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	breaker, ok := s.breakers[service]
+	if !ok {
+		breaker = &Breaker{name: service, settings: s.settings, state: StateClosed}
+		s.breakers[service] = breaker
+	}
+	return breaker
+}
+
+// Statuses returns the state of every service's breaker, sorted by service
+func (s *Set) Statuses() []Status {
+	s.mu.Lock()
+	breakers := make([]*Breaker, 0, len(s.breakers))
+	for _, breaker := range s.breakers {
+		breakers = append(breakers, breaker)
+	}
+	s.mu.Unlock()
+
+	statuses := make([]Status, 0, len(breakers))
+	for _, breaker := range breakers {
+		statuses = append(statuses, breaker.status())
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Service < statuses[j].Service })
+	return statuses
+}
@@ -7,13 +7,22 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
+// level is shared by every logger built here so it can be changed at runtime
+var level = zap.NewAtomicLevel()
+
+// Level returns the adjustable level of loggers created by NewLogger
+func Level() zap.AtomicLevel {
+	return level
+}
+
 // NewLogger creates a new Zap logger based on configuration
 func NewLogger(cfg *config.LoggingConfig) (*zap.Logger, error) {
 	// Parse log level
-	var level zapcore.Level
-	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
-		level = zapcore.InfoLevel // Default to info
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(cfg.Level)); err != nil {
+		lvl = zapcore.InfoLevel // Default to info
 	}
+	level.SetLevel(lvl)
 
 	// Configure encoder
 	var encoderConfig zapcore.EncoderConfig
@@ -29,7 +38,7 @@ func NewLogger(cfg *config.LoggingConfig) (*zap.Logger, error) {
 
 	// Build logger config
 	zapConfig := zap.Config{
-		Level:            zap.NewAtomicLevelAt(level),
+		Level:            level,
 		Development:      cfg.Encoding == "console",
 		Encoding:         cfg.Encoding,
 		EncoderConfig:    encoderConfig,
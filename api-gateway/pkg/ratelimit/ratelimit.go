@@ -0,0 +1,87 @@
+// Package ratelimit implements token buckets shared by all gateway instances in Redis.
+//
+// A bucket holds up to Burst tokens and refills at RequestsPerMinute/60 tokens per
+// second; every request takes one token and is rejected while the bucket is empty.
+// Refill and take run in one Lua script against the Redis clock, so concurrent
+// requests on different instances never both get the last token and instance clocks
+// do not matter. Idle buckets expire once they would be full again.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript refills the bucket for the time passed since its last request and takes a
+// token when one is left. Returns {allowed, remaining tokens, retry after ms, full after ms}
+var takeScript = redis.NewScript(`
+local rate = tonumber(ARGV[1]) -- tokens per millisecond
+local burst = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate)
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+else
+  retry = math.ceil((1 - tokens) / rate)
+end
+local full = math.ceil((burst - tokens) / rate)
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], full + 1000)
+return {allowed, math.floor(tokens), retry, full}
+`)
+
+// Rate is the size and refill speed of a bucket
+type Rate struct {
+	RequestsPerMinute int
+	Burst             int
+}
+
+// Result is the state of a bucket after a request
+type Result struct {
+	Allowed    bool
+	Remaining  int           // Tokens left
+	RetryAfter time.Duration // Until the next token, 0 when allowed
+	ResetAfter time.Duration // Until the bucket is full again
+}
+
+// Limiter takes tokens from the Redis buckets under a key prefix
+type Limiter struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewLimiter creates a limiter storing its buckets under <prefix><key>
+func NewLimiter(client *redis.Client, prefix string) *Limiter {
+	return &Limiter{client: client, prefix: prefix}
+}
+
+// Allow takes a token from the bucket of key, creating it full on the first request
+func (l *Limiter) Allow(ctx context.Context, key string, rate Rate) (*Result, error) {
+	perMillisecond := float64(rate.RequestsPerMinute) / float64(time.Minute/time.Millisecond)
+	values, err := takeScript.Run(ctx, l.client, []string{l.prefix + key}, perMillisecond, rate.Burst).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to take rate limit token: %w", err)
+	}
+	if len(values) != 4 {
+		return nil, fmt.Errorf("unexpected rate limit script result: %v", values)
+	}
+	return &Result{
+		Allowed:    values[0] == 1,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+		ResetAfter: time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
@@ -30,6 +30,10 @@ func GetClient(cfg *config.RedisConfig) (*redis.Client, error) {
 			DB:           cfg.DB,
 			PoolSize:     cfg.PoolSize,
 			MinIdleConns: cfg.MinIdleConns,
+			DialTimeout:  cfg.DialTimeout,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+			PoolTimeout:  cfg.PoolTimeout,
 		})
 
 		// Test connection
@@ -0,0 +1,279 @@
+// Package stats keeps rolling request statistics of the gateway in memory.
+//
+// Every request is counted against its route (method and Gin route pattern), every
+// proxied call against the backend service it went to. Counters live in 10-second
+// buckets covering the last 15 minutes and are summarized over the 1m, 5m and 15m
+// windows: request count, error rate (5xx or transport failure) and p95 latency.
+// Each gateway instance reports its own traffic; the counters reset on restart.
+package stats
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	bucketWidth = 10 * time.Second
+	bucketCount = int(15 * time.Minute / bucketWidth)
+)
+
+// Windows are the spans the statistics are summarized over
+var Windows = []time.Duration{time.Minute, 5 * time.Minute, 15 * time.Minute}
+
+// latencyBounds are the upper bounds of the latency histogram; p95 is reported as the
+// bound of the bucket it falls in (or the slowest request when above the last bound)
+var latencyBounds = [...]time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// WindowStats summarizes the requests of one route or service over a window
+type WindowStats struct {
+	Requests  int64   `json:"requests"`
+	Errors    int64   `json:"errors"`
+	ErrorRate float64 `json:"error_rate"`
+	P95Ms     float64 `json:"p95_ms"`
+}
+
+// RouteStats are the statistics of one gateway route, keyed by window ("1m", "5m", "15m")
+type RouteStats struct {
+	Method  string                 `json:"method"`
+	Route   string                 `json:"route"`
+	Windows map[string]WindowStats `json:"windows"`
+}
+
+// ServiceWindowStats summarizes the proxied calls to a backend service over a window.
+// Availability is the share of calls answered without a 5xx or transport error
+type ServiceWindowStats struct {
+	WindowStats
+	Availability float64 `json:"availability"`
+}
+
+// ServiceStats are the statistics of one backend service, keyed by window
+type ServiceStats struct {
+	Service string                        `json:"service"`
+	Windows map[string]ServiceWindowStats `json:"windows"`
+}
+
+// Snapshot is the state of all counters at one point in time
+type Snapshot struct {
+	GeneratedAt time.Time      `json:"generated_at"`
+	Routes      []RouteStats   `json:"routes"`
+	Services    []ServiceStats `json:"services"`
+}
+
+type routeKey struct {
+	method string
+	route  string
+}
+
+// Recorder collects the statistics; safe for concurrent use
+type Recorder struct {
+	mu       sync.Mutex
+	routes   map[routeKey]*series
+	services map[string]*series
+	now      func() time.Time
+}
+
+// NewRecorder creates an empty recorder
+func NewRecorder() *Recorder {
+	return &Recorder{
+		routes:   make(map[routeKey]*series),
+		services: make(map[string]*series),
+		now:      time.Now,
+	}
+}
+
+// RecordRequest counts a request handled by the gateway; a 5xx status is an error.
+// route is the Gin route pattern, so path parameters do not multiply the entries
+func (r *Recorder) RecordRequest(method, route string, status int, latency time.Duration) {
+	now := r.now()
+	key := routeKey{method: method, route: route}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.routes[key]
+	if !ok {
+		s = &series{}
+		r.routes[key] = s
+	}
+	s.add(now, latency, status >= 500)
+}
+
+// RecordUpstream counts a call proxied to a backend service; a transport error or a
+// 5xx status makes it unavailable for that call
+func (r *Recorder) RecordUpstream(service string, status int, latency time.Duration, err error) {
+	now := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.services[service]
+	if !ok {
+		s = &series{}
+		r.services[service] = s
+	}
+	s.add(now, latency, err != nil || status >= 500)
+}
+
+// Snapshot summarizes every route and service over each of the Windows. Routes and
+// services without traffic in the last 15 minutes are dropped
+func (r *Recorder) Snapshot() Snapshot {
+	now := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snapshot := Snapshot{
+		GeneratedAt: now,
+		Routes:      make([]RouteStats, 0, len(r.routes)),
+		Services:    make([]ServiceStats, 0, len(r.services)),
+	}
+	for key, s := range r.routes {
+		windows := make(map[string]WindowStats, len(Windows))
+		for _, window := range Windows {
+			windows[windowName(window)] = s.summary(now, window)
+		}
+		if windows[windowName(Windows[len(Windows)-1])].Requests == 0 {
+			delete(r.routes, key)
+			continue
+		}
+		snapshot.Routes = append(snapshot.Routes, RouteStats{Method: key.method, Route: key.route, Windows: windows})
+	}
+	for name, s := range r.services {
+		windows := make(map[string]ServiceWindowStats, len(Windows))
+		for _, window := range Windows {
+			summary := s.summary(now, window)
+			windows[windowName(window)] = ServiceWindowStats{
+				WindowStats:  summary,
+				Availability: round(1 - summary.ErrorRate),
+			}
+		}
+		if windows[windowName(Windows[len(Windows)-1])].Requests == 0 {
+			delete(r.services, name)
+			continue
+		}
+		snapshot.Services = append(snapshot.Services, ServiceStats{Service: name, Windows: windows})
+	}
+
+	sort.Slice(snapshot.Routes, func(i, j int) bool {
+		if snapshot.Routes[i].Route != snapshot.Routes[j].Route {
+			return snapshot.Routes[i].Route < snapshot.Routes[j].Route
+		}
+		return snapshot.Routes[i].Method < snapshot.Routes[j].Method
+	})
+	sort.Slice(snapshot.Services, func(i, j int) bool {
+		return snapshot.Services[i].Service < snapshot.Services[j].Service
+	})
+	return snapshot
+}
+
+// bucket holds the counters of one bucketWidth interval
+type bucket struct {
+	start      int64 // unix seconds; a bucket with an old start is stale and reused
+	requests   int64
+	errors     int64
+	latency    [len(latencyBounds) + 1]int64
+	maxLatency time.Duration
+}
+
+// series is a ring of buckets covering the longest window
+type series struct {
+	buckets [bucketCount]bucket
+}
+
+func (s *series) add(now time.Time, latency time.Duration, failed bool) {
+	start := bucketStart(now)
+	b := &s.buckets[(start/int64(bucketWidth/time.Second))%int64(bucketCount)]
+	if b.start != start {
+		*b = bucket{start: start}
+	}
+
+	b.requests++
+	if failed {
+		b.errors++
+	}
+	b.latency[sort.Search(len(latencyBounds), func(i int) bool { return latency <= latencyBounds[i] })]++
+	if latency > b.maxLatency {
+		b.maxLatency = latency
+	}
+}
+
+// summary merges the buckets that started within window of now (the current,
+// partially filled bucket included)
+func (s *series) summary(now time.Time, window time.Duration) WindowStats {
+	oldest := bucketStart(now) - int64((window-bucketWidth)/time.Second)
+
+	var (
+		merged     bucket
+		maxLatency time.Duration
+	)
+	for i := range s.buckets {
+		b := &s.buckets[i]
+		if b.requests == 0 || b.start < oldest {
+			continue
+		}
+		merged.requests += b.requests
+		merged.errors += b.errors
+		for j, n := range b.latency {
+			merged.latency[j] += n
+		}
+		maxLatency = max(maxLatency, b.maxLatency)
+	}
+
+	stats := WindowStats{Requests: merged.requests, Errors: merged.errors}
+	if merged.requests == 0 {
+		return stats
+	}
+	stats.ErrorRate = round(float64(merged.errors) / float64(merged.requests))
+	stats.P95Ms = percentile(merged.latency[:], merged.requests, 0.95, maxLatency)
+	return stats
+}
+
+// percentile returns the histogram bound the p-th request falls under, in milliseconds
+func percentile(histogram []int64, total int64, p float64, maxLatency time.Duration) float64 {
+	target := int64(math.Ceil(float64(total) * p))
+	var seen int64
+	for i, n := range histogram {
+		seen += n
+		if seen >= target {
+			if i < len(latencyBounds) {
+				return milliseconds(min(latencyBounds[i], maxLatency))
+			}
+			break
+		}
+	}
+	return milliseconds(maxLatency)
+}
+
+func bucketStart(t time.Time) int64 {
+	return t.Truncate(bucketWidth).Unix()
+}
+
+// windowName formats a window as 1m, 5m, 15m
+func windowName(d time.Duration) string {
+	if d%time.Minute == 0 {
+		return strconv.Itoa(int(d/time.Minute)) + "m"
+	}
+	return d.String()
+}
+
+func milliseconds(d time.Duration) float64 {
+	return round(float64(d) / float64(time.Millisecond))
+}
+
+// round keeps three decimals, enough for rates and sub-millisecond latencies
+func round(f float64) float64 {
+	return math.Round(f*1000) / 1000
+}
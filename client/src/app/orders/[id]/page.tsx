@@ -50,6 +50,7 @@ export default function OrderDetailPage() {
         return "bg-yellow-100 text-yellow-800";
       case "paid":
         return "bg-blue-100 text-blue-800";
+      case "confirmed":
       case "processing":
         return "bg-purple-100 text-purple-800";
       case "shipped":
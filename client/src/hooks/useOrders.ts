@@ -60,11 +60,12 @@ export interface CreateOrderRequest {
 
   // Financials
   shipping_fee?: number;
-  shipping_discount?: number;
-  voucher_discount?: number;
   tax?: number;
   discount?: number;
 
+  // Vouchers (validated and applied by the server)
+  voucher_codes?: string[];
+
   // Payment
   payment_method?: string;
 
@@ -12,14 +12,22 @@ export const apiClient = axios.create({
   },
 });
 
+// CSRF token issued by the gateway (X-CSRF-Token response header), echoed on
+// state-changing requests because authentication is cookie-based
+let csrfToken: string | null = null;
+const CSRF_SAFE_METHODS = ["get", "head", "options"];
+
 // Flag to prevent infinite refresh loops
 let isRefreshing = false;
 let refreshPromise: Promise<void> | null = null;
 
-// Request interceptor - just log requests (no Authorization header needed)
+// Request interceptor - log requests and attach the CSRF token (no Authorization header needed)
 apiClient.interceptors.request.use(
   (config: InternalAxiosRequestConfig) => {
     console.log(`🚀 [API] ${config.method?.toUpperCase()} ${config.url}`);
+    if (csrfToken && !CSRF_SAFE_METHODS.includes(config.method || "get")) {
+      config.headers.set("X-CSRF-Token", csrfToken);
+    }
     return config;
   },
   (error) => {
@@ -31,6 +39,7 @@ apiClient.interceptors.request.use(
 // Response interceptor with automatic token refresh
 apiClient.interceptors.response.use(
   (response) => {
+    csrfToken = response.headers["x-csrf-token"] || csrfToken;
     console.log(
       `✅ [API] ${response.config.method?.toUpperCase()} ${
         response.config.url
@@ -39,10 +48,22 @@ apiClient.interceptors.response.use(
     return response;
   },
   async (error: AxiosError) => {
+    csrfToken = error.response?.headers["x-csrf-token"] || csrfToken;
     const originalRequest = error.config as InternalAxiosRequestConfig & {
       _retry?: boolean;
     };
 
+    // CSRF token missing or rotated: retry once with the token from this response
+    const errorCode = (error.response?.data as { code?: string } | undefined)?.code;
+    if (
+      error.response?.status === 403 &&
+      errorCode === "CSRF_TOKEN_INVALID" &&
+      !originalRequest._retry
+    ) {
+      originalRequest._retry = true;
+      return apiClient(originalRequest);
+    }
+
     // If 401 and not already retrying
     if (error.response?.status === 401 && !originalRequest._retry) {
       // Don't retry login/register/refresh endpoints
@@ -112,6 +112,7 @@ export interface ApiError {
 export type OrderStatus =
   | "pending"
   | "paid"
+  | "confirmed"
   | "processing"
   | "shipped"
   | "delivered"
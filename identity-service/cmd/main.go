@@ -12,8 +12,15 @@ import (
 	"identity-service/internal/router"
 	"identity-service/internal/service"
 	"identity-service/pkg/database"
+	"identity-service/pkg/errorreport"
+	"identity-service/pkg/jobs"
 	"identity-service/pkg/logger"
+	"identity-service/pkg/mailer"
+	"identity-service/pkg/order_client"
+	"identity-service/pkg/product_client"
 	redisClient "identity-service/pkg/redis"
+	"identity-service/pkg/serviceauth"
+	"identity-service/pkg/validation"
 	"log"
 	"net/http"
 	"os"
@@ -41,6 +48,17 @@ func main() {
 
 	appLogger.Info("Starting Identity Service...")
 
+	// Report recovered panics to Sentry (disabled without a DSN)
+	if err := errorreport.Init(&cfg.Sentry, "identity-service"); err != nil {
+		appLogger.Warn("Failed to initialize error reporting, panics are only logged", zap.Error(err))
+	}
+	defer errorreport.Flush(2 * time.Second)
+
+	// Field-level binding errors and the vnphone/slug rules
+	if err := validation.Register(); err != nil {
+		appLogger.Fatal("Failed to register request validators", zap.Error(err))
+	}
+
 	// Set Gin mode
 	gin.SetMode(cfg.Server.Mode)
 
@@ -51,8 +69,13 @@ func main() {
 	}
 	defer database.CloseDB()
 
+	// Per-table query latency metrics (/metrics) and slow query logging
+	if err := db.Use(database.NewQueryMetrics(cfg.Database.SlowQueryThreshold, appLogger)); err != nil {
+		appLogger.Fatal("Failed to register query metrics", zap.Error(err))
+	}
+
 	// Run database migrations
-	if err := db.AutoMigrate(&domain.User{}, &domain.Address{}, &domain.Shop{}, &domain.RefreshToken{}); err != nil {
+	if err := db.AutoMigrate(&domain.User{}, &domain.Address{}, &domain.Shop{}, &domain.ShopSlugHistory{}, &domain.RefreshToken{}, &domain.Setting{}, &domain.SettingAudit{}, &domain.FeatureFlag{}, &domain.SecurityEvent{}, &domain.Impersonation{}, &domain.NotificationPreference{}, &domain.EmailTemplate{}, &domain.PushDevice{}); err != nil {
 		appLogger.Fatal("Failed to run migrations", zap.Error(err))
 	}
 	appLogger.Info("Database migrations completed")
@@ -68,26 +91,135 @@ func main() {
 	userRepo := postgres.NewUserRepository(db)
 	addressRepo := postgres.NewAddressRepository(db)
 	shopRepo := postgres.NewShopRepository(db)
+	shopSlugHistoryRepo := postgres.NewShopSlugHistoryRepository(db)
 	refreshTokenRepo := postgres.NewRefreshTokenRepository(db)
 	sessionRepo := redisRepo.NewSessionRedisRepository(redisClientInstance, appLogger)
+	settingRepo := postgres.NewSettingRepository(db)
+	settingAuditRepo := postgres.NewSettingAuditRepository(db)
+	settingCache := redisRepo.NewSettingRedisCache(redisClientInstance)
+	featureFlagRepo := postgres.NewFeatureFlagRepository(db)
+	featureFlagCache := redisRepo.NewFeatureFlagRedisCache(redisClientInstance)
+	securityEventRepo := postgres.NewSecurityEventRepository(db)
+	impersonationRepo := postgres.NewImpersonationRepository(db)
+	notificationPreferenceRepo := postgres.NewNotificationPreferenceRepository(db)
+	notificationPreferenceCache := redisRepo.NewNotificationPreferenceRedisCache(redisClientInstance)
+	emailTemplateRepo := postgres.NewEmailTemplateRepository(db)
+	pushDeviceRepo := postgres.NewPushDeviceRepository(db)
+
+	// Background jobs (Redis-backed, namespace "identity")
+	jobWorker := jobs.NewWorker(redisClientInstance, "identity", 5, appLogger)
+	mail := mailer.New(&mailer.Config{
+		Host:     cfg.Mail.Host,
+		Port:     cfg.Mail.Port,
+		Username: cfg.Mail.Username,
+		Password: cfg.Mail.Password,
+		From:     cfg.Mail.From,
+	}, appLogger)
 
 	// Initialize services
-	authService := service.NewAuthService(userRepo, refreshTokenRepo, sessionRepo, appLogger, cfg.JWT.Secret)
+	emailTemplateService := service.NewEmailTemplateService(emailTemplateRepo, appLogger)
+	if err := emailTemplateService.EnsureDefaults(); err != nil {
+		appLogger.Warn("Failed to ensure default email templates", zap.Error(err))
+	}
+	emailService := service.NewEmailService(jobWorker.Client, mail, emailTemplateService, appLogger)
+	authService := service.NewAuthService(userRepo, refreshTokenRepo, sessionRepo, emailService, appLogger, cfg.JWT.Secret)
 	userService := service.NewUserService(userRepo, appLogger)
+	notificationPreferenceService := service.NewNotificationPreferenceService(notificationPreferenceRepo, notificationPreferenceCache, appLogger)
+	pushDeviceService := service.NewPushDeviceService(pushDeviceRepo, cfg.PushDevices.MaxPerUser, cfg.PushDevices.MaxFailures, appLogger)
 	addressService := service.NewAddressService(addressRepo, appLogger)
-	shopService := service.NewShopService(shopRepo, userRepo, appLogger)
+	shopService := service.NewShopService(shopRepo, shopSlugHistoryRepo, userRepo, appLogger)
+
+	settingService := service.NewSettingService(settingRepo, settingAuditRepo, settingCache, appLogger)
+
+	// Create default settings and warm Redis cache for other services
+	if err := settingService.EnsureDefaults(); err != nil {
+		appLogger.Warn("Failed to ensure default settings", zap.Error(err))
+	}
+
+	featureFlagService := service.NewFeatureFlagService(featureFlagRepo, featureFlagCache, appLogger)
+	if err := featureFlagService.WarmCache(); err != nil {
+		appLogger.Warn("Failed to warm feature flag cache", zap.Error(err))
+	}
+
+	// Brute-force limits of sensitive auth endpoints (disabled = no policies)
+	bruteForcePolicies := make(map[string]service.BruteForcePolicy)
+	if cfg.BruteForce.Enabled {
+		for name, p := range cfg.BruteForce.Policies {
+			bruteForcePolicies[name] = service.BruteForcePolicy{
+				Limit:        p.Limit,
+				Window:       p.Window,
+				FreeAttempts: p.FreeAttempts,
+				BaseDelay:    p.BaseDelay,
+				MaxDelay:     p.MaxDelay,
+			}
+		}
+	}
+	bruteForceService := service.NewBruteForceService(redisClientInstance, securityEventRepo, bruteForcePolicies, appLogger)
+	introspectionService := service.NewIntrospectionService(authService, redisClientInstance, cfg.Introspection.CacheTTL, appLogger)
+	impersonationService := service.NewImpersonationService(authService, impersonationRepo, cfg.Impersonation.DefaultDuration, cfg.Impersonation.MaxDuration, appLogger)
+
+	// Shop export adds the GMV (order-service) and rating (product-service) of each shop
+	signer := serviceauth.NewSigner(cfg.InternalAuth.ServiceName, cfg.InternalAuth.Secret)
+	orderClient := order_client.NewOrderClient(cfg.OrderService.BaseURL, cfg.OrderService.Timeout, signer)
+	productClient := product_client.NewProductClient(cfg.ProductService.BaseURL, cfg.ProductService.Timeout, signer)
+	exportService := service.NewExportService(userRepo, shopRepo, orderClient, productClient, appLogger)
+	audienceService := service.NewAudienceService(userRepo, shopRepo, appLogger)
+
+	// Generate slugs for shops created before slugs existed
+	if _, err := shopService.BackfillSlugs(); err != nil {
+		appLogger.Warn("Failed to backfill shop slugs", zap.Error(err))
+	}
+
+	jobWorker.Register(service.JobTypeSendEmail, emailService.HandleSendEmail)
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	jobsDone := make(chan struct{})
+	go func() {
+		jobWorker.Start(jobsCtx)
+		close(jobsDone)
+	}()
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService, appLogger)
 	userHandler := handler.NewUserHandler(userService, appLogger)
+	notificationPreferenceHandler := handler.NewNotificationPreferenceHandler(notificationPreferenceService, appLogger)
+	pushDeviceHandler := handler.NewPushDeviceHandler(pushDeviceService, appLogger)
 	addressHandler := handler.NewAddressHandler(addressService, appLogger)
 	shopHandler := handler.NewShopHandler(shopService, appLogger)
+	settingHandler := handler.NewSettingHandler(settingService, appLogger)
+	featureFlagHandler := handler.NewFeatureFlagHandler(featureFlagService, appLogger)
+	emailTemplateHandler := handler.NewEmailTemplateHandler(emailTemplateService, appLogger)
+	securityEventHandler := handler.NewSecurityEventHandler(bruteForceService, appLogger)
+	introspectionHandler := handler.NewIntrospectionHandler(introspectionService, appLogger)
+	impersonationHandler := handler.NewImpersonationHandler(impersonationService, appLogger)
+	exportHandler := handler.NewExportHandler(exportService, appLogger)
+	audienceHandler := handler.NewAudienceHandler(audienceService, appLogger)
+	jobHandler := handler.NewJobHandler(jobs.NewInspector(redisClientInstance, "identity"), appLogger)
+	logLevelHandler := handler.NewLogLevelHandler(logger.Level(), appLogger)
 
 	// Initialize middleware
 	authMiddleware := middleware.AuthMiddleware(authService)
+	adminMiddleware := middleware.AdminMiddleware()
+	refreshLimit := middleware.BruteForceMiddleware(bruteForceService, service.BruteForceRefresh, appLogger)
+	introspectionClients := cfg.Introspection.Clients
+	if !cfg.Introspection.Enabled {
+		introspectionClients = nil // every client is rejected
+	}
+	introspectionClient := middleware.IntrospectionClientMiddleware(introspectionClients, appLogger)
+
+	// Internal endpoints only accept calls signed by trusted services (disabled = unchecked)
+	var serviceAuth *serviceauth.Verifier
+	if cfg.InternalAuth.Enabled {
+		serviceAuth = serviceauth.NewVerifier(cfg.InternalAuth.TrustedServices, cfg.InternalAuth.MaxAge)
+	}
+	pushService := middleware.RequireService(serviceAuth, appLogger, "notification_service")
+	announcementService := middleware.RequireService(serviceAuth, appLogger, "order_service")
 
 	// Setup router
-	router := router.SetupRouter(authHandler, userHandler, addressHandler, shopHandler, authMiddleware)
+	router := router.SetupRouter(authHandler, userHandler, notificationPreferenceHandler, pushDeviceHandler, addressHandler, shopHandler, settingHandler, featureFlagHandler, emailTemplateHandler, securityEventHandler, introspectionHandler, impersonationHandler, exportHandler, audienceHandler, jobHandler, logLevelHandler, middleware.Recovery(appLogger), middleware.FaultInjection(&cfg.FaultInjection, "identity-service", appLogger), authMiddleware, adminMiddleware, refreshLimit, introspectionClient, pushService, announcementService)
+	// Client IP of the brute-force limits: X-Forwarded-For is only trusted from the gateway
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		appLogger.Fatal("Invalid trusted proxies", zap.Error(err))
+	}
 
 	// Create HTTP server
 	srv := &http.Server{
@@ -120,5 +252,9 @@ func main() {
 		appLogger.Error("Server forced to shutdown", zap.Error(err))
 	}
 
+	// Stop background jobs (waits for in-flight jobs)
+	stopJobs()
+	<-jobsDone
+
 	appLogger.Info("Server exited gracefully")
 }
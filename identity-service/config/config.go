@@ -11,11 +11,21 @@ import (
 
 // Config holds all configuration for the application
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
-	Logging  LoggingConfig
+	Server         ServerConfig
+	Database       DatabaseConfig
+	Redis          RedisConfig
+	JWT            JWTConfig
+	Logging        LoggingConfig
+	Mail           MailConfig
+	BruteForce     BruteForceConfig     `mapstructure:"brute_force"`
+	Introspection  IntrospectionConfig  `mapstructure:"introspection"`
+	Impersonation  ImpersonationConfig  `mapstructure:"impersonation"`
+	InternalAuth   InternalAuthConfig   `mapstructure:"internal_auth"`
+	OrderService   OrderServiceConfig   `mapstructure:"order_service"`
+	ProductService ProductServiceConfig `mapstructure:"product_service"`
+	PushDevices    PushDevicesConfig    `mapstructure:"push_devices"`
+	Sentry         SentryConfig         `mapstructure:"sentry"`
+	FaultInjection FaultInjectionConfig `mapstructure:"fault_injection"`
 }
 
 // ServerConfig holds HTTP server configuration
@@ -24,29 +34,44 @@ type ServerConfig struct {
 	Mode         string
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
+
+	// Proxies (IPs or CIDRs) whose X-Forwarded-For is trusted for the client IP: the API
+	// Gateway only. Requests from other addresses are keyed on their connection address
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
 }
 
 // DatabaseConfig holds PostgreSQL connection configuration
 type DatabaseConfig struct {
-	Host            string
-	Port            int
-	User            string
-	Password        string
-	DBName          string
-	SSLMode         string
-	MaxOpenConns    int
-	MaxIdleConns    int
-	ConnMaxLifetime time.Duration
+	Host     string
+	Port     int
+	User     string
+	Password string
+	DBName   string
+	SSLMode  string
+
+	// Connection pool (database/sql)
+	MaxOpenConns    int           `mapstructure:"max_open_conns"`
+	MaxIdleConns    int           `mapstructure:"max_idle_conns"`     // At most MaxOpenConns
+	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`  // Recycle connections (e.g. after a failover)
+	ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time"` // Close idle connections after a traffic peak
+
+	SlowQueryThreshold time.Duration `mapstructure:"slow_query_threshold"` // Statements slower than this are logged; 0 disables
 }
 
 // RedisConfig holds Redis connection configuration
 type RedisConfig struct {
-	Host         string
-	Port         int
-	Password     string
-	DB           int
-	PoolSize     int
-	MinIdleConns int
+	Host     string
+	Port     int
+	Password string
+	DB       int
+
+	// Connection pool and timeouts (go-redis)
+	PoolSize     int           `mapstructure:"pool_size"`
+	MinIdleConns int           `mapstructure:"min_idle_conns"` // At most PoolSize
+	DialTimeout  time.Duration `mapstructure:"dial_timeout"`
+	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
+	WriteTimeout time.Duration `mapstructure:"write_timeout"`
+	PoolTimeout  time.Duration `mapstructure:"pool_timeout"` // Wait for a free connection when the pool is exhausted
 }
 
 // JWTConfig holds JWT configuration
@@ -55,6 +80,97 @@ type JWTConfig struct {
 	Expiration time.Duration
 }
 
+// MailConfig holds SMTP configuration (empty host = emails are only logged)
+type MailConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// BruteForceConfig holds brute-force limits of sensitive auth endpoints
+// (refresh), enforced here in addition to the gateway rate limit
+type BruteForceConfig struct {
+	Enabled  bool                             `mapstructure:"enabled"`
+	Policies map[string]BruteForcePolicyConfig `mapstructure:"policies"`
+}
+
+// BruteForcePolicyConfig limits attempts of one client IP on one endpoint
+type BruteForcePolicyConfig struct {
+	Limit        int           `mapstructure:"limit"`         // Attempts allowed per sliding window
+	Window       time.Duration `mapstructure:"window"`        // Sliding window length
+	FreeAttempts int           `mapstructure:"free_attempts"` // Attempts answered without delay
+	BaseDelay    time.Duration `mapstructure:"base_delay"`    // First delay, doubled for each further attempt
+	MaxDelay     time.Duration `mapstructure:"max_delay"`     // Upper bound of the delay
+}
+
+// IntrospectionConfig holds the token introspection endpoint configuration
+type IntrospectionConfig struct {
+	Enabled  bool              `mapstructure:"enabled"`   // false = every client is rejected
+	CacheTTL time.Duration     `mapstructure:"cache_ttl"` // Cache lifetime of results (capped at token expiry), 0 = no cache
+	Clients  map[string]string `mapstructure:"clients"`   // Client ID -> secret of services allowed to introspect
+}
+
+// ImpersonationConfig holds the lifetime of admin impersonation tokens
+type ImpersonationConfig struct {
+	DefaultDuration time.Duration `mapstructure:"default_duration"` // When the request gives no duration
+	MaxDuration     time.Duration `mapstructure:"max_duration"`     // Upper bound of requested durations
+}
+
+// InternalAuthConfig holds service-to-service authentication with signed service tokens
+type InternalAuthConfig struct {
+	Enabled         bool              `mapstructure:"enabled"`          // false = internal endpoints accept unsigned calls
+	ServiceName     string            `mapstructure:"service_name"`     // Name this service signs its outgoing calls with
+	Secret          string            `mapstructure:"secret"`           // Signing secret of this service
+	MaxAge          time.Duration     `mapstructure:"max_age"`          // Lifetime of accepted tokens
+	TrustedServices map[string]string `mapstructure:"trusted_services"` // Calling service -> its signing secret
+}
+
+// OrderServiceConfig holds Order Service client configuration (shop GMV of the admin shop export)
+type OrderServiceConfig struct {
+	BaseURL string        `mapstructure:"base_url"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// ProductServiceConfig holds Product Service client configuration (shop ratings of the admin shop export)
+type ProductServiceConfig struct {
+	BaseURL string        `mapstructure:"base_url"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// PushDevicesConfig holds the limits of the push notification device token registry
+type PushDevicesConfig struct {
+	MaxPerUser  int `mapstructure:"max_per_user"` // Oldest devices are dropped beyond this
+	MaxFailures int `mapstructure:"max_failures"` // Consecutive transient delivery failures before a token is pruned
+}
+
+// SentryConfig holds the reporting of recovered panics to Sentry
+type SentryConfig struct {
+	DSN         string  `mapstructure:"dsn"`         // empty disables reporting (panics are only logged)
+	Environment string  `mapstructure:"environment"` // e.g. production, staging
+	Release     string  `mapstructure:"release"`     // deployed version, groups issues by release
+	SampleRate  float64 `mapstructure:"sample_rate"` // share of panics reported, 0-1
+}
+
+// FaultInjectionConfig holds the opt-in injection of latency and errors, used to check
+// timeouts, retries and circuit breakers of the callers. Refused in release mode
+type FaultInjectionConfig struct {
+	Enabled bool              `mapstructure:"enabled"`
+	Headers bool              `mapstructure:"headers"` // honor X-Fault-* request headers naming this service
+	Rules   []FaultRuleConfig `mapstructure:"rules"`
+}
+
+// FaultRuleConfig injects faults into the requests matching a path prefix (and method)
+type FaultRuleConfig struct {
+	PathPrefix  string        `mapstructure:"path_prefix"`
+	Method      string        `mapstructure:"method"`       // empty matches any method
+	Latency     time.Duration `mapstructure:"latency"`      // added before the request is handled
+	Jitter      time.Duration `mapstructure:"jitter"`       // random extra latency, up to this much
+	ErrorRate   float64       `mapstructure:"error_rate"`   // share of matching requests answered with ErrorStatus, 0-1
+	ErrorStatus int           `mapstructure:"error_status"` // default 503
+}
+
 // LoggingConfig holds logging configuration
 type LoggingConfig struct {
 	Level            string
@@ -86,6 +202,11 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	// Fail fast on settings that would only break under load (pools, timeouts)
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
 	return config, nil
 }
 
@@ -94,6 +215,7 @@ func setDefaults() {
 	viper.SetDefault("server.mode", "debug")
 	viper.SetDefault("server.read_timeout", "30s")
 	viper.SetDefault("server.write_timeout", "30s")
+	viper.SetDefault("server.trusted_proxies", []string{"127.0.0.1", "::1"})
 
 	viper.SetDefault("database.host", "localhost")
 	viper.SetDefault("database.port", 5433)
@@ -104,6 +226,8 @@ func setDefaults() {
 	viper.SetDefault("database.max_open_conns", 25)
 	viper.SetDefault("database.max_idle_conns", 5)
 	viper.SetDefault("database.conn_max_lifetime", "5m")
+	viper.SetDefault("database.conn_max_idle_time", "1m")
+	viper.SetDefault("database.slow_query_threshold", "200ms")
 
 	viper.SetDefault("redis.host", "localhost")
 	viper.SetDefault("redis.port", 6379)
@@ -111,10 +235,52 @@ func setDefaults() {
 	viper.SetDefault("redis.db", 0)
 	viper.SetDefault("redis.pool_size", 10)
 	viper.SetDefault("redis.min_idle_conns", 5)
+	viper.SetDefault("redis.dial_timeout", "5s")
+	viper.SetDefault("redis.read_timeout", "3s")
+	viper.SetDefault("redis.write_timeout", "3s")
+	viper.SetDefault("redis.pool_timeout", "4s")
 
 	viper.SetDefault("jwt.secret", "your-secret-key-change-in-production")
 	viper.SetDefault("jwt.expiration", "24h")
 
+	viper.SetDefault("mail.host", "")
+	viper.SetDefault("mail.port", 587)
+	viper.SetDefault("mail.from", "no-reply@ecommerce.local")
+
+	viper.SetDefault("brute_force.enabled", true)
+	viper.SetDefault("brute_force.policies.refresh.limit", 30)
+	viper.SetDefault("brute_force.policies.refresh.window", "15m")
+	viper.SetDefault("brute_force.policies.refresh.free_attempts", 10)
+	viper.SetDefault("brute_force.policies.refresh.base_delay", "250ms")
+	viper.SetDefault("brute_force.policies.refresh.max_delay", "5s")
+
+	viper.SetDefault("introspection.enabled", true)
+	viper.SetDefault("introspection.cache_ttl", "30s")
+
+	viper.SetDefault("impersonation.default_duration", "30m")
+	viper.SetDefault("impersonation.max_duration", "1h")
+
+	viper.SetDefault("internal_auth.enabled", true)
+	viper.SetDefault("internal_auth.service_name", "identity_service")
+	viper.SetDefault("internal_auth.max_age", "5m")
+
+	viper.SetDefault("order_service.base_url", "http://localhost:8083")
+	viper.SetDefault("order_service.timeout", "10s")
+	viper.SetDefault("product_service.base_url", "http://localhost:8080")
+	viper.SetDefault("product_service.timeout", "10s")
+
+	viper.SetDefault("push_devices.max_per_user", 10)
+	viper.SetDefault("push_devices.max_failures", 5)
+
+	// Panic reporting defaults (set SENTRY_DSN to enable)
+	viper.SetDefault("sentry.dsn", "")
+	viper.SetDefault("sentry.environment", "development")
+	viper.SetDefault("sentry.sample_rate", 1.0)
+
+	// Fault injection is for resilience testing only
+	viper.SetDefault("fault_injection.enabled", false)
+	viper.SetDefault("fault_injection.headers", false)
+
 	viper.SetDefault("logging.level", "info")
 	viper.SetDefault("logging.encoding", "json")
 	viper.SetDefault("logging.output_paths", []string{"stdout"})
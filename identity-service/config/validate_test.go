@@ -0,0 +1,63 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestLoadConfigSecretsFromEnv checks that config.yaml ships no secrets: identity-service's
+// signing secret, the secret of its caller (order-service) and the token introspection
+// client secrets are read from the environment, and startup fails while a required one is missing
+func TestLoadConfigSecretsFromEnv(t *testing.T) {
+	env := map[string]string{
+		"INTERNAL_AUTH_SECRET":                         "own-secret",
+		"INTERNAL_AUTH_TRUSTED_SERVICES_ORDER_SERVICE": "order-secret",
+		"INTROSPECTION_CLIENTS_PRODUCT_SERVICE":        "product-introspection-secret",
+		"INTROSPECTION_CLIENTS_ORDER_SERVICE":          "order-introspection-secret",
+	}
+	tests := []struct {
+		name    string
+		unset   string // env var left empty
+		wantErr string
+	}{
+		{"all secrets set", "", ""},
+		{"own secret missing", "INTERNAL_AUTH_SECRET", "INTERNAL_AUTH_SECRET"},
+		{"caller without secret is not trusted", "INTERNAL_AUTH_TRUSTED_SERVICES_ORDER_SERVICE", ""},
+		{"introspection client without secret", "INTROSPECTION_CLIENTS_ORDER_SERVICE", "INTROSPECTION_CLIENTS_ORDER_SERVICE"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := make(map[string]string, len(env))
+			for key, value := range env {
+				if key == tt.unset {
+					value = ""
+				}
+				want[key] = value
+				t.Setenv(key, value)
+			}
+
+			cfg, err := LoadConfig(".")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to mention %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadConfig: %v", err)
+			}
+			if cfg.InternalAuth.Secret != want["INTERNAL_AUTH_SECRET"] {
+				t.Errorf("secret = %q, want it from INTERNAL_AUTH_SECRET", cfg.InternalAuth.Secret)
+			}
+			if got := cfg.InternalAuth.TrustedServices["order_service"]; got != want["INTERNAL_AUTH_TRUSTED_SERVICES_ORDER_SERVICE"] {
+				t.Errorf("trusted order_service secret = %q, want it from the environment", got)
+			}
+			for _, client := range []string{"product_service", "order_service"} {
+				key := "INTROSPECTION_CLIENTS_" + strings.ToUpper(client)
+				if got := cfg.Introspection.Clients[client]; got != want[key] {
+					t.Errorf("introspection client %s secret = %q, want %q from %s", client, got, want[key], key)
+				}
+			}
+		})
+	}
+}
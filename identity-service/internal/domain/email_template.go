@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+// Email template source formats
+const (
+	EmailTemplateFormatHTML = "html"
+	EmailTemplateFormatMJML = "mjml" // Compiled to HTML by the admin UI (mjml-browser) before saving
+)
+
+// Transactional email template keys
+const (
+	EmailTemplateWelcome = "welcome"
+)
+
+// DefaultEmailLocale is used when a template has no variant for the requested locale
+const DefaultEmailLocale = "en"
+
+// EmailTemplate is one version of a transactional email template for a locale
+// Every change creates a new version (never edited in place); exactly one version
+// per (key, locale) is active and used by the email dispatcher.
+// Subject and bodies are Go templates, e.g. "Hi {{.FullName}}"
+type EmailTemplate struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Key         string    `gorm:"size:100;not null;uniqueIndex:idx_email_template_version" json:"key"`
+	Locale      string    `gorm:"size:10;not null;uniqueIndex:idx_email_template_version" json:"locale"`
+	Version     int       `gorm:"not null;uniqueIndex:idx_email_template_version" json:"version"`
+	Format      string    `gorm:"size:10;not null;default:'html'" json:"format"`
+	Subject     string    `gorm:"size:255;not null" json:"subject"`
+	Source      string    `gorm:"type:text;not null" json:"source"`            // MJML or HTML as authored
+	HTMLBody    string    `gorm:"column:html_body;type:text" json:"html_body"` // Sent HTML (= Source for html templates)
+	TextBody    string    `gorm:"column:text_body;type:text" json:"text_body"` // Plain-text alternative
+	Variables   []string  `gorm:"type:jsonb;serializer:json" json:"variables"` // Documented variables, e.g. ["FullName"]
+	IsActive    bool      `gorm:"column:is_active;default:false;index" json:"is_active"`
+	Description string    `gorm:"type:text" json:"description"`
+	CreatedBy   uint      `gorm:"column:created_by" json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (EmailTemplate) TableName() string {
+	return "email_template"
+}
+
+// EmailTemplateRepository defines the interface for email template data access
+type EmailTemplateRepository interface {
+	Create(template *EmailTemplate) error
+	GetActive(key, locale string) (*EmailTemplate, error)
+	GetVersion(key, locale string, version int) (*EmailTemplate, error)
+	LatestVersion(key, locale string) (int, error) // 0 if the template has no versions
+	ListActive() ([]*EmailTemplate, error)
+	ListVersions(key, locale string) ([]*EmailTemplate, error)
+	Activate(key, locale string, version int) error // Deactivates the other versions
+}
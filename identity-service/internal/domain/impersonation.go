@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// ImpersonationDeviceType marks the session of an impersonation token (cannot be refreshed)
+const ImpersonationDeviceType = "impersonation"
+
+// Impersonation is the audit record of an admin acting as a user (support cases).
+// The impersonation token is bound to a Redis session with the same ID, so revoking
+// the impersonation invalidates the token immediately
+type Impersonation struct {
+	ID        string     `gorm:"primaryKey;size:36" json:"id"` // Also the session ID (sid claim) of the token
+	AdminID   uint       `gorm:"index;not null" json:"admin_id"`
+	UserID    uint       `gorm:"index;not null" json:"user_id"` // Impersonated user
+	Reason    string     `gorm:"size:500;not null" json:"reason"`
+	IPAddress string     `gorm:"size:64" json:"ip_address"` // Admin's client IP
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	RevokedBy *uint      `json:"revoked_by,omitempty"`
+	CreatedAt time.Time  `gorm:"index" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Impersonation) TableName() string {
+	return "impersonations"
+}
+
+// IsActive reports whether the impersonation token may still be used
+func (i *Impersonation) IsActive() bool {
+	return i.RevokedAt == nil && time.Now().Before(i.ExpiresAt)
+}
+
+// ImpersonationRepository defines the interface for impersonation audit records
+type ImpersonationRepository interface {
+	Create(impersonation *Impersonation) error
+	GetByID(id string) (*Impersonation, error)
+	Update(impersonation *Impersonation) error
+	// List returns impersonations newest first; adminID/userID 0 matches all
+	List(adminID, userID uint, page, limit int) ([]*Impersonation, int64, error)
+}
@@ -0,0 +1,63 @@
+package domain
+
+import "time"
+
+// Notification categories a user can opt in or out of
+const (
+	NotificationOrderUpdates = "order_updates"
+	NotificationPromotions   = "promotions"
+	NotificationChat         = "chat"
+	NotificationPriceAlerts  = "price_alerts"
+)
+
+// Notification delivery channels
+const (
+	NotificationChannelEmail = "email"
+	NotificationChannelSMS   = "sms"
+	NotificationChannelPush  = "push"
+)
+
+// NotificationCategories lists all categories in display order
+var NotificationCategories = []string{NotificationOrderUpdates, NotificationPromotions, NotificationChat, NotificationPriceAlerts}
+
+// NotificationChannels lists all channels in display order
+var NotificationChannels = []string{NotificationChannelEmail, NotificationChannelSMS, NotificationChannelPush}
+
+// DefaultNotificationPreferences applies to every category/channel the user has not set
+// (transactional messages on, marketing opt-in only)
+var DefaultNotificationPreferences = NotificationPreferenceMatrix{
+	NotificationOrderUpdates: {NotificationChannelEmail: true, NotificationChannelSMS: false, NotificationChannelPush: true},
+	NotificationPromotions:   {NotificationChannelEmail: false, NotificationChannelSMS: false, NotificationChannelPush: false},
+	NotificationChat:         {NotificationChannelEmail: false, NotificationChannelSMS: false, NotificationChannelPush: true},
+	NotificationPriceAlerts:  {NotificationChannelEmail: true, NotificationChannelSMS: false, NotificationChannelPush: true},
+}
+
+// NotificationPreferenceMatrix maps category -> channel -> enabled
+type NotificationPreferenceMatrix map[string]map[string]bool
+
+// NotificationPreference is one explicit choice of a user (category x channel)
+type NotificationPreference struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"uniqueIndex:idx_notification_pref;not null" json:"user_id"`
+	Category  string    `gorm:"uniqueIndex:idx_notification_pref;size:50;not null" json:"category"`
+	Channel   string    `gorm:"uniqueIndex:idx_notification_pref;size:20;not null" json:"channel"`
+	Enabled   bool      `gorm:"not null" json:"enabled"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
+
+// NotificationPreferenceRepository defines the interface for notification preference data access
+type NotificationPreferenceRepository interface {
+	ListByUser(userID uint) ([]*NotificationPreference, error)
+	Upsert(pref *NotificationPreference) error
+}
+
+// NotificationPreferenceCache publishes the resolved preferences of a user to Redis,
+// where the services sending notifications read them (hash per user)
+type NotificationPreferenceCache interface {
+	Set(userID uint, prefs NotificationPreferenceMatrix) error
+}
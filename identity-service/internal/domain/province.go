@@ -0,0 +1,41 @@
+package domain
+
+// Provinces maps the code of each province-level unit of Vietnam (since the July 2025
+// merger) to its name. The code is the slug of the name, e.g. "Hồ Chí Minh" -> "ho-chi-minh";
+// shops store the code, search uses it for "ships from" filters and delivery estimates
+var Provinces = map[string]string{
+	"ha-noi":      "Hà Nội",
+	"hue":         "Huế",
+	"hai-phong":   "Hải Phòng",
+	"da-nang":     "Đà Nẵng",
+	"ho-chi-minh": "Hồ Chí Minh",
+	"can-tho":     "Cần Thơ",
+	"lai-chau":    "Lai Châu",
+	"dien-bien":   "Điện Biên",
+	"son-la":      "Sơn La",
+	"lang-son":    "Lạng Sơn",
+	"cao-bang":    "Cao Bằng",
+	"quang-ninh":  "Quảng Ninh",
+	"thanh-hoa":   "Thanh Hóa",
+	"nghe-an":     "Nghệ An",
+	"ha-tinh":     "Hà Tĩnh",
+	"tuyen-quang": "Tuyên Quang",
+	"lao-cai":     "Lào Cai",
+	"thai-nguyen": "Thái Nguyên",
+	"phu-tho":     "Phú Thọ",
+	"bac-ninh":    "Bắc Ninh",
+	"hung-yen":    "Hưng Yên",
+	"ninh-binh":   "Ninh Bình",
+	"quang-tri":   "Quảng Trị",
+	"quang-ngai":  "Quảng Ngãi",
+	"gia-lai":     "Gia Lai",
+	"khanh-hoa":   "Khánh Hòa",
+	"lam-dong":    "Lâm Đồng",
+	"dak-lak":     "Đắk Lắk",
+	"dong-nai":    "Đồng Nai",
+	"tay-ninh":    "Tây Ninh",
+	"vinh-long":   "Vĩnh Long",
+	"dong-thap":   "Đồng Tháp",
+	"ca-mau":      "Cà Mau",
+	"an-giang":    "An Giang",
+}
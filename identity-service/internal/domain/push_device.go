@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// Push notification providers
+const (
+	PushPlatformFCM  = "fcm"  // Firebase Cloud Messaging (Android, web)
+	PushPlatformAPNs = "apns" // Apple Push Notification service (iOS)
+)
+
+// PushDevice is a device registered by a user to receive push notifications
+// One token per (user, device): re-registering a device replaces its token. A token
+// belongs to one user only; registering it again moves it (device changed hands)
+type PushDevice struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	UserID       uint      `gorm:"not null;uniqueIndex:idx_push_device_user_device" json:"user_id"`
+	DeviceID     string    `gorm:"column:device_id;size:255;not null;uniqueIndex:idx_push_device_user_device" json:"device_id"`
+	Platform     string    `gorm:"size:10;not null" json:"platform"`
+	Token        string    `gorm:"size:512;not null;uniqueIndex" json:"-"` // Provider registration token (never returned)
+	AppVersion   string    `gorm:"column:app_version;size:50" json:"app_version"`
+	FailureCount int       `gorm:"column:failure_count;not null;default:0" json:"-"` // Consecutive transient delivery failures
+	LastSeenAt   time.Time `gorm:"column:last_seen_at" json:"last_seen_at"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (PushDevice) TableName() string {
+	return "push_devices"
+}
+
+// PushDeviceRepository defines the interface for push device data access
+type PushDeviceRepository interface {
+	Register(device *PushDevice) error // Upsert by (user, device); removes the token from other users
+	ListByUser(userID uint) ([]*PushDevice, error)
+	PruneOldest(userID uint, keep int) (int64, error)
+	DeleteByDevice(userID uint, deviceID string) (int64, error)
+	DeleteByTokens(tokens []string) (int64, error)
+	RecordFailures(tokens []string, maxFailures int) (int64, error) // Returns the number of pruned tokens
+	ResetFailures(tokens []string) error
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// Security event types
+const (
+	SecurityEventAuthThrottled = "AUTH_THROTTLED" // A client exceeded the brute-force limit of an auth endpoint
+)
+
+// SecurityEvent is an audit record of a security-relevant incident
+type SecurityEvent struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Type      string    `gorm:"size:50;index;not null" json:"type"`
+	Policy    string    `gorm:"size:50" json:"policy"` // Brute-force policy (refresh)
+	Path      string    `gorm:"size:255" json:"path"`
+	IP        string    `gorm:"size:64;index" json:"ip"`
+	Attempts  int       `json:"attempts"` // Attempts in the window when the limit was hit
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (SecurityEvent) TableName() string {
+	return "security_events"
+}
+
+// SecurityEventRepository defines the interface for security audit events
+type SecurityEventRepository interface {
+	Create(event *SecurityEvent) error
+	// List returns events newest first; eventType "" matches all
+	List(eventType string, page, limit int) ([]*SecurityEvent, int64, error)
+}
@@ -0,0 +1,88 @@
+package domain
+
+import "time"
+
+// Setting value types
+const (
+	SettingTypeString = "STRING"
+	SettingTypeInt    = "INT"
+	SettingTypeFloat  = "FLOAT"
+	SettingTypeBool   = "BOOL"
+	SettingTypeJSON   = "JSON"
+)
+
+// Setting scopes - which service/area a setting belongs to
+const (
+	SettingScopeGlobal  = "global"
+	SettingScopeOrder   = "order"
+	SettingScopeCart    = "cart"
+	SettingScopeProduct = "product"
+)
+
+// Setting represents a runtime-tunable platform parameter (admin global configuration)
+// Values are stored as strings and parsed according to ValueType
+// Unique per (scope, key), e.g. ("order", "platform_fee_percent")
+type Setting struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Scope       string    `gorm:"size:50;not null;uniqueIndex:idx_setting_scope_key" json:"scope"`
+	Key         string    `gorm:"size:100;not null;uniqueIndex:idx_setting_scope_key" json:"key"`
+	Value       string    `gorm:"type:text;not null" json:"value"`
+	ValueType   string    `gorm:"column:value_type;size:20;not null;default:'STRING'" json:"value_type"`
+	Description string    `gorm:"type:text" json:"description"`
+	UpdatedBy   uint      `gorm:"column:updated_by" json:"updated_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Setting) TableName() string {
+	return "setting"
+}
+
+// SettingAudit records every change of a setting (who, when, old -> new)
+type SettingAudit struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	SettingID uint      `gorm:"column:setting_id;index;not null" json:"setting_id"`
+	Scope     string    `gorm:"size:50;not null" json:"scope"`
+	Key       string    `gorm:"size:100;not null" json:"key"`
+	OldValue  string    `gorm:"column:old_value;type:text" json:"old_value"`
+	NewValue  string    `gorm:"column:new_value;type:text" json:"new_value"`
+	ChangedBy uint      `gorm:"column:changed_by" json:"changed_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (SettingAudit) TableName() string {
+	return "setting_audit"
+}
+
+// SettingChangedEvent is broadcast to other services when a setting changes
+type SettingChangedEvent struct {
+	Scope     string    `json:"scope"`
+	Key       string    `json:"key"`
+	Value     string    `json:"value"`
+	ValueType string    `json:"value_type"`
+	UpdatedBy uint      `json:"updated_by"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// SettingRepository defines the interface for setting data access
+type SettingRepository interface {
+	Create(setting *Setting) error
+	Update(setting *Setting) error
+	Get(scope, key string) (*Setting, error)
+	List(scope string) ([]*Setting, error) // Empty scope = all settings
+}
+
+// SettingAuditRepository defines the interface for setting audit data access
+type SettingAuditRepository interface {
+	Create(audit *SettingAudit) error
+	ListBySetting(settingID uint, page, limit int) ([]*SettingAudit, int64, error)
+}
+
+// SettingCache caches settings in Redis and broadcasts changes to other services
+// Other services read settings directly from the cache (hash per scope)
+type SettingCache interface {
+	Set(setting *Setting) error
+	Publish(event *SettingChangedEvent) error
+}
@@ -9,6 +9,7 @@ type Shop struct {
 	ID           uint      `gorm:"primaryKey" json:"id"`
 	OwnerUserID  uint      `gorm:"column:owner_user_id;uniqueIndex;not null" json:"owner_user_id"` // 1 User = 1 Shop
 	Name         string    `gorm:"size:100;not null" json:"name"`
+	Slug         string    `gorm:"size:120;index" json:"slug"` // URL slug, auto-generated from Name (unique per shop)
 	Description  string    `gorm:"type:text" json:"description"`
 	LogoURL      string    `gorm:"column:logo_url;size:255" json:"logo_url"`
 	CoverURL     string    `gorm:"column:cover_url;size:255" json:"cover_url"`
@@ -18,6 +19,12 @@ type Shop struct {
 	Status       string    `gorm:"size:20;default:'ACTIVE'" json:"status"` // ACTIVE, SUSPENDED
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
+
+	// Where the shop ships from: province code (see Provinces) and warehouse coordinates,
+	// copied onto the shop's products for "ships from" search and delivery estimates
+	Province  string   `gorm:"size:50;index" json:"province,omitempty"`
+	Latitude  *float64 `gorm:"type:decimal(9,6)" json:"latitude,omitempty"`
+	Longitude *float64 `gorm:"type:decimal(9,6)" json:"longitude,omitempty"`
 }
 
 // TableName specifies the table name for GORM
@@ -25,6 +32,14 @@ func (Shop) TableName() string {
 	return "shop"
 }
 
+// ShopExportFilter selects the shops of the admin export; empty fields match every shop
+type ShopExportFilter struct {
+	Status      string    // ACTIVE or SUSPENDED
+	Province    string    // Ships-from province code
+	CreatedFrom time.Time // Created at or after
+	CreatedTo   time.Time // Created before
+}
+
 // ShopRepository defines the interface for shop data access
 // This is part of the domain layer - it defines WHAT we need, not HOW
 type ShopRepository interface {
@@ -32,9 +47,15 @@ type ShopRepository interface {
 	Update(shop *Shop) error
 	GetByID(id uint) (*Shop, error)
 	GetByOwnerUserID(ownerUserID uint) (*Shop, error)
+	GetBySlug(slug string) (*Shop, error)
+	ExistsBySlug(slug string, excludeID uint) (bool, error) // excludeID = 0 checks all shops
+	GetWithoutSlug() ([]*Shop, error)
 	GetAll(page, limit int) ([]*Shop, int64, error)
 	GetByStatus(status string, page, limit int) ([]*Shop, int64, error)
+	Search(name, slug string, offset, limit int) ([]*Shop, int64, error) // ACTIVE shops whose name or slug contains the text
 	Delete(id uint) error
 	UpdateStatus(id uint, status string) error
+	ListForExport(filter ShopExportFilter, afterID uint, limit int) ([]*Shop, error) // Next batch by ID after afterID
+	OwnerUserIDs(shopIDs []uint) ([]uint, error)                                     // Owners of the ACTIVE shops among shopIDs
 }
 
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// ShopSlugHistory stores previous slugs of a shop
+// When a shop is renamed its old slug is kept here so old shop URLs can be redirected
+type ShopSlugHistory struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ShopID    uint      `gorm:"column:shop_id;index;not null" json:"shop_id"`
+	Slug      string    `gorm:"size:120;uniqueIndex;not null" json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ShopSlugHistory) TableName() string {
+	return "shop_slug_history"
+}
+
+// ShopSlugHistoryRepository defines the interface for shop slug history data access
+type ShopSlugHistoryRepository interface {
+	Create(history *ShopSlugHistory) error
+	GetBySlug(slug string) (*ShopSlugHistory, error)
+	DeleteBySlug(slug string) error
+}
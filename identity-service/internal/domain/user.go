@@ -16,6 +16,7 @@ type User struct {
 	AvatarURL   string    `gorm:"column:avatar_url;size:255" json:"avatar_url"`
 	Role        string    `gorm:"size:20;default:'BUYER'" json:"role"` // ADMIN, SELLER, BUYER
 	Status      string    `gorm:"size:20;default:'ACTIVE'" json:"status"` // ACTIVE, BANNED, DELETED
+	LastLoginAt *time.Time `gorm:"column:last_login_at;index" json:"last_login_at,omitempty"` // Set on each password login
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
@@ -25,6 +26,20 @@ func (User) TableName() string {
 	return "user"
 }
 
+// UserExportFilter selects the users of the admin export; empty fields match every user
+type UserExportFilter struct {
+	Role        string    // ADMIN, SELLER or BUYER
+	Status      string    // ACTIVE, BANNED or DELETED
+	CreatedFrom time.Time // Signed up at or after
+	CreatedTo   time.Time // Signed up before
+}
+
+// UserAudienceFilter selects the ACTIVE users an announcement is sent to
+type UserAudienceFilter struct {
+	Roles         []string  // Any of these roles
+	InactiveSince time.Time // Only users without a login since then (signup counts as the first login); zero = all
+}
+
 // UserRepository defines the interface for user data access
 // This is part of the domain layer - it defines WHAT we need, not HOW
 type UserRepository interface {
@@ -34,5 +49,8 @@ type UserRepository interface {
 	GetByEmail(email string) (*User, error)
 	GetByUsername(username string) (*User, error)
 	Delete(id uint) error
+	ListForExport(filter UserExportFilter, afterID uint, limit int) ([]*User, error) // Next batch by ID after afterID
+	ListAudience(filter UserAudienceFilter, afterID uint, limit int) ([]uint, error) // Next batch of user IDs after afterID
+	UpdateLastLogin(id uint, at time.Time) error
 }
 
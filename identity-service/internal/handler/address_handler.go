@@ -42,14 +42,13 @@ func (h *AddressHandler) CreateAddress(c *gin.Context) {
 	var req service.CreateAddressRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Warn("invalid create address request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	address, err := h.addressService.CreateAddress(userIDUint, &req)
 	if err != nil {
-		h.logger.Error("failed to create address", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to create address", err)
 		return
 	}
 
@@ -75,8 +74,7 @@ func (h *AddressHandler) GetAddresses(c *gin.Context) {
 
 	addresses, err := h.addressService.GetAddresses(userIDUint)
 	if err != nil {
-		h.logger.Error("failed to get addresses", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to get addresses", err)
 		return
 	}
 
@@ -109,7 +107,7 @@ func (h *AddressHandler) GetAddress(c *gin.Context) {
 
 	address, err := h.addressService.GetAddress(userIDUint, uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to get address", err)
 		return
 	}
 
@@ -144,14 +142,13 @@ func (h *AddressHandler) UpdateAddress(c *gin.Context) {
 	var req service.UpdateAddressRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Warn("invalid update address request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	address, err := h.addressService.UpdateAddress(userIDUint, uint(id), &req)
 	if err != nil {
-		h.logger.Error("failed to update address", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to update address", err)
 		return
 	}
 
@@ -184,8 +181,7 @@ func (h *AddressHandler) DeleteAddress(c *gin.Context) {
 	}
 
 	if err := h.addressService.DeleteAddress(userIDUint, uint(id)); err != nil {
-		h.logger.Error("failed to delete address", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to delete address", err)
 		return
 	}
 
@@ -217,8 +213,7 @@ func (h *AddressHandler) SetDefaultAddress(c *gin.Context) {
 	}
 
 	if err := h.addressService.SetDefaultAddress(userIDUint, uint(id)); err != nil {
-		h.logger.Error("failed to set default address", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to set default address", err)
 		return
 	}
 
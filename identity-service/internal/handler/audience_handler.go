@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"identity-service/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AudienceHandler handles the audience lookups of admin announcements (order-service)
+type AudienceHandler struct {
+	audienceService *service.AudienceService
+	logger          *zap.Logger
+}
+
+// NewAudienceHandler creates a new audience handler
+func NewAudienceHandler(audienceService *service.AudienceService, logger *zap.Logger) *AudienceHandler {
+	return &AudienceHandler{
+		audienceService: audienceService,
+		logger:          logger,
+	}
+}
+
+// ListUsers godoc
+// @Summary Users of an announcement segment (internal)
+// @Description Next batch of active user IDs of the segment after after_id, ordered by ID; an empty batch ends the segment (signed service calls only)
+// @Tags internal
+// @Produce json
+// @Param segment query string true "buyers or inactive_users"
+// @Param inactive_days query int false "Days without a login (inactive_users)"
+// @Param after_id query int false "Last user ID of the previous batch"
+// @Param limit query int false "Batch size (max 1000)" default(500)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /internal/audience/users [get]
+func (h *AudienceHandler) ListUsers(c *gin.Context) {
+	afterID, err := strconv.ParseUint(c.DefaultQuery("after_id", "0"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid after_id"})
+		return
+	}
+	inactiveDays, _ := strconv.Atoi(c.Query("inactive_days"))
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	ids, err := h.audienceService.Users(c.Query("segment"), inactiveDays, uint(afterID), limit)
+	if err != nil {
+		respondError(c, h.logger, "failed to list audience", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user_ids": ids})
+}
+
+// ShopOwnersRequest lists the shops whose owners are looked up
+type ShopOwnersRequest struct {
+	ShopIDs []uint `json:"shop_ids"`
+}
+
+// ShopOwners godoc
+// @Summary Owners of shops (internal)
+// @Description User IDs of the owners of the active shops among shop_ids, e.g. the sellers of a category (signed service calls only)
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param request body ShopOwnersRequest true "Shop IDs"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /internal/audience/shop-owners [post]
+func (h *AudienceHandler) ShopOwners(c *gin.Context) {
+	var req ShopOwnersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	ids, err := h.audienceService.ShopOwners(req.ShopIDs)
+	if err != nil {
+		respondError(c, h.logger, "failed to get shop owners", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user_ids": ids})
+}
@@ -38,14 +38,13 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	var req service.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Warn("invalid register request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	response, err := h.authService.Register(&req)
 	if err != nil {
-		h.logger.Error("failed to register", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to register", err)
 		return
 	}
 
@@ -102,7 +101,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	var req service.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Warn("invalid login request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -240,8 +239,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	if err == nil && sessionID != "" {
 		// Delete session from Redis
 		if err := h.authService.LogoutBySession(sessionID); err != nil {
-			h.logger.Error("failed to logout session", zap.Error(err))
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to logout"})
+			respondError(c, h.logger, "failed to logout", err)
 			return
 		}
 
@@ -284,8 +282,7 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 
 	// Revoke all refresh tokens
 	if err := h.authService.Logout(uid); err != nil {
-		h.logger.Error("failed to logout", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to logout"})
+		respondError(c, h.logger, "failed to logout", err)
 		return
 	}
 
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"identity-service/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// EmailTemplateHandler handles HTTP requests for transactional email templates
+type EmailTemplateHandler struct {
+	templateService *service.EmailTemplateService
+	logger          *zap.Logger
+}
+
+// NewEmailTemplateHandler creates a new email template handler
+func NewEmailTemplateHandler(templateService *service.EmailTemplateService, logger *zap.Logger) *EmailTemplateHandler {
+	return &EmailTemplateHandler{
+		templateService: templateService,
+		logger:          logger,
+	}
+}
+
+// ListTemplates godoc
+// @Summary List email templates
+// @Description List the active version of every email template and locale (ADMIN only)
+// @Tags email-templates
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /admin/email-templates [get]
+func (h *EmailTemplateHandler) ListTemplates(c *gin.Context) {
+	templates, err := h.templateService.ListTemplates()
+	if err != nil {
+		respondError(c, h.logger, "failed to list email templates", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"templates": templates})
+}
+
+// GetTemplate godoc
+// @Summary Get email template
+// @Description Get the active version of an email template for a locale (ADMIN only)
+// @Tags email-templates
+// @Produce json
+// @Param key path string true "Template key"
+// @Param locale path string true "Locale"
+// @Success 200 {object} domain.EmailTemplate
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /admin/email-templates/{key}/{locale} [get]
+func (h *EmailTemplateHandler) GetTemplate(c *gin.Context) {
+	template, err := h.templateService.GetTemplate(c.Param("key"), c.Param("locale"))
+	if err != nil {
+		respondError(c, h.logger, "failed to get email template", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// SaveTemplate godoc
+// @Summary Save email template
+// @Description Create a new version of an email template for a locale, active unless draft (ADMIN only)
+// @Tags email-templates
+// @Accept json
+// @Produce json
+// @Param key path string true "Template key"
+// @Param locale path string true "Locale"
+// @Param template body service.SaveEmailTemplateRequest true "Template"
+// @Success 200 {object} domain.EmailTemplate
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /admin/email-templates/{key}/{locale} [put]
+func (h *EmailTemplateHandler) SaveTemplate(c *gin.Context) {
+	var req service.SaveEmailTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found in context"})
+		return
+	}
+
+	template, err := h.templateService.SaveTemplate(c.Param("key"), c.Param("locale"), &req, userID.(uint))
+	if err != nil {
+		respondError(c, h.logger, "failed to save email template", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// ListVersions godoc
+// @Summary List email template versions
+// @Description List all versions of an email template for a locale, newest first (ADMIN only)
+// @Tags email-templates
+// @Produce json
+// @Param key path string true "Template key"
+// @Param locale path string true "Locale"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /admin/email-templates/{key}/{locale}/versions [get]
+func (h *EmailTemplateHandler) ListVersions(c *gin.Context) {
+	versions, err := h.templateService.ListVersions(c.Param("key"), c.Param("locale"))
+	if err != nil {
+		respondError(c, h.logger, "failed to list email template versions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"versions": versions})
+}
+
+// ActivateVersion godoc
+// @Summary Activate email template version
+// @Description Publish a draft or roll back to an earlier version (ADMIN only)
+// @Tags email-templates
+// @Produce json
+// @Param key path string true "Template key"
+// @Param locale path string true "Locale"
+// @Param version path int true "Version"
+// @Success 200 {object} domain.EmailTemplate
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /admin/email-templates/{key}/{locale}/versions/{version}/activate [post]
+func (h *EmailTemplateHandler) ActivateVersion(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid version"})
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found in context"})
+		return
+	}
+
+	template, err := h.templateService.ActivateVersion(c.Param("key"), c.Param("locale"), version, userID.(uint))
+	if err != nil {
+		respondError(c, h.logger, "failed to activate email template version", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// PreviewTemplate godoc
+// @Summary Preview email template
+// @Description Render a stored version or an unsaved template with sample data (ADMIN only)
+// @Tags email-templates
+// @Accept json
+// @Produce json
+// @Param preview body service.PreviewEmailTemplateRequest true "Template and sample data"
+// @Success 200 {object} service.RenderedEmail
+// @Failure 400 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /admin/email-templates/preview [post]
+func (h *EmailTemplateHandler) PreviewTemplate(c *gin.Context) {
+	var req service.PreviewEmailTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	rendered, err := h.templateService.Preview(&req)
+	if err != nil {
+		respondError(c, h.logger, "failed to preview email template", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rendered)
+}
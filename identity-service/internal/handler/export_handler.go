@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"encoding/csv"
+	"fmt"
+	"identity-service/internal/service"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// exportWriteTimeout replaces the server write timeout for an export, which streams
+// for as long as the database keeps returning batches
+const exportWriteTimeout = 10 * time.Minute
+
+// ExportHandler handles the admin CSV exports of users and shops
+type ExportHandler struct {
+	exportService *service.ExportService
+	logger        *zap.Logger
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(exportService *service.ExportService, logger *zap.Logger) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+		logger:        logger,
+	}
+}
+
+// ExportUsers godoc
+// @Summary Export users as CSV
+// @Description Stream the users matching the filters as CSV (ADMIN only), ordered by ID: id, username, email, phone_number, full_name, role, status, created_at
+// @Tags admin
+// @Produce text/csv
+// @Param role query string false "Role (ADMIN, SELLER, BUYER)"
+// @Param status query string false "Status (ACTIVE, BANNED, DELETED)"
+// @Param created_from query string false "First signup day (YYYY-MM-DD)"
+// @Param created_to query string false "Last signup day, included (YYYY-MM-DD)"
+// @Success 200 {file} file "Users"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /admin/users/export [get]
+func (h *ExportHandler) ExportUsers(c *gin.Context) {
+	query := service.UserExportQuery{
+		Role:        c.Query("role"),
+		Status:      c.Query("status"),
+		CreatedFrom: c.Query("created_from"),
+		CreatedTo:   c.Query("created_to"),
+	}
+	filename := fmt.Sprintf("users-%s.csv", time.Now().Format("20060102"))
+
+	h.streamCSV(c, "failed to export users", filename, func(write func([][]string) error) error {
+		return h.exportService.ExportUsers(c.Request.Context(), query, write)
+	})
+}
+
+// ExportShops godoc
+// @Summary Export shops as CSV
+// @Description Stream the shops matching the filters as CSV (ADMIN only), ordered by ID, with their rating over all reviews and their GMV (merchandise subtotal of paid, not cancelled orders) in the GMV period: id, name, slug, owner_user_id, status, is_official, province, created_at, rating_avg, rating_count, gmv, gmv_order_count
+// @Tags admin
+// @Produce text/csv
+// @Param status query string false "Status (ACTIVE, SUSPENDED)"
+// @Param province query string false "Ships-from province code"
+// @Param created_from query string false "First creation day (YYYY-MM-DD)"
+// @Param created_to query string false "Last creation day, included (YYYY-MM-DD)"
+// @Param min_rating query number false "Minimum rating (0-5)"
+// @Param min_gmv query number false "Minimum GMV in the GMV period"
+// @Param gmv_from query string false "First day of the GMV period (YYYY-MM-DD), default 30 days before gmv_to"
+// @Param gmv_to query string false "Last day of the GMV period, included (YYYY-MM-DD), default today"
+// @Success 200 {file} file "Shops"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{} "Ratings or GMV unavailable"
+// @Security BearerAuth
+// @Router /admin/shops/export [get]
+func (h *ExportHandler) ExportShops(c *gin.Context) {
+	query := service.ShopExportQuery{
+		Status:      c.Query("status"),
+		Province:    c.Query("province"),
+		CreatedFrom: c.Query("created_from"),
+		CreatedTo:   c.Query("created_to"),
+		MinRating:   c.Query("min_rating"),
+		MinGMV:      c.Query("min_gmv"),
+		GMVFrom:     c.Query("gmv_from"),
+		GMVTo:       c.Query("gmv_to"),
+	}
+	filename := fmt.Sprintf("shops-%s.csv", time.Now().Format("20060102"))
+
+	h.streamCSV(c, "failed to export shops", filename, func(write func([][]string) error) error {
+		return h.exportService.ExportShops(c.Request.Context(), query, write)
+	})
+}
+
+// streamCSV runs an export that writes its records in batches, sending each batch to the
+// client as soon as it is written. The response starts with the first batch, so errors
+// before it (invalid filters) get their usual status; a failure later can only cut the
+// file short, and is logged
+func (h *ExportHandler) streamCSV(c *gin.Context, message, filename string, export func(write func([][]string) error) error) {
+	w := csv.NewWriter(c.Writer)
+	started := false
+	err := export(func(records [][]string) error {
+		if !started {
+			started = true
+			if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(exportWriteTimeout)); err != nil {
+				h.logger.Warn("Failed to extend the write deadline of an export", zap.Error(err))
+			}
+			c.Header("Content-Type", "text/csv; charset=utf-8")
+			c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+			c.Status(http.StatusOK)
+		}
+		if err := w.WriteAll(records); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err == nil {
+		return
+	}
+	if !started {
+		respondError(c, h.logger, message, err)
+		return
+	}
+	h.logger.Error(message+", the file was cut short", zap.String("filename", filename), zap.Error(err))
+}
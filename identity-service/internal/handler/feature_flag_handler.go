@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"identity-service/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// FeatureFlagHandler handles HTTP requests for feature flags
+type FeatureFlagHandler struct {
+	flagService *service.FeatureFlagService
+	logger      *zap.Logger
+}
+
+// NewFeatureFlagHandler creates a new feature flag handler
+func NewFeatureFlagHandler(flagService *service.FeatureFlagService, logger *zap.Logger) *FeatureFlagHandler {
+	return &FeatureFlagHandler{
+		flagService: flagService,
+		logger:      logger,
+	}
+}
+
+// ListFlags godoc
+// @Summary List feature flags
+// @Description List all feature flags (ADMIN only)
+// @Tags feature-flags
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /admin/feature-flags [get]
+func (h *FeatureFlagHandler) ListFlags(c *gin.Context) {
+	flags, err := h.flagService.ListFlags()
+	if err != nil {
+		respondError(c, h.logger, "failed to list feature flags", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"flags": flags})
+}
+
+// GetFlag godoc
+// @Summary Get feature flag
+// @Description Get a feature flag by key (ADMIN only)
+// @Tags feature-flags
+// @Produce json
+// @Param key path string true "Flag key"
+// @Success 200 {object} domain.FeatureFlag
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /admin/feature-flags/{key} [get]
+func (h *FeatureFlagHandler) GetFlag(c *gin.Context) {
+	flag, err := h.flagService.GetFlag(c.Param("key"))
+	if err != nil {
+		respondError(c, h.logger, "failed to get feature flag", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, flag)
+}
+
+// UpsertFlag godoc
+// @Summary Create or update feature flag
+// @Description Create or update a feature flag (ADMIN only). Rollout by user percentage and/or allow-listed users/shops
+// @Tags feature-flags
+// @Accept json
+// @Produce json
+// @Param key path string true "Flag key"
+// @Param flag body service.UpsertFeatureFlagRequest true "Flag config"
+// @Success 200 {object} domain.FeatureFlag
+// @Failure 400 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /admin/feature-flags/{key} [put]
+func (h *FeatureFlagHandler) UpsertFlag(c *gin.Context) {
+	var req service.UpsertFeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found in context"})
+		return
+	}
+
+	flag, err := h.flagService.UpsertFlag(c.Param("key"), &req, userID.(uint))
+	if err != nil {
+		respondError(c, h.logger, "failed to save feature flag", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, flag)
+}
+
+// DeleteFlag godoc
+// @Summary Delete feature flag
+// @Description Delete a feature flag (ADMIN only). Services treat missing flags as disabled
+// @Tags feature-flags
+// @Produce json
+// @Param key path string true "Flag key"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /admin/feature-flags/{key} [delete]
+func (h *FeatureFlagHandler) DeleteFlag(c *gin.Context) {
+	if err := h.flagService.DeleteFlag(c.Param("key")); err != nil {
+		respondError(c, h.logger, "failed to delete feature flag", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "feature flag deleted successfully"})
+}
+
+// EvaluateFlags godoc
+// @Summary Evaluate feature flags
+// @Description Get enabled state of all flags for the current user (and optional shop)
+// @Tags feature-flags
+// @Produce json
+// @Param shop_id query int false "Shop ID"
+// @Success 200 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /feature-flags [get]
+func (h *FeatureFlagHandler) EvaluateFlags(c *gin.Context) {
+	var userID uint
+	if id, exists := c.Get("user_id"); exists {
+		userID = id.(uint)
+	}
+	shopID, _ := strconv.ParseUint(c.Query("shop_id"), 10, 32)
+
+	flags, err := h.flagService.EvaluateFlags(userID, uint(shopID))
+	if err != nil {
+		respondError(c, h.logger, "failed to evaluate feature flags", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"flags": flags})
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"identity-service/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ImpersonationHandler handles admin impersonation of users (support cases)
+type ImpersonationHandler struct {
+	impersonationService *service.ImpersonationService
+	logger               *zap.Logger
+}
+
+// NewImpersonationHandler creates a new impersonation handler
+func NewImpersonationHandler(impersonationService *service.ImpersonationService, logger *zap.Logger) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		impersonationService: impersonationService,
+		logger:               logger,
+	}
+}
+
+// StartImpersonation godoc
+// @Summary Impersonate a user
+// @Description Issue a time-boxed access token of a user for a support case (ADMIN only). The token carries an act claim naming the admin, cannot be refreshed and is recorded in the impersonation audit trail
+// @Tags impersonation
+// @Accept json
+// @Produce json
+// @Param request body service.StartImpersonationRequest true "User, reason and duration"
+// @Success 201 {object} service.ImpersonationResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /admin/impersonations [post]
+func (h *ImpersonationHandler) StartImpersonation(c *gin.Context) {
+	var req service.StartImpersonationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found in context"})
+		return
+	}
+
+	response, err := h.impersonationService.Start(adminID.(uint), &req, c.ClientIP())
+	if err != nil {
+		h.logger.Warn("failed to start impersonation", zap.Uint("user_id", req.UserID), zap.Error(err))
+		respondError(c, h.logger, "failed to start impersonation", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// ListImpersonations godoc
+// @Summary List impersonations
+// @Description Impersonation audit trail, newest first (ADMIN only)
+// @Tags impersonation
+// @Produce json
+// @Param admin_id query int false "Filter by admin"
+// @Param user_id query int false "Filter by impersonated user"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /admin/impersonations [get]
+func (h *ImpersonationHandler) ListImpersonations(c *gin.Context) {
+	adminID, _ := strconv.ParseUint(c.Query("admin_id"), 10, 32)
+	userID, _ := strconv.ParseUint(c.Query("user_id"), 10, 32)
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	impersonations, total, err := h.impersonationService.List(uint(adminID), uint(userID), page, limit)
+	if err != nil {
+		respondError(c, h.logger, "failed to list impersonations", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"impersonations": impersonations,
+		"total":          total,
+		"page":           page,
+		"limit":          limit,
+	})
+}
+
+// RevokeImpersonation godoc
+// @Summary Revoke an impersonation
+// @Description End an impersonation before it expires; its token stops working immediately (ADMIN only)
+// @Tags impersonation
+// @Produce json
+// @Param id path string true "Impersonation ID"
+// @Success 200 {object} domain.Impersonation
+// @Failure 400 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /admin/impersonations/{id} [delete]
+func (h *ImpersonationHandler) RevokeImpersonation(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found in context"})
+		return
+	}
+
+	impersonation, err := h.impersonationService.Revoke(c.Param("id"), adminID.(uint))
+	if err != nil {
+		h.logger.Warn("failed to revoke impersonation", zap.String("impersonation_id", c.Param("id")), zap.Error(err))
+		respondError(c, h.logger, "failed to revoke impersonation", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, impersonation)
+}
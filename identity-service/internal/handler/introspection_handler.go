@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"identity-service/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// IntrospectionHandler handles token introspection requests of downstream services
+type IntrospectionHandler struct {
+	introspectionService *service.IntrospectionService
+	logger               *zap.Logger
+}
+
+// NewIntrospectionHandler creates a new introspection handler
+func NewIntrospectionHandler(introspectionService *service.IntrospectionService, logger *zap.Logger) *IntrospectionHandler {
+	return &IntrospectionHandler{
+		introspectionService: introspectionService,
+		logger:               logger,
+	}
+}
+
+// Introspect godoc
+// @Summary Introspect a token
+// @Description RFC 7662 token introspection for internal services (HTTP Basic client credentials). Accepts an access token, session ID or refresh token and reports whether it is active (valid, not revoked, user ACTIVE) with its user, role and scopes
+// @Tags auth
+// @Accept x-www-form-urlencoded,json
+// @Produce json
+// @Param token formData string true "Token to introspect"
+// @Param token_type_hint formData string false "access_token, session_id or refresh_token"
+// @Success 200 {object} service.IntrospectionResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Security BasicAuth
+// @Router /auth/introspect [post]
+func (h *IntrospectionHandler) Introspect(c *gin.Context) {
+	var req service.IntrospectionRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid_request"})
+		return
+	}
+
+	resp := h.introspectionService.Introspect(c.Request.Context(), &req)
+	h.logger.Debug("token introspected",
+		zap.String("client", c.GetString("introspection_client")),
+		zap.String("token_type_hint", req.TokenTypeHint),
+		zap.Bool("active", resp.Active),
+	)
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, resp)
+}
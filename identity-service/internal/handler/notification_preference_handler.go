@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"identity-service/internal/domain"
+	"identity-service/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// NotificationPreferenceHandler handles HTTP requests for the current user's notification preferences
+type NotificationPreferenceHandler struct {
+	preferenceService *service.NotificationPreferenceService
+	logger            *zap.Logger
+}
+
+// NewNotificationPreferenceHandler creates a new notification preference handler
+func NewNotificationPreferenceHandler(preferenceService *service.NotificationPreferenceService, logger *zap.Logger) *NotificationPreferenceHandler {
+	return &NotificationPreferenceHandler{
+		preferenceService: preferenceService,
+		logger:            logger,
+	}
+}
+
+// GetPreferences godoc
+// @Summary Get notification preferences
+// @Description Which notifications (order_updates, promotions, chat, price_alerts) the current user receives on which channel (email, sms, push); defaults where not set
+// @Tags users
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /users/me/notification-preferences [get]
+func (h *NotificationPreferenceHandler) GetPreferences(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	prefs, err := h.preferenceService.GetPreferences(userID.(uint))
+	if err != nil {
+		respondError(c, h.logger, "failed to get notification preferences", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": prefs})
+}
+
+// UpdatePreferences godoc
+// @Summary Update notification preferences
+// @Description Change notification preferences of the current user; only the given category/channel pairs change, e.g. {"promotions": {"email": true}}
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body map[string]map[string]bool true "category -> channel -> enabled"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /users/me/notification-preferences [put]
+func (h *NotificationPreferenceHandler) UpdatePreferences(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req domain.NotificationPreferenceMatrix
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	prefs, err := h.preferenceService.UpdatePreferences(userID.(uint), req)
+	if err != nil {
+		respondError(c, h.logger, "failed to update notification preferences", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": prefs})
+}
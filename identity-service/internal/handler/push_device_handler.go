@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"identity-service/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// PushDeviceHandler handles HTTP requests for push notification device tokens
+type PushDeviceHandler struct {
+	deviceService *service.PushDeviceService
+	logger        *zap.Logger
+}
+
+// NewPushDeviceHandler creates a new push device handler
+func NewPushDeviceHandler(deviceService *service.PushDeviceService, logger *zap.Logger) *PushDeviceHandler {
+	return &PushDeviceHandler{
+		deviceService: deviceService,
+		logger:        logger,
+	}
+}
+
+// ListDevices godoc
+// @Summary List push devices
+// @Description Devices of the current user registered for push notifications (tokens are not returned)
+// @Tags users
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /users/me/devices [get]
+func (h *PushDeviceHandler) ListDevices(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	devices, err := h.deviceService.ListDevices(userID.(uint))
+	if err != nil {
+		respondError(c, h.logger, "failed to list push devices", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": devices})
+}
+
+// RegisterDevice godoc
+// @Summary Register push device
+// @Description Register or refresh the FCM/APNs token of a device of the current user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body service.RegisterPushDeviceRequest true "Device token"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /users/me/devices [post]
+func (h *PushDeviceHandler) RegisterDevice(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	var req service.RegisterPushDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	device, err := h.deviceService.RegisterDevice(userID.(uint), &req)
+	if err != nil {
+		respondError(c, h.logger, "failed to register push device", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": device})
+}
+
+// UnregisterDevice godoc
+// @Summary Unregister push device
+// @Description Stop push notifications to a device of the current user
+// @Tags users
+// @Produce json
+// @Param device_id path string true "Device ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /users/me/devices/{device_id} [delete]
+func (h *PushDeviceHandler) UnregisterDevice(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+
+	if err := h.deviceService.UnregisterDevice(userID.(uint), c.Param("device_id")); err != nil {
+		respondError(c, h.logger, "failed to unregister push device", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "push device unregistered"})
+}
+
+// GetTargets godoc
+// @Summary Push targets of a user (internal)
+// @Description Device tokens the push channel delivers to (signed service calls only)
+// @Tags internal
+// @Produce json
+// @Param user_id path int true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /internal/push/users/{user_id}/targets [get]
+func (h *PushDeviceHandler) GetTargets(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
+		return
+	}
+
+	targets, err := h.deviceService.GetTargets(uint(userID))
+	if err != nil {
+		respondError(c, h.logger, "failed to get push targets", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": targets})
+}
+
+// ReportDelivery godoc
+// @Summary Report push delivery results (internal)
+// @Description Prunes tokens rejected by FCM/APNs and tracks transient failures (signed service calls only)
+// @Tags internal
+// @Accept json
+// @Produce json
+// @Param request body service.PushDeliveryReport true "Delivery results"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Router /internal/push/delivery-reports [post]
+func (h *PushDeviceHandler) ReportDelivery(c *gin.Context) {
+	var req service.PushDeliveryReport
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	pruned, err := h.deviceService.ReportDelivery(&req)
+	if err != nil {
+		respondError(c, h.logger, "failed to process push delivery report", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pruned": pruned})
+}
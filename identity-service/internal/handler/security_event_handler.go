@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"identity-service/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SecurityEventHandler handles admin HTTP requests for security audit events
+type SecurityEventHandler struct {
+	bruteForceService *service.BruteForceService
+	logger            *zap.Logger
+}
+
+// NewSecurityEventHandler creates a new security event handler
+func NewSecurityEventHandler(bruteForceService *service.BruteForceService, logger *zap.Logger) *SecurityEventHandler {
+	return &SecurityEventHandler{
+		bruteForceService: bruteForceService,
+		logger:            logger,
+	}
+}
+
+// ListEvents godoc
+// @Summary List security events
+// @Description Security audit events, newest first (ADMIN only), e.g. AUTH_THROTTLED when a client hits the brute-force limit of token refresh
+// @Tags security
+// @Produce json
+// @Param type query string false "Event type (AUTH_THROTTLED)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /admin/security-events [get]
+func (h *SecurityEventHandler) ListEvents(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	events, total, err := h.bruteForceService.ListEvents(c.Query("type"), page, limit)
+	if err != nil {
+		respondError(c, h.logger, "failed to list security events", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+		"total":  total,
+		"page":   page,
+		"limit":  limit,
+	})
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"identity-service/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SettingHandler handles HTTP requests for admin global configuration
+type SettingHandler struct {
+	settingService *service.SettingService
+	logger         *zap.Logger
+}
+
+// NewSettingHandler creates a new setting handler
+func NewSettingHandler(settingService *service.SettingService, logger *zap.Logger) *SettingHandler {
+	return &SettingHandler{
+		settingService: settingService,
+		logger:         logger,
+	}
+}
+
+// ListSettings godoc
+// @Summary List settings
+// @Description List platform settings (ADMIN only), optionally filtered by scope
+// @Tags settings
+// @Produce json
+// @Param scope query string false "Scope (global, order, cart, product)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /admin/settings [get]
+func (h *SettingHandler) ListSettings(c *gin.Context) {
+	settings, err := h.settingService.ListSettings(c.Query("scope"))
+	if err != nil {
+		respondError(c, h.logger, "failed to list settings", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+// GetSetting godoc
+// @Summary Get setting
+// @Description Get a platform setting by scope and key (ADMIN only)
+// @Tags settings
+// @Produce json
+// @Param scope path string true "Scope"
+// @Param key path string true "Key"
+// @Success 200 {object} domain.Setting
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /admin/settings/{scope}/{key} [get]
+func (h *SettingHandler) GetSetting(c *gin.Context) {
+	setting, err := h.settingService.GetSetting(c.Param("scope"), c.Param("key"))
+	if err != nil {
+		respondError(c, h.logger, "failed to get setting", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, setting)
+}
+
+// UpdateSetting godoc
+// @Summary Create or update setting
+// @Description Create or update a platform setting (ADMIN only). Change is audited and broadcast to services
+// @Tags settings
+// @Accept json
+// @Produce json
+// @Param scope path string true "Scope"
+// @Param key path string true "Key"
+// @Param setting body service.UpdateSettingRequest true "Setting value"
+// @Success 200 {object} domain.Setting
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /admin/settings/{scope}/{key} [put]
+func (h *SettingHandler) UpdateSetting(c *gin.Context) {
+	var req service.UpdateSettingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user_id not found in context"})
+		return
+	}
+
+	setting, err := h.settingService.UpdateSetting(c.Param("scope"), c.Param("key"), &req, userID.(uint))
+	if err != nil {
+		respondError(c, h.logger, "failed to update setting", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, setting)
+}
+
+// GetSettingAudit godoc
+// @Summary Get setting audit
+// @Description Get change history of a setting (ADMIN only)
+// @Tags settings
+// @Produce json
+// @Param scope path string true "Scope"
+// @Param key path string true "Key"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /admin/settings/{scope}/{key}/audit [get]
+func (h *SettingHandler) GetSettingAudit(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	audits, total, err := h.settingService.GetSettingAudit(c.Param("scope"), c.Param("key"), page, limit)
+	if err != nil {
+		respondError(c, h.logger, "failed to get setting audit", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"audits": audits,
+		"total":  total,
+		"page":   page,
+		"limit":  limit,
+	})
+}
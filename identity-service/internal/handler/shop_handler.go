@@ -39,7 +39,7 @@ func NewShopHandler(shopService *service.ShopService, logger *zap.Logger) *ShopH
 func (h *ShopHandler) CreateShop(c *gin.Context) {
 	var req service.CreateShopRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -55,8 +55,7 @@ func (h *ShopHandler) CreateShop(c *gin.Context) {
 
 	shop, err := h.shopService.CreateShop(&req)
 	if err != nil {
-		h.logger.Error("failed to create shop", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to create shop", err)
 		return
 	}
 
@@ -82,7 +81,33 @@ func (h *ShopHandler) GetShop(c *gin.Context) {
 
 	shop, err := h.shopService.GetShop(uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to get shop", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, shop)
+}
+
+// GetShopBySlug godoc
+// @Summary Get shop by slug
+// @Description Get shop details by URL slug. Old slugs (before rename) redirect to the current slug with 301
+// @Tags shops
+// @Produce json
+// @Param slug path string true "Shop slug"
+// @Success 200 {object} domain.Shop
+// @Success 301 {string} string "Redirect to current slug"
+// @Failure 404 {object} map[string]interface{}
+// @Router /shops/slug/{slug} [get]
+func (h *ShopHandler) GetShopBySlug(c *gin.Context) {
+	shop, redirected, err := h.shopService.GetShopBySlug(c.Param("slug"))
+	if err != nil {
+		respondError(c, h.logger, "failed to get shop", err)
+		return
+	}
+
+	// Old slug - redirect so clients/SEO pick up the new URL
+	if redirected {
+		c.Redirect(http.StatusMovedPermanently, "/api/v1/shops/slug/"+shop.Slug)
 		return
 	}
 
@@ -110,7 +135,7 @@ func (h *ShopHandler) GetMyShop(c *gin.Context) {
 
 	shop, err := h.shopService.GetMyShop(userID.(uint))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to get shop", err)
 		return
 	}
 
@@ -133,8 +158,7 @@ func (h *ShopHandler) ListShops(c *gin.Context) {
 
 	shops, total, err := h.shopService.ListShops(page, limit)
 	if err != nil {
-		h.logger.Error("failed to list shops", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list shops"})
+		respondError(c, h.logger, "failed to list shops", err)
 		return
 	}
 
@@ -146,6 +170,30 @@ func (h *ShopHandler) ListShops(c *gin.Context) {
 	})
 }
 
+// SearchShops godoc
+// @Summary Search shops
+// @Description Find active shops by name, ignoring case and diacritics; official and best rated shops first
+// @Tags shops
+// @Produce json
+// @Param q query string true "Search text"
+// @Param limit query int false "Items per page (max 50)" default(10)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Success 200 {object} service.ShopSearchResult
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /shops/search [get]
+func (h *ShopHandler) SearchShops(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	result, err := h.shopService.SearchShops(c.Query("q"), c.Query("cursor"), limit)
+	if err != nil {
+		respondError(c, h.logger, "failed to search shops", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 // UpdateShop godoc
 // @Summary Update shop
 // @Description Update shop information (only shop owner or ADMIN)
@@ -178,18 +226,13 @@ func (h *ShopHandler) UpdateShop(c *gin.Context) {
 
 	var req service.UpdateShopRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	shop, err := h.shopService.UpdateShop(uint(id), userID.(uint), &req)
 	if err != nil {
-		h.logger.Error("failed to update shop", zap.Error(err))
-		if err.Error() == "only shop owner or ADMIN can update shop" {
-			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to update shop", err)
 		return
 	}
 
@@ -224,12 +267,7 @@ func (h *ShopHandler) DeleteShop(c *gin.Context) {
 	}
 
 	if err := h.shopService.DeleteShop(uint(id), userID.(uint)); err != nil {
-		h.logger.Error("failed to delete shop", zap.Error(err))
-		if err.Error() == "only ADMIN can delete shop" {
-			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to delete shop", err)
 		return
 	}
 
@@ -269,17 +307,12 @@ func (h *ShopHandler) UpdateShopStatus(c *gin.Context) {
 		Status string `json:"status" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	if err := h.shopService.UpdateShopStatus(uint(id), req.Status, userID.(uint)); err != nil {
-		h.logger.Error("failed to update shop status", zap.Error(err))
-		if err.Error() == "only ADMIN can update shop status" {
-			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to update shop status", err)
 		return
 	}
 
@@ -39,8 +39,7 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 
 	profile, err := h.userService.GetProfile(userIDUint)
 	if err != nil {
-		h.logger.Error("failed to get profile", zap.Error(err))
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to get profile", err)
 		return
 	}
 
@@ -68,14 +67,13 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	var req service.UpdateProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Warn("invalid update profile request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	profile, err := h.userService.UpdateProfile(userIDUint, &req)
 	if err != nil {
-		h.logger.Error("failed to update profile", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to update profile", err)
 		return
 	}
 
@@ -104,13 +102,12 @@ func (h *UserHandler) ChangePassword(c *gin.Context) {
 	var req service.ChangePasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Warn("invalid change password request", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	if err := h.userService.ChangePassword(userIDUint, &req); err != nil {
-		h.logger.Error("failed to change password", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to change password", err)
 		return
 	}
 
@@ -41,3 +41,17 @@ func AuthMiddleware(authService *service.AuthService) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// AdminMiddleware only allows users with ADMIN role
+// Must be used after AuthMiddleware (needs user_role in context)
+func AdminMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, _ := c.Get("user_role")
+		if role != "ADMIN" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "admin permission required"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"identity-service/internal/service"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// BruteForceMiddleware throttles an auth endpoint per client IP with the given policy:
+// attempts over the free allowance are delayed progressively, attempts over the limit
+// get 429 with Retry-After. Fails open (logs) if Redis is unavailable
+func BruteForceMiddleware(bruteForceService *service.BruteForceService, policy string, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		decision, err := bruteForceService.Check(c.Request.Context(), policy, c.ClientIP(), c.Request.URL.Path)
+		if err != nil {
+			logger.Warn("brute-force check failed, allowing request", zap.String("policy", policy), zap.Error(err))
+			c.Next()
+			return
+		}
+
+		if decision.Blocked {
+			retryAfter := int(math.Ceil(decision.RetryAfter.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.JSON(http.StatusTooManyRequests, gin.H{
+				"error":       "too many attempts, try again later",
+				"retry_after": retryAfter,
+			})
+			c.Abort()
+			return
+		}
+
+		if decision.Delay > 0 {
+			timer := time.NewTimer(decision.Delay)
+			select {
+			case <-timer.C:
+			case <-c.Request.Context().Done():
+				timer.Stop()
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"identity-service/config"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Fault injection request headers, honored when fault_injection.headers is on and
+// X-Fault-Target names this service (the gateway forwards them to every backend)
+const (
+	FaultTargetHeader = "X-Fault-Target" // service to inject into, e.g. product-service
+	FaultDelayHeader  = "X-Fault-Delay"  // latency to add, e.g. 2s
+	FaultStatusHeader = "X-Fault-Status" // status to answer with instead of handling the request
+	FaultRateHeader   = "X-Fault-Rate"   // share of requests that get the status, 0-1 (default 1)
+
+	// FaultInjectedHeader names the service on responses it delayed or failed on purpose
+	FaultInjectedHeader = "X-Fault-Injected"
+)
+
+// injectedFault is what to do to one request
+type injectedFault struct {
+	delay  time.Duration
+	status int
+}
+
+// FaultInjection adds latency and errors to requests, to check how callers handle slow
+// and failing dependencies. Faults come from the configured rules and, if enabled, from
+// the X-Fault-* headers. A no-op unless fault_injection.enabled (refused in release mode)
+//
+// This file is the same in the gateway and every service (each image builds from its own
+// directory, so there is no shared module); scripts/check-shared-copies.sh compares them
+func FaultInjection(cfg *config.FaultInjectionConfig, service string, logger *zap.Logger) gin.HandlerFunc {
+	if !cfg.Enabled {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	logger.Warn("fault injection enabled", zap.Int("rules", len(cfg.Rules)), zap.Bool("headers", cfg.Headers))
+
+	return func(c *gin.Context) {
+		fault := faultFromRules(cfg.Rules, c.Request)
+		if cfg.Headers && c.GetHeader(FaultTargetHeader) == service {
+			fault = fault.merge(faultFromHeaders(c.Request.Header))
+		}
+		if fault.delay == 0 && fault.status == 0 {
+			c.Next()
+			return
+		}
+
+		c.Header(FaultInjectedHeader, service)
+		if fault.delay > 0 {
+			timer := time.NewTimer(fault.delay)
+			select {
+			case <-timer.C:
+			case <-c.Request.Context().Done():
+				// Caller gave up (its timeout fired) - nothing left to answer
+				timer.Stop()
+				c.Abort()
+				return
+			}
+		}
+		if fault.status != 0 {
+			logger.Debug("injected fault",
+				zap.String("path", c.Request.URL.Path),
+				zap.Duration("delay", fault.delay),
+				zap.Int("status", fault.status),
+			)
+			c.AbortWithStatusJSON(fault.status, gin.H{"error": "injected fault"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// faultFromRules combines the rules matching the request: latencies add up, the
+// first rule whose error rate fires sets the status
+func faultFromRules(rules []config.FaultRuleConfig, r *http.Request) injectedFault {
+	var fault injectedFault
+	for _, rule := range rules {
+		if !strings.HasPrefix(r.URL.Path, rule.PathPrefix) {
+			continue
+		}
+		if rule.Method != "" && !strings.EqualFold(rule.Method, r.Method) {
+			continue
+		}
+		fault.delay += rule.Latency
+		if rule.Jitter > 0 {
+			fault.delay += rand.N(rule.Jitter)
+		}
+		if fault.status == 0 && rule.ErrorRate > 0 && rand.Float64() < rule.ErrorRate {
+			fault.status = rule.ErrorStatus
+			if fault.status == 0 {
+				fault.status = http.StatusServiceUnavailable
+			}
+		}
+	}
+	return fault
+}
+
+// faultFromHeaders reads the X-Fault-* headers; malformed values are ignored
+func faultFromHeaders(h http.Header) injectedFault {
+	var fault injectedFault
+	if delay, err := time.ParseDuration(h.Get(FaultDelayHeader)); err == nil && delay > 0 {
+		fault.delay = delay
+	}
+	status, err := strconv.Atoi(h.Get(FaultStatusHeader))
+	if err != nil || status < 400 || status > 599 {
+		return fault
+	}
+	rate := 1.0
+	if v := h.Get(FaultRateHeader); v != "" {
+		if rate, err = strconv.ParseFloat(v, 64); err != nil {
+			return fault
+		}
+	}
+	if rand.Float64() < rate {
+		fault.status = status
+	}
+	return fault
+}
+
+// merge adds the latency of other and takes its status when none is set yet
+func (f injectedFault) merge(other injectedFault) injectedFault {
+	f.delay += other.delay
+	if f.status == 0 {
+		f.status = other.status
+	}
+	return f
+}
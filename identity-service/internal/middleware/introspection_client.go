@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// IntrospectionClientMiddleware authenticates internal services calling the introspection
+// endpoint with HTTP Basic client credentials (client ID -> secret). No clients configured
+// rejects every request
+func IntrospectionClientMiddleware(clients map[string]string, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		clientID, secret, ok := c.Request.BasicAuth()
+		expected, known := clients[clientID]
+		if !ok || !known || expected == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(expected)) != 1 {
+			logger.Warn("introspection client authentication failed",
+				zap.String("client", clientID),
+				zap.String("ip", c.ClientIP()),
+			)
+			c.Header("WWW-Authenticate", `Basic realm="introspection"`)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid_client"})
+			c.Abort()
+			return
+		}
+
+		c.Set("introspection_client", clientID)
+		c.Next()
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"identity-service/pkg/serviceauth"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RequireService only allows internal calls signed by one of the given services
+// (X-Service-Token, see pkg/serviceauth); a nil verifier disables the check
+func RequireService(verifier *serviceauth.Verifier, logger *zap.Logger, services ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if verifier == nil {
+			c.Next()
+			return
+		}
+		caller, err := verifier.Verify(c.GetHeader(serviceauth.Header))
+		if err != nil {
+			logger.Warn("service authentication failed", zap.String("ip", c.ClientIP()), zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "service authentication required"})
+			return
+		}
+		if !slices.Contains(services, caller) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "service not allowed"})
+			return
+		}
+		c.Set("calling_service", caller)
+		c.Next()
+	}
+}
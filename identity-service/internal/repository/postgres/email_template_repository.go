@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"identity-service/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// emailTemplateRepository implements the EmailTemplateRepository interface
+type emailTemplateRepository struct {
+	db *gorm.DB
+}
+
+// NewEmailTemplateRepository creates a new PostgreSQL email template repository
+func NewEmailTemplateRepository(db *gorm.DB) domain.EmailTemplateRepository {
+	return &emailTemplateRepository{db: db}
+}
+
+// Create inserts a new template version
+func (r *emailTemplateRepository) Create(template *domain.EmailTemplate) error {
+	return r.db.Create(template).Error
+}
+
+// GetActive retrieves the active version of a template
+func (r *emailTemplateRepository) GetActive(key, locale string) (*domain.EmailTemplate, error) {
+	var template domain.EmailTemplate
+	err := r.db.Where("key = ? AND locale = ? AND is_active = ?", key, locale, true).First(&template).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// GetVersion retrieves a specific version of a template
+func (r *emailTemplateRepository) GetVersion(key, locale string, version int) (*domain.EmailTemplate, error) {
+	var template domain.EmailTemplate
+	err := r.db.Where("key = ? AND locale = ? AND version = ?", key, locale, version).First(&template).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// LatestVersion returns the highest version number of a template (0 if none)
+func (r *emailTemplateRepository) LatestVersion(key, locale string) (int, error) {
+	var version int
+	err := r.db.Model(&domain.EmailTemplate{}).
+		Where("key = ? AND locale = ?", key, locale).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&version).Error
+	return version, err
+}
+
+// ListActive retrieves the active version of every template and locale
+func (r *emailTemplateRepository) ListActive() ([]*domain.EmailTemplate, error) {
+	var templates []*domain.EmailTemplate
+	if err := r.db.Where("is_active = ?", true).Order("key, locale").Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// ListVersions retrieves all versions of a template, newest first
+func (r *emailTemplateRepository) ListVersions(key, locale string) ([]*domain.EmailTemplate, error) {
+	var templates []*domain.EmailTemplate
+	err := r.db.Where("key = ? AND locale = ?", key, locale).Order("version DESC").Find(&templates).Error
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// Activate makes one version the active one, in a single transaction
+func (r *emailTemplateRepository) Activate(key, locale string, version int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.EmailTemplate{}).
+			Where("key = ? AND locale = ? AND version <> ?", key, locale, version).
+			Update("is_active", false).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&domain.EmailTemplate{}).
+			Where("key = ? AND locale = ? AND version = ?", key, locale, version).
+			Update("is_active", true)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"identity-service/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// featureFlagRepository implements the FeatureFlagRepository interface
+// This is the infrastructure layer - it knows HOW to interact with PostgreSQL
+type featureFlagRepository struct {
+	db *gorm.DB
+}
+
+// NewFeatureFlagRepository creates a new PostgreSQL feature flag repository
+func NewFeatureFlagRepository(db *gorm.DB) domain.FeatureFlagRepository {
+	return &featureFlagRepository{db: db}
+}
+
+// Create inserts a new feature flag
+func (r *featureFlagRepository) Create(flag *domain.FeatureFlag) error {
+	return r.db.Create(flag).Error
+}
+
+// Update updates an existing feature flag
+func (r *featureFlagRepository) Update(flag *domain.FeatureFlag) error {
+	return r.db.Save(flag).Error
+}
+
+// GetByKey retrieves a feature flag by key
+func (r *featureFlagRepository) GetByKey(key string) (*domain.FeatureFlag, error) {
+	var flag domain.FeatureFlag
+	err := r.db.Where("key = ?", key).First(&flag).Error
+	if err != nil {
+		return nil, err
+	}
+	return &flag, nil
+}
+
+// GetAll retrieves all feature flags
+func (r *featureFlagRepository) GetAll() ([]*domain.FeatureFlag, error) {
+	var flags []*domain.FeatureFlag
+	if err := r.db.Order("key").Find(&flags).Error; err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
+
+// Delete removes a feature flag
+func (r *featureFlagRepository) Delete(key string) error {
+	return r.db.Where("key = ?", key).Delete(&domain.FeatureFlag{}).Error
+}
@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"identity-service/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// impersonationRepository implements the ImpersonationRepository interface
+type impersonationRepository struct {
+	db *gorm.DB
+}
+
+// NewImpersonationRepository creates a new PostgreSQL impersonation repository
+func NewImpersonationRepository(db *gorm.DB) domain.ImpersonationRepository {
+	return &impersonationRepository{db: db}
+}
+
+// Create inserts a new impersonation record
+func (r *impersonationRepository) Create(impersonation *domain.Impersonation) error {
+	return r.db.Create(impersonation).Error
+}
+
+// GetByID retrieves an impersonation by ID
+func (r *impersonationRepository) GetByID(id string) (*domain.Impersonation, error) {
+	var impersonation domain.Impersonation
+	if err := r.db.First(&impersonation, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &impersonation, nil
+}
+
+// Update saves an impersonation record
+func (r *impersonationRepository) Update(impersonation *domain.Impersonation) error {
+	return r.db.Save(impersonation).Error
+}
+
+// List retrieves impersonations (newest first) with pagination
+func (r *impersonationRepository) List(adminID, userID uint, page, limit int) ([]*domain.Impersonation, int64, error) {
+	var impersonations []*domain.Impersonation
+	var total int64
+
+	query := r.db.Model(&domain.Impersonation{})
+	if adminID != 0 {
+		query = query.Where("admin_id = ?", adminID)
+	}
+	if userID != 0 {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&impersonations).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return impersonations, total, nil
+}
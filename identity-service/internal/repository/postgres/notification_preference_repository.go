@@ -0,0 +1,34 @@
+package postgres
+
+import (
+	"identity-service/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// notificationPreferenceRepository implements the NotificationPreferenceRepository interface
+type notificationPreferenceRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationPreferenceRepository creates a new PostgreSQL notification preference repository
+func NewNotificationPreferenceRepository(db *gorm.DB) domain.NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{db: db}
+}
+
+// ListByUser retrieves the explicit preferences of a user
+func (r *notificationPreferenceRepository) ListByUser(userID uint) ([]*domain.NotificationPreference, error) {
+	var prefs []*domain.NotificationPreference
+	if err := r.db.Where("user_id = ?", userID).Find(&prefs).Error; err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+// Upsert creates or updates the preference of a user for one category and channel
+func (r *notificationPreferenceRepository) Upsert(pref *domain.NotificationPreference) error {
+	return r.db.
+		Where("user_id = ? AND category = ? AND channel = ?", pref.UserID, pref.Category, pref.Channel).
+		Assign(domain.NotificationPreference{Enabled: pref.Enabled}).
+		FirstOrCreate(pref).Error
+}
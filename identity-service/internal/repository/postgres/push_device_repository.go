@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"identity-service/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// pushDeviceRepository implements the PushDeviceRepository interface
+type pushDeviceRepository struct {
+	db *gorm.DB
+}
+
+// NewPushDeviceRepository creates a new PostgreSQL push device repository
+func NewPushDeviceRepository(db *gorm.DB) domain.PushDeviceRepository {
+	return &pushDeviceRepository{db: db}
+}
+
+// Register creates or updates the device of a user; the token is first removed from
+// any other device so it is never delivered to two users
+func (r *pushDeviceRepository) Register(device *domain.PushDevice) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("token = ? AND NOT (user_id = ? AND device_id = ?)", device.Token, device.UserID, device.DeviceID).
+			Delete(&domain.PushDevice{}).Error; err != nil {
+			return err
+		}
+		return tx.
+			Where("user_id = ? AND device_id = ?", device.UserID, device.DeviceID).
+			Assign(map[string]interface{}{ // Map, so the failure count reset (zero) is applied too
+				"platform":      device.Platform,
+				"token":         device.Token,
+				"app_version":   device.AppVersion,
+				"failure_count": 0,
+				"last_seen_at":  device.LastSeenAt,
+			}).
+			FirstOrCreate(device).Error
+	})
+}
+
+// ListByUser retrieves the devices of a user, most recently seen first
+func (r *pushDeviceRepository) ListByUser(userID uint) ([]*domain.PushDevice, error) {
+	var devices []*domain.PushDevice
+	if err := r.db.Where("user_id = ?", userID).Order("last_seen_at DESC").Find(&devices).Error; err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+// PruneOldest deletes the least recently seen devices of a user beyond keep
+func (r *pushDeviceRepository) PruneOldest(userID uint, keep int) (int64, error) {
+	keepIDs := r.db.Model(&domain.PushDevice{}).
+		Select("id").
+		Where("user_id = ?", userID).
+		Order("last_seen_at DESC").
+		Limit(keep)
+	result := r.db.Where("user_id = ? AND id NOT IN (?)", userID, keepIDs).Delete(&domain.PushDevice{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteByDevice deletes one device of a user
+func (r *pushDeviceRepository) DeleteByDevice(userID uint, deviceID string) (int64, error) {
+	result := r.db.Where("user_id = ? AND device_id = ?", userID, deviceID).Delete(&domain.PushDevice{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteByTokens deletes the devices holding the given tokens
+func (r *pushDeviceRepository) DeleteByTokens(tokens []string) (int64, error) {
+	result := r.db.Where("token IN ?", tokens).Delete(&domain.PushDevice{})
+	return result.RowsAffected, result.Error
+}
+
+// RecordFailures increments the failure count of the given tokens and deletes the
+// ones that reached maxFailures
+func (r *pushDeviceRepository) RecordFailures(tokens []string, maxFailures int) (int64, error) {
+	var pruned int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.PushDevice{}).
+			Where("token IN ?", tokens).
+			Update("failure_count", gorm.Expr("failure_count + 1")).Error; err != nil {
+			return err
+		}
+		result := tx.Where("token IN ? AND failure_count >= ?", tokens, maxFailures).Delete(&domain.PushDevice{})
+		pruned = result.RowsAffected
+		return result.Error
+	})
+	return pruned, err
+}
+
+// ResetFailures clears the failure count of tokens that were delivered to
+func (r *pushDeviceRepository) ResetFailures(tokens []string) error {
+	return r.db.Model(&domain.PushDevice{}).
+		Where("token IN ? AND failure_count > 0", tokens).
+		Update("failure_count", 0).Error
+}
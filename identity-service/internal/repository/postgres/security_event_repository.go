@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"identity-service/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// securityEventRepository implements the SecurityEventRepository interface
+type securityEventRepository struct {
+	db *gorm.DB
+}
+
+// NewSecurityEventRepository creates a new PostgreSQL security event repository
+func NewSecurityEventRepository(db *gorm.DB) domain.SecurityEventRepository {
+	return &securityEventRepository{db: db}
+}
+
+// Create inserts a new security event
+func (r *securityEventRepository) Create(event *domain.SecurityEvent) error {
+	return r.db.Create(event).Error
+}
+
+// List retrieves security events (newest first) with pagination
+func (r *securityEventRepository) List(eventType string, page, limit int) ([]*domain.SecurityEvent, int64, error) {
+	var events []*domain.SecurityEvent
+	var total int64
+
+	query := r.db.Model(&domain.SecurityEvent{})
+	if eventType != "" {
+		query = query.Where("type = ?", eventType)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
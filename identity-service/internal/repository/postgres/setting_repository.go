@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"identity-service/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// settingRepository implements the SettingRepository interface
+// This is the infrastructure layer - it knows HOW to interact with PostgreSQL
+type settingRepository struct {
+	db *gorm.DB
+}
+
+// NewSettingRepository creates a new PostgreSQL setting repository
+func NewSettingRepository(db *gorm.DB) domain.SettingRepository {
+	return &settingRepository{db: db}
+}
+
+// Create inserts a new setting
+func (r *settingRepository) Create(setting *domain.Setting) error {
+	return r.db.Create(setting).Error
+}
+
+// Update updates an existing setting
+func (r *settingRepository) Update(setting *domain.Setting) error {
+	return r.db.Save(setting).Error
+}
+
+// Get retrieves a setting by scope and key
+func (r *settingRepository) Get(scope, key string) (*domain.Setting, error) {
+	var setting domain.Setting
+	err := r.db.Where("scope = ? AND key = ?", scope, key).First(&setting).Error
+	if err != nil {
+		return nil, err
+	}
+	return &setting, nil
+}
+
+// List retrieves settings, optionally filtered by scope
+func (r *settingRepository) List(scope string) ([]*domain.Setting, error) {
+	var settings []*domain.Setting
+	query := r.db.Model(&domain.Setting{})
+	if scope != "" {
+		query = query.Where("scope = ?", scope)
+	}
+	if err := query.Order("scope, key").Find(&settings).Error; err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// settingAuditRepository implements the SettingAuditRepository interface
+type settingAuditRepository struct {
+	db *gorm.DB
+}
+
+// NewSettingAuditRepository creates a new PostgreSQL setting audit repository
+func NewSettingAuditRepository(db *gorm.DB) domain.SettingAuditRepository {
+	return &settingAuditRepository{db: db}
+}
+
+// Create inserts a new audit record
+func (r *settingAuditRepository) Create(audit *domain.SettingAudit) error {
+	return r.db.Create(audit).Error
+}
+
+// ListBySetting retrieves audit records of a setting (newest first) with pagination
+func (r *settingAuditRepository) ListBySetting(settingID uint, page, limit int) ([]*domain.SettingAudit, int64, error) {
+	var audits []*domain.SettingAudit
+	var total int64
+
+	offset := (page - 1) * limit
+
+	// Count total
+	if err := r.db.Model(&domain.SettingAudit{}).Where("setting_id = ?", settingID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Get paginated results
+	if err := r.db.Where("setting_id = ?", settingID).Order("id DESC").
+		Offset(offset).Limit(limit).Find(&audits).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return audits, total, nil
+}
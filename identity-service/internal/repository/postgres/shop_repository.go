@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"identity-service/internal/domain"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -48,6 +49,38 @@ func (r *shopRepository) GetByOwnerUserID(ownerUserID uint) (*domain.Shop, error
 	return &shop, nil
 }
 
+// GetBySlug retrieves a shop by its current slug
+func (r *shopRepository) GetBySlug(slug string) (*domain.Shop, error) {
+	var shop domain.Shop
+	err := r.db.Where("slug = ?", slug).First(&shop).Error
+	if err != nil {
+		return nil, err
+	}
+	return &shop, nil
+}
+
+// ExistsBySlug checks if a slug is already used by another shop
+func (r *shopRepository) ExistsBySlug(slug string, excludeID uint) (bool, error) {
+	var count int64
+	query := r.db.Model(&domain.Shop{}).Where("slug = ?", slug)
+	if excludeID > 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetWithoutSlug retrieves shops created before slugs existed
+func (r *shopRepository) GetWithoutSlug() ([]*domain.Shop, error) {
+	var shops []*domain.Shop
+	if err := r.db.Where("slug IS NULL OR slug = ''").Find(&shops).Error; err != nil {
+		return nil, err
+	}
+	return shops, nil
+}
+
 // GetAll retrieves all shops with pagination
 func (r *shopRepository) GetAll(page, limit int) ([]*domain.Shop, int64, error) {
 	var shops []*domain.Shop
@@ -88,6 +121,30 @@ func (r *shopRepository) GetByStatus(status string, page, limit int) ([]*domain.
 	return shops, total, nil
 }
 
+// likeEscaper escapes the LIKE wildcards of user input
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Search retrieves ACTIVE shops whose name contains name (any case) or whose slug contains
+// slug (the name without diacritics), official and best rated shops first
+func (r *shopRepository) Search(name, slug string, offset, limit int) ([]*domain.Shop, int64, error) {
+	var shops []*domain.Shop
+	var total int64
+
+	query := r.db.Model(&domain.Shop{}).
+		Where("status = ?", "ACTIVE").
+		Where("name ILIKE ? OR slug LIKE ?", "%"+likeEscaper.Replace(name)+"%", "%"+likeEscaper.Replace(slug)+"%")
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Order("is_official DESC, rating DESC, id").Offset(offset).Limit(limit).Find(&shops).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return shops, total, nil
+}
+
 // Delete soft deletes a shop (sets status to SUSPENDED)
 func (r *shopRepository) Delete(id uint) error {
 	return r.db.Model(&domain.Shop{}).Where("id = ?", id).Update("status", "SUSPENDED").Error
@@ -98,3 +155,38 @@ func (r *shopRepository) UpdateStatus(id uint, status string) error {
 	return r.db.Model(&domain.Shop{}).Where("id = ?", id).Update("status", status).Error
 }
 
+
+// ListForExport retrieves the next batch of shops matching the filter, ordered by ID
+func (r *shopRepository) ListForExport(filter domain.ShopExportFilter, afterID uint, limit int) ([]*domain.Shop, error) {
+	query := r.db.Where("id > ?", afterID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Province != "" {
+		query = query.Where("province = ?", filter.Province)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		query = query.Where("created_at >= ?", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		query = query.Where("created_at < ?", filter.CreatedTo)
+	}
+
+	var shops []*domain.Shop
+	if err := query.Order("id").Limit(limit).Find(&shops).Error; err != nil {
+		return nil, err
+	}
+	return shops, nil
+}
+
+// OwnerUserIDs returns the owners of the ACTIVE shops among shopIDs, ordered by user ID
+func (r *shopRepository) OwnerUserIDs(shopIDs []uint) ([]uint, error) {
+	var ownerIDs []uint
+	if len(shopIDs) == 0 {
+		return ownerIDs, nil
+	}
+	err := r.db.Model(&domain.Shop{}).
+		Where("id IN ? AND status = ?", shopIDs, "ACTIVE").
+		Order("owner_user_id").Pluck("owner_user_id", &ownerIDs).Error
+	return ownerIDs, err
+}
@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"identity-service/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// shopSlugHistoryRepository implements the ShopSlugHistoryRepository interface
+type shopSlugHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewShopSlugHistoryRepository creates a new PostgreSQL shop slug history repository
+func NewShopSlugHistoryRepository(db *gorm.DB) domain.ShopSlugHistoryRepository {
+	return &shopSlugHistoryRepository{db: db}
+}
+
+// Create inserts a new slug history record
+func (r *shopSlugHistoryRepository) Create(history *domain.ShopSlugHistory) error {
+	return r.db.Create(history).Error
+}
+
+// GetBySlug retrieves a slug history record by old slug
+func (r *shopSlugHistoryRepository) GetBySlug(slug string) (*domain.ShopSlugHistory, error) {
+	var history domain.ShopSlugHistory
+	err := r.db.Where("slug = ?", slug).First(&history).Error
+	if err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// DeleteBySlug removes a slug history record (used when a shop reclaims an old slug)
+func (r *shopSlugHistoryRepository) DeleteBySlug(slug string) error {
+	return r.db.Where("slug = ?", slug).Delete(&domain.ShopSlugHistory{}).Error
+}
@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"identity-service/internal/domain"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -63,4 +64,47 @@ func (r *userRepository) Delete(id uint) error {
 	return r.db.Model(&domain.User{}).Where("id = ?", id).Update("status", "DELETED").Error
 }
 
+// ListForExport retrieves the next batch of users matching the filter, ordered by ID
+// Keyset pagination keeps every batch an index range scan however deep the export goes
+func (r *userRepository) ListForExport(filter domain.UserExportFilter, afterID uint, limit int) ([]*domain.User, error) {
+	query := r.db.Where("id > ?", afterID)
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		query = query.Where("created_at >= ?", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		query = query.Where("created_at < ?", filter.CreatedTo)
+	}
+
+	var users []*domain.User
+	if err := query.Order("id").Limit(limit).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// ListAudience retrieves the IDs of the next batch of ACTIVE users matching the filter, ordered by ID
+func (r *userRepository) ListAudience(filter domain.UserAudienceFilter, afterID uint, limit int) ([]uint, error) {
+	query := r.db.Model(&domain.User{}).Where("id > ? AND status = ? AND role IN ?", afterID, "ACTIVE", filter.Roles)
+	if !filter.InactiveSince.IsZero() {
+		query = query.Where("COALESCE(last_login_at, created_at) < ?", filter.InactiveSince)
+	}
+
+	var ids []uint
+	if err := query.Order("id").Limit(limit).Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// UpdateLastLogin records a login without touching the other columns
+func (r *userRepository) UpdateLastLogin(id uint, at time.Time) error {
+	return r.db.Model(&domain.User{}).Where("id = ?", id).UpdateColumn("last_login_at", at).Error
+}
+
 
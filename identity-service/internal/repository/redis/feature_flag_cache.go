@@ -0,0 +1,53 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"identity-service/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis key patterns for feature flags (shared contract with other services)
+const (
+	featureFlagsKey            = "feature_flags"         // Hash {flag_key: FeatureFlag JSON}
+	FeatureFlagsChangedChannel = "feature_flags:changed" // Pub/Sub channel, payload = flag key
+)
+
+// FeatureFlagRedisCache implements domain.FeatureFlagCache
+type FeatureFlagRedisCache struct {
+	client *redis.Client
+	ctx    context.Context
+}
+
+// NewFeatureFlagRedisCache creates a new Redis feature flag cache
+func NewFeatureFlagRedisCache(client *redis.Client) *FeatureFlagRedisCache {
+	return &FeatureFlagRedisCache{
+		client: client,
+		ctx:    context.Background(),
+	}
+}
+
+// Set writes a flag into the flags hash
+func (r *FeatureFlagRedisCache) Set(flag *domain.FeatureFlag) error {
+	data, err := json.Marshal(flag)
+	if err != nil {
+		return fmt.Errorf("failed to marshal feature flag: %w", err)
+	}
+	if err := r.client.HSet(r.ctx, featureFlagsKey, flag.Key, data).Err(); err != nil {
+		return fmt.Errorf("failed to cache feature flag: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a flag from the flags hash
+func (r *FeatureFlagRedisCache) Delete(key string) error {
+	return r.client.HDel(r.ctx, featureFlagsKey, key).Err()
+}
+
+// PublishChanged notifies services that a flag changed (they reload it from the hash)
+func (r *FeatureFlagRedisCache) PublishChanged(key string) error {
+	return r.client.Publish(r.ctx, FeatureFlagsChangedChannel, key).Err()
+}
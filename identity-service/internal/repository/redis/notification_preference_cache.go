@@ -0,0 +1,49 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"identity-service/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis key pattern for notification preferences (shared contract with services sending notifications)
+// notification_preferences:{user_id} -> Hash {"{category}:{channel}": "1" | "0"}
+const notificationPreferencesKeyPrefix = "notification_preferences:"
+
+// NotificationPreferenceRedisCache implements domain.NotificationPreferenceCache
+type NotificationPreferenceRedisCache struct {
+	client *redis.Client
+	ctx    context.Context
+}
+
+// NewNotificationPreferenceRedisCache creates a new Redis notification preference cache
+func NewNotificationPreferenceRedisCache(client *redis.Client) *NotificationPreferenceRedisCache {
+	return &NotificationPreferenceRedisCache{
+		client: client,
+		ctx:    context.Background(),
+	}
+}
+
+// Set writes the full preference matrix of a user (no TTL - Postgres is source of truth,
+// the hash is rewritten on every change; a missing hash means defaults)
+func (r *NotificationPreferenceRedisCache) Set(userID uint, prefs domain.NotificationPreferenceMatrix) error {
+	values := make(map[string]interface{})
+	for category, channels := range prefs {
+		for channel, enabled := range channels {
+			value := "0"
+			if enabled {
+				value = "1"
+			}
+			values[category+":"+channel] = value
+		}
+	}
+
+	key := fmt.Sprintf("%s%d", notificationPreferencesKeyPrefix, userID)
+	if err := r.client.HSet(r.ctx, key, values).Err(); err != nil {
+		return fmt.Errorf("failed to cache notification preferences: %w", err)
+	}
+	return nil
+}
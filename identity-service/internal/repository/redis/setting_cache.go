@@ -0,0 +1,53 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"identity-service/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis key patterns for settings (shared contract with other services)
+const (
+	settingsKeyPrefix      = "settings:"        // settings:{scope} -> Hash {key: value}
+	SettingsChangedChannel = "settings:changed" // Pub/Sub channel for SettingChangedEvent
+)
+
+// SettingRedisCache implements domain.SettingCache
+// Settings are stored as one hash per scope so services can HGET a single value
+type SettingRedisCache struct {
+	client *redis.Client
+	ctx    context.Context
+}
+
+// NewSettingRedisCache creates a new Redis setting cache
+func NewSettingRedisCache(client *redis.Client) *SettingRedisCache {
+	return &SettingRedisCache{
+		client: client,
+		ctx:    context.Background(),
+	}
+}
+
+// Set writes a setting value into its scope hash (no TTL - Postgres is source of truth, cache is rewritten on change)
+func (r *SettingRedisCache) Set(setting *domain.Setting) error {
+	key := fmt.Sprintf("%s%s", settingsKeyPrefix, setting.Scope)
+	if err := r.client.HSet(r.ctx, key, setting.Key, setting.Value).Err(); err != nil {
+		return fmt.Errorf("failed to cache setting: %w", err)
+	}
+	return nil
+}
+
+// Publish broadcasts a setting change to all subscribed services
+func (r *SettingRedisCache) Publish(event *domain.SettingChangedEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal setting event: %w", err)
+	}
+	if err := r.client.Publish(r.ctx, SettingsChangedChannel, data).Err(); err != nil {
+		return fmt.Errorf("failed to publish setting event: %w", err)
+	}
+	return nil
+}
@@ -2,26 +2,74 @@ package router
 
 import (
 	"identity-service/internal/handler"
+	"identity-service/pkg/requestid"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// RequestID middleware keeps the request ID (X-Request-Id, set by API Gateway) in the request
+// context for logs and query metrics; requests that bypassed the gateway get a new one
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if id == "" {
+			id = requestid.New()
+		}
+		c.Request = c.Request.WithContext(requestid.NewContext(c.Request.Context(), id))
+		c.Header(requestid.Header, id)
+		c.Next()
+	}
+}
+
 // SetupRouter configures all API routes
 // NOTE: CORS is handled by API Gateway, not here
 func SetupRouter(
 	authHandler *handler.AuthHandler,
 	userHandler *handler.UserHandler,
+	notificationPreferenceHandler *handler.NotificationPreferenceHandler,
+	pushDeviceHandler *handler.PushDeviceHandler,
 	addressHandler *handler.AddressHandler,
 	shopHandler *handler.ShopHandler,
+	settingHandler *handler.SettingHandler,
+	featureFlagHandler *handler.FeatureFlagHandler,
+	emailTemplateHandler *handler.EmailTemplateHandler,
+	securityEventHandler *handler.SecurityEventHandler,
+	introspectionHandler *handler.IntrospectionHandler,
+	impersonationHandler *handler.ImpersonationHandler,
+	exportHandler *handler.ExportHandler,
+	audienceHandler *handler.AudienceHandler,
+	jobHandler *handler.JobHandler,
+	logLevelHandler *handler.LogLevelHandler,
+	recovery gin.HandlerFunc,
+	faults gin.HandlerFunc,
 	authMiddleware gin.HandlerFunc,
+	adminMiddleware gin.HandlerFunc,
+	refreshLimit gin.HandlerFunc,
+	introspectionClient gin.HandlerFunc,
+	pushService gin.HandlerFunc,
+	announcementService gin.HandlerFunc,
 ) *gin.Engine {
-	router := gin.Default()
+	router := gin.New()
+
+	// Panic recovery first so it also covers the other middleware (replaces gin.Recovery)
+	router.Use(recovery, gin.Logger())
+
+	// Request ID for logs and query metrics
+	router.Use(RequestID())
+
+	// Opt-in latency/error injection for resilience testing (no-op unless enabled)
+	router.Use(faults)
 
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
+	// Prometheus metrics (OpenMetrics format carries the request ID exemplars)
+	router.GET("/metrics", gin.WrapH(promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{EnableOpenMetrics: true})))
+
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 	{
@@ -30,8 +78,28 @@ func SetupRouter(
 		{
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
-			auth.POST("/refresh", authHandler.RefreshToken) // Refresh access token
-			auth.POST("/logout", authHandler.Logout)        // Logout (will need middleware for user_id)
+			auth.POST("/refresh", refreshLimit, authHandler.RefreshToken) // Refresh access token (brute-force limited)
+			auth.POST("/logout", authHandler.Logout)                      // Logout (will need middleware for user_id)
+
+			// Token introspection for internal services (client credentials, not exposed by the gateway)
+			auth.POST("/introspect", introspectionClient, introspectionHandler.Introspect)
+		}
+
+		// Internal: push channel of the notification service (signed service calls, not exposed by the gateway)
+		// Closed until a notification_service client is added to internal_auth.trusted_services
+		internalPush := v1.Group("/internal/push")
+		internalPush.Use(pushService)
+		{
+			internalPush.GET("/users/:user_id/targets", pushDeviceHandler.GetTargets)
+			internalPush.POST("/delivery-reports", pushDeviceHandler.ReportDelivery)
+		}
+
+		// Internal: recipients of admin announcements broadcast by order-service (signed service calls, not exposed by the gateway)
+		internalAudience := v1.Group("/internal/audience")
+		internalAudience.Use(announcementService)
+		{
+			internalAudience.GET("/users", audienceHandler.ListUsers)
+			internalAudience.POST("/shop-owners", audienceHandler.ShopOwners)
 		}
 
 		// Protected routes (authentication required)
@@ -44,6 +112,15 @@ func SetupRouter(
 				users.GET("/profile", userHandler.GetProfile)
 				users.PUT("/profile", userHandler.UpdateProfile)
 				users.PUT("/password", userHandler.ChangePassword)
+
+				// Notification preferences (category x channel)
+				users.GET("/me/notification-preferences", notificationPreferenceHandler.GetPreferences)
+				users.PUT("/me/notification-preferences", notificationPreferenceHandler.UpdatePreferences)
+
+				// Push notification devices (FCM/APNs tokens)
+				users.GET("/me/devices", pushDeviceHandler.ListDevices)
+				users.POST("/me/devices", pushDeviceHandler.RegisterDevice)
+				users.DELETE("/me/devices/:device_id", pushDeviceHandler.UnregisterDevice)
 			}
 
 			// Address routes
@@ -56,14 +133,19 @@ func SetupRouter(
 				addresses.DELETE("/:id", addressHandler.DeleteAddress)
 				addresses.PUT("/:id/default", addressHandler.SetDefaultAddress)
 			}
+
+			// Feature flags evaluated for current user
+			protected.GET("/feature-flags", featureFlagHandler.EvaluateFlags)
 		}
 
 		// Shop routes
 		shops := v1.Group("/shops")
 		{
 			// Public routes
-			shops.GET("", shopHandler.ListShops)   // List all shops
-			shops.GET("/:id", shopHandler.GetShop) // Get shop by ID
+			shops.GET("", shopHandler.ListShops)                // List all shops
+			shops.GET("/slug/:slug", shopHandler.GetShopBySlug) // Get shop by slug (must be before /:id)
+			shops.GET("/search", shopHandler.SearchShops)       // Search shops by name (must be before /:id)
+			shops.GET("/:id", shopHandler.GetShop)              // Get shop by ID
 		}
 
 		// Protected shop routes
@@ -76,6 +158,54 @@ func SetupRouter(
 			protectedShops.DELETE("/:id", shopHandler.DeleteShop)           // Delete shop (ADMIN only)
 			protectedShops.PUT("/:id/status", shopHandler.UpdateShopStatus) // Update status (ADMIN only)
 		}
+
+		// Admin routes (ADMIN role required)
+		admin := v1.Group("/admin")
+		admin.Use(authMiddleware, adminMiddleware)
+		{
+			// Global configuration (settings)
+			admin.GET("/settings", settingHandler.ListSettings)
+			admin.GET("/settings/:scope/:key", settingHandler.GetSetting)
+			admin.PUT("/settings/:scope/:key", settingHandler.UpdateSetting)
+			admin.GET("/settings/:scope/:key/audit", settingHandler.GetSettingAudit)
+
+			// Feature flags
+			admin.GET("/feature-flags", featureFlagHandler.ListFlags)
+			admin.GET("/feature-flags/:key", featureFlagHandler.GetFlag)
+			admin.PUT("/feature-flags/:key", featureFlagHandler.UpsertFlag)
+			admin.DELETE("/feature-flags/:key", featureFlagHandler.DeleteFlag)
+
+			// Transactional email templates (versioned, per locale)
+			admin.GET("/email-templates", emailTemplateHandler.ListTemplates)
+			admin.POST("/email-templates/preview", emailTemplateHandler.PreviewTemplate)
+			admin.GET("/email-templates/:key/:locale", emailTemplateHandler.GetTemplate)
+			admin.PUT("/email-templates/:key/:locale", emailTemplateHandler.SaveTemplate)
+			admin.GET("/email-templates/:key/:locale/versions", emailTemplateHandler.ListVersions)
+			admin.POST("/email-templates/:key/:locale/versions/:version/activate", emailTemplateHandler.ActivateVersion)
+
+			// Security audit events (brute-force limit breaches)
+			admin.GET("/security-events", securityEventHandler.ListEvents)
+
+			// Impersonation of users for support cases (audited, revocable)
+			admin.POST("/impersonations", impersonationHandler.StartImpersonation)
+			admin.GET("/impersonations", impersonationHandler.ListImpersonations)
+			admin.DELETE("/impersonations/:id", impersonationHandler.RevokeImpersonation)
+
+			// CSV exports for operations and marketing (streamed)
+			admin.GET("/users/export", exportHandler.ExportUsers)
+			admin.GET("/shops/export", exportHandler.ExportShops)
+
+			// Background jobs (namespaced per service behind the gateway)
+			admin.GET("/jobs/identity", jobHandler.GetStats)
+			admin.GET("/jobs/identity/list", jobHandler.ListJobs)
+			admin.GET("/jobs/identity/:id", jobHandler.GetJob)
+			admin.POST("/jobs/identity/:id/retry", jobHandler.RetryJob)
+			admin.DELETE("/jobs/identity/:id", jobHandler.DeleteJob)
+
+			// Runtime log level
+			admin.GET("/log-level/identity", logLevelHandler.GetLogLevel)
+			admin.PUT("/log-level/identity", logLevelHandler.SetLogLevel)
+		}
 	}
 
 	return router
@@ -1,7 +1,6 @@
 package service
 
 import (
-	"errors"
 	"fmt"
 	"identity-service/internal/domain"
 
@@ -28,7 +27,7 @@ func NewAddressService(
 // CreateAddressRequest represents the request to create an address
 type CreateAddressRequest struct {
 	RecipientName string `json:"recipient_name" binding:"required"`
-	PhoneNumber   string `json:"phone_number" binding:"required"`
+	PhoneNumber   string `json:"phone_number" binding:"required,vnphone"`
 	AddressLine   string `json:"address_line" binding:"required"`
 	City          string `json:"city" binding:"required"`
 	District      string `json:"district" binding:"required"`
@@ -78,7 +77,7 @@ func (s *AddressService) CreateAddress(userID uint, req *CreateAddressRequest) (
 // UpdateAddressRequest represents the request to update an address
 type UpdateAddressRequest struct {
 	RecipientName string `json:"recipient_name"`
-	PhoneNumber   string `json:"phone_number"`
+	PhoneNumber   string `json:"phone_number" binding:"omitempty,vnphone"`
 	AddressLine   string `json:"address_line"`
 	City          string `json:"city"`
 	District      string `json:"district"`
@@ -92,12 +91,12 @@ func (s *AddressService) UpdateAddress(userID uint, addressID uint, req *UpdateA
 	// Get address
 	address, err := s.addressRepo.GetByID(addressID)
 	if err != nil {
-		return nil, errors.New("address not found")
+		return nil, domain.NotFound("address not found")
 	}
 
 	// Verify ownership
 	if address.UserID != userID {
-		return nil, errors.New("unauthorized")
+		return nil, domain.Forbidden("unauthorized")
 	}
 
 	// Update fields
@@ -156,12 +155,12 @@ func (s *AddressService) GetAddresses(userID uint) ([]*domain.Address, error) {
 func (s *AddressService) GetAddress(userID uint, addressID uint) (*domain.Address, error) {
 	address, err := s.addressRepo.GetByID(addressID)
 	if err != nil {
-		return nil, errors.New("address not found")
+		return nil, domain.NotFound("address not found")
 	}
 
 	// Verify ownership
 	if address.UserID != userID {
-		return nil, errors.New("unauthorized")
+		return nil, domain.Forbidden("unauthorized")
 	}
 
 	return address, nil
@@ -172,12 +171,12 @@ func (s *AddressService) DeleteAddress(userID uint, addressID uint) error {
 	// Get address to verify ownership
 	address, err := s.addressRepo.GetByID(addressID)
 	if err != nil {
-		return errors.New("address not found")
+		return domain.NotFound("address not found")
 	}
 
 	// Verify ownership
 	if address.UserID != userID {
-		return errors.New("unauthorized")
+		return domain.Forbidden("unauthorized")
 	}
 
 	// Delete address
@@ -195,11 +194,11 @@ func (s *AddressService) SetDefaultAddress(userID uint, addressID uint) error {
 	// Verify ownership
 	address, err := s.addressRepo.GetByID(addressID)
 	if err != nil {
-		return errors.New("address not found")
+		return domain.NotFound("address not found")
 	}
 
 	if address.UserID != userID {
-		return errors.New("unauthorized")
+		return domain.Forbidden("unauthorized")
 	}
 
 	// Set as default
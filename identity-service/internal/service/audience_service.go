@@ -0,0 +1,83 @@
+package service
+
+import (
+	"fmt"
+	"identity-service/internal/domain"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Audience segments of admin announcements resolved here (order-service sends them; the
+// sellers of a category are resolved from the shops selling in it, see ShopOwners)
+const (
+	AudienceBuyers        = "buyers"         // Every active buyer
+	AudienceInactiveUsers = "inactive_users" // Active buyers and sellers without a login for inactive_days
+)
+
+const (
+	defaultAudienceBatch = 500
+	maxAudienceBatch     = 1000
+	maxInactiveDays      = 3650
+	maxAudienceShops     = 50000
+)
+
+// AudienceService resolves the recipients of the announcements order-service broadcasts
+type AudienceService struct {
+	userRepo domain.UserRepository
+	shopRepo domain.ShopRepository
+	logger   *zap.Logger
+}
+
+// NewAudienceService creates a new audience service
+func NewAudienceService(userRepo domain.UserRepository, shopRepo domain.ShopRepository, logger *zap.Logger) *AudienceService {
+	return &AudienceService{
+		userRepo: userRepo,
+		shopRepo: shopRepo,
+		logger:   logger,
+	}
+}
+
+// Users returns the IDs of the next batch of users of the segment after afterID, ordered by ID
+// (an empty batch ends the audience)
+func (s *AudienceService) Users(segment string, inactiveDays int, afterID uint, limit int) ([]uint, error) {
+	if limit < 1 {
+		limit = defaultAudienceBatch
+	}
+	if limit > maxAudienceBatch {
+		limit = maxAudienceBatch
+	}
+
+	var filter domain.UserAudienceFilter
+	switch segment {
+	case AudienceBuyers:
+		filter.Roles = []string{"BUYER"}
+	case AudienceInactiveUsers:
+		if inactiveDays < 1 || inactiveDays > maxInactiveDays {
+			return nil, domain.Validation("inactive_days must be between 1 and %d", maxInactiveDays)
+		}
+		filter.Roles = []string{"BUYER", "SELLER"}
+		filter.InactiveSince = time.Now().AddDate(0, 0, -inactiveDays)
+	default:
+		return nil, domain.Validation("segment must be %s or %s", AudienceBuyers, AudienceInactiveUsers)
+	}
+
+	ids, err := s.userRepo.ListAudience(filter, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audience: %w", err)
+	}
+	return ids, nil
+}
+
+// ShopOwners returns the IDs of the users owning the active shops among shopIDs
+func (s *AudienceService) ShopOwners(shopIDs []uint) ([]uint, error) {
+	if len(shopIDs) > maxAudienceShops {
+		return nil, domain.Validation("at most %d shop IDs", maxAudienceShops)
+	}
+
+	ids, err := s.shopRepo.OwnerUserIDs(shopIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shop owners: %w", err)
+	}
+	return ids, nil
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
@@ -21,15 +22,23 @@ type AuthService struct {
 	userRepo         domain.UserRepository
 	refreshTokenRepo domain.RefreshTokenRepository
 	sessionRepo      domain.SessionRepository
+	emails           EmailQueuer
 	logger           *zap.Logger
 	jwtSecret        string
 }
 
+// EmailQueuer queues transactional emails (implemented by EmailService)
+type EmailQueuer interface {
+	QueueEmail(ctx context.Context, to, subject, body string) error
+	QueueTemplateEmail(ctx context.Context, to, key, locale string, data map[string]interface{}) error
+}
+
 // NewAuthService creates a new auth service
 func NewAuthService(
 	userRepo domain.UserRepository,
 	refreshTokenRepo domain.RefreshTokenRepository,
 	sessionRepo domain.SessionRepository,
+	emails EmailQueuer,
 	logger *zap.Logger,
 	jwtSecret string,
 ) *AuthService {
@@ -37,6 +46,7 @@ func NewAuthService(
 		userRepo:         userRepo,
 		refreshTokenRepo: refreshTokenRepo,
 		sessionRepo:      sessionRepo,
+		emails:           emails,
 		logger:           logger,
 		jwtSecret:        jwtSecret,
 	}
@@ -48,7 +58,8 @@ type RegisterRequest struct {
 	Email       string `json:"email" binding:"required,email"`
 	Password    string `json:"password" binding:"required,min=6"`
 	FullName    string `json:"full_name" binding:"required"`
-	PhoneNumber string `json:"phone_number"`
+	PhoneNumber string `json:"phone_number" binding:"omitempty,vnphone"`
+	Locale      string `json:"locale"` // Language of transactional emails, e.g. "vi" (default "en")
 }
 
 // LoginRequest represents the request to login
@@ -73,13 +84,13 @@ func (s *AuthService) Register(req *RegisterRequest) (*AuthResponse, error) {
 	// Check if email already exists
 	existing, _ := s.userRepo.GetByEmail(req.Email)
 	if existing != nil {
-		return nil, errors.New("email already exists")
+		return nil, domain.Conflict("email already exists")
 	}
 
 	// Check if username already exists
 	existing, _ = s.userRepo.GetByUsername(req.Username)
 	if existing != nil {
-		return nil, errors.New("username already exists")
+		return nil, domain.Conflict("username already exists")
 	}
 
 	// Hash password
@@ -107,8 +118,17 @@ func (s *AuthService) Register(req *RegisterRequest) (*AuthResponse, error) {
 
 	s.logger.Info("user registered", zap.Uint("user_id", user.ID), zap.String("email", user.Email))
 
+	// Welcome email is sent by a background job (registration must not fail on SMTP errors)
+	if err := s.emails.QueueTemplateEmail(context.Background(), user.Email, domain.EmailTemplateWelcome, req.Locale,
+		map[string]interface{}{"FullName": user.FullName, "Email": user.Email}); err != nil {
+		s.logger.Warn("failed to queue welcome email", zap.Uint("user_id", user.ID), zap.Error(err))
+	}
+
+	// Session ID is bound into the access token (sid claim) so revoking the session revokes the token
+	sessionID := uuid.New().String()
+
 	// Generate Access Token (short-lived: 15 minutes)
-	accessToken, err := s.generateAccessToken(user)
+	accessToken, err := s.generateAccessToken(user, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
@@ -121,7 +141,7 @@ func (s *AuthService) Register(req *RegisterRequest) (*AuthResponse, error) {
 
 	// Create session in Redis with refresh token hash
 	session := &domain.Session{
-		ID:               uuid.New().String(),
+		ID:               sessionID,
 		UserID:           int64(user.ID),
 		RefreshTokenHash: hashToken(refreshToken),
 		IsRevoked:        false,
@@ -165,8 +185,16 @@ func (s *AuthService) Login(req *LoginRequest) (*AuthResponse, error) {
 
 	s.logger.Info("user logged in", zap.Uint("user_id", user.ID), zap.String("email", user.Email))
 
+	// Last login selects inactive users for announcements; a failure must not block the login
+	if err := s.userRepo.UpdateLastLogin(user.ID, time.Now()); err != nil {
+		s.logger.Warn("failed to record last login", zap.Uint("user_id", user.ID), zap.Error(err))
+	}
+
+	// Session ID is bound into the access token (sid claim) so revoking the session revokes the token
+	sessionID := uuid.New().String()
+
 	// Generate Access Token (short-lived: 15 minutes)
-	accessToken, err := s.generateAccessToken(user)
+	accessToken, err := s.generateAccessToken(user, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
@@ -179,7 +207,7 @@ func (s *AuthService) Login(req *LoginRequest) (*AuthResponse, error) {
 
 	// Create session in Redis with refresh token hash
 	session := &domain.Session{
-		ID:               uuid.New().String(),
+		ID:               sessionID,
 		UserID:           int64(user.ID),
 		RefreshTokenHash: hashToken(refreshToken),
 		IsRevoked:        false,
@@ -204,7 +232,8 @@ func (s *AuthService) Login(req *LoginRequest) (*AuthResponse, error) {
 }
 
 // generateAccessToken generates a short-lived JWT access token (15 minutes)
-func (s *AuthService) generateAccessToken(user *domain.User) (string, error) {
+// sessionID ("" for legacy refresh tokens) is stored as the sid claim
+func (s *AuthService) generateAccessToken(user *domain.User, sessionID string) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id": user.ID,
 		"email":   user.Email,
@@ -213,6 +242,9 @@ func (s *AuthService) generateAccessToken(user *domain.User) (string, error) {
 		"exp":     time.Now().Add(time.Minute * 15).Unix(), // 15 minutes
 		"iat":     time.Now().Unix(),
 	}
+	if sessionID != "" {
+		claims["sid"] = sessionID
+	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(s.jwtSecret))
@@ -246,6 +278,21 @@ func (s *AuthService) generateRefreshToken(user *domain.User) (string, error) {
 
 // ValidateToken validates a JWT token and returns the user ID
 func (s *AuthService) ValidateToken(tokenString string) (uint, string, error) {
+	claims, err := s.parseAccessToken(tokenString)
+	if err != nil {
+		return 0, "", err
+	}
+
+	userID, _ := claims["user_id"].(float64)
+	role, _ := claims["role"].(string)
+	if userID == 0 {
+		return 0, "", errors.New("invalid token")
+	}
+	return uint(userID), role, nil
+}
+
+// parseAccessToken verifies signature and expiry of a JWT access token and returns its claims
+func (s *AuthService) parseAccessToken(tokenString string) (jwt.MapClaims, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
@@ -254,16 +301,14 @@ func (s *AuthService) ValidateToken(tokenString string) (uint, string, error) {
 	})
 
 	if err != nil {
-		return 0, "", err
+		return nil, err
 	}
 
 	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		userID := uint(claims["user_id"].(float64))
-		role := claims["role"].(string)
-		return userID, role, nil
+		return claims, nil
 	}
 
-	return 0, "", errors.New("invalid token")
+	return nil, errors.New("invalid token")
 }
 
 // RefreshRequest represents the request to refresh access token
@@ -286,6 +331,12 @@ func (s *AuthService) RefreshAccessTokenBySession(sessionID string) (*AuthRespon
 		return nil, errors.New("session expired or revoked")
 	}
 
+	// Impersonation tokens are time-boxed and must not be extended
+	if session.DeviceType == domain.ImpersonationDeviceType {
+		s.logger.Warn("refresh of impersonation session rejected", zap.String("session_id", sessionID))
+		return nil, errors.New("impersonation sessions cannot be refreshed")
+	}
+
 	// Get user
 	user, err := s.userRepo.GetByID(uint(session.UserID))
 	if err != nil {
@@ -299,7 +350,7 @@ func (s *AuthService) RefreshAccessTokenBySession(sessionID string) (*AuthRespon
 	}
 
 	// Generate new access token
-	accessToken, err := s.generateAccessToken(user)
+	accessToken, err := s.generateAccessToken(user, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
@@ -348,7 +399,7 @@ func (s *AuthService) RefreshAccessToken(refreshTokenString string) (*AuthRespon
 	}
 
 	// Generate new access token
-	accessToken, err := s.generateAccessToken(user)
+	accessToken, err := s.generateAccessToken(user, "")
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
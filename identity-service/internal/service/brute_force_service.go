@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"identity-service/internal/domain"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Brute-force policies of sensitive auth endpoints (limits come from config brute_force.policies)
+const (
+	BruteForceRefresh = "refresh"
+)
+
+// BruteForcePolicy limits attempts of one client on one endpoint
+type BruteForcePolicy struct {
+	Limit        int           // Attempts allowed per sliding window
+	Window       time.Duration // Sliding window length
+	FreeAttempts int           // Attempts in the window answered without delay
+	BaseDelay    time.Duration // Delay of the first attempt over FreeAttempts, doubled for each further attempt
+	MaxDelay     time.Duration // Upper bound of the progressive delay
+}
+
+// ThrottleDecision is the outcome of an attempt check
+type ThrottleDecision struct {
+	Attempts   int           // Attempts in the window, including this one
+	Delay      time.Duration // Wait before handling the attempt
+	Blocked    bool          // Limit reached, reject the attempt
+	RetryAfter time.Duration // When blocked: until the oldest attempt leaves the window
+}
+
+// slidingWindowScript records an attempt in a sorted set (score = time in ms) unless the
+// limit is reached; returns {attempts, retry_after_ms} (retry_after_ms > 0 = blocked)
+// KEYS[1] = window key, ARGV[1] = now ms, ARGV[2] = window ms, ARGV[3] = limit, ARGV[4] = member
+var slidingWindowScript = redis.NewScript(`
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', tonumber(ARGV[1]) - tonumber(ARGV[2]))
+local count = redis.call('ZCARD', KEYS[1])
+if count < tonumber(ARGV[3]) then
+	redis.call('ZADD', KEYS[1], ARGV[1], ARGV[4])
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return {count + 1, 0}
+end
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+local retry = tonumber(oldest[2]) + tonumber(ARGV[2]) - tonumber(ARGV[1])
+if retry < 1 then
+	retry = 1
+end
+return {count + 1, retry}
+`)
+
+// BruteForceService throttles repeated attempts on sensitive auth endpoints with
+// Redis sliding-window counters (per policy and client, independent of the gateway
+// rate limit) and records an audit event the first time a client hits the limit
+type BruteForceService struct {
+	redisClient *redis.Client
+	events      domain.SecurityEventRepository
+	policies    map[string]BruteForcePolicy
+	logger      *zap.Logger
+}
+
+// NewBruteForceService creates a new brute-force service
+func NewBruteForceService(
+	redisClient *redis.Client,
+	events domain.SecurityEventRepository,
+	policies map[string]BruteForcePolicy,
+	logger *zap.Logger,
+) *BruteForceService {
+	return &BruteForceService{
+		redisClient: redisClient,
+		events:      events,
+		policies:    policies,
+		logger:      logger,
+	}
+}
+
+// Check records an attempt of subject (client IP) under policy and decides whether it
+// may proceed and after which delay; unknown policies are not throttled
+func (s *BruteForceService) Check(ctx context.Context, policyName, subject, path string) (*ThrottleDecision, error) {
+	policy, ok := s.policies[policyName]
+	if !ok || policy.Limit <= 0 || policy.Window <= 0 {
+		return &ThrottleDecision{}, nil
+	}
+
+	now := time.Now()
+	key := fmt.Sprintf("bruteforce:%s:%s", policyName, subject)
+	member := strconv.FormatInt(now.UnixNano(), 10) + "-" + strconv.Itoa(rand.Intn(1000000))
+
+	res, err := slidingWindowScript.Run(ctx, s.redisClient, []string{key},
+		now.UnixMilli(), policy.Window.Milliseconds(), policy.Limit, member).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check attempts: %w", err)
+	}
+
+	decision := &ThrottleDecision{Attempts: int(res[0])}
+	if res[1] > 0 {
+		decision.Blocked = true
+		decision.RetryAfter = time.Duration(res[1]) * time.Millisecond
+		s.auditBreach(ctx, policyName, policy, subject, path, decision.Attempts)
+		return decision, nil
+	}
+
+	decision.Delay = progressiveDelay(policy, decision.Attempts)
+	return decision, nil
+}
+
+// auditBreach records the first blocked attempt of a client per window
+// (later blocked attempts in the same window are not recorded again)
+func (s *BruteForceService) auditBreach(ctx context.Context, policyName string, policy BruteForcePolicy, subject, path string, attempts int) {
+	alertKey := fmt.Sprintf("bruteforce:%s:%s:alerted", policyName, subject)
+	first, err := s.redisClient.SetNX(ctx, alertKey, 1, policy.Window).Result()
+	if err != nil || !first {
+		return
+	}
+
+	s.logger.Warn("brute-force limit exceeded",
+		zap.String("policy", policyName),
+		zap.String("ip", subject),
+		zap.String("path", path),
+		zap.Int("attempts", attempts),
+		zap.Duration("window", policy.Window),
+	)
+
+	event := &domain.SecurityEvent{
+		Type:     domain.SecurityEventAuthThrottled,
+		Policy:   policyName,
+		Path:     path,
+		IP:       subject,
+		Attempts: attempts,
+	}
+	if err := s.events.Create(event); err != nil {
+		s.logger.Error("failed to record security event", zap.String("policy", policyName), zap.Error(err))
+	}
+}
+
+// ListEvents returns security audit events, newest first
+func (s *BruteForceService) ListEvents(eventType string, page, limit int) ([]*domain.SecurityEvent, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return s.events.List(eventType, page, limit)
+}
+
+// progressiveDelay returns BaseDelay doubled for each attempt over FreeAttempts, capped at MaxDelay
+func progressiveDelay(policy BruteForcePolicy, attempts int) time.Duration {
+	over := attempts - policy.FreeAttempts
+	if over <= 0 || policy.BaseDelay <= 0 {
+		return 0
+	}
+	delay := policy.BaseDelay
+	for i := 1; i < over; i++ {
+		delay *= 2
+		if policy.MaxDelay > 0 && delay >= policy.MaxDelay {
+			return policy.MaxDelay
+		}
+	}
+	if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+		return policy.MaxDelay
+	}
+	return delay
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"identity-service/pkg/jobs"
+	"identity-service/pkg/mailer"
+
+	"go.uber.org/zap"
+)
+
+// JobTypeSendEmail delivers one email (payload: mailer.Message)
+const JobTypeSendEmail = "email:send"
+
+// JobEnqueuer schedules background jobs (implemented by pkg/jobs)
+type JobEnqueuer interface {
+	Enqueue(ctx context.Context, jobType string, payload interface{}, opts ...jobs.Option) (*jobs.Job, error)
+}
+
+// EmailTemplateRenderer renders transactional email templates (implemented by EmailTemplateService)
+type EmailTemplateRenderer interface {
+	Render(key, locale string, data map[string]interface{}) (*RenderedEmail, error)
+}
+
+// EmailService queues emails and sends them from a background job
+// so that SMTP latency and outages never block API requests
+type EmailService struct {
+	jobs      JobEnqueuer
+	mailer    mailer.Mailer
+	templates EmailTemplateRenderer
+	logger    *zap.Logger
+}
+
+// NewEmailService creates a new email service
+func NewEmailService(jobEnqueuer JobEnqueuer, m mailer.Mailer, templates EmailTemplateRenderer, logger *zap.Logger) *EmailService {
+	return &EmailService{
+		jobs:      jobEnqueuer,
+		mailer:    m,
+		templates: templates,
+		logger:    logger,
+	}
+}
+
+// QueueEmail schedules an email for delivery (retried on failure)
+func (s *EmailService) QueueEmail(ctx context.Context, to, subject, body string) error {
+	_, err := s.jobs.Enqueue(ctx, JobTypeSendEmail, mailer.Message{To: to, Subject: subject, Body: body})
+	if err != nil {
+		return fmt.Errorf("failed to queue email: %w", err)
+	}
+	return nil
+}
+
+// QueueTemplateEmail renders the active template for the locale (with fallback to
+// the default locale) and schedules it for delivery; the rendered content is queued,
+// so template changes never affect emails already waiting to be sent
+func (s *EmailService) QueueTemplateEmail(ctx context.Context, to, key, locale string, data map[string]interface{}) error {
+	rendered, err := s.templates.Render(key, locale, data)
+	if err != nil {
+		return fmt.Errorf("failed to render email: %w", err)
+	}
+
+	msg := mailer.Message{To: to, Subject: rendered.Subject, Body: rendered.TextBody, HTML: rendered.HTMLBody}
+	if _, err := s.jobs.Enqueue(ctx, JobTypeSendEmail, msg); err != nil {
+		return fmt.Errorf("failed to queue email: %w", err)
+	}
+	return nil
+}
+
+// HandleSendEmail is the job handler for JobTypeSendEmail
+func (s *EmailService) HandleSendEmail(ctx context.Context, payload []byte) error {
+	var msg mailer.Message
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("invalid email payload: %w", err)
+	}
+
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		return err
+	}
+
+	s.logger.Info("email sent", zap.String("to", msg.To), zap.String("subject", msg.Subject))
+	return nil
+}
@@ -0,0 +1,371 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"identity-service/internal/domain"
+	"regexp"
+	"strings"
+	texttemplate "text/template"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	emailTemplateKeyPattern    = regexp.MustCompile(`^[a-z0-9_]{1,100}$`)
+	emailTemplateLocalePattern = regexp.MustCompile(`^[a-z]{2}(-[A-Z]{2})?$`)
+)
+
+// defaultEmailTemplates are created on startup if the (key, locale) has no version yet
+var defaultEmailTemplates = []domain.EmailTemplate{
+	{
+		Key:         domain.EmailTemplateWelcome,
+		Locale:      domain.DefaultEmailLocale,
+		Format:      domain.EmailTemplateFormatHTML,
+		Subject:     "Welcome to our store",
+		Source:      "<p>Hi {{.FullName}},</p>\n<p>Your account has been created. Happy shopping!</p>",
+		TextBody:    "Hi {{.FullName}},\n\nYour account has been created. Happy shopping!",
+		Variables:   []string{"FullName", "Email"},
+		Description: "Sent after registration",
+	},
+	{
+		Key:         domain.EmailTemplateWelcome,
+		Locale:      "vi",
+		Format:      domain.EmailTemplateFormatHTML,
+		Subject:     "Chào mừng bạn đến với cửa hàng",
+		Source:      "<p>Xin chào {{.FullName}},</p>\n<p>Tài khoản của bạn đã được tạo. Chúc bạn mua sắm vui vẻ!</p>",
+		TextBody:    "Xin chào {{.FullName}},\n\nTài khoản của bạn đã được tạo. Chúc bạn mua sắm vui vẻ!",
+		Variables:   []string{"FullName", "Email"},
+		Description: "Sent after registration",
+	},
+}
+
+// RenderedEmail is a template rendered with its variables
+type RenderedEmail struct {
+	Key      string `json:"key,omitempty"`
+	Locale   string `json:"locale,omitempty"` // Locale actually used (after fallback)
+	Version  int    `json:"version,omitempty"`
+	Subject  string `json:"subject"`
+	HTMLBody string `json:"html_body"`
+	TextBody string `json:"text_body"`
+}
+
+// SaveEmailTemplateRequest creates a new version of a template
+// MJML templates must carry the compiled html_body; for HTML templates it defaults to source
+type SaveEmailTemplateRequest struct {
+	Format      string   `json:"format" binding:"required"`
+	Subject     string   `json:"subject" binding:"required"`
+	Source      string   `json:"source" binding:"required"`
+	HTMLBody    string   `json:"html_body"`
+	TextBody    string   `json:"text_body"`
+	Variables   []string `json:"variables"`
+	Description string   `json:"description"`
+	Draft       bool     `json:"draft"` // Save without activating
+}
+
+// PreviewEmailTemplateRequest renders a stored version (key/locale, version 0 = active)
+// or, when subject is set, the unsaved template in the request
+type PreviewEmailTemplateRequest struct {
+	Key      string                 `json:"key"`
+	Locale   string                 `json:"locale"`
+	Version  int                    `json:"version"`
+	Subject  string                 `json:"subject"`
+	HTMLBody string                 `json:"html_body"`
+	TextBody string                 `json:"text_body"`
+	Data     map[string]interface{} `json:"data"`
+}
+
+// EmailTemplateService manages versioned, per-locale transactional email templates
+// and renders them for the email dispatcher
+type EmailTemplateService struct {
+	templateRepo domain.EmailTemplateRepository
+	logger       *zap.Logger
+}
+
+// NewEmailTemplateService creates a new email template service
+func NewEmailTemplateService(templateRepo domain.EmailTemplateRepository, logger *zap.Logger) *EmailTemplateService {
+	return &EmailTemplateService{
+		templateRepo: templateRepo,
+		logger:       logger,
+	}
+}
+
+// EnsureDefaults creates the built-in templates that do not exist yet
+func (s *EmailTemplateService) EnsureDefaults() error {
+	for i := range defaultEmailTemplates {
+		def := defaultEmailTemplates[i]
+		latest, err := s.templateRepo.LatestVersion(def.Key, def.Locale)
+		if err != nil {
+			return fmt.Errorf("failed to get email template: %w", err)
+		}
+		if latest > 0 {
+			continue
+		}
+		def.Version = 1
+		def.HTMLBody = def.Source
+		def.IsActive = true
+		if err := s.templateRepo.Create(&def); err != nil {
+			return fmt.Errorf("failed to create default email template: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListTemplates lists the active version of every template and locale
+func (s *EmailTemplateService) ListTemplates() ([]*domain.EmailTemplate, error) {
+	templates, err := s.templateRepo.ListActive()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list email templates: %w", err)
+	}
+	return templates, nil
+}
+
+// GetTemplate retrieves the active version of a template
+func (s *EmailTemplateService) GetTemplate(key, locale string) (*domain.EmailTemplate, error) {
+	template, err := s.templateRepo.GetActive(key, locale)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NotFound("email template not found")
+		}
+		return nil, fmt.Errorf("failed to get email template: %w", err)
+	}
+	return template, nil
+}
+
+// ListVersions lists all versions of a template, newest first
+func (s *EmailTemplateService) ListVersions(key, locale string) ([]*domain.EmailTemplate, error) {
+	versions, err := s.templateRepo.ListVersions(key, locale)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list email template versions: %w", err)
+	}
+	if len(versions) == 0 {
+		return nil, domain.NotFound("email template not found")
+	}
+	return versions, nil
+}
+
+// SaveTemplate stores a new version of a template (creating the template or locale
+// variant if needed) and activates it unless it is a draft
+// Business rule: only ADMIN can manage templates (checked by caller)
+func (s *EmailTemplateService) SaveTemplate(key, locale string, req *SaveEmailTemplateRequest, adminUserID uint) (*domain.EmailTemplate, error) {
+	if !emailTemplateKeyPattern.MatchString(key) {
+		return nil, domain.Validation("invalid template key (lowercase letters, digits and _)")
+	}
+	if !emailTemplateLocalePattern.MatchString(locale) {
+		return nil, domain.Validation("invalid locale (e.g. en, vi, en-US)")
+	}
+
+	htmlBody := req.HTMLBody
+	switch req.Format {
+	case domain.EmailTemplateFormatHTML:
+		if htmlBody == "" {
+			htmlBody = req.Source
+		}
+	case domain.EmailTemplateFormatMJML:
+		if htmlBody == "" {
+			return nil, domain.Validation("html_body (compiled MJML) is required for mjml templates")
+		}
+	default:
+		return nil, domain.Validation("invalid format: %s", req.Format)
+	}
+
+	if _, err := parseEmailTemplate(req.Subject, htmlBody, req.TextBody); err != nil {
+		return nil, err
+	}
+
+	latest, err := s.templateRepo.LatestVersion(key, locale)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email template: %w", err)
+	}
+
+	template := &domain.EmailTemplate{
+		Key:         key,
+		Locale:      locale,
+		Version:     latest + 1,
+		Format:      req.Format,
+		Subject:     req.Subject,
+		Source:      req.Source,
+		HTMLBody:    htmlBody,
+		TextBody:    req.TextBody,
+		Variables:   req.Variables,
+		Description: req.Description,
+		CreatedBy:   adminUserID,
+	}
+	if err := s.templateRepo.Create(template); err != nil {
+		s.logger.Error("failed to save email template", zap.Error(err))
+		return nil, fmt.Errorf("failed to save email template: %w", err)
+	}
+
+	if !req.Draft {
+		if err := s.templateRepo.Activate(key, locale, template.Version); err != nil {
+			return nil, fmt.Errorf("failed to activate email template: %w", err)
+		}
+		template.IsActive = true
+	}
+
+	s.logger.Info("email template saved",
+		zap.String("key", key),
+		zap.String("locale", locale),
+		zap.Int("version", template.Version),
+		zap.Bool("active", template.IsActive),
+		zap.Uint("created_by", adminUserID),
+	)
+
+	return template, nil
+}
+
+// ActivateVersion makes an existing version the active one (publish a draft or roll back)
+func (s *EmailTemplateService) ActivateVersion(key, locale string, version int, adminUserID uint) (*domain.EmailTemplate, error) {
+	if err := s.templateRepo.Activate(key, locale, version); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NotFound("email template version not found")
+		}
+		return nil, fmt.Errorf("failed to activate email template: %w", err)
+	}
+
+	s.logger.Info("email template version activated",
+		zap.String("key", key),
+		zap.String("locale", locale),
+		zap.Int("version", version),
+		zap.Uint("activated_by", adminUserID),
+	)
+
+	return s.templateRepo.GetVersion(key, locale, version)
+}
+
+// Preview renders a stored version or an unsaved template with sample data
+func (s *EmailTemplateService) Preview(req *PreviewEmailTemplateRequest) (*RenderedEmail, error) {
+	if req.Subject != "" {
+		parsed, err := parseEmailTemplate(req.Subject, req.HTMLBody, req.TextBody)
+		if err != nil {
+			return nil, err
+		}
+		return parsed.render(req.Data)
+	}
+
+	if req.Key == "" || req.Locale == "" {
+		return nil, domain.Validation("key and locale, or subject, are required")
+	}
+
+	var (
+		template *domain.EmailTemplate
+		err      error
+	)
+	if req.Version > 0 {
+		template, err = s.templateRepo.GetVersion(req.Key, req.Locale, req.Version)
+	} else {
+		template, err = s.templateRepo.GetActive(req.Key, req.Locale)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NotFound("email template not found")
+		}
+		return nil, fmt.Errorf("failed to get email template: %w", err)
+	}
+
+	return renderEmailTemplate(template, req.Data)
+}
+
+// Render renders the active template for a locale, falling back to the language
+// (vi-VN -> vi) and then to DefaultEmailLocale
+func (s *EmailTemplateService) Render(key, locale string, data map[string]interface{}) (*RenderedEmail, error) {
+	for _, candidate := range emailLocaleFallbacks(locale) {
+		template, err := s.templateRepo.GetActive(key, candidate)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get email template: %w", err)
+		}
+		return renderEmailTemplate(template, data)
+	}
+	return nil, domain.NotFound("email template %q not found", key)
+}
+
+// emailLocaleFallbacks lists the locales tried for a requested locale, most specific first
+func emailLocaleFallbacks(locale string) []string {
+	var locales []string
+	if locale != "" {
+		locales = append(locales, locale)
+		if lang, _, found := strings.Cut(locale, "-"); found {
+			locales = append(locales, lang)
+		}
+	}
+	if locale != domain.DefaultEmailLocale {
+		locales = append(locales, domain.DefaultEmailLocale)
+	}
+	return locales
+}
+
+// parsedEmailTemplate holds the parsed subject and bodies of a template
+type parsedEmailTemplate struct {
+	subject *texttemplate.Template
+	html    *htmltemplate.Template
+	text    *texttemplate.Template
+}
+
+// parseEmailTemplate parses subject and bodies; variables must be provided when rendering
+// HTML is rendered with html/template, so variables are escaped
+func parseEmailTemplate(subject, htmlBody, textBody string) (*parsedEmailTemplate, error) {
+	if htmlBody == "" && textBody == "" {
+		return nil, domain.Validation("html_body or text_body is required")
+	}
+
+	var (
+		parsed parsedEmailTemplate
+		err    error
+	)
+	if parsed.subject, err = texttemplate.New("subject").Option("missingkey=error").Parse(subject); err != nil {
+		return nil, fmt.Errorf("invalid subject template: %w", err)
+	}
+	if parsed.html, err = htmltemplate.New("html").Option("missingkey=error").Parse(htmlBody); err != nil {
+		return nil, fmt.Errorf("invalid html template: %w", err)
+	}
+	if parsed.text, err = texttemplate.New("text").Option("missingkey=error").Parse(textBody); err != nil {
+		return nil, fmt.Errorf("invalid text template: %w", err)
+	}
+	return &parsed, nil
+}
+
+// render executes the parsed template with data
+func (p *parsedEmailTemplate) render(data map[string]interface{}) (*RenderedEmail, error) {
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+
+	var subject, html, text bytes.Buffer
+	if err := p.subject.Execute(&subject, data); err != nil {
+		return nil, fmt.Errorf("failed to render subject: %w", err)
+	}
+	if err := p.html.Execute(&html, data); err != nil {
+		return nil, fmt.Errorf("failed to render html body: %w", err)
+	}
+	if err := p.text.Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("failed to render text body: %w", err)
+	}
+
+	return &RenderedEmail{
+		Subject:  strings.TrimSpace(subject.String()),
+		HTMLBody: html.String(),
+		TextBody: text.String(),
+	}, nil
+}
+
+// renderEmailTemplate renders a stored template version
+func renderEmailTemplate(template *domain.EmailTemplate, data map[string]interface{}) (*RenderedEmail, error) {
+	parsed, err := parseEmailTemplate(template.Subject, template.HTMLBody, template.TextBody)
+	if err != nil {
+		return nil, err
+	}
+	rendered, err := parsed.render(data)
+	if err != nil {
+		return nil, err
+	}
+	rendered.Key = template.Key
+	rendered.Locale = template.Locale
+	rendered.Version = template.Version
+	return rendered, nil
+}
@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"identity-service/internal/domain"
+	"identity-service/pkg/order_client"
+	"identity-service/pkg/product_client"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// exportBatchSize is how many users or shops an export reads from the database at a time
+	exportBatchSize = 500
+
+	// GMV period of the shop export when none is given, and its upper bound (order-service
+	// sums at most one year at once)
+	defaultGMVDays = 30
+	maxGMVPeriod   = 366 * 24 * time.Hour
+)
+
+var (
+	userExportHeader = []string{"id", "username", "email", "phone_number", "full_name", "role", "status", "created_at"}
+	shopExportHeader = []string{"id", "name", "slug", "owner_user_id", "status", "is_official", "province", "created_at", "rating_avg", "rating_count", "gmv", "gmv_order_count"}
+)
+
+// ExportService streams the user and shop exports of the operations and marketing teams
+// Rows are read in ID order one batch at a time, so an export of any size holds one batch in memory
+type ExportService struct {
+	userRepo      domain.UserRepository
+	shopRepo      domain.ShopRepository
+	orderClient   *order_client.OrderClient
+	productClient *product_client.ProductClient
+	logger        *zap.Logger
+}
+
+// NewExportService creates a new export service
+func NewExportService(
+	userRepo domain.UserRepository,
+	shopRepo domain.ShopRepository,
+	orderClient *order_client.OrderClient,
+	productClient *product_client.ProductClient,
+	logger *zap.Logger,
+) *ExportService {
+	return &ExportService{
+		userRepo:      userRepo,
+		shopRepo:      shopRepo,
+		orderClient:   orderClient,
+		productClient: productClient,
+		logger:        logger,
+	}
+}
+
+// UserExportQuery holds the filters of the user export as given in the request
+type UserExportQuery struct {
+	Role        string // ADMIN, SELLER or BUYER
+	Status      string // ACTIVE, BANNED or DELETED
+	CreatedFrom string // First signup day (YYYY-MM-DD)
+	CreatedTo   string // Last signup day, included (YYYY-MM-DD)
+}
+
+// ShopExportQuery holds the filters of the shop export as given in the request
+type ShopExportQuery struct {
+	Status      string // ACTIVE or SUSPENDED
+	Province    string // Ships-from province code
+	CreatedFrom string // First creation day (YYYY-MM-DD)
+	CreatedTo   string // Last creation day, included (YYYY-MM-DD)
+	MinRating   string // Only shops rated at least this (0-5)
+	MinGMV      string // Only shops with at least this GMV in the GMV period
+	GMVFrom     string // First day of the GMV period (YYYY-MM-DD), default 30 days before GMVTo
+	GMVTo       string // Last day of the GMV period, included (YYYY-MM-DD), default today
+}
+
+// ExportUsers writes the users matching the query as CSV records, the header first and
+// then one call of write per batch. Invalid filters are reported before anything is written
+func (s *ExportService) ExportUsers(ctx context.Context, query UserExportQuery, write func(records [][]string) error) error {
+	filter, err := userExportFilter(query)
+	if err != nil {
+		return err
+	}
+	if err := write([][]string{userExportHeader}); err != nil {
+		return err
+	}
+
+	var afterID uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		users, err := s.userRepo.ListForExport(filter, afterID, exportBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list users: %w", err)
+		}
+		if len(users) == 0 {
+			return nil
+		}
+
+		records := make([][]string, 0, len(users))
+		for _, user := range users {
+			records = append(records, []string{
+				strconv.FormatUint(uint64(user.ID), 10),
+				csvText(user.Username),
+				csvText(user.Email),
+				user.PhoneNumber,
+				csvText(user.FullName),
+				user.Role,
+				user.Status,
+				user.CreatedAt.Format(time.RFC3339),
+			})
+		}
+		if err := write(records); err != nil {
+			return err
+		}
+		afterID = users[len(users)-1].ID
+	}
+}
+
+// ExportShops writes the shops matching the query as CSV records with their rating (all
+// reviews, from product-service) and GMV in the GMV period (from order-service), the header
+// first and then one call of write per batch. Invalid filters and failures to get the
+// ratings or GMV are reported before anything is written
+func (s *ExportService) ExportShops(ctx context.Context, query ShopExportQuery, write func(records [][]string) error) error {
+	filter, err := shopExportFilter(query)
+	if err != nil {
+		return err
+	}
+	minRating, err := exportMinimum("min_rating", query.MinRating)
+	if err != nil {
+		return err
+	}
+	if minRating > 5 {
+		return domain.Validation("min_rating must be between 0 and 5")
+	}
+	minGMV, err := exportMinimum("min_gmv", query.MinGMV)
+	if err != nil {
+		return err
+	}
+	gmvFrom, gmvTo, err := gmvPeriod(query.GMVFrom, query.GMVTo)
+	if err != nil {
+		return err
+	}
+
+	ratings, err := s.productClient.ShopRatings(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get shop ratings: %w", err)
+	}
+	gmv, err := s.orderClient.ShopGMV(ctx, gmvFrom, gmvTo)
+	if err != nil {
+		return fmt.Errorf("failed to get shop GMV: %w", err)
+	}
+
+	if err := write([][]string{shopExportHeader}); err != nil {
+		return err
+	}
+
+	var afterID uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		shops, err := s.shopRepo.ListForExport(filter, afterID, exportBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to list shops: %w", err)
+		}
+		if len(shops) == 0 {
+			return nil
+		}
+		afterID = shops[len(shops)-1].ID
+
+		records := make([][]string, 0, len(shops))
+		for _, shop := range shops {
+			rating, sales := ratings[shop.ID], gmv[shop.ID]
+			if rating.RatingAvg < minRating || sales.GMV < minGMV {
+				continue
+			}
+			records = append(records, []string{
+				strconv.FormatUint(uint64(shop.ID), 10),
+				csvText(shop.Name),
+				shop.Slug,
+				strconv.FormatUint(uint64(shop.OwnerUserID), 10),
+				shop.Status,
+				strconv.FormatBool(shop.IsOfficial),
+				shop.Province,
+				shop.CreatedAt.Format(time.RFC3339),
+				strconv.FormatFloat(rating.RatingAvg, 'f', 2, 64),
+				strconv.Itoa(rating.RatingCount),
+				strconv.FormatFloat(sales.GMV, 'f', 2, 64),
+				strconv.FormatInt(sales.OrderCount, 10),
+			})
+		}
+		if len(records) == 0 {
+			continue
+		}
+		if err := write(records); err != nil {
+			return err
+		}
+	}
+}
+
+// userExportFilter validates the user export query
+func userExportFilter(query UserExportQuery) (domain.UserExportFilter, error) {
+	filter := domain.UserExportFilter{
+		Role:   strings.ToUpper(query.Role),
+		Status: strings.ToUpper(query.Status),
+	}
+	switch filter.Role {
+	case "", "ADMIN", "SELLER", "BUYER":
+	default:
+		return filter, domain.Validation("role must be ADMIN, SELLER or BUYER")
+	}
+	switch filter.Status {
+	case "", "ACTIVE", "BANNED", "DELETED":
+	default:
+		return filter, domain.Validation("status must be ACTIVE, BANNED or DELETED")
+	}
+
+	var err error
+	filter.CreatedFrom, filter.CreatedTo, err = exportDays("created", query.CreatedFrom, query.CreatedTo)
+	return filter, err
+}
+
+// shopExportFilter validates the shop export query
+func shopExportFilter(query ShopExportQuery) (domain.ShopExportFilter, error) {
+	filter := domain.ShopExportFilter{
+		Status:   strings.ToUpper(query.Status),
+		Province: query.Province,
+	}
+	switch filter.Status {
+	case "", "ACTIVE", "SUSPENDED":
+	default:
+		return filter, domain.Validation("status must be ACTIVE or SUSPENDED")
+	}
+	if filter.Province != "" {
+		if _, ok := domain.Provinces[filter.Province]; !ok {
+			return filter, domain.Validation("unknown province %q", filter.Province)
+		}
+	}
+
+	var err error
+	filter.CreatedFrom, filter.CreatedTo, err = exportDays("created", query.CreatedFrom, query.CreatedTo)
+	return filter, err
+}
+
+// exportDays parses the optional <name>_from and <name>_to days (YYYY-MM-DD, to included)
+// into the bounds [from, to); a missing day leaves its bound zero
+func exportDays(name, from, to string) (time.Time, time.Time, error) {
+	var start, end time.Time
+	var err error
+	if from != "" {
+		if start, err = time.ParseInLocation("2006-01-02", from, time.Local); err != nil {
+			return start, end, domain.Validation("%s_from must be a date (YYYY-MM-DD)", name)
+		}
+	}
+	if to != "" {
+		if end, err = time.ParseInLocation("2006-01-02", to, time.Local); err != nil {
+			return start, end, domain.Validation("%s_to must be a date (YYYY-MM-DD)", name)
+		}
+		end = end.AddDate(0, 0, 1)
+	}
+	if !start.IsZero() && !end.IsZero() && !start.Before(end) {
+		return start, end, domain.Validation("%s_from must not be after %s_to", name, name)
+	}
+	return start, end, nil
+}
+
+// gmvPeriod returns the first and last day of the GMV period, by default the last 30 days
+func gmvPeriod(from, to string) (time.Time, time.Time, error) {
+	start, end, err := exportDays("gmv", from, to)
+	if err != nil {
+		return start, end, err
+	}
+	if end.IsZero() {
+		now := time.Now()
+		end = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.Local)
+	}
+	if start.IsZero() {
+		start = end.AddDate(0, 0, -defaultGMVDays)
+	}
+	if !start.Before(end) || end.Sub(start) > maxGMVPeriod {
+		return start, end, domain.Validation("gmv_from must not be after gmv_to, at most one year apart")
+	}
+	return start, end.AddDate(0, 0, -1), nil
+}
+
+// exportMinimum parses an optional non-negative minimum; empty is 0
+func exportMinimum(name, value string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	minimum, err := strconv.ParseFloat(value, 64)
+	if err != nil || minimum < 0 {
+		return 0, domain.Validation("%s must be a non-negative number", name)
+	}
+	return minimum, nil
+}
+
+// csvText neutralizes text starting like a spreadsheet formula (=, +, -, @) so a name
+// entered by a user cannot run as a formula when the export is opened in a spreadsheet
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
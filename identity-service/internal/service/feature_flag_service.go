@@ -0,0 +1,167 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"identity-service/internal/domain"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// FeatureFlagService contains the business logic for feature flags
+// Flags are stored in Postgres and mirrored to Redis, where services evaluate them locally
+type FeatureFlagService struct {
+	flagRepo domain.FeatureFlagRepository
+	cache    domain.FeatureFlagCache
+	logger   *zap.Logger
+}
+
+// NewFeatureFlagService creates a new feature flag service
+func NewFeatureFlagService(
+	flagRepo domain.FeatureFlagRepository,
+	cache domain.FeatureFlagCache,
+	logger *zap.Logger,
+) *FeatureFlagService {
+	return &FeatureFlagService{
+		flagRepo: flagRepo,
+		cache:    cache,
+		logger:   logger,
+	}
+}
+
+// UpsertFeatureFlagRequest represents the request to create or update a feature flag
+type UpsertFeatureFlagRequest struct {
+	Description       string `json:"description"`
+	Enabled           bool   `json:"enabled"`
+	RolloutPercentage int    `json:"rollout_percentage" binding:"min=0,max=100"`
+	AllowedUserIDs    []uint `json:"allowed_user_ids"`
+	AllowedShopIDs    []uint `json:"allowed_shop_ids"`
+}
+
+// ListFlags lists all feature flags
+func (s *FeatureFlagService) ListFlags() ([]*domain.FeatureFlag, error) {
+	flags, err := s.flagRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feature flags: %w", err)
+	}
+	return flags, nil
+}
+
+// GetFlag retrieves a feature flag by key
+func (s *FeatureFlagService) GetFlag(key string) (*domain.FeatureFlag, error) {
+	flag, err := s.flagRepo.GetByKey(key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NotFound("feature flag not found")
+		}
+		return nil, fmt.Errorf("failed to get feature flag: %w", err)
+	}
+	return flag, nil
+}
+
+// UpsertFlag creates or updates a feature flag and syncs it to Redis
+func (s *FeatureFlagService) UpsertFlag(key string, req *UpsertFeatureFlagRequest, adminUserID uint) (*domain.FeatureFlag, error) {
+	if key == "" {
+		return nil, domain.Validation("flag key is required")
+	}
+
+	flag, err := s.flagRepo.GetByKey(key)
+	isNew := false
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to get feature flag: %w", err)
+		}
+		flag = &domain.FeatureFlag{Key: key}
+		isNew = true
+	}
+
+	flag.Description = req.Description
+	flag.Enabled = req.Enabled
+	flag.RolloutPercentage = req.RolloutPercentage
+	flag.AllowedUserIDs = req.AllowedUserIDs
+	flag.AllowedShopIDs = req.AllowedShopIDs
+	flag.UpdatedBy = adminUserID
+
+	if isNew {
+		err = s.flagRepo.Create(flag)
+	} else {
+		err = s.flagRepo.Update(flag)
+	}
+	if err != nil {
+		s.logger.Error("failed to save feature flag", zap.Error(err))
+		return nil, fmt.Errorf("failed to save feature flag: %w", err)
+	}
+
+	s.syncToCache(flag)
+
+	s.logger.Info("feature flag updated",
+		zap.String("key", flag.Key),
+		zap.Bool("enabled", flag.Enabled),
+		zap.Int("rollout_percentage", flag.RolloutPercentage),
+		zap.Uint("updated_by", adminUserID),
+	)
+
+	return flag, nil
+}
+
+// DeleteFlag deletes a feature flag (services treat missing flags as disabled)
+func (s *FeatureFlagService) DeleteFlag(key string) error {
+	if _, err := s.GetFlag(key); err != nil {
+		return err
+	}
+
+	if err := s.flagRepo.Delete(key); err != nil {
+		s.logger.Error("failed to delete feature flag", zap.Error(err))
+		return fmt.Errorf("failed to delete feature flag: %w", err)
+	}
+
+	if err := s.cache.Delete(key); err != nil {
+		s.logger.Warn("failed to delete feature flag from cache", zap.Error(err))
+	}
+	if err := s.cache.PublishChanged(key); err != nil {
+		s.logger.Warn("failed to publish feature flag change", zap.Error(err))
+	}
+
+	s.logger.Info("feature flag deleted", zap.String("key", key))
+	return nil
+}
+
+// EvaluateFlags returns enabled state of all flags for a user/shop
+// Used by frontends to toggle UI features
+func (s *FeatureFlagService) EvaluateFlags(userID, shopID uint) (map[string]bool, error) {
+	flags, err := s.ListFlags()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]bool, len(flags))
+	for _, flag := range flags {
+		result[flag.Key] = flag.IsEnabledFor(userID, shopID)
+	}
+	return result, nil
+}
+
+// WarmCache writes all flags to Redis (called on startup)
+func (s *FeatureFlagService) WarmCache() error {
+	flags, err := s.ListFlags()
+	if err != nil {
+		return err
+	}
+	for _, flag := range flags {
+		if err := s.cache.Set(flag); err != nil {
+			return fmt.Errorf("failed to cache feature flag: %w", err)
+		}
+	}
+	return nil
+}
+
+// syncToCache mirrors a flag to Redis and notifies services
+func (s *FeatureFlagService) syncToCache(flag *domain.FeatureFlag) {
+	if err := s.cache.Set(flag); err != nil {
+		s.logger.Warn("failed to cache feature flag", zap.String("key", flag.Key), zap.Error(err))
+	}
+	if err := s.cache.PublishChanged(flag.Key); err != nil {
+		s.logger.Warn("failed to publish feature flag change", zap.String("key", flag.Key), zap.Error(err))
+	}
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"fmt"
+	"identity-service/internal/domain"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// StartImpersonationRequest represents an admin's request to act as a user
+type StartImpersonationRequest struct {
+	UserID          uint   `json:"user_id" binding:"required"`
+	Reason          string `json:"reason" binding:"required,min=10,max=500"` // Support case, recorded in the audit trail
+	DurationMinutes int    `json:"duration_minutes"`                         // 0 = default duration, capped at the maximum
+}
+
+// ImpersonationResponse carries the impersonation token (not refreshable, expires with the impersonation)
+type ImpersonationResponse struct {
+	AccessToken   string                `json:"access_token"`
+	Impersonation *domain.Impersonation `json:"impersonation"`
+	ExpiresIn     int64                 `json:"expires_in"` // seconds until the token expires
+}
+
+// ImpersonationService lets admins obtain a time-boxed token of a user for support cases.
+// Tokens carry an "act" claim naming the admin (RFC 8693 actor), are bound to a dedicated
+// session that cannot be refreshed, and every start and revocation is recorded
+type ImpersonationService struct {
+	authService     *AuthService
+	impersonations  domain.ImpersonationRepository
+	defaultDuration time.Duration
+	maxDuration     time.Duration
+	logger          *zap.Logger
+}
+
+// NewImpersonationService creates a new impersonation service
+func NewImpersonationService(
+	authService *AuthService,
+	impersonations domain.ImpersonationRepository,
+	defaultDuration, maxDuration time.Duration,
+	logger *zap.Logger,
+) *ImpersonationService {
+	if maxDuration <= 0 {
+		maxDuration = time.Hour
+	}
+	if defaultDuration <= 0 || defaultDuration > maxDuration {
+		defaultDuration = maxDuration
+	}
+	return &ImpersonationService{
+		authService:     authService,
+		impersonations:  impersonations,
+		defaultDuration: defaultDuration,
+		maxDuration:     maxDuration,
+		logger:          logger,
+	}
+}
+
+// Start issues an impersonation token of req.UserID for adminID
+// Admins cannot impersonate themselves, other admins or inactive users
+func (s *ImpersonationService) Start(adminID uint, req *StartImpersonationRequest, ipAddress string) (*ImpersonationResponse, error) {
+	admin, err := s.authService.userRepo.GetByID(adminID)
+	if err != nil || admin.Role != "ADMIN" || admin.Status != "ACTIVE" {
+		return nil, domain.Forbidden("only active admins can impersonate users")
+	}
+	if req.UserID == adminID {
+		return nil, domain.Validation("cannot impersonate yourself")
+	}
+
+	user, err := s.authService.userRepo.GetByID(req.UserID)
+	if err != nil {
+		return nil, domain.NotFound("user not found")
+	}
+	if user.Role == "ADMIN" {
+		return nil, domain.Forbidden("cannot impersonate an admin")
+	}
+	if user.Status != "ACTIVE" {
+		return nil, domain.Conflict("user is not active")
+	}
+
+	duration := s.defaultDuration
+	if req.DurationMinutes > 0 {
+		duration = min(time.Duration(req.DurationMinutes)*time.Minute, s.maxDuration)
+	}
+
+	now := time.Now()
+	impersonation := &domain.Impersonation{
+		ID:        uuid.New().String(),
+		AdminID:   admin.ID,
+		UserID:    user.ID,
+		Reason:    req.Reason,
+		IPAddress: ipAddress,
+		ExpiresAt: now.Add(duration),
+	}
+	if err := s.impersonations.Create(impersonation); err != nil {
+		return nil, fmt.Errorf("failed to record impersonation: %w", err)
+	}
+
+	// Dedicated session: revoking deletes it, which invalidates the token at the gateway and introspection
+	session := &domain.Session{
+		ID:         impersonation.ID,
+		UserID:     int64(user.ID),
+		DeviceType: domain.ImpersonationDeviceType,
+		IPAddress:  ipAddress,
+		CreatedAt:  now,
+		ExpiresAt:  impersonation.ExpiresAt,
+		LastUsedAt: now,
+	}
+	if err := s.authService.sessionRepo.CreateSession(session); err != nil {
+		return nil, fmt.Errorf("failed to create impersonation session: %w", err)
+	}
+
+	token, err := s.generateToken(admin, user, impersonation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate impersonation token: %w", err)
+	}
+
+	s.logger.Warn("admin impersonation started",
+		zap.String("impersonation_id", impersonation.ID),
+		zap.Uint("admin_id", admin.ID),
+		zap.Uint("user_id", user.ID),
+		zap.String("reason", req.Reason),
+		zap.String("ip", ipAddress),
+		zap.Time("expires_at", impersonation.ExpiresAt),
+	)
+
+	return &ImpersonationResponse{
+		AccessToken:   token,
+		Impersonation: impersonation,
+		ExpiresIn:     int64(duration.Seconds()),
+	}, nil
+}
+
+// Revoke ends an impersonation before it expires
+func (s *ImpersonationService) Revoke(id string, revokedBy uint) (*domain.Impersonation, error) {
+	impersonation, err := s.impersonations.GetByID(id)
+	if err != nil {
+		return nil, domain.NotFound("impersonation not found")
+	}
+	if impersonation.RevokedAt != nil {
+		return nil, domain.Conflict("impersonation already revoked")
+	}
+
+	if err := s.authService.sessionRepo.DeleteSession(impersonation.ID); err != nil {
+		return nil, fmt.Errorf("failed to revoke impersonation session: %w", err)
+	}
+
+	now := time.Now()
+	impersonation.RevokedAt = &now
+	impersonation.RevokedBy = &revokedBy
+	if err := s.impersonations.Update(impersonation); err != nil {
+		return nil, fmt.Errorf("failed to record revocation: %w", err)
+	}
+
+	s.logger.Warn("admin impersonation revoked",
+		zap.String("impersonation_id", impersonation.ID),
+		zap.Uint("admin_id", impersonation.AdminID),
+		zap.Uint("user_id", impersonation.UserID),
+		zap.Uint("revoked_by", revokedBy),
+	)
+
+	return impersonation, nil
+}
+
+// List returns the impersonation audit trail, newest first
+func (s *ImpersonationService) List(adminID, userID uint, page, limit int) ([]*domain.Impersonation, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return s.impersonations.List(adminID, userID, page, limit)
+}
+
+// generateToken signs an access token of user with the admin as actor (act claim)
+func (s *ImpersonationService) generateToken(admin, user *domain.User, impersonation *domain.Impersonation) (string, error) {
+	claims := jwt.MapClaims{
+		"user_id": user.ID,
+		"email":   user.Email,
+		"role":    user.Role,
+		"type":    "access",
+		"sid":     impersonation.ID,
+		"exp":     impersonation.ExpiresAt.Unix(),
+		"iat":     time.Now().Unix(),
+		"act": map[string]interface{}{ // Impersonating admin (RFC 8693 actor claim)
+			"user_id": admin.ID,
+			"email":   admin.Email,
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(s.authService.jwtSecret))
+}
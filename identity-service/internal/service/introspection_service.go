@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Token type hints accepted by introspection (RFC 7662 token_type_hint)
+const (
+	TokenHintAccessToken  = "access_token"
+	TokenHintRefreshToken = "refresh_token"
+	TokenHintSessionID    = "session_id"
+)
+
+// roleScopes maps user roles to the scopes reported by introspection
+var roleScopes = map[string][]string{
+	"BUYER":  {"profile", "orders", "cart"},
+	"SELLER": {"profile", "orders", "cart", "shop", "products"},
+	"ADMIN":  {"profile", "orders", "cart", "shop", "products", "admin"},
+}
+
+// IntrospectionRequest is an RFC 7662 introspection request (form or JSON body)
+type IntrospectionRequest struct {
+	Token         string `form:"token" json:"token" binding:"required"`
+	TokenTypeHint string `form:"token_type_hint" json:"token_type_hint"`
+}
+
+// IntrospectionResponse is an RFC 7662 introspection response; inactive tokens only carry active=false
+type IntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	TokenType string `json:"token_type,omitempty"` // access_token, refresh_token or session_id
+	Exp       int64  `json:"exp,omitempty"`
+	Iat       int64  `json:"iat,omitempty"`
+	Sub       string `json:"sub,omitempty"`
+	UserID    uint   `json:"user_id,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Role      string `json:"role,omitempty"`
+	SessionID string `json:"sid,omitempty"`
+
+	ImpersonatorID uint `json:"impersonator_id,omitempty"` // Admin acting as the user (impersonation token)
+}
+
+// IntrospectionService lets downstream services validate access tokens, session IDs
+// and refresh tokens (including revocation) without sharing the JWT signing secret.
+// Results are cached in Redis for a short time, never beyond the token expiry
+type IntrospectionService struct {
+	authService *AuthService
+	redisClient *redis.Client
+	cacheTTL    time.Duration
+	logger      *zap.Logger
+}
+
+// NewIntrospectionService creates a new introspection service (cacheTTL 0 = no caching)
+func NewIntrospectionService(authService *AuthService, redisClient *redis.Client, cacheTTL time.Duration, logger *zap.Logger) *IntrospectionService {
+	return &IntrospectionService{
+		authService: authService,
+		redisClient: redisClient,
+		cacheTTL:    cacheTTL,
+		logger:      logger,
+	}
+}
+
+// Introspect reports whether token is active and, if so, whom it belongs to.
+// The hint is tried first; unknown or wrong hints fall back to the other token types
+func (s *IntrospectionService) Introspect(ctx context.Context, req *IntrospectionRequest) *IntrospectionResponse {
+	cacheKey := introspectionCacheKey(req.Token)
+	if cached := s.getCached(ctx, cacheKey); cached != nil {
+		return cached
+	}
+
+	resp := s.introspect(req.Token, req.TokenTypeHint)
+	s.setCached(ctx, cacheKey, resp)
+	return resp
+}
+
+// introspect resolves the token without the cache
+func (s *IntrospectionService) introspect(token, hint string) *IntrospectionResponse {
+	order := []string{TokenHintAccessToken, TokenHintSessionID, TokenHintRefreshToken}
+	for i, t := range order {
+		if t == hint {
+			order = append([]string{t}, append(order[:i:i], order[i+1:]...)...)
+			break
+		}
+	}
+
+	for _, t := range order {
+		var resp *IntrospectionResponse
+		switch t {
+		case TokenHintAccessToken:
+			resp = s.introspectAccessToken(token)
+		case TokenHintSessionID:
+			resp = s.introspectSession(token)
+		case TokenHintRefreshToken:
+			resp = s.introspectRefreshToken(token)
+		}
+		if resp != nil {
+			return resp
+		}
+	}
+	return &IntrospectionResponse{Active: false}
+}
+
+// introspectAccessToken checks signature, expiry, type and the bound session (sid claim)
+func (s *IntrospectionService) introspectAccessToken(token string) *IntrospectionResponse {
+	if strings.Count(token, ".") != 2 {
+		return nil
+	}
+	claims, err := s.authService.parseAccessToken(token)
+	if err != nil {
+		return nil
+	}
+	if tokenType, _ := claims["type"].(string); tokenType != "access" {
+		return nil
+	}
+
+	userID, _ := claims["user_id"].(float64)
+	sessionID, _ := claims["sid"].(string)
+	if sessionID != "" {
+		session, err := s.authService.sessionRepo.GetSession(sessionID)
+		if err != nil || session == nil || !session.IsValid() {
+			return nil
+		}
+	}
+
+	resp := s.activeResponse(uint(userID), TokenHintAccessToken)
+	if resp == nil {
+		return nil
+	}
+	resp.Exp = claimUnix(claims, "exp")
+	resp.Iat = claimUnix(claims, "iat")
+	resp.SessionID = sessionID
+	if act, ok := claims["act"].(map[string]interface{}); ok {
+		adminID, _ := act["user_id"].(float64)
+		resp.ImpersonatorID = uint(adminID)
+	}
+	return resp
+}
+
+// introspectSession checks an opaque session ID (session_id cookie)
+func (s *IntrospectionService) introspectSession(token string) *IntrospectionResponse {
+	session, err := s.authService.sessionRepo.GetSession(token)
+	if err != nil || session == nil || !session.IsValid() {
+		return nil
+	}
+
+	resp := s.activeResponse(uint(session.UserID), TokenHintSessionID)
+	if resp == nil {
+		return nil
+	}
+	resp.Exp = session.ExpiresAt.Unix()
+	resp.Iat = session.CreatedAt.Unix()
+	resp.SessionID = session.ID
+	return resp
+}
+
+// introspectRefreshToken checks a legacy database refresh token
+func (s *IntrospectionService) introspectRefreshToken(token string) *IntrospectionResponse {
+	refreshToken, err := s.authService.refreshTokenRepo.GetByToken(token)
+	if err != nil || refreshToken == nil || !refreshToken.IsValid() {
+		return nil
+	}
+
+	resp := s.activeResponse(refreshToken.UserID, TokenHintRefreshToken)
+	if resp == nil {
+		return nil
+	}
+	resp.Exp = refreshToken.ExpiresAt.Unix()
+	resp.Iat = refreshToken.CreatedAt.Unix()
+	return resp
+}
+
+// activeResponse builds the active response of a user; nil if the user is missing or not ACTIVE
+func (s *IntrospectionService) activeResponse(userID uint, tokenType string) *IntrospectionResponse {
+	if userID == 0 {
+		return nil
+	}
+	user, err := s.authService.userRepo.GetByID(userID)
+	if err != nil || user == nil || user.Status != "ACTIVE" {
+		return nil
+	}
+
+	return &IntrospectionResponse{
+		Active:    true,
+		Scope:     strings.Join(roleScopes[user.Role], " "),
+		TokenType: tokenType,
+		Sub:       strconv.FormatUint(uint64(user.ID), 10),
+		UserID:    user.ID,
+		Email:     user.Email,
+		Role:      user.Role,
+	}
+}
+
+// getCached returns a cached result, nil on miss or Redis error
+func (s *IntrospectionService) getCached(ctx context.Context, key string) *IntrospectionResponse {
+	if s.cacheTTL <= 0 {
+		return nil
+	}
+	data, err := s.redisClient.Get(ctx, key).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			s.logger.Warn("failed to read introspection cache", zap.Error(err))
+		}
+		return nil
+	}
+
+	var resp IntrospectionResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil
+	}
+	return &resp
+}
+
+// setCached caches a result for cacheTTL, never past the token expiry
+func (s *IntrospectionService) setCached(ctx context.Context, key string, resp *IntrospectionResponse) {
+	if s.cacheTTL <= 0 {
+		return
+	}
+	ttl := s.cacheTTL
+	if resp.Active && resp.Exp > 0 {
+		ttl = min(ttl, time.Until(time.Unix(resp.Exp, 0)))
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return
+	}
+	if err := s.redisClient.Set(ctx, key, data, ttl).Err(); err != nil {
+		s.logger.Warn("failed to write introspection cache", zap.Error(err))
+	}
+}
+
+// introspectionCacheKey hashes the token so raw credentials are never stored in Redis
+func introspectionCacheKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "introspect:" + hex.EncodeToString(sum[:])
+}
+
+// claimUnix reads a numeric date claim
+func claimUnix(claims jwt.MapClaims, name string) int64 {
+	v, _ := claims[name].(float64)
+	return int64(v)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"fmt"
+	"identity-service/internal/domain"
+	"slices"
+
+	"go.uber.org/zap"
+)
+
+// NotificationPreferenceService manages which notifications a user receives on which channel
+// Preferences are stored in Postgres and mirrored to Redis for the services sending notifications
+type NotificationPreferenceService struct {
+	repo   domain.NotificationPreferenceRepository
+	cache  domain.NotificationPreferenceCache
+	logger *zap.Logger
+}
+
+// NewNotificationPreferenceService creates a new notification preference service
+func NewNotificationPreferenceService(repo domain.NotificationPreferenceRepository, cache domain.NotificationPreferenceCache, logger *zap.Logger) *NotificationPreferenceService {
+	return &NotificationPreferenceService{
+		repo:   repo,
+		cache:  cache,
+		logger: logger,
+	}
+}
+
+// GetPreferences returns the full preference matrix of a user (defaults where not set)
+func (s *NotificationPreferenceService) GetPreferences(userID uint) (domain.NotificationPreferenceMatrix, error) {
+	prefs, err := s.repo.ListByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load notification preferences: %w", err)
+	}
+
+	matrix := defaultPreferenceMatrix()
+	for _, pref := range prefs {
+		if channels, ok := matrix[pref.Category]; ok {
+			if _, ok := channels[pref.Channel]; ok {
+				channels[pref.Channel] = pref.Enabled
+			}
+		}
+	}
+	return matrix, nil
+}
+
+// UpdatePreferences applies a partial matrix (only the given category/channel pairs change)
+// and returns the full matrix
+func (s *NotificationPreferenceService) UpdatePreferences(userID uint, update domain.NotificationPreferenceMatrix) (domain.NotificationPreferenceMatrix, error) {
+	for category, channels := range update {
+		if !slices.Contains(domain.NotificationCategories, category) {
+			return nil, domain.Validation("unknown notification category: %s", category)
+		}
+		for channel := range channels {
+			if !slices.Contains(domain.NotificationChannels, channel) {
+				return nil, domain.Validation("unknown notification channel: %s", channel)
+			}
+		}
+	}
+
+	for category, channels := range update {
+		for channel, enabled := range channels {
+			pref := &domain.NotificationPreference{
+				UserID:   userID,
+				Category: category,
+				Channel:  channel,
+				Enabled:  enabled,
+			}
+			if err := s.repo.Upsert(pref); err != nil {
+				return nil, fmt.Errorf("failed to save notification preference: %w", err)
+			}
+		}
+	}
+
+	matrix, err := s.GetPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Redis is rewritten from Postgres; a failed write keeps the previous copy until the next change
+	if err := s.cache.Set(userID, matrix); err != nil {
+		s.logger.Warn("failed to cache notification preferences", zap.Uint("user_id", userID), zap.Error(err))
+	}
+
+	s.logger.Info("notification preferences updated", zap.Uint("user_id", userID))
+	return matrix, nil
+}
+
+// defaultPreferenceMatrix returns a copy of the default preferences
+func defaultPreferenceMatrix() domain.NotificationPreferenceMatrix {
+	matrix := make(domain.NotificationPreferenceMatrix, len(domain.DefaultNotificationPreferences))
+	for category, channels := range domain.DefaultNotificationPreferences {
+		matrix[category] = make(map[string]bool, len(channels))
+		for channel, enabled := range channels {
+			matrix[category][channel] = enabled
+		}
+	}
+	return matrix
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"fmt"
+	"identity-service/internal/domain"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// RegisterPushDeviceRequest registers (or refreshes) the push token of a device
+type RegisterPushDeviceRequest struct {
+	DeviceID   string `json:"device_id" binding:"required,max=255"`
+	Platform   string `json:"platform" binding:"required,oneof=fcm apns"`
+	Token      string `json:"token" binding:"required,max=512"`
+	AppVersion string `json:"app_version" binding:"max=50"`
+}
+
+// PushTarget is a token the push channel delivers to
+type PushTarget struct {
+	DeviceID string `json:"device_id"`
+	Platform string `json:"platform"`
+	Token    string `json:"token"`
+}
+
+// PushDeliveryReport is sent by the push channel after a delivery attempt
+// Invalid: provider rejected the token for good (FCM UNREGISTERED/INVALID_ARGUMENT,
+// APNs BadDeviceToken/Unregistered), pruned right away
+// Failed: transient failure, pruned after max consecutive failures
+// Delivered: resets the failure count
+type PushDeliveryReport struct {
+	Invalid   []string `json:"invalid"`
+	Failed    []string `json:"failed"`
+	Delivered []string `json:"delivered"`
+}
+
+// PushDeviceService manages the FCM/APNs device tokens of users for the push
+// notification channel
+type PushDeviceService struct {
+	deviceRepo  domain.PushDeviceRepository
+	maxPerUser  int
+	maxFailures int
+	logger      *zap.Logger
+}
+
+// NewPushDeviceService creates a new push device service
+func NewPushDeviceService(deviceRepo domain.PushDeviceRepository, maxPerUser, maxFailures int, logger *zap.Logger) *PushDeviceService {
+	if maxFailures < 1 {
+		maxFailures = 1
+	}
+	return &PushDeviceService{
+		deviceRepo:  deviceRepo,
+		maxPerUser:  maxPerUser,
+		maxFailures: maxFailures,
+		logger:      logger,
+	}
+}
+
+// RegisterDevice stores the token of a device of the user, replacing the previous
+// token of the device; the least recently seen devices beyond the per-user limit are dropped
+func (s *PushDeviceService) RegisterDevice(userID uint, req *RegisterPushDeviceRequest) (*domain.PushDevice, error) {
+	device := &domain.PushDevice{
+		UserID:     userID,
+		DeviceID:   req.DeviceID,
+		Platform:   req.Platform,
+		Token:      req.Token,
+		AppVersion: req.AppVersion,
+		LastSeenAt: time.Now(),
+	}
+	if err := s.deviceRepo.Register(device); err != nil {
+		s.logger.Error("failed to register push device", zap.Error(err))
+		return nil, fmt.Errorf("failed to register push device: %w", err)
+	}
+
+	if s.maxPerUser > 0 {
+		if pruned, err := s.deviceRepo.PruneOldest(userID, s.maxPerUser); err != nil {
+			s.logger.Warn("failed to prune push devices", zap.Uint("user_id", userID), zap.Error(err))
+		} else if pruned > 0 {
+			s.logger.Info("pruned oldest push devices", zap.Uint("user_id", userID), zap.Int64("count", pruned))
+		}
+	}
+
+	s.logger.Info("push device registered",
+		zap.Uint("user_id", userID),
+		zap.String("device_id", device.DeviceID),
+		zap.String("platform", device.Platform),
+	)
+
+	return device, nil
+}
+
+// ListDevices lists the registered devices of the user
+func (s *PushDeviceService) ListDevices(userID uint) ([]*domain.PushDevice, error) {
+	devices, err := s.deviceRepo.ListByUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list push devices: %w", err)
+	}
+	return devices, nil
+}
+
+// UnregisterDevice removes a device of the user (logout, notifications disabled on the device)
+func (s *PushDeviceService) UnregisterDevice(userID uint, deviceID string) error {
+	deleted, err := s.deviceRepo.DeleteByDevice(userID, deviceID)
+	if err != nil {
+		return fmt.Errorf("failed to unregister push device: %w", err)
+	}
+	if deleted == 0 {
+		return domain.NotFound("push device not found")
+	}
+
+	s.logger.Info("push device unregistered", zap.Uint("user_id", userID), zap.String("device_id", deviceID))
+	return nil
+}
+
+// GetTargets returns the tokens the push channel delivers to for a user
+func (s *PushDeviceService) GetTargets(userID uint) ([]PushTarget, error) {
+	devices, err := s.ListDevices(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]PushTarget, 0, len(devices))
+	for _, d := range devices {
+		targets = append(targets, PushTarget{DeviceID: d.DeviceID, Platform: d.Platform, Token: d.Token})
+	}
+	return targets, nil
+}
+
+// ReportDelivery prunes tokens the provider rejected and tracks transient failures
+// Returns the number of pruned tokens
+func (s *PushDeviceService) ReportDelivery(report *PushDeliveryReport) (int64, error) {
+	var pruned int64
+
+	if len(report.Invalid) > 0 {
+		deleted, err := s.deviceRepo.DeleteByTokens(report.Invalid)
+		if err != nil {
+			return 0, fmt.Errorf("failed to prune invalid push tokens: %w", err)
+		}
+		pruned += deleted
+	}
+
+	if len(report.Failed) > 0 {
+		deleted, err := s.deviceRepo.RecordFailures(report.Failed, s.maxFailures)
+		if err != nil {
+			return pruned, fmt.Errorf("failed to record push delivery failures: %w", err)
+		}
+		pruned += deleted
+	}
+
+	if len(report.Delivered) > 0 {
+		if err := s.deviceRepo.ResetFailures(report.Delivered); err != nil {
+			s.logger.Warn("failed to reset push delivery failures", zap.Error(err))
+		}
+	}
+
+	if pruned > 0 {
+		s.logger.Info("pruned push tokens after delivery failures",
+			zap.Int("invalid", len(report.Invalid)),
+			zap.Int("failed", len(report.Failed)),
+			zap.Int64("pruned", pruned),
+		)
+	}
+
+	return pruned, nil
+}
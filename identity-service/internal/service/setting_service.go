@@ -0,0 +1,237 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"identity-service/internal/domain"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// defaultSettings are created on startup if missing
+// Values match the previously hard-coded constants in each service
+var defaultSettings = []domain.Setting{
+	{Scope: domain.SettingScopeGlobal, Key: "maintenance_mode", Value: "false", ValueType: domain.SettingTypeBool, Description: "Put the storefront into maintenance mode"},
+	{Scope: domain.SettingScopeOrder, Key: "platform_fee_percent", Value: "5", ValueType: domain.SettingTypeFloat, Description: "Platform fee charged to sellers, in percent of merchandise subtotal"},
+	{Scope: domain.SettingScopeOrder, Key: "retention_enabled", Value: "false", ValueType: domain.SettingTypeBool, Description: "Anonymize buyer data on finished orders older than retention_days"},
+	{Scope: domain.SettingScopeOrder, Key: "retention_days", Value: "730", ValueType: domain.SettingTypeInt, Description: "Days after ordering before buyer data on finished orders is anonymized (amounts are kept)"},
+	{Scope: domain.SettingScopeOrder, Key: "archive_enabled", Value: "false", ValueType: domain.SettingTypeBool, Description: "Move finished orders older than archive_after_years to the order archive (still readable by ID and order number)"},
+	{Scope: domain.SettingScopeOrder, Key: "archive_after_years", Value: "3", ValueType: domain.SettingTypeInt, Description: "Years after ordering before finished orders are archived"},
+	{Scope: domain.SettingScopeCart, Key: "max_item_quantity", Value: "999", ValueType: domain.SettingTypeInt, Description: "Maximum quantity of a single item in cart"},
+	{Scope: domain.SettingScopeProduct, Key: "reservation_ttl_minutes", Value: "15", ValueType: domain.SettingTypeInt, Description: "How long checkout stock reservations are held"},
+}
+
+// SettingService contains the business logic for admin global configuration
+// Settings are stored in Postgres (source of truth + audit), cached in Redis and
+// every change is broadcast so other services can refresh without restart
+type SettingService struct {
+	settingRepo domain.SettingRepository
+	auditRepo   domain.SettingAuditRepository
+	cache       domain.SettingCache
+	logger      *zap.Logger
+}
+
+// NewSettingService creates a new setting service
+func NewSettingService(
+	settingRepo domain.SettingRepository,
+	auditRepo domain.SettingAuditRepository,
+	cache domain.SettingCache,
+	logger *zap.Logger,
+) *SettingService {
+	return &SettingService{
+		settingRepo: settingRepo,
+		auditRepo:   auditRepo,
+		cache:       cache,
+		logger:      logger,
+	}
+}
+
+// UpdateSettingRequest represents the request to update a setting
+type UpdateSettingRequest struct {
+	Value       string `json:"value" binding:"required"`
+	ValueType   string `json:"value_type"` // Required when creating a new setting
+	Description string `json:"description"`
+}
+
+// EnsureDefaults creates missing default settings and warms the Redis cache
+func (s *SettingService) EnsureDefaults() error {
+	for i := range defaultSettings {
+		def := defaultSettings[i]
+		_, err := s.settingRepo.Get(def.Scope, def.Key)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to get setting: %w", err)
+		}
+		if err := s.settingRepo.Create(&def); err != nil {
+			return fmt.Errorf("failed to create default setting: %w", err)
+		}
+	}
+
+	// Warm cache so services can read settings right away
+	settings, err := s.settingRepo.List("")
+	if err != nil {
+		return fmt.Errorf("failed to list settings: %w", err)
+	}
+	for _, setting := range settings {
+		if err := s.cache.Set(setting); err != nil {
+			s.logger.Warn("failed to cache setting", zap.String("scope", setting.Scope), zap.String("key", setting.Key), zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// ListSettings lists settings, optionally filtered by scope
+func (s *SettingService) ListSettings(scope string) ([]*domain.Setting, error) {
+	settings, err := s.settingRepo.List(scope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list settings: %w", err)
+	}
+	return settings, nil
+}
+
+// GetSetting retrieves a setting by scope and key
+func (s *SettingService) GetSetting(scope, key string) (*domain.Setting, error) {
+	setting, err := s.settingRepo.Get(scope, key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NotFound("setting not found")
+		}
+		return nil, fmt.Errorf("failed to get setting: %w", err)
+	}
+	return setting, nil
+}
+
+// UpdateSetting creates or updates a setting, writes an audit record,
+// refreshes the cache and broadcasts the change
+// Business rule: only ADMIN can change settings (checked by caller)
+func (s *SettingService) UpdateSetting(scope, key string, req *UpdateSettingRequest, adminUserID uint) (*domain.Setting, error) {
+	setting, err := s.settingRepo.Get(scope, key)
+	isNew := false
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("failed to get setting: %w", err)
+		}
+		if req.ValueType == "" {
+			return nil, domain.Validation("value_type is required for new setting")
+		}
+		setting = &domain.Setting{Scope: scope, Key: key, ValueType: req.ValueType}
+		isNew = true
+	}
+
+	// Type of an existing setting cannot change (services parse it)
+	if !isNew && req.ValueType != "" && req.ValueType != setting.ValueType {
+		return nil, domain.Validation("value_type of existing setting cannot be changed")
+	}
+
+	if err := validateSettingValue(setting.ValueType, req.Value); err != nil {
+		return nil, err
+	}
+
+	oldValue := setting.Value
+	setting.Value = req.Value
+	setting.UpdatedBy = adminUserID
+	if req.Description != "" {
+		setting.Description = req.Description
+	}
+
+	if isNew {
+		err = s.settingRepo.Create(setting)
+	} else {
+		err = s.settingRepo.Update(setting)
+	}
+	if err != nil {
+		s.logger.Error("failed to save setting", zap.Error(err))
+		return nil, fmt.Errorf("failed to save setting: %w", err)
+	}
+
+	// Audit trail
+	if err := s.auditRepo.Create(&domain.SettingAudit{
+		SettingID: setting.ID,
+		Scope:     setting.Scope,
+		Key:       setting.Key,
+		OldValue:  oldValue,
+		NewValue:  setting.Value,
+		ChangedBy: adminUserID,
+	}); err != nil {
+		s.logger.Error("failed to write setting audit", zap.Error(err))
+	}
+
+	// Refresh cache + broadcast (services keep working with old value if this fails)
+	if err := s.cache.Set(setting); err != nil {
+		s.logger.Warn("failed to cache setting", zap.Error(err))
+	}
+	if err := s.cache.Publish(&domain.SettingChangedEvent{
+		Scope:     setting.Scope,
+		Key:       setting.Key,
+		Value:     setting.Value,
+		ValueType: setting.ValueType,
+		UpdatedBy: adminUserID,
+		Timestamp: time.Now(),
+	}); err != nil {
+		s.logger.Warn("failed to publish setting change", zap.Error(err))
+	}
+
+	s.logger.Info("setting updated",
+		zap.String("scope", setting.Scope),
+		zap.String("key", setting.Key),
+		zap.String("old_value", oldValue),
+		zap.String("new_value", setting.Value),
+		zap.Uint("updated_by", adminUserID),
+	)
+
+	return setting, nil
+}
+
+// GetSettingAudit retrieves the change history of a setting with pagination
+func (s *SettingService) GetSettingAudit(scope, key string, page, limit int) ([]*domain.SettingAudit, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	setting, err := s.GetSetting(scope, key)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	audits, total, err := s.auditRepo.ListBySetting(setting.ID, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get setting audit: %w", err)
+	}
+
+	return audits, total, nil
+}
+
+// validateSettingValue checks that value can be parsed as valueType
+func validateSettingValue(valueType, value string) error {
+	var err error
+	switch valueType {
+	case domain.SettingTypeString:
+		return nil
+	case domain.SettingTypeInt:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case domain.SettingTypeFloat:
+		_, err = strconv.ParseFloat(value, 64)
+	case domain.SettingTypeBool:
+		_, err = strconv.ParseBool(value)
+	case domain.SettingTypeJSON:
+		if !json.Valid([]byte(value)) {
+			err = errors.New("invalid JSON")
+		}
+	default:
+		return domain.Validation("invalid value_type: %s", valueType)
+	}
+	if err != nil {
+		return domain.Validation("value is not a valid %s", valueType)
+	}
+	return nil
+}
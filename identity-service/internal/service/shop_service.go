@@ -4,6 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"identity-service/internal/domain"
+	"identity-service/pkg/slug"
+	"strconv"
+	"strings"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -12,21 +15,24 @@ import (
 // ShopService contains the business logic for shop operations
 // Following Clean Architecture: business logic is independent of infrastructure
 type ShopService struct {
-	shopRepo domain.ShopRepository
-	userRepo domain.UserRepository
-	logger   *zap.Logger
+	shopRepo        domain.ShopRepository
+	slugHistoryRepo domain.ShopSlugHistoryRepository
+	userRepo        domain.UserRepository
+	logger          *zap.Logger
 }
 
 // NewShopService creates a new shop service
 func NewShopService(
 	shopRepo domain.ShopRepository,
+	slugHistoryRepo domain.ShopSlugHistoryRepository,
 	userRepo domain.UserRepository,
 	logger *zap.Logger,
 ) *ShopService {
 	return &ShopService{
-		shopRepo: shopRepo,
-		userRepo: userRepo,
-		logger:   logger,
+		shopRepo:        shopRepo,
+		slugHistoryRepo: slugHistoryRepo,
+		userRepo:        userRepo,
+		logger:          logger,
 	}
 }
 
@@ -37,14 +43,25 @@ type CreateShopRequest struct {
 	Description  string `json:"description"`
 	LogoURL      string `json:"logo_url"`
 	CoverURL     string `json:"cover_url"`
+
+	// Ships-from location (optional): province code or name, e.g. "ho-chi-minh" or "Hồ Chí Minh"
+	Province  string   `json:"province" example:"ho-chi-minh"`
+	Latitude  *float64 `json:"latitude" binding:"omitempty,min=-90,max=90" example:"10.7769"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180" example:"106.7009"`
 }
 
 // UpdateShopRequest represents the request to update a shop
 type UpdateShopRequest struct {
 	Name         string `json:"name" binding:"omitempty,min=3,max=100"`
+	Slug         string `json:"slug" binding:"omitempty,max=120,slug"` // Optional - regenerated from name if name changes
 	Description  string `json:"description"`
 	LogoURL      string `json:"logo_url"`
 	CoverURL     string `json:"cover_url"`
+
+	// Ships-from location (optional): province code or name, e.g. "ho-chi-minh" or "Hồ Chí Minh"
+	Province  string   `json:"province" example:"ho-chi-minh"`
+	Latitude  *float64 `json:"latitude" binding:"omitempty,min=-90,max=90" example:"10.7769"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180" example:"106.7009"`
 }
 
 // CreateShop creates a new shop
@@ -57,7 +74,7 @@ func (s *ShopService) CreateShop(req *CreateShopRequest) (*domain.Shop, error) {
 	user, err := s.userRepo.GetByID(req.OwnerUserID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user not found")
+			return nil, domain.NotFound("user not found")
 		}
 		s.logger.Error("failed to get user", zap.Error(err))
 		return nil, fmt.Errorf("failed to get user: %w", err)
@@ -65,24 +82,31 @@ func (s *ShopService) CreateShop(req *CreateShopRequest) (*domain.Shop, error) {
 
 	// Check user status
 	if user.Status != "ACTIVE" {
-		return nil, errors.New("user is not active")
+		return nil, domain.Forbidden("user is not active")
 	}
 
 	// Check user role (only SELLER can create shop)
 	if user.Role != "SELLER" && user.Role != "ADMIN" {
-		return nil, errors.New("only SELLER or ADMIN can create shop")
+		return nil, domain.Forbidden("only SELLER or ADMIN can create shop")
 	}
 
 	// Check if user already has a shop (1 User = 1 Shop)
 	existingShop, err := s.shopRepo.GetByOwnerUserID(req.OwnerUserID)
 	if err == nil && existingShop != nil {
-		return nil, errors.New("user already has a shop")
+		return nil, domain.Conflict("user already has a shop")
+	}
+
+	// Generate unique slug from shop name
+	shopSlug, err := s.generateUniqueSlug(req.Name, 0)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create shop
 	shop := &domain.Shop{
 		OwnerUserID:  req.OwnerUserID,
 		Name:         req.Name,
+		Slug:         shopSlug,
 		Description:  req.Description,
 		LogoURL:      req.LogoURL,
 		CoverURL:     req.CoverURL,
@@ -91,6 +115,9 @@ func (s *ShopService) CreateShop(req *CreateShopRequest) (*domain.Shop, error) {
 		ResponseRate: 0,
 		Status:       "ACTIVE",
 	}
+	if err := applyShopLocation(shop, req.Province, req.Latitude, req.Longitude); err != nil {
+		return nil, err
+	}
 
 	if err := s.shopRepo.Create(shop); err != nil {
 		s.logger.Error("failed to create shop", zap.Error(err))
@@ -109,7 +136,7 @@ func (s *ShopService) UpdateShop(shopID uint, ownerUserID uint, req *UpdateShopR
 	shop, err := s.shopRepo.GetByID(shopID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("shop not found")
+			return nil, domain.NotFound("shop not found")
 		}
 		return nil, fmt.Errorf("failed to get shop: %w", err)
 	}
@@ -117,11 +144,29 @@ func (s *ShopService) UpdateShop(shopID uint, ownerUserID uint, req *UpdateShopR
 	// Validate ownership (only owner or ADMIN can update)
 	user, err := s.userRepo.GetByID(ownerUserID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, domain.NotFound("user not found")
 	}
 
 	if shop.OwnerUserID != ownerUserID && user.Role != "ADMIN" {
-		return nil, errors.New("only shop owner or ADMIN can update shop")
+		return nil, domain.Forbidden("only shop owner or ADMIN can update shop")
+	}
+
+	oldSlug := shop.Slug
+
+	// Update slug: explicit slug wins, otherwise regenerate when name changes
+	switch {
+	case req.Slug != "" && req.Slug != shop.Slug:
+		newSlug, err := s.generateUniqueSlug(req.Slug, shop.ID)
+		if err != nil {
+			return nil, err
+		}
+		shop.Slug = newSlug
+	case req.Name != "" && req.Name != shop.Name:
+		newSlug, err := s.generateUniqueSlug(req.Name, shop.ID)
+		if err != nil {
+			return nil, err
+		}
+		shop.Slug = newSlug
 	}
 
 	// Update fields
@@ -137,12 +182,20 @@ func (s *ShopService) UpdateShop(shopID uint, ownerUserID uint, req *UpdateShopR
 	if req.CoverURL != "" {
 		shop.CoverURL = req.CoverURL
 	}
+	if err := applyShopLocation(shop, req.Province, req.Latitude, req.Longitude); err != nil {
+		return nil, err
+	}
 
 	if err := s.shopRepo.Update(shop); err != nil {
 		s.logger.Error("failed to update shop", zap.Error(err))
 		return nil, fmt.Errorf("failed to update shop: %w", err)
 	}
 
+	// Keep old slug for redirects
+	if oldSlug != "" && oldSlug != shop.Slug {
+		s.recordSlugChange(shop.ID, oldSlug, shop.Slug)
+	}
+
 	s.logger.Info("shop updated", zap.Uint("shop_id", shop.ID))
 
 	return shop, nil
@@ -153,19 +206,44 @@ func (s *ShopService) GetShop(id uint) (*domain.Shop, error) {
 	shop, err := s.shopRepo.GetByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("shop not found")
+			return nil, domain.NotFound("shop not found")
 		}
 		return nil, fmt.Errorf("failed to get shop: %w", err)
 	}
 	return shop, nil
 }
 
+// GetShopBySlug retrieves a shop by slug
+// Returns redirected = true when the slug is an old one (shop was renamed)
+func (s *ShopService) GetShopBySlug(slugValue string) (*domain.Shop, bool, error) {
+	shop, err := s.shopRepo.GetBySlug(slugValue)
+	if err == nil {
+		return shop, false, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, false, fmt.Errorf("failed to get shop: %w", err)
+	}
+
+	// Not a current slug - look up slug history
+	history, err := s.slugHistoryRepo.GetBySlug(slugValue)
+	if err != nil {
+		return nil, false, domain.NotFound("shop not found")
+	}
+
+	shop, err = s.GetShop(history.ShopID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return shop, true, nil
+}
+
 // GetMyShop retrieves the shop of the current user (1 User = 1 Shop)
 func (s *ShopService) GetMyShop(userID uint) (*domain.Shop, error) {
 	shop, err := s.shopRepo.GetByOwnerUserID(userID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("user does not have a shop")
+			return nil, domain.NotFound("user does not have a shop")
 		}
 		return nil, fmt.Errorf("failed to get shop: %w", err)
 	}
@@ -190,17 +268,72 @@ func (s *ShopService) ListShops(page, limit int) ([]*domain.Shop, int64, error)
 	return shops, total, nil
 }
 
+// Shop search pages through at most MaxShopSearchOffset results
+const (
+	DefaultShopSearchLimit = 10
+	MaxShopSearchLimit     = 50
+	MaxShopSearchOffset    = 1000
+)
+
+// ShopSearchResult is one page of shops matching a search
+type ShopSearchResult struct {
+	Shops      []*domain.Shop `json:"shops"`
+	Total      int64          `json:"total"`
+	NextCursor string         `json:"next_cursor,omitempty"` // Pass as cursor for the next page; empty on the last page
+}
+
+// SearchShops finds ACTIVE shops by name, ignoring case and diacritics ("cua hang" matches
+// "Cửa Hàng"). cursor is the next_cursor of the previous page
+func (s *ShopService) SearchShops(query, cursor string, limit int) (*ShopSearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, domain.Validation("q is required")
+	}
+	if limit < 1 {
+		limit = DefaultShopSearchLimit
+	}
+	if limit > MaxShopSearchLimit {
+		limit = MaxShopSearchLimit
+	}
+
+	offset := 0
+	if cursor != "" {
+		var err error
+		if offset, err = strconv.Atoi(cursor); err != nil || offset < 0 || offset > MaxShopSearchOffset {
+			return nil, domain.Validation("invalid cursor")
+		}
+	}
+
+	// A query without letters or digits has an empty slug, which would match every shop
+	slugQuery := slug.Make(query)
+	if slugQuery == "" {
+		slugQuery = query
+	}
+
+	shops, total, err := s.shopRepo.Search(query, slugQuery, offset, limit)
+	if err != nil {
+		s.logger.Error("failed to search shops", zap.Error(err))
+		return nil, fmt.Errorf("failed to search shops: %w", err)
+	}
+
+	result := &ShopSearchResult{Shops: shops, Total: total}
+	if next := offset + len(shops); len(shops) == limit && int64(next) < total && next <= MaxShopSearchOffset {
+		result.NextCursor = strconv.Itoa(next)
+	}
+	return result, nil
+}
+
 // DeleteShop soft deletes a shop (sets status to SUSPENDED)
 // Business rule: Only ADMIN can delete shop
 func (s *ShopService) DeleteShop(shopID uint, userID uint) error {
 	// Validate user is ADMIN
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
-		return errors.New("user not found")
+		return domain.NotFound("user not found")
 	}
 
 	if user.Role != "ADMIN" {
-		return errors.New("only ADMIN can delete shop")
+		return domain.Forbidden("only ADMIN can delete shop")
 	}
 
 	// Soft delete (set status to SUSPENDED)
@@ -220,16 +353,16 @@ func (s *ShopService) UpdateShopStatus(shopID uint, status string, userID uint)
 	// Validate user is ADMIN
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
-		return errors.New("user not found")
+		return domain.NotFound("user not found")
 	}
 
 	if user.Role != "ADMIN" {
-		return errors.New("only ADMIN can update shop status")
+		return domain.Forbidden("only ADMIN can update shop status")
 	}
 
 	// Validate status
 	if status != "ACTIVE" && status != "SUSPENDED" {
-		return errors.New("invalid status: must be ACTIVE or SUSPENDED")
+		return domain.Validation("invalid status: must be ACTIVE or SUSPENDED")
 	}
 
 	if err := s.shopRepo.UpdateStatus(shopID, status); err != nil {
@@ -242,3 +375,99 @@ func (s *ShopService) UpdateShopStatus(shopID uint, status string, userID uint)
 	return nil
 }
 
+
+// BackfillSlugs generates slugs for shops created before slugs existed
+func (s *ShopService) BackfillSlugs() (int, error) {
+	shops, err := s.shopRepo.GetWithoutSlug()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get shops without slug: %w", err)
+	}
+
+	for i, shop := range shops {
+		shopSlug, err := s.generateUniqueSlug(shop.Name, shop.ID)
+		if err != nil {
+			return i, err
+		}
+		shop.Slug = shopSlug
+		if err := s.shopRepo.Update(shop); err != nil {
+			return i, fmt.Errorf("failed to update shop slug: %w", err)
+		}
+	}
+
+	if len(shops) > 0 {
+		s.logger.Info("shop slugs backfilled", zap.Int("count", len(shops)))
+	}
+
+	return len(shops), nil
+}
+
+// applyShopLocation sets the ships-from location of a shop; an empty province or missing
+// coordinates keep the current ones. The province is stored as its code (see domain.Provinces)
+func applyShopLocation(shop *domain.Shop, province string, latitude, longitude *float64) error {
+	if province != "" {
+		code := slug.Make(province)
+		if _, ok := domain.Provinces[code]; !ok {
+			return domain.Validation("unknown province %q", province)
+		}
+		shop.Province = code
+	}
+
+	if (latitude == nil) != (longitude == nil) {
+		return domain.Validation("latitude and longitude must be set together")
+	}
+	if latitude != nil {
+		shop.Latitude = latitude
+		shop.Longitude = longitude
+	}
+	return nil
+}
+
+// generateUniqueSlug builds a slug from source and appends -2, -3, ... until it is unique
+// A slug is taken if another shop uses it, or it is an old slug of another shop
+func (s *ShopService) generateUniqueSlug(source string, shopID uint) (string, error) {
+	base := slug.Make(source)
+	if base == "" {
+		base = "shop" // Name had no usable characters
+	}
+
+	for n := 1; n <= 1000; n++ {
+		candidate := slug.WithSuffix(base, n)
+
+		exists, err := s.shopRepo.ExistsBySlug(candidate, shopID)
+		if err != nil {
+			return "", fmt.Errorf("failed to check slug: %w", err)
+		}
+		if exists {
+			continue
+		}
+
+		history, err := s.slugHistoryRepo.GetBySlug(candidate)
+		if err == nil && history != nil && history.ShopID != shopID {
+			continue
+		}
+
+		return candidate, nil
+	}
+
+	return "", errors.New("could not generate unique slug")
+}
+
+// recordSlugChange stores the old slug in history so old shop URLs keep working
+// Failures are logged only - shop update already succeeded
+func (s *ShopService) recordSlugChange(shopID uint, oldSlug, newSlug string) {
+	// Shop reclaimed one of its own old slugs - it is current again
+	if err := s.slugHistoryRepo.DeleteBySlug(newSlug); err != nil {
+		s.logger.Warn("failed to clean slug history", zap.String("slug", newSlug), zap.Error(err))
+	}
+
+	if err := s.slugHistoryRepo.Create(&domain.ShopSlugHistory{
+		ShopID: shopID,
+		Slug:   oldSlug,
+	}); err != nil {
+		s.logger.Warn("failed to record slug history",
+			zap.Uint("shop_id", shopID),
+			zap.String("old_slug", oldSlug),
+			zap.Error(err),
+		)
+	}
+}
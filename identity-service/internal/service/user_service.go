@@ -1,7 +1,6 @@
 package service
 
 import (
-	"errors"
 	"fmt"
 	"identity-service/internal/domain"
 
@@ -30,7 +29,7 @@ func NewUserService(
 func (s *UserService) GetProfile(userID uint) (*domain.User, error) {
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, domain.NotFound("user not found")
 	}
 
 	// Don't return password hash
@@ -41,7 +40,7 @@ func (s *UserService) GetProfile(userID uint) (*domain.User, error) {
 // UpdateProfile updates a user's profile
 type UpdateProfileRequest struct {
 	FullName    string `json:"full_name"`
-	PhoneNumber string `json:"phone_number"`
+	PhoneNumber string `json:"phone_number" binding:"omitempty,vnphone"`
 	AvatarURL   string `json:"avatar_url"`
 }
 
@@ -49,7 +48,7 @@ func (s *UserService) UpdateProfile(userID uint, req *UpdateProfileRequest) (*do
 	// Get existing user
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return nil, domain.NotFound("user not found")
 	}
 
 	// Update fields
@@ -86,12 +85,12 @@ func (s *UserService) ChangePassword(userID uint, req *ChangePasswordRequest) er
 	// Get user
 	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
-		return errors.New("user not found")
+		return domain.NotFound("user not found")
 	}
 
 	// Verify old password
 	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.OldPassword)); err != nil {
-		return errors.New("invalid old password")
+		return domain.Validation("invalid old password")
 	}
 
 	// Hash new password
@@ -1,11 +1,14 @@
 package database
 
 import (
+	"database/sql"
 	"fmt"
 	"identity-service/config"
 	"log"
 	"sync"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -37,15 +40,23 @@ func GetDB(cfg *config.DatabaseConfig) (*gorm.DB, error) {
 			return
 		}
 
-		sqlDB, err := dbInstance.DB()
+		var sqlDB *sql.DB
+		sqlDB, err = dbInstance.DB()
 		if err != nil {
 			log.Printf("Failed to get sql.DB: %v", err)
 			return
 		}
 
+		// Connection pool (validated by config.Validate)
 		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
 		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
 		sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+		sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+		// Pool usage (open/in use/idle connections, waits) on /metrics
+		if regErr := prometheus.Register(collectors.NewDBStatsCollector(sqlDB, cfg.DBName)); regErr != nil {
+			log.Printf("Failed to register database pool metrics: %v", regErr)
+		}
 
 		log.Println("Database connection established successfully")
 	})
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"errors"
+	"identity-service/pkg/requestid"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// queryStartKey stores the start time of a statement between the before/after callbacks
+const queryStartKey = "query_metrics:start"
+
+// queryDuration is the latency of SQL statements per table and operation
+// Observations made while handling a request carry its request ID as exemplar
+var queryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "db_query_duration_seconds",
+	Help:    "Latency of SQL statements by table and operation.",
+	Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+}, []string{"table", "operation"})
+
+// QueryMetrics is a GORM plugin that times every statement: it exports the latency
+// histogram and logs statements slower than a threshold. Logged SQL keeps its
+// placeholders ($1, $2...) - bound parameters may hold personal data and are never logged
+type QueryMetrics struct {
+	slowThreshold time.Duration
+	logger        *zap.Logger
+}
+
+// NewQueryMetrics creates the plugin; a zero slowThreshold disables slow query logging
+func NewQueryMetrics(slowThreshold time.Duration, logger *zap.Logger) *QueryMetrics {
+	return &QueryMetrics{slowThreshold: slowThreshold, logger: logger}
+}
+
+// Name implements gorm.Plugin
+func (p *QueryMetrics) Name() string {
+	return "query_metrics"
+}
+
+// Initialize implements gorm.Plugin: it wraps the create, query, update, delete, row and raw callbacks
+func (p *QueryMetrics) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("query_metrics:before_create", p.before),
+		cb.Create().After("gorm:create").Register("query_metrics:after_create", p.after("create")),
+		cb.Query().Before("gorm:query").Register("query_metrics:before_query", p.before),
+		cb.Query().After("gorm:query").Register("query_metrics:after_query", p.after("query")),
+		cb.Update().Before("gorm:update").Register("query_metrics:before_update", p.before),
+		cb.Update().After("gorm:update").Register("query_metrics:after_update", p.after("update")),
+		cb.Delete().Before("gorm:delete").Register("query_metrics:before_delete", p.before),
+		cb.Delete().After("gorm:delete").Register("query_metrics:after_delete", p.after("delete")),
+		cb.Row().Before("gorm:row").Register("query_metrics:before_row", p.before),
+		cb.Row().After("gorm:row").Register("query_metrics:after_row", p.after("row")),
+		cb.Raw().Before("gorm:raw").Register("query_metrics:before_raw", p.before),
+		cb.Raw().After("gorm:raw").Register("query_metrics:after_raw", p.after("raw")),
+	)
+}
+
+func (p *QueryMetrics) before(db *gorm.DB) {
+	db.InstanceSet(queryStartKey, time.Now())
+}
+
+func (p *QueryMetrics) after(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(queryStartKey)
+		if !ok {
+			return
+		}
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+		elapsed := time.Since(start)
+
+		table := db.Statement.Table
+		if table == "" {
+			table = "raw" // db.Exec / db.Raw without a model
+		}
+		requestID := requestid.FromContext(db.Statement.Context)
+
+		observer := queryDuration.WithLabelValues(table, operation)
+		if exemplarObserver, ok := observer.(prometheus.ExemplarObserver); ok && requestID != "" {
+			exemplarObserver.ObserveWithExemplar(elapsed.Seconds(), prometheus.Labels{"request_id": requestID})
+		} else {
+			observer.Observe(elapsed.Seconds())
+		}
+
+		if p.slowThreshold > 0 && elapsed >= p.slowThreshold {
+			p.logger.Warn("slow query",
+				zap.String("sql", db.Statement.SQL.String()),
+				zap.Int("params", len(db.Statement.Vars)),
+				zap.String("table", table),
+				zap.String("operation", operation),
+				zap.Duration("duration", elapsed),
+				zap.Int64("rows", db.RowsAffected),
+				zap.String("request_id", requestID),
+				zap.Error(db.Error),
+			)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// Package errorreport sends panics recovered while handling requests to Sentry.
+//
+// Reporting is disabled when sentry.dsn is empty (e.g. local development):
+// panics are then only logged, see middleware.Recovery.
+//
+// Every service keeps its own copy of this package: each image is built from the
+// service's directory alone, so a shared module would not be in the build context.
+// Change all copies together; scripts/check-shared-copies.sh fails when they drift.
+package errorreport
+
+import (
+	"identity-service/config"
+	"net/http"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// Init configures the Sentry client for the service; an empty DSN disables reporting
+func Init(cfg *config.SentryConfig, service string) error {
+	if cfg.DSN == "" {
+		return nil
+	}
+	return sentry.Init(sentry.ClientOptions{
+		Dsn:         cfg.DSN,
+		Environment: cfg.Environment,
+		Release:     cfg.Release,
+		SampleRate:  cfg.SampleRate,
+		ServerName:  service,
+	})
+}
+
+// Flush waits up to timeout for queued reports to be sent (call before exiting)
+func Flush(timeout time.Duration) {
+	sentry.Flush(timeout)
+}
+
+// Request describes the request a panic happened in
+type Request struct {
+	HTTP      *http.Request
+	Route     string
+	RequestID string
+	UserID    string
+}
+
+// ReportPanic sends a recovered panic with the stack of the panicking goroutine and
+// the request context. Cookies and the Authorization header are stripped by the SDK
+// (SendDefaultPII is off). Returns false when reporting is disabled
+func ReportPanic(recovered interface{}, req Request) bool {
+	hub := sentry.CurrentHub()
+	if hub.Client() == nil {
+		return false
+	}
+	hub = hub.Clone()
+	hub.ConfigureScope(func(scope *sentry.Scope) {
+		scope.SetRequest(req.HTTP)
+		scope.SetTag("route", req.Route)
+		if req.RequestID != "" {
+			scope.SetTag("request_id", req.RequestID)
+		}
+		if req.UserID != "" {
+			scope.SetUser(sentry.User{ID: req.UserID})
+		}
+	})
+	return hub.RecoverWithContext(req.HTTP.Context(), recovered) != nil
+}
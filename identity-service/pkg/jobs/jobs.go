@@ -0,0 +1,197 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis layout (ns = "jobs:{service}")
+//   {ns}:job:{id}   String  Job JSON
+//   {ns}:scheduled  ZSET    job id -> run at (unix ms), pending and retrying jobs
+//   {ns}:active     ZSET    job id -> lease deadline (unix ms), jobs being processed
+//   {ns}:dead       ZSET    job id -> failed at (unix ms), jobs out of retries
+//   {ns}:stats      Hash    counters (enqueued, processed, failed, dead)
+
+// Job states
+const (
+	StateScheduled = "scheduled"
+	StateActive    = "active"
+	StateCompleted = "completed"
+	StateDead      = "dead"
+)
+
+const (
+	defaultMaxRetries = 5
+	defaultTimeout    = 5 * time.Minute
+	completedJobTTL   = 24 * time.Hour
+)
+
+// ErrDuplicateJob is returned when a job with the same ID is already enqueued
+var ErrDuplicateJob = errors.New("job already exists")
+
+// ErrJobNotFound is returned when a job does not exist (or has expired)
+var ErrJobNotFound = errors.New("job not found")
+
+// Job is a unit of deferred work stored in Redis
+type Job struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Payload    json.RawMessage `json:"payload"`
+	State      string          `json:"state"`
+	Attempts   int             `json:"attempts"`
+	MaxRetries int             `json:"max_retries"`
+	Timeout    time.Duration   `json:"timeout"`
+	LastError  string          `json:"last_error,omitempty"`
+	RunAt      time.Time       `json:"run_at"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+// Option customizes a job at enqueue time
+type Option func(*Job)
+
+// WithID sets a deterministic job ID (enqueueing the same ID twice returns ErrDuplicateJob)
+func WithID(id string) Option {
+	return func(j *Job) { j.ID = id }
+}
+
+// ProcessAt schedules the job for a specific time
+func ProcessAt(t time.Time) Option {
+	return func(j *Job) { j.RunAt = t }
+}
+
+// ProcessIn schedules the job after a delay
+func ProcessIn(d time.Duration) Option {
+	return func(j *Job) { j.RunAt = time.Now().Add(d) }
+}
+
+// MaxRetries sets how many times a failed job is retried before moving to the dead set
+func MaxRetries(n int) Option {
+	return func(j *Job) { j.MaxRetries = n }
+}
+
+// Timeout sets the per-attempt timeout (also the lease after which a crashed worker's job is retried)
+func Timeout(d time.Duration) Option {
+	return func(j *Job) { j.Timeout = d }
+}
+
+// Client enqueues jobs into a namespace
+type Client struct {
+	client *redis.Client
+	ns     string
+}
+
+// NewClient creates a job client for a namespace (e.g. "product")
+func NewClient(client *redis.Client, namespace string) *Client {
+	return &Client{client: client, ns: "jobs:" + namespace}
+}
+
+// Enqueue stores a job and schedules it (payload is JSON-encoded)
+func (c *Client) Enqueue(ctx context.Context, jobType string, payload interface{}, opts ...Option) (*Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal job payload: %w", err)
+	}
+
+	now := time.Now()
+	job := &Job{
+		Type:       jobType,
+		Payload:    data,
+		State:      StateScheduled,
+		MaxRetries: defaultMaxRetries,
+		Timeout:    defaultTimeout,
+		RunAt:      now,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+	if job.ID == "" {
+		job.ID = newJobID()
+	}
+
+	raw, err := json.Marshal(job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal job: %w", err)
+	}
+
+	created, err := c.client.SetNX(ctx, c.jobKey(job.ID), raw, 0).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to store job: %w", err)
+	}
+	if !created {
+		return nil, ErrDuplicateJob
+	}
+
+	pipe := c.client.TxPipeline()
+	pipe.ZAdd(ctx, c.scheduledKey(), redis.Z{Score: float64(job.RunAt.UnixMilli()), Member: job.ID})
+	pipe.HIncrBy(ctx, c.statsKey(), "enqueued", 1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to schedule job: %w", err)
+	}
+
+	return job, nil
+}
+
+// Cancel removes a scheduled job (no-op if it already ran)
+func (c *Client) Cancel(ctx context.Context, id string) error {
+	removed, err := c.client.ZRem(ctx, c.scheduledKey(), id).Result()
+	if err != nil {
+		return fmt.Errorf("failed to cancel job: %w", err)
+	}
+	if removed > 0 {
+		return c.client.Del(ctx, c.jobKey(id)).Err()
+	}
+	return nil
+}
+
+func (c *Client) jobKey(id string) string { return c.ns + ":job:" + id }
+func (c *Client) scheduledKey() string    { return c.ns + ":scheduled" }
+func (c *Client) activeKey() string       { return c.ns + ":active" }
+func (c *Client) deadKey() string         { return c.ns + ":dead" }
+func (c *Client) statsKey() string        { return c.ns + ":stats" }
+func (c *Client) periodicKey(name string) string {
+	return c.ns + ":periodic:" + name
+}
+
+// getJob loads a job by ID
+func (c *Client) getJob(ctx context.Context, id string) (*Job, error) {
+	raw, err := c.client.Get(ctx, c.jobKey(id)).Bytes()
+	if err == redis.Nil {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var job Job
+	if err := json.Unmarshal(raw, &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal job: %w", err)
+	}
+	return &job, nil
+}
+
+// saveJob overwrites a job (ttl 0 = keep)
+func (c *Client) saveJob(ctx context.Context, job *Job, ttl time.Duration) error {
+	job.UpdatedAt = time.Now()
+	raw, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job: %w", err)
+	}
+	return c.client.Set(ctx, c.jobKey(job.ID), raw, ttl).Err()
+}
+
+func newJobID() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
@@ -7,12 +7,21 @@ import (
 	"go.uber.org/zap/zapcore"
 )
 
+// level is shared by every logger built here so it can be changed at runtime
+var level = zap.NewAtomicLevel()
+
+// Level returns the adjustable level of loggers created by NewLogger
+func Level() zap.AtomicLevel {
+	return level
+}
+
 // NewLogger creates a new Zap logger based on configuration
 func NewLogger(cfg *config.LoggingConfig) (*zap.Logger, error) {
-	var level zapcore.Level
-	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
-		level = zapcore.InfoLevel
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(cfg.Level)); err != nil {
+		lvl = zapcore.InfoLevel
 	}
+	level.SetLevel(lvl)
 
 	var encoderConfig zapcore.EncoderConfig
 	if cfg.Encoding == "json" {
@@ -26,7 +35,7 @@ func NewLogger(cfg *config.LoggingConfig) (*zap.Logger, error) {
 	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 
 	zapConfig := zap.Config{
-		Level:            zap.NewAtomicLevelAt(level),
+		Level:            level,
 		Development:      cfg.Encoding == "console",
 		Encoding:         cfg.Encoding,
 		EncoderConfig:    encoderConfig,
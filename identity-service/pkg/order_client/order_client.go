@@ -0,0 +1,79 @@
+package order_client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"identity-service/pkg/serviceauth"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// OrderClient handles communication with Order Service
+type OrderClient struct {
+	baseURL    string
+	httpClient *http.Client
+	signer     *serviceauth.Signer
+}
+
+// NewOrderClient creates a new order client; signer signs calls to internal order-service endpoints
+func NewOrderClient(baseURL string, timeout time.Duration, signer *serviceauth.Signer) *OrderClient {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &OrderClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		signer: signer,
+	}
+}
+
+// ShopGMV is the gross merchandise value of a shop's orders in a period
+type ShopGMV struct {
+	ShopID     uint    `json:"shop_id"`
+	OrderCount int64   `json:"order_count"`
+	GMV        float64 `json:"gmv"`
+}
+
+// ShopGMV returns the GMV of every shop with orders placed from the first to the last
+// day (included), keyed by shop ID; shops without orders are missing
+func (c *OrderClient) ShopGMV(ctx context.Context, from, to time.Time) (map[uint]ShopGMV, error) {
+	query := url.Values{}
+	query.Set("from", from.Format("2006-01-02"))
+	query.Set("to", to.Format("2006-01-02"))
+	reqURL := fmt.Sprintf("%s/api/v1/orders/shops/gmv?%s", c.baseURL, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build order service request: %w", err)
+	}
+	c.signer.Sign(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call order service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("order service returned error: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var response struct {
+		Shops []ShopGMV `json:"shops"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode shop GMV: %w", err)
+	}
+
+	gmv := make(map[uint]ShopGMV, len(response.Shops))
+	for _, shop := range response.Shops {
+		gmv[shop.ShopID] = shop
+	}
+	return gmv, nil
+}
@@ -0,0 +1,75 @@
+package product_client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"identity-service/pkg/serviceauth"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ProductClient handles communication with Product Service
+type ProductClient struct {
+	baseURL    string
+	httpClient *http.Client
+	signer     *serviceauth.Signer
+}
+
+// NewProductClient creates a new product client; signer signs calls to internal product-service endpoints
+func NewProductClient(baseURL string, timeout time.Duration, signer *serviceauth.Signer) *ProductClient {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &ProductClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		signer: signer,
+	}
+}
+
+// ShopRating is the rating aggregate of the reviews of a shop's products
+type ShopRating struct {
+	ShopID      uint    `json:"shop_id"`
+	RatingAvg   float64 `json:"rating_avg"`
+	RatingCount int     `json:"rating_count"`
+}
+
+// ShopRatings returns the rating of every shop with reviews, keyed by shop ID; shops
+// without reviews are missing
+func (c *ProductClient) ShopRatings(ctx context.Context) (map[uint]ShopRating, error) {
+	url := fmt.Sprintf("%s/api/v1/shops/ratings", c.baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build product service request: %w", err)
+	}
+	c.signer.Sign(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call product service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("product service returned error: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var response struct {
+		Shops []ShopRating `json:"shops"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode shop ratings: %w", err)
+	}
+
+	ratings := make(map[uint]ShopRating, len(response.Shops))
+	for _, shop := range response.Shops {
+		ratings[shop.ShopID] = shop
+	}
+	return ratings, nil
+}
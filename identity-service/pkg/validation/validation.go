@@ -0,0 +1,163 @@
+// Package validation turns request binding errors into field-level messages.
+//
+// A request that fails its binding tags is answered 400 with one entry per field:
+//
+//	{"error": "validation failed", "fields": [{"field": "items[0].quantity", "rule": "min", "message": "must be at least 1"}]}
+//
+// Field names are the JSON names the client sent. Besides the validator's built-in rules,
+// Register adds:
+//
+//	vnphone - Vietnamese phone number: 0912345678, +84912345678 or a 02x landline
+//	slug    - lowercase letters and digits separated by single hyphens ("ao-thun-nam")
+//
+// The gateway and each service carry an identical copy, since their images are built
+// from their own directories; keep them in sync (scripts/check-shared-copies.sh).
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	vnPhonePattern = regexp.MustCompile(`^(\+84|0)([35789][0-9]{8}|2[0-9]{9})$`)
+	slugPattern    = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+)
+
+// FieldError describes why one field of a request was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Register configures Gin's validator: fields are reported by their JSON name and the
+// vnphone and slug rules become available in binding tags. Call once before serving
+func Register() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("gin validator is not go-playground/validator")
+	}
+	v.RegisterTagNameFunc(jsonFieldName)
+	return errors.Join(
+		v.RegisterValidation("vnphone", matches(vnPhonePattern)),
+		v.RegisterValidation("slug", matches(slugPattern)),
+	)
+}
+
+// Errors converts a binding error into field errors. It returns nil when err is not
+// about individual fields (e.g. malformed JSON), the caller then reports err itself
+func Errors(err error) []FieldError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fields = append(fields, FieldError{
+				Field:   fieldPath(fe.Namespace()),
+				Rule:    fe.Tag(),
+				Message: message(fe),
+			})
+		}
+		return fields
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: "must be " + typeName(typeErr.Type),
+		}}
+	}
+	return nil
+}
+
+func matches(pattern *regexp.Regexp) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		return pattern.MatchString(fl.Field().String())
+	}
+}
+
+// jsonFieldName names struct fields after their json tag ("-" hides the field)
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
+
+// fieldPath drops the request struct name from a namespace:
+// "CreateOrderRequest.items[0].quantity" -> "items[0].quantity"
+func fieldPath(namespace string) string {
+	if _, path, ok := strings.Cut(namespace, "."); ok {
+		return path
+	}
+	return namespace
+}
+
+func message(fe validator.FieldError) string {
+	param := fe.Param()
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min", "gte":
+		return "must be at least " + param + sizeUnit(fe)
+	case "max", "lte":
+		return "must be at most " + param + sizeUnit(fe)
+	case "len":
+		return "must be exactly " + param + sizeUnit(fe)
+	case "gt":
+		return "must be greater than " + param + sizeUnit(fe)
+	case "lt":
+		return "must be less than " + param + sizeUnit(fe)
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(param), ", ")
+	case "email":
+		return "must be a valid email address"
+	case "url", "http_url":
+		return "must be a valid URL"
+	case "vnphone":
+		return "must be a Vietnamese phone number, e.g. 0912345678 or +84912345678"
+	case "slug":
+		return "must contain only lowercase letters, digits and single hyphens"
+	}
+	return "failed the " + fe.Tag() + " rule"
+}
+
+// sizeUnit tells what min/max count for strings and collections
+func sizeUnit(fe validator.FieldError) string {
+	switch fe.Kind() {
+	case reflect.String:
+		return " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return " items"
+	}
+	return ""
+}
+
+func typeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "a list"
+	}
+	return "an object"
+}
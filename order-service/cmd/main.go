@@ -8,15 +8,26 @@ import (
 	"order-service/config"
 	"order-service/internal/domain"
 	"order-service/internal/handler"
+	"order-service/internal/middleware"
 	"order-service/internal/repository/kafka"
 	"order-service/internal/repository/postgres"
 	"order-service/internal/repository/redis"
 	"order-service/internal/router"
 	"order-service/internal/service"
+	"order-service/pkg/audience_client"
 	"order-service/pkg/database"
+	"order-service/pkg/errorreport"
+	"order-service/pkg/jobs"
 	"order-service/pkg/logger"
+	"order-service/pkg/notification_prefs"
 	"order-service/pkg/product_client"
 	redisClient "order-service/pkg/redis"
+	"order-service/pkg/serviceauth"
+	"order-service/pkg/settings"
+	"order-service/pkg/shop_client"
+	"order-service/pkg/shutdown"
+	"order-service/pkg/taskqueue"
+	"order-service/pkg/validation"
 	"os"
 	"os/signal"
 	"syscall"
@@ -66,6 +77,17 @@ func main() {
 
 	appLogger.Info("Starting Order Service...")
 
+	// Report recovered panics to Sentry (disabled without a DSN)
+	if err := errorreport.Init(&cfg.Sentry, "order-service"); err != nil {
+		appLogger.Warn("Failed to initialize error reporting, panics are only logged", zap.Error(err))
+	}
+	defer errorreport.Flush(2 * time.Second)
+
+	// Field-level binding errors and the vnphone/slug rules
+	if err := validation.Register(); err != nil {
+		appLogger.Fatal("Failed to register request validators", zap.Error(err))
+	}
+
 	// Set Gin mode based on config
 	gin.SetMode(cfg.Server.Mode)
 
@@ -76,10 +98,26 @@ func main() {
 	}
 	defer database.CloseDB()
 
+	// Per-table query latency metrics (/metrics) and slow query logging
+	if err := db.Use(database.NewQueryMetrics(cfg.Database.SlowQueryThreshold, appLogger)); err != nil {
+		appLogger.Fatal("Failed to register query metrics", zap.Error(err))
+	}
+
+	// Partition the order tables by month before AutoMigrate (which then adds their indexes)
+	if err := postgres.MigrateOrderPartitions(db, cfg.Database.PartitionMonthsAhead); err != nil {
+		appLogger.Fatal("Failed to partition order tables", zap.Error(err))
+	}
+
 	// Run database migrations
-	if err := db.AutoMigrate(&domain.Order{}, &domain.OrderItem{}); err != nil {
+	if err := db.AutoMigrate(&domain.Order{}, &domain.OrderItem{}, &domain.PayoutBatch{}, &domain.CartBackup{}, &domain.ProductSubscription{}, &domain.Notification{}, &domain.Dispute{}, &domain.DisputeEvidence{}, &domain.PayoutAdjustment{}, &domain.ArchivedOrder{}, &domain.OrderAdjustment{}, &domain.OrderStatusHistory{}, &domain.JournalEntry{}, &domain.JournalLine{}, &domain.Voucher{}, &domain.VoucherRedemption{}, &domain.Announcement{}); err != nil {
 		appLogger.Fatal("Failed to run migrations", zap.Error(err))
 	}
+	if err := postgres.MigrateOrderNumbers(db); err != nil {
+		appLogger.Fatal("Failed to migrate order numbers", zap.Error(err))
+	}
+	if err := postgres.BackfillDisputeRefunds(db); err != nil {
+		appLogger.Fatal("Failed to backfill dispute refund adjustments", zap.Error(err))
+	}
 	appLogger.Info("Database migrations completed")
 
 	// Initialize Redis client (Singleton)
@@ -89,51 +127,224 @@ func main() {
 	}
 	defer redisClient.CloseClient()
 
-	// Initialize Kafka event publisher
+	// Initialize Kafka event publisher (one topic per event type)
+	kafkaTopics := kafka.Topics{Prefix: cfg.Kafka.TopicPrefix, Overrides: cfg.Kafka.Topics}
 	appLogger.Info("Initializing Kafka event publisher",
 		zap.Strings("brokers", cfg.Kafka.Brokers),
-		zap.String("topic", cfg.Kafka.TopicOrderCreated),
-	)
-	eventPublisher := kafka.NewEventPublisher(
-		cfg.Kafka.Brokers,
-		cfg.Kafka.TopicOrderCreated,
-		cfg.Kafka.WriteTimeout,
-		cfg.Kafka.RequiredAcks,
+		zap.String("topic_prefix", cfg.Kafka.TopicPrefix),
 	)
+	eventPublisher := kafka.NewEventPublisher(kafka.PublisherOptions{
+		Brokers:       cfg.Kafka.Brokers,
+		Topics:        kafkaTopics,
+		WriteTimeout:  cfg.Kafka.WriteTimeout,
+		RequiredAcks:  cfg.Kafka.RequiredAcks,
+		MaxRetries:    cfg.Kafka.MaxRetries,
+		RetryBackoff:  cfg.Kafka.RetryBackoff,
+		BufferKey:     "events:buffer:order",
+		FlushInterval: cfg.Kafka.FlushInterval,
+	}, redisClientInstance, appLogger)
 	if eventPublisher == nil {
 		appLogger.Fatal("Failed to create Kafka event publisher")
 	}
-	defer eventPublisher.Close()
+	// Redeliver events buffered during broker outages
+	publisherCtx, stopPublisher := context.WithCancel(context.Background())
+	defer stopPublisher()
+	go eventPublisher.Start(publisherCtx)
 	appLogger.Info("Kafka event publisher initialized successfully")
 
+	// Shutdown coordinator drains the service in order on exit
+	coordinator := shutdown.NewCoordinator(appLogger)
+
 	// Initialize repositories
-	cartRepo := redis.NewCartRepository(redisClientInstance, appLogger)
 	orderRepo := postgres.NewOrderRepository(db)
+	payoutRepo := postgres.NewPayoutRepository(db)
+	cartBackupRepo := postgres.NewCartBackupRepository(db)
+	subscriptionRepo := postgres.NewSubscriptionRepository(db)
+	notificationRepo := postgres.NewNotificationRepository(db)
+	disputeRepo := postgres.NewDisputeRepository(db)
+	voucherRepo := postgres.NewVoucherRepository(db)
+	announcementRepo := postgres.NewAnnouncementRepository(db)
+
+	cartPolicy := redis.CartPolicy{TTL: cfg.Cart.TTL, Sliding: cfg.Cart.SlidingTTL}
+	if cfg.Cart.BackupEnabled {
+		cartPolicy.Backups = cartBackupRepo
+	}
+	cartRepo := redis.NewCartRepository(redisClientInstance, cartPolicy, appLogger)
+
+	// Calls to internal endpoints of other services are signed (X-Service-Token)
+	signer := serviceauth.NewSigner(cfg.InternalAuth.ServiceName, cfg.InternalAuth.Secret)
 
 	// Initialize Product Service client
-	productClientRaw := product_client.NewProductClient(cfg.ProductService.BaseURL)
+	productClientRaw := product_client.NewProductClient(product_client.Options{
+		BaseURL:          cfg.ProductService.BaseURL,
+		Timeout:          cfg.ProductService.Timeout,
+		MaxRetries:       cfg.ProductService.MaxRetries,
+		RetryBackoff:     cfg.ProductService.RetryBackoff,
+		BreakerThreshold: cfg.ProductService.BreakerThreshold,
+		BreakerCooldown:  cfg.ProductService.BreakerCooldown,
+		Signer:           signer,
+	}, appLogger)
 
 	// Create adapters for CartService and OrderService (different DTOs)
 	cartProductClient := &service.CartProductClientAdapter{Client: productClientRaw}
 	orderProductClient := &service.OrderProductClientAdapter{Client: productClientRaw}
 
-	log.Printf("[DEBUG] Product Service base URL: %s\n", cfg.ProductService.BaseURL)
-
 	appLogger.Info("Product Service client initialized",
 		zap.String("base_url", cfg.ProductService.BaseURL),
 		zap.Duration("timeout", cfg.ProductService.Timeout),
+		zap.Int("max_retries", cfg.ProductService.MaxRetries),
+		zap.Int("breaker_threshold", cfg.ProductService.BreakerThreshold),
 	)
 
+	// Identity Service client (shop name/logo snapshotted on new orders, shop owners for disputes)
+	orderShopClient := &service.OrderShopClientAdapter{
+		Client: shop_client.NewShopClient(cfg.IdentityService.BaseURL, cfg.IdentityService.Timeout),
+	}
+
+	// Identity Service audience lookups (announcement recipients)
+	audienceClient := audience_client.NewAudienceClient(cfg.IdentityService.BaseURL, cfg.IdentityService.Timeout, signer)
+
 	// Initialize services
-	cartService := service.NewCartService(cartRepo, cartProductClient, appLogger)
-	orderService := service.NewOrderService(orderRepo, cartRepo, orderProductClient, eventPublisher, appLogger)
+	// Admin-managed settings (written by identity-service, shared Redis)
+	settingsClient := settings.NewClient(redisClientInstance, appLogger)
+	settingsCtx, stopSettings := context.WithCancel(context.Background())
+	defer stopSettings()
+	go settingsClient.Start(settingsCtx)
+
+	// Bounded worker pool for side effects (event publishing)
+	taskPool := taskqueue.NewPool(taskqueue.Options{
+		Workers:    cfg.Async.Workers,
+		QueueSize:  cfg.Async.QueueSize,
+		MaxRetries: cfg.Async.MaxRetries,
+		Backoff:    cfg.Async.RetryBackoff,
+		Timeout:    cfg.Async.TaskTimeout,
+	}, appLogger)
+
+	cartService := service.NewCartService(cartRepo, cartProductClient, settingsClient, appLogger)
+	// Notifications honour the preferences users manage in identity-service
+	notificationPrefs := notification_prefs.NewClient(redisClientInstance, appLogger)
+	notifier := service.NewPreferenceAwareNotifier(eventPublisher, notificationPrefs, appLogger)
+
+	orderStatusService := service.NewOrderStatusService(orderRepo, orderShopClient, orderProductClient, eventPublisher, taskPool, appLogger)
+	voucherService := service.NewVoucherService(voucherRepo, orderShopClient, taskPool, appLogger)
+	orderService := service.NewOrderService(orderRepo, cartRepo, orderProductClient, eventPublisher, notifier, settingsClient, taskPool, redis.NewOrderNumberGenerator(redisClientInstance), orderShopClient, orderStatusService, voucherService, appLogger)
+	payoutService := service.NewPayoutService(orderRepo, payoutRepo, appLogger)
+	accountingService := service.NewAccountingService(orderRepo, appLogger)
+	cartBackupService := service.NewCartBackupService(cartRepo, cartBackupRepo, cfg.Cart.TTL, appLogger)
+	retentionService := service.NewRetentionService(orderRepo, settingsClient, appLogger)
+	archiveService := service.NewArchiveService(orderRepo, settingsClient, appLogger)
+	partitionService := service.NewPartitionService(orderRepo, cfg.Database.PartitionMonthsAhead, appLogger)
+	subscriptionService := service.NewSubscriptionService(subscriptionRepo, productClientRaw, notifier, appLogger)
+	inboxService := service.NewInboxService(notificationRepo, appLogger)
+	disputeService := service.NewDisputeService(disputeRepo, orderRepo, orderShopClient, eventPublisher, appLogger)
+	// Announcements reach every recipient's inbox, so they bypass the notifier's opt-out
+	// filter and pick the other channels from the "promotions" preferences themselves
+	announcementService := service.NewAnnouncementService(announcementRepo, notificationRepo, eventPublisher, notificationPrefs, audienceClient, productClientRaw, jobs.NewClient(redisClientInstance, "order"), service.AnnouncementOptions{
+		Rate:      cfg.Announcements.Rate,
+		BatchSize: cfg.Announcements.BatchSize,
+	}, appLogger)
+
+	// Background jobs (Redis-backed, namespace "order")
+	// Payout batching runs hourly and is idempotent per shop/day
+	jobWorker := jobs.NewWorker(redisClientInstance, "order", 5, appLogger)
+	jobWorker.Register(service.JobTypePayoutBatch, payoutService.HandleDailyBatch)
+	jobWorker.Every("payout_daily_batch", time.Hour, service.JobTypePayoutBatch, nil)
+	// Cart backup sync copies carts changed since the last run to Postgres
+	if cfg.Cart.BackupEnabled {
+		jobWorker.Register(service.JobTypeCartBackupSync, cartBackupService.HandleSync)
+		jobWorker.Every("cart_backup_sync", cfg.Cart.BackupInterval, service.JobTypeCartBackupSync, nil)
+	}
+	// Data retention anonymizes old finished orders (admin settings order.retention_*)
+	jobWorker.Register(service.JobTypeOrderRetention, retentionService.HandleRetention)
+	jobWorker.Every("order_retention", 24*time.Hour, service.JobTypeOrderRetention, nil)
+	// Archival moves old finished orders out of shop_order (admin settings order.archive_*)
+	jobWorker.Register(service.JobTypeOrderArchive, archiveService.HandleArchive)
+	jobWorker.Every("order_archive", 24*time.Hour, service.JobTypeOrderArchive, nil)
+	// Monthly order partitions are created ahead of new orders
+	jobWorker.Register(service.JobTypeOrderPartitions, partitionService.HandlePartitions)
+	jobWorker.Every("order_partitions", 24*time.Hour, service.JobTypeOrderPartitions, nil)
+	// Announcement fan-out, enqueued per announcement (and per slice of its audience)
+	jobWorker.Register(service.JobTypeAnnouncementFanOut, announcementService.HandleFanOut)
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	jobsDone := make(chan struct{})
+	go func() {
+		jobWorker.Start(jobsCtx)
+		close(jobsDone)
+	}()
+
+	// Product events: flag cart lines whose price changed since they were added and
+	// notify price drop / back in stock subscribers
+	productEventConsumer := kafka.NewProductEventConsumer(
+		cfg.Kafka.Brokers,
+		[]string{kafkaTopics.For("product_updated"), kafkaTopics.For("product_item_updated")},
+		cfg.Kafka.ConsumerGroup,
+		[]domain.ProductEventHandler{cartService, subscriptionService},
+		appLogger,
+	)
+	consumerCtx, stopConsumer := context.WithCancel(context.Background())
+	consumerDone := make(chan struct{})
+	go func() {
+		productEventConsumer.Start(consumerCtx)
+		close(consumerDone)
+	}()
+
+	// Notification events: persist them in the users' in-app inbox (own consumer group,
+	// so the email/SMS/push dispatchers still receive every event)
+	inboxConsumer := kafka.NewNotificationEventConsumer(
+		cfg.Kafka.Brokers,
+		[]string{
+			kafkaTopics.For(domain.EventNotificationPriceDrop),
+			kafkaTopics.For(domain.EventNotificationBackInStock),
+			kafkaTopics.For(domain.EventNotificationDigitalCodesIssued),
+			kafkaTopics.For(domain.EventNotificationAnnouncement),
+		},
+		cfg.Kafka.InboxGroup,
+		inboxService,
+		appLogger,
+	)
+	inboxCtx, stopInbox := context.WithCancel(context.Background())
+	inboxDone := make(chan struct{})
+	go func() {
+		inboxConsumer.Start(inboxCtx)
+		close(inboxDone)
+	}()
+
+	// Payment events: payment_succeeded marks the order paid, payment_failed cancels it
+	paymentConsumer := kafka.NewPaymentEventConsumer(
+		cfg.Kafka.Brokers,
+		[]string{kafkaTopics.For(domain.EventPaymentSucceeded), kafkaTopics.For(domain.EventPaymentFailed)},
+		cfg.Kafka.PaymentGroup,
+		orderService,
+		appLogger,
+	)
+	paymentCtx, stopPayments := context.WithCancel(context.Background())
+	paymentDone := make(chan struct{})
+	go func() {
+		paymentConsumer.Start(paymentCtx)
+		close(paymentDone)
+	}()
 
 	// Initialize handlers
 	cartHandler := handler.NewCartHandler(cartService, appLogger)
-	orderHandler := handler.NewOrderHandler(orderService, appLogger)
+	orderHandler := handler.NewOrderHandler(orderService, orderStatusService, appLogger)
+	jobHandler := handler.NewJobHandler(jobs.NewInspector(redisClientInstance, "order"), appLogger)
+	logLevelHandler := handler.NewLogLevelHandler(logger.Level(), appLogger)
+	taskHandler := handler.NewTaskHandler(taskPool, eventPublisher, appLogger)
+	subscriptionHandler := handler.NewSubscriptionHandler(subscriptionService, appLogger)
+	notificationHandler := handler.NewNotificationHandler(inboxService, appLogger)
+	disputeHandler := handler.NewDisputeHandler(disputeService, appLogger)
+	accountingHandler := handler.NewAccountingHandler(accountingService, appLogger)
+	voucherHandler := handler.NewVoucherHandler(voucherService, appLogger)
+	announcementHandler := handler.NewAnnouncementHandler(announcementService, appLogger)
+
+	// Internal endpoints only accept calls signed by trusted services (disabled = unchecked)
+	var serviceAuth *serviceauth.Verifier
+	if cfg.InternalAuth.Enabled {
+		serviceAuth = serviceauth.NewVerifier(cfg.InternalAuth.TrustedServices, cfg.InternalAuth.MaxAge)
+	}
 
 	// Setup router
-	router := router.SetupRouter(cartHandler, orderHandler)
+	router := router.SetupRouter(cartHandler, orderHandler, jobHandler, logLevelHandler, taskHandler, subscriptionHandler, notificationHandler, disputeHandler, accountingHandler, voucherHandler, announcementHandler, serviceAuth, middleware.Recovery(appLogger), middleware.FaultInjection(&cfg.FaultInjection, "order-service", appLogger), middleware.ImpersonationLogger(appLogger))
 
 	// Create HTTP server
 	srv := &http.Server{
@@ -158,13 +369,54 @@ func main() {
 
 	appLogger.Info("Shutting down server...")
 
-	// Graceful shutdown
+	// Drain in order: stop accepting requests (in-flight orders finish publishing),
+	// stop background jobs, wait for async work, then flush and close the Kafka writer
+	coordinator.OnShutdown("http server", srv.Shutdown)
+	coordinator.OnShutdown("background jobs", func(ctx context.Context) error {
+		stopJobs()
+		select {
+		case <-jobsDone:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	coordinator.OnShutdown("product event consumer", func(ctx context.Context) error {
+		stopConsumer()
+		select {
+		case <-consumerDone:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		return productEventConsumer.Close()
+	})
+	coordinator.OnShutdown("notification inbox consumer", func(ctx context.Context) error {
+		stopInbox()
+		select {
+		case <-inboxDone:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		return inboxConsumer.Close()
+	})
+	coordinator.OnShutdown("payment event consumer", func(ctx context.Context) error {
+		stopPayments()
+		select {
+		case <-paymentDone:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		return paymentConsumer.Close()
+	})
+	coordinator.OnShutdown("async tasks", taskPool.Shutdown)
+	coordinator.OnShutdown("kafka publisher", func(context.Context) error {
+		stopPublisher()
+		return eventPublisher.Close()
+	})
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
-
-	if err := srv.Shutdown(ctx); err != nil {
-		appLogger.Error("Server forced to shutdown", zap.Error(err))
-	}
+	coordinator.Shutdown(ctx)
 
 	appLogger.Info("Server exited gracefully")
 }
@@ -17,21 +17,80 @@ type Config struct {
 	Kafka          KafkaConfig
 	Logging        LoggingConfig
 	ProductService ProductServiceConfig
+	Async          AsyncConfig
+	Cart           CartConfig
+
+	IdentityService IdentityServiceConfig `mapstructure:"identity_service"`
+	InternalAuth    InternalAuthConfig    `mapstructure:"internal_auth"`
+	Sentry          SentryConfig          `mapstructure:"sentry"`
+	FaultInjection  FaultInjectionConfig  `mapstructure:"fault_injection"`
+	Announcements   AnnouncementConfig    `mapstructure:"announcements"`
+}
+
+// CartConfig holds cart expiry and Postgres backup configuration
+type CartConfig struct {
+	TTL            time.Duration `mapstructure:"ttl"`             // Cart lifetime since last activity
+	SlidingTTL     bool          `mapstructure:"sliding_ttl"`     // Reads extend the TTL too (not only writes)
+	BackupEnabled  bool          `mapstructure:"backup_enabled"`  // Back up carts to Postgres and restore them if Redis loses them
+	BackupInterval time.Duration `mapstructure:"backup_interval"` // How often changed carts are synced to Postgres
+}
+
+// AsyncConfig sizes the worker pool for side effects (event publishing)
+type AsyncConfig struct {
+	Workers      int           `mapstructure:"workers"`
+	QueueSize    int           `mapstructure:"queue_size"`
+	MaxRetries   int           `mapstructure:"max_retries"`
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"`
+	TaskTimeout  time.Duration `mapstructure:"task_timeout"`
+}
+
+// AnnouncementConfig throttles the fan-out of admin announcements
+type AnnouncementConfig struct {
+	Rate      int `mapstructure:"rate"`       // Notification events published per second
+	BatchSize int `mapstructure:"batch_size"` // Recipients resolved (and progress saved) at a time
 }
 
 // ProductServiceConfig holds Product Service client configuration
 type ProductServiceConfig struct {
+	BaseURL          string        `mapstructure:"base_url"`
+	Timeout          time.Duration `mapstructure:"timeout"`
+	MaxRetries       int           `mapstructure:"max_retries"`
+	RetryBackoff     time.Duration `mapstructure:"retry_backoff"`
+	BreakerThreshold int           `mapstructure:"breaker_threshold"`
+	BreakerCooldown  time.Duration `mapstructure:"breaker_cooldown"`
+}
+
+// IdentityServiceConfig holds Identity Service client configuration (shop info)
+type IdentityServiceConfig struct {
 	BaseURL string        `mapstructure:"base_url"`
 	Timeout time.Duration `mapstructure:"timeout"`
 }
 
+// InternalAuthConfig holds service-to-service authentication with signed service tokens
+type InternalAuthConfig struct {
+	Enabled         bool              `mapstructure:"enabled"`          // false = internal endpoints accept unsigned calls
+	ServiceName     string            `mapstructure:"service_name"`     // Name this service signs its outgoing calls with
+	Secret          string            `mapstructure:"secret"`           // Signing secret of this service
+	MaxAge          time.Duration     `mapstructure:"max_age"`          // Lifetime of accepted tokens
+	TrustedServices map[string]string `mapstructure:"trusted_services"` // Calling service -> its signing secret
+}
+
 // KafkaConfig holds Kafka configuration
 type KafkaConfig struct {
-	Brokers           []string      `mapstructure:"brokers"`
-	TopicOrderCreated string        `mapstructure:"topic_order_created"`
-	WriteTimeout      time.Duration `mapstructure:"write_timeout"`
-	ReadTimeout       time.Duration `mapstructure:"read_timeout"`
-	RequiredAcks      int           `mapstructure:"required_acks"`
+	Brokers       []string          `mapstructure:"brokers"`
+	TopicPrefix   string            `mapstructure:"topic_prefix"` // One topic per event type: <prefix>order.created, ...
+	Topics        map[string]string `mapstructure:"topics"`       // Event type -> topic override
+	ConsumerGroup string            `mapstructure:"consumer_group"`
+	InboxGroup    string            `mapstructure:"inbox_consumer_group"`   // Notification events -> in-app inbox
+	PaymentGroup  string            `mapstructure:"payment_consumer_group"` // Payment events -> order paid/cancelled
+	WriteTimeout  time.Duration     `mapstructure:"write_timeout"`
+	ReadTimeout   time.Duration     `mapstructure:"read_timeout"`
+	RequiredAcks  int               `mapstructure:"required_acks"`
+
+	// Publisher retry and Redis buffer (events that still fail are redelivered later)
+	MaxRetries    int           `mapstructure:"max_retries"`
+	RetryBackoff  time.Duration `mapstructure:"retry_backoff"`
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
 }
 
 // ServerConfig holds HTTP server configuration
@@ -44,25 +103,64 @@ type ServerConfig struct {
 
 // DatabaseConfig holds PostgreSQL connection configuration
 type DatabaseConfig struct {
-	Host            string
-	Port            int
-	User            string
-	Password        string
-	DBName          string
-	SSLMode         string
-	MaxOpenConns    int
-	MaxIdleConns    int
-	ConnMaxLifetime time.Duration
+	Host     string
+	Port     int
+	User     string
+	Password string
+	DBName   string
+	SSLMode  string
+
+	// Connection pool (database/sql)
+	MaxOpenConns    int           `mapstructure:"max_open_conns"`
+	MaxIdleConns    int           `mapstructure:"max_idle_conns"`     // At most MaxOpenConns
+	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`  // Recycle connections (e.g. after a failover)
+	ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time"` // Close idle connections after a traffic peak
+
+	SlowQueryThreshold time.Duration `mapstructure:"slow_query_threshold"` // Statements slower than this are logged; 0 disables
+
+	PartitionMonthsAhead int `mapstructure:"partition_months_ahead"` // Monthly order partitions kept ready beyond the current month
 }
 
 // RedisConfig holds Redis connection configuration
 type RedisConfig struct {
-	Host         string
-	Port         int
-	Password     string
-	DB           int
-	PoolSize     int
-	MinIdleConns int
+	Host     string
+	Port     int
+	Password string
+	DB       int
+
+	// Connection pool and timeouts (go-redis)
+	PoolSize     int           `mapstructure:"pool_size"`
+	MinIdleConns int           `mapstructure:"min_idle_conns"` // At most PoolSize
+	DialTimeout  time.Duration `mapstructure:"dial_timeout"`
+	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
+	WriteTimeout time.Duration `mapstructure:"write_timeout"`
+	PoolTimeout  time.Duration `mapstructure:"pool_timeout"` // Wait for a free connection when the pool is exhausted
+}
+
+// SentryConfig holds the reporting of recovered panics to Sentry
+type SentryConfig struct {
+	DSN         string  `mapstructure:"dsn"`         // empty disables reporting (panics are only logged)
+	Environment string  `mapstructure:"environment"` // e.g. production, staging
+	Release     string  `mapstructure:"release"`     // deployed version, groups issues by release
+	SampleRate  float64 `mapstructure:"sample_rate"` // share of panics reported, 0-1
+}
+
+// FaultInjectionConfig holds the opt-in injection of latency and errors, used to check
+// timeouts, retries and circuit breakers of the callers. Refused in release mode
+type FaultInjectionConfig struct {
+	Enabled bool              `mapstructure:"enabled"`
+	Headers bool              `mapstructure:"headers"` // honor X-Fault-* request headers naming this service
+	Rules   []FaultRuleConfig `mapstructure:"rules"`
+}
+
+// FaultRuleConfig injects faults into the requests matching a path prefix (and method)
+type FaultRuleConfig struct {
+	PathPrefix  string        `mapstructure:"path_prefix"`
+	Method      string        `mapstructure:"method"`       // empty matches any method
+	Latency     time.Duration `mapstructure:"latency"`      // added before the request is handled
+	Jitter      time.Duration `mapstructure:"jitter"`       // random extra latency, up to this much
+	ErrorRate   float64       `mapstructure:"error_rate"`   // share of matching requests answered with ErrorStatus, 0-1
+	ErrorStatus int           `mapstructure:"error_status"` // default 503
 }
 
 // LoggingConfig holds logging configuration
@@ -103,6 +201,11 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	// Fail fast on settings that would only break under load (pools, timeouts)
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
 	// Debug: print loaded config
 	log.Printf("Config loaded - ProductService.BaseURL: %s", config.ProductService.BaseURL)
 
@@ -127,6 +230,9 @@ func setDefaults() {
 	viper.SetDefault("database.max_open_conns", 25)
 	viper.SetDefault("database.max_idle_conns", 5)
 	viper.SetDefault("database.conn_max_lifetime", "5m")
+	viper.SetDefault("database.conn_max_idle_time", "1m")
+	viper.SetDefault("database.slow_query_threshold", "200ms")
+	viper.SetDefault("database.partition_months_ahead", 3)
 
 	// Redis defaults
 	viper.SetDefault("redis.host", "localhost")
@@ -135,13 +241,43 @@ func setDefaults() {
 	viper.SetDefault("redis.db", 0)
 	viper.SetDefault("redis.pool_size", 10)
 	viper.SetDefault("redis.min_idle_conns", 5)
+	viper.SetDefault("redis.dial_timeout", "5s")
+	viper.SetDefault("redis.read_timeout", "3s")
+	viper.SetDefault("redis.write_timeout", "3s")
+	viper.SetDefault("redis.pool_timeout", "4s")
 
 	// Kafka defaults
 	viper.SetDefault("kafka.brokers", []string{"localhost:9092"})
-	viper.SetDefault("kafka.topic_order_created", "order_created")
+	viper.SetDefault("kafka.topic_prefix", "")
+	viper.SetDefault("kafka.consumer_group", "order-service")
+	viper.SetDefault("kafka.inbox_consumer_group", "order-service-inbox")
+	viper.SetDefault("kafka.payment_consumer_group", "order-service-payments")
 	viper.SetDefault("kafka.write_timeout", "10s")
 	viper.SetDefault("kafka.read_timeout", "10s")
 	viper.SetDefault("kafka.required_acks", 1)
+	viper.SetDefault("kafka.max_retries", 3)
+	viper.SetDefault("kafka.retry_backoff", "200ms")
+	viper.SetDefault("kafka.flush_interval", "10s")
+
+	// Async side effect pool defaults
+	viper.SetDefault("async.workers", 4)
+	viper.SetDefault("async.queue_size", 500)
+	viper.SetDefault("async.max_retries", 3)
+	viper.SetDefault("async.retry_backoff", "200ms")
+	viper.SetDefault("async.task_timeout", "10s")
+
+	// Announcement fan-out defaults
+	viper.SetDefault("announcements.rate", 100)
+	viper.SetDefault("announcements.batch_size", 500)
+
+	// Panic reporting defaults (set SENTRY_DSN to enable)
+	viper.SetDefault("sentry.dsn", "")
+	viper.SetDefault("sentry.environment", "development")
+	viper.SetDefault("sentry.sample_rate", 1.0)
+
+	// Fault injection is for resilience testing only
+	viper.SetDefault("fault_injection.enabled", false)
+	viper.SetDefault("fault_injection.headers", false)
 
 	// Logging defaults
 	viper.SetDefault("logging.level", "info")
@@ -149,9 +285,28 @@ func setDefaults() {
 	viper.SetDefault("logging.output_paths", []string{"stdout"})
 	viper.SetDefault("logging.error_output_paths", []string{"stderr"})
 
+	// Cart defaults
+	viper.SetDefault("cart.ttl", "720h") // 30 days
+	viper.SetDefault("cart.sliding_ttl", true)
+	viper.SetDefault("cart.backup_enabled", true)
+	viper.SetDefault("cart.backup_interval", "5m")
+
 	// Product Service defaults
 	viper.SetDefault("product_service.base_url", "http://localhost:8080")
 	viper.SetDefault("product_service.timeout", "10s")
+	viper.SetDefault("product_service.max_retries", 2)
+	viper.SetDefault("product_service.retry_backoff", "100ms")
+	viper.SetDefault("product_service.breaker_threshold", 5)
+	viper.SetDefault("product_service.breaker_cooldown", "30s")
+
+	// Identity Service defaults
+	viper.SetDefault("identity_service.base_url", "http://localhost:8081")
+	viper.SetDefault("identity_service.timeout", "5s")
+
+	// Service-to-service authentication defaults
+	viper.SetDefault("internal_auth.enabled", true)
+	viper.SetDefault("internal_auth.service_name", "order_service")
+	viper.SetDefault("internal_auth.max_age", "5m")
 }
 
 // GetDSN returns the PostgreSQL Data Source Name
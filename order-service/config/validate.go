@@ -0,0 +1,117 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Validate checks the connection pool and timeout settings of the backing stores,
+// so a bad value fails at startup instead of as timeouts or exhausted pools under load
+func (c *Config) Validate() error {
+	return errors.Join(
+		c.Database.Validate(),
+		c.Redis.Validate(),
+		c.FaultInjection.Validate(c.Server.Mode),
+		c.Announcements.Validate(),
+		c.InternalAuth.Validate(),
+	)
+}
+
+// Validate checks the PostgreSQL connection and pool settings
+func (c *DatabaseConfig) Validate() error {
+	var errs []error
+	if c.Host == "" || c.DBName == "" {
+		errs = append(errs, errors.New("database: host and dbname are required"))
+	}
+	if c.MaxOpenConns <= 0 {
+		errs = append(errs, fmt.Errorf("database: max_open_conns must be positive, got %d", c.MaxOpenConns))
+	}
+	if c.MaxIdleConns < 0 || c.MaxIdleConns > c.MaxOpenConns {
+		errs = append(errs, fmt.Errorf("database: max_idle_conns must be between 0 and max_open_conns (%d), got %d", c.MaxOpenConns, c.MaxIdleConns))
+	}
+	if c.ConnMaxLifetime < 0 || c.ConnMaxIdleTime < 0 {
+		errs = append(errs, errors.New("database: conn_max_lifetime and conn_max_idle_time must not be negative"))
+	}
+	if c.SlowQueryThreshold < 0 {
+		errs = append(errs, errors.New("database: slow_query_threshold must not be negative"))
+	}
+	if c.PartitionMonthsAhead < 1 {
+		errs = append(errs, fmt.Errorf("database: partition_months_ahead must be at least 1, got %d", c.PartitionMonthsAhead))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks the Redis pool and timeout settings
+func (c *RedisConfig) Validate() error {
+	var errs []error
+	if c.Host == "" {
+		errs = append(errs, errors.New("redis: host is required"))
+	}
+	if c.PoolSize <= 0 {
+		errs = append(errs, fmt.Errorf("redis: pool_size must be positive, got %d", c.PoolSize))
+	}
+	if c.MinIdleConns < 0 || c.MinIdleConns > c.PoolSize {
+		errs = append(errs, fmt.Errorf("redis: min_idle_conns must be between 0 and pool_size (%d), got %d", c.PoolSize, c.MinIdleConns))
+	}
+	if c.DialTimeout <= 0 || c.ReadTimeout <= 0 || c.WriteTimeout <= 0 || c.PoolTimeout <= 0 {
+		errs = append(errs, errors.New("redis: dial_timeout, read_timeout, write_timeout and pool_timeout must be positive"))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks the fault injection rules; injecting faults in release mode is refused
+func (c *FaultInjectionConfig) Validate(serverMode string) error {
+	if !c.Enabled {
+		return nil
+	}
+	if serverMode == "release" {
+		return errors.New("fault_injection: must not be enabled when server.mode is release")
+	}
+	var errs []error
+	for i, rule := range c.Rules {
+		if rule.PathPrefix == "" {
+			errs = append(errs, fmt.Errorf("fault_injection: rules[%d].path_prefix is required", i))
+		}
+		if rule.Latency < 0 || rule.Jitter < 0 {
+			errs = append(errs, fmt.Errorf("fault_injection: rules[%d] latency and jitter must not be negative", i))
+		}
+		if rule.ErrorRate < 0 || rule.ErrorRate > 1 {
+			errs = append(errs, fmt.Errorf("fault_injection: rules[%d].error_rate must be between 0 and 1, got %g", i, rule.ErrorRate))
+		}
+		if rule.ErrorStatus != 0 && (rule.ErrorStatus < 400 || rule.ErrorStatus > 599) {
+			errs = append(errs, fmt.Errorf("fault_injection: rules[%d].error_status must be a 4xx or 5xx status, got %d", i, rule.ErrorStatus))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks the announcement throttling; a batch must be published within a minute
+// so a fan-out run saves its progress well within its job timeout
+func (c *AnnouncementConfig) Validate() error {
+	if c.Rate <= 0 || c.BatchSize <= 0 {
+		return fmt.Errorf("announcements: rate and batch_size must be positive, got %d and %d", c.Rate, c.BatchSize)
+	}
+	if c.BatchSize > c.Rate*60 {
+		return fmt.Errorf("announcements: batch_size must be at most 60 * rate (%d), got %d", c.Rate*60, c.BatchSize)
+	}
+	return nil
+}
+
+// Validate checks that signing is configured when internal endpoints require signed calls;
+// the secret comes from the environment (INTERNAL_AUTH_SECRET), never from config.yaml
+func (c *InternalAuthConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	var errs []error
+	if c.ServiceName == "" {
+		errs = append(errs, errors.New("internal_auth: service_name is required when enabled"))
+	}
+	if c.Secret == "" {
+		errs = append(errs, errors.New("internal_auth: secret is required when enabled (set INTERNAL_AUTH_SECRET)"))
+	}
+	if c.MaxAge <= 0 {
+		errs = append(errs, fmt.Errorf("internal_auth: max_age must be positive, got %s", c.MaxAge))
+	}
+	return errors.Join(errs...)
+}
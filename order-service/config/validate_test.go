@@ -0,0 +1,59 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestLoadConfigSecretsFromEnv checks that config.yaml ships no signing secrets: order-service's
+// own secret and those of its callers (product, payment and identity service) are read from
+// the environment, and startup fails without its own secret
+func TestLoadConfigSecretsFromEnv(t *testing.T) {
+	env := map[string]string{
+		"INTERNAL_AUTH_SECRET":                            "own-secret",
+		"INTERNAL_AUTH_TRUSTED_SERVICES_PRODUCT_SERVICE":  "product-secret",
+		"INTERNAL_AUTH_TRUSTED_SERVICES_PAYMENT_SERVICE":  "payment-secret",
+		"INTERNAL_AUTH_TRUSTED_SERVICES_IDENTITY_SERVICE": "identity-secret",
+	}
+	tests := []struct {
+		name    string
+		unset   string // env var left empty
+		wantErr string
+	}{
+		{"all secrets set", "", ""},
+		{"own secret missing", "INTERNAL_AUTH_SECRET", "INTERNAL_AUTH_SECRET"},
+		{"caller without secret is not trusted", "INTERNAL_AUTH_TRUSTED_SERVICES_PAYMENT_SERVICE", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := make(map[string]string, len(env))
+			for key, value := range env {
+				if key == tt.unset {
+					value = ""
+				}
+				want[key] = value
+				t.Setenv(key, value)
+			}
+
+			cfg, err := LoadConfig(".")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to mention %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadConfig: %v", err)
+			}
+			if cfg.InternalAuth.Secret != want["INTERNAL_AUTH_SECRET"] {
+				t.Errorf("secret = %q, want it from INTERNAL_AUTH_SECRET", cfg.InternalAuth.Secret)
+			}
+			for _, caller := range []string{"product_service", "payment_service", "identity_service"} {
+				key := "INTERNAL_AUTH_TRUSTED_SERVICES_" + strings.ToUpper(caller)
+				if got := cfg.InternalAuth.TrustedServices[caller]; got != want[key] {
+					t.Errorf("trusted %s secret = %q, want %q from %s", caller, got, want[key], key)
+				}
+			}
+		})
+	}
+}
@@ -783,6 +783,7 @@ const docTemplate = `{
             "enum": [
                 "pending",
                 "paid",
+                "confirmed",
                 "processing",
                 "shipped",
                 "delivered",
@@ -790,16 +791,18 @@ const docTemplate = `{
             ],
             "x-enum-comments": {
                 "OrderStatusCancelled": "Order has been cancelled",
+                "OrderStatusConfirmed": "Accepted by the seller, being prepared",
                 "OrderStatusDelivered": "Order has been delivered",
                 "OrderStatusPaid": "Payment completed",
                 "OrderStatusPending": "Order created, waiting for payment",
-                "OrderStatusProcessing": "Order is being processed",
+                "OrderStatusProcessing": "Legacy confirmed status of orders confirmed before \"confirmed\" existed",
                 "OrderStatusShipped": "Order has been shipped"
             },
             "x-enum-descriptions": [
                 "Order created, waiting for payment",
                 "Payment completed",
-                "Order is being processed",
+                "Accepted by the seller, being prepared",
+                "Legacy confirmed status of orders confirmed before \"confirmed\" existed",
                 "Order has been shipped",
                 "Order has been delivered",
                 "Order has been cancelled"
@@ -807,6 +810,7 @@ const docTemplate = `{
             "x-enum-varnames": [
                 "OrderStatusPending",
                 "OrderStatusPaid",
+                "OrderStatusConfirmed",
                 "OrderStatusProcessing",
                 "OrderStatusShipped",
                 "OrderStatusDelivered",
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Announcement audience segments
+const (
+	AnnouncementSegmentBuyers          = "buyers"           // Every active buyer
+	AnnouncementSegmentCategorySellers = "category_sellers" // Owners of the shops selling in a category (or its subcategories)
+	AnnouncementSegmentInactiveUsers   = "inactive_users"   // Buyers and sellers without a login for InactiveDays
+)
+
+// AnnouncementSegments are the segments an admin can target
+var AnnouncementSegments = []string{
+	AnnouncementSegmentBuyers,
+	AnnouncementSegmentCategorySellers,
+	AnnouncementSegmentInactiveUsers,
+}
+
+type AnnouncementStatus string
+
+// Announcement workflow: QUEUED -> SENDING -> SENT | FAILED
+// (an admin may cancel a QUEUED or SENDING announcement, users already notified keep it)
+const (
+	AnnouncementStatusQueued   AnnouncementStatus = "QUEUED"   // Waiting for the fan-out job
+	AnnouncementStatusSending  AnnouncementStatus = "SENDING"  // Fan-out in progress
+	AnnouncementStatusSent     AnnouncementStatus = "SENT"     // Every recipient was notified
+	AnnouncementStatusCanceled AnnouncementStatus = "CANCELED" // Stopped by an admin
+	AnnouncementStatusFailed   AnnouncementStatus = "FAILED"   // The audience could not be resolved, see FailureReason
+)
+
+// IsFinished reports whether the fan-out is over
+func (s AnnouncementStatus) IsFinished() bool {
+	return s == AnnouncementStatusSent || s == AnnouncementStatusCanceled || s == AnnouncementStatusFailed
+}
+
+// Announcement is a message an admin broadcasts to a segment of users. It is fanned out
+// as one notification event per recipient, throttled, by a background job
+type Announcement struct {
+	ID uint `json:"id" gorm:"primaryKey"`
+
+	Title        string `json:"title" gorm:"size:255;not null"`
+	Body         string `json:"body" gorm:"type:text;not null"`
+	Link         string `json:"link,omitempty" gorm:"size:255"` // Client route to open, e.g. /campaigns/summer-sale
+	Segment      string `json:"segment" gorm:"size:30;not null"`
+	CategoryID   uint   `json:"category_id,omitempty"`   // category_sellers
+	InactiveDays int    `json:"inactive_days,omitempty"` // inactive_users
+
+	Status        AnnouncementStatus `json:"status" gorm:"type:varchar(20);index;not null"`
+	FailureReason string             `json:"failure_reason,omitempty" gorm:"type:text"`
+	CreatedBy     uint               `json:"created_by" gorm:"not null"`
+
+	// Fan-out progress, saved after every batch so a retried job resumes after LastUserID
+	LastUserID  uint       `json:"-" gorm:"not null;default:0"`
+	SentCount   int64      `json:"sent_count" gorm:"not null;default:0"` // Notification events published
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for Announcement
+func (Announcement) TableName() string {
+	return "announcements"
+}
+
+// AnnouncementStats are the delivery figures of an announcement
+type AnnouncementStats struct {
+	Sent      int64   `json:"sent"`      // Notification events published
+	Delivered int64   `json:"delivered"` // Added to the recipients' in-app inbox
+	Opened    int64   `json:"opened"`    // Read in the inbox
+	OpenRate  float64 `json:"open_rate"` // Opened / delivered, 0-1
+}
+
+// ErrAnnouncementNotFound is returned when the announcement does not exist
+var ErrAnnouncementNotFound = NotFound("announcement not found")
+
+// AnnouncementDedupeKey identifies the notification of an announcement to a user
+func AnnouncementDedupeKey(announcementID, userID uint) string {
+	return fmt.Sprintf("announcement:%d:%d", announcementID, userID)
+}
+
+// AnnouncementRepository stores announcements (implemented by postgres.AnnouncementRepository)
+type AnnouncementRepository interface {
+	Create(ctx context.Context, announcement *Announcement) error
+	GetByID(ctx context.Context, id uint) (*Announcement, error) // ErrAnnouncementNotFound
+	List(ctx context.Context, page, limit int) ([]*Announcement, int64, error)
+
+	// SaveProgress writes the status and fan-out progress if the announcement is still in
+	// one of the from statuses, reporting false if it is not (e.g. canceled meanwhile)
+	SaveProgress(ctx context.Context, announcement *Announcement, from ...AnnouncementStatus) (bool, error)
+
+	// Cancel moves a QUEUED or SENDING announcement to CANCELED, reporting false if it is
+	// already finished; the progress columns are left to the fan-out job
+	Cancel(ctx context.Context, id uint, at time.Time) (bool, error)
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"errors"
+	"fmt"
 )
 
 // CartItem represents a single item in the shopping cart
@@ -12,6 +13,15 @@ type CartItem struct {
 	Quantity      int  `json:"quantity"`
 	IsSelected    bool `json:"is_selected"`
 
+	// ✅ STORED in Redis - snapshot taken when the item is added, so price/name
+	// changes in Product Service can be shown to the buyer before checkout
+	ProductID     uint    `json:"product_id,omitempty"`
+	SnapshotName  string  `json:"snapshot_name,omitempty"`
+	SnapshotPrice float64 `json:"snapshot_price,omitempty"`
+	PriceChanged  bool    `json:"price_changed"`
+	OldPrice      float64 `json:"old_price,omitempty"` // Snapshot price when the change was detected
+	NewPrice      float64 `json:"new_price,omitempty"` // Current price in Product Service
+
 	// ❌ NOT stored in Redis - Fetched from Product Service on-demand
 	ShopID      uint    `json:"shop_id,omitempty" redis:"-"`
 	ProductName string  `json:"product_name,omitempty" redis:"-"`
@@ -35,6 +45,7 @@ type ShoppingCart struct {
 	SelectedQuantity   int     `json:"selected_quantity" redis:"-"`    // Sum of selected quantities
 	TotalPrice         float64 `json:"total_price" redis:"-"`          // Total price (all)
 	SelectedTotalPrice float64 `json:"selected_total_price" redis:"-"` // Total price (selected)
+	PriceChangedCount  int     `json:"price_changed_count" redis:"-"`  // Items whose price changed since added
 }
 
 // CalculateTotals computes CART-LEVEL metrics ONLY
@@ -48,6 +59,7 @@ func (c *ShoppingCart) CalculateTotals() {
 	c.SelectedQuantity = 0
 	c.TotalPrice = 0
 	c.SelectedTotalPrice = 0
+	c.PriceChangedCount = 0
 
 	for _, item := range c.Items {
 		// ✅ Cart Service chỉ quan tâm: đếm và tính tổng
@@ -59,6 +71,10 @@ func (c *ShoppingCart) CalculateTotals() {
 		c.TotalQuantity += qty
 		c.TotalPrice += linePrice
 
+		if item.PriceChanged {
+			c.PriceChangedCount++
+		}
+
 		// Count selected items
 		if item.IsSelected {
 			c.SelectedItemCount++
@@ -108,6 +124,42 @@ func (c *ShoppingCart) FindItemByProductItemID(productItemID uint) *CartItem {
 	return nil
 }
 
+// MarkPriceChange compares the snapshot price with the current price and flags the
+// item if they differ. Returns true if the item changed (needs saving)
+func (ci *CartItem) MarkPriceChange(currentPrice float64) bool {
+	if ci.SnapshotPrice == 0 {
+		return false // added before snapshots existed
+	}
+	if currentPrice == ci.SnapshotPrice {
+		if !ci.PriceChanged {
+			return false
+		}
+		// Price went back to the snapshot
+		ci.PriceChanged = false
+		ci.OldPrice = 0
+		ci.NewPrice = 0
+		return true
+	}
+	if ci.PriceChanged && ci.NewPrice == currentPrice {
+		return false
+	}
+	ci.PriceChanged = true
+	ci.OldPrice = ci.SnapshotPrice
+	ci.NewPrice = currentPrice
+	return true
+}
+
+// AcceptPriceChange takes the new price as the snapshot and clears the flag
+func (ci *CartItem) AcceptPriceChange() {
+	if !ci.PriceChanged {
+		return
+	}
+	ci.SnapshotPrice = ci.NewPrice
+	ci.PriceChanged = false
+	ci.OldPrice = 0
+	ci.NewPrice = 0
+}
+
 // Validate validates cart item
 func (ci *CartItem) Validate() error {
 	if ci.ProductItemID == 0 {
@@ -116,24 +168,55 @@ func (ci *CartItem) Validate() error {
 	if ci.Quantity <= 0 {
 		return ErrInvalidQuantity
 	}
-	if ci.Quantity > 999 {
+	if ci.Quantity > MaxItemQuantity {
 		return ErrQuantityExceedsLimit
 	}
 	return nil
 }
 
+// MaxItemQuantity is the hard upper limit of one cart item quantity
+// Admin setting cart.max_item_quantity can only lower it
+const MaxItemQuantity = 999
+
 // ==========================================
 // DOMAIN ERRORS
 // ==========================================
 
 var (
-	ErrCartNotFound         = errors.New("cart not found")
-	ErrCartItemNotFound     = errors.New("cart item not found")
-	ErrInvalidProductItem   = errors.New("invalid product item")
-	ErrInvalidQuantity      = errors.New("quantity must be greater than 0")
-	ErrQuantityExceedsLimit = errors.New("quantity exceeds maximum limit (999)")
-	ErrCartEmpty            = errors.New("cart is empty")
-	ErrNoItemsSelected      = errors.New("no items selected for checkout")
-	ErrProductOutOfStock    = errors.New("product is out of stock")
-	ErrInsufficientStock    = errors.New("insufficient stock for requested quantity")
+	ErrCartNotFound         = NotFound("cart not found")
+	ErrCartItemNotFound     = NotFound("cart item not found")
+	ErrInvalidProductItem   = Validation("invalid product item")
+	ErrInvalidQuantity      = Validation("quantity must be greater than 0")
+	ErrQuantityExceedsLimit = Validation("quantity exceeds maximum limit (999)")
+	ErrCartEmpty            = Validation("cart is empty")
+	ErrNoItemsSelected      = Validation("no items selected for checkout")
+	ErrProductOutOfStock    = Conflict("product is out of stock")
+	ErrInsufficientStock    = Conflict("insufficient stock for requested quantity")
+	ErrCartVersionConflict  = Conflict("cart was modified by another request")
+
+	ErrPurchaseLimitExceeded = errors.New("purchase limit exceeded")
 )
+
+// Error codes returned with quantity errors so clients can react without parsing messages
+const (
+	ErrCodeQuantityExceedsLimit  = "QUANTITY_EXCEEDS_LIMIT"
+	ErrCodePurchaseLimitExceeded = "PURCHASE_LIMIT_EXCEEDED"
+)
+
+// PurchaseLimitError is returned when a customer's quantity of a product (summed over
+// all its SKUs) exceeds the product's max purchase quantity set in Product Service
+// errors.Is(err, ErrPurchaseLimitExceeded) matches it
+type PurchaseLimitError struct {
+	ProductID   uint
+	ProductName string
+	Limit       int
+	Requested   int
+}
+
+func (e *PurchaseLimitError) Error() string {
+	return fmt.Sprintf("%s is limited to %d per customer (requested: %d)", e.ProductName, e.Limit, e.Requested)
+}
+
+func (e *PurchaseLimitError) Unwrap() error {
+	return ErrPurchaseLimitExceeded
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// CartBackup is the Postgres copy of a Redis cart, synced periodically so carts
+// survive a Redis flush and are restored on the next read
+type CartBackup struct {
+	UserID    string    `json:"user_id" gorm:"primaryKey;type:varchar(64)"`
+	Data      string    `json:"data" gorm:"type:jsonb;not null"` // Cart JSON as stored in Redis
+	Version   int       `json:"version" gorm:"not null"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"index"` // When the synced cart was last seen in Redis
+}
+
+// TableName specifies the table name for CartBackup
+func (CartBackup) TableName() string {
+	return "cart_backups"
+}
+
+// CartBackupStore reads and writes cart backups (implemented by postgres.CartBackupRepository)
+type CartBackupStore interface {
+	Get(userID string) (*CartBackup, error) // nil if there is no backup
+	Upsert(backup *CartBackup) error
+	Delete(userID string) error
+}
@@ -0,0 +1,184 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+type DisputeStatus string
+
+// Dispute workflow: OPEN -> UNDER_REVIEW -> RESOLVED_BUYER | RESOLVED_SELLER
+// (an admin may also resolve an OPEN dispute directly)
+const (
+	DisputeStatusOpen           DisputeStatus = "OPEN"            // Raised by the buyer, evidence can be added
+	DisputeStatusUnderReview    DisputeStatus = "UNDER_REVIEW"    // An admin is reviewing, evidence can still be added
+	DisputeStatusResolvedBuyer  DisputeStatus = "RESOLVED_BUYER"  // Buyer refunded, seller payout adjusted
+	DisputeStatusResolvedSeller DisputeStatus = "RESOLVED_SELLER" // Dispute rejected, nothing changes
+)
+
+// IsResolved reports whether the dispute is closed
+func (s DisputeStatus) IsResolved() bool {
+	return s == DisputeStatusResolvedBuyer || s == DisputeStatusResolvedSeller
+}
+
+// Dispute reasons
+const (
+	DisputeReasonNotReceived    = "not_received"
+	DisputeReasonNotAsDescribed = "not_as_described"
+	DisputeReasonDamaged        = "damaged"
+	DisputeReasonCounterfeit    = "counterfeit"
+	DisputeReasonOther          = "other"
+)
+
+// DisputeReasons are the reasons a buyer can give
+var DisputeReasons = []string{
+	DisputeReasonNotReceived,
+	DisputeReasonNotAsDescribed,
+	DisputeReasonDamaged,
+	DisputeReasonCounterfeit,
+	DisputeReasonOther,
+}
+
+// Parties that submit dispute evidence
+const (
+	DisputePartyBuyer  = "buyer"
+	DisputePartySeller = "seller"
+	DisputePartyAdmin  = "admin"
+)
+
+// MaxDisputeEvidence bounds the evidence items of one dispute
+const MaxDisputeEvidence = 20
+
+// Dispute is a buyer's claim against a shop_order, decided by an admin
+// One dispute per order
+type Dispute struct {
+	ID uint `json:"id" gorm:"primaryKey"`
+
+	OrderID     uint   `json:"order_id" gorm:"uniqueIndex;not null"`
+	OrderNumber string `json:"order_number" gorm:"size:50;not null"`
+	BuyerID     uint   `json:"buyer_id" gorm:"index;not null"`
+	ShopID      uint   `json:"shop_id" gorm:"index;not null"`
+
+	Reason      string        `json:"reason" gorm:"size:50;not null"`
+	Description string        `json:"description" gorm:"type:text"`
+	Status      DisputeStatus `json:"status" gorm:"type:varchar(20);index;not null"`
+
+	// Decision (set on resolution)
+	RefundAmount   float64    `json:"refund_amount" gorm:"type:decimal(15,2);not null;default:0"`
+	ResolutionNote string     `json:"resolution_note,omitempty" gorm:"type:text"`
+	ReviewedBy     uint       `json:"reviewed_by,omitempty"`
+	ResolvedBy     uint       `json:"resolved_by,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+
+	Evidence []*DisputeEvidence `json:"evidence,omitempty" gorm:"foreignKey:DisputeID"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for Dispute
+func (Dispute) TableName() string {
+	return "disputes"
+}
+
+// DisputeEvidence is a file (photo, video, document) or statement submitted for a dispute
+// Files are uploaded to storage by the client first; only the URL is kept here
+type DisputeEvidence struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	DisputeID   uint      `json:"dispute_id" gorm:"index;not null"`
+	SubmittedBy uint      `json:"submitted_by" gorm:"not null"`
+	Party       string    `json:"party" gorm:"size:10;not null"` // buyer, seller, admin
+	URL         string    `json:"url,omitempty" gorm:"size:500"`
+	Note        string    `json:"note,omitempty" gorm:"type:text"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for DisputeEvidence
+func (DisputeEvidence) TableName() string {
+	return "dispute_evidence"
+}
+
+// PayoutAdjustmentStatus tracks whether an adjustment was applied to a payout batch
+type PayoutAdjustmentStatus string
+
+const (
+	PayoutAdjustmentPending PayoutAdjustmentStatus = "pending" // Applied to the shop's next payout batch
+	PayoutAdjustmentApplied PayoutAdjustmentStatus = "applied"
+)
+
+// PayoutAdjustment changes a shop's next payout, e.g. the seller's share of a refund
+// decided in a dispute or the earning difference of an order adjustment
+// (negative amount = deducted from the payout)
+type PayoutAdjustment struct {
+	ID uint `json:"id" gorm:"primaryKey"`
+
+	ShopID            uint                   `json:"shop_id" gorm:"index;not null"`
+	OrderID           uint                   `json:"order_id" gorm:"not null"`
+	DisputeID         *uint                  `json:"dispute_id,omitempty" gorm:"uniqueIndex"`          // Set for dispute refunds
+	OrderAdjustmentID *uint                  `json:"order_adjustment_id,omitempty" gorm:"uniqueIndex"` // Set for admin order adjustments
+	Amount            float64                `json:"amount" gorm:"type:decimal(15,2);not null"`
+	Reason            string                 `json:"reason" gorm:"size:255"`
+	Status            PayoutAdjustmentStatus `json:"status" gorm:"type:varchar(20);index;not null"`
+	BatchID           *uint                  `json:"batch_id,omitempty"` // Payout batch the adjustment was applied to
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for PayoutAdjustment
+func (PayoutAdjustment) TableName() string {
+	return "payout_adjustments"
+}
+
+// DisputeRefundMetadata is the metadata of the order_refund_requested event
+// (consumed by payment-service to refund the buyer)
+type DisputeRefundMetadata struct {
+	DisputeID    uint    `json:"dispute_id"`
+	RefundAmount float64 `json:"refund_amount"`
+}
+
+// Dispute errors
+var (
+	ErrDisputeNotFound       = NotFound("dispute not found")
+	ErrDisputeOrderNotFound  = NotFound("order not found")
+	ErrDisputeExists         = Conflict("a dispute already exists for this order")
+	ErrDisputeNotAllowed     = errors.New("order cannot be disputed in its current status")
+	ErrInvalidDisputeReason  = Validation("invalid dispute reason")
+	ErrInvalidDisputeStatus  = Conflict("dispute cannot be changed in its current status")
+	ErrInvalidEvidence       = Validation("evidence needs an http(s) url or a note")
+	ErrDisputeEvidenceLimit  = errors.New("too many evidence items for this dispute")
+	ErrInvalidRefundAmount   = Validation("refund amount must be greater than 0 and at most the order amount")
+	ErrInvalidDisputeOutcome = Validation("outcome must be buyer or seller")
+)
+
+// DisputeRepository stores disputes and their evidence (implemented by postgres.DisputeRepository)
+type DisputeRepository interface {
+	Create(ctx context.Context, dispute *Dispute) error // ErrDisputeExists if the order already has one
+	GetByID(ctx context.Context, id uint) (*Dispute, error)
+	List(ctx context.Context, filter DisputeFilter, page, limit int) ([]*Dispute, int64, error)
+	AddEvidence(ctx context.Context, evidence *DisputeEvidence) error
+	CountEvidence(ctx context.Context, disputeID uint) (int64, error)
+
+	// UpdateStatus moves the dispute from one of the from statuses, reporting false if it
+	// was not in one of them (changed concurrently)
+	UpdateStatus(ctx context.Context, dispute *Dispute, from ...DisputeStatus) (bool, error)
+
+	// Resolve stores the decision and the refund (if any) in one transaction
+	Resolve(ctx context.Context, dispute *Dispute, refund *DisputeRefund, from ...DisputeStatus) (bool, error)
+}
+
+// DisputeRefund is what a decision for the buyer records besides the dispute: the refund
+// adjustment of the order and the deduction of the seller's share from the next payout
+type DisputeRefund struct {
+	Order      *Order
+	Adjustment *OrderAdjustment
+	Payout     *PayoutAdjustment
+}
+
+// DisputeFilter narrows dispute listings (zero values = no filter)
+type DisputeFilter struct {
+	BuyerID uint
+	ShopID  uint
+	Status  DisputeStatus
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"encoding/json"
 	"time"
 )
@@ -8,12 +9,14 @@ import (
 // OrderEvent represents a domain event for order changes
 // Events are used for inter-service communication via Kafka
 // Following Domain-Driven Design principles
+// Build it with NewOrderEvent so the payload and schema version are set
 type OrderEvent struct {
-	EventType   string      `json:"event_type"`   // e.g., "order_created", "order_updated"
-	OrderID     uint        `json:"order_id"`
-	OrderData   *Order      `json:"order_data"`
-	Timestamp   time.Time   `json:"timestamp"`
-	Metadata    interface{} `json:"metadata,omitempty"`
+	EventType     string        `json:"event_type"`     // e.g., "order_created", "order_updated"
+	SchemaVersion int           `json:"schema_version"` // OrderEventSchemaVersion
+	OrderID       uint          `json:"order_id"`
+	Order         *OrderPayload `json:"order"`
+	Timestamp     time.Time     `json:"timestamp"`
+	Metadata      interface{}   `json:"metadata,omitempty"`
 }
 
 // ToJSON converts the event to JSON bytes for Kafka publishing
@@ -28,3 +31,73 @@ type OrderEventPublisher interface {
 	Close() error // Close releases resources (e.g., Kafka connections)
 }
 
+// BufferedOrderEventPublisher is an OrderEventPublisher that parks events it cannot
+// deliver (after retries) in a durable buffer and redelivers them later (at-least-once)
+type BufferedOrderEventPublisher interface {
+	OrderEventPublisher
+	NotificationEventPublisher
+	Start(ctx context.Context)                     // Flushes the buffer periodically until ctx is canceled
+	Flush(ctx context.Context) (int, error)        // Redelivers buffered events now, returns how many were sent
+	Stats(ctx context.Context) EventPublisherStats // Delivery counters and buffer lag
+}
+
+// EventPublisherStats is a snapshot of publisher counters
+type EventPublisherStats struct {
+	Published    int64      `json:"published"`     // Events written to Kafka (directly or from the buffer)
+	Retried      int64      `json:"retried"`       // Write attempts that were retried
+	Buffered     int64      `json:"buffered"`      // Events parked in the buffer
+	Flushed      int64      `json:"flushed"`       // Buffered events delivered later
+	Failed       int64      `json:"failed"`        // Events that could neither be written nor buffered
+	BufferLength int64      `json:"buffer_length"` // Events waiting in the buffer
+	LagSeconds   float64    `json:"lag_seconds"`   // Age of the oldest buffered event
+	LastError    string     `json:"last_error,omitempty"`
+	LastErrorAt  *time.Time `json:"last_error_at,omitempty"`
+}
+
+// ProductEvent is the product_created/product_updated event published by Product Service
+// Only the fields order-service needs are decoded
+type ProductEvent struct {
+	EventType string          `json:"event_type"`
+	ProductID uint            `json:"product_id"`
+	Timestamp time.Time       `json:"timestamp"`
+	Metadata  json.RawMessage `json:"metadata,omitempty"` // ProductItemChange for product_item_updated
+}
+
+// ProductEventHandler reacts to product events (implemented by CartService and SubscriptionService)
+type ProductEventHandler interface {
+	HandleProductUpdated(ctx context.Context, productID uint) error
+}
+
+// ProductItemChange is the metadata of a product_item_updated event (SKU price or stock changed)
+type ProductItemChange struct {
+	ProductItemID      uint    `json:"product_item_id"`
+	Price              float64 `json:"price"`
+	PreviousPrice      float64 `json:"previous_price"`
+	QtyInStock         int     `json:"qty_in_stock"`
+	PreviousQtyInStock int     `json:"previous_qty_in_stock"`
+	Status             string  `json:"status"`
+}
+
+// ProductItemEventHandler is implemented by ProductEventHandlers that also react to SKU changes
+type ProductItemEventHandler interface {
+	HandleProductItemUpdated(ctx context.Context, productID uint, change *ProductItemChange) error
+}
+
+// Payment event types published by Payment Service
+const (
+	EventPaymentSucceeded = "payment_succeeded"
+	EventPaymentFailed    = "payment_failed"
+)
+
+// PaymentEvent is the payment_succeeded/payment_failed event published by Payment Service
+// Only the fields order-service needs are decoded; events may be delivered twice
+type PaymentEvent struct {
+	EventType       string    `json:"event_type"`
+	PaymentIntentID uint      `json:"payment_intent_id"`
+	OrderID         uint      `json:"order_id"`
+	OrderNumber     string    `json:"order_number"`
+	AmountMinor     int64     `json:"amount_minor"` // In minor units of the payment's currency
+	ProviderRef     string    `json:"provider_ref,omitempty"`
+	Reason          string    `json:"reason,omitempty"` // payment_failed: decline reason or "expired"
+	Timestamp       time.Time `json:"timestamp"`
+}
@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+type JournalAccount string
+
+// Ledger accounts of the platform's books
+const (
+	AccountGatewayReceivable  JournalAccount = "payment_gateway_receivable" // Asset: collected by the payment gateway, not yet settled to the platform
+	AccountSellerPayable      JournalAccount = "seller_payable"             // Liability: owed to shops, paid out in payout batches
+	AccountPlatformFeeRevenue JournalAccount = "platform_fee_revenue"       // Revenue: platform fees kept from orders
+	AccountRefundsPayable     JournalAccount = "refunds_payable"            // Liability: refunds owed to buyers
+)
+
+// JournalEntryPayment is the entry type of an order paid through the payment gateway;
+// adjustments post entries of their adjustment type (refund, fee_adjustment, shipping_correction)
+const JournalEntryPayment = "payment"
+
+// Journal export errors
+var (
+	ErrInvalidJournalPeriod = Validation("from and to must be dates (YYYY-MM-DD), from not after to, at most one year apart")
+	ErrInvalidExportFormat  = Validation("format must be csv or xlsx")
+)
+
+// JournalEntry is one balanced double-entry posting (the debits of its lines equal the
+// credits), made when an order is paid or adjusted. Entries are append-only and
+// SourceKey makes posting the same payment or adjustment twice a no-op
+type JournalEntry struct {
+	ID uint `json:"id" gorm:"primaryKey"`
+
+	SourceKey   string `json:"source_key" gorm:"size:60;not null;uniqueIndex"` // e.g. order_paid:42, order_adjustment:7
+	EntryType   string `json:"entry_type" gorm:"size:30;not null"`
+	OrderID     uint   `json:"order_id" gorm:"index;not null"` // shop_order is partitioned, so no foreign key
+	OrderNumber string `json:"order_number" gorm:"size:50"`
+	ShopID      uint   `json:"shop_id" gorm:"index"`
+	Description string `json:"description" gorm:"size:500"`
+	Reference   string `json:"reference,omitempty" gorm:"size:100"` // Payment gateway reference
+
+	PostedAt  time.Time `json:"posted_at" gorm:"index;not null"` // When the payment or adjustment happened
+	CreatedAt time.Time `json:"created_at"`
+
+	Lines []JournalLine `json:"lines" gorm:"foreignKey:EntryID"`
+}
+
+// TableName specifies the table name for JournalEntry
+func (JournalEntry) TableName() string {
+	return "journal_entries"
+}
+
+// JournalLine debits or credits one account (one of Debit and Credit is zero)
+type JournalLine struct {
+	ID uint `json:"id" gorm:"primaryKey"`
+
+	EntryID uint           `json:"entry_id" gorm:"index;not null"`
+	Account JournalAccount `json:"account" gorm:"type:varchar(40);not null"`
+	Debit   float64        `json:"debit" gorm:"type:decimal(15,2);not null;default:0"`
+	Credit  float64        `json:"credit" gorm:"type:decimal(15,2);not null;default:0"`
+}
+
+// TableName specifies the table name for JournalLine
+func (JournalLine) TableName() string {
+	return "journal_lines"
+}
+
+// NewPaymentJournalEntry posts a paid order: the gateway owes the platform what the
+// buyer paid, which is owed on to the shop except for the platform fee
+// The fee is what the shop does not earn, so the entry balances even if the earning
+// was floored at zero
+func NewPaymentJournalEntry(order *Order, payment *PaymentEvent) *JournalEntry {
+	postedAt := payment.Timestamp
+	if order.PaidAt != nil {
+		postedAt = *order.PaidAt
+	}
+	return &JournalEntry{
+		SourceKey:   fmt.Sprintf("order_paid:%d", order.ID),
+		EntryType:   JournalEntryPayment,
+		OrderID:     order.ID,
+		OrderNumber: order.OrderNumber,
+		ShopID:      order.ShopID,
+		Description: fmt.Sprintf("Payment of order %s (%s)", order.OrderNumber, order.PaymentMethod),
+		Reference:   payment.ProviderRef,
+		PostedAt:    postedAt,
+		Lines: journalLines(AccountGatewayReceivable,
+			order.FinalAmount, order.EarningAmount, order.FinalAmount-order.EarningAmount),
+	}
+}
+
+// NewAdjustmentJournalEntry posts a stored order adjustment from its deltas: a refund
+// is owed to the buyer, other changes of the final amount are collected through the
+// gateway; the shop's and the platform's shares move with their deltas
+func NewAdjustmentJournalEntry(order *Order, adjustment *OrderAdjustment) *JournalEntry {
+	account := AccountGatewayReceivable
+	if adjustment.Type == OrderAdjustmentRefund {
+		account = AccountRefundsPayable
+	}
+	return &JournalEntry{
+		SourceKey:   fmt.Sprintf("order_adjustment:%d", adjustment.ID),
+		EntryType:   string(adjustment.Type),
+		OrderID:     order.ID,
+		OrderNumber: order.OrderNumber,
+		ShopID:      order.ShopID,
+		Description: fmt.Sprintf("Order %s %s: %s", order.OrderNumber, adjustment.Type, adjustment.Reason),
+		PostedAt:    adjustment.CreatedAt,
+		Lines: journalLines(account,
+			adjustment.FinalAmountDelta, adjustment.EarningAmountDelta, adjustment.PlatformFeeDelta),
+	}
+}
+
+// journalLines books a signed change of what the buyer pays (debited to account) against
+// the shop's earning and the platform fee (credited); negative changes reverse the sides
+// Zero amounts get no line
+func journalLines(account JournalAccount, final, earning, fee float64) []JournalLine {
+	lines := make([]JournalLine, 0, 3)
+	for _, l := range []struct {
+		account JournalAccount
+		amount  float64 // Positive = debit
+	}{
+		{account, final},
+		{AccountSellerPayable, -earning},
+		{AccountPlatformFeeRevenue, -fee},
+	} {
+		switch {
+		case l.amount > 0:
+			lines = append(lines, JournalLine{Account: l.account, Debit: l.amount})
+		case l.amount < 0:
+			lines = append(lines, JournalLine{Account: l.account, Credit: -l.amount})
+		}
+	}
+	return lines
+}
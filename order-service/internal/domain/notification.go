@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Notification is an entry of a user's in-app inbox (bell icon), created from the
+// notification events the services publish
+type Notification struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"index:idx_notification_user_read;not null"`
+	EventType string     `json:"event_type" gorm:"type:varchar(50);not null"`
+	DedupeKey string     `json:"-" gorm:"type:varchar(255);uniqueIndex;not null"` // Redelivered events map to the same entry
+	Title     string     `json:"title" gorm:"type:varchar(255);not null"`
+	Body      string     `json:"body" gorm:"type:text"`
+	Link      string     `json:"link,omitempty" gorm:"type:varchar(255)"` // Client route to open, e.g. /orders/ORD-...
+	ReadAt    *time.Time `json:"read_at,omitempty" gorm:"index:idx_notification_user_read"`
+	CreatedAt time.Time  `json:"created_at" gorm:"index"`
+
+	AnnouncementID *uint `json:"announcement_id,omitempty" gorm:"index"` // Set for admin announcements (delivery/open stats)
+}
+
+// TableName specifies the table name for Notification
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+// IsRead reports whether the user has read the notification
+func (n *Notification) IsRead() bool {
+	return n.ReadAt != nil
+}
+
+// ErrNotificationNotFound is returned when the notification does not exist or is not the user's
+var ErrNotificationNotFound = NotFound("notification not found")
+
+// NotificationRepository stores inbox notifications (implemented by postgres.NotificationRepository)
+type NotificationRepository interface {
+	// Create stores the notification unless one with the same DedupeKey exists, reporting whether it did
+	Create(ctx context.Context, notification *Notification) (bool, error)
+	ListByUser(ctx context.Context, userID uint, unreadOnly bool, page, limit int) ([]*Notification, int64, error)
+	CountUnread(ctx context.Context, userID uint) (int64, error)
+	MarkRead(ctx context.Context, userID, id uint) error // ErrNotificationNotFound if not the user's
+	MarkAllRead(ctx context.Context, userID uint) (int64, error)
+
+	// CountByAnnouncement counts the inbox entries of an announcement and how many were read
+	CountByAnnouncement(ctx context.Context, announcementID uint) (delivered, opened int64, err error)
+}
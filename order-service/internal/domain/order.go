@@ -1,38 +1,150 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
 
 type OrderStatus string
 
 const (
 	OrderStatusPending    OrderStatus = "pending"    // Order created, waiting for payment
 	OrderStatusPaid       OrderStatus = "paid"       // Payment completed
-	OrderStatusProcessing OrderStatus = "processing" // Order is being processed
+	OrderStatusConfirmed  OrderStatus = "confirmed"  // Accepted by the seller, being prepared
+	OrderStatusProcessing OrderStatus = "processing" // Legacy confirmed status of orders confirmed before "confirmed" existed
 	OrderStatusShipped    OrderStatus = "shipped"    // Order has been shipped
 	OrderStatusDelivered  OrderStatus = "delivered"  // Order has been delivered
 	OrderStatusCancelled  OrderStatus = "cancelled"  // Order has been cancelled
 )
 
+// Fulfillment types of order items (snapshot of the product type at order time)
+const (
+	FulfillmentPhysical = "PHYSICAL" // Shipped to the shipping address
+	FulfillmentDigital  = "DIGITAL"  // Codes issued from the seller's code pool after payment
+)
+
+// Order payment errors
+var (
+	ErrOrderNotPayable       = Conflict("order cannot be paid in its current status")
+	ErrDigitalCashOnDelivery = Validation("digital products must be paid online, not cash on delivery")
+)
+
+// ErrDuplicateOrderNumber is returned when an order number is already taken
+// (primary key of order_numbers); the caller retries with a new number
+var ErrDuplicateOrderNumber = errors.New("order number already exists")
+
+// IssuedOrderNumber keeps order numbers unique: shop_order is partitioned, so its unique
+// keys include ordered_at and cannot do it. One row per order number ever stored, written
+// with the order and kept when the order is archived or deleted
+type IssuedOrderNumber struct {
+	OrderNumber string    `gorm:"primaryKey;size:50"`
+	OrderedAt   time.Time `gorm:"not null"`
+}
+
+// TableName specifies the table name for IssuedOrderNumber
+func (IssuedOrderNumber) TableName() string {
+	return "order_numbers"
+}
+
+// OrderNumberGenerator issues order numbers (implemented by redis.OrderNumberGenerator)
+type OrderNumberGenerator interface {
+	Next(ctx context.Context) (string, error)
+}
+
+// OpenOrderStatuses are the statuses of orders that are not finished yet
+// (their SKUs must stay resolvable until delivery or cancellation)
+var OpenOrderStatuses = []OrderStatus{
+	OrderStatusPending,
+	OrderStatusPaid,
+	OrderStatusConfirmed,
+	OrderStatusProcessing,
+	OrderStatusShipped,
+}
+
+// GMVOrderStatuses are the statuses of orders counted in GMV: paid, or confirmed for cash
+// on delivery, and not cancelled
+var GMVOrderStatuses = []OrderStatus{
+	OrderStatusPaid,
+	OrderStatusConfirmed,
+	OrderStatusProcessing,
+	OrderStatusShipped,
+	OrderStatusDelivered,
+}
+
+// ShopGMV is the gross merchandise value of a shop's orders placed in a period
+type ShopGMV struct {
+	ShopID     uint    `json:"shop_id"`
+	OrderCount int64   `json:"order_count"`
+	GMV        float64 `json:"gmv"`
+}
+
+// CheckoutStatus is the outcome of a multi-shop checkout
+type CheckoutStatus string
+
+const (
+	CheckoutStatusCompleted CheckoutStatus = "COMPLETED" // Every shop_order was created
+	CheckoutStatusFailed    CheckoutStatus = "FAILED"    // A shop_order failed and the created siblings were rolled back
+	CheckoutStatusPartial   CheckoutStatus = "PARTIAL"   // A shop_order failed and some siblings could not be removed (cancelled + compensation events)
+)
+
+// ShopCheckoutResult is the outcome of creating one shop's order within a checkout
+type ShopCheckoutResult struct {
+	ShopID      uint   `json:"shop_id"`
+	OrderID     uint   `json:"order_id,omitempty"`
+	OrderNumber string `json:"order_number,omitempty"`
+	Success     bool   `json:"success"`
+	RolledBack  bool   `json:"rolled_back,omitempty"` // Order was created, then removed because a sibling failed
+	Cancelled   bool   `json:"cancelled,omitempty"`   // Order could not be removed and was cancelled instead
+	Error       string `json:"error,omitempty"`
+}
+
+// CheckoutError is returned when at least one shop_order of a checkout failed
+// Status is FAILED when all created siblings were rolled back, PARTIAL otherwise
+type CheckoutError struct {
+	CheckoutID string
+	Status     CheckoutStatus
+	Shops      []ShopCheckoutResult
+	Err        error // First shop failure
+}
+
+func (e *CheckoutError) Error() string {
+	return fmt.Sprintf("checkout %s %s: %v", e.CheckoutID, strings.ToLower(string(e.Status)), e.Err)
+}
+
+func (e *CheckoutError) Unwrap() error {
+	return e.Err
+}
+
 // Order represents an order in the system (shop_order in db-diagram.db)
 // This is the domain entity - it contains business logic and validation
 // NOTE: Following db-diagram.db schema (SOURCE OF TRUTH)
+// shop_order is partitioned by ordered_at month (see postgres.MigrateOrderPartitions),
+// so ordered_at is part of the primary key; order numbers are unique by IssuedOrderNumber
 type Order struct {
 	ID uint `json:"id" gorm:"primaryKey"`
 
 	// Business identifiers
-	OrderNumber string `json:"order_number" gorm:"size:50;uniqueIndex;not null"`
+	OrderNumber string `json:"order_number" gorm:"size:50;index:idx_shop_order_order_number;not null"`
+	CheckoutID  string `json:"checkout_id" gorm:"size:40;index"` // Groups the shop_orders created by one checkout
 
 	// Ownership
 	UserID uint `json:"user_id" gorm:"index;not null"`
 	ShopID uint `json:"shop_id" gorm:"index;not null"`
 
+	// Shop snapshot at order time (from identity-service)
+	ShopName    string `json:"shop_name" gorm:"size:100"`
+	ShopLogoURL string `json:"shop_logo_url" gorm:"size:255"`
+
 	// Shipping
 	ShippingAddressID uint `json:"shipping_address_id" gorm:"index;not null"`
 
 	// Status
 	Status OrderStatus `json:"status" gorm:"type:varchar(20);not null"`
 
-	// Financial snapshot (SOURCE OF TRUTH)
+	// Financial snapshot at checkout (SOURCE OF TRUTH); later changes are OrderAdjustments
 	MerchandiseSubtotal float64 `json:"merchandise_subtotal" gorm:"type:decimal(15,2);not null"`
 	ShippingFee         float64 `json:"shipping_fee" gorm:"type:decimal(15,2);not null"`
 	ShippingDiscount    float64 `json:"shipping_discount" gorm:"type:decimal(15,2);not null"`
@@ -43,14 +155,29 @@ type Order struct {
 	EarningAmount float64 `json:"earning_amount" gorm:"type:decimal(15,2);not null"`
 
 	// Payment
-	PaymentMethod string `json:"payment_method" gorm:"size:50;not null"`
+	PaymentMethod string     `json:"payment_method" gorm:"size:50;not null"`
+	PaidAt        *time.Time `json:"paid_at,omitempty"`
 
 	// Time
-	OrderedAt time.Time `json:"ordered_at" gorm:"index;not null"`
+	OrderedAt time.Time `json:"ordered_at" gorm:"primaryKey;index;not null"` // Partition key
 	UpdatedAt time.Time `json:"updated_at"`
 
-	// Relations
-	Items []OrderItem `json:"items" gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+	// Data retention: buyer PII removed (user_id, shipping_address_id zeroed), amounts kept
+	AnonymizedAt *time.Time `json:"anonymized_at,omitempty" gorm:"index"`
+
+	// Set when the order was loaded from the archive (shop_order_archive), read-only
+	ArchivedAt *time.Time `json:"archived_at,omitempty" gorm:"-"`
+
+	// Relations (order_line references (id, ordered_at); the constraint is created with the partitions)
+	Items []OrderItem `json:"items" gorm:"foreignKey:OrderID;constraint:-"`
+
+	// Financial changes after checkout and the amounts they lead to; the financial
+	// snapshot above is never updated (order detail only, see DeriveOrderTotals)
+	Adjustments []OrderAdjustment `json:"adjustments,omitempty" gorm:"-"`
+	Totals      *OrderTotals      `json:"totals,omitempty" gorm:"-"`
+
+	// Status changes after checkout, oldest first (order detail only)
+	StatusHistory []OrderStatusHistory `json:"status_history,omitempty" gorm:"-"`
 }
 
 // OrderItem represents an item in an order (order_line in db-diagram.db)
@@ -60,13 +187,51 @@ type OrderItem struct {
 
 	OrderID       uint `json:"order_id" gorm:"index;not null"`
 	ProductItemID uint `json:"product_item_id" gorm:"index;not null"`
+	ProductID     uint `json:"product_id" gorm:"index"` // Base product of the SKU (sales per product)
 
 	Quantity        int     `json:"quantity" gorm:"not null"`
 	PriceAtPurchase float64 `json:"price_at_purchase" gorm:"type:decimal(15,2);not null"`
 
+	// Product snapshot at order time (order page shows these without product lookups)
+	ProductName    string `json:"product_name" gorm:"size:255"`
+	SKUCode        string `json:"sku_code" gorm:"size:100"`
+	ImageURL       string `json:"image_url" gorm:"size:500"`
+	VariationLabel string `json:"variation_label" gorm:"size:255"` // e.g. "Size L, Màu Đen"
+
+	// Fulfillment: DIGITAL items are not shipped, their codes are issued after payment
+	FulfillmentType string     `json:"fulfillment_type" gorm:"size:20;not null;default:'PHYSICAL'"`
+	FulfilledAt     *time.Time `json:"fulfilled_at,omitempty"`
+	DigitalCodes    []string   `json:"digital_codes,omitempty" gorm:"-"` // Loaded from product-service for the order detail only
+
+	OrderedAt time.Time `json:"-" gorm:"primaryKey"` // Partition key, copied from the order
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// IsDigital reports whether the item is fulfilled with digital codes
+func (i *OrderItem) IsDigital() bool {
+	return i.FulfillmentType == FulfillmentDigital
+}
+
+// HasDigitalItems reports whether any item of the order is fulfilled with digital codes
+func (o *Order) HasDigitalItems() bool {
+	for i := range o.Items {
+		if o.Items[i].IsDigital() {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDigitalOnly reports whether the order has nothing to ship
+func (o *Order) IsDigitalOnly() bool {
+	for i := range o.Items {
+		if !o.Items[i].IsDigital() {
+			return false
+		}
+	}
+	return len(o.Items) > 0
+}
+
 // TableName specifies the table name for Order
 // NOTE: Đổi từ "orders" sang "shop_order" theo db-diagram.db
 func (Order) TableName() string {
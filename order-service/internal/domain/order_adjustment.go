@@ -0,0 +1,107 @@
+package domain
+
+import "time"
+
+type OrderAdjustmentType string
+
+// Financial changes made to an order after it was created
+const (
+	OrderAdjustmentRefund             OrderAdjustmentType = "refund"              // Money returned to the buyer (dispute decided for the buyer)
+	OrderAdjustmentFeeAdjustment      OrderAdjustmentType = "fee_adjustment"      // Platform fee changed, the difference goes to or comes from the shop
+	OrderAdjustmentShippingCorrection OrderAdjustmentType = "shipping_correction" // Shipping fee corrected, charged to or returned to the buyer
+)
+
+// ManualOrderAdjustmentTypes can be recorded by an admin; refunds come from disputes
+var ManualOrderAdjustmentTypes = []OrderAdjustmentType{
+	OrderAdjustmentFeeAdjustment,
+	OrderAdjustmentShippingCorrection,
+}
+
+// Order adjustment errors
+var (
+	ErrInvalidAdjustmentType   = Validation("invalid adjustment type")
+	ErrInvalidAdjustmentAmount = Validation("adjustment amount must not be zero")
+	ErrAdjustmentExceedsOrder  = Validation("adjustment would take the order's amounts below zero")
+	ErrOrderNotAdjustable      = Conflict("order can only be adjusted once paid and while not cancelled")
+)
+
+// OrderAdjustment is one financial change made to an order after it was created
+// Adjustments are append-only: the financial columns of shop_order stay the snapshot
+// taken at checkout and the current amounts are derived from the snapshot plus the
+// adjustments (see DeriveOrderTotals). A change is undone by a new, opposite adjustment
+// Deltas are signed: a refund has a negative FinalAmountDelta
+type OrderAdjustment struct {
+	ID uint `json:"id" gorm:"primaryKey"`
+
+	OrderID uint                `json:"order_id" gorm:"index;not null"` // shop_order is partitioned, so no foreign key
+	Type    OrderAdjustmentType `json:"type" gorm:"type:varchar(30);not null"`
+
+	FinalAmountDelta   float64 `json:"final_amount_delta" gorm:"type:decimal(15,2);not null;default:0"`
+	PlatformFeeDelta   float64 `json:"platform_fee_delta" gorm:"type:decimal(15,2);not null;default:0"`
+	EarningAmountDelta float64 `json:"earning_amount_delta" gorm:"type:decimal(15,2);not null;default:0"`
+	ShippingFeeDelta   float64 `json:"shipping_fee_delta" gorm:"type:decimal(15,2);not null;default:0"`
+
+	Reason    string `json:"reason" gorm:"size:255"`
+	DisputeID *uint  `json:"dispute_id,omitempty" gorm:"uniqueIndex"` // Refunds decided in a dispute
+	CreatedBy uint   `json:"created_by,omitempty"`                    // Admin who recorded or decided it
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for OrderAdjustment
+func (OrderAdjustment) TableName() string {
+	return "order_adjustments"
+}
+
+// OrderTotals are the current amounts of an order: the checkout snapshot plus all adjustments
+type OrderTotals struct {
+	ShippingFee    float64 `json:"shipping_fee"`
+	FinalAmount    float64 `json:"final_amount"` // What the buyer paid, net of refunds
+	PlatformFee    float64 `json:"platform_fee"`
+	EarningAmount  float64 `json:"earning_amount"`  // What the shop earns
+	RefundedAmount float64 `json:"refunded_amount"` // Sum of refunds
+}
+
+// DeriveOrderTotals folds the adjustments (oldest first) into the order's snapshot
+func DeriveOrderTotals(order *Order, adjustments []OrderAdjustment) OrderTotals {
+	totals := OrderTotals{
+		ShippingFee:   order.ShippingFee,
+		FinalAmount:   order.FinalAmount,
+		PlatformFee:   order.PlatformFee,
+		EarningAmount: order.EarningAmount,
+	}
+	for i := range adjustments {
+		totals = totals.Apply(&adjustments[i])
+	}
+	return totals
+}
+
+// Apply returns the totals after one more adjustment
+func (t OrderTotals) Apply(a *OrderAdjustment) OrderTotals {
+	t.ShippingFee += a.ShippingFeeDelta
+	t.FinalAmount += a.FinalAmountDelta
+	t.PlatformFee += a.PlatformFeeDelta
+	t.EarningAmount += a.EarningAmountDelta
+	if a.Type == OrderAdjustmentRefund {
+		t.RefundedAmount -= a.FinalAmountDelta
+	}
+	return t
+}
+
+// Valid reports whether no amount the buyer pays or the shop earns went below zero
+// (the platform fee may: the platform bears the part of a refund above its fee)
+func (t OrderTotals) Valid() bool {
+	return t.FinalAmount >= 0 && t.EarningAmount >= 0 && t.ShippingFee >= 0
+}
+
+// CanBeAdjusted reports whether money changed hands for the order: it is paid (or past
+// payment) and not cancelled
+func (o *Order) CanBeAdjusted() bool {
+	return o.Status != OrderStatusPending && o.Status != OrderStatusCancelled
+}
+
+// OrderAdjustedMetadata is the metadata of the order_adjusted event
+type OrderAdjustedMetadata struct {
+	Adjustment *OrderAdjustment `json:"adjustment"`
+	Totals     OrderTotals      `json:"totals"`
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ArchivedOrder is a finished order moved out of shop_order by the archive job
+// The columns are a thin index for lookups (by ID, order number, buyer, shop); the
+// order and its items are kept as gzipped JSON, stored out of line by Postgres (TOAST)
+type ArchivedOrder struct {
+	OrderID     uint        `json:"order_id" gorm:"primaryKey;autoIncrement:false"`
+	OrderNumber string      `json:"order_number" gorm:"size:50;uniqueIndex;not null"`
+	UserID      uint        `json:"user_id" gorm:"index;not null"`
+	ShopID      uint        `json:"shop_id" gorm:"index;not null"`
+	Status      OrderStatus `json:"status" gorm:"type:varchar(20);not null"`
+	FinalAmount float64     `json:"final_amount" gorm:"type:decimal(15,2);not null"`
+	OrderedAt   time.Time   `json:"ordered_at" gorm:"index;not null"`
+	ArchivedAt  time.Time   `json:"archived_at" gorm:"not null"`
+
+	// Data retention: buyer PII removed from the index and the archived order
+	AnonymizedAt *time.Time `json:"anonymized_at,omitempty" gorm:"index"`
+
+	Data []byte `json:"-" gorm:"type:bytea;not null"` // gzip(JSON of Order with Items)
+}
+
+// TableName specifies the table name for ArchivedOrder
+func (ArchivedOrder) TableName() string {
+	return "shop_order_archive"
+}
+
+// NewArchivedOrder packs an order (with its items loaded) for the archive
+func NewArchivedOrder(order *Order, archivedAt time.Time) (*ArchivedOrder, error) {
+	archived := &ArchivedOrder{
+		OrderID:      order.ID,
+		OrderNumber:  order.OrderNumber,
+		UserID:       order.UserID,
+		ShopID:       order.ShopID,
+		Status:       order.Status,
+		FinalAmount:  order.FinalAmount,
+		OrderedAt:    order.OrderedAt,
+		ArchivedAt:   archivedAt,
+		AnonymizedAt: order.AnonymizedAt,
+	}
+	if err := archived.SetOrder(order); err != nil {
+		return nil, err
+	}
+	return archived, nil
+}
+
+// SetOrder replaces the archived order data
+func (a *ArchivedOrder) SetOrder(order *Order) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(order); err != nil {
+		return fmt.Errorf("failed to encode archived order %d: %w", order.ID, err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to compress archived order %d: %w", order.ID, err)
+	}
+	a.Data = buf.Bytes()
+	return nil
+}
+
+// Order unpacks the archived order; ArchivedAt is set so callers can tell it apart
+func (a *ArchivedOrder) Order() (*Order, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(a.Data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress archived order %d: %w", a.OrderID, err)
+	}
+	defer zr.Close()
+
+	raw, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress archived order %d: %w", a.OrderID, err)
+	}
+
+	var order Order
+	if err := json.Unmarshal(raw, &order); err != nil {
+		return nil, fmt.Errorf("failed to decode archived order %d: %w", a.OrderID, err)
+	}
+	archivedAt := a.ArchivedAt
+	order.ArchivedAt = &archivedAt
+	return &order, nil
+}
@@ -0,0 +1,106 @@
+package domain
+
+import "time"
+
+// OrderEventSchemaVersion is the version of the OrderEvent payload
+// v1 embedded the Order persistence model; v2 carries OrderPayload, which only
+// changes together with this version
+const OrderEventSchemaVersion = 2
+
+// OrderPayload is the order as published in events, decoupled from the GORM model
+// Add fields freely; renaming or removing one requires a new schema version
+type OrderPayload struct {
+	ID          uint        `json:"id"`
+	OrderNumber string      `json:"order_number"`
+	CheckoutID  string      `json:"checkout_id,omitempty"`
+	UserID      uint        `json:"user_id"`
+	ShopID      uint        `json:"shop_id"`
+	Status      OrderStatus `json:"status"`
+
+	MerchandiseSubtotal float64 `json:"merchandise_subtotal"`
+	ShippingFee         float64 `json:"shipping_fee"`
+	ShippingDiscount    float64 `json:"shipping_discount"`
+	VoucherDiscount     float64 `json:"voucher_discount"`
+	FinalAmount         float64 `json:"final_amount"`
+	PlatformFee         float64 `json:"platform_fee"`
+	EarningAmount       float64 `json:"earning_amount"`
+	PaymentMethod       string  `json:"payment_method"`
+
+	PaidAt *time.Time `json:"paid_at,omitempty"`
+
+	ItemCount int                `json:"item_count"` // Total quantity over all items
+	Items     []OrderItemPayload `json:"items"`
+
+	OrderedAt time.Time `json:"ordered_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// OrderItemPayload is an order line as published in events
+type OrderItemPayload struct {
+	ProductID      uint    `json:"product_id"`
+	ProductItemID  uint    `json:"product_item_id"`
+	ProductName    string  `json:"product_name,omitempty"`
+	SKUCode        string  `json:"sku_code,omitempty"`
+	VariationLabel string  `json:"variation_label,omitempty"`
+	Quantity       int     `json:"quantity"`
+	UnitPrice      float64 `json:"unit_price"`
+	LineTotal      float64 `json:"line_total"`
+
+	FulfillmentType string `json:"fulfillment_type,omitempty"` // PHYSICAL, DIGITAL (codes themselves are never published)
+}
+
+// NewOrderPayload builds the event payload of an order
+func NewOrderPayload(order *Order) *OrderPayload {
+	payload := &OrderPayload{
+		ID:          order.ID,
+		OrderNumber: order.OrderNumber,
+		CheckoutID:  order.CheckoutID,
+		UserID:      order.UserID,
+		ShopID:      order.ShopID,
+		Status:      order.Status,
+
+		MerchandiseSubtotal: order.MerchandiseSubtotal,
+		ShippingFee:         order.ShippingFee,
+		ShippingDiscount:    order.ShippingDiscount,
+		VoucherDiscount:     order.VoucherDiscount,
+		FinalAmount:         order.FinalAmount,
+		PlatformFee:         order.PlatformFee,
+		EarningAmount:       order.EarningAmount,
+		PaymentMethod:       order.PaymentMethod,
+		PaidAt:              order.PaidAt,
+
+		Items: make([]OrderItemPayload, 0, len(order.Items)),
+
+		OrderedAt: order.OrderedAt,
+		UpdatedAt: order.UpdatedAt,
+	}
+
+	for _, item := range order.Items {
+		payload.ItemCount += item.Quantity
+		payload.Items = append(payload.Items, OrderItemPayload{
+			ProductID:      item.ProductID,
+			ProductItemID:  item.ProductItemID,
+			ProductName:    item.ProductName,
+			SKUCode:        item.SKUCode,
+			VariationLabel: item.VariationLabel,
+			Quantity:       item.Quantity,
+			UnitPrice:      item.PriceAtPurchase,
+			LineTotal:      item.PriceAtPurchase * float64(item.Quantity),
+
+			FulfillmentType: item.FulfillmentType,
+		})
+	}
+	return payload
+}
+
+// NewOrderEvent creates a current-version event for an order
+func NewOrderEvent(eventType string, order *Order, metadata interface{}) *OrderEvent {
+	return &OrderEvent{
+		EventType:     eventType,
+		SchemaVersion: OrderEventSchemaVersion,
+		OrderID:       order.ID,
+		Order:         NewOrderPayload(order),
+		Timestamp:     time.Now(),
+		Metadata:      metadata,
+	}
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// orderStatusTransitions is the order status state machine:
+//
+//	pending -> paid -> confirmed -> shipped -> delivered
+//	pending -> confirmed (cash on delivery, confirmed by the seller before payment)
+//	paid -> delivered (digital-only orders, once their codes are issued)
+//	pending | confirmed -> cancelled (only while nothing was charged)
+//
+// delivered and cancelled are final. Nothing moves to processing anymore: orders confirmed
+// before confirmed existed keep it and leave it like confirmed orders do
+var orderStatusTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending:    {OrderStatusPaid, OrderStatusConfirmed, OrderStatusCancelled},
+	OrderStatusPaid:       {OrderStatusConfirmed, OrderStatusDelivered},
+	OrderStatusConfirmed:  {OrderStatusShipped, OrderStatusCancelled},
+	OrderStatusProcessing: {OrderStatusShipped, OrderStatusCancelled},
+	OrderStatusShipped:    {OrderStatusDelivered},
+}
+
+// OrderStatuses are all order statuses
+var OrderStatuses = []OrderStatus{
+	OrderStatusPending,
+	OrderStatusPaid,
+	OrderStatusConfirmed,
+	OrderStatusProcessing,
+	OrderStatusShipped,
+	OrderStatusDelivered,
+	OrderStatusCancelled,
+}
+
+// Actors of order status changes
+const (
+	StatusActorBuyer  = "buyer"
+	StatusActorSeller = "seller"
+	StatusActorAdmin  = "admin"
+	StatusActorSystem = "system" // Payment confirmation, digital fulfillment, checkout rollback
+)
+
+// Order status errors
+var (
+	ErrInvalidOrderStatus      = Validation("invalid order status")
+	ErrInvalidStatusTransition = Conflict("order cannot move to this status from its current status")
+	ErrOrderNotPaid            = Conflict("order must be paid before it is confirmed (only cash on delivery orders are confirmed unpaid)")
+	ErrPaidOrderNotCancellable = Conflict("paid orders cannot be cancelled, open a dispute for a refund")
+	ErrStatusChangeForbidden   = Forbidden("not allowed to move the order to this status")
+)
+
+// IsCashOnDelivery reports whether the order is paid on delivery
+func (o *Order) IsCashOnDelivery() bool {
+	return strings.EqualFold(o.PaymentMethod, "COD")
+}
+
+// CheckTransition returns why the order cannot move to status to, nil if it can
+func (o *Order) CheckTransition(to OrderStatus) error {
+	if !slices.Contains(orderStatusTransitions[o.Status], to) {
+		return ErrInvalidStatusTransition
+	}
+	switch {
+	case o.Status == OrderStatusPending && to == OrderStatusConfirmed && !o.IsCashOnDelivery():
+		return ErrOrderNotPaid
+	case o.Status == OrderStatusPaid && to == OrderStatusDelivered && !o.IsDigitalOnly():
+		return ErrInvalidStatusTransition
+	case to == OrderStatusCancelled && o.PaidAt != nil:
+		return ErrPaidOrderNotCancellable
+	}
+	return nil
+}
+
+// OrderStatusHistory is one status change of an order (append-only)
+type OrderStatusHistory struct {
+	ID uint `json:"id" gorm:"primaryKey"`
+
+	OrderID    uint        `json:"order_id" gorm:"index;not null"` // shop_order is partitioned, so no foreign key
+	FromStatus OrderStatus `json:"from_status" gorm:"type:varchar(20);not null"`
+	ToStatus   OrderStatus `json:"to_status" gorm:"type:varchar(20);not null"`
+
+	Actor     string `json:"actor" gorm:"size:10;not null"` // buyer, seller, admin, system
+	ChangedBy uint   `json:"changed_by,omitempty"`          // User ID (none for system changes)
+	Reason    string `json:"reason,omitempty" gorm:"size:255"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for OrderStatusHistory
+func (OrderStatusHistory) TableName() string {
+	return "order_status_history"
+}
+
+// OrderStatusChangedMetadata is the metadata of the order_status_changed event
+type OrderStatusChangedMetadata struct {
+	FromStatus OrderStatus `json:"from_status"`
+	ToStatus   OrderStatus `json:"to_status"`
+	Actor      string      `json:"actor"`
+	ChangedBy  uint        `json:"changed_by,omitempty"`
+	Reason     string      `json:"reason,omitempty"`
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckTransition(t *testing.T) {
+	paidAt := time.Now()
+	physical := []OrderItem{{FulfillmentType: FulfillmentPhysical}}
+	digital := []OrderItem{{FulfillmentType: FulfillmentDigital}}
+
+	tests := []struct {
+		name  string
+		order Order
+		to    OrderStatus
+		want  error
+	}{
+		{"pending -> paid", Order{Status: OrderStatusPending, Items: physical}, OrderStatusPaid, nil},
+		{"paid -> confirmed", Order{Status: OrderStatusPaid, PaidAt: &paidAt, Items: physical}, OrderStatusConfirmed, nil},
+		{"confirmed -> shipped", Order{Status: OrderStatusConfirmed, PaidAt: &paidAt, Items: physical}, OrderStatusShipped, nil},
+		{"shipped -> delivered", Order{Status: OrderStatusShipped, PaidAt: &paidAt, Items: physical}, OrderStatusDelivered, nil},
+		{"cash on delivery confirmed unpaid", Order{Status: OrderStatusPending, PaymentMethod: "COD", Items: physical}, OrderStatusConfirmed, nil},
+		{"online payment confirmed unpaid", Order{Status: OrderStatusPending, PaymentMethod: "VNPAY", Items: physical}, OrderStatusConfirmed, ErrOrderNotPaid},
+		{"unpaid pending cancelled", Order{Status: OrderStatusPending, Items: physical}, OrderStatusCancelled, nil},
+		{"unpaid confirmed cancelled", Order{Status: OrderStatusConfirmed, PaymentMethod: "COD", Items: physical}, OrderStatusCancelled, nil},
+		{"paid confirmed cancelled", Order{Status: OrderStatusConfirmed, PaidAt: &paidAt, Items: physical}, OrderStatusCancelled, ErrPaidOrderNotCancellable},
+		{"digital-only paid -> delivered", Order{Status: OrderStatusPaid, PaidAt: &paidAt, Items: digital}, OrderStatusDelivered, nil},
+		{"physical paid -> delivered", Order{Status: OrderStatusPaid, PaidAt: &paidAt, Items: physical}, OrderStatusDelivered, ErrInvalidStatusTransition},
+		{"pending -> shipped", Order{Status: OrderStatusPending, PaymentMethod: "COD", Items: physical}, OrderStatusShipped, ErrInvalidStatusTransition},
+		{"paid -> processing", Order{Status: OrderStatusPaid, PaidAt: &paidAt, Items: physical}, OrderStatusProcessing, ErrInvalidStatusTransition},
+		{"legacy processing -> shipped", Order{Status: OrderStatusProcessing, PaidAt: &paidAt, Items: physical}, OrderStatusShipped, nil},
+		{"delivered is final", Order{Status: OrderStatusDelivered, PaidAt: &paidAt, Items: physical}, OrderStatusCancelled, ErrInvalidStatusTransition},
+		{"cancelled is final", Order{Status: OrderStatusCancelled, Items: physical}, OrderStatusConfirmed, ErrInvalidStatusTransition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.order.CheckTransition(tt.to); !errors.Is(err, tt.want) {
+				t.Fatalf("CheckTransition(%s -> %s) = %v, want %v", tt.order.Status, tt.to, err, tt.want)
+			}
+		})
+	}
+}
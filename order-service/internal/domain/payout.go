@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+type PayoutStatus string
+
+const (
+	PayoutStatusPending PayoutStatus = "pending" // Batch created, waiting for transfer
+	PayoutStatusPaid    PayoutStatus = "paid"    // Transferred to shop
+)
+
+// PayoutBatch aggregates a shop's earnings over one period (one batch per shop per day)
+// Created by the daily payout background job
+type PayoutBatch struct {
+	ID uint `json:"id" gorm:"primaryKey"`
+
+	ShopID      uint      `json:"shop_id" gorm:"uniqueIndex:idx_payout_shop_period;not null"`
+	PeriodStart time.Time `json:"period_start" gorm:"uniqueIndex:idx_payout_shop_period;not null"`
+	PeriodEnd   time.Time `json:"period_end" gorm:"not null"`
+
+	OrderCount    int64   `json:"order_count" gorm:"not null"`
+	TotalAmount   float64 `json:"total_amount" gorm:"type:decimal(15,2);not null"`   // Sum of final_amount
+	PlatformFee   float64 `json:"platform_fee" gorm:"type:decimal(15,2);not null"`   // Sum of platform_fee
+	EarningAmount float64 `json:"earning_amount" gorm:"type:decimal(15,2);not null"` // Amount to transfer to shop (adjustments included)
+
+	AdjustmentAmount float64 `json:"adjustment_amount" gorm:"type:decimal(15,2);not null;default:0"` // Sum of applied payout adjustments (e.g. dispute refunds)
+
+	Status PayoutStatus `json:"status" gorm:"type:varchar(20);not null"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ShopEarning is the per-shop aggregate of delivered orders in a period
+type ShopEarning struct {
+	ShopID        uint
+	OrderCount    int64
+	TotalAmount   float64
+	PlatformFee   float64
+	EarningAmount float64
+}
+
+// TableName specifies the table name for PayoutBatch
+func (PayoutBatch) TableName() string {
+	return "payout_batch"
+}
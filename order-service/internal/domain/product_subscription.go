@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type SubscriptionType string
+
+const (
+	SubscriptionPriceDrop   SubscriptionType = "price_drop"    // Notify when the price falls to TargetPrice or below
+	SubscriptionBackInStock SubscriptionType = "back_in_stock" // Notify when a sold out product is available again
+)
+
+// ProductSubscription is a user's request to be notified about a product (or one of its SKUs)
+// One subscription per user, product, SKU and type; subscribing again updates it
+type ProductSubscription struct {
+	ID uint `json:"id" gorm:"primaryKey"`
+
+	UserID        uint             `json:"user_id" gorm:"uniqueIndex:idx_product_subscription;not null"`
+	ProductID     uint             `json:"product_id" gorm:"uniqueIndex:idx_product_subscription;index;not null"`
+	ProductItemID uint             `json:"product_item_id" gorm:"uniqueIndex:idx_product_subscription;not null;default:0"` // 0 = any SKU of the product
+	Type          SubscriptionType `json:"type" gorm:"uniqueIndex:idx_product_subscription;type:varchar(20);not null"`
+	TargetPrice   float64          `json:"target_price,omitempty" gorm:"type:decimal(15,2)"` // price_drop only
+
+	// Dedupe state, compared and swapped on every notification (see NotifyCount)
+	NotifiedPrice  *float64   `json:"notified_price,omitempty" gorm:"type:decimal(15,2)"` // price_drop: price last notified, nil = armed
+	InStock        bool       `json:"in_stock"`                                           // back_in_stock: availability last seen
+	NotifyCount    int        `json:"notify_count" gorm:"not null;default:0"`
+	LastNotifiedAt *time.Time `json:"last_notified_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for ProductSubscription
+func (ProductSubscription) TableName() string {
+	return "product_subscriptions"
+}
+
+// Subscription errors
+var (
+	ErrSubscriptionNotFound        = NotFound("subscription not found")
+	ErrSubscriptionProductNotFound = NotFound("product not found")
+	ErrInvalidSubscriptionType     = Validation("type must be price_drop or back_in_stock")
+	ErrInvalidTargetPrice          = Validation("target_price must be greater than 0 for price_drop")
+	ErrSubscriptionLimit           = errors.New("too many product subscriptions")
+)
+
+// SubscriptionRepository stores product subscriptions (implemented by postgres.SubscriptionRepository)
+type SubscriptionRepository interface {
+	Upsert(ctx context.Context, sub *ProductSubscription) error
+	ListByUser(ctx context.Context, userID uint) ([]*ProductSubscription, error)
+	ListByProduct(ctx context.Context, productID uint) ([]*ProductSubscription, error)
+	Delete(ctx context.Context, userID, id uint) error // ErrSubscriptionNotFound if not the user's
+
+	// SaveState writes the dedupe state only if NotifyCount is still expectedCount,
+	// reporting whether it did; the caller that wins notifies, the others skip
+	SaveState(ctx context.Context, sub *ProductSubscription, expectedCount int) (bool, error)
+}
+
+// Notification event types (topics notification.price_drop, notification.back_in_stock, notification.digital_codes_issued,
+// notification.announcement)
+const (
+	EventNotificationPriceDrop          = "notification_price_drop"
+	EventNotificationBackInStock        = "notification_back_in_stock"
+	EventNotificationDigitalCodesIssued = "notification_digital_codes_issued" // Codes are on the order detail, never in the event
+	EventNotificationAnnouncement       = "notification_announcement"         // Admin broadcast, the message is in Title/Body/Link
+)
+
+// NotificationEvent asks the notification pipeline to tell a user something
+// DedupeKey is unique per notification: redeliveries of the same event carry the same key
+type NotificationEvent struct {
+	EventType      string    `json:"event_type"`
+	DedupeKey      string    `json:"dedupe_key"`
+	UserID         uint      `json:"user_id"`
+	SubscriptionID uint      `json:"subscription_id,omitempty"`
+	OrderID        uint      `json:"order_id,omitempty"`
+	OrderNumber    string    `json:"order_number,omitempty"`
+	ProductID      uint      `json:"product_id"`
+	ProductItemID  uint      `json:"product_item_id,omitempty"`
+	ProductName    string    `json:"product_name,omitempty"`
+	Price          float64   `json:"price"`
+	TargetPrice    float64   `json:"target_price,omitempty"`
+	QtyInStock     int       `json:"qty_in_stock"`
+	AnnouncementID uint      `json:"announcement_id,omitempty"`
+	Title          string    `json:"title,omitempty"`
+	Body           string    `json:"body,omitempty"`
+	Link           string    `json:"link,omitempty"`
+	Channels       []string  `json:"channels"` // Channels the user enabled for this kind of notification (email, sms, push)
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// NotificationDedupeKey identifies the n-th notification of a subscription
+func NotificationDedupeKey(subscriptionID uint, n int) string {
+	return fmt.Sprintf("product_subscription:%d:%d", subscriptionID, n)
+}
+
+// NotificationEventPublisher publishes notification events (keyed by user ID)
+type NotificationEventPublisher interface {
+	PublishNotificationEvent(event *NotificationEvent) error
+}
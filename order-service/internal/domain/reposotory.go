@@ -19,4 +19,15 @@ type CartRepository interface {
 
 	// Utility
 	GetCartItemCount(userID string) (int, error)
+
+	// Product index: which carts hold items of a product (for product_updated events)
+	// Entries may be stale - callers re-check the cart
+	IndexProduct(productID uint, userID string) error
+	UnindexProduct(productID uint, userID string) error
+	GetUsersByProduct(productID uint) ([]string, error)
+
+	// Backup sync: carts changed since the last sync, and raw stored carts
+	PopBackupDirty(count int) ([]string, error)
+	MarkBackupDirty(userIDs ...string) error
+	GetRawCart(userID string) (string, error)
 }
@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+type VoucherType string
+
+// How a voucher's discount is computed
+const (
+	VoucherTypePercent VoucherType = "percent" // Value % of the amount it applies to, up to MaxDiscount
+	VoucherTypeFixed   VoucherType = "fixed"   // Value off, up to the amount it applies to
+)
+
+type VoucherTarget string
+
+// What a voucher's discount applies to
+const (
+	VoucherTargetMerchandise VoucherTarget = "merchandise" // Mã giảm giá: the merchandise subtotal
+	VoucherTargetShipping    VoucherTarget = "shipping"    // Mã freeship: the shipping fee
+)
+
+// Voucher errors
+var (
+	ErrVoucherNotFound      = NotFound("voucher not found")
+	ErrVoucherCodeExists    = Conflict("a voucher with this code already exists")
+	ErrVoucherInUse         = Conflict("voucher has been redeemed, deactivate it instead")
+	ErrInvalidVoucher       = Validation("invalid voucher: percent must be between 0 and 100, amounts must not be negative and ends_at must be after starts_at")
+	ErrVoucherNotApplicable = Validation("voucher cannot be used for this checkout")
+	ErrVoucherUsedUp        = Conflict("voucher has reached its usage limit")
+	ErrDuplicateVoucher     = Validation("only one voucher per shop (and one platform voucher) of each kind can be used")
+)
+
+// Voucher is a discount code created by an admin (platform voucher, ShopID nil, split
+// across the shops of a checkout) or by a seller for their shop (only reduces that
+// shop's order). Discounts are computed server-side at checkout
+type Voucher struct {
+	ID uint `json:"id" gorm:"primaryKey"`
+
+	Code   string        `json:"code" gorm:"size:40;not null;uniqueIndex"` // Upper case
+	ShopID *uint         `json:"shop_id,omitempty" gorm:"index"`           // nil = platform voucher
+	Type   VoucherType   `json:"type" gorm:"type:varchar(10);not null"`
+	Target VoucherTarget `json:"target" gorm:"type:varchar(20);not null;default:'merchandise'"`
+
+	Value         float64 `json:"value" gorm:"type:decimal(15,2);not null"`                     // Percent or VND
+	MaxDiscount   float64 `json:"max_discount" gorm:"type:decimal(15,2);not null;default:0"`    // Cap of percent vouchers (0 = none)
+	MinOrderValue float64 `json:"min_order_value" gorm:"type:decimal(15,2);not null;default:0"` // Merchandise subtotal of the shop (platform: of the checkout)
+
+	UsageLimit   int `json:"usage_limit" gorm:"not null;default:0"`    // Checkouts in total (0 = unlimited)
+	PerUserLimit int `json:"per_user_limit" gorm:"not null;default:0"` // Checkouts per buyer (0 = unlimited)
+	UsedCount    int `json:"used_count" gorm:"not null;default:0"`
+
+	StartsAt time.Time  `json:"starts_at" gorm:"not null"`
+	EndsAt   *time.Time `json:"ends_at,omitempty"` // nil = no end
+	IsActive bool       `json:"is_active" gorm:"not null;default:true"`
+
+	CreatedBy uint      `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for Voucher
+func (Voucher) TableName() string {
+	return "vouchers"
+}
+
+// NormalizeVoucherCode returns the stored form of a code (trimmed, upper case)
+func NormalizeVoucherCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// IsPlatform reports whether the voucher is the platform's, not a shop's
+func (v *Voucher) IsPlatform() bool {
+	return v.ShopID == nil
+}
+
+// Valid reports whether the voucher's settings are consistent
+func (v *Voucher) Valid() bool {
+	switch v.Type {
+	case VoucherTypePercent:
+		if v.Value <= 0 || v.Value > 100 {
+			return false
+		}
+	case VoucherTypeFixed:
+		if v.Value <= 0 {
+			return false
+		}
+	default:
+		return false
+	}
+	if v.Target != VoucherTargetMerchandise && v.Target != VoucherTargetShipping {
+		return false
+	}
+	if v.MaxDiscount < 0 || v.MinOrderValue < 0 || v.UsageLimit < 0 || v.PerUserLimit < 0 {
+		return false
+	}
+	return v.EndsAt == nil || v.EndsAt.After(v.StartsAt)
+}
+
+// IsRedeemableAt reports whether the voucher is active and within its validity window
+// (usage limits are checked when it is redeemed)
+func (v *Voucher) IsRedeemableAt(t time.Time) bool {
+	return v.IsActive && !t.Before(v.StartsAt) && (v.EndsAt == nil || t.Before(*v.EndsAt))
+}
+
+// VoucherRedemption is one use of a voucher by a checkout (one per voucher and checkout)
+// Released again if the checkout fails
+type VoucherRedemption struct {
+	ID uint `json:"id" gorm:"primaryKey"`
+
+	VoucherID  uint    `json:"voucher_id" gorm:"not null;uniqueIndex:idx_voucher_redemption"`
+	CheckoutID string  `json:"checkout_id" gorm:"size:40;not null;uniqueIndex:idx_voucher_redemption;index"`
+	UserID     uint    `json:"user_id" gorm:"index;not null"`
+	Code       string  `json:"code" gorm:"size:40;not null"`
+	Discount   float64 `json:"discount" gorm:"type:decimal(15,2);not null"` // Before the checkout's caps are applied
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for VoucherRedemption
+func (VoucherRedemption) TableName() string {
+	return "voucher_redemptions"
+}
+
+// VoucherFilter selects vouchers to list
+type VoucherFilter struct {
+	ShopID   *uint // nil = all (admin); set = one shop's vouchers
+	Platform bool  // Only platform vouchers
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"order-service/internal/domain"
+	"order-service/internal/service"
+	"order-service/pkg/xlsx"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// AccountingHandler handles HTTP requests for the finance team's journal export
+type AccountingHandler struct {
+	accountingService *service.AccountingService
+	logger            *zap.Logger
+}
+
+// NewAccountingHandler creates a new accounting handler
+func NewAccountingHandler(accountingService *service.AccountingService, logger *zap.Logger) *AccountingHandler {
+	return &AccountingHandler{
+		accountingService: accountingService,
+		logger:            logger,
+	}
+}
+
+// ExportJournal handles GET /admin/accounting/journal/export
+// @Summary Export the accounting journal (admin)
+// @Description Download the double-entry journal lines (payment gateway receivable, seller payable, platform fee revenue, refunds payable) posted from order payments and adjustments in a period, one row per line with debit and credit totals
+// @Tags Accounting
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param from query string true "First day (YYYY-MM-DD)"
+// @Param to query string true "Last day, included (YYYY-MM-DD)"
+// @Param format query string false "csv (default) or xlsx"
+// @Success 200 {file} file "Journal"
+// @Failure 400 {object} map[string]string "Invalid period or format"
+// @Failure 403 {object} map[string]string "Admin permission required"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/accounting/journal/export [get]
+func (h *AccountingHandler) ExportJournal(c *gin.Context) {
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "xlsx" {
+		respondError(c, h.logger, "failed to export journal", domain.ErrInvalidExportFormat)
+		return
+	}
+	from, to, err := service.JournalPeriod(c.Query("from"), c.Query("to"))
+	if err != nil {
+		respondError(c, h.logger, "failed to export journal", err)
+		return
+	}
+
+	rows, err := h.accountingService.ExportJournalRows(from, to)
+	if err != nil {
+		respondError(c, h.logger, "failed to export journal", err)
+		return
+	}
+
+	var buf bytes.Buffer
+	contentType := "text/csv; charset=utf-8"
+	if format == "xlsx" {
+		contentType = xlsxContentType
+		err = xlsx.Write(&buf, "Journal", rows)
+	} else {
+		err = writeCSV(&buf, rows)
+	}
+	if err != nil {
+		respondError(c, h.logger, "failed to export journal", err)
+		return
+	}
+
+	filename := fmt.Sprintf("journal-%s-%s.%s", c.Query("from"), c.Query("to"), format)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// writeCSV writes sheet rows as CSV; amounts keep two decimals
+func writeCSV(buf *bytes.Buffer, rows [][]any) error {
+	w := csv.NewWriter(buf)
+	for _, row := range rows {
+		record := make([]string, len(row))
+		for i, value := range row {
+			switch v := value.(type) {
+			case nil:
+			case float64:
+				record[i] = strconv.FormatFloat(v, 'f', 2, 64)
+			default:
+				record[i] = fmt.Sprint(v)
+			}
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"net/http"
+	"order-service/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AnnouncementHandler handles HTTP requests for admin announcement broadcasts
+type AnnouncementHandler struct {
+	announcementService *service.AnnouncementService
+	logger              *zap.Logger
+}
+
+// NewAnnouncementHandler creates a new announcement handler
+func NewAnnouncementHandler(announcementService *service.AnnouncementService, logger *zap.Logger) *AnnouncementHandler {
+	return &AnnouncementHandler{
+		announcementService: announcementService,
+		logger:              logger,
+	}
+}
+
+// CreateAnnouncement handles POST /admin/announcements
+// @Summary Broadcast an announcement (admin)
+// @Description Queue a message to a segment: buyers, category_sellers (category_id) or inactive_users (inactive_days without a login). It is fanned out in the background, throttled, to the recipients' inbox and the channels they enabled for promotions
+// @Tags Announcements
+// @Accept json
+// @Produce json
+// @Param request body service.CreateAnnouncementRequest true "Announcement"
+// @Success 202 {object} domain.Announcement "Announcement queued"
+// @Failure 400 {object} map[string]string "Invalid announcement"
+// @Failure 403 {object} map[string]string "Admin permission required"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/announcements [post]
+func (h *AnnouncementHandler) CreateAnnouncement(c *gin.Context) {
+	adminID, ok := subscriptionUserID(c)
+	if !ok {
+		return
+	}
+
+	var req service.CreateAnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	announcement, err := h.announcementService.Create(c.Request.Context(), adminID, &req)
+	if err != nil {
+		respondError(c, h.logger, "failed to create announcement", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, announcement)
+}
+
+// ListAnnouncements handles GET /admin/announcements
+// @Summary List announcements (admin)
+// @Description Announcements, newest first, with their status and fan-out progress
+// @Tags Announcements
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{} "Announcements and total"
+// @Failure 403 {object} map[string]string "Admin permission required"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/announcements [get]
+func (h *AnnouncementHandler) ListAnnouncements(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	announcements, total, err := h.announcementService.List(c.Request.Context(), page, limit)
+	if err != nil {
+		respondError(c, h.logger, "failed to list announcements", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"announcements": announcements,
+		"total":         total,
+		"page":          page,
+		"limit":         limit,
+	})
+}
+
+// GetAnnouncement handles GET /admin/announcements/:id
+// @Summary Get an announcement (admin)
+// @Description Announcement with its delivery stats: events sent, delivered to the inbox, opened and open rate
+// @Tags Announcements
+// @Produce json
+// @Param id path int true "Announcement ID"
+// @Success 200 {object} map[string]interface{} "Announcement and stats"
+// @Failure 403 {object} map[string]string "Admin permission required"
+// @Failure 404 {object} map[string]string "Announcement not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/announcements/{id} [get]
+func (h *AnnouncementHandler) GetAnnouncement(c *gin.Context) {
+	id, ok := announcementID(c)
+	if !ok {
+		return
+	}
+
+	announcement, stats, err := h.announcementService.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, "failed to get announcement", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"announcement": announcement,
+		"stats":        stats,
+	})
+}
+
+// CancelAnnouncement handles POST /admin/announcements/:id/cancel
+// @Summary Cancel an announcement (admin)
+// @Description Stop the fan-out of a queued or sending announcement; users already notified keep it
+// @Tags Announcements
+// @Produce json
+// @Param id path int true "Announcement ID"
+// @Success 200 {object} domain.Announcement "Announcement canceled"
+// @Failure 403 {object} map[string]string "Admin permission required"
+// @Failure 404 {object} map[string]string "Announcement not found"
+// @Failure 409 {object} map[string]string "Announcement already finished"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/announcements/{id}/cancel [post]
+func (h *AnnouncementHandler) CancelAnnouncement(c *gin.Context) {
+	id, ok := announcementID(c)
+	if !ok {
+		return
+	}
+
+	announcement, err := h.announcementService.Cancel(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, h.logger, "failed to cancel announcement", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, announcement)
+}
+
+// announcementID parses the :id path parameter; answers 400 if invalid
+func announcementID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid announcement id"})
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -1,8 +1,9 @@
 package handler
 
 import (
-	"log"
+	"errors"
 	"net/http"
+	"order-service/internal/domain"
 	"order-service/internal/service"
 	"strconv"
 
@@ -40,7 +41,7 @@ type UpdateItemRequest struct {
 
 // GetCart handles GET /cart
 // @Summary Get cart
-// @Description Get the shopping cart for the current user
+// @Description Get the shopping cart for the current user or guest; a guest cart is merged into the user's cart on the first request after login
 // @Tags Cart
 // @Produce json
 // @Success 200 {object} domain.Cart "Cart retrieved successfully"
@@ -48,18 +49,22 @@ type UpdateItemRequest struct {
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /cart [get]
 func (h *CartHandler) GetCart(c *gin.Context) {
-	// Get user_id from header (set by API Gateway after JWT validation)
-	userID := c.GetHeader("X-User-Id")
-
-	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	// Cart owner: the user, or before login the guest (set by API Gateway)
+	userID, ok := cartOwner(c)
+	if !ok {
 		return
 	}
 
+	// First request after login: move the guest's cart into the user's cart
+	if guestID := c.GetHeader("X-Guest-Id"); guestID != "" && c.GetHeader("X-User-Id") != "" {
+		if _, err := h.cartService.MergeGuestCart(c.Request.Context(), userID, guestID); err != nil {
+			h.logger.Warn("failed to merge guest cart", zap.String("user_id", userID), zap.Error(err))
+		}
+	}
+
 	cart, err := h.cartService.GetCart(c.Request.Context(), userID)
 	if err != nil {
-		h.logger.Error("failed to get cart", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to get cart", err)
 		return
 	}
 
@@ -73,24 +78,23 @@ func (h *CartHandler) GetCart(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param request body AddItemRequest true "Add Item Request"
-// @Success 200 {object} map[string]string "Item added successfully"
+// @Param X-Cart-Version header int false "Cart version the client last saw"
+// @Success 200 {object} map[string]interface{} "Item added; cart, version and conflict flag"
 // @Failure 400 {object} map[string]string "Invalid request payload"
 // @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 422 {object} map[string]interface{} "Purchase limit exceeded (code PURCHASE_LIMIT_EXCEEDED)"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /cart/items [post]
 func (h *CartHandler) AddItem(c *gin.Context) {
-	// Get user_id from header (set by API Gateway after JWT validation)
-	userID := c.GetHeader("X-User-Id")
-	log.Println("")
-	log.Println("userID:", userID)
-	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	// Cart owner: the user, or before login the guest (set by API Gateway)
+	userID, ok := cartOwner(c)
+	if !ok {
 		return
 	}
 
 	var req AddItemRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -100,18 +104,23 @@ func (h *CartHandler) AddItem(c *gin.Context) {
 		return
 	}
 
-	if err := h.cartService.AddToCart(
+	result, err := h.cartService.AddToCart(
 		c.Request.Context(),
 		userID,
 		req.ProductItemID,
 		req.Quantity,
-	); err != nil {
-		h.logger.Error("failed to add item to cart", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		cartVersion(c),
+	)
+	if err != nil {
+		if status, body, ok := quantityErrorResponse(err); ok {
+			c.JSON(status, body)
+			return
+		}
+		respondError(c, h.logger, "failed to add item to cart", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Item added to cart successfully"})
+	respondCartUpdate(c, "Item added to cart successfully", result)
 }
 
 // UpdateItem handles PUT /cart/items/:product_item_id
@@ -122,18 +131,18 @@ func (h *CartHandler) AddItem(c *gin.Context) {
 // @Produce json
 // @Param product_item_id path int true "Product Item ID (SKU)"
 // @Param request body UpdateItemRequest true "Update Item Request"
-// @Success 200 {object} map[string]string "Item updated successfully"
+// @Param X-Cart-Version header int false "Cart version the client last saw"
+// @Success 200 {object} map[string]interface{} "Item updated; cart, version and conflict flag"
 // @Failure 400 {object} map[string]string "Invalid request payload"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 404 {object} map[string]string "Item not found"
+// @Failure 422 {object} map[string]interface{} "Purchase limit exceeded (code PURCHASE_LIMIT_EXCEEDED)"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /cart/items/{product_item_id} [put]
 func (h *CartHandler) UpdateItem(c *gin.Context) {
-	// Get user_id from header (set by API Gateway after JWT validation)
-	userID := c.GetHeader("X-User-Id")
-
-	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	// Cart owner: the user, or before login the guest (set by API Gateway)
+	userID, ok := cartOwner(c)
+	if !ok {
 		return
 	}
 
@@ -146,26 +155,27 @@ func (h *CartHandler) UpdateItem(c *gin.Context) {
 
 	var req UpdateItemRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
-	if err := h.cartService.UpdateItemQuantity(
+	result, err := h.cartService.UpdateItemQuantity(
 		c.Request.Context(),
 		userID,
 		uint(productItemIDUint),
 		req.Quantity,
-	); err != nil {
-		if err.Error() == "item not found in cart" {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		cartVersion(c),
+	)
+	if err != nil {
+		if status, body, ok := quantityErrorResponse(err); ok {
+			c.JSON(status, body)
 			return
 		}
-		h.logger.Error("failed to update item", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to update item", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Item updated successfully"})
+	respondCartUpdate(c, "Item updated successfully", result)
 }
 
 // RemoveItem handles DELETE /cart/items/:product_item_id
@@ -174,18 +184,17 @@ func (h *CartHandler) UpdateItem(c *gin.Context) {
 // @Tags Cart
 // @Produce json
 // @Param product_item_id path int true "Product Item ID (SKU)"
-// @Success 200 {object} map[string]string "Item removed successfully"
+// @Param X-Cart-Version header int false "Cart version the client last saw"
+// @Success 200 {object} map[string]interface{} "Item removed; cart, version and conflict flag"
 // @Failure 400 {object} map[string]string "Invalid request parameters"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 404 {object} map[string]string "Item not found"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /cart/items/{product_item_id} [delete]
 func (h *CartHandler) RemoveItem(c *gin.Context) {
-	// Get user_id from header (set by API Gateway after JWT validation)
-	userID := c.GetHeader("X-User-Id")
-
-	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	// Cart owner: the user, or before login the guest (set by API Gateway)
+	userID, ok := cartOwner(c)
+	if !ok {
 		return
 	}
 
@@ -196,21 +205,18 @@ func (h *CartHandler) RemoveItem(c *gin.Context) {
 		return
 	}
 
-	if err := h.cartService.RemoveFromCart(
+	result, err := h.cartService.RemoveFromCart(
 		c.Request.Context(),
 		userID,
 		uint(productItemIDUint),
-	); err != nil {
-		if err.Error() == "item not found in cart" {
-			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-			return
-		}
-		h.logger.Error("failed to remove item", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		cartVersion(c),
+	)
+	if err != nil {
+		respondError(c, h.logger, "failed to remove item", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Item removed successfully"})
+	respondCartUpdate(c, "Item removed successfully", result)
 }
 
 // ClearCart handles DELETE /cart
@@ -218,26 +224,108 @@ func (h *CartHandler) RemoveItem(c *gin.Context) {
 // @Description Remove all items from the shopping cart
 // @Tags Cart
 // @Produce json
-// @Success 200 {object} map[string]string "Cart cleared successfully"
+// @Param X-Cart-Version header int false "Cart version the client last saw"
+// @Success 200 {object} map[string]interface{} "Cart cleared; cart, version and conflict flag"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /cart [delete]
 func (h *CartHandler) ClearCart(c *gin.Context) {
-	// Get user_id from header (set by API Gateway after JWT validation)
-	userID := c.GetHeader("X-User-Id")
+	// Cart owner: the user, or before login the guest (set by API Gateway)
+	userID, ok := cartOwner(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.cartService.ClearCart(c.Request.Context(), userID, cartVersion(c))
+	if err != nil {
+		respondError(c, h.logger, "failed to clear cart", err)
+		return
+	}
 
-	if userID == "" {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	respondCartUpdate(c, "Cart cleared successfully", result)
+}
+
+// AcknowledgePriceChanges handles POST /cart/price-changes/acknowledge
+// @Summary Acknowledge cart price changes
+// @Description Accept the new prices of items flagged price_changed (clears the flags)
+// @Tags Cart
+// @Produce json
+// @Param X-Cart-Version header int false "Cart version the client last saw"
+// @Success 200 {object} map[string]interface{} "Price changes acknowledged; cart, version and conflict flag"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /cart/price-changes/acknowledge [post]
+func (h *CartHandler) AcknowledgePriceChanges(c *gin.Context) {
+	// Cart owner: the user, or before login the guest (set by API Gateway)
+	userID, ok := cartOwner(c)
+	if !ok {
 		return
 	}
 
-	if err := h.cartService.ClearCart(c.Request.Context(), userID); err != nil {
-		h.logger.Error("failed to clear cart", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	result, err := h.cartService.AcknowledgePriceChanges(c.Request.Context(), userID, cartVersion(c))
+	if err != nil {
+		respondError(c, h.logger, "failed to acknowledge price changes", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Cart cleared successfully"})
+	respondCartUpdate(c, "Price changes acknowledged", result)
+}
+
+// cartOwner returns whose cart a request uses: X-User-Id for signed-in users,
+// otherwise the guest identity X-Guest-Id issued by the API Gateway; responds 401 when neither is set
+func cartOwner(c *gin.Context) (string, bool) {
+	if userID := c.GetHeader("X-User-Id"); userID != "" {
+		return userID, true
+	}
+	if guestID := c.GetHeader("X-Guest-Id"); guestID != "" {
+		return service.GuestCartOwner(guestID), true
+	}
+	c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	return "", false
+}
+
+// cartVersion reads the cart version the client last saw from the optional
+// X-Cart-Version header (0 = unknown, no conflict check against the client)
+func cartVersion(c *gin.Context) int {
+	version, err := strconv.Atoi(c.GetHeader("X-Cart-Version"))
+	if err != nil || version < 0 {
+		return 0
+	}
+	return version
+}
+
+// respondCartUpdate writes a cart write result: the cart after the write (merged
+// with concurrent writes from other devices) and whether a conflict was detected
+func respondCartUpdate(c *gin.Context, message string, result *service.CartUpdateResult) {
+	c.JSON(http.StatusOK, gin.H{
+		"message":  message,
+		"cart":     result.Cart,
+		"version":  result.Cart.Version,
+		"conflict": result.Conflict,
+	})
+}
+
+// quantityErrorResponse maps quantity limit errors to a response with an error code:
+// QUANTITY_EXCEEDS_LIMIT (400) for the per-line cart limit,
+// PURCHASE_LIMIT_EXCEEDED (422) for the product's max purchase quantity
+func quantityErrorResponse(err error) (int, gin.H, bool) {
+	var limitErr *domain.PurchaseLimitError
+	switch {
+	case errors.As(err, &limitErr):
+		return http.StatusUnprocessableEntity, gin.H{
+			"error":      err.Error(),
+			"code":       domain.ErrCodePurchaseLimitExceeded,
+			"product_id": limitErr.ProductID,
+			"limit":      limitErr.Limit,
+			"requested":  limitErr.Requested,
+		}, true
+	case errors.Is(err, domain.ErrQuantityExceedsLimit):
+		return http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+			"code":  domain.ErrCodeQuantityExceedsLimit,
+		}, true
+	}
+	return 0, nil, false
 }
 
 // HealthCheck handles GET /health
@@ -0,0 +1,321 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"order-service/internal/domain"
+	"order-service/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DisputeHandler handles HTTP requests for the dispute resolution center
+type DisputeHandler struct {
+	disputeService *service.DisputeService
+	logger         *zap.Logger
+}
+
+// NewDisputeHandler creates a new dispute handler
+func NewDisputeHandler(disputeService *service.DisputeService, logger *zap.Logger) *DisputeHandler {
+	return &DisputeHandler{
+		disputeService: disputeService,
+		logger:         logger,
+	}
+}
+
+// OpenDispute handles POST /disputes
+// @Summary Open a dispute
+// @Description Open a dispute on one of the current user's orders (one per order). Evidence files are uploaded first; only their URLs are sent
+// @Tags Disputes
+// @Accept json
+// @Produce json
+// @Param request body service.OpenDisputeRequest true "Open Dispute Request"
+// @Success 201 {object} domain.Dispute "Dispute opened"
+// @Failure 400 {object} map[string]string "Invalid request payload"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Order not found"
+// @Failure 409 {object} map[string]string "Dispute already exists"
+// @Failure 422 {object} map[string]string "Order cannot be disputed"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /disputes [post]
+func (h *DisputeHandler) OpenDispute(c *gin.Context) {
+	userID, ok := subscriptionUserID(c)
+	if !ok {
+		return
+	}
+
+	var req service.OpenDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	dispute, err := h.disputeService.OpenDispute(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.writeError(c, err, "failed to open dispute")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dispute)
+}
+
+// ListDisputes handles GET /disputes
+// @Summary List disputes
+// @Description List the current user's disputes, or the disputes against a shop they own (shop_id)
+// @Tags Disputes
+// @Produce json
+// @Param shop_id query int false "Shop ID (seller view)"
+// @Param status query string false "Status filter (OPEN, UNDER_REVIEW, RESOLVED_BUYER, RESOLVED_SELLER)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{} "Disputes and total"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Shop not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /disputes [get]
+func (h *DisputeHandler) ListDisputes(c *gin.Context) {
+	actor, ok := disputeActor(c)
+	if !ok {
+		return
+	}
+
+	status := domain.DisputeStatus(c.Query("status"))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	var disputes []*domain.Dispute
+	var total int64
+	var err error
+	if shopIDStr := c.Query("shop_id"); shopIDStr != "" {
+		shopID, parseErr := strconv.ParseUint(shopIDStr, 10, 32)
+		if parseErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid shop_id"})
+			return
+		}
+		disputes, total, err = h.disputeService.ListForShop(c.Request.Context(), actor, uint(shopID), status, page, limit)
+	} else {
+		disputes, total, err = h.disputeService.ListForBuyer(c.Request.Context(), actor.UserID, status, page, limit)
+	}
+	if err != nil {
+		h.writeError(c, err, "failed to list disputes")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"disputes": disputes,
+		"total":    total,
+		"page":     page,
+		"limit":    limit,
+	})
+}
+
+// GetDispute handles GET /disputes/:id
+// @Summary Get a dispute
+// @Description Get a dispute with its evidence (buyer, shop owner or admin)
+// @Tags Disputes
+// @Produce json
+// @Param id path int true "Dispute ID"
+// @Success 200 {object} domain.Dispute "Dispute"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Dispute not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /disputes/{id} [get]
+func (h *DisputeHandler) GetDispute(c *gin.Context) {
+	actor, ok := disputeActor(c)
+	if !ok {
+		return
+	}
+	id, ok := disputeID(c)
+	if !ok {
+		return
+	}
+
+	dispute, err := h.disputeService.GetDispute(c.Request.Context(), actor, id)
+	if err != nil {
+		h.writeError(c, err, "failed to get dispute")
+		return
+	}
+
+	c.JSON(http.StatusOK, dispute)
+}
+
+// AddEvidence handles POST /disputes/:id/evidence
+// @Summary Add dispute evidence
+// @Description Add a file URL and/or statement to an unresolved dispute (buyer, shop owner or admin)
+// @Tags Disputes
+// @Accept json
+// @Produce json
+// @Param id path int true "Dispute ID"
+// @Param request body service.EvidenceInput true "Evidence"
+// @Success 201 {object} domain.DisputeEvidence "Evidence added"
+// @Failure 400 {object} map[string]string "Invalid evidence"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Dispute not found"
+// @Failure 409 {object} map[string]string "Dispute already resolved"
+// @Failure 422 {object} map[string]string "Evidence limit reached"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /disputes/{id}/evidence [post]
+func (h *DisputeHandler) AddEvidence(c *gin.Context) {
+	actor, ok := disputeActor(c)
+	if !ok {
+		return
+	}
+	id, ok := disputeID(c)
+	if !ok {
+		return
+	}
+
+	var req service.EvidenceInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	evidence, err := h.disputeService.AddEvidence(c.Request.Context(), actor, id, req)
+	if err != nil {
+		h.writeError(c, err, "failed to add evidence")
+		return
+	}
+
+	c.JSON(http.StatusCreated, evidence)
+}
+
+// AdminListDisputes handles GET /admin/disputes
+// @Summary List disputes (admin)
+// @Description Dispute queue for admins, filtered by status, shop or buyer
+// @Tags Disputes
+// @Produce json
+// @Param status query string false "Status filter"
+// @Param shop_id query int false "Shop ID"
+// @Param buyer_id query int false "Buyer ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{} "Disputes and total"
+// @Failure 403 {object} map[string]string "Admin permission required"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/disputes [get]
+func (h *DisputeHandler) AdminListDisputes(c *gin.Context) {
+	shopID, _ := strconv.ParseUint(c.Query("shop_id"), 10, 32)
+	buyerID, _ := strconv.ParseUint(c.Query("buyer_id"), 10, 32)
+	filter := domain.DisputeFilter{
+		BuyerID: uint(buyerID),
+		ShopID:  uint(shopID),
+		Status:  domain.DisputeStatus(c.Query("status")),
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	disputes, total, err := h.disputeService.ListAll(c.Request.Context(), filter, page, limit)
+	if err != nil {
+		h.writeError(c, err, "failed to list disputes")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"disputes": disputes,
+		"total":    total,
+		"page":     page,
+		"limit":    limit,
+	})
+}
+
+// StartReview handles POST /admin/disputes/:id/review
+// @Summary Start reviewing a dispute (admin)
+// @Description Move an OPEN dispute to UNDER_REVIEW
+// @Tags Disputes
+// @Produce json
+// @Param id path int true "Dispute ID"
+// @Success 200 {object} domain.Dispute "Dispute under review"
+// @Failure 403 {object} map[string]string "Admin permission required"
+// @Failure 404 {object} map[string]string "Dispute not found"
+// @Failure 409 {object} map[string]string "Dispute is not open"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/disputes/{id}/review [post]
+func (h *DisputeHandler) StartReview(c *gin.Context) {
+	adminID, ok := subscriptionUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := disputeID(c)
+	if !ok {
+		return
+	}
+
+	dispute, err := h.disputeService.StartReview(c.Request.Context(), adminID, id)
+	if err != nil {
+		h.writeError(c, err, "failed to start dispute review")
+		return
+	}
+
+	c.JSON(http.StatusOK, dispute)
+}
+
+// ResolveDispute handles POST /admin/disputes/:id/resolve
+// @Summary Resolve a dispute (admin)
+// @Description Decide for the buyer (refund, default full order amount, deducted from the seller's next payout) or for the seller
+// @Tags Disputes
+// @Accept json
+// @Produce json
+// @Param id path int true "Dispute ID"
+// @Param request body service.ResolveDisputeRequest true "Decision"
+// @Success 200 {object} domain.Dispute "Dispute resolved"
+// @Failure 400 {object} map[string]string "Invalid decision"
+// @Failure 403 {object} map[string]string "Admin permission required"
+// @Failure 404 {object} map[string]string "Dispute not found"
+// @Failure 409 {object} map[string]string "Dispute already resolved"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/disputes/{id}/resolve [post]
+func (h *DisputeHandler) ResolveDispute(c *gin.Context) {
+	adminID, ok := subscriptionUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := disputeID(c)
+	if !ok {
+		return
+	}
+
+	var req service.ResolveDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	dispute, err := h.disputeService.Resolve(c.Request.Context(), adminID, id, &req)
+	if err != nil {
+		h.writeError(c, err, "failed to resolve dispute")
+		return
+	}
+
+	c.JSON(http.StatusOK, dispute)
+}
+
+// writeError answers 422 when the dispute rules refuse the action, other errors by their kind
+func (h *DisputeHandler) writeError(c *gin.Context, err error, message string) {
+	if errors.Is(err, domain.ErrDisputeNotAllowed) || errors.Is(err, domain.ErrDisputeEvidenceLimit) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	respondError(c, h.logger, message, err)
+}
+
+// disputeActor reads the user and role set by API Gateway; answers 401 without a user
+func disputeActor(c *gin.Context) (service.DisputeActor, bool) {
+	userID, ok := subscriptionUserID(c)
+	if !ok {
+		return service.DisputeActor{}, false
+	}
+	return service.DisputeActor{UserID: userID, Role: c.GetHeader("X-User-Role")}, true
+}
+
+// disputeID parses the :id path parameter; answers 400 if invalid
+func disputeID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid dispute id"})
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"order-service/internal/domain"
+	"order-service/pkg/validation"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// errorStatus maps a service error to its HTTP status by its kind (see domain.Error)
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// respondError answers a failed service call. Business rule violations return their
+// message with the status of their kind; other errors are logged and answered 500
+// with the generic message, so database and network details don't reach clients
+func respondError(c *gin.Context, logger *zap.Logger, message string, err error) {
+	status := errorStatus(err)
+	if status == http.StatusInternalServerError {
+		logger.Error(message, zap.Error(err))
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
+
+// respondBindError answers a request that failed binding with 400: one entry per
+// rejected field when the binding tags failed, the decoding error otherwise
+func respondBindError(c *gin.Context, err error) {
+	if fields := validation.Errors(err); fields != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validation failed", "fields": fields})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"order-service/pkg/jobs"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// JobHandler handles admin HTTP requests for background job status
+type JobHandler struct {
+	inspector *jobs.Inspector
+	logger    *zap.Logger
+}
+
+// NewJobHandler creates a new job handler
+func NewJobHandler(inspector *jobs.Inspector, logger *zap.Logger) *JobHandler {
+	return &JobHandler{
+		inspector: inspector,
+		logger:    logger,
+	}
+}
+
+// GetStats handles GET /admin/jobs/order
+// @Summary Get background job stats (admin)
+// @Description Queue sizes (scheduled, active, dead) and lifetime counters
+// @Tags Jobs
+// @Produce json
+// @Success 200 {object} jobs.Stats "Job stats"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Router /admin/jobs/order [get]
+func (h *JobHandler) GetStats(c *gin.Context) {
+	stats, err := h.inspector.Stats(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// ListJobs handles GET /admin/jobs/order/list
+// @Summary List background jobs (admin)
+// @Description List jobs in a state (scheduled, active, dead)
+// @Tags Jobs
+// @Produce json
+// @Param state query string false "Job state" default(dead)
+// @Param limit query int false "Max jobs" default(50)
+// @Success 200 {object} map[string]interface{} "List of jobs"
+// @Failure 400 {object} map[string]string "Invalid state"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Router /admin/jobs/order/list [get]
+func (h *JobHandler) ListJobs(c *gin.Context) {
+	state := c.DefaultQuery("state", jobs.StateDead)
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit < 1 || limit > 500 {
+		limit = 50
+	}
+
+	list, err := h.inspector.ListJobs(c.Request.Context(), state, limit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"jobs": list, "state": state})
+}
+
+// GetJob handles GET /admin/jobs/order/:id
+// @Summary Get a background job (admin)
+// @Tags Jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} jobs.Job "Job"
+// @Failure 404 {object} map[string]string "Job not found"
+// @Router /admin/jobs/order/{id} [get]
+func (h *JobHandler) GetJob(c *gin.Context) {
+	job, err := h.inspector.GetJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// RetryJob handles POST /admin/jobs/order/:id/retry
+// @Summary Retry a dead job (admin)
+// @Tags Jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} jobs.Job "Rescheduled job"
+// @Failure 404 {object} map[string]string "Job not found in dead set"
+// @Router /admin/jobs/order/{id}/retry [post]
+func (h *JobHandler) RetryJob(c *gin.Context) {
+	job, err := h.inspector.RetryDead(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	h.logger.Info("dead job retried", zap.String("job_id", job.ID), zap.String("type", job.Type))
+	c.JSON(http.StatusOK, job)
+}
+
+// DeleteJob handles DELETE /admin/jobs/order/:id
+// @Summary Discard a dead job (admin)
+// @Tags Jobs
+// @Param id path string true "Job ID"
+// @Success 204 "Deleted"
+// @Failure 404 {object} map[string]string "Job not found in dead set"
+// @Router /admin/jobs/order/{id} [delete]
+func (h *JobHandler) DeleteJob(c *gin.Context) {
+	if err := h.inspector.DeleteDead(c.Request.Context(), c.Param("id")); err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *JobHandler) respondError(c *gin.Context, err error) {
+	if errors.Is(err, jobs.ErrJobNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"net/http"
+	"order-service/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// NotificationHandler handles HTTP requests for the in-app notification inbox
+type NotificationHandler struct {
+	inboxService *service.InboxService
+	logger       *zap.Logger
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(inboxService *service.InboxService, logger *zap.Logger) *NotificationHandler {
+	return &NotificationHandler{
+		inboxService: inboxService,
+		logger:       logger,
+	}
+}
+
+// ListNotifications handles GET /notifications
+// @Summary List notifications
+// @Description List the current user's in-app notifications, newest first
+// @Tags Notifications
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{} "Notifications, total and unread count"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /notifications [get]
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	userID, ok := subscriptionUserID(c)
+	if !ok {
+		return
+	}
+
+	unreadOnly := c.Query("unread") == "true"
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	notifications, total, err := h.inboxService.List(c.Request.Context(), userID, unreadOnly, page, limit)
+	if err != nil {
+		respondError(c, h.logger, "failed to list notifications", err)
+		return
+	}
+
+	unread, err := h.inboxService.UnreadCount(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, h.logger, "failed to list notifications", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": notifications,
+		"total":         total,
+		"unread_count":  unread,
+		"page":          page,
+		"limit":         limit,
+	})
+}
+
+// GetUnreadCount handles GET /notifications/unread-count
+// @Summary Unread notification count
+// @Description Number of unread notifications (bell icon badge)
+// @Tags Notifications
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Unread count"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /notifications/unread-count [get]
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	userID, ok := subscriptionUserID(c)
+	if !ok {
+		return
+	}
+
+	unread, err := h.inboxService.UnreadCount(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, h.logger, "failed to count unread notifications", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unread_count": unread})
+}
+
+// MarkRead handles PUT /notifications/:id/read
+// @Summary Mark a notification as read
+// @Tags Notifications
+// @Produce json
+// @Param id path int true "Notification ID"
+// @Success 200 {object} map[string]string "Marked as read"
+// @Failure 400 {object} map[string]string "Invalid notification ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Notification not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /notifications/{id}/read [put]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID, ok := subscriptionUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid notification ID"})
+		return
+	}
+
+	if err := h.inboxService.MarkRead(c.Request.Context(), userID, uint(id)); err != nil {
+		respondError(c, h.logger, "failed to mark notification as read", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "notification marked as read"})
+}
+
+// MarkAllRead handles PUT /notifications/read-all
+// @Summary Mark all notifications as read
+// @Tags Notifications
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Number of notifications marked"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /notifications/read-all [put]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID, ok := subscriptionUserID(c)
+	if !ok {
+		return
+	}
+
+	marked, err := h.inboxService.MarkAllRead(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, h.logger, "failed to mark notifications as read", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"marked": marked})
+}
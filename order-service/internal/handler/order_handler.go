@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"order-service/internal/domain"
 	"order-service/internal/service"
 	"strconv"
 
@@ -13,15 +15,17 @@ import (
 // This is the transport layer - it knows HOW to handle HTTP (Gin framework)
 // It delegates business logic to the service layer
 type OrderHandler struct {
-	orderService *service.OrderService
-	logger       *zap.Logger
+	orderService  *service.OrderService
+	statusService *service.OrderStatusService
+	logger        *zap.Logger
 }
 
 // NewOrderHandler creates a new order handler
-func NewOrderHandler(orderService *service.OrderService, logger *zap.Logger) *OrderHandler {
+func NewOrderHandler(orderService *service.OrderService, statusService *service.OrderStatusService, logger *zap.Logger) *OrderHandler {
 	return &OrderHandler{
-		orderService: orderService,
-		logger:       logger,
+		orderService:  orderService,
+		statusService: statusService,
+		logger:        logger,
 	}
 }
 
@@ -33,14 +37,15 @@ func NewOrderHandler(orderService *service.OrderService, logger *zap.Logger) *Or
 // @Produce json
 // @Param order body service.CreateOrderRequest true "Order creation request"
 // @Success 201 {object} service.CreateOrderResponse "Order(s) created successfully (can be multiple shop_orders)"
-// @Failure 400 {object} map[string]string "Invalid request"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} map[string]string "Invalid request (digital items paid cash on delivery)"
+// @Failure 422 {object} map[string]interface{} "Purchase limit exceeded (code PURCHASE_LIMIT_EXCEEDED)"
+// @Failure 500 {object} map[string]interface{} "Internal server error (checkout_id, checkout_status FAILED/PARTIAL and per-shop results when a shop_order failed)"
 // @Router /orders [post]
 func (h *OrderHandler) CreateOrder(c *gin.Context) {
 	var req service.CreateOrderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Warn("invalid request body", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -65,10 +70,24 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 		req.SessionID = c.Query("session_id")
 	}
 
-	response, err := h.orderService.CreateOrder(&req)
+	response, err := h.orderService.CreateOrder(c.Request.Context(), &req)
 	if err != nil {
-		h.logger.Error("failed to create order(s)", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if status, body, ok := quantityErrorResponse(err); ok {
+			c.JSON(status, body)
+			return
+		}
+		var checkoutErr *domain.CheckoutError
+		if errors.As(err, &checkoutErr) {
+			h.logger.Error("checkout failed", zap.String("checkout_id", checkoutErr.CheckoutID), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":           err.Error(),
+				"checkout_id":     checkoutErr.CheckoutID,
+				"checkout_status": checkoutErr.Status,
+				"shops":           checkoutErr.Shops,
+			})
+			return
+		}
+		respondError(c, h.logger, "failed to create order(s)", err)
 		return
 	}
 
@@ -77,7 +96,7 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 
 // GetOrder handles GET /orders/:id
 // @Summary Get order by ID
-// @Description Get order details by order ID
+// @Description Get order details by order ID, with the financial adjustments made after checkout and the current totals derived from them. Digital items include their issued codes when the caller (X-User-Id) is the buyer
 // @Tags Order
 // @Produce json
 // @Param id path int true "Order ID"
@@ -93,19 +112,217 @@ func (h *OrderHandler) GetOrder(c *gin.Context) {
 		return
 	}
 
-	order, err := h.orderService.GetOrder(uint(id))
+	order, err := h.orderService.GetOrder(c.Request.Context(), uint(id), viewerID(c))
 	if err != nil {
-		h.logger.Error("failed to get order", zap.Error(err), zap.Uint("order_id", uint(id)))
-		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+		respondError(c, h.logger, "failed to get order", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, order)
 }
 
+// ConfirmPayment handles POST /orders/:id/payment-confirmed
+// @Summary Confirm the payment of an order (internal)
+// @Description Called by the payment flow once the order is paid. Marks it paid and issues the codes of its digital items; an order with only digital items is delivered immediately. Safe to retry: a paid order only retries unfulfilled digital items
+// @Tags Order
+// @Produce json
+// @Param id path int true "Order ID"
+// @Success 200 {object} domain.Order "Order paid (and digital items fulfilled)"
+// @Failure 400 {object} map[string]string "Invalid order ID"
+// @Failure 404 {object} map[string]string "Order not found"
+// @Failure 409 {object} map[string]string "Order cannot be paid (cancelled)"
+// @Failure 502 {object} map[string]string "Order paid but digital codes could not be issued, retry"
+// @Router /orders/{id}/payment-confirmed [post]
+func (h *OrderHandler) ConfirmPayment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	order, err := h.orderService.ConfirmPayment(c.Request.Context(), uint(id))
+	if err != nil {
+		if order != nil {
+			// Payment is recorded; only the digital fulfillment failed
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error(), "order": order})
+			return
+		}
+		respondError(c, h.logger, "failed to confirm payment", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// ChangeStatus handles PUT /orders/:id/status
+// @Summary Change the status of an order
+// @Description Move an order through its status state machine: pending -> paid (payment only) -> confirmed (by the seller; cash on delivery orders also from pending) -> shipped -> delivered, or cancelled while nothing was charged. The buyer can only cancel; the seller and admins make the other changes. Every change is recorded in the order's status history and published as order_status_changed
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param request body service.ChangeOrderStatusRequest true "New status"
+// @Success 200 {object} domain.Order "Status changed"
+// @Failure 400 {object} map[string]string "Invalid status"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Status change not allowed to the caller"
+// @Failure 404 {object} map[string]string "Order not found"
+// @Failure 409 {object} map[string]string "Transition not allowed from the current status"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /orders/{id}/status [put]
+func (h *OrderHandler) ChangeStatus(c *gin.Context) {
+	userID, ok := subscriptionUserID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	var req service.ChangeOrderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	actor := service.OrderStatusActor{UserID: userID, Role: c.GetHeader("X-User-Role")}
+	order, err := h.statusService.ChangeStatus(c.Request.Context(), actor, uint(id), &req)
+	if err != nil {
+		respondError(c, h.logger, "failed to change order status", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// AdjustOrder handles POST /admin/orders/:id/adjustments
+// @Summary Record a financial adjustment of an order (admin)
+// @Description Append a fee adjustment (amount = platform fee change) or shipping correction (amount = shipping fee change) to a paid order. The order's checkout snapshot is kept, its totals are derived from the adjustments and the shop's earning difference goes to its next payout
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param request body service.AdjustOrderRequest true "Adjustment"
+// @Success 201 {object} domain.Order "Order with its adjustments and new totals"
+// @Failure 400 {object} map[string]string "Invalid adjustment, or amounts would go below zero"
+// @Failure 403 {object} map[string]string "Admin permission required"
+// @Failure 404 {object} map[string]string "Order not found"
+// @Failure 409 {object} map[string]string "Order not paid, cancelled or archived"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/orders/{id}/adjustments [post]
+func (h *OrderHandler) AdjustOrder(c *gin.Context) {
+	adminID, ok := subscriptionUserID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	var req service.AdjustOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	order, err := h.orderService.AdjustOrder(c.Request.Context(), adminID, uint(id), &req)
+	if err != nil {
+		respondError(c, h.logger, "failed to adjust order", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, order)
+}
+
+// CountOpenOrdersByProductItem handles GET /orders/product-items/:product_item_id/open-count
+// @Summary Count open orders for a SKU
+// @Description Number of unfinished orders (pending, paid, confirmed, shipped) containing the product item. Called by product-service before deleting a SKU
+// @Tags Order
+// @Produce json
+// @Param product_item_id path int true "Product Item ID"
+// @Success 200 {object} map[string]interface{} "Open order count"
+// @Failure 400 {object} map[string]string "Invalid product item ID"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /orders/product-items/{product_item_id}/open-count [get]
+func (h *OrderHandler) CountOpenOrdersByProductItem(c *gin.Context) {
+	productItemID, err := strconv.ParseUint(c.Param("product_item_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product item ID"})
+		return
+	}
+
+	count, err := h.orderService.CountOpenOrdersByProductItem(uint(productItemID))
+	if err != nil {
+		respondError(c, h.logger, "Failed to count open orders", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"product_item_id": productItemID,
+		"open_orders":     count,
+	})
+}
+
+// VerifyPurchase handles GET /orders/purchases/verify
+// @Summary Verify a purchase
+// @Description Whether the user has a delivered order containing the product, and which. Called by product-service before accepting a review
+// @Tags Order
+// @Produce json
+// @Param user_id query int true "Buyer user ID"
+// @Param product_id query int true "Product ID"
+// @Success 200 {object} service.PurchaseVerification "Purchase verification"
+// @Failure 400 {object} map[string]string "Invalid user or product ID"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /orders/purchases/verify [get]
+func (h *OrderHandler) VerifyPurchase(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Query("user_id"), 10, 32)
+	if err != nil || userID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	productID, err := strconv.ParseUint(c.Query("product_id"), 10, 32)
+	if err != nil || productID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	verification, err := h.orderService.VerifyPurchase(uint(userID), uint(productID))
+	if err != nil {
+		respondError(c, h.logger, "Failed to verify purchase", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, verification)
+}
+
+// GetShopGMV handles GET /orders/shops/gmv
+// @Summary GMV per shop
+// @Description Gross merchandise value (merchandise subtotal) and order count per shop of the orders placed in a period that were paid, or confirmed for cash on delivery, and not cancelled. Called by identity-service for the admin shop export
+// @Tags Order
+// @Produce json
+// @Param from query string true "First day (YYYY-MM-DD)"
+// @Param to query string true "Last day, included (YYYY-MM-DD)"
+// @Success 200 {object} map[string]interface{} "GMV per shop"
+// @Failure 400 {object} map[string]string "Invalid period"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /orders/shops/gmv [get]
+func (h *OrderHandler) GetShopGMV(c *gin.Context) {
+	gmv, err := h.orderService.ShopGMV(c.Query("from"), c.Query("to"))
+	if err != nil {
+		respondError(c, h.logger, "Failed to get shop GMV", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"shops": gmv})
+}
+
 // GetOrderByOrderNumber handles GET /orders/number/:order_number
 // @Summary Get order by order number
-// @Description Get order details by order number
+// @Description Get order details by order number, with the financial adjustments made after checkout and the current totals derived from them. Digital items include their issued codes when the caller (X-User-Id) is the buyer
 // @Tags Order
 // @Produce json
 // @Param order_number path string true "Order Number"
@@ -120,10 +337,9 @@ func (h *OrderHandler) GetOrderByOrderNumber(c *gin.Context) {
 		return
 	}
 
-	order, err := h.orderService.GetOrderByOrderNumber(orderNumber)
+	order, err := h.orderService.GetOrderByOrderNumber(c.Request.Context(), orderNumber, viewerID(c))
 	if err != nil {
-		h.logger.Error("failed to get order", zap.Error(err), zap.String("order_number", orderNumber))
-		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+		respondError(c, h.logger, "failed to get order", err)
 		return
 	}
 
@@ -184,8 +400,7 @@ func (h *OrderHandler) ListOrders(c *gin.Context) {
 
 	orders, total, err := h.orderService.ListOrders(userID, sessionID, limit, offset)
 	if err != nil {
-		h.logger.Error("failed to list orders", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to list orders", err)
 		return
 	}
 
@@ -196,3 +411,12 @@ func (h *OrderHandler) ListOrders(c *gin.Context) {
 		"offset": offset,
 	})
 }
+
+// viewerID returns the caller from X-User-Id (set by API Gateway), 0 if absent
+func viewerID(c *gin.Context) uint {
+	id, err := strconv.ParseUint(c.GetHeader("X-User-Id"), 10, 32)
+	if err != nil {
+		return 0
+	}
+	return uint(id)
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"order-service/internal/domain"
+	"order-service/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SubscriptionHandler handles HTTP requests for product subscriptions (price drop, back in stock)
+type SubscriptionHandler struct {
+	subscriptionService *service.SubscriptionService
+	logger              *zap.Logger
+}
+
+// NewSubscriptionHandler creates a new subscription handler
+func NewSubscriptionHandler(subscriptionService *service.SubscriptionService, logger *zap.Logger) *SubscriptionHandler {
+	return &SubscriptionHandler{
+		subscriptionService: subscriptionService,
+		logger:              logger,
+	}
+}
+
+// Subscribe handles POST /subscriptions
+// @Summary Subscribe to a product
+// @Description Get notified when the price drops to target_price or below (price_drop) or the product is back in stock (back_in_stock). Subscribing again to the same product, SKU and type updates the subscription
+// @Tags Subscriptions
+// @Accept json
+// @Produce json
+// @Param request body service.SubscribeRequest true "Subscribe Request"
+// @Success 201 {object} domain.ProductSubscription "Subscription saved"
+// @Failure 400 {object} map[string]string "Invalid request payload"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Product not found"
+// @Failure 422 {object} map[string]string "Subscription limit reached"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /subscriptions [post]
+func (h *SubscriptionHandler) Subscribe(c *gin.Context) {
+	userID, ok := subscriptionUserID(c)
+	if !ok {
+		return
+	}
+
+	var req service.SubscribeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	sub, err := h.subscriptionService.Subscribe(c.Request.Context(), userID, &req)
+	if err != nil {
+		if errors.Is(err, domain.ErrSubscriptionLimit) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		respondError(c, h.logger, "failed to save subscription", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, sub)
+}
+
+// ListSubscriptions handles GET /subscriptions
+// @Summary List product subscriptions
+// @Description List the current user's price drop and back in stock subscriptions
+// @Tags Subscriptions
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Subscriptions and count"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /subscriptions [get]
+func (h *SubscriptionHandler) ListSubscriptions(c *gin.Context) {
+	userID, ok := subscriptionUserID(c)
+	if !ok {
+		return
+	}
+
+	subs, err := h.subscriptionService.ListSubscriptions(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, h.logger, "failed to list subscriptions", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"subscriptions": subs,
+		"count":         len(subs),
+	})
+}
+
+// Unsubscribe handles DELETE /subscriptions/:id
+// @Summary Delete a product subscription
+// @Tags Subscriptions
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} map[string]string "Subscription deleted"
+// @Failure 400 {object} map[string]string "Invalid subscription ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Subscription not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /subscriptions/{id} [delete]
+func (h *SubscriptionHandler) Unsubscribe(c *gin.Context) {
+	userID, ok := subscriptionUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription ID"})
+		return
+	}
+
+	if err := h.subscriptionService.Unsubscribe(c.Request.Context(), userID, uint(id)); err != nil {
+		respondError(c, h.logger, "failed to delete subscription", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "subscription deleted"})
+}
+
+// subscriptionUserID reads the user from X-User-Id (set by API Gateway after JWT validation)
+// and answers 401 if it is missing or invalid
+func subscriptionUserID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.GetHeader("X-User-Id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return 0, false
+	}
+	return uint(id), true
+}
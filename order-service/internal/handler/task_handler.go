@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+	"order-service/internal/domain"
+	"order-service/pkg/taskqueue"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// TaskHandler handles admin HTTP requests for the async side effect pool and
+// the event publisher it feeds
+type TaskHandler struct {
+	pool      *taskqueue.Pool
+	publisher domain.BufferedOrderEventPublisher
+	logger    *zap.Logger
+}
+
+// NewTaskHandler creates a new task handler
+func NewTaskHandler(pool *taskqueue.Pool, publisher domain.BufferedOrderEventPublisher, logger *zap.Logger) *TaskHandler {
+	return &TaskHandler{
+		pool:      pool,
+		publisher: publisher,
+		logger:    logger,
+	}
+}
+
+// GetStats handles GET /admin/tasks/order
+// @Summary Get async task pool stats (admin)
+// @Description Worker/queue sizes and counters for event publishing side effects
+// @Tags Jobs
+// @Produce json
+// @Success 200 {object} taskqueue.Stats "Pool stats"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Router /admin/tasks/order [get]
+func (h *TaskHandler) GetStats(c *gin.Context) {
+	c.JSON(http.StatusOK, h.pool.Stats())
+}
+
+// GetEventStats handles GET /admin/events/order
+// @Summary Get Kafka event publisher stats (admin)
+// @Description Published/retried/buffered counters, buffer length and lag of the oldest buffered event
+// @Tags Jobs
+// @Produce json
+// @Success 200 {object} domain.EventPublisherStats "Publisher stats"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Router /admin/events/order [get]
+func (h *TaskHandler) GetEventStats(c *gin.Context) {
+	c.JSON(http.StatusOK, h.publisher.Stats(c.Request.Context()))
+}
+
+// FlushEvents handles POST /admin/events/order/flush
+// @Summary Redeliver buffered Kafka events now (admin)
+// @Description Writes buffered events in order until the buffer is empty or Kafka fails again
+// @Tags Jobs
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Flushed count and publisher stats"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Failure 502 {object} map[string]interface{} "Kafka still failing"
+// @Router /admin/events/order/flush [post]
+func (h *TaskHandler) FlushEvents(c *gin.Context) {
+	flushed, err := h.publisher.Flush(c.Request.Context())
+	if err != nil {
+		h.logger.Warn("manual event flush failed", zap.Int("flushed", flushed), zap.Error(err))
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error(), "flushed": flushed})
+		return
+	}
+
+	h.logger.Info("manual event flush",
+		zap.Int("flushed", flushed),
+		zap.String("user_id", c.GetHeader("X-User-Id")),
+	)
+	c.JSON(http.StatusOK, gin.H{"flushed": flushed, "stats": h.publisher.Stats(c.Request.Context())})
+}
@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"net/http"
+	"order-service/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// VoucherHandler handles HTTP requests for shop and platform vouchers
+type VoucherHandler struct {
+	voucherService *service.VoucherService
+	logger         *zap.Logger
+}
+
+// NewVoucherHandler creates a new voucher handler
+func NewVoucherHandler(voucherService *service.VoucherService, logger *zap.Logger) *VoucherHandler {
+	return &VoucherHandler{
+		voucherService: voucherService,
+		logger:         logger,
+	}
+}
+
+// CreateVoucher handles POST /vouchers
+// @Summary Create a voucher
+// @Description Create a voucher for a shop the current user owns (shop_id), or a platform voucher (admin, no shop_id). Buyers apply it at checkout with its code
+// @Tags Vouchers
+// @Accept json
+// @Produce json
+// @Param request body service.CreateVoucherRequest true "Create Voucher Request"
+// @Success 201 {object} domain.Voucher "Voucher created"
+// @Failure 400 {object} map[string]string "Invalid voucher"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the shop owner or admin"
+// @Failure 409 {object} map[string]string "Code already exists"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /vouchers [post]
+func (h *VoucherHandler) CreateVoucher(c *gin.Context) {
+	actor, ok := voucherActor(c)
+	if !ok {
+		return
+	}
+
+	var req service.CreateVoucherRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	voucher, err := h.voucherService.Create(c.Request.Context(), actor, &req)
+	if err != nil {
+		respondError(c, h.logger, "failed to create voucher", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, voucher)
+}
+
+// ListVouchers handles GET /vouchers
+// @Summary List vouchers
+// @Description List the vouchers of a shop the current user owns (shop_id), or all vouchers (admin) - only platform vouchers with platform=true
+// @Tags Vouchers
+// @Produce json
+// @Param shop_id query int false "Shop ID (required for sellers)"
+// @Param platform query bool false "Only platform vouchers (admin)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{} "Vouchers and total"
+// @Failure 400 {object} map[string]string "Invalid shop_id"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the shop owner or admin"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /vouchers [get]
+func (h *VoucherHandler) ListVouchers(c *gin.Context) {
+	actor, ok := voucherActor(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	platformOnly := c.Query("platform") == "true"
+
+	var shopID *uint
+	if shopIDStr := c.Query("shop_id"); shopIDStr != "" {
+		id, err := strconv.ParseUint(shopIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid shop_id"})
+			return
+		}
+		shop := uint(id)
+		shopID = &shop
+	}
+
+	vouchers, total, err := h.voucherService.List(c.Request.Context(), actor, shopID, platformOnly, page, limit)
+	if err != nil {
+		respondError(c, h.logger, "failed to list vouchers", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"vouchers": vouchers,
+		"total":    total,
+		"page":     page,
+		"limit":    limit,
+	})
+}
+
+// GetVoucher handles GET /vouchers/:id
+// @Summary Get a voucher
+// @Description Get a voucher with its usage count (shop owner or admin)
+// @Tags Vouchers
+// @Produce json
+// @Param id path int true "Voucher ID"
+// @Success 200 {object} domain.Voucher "Voucher"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the shop owner or admin"
+// @Failure 404 {object} map[string]string "Voucher not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /vouchers/{id} [get]
+func (h *VoucherHandler) GetVoucher(c *gin.Context) {
+	actor, ok := voucherActor(c)
+	if !ok {
+		return
+	}
+	id, ok := voucherID(c)
+	if !ok {
+		return
+	}
+
+	voucher, err := h.voucherService.Get(c.Request.Context(), actor, id)
+	if err != nil {
+		respondError(c, h.logger, "failed to get voucher", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, voucher)
+}
+
+// UpdateVoucher handles PUT /vouchers/:id
+// @Summary Update a voucher
+// @Description Change the discount, limits, validity window or active flag of a voucher (shop owner or admin). The code and shop cannot change
+// @Tags Vouchers
+// @Accept json
+// @Produce json
+// @Param id path int true "Voucher ID"
+// @Param request body service.UpdateVoucherRequest true "Update Voucher Request"
+// @Success 200 {object} domain.Voucher "Voucher updated"
+// @Failure 400 {object} map[string]string "Invalid voucher"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the shop owner or admin"
+// @Failure 404 {object} map[string]string "Voucher not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /vouchers/{id} [put]
+func (h *VoucherHandler) UpdateVoucher(c *gin.Context) {
+	actor, ok := voucherActor(c)
+	if !ok {
+		return
+	}
+	id, ok := voucherID(c)
+	if !ok {
+		return
+	}
+
+	var req service.UpdateVoucherRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	voucher, err := h.voucherService.Update(c.Request.Context(), actor, id, &req)
+	if err != nil {
+		respondError(c, h.logger, "failed to update voucher", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, voucher)
+}
+
+// DeleteVoucher handles DELETE /vouchers/:id
+// @Summary Delete a voucher
+// @Description Delete a voucher that was never redeemed (shop owner or admin); redeemed vouchers are deactivated instead
+// @Tags Vouchers
+// @Produce json
+// @Param id path int true "Voucher ID"
+// @Success 200 {object} map[string]string "Voucher deleted"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Not the shop owner or admin"
+// @Failure 404 {object} map[string]string "Voucher not found"
+// @Failure 409 {object} map[string]string "Voucher already redeemed"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /vouchers/{id} [delete]
+func (h *VoucherHandler) DeleteVoucher(c *gin.Context) {
+	actor, ok := voucherActor(c)
+	if !ok {
+		return
+	}
+	id, ok := voucherID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.voucherService.Delete(c.Request.Context(), actor, id); err != nil {
+		respondError(c, h.logger, "failed to delete voucher", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "voucher deleted"})
+}
+
+// voucherActor reads the user and role set by API Gateway; answers 401 without a user
+func voucherActor(c *gin.Context) (service.VoucherActor, bool) {
+	userID, ok := subscriptionUserID(c)
+	if !ok {
+		return service.VoucherActor{}, false
+	}
+	return service.VoucherActor{UserID: userID, Role: c.GetHeader("X-User-Role")}, true
+}
+
+// voucherID parses the :id path parameter; answers 400 if invalid
+func voucherID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid voucher id"})
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ImpersonationLogger logs requests made by an admin impersonating a user
+// (X-Impersonator-Id, set by the gateway from the token's act claim), so support
+// actions on orders and carts can be told apart from the user's own actions
+func ImpersonationLogger(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		impersonatorID := c.GetHeader("X-Impersonator-Id")
+		if impersonatorID == "" {
+			c.Next()
+			return
+		}
+
+		c.Next()
+
+		logger.Info("impersonated request handled",
+			zap.String("impersonator_id", impersonatorID),
+			zap.String("user_id", c.GetHeader("X-User-Id")),
+			zap.String("impersonation_id", c.GetHeader("X-Impersonation-Id")),
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", c.Writer.Status()),
+		)
+	}
+}
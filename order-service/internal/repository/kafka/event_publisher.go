@@ -3,26 +3,80 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"order-service/internal/domain"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/redis/go-redis/v9"
 	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
 )
 
-// eventPublisher implements the OrderEventPublisher interface
+// PublisherOptions configures the Kafka event publisher
+type PublisherOptions struct {
+	Brokers       []string
+	Topics        Topics
+	WriteTimeout  time.Duration
+	RequiredAcks  int           // 0: no ack, 1: leader ack, -1: all replicas ack
+	MaxRetries    int           // write retries before an event is buffered
+	RetryBackoff  time.Duration // delay before retry n is n*RetryBackoff
+	BufferKey     string        // Redis list holding events that could not be written
+	FlushInterval time.Duration // how often the buffer is redelivered
+}
+
+// bufferedMessage is a Kafka message parked in the Redis buffer
+type bufferedMessage struct {
+	Topic      string            `json:"topic"`
+	Key        string            `json:"key"`
+	Value      json.RawMessage   `json:"value"`
+	Headers    map[string]string `json:"headers"`
+	BufferedAt time.Time         `json:"buffered_at"`
+}
+
+// popIfHeadScript removes the head of the buffer only if it is still the message
+// that was just delivered (another replica may have flushed it already)
+var popIfHeadScript = redis.NewScript(`
+if redis.call("LINDEX", KEYS[1], 0) == ARGV[1] then
+	return redis.call("LPOP", KEYS[1])
+end
+return false
+`)
+
+// eventPublisher implements the BufferedOrderEventPublisher interface
 // This is the infrastructure layer - it knows HOW to publish events to Kafka
+// Writes are retried with backoff; events that still fail are parked in a Redis list
+// and redelivered in order by Start/Flush, so a broker outage delays events instead
+// of dropping them (consumers may see duplicates - at-least-once)
 type eventPublisher struct {
 	writer *kafka.Writer
-	topic  string
+	opts   PublisherOptions
+	buffer *redis.Client
+	logger *zap.Logger
+
+	flushMu sync.Mutex // one flush at a time per process
+
+	published atomic.Int64
+	retried   atomic.Int64
+	buffered  atomic.Int64
+	flushed   atomic.Int64
+	failed    atomic.Int64
+
+	errMu       sync.Mutex
+	lastError   string
+	lastErrorAt time.Time
 }
 
-// NewEventPublisher creates a new Kafka event publisher for orders
-// Dependency injection: we inject the Kafka writer
-func NewEventPublisher(brokers []string, topic string, writeTimeout time.Duration, requiredAcks int) domain.OrderEventPublisher {
+// NewEventPublisher creates a new Kafka event publisher
+// Each event type is written to its own topic (see Topics); messages are keyed and
+// hash-partitioned by order ID so events of one order stay in order
+func NewEventPublisher(opts PublisherOptions, buffer *redis.Client, logger *zap.Logger) domain.BufferedOrderEventPublisher {
 	// Convert int to kafka.RequiredAcks
 	var kafkaAcks kafka.RequiredAcks
-	switch requiredAcks {
+	switch opts.RequiredAcks {
 	case -1:
 		kafkaAcks = kafka.RequireAll
 	case 0:
@@ -32,26 +86,37 @@ func NewEventPublisher(brokers []string, topic string, writeTimeout time.Duratio
 	default:
 		kafkaAcks = kafka.RequireOne
 	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = 200 * time.Millisecond
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 10 * time.Second
+	}
+	if opts.BufferKey == "" {
+		opts.BufferKey = "events:buffer:order"
+	}
 
 	writer := &kafka.Writer{
-		Addr:         kafka.TCP(brokers...),
-		Topic:        topic,
-		Balancer:     &kafka.LeastBytes{},
-		WriteTimeout: writeTimeout,
+		Addr:         kafka.TCP(opts.Brokers...),
+		Balancer:     &kafka.Hash{}, // same key -> same partition
+		WriteTimeout: opts.WriteTimeout,
 		RequiredAcks: kafkaAcks,
 		Async:        false, // Synchronous writes for reliability
 	}
 
 	return &eventPublisher{
 		writer: writer,
-		topic:  topic,
+		opts:   opts,
+		buffer: buffer,
+		logger: logger,
 	}
 }
 
 // PublishOrderEvent publishes an order event to Kafka
 // This enables event-driven architecture and inter-service communication
+// Returns nil once the event is written or safely buffered
 func (p *eventPublisher) PublishOrderEvent(event *domain.OrderEvent) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
 	// Convert event to JSON
@@ -62,23 +127,211 @@ func (p *eventPublisher) PublishOrderEvent(event *domain.OrderEvent) error {
 
 	// Create Kafka message
 	message := kafka.Message{
+		Topic: p.opts.Topics.For(event.EventType),
 		Key:   []byte(fmt.Sprintf("%d", event.OrderID)),
 		Value: eventJSON,
 		Headers: []kafka.Header{
 			{Key: "event_type", Value: []byte(event.EventType)},
+			{Key: "schema_version", Value: []byte(strconv.Itoa(event.SchemaVersion))},
 			{Key: "timestamp", Value: []byte(event.Timestamp.Format(time.RFC3339))},
 		},
 	}
 
-	// Write message to Kafka
-	err = p.writer.WriteMessages(ctx, message)
+	return p.publish(ctx, message)
+}
+
+// PublishNotificationEvent publishes a notification event to Kafka, keyed by user ID
+// Returns nil once the event is written or safely buffered
+func (p *eventPublisher) PublishNotificationEvent(event *domain.NotificationEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	eventJSON, err := json.Marshal(event)
 	if err != nil {
-		return fmt.Errorf("failed to write message to kafka (topic: %s): %w", p.topic, err)
+		return fmt.Errorf("failed to marshal event: %w", err)
 	}
 
+	message := kafka.Message{
+		Topic: p.opts.Topics.For(event.EventType),
+		Key:   []byte(fmt.Sprintf("%d", event.UserID)),
+		Value: eventJSON,
+		Headers: []kafka.Header{
+			{Key: "event_type", Value: []byte(event.EventType)},
+			{Key: "dedupe_key", Value: []byte(event.DedupeKey)},
+			{Key: "timestamp", Value: []byte(event.Timestamp.Format(time.RFC3339))},
+		},
+	}
+
+	return p.publish(ctx, message)
+}
+
+// publish writes a message, or parks it in the buffer if it cannot be written
+func (p *eventPublisher) publish(ctx context.Context, message kafka.Message) error {
+	// Older events are still waiting: queue behind them to keep per-key order
+	if n, err := p.buffer.LLen(ctx, p.opts.BufferKey).Result(); err == nil && n > 0 {
+		return p.park(ctx, message, errors.New("buffer not empty"))
+	}
+
+	if err := p.write(ctx, message, p.opts.MaxRetries); err != nil {
+		return p.park(ctx, message, err)
+	}
 	return nil
 }
 
+// write sends a message, retrying up to retries times with linear backoff
+func (p *eventPublisher) write(ctx context.Context, message kafka.Message, retries int) error {
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			p.retried.Add(1)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(attempt) * p.opts.RetryBackoff):
+			}
+		}
+
+		writeCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		err = p.writer.WriteMessages(writeCtx, message)
+		cancel()
+		if err == nil {
+			p.published.Add(1)
+			return nil
+		}
+		err = fmt.Errorf("failed to write message to kafka (topic: %s): %w", message.Topic, err)
+		p.recordError(err)
+	}
+	return err
+}
+
+// park appends a message to the Redis buffer
+func (p *eventPublisher) park(ctx context.Context, message kafka.Message, cause error) error {
+	entry := bufferedMessage{
+		Topic:      message.Topic,
+		Key:        string(message.Key),
+		Value:      message.Value,
+		Headers:    make(map[string]string, len(message.Headers)),
+		BufferedAt: time.Now(),
+	}
+	for _, h := range message.Headers {
+		entry.Headers[h.Key] = string(h.Value)
+	}
+
+	raw, err := json.Marshal(entry)
+	if err == nil {
+		err = p.buffer.RPush(ctx, p.opts.BufferKey, raw).Err()
+	}
+	if err != nil {
+		p.failed.Add(1)
+		p.recordError(err)
+		return fmt.Errorf("failed to publish event (%v) and to buffer it: %w", cause, err)
+	}
+
+	p.buffered.Add(1)
+	p.logger.Warn("event buffered for redelivery",
+		zap.String("topic", message.Topic),
+		zap.String("key", entry.Key),
+		zap.NamedError("cause", cause),
+	)
+	return nil
+}
+
+// Start redelivers buffered events every FlushInterval until ctx is canceled
+func (p *eventPublisher) Start(ctx context.Context) {
+	ticker := time.NewTicker(p.opts.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := p.Flush(ctx); err != nil {
+				p.logger.Warn("event buffer flush stopped", zap.Int("flushed", n), zap.Error(err))
+			} else if n > 0 {
+				p.logger.Info("event buffer flushed", zap.Int("flushed", n))
+			}
+		}
+	}
+}
+
+// Flush redelivers buffered events in order until the buffer is empty or a write fails
+func (p *eventPublisher) Flush(ctx context.Context) (int, error) {
+	p.flushMu.Lock()
+	defer p.flushMu.Unlock()
+
+	flushed := 0
+	for {
+		raw, err := p.buffer.LIndex(ctx, p.opts.BufferKey, 0).Result()
+		if errors.Is(err, redis.Nil) {
+			return flushed, nil
+		}
+		if err != nil {
+			return flushed, fmt.Errorf("failed to read event buffer: %w", err)
+		}
+
+		var entry bufferedMessage
+		if err := json.Unmarshal([]byte(raw), &entry); err != nil {
+			p.logger.Error("dropping malformed buffered event", zap.Error(err))
+			p.failed.Add(1)
+		} else {
+			message := kafka.Message{
+				Topic: entry.Topic,
+				Key:   []byte(entry.Key),
+				Value: entry.Value,
+			}
+			for k, v := range entry.Headers {
+				message.Headers = append(message.Headers, kafka.Header{Key: k, Value: []byte(v)})
+			}
+			if err := p.write(ctx, message, 0); err != nil {
+				return flushed, err
+			}
+			p.flushed.Add(1)
+			flushed++
+		}
+
+		if err := popIfHeadScript.Run(ctx, p.buffer, []string{p.opts.BufferKey}, raw).Err(); err != nil && !errors.Is(err, redis.Nil) {
+			return flushed, fmt.Errorf("failed to remove flushed event from buffer: %w", err)
+		}
+	}
+}
+
+// Stats returns delivery counters and the buffer backlog
+func (p *eventPublisher) Stats(ctx context.Context) domain.EventPublisherStats {
+	stats := domain.EventPublisherStats{
+		Published: p.published.Load(),
+		Retried:   p.retried.Load(),
+		Buffered:  p.buffered.Load(),
+		Flushed:   p.flushed.Load(),
+		Failed:    p.failed.Load(),
+	}
+
+	stats.BufferLength, _ = p.buffer.LLen(ctx, p.opts.BufferKey).Result()
+	if raw, err := p.buffer.LIndex(ctx, p.opts.BufferKey, 0).Result(); err == nil {
+		var oldest bufferedMessage
+		if json.Unmarshal([]byte(raw), &oldest) == nil {
+			stats.LagSeconds = time.Since(oldest.BufferedAt).Seconds()
+		}
+	}
+
+	p.errMu.Lock()
+	if p.lastError != "" {
+		at := p.lastErrorAt
+		stats.LastError = p.lastError
+		stats.LastErrorAt = &at
+	}
+	p.errMu.Unlock()
+
+	return stats
+}
+
+func (p *eventPublisher) recordError(err error) {
+	p.errMu.Lock()
+	p.lastError = err.Error()
+	p.lastErrorAt = time.Now()
+	p.errMu.Unlock()
+}
+
 // Close closes the Kafka writer connection
 // This should be called during graceful shutdown
 func (p *eventPublisher) Close() error {
@@ -87,4 +340,3 @@ func (p *eventPublisher) Close() error {
 	}
 	return nil
 }
-
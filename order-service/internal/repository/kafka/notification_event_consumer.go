@@ -0,0 +1,110 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"order-service/internal/domain"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+// NotificationEventHandler handles notification events (implemented by service.InboxService)
+type NotificationEventHandler interface {
+	HandleNotificationEvent(ctx context.Context, event *domain.NotificationEvent) error
+}
+
+// notificationEventTimeout bounds handling of one notification event
+const notificationEventTimeout = 10 * time.Second
+
+// NotificationEventConsumer consumes the notification topics and fills the users'
+// in-app inboxes. It runs in its own consumer group, independent of the dispatchers
+// sending email/SMS/push; messages are committed after handling (at-least-once) and
+// the inbox dedupes redeliveries by dedupe key
+type NotificationEventConsumer struct {
+	reader  *kafka.Reader
+	handler NotificationEventHandler
+	logger  *zap.Logger
+}
+
+// NewNotificationEventConsumer creates a new Kafka consumer for notification events
+func NewNotificationEventConsumer(
+	brokers []string,
+	topics []string,
+	consumerGroup string,
+	handler NotificationEventHandler,
+	logger *zap.Logger,
+) *NotificationEventConsumer {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:        brokers,
+		GroupTopics:    topics,
+		GroupID:        consumerGroup,
+		MinBytes:       1,
+		MaxBytes:       10e6,
+		ReadBackoffMin: 100 * time.Millisecond,
+		ReadBackoffMax: 1 * time.Second,
+	})
+
+	return &NotificationEventConsumer{
+		reader:  reader,
+		handler: handler,
+		logger:  logger,
+	}
+}
+
+// Start consumes messages until ctx is canceled
+func (c *NotificationEventConsumer) Start(ctx context.Context) {
+	c.logger.Info("notification event consumer started",
+		zap.Strings("topics", c.reader.Config().GroupTopics),
+		zap.String("consumer_group", c.reader.Config().GroupID),
+	)
+
+	for {
+		message, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				c.logger.Info("notification event consumer stopped")
+				return
+			}
+			c.logger.Error("failed to fetch notification event", zap.Error(err))
+			time.Sleep(1 * time.Second) // Backoff on error
+			continue
+		}
+
+		if err := c.processMessage(ctx, message); err != nil {
+			// Logged and skipped: a stuck message must not block the partition
+			c.logger.Error("failed to handle notification event",
+				zap.String("topic", message.Topic),
+				zap.Int("partition", message.Partition),
+				zap.Int64("offset", message.Offset),
+				zap.Error(err),
+			)
+		}
+
+		if err := c.reader.CommitMessages(ctx, message); err != nil && ctx.Err() == nil {
+			c.logger.Error("failed to commit notification event", zap.Error(err))
+		}
+	}
+}
+
+// processMessage decodes and handles a single message
+func (c *NotificationEventConsumer) processMessage(ctx context.Context, message kafka.Message) error {
+	var event domain.NotificationEvent
+	if err := json.Unmarshal(message.Value, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal notification event: %w", err)
+	}
+
+	handlerCtx, cancel := context.WithTimeout(ctx, notificationEventTimeout)
+	defer cancel()
+	return c.handler.HandleNotificationEvent(handlerCtx, &event)
+}
+
+// Close closes the Kafka reader connection
+func (c *NotificationEventConsumer) Close() error {
+	if c.reader != nil {
+		return c.reader.Close()
+	}
+	return nil
+}
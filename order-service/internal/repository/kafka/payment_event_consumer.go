@@ -0,0 +1,109 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"order-service/internal/domain"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+// PaymentEventHandler handles payment events (implemented by service.OrderService)
+type PaymentEventHandler interface {
+	HandlePaymentEvent(ctx context.Context, event *domain.PaymentEvent) error
+}
+
+// paymentEventTimeout bounds handling of one payment event (may call product-service)
+const paymentEventTimeout = 30 * time.Second
+
+// PaymentEventConsumer consumes the payment topics of payment-service and moves pending
+// orders to paid or cancelled. Messages are committed after handling (at-least-once);
+// both transitions are conditional on the order status, so redeliveries are no-ops
+type PaymentEventConsumer struct {
+	reader  *kafka.Reader
+	handler PaymentEventHandler
+	logger  *zap.Logger
+}
+
+// NewPaymentEventConsumer creates a new Kafka consumer for payment events
+func NewPaymentEventConsumer(
+	brokers []string,
+	topics []string,
+	consumerGroup string,
+	handler PaymentEventHandler,
+	logger *zap.Logger,
+) *PaymentEventConsumer {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:        brokers,
+		GroupTopics:    topics,
+		GroupID:        consumerGroup,
+		MinBytes:       1,
+		MaxBytes:       10e6,
+		ReadBackoffMin: 100 * time.Millisecond,
+		ReadBackoffMax: 1 * time.Second,
+	})
+
+	return &PaymentEventConsumer{
+		reader:  reader,
+		handler: handler,
+		logger:  logger,
+	}
+}
+
+// Start consumes messages until ctx is canceled
+func (c *PaymentEventConsumer) Start(ctx context.Context) {
+	c.logger.Info("payment event consumer started",
+		zap.Strings("topics", c.reader.Config().GroupTopics),
+		zap.String("consumer_group", c.reader.Config().GroupID),
+	)
+
+	for {
+		message, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				c.logger.Info("payment event consumer stopped")
+				return
+			}
+			c.logger.Error("failed to fetch payment event", zap.Error(err))
+			time.Sleep(1 * time.Second) // Backoff on error
+			continue
+		}
+
+		if err := c.processMessage(ctx, message); err != nil {
+			// Logged and skipped: a stuck message must not block the partition
+			c.logger.Error("failed to handle payment event",
+				zap.String("topic", message.Topic),
+				zap.Int("partition", message.Partition),
+				zap.Int64("offset", message.Offset),
+				zap.Error(err),
+			)
+		}
+
+		if err := c.reader.CommitMessages(ctx, message); err != nil && ctx.Err() == nil {
+			c.logger.Error("failed to commit payment event", zap.Error(err))
+		}
+	}
+}
+
+// processMessage decodes and handles a single message
+func (c *PaymentEventConsumer) processMessage(ctx context.Context, message kafka.Message) error {
+	var event domain.PaymentEvent
+	if err := json.Unmarshal(message.Value, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal payment event: %w", err)
+	}
+
+	handlerCtx, cancel := context.WithTimeout(ctx, paymentEventTimeout)
+	defer cancel()
+	return c.handler.HandlePaymentEvent(handlerCtx, &event)
+}
+
+// Close closes the Kafka reader connection
+func (c *PaymentEventConsumer) Close() error {
+	if c.reader != nil {
+		return c.reader.Close()
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"order-service/internal/domain"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+// ProductEventConsumer consumes product events from Product Service and hands
+// product_updated to the cart so affected lines get flagged, and product_updated /
+// product_item_updated to the product subscriptions (price drop, back in stock)
+// Messages are processed in order and committed after handling (at-least-once);
+// handling is idempotent, so redelivery only repeats the checks
+type ProductEventConsumer struct {
+	reader   *kafka.Reader
+	handlers []domain.ProductEventHandler
+	logger   *zap.Logger
+}
+
+// productEventTimeout bounds handling of one event by one handler (e.g. all carts holding the product)
+const productEventTimeout = 30 * time.Second
+
+// NewProductEventConsumer creates a new Kafka consumer for product events
+// Every handler sees every event; handlers that implement domain.ProductItemEventHandler
+// also get product_item_updated
+func NewProductEventConsumer(
+	brokers []string,
+	topics []string,
+	consumerGroup string,
+	handlers []domain.ProductEventHandler,
+	logger *zap.Logger,
+) *ProductEventConsumer {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:        brokers,
+		GroupTopics:    topics,
+		GroupID:        consumerGroup,
+		MinBytes:       1,
+		MaxBytes:       10e6,
+		ReadBackoffMin: 100 * time.Millisecond,
+		ReadBackoffMax: 1 * time.Second,
+	})
+
+	return &ProductEventConsumer{
+		reader:   reader,
+		handlers: handlers,
+		logger:   logger,
+	}
+}
+
+// Start consumes messages until ctx is canceled
+func (c *ProductEventConsumer) Start(ctx context.Context) {
+	c.logger.Info("product event consumer started",
+		zap.Strings("topics", c.reader.Config().GroupTopics),
+		zap.String("consumer_group", c.reader.Config().GroupID),
+	)
+
+	for {
+		message, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				c.logger.Info("product event consumer stopped")
+				return
+			}
+			c.logger.Error("failed to fetch product event", zap.Error(err))
+			time.Sleep(1 * time.Second) // Backoff on error
+			continue
+		}
+
+		if err := c.processMessage(ctx, message); err != nil {
+			// Logged and skipped: a stuck message must not block the partition,
+			// GetCart still compares snapshots against live prices
+			c.logger.Error("failed to handle product event",
+				zap.String("topic", message.Topic),
+				zap.Int("partition", message.Partition),
+				zap.Int64("offset", message.Offset),
+				zap.Error(err),
+			)
+		}
+
+		if err := c.reader.CommitMessages(ctx, message); err != nil && ctx.Err() == nil {
+			c.logger.Error("failed to commit product event", zap.Error(err))
+		}
+	}
+}
+
+// processMessage decodes and dispatches a single message
+func (c *ProductEventConsumer) processMessage(ctx context.Context, message kafka.Message) error {
+	var event domain.ProductEvent
+	if err := json.Unmarshal(message.Value, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal product event: %w", err)
+	}
+
+	switch event.EventType {
+	case "product_updated":
+		if event.ProductID == 0 {
+			return errors.New("product_updated event without product_id")
+		}
+
+		c.logger.Debug("handling product_updated",
+			zap.Uint("product_id", event.ProductID),
+			zap.Time("timestamp", event.Timestamp),
+		)
+		return c.dispatch(ctx, func(ctx context.Context, h domain.ProductEventHandler) error {
+			return h.HandleProductUpdated(ctx, event.ProductID)
+		})
+
+	case "product_item_updated":
+		var change domain.ProductItemChange
+		if err := json.Unmarshal(event.Metadata, &change); err != nil || event.ProductID == 0 || change.ProductItemID == 0 {
+			return errors.New("product_item_updated event without product_id or product_item_id")
+		}
+
+		c.logger.Debug("handling product_item_updated",
+			zap.Uint("product_id", event.ProductID),
+			zap.Uint("product_item_id", change.ProductItemID),
+			zap.Time("timestamp", event.Timestamp),
+		)
+		return c.dispatch(ctx, func(ctx context.Context, h domain.ProductEventHandler) error {
+			if itemHandler, ok := h.(domain.ProductItemEventHandler); ok {
+				return itemHandler.HandleProductItemUpdated(ctx, event.ProductID, &change)
+			}
+			return nil
+		})
+
+	default:
+		// product_created and others do not affect existing carts or subscriptions
+		return nil
+	}
+}
+
+// dispatch runs fn for every handler; one failing handler does not stop the others
+func (c *ProductEventConsumer) dispatch(ctx context.Context, fn func(ctx context.Context, h domain.ProductEventHandler) error) error {
+	var errs []error
+	for _, h := range c.handlers {
+		handlerCtx, cancel := context.WithTimeout(ctx, productEventTimeout)
+		if err := fn(handlerCtx, h); err != nil {
+			errs = append(errs, err)
+		}
+		cancel()
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("product event handlers failed: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// Close closes the Kafka reader connection
+func (c *ProductEventConsumer) Close() error {
+	if c.reader != nil {
+		return c.reader.Close()
+	}
+	return nil
+}
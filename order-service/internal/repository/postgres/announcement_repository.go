@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"order-service/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AnnouncementRepository handles database operations for admin announcements
+type AnnouncementRepository struct {
+	db *gorm.DB
+}
+
+// NewAnnouncementRepository creates a new announcement repository
+func NewAnnouncementRepository(db *gorm.DB) *AnnouncementRepository {
+	return &AnnouncementRepository{db: db}
+}
+
+// Create inserts an announcement
+func (r *AnnouncementRepository) Create(ctx context.Context, announcement *domain.Announcement) error {
+	return r.db.WithContext(ctx).Create(announcement).Error
+}
+
+// GetByID retrieves an announcement
+func (r *AnnouncementRepository) GetByID(ctx context.Context, id uint) (*domain.Announcement, error) {
+	var announcement domain.Announcement
+	if err := r.db.WithContext(ctx).First(&announcement, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrAnnouncementNotFound
+		}
+		return nil, err
+	}
+	return &announcement, nil
+}
+
+// List returns announcements, newest first
+func (r *AnnouncementRepository) List(ctx context.Context, page, limit int) ([]*domain.Announcement, int64, error) {
+	var announcements []*domain.Announcement
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.Announcement{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&announcements).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return announcements, total, nil
+}
+
+// SaveProgress writes the status and fan-out progress if the announcement is still in one of the from statuses
+func (r *AnnouncementRepository) SaveProgress(ctx context.Context, announcement *domain.Announcement, from ...domain.AnnouncementStatus) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.Announcement{}).
+		Where("id = ? AND status IN ?", announcement.ID, from).
+		Updates(map[string]interface{}{
+			"status":         announcement.Status,
+			"failure_reason": announcement.FailureReason,
+			"last_user_id":   announcement.LastUserID,
+			"sent_count":     announcement.SentCount,
+			"started_at":     announcement.StartedAt,
+			"completed_at":   announcement.CompletedAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Cancel moves a QUEUED or SENDING announcement to CANCELED
+func (r *AnnouncementRepository) Cancel(ctx context.Context, id uint, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.Announcement{}).
+		Where("id = ? AND status IN ?", id, []domain.AnnouncementStatus{domain.AnnouncementStatusQueued, domain.AnnouncementStatusSending}).
+		Updates(map[string]interface{}{
+			"status":       domain.AnnouncementStatusCanceled,
+			"completed_at": at,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
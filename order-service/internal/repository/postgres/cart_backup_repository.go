@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"errors"
+	"order-service/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CartBackupRepository handles database operations for cart backups
+type CartBackupRepository struct {
+	db *gorm.DB
+}
+
+// NewCartBackupRepository creates a new cart backup repository
+func NewCartBackupRepository(db *gorm.DB) *CartBackupRepository {
+	return &CartBackupRepository{db: db}
+}
+
+// Get returns the user's cart backup, nil if there is none
+func (r *CartBackupRepository) Get(userID string) (*domain.CartBackup, error) {
+	var backup domain.CartBackup
+	err := r.db.Where("user_id = ?", userID).First(&backup).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &backup, nil
+}
+
+// Upsert inserts or replaces the user's cart backup
+func (r *CartBackupRepository) Upsert(backup *domain.CartBackup) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"data", "version", "updated_at"}),
+	}).Create(backup).Error
+}
+
+// Delete removes the user's cart backup
+func (r *CartBackupRepository) Delete(userID string) error {
+	return r.db.Where("user_id = ?", userID).Delete(&domain.CartBackup{}).Error
+}
+
+// DeleteOlderThan removes backups of carts that have expired in Redis
+func (r *CartBackupRepository) DeleteOlderThan(before time.Time) (int64, error) {
+	result := r.db.Where("updated_at < ?", before).Delete(&domain.CartBackup{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"order-service/internal/domain"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// DisputeRepository handles database operations for order disputes
+type DisputeRepository struct {
+	db *gorm.DB
+}
+
+// NewDisputeRepository creates a new dispute repository
+func NewDisputeRepository(db *gorm.DB) *DisputeRepository {
+	return &DisputeRepository{db: db}
+}
+
+// Create inserts the dispute with its initial evidence
+// Returns domain.ErrDisputeExists if the order already has a dispute
+func (r *DisputeRepository) Create(ctx context.Context, dispute *domain.Dispute) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.Create(dispute).Error
+	})
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return domain.ErrDisputeExists
+	}
+	return err
+}
+
+// GetByID retrieves a dispute with its evidence, oldest evidence first
+func (r *DisputeRepository) GetByID(ctx context.Context, id uint) (*domain.Dispute, error) {
+	var dispute domain.Dispute
+	err := r.db.WithContext(ctx).
+		Preload("Evidence", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		First(&dispute, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrDisputeNotFound
+		}
+		return nil, err
+	}
+	return &dispute, nil
+}
+
+// List returns disputes matching the filter, newest first (without evidence)
+func (r *DisputeRepository) List(ctx context.Context, filter domain.DisputeFilter, page, limit int) ([]*domain.Dispute, int64, error) {
+	var disputes []*domain.Dispute
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.Dispute{})
+	if filter.BuyerID != 0 {
+		query = query.Where("buyer_id = ?", filter.BuyerID)
+	}
+	if filter.ShopID != 0 {
+		query = query.Where("shop_id = ?", filter.ShopID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&disputes).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return disputes, total, nil
+}
+
+// AddEvidence inserts an evidence item
+func (r *DisputeRepository) AddEvidence(ctx context.Context, evidence *domain.DisputeEvidence) error {
+	return r.db.WithContext(ctx).Create(evidence).Error
+}
+
+// CountEvidence counts the evidence items of a dispute
+func (r *DisputeRepository) CountEvidence(ctx context.Context, disputeID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.DisputeEvidence{}).
+		Where("dispute_id = ?", disputeID).
+		Count(&count).Error
+	return count, err
+}
+
+// UpdateStatus moves the dispute to dispute.Status if it is still in one of the from statuses
+func (r *DisputeRepository) UpdateStatus(ctx context.Context, dispute *domain.Dispute, from ...domain.DisputeStatus) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.Dispute{}).
+		Where("id = ? AND status IN ?", dispute.ID, from).
+		Updates(map[string]interface{}{
+			"status":      dispute.Status,
+			"reviewed_by": dispute.ReviewedBy,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Resolve stores the decision and, if given, the refund adjustment of the order and the
+// payout adjustment in one transaction
+// Reports false (and writes nothing) if the dispute is no longer in one of the from statuses;
+// returns domain.ErrAdjustmentExceedsOrder if the order's totals cannot cover the refund
+func (r *DisputeRepository) Resolve(ctx context.Context, dispute *domain.Dispute, refund *domain.DisputeRefund, from ...domain.DisputeStatus) (bool, error) {
+	resolved := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Dispute{}).
+			Where("id = ? AND status IN ?", dispute.ID, from).
+			Updates(map[string]interface{}{
+				"status":          dispute.Status,
+				"refund_amount":   dispute.RefundAmount,
+				"resolution_note": dispute.ResolutionNote,
+				"resolved_by":     dispute.ResolvedBy,
+				"resolved_at":     dispute.ResolvedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		resolved = true
+
+		if refund == nil {
+			return nil
+		}
+		if _, err := appendOrderAdjustment(tx, refund.Order, refund.Adjustment); err != nil {
+			return err
+		}
+		return tx.Create(refund.Payout).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return resolved, nil
+}
@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"order-service/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PostJournalEntry stores an entry with its lines; an entry with the same source key
+// already posted is left alone (reports false)
+func (r *OrderRepository) PostJournalEntry(entry *domain.JournalEntry) (bool, error) {
+	var posted bool
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		posted, err = postJournalEntry(tx, entry)
+		return err
+	})
+	return posted, err
+}
+
+// ListJournalEntries returns the entries posted in [from, to) with their lines, oldest first
+func (r *OrderRepository) ListJournalEntries(from, to time.Time) ([]domain.JournalEntry, error) {
+	var entries []domain.JournalEntry
+	err := r.db.
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Where("posted_at >= ? AND posted_at < ?", from, to).
+		Order("posted_at, id").
+		Find(&entries).Error
+	return entries, err
+}
+
+// postJournalEntry inserts an entry and its lines within tx unless its source key is taken
+func postJournalEntry(tx *gorm.DB, entry *domain.JournalEntry) (bool, error) {
+	lines := entry.Lines
+	entry.Lines = nil
+	defer func() { entry.Lines = lines }()
+
+	result := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "source_key"}},
+		DoNothing: true,
+	}).Create(entry)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	for i := range lines {
+		lines[i].EntryID = entry.ID
+	}
+	if len(lines) > 0 {
+		if err := tx.Create(&lines).Error; err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"order-service/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationRepository handles database operations for the in-app notification inbox
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Create inserts the notification; a redelivered event (same dedupe key) is ignored
+func (r *NotificationRepository) Create(ctx context.Context, notification *domain.Notification) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "dedupe_key"}},
+		DoNothing: true,
+	}).Create(notification)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListByUser returns the user's notifications, newest first
+func (r *NotificationRepository) ListByUser(ctx context.Context, userID uint, unreadOnly bool, page, limit int) ([]*domain.Notification, int64, error) {
+	var notifications []*domain.Notification
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, total, nil
+}
+
+// CountUnread returns how many of the user's notifications are unread
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkRead marks one of the user's notifications as read (already read stays unchanged)
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID, id uint) error {
+	var notification domain.Notification
+	if err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&notification).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrNotificationNotFound
+		}
+		return err
+	}
+	if notification.IsRead() {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Model(&notification).Update("read_at", time.Now()).Error
+}
+
+// MarkAllRead marks all unread notifications of the user as read, returning how many changed
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID uint) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&domain.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+// CountByAnnouncement counts the inbox entries of an announcement and how many were read
+func (r *NotificationRepository) CountByAnnouncement(ctx context.Context, announcementID uint) (int64, int64, error) {
+	var counts struct {
+		Delivered int64
+		Opened    int64
+	}
+	err := r.db.WithContext(ctx).Model(&domain.Notification{}).
+		Select("COUNT(*) AS delivered, COUNT(read_at) AS opened").
+		Where("announcement_id = ?", announcementID).
+		Scan(&counts).Error
+	return counts.Delivered, counts.Opened, err
+}
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"order-service/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// orderAdjustmentLockKey namespaces the per-order advisory locks taken while appending
+// adjustments (pg_advisory_xact_lock(key, order_id))
+const orderAdjustmentLockKey = 7_246_002
+
+// BackfillDisputeRefunds records the refund adjustments of disputes decided for the buyer
+// before refunds were kept as order adjustments (their payout adjustment has the seller's
+// share); disputes that already have one are skipped, so it is safe to run on every start
+func BackfillDisputeRefunds(db *gorm.DB) error {
+	return db.Exec(`INSERT INTO order_adjustments
+			(order_id, type, final_amount_delta, platform_fee_delta, earning_amount_delta, shipping_fee_delta, reason, dispute_id, created_by, created_at)
+		SELECT d.order_id, ?, -d.refund_amount, -(d.refund_amount + pa.amount), pa.amount, 0, pa.reason, d.id, d.resolved_by, d.resolved_at
+		FROM disputes d JOIN payout_adjustments pa ON pa.dispute_id = d.id
+		WHERE d.status = ?
+		ON CONFLICT (dispute_id) DO NOTHING`,
+		domain.OrderAdjustmentRefund, domain.DisputeStatusResolvedBuyer).Error
+}
+
+// ListAdjustments returns the adjustments of an order, oldest first
+// Adjustments are kept when the order is archived
+func (r *OrderRepository) ListAdjustments(orderID uint) ([]domain.OrderAdjustment, error) {
+	var adjustments []domain.OrderAdjustment
+	err := r.db.Where("order_id = ?", orderID).Order("id").Find(&adjustments).Error
+	return adjustments, err
+}
+
+// AppendAdjustment stores an adjustment of the order and, if given, the payout adjustment
+// of its earning difference in one transaction, and returns the order's new totals
+// Returns domain.ErrAdjustmentExceedsOrder (and writes nothing) if the totals would not be valid
+func (r *OrderRepository) AppendAdjustment(order *domain.Order, adjustment *domain.OrderAdjustment, payout *domain.PayoutAdjustment) (domain.OrderTotals, error) {
+	var totals domain.OrderTotals
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		totals, err = appendOrderAdjustment(tx, order, adjustment)
+		if err != nil {
+			return err
+		}
+		if payout != nil {
+			payout.OrderAdjustmentID = &adjustment.ID
+			return tx.Create(payout).Error
+		}
+		return nil
+	})
+	return totals, err
+}
+
+// appendOrderAdjustment inserts an adjustment and its journal entry within tx once the
+// order's totals with it are known to be valid. Appends to one order are serialized by
+// an advisory lock, so concurrent adjustments are checked against each other
+func appendOrderAdjustment(tx *gorm.DB, order *domain.Order, adjustment *domain.OrderAdjustment) (domain.OrderTotals, error) {
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", orderAdjustmentLockKey, int(order.ID)).Error; err != nil {
+		return domain.OrderTotals{}, err
+	}
+
+	var history []domain.OrderAdjustment
+	if err := tx.Where("order_id = ?", order.ID).Order("id").Find(&history).Error; err != nil {
+		return domain.OrderTotals{}, err
+	}
+	totals := domain.DeriveOrderTotals(order, history).Apply(adjustment)
+	if !totals.Valid() {
+		return domain.OrderTotals{}, domain.ErrAdjustmentExceedsOrder
+	}
+
+	adjustment.OrderID = order.ID
+	if err := tx.Create(adjustment).Error; err != nil {
+		return domain.OrderTotals{}, err
+	}
+	if _, err := postJournalEntry(tx, domain.NewAdjustmentJournalEntry(order, adjustment)); err != nil {
+		return domain.OrderTotals{}, err
+	}
+	return totals, nil
+}
@@ -0,0 +1,240 @@
+package postgres
+
+import (
+	"fmt"
+	"order-service/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// partitionLockKey serializes partition DDL between replicas (pg_advisory_xact_lock)
+const partitionLockKey = 7_246_001
+
+// partitionedOrderTables are range-partitioned by ordered_at month, parent first
+// order_line carries a copy of its order's ordered_at so both are partitioned alike
+var partitionedOrderTables = []string{"shop_order", "order_line"}
+
+// MigrateOrderPartitions turns shop_order and order_line into tables partitioned by
+// ordered_at month and creates the partitions from the oldest order up to monthsAhead
+// months from now. Run it before AutoMigrate: an unpartitioned table (or a fresh
+// database) is converted here, AutoMigrate then recreates the secondary indexes on
+// the partitioned tables. Already partitioned tables only get missing partitions
+//
+// Partitioned tables require the partition key in every unique key, so the primary
+// keys are (id, ordered_at) and order_line references shop_order by (order_id, ordered_at);
+// order numbers are kept unique by order_numbers (see MigrateOrderNumbers)
+func MigrateOrderPartitions(db *gorm.DB, monthsAhead int) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", partitionLockKey).Error; err != nil {
+			return err
+		}
+
+		kind, err := relationKind(tx, "shop_order")
+		if err != nil {
+			return err
+		}
+		switch kind {
+		case "p":
+			// Already partitioned
+		case "":
+			// Fresh database: create the tables from the models, then convert them (empty)
+			if err := tx.Migrator().CreateTable(&domain.Order{}, &domain.OrderItem{}); err != nil {
+				return fmt.Errorf("failed to create order tables: %w", err)
+			}
+			if err := convertOrderTables(tx); err != nil {
+				return err
+			}
+		default:
+			if err := backfillOrderLineOrderedAt(tx); err != nil {
+				return err
+			}
+			if err := convertOrderTables(tx); err != nil {
+				return err
+			}
+		}
+
+		from := time.Now()
+		var oldest *time.Time
+		if err := tx.Raw("SELECT MIN(ordered_at) FROM shop_order").Scan(&oldest).Error; err != nil {
+			return err
+		}
+		if oldest != nil && oldest.Before(from) {
+			from = *oldest
+		}
+		return ensureMonthlyPartitions(tx, from, time.Now().AddDate(0, monthsAhead, 0))
+	})
+}
+
+// MigrateOrderNumbers creates order_numbers, the unpartitioned table keeping order numbers
+// unique across partitions, and fills it with the numbers of the existing orders (archived
+// included) when it is created. Run it after AutoMigrate
+func MigrateOrderNumbers(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", partitionLockKey).Error; err != nil {
+			return err
+		}
+		if tx.Migrator().HasTable(&domain.IssuedOrderNumber{}) {
+			return nil
+		}
+		if err := tx.Migrator().CreateTable(&domain.IssuedOrderNumber{}); err != nil {
+			return fmt.Errorf("failed to create order_numbers: %w", err)
+		}
+		err := tx.Exec(`INSERT INTO order_numbers (order_number, ordered_at)
+			SELECT order_number, ordered_at FROM shop_order
+			UNION SELECT order_number, ordered_at FROM shop_order_archive
+			ON CONFLICT (order_number) DO NOTHING`).Error
+		if err != nil {
+			return fmt.Errorf("failed to backfill order_numbers: %w", err)
+		}
+		return nil
+	})
+}
+
+// EnsureOrderPartitions creates the monthly partitions of the orders placed from this
+// month up to monthsAhead months from now; existing partitions are kept
+func (r *OrderRepository) EnsureOrderPartitions(monthsAhead int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", partitionLockKey).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		return ensureMonthlyPartitions(tx, now, now.AddDate(0, monthsAhead, 0))
+	})
+}
+
+// relationKind returns the pg_class.relkind of a table in the current schema
+// ("r" table, "p" partitioned table), or "" if it does not exist
+func relationKind(tx *gorm.DB, table string) (string, error) {
+	var kinds []string
+	err := tx.Raw(`SELECT c.relkind::text FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relname = ? AND n.nspname = current_schema()`, table).
+		Scan(&kinds).Error
+	if err != nil || len(kinds) == 0 {
+		return "", err
+	}
+	return kinds[0], nil
+}
+
+// backfillOrderLineOrderedAt copies ordered_at from shop_order onto the order lines
+// of a database created before partitioning
+func backfillOrderLineOrderedAt(tx *gorm.DB) error {
+	statements := []string{
+		`ALTER TABLE order_line ADD COLUMN IF NOT EXISTS ordered_at timestamptz`,
+		`UPDATE order_line SET ordered_at = shop_order.ordered_at
+			FROM shop_order WHERE shop_order.id = order_line.order_id AND order_line.ordered_at IS NULL`,
+		`ALTER TABLE order_line ALTER COLUMN ordered_at SET NOT NULL`,
+	}
+	for _, stmt := range statements {
+		if err := tx.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("failed to backfill order_line.ordered_at: %w", err)
+		}
+	}
+	return nil
+}
+
+// convertOrderTables replaces the unpartitioned order tables by partitioned copies
+// The old tables are renamed, their rows copied once the partitions they need exist,
+// then dropped; their ID sequences are kept so IDs keep increasing
+func convertOrderTables(tx *gorm.DB) error {
+	for _, table := range partitionedOrderTables {
+		old := table + "_unpartitioned"
+		statements := []string{
+			fmt.Sprintf(`ALTER TABLE %s RENAME TO %s`, table, old),
+			fmt.Sprintf(`CREATE TABLE %s (LIKE %s INCLUDING DEFAULTS) PARTITION BY RANGE (ordered_at)`, table, old),
+		}
+		for _, stmt := range statements {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return fmt.Errorf("failed to partition %s: %w", table, err)
+			}
+		}
+	}
+
+	var bounds struct {
+		Oldest *time.Time
+		Newest *time.Time
+	}
+	if err := tx.Raw("SELECT MIN(ordered_at) AS oldest, MAX(ordered_at) AS newest FROM shop_order_unpartitioned").Scan(&bounds).Error; err != nil {
+		return err
+	}
+	if bounds.Oldest != nil {
+		if err := ensureMonthlyPartitions(tx, *bounds.Oldest, *bounds.Newest); err != nil {
+			return err
+		}
+	}
+
+	for _, table := range partitionedOrderTables {
+		old := table + "_unpartitioned"
+		if err := tx.Exec(fmt.Sprintf(`INSERT INTO %s SELECT * FROM %s`, table, old)).Error; err != nil {
+			return fmt.Errorf("failed to move %s rows: %w", table, err)
+		}
+
+		// The ID sequence belongs to the old table: hand it over before the drop
+		var sequence *string
+		if err := tx.Raw("SELECT pg_get_serial_sequence(?, 'id')", old).Scan(&sequence).Error; err != nil {
+			return err
+		}
+		if sequence != nil {
+			if err := tx.Exec(fmt.Sprintf(`ALTER SEQUENCE %s OWNED BY %s.id`, *sequence, table)).Error; err != nil {
+				return fmt.Errorf("failed to move %s id sequence: %w", table, err)
+			}
+		}
+	}
+
+	// order_line first: it references shop_order
+	statements := []string{
+		`DROP TABLE order_line_unpartitioned`,
+		`DROP TABLE shop_order_unpartitioned`,
+		`ALTER TABLE shop_order ADD PRIMARY KEY (id, ordered_at)`,
+		`ALTER TABLE order_line ADD PRIMARY KEY (id, ordered_at)`,
+		`ALTER TABLE order_line ADD CONSTRAINT fk_shop_order_items
+			FOREIGN KEY (order_id, ordered_at) REFERENCES shop_order (id, ordered_at) ON DELETE CASCADE`,
+	}
+	for _, stmt := range statements {
+		if err := tx.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("failed to finish order partitioning: %w", err)
+		}
+	}
+	return nil
+}
+
+// ensureMonthlyPartitions creates the partitions of both order tables for every
+// month (UTC) from the month of from to the month of to, skipping existing ones
+func ensureMonthlyPartitions(tx *gorm.DB, from, to time.Time) error {
+	from = from.UTC()
+	month := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for !month.After(to) {
+		next := month.AddDate(0, 1, 0)
+		for _, table := range partitionedOrderTables {
+			stmt := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s')`,
+				monthlyPartitionName(table, month), table,
+				month.Format("2006-01-02 15:04:05-07"), next.Format("2006-01-02 15:04:05-07"))
+			if err := tx.Exec(stmt).Error; err != nil {
+				return fmt.Errorf("failed to create partition %s: %w", monthlyPartitionName(table, month), err)
+			}
+		}
+		month = next
+	}
+	return nil
+}
+
+// monthlyPartitionName is e.g. shop_order_y2026m01
+func monthlyPartitionName(table string, month time.Time) string {
+	return fmt.Sprintf("%s_y%04dm%02d", table, month.Year(), int(month.Month()))
+}
+
+// orderNumberWindow returns the ordered_at range of an order number; order numbers
+// carry the day they were issued (ORD-YYYYMMDD-...). The range spans a day either way
+// so orders numbered just before midnight (or in another time zone) are still found.
+// ok is false for numbers in another format
+func orderNumberWindow(orderNumber string) (from, to time.Time, ok bool) {
+	if len(orderNumber) < 12 || orderNumber[:4] != "ORD-" {
+		return time.Time{}, time.Time{}, false
+	}
+	day, err := time.ParseInLocation("20060102", orderNumber[4:12], time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	return day.AddDate(0, 0, -1), day.AddDate(0, 0, 2), true
+}
@@ -1,9 +1,14 @@
 package postgres
 
 import (
+	"errors"
 	"order-service/internal/domain"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // OrderRepository handles database operations for orders
@@ -17,31 +22,78 @@ func NewOrderRepository(db *gorm.DB) *OrderRepository {
 	return &OrderRepository{db: db}
 }
 
+// byOrder scopes a statement on shop_order to one order, including the partition key
+// so only the partition of the order is touched
+func byOrder(order *domain.Order) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("id = ? AND ordered_at = ?", order.ID, order.OrderedAt)
+	}
+}
+
 // Create creates a new order in the database
+// Returns domain.ErrDuplicateOrderNumber if the order number is taken
+// The order number, the order and its items are written in one transaction
 func (r *OrderRepository) Create(order *domain.Order) error {
-	return r.db.Create(order).Error
+	// PostgreSQL keeps microseconds: truncate so the in-memory order still matches its
+	// row in byOrder. Items are stored in the partition of their order
+	order.OrderedAt = order.OrderedAt.Truncate(time.Microsecond)
+	for i := range order.Items {
+		order.Items[i].OrderedAt = order.OrderedAt
+	}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		issued := &domain.IssuedOrderNumber{OrderNumber: order.OrderNumber, OrderedAt: order.OrderedAt}
+		if err := tx.Create(issued).Error; err != nil {
+			return err
+		}
+		return tx.Create(order).Error
+	})
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" && strings.Contains(pgErr.ConstraintName, "order_number") {
+		return domain.ErrDuplicateOrderNumber
+	}
+	return err
 }
 
-// GetByID retrieves an order by ID
+// GetByID retrieves an order by ID, from the archive if it was archived
 func (r *OrderRepository) GetByID(id uint) (*domain.Order, error) {
 	var order domain.Order
 	err := r.db.Preload("Items").First(&order, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return r.getArchived("order_id = ?", id)
+	}
 	if err != nil {
 		return nil, err
 	}
 	return &order, nil
 }
 
-// GetByOrderNumber retrieves an order by order number
+// GetByOrderNumber retrieves an order by order number, from the archive if it was archived
+// The day in the order number limits the lookup to the partitions around it
 func (r *OrderRepository) GetByOrderNumber(orderNumber string) (*domain.Order, error) {
 	var order domain.Order
-	err := r.db.Preload("Items").Where("order_number = ?", orderNumber).First(&order).Error
+	query := r.db.Preload("Items").Where("order_number = ?", orderNumber)
+	if from, to, ok := orderNumberWindow(orderNumber); ok {
+		query = query.Where("ordered_at >= ? AND ordered_at < ?", from, to)
+	}
+	err := query.First(&order).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return r.getArchived("order_number = ?", orderNumber)
+	}
 	if err != nil {
 		return nil, err
 	}
 	return &order, nil
 }
 
+// getArchived loads an archived order; returns gorm.ErrRecordNotFound if there is none
+func (r *OrderRepository) getArchived(query string, args ...interface{}) (*domain.Order, error) {
+	var archived domain.ArchivedOrder
+	if err := r.db.Where(query, args...).First(&archived).Error; err != nil {
+		return nil, err
+	}
+	return archived.Order()
+}
+
 // GetByUserID retrieves all orders for a user
 func (r *OrderRepository) GetByUserID(userID uint, limit, offset int) ([]*domain.Order, int64, error) {
 	var orders []*domain.Order
@@ -92,8 +144,241 @@ func (r *OrderRepository) GetBySessionID(sessionID string, limit, offset int) ([
 	return orders, total, nil
 }
 
-// UpdateStatus updates the status of an order
-func (r *OrderRepository) UpdateStatus(orderID uint, status domain.OrderStatus) error {
-	return r.db.Model(&domain.Order{}).Where("id = ?", orderID).Update("status", status).Error
+// Delete removes an order and its items in one transaction
+// Only used to roll back orders of a failed checkout before anything was published
+func (r *OrderRepository) Delete(order *domain.Order) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("order_id = ? AND ordered_at = ?", order.ID, order.OrderedAt).Delete(&domain.OrderItem{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Scopes(byOrder(order)).Delete(&domain.Order{}).Error
+	})
+}
+
+// TransitionStatus moves an order from change.FromStatus to change.ToStatus (with the
+// extra column updates, if any) and records the change in order_status_history, in one
+// transaction. Reports false (and writes nothing) if the order was no longer in
+// change.FromStatus, e.g. changed by a concurrent request
+func (r *OrderRepository) TransitionStatus(order *domain.Order, change *domain.OrderStatusHistory, updates map[string]interface{}) (bool, error) {
+	if change.CreatedAt.IsZero() {
+		change.CreatedAt = time.Now()
+	}
+	columns := map[string]interface{}{
+		"status":     change.ToStatus,
+		"updated_at": change.CreatedAt,
+	}
+	for column, value := range updates {
+		columns[column] = value
+	}
+
+	changed := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Order{}).
+			Scopes(byOrder(order)).
+			Where("status = ?", change.FromStatus).
+			Updates(columns)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		changed = true
+
+		change.OrderID = order.ID
+		return tx.Create(change).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return changed, nil
+}
+
+// ListStatusHistory returns the status changes of an order, oldest first
+func (r *OrderRepository) ListStatusHistory(orderID uint) ([]domain.OrderStatusHistory, error) {
+	var history []domain.OrderStatusHistory
+	err := r.db.Where("order_id = ?", orderID).Order("id").Find(&history).Error
+	return history, err
+}
+
+// MarkItemsFulfilled records that items of an order were fulfilled (digital codes issued)
+func (r *OrderRepository) MarkItemsFulfilled(order *domain.Order, itemIDs []uint, fulfilledAt time.Time) error {
+	if len(itemIDs) == 0 {
+		return nil
+	}
+	return r.db.Model(&domain.OrderItem{}).
+		Where("id IN ? AND ordered_at = ? AND fulfilled_at IS NULL", itemIDs, order.OrderedAt).
+		Update("fulfilled_at", fulfilledAt).Error
+}
+
+// CountOpenByProductItem counts unfinished orders that contain a product item (SKU)
+func (r *OrderRepository) CountOpenByProductItem(productItemID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.OrderItem{}).
+		Joins("JOIN shop_order ON shop_order.id = order_line.order_id AND shop_order.ordered_at = order_line.ordered_at").
+		Where("order_line.product_item_id = ? AND shop_order.status IN ?", productItemID, domain.OpenOrderStatuses).
+		Distinct("order_line.order_id").
+		Count(&count).Error
+	return count, err
+}
+
+// LatestDeliveredWithProduct returns the user's most recently delivered order containing
+// the product (archived orders are not searched); gorm.ErrRecordNotFound if there is none
+func (r *OrderRepository) LatestDeliveredWithProduct(userID, productID uint) (*domain.Order, error) {
+	var order domain.Order
+	err := r.db.Model(&domain.Order{}).
+		Select("shop_order.*").
+		Joins("JOIN order_line ON order_line.order_id = shop_order.id AND order_line.ordered_at = shop_order.ordered_at").
+		Where("shop_order.user_id = ? AND shop_order.status = ? AND order_line.product_id = ?", userID, domain.OrderStatusDelivered, productID).
+		Order("shop_order.updated_at DESC").
+		Limit(1).
+		Take(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// SumEarningsByShop aggregates delivered orders per shop whose last update falls in [from, to)
+// Orders are updated after they are placed, so partitions of later months are skipped
+func (r *OrderRepository) SumEarningsByShop(from, to time.Time) ([]domain.ShopEarning, error) {
+	var earnings []domain.ShopEarning
+	err := r.db.Model(&domain.Order{}).
+		Select("shop_id, COUNT(*) AS order_count, SUM(final_amount) AS total_amount, SUM(platform_fee) AS platform_fee, SUM(earning_amount) AS earning_amount").
+		Where("status = ? AND updated_at >= ? AND updated_at < ? AND ordered_at < ?", domain.OrderStatusDelivered, from, to, to).
+		Group("shop_id").
+		Scan(&earnings).Error
+	return earnings, err
 }
 
+// SumGMVByShop aggregates the merchandise subtotal of the orders placed in [from, to) per
+// shop, counting only GMVOrderStatuses (archived orders are not included)
+func (r *OrderRepository) SumGMVByShop(from, to time.Time) ([]domain.ShopGMV, error) {
+	var gmv []domain.ShopGMV
+	err := r.db.Model(&domain.Order{}).
+		Select("shop_id, COUNT(*) AS order_count, SUM(merchandise_subtotal) AS gmv").
+		Where("status IN ? AND ordered_at >= ? AND ordered_at < ?", domain.GMVOrderStatuses, from, to).
+		Group("shop_id").
+		Scan(&gmv).Error
+	return gmv, err
+}
+
+// AnonymizeFinishedBefore removes buyer PII from up to limit finished (delivered or
+// cancelled) orders placed before cutoff and returns how many were anonymized
+// UpdateColumns keeps updated_at untouched so payout windows are not affected
+func (r *OrderRepository) AnonymizeFinishedBefore(cutoff time.Time, limit int) (int64, error) {
+	ids := r.db.Model(&domain.Order{}).
+		Select("id").
+		Where("ordered_at < ? AND anonymized_at IS NULL AND status IN ?", cutoff, finishedOrderStatuses).
+		Order("id").
+		Limit(limit)
+
+	result := r.db.Model(&domain.Order{}).
+		Where("ordered_at < ? AND id IN (?)", cutoff, ids).
+		UpdateColumns(map[string]interface{}{
+			"user_id":             0,
+			"shipping_address_id": 0,
+			"anonymized_at":       time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// finishedOrderStatuses are the statuses of orders that no longer change
+var finishedOrderStatuses = []domain.OrderStatus{domain.OrderStatusDelivered, domain.OrderStatusCancelled}
+
+// ArchiveFinishedBefore moves up to limit finished orders placed before cutoff, with
+// their items, to shop_order_archive and returns how many were moved. Orders with an
+// unresolved dispute stay until it is resolved. Rows are locked with SKIP LOCKED, so
+// concurrent runs archive different orders
+func (r *OrderRepository) ArchiveFinishedBefore(cutoff time.Time, limit int) (int64, error) {
+	var archived int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var orders []*domain.Order
+		err := tx.Preload("Items").
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("ordered_at < ? AND status IN ?", cutoff, finishedOrderStatuses).
+			Where("NOT EXISTS (SELECT 1 FROM disputes WHERE disputes.order_id = shop_order.id AND disputes.status IN ?)",
+				[]domain.DisputeStatus{domain.DisputeStatusOpen, domain.DisputeStatusUnderReview}).
+			Order("id").
+			Limit(limit).
+			Find(&orders).Error
+		if err != nil || len(orders) == 0 {
+			return err
+		}
+
+		now := time.Now()
+		archives := make([]*domain.ArchivedOrder, 0, len(orders))
+		ids := make([]uint, 0, len(orders))
+		for _, order := range orders {
+			entry, err := domain.NewArchivedOrder(order, now)
+			if err != nil {
+				return err
+			}
+			archives = append(archives, entry)
+			ids = append(ids, order.ID)
+		}
+
+		// An order archived by an interrupted run that committed is not in shop_order anymore,
+		// so a conflict means the archive row is already there
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&archives).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("ordered_at < ? AND order_id IN ?", cutoff, ids).Delete(&domain.OrderItem{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("ordered_at < ? AND id IN ?", cutoff, ids).Delete(&domain.Order{})
+		archived = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return archived, nil
+}
+
+// AnonymizeArchivedBefore removes buyer PII from up to limit archived orders placed
+// before cutoff (index columns and archived data) and returns how many were anonymized
+func (r *OrderRepository) AnonymizeArchivedBefore(cutoff time.Time, limit int) (int64, error) {
+	var anonymized int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var archives []*domain.ArchivedOrder
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("ordered_at < ? AND anonymized_at IS NULL", cutoff).
+			Order("order_id").
+			Limit(limit).
+			Find(&archives).Error
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, archived := range archives {
+			order, err := archived.Order()
+			if err != nil {
+				return err
+			}
+			order.UserID = 0
+			order.ShippingAddressID = 0
+			order.AnonymizedAt = &now
+			order.ArchivedAt = nil
+			if err := archived.SetOrder(order); err != nil {
+				return err
+			}
+
+			err = tx.Model(&domain.ArchivedOrder{}).
+				Where("order_id = ?", archived.OrderID).
+				UpdateColumns(map[string]interface{}{
+					"user_id":       0,
+					"anonymized_at": now,
+					"data":          archived.Data,
+				}).Error
+			if err != nil {
+				return err
+			}
+			anonymized++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return anonymized, nil
+}
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"fmt"
+	"order-service/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PayoutRepository handles database operations for payout batches
+type PayoutRepository struct {
+	db *gorm.DB
+}
+
+// NewPayoutRepository creates a new payout repository
+func NewPayoutRepository(db *gorm.DB) *PayoutRepository {
+	return &PayoutRepository{db: db}
+}
+
+// CreateIfAbsent inserts a batch unless one exists for the same shop and period
+// Returns false when the batch already existed (job retried or run twice)
+func (r *PayoutRepository) CreateIfAbsent(batch *domain.PayoutBatch) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(batch)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ListPendingAdjustments returns the shop's adjustments not yet applied to a batch, oldest first
+func (r *PayoutRepository) ListPendingAdjustments(shopID uint) ([]*domain.PayoutAdjustment, error) {
+	var adjustments []*domain.PayoutAdjustment
+	err := r.db.Where("shop_id = ? AND status = ?", shopID, domain.PayoutAdjustmentPending).
+		Order("id").
+		Find(&adjustments).Error
+	return adjustments, err
+}
+
+// CreateWithAdjustments is CreateIfAbsent that also marks the given adjustments as applied
+// to the new batch, in one transaction (an adjustment is never deducted twice)
+func (r *PayoutRepository) CreateWithAdjustments(batch *domain.PayoutBatch, adjustmentIDs []uint) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(batch)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		created = true
+
+		if len(adjustmentIDs) == 0 {
+			return nil
+		}
+		result = tx.Model(&domain.PayoutAdjustment{}).
+			Where("id IN ? AND status = ?", adjustmentIDs, domain.PayoutAdjustmentPending).
+			Updates(map[string]interface{}{
+				"status":   domain.PayoutAdjustmentApplied,
+				"batch_id": batch.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != int64(len(adjustmentIDs)) {
+			return fmt.Errorf("payout adjustments of shop %d were applied concurrently", batch.ShopID)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return created, nil
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"context"
+	"order-service/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SubscriptionRepository handles database operations for product subscriptions
+type SubscriptionRepository struct {
+	db *gorm.DB
+}
+
+// NewSubscriptionRepository creates a new product subscription repository
+func NewSubscriptionRepository(db *gorm.DB) *SubscriptionRepository {
+	return &SubscriptionRepository{db: db}
+}
+
+// Upsert creates the subscription or, if the user already has one for the same
+// product, SKU and type, replaces its target and dedupe state
+func (r *SubscriptionRepository) Upsert(ctx context.Context, sub *domain.ProductSubscription) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "product_id"}, {Name: "product_item_id"}, {Name: "type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"target_price", "notified_price", "in_stock", "updated_at"}),
+	}, clause.Returning{}).Create(sub).Error
+}
+
+// ListByUser returns the user's subscriptions, newest first
+func (r *SubscriptionRepository) ListByUser(ctx context.Context, userID uint) ([]*domain.ProductSubscription, error) {
+	var subs []*domain.ProductSubscription
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&subs).Error
+	return subs, err
+}
+
+// ListByProduct returns all subscriptions of a product
+func (r *SubscriptionRepository) ListByProduct(ctx context.Context, productID uint) ([]*domain.ProductSubscription, error) {
+	var subs []*domain.ProductSubscription
+	err := r.db.WithContext(ctx).Where("product_id = ?", productID).Order("id").Find(&subs).Error
+	return subs, err
+}
+
+// Delete removes one of the user's subscriptions
+func (r *SubscriptionRepository) Delete(ctx context.Context, userID, id uint) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&domain.ProductSubscription{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+// SaveState writes the dedupe state if notify_count still equals expectedCount
+func (r *SubscriptionRepository) SaveState(ctx context.Context, sub *domain.ProductSubscription, expectedCount int) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.ProductSubscription{}).
+		Where("id = ? AND notify_count = ?", sub.ID, expectedCount).
+		Updates(map[string]interface{}{
+			"notified_price":   sub.NotifiedPrice,
+			"in_stock":         sub.InStock,
+			"notify_count":     sub.NotifyCount,
+			"last_notified_at": sub.LastNotifiedAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"order-service/internal/domain"
+	"sort"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// VoucherRepository handles database operations for vouchers and their redemptions
+type VoucherRepository struct {
+	db *gorm.DB
+}
+
+// NewVoucherRepository creates a new voucher repository
+func NewVoucherRepository(db *gorm.DB) *VoucherRepository {
+	return &VoucherRepository{db: db}
+}
+
+// Create inserts a voucher; returns domain.ErrVoucherCodeExists if its code is taken
+func (r *VoucherRepository) Create(ctx context.Context, voucher *domain.Voucher) error {
+	err := r.db.WithContext(ctx).Create(voucher).Error
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return domain.ErrVoucherCodeExists
+	}
+	return err
+}
+
+// GetByID retrieves a voucher
+func (r *VoucherRepository) GetByID(ctx context.Context, id uint) (*domain.Voucher, error) {
+	var voucher domain.Voucher
+	if err := r.db.WithContext(ctx).First(&voucher, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrVoucherNotFound
+		}
+		return nil, err
+	}
+	return &voucher, nil
+}
+
+// GetByCodes retrieves the vouchers with the given (normalized) codes; unknown codes are missing
+func (r *VoucherRepository) GetByCodes(ctx context.Context, codes []string) ([]*domain.Voucher, error) {
+	var vouchers []*domain.Voucher
+	err := r.db.WithContext(ctx).Where("code IN ?", codes).Find(&vouchers).Error
+	return vouchers, err
+}
+
+// List returns vouchers matching the filter, newest first
+func (r *VoucherRepository) List(ctx context.Context, filter domain.VoucherFilter, page, limit int) ([]*domain.Voucher, int64, error) {
+	var vouchers []*domain.Voucher
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&domain.Voucher{})
+	switch {
+	case filter.ShopID != nil:
+		query = query.Where("shop_id = ?", *filter.ShopID)
+	case filter.Platform:
+		query = query.Where("shop_id IS NULL")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(limit).Find(&vouchers).Error; err != nil {
+		return nil, 0, err
+	}
+	return vouchers, total, nil
+}
+
+// Update saves the editable settings of a voucher (code, shop and usage count are kept)
+func (r *VoucherRepository) Update(ctx context.Context, voucher *domain.Voucher) error {
+	return r.db.WithContext(ctx).Model(voucher).
+		Select("type", "target", "value", "max_discount", "min_order_value", "usage_limit",
+			"per_user_limit", "starts_at", "ends_at", "is_active").
+		Updates(voucher).Error
+}
+
+// Delete removes a voucher that was never redeemed; returns domain.ErrVoucherInUse otherwise
+func (r *VoucherRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND used_count = 0", id).Delete(&domain.Voucher{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		if _, err := r.GetByID(ctx, id); err != nil {
+			return err
+		}
+		return domain.ErrVoucherInUse
+	}
+	return nil
+}
+
+// Redeem records the redemptions of a checkout and counts them against the vouchers'
+// usage limits, all or nothing. Each voucher row is locked while its limits are checked,
+// so concurrent checkouts cannot both take the last use
+// Returns domain.ErrVoucherUsedUp if a voucher has no use left (overall or for the user)
+func (r *VoucherRepository) Redeem(ctx context.Context, redemptions []*domain.VoucherRedemption) error {
+	// Lock in voucher order so two checkouts with the same vouchers cannot deadlock
+	sorted := append([]*domain.VoucherRedemption(nil), redemptions...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].VoucherID < sorted[j].VoucherID })
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, redemption := range sorted {
+			var voucher domain.Voucher
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&voucher, redemption.VoucherID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return domain.ErrVoucherNotFound
+				}
+				return err
+			}
+			if voucher.UsageLimit > 0 && voucher.UsedCount >= voucher.UsageLimit {
+				return domain.ErrVoucherUsedUp
+			}
+			if voucher.PerUserLimit > 0 {
+				var used int64
+				if err := tx.Model(&domain.VoucherRedemption{}).
+					Where("voucher_id = ? AND user_id = ?", voucher.ID, redemption.UserID).
+					Count(&used).Error; err != nil {
+					return err
+				}
+				if used >= int64(voucher.PerUserLimit) {
+					return domain.ErrVoucherUsedUp
+				}
+			}
+
+			if err := tx.Model(&voucher).UpdateColumn("used_count", gorm.Expr("used_count + 1")).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(redemption).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ReleaseCheckout gives the uses of a failed checkout's vouchers back
+// Safe to call again: released redemptions are gone
+func (r *VoucherRepository) ReleaseCheckout(ctx context.Context, checkoutID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var redemptions []domain.VoucherRedemption
+		if err := tx.Clauses(clause.Returning{}).
+			Where("checkout_id = ?", checkoutID).
+			Delete(&redemptions).Error; err != nil {
+			return err
+		}
+		for _, redemption := range redemptions {
+			if err := tx.Model(&domain.Voucher{}).
+				Where("id = ?", redemption.VoucherID).
+				UpdateColumn("used_count", gorm.Expr("GREATEST(used_count - 1, 0)")).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
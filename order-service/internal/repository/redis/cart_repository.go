@@ -11,14 +11,26 @@ import (
 	"go.uber.org/zap"
 )
 
+// CartPolicy controls cart expiry and Postgres backup
+type CartPolicy struct {
+	TTL     time.Duration          // How long an untouched cart (and its product index entries) lives
+	Sliding bool                   // Reads also extend the TTL, not only writes
+	Backups domain.CartBackupStore // Postgres backup used to restore carts lost from Redis (nil disables)
+}
+
 type cartRepository struct {
 	client *redis.Client
+	policy CartPolicy
 	logger *zap.Logger
 }
 
-func NewCartRepository(client *redis.Client, logger *zap.Logger) domain.CartRepository {
+func NewCartRepository(client *redis.Client, policy CartPolicy, logger *zap.Logger) domain.CartRepository {
+	if policy.TTL <= 0 {
+		policy.TTL = 30 * 24 * time.Hour
+	}
 	return &cartRepository{
 		client: client,
+		policy: policy,
 		logger: logger,
 	}
 }
@@ -28,6 +40,14 @@ func (r *cartRepository) getCartKey(userID string) string {
 	return fmt.Sprintf("cart:user:%s", userID)
 }
 
+// Redis key of the set of users whose cart holds items of a product
+func (r *cartRepository) getProductIndexKey(productID uint) string {
+	return fmt.Sprintf("cart:product:%d", productID)
+}
+
+// cartBackupDirtyKey is the set of users whose cart changed since the last backup sync
+const cartBackupDirtyKey = "cart:backup:dirty"
+
 // GetCart retrieves a cart from Redis
 func (r *cartRepository) GetCart(userID string) (*domain.ShoppingCart, error) {
 	ctx := context.Background()
@@ -35,12 +55,24 @@ func (r *cartRepository) GetCart(userID string) (*domain.ShoppingCart, error) {
 
 	val, err := r.client.Get(ctx, key).Result()
 	if err == redis.Nil {
-		// Return empty cart
-		return &domain.ShoppingCart{
-			UserID:  userID,
-			Items:   make([]*domain.CartItem, 0),
-			Version: 1,
-		}, nil
+		// Lost from Redis (flush/failover)? Restore from the Postgres backup
+		val, err = r.restoreCart(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if val == "" {
+			// Return empty cart
+			return &domain.ShoppingCart{
+				UserID:  userID,
+				Items:   make([]*domain.CartItem, 0),
+				Version: 1,
+			}, nil
+		}
+	} else if err == nil && r.policy.Sliding {
+		// Sliding expiry: an active cart never expires
+		if err := r.client.Expire(ctx, key, r.policy.TTL).Err(); err != nil {
+			r.logger.Warn("failed to extend cart TTL", zap.String("user_id", userID), zap.Error(err))
+		}
 	}
 	if err != nil {
 		r.logger.Error("failed to get cart from Redis",
@@ -67,7 +99,26 @@ func (r *cartRepository) GetCart(userID string) (*domain.ShoppingCart, error) {
 	return &cart, nil
 }
 
-// SaveCart saves a cart to Redis with TTL
+// saveCartScript writes the cart only if the stored version still equals the
+// version the caller read (a missing cart counts as version 1, like GetCart)
+// KEYS[1] = cart key, ARGV[1] = expected version, ARGV[2] = cart JSON, ARGV[3] = TTL ms
+// Returns 1 on success, 0 on version conflict
+var saveCartScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+local version = 1
+if current then
+	version = tonumber(cjson.decode(current)['version']) or 1
+end
+if version ~= tonumber(ARGV[1]) then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+return 1
+`)
+
+// SaveCart saves a cart to Redis with TTL using compare-and-set on the version:
+// if another request saved the cart since it was read, nothing is written and
+// domain.ErrCartVersionConflict is returned. On success cart.Version is bumped
 func (r *cartRepository) SaveCart(cart *domain.ShoppingCart) error {
 	if cart.UserID == "" {
 		return fmt.Errorf("user_id is required - authentication required")
@@ -76,9 +127,6 @@ func (r *cartRepository) SaveCart(cart *domain.ShoppingCart) error {
 	ctx := context.Background()
 	key := r.getCartKey(cart.UserID)
 
-	// Update metadata
-	cart.Version++ // Increment version for optimistic locking
-
 	// Create minimal cart for Redis storage (without computed fields)
 	minimalCart := struct {
 		UserID  string             `json:"user_id"`
@@ -87,7 +135,7 @@ func (r *cartRepository) SaveCart(cart *domain.ShoppingCart) error {
 	}{
 		UserID:  cart.UserID,
 		Items:   cart.Items,
-		Version: cart.Version,
+		Version: cart.Version + 1, // Increment version for optimistic locking
 	}
 
 	// Serialize to JSON
@@ -100,15 +148,31 @@ func (r *cartRepository) SaveCart(cart *domain.ShoppingCart) error {
 		return fmt.Errorf("failed to marshal cart: %w", err)
 	}
 
-	// Save with 30 days TTL
-	ttl := 30 * 24 * time.Hour
-	if err := r.client.Set(ctx, key, cartJSON, ttl).Err(); err != nil {
+	// Save with 30 days TTL if the version did not move
+	saved, err := saveCartScript.Run(ctx, r.client, []string{key}, cart.Version, cartJSON, r.policy.TTL.Milliseconds()).Int()
+	if err != nil {
 		r.logger.Error("failed to save cart to Redis",
 			zap.Error(err),
 			zap.String("user_id", cart.UserID),
 		)
 		return fmt.Errorf("failed to save cart to Redis: %w", err)
 	}
+	if saved == 0 {
+		r.logger.Info("cart version conflict",
+			zap.String("user_id", cart.UserID),
+			zap.Int("expected_version", cart.Version),
+		)
+		return domain.ErrCartVersionConflict
+	}
+
+	cart.Version = minimalCart.Version
+
+	// Picked up by the periodic backup sync
+	if r.policy.Backups != nil {
+		if err := r.client.SAdd(ctx, cartBackupDirtyKey, cart.UserID).Err(); err != nil {
+			r.logger.Warn("failed to mark cart for backup", zap.String("user_id", cart.UserID), zap.Error(err))
+		}
+	}
 
 	r.logger.Info("cart saved successfully",
 		zap.String("user_id", cart.UserID),
@@ -132,6 +196,18 @@ func (r *cartRepository) DeleteCart(userID string) error {
 		return fmt.Errorf("failed to delete cart: %w", err)
 	}
 
+	// Drop the backup too, otherwise the next read would restore the deleted cart
+	if r.policy.Backups != nil {
+		if err := r.policy.Backups.Delete(userID); err != nil {
+			r.logger.Error("failed to delete cart backup",
+				zap.Error(err),
+				zap.String("user_id", userID),
+			)
+			return fmt.Errorf("failed to delete cart backup: %w", err)
+		}
+		r.client.SRem(ctx, cartBackupDirtyKey, userID)
+	}
+
 	r.logger.Info("cart deleted successfully",
 		zap.String("user_id", userID),
 	)
@@ -332,3 +408,112 @@ func (r *cartRepository) GetCartItemCount(userID string) (int, error) {
 
 	return totalCount, nil
 }
+
+// IndexProduct records that the user's cart holds items of the product
+func (r *cartRepository) IndexProduct(productID uint, userID string) error {
+	ctx := context.Background()
+	key := r.getProductIndexKey(productID)
+
+	pipe := r.client.TxPipeline()
+	pipe.SAdd(ctx, key, userID)
+	pipe.Expire(ctx, key, r.policy.TTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to index cart product: %w", err)
+	}
+	return nil
+}
+
+// UnindexProduct removes the user from the product index
+func (r *cartRepository) UnindexProduct(productID uint, userID string) error {
+	ctx := context.Background()
+	if err := r.client.SRem(ctx, r.getProductIndexKey(productID), userID).Err(); err != nil {
+		return fmt.Errorf("failed to unindex cart product: %w", err)
+	}
+	return nil
+}
+
+// GetUsersByProduct returns users whose cart (may) hold items of the product
+func (r *cartRepository) GetUsersByProduct(productID uint) ([]string, error) {
+	ctx := context.Background()
+	userIDs, err := r.client.SMembers(ctx, r.getProductIndexKey(productID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get carts by product: %w", err)
+	}
+	return userIDs, nil
+}
+
+// restoreCart copies a cart's Postgres backup back into Redis
+// Returns the cart JSON, or "" if there is no usable backup
+func (r *cartRepository) restoreCart(ctx context.Context, userID string) (string, error) {
+	if r.policy.Backups == nil {
+		return "", nil
+	}
+
+	backup, err := r.policy.Backups.Get(userID)
+	if err != nil {
+		// Redis is the source of truth, a broken backup must not break the cart
+		r.logger.Error("failed to load cart backup", zap.String("user_id", userID), zap.Error(err))
+		return "", nil
+	}
+	if backup == nil || time.Since(backup.UpdatedAt) > r.policy.TTL {
+		return "", nil // No backup, or the cart would have expired anyway
+	}
+
+	key := r.getCartKey(userID)
+	restored, err := r.client.SetNX(ctx, key, backup.Data, r.policy.TTL).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to restore cart to Redis: %w", err)
+	}
+	if !restored {
+		// Written concurrently, use that cart
+		val, err := r.client.Get(ctx, key).Result()
+		if err == redis.Nil {
+			return "", nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to get cart from Redis: %w", err)
+		}
+		return val, nil
+	}
+
+	r.logger.Info("cart restored from backup",
+		zap.String("user_id", userID),
+		zap.Int("version", backup.Version),
+		zap.Time("backup_updated_at", backup.UpdatedAt),
+	)
+	return backup.Data, nil
+}
+
+// PopBackupDirty removes and returns up to count users whose cart changed since the last backup
+func (r *cartRepository) PopBackupDirty(count int) ([]string, error) {
+	ctx := context.Background()
+	userIDs, err := r.client.SPopN(ctx, cartBackupDirtyKey, int64(count)).Result()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to pop dirty carts: %w", err)
+	}
+	return userIDs, nil
+}
+
+// MarkBackupDirty re-queues users for the next backup sync (e.g. after a failed upsert)
+func (r *cartRepository) MarkBackupDirty(userIDs ...string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	members := make([]interface{}, len(userIDs))
+	for i, id := range userIDs {
+		members[i] = id
+	}
+	return r.client.SAdd(context.Background(), cartBackupDirtyKey, members...).Err()
+}
+
+// GetRawCart returns the cart JSON as stored in Redis (no restore), "" if missing
+func (r *cartRepository) GetRawCart(userID string) (string, error) {
+	val, err := r.client.Get(context.Background(), r.getCartKey(userID)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get cart from Redis: %w", err)
+	}
+	return val, nil
+}
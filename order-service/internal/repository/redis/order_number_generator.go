@@ -0,0 +1,39 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"order-service/internal/domain"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// orderSeqTTL keeps a day's counter a bit past midnight (clock skew between instances)
+const orderSeqTTL = 48 * time.Hour
+
+// orderNumberGenerator issues order numbers from a per-day Redis counter:
+// ORD-YYYYMMDD-NNNNNNN (INCR is atomic, so instances never hand out the same number)
+type orderNumberGenerator struct {
+	client *redis.Client
+}
+
+// NewOrderNumberGenerator creates a Redis-backed order number generator
+func NewOrderNumberGenerator(client *redis.Client) domain.OrderNumberGenerator {
+	return &orderNumberGenerator{client: client}
+}
+
+// Next returns the next order number of the current day
+func (g *orderNumberGenerator) Next(ctx context.Context) (string, error) {
+	day := time.Now().Format("20060102")
+	key := fmt.Sprintf("order:seq:%s", day)
+
+	pipe := g.client.TxPipeline()
+	seq := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, orderSeqTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", fmt.Errorf("failed to increment order sequence: %w", err)
+	}
+
+	return fmt.Sprintf("ORD-%s-%07d", day, seq.Val()), nil
+}
@@ -1,18 +1,84 @@
 package router
 
 import (
+	"net/http"
 	"order-service/internal/handler"
+	"order-service/pkg/requestid"
+	"order-service/pkg/serviceauth"
+	"slices"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// RequireAdmin middleware only allows requests from ADMIN users
+// Role comes from X-User-Role header, set by API Gateway after JWT validation
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("X-User-Role") != "ADMIN" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin permission required"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireService middleware only allows internal calls signed by one of the given services
+// (X-Service-Token, see pkg/serviceauth); a nil verifier disables the check
+func RequireService(verifier *serviceauth.Verifier, services ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if verifier == nil {
+			c.Next()
+			return
+		}
+		caller, err := verifier.Verify(c.GetHeader(serviceauth.Header))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "service authentication required"})
+			return
+		}
+		if !slices.Contains(services, caller) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "service not allowed"})
+			return
+		}
+		c.Set("calling_service", caller)
+		c.Next()
+	}
+}
+
+// RequestID middleware keeps the request ID (X-Request-Id, set by API Gateway) in the request
+// context for logs and query metrics; requests that bypassed the gateway get a new one
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if id == "" {
+			id = requestid.New()
+		}
+		c.Request = c.Request.WithContext(requestid.NewContext(c.Request.Context(), id))
+		c.Header(requestid.Header, id)
+		c.Next()
+	}
+}
+
 // SetupRouter configures all API routes
 // This is the transport layer - it defines the HTTP API surface
 // NOTE: CORS is handled by API Gateway - this service should only receive internal requests
-func SetupRouter(cartHandler *handler.CartHandler, orderHandler *handler.OrderHandler) *gin.Engine {
-	router := gin.Default()
+func SetupRouter(cartHandler *handler.CartHandler, orderHandler *handler.OrderHandler, jobHandler *handler.JobHandler, logLevelHandler *handler.LogLevelHandler, taskHandler *handler.TaskHandler, subscriptionHandler *handler.SubscriptionHandler, notificationHandler *handler.NotificationHandler, disputeHandler *handler.DisputeHandler, accountingHandler *handler.AccountingHandler, voucherHandler *handler.VoucherHandler, announcementHandler *handler.AnnouncementHandler, serviceAuth *serviceauth.Verifier, recovery gin.HandlerFunc, faults gin.HandlerFunc, impersonationLogger gin.HandlerFunc) *gin.Engine {
+	router := gin.New()
+
+	// Panic recovery first so it also covers the other middleware (replaces gin.Recovery)
+	router.Use(recovery, gin.Logger())
+
+	// Request ID for logs and query metrics
+	router.Use(RequestID())
+
+	// Opt-in latency/error injection for resilience testing (no-op unless enabled)
+	router.Use(faults)
+
+	// Admin impersonation: actions taken on behalf of users are logged distinctly
+	router.Use(impersonationLogger)
 
 	// Swagger documentation
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -20,17 +86,21 @@ func SetupRouter(cartHandler *handler.CartHandler, orderHandler *handler.OrderHa
 	// Health check endpoint
 	router.GET("/health", cartHandler.HealthCheck)
 
+	// Prometheus metrics (OpenMetrics format carries the request ID exemplars)
+	router.GET("/metrics", gin.WrapH(promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{EnableOpenMetrics: true})))
+
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 	{
 		// Cart routes
 		cart := v1.Group("/cart")
 		{
-			cart.GET("", cartHandler.GetCart)                              // Get cart
-			cart.DELETE("", cartHandler.ClearCart)                         // Clear cart
-			cart.POST("/items", cartHandler.AddItem)                       // Add item to cart
-			cart.PUT("/items/:product_item_id", cartHandler.UpdateItem)    // Update item quantity
-			cart.DELETE("/items/:product_item_id", cartHandler.RemoveItem) // Remove item from cart
+			cart.GET("", cartHandler.GetCart)                                            // Get cart
+			cart.DELETE("", cartHandler.ClearCart)                                       // Clear cart
+			cart.POST("/items", cartHandler.AddItem)                                     // Add item to cart
+			cart.PUT("/items/:product_item_id", cartHandler.UpdateItem)                  // Update item quantity
+			cart.DELETE("/items/:product_item_id", cartHandler.RemoveItem)               // Remove item from cart
+			cart.POST("/price-changes/acknowledge", cartHandler.AcknowledgePriceChanges) // Accept changed prices
 		}
 
 		// Order routes
@@ -40,6 +110,90 @@ func SetupRouter(cartHandler *handler.CartHandler, orderHandler *handler.OrderHa
 			orders.GET("", orderHandler.ListOrders)                                 // List orders
 			orders.GET("/:id", orderHandler.GetOrder)                               // Get order by ID
 			orders.GET("/number/:order_number", orderHandler.GetOrderByOrderNumber) // Get order by order number
+			orders.PUT("/:id/status", orderHandler.ChangeStatus)                    // Confirm, ship, deliver or cancel
+
+			// Internal: payment confirmed, fulfill digital items (signed by payment-service)
+			orders.POST("/:id/payment-confirmed", RequireService(serviceAuth, "payment_service"), orderHandler.ConfirmPayment)
+
+			// Internal: product-service checks this before discontinuing a SKU
+			orders.GET("/product-items/:product_item_id/open-count", RequireService(serviceAuth, "product_service"), orderHandler.CountOpenOrdersByProductItem)
+
+			// Internal: product-service checks this before accepting a review (verified purchase)
+			orders.GET("/purchases/verify", RequireService(serviceAuth, "product_service"), orderHandler.VerifyPurchase)
+
+			// Internal: identity-service adds the GMV of each shop to the admin shop export
+			orders.GET("/shops/gmv", RequireService(serviceAuth, "identity_service"), orderHandler.GetShopGMV)
+		}
+
+		// Product subscriptions (price drop, back in stock)
+		subscriptions := v1.Group("/subscriptions")
+		{
+			subscriptions.GET("", subscriptionHandler.ListSubscriptions)  // List subscriptions
+			subscriptions.POST("", subscriptionHandler.Subscribe)         // Subscribe (or update)
+			subscriptions.DELETE("/:id", subscriptionHandler.Unsubscribe) // Unsubscribe
+		}
+
+		// In-app notification inbox (bell icon)
+		notifications := v1.Group("/notifications")
+		{
+			notifications.GET("", notificationHandler.ListNotifications)
+			notifications.GET("/unread-count", notificationHandler.GetUnreadCount)
+			notifications.PUT("/read-all", notificationHandler.MarkAllRead)
+			notifications.PUT("/:id/read", notificationHandler.MarkRead)
+		}
+
+		// Dispute resolution center (buyer, or seller with ?shop_id=)
+		disputes := v1.Group("/disputes")
+		{
+			disputes.POST("", disputeHandler.OpenDispute)
+			disputes.GET("", disputeHandler.ListDisputes)
+			disputes.GET("/:id", disputeHandler.GetDispute)
+			disputes.POST("/:id/evidence", disputeHandler.AddEvidence)
+		}
+
+		// Vouchers (seller for their shop with shop_id, admin for the platform)
+		vouchers := v1.Group("/vouchers")
+		{
+			vouchers.POST("", voucherHandler.CreateVoucher)
+			vouchers.GET("", voucherHandler.ListVouchers)
+			vouchers.GET("/:id", voucherHandler.GetVoucher)
+			vouchers.PUT("/:id", voucherHandler.UpdateVoucher)
+			vouchers.DELETE("/:id", voucherHandler.DeleteVoucher)
+		}
+
+		// Admin: background jobs (namespaced per service behind the gateway)
+		admin := v1.Group("/admin")
+		admin.Use(RequireAdmin())
+		{
+			admin.GET("/jobs/order", jobHandler.GetStats)
+			admin.GET("/jobs/order/list", jobHandler.ListJobs)
+			admin.GET("/jobs/order/:id", jobHandler.GetJob)
+			admin.POST("/jobs/order/:id/retry", jobHandler.RetryJob)
+			admin.DELETE("/jobs/order/:id", jobHandler.DeleteJob)
+			admin.GET("/tasks/order", taskHandler.GetStats)
+			admin.GET("/events/order", taskHandler.GetEventStats)
+			admin.POST("/events/order/flush", taskHandler.FlushEvents)
+
+			// Financial adjustments of orders (fee adjustments, shipping corrections)
+			admin.POST("/orders/:id/adjustments", orderHandler.AdjustOrder)
+
+			// Dispute decisions
+			admin.GET("/disputes", disputeHandler.AdminListDisputes)
+			admin.POST("/disputes/:id/review", disputeHandler.StartReview)
+			admin.POST("/disputes/:id/resolve", disputeHandler.ResolveDispute)
+
+			// Announcement broadcasts to user segments
+			admin.GET("/announcements", announcementHandler.ListAnnouncements)
+			admin.POST("/announcements", announcementHandler.CreateAnnouncement)
+			admin.GET("/announcements/:id", announcementHandler.GetAnnouncement)
+			admin.POST("/announcements/:id/cancel", announcementHandler.CancelAnnouncement)
+
+			// Accounting journal export (finance)
+			admin.GET("/accounting/journal/export", accountingHandler.ExportJournal)
+
+			// Runtime log level
+			admin.GET("/log-level/order", logLevelHandler.GetLogLevel)
+			admin.PUT("/log-level/order", logLevelHandler.SetLogLevel)
 		}
 	}
 
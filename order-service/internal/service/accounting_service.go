@@ -0,0 +1,99 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"order-service/internal/domain"
+	"order-service/internal/repository/postgres"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// maxJournalPeriod bounds one journal export
+const maxJournalPeriod = 366 * 24 * time.Hour
+
+var journalSheetHeader = []any{
+	"Entry ID",
+	"Posted At",
+	"Type",
+	"Order Number",
+	"Shop ID",
+	"Account",
+	"Debit",
+	"Credit",
+	"Description",
+	"Reference",
+}
+
+// AccountingService exports the double-entry journal posted from order payments and
+// adjustments (see domain.JournalEntry) for the finance team
+type AccountingService struct {
+	orderRepo *postgres.OrderRepository
+	logger    *zap.Logger
+}
+
+// NewAccountingService creates a new accounting service
+func NewAccountingService(orderRepo *postgres.OrderRepository, logger *zap.Logger) *AccountingService {
+	return &AccountingService{
+		orderRepo: orderRepo,
+		logger:    logger,
+	}
+}
+
+// JournalPeriod parses an export period of whole days: from and to (YYYY-MM-DD, local
+// time) are both included. Returns [from, day after to)
+func JournalPeriod(from, to string) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation("2006-01-02", from, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, domain.ErrInvalidJournalPeriod
+	}
+	last, err := time.ParseInLocation("2006-01-02", to, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, domain.ErrInvalidJournalPeriod
+	}
+	end := last.AddDate(0, 0, 1)
+	if !start.Before(end) || end.Sub(start) > maxJournalPeriod {
+		return time.Time{}, time.Time{}, domain.ErrInvalidJournalPeriod
+	}
+	return start, end, nil
+}
+
+// ExportJournalRows returns the journal lines posted in [from, to) as sheet rows, one per
+// line under a header row, and a last row with the debit and credit totals
+func (s *AccountingService) ExportJournalRows(from, to time.Time) ([][]any, error) {
+	entries, err := s.orderRepo.ListJournalEntries(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list journal entries: %w", err)
+	}
+
+	rows := [][]any{journalSheetHeader}
+	var debits, credits float64
+	for _, entry := range entries {
+		for _, line := range entry.Lines {
+			rows = append(rows, []any{
+				entry.ID,
+				entry.PostedAt.Format(time.RFC3339),
+				entry.EntryType,
+				entry.OrderNumber,
+				entry.ShopID,
+				string(line.Account),
+				line.Debit,
+				line.Credit,
+				entry.Description,
+				entry.Reference,
+			})
+			debits += line.Debit
+			credits += line.Credit
+		}
+	}
+	rows = append(rows, []any{"Total", nil, nil, nil, nil, nil, math.Round(debits*100) / 100, math.Round(credits*100) / 100})
+
+	s.logger.Info("journal exported",
+		zap.Time("from", from),
+		zap.Time("to", to),
+		zap.Int("entries", len(entries)),
+		zap.Int("lines", len(rows)-2),
+	)
+	return rows, nil
+}
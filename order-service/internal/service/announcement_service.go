@@ -0,0 +1,390 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"order-service/internal/domain"
+	"order-service/pkg/jobs"
+	"order-service/pkg/notification_prefs"
+	"order-service/pkg/product_client"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Announcements are fanned out by a background job in slices: a run publishes for at
+// most announcementSliceDuration, saves its progress after every batch and enqueues the
+// next slice, so no run holds a job lease for the whole audience and a crashed run
+// resumes after the last saved batch
+const (
+	JobTypeAnnouncementFanOut = "notification:announcement_fan_out"
+	announcementSliceDuration = 10 * time.Minute
+	announcementJobTimeout    = 15 * time.Minute
+	maxAnnouncementBody       = 2000
+	maxInactiveDays           = 3650
+)
+
+// Announcement validation errors
+var (
+	ErrInvalidAnnouncementSegment = domain.Validation("segment must be one of %s", strings.Join(domain.AnnouncementSegments, ", "))
+	ErrAnnouncementFinished       = domain.Conflict("announcement is already finished")
+)
+
+// AnnouncementAudience resolves announcement recipients (implemented by audience_client.AudienceClient)
+type AnnouncementAudience interface {
+	Users(ctx context.Context, segment string, inactiveDays int, afterID uint, limit int) ([]uint, error)
+	ShopOwners(ctx context.Context, shopIDs []uint) ([]uint, error)
+}
+
+// AnnouncementCategoryShops finds the shops selling in a category (implemented by product_client.ProductClient)
+type AnnouncementCategoryShops interface {
+	GetCategoryShopIDs(ctx context.Context, categoryID uint) ([]uint, error)
+}
+
+// AnnouncementOptions throttles the fan-out
+type AnnouncementOptions struct {
+	Rate      int // Notification events published per second
+	BatchSize int // Recipients resolved (and progress saved) at a time
+}
+
+// CreateAnnouncementRequest represents the request to broadcast an announcement
+type CreateAnnouncementRequest struct {
+	Title        string `json:"title" binding:"required"`
+	Body         string `json:"body" binding:"required"`
+	Link         string `json:"link,omitempty"` // Client route, e.g. /campaigns/summer-sale
+	Segment      string `json:"segment" binding:"required"`
+	CategoryID   uint   `json:"category_id,omitempty"`   // Required for category_sellers
+	InactiveDays int    `json:"inactive_days,omitempty"` // Required for inactive_users
+}
+
+// announcementJob is the payload of JobTypeAnnouncementFanOut
+type announcementJob struct {
+	AnnouncementID uint `json:"announcement_id"`
+}
+
+// AnnouncementService broadcasts admin announcements to a segment of users through the
+// notification pipeline: one notification event per recipient (topic
+// notification.announcement), published at a throttled rate. Every recipient gets the
+// announcement in their in-app inbox; email, SMS and push follow their "promotions"
+// notification preferences. Delivery and opens are counted from the inbox entries
+type AnnouncementService struct {
+	repo          domain.AnnouncementRepository
+	notifications domain.NotificationRepository
+	publisher     domain.NotificationEventPublisher
+	prefs         NotificationPreferenceReader
+	audience      AnnouncementAudience
+	categoryShops AnnouncementCategoryShops
+	jobs          *jobs.Client
+	opts          AnnouncementOptions
+	logger        *zap.Logger
+}
+
+// NewAnnouncementService creates a new announcement service
+func NewAnnouncementService(
+	repo domain.AnnouncementRepository,
+	notifications domain.NotificationRepository,
+	publisher domain.NotificationEventPublisher,
+	prefs NotificationPreferenceReader,
+	audience AnnouncementAudience,
+	categoryShops AnnouncementCategoryShops,
+	jobClient *jobs.Client,
+	opts AnnouncementOptions,
+	logger *zap.Logger,
+) *AnnouncementService {
+	return &AnnouncementService{
+		repo:          repo,
+		notifications: notifications,
+		publisher:     publisher,
+		prefs:         prefs,
+		audience:      audience,
+		categoryShops: categoryShops,
+		jobs:          jobClient,
+		opts:          opts,
+		logger:        logger,
+	}
+}
+
+// Create stores the announcement and queues its fan-out
+func (s *AnnouncementService) Create(ctx context.Context, adminID uint, req *CreateAnnouncementRequest) (*domain.Announcement, error) {
+	announcement := &domain.Announcement{
+		Title:     strings.TrimSpace(req.Title),
+		Body:      strings.TrimSpace(req.Body),
+		Link:      strings.TrimSpace(req.Link),
+		Segment:   req.Segment,
+		Status:    domain.AnnouncementStatusQueued,
+		CreatedBy: adminID,
+	}
+	switch {
+	case announcement.Title == "" || len(announcement.Title) > 255:
+		return nil, domain.Validation("title is required, at most 255 characters")
+	case announcement.Body == "" || len(announcement.Body) > maxAnnouncementBody:
+		return nil, domain.Validation("body is required, at most %d characters", maxAnnouncementBody)
+	case announcement.Link != "" && (!strings.HasPrefix(announcement.Link, "/") || len(announcement.Link) > 255):
+		return nil, domain.Validation("link must be a client route starting with /, at most 255 characters")
+	}
+
+	switch req.Segment {
+	case domain.AnnouncementSegmentBuyers:
+	case domain.AnnouncementSegmentCategorySellers:
+		if req.CategoryID == 0 {
+			return nil, domain.Validation("category_id is required for segment %s", req.Segment)
+		}
+		announcement.CategoryID = req.CategoryID
+	case domain.AnnouncementSegmentInactiveUsers:
+		if req.InactiveDays < 1 || req.InactiveDays > maxInactiveDays {
+			return nil, domain.Validation("inactive_days must be between 1 and %d for segment %s", maxInactiveDays, req.Segment)
+		}
+		announcement.InactiveDays = req.InactiveDays
+	default:
+		return nil, ErrInvalidAnnouncementSegment
+	}
+
+	if err := s.repo.Create(ctx, announcement); err != nil {
+		return nil, fmt.Errorf("failed to create announcement: %w", err)
+	}
+	if err := s.enqueue(ctx, announcement, 0); err != nil {
+		announcement.Status = domain.AnnouncementStatusFailed
+		announcement.FailureReason = "the fan-out could not be queued"
+		if _, saveErr := s.repo.SaveProgress(ctx, announcement, domain.AnnouncementStatusQueued); saveErr != nil {
+			s.logger.Error("failed to mark announcement failed", zap.Uint("announcement_id", announcement.ID), zap.Error(saveErr))
+		}
+		return nil, err
+	}
+
+	s.logger.Info("announcement queued",
+		zap.Uint("announcement_id", announcement.ID),
+		zap.String("segment", announcement.Segment),
+		zap.Uint("admin_id", adminID),
+	)
+	return announcement, nil
+}
+
+// List returns announcements, newest first
+func (s *AnnouncementService) List(ctx context.Context, page, limit int) ([]*domain.Announcement, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return s.repo.List(ctx, page, limit)
+}
+
+// Get returns an announcement with its delivery stats
+func (s *AnnouncementService) Get(ctx context.Context, id uint) (*domain.Announcement, *domain.AnnouncementStats, error) {
+	announcement, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	delivered, opened, err := s.notifications.CountByAnnouncement(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to count announcement notifications: %w", err)
+	}
+	stats := &domain.AnnouncementStats{
+		Sent:      announcement.SentCount,
+		Delivered: delivered,
+		Opened:    opened,
+	}
+	if delivered > 0 {
+		stats.OpenRate = math.Round(float64(opened)/float64(delivered)*10000) / 10000
+	}
+	return announcement, stats, nil
+}
+
+// Cancel stops the fan-out of a queued or sending announcement; users already notified keep it
+func (s *AnnouncementService) Cancel(ctx context.Context, id uint) (*domain.Announcement, error) {
+	canceled, err := s.repo.Cancel(ctx, id, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel announcement: %w", err)
+	}
+
+	announcement, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !canceled {
+		return nil, ErrAnnouncementFinished
+	}
+
+	s.logger.Info("announcement canceled",
+		zap.Uint("announcement_id", id),
+		zap.Int64("sent_count", announcement.SentCount),
+	)
+	return announcement, nil
+}
+
+// HandleFanOut is the job handler for JobTypeAnnouncementFanOut
+// Publishes batch after batch at the configured rate until the audience is exhausted,
+// the announcement is canceled or the slice is over (the next slice is then enqueued)
+// Errors are returned so the job is retried and resumes after the last saved batch;
+// a category that no longer exists fails the announcement
+func (s *AnnouncementService) HandleFanOut(ctx context.Context, payload []byte) error {
+	var job announcementJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return fmt.Errorf("failed to decode announcement job: %w", err)
+	}
+	announcement, err := s.repo.GetByID(ctx, job.AnnouncementID)
+	if err != nil {
+		if errors.Is(err, domain.ErrAnnouncementNotFound) {
+			return nil
+		}
+		return err
+	}
+	if announcement.Status.IsFinished() {
+		return nil
+	}
+
+	if announcement.Status == domain.AnnouncementStatusQueued {
+		startedAt := time.Now()
+		announcement.Status = domain.AnnouncementStatusSending
+		announcement.StartedAt = &startedAt
+		saved, err := s.repo.SaveProgress(ctx, announcement, domain.AnnouncementStatusQueued)
+		if err != nil || !saved {
+			return err
+		}
+	}
+
+	next, err := s.recipients(ctx, announcement)
+	if errors.Is(err, product_client.ErrNotFound) {
+		return s.fail(ctx, announcement, "the category no longer exists")
+	}
+	if err != nil {
+		return err
+	}
+
+	interval := time.Second / time.Duration(max(s.opts.Rate, 1))
+	throttle := time.NewTicker(interval)
+	defer throttle.Stop()
+
+	deadline := time.Now().Add(announcementSliceDuration)
+	for time.Now().Before(deadline) {
+		userIDs, err := next(announcement.LastUserID)
+		if err != nil {
+			return fmt.Errorf("failed to resolve announcement recipients: %w", err)
+		}
+		if len(userIDs) == 0 {
+			completedAt := time.Now()
+			announcement.Status = domain.AnnouncementStatusSent
+			announcement.CompletedAt = &completedAt
+			if _, err := s.repo.SaveProgress(ctx, announcement, domain.AnnouncementStatusSending); err != nil {
+				return err
+			}
+			s.logger.Info("announcement sent",
+				zap.Uint("announcement_id", announcement.ID),
+				zap.Int64("sent_count", announcement.SentCount),
+			)
+			return nil
+		}
+
+		for _, userID := range userIDs {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-throttle.C:
+			}
+			if err := s.publish(ctx, announcement, userID); err != nil {
+				return err
+			}
+			announcement.SentCount++
+		}
+
+		announcement.LastUserID = userIDs[len(userIDs)-1]
+		saved, err := s.repo.SaveProgress(ctx, announcement, domain.AnnouncementStatusSending)
+		if err != nil {
+			return err
+		}
+		if !saved {
+			s.logger.Info("announcement fan-out stopped, canceled",
+				zap.Uint("announcement_id", announcement.ID),
+				zap.Int64("sent_count", announcement.SentCount),
+			)
+			return nil
+		}
+	}
+
+	// A run that crashed after enqueueing the next slice finds it queued already
+	if err := s.enqueue(ctx, announcement, announcement.LastUserID); err != nil && !errors.Is(err, jobs.ErrDuplicateJob) {
+		return err
+	}
+	return nil
+}
+
+// recipients returns a function listing the next batch of recipient IDs after a user ID
+// (ordered by ID, empty when done)
+func (s *AnnouncementService) recipients(ctx context.Context, announcement *domain.Announcement) (func(afterID uint) ([]uint, error), error) {
+	if announcement.Segment != domain.AnnouncementSegmentCategorySellers {
+		return func(afterID uint) ([]uint, error) {
+			return s.audience.Users(ctx, announcement.Segment, announcement.InactiveDays, afterID, s.opts.BatchSize)
+		}, nil
+	}
+
+	// Sellers of a category are few enough to resolve at once (one owner per shop)
+	shopIDs, err := s.categoryShops.GetCategoryShopIDs(ctx, announcement.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	ownerIDs, err := s.audience.ShopOwners(ctx, shopIDs)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(ownerIDs)
+	return func(afterID uint) ([]uint, error) {
+		start, _ := slices.BinarySearch(ownerIDs, afterID+1)
+		return ownerIDs[start:min(start+s.opts.BatchSize, len(ownerIDs))], nil
+	}, nil
+}
+
+// publish sends the announcement to one user; the dedupe key keeps a republished
+// event (retried batch) from adding a second inbox entry
+func (s *AnnouncementService) publish(ctx context.Context, announcement *domain.Announcement, userID uint) error {
+	event := &domain.NotificationEvent{
+		EventType:      domain.EventNotificationAnnouncement,
+		DedupeKey:      domain.AnnouncementDedupeKey(announcement.ID, userID),
+		UserID:         userID,
+		AnnouncementID: announcement.ID,
+		Title:          announcement.Title,
+		Body:           announcement.Body,
+		Link:           announcement.Link,
+		Channels:       s.prefs.EnabledChannels(ctx, userID, notification_prefs.CategoryPromotions),
+		Timestamp:      time.Now(),
+	}
+	if err := s.publisher.PublishNotificationEvent(event); err != nil {
+		return fmt.Errorf("failed to publish announcement notification: %w", err)
+	}
+	return nil
+}
+
+// enqueue queues the fan-out job of an announcement, resuming after a user ID
+func (s *AnnouncementService) enqueue(ctx context.Context, announcement *domain.Announcement, afterID uint) error {
+	_, err := s.jobs.Enqueue(ctx, JobTypeAnnouncementFanOut, announcementJob{AnnouncementID: announcement.ID},
+		jobs.WithID(fmt.Sprintf("announcement:%d:%d", announcement.ID, afterID)),
+		jobs.Timeout(announcementJobTimeout),
+		jobs.MaxRetries(5),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue announcement fan-out: %w", err)
+	}
+	return nil
+}
+
+// fail stops the fan-out of an announcement for a reason shown to the admins
+func (s *AnnouncementService) fail(ctx context.Context, announcement *domain.Announcement, reason string) error {
+	completedAt := time.Now()
+	announcement.Status = domain.AnnouncementStatusFailed
+	announcement.FailureReason = reason
+	announcement.CompletedAt = &completedAt
+	if _, err := s.repo.SaveProgress(ctx, announcement, domain.AnnouncementStatusSending); err != nil {
+		return err
+	}
+	s.logger.Warn("announcement failed",
+		zap.Uint("announcement_id", announcement.ID),
+		zap.String("reason", reason),
+	)
+	return nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"order-service/internal/repository/postgres"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// JobTypeOrderArchive moves old finished orders to the archive table (no payload)
+const JobTypeOrderArchive = "order:archive"
+
+// archiveBatchSize bounds the orders moved per transaction
+const archiveBatchSize = 200
+
+// ArchiveService keeps shop_order small by moving old finished orders to
+// shop_order_archive; order lookups by ID or number fall back to the archive
+// Windows are admin settings (scope "order"): archive_enabled, archive_after_years
+type ArchiveService struct {
+	orderRepo *postgres.OrderRepository
+	settings  SettingsReader
+	logger    *zap.Logger
+}
+
+// NewArchiveService creates a new archive service
+func NewArchiveService(orderRepo *postgres.OrderRepository, settings SettingsReader, logger *zap.Logger) *ArchiveService {
+	return &ArchiveService{
+		orderRepo: orderRepo,
+		settings:  settings,
+		logger:    logger,
+	}
+}
+
+// HandleArchive is the job handler for JobTypeOrderArchive
+func (s *ArchiveService) HandleArchive(ctx context.Context, _ []byte) error {
+	if !s.settings.GetBool("order", "archive_enabled", false) {
+		return nil
+	}
+
+	years := s.settings.GetInt("order", "archive_after_years", 3)
+	if years <= 0 {
+		s.logger.Warn("order archival skipped, archive_after_years must be positive", zap.Int("archive_after_years", years))
+		return nil
+	}
+
+	_, err := s.ArchiveOlderThan(ctx, time.Now().AddDate(-years, 0, 0))
+	return err
+}
+
+// ArchiveOlderThan moves finished orders placed before cutoff to the archive
+func (s *ArchiveService) ArchiveOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		n, err := s.orderRepo.ArchiveFinishedBefore(cutoff, archiveBatchSize)
+		if err != nil {
+			return total, fmt.Errorf("failed to archive orders: %w", err)
+		}
+		total += n
+		if n < archiveBatchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		s.logger.Info("orders archived",
+			zap.Int64("count", total),
+			zap.Time("cutoff", cutoff),
+		)
+	}
+	return total, nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"order-service/internal/domain"
+	"order-service/internal/repository/postgres"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// JobTypeCartBackupSync copies changed Redis carts to Postgres (no payload)
+const JobTypeCartBackupSync = "cart:backup_sync"
+
+// cartBackupBatchSize is how many changed carts are popped per round
+const cartBackupBatchSize = 200
+
+// CartBackupService keeps the Postgres copy of Redis carts up to date
+// Carts are marked dirty on every save; the periodic job backs up only those
+type CartBackupService struct {
+	cartRepo   domain.CartRepository
+	backupRepo *postgres.CartBackupRepository
+	ttl        time.Duration
+	logger     *zap.Logger
+}
+
+// NewCartBackupService creates a new cart backup service
+// ttl is the cart TTL: older backups belong to expired carts and are purged
+func NewCartBackupService(cartRepo domain.CartRepository, backupRepo *postgres.CartBackupRepository, ttl time.Duration, logger *zap.Logger) *CartBackupService {
+	return &CartBackupService{
+		cartRepo:   cartRepo,
+		backupRepo: backupRepo,
+		ttl:        ttl,
+		logger:     logger,
+	}
+}
+
+// HandleSync is the job handler for JobTypeCartBackupSync
+func (s *CartBackupService) HandleSync(ctx context.Context, _ []byte) error {
+	_, err := s.Sync(ctx)
+	return err
+}
+
+// Sync backs up every cart changed since the last run and purges expired backups
+// Returns the number of carts synced
+func (s *CartBackupService) Sync(ctx context.Context) (int, error) {
+	synced := 0
+	failed := make([]string, 0)
+
+	for ctx.Err() == nil {
+		userIDs, err := s.cartRepo.PopBackupDirty(cartBackupBatchSize)
+		if err != nil {
+			return synced, err
+		}
+		if len(userIDs) == 0 {
+			break
+		}
+
+		for _, userID := range userIDs {
+			if err := s.syncCart(userID); err != nil {
+				failed = append(failed, userID)
+				s.logger.Error("failed to back up cart",
+					zap.String("user_id", userID),
+					zap.Error(err),
+				)
+				continue
+			}
+			synced++
+		}
+	}
+
+	// Retry failed carts next run
+	if err := s.cartRepo.MarkBackupDirty(failed...); err != nil {
+		s.logger.Error("failed to re-queue cart backups", zap.Int("count", len(failed)), zap.Error(err))
+	}
+
+	purged, err := s.backupRepo.DeleteOlderThan(time.Now().Add(-s.ttl))
+	if err != nil {
+		return synced, fmt.Errorf("failed to purge expired cart backups: %w", err)
+	}
+
+	s.logger.Info("cart backup sync finished",
+		zap.Int("synced", synced),
+		zap.Int("failed", len(failed)),
+		zap.Int64("purged", purged),
+	)
+
+	if len(failed) > 0 {
+		return synced, fmt.Errorf("failed to back up %d carts", len(failed))
+	}
+	return synced, nil
+}
+
+// syncCart copies one cart as stored in Redis; a cart gone from Redis loses its backup
+func (s *CartBackupService) syncCart(userID string) error {
+	data, err := s.cartRepo.GetRawCart(userID)
+	if err != nil {
+		return err
+	}
+	if data == "" {
+		return s.backupRepo.Delete(userID)
+	}
+
+	var stored struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal([]byte(data), &stored); err != nil {
+		return fmt.Errorf("failed to unmarshal cart: %w", err)
+	}
+
+	return s.backupRepo.Upsert(&domain.CartBackup{
+		UserID:    userID,
+		Data:      data,
+		Version:   stored.Version,
+		UpdatedAt: time.Now(),
+	})
+}
@@ -14,16 +14,24 @@ import (
 type CartService struct {
 	cartRepo      domain.CartRepository
 	productClient ProductServiceClient
+	settings      SettingsReader
 	logger        *zap.Logger
 }
 
+// SettingsReader reads admin-managed runtime settings (implemented by pkg/settings)
+type SettingsReader interface {
+	GetInt(scope, key string, def int) int
+	GetFloat(scope, key string, def float64) float64
+	GetBool(scope, key string, def bool) bool
+}
+
 // ProductServiceClient defines interface to communicate with Product Service
 type ProductServiceClient interface {
 	// GetProductItem fetches single product item details (SKU-level)
-	GetProductItem(productItemID uint) (*ProductItemDTO, error)
+	GetProductItem(ctx context.Context, productItemID uint) (*ProductItemDTO, error)
 
 	// GetProductItems fetches multiple product items in batch (for performance)
-	GetProductItems(productItemIDs []uint) (map[uint]*ProductItemDTO, error)
+	GetProductItems(ctx context.Context, productItemIDs []uint) (map[uint]*ProductItemDTO, error)
 }
 
 // ProductItemDTO represents product item data from Product Service
@@ -31,6 +39,7 @@ type ProductServiceClient interface {
 // NOTE: This is DISPLAY-ONLY for cart. Order validation uses full DTO with Stock/IsActive.
 type ProductItemDTO struct {
 	ID          uint    `json:"id"`           // ProductItem ID (SKU)
+	ProductID   uint    `json:"product_id"`   // Base product ID
 	ShopID      uint    `json:"shop_id"`      // Shop that owns this product
 	ProductName string  `json:"product_name"` // Product name
 	SKUCode     string  `json:"sku_code"`     // SKU code
@@ -38,26 +47,40 @@ type ProductItemDTO struct {
 	ImageURL    string  `json:"image_url"`    // Product image
 	QtyInStock  int     `json:"qty_in_stock"` // Stock quantity
 	Status      string  `json:"status"`       // ACTIVE, INACTIVE
+
+	MaxPurchaseQuantity int `json:"max_purchase_quantity"` // Per-customer limit of the product (0 = no limit)
 }
 
 // NewCartService creates a new cart service
 func NewCartService(
 	cartRepo domain.CartRepository,
 	productClient ProductServiceClient,
+	settings SettingsReader,
 	logger *zap.Logger,
 ) *CartService {
 	return &CartService{
 		cartRepo:      cartRepo,
 		productClient: productClient,
+		settings:      settings,
 		logger:        logger,
 	}
 }
 
+// maxItemQuantity returns the configured max quantity per cart item
+// (setting cart.max_item_quantity, never above domain.MaxItemQuantity)
+func (s *CartService) maxItemQuantity() int {
+	limit := s.settings.GetInt("cart", "max_item_quantity", domain.MaxItemQuantity)
+	if limit <= 0 || limit > domain.MaxItemQuantity {
+		return domain.MaxItemQuantity
+	}
+	return limit
+}
+
 // GetCart retrieves user's cart and enriches with product data from Product Service
 func (s *CartService) GetCart(ctx context.Context, userID string) (*domain.ShoppingCart, error) {
 	log.Println("Fetching cart for userId:", userID)
 	if userID == "" {
-		return nil, errors.New("user_id is required")
+		return nil, domain.Validation("user_id is required")
 	}
 
 	// 1. Get cart from Redis (only contains product_item_id, quantity, is_selected)
@@ -76,7 +99,7 @@ func (s *CartService) GetCart(ctx context.Context, userID string) (*domain.Shopp
 	}
 
 	// 3. Fetch product details from Product Service
-	if err := s.enrichCartWithProductData(cart); err != nil {
+	if err := s.enrichCartWithProductData(ctx, cart); err != nil {
 		s.logger.Warn("failed to enrich cart with product data",
 			zap.String("user_id", userID),
 			zap.Error(err),
@@ -90,45 +113,124 @@ func (s *CartService) GetCart(ctx context.Context, userID string) (*domain.Shopp
 	return cart, nil
 }
 
+// CartUpdateResult is returned by cart writes
+type CartUpdateResult struct {
+	Cart     *domain.ShoppingCart `json:"cart"`     // Cart after the write (merged if another device wrote in between)
+	Conflict bool                 `json:"conflict"` // Cart changed since the version the client last saw
+}
+
+// errCartUnchanged is returned by an update func to skip the save
+var errCartUnchanged = errors.New("cart unchanged")
+
+// maxCartSaveAttempts bounds compare-and-set retries when devices write concurrently
+const maxCartSaveAttempts = 3
+
+// updateCart applies fn to the latest cart and saves it with compare-and-set.
+// If another request (e.g. the same user on web and app) saved in between, the cart
+// is re-read and fn re-applied: each write only touches its own items, so both
+// changes survive. expectedVersion is the version the client last saw (0 = unknown);
+// the result reports a conflict when it differs from the stored one or a retry happened
+func (s *CartService) updateCart(userID string, expectedVersion int, fn func(cart *domain.ShoppingCart) error) (*CartUpdateResult, error) {
+	conflict := false
+
+	for attempt := 1; ; attempt++ {
+		cart, err := s.cartRepo.GetCart(userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cart: %w", err)
+		}
+		if attempt == 1 && expectedVersion > 0 && cart.Version != expectedVersion {
+			conflict = true
+		}
+
+		if err := fn(cart); err != nil {
+			if errors.Is(err, errCartUnchanged) {
+				return &CartUpdateResult{Cart: cart, Conflict: conflict}, nil
+			}
+			return nil, err
+		}
+
+		err = s.cartRepo.SaveCart(cart)
+		if err == nil {
+			return &CartUpdateResult{Cart: cart, Conflict: conflict}, nil
+		}
+		if !errors.Is(err, domain.ErrCartVersionConflict) || attempt == maxCartSaveAttempts {
+			s.logger.Error("failed to save cart to Redis",
+				zap.String("user_id", userID),
+				zap.Int("attempt", attempt),
+				zap.Error(err),
+			)
+			return nil, fmt.Errorf("failed to save cart: %w", err)
+		}
+
+		conflict = true
+		s.logger.Info("cart changed concurrently, merging",
+			zap.String("user_id", userID),
+			zap.Int("attempt", attempt),
+		)
+	}
+}
+
+// prepareResult enriches and totals a written cart for the response
+func (s *CartService) prepareResult(ctx context.Context, result *CartUpdateResult) *CartUpdateResult {
+	if err := s.enrichCartWithProductData(ctx, result.Cart); err != nil {
+		s.logger.Warn("failed to enrich cart with product data",
+			zap.String("user_id", result.Cart.UserID),
+			zap.Error(err),
+		)
+	}
+	result.Cart.CalculateTotals()
+	return result
+}
+
 // AddToCart adds a product item (SKU) to cart
-func (s *CartService) AddToCart(ctx context.Context, userID string, productItemID uint, quantity int) error {
+// expectedVersion is the cart version the client last saw (0 = unknown)
+func (s *CartService) AddToCart(ctx context.Context, userID string, productItemID uint, quantity int, expectedVersion int) (*CartUpdateResult, error) {
 	if userID == "" {
-		return errors.New("user_id is required")
+		return nil, domain.Validation("user_id is required")
 	}
 
 	if productItemID == 0 {
-		return domain.ErrInvalidProductItem
+		return nil, domain.ErrInvalidProductItem
 	}
 
 	if quantity <= 0 {
-		return domain.ErrInvalidQuantity
+		return nil, domain.ErrInvalidQuantity
 	}
 
-	if quantity > 999 {
-		return domain.ErrQuantityExceedsLimit
+	if quantity > s.maxItemQuantity() {
+		return nil, domain.ErrQuantityExceedsLimit
 	}
 
-	// 4. Get cart from Redis
-	cart, err := s.cartRepo.GetCart(userID)
+	// Load the SKU (snapshot + purchase limit); nil if Product Service is unavailable
+	productItem, err := s.lookupProductItem(ctx, productItemID)
 	if err != nil {
-		return fmt.Errorf("failed to get cart: %w", err)
+		return nil, err
 	}
 
-	// 5. Check if item already exists
-	existingItem := cart.FindItemByProductItemID(productItemID)
+	var addedProductID uint
+	result, err := s.updateCart(userID, expectedVersion, func(cart *domain.ShoppingCart) error {
+		addedProductID = 0
 
-	if existingItem != nil {
-		// Update quantity
-		newQuantity := existingItem.Quantity + quantity
+		// Check if item already exists
+		existingItem := cart.FindItemByProductItemID(productItemID)
 
-		if newQuantity > 999 {
-			return domain.ErrQuantityExceedsLimit
-		}
+		if existingItem != nil {
+			// Update quantity
+			newQuantity := existingItem.Quantity + quantity
+
+			if newQuantity > s.maxItemQuantity() {
+				return domain.ErrQuantityExceedsLimit
+			}
+
+			if err := checkPurchaseLimit(cart, productItem, productItemID, newQuantity); err != nil {
+				return err
+			}
 
-		existingItem.Quantity = newQuantity
+			existingItem.Quantity = newQuantity
+			return nil
+		}
 
-	} else {
-		// Add new item (only store minimal data in Redis)
+		// Add new item (minimal data + name/price snapshot in Redis)
 		newItem := &domain.CartItem{
 			ProductItemID: productItemID,
 			Quantity:      quantity,
@@ -139,166 +241,261 @@ func (s *CartService) AddToCart(ctx context.Context, userID string, productItemI
 			return err
 		}
 
+		if err := checkPurchaseLimit(cart, productItem, productItemID, quantity); err != nil {
+			return err
+		}
+
+		if productItem != nil {
+			newItem.ProductID = productItem.ProductID
+			newItem.SnapshotName = productItem.ProductName
+			newItem.SnapshotPrice = productItem.Price
+		}
+
 		cart.Items = append(cart.Items, newItem)
+		addedProductID = newItem.ProductID
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// 6. Save cart to Redis
-	if err := s.cartRepo.SaveCart(cart); err != nil {
-		s.logger.Error("failed to save cart to Redis",
-			zap.String("user_id", userID),
-			zap.Error(err),
-		)
-		return fmt.Errorf("failed to save cart: %w", err)
+	// Index the product so product_updated events can find this cart
+	if addedProductID != 0 {
+		if err := s.cartRepo.IndexProduct(addedProductID, userID); err != nil {
+			s.logger.Warn("failed to index cart product",
+				zap.String("user_id", userID),
+				zap.Uint("product_id", addedProductID),
+				zap.Error(err),
+			)
+		}
 	}
 
 	s.logger.Info("item added to cart",
 		zap.String("user_id", userID),
 		zap.Uint("product_item_id", productItemID),
 		zap.Int("quantity", quantity),
+		zap.Bool("conflict", result.Conflict),
 	)
 
-	return nil
+	return s.prepareResult(ctx, result), nil
 }
 
 // UpdateItemQuantity updates quantity of a cart item
-func (s *CartService) UpdateItemQuantity(ctx context.Context, userID string, productItemID uint, quantity int) error {
+func (s *CartService) UpdateItemQuantity(ctx context.Context, userID string, productItemID uint, quantity int, expectedVersion int) (*CartUpdateResult, error) {
 	if userID == "" {
-		return errors.New("user_id is required")
+		return nil, domain.Validation("user_id is required")
 	}
 
 	// If quantity is 0, remove item
 	if quantity == 0 {
-		return s.RemoveFromCart(ctx, userID, productItemID)
+		return s.RemoveFromCart(ctx, userID, productItemID, expectedVersion)
 	}
 
 	if quantity < 0 {
-		return domain.ErrInvalidQuantity
+		return nil, domain.ErrInvalidQuantity
 	}
 
-	if quantity > 999 {
-		return domain.ErrQuantityExceedsLimit
+	if quantity > s.maxItemQuantity() {
+		return nil, domain.ErrQuantityExceedsLimit
 	}
 
-	// Get cart
-	cart, err := s.cartRepo.GetCart(userID)
-	if err != nil {
-		return fmt.Errorf("failed to get cart: %w", err)
-	}
+	// Loaded lazily: only increases are checked against the purchase limit
+	var productItem *ProductItemDTO
+	loaded := false
 
-	// Find item
-	item := cart.FindItemByProductItemID(productItemID)
-	if item == nil {
-		return domain.ErrCartItemNotFound
-	}
+	result, err := s.updateCart(userID, expectedVersion, func(cart *domain.ShoppingCart) error {
+		// Find item
+		item := cart.FindItemByProductItemID(productItemID)
+		if item == nil {
+			return domain.ErrCartItemNotFound
+		}
 
-	// Update quantity
-	item.Quantity = quantity
+		// Purchase limit only blocks increases (a lowered limit must not trap the buyer)
+		if quantity > item.Quantity {
+			if !loaded {
+				var err error
+				if productItem, err = s.lookupProductItem(ctx, productItemID); err != nil {
+					return err
+				}
+				loaded = true
+			}
+			if err := checkPurchaseLimit(cart, productItem, productItemID, quantity); err != nil {
+				return err
+			}
+		}
 
-	// Save cart
-	if err := s.cartRepo.SaveCart(cart); err != nil {
-		return fmt.Errorf("failed to save cart: %w", err)
+		// Update quantity
+		item.Quantity = quantity
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	s.logger.Info("cart item quantity updated",
 		zap.String("user_id", userID),
 		zap.Uint("product_item_id", productItemID),
 		zap.Int("new_quantity", quantity),
+		zap.Bool("conflict", result.Conflict),
 	)
 
-	return nil
+	return s.prepareResult(ctx, result), nil
 }
 
 // RemoveFromCart removes an item from cart
-func (s *CartService) RemoveFromCart(ctx context.Context, userID string, productItemID uint) error {
+func (s *CartService) RemoveFromCart(ctx context.Context, userID string, productItemID uint, expectedVersion int) (*CartUpdateResult, error) {
 	if userID == "" {
-		return errors.New("user_id is required")
-	}
-
-	cart, err := s.cartRepo.GetCart(userID)
-	if err != nil {
-		return fmt.Errorf("failed to get cart: %w", err)
+		return nil, domain.Validation("user_id is required")
 	}
 
-	// Find and remove item
-	newItems := make([]*domain.CartItem, 0, len(cart.Items))
-	found := false
+	result, err := s.updateCart(userID, expectedVersion, func(cart *domain.ShoppingCart) error {
+		// Find and remove item
+		newItems := make([]*domain.CartItem, 0, len(cart.Items))
+		found := false
 
-	for _, item := range cart.Items {
-		if item.ProductItemID == productItemID {
-			found = true
-			continue
+		for _, item := range cart.Items {
+			if item.ProductItemID == productItemID {
+				found = true
+				continue
+			}
+			newItems = append(newItems, item)
 		}
-		newItems = append(newItems, item)
-	}
-
-	if !found {
-		return domain.ErrCartItemNotFound
-	}
 
-	cart.Items = newItems
+		if !found {
+			return domain.ErrCartItemNotFound
+		}
 
-	if err := s.cartRepo.SaveCart(cart); err != nil {
-		return fmt.Errorf("failed to save cart: %w", err)
+		cart.Items = newItems
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	s.logger.Info("item removed from cart",
 		zap.String("user_id", userID),
 		zap.Uint("product_item_id", productItemID),
+		zap.Bool("conflict", result.Conflict),
 	)
 
-	return nil
+	return s.prepareResult(ctx, result), nil
 }
 
 // ClearCart removes all items from cart
-func (s *CartService) ClearCart(ctx context.Context, userID string) error {
+func (s *CartService) ClearCart(ctx context.Context, userID string, expectedVersion int) (*CartUpdateResult, error) {
 	if userID == "" {
-		return errors.New("user_id is required")
+		return nil, domain.Validation("user_id is required")
 	}
 
-	cart, err := s.cartRepo.GetCart(userID)
+	result, err := s.updateCart(userID, expectedVersion, func(cart *domain.ShoppingCart) error {
+		cart.Items = make([]*domain.CartItem, 0)
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to get cart: %w", err)
+		return nil, fmt.Errorf("failed to clear cart: %w", err)
 	}
 
-	cart.Items = make([]*domain.CartItem, 0)
+	s.logger.Info("cart cleared", zap.String("user_id", userID))
 
-	if err := s.cartRepo.SaveCart(cart); err != nil {
-		return fmt.Errorf("failed to clear cart: %w", err)
-	}
+	result.Cart.CalculateTotals()
+	return result, nil
+}
 
-	s.logger.Info("cart cleared", zap.String("user_id", userID))
+// guestCartPrefix keeps guest carts (X-Guest-Id from the API Gateway) apart from user carts
+const guestCartPrefix = "guest:"
 
-	return nil
+// GuestCartOwner returns the cart owner key of a guest
+func GuestCartOwner(guestID string) string {
+	return guestCartPrefix + guestID
 }
 
-// ClearSelectedItems removes only selected items (after checkout)
-func (s *CartService) ClearSelectedItems(ctx context.Context, userID string) error {
-	if userID == "" {
-		return errors.New("user_id is required")
+// MergeGuestCart moves the cart a guest filled before login into the user's cart
+// Quantities of SKUs in both carts are added up (capped at the per-line limit;
+// purchase limits are re-checked at checkout) and the guest cart is deleted,
+// so merging again is a no-op. Returns the number of merged lines
+func (s *CartService) MergeGuestCart(ctx context.Context, userID, guestID string) (int, error) {
+	if userID == "" || guestID == "" {
+		return 0, nil
 	}
 
-	cart, err := s.cartRepo.GetCart(userID)
+	guestOwner := GuestCartOwner(guestID)
+	guestCart, err := s.cartRepo.GetCart(guestOwner)
 	if err != nil {
-		return fmt.Errorf("failed to get cart: %w", err)
+		return 0, fmt.Errorf("failed to get guest cart: %w", err)
+	}
+	if len(guestCart.Items) == 0 {
+		return 0, nil
 	}
 
-	// Keep only unselected items
-	unselectedItems := make([]*domain.CartItem, 0, len(cart.Items))
-	for _, item := range cart.Items {
-		if !item.IsSelected {
-			unselectedItems = append(unselectedItems, item)
+	maxQuantity := s.maxItemQuantity()
+	_, err = s.updateCart(userID, 0, func(cart *domain.ShoppingCart) error {
+		for _, guestItem := range guestCart.Items {
+			if existing := cart.FindItemByProductItemID(guestItem.ProductItemID); existing != nil {
+				existing.Quantity = min(existing.Quantity+guestItem.Quantity, maxQuantity)
+				continue
+			}
+			item := *guestItem
+			cart.Items = append(cart.Items, &item)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to merge guest cart: %w", err)
+	}
+
+	// Index the merged products so product_updated events find the user's cart
+	for _, item := range guestCart.Items {
+		if item.ProductID == 0 {
+			continue
+		}
+		if err := s.cartRepo.IndexProduct(item.ProductID, userID); err != nil {
+			s.logger.Warn("failed to index cart product",
+				zap.String("user_id", userID),
+				zap.Uint("product_id", item.ProductID),
+				zap.Error(err),
+			)
 		}
 	}
 
-	cart.Items = unselectedItems
+	if err := s.cartRepo.DeleteCart(guestOwner); err != nil {
+		s.logger.Warn("failed to delete merged guest cart", zap.String("guest_id", guestID), zap.Error(err))
+	}
+
+	s.logger.Info("guest cart merged",
+		zap.String("user_id", userID),
+		zap.String("guest_id", guestID),
+		zap.Int("items", len(guestCart.Items)),
+	)
+	return len(guestCart.Items), nil
+}
+
+// ClearSelectedItems removes only selected items (after checkout)
+func (s *CartService) ClearSelectedItems(ctx context.Context, userID string) error {
+	if userID == "" {
+		return domain.Validation("user_id is required")
+	}
+
+	remaining := 0
+	_, err := s.updateCart(userID, 0, func(cart *domain.ShoppingCart) error {
+		// Keep only unselected items
+		unselectedItems := make([]*domain.CartItem, 0, len(cart.Items))
+		for _, item := range cart.Items {
+			if !item.IsSelected {
+				unselectedItems = append(unselectedItems, item)
+			}
+		}
 
-	if err := s.cartRepo.SaveCart(cart); err != nil {
+		cart.Items = unselectedItems
+		remaining = len(unselectedItems)
+		return nil
+	})
+	if err != nil {
 		return fmt.Errorf("failed to clear selected items: %w", err)
 	}
 
 	s.logger.Info("selected items cleared",
 		zap.String("user_id", userID),
-		zap.Int("remaining_items", len(unselectedItems)),
+		zap.Int("remaining_items", remaining),
 	)
 
 	return nil
@@ -307,29 +504,28 @@ func (s *CartService) ClearSelectedItems(ctx context.Context, userID string) err
 // ToggleItemSelection toggles selection state of an item
 func (s *CartService) ToggleItemSelection(ctx context.Context, userID string, productItemID uint) error {
 	if userID == "" {
-		return errors.New("user_id is required")
+		return domain.Validation("user_id is required")
 	}
 
-	cart, err := s.cartRepo.GetCart(userID)
-	if err != nil {
-		return fmt.Errorf("failed to get cart: %w", err)
-	}
-
-	item := cart.FindItemByProductItemID(productItemID)
-	if item == nil {
-		return domain.ErrCartItemNotFound
-	}
-
-	item.IsSelected = !item.IsSelected
+	selected := false
+	_, err := s.updateCart(userID, 0, func(cart *domain.ShoppingCart) error {
+		item := cart.FindItemByProductItemID(productItemID)
+		if item == nil {
+			return domain.ErrCartItemNotFound
+		}
 
-	if err := s.cartRepo.SaveCart(cart); err != nil {
-		return fmt.Errorf("failed to save cart: %w", err)
+		item.IsSelected = !item.IsSelected
+		selected = item.IsSelected
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	s.logger.Info("item selection toggled",
 		zap.String("user_id", userID),
 		zap.Uint("product_item_id", productItemID),
-		zap.Bool("is_selected", item.IsSelected),
+		zap.Bool("is_selected", selected),
 	)
 
 	return nil
@@ -338,20 +534,17 @@ func (s *CartService) ToggleItemSelection(ctx context.Context, userID string, pr
 // SelectAllItems selects/deselects all items
 func (s *CartService) SelectAllItems(ctx context.Context, userID string, selected bool) error {
 	if userID == "" {
-		return errors.New("user_id is required")
+		return domain.Validation("user_id is required")
 	}
 
-	cart, err := s.cartRepo.GetCart(userID)
+	_, err := s.updateCart(userID, 0, func(cart *domain.ShoppingCart) error {
+		for i := range cart.Items {
+			cart.Items[i].IsSelected = selected
+		}
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to get cart: %w", err)
-	}
-
-	for i := range cart.Items {
-		cart.Items[i].IsSelected = selected
-	}
-
-	if err := s.cartRepo.SaveCart(cart); err != nil {
-		return fmt.Errorf("failed to save cart: %w", err)
+		return err
 	}
 
 	s.logger.Info("all items selection updated",
@@ -365,23 +558,20 @@ func (s *CartService) SelectAllItems(ctx context.Context, userID string, selecte
 // SelectShopItems selects/deselects all items from a specific shop
 func (s *CartService) SelectShopItems(ctx context.Context, userID string, shopID uint, selected bool) error {
 	if userID == "" {
-		return errors.New("user_id is required")
-	}
-
-	cart, err := s.cartRepo.GetCart(userID)
-	if err != nil {
-		return fmt.Errorf("failed to get cart: %w", err)
+		return domain.Validation("user_id is required")
 	}
 
-	// Update selection for items from this shop
-	for i := range cart.Items {
-		if cart.Items[i].ShopID == shopID {
-			cart.Items[i].IsSelected = selected
+	_, err := s.updateCart(userID, 0, func(cart *domain.ShoppingCart) error {
+		// Update selection for items from this shop
+		for i := range cart.Items {
+			if cart.Items[i].ShopID == shopID {
+				cart.Items[i].IsSelected = selected
+			}
 		}
-	}
-
-	if err := s.cartRepo.SaveCart(cart); err != nil {
-		return fmt.Errorf("failed to save cart: %w", err)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	s.logger.Info("shop items selection updated",
@@ -396,7 +586,7 @@ func (s *CartService) SelectShopItems(ctx context.Context, userID string, shopID
 // ValidateCart validates all items in cart
 func (s *CartService) ValidateCart(ctx context.Context, userID string) error {
 	if userID == "" {
-		return errors.New("user_id is required")
+		return domain.Validation("user_id is required")
 	}
 
 	cart, err := s.GetCart(ctx, userID)
@@ -412,7 +602,7 @@ func (s *CartService) ValidateCart(ctx context.Context, userID string) error {
 }
 
 // enrichCartWithProductData fetches product details from Product Service
-func (s *CartService) enrichCartWithProductData(cart *domain.ShoppingCart) error {
+func (s *CartService) enrichCartWithProductData(ctx context.Context, cart *domain.ShoppingCart) error {
 	if len(cart.Items) == 0 {
 		return nil
 	}
@@ -424,7 +614,7 @@ func (s *CartService) enrichCartWithProductData(cart *domain.ShoppingCart) error
 	}
 
 	// Batch fetch from Product Service
-	productItems, err := s.productClient.GetProductItems(productItemIDs)
+	productItems, err := s.productClient.GetProductItems(ctx, productItemIDs)
 	if err != nil {
 		s.logger.Error("failed to fetch product items from Product Service",
 			zap.Uints("product_item_ids", productItemIDs),
@@ -446,6 +636,7 @@ func (s *CartService) enrichCartWithProductData(cart *domain.ShoppingCart) error
 			item.SKUCode = productItem.SKUCode
 			item.Price = productItem.Price
 			item.ImageURL = productItem.ImageURL
+			item.MarkPriceChange(productItem.Price) // display only, the consumer persists it
 			s.logger.Debug("enriched cart item",
 				zap.Uint("product_item_id", item.ProductItemID),
 				zap.Uint("shop_id", item.ShopID),
@@ -461,6 +652,176 @@ func (s *CartService) enrichCartWithProductData(cart *domain.ShoppingCart) error
 	return nil
 }
 
+// lookupProductItem loads a SKU for add/update checks. If Product Service is
+// unavailable it returns nil (no snapshot, no purchase limit check) so the cart keeps
+// working; checkout re-validates everything
+func (s *CartService) lookupProductItem(ctx context.Context, productItemID uint) (*ProductItemDTO, error) {
+	productItem, err := s.productClient.GetProductItem(ctx, productItemID)
+	if err != nil {
+		s.logger.Warn("failed to load product item, skipping snapshot and purchase limit",
+			zap.Uint("product_item_id", productItemID),
+			zap.Error(err),
+		)
+		return nil, nil
+	}
+	if productItem == nil {
+		return nil, domain.ErrInvalidProductItem
+	}
+	return productItem, nil
+}
+
+// checkPurchaseLimit checks the product's max purchase quantity against the cart
+// total of the product (all its SKUs) with productItemID set to quantity
+func checkPurchaseLimit(cart *domain.ShoppingCart, productItem *ProductItemDTO, productItemID uint, quantity int) error {
+	if productItem == nil || productItem.MaxPurchaseQuantity <= 0 {
+		return nil
+	}
+
+	total := quantity
+	for _, item := range cart.Items {
+		if item.ProductItemID != productItemID && item.ProductID == productItem.ProductID {
+			total += item.Quantity
+		}
+	}
+
+	if total > productItem.MaxPurchaseQuantity {
+		return &domain.PurchaseLimitError{
+			ProductID:   productItem.ProductID,
+			ProductName: productItem.ProductName,
+			Limit:       productItem.MaxPurchaseQuantity,
+			Requested:   total,
+		}
+	}
+	return nil
+}
+
+// HandleProductUpdated reacts to a product_updated event: every cart holding
+// items of the product gets its snapshot name refreshed and lines whose price
+// differs from the snapshot flagged (price_changed with old/new price)
+func (s *CartService) HandleProductUpdated(ctx context.Context, productID uint) error {
+	userIDs, err := s.cartRepo.GetUsersByProduct(productID)
+	if err != nil {
+		return err
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	var failed int
+	for _, userID := range userIDs {
+		if err := s.refreshCartProduct(ctx, userID, productID); err != nil {
+			failed++
+			s.logger.Error("failed to refresh cart after product update",
+				zap.String("user_id", userID),
+				zap.Uint("product_id", productID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	s.logger.Info("carts refreshed after product update",
+		zap.Uint("product_id", productID),
+		zap.Int("carts", len(userIDs)),
+		zap.Int("failed", failed),
+	)
+
+	if failed > 0 {
+		return fmt.Errorf("failed to refresh %d of %d carts", failed, len(userIDs))
+	}
+	return nil
+}
+
+// refreshCartProduct re-checks one cart's lines of a product against Product Service
+func (s *CartService) refreshCartProduct(ctx context.Context, userID string, productID uint) error {
+	cart, err := s.cartRepo.GetCart(userID)
+	if err != nil {
+		return err
+	}
+
+	productItemIDs := make([]uint, 0)
+	for _, item := range cart.Items {
+		if item.ProductID == productID {
+			productItemIDs = append(productItemIDs, item.ProductItemID)
+		}
+	}
+
+	// Stale index entry (item removed or cart checked out)
+	if len(productItemIDs) == 0 {
+		return s.cartRepo.UnindexProduct(productID, userID)
+	}
+
+	productItems, err := s.productClient.GetProductItems(ctx, productItemIDs)
+	if err != nil {
+		return fmt.Errorf("failed to fetch product items: %w", err)
+	}
+
+	// Applied to the latest cart, so a concurrent buyer write is not lost
+	_, err = s.updateCart(userID, 0, func(cart *domain.ShoppingCart) error {
+		changed := false
+		for _, item := range cart.Items {
+			if item.ProductID != productID {
+				continue
+			}
+			productItem, ok := productItems[item.ProductItemID]
+			if !ok {
+				continue // SKU gone, checkout validation reports it
+			}
+			if productItem.ProductName != "" && item.SnapshotName != productItem.ProductName {
+				item.SnapshotName = productItem.ProductName
+				changed = true
+			}
+			if item.MarkPriceChange(productItem.Price) {
+				changed = true
+				s.logger.Info("cart item price changed",
+					zap.String("user_id", userID),
+					zap.Uint("product_item_id", item.ProductItemID),
+					zap.Float64("old_price", item.OldPrice),
+					zap.Float64("new_price", item.NewPrice),
+				)
+			}
+		}
+		if !changed {
+			return errCartUnchanged
+		}
+		return nil
+	})
+	return err
+}
+
+// AcknowledgePriceChanges accepts the new prices of all flagged items
+// (the buyer has seen them), clearing price_changed
+func (s *CartService) AcknowledgePriceChanges(ctx context.Context, userID string, expectedVersion int) (*CartUpdateResult, error) {
+	if userID == "" {
+		return nil, domain.Validation("user_id is required")
+	}
+
+	acknowledged := 0
+	result, err := s.updateCart(userID, expectedVersion, func(cart *domain.ShoppingCart) error {
+		acknowledged = 0
+		for _, item := range cart.Items {
+			if item.PriceChanged {
+				item.AcceptPriceChange()
+				acknowledged++
+			}
+		}
+		if acknowledged == 0 {
+			return errCartUnchanged
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if acknowledged > 0 {
+		s.logger.Info("cart price changes acknowledged",
+			zap.String("user_id", userID),
+			zap.Int("items", acknowledged),
+		)
+	}
+	return s.prepareResult(ctx, result), nil
+}
+
 // validateSelectedItems validates all selected items in the cart
 func (s *CartService) validateSelectedItems(cart *domain.ShoppingCart) error {
 	// Collect all product item IDs from selected items
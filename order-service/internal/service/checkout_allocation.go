@@ -0,0 +1,137 @@
+package service
+
+import "order-service/pkg/money"
+
+// checkoutCurrency is the currency of prices, fees and vouchers
+const checkoutCurrency = money.VND
+
+// flatShippingFee is the MVP shipping fee per shop_order
+// TODO: Call ShippingService for accurate per-shop shipping fee
+var flatShippingFee = money.New(30000, checkoutCurrency)
+
+// ShopDiscount is the discount of a shop's vouchers applied at checkout: it only
+// reduces the order of that shop (see VoucherService.ResolveCheckout)
+type ShopDiscount struct {
+	ShopID           uint
+	VoucherDiscount  money.Money
+	ShippingDiscount money.Money
+}
+
+// shopAllocation holds the amounts of one shop_order in a checkout
+type shopAllocation struct {
+	ShopID           uint
+	Subtotal         money.Money
+	ShippingFee      money.Money
+	VoucherDiscount  money.Money
+	ShippingDiscount money.Money
+}
+
+// finalAmount returns what the buyer pays for the shop_order: subtotal plus shipping
+// fee minus both discounts, never below zero
+func (a *shopAllocation) finalAmount() (money.Money, error) {
+	amount, err := a.Subtotal.Add(a.ShippingFee)
+	if err != nil {
+		return money.Money{}, err
+	}
+	if amount, err = amount.Sub(a.ShippingDiscount); err != nil {
+		return money.Money{}, err
+	}
+	if amount, err = amount.Sub(a.VoucherDiscount); err != nil {
+		return money.Money{}, err
+	}
+	return amount.NonNegative(), nil
+}
+
+// allocateCheckoutDiscounts fills VoucherDiscount and ShippingDiscount of each shop:
+//   - shop vouchers apply to their own shop only, capped at its subtotal / shipping fee
+//   - the platform voucher is split proportionally to each shop's subtotal left after
+//     its shop voucher
+//   - the platform shipping discount is split proportionally to each shop's shipping
+//     fee left after its shop shipping discount
+//
+// Amounts are whole VND; platform shares are rounded with the largest remainder method
+// so they always add up to the (capped) platform amount. An error means an amount is
+// in another currency or overflows
+func allocateCheckoutDiscounts(shops []*shopAllocation, shopDiscounts []ShopDiscount, platformVoucher, platformShipping money.Money) error {
+	byShop := make(map[uint]*shopAllocation, len(shops))
+	for _, shop := range shops {
+		shop.VoucherDiscount = money.Zero(checkoutCurrency)
+		shop.ShippingDiscount = money.Zero(checkoutCurrency)
+		byShop[shop.ShopID] = shop
+	}
+
+	for _, discount := range shopDiscounts {
+		shop, ok := byShop[discount.ShopID]
+		if !ok {
+			continue // voucher of a shop that is not in this checkout
+		}
+		var err error
+		if shop.VoucherDiscount, err = addCapped(shop.VoucherDiscount, discount.VoucherDiscount.NonNegative(), shop.Subtotal); err != nil {
+			return err
+		}
+		if shop.ShippingDiscount, err = addCapped(shop.ShippingDiscount, discount.ShippingDiscount.NonNegative(), shop.ShippingFee); err != nil {
+			return err
+		}
+	}
+
+	voucherWeights := make([]money.Money, len(shops))
+	shippingWeights := make([]money.Money, len(shops))
+	for i, shop := range shops {
+		var err error
+		if voucherWeights[i], err = shop.Subtotal.Sub(shop.VoucherDiscount); err != nil {
+			return err
+		}
+		if shippingWeights[i], err = shop.ShippingFee.Sub(shop.ShippingDiscount); err != nil {
+			return err
+		}
+	}
+
+	voucherShares, err := allocateProportionally(platformVoucher.NonNegative(), voucherWeights)
+	if err != nil {
+		return err
+	}
+	shippingShares, err := allocateProportionally(platformShipping.NonNegative(), shippingWeights)
+	if err != nil {
+		return err
+	}
+	for i := range shops {
+		if shops[i].VoucherDiscount, err = shops[i].VoucherDiscount.Add(voucherShares[i]); err != nil {
+			return err
+		}
+		if shops[i].ShippingDiscount, err = shops[i].ShippingDiscount.Add(shippingShares[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addCapped returns min(amount + extra, limit)
+func addCapped(amount, extra, limit money.Money) (money.Money, error) {
+	sum, err := amount.Add(extra)
+	if err != nil {
+		return money.Money{}, err
+	}
+	return sum.Min(limit)
+}
+
+// allocateProportionally splits an amount by weight without exceeding any weight
+// The total is capped at the sum of the positive weights, so no share can exceed its
+// weight (see money.Money.Allocate for the rounding)
+func allocateProportionally(total money.Money, weights []money.Money) ([]money.Money, error) {
+	capacity := money.Zero(checkoutCurrency)
+	units := make([]int64, len(weights))
+	for i, w := range weights {
+		if w.IsPositive() {
+			var err error
+			if capacity, err = capacity.Add(w); err != nil {
+				return nil, err
+			}
+			units[i] = w.Minor()
+		}
+	}
+	capped, err := total.Min(capacity)
+	if err != nil {
+		return nil, err
+	}
+	return capped.Allocate(units), nil
+}
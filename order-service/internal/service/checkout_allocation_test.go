@@ -0,0 +1,175 @@
+package service
+
+import (
+	"errors"
+	"math/rand"
+	"order-service/pkg/money"
+	"testing"
+)
+
+func vnd(amount int64) money.Money {
+	return money.New(amount, checkoutCurrency)
+}
+
+func vnds(amounts ...int64) []money.Money {
+	out := make([]money.Money, len(amounts))
+	for i, a := range amounts {
+		out[i] = vnd(a)
+	}
+	return out
+}
+
+func TestAllocateProportionally(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   int64
+		weights []int64
+		want    []int64
+	}{
+		{"even split, leftover to the earlier index", 100, []int64{100, 100, 100}, []int64{34, 33, 33}},
+		{"leftover to the largest remainder", 10, []int64{10, 20, 30}, []int64{2, 3, 5}},
+		{"two thirds rounded", 10000, []int64{200000, 100000}, []int64{6667, 3333}},
+		{"capped at the sum of the weights", 1000, []int64{300, 200}, []int64{300, 200}},
+		{"non-positive weights get nothing", 90, []int64{0, -5, 30, 60}, []int64{0, 0, 30, 60}},
+		{"no positive weight", 500, []int64{0, 0}, []int64{0, 0}},
+		{"nothing to split", 0, []int64{10, 20}, []int64{0, 0}},
+		{"no weights", 500, nil, []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := allocateProportionally(vnd(tt.total), vnds(tt.weights...))
+			if err != nil {
+				t.Fatalf("allocateProportionally: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d shares, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i].Minor() != tt.want[i] {
+					t.Errorf("share %d = %d, want %d (shares %v)", i, got[i].Minor(), tt.want[i], got)
+				}
+			}
+		})
+	}
+}
+
+// TestAllocateProportionallyInvariants checks random splits: the shares add up to the
+// capped total, no share exceeds its weight and each is within a unit of its exact share
+func TestAllocateProportionallyInvariants(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for run := 0; run < 2000; run++ {
+		weights := make([]int64, 1+rng.Intn(8))
+		capacity := int64(0)
+		for i := range weights {
+			weights[i] = rng.Int63n(2_000_000) - 200_000 // some non-positive
+			if weights[i] > 0 {
+				capacity += weights[i]
+			}
+		}
+		total := rng.Int63n(3_000_000)
+
+		shares, err := allocateProportionally(vnd(total), vnds(weights...))
+		if err != nil {
+			t.Fatalf("run %d: %v", run, err)
+		}
+
+		want := min(total, capacity)
+		sum := int64(0)
+		for i, share := range shares {
+			s := share.Minor()
+			sum += s
+			if weights[i] <= 0 {
+				if s != 0 {
+					t.Fatalf("run %d: weight %d got share %d", run, weights[i], s)
+				}
+				continue
+			}
+			if s < 0 || s > weights[i] {
+				t.Fatalf("run %d: share %d outside [0, weight %d]", run, s, weights[i])
+			}
+			exact := float64(want) * float64(weights[i]) / float64(capacity)
+			if float64(s) < exact-1 || float64(s) > exact+1 {
+				t.Fatalf("run %d: share %d is not within a unit of %.2f", run, s, exact)
+			}
+		}
+		if sum != want {
+			t.Fatalf("run %d: shares add up to %d, want %d (weights %v, total %d)", run, sum, want, weights, total)
+		}
+	}
+}
+
+func TestAllocateCheckoutDiscounts(t *testing.T) {
+	shops := []*shopAllocation{
+		{ShopID: 1, Subtotal: vnd(100000), ShippingFee: vnd(30000)},
+		{ShopID: 2, Subtotal: vnd(50000), ShippingFee: vnd(0)}, // digital only
+	}
+	shopDiscounts := []ShopDiscount{
+		{ShopID: 1, VoucherDiscount: vnd(120000), ShippingDiscount: vnd(-5000)}, // capped, negative ignored
+		{ShopID: 3, VoucherDiscount: vnd(10000), ShippingDiscount: vnd(10000)},  // not in the checkout
+	}
+
+	if err := allocateCheckoutDiscounts(shops, shopDiscounts, vnd(30000), vnd(40000)); err != nil {
+		t.Fatalf("allocateCheckoutDiscounts: %v", err)
+	}
+
+	want := []struct{ voucher, shipping int64 }{
+		{100000, 30000}, // whole subtotal by its shop voucher, platform shipping capped at its fee
+		{30000, 0},      // all of the platform voucher: shop 1 has nothing left to discount
+	}
+	for i, shop := range shops {
+		if shop.VoucherDiscount.Minor() != want[i].voucher || shop.ShippingDiscount.Minor() != want[i].shipping {
+			t.Errorf("shop %d discounts = %v / %v, want %d / %d",
+				shop.ShopID, shop.VoucherDiscount, shop.ShippingDiscount, want[i].voucher, want[i].shipping)
+		}
+	}
+}
+
+func TestAllocateCheckoutDiscountsPlatformSplit(t *testing.T) {
+	shops := []*shopAllocation{
+		{ShopID: 1, Subtotal: vnd(200000), ShippingFee: vnd(30000)},
+		{ShopID: 2, Subtotal: vnd(150000), ShippingFee: vnd(30000)},
+	}
+	shopDiscounts := []ShopDiscount{
+		{ShopID: 2, VoucherDiscount: vnd(50000)},
+	}
+
+	// Platform voucher split 2:1 on the subtotals left after shop vouchers
+	if err := allocateCheckoutDiscounts(shops, shopDiscounts, vnd(10000), vnd(15001)); err != nil {
+		t.Fatalf("allocateCheckoutDiscounts: %v", err)
+	}
+
+	if got := shops[0].VoucherDiscount.Minor(); got != 6667 {
+		t.Errorf("shop 1 voucher discount = %d, want 6667", got)
+	}
+	if got := shops[1].VoucherDiscount.Minor(); got != 50000+3333 {
+		t.Errorf("shop 2 voucher discount = %d, want %d", got, 50000+3333)
+	}
+	if got := shops[0].ShippingDiscount.Minor() + shops[1].ShippingDiscount.Minor(); got != 15001 {
+		t.Errorf("shipping discounts add up to %d, want 15001", got)
+	}
+}
+
+func TestAllocateCheckoutDiscountsWithoutVouchers(t *testing.T) {
+	shops := []*shopAllocation{
+		{ShopID: 1, Subtotal: vnd(100000), ShippingFee: vnd(30000), VoucherDiscount: vnd(999)},
+	}
+
+	if err := allocateCheckoutDiscounts(shops, nil, money.Zero(checkoutCurrency), money.Zero(checkoutCurrency)); err != nil {
+		t.Fatalf("allocateCheckoutDiscounts: %v", err)
+	}
+
+	if !shops[0].VoucherDiscount.IsZero() || !shops[0].ShippingDiscount.IsZero() {
+		t.Errorf("discounts = %v / %v, want none", shops[0].VoucherDiscount, shops[0].ShippingDiscount)
+	}
+}
+
+func TestAllocateCheckoutDiscountsCurrencyMismatch(t *testing.T) {
+	shops := []*shopAllocation{
+		{ShopID: 1, Subtotal: vnd(100000), ShippingFee: vnd(30000)},
+	}
+
+	err := allocateCheckoutDiscounts(shops, nil, money.New(500, money.USD), money.Zero(checkoutCurrency))
+	if !errors.Is(err, money.ErrCurrencyMismatch) {
+		t.Errorf("err = %v, want ErrCurrencyMismatch", err)
+	}
+}
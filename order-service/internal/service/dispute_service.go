@@ -0,0 +1,411 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"order-service/internal/domain"
+	"order-service/internal/repository/postgres"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// DisputeShopClient resolves shop owners from Identity Service (seller access to disputes)
+type DisputeShopClient interface {
+	GetShopOwner(ctx context.Context, shopID uint) (uint, error)
+}
+
+// DisputeService runs the dispute resolution center: buyers open disputes on their orders,
+// buyer and seller add evidence, and an admin decides. A decision for the buyer requests
+// the refund (order_refund_requested event) and deducts the seller's share from the next payout
+type DisputeService struct {
+	disputeRepo    domain.DisputeRepository
+	orderRepo      *postgres.OrderRepository
+	shops          DisputeShopClient
+	eventPublisher domain.OrderEventPublisher
+	logger         *zap.Logger
+}
+
+// NewDisputeService creates a new dispute service
+func NewDisputeService(
+	disputeRepo domain.DisputeRepository,
+	orderRepo *postgres.OrderRepository,
+	shops DisputeShopClient,
+	eventPublisher domain.OrderEventPublisher,
+	logger *zap.Logger,
+) *DisputeService {
+	return &DisputeService{
+		disputeRepo:    disputeRepo,
+		orderRepo:      orderRepo,
+		shops:          shops,
+		eventPublisher: eventPublisher,
+		logger:         logger,
+	}
+}
+
+// DisputeActor is the user acting on a dispute (X-User-Id / X-User-Role from API Gateway)
+type DisputeActor struct {
+	UserID uint
+	Role   string
+}
+
+// IsAdmin reports whether the actor is an admin
+func (a DisputeActor) IsAdmin() bool {
+	return a.Role == "ADMIN"
+}
+
+// EvidenceInput is one evidence item: an uploaded file URL, a statement, or both
+type EvidenceInput struct {
+	URL  string `json:"url,omitempty"`
+	Note string `json:"note,omitempty"`
+}
+
+// OpenDisputeRequest represents the request to open a dispute
+type OpenDisputeRequest struct {
+	OrderID     uint            `json:"order_id" binding:"required"`
+	Reason      string          `json:"reason" binding:"required"`
+	Description string          `json:"description"`
+	Evidence    []EvidenceInput `json:"evidence,omitempty"`
+}
+
+// ResolveDisputeRequest is the admin decision
+type ResolveDisputeRequest struct {
+	Outcome      string  `json:"outcome" binding:"required"` // buyer or seller
+	RefundAmount float64 `json:"refund_amount,omitempty"`    // Buyer outcome only; 0 = full order amount
+	Note         string  `json:"note,omitempty"`
+}
+
+// disputableOrderStatuses are the order statuses a dispute can be opened in
+var disputableOrderStatuses = []domain.OrderStatus{
+	domain.OrderStatusPaid,
+	domain.OrderStatusConfirmed,
+	domain.OrderStatusProcessing,
+	domain.OrderStatusShipped,
+	domain.OrderStatusDelivered,
+}
+
+// OpenDispute opens a dispute on one of the buyer's orders
+func (s *DisputeService) OpenDispute(ctx context.Context, buyerID uint, req *OpenDisputeRequest) (*domain.Dispute, error) {
+	if !slices.Contains(domain.DisputeReasons, req.Reason) {
+		return nil, domain.ErrInvalidDisputeReason
+	}
+	if len(req.Evidence) > domain.MaxDisputeEvidence {
+		return nil, domain.ErrDisputeEvidenceLimit
+	}
+
+	order, err := s.orderRepo.GetByID(req.OrderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrDisputeOrderNotFound
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order.UserID != buyerID {
+		return nil, domain.ErrDisputeOrderNotFound
+	}
+	if !slices.Contains(disputableOrderStatuses, order.Status) {
+		return nil, domain.ErrDisputeNotAllowed
+	}
+
+	dispute := &domain.Dispute{
+		OrderID:     order.ID,
+		OrderNumber: order.OrderNumber,
+		BuyerID:     buyerID,
+		ShopID:      order.ShopID,
+		Reason:      req.Reason,
+		Description: strings.TrimSpace(req.Description),
+		Status:      domain.DisputeStatusOpen,
+	}
+	for _, input := range req.Evidence {
+		evidence, err := newEvidence(input, buyerID, domain.DisputePartyBuyer)
+		if err != nil {
+			return nil, err
+		}
+		dispute.Evidence = append(dispute.Evidence, evidence)
+	}
+
+	if err := s.disputeRepo.Create(ctx, dispute); err != nil {
+		if errors.Is(err, domain.ErrDisputeExists) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create dispute: %w", err)
+	}
+
+	s.logger.Info("dispute opened",
+		zap.Uint("dispute_id", dispute.ID),
+		zap.Uint("order_id", order.ID),
+		zap.Uint("shop_id", order.ShopID),
+		zap.String("reason", dispute.Reason),
+	)
+	return dispute, nil
+}
+
+// GetDispute returns a dispute with its evidence to its buyer, the shop owner or an admin
+func (s *DisputeService) GetDispute(ctx context.Context, actor DisputeActor, id uint) (*domain.Dispute, error) {
+	dispute, err := s.disputeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.party(ctx, actor, dispute); err != nil {
+		return nil, err
+	}
+	return dispute, nil
+}
+
+// ListForBuyer lists the buyer's disputes
+func (s *DisputeService) ListForBuyer(ctx context.Context, buyerID uint, status domain.DisputeStatus, page, limit int) ([]*domain.Dispute, int64, error) {
+	page, limit = disputePage(page, limit)
+	return s.disputeRepo.List(ctx, domain.DisputeFilter{BuyerID: buyerID, Status: status}, page, limit)
+}
+
+// ListForShop lists the disputes against a shop; only its owner (or an admin) may see them
+func (s *DisputeService) ListForShop(ctx context.Context, actor DisputeActor, shopID uint, status domain.DisputeStatus, page, limit int) ([]*domain.Dispute, int64, error) {
+	if !actor.IsAdmin() {
+		ownerID, err := s.shops.GetShopOwner(ctx, shopID)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get shop: %w", err)
+		}
+		if ownerID != actor.UserID {
+			return nil, 0, domain.ErrDisputeNotFound
+		}
+	}
+	page, limit = disputePage(page, limit)
+	return s.disputeRepo.List(ctx, domain.DisputeFilter{ShopID: shopID, Status: status}, page, limit)
+}
+
+// ListAll lists disputes for the admin queue
+func (s *DisputeService) ListAll(ctx context.Context, filter domain.DisputeFilter, page, limit int) ([]*domain.Dispute, int64, error) {
+	page, limit = disputePage(page, limit)
+	return s.disputeRepo.List(ctx, filter, page, limit)
+}
+
+// AddEvidence adds evidence from the buyer, the shop owner or an admin while the dispute is not resolved
+func (s *DisputeService) AddEvidence(ctx context.Context, actor DisputeActor, id uint, input EvidenceInput) (*domain.DisputeEvidence, error) {
+	dispute, err := s.disputeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	party, err := s.party(ctx, actor, dispute)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.Status.IsResolved() {
+		return nil, domain.ErrInvalidDisputeStatus
+	}
+
+	evidence, err := newEvidence(input, actor.UserID, party)
+	if err != nil {
+		return nil, err
+	}
+	evidence.DisputeID = dispute.ID
+
+	count, err := s.disputeRepo.CountEvidence(ctx, dispute.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count evidence: %w", err)
+	}
+	if count >= domain.MaxDisputeEvidence {
+		return nil, domain.ErrDisputeEvidenceLimit
+	}
+
+	if err := s.disputeRepo.AddEvidence(ctx, evidence); err != nil {
+		return nil, fmt.Errorf("failed to add evidence: %w", err)
+	}
+	return evidence, nil
+}
+
+// StartReview moves an OPEN dispute to UNDER_REVIEW (admin)
+func (s *DisputeService) StartReview(ctx context.Context, adminID, id uint) (*domain.Dispute, error) {
+	dispute, err := s.disputeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.Status != domain.DisputeStatusOpen {
+		return nil, domain.ErrInvalidDisputeStatus
+	}
+
+	dispute.Status = domain.DisputeStatusUnderReview
+	dispute.ReviewedBy = adminID
+	ok, err := s.disputeRepo.UpdateStatus(ctx, dispute, domain.DisputeStatusOpen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update dispute: %w", err)
+	}
+	if !ok {
+		return nil, domain.ErrInvalidDisputeStatus
+	}
+	return dispute, nil
+}
+
+// Resolve decides an open or reviewed dispute (admin)
+// Buyer outcome: the refund (default: what the buyer paid net of earlier adjustments) is recorded
+// as a refund adjustment of the order and requested from payment-service via an
+// order_refund_requested event, and the seller's share of it (refund minus the platform fee
+// share) is deducted from the shop's next payout batch
+// Seller outcome: the dispute is closed without changes
+func (s *DisputeService) Resolve(ctx context.Context, adminID, id uint, req *ResolveDisputeRequest) (*domain.Dispute, error) {
+	if req.Outcome != domain.DisputePartyBuyer && req.Outcome != domain.DisputePartySeller {
+		return nil, domain.ErrInvalidDisputeOutcome
+	}
+
+	dispute, err := s.disputeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.Status.IsResolved() {
+		return nil, domain.ErrInvalidDisputeStatus
+	}
+
+	order, err := s.orderRepo.GetByID(dispute.OrderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	now := time.Now()
+	dispute.ResolutionNote = strings.TrimSpace(req.Note)
+	dispute.ResolvedBy = adminID
+	dispute.ResolvedAt = &now
+
+	var refund *domain.DisputeRefund
+	if req.Outcome == domain.DisputePartyBuyer {
+		history, err := s.orderRepo.ListAdjustments(order.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get order adjustments: %w", err)
+		}
+		totals := domain.DeriveOrderTotals(order, history)
+
+		amount := req.RefundAmount
+		if amount == 0 {
+			amount = totals.FinalAmount
+		}
+		if amount <= 0 || amount > totals.FinalAmount {
+			return nil, domain.ErrInvalidRefundAmount
+		}
+
+		dispute.Status = domain.DisputeStatusResolvedBuyer
+		dispute.RefundAmount = amount
+		sellerShare := sellerRefundShare(totals, amount)
+		reason := fmt.Sprintf("Dispute #%d refund (order %s)", dispute.ID, dispute.OrderNumber)
+		refund = &domain.DisputeRefund{
+			Order: order,
+			Adjustment: &domain.OrderAdjustment{
+				Type:               domain.OrderAdjustmentRefund,
+				FinalAmountDelta:   -amount,
+				PlatformFeeDelta:   -(amount - sellerShare),
+				EarningAmountDelta: -sellerShare,
+				Reason:             reason,
+				DisputeID:          &dispute.ID,
+				CreatedBy:          adminID,
+			},
+			Payout: &domain.PayoutAdjustment{
+				ShopID:    dispute.ShopID,
+				OrderID:   dispute.OrderID,
+				DisputeID: &dispute.ID,
+				Amount:    -sellerShare,
+				Reason:    reason,
+				Status:    domain.PayoutAdjustmentPending,
+			},
+		}
+	} else {
+		dispute.Status = domain.DisputeStatusResolvedSeller
+		dispute.RefundAmount = 0
+	}
+
+	ok, err := s.disputeRepo.Resolve(ctx, dispute, refund, domain.DisputeStatusOpen, domain.DisputeStatusUnderReview)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve dispute: %w", err)
+	}
+	if !ok {
+		return nil, domain.ErrInvalidDisputeStatus
+	}
+
+	s.logger.Info("dispute resolved",
+		zap.Uint("dispute_id", dispute.ID),
+		zap.Uint("order_id", dispute.OrderID),
+		zap.String("status", string(dispute.Status)),
+		zap.Float64("refund_amount", dispute.RefundAmount),
+		zap.Uint("resolved_by", adminID),
+	)
+
+	if dispute.Status == domain.DisputeStatusResolvedBuyer {
+		// The decision is committed; the publisher buffers the event if Kafka is down
+		event := domain.NewOrderEvent("order_refund_requested", order, domain.DisputeRefundMetadata{
+			DisputeID:    dispute.ID,
+			RefundAmount: dispute.RefundAmount,
+		})
+		if err := s.eventPublisher.PublishOrderEvent(event); err != nil {
+			s.logger.Error("failed to publish order_refund_requested event",
+				zap.Uint("dispute_id", dispute.ID),
+				zap.Uint("order_id", dispute.OrderID),
+				zap.Error(err),
+			)
+		}
+	}
+	return dispute, nil
+}
+
+// party returns which side the actor is on; ErrDisputeNotFound if the actor has no access
+func (s *DisputeService) party(ctx context.Context, actor DisputeActor, dispute *domain.Dispute) (string, error) {
+	switch {
+	case actor.IsAdmin():
+		return domain.DisputePartyAdmin, nil
+	case actor.UserID == dispute.BuyerID:
+		return domain.DisputePartyBuyer, nil
+	}
+
+	ownerID, err := s.shops.GetShopOwner(ctx, dispute.ShopID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get shop: %w", err)
+	}
+	if ownerID == 0 || ownerID != actor.UserID {
+		return "", domain.ErrDisputeNotFound
+	}
+	return domain.DisputePartySeller, nil
+}
+
+// newEvidence validates an evidence item (http(s) URL and/or note)
+func newEvidence(input EvidenceInput, submittedBy uint, party string) (*domain.DisputeEvidence, error) {
+	rawURL := strings.TrimSpace(input.URL)
+	note := strings.TrimSpace(input.Note)
+	if rawURL == "" && note == "" {
+		return nil, domain.ErrInvalidEvidence
+	}
+	if rawURL != "" {
+		u, err := url.Parse(rawURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(rawURL) > 500 {
+			return nil, domain.ErrInvalidEvidence
+		}
+	}
+
+	return &domain.DisputeEvidence{
+		SubmittedBy: submittedBy,
+		Party:       party,
+		URL:         rawURL,
+		Note:        note,
+	}, nil
+}
+
+// sellerRefundShare is the part of a refund paid by the seller: the refund minus the
+// platform fee share of it (proportional to the order's current earning / final amount),
+// at most what the seller still earns on the order
+func sellerRefundShare(totals domain.OrderTotals, refund float64) float64 {
+	if totals.FinalAmount <= 0 {
+		return 0
+	}
+	return math.Min(math.Round(refund*totals.EarningAmount/totals.FinalAmount), totals.EarningAmount)
+}
+
+// disputePage normalizes pagination
+func disputePage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return page, limit
+}
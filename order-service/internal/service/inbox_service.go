@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"order-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// InboxService keeps the in-app notification inbox of each user: notification events
+// become inbox entries the user can list and mark as read
+type InboxService struct {
+	repo   domain.NotificationRepository
+	logger *zap.Logger
+}
+
+// NewInboxService creates a new inbox service
+func NewInboxService(repo domain.NotificationRepository, logger *zap.Logger) *InboxService {
+	return &InboxService{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// HandleNotificationEvent adds the event to the user's inbox; redelivered events
+// (same dedupe key) are ignored and unknown event types are skipped
+func (s *InboxService) HandleNotificationEvent(ctx context.Context, event *domain.NotificationEvent) error {
+	notification := inboxEntry(event)
+	if notification == nil {
+		return nil
+	}
+
+	created, err := s.repo.Create(ctx, notification)
+	if err != nil {
+		return fmt.Errorf("failed to store notification: %w", err)
+	}
+	if created {
+		s.logger.Debug("notification added to inbox",
+			zap.Uint("user_id", event.UserID),
+			zap.String("event_type", event.EventType),
+			zap.String("dedupe_key", event.DedupeKey),
+		)
+	}
+	return nil
+}
+
+// List returns the user's notifications, newest first
+func (s *InboxService) List(ctx context.Context, userID uint, unreadOnly bool, page, limit int) ([]*domain.Notification, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return s.repo.ListByUser(ctx, userID, unreadOnly, page, limit)
+}
+
+// UnreadCount returns the number shown on the bell icon
+func (s *InboxService) UnreadCount(ctx context.Context, userID uint) (int64, error) {
+	return s.repo.CountUnread(ctx, userID)
+}
+
+// MarkRead marks one notification as read
+func (s *InboxService) MarkRead(ctx context.Context, userID, id uint) error {
+	return s.repo.MarkRead(ctx, userID, id)
+}
+
+// MarkAllRead marks all of the user's notifications as read
+func (s *InboxService) MarkAllRead(ctx context.Context, userID uint) (int64, error) {
+	return s.repo.MarkAllRead(ctx, userID)
+}
+
+// inboxEntry renders a notification event as an inbox entry (nil = not shown in the inbox)
+func inboxEntry(event *domain.NotificationEvent) *domain.Notification {
+	if event.UserID == 0 || event.DedupeKey == "" {
+		return nil
+	}
+
+	productName := event.ProductName
+	if productName == "" {
+		productName = "A product you follow"
+	}
+
+	notification := &domain.Notification{
+		UserID:    event.UserID,
+		EventType: event.EventType,
+		DedupeKey: event.DedupeKey,
+	}
+	if !event.Timestamp.IsZero() {
+		notification.CreatedAt = event.Timestamp
+	}
+
+	switch event.EventType {
+	case domain.EventNotificationPriceDrop:
+		notification.Title = "Price drop"
+		notification.Body = fmt.Sprintf("%s is now %.2f, at or below your target of %.2f", productName, event.Price, event.TargetPrice)
+		notification.Link = fmt.Sprintf("/products/%d", event.ProductID)
+	case domain.EventNotificationBackInStock:
+		notification.Title = "Back in stock"
+		notification.Body = fmt.Sprintf("%s is available again", productName)
+		notification.Link = fmt.Sprintf("/products/%d", event.ProductID)
+	case domain.EventNotificationDigitalCodesIssued:
+		notification.Title = "Your codes are ready"
+		notification.Body = fmt.Sprintf("The codes of order %s are on the order detail page", event.OrderNumber)
+		notification.Link = fmt.Sprintf("/orders/%s", event.OrderNumber)
+	case domain.EventNotificationAnnouncement:
+		if event.AnnouncementID == 0 {
+			return nil
+		}
+		announcementID := event.AnnouncementID
+		notification.Title = event.Title
+		notification.Body = event.Body
+		notification.Link = event.Link
+		notification.AnnouncementID = &announcementID
+	default:
+		return nil
+	}
+	return notification
+}
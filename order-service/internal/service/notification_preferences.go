@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"order-service/internal/domain"
+	"order-service/pkg/notification_prefs"
+
+	"go.uber.org/zap"
+)
+
+// notificationCategories maps notification event types to the preference category users control
+var notificationCategories = map[string]string{
+	domain.EventNotificationPriceDrop:          notification_prefs.CategoryPriceAlerts,
+	domain.EventNotificationBackInStock:        notification_prefs.CategoryPriceAlerts,
+	domain.EventNotificationDigitalCodesIssued: notification_prefs.CategoryOrderUpdates,
+}
+
+// NotificationPreferenceReader resolves the channels a user enabled for a category
+type NotificationPreferenceReader interface {
+	EnabledChannels(ctx context.Context, userID uint, category string) []string
+}
+
+// PreferenceAwareNotifier applies users' notification preferences before publishing:
+// the event carries the enabled channels for the dispatcher, and events of a category
+// the user opted out of entirely are dropped
+type PreferenceAwareNotifier struct {
+	publisher domain.NotificationEventPublisher
+	prefs     NotificationPreferenceReader
+	logger    *zap.Logger
+}
+
+// NewPreferenceAwareNotifier wraps a notification publisher with preference checks
+func NewPreferenceAwareNotifier(publisher domain.NotificationEventPublisher, prefs NotificationPreferenceReader, logger *zap.Logger) *PreferenceAwareNotifier {
+	return &PreferenceAwareNotifier{
+		publisher: publisher,
+		prefs:     prefs,
+		logger:    logger,
+	}
+}
+
+// PublishNotificationEvent publishes the event on the channels the user enabled
+// Events without a known category are published unchanged
+func (n *PreferenceAwareNotifier) PublishNotificationEvent(event *domain.NotificationEvent) error {
+	category, ok := notificationCategories[event.EventType]
+	if !ok {
+		return n.publisher.PublishNotificationEvent(event)
+	}
+
+	event.Channels = n.prefs.EnabledChannels(context.Background(), event.UserID, category)
+	if len(event.Channels) == 0 {
+		n.logger.Debug("notification skipped, user opted out",
+			zap.String("event_type", event.EventType),
+			zap.Uint("user_id", event.UserID),
+			zap.String("category", category),
+		)
+		return nil
+	}
+	return n.publisher.PublishNotificationEvent(event)
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"order-service/internal/domain"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// AdjustOrderRequest is an admin's financial correction of an order
+// Amount is the signed change: of the platform fee for fee_adjustment (the shop's earning
+// changes by the opposite), of the shipping fee for shipping_correction (charged to or
+// returned to the buyer, the shop's earning changes alike)
+type AdjustOrderRequest struct {
+	Type   string  `json:"type" binding:"required"` // fee_adjustment or shipping_correction
+	Amount float64 `json:"amount"`
+	Reason string  `json:"reason" binding:"required,max=255"`
+}
+
+// AdjustOrder records a fee adjustment or shipping correction of a paid order (admin)
+// The order's snapshot is not changed: the adjustment is appended to its history and the
+// earning difference goes to the shop's next payout. Returns the order with its history
+// and new totals; refunds are recorded by dispute decisions
+func (s *OrderService) AdjustOrder(ctx context.Context, adminID, orderID uint, req *AdjustOrderRequest) (*domain.Order, error) {
+	adjustmentType := domain.OrderAdjustmentType(req.Type)
+	if !slices.Contains(domain.ManualOrderAdjustmentTypes, adjustmentType) {
+		return nil, domain.ErrInvalidAdjustmentType
+	}
+	if req.Amount == 0 {
+		return nil, domain.ErrInvalidAdjustmentAmount
+	}
+
+	order, err := s.orderRepo.GetByID(orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NotFound("order not found")
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if order.ArchivedAt != nil || !order.CanBeAdjusted() {
+		return nil, domain.ErrOrderNotAdjustable
+	}
+	if err := s.attachAdjustments(order); err != nil {
+		return nil, err
+	}
+
+	adjustment := &domain.OrderAdjustment{
+		Type:      adjustmentType,
+		Reason:    strings.TrimSpace(req.Reason),
+		CreatedBy: adminID,
+	}
+	switch adjustmentType {
+	case domain.OrderAdjustmentFeeAdjustment:
+		// The platform does not pay the shop beyond waiving its whole fee
+		if order.Totals.PlatformFee+req.Amount < 0 {
+			return nil, domain.ErrAdjustmentExceedsOrder
+		}
+		adjustment.PlatformFeeDelta = req.Amount
+		adjustment.EarningAmountDelta = -req.Amount
+	case domain.OrderAdjustmentShippingCorrection:
+		adjustment.ShippingFeeDelta = req.Amount
+		adjustment.FinalAmountDelta = req.Amount
+		adjustment.EarningAmountDelta = req.Amount
+	}
+
+	payout := &domain.PayoutAdjustment{
+		ShopID:  order.ShopID,
+		OrderID: order.ID,
+		Amount:  adjustment.EarningAmountDelta,
+		Reason:  fmt.Sprintf("Order %s %s: %s", order.OrderNumber, adjustmentType, adjustment.Reason),
+		Status:  domain.PayoutAdjustmentPending,
+	}
+	totals, err := s.orderRepo.AppendAdjustment(order, adjustment, payout)
+	if err != nil {
+		if errors.Is(err, domain.ErrAdjustmentExceedsOrder) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to record order adjustment: %w", err)
+	}
+	order.Adjustments = append(order.Adjustments, *adjustment)
+	order.Totals = &totals
+
+	s.logger.Info("order adjusted",
+		zap.Uint("order_id", order.ID),
+		zap.Uint("adjustment_id", adjustment.ID),
+		zap.String("type", string(adjustment.Type)),
+		zap.Float64("amount", req.Amount),
+		zap.Float64("final_amount", totals.FinalAmount),
+		zap.Float64("earning_amount", totals.EarningAmount),
+		zap.Uint("created_by", adminID),
+	)
+
+	event := domain.NewOrderEvent("order_adjusted", order, domain.OrderAdjustedMetadata{
+		Adjustment: adjustment,
+		Totals:     totals,
+	})
+	s.async.Submit(context.Background(), "publish_order_adjusted", func(context.Context) error {
+		return s.eventPublisher.PublishOrderEvent(event)
+	})
+	return order, nil
+}
+
+// attachAdjustments loads the order's adjustment history and derives its current totals
+func (s *OrderService) attachAdjustments(order *domain.Order) error {
+	adjustments, err := s.orderRepo.ListAdjustments(order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get order adjustments: %w", err)
+	}
+	totals := domain.DeriveOrderTotals(order, adjustments)
+	order.Adjustments = adjustments
+	order.Totals = &totals
+	return nil
+}
@@ -0,0 +1,419 @@
+//go:build integration
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"order-service/internal/domain"
+	"order-service/internal/repository/postgres"
+	redisrepo "order-service/internal/repository/redis"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	gormpostgres "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Run with: TEST_DATABASE_DSN=postgres://... TEST_REDIS_ADDR=localhost:6379 go test -tags integration ./internal/service
+// (docker compose -f docker-compose.test.yml up -d starts both)
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+	db, err := gorm.Open(gormpostgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	// Same order as cmd/main.go: partition the order tables before AutoMigrate adds their indexes
+	if err := postgres.MigrateOrderPartitions(db, 1); err != nil {
+		t.Fatalf("failed to partition order tables: %v", err)
+	}
+	if err := db.AutoMigrate(&domain.Order{}, &domain.OrderItem{}, &domain.OrderStatusHistory{}, &domain.Voucher{}, &domain.VoucherRedemption{}); err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	if err := postgres.MigrateOrderNumbers(db); err != nil {
+		t.Fatalf("failed to migrate order numbers: %v", err)
+	}
+	return db
+}
+
+func openTestRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	addr := os.Getenv("TEST_REDIS_ADDR")
+	if addr == "" {
+		t.Skip("TEST_REDIS_ADDR is not set")
+	}
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// inlineRunner runs side effects right away so the test can check them on return
+type inlineRunner struct{}
+
+func (inlineRunner) Submit(ctx context.Context, name string, fn func(ctx context.Context) error) {
+	_ = fn(ctx)
+}
+
+type defaultSettings struct{}
+
+func (defaultSettings) GetInt(scope, key string, def int) int           { return def }
+func (defaultSettings) GetFloat(scope, key string, def float64) float64 { return def }
+func (defaultSettings) GetBool(scope, key string, def bool) bool        { return def }
+
+type recordedEvents struct {
+	mu     sync.Mutex
+	events []*domain.OrderEvent
+}
+
+func (r *recordedEvents) PublishOrderEvent(event *domain.OrderEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *recordedEvents) Close() error { return nil }
+
+func (r *recordedEvents) PublishNotificationEvent(event *domain.NotificationEvent) error { return nil }
+
+func (r *recordedEvents) count(eventType string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, event := range r.events {
+		if event.EventType == eventType {
+			n++
+		}
+	}
+	return n
+}
+
+// fakeProductService holds SKUs and their reservations in memory; ReserveStock is
+// all-or-nothing per reservation like product-service's reserve script
+type fakeProductService struct {
+	mu           sync.Mutex
+	items        map[uint]*OrderProductItemDTO
+	stock        map[uint]int
+	reservations map[string]map[uint]int
+}
+
+func (p *fakeProductService) GetProductItem(ctx context.Context, id uint) (*OrderProductItemDTO, error) {
+	items, err := p.GetProductItems(ctx, []uint{id})
+	if err != nil || items[id] == nil {
+		return nil, domain.NotFound("product item %d not found", id)
+	}
+	return items[id], nil
+}
+
+func (p *fakeProductService) GetProductItems(ctx context.Context, ids []uint) (map[uint]*OrderProductItemDTO, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make(map[uint]*OrderProductItemDTO, len(ids))
+	for _, id := range ids {
+		if item, ok := p.items[id]; ok {
+			copied := *item
+			copied.Stock = p.stock[id]
+			out[id] = &copied
+		}
+	}
+	return out, nil
+}
+
+func (p *fakeProductService) IssueDigitalCodes(ctx context.Context, orderNumber string, quantities map[uint]int) (map[uint][]string, error) {
+	return nil, errors.New("not used")
+}
+
+func (p *fakeProductService) GetDigitalCodes(ctx context.Context, orderNumber string) (map[uint][]string, error) {
+	return nil, errors.New("not used")
+}
+
+func (p *fakeProductService) reservedLocked(id uint) int {
+	n := 0
+	for _, quantities := range p.reservations {
+		n += quantities[id]
+	}
+	return n
+}
+
+func (p *fakeProductService) ReserveStock(ctx context.Context, reservationID string, quantities map[uint]int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.reservations[reservationID]; ok {
+		return nil
+	}
+	for id, qty := range quantities {
+		if qty > p.stock[id]-p.reservedLocked(id) {
+			return domain.ErrInsufficientStock
+		}
+	}
+	p.reservations[reservationID] = quantities
+	return nil
+}
+
+func (p *fakeProductService) DeductStock(ctx context.Context, reservationID string, quantities map[uint]int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for id, qty := range quantities {
+		p.stock[id] -= qty
+	}
+	delete(p.reservations, reservationID)
+	return nil
+}
+
+func (p *fakeProductService) ReleaseStock(ctx context.Context, reservationID string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.reservations, reservationID)
+	return nil
+}
+
+func (p *fakeProductService) reserved(id uint) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.reservedLocked(id)
+}
+
+type namedShops struct{}
+
+func (namedShops) GetShop(ctx context.Context, shopID uint) (*OrderShopDTO, error) {
+	return &OrderShopDTO{ID: shopID, Name: fmt.Sprintf("Shop %d", shopID)}, nil
+}
+
+// checkoutFixture wires OrderService to Postgres and Redis with a fake product-service;
+// user and SKU IDs are unique per test so runs on the same database do not collide
+type checkoutFixture struct {
+	service  *OrderService
+	db       *gorm.DB
+	carts    domain.CartRepository
+	products *fakeProductService
+	events   *recordedEvents
+	base     uint
+	buyers   []string
+}
+
+func newCheckoutFixture(t *testing.T) *checkoutFixture {
+	t.Helper()
+	db := openTestDB(t)
+	client := openTestRedis(t)
+	log := zap.NewNop()
+
+	f := &checkoutFixture{
+		db:       db,
+		carts:    redisrepo.NewCartRepository(client, redisrepo.CartPolicy{TTL: time.Hour}, log),
+		products: &fakeProductService{items: map[uint]*OrderProductItemDTO{}, stock: map[uint]int{}, reservations: map[string]map[uint]int{}},
+		events:   &recordedEvents{},
+		base:     uint(time.Now().UnixNano()%1_000_000) * 1000,
+	}
+	orderRepo := postgres.NewOrderRepository(db)
+	statuses := NewOrderStatusService(orderRepo, nil, f.products, f.events, inlineRunner{}, log)
+	vouchers := NewVoucherService(postgres.NewVoucherRepository(db), nil, inlineRunner{}, log)
+	f.service = NewOrderService(orderRepo, f.carts, f.products, f.events, f.events, defaultSettings{}, inlineRunner{},
+		redisrepo.NewOrderNumberGenerator(client), namedShops{}, statuses, vouchers, log)
+
+	t.Cleanup(func() {
+		var ids []uint
+		db.Model(&domain.Order{}).Where("user_id BETWEEN ? AND ?", f.base, f.base+999).Pluck("id", &ids)
+		if len(ids) > 0 {
+			db.Unscoped().Where("order_id IN ?", ids).Delete(&domain.OrderItem{})
+			db.Unscoped().Where("id IN ?", ids).Delete(&domain.Order{})
+		}
+		for _, buyer := range f.buyers {
+			f.carts.DeleteCart(buyer)
+		}
+	})
+	return f
+}
+
+func (f *checkoutFixture) addSKU(n uint, shopID uint, price float64, stock int) uint {
+	id := f.base + n
+	f.products.items[id] = &OrderProductItemDTO{ID: id, ProductID: id, ShopID: shopID, ProductName: fmt.Sprintf("Item %d", n), SKU: fmt.Sprintf("SKU-%d", id), Price: price, IsActive: true}
+	f.products.stock[id] = stock
+	return id
+}
+
+func (f *checkoutFixture) user(n uint) uint {
+	return f.base + n
+}
+
+func (f *checkoutFixture) fillCart(t *testing.T, userID uint, quantities map[uint]int) {
+	t.Helper()
+	cart := &domain.ShoppingCart{UserID: fmt.Sprintf("%d", userID)}
+	f.buyers = append(f.buyers, cart.UserID)
+	for id, qty := range quantities {
+		cart.Items = append(cart.Items, &domain.CartItem{ProductItemID: id, Quantity: qty, IsSelected: true})
+	}
+	if err := f.carts.SaveCart(cart); err != nil {
+		t.Fatalf("save cart: %v", err)
+	}
+}
+
+func (f *checkoutFixture) checkout(userID uint) (*CreateOrderResponse, error) {
+	addressID := uint(1)
+	return f.service.CreateOrder(context.Background(), &CreateOrderRequest{
+		UserID:            &userID,
+		ShippingName:      "Nguyen Van A",
+		ShippingPhone:     "0901234567",
+		ShippingAddress:   "1 Le Loi",
+		ShippingCity:      "Ho Chi Minh",
+		ShippingAddressID: &addressID,
+		PaymentMethod:     "COD",
+	})
+}
+
+func (f *checkoutFixture) orderCount(t *testing.T, userID uint) int64 {
+	t.Helper()
+	var n int64
+	if err := f.db.Model(&domain.Order{}).Where("user_id = ?", userID).Count(&n).Error; err != nil {
+		t.Fatalf("count orders: %v", err)
+	}
+	return n
+}
+
+// TestCheckoutSplitsOrdersByShop checks a two-shop checkout end to end: one shop_order per
+// shop with server-side amounts, stock held per shop, events published and the cart cleared
+func TestCheckoutSplitsOrdersByShop(t *testing.T) {
+	f := newCheckoutFixture(t)
+	phone := f.addSKU(1, 11, 100000, 10)
+	cable := f.addSKU(2, 12, 50000, 10)
+	buyer := f.user(1)
+	f.fillCart(t, buyer, map[uint]int{phone: 2, cable: 1})
+
+	resp, err := f.checkout(buyer)
+	if err != nil {
+		t.Fatalf("checkout: %v", err)
+	}
+	if resp.Status != domain.CheckoutStatusCompleted || len(resp.Orders) != 2 {
+		t.Fatalf("checkout = %s with %d orders, want completed with one order per shop", resp.Status, len(resp.Orders))
+	}
+
+	orderRepo := postgres.NewOrderRepository(f.db)
+	want := map[uint]struct{ subtotal, final, fee, earning float64 }{
+		11: {200000, 230000, 10000, 220000},
+		12: {50000, 80000, 2500, 77500},
+	}
+	for _, created := range resp.Orders {
+		order, err := orderRepo.GetByID(created.ID)
+		if err != nil {
+			t.Fatalf("reload order %d: %v", created.ID, err)
+		}
+		w := want[order.ShopID]
+		if order.Status != domain.OrderStatusPending || order.CheckoutID != resp.CheckoutID {
+			t.Errorf("shop %d: status %s, checkout %q", order.ShopID, order.Status, order.CheckoutID)
+		}
+		if order.MerchandiseSubtotal != w.subtotal || order.FinalAmount != w.final || order.PlatformFee != w.fee || order.EarningAmount != w.earning {
+			t.Errorf("shop %d amounts = %v/%v/%v/%v, want %v/%v/%v/%v", order.ShopID,
+				order.MerchandiseSubtotal, order.FinalAmount, order.PlatformFee, order.EarningAmount,
+				w.subtotal, w.final, w.fee, w.earning)
+		}
+		if order.ShopName != fmt.Sprintf("Shop %d", order.ShopID) || len(order.Items) != 1 {
+			t.Errorf("shop %d: name %q with %d items", order.ShopID, order.ShopName, len(order.Items))
+		}
+	}
+
+	if f.products.reserved(phone) != 2 || f.products.reserved(cable) != 1 {
+		t.Errorf("reserved = %d/%d, want the cart quantities 2/1", f.products.reserved(phone), f.products.reserved(cable))
+	}
+	if got := f.events.count("order_created"); got != 2 {
+		t.Errorf("order_created events = %d, want 2", got)
+	}
+	if cart, _ := f.carts.GetCart(fmt.Sprintf("%d", buyer)); cart != nil && !cart.IsEmpty() {
+		t.Errorf("cart still has %d items after checkout", len(cart.Items))
+	}
+}
+
+// TestCheckoutShortStockCreatesNothing checks that a shop whose stock cannot be held
+// aborts the whole checkout: no order is written, nothing stays reserved, the cart is kept
+func TestCheckoutShortStockCreatesNothing(t *testing.T) {
+	f := newCheckoutFixture(t)
+	inStock := f.addSKU(1, 21, 100000, 5)
+	short := f.addSKU(2, 22, 100000, 5)
+	buyer, other := f.user(1), f.user(2)
+
+	// Another order holds most of the second SKU after the buyer's cart was validated
+	if err := f.products.ReserveStock(context.Background(), fmt.Sprintf("other-%d", other), map[uint]int{short: 4}); err != nil {
+		t.Fatalf("reserve for the other order: %v", err)
+	}
+	f.fillCart(t, buyer, map[uint]int{inStock: 1, short: 2})
+
+	if _, err := f.checkout(buyer); !errors.Is(err, domain.ErrInsufficientStock) {
+		t.Fatalf("checkout err = %v, want %v", err, domain.ErrInsufficientStock)
+	}
+	if n := f.orderCount(t, buyer); n != 0 {
+		t.Errorf("%d orders written for a failed checkout", n)
+	}
+	if got := f.products.reserved(inStock); got != 0 {
+		t.Errorf("first shop still holds %d units", got)
+	}
+	if cart, _ := f.carts.GetCart(fmt.Sprintf("%d", buyer)); cart == nil || len(cart.Items) != 2 {
+		t.Error("cart was cleared by a failed checkout")
+	}
+}
+
+// TestConcurrentCheckoutsLastUnits races buyers for a SKU with little stock: only as many
+// checkouts as there are units get an order, the others fail with insufficient stock
+func TestConcurrentCheckoutsLastUnits(t *testing.T) {
+	const (
+		stock  = 3
+		buyers = 12
+	)
+	f := newCheckoutFixture(t)
+	sku := f.addSKU(1, 31, 250000, stock)
+	for b := uint(1); b <= buyers; b++ {
+		f.fillCart(t, f.user(b), map[uint]int{sku: 1})
+	}
+
+	var (
+		succeeded atomic.Int64
+		wg        sync.WaitGroup
+		start     = make(chan struct{})
+		errs      = make(chan error, buyers)
+	)
+	for b := uint(1); b <= buyers; b++ {
+		wg.Add(1)
+		go func(userID uint) {
+			defer wg.Done()
+			<-start
+			_, err := f.checkout(userID)
+			switch {
+			case err == nil:
+				succeeded.Add(1)
+			case !errors.Is(err, domain.ErrInsufficientStock):
+				errs <- err
+			}
+		}(f.user(b))
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("unexpected checkout error: %v", err)
+	}
+	if got := succeeded.Load(); got != stock {
+		t.Errorf("%d checkouts succeeded, want exactly the %d units in stock", got, stock)
+	}
+	var orders int64
+	if err := f.db.Model(&domain.Order{}).Where("user_id BETWEEN ? AND ?", f.user(1), f.user(buyers)).Count(&orders).Error; err != nil {
+		t.Fatalf("count orders: %v", err)
+	}
+	if orders != stock {
+		t.Errorf("%d orders written, want %d", orders, stock)
+	}
+	if got := f.products.reserved(sku); got != stock {
+		t.Errorf("reserved = %d, want %d", got, stock)
+	}
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"order-service/internal/domain"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// ConfirmPayment records the payment of an order and fulfills its digital items
+// Digital items get codes from the seller's code pool (product-service); an order
+// with nothing to ship is delivered right away and the buyer is notified
+// Safe to call again: an order that is already paid only retries the digital
+// items that are not fulfilled yet
+func (s *OrderService) ConfirmPayment(ctx context.Context, orderID uint) (*domain.Order, error) {
+	order, err := s.orderRepo.GetByID(orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NotFound("order not found")
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	switch order.Status {
+	case domain.OrderStatusPending:
+		paidAt := time.Now()
+		paid, err := s.statuses.Apply(order, domain.OrderStatusPaid, domain.StatusActorSystem, 0, "payment_confirmed", map[string]interface{}{
+			"paid_at": paidAt,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to mark order paid: %w", err)
+		}
+		if !paid {
+			// Another confirmation (or a cancellation) got there first
+			if order, err = s.orderRepo.GetByID(orderID); err != nil {
+				return nil, fmt.Errorf("failed to get order: %w", err)
+			}
+			if order.Status == domain.OrderStatusCancelled {
+				return nil, domain.ErrOrderNotPayable
+			}
+			break
+		}
+
+		order.PaidAt = &paidAt
+		s.publishOrderEvent("order_paid", order)
+		s.logger.Info("order paid", zap.Uint("order_id", order.ID), zap.String("order_number", order.OrderNumber))
+	case domain.OrderStatusCancelled:
+		return nil, domain.ErrOrderNotPayable
+	}
+
+	if err := s.fulfillDigitalItems(ctx, order); err != nil {
+		return order, err
+	}
+	return order, nil
+}
+
+// fulfillDigitalItems issues the codes of the order's unfulfilled digital items
+func (s *OrderService) fulfillDigitalItems(ctx context.Context, order *domain.Order) error {
+	quantities := make(map[uint]int)
+	var pending []uint
+	for _, item := range order.Items {
+		if item.IsDigital() && item.FulfilledAt == nil {
+			quantities[item.ProductItemID] += item.Quantity
+			pending = append(pending, item.ID)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	// Product-service issues per order number, so a retry after a partial failure
+	// returns the same codes instead of new ones
+	if _, err := s.productClient.IssueDigitalCodes(ctx, order.OrderNumber, quantities); err != nil {
+		s.logger.Error("failed to issue digital codes",
+			zap.Uint("order_id", order.ID),
+			zap.String("order_number", order.OrderNumber),
+			zap.Error(err),
+		)
+		return fmt.Errorf("order paid but digital codes could not be issued: %w", err)
+	}
+
+	fulfilledAt := time.Now()
+	if err := s.orderRepo.MarkItemsFulfilled(order, pending, fulfilledAt); err != nil {
+		return fmt.Errorf("failed to mark digital items fulfilled: %w", err)
+	}
+	for i := range order.Items {
+		if order.Items[i].IsDigital() && order.Items[i].FulfilledAt == nil {
+			order.Items[i].FulfilledAt = &fulfilledAt
+		}
+	}
+
+	// Nothing to ship: the order is complete once its codes are issued
+	if order.IsDigitalOnly() && order.Status == domain.OrderStatusPaid {
+		delivered, err := s.statuses.Apply(order, domain.OrderStatusDelivered, domain.StatusActorSystem, 0, "digital_codes_issued", nil)
+		if err != nil {
+			return fmt.Errorf("failed to mark digital order delivered: %w", err)
+		}
+		if delivered {
+			s.publishOrderEvent("order_delivered", order)
+		}
+	}
+
+	s.notifyDigitalCodesIssued(order)
+	s.logger.Info("digital items fulfilled",
+		zap.Uint("order_id", order.ID),
+		zap.Int("items", len(pending)),
+	)
+	return nil
+}
+
+// notifyDigitalCodesIssued tells the buyer their codes are ready on the order detail
+// The event never carries the codes; the dedupe key is per order
+func (s *OrderService) notifyDigitalCodesIssued(order *domain.Order) {
+	if s.notifications == nil {
+		return
+	}
+
+	event := &domain.NotificationEvent{
+		EventType:   domain.EventNotificationDigitalCodesIssued,
+		DedupeKey:   fmt.Sprintf("order:%d:digital_codes", order.ID),
+		UserID:      order.UserID,
+		OrderID:     order.ID,
+		OrderNumber: order.OrderNumber,
+		Timestamp:   time.Now(),
+	}
+	for _, item := range order.Items {
+		if item.IsDigital() {
+			event.ProductID = item.ProductID
+			event.ProductItemID = item.ProductItemID
+			event.ProductName = item.ProductName
+			break
+		}
+	}
+
+	s.async.Submit(context.Background(), "publish_digital_codes_notification", func(context.Context) error {
+		return s.notifications.PublishNotificationEvent(event)
+	})
+}
+
+// publishOrderEvent publishes an order lifecycle event on the worker pool
+func (s *OrderService) publishOrderEvent(eventType string, order *domain.Order) {
+	event := domain.NewOrderEvent(eventType, order, nil)
+	s.async.Submit(context.Background(), "publish_"+eventType, func(context.Context) error {
+		return s.eventPublisher.PublishOrderEvent(event)
+	})
+}
+
+// attachDigitalCodes fills the issued codes of the order's digital items for its buyer
+// Best effort: the order is still returned without codes if product-service fails
+func (s *OrderService) attachDigitalCodes(ctx context.Context, order *domain.Order, viewerID uint) {
+	if viewerID == 0 || viewerID != order.UserID || !order.HasDigitalItems() {
+		return
+	}
+
+	codes, err := s.productClient.GetDigitalCodes(ctx, order.OrderNumber)
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			s.logger.Warn("failed to load digital codes",
+				zap.Uint("order_id", order.ID),
+				zap.Error(err),
+			)
+		}
+		return
+	}
+
+	// Several lines of the same SKU share its codes in line order
+	for i := range order.Items {
+		item := &order.Items[i]
+		if !item.IsDigital() {
+			continue
+		}
+		available := codes[item.ProductItemID]
+		n := item.Quantity
+		if n > len(available) {
+			n = len(available)
+		}
+		item.DigitalCodes = available[:n]
+		codes[item.ProductItemID] = available[n:]
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"order-service/internal/domain"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// HandlePaymentEvent applies a payment-service event to its order
+// payment_succeeded confirms the payment (see ConfirmPayment) and posts it to the
+// accounting journal; payment_failed cancels the order if it is still pending. Both are
+// safe to receive twice
+func (s *OrderService) HandlePaymentEvent(ctx context.Context, event *domain.PaymentEvent) error {
+	switch event.EventType {
+	case domain.EventPaymentSucceeded:
+		order, err := s.ConfirmPayment(ctx, event.OrderID)
+		if order != nil {
+			s.postPaymentJournal(order, event)
+		}
+		if errors.Is(err, domain.ErrOrderNotPayable) {
+			// The buyer was charged for an order cancelled meanwhile: needs a refund
+			s.logger.Error("payment succeeded for a cancelled order",
+				zap.Uint("order_id", event.OrderID),
+				zap.Uint("payment_intent_id", event.PaymentIntentID),
+				zap.String("provider_ref", event.ProviderRef),
+			)
+			return nil
+		}
+		return err
+	case domain.EventPaymentFailed:
+		return s.CancelUnpaidOrder(event.OrderID, event.Reason)
+	default:
+		return nil
+	}
+}
+
+// CancelUnpaidOrder cancels a pending order whose payment failed for good
+// An order that is no longer pending (paid by another intent, already cancelled) is left alone
+func (s *OrderService) CancelUnpaidOrder(orderID uint, reason string) error {
+	order, err := s.orderRepo.GetByID(orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.NotFound("order not found")
+		}
+		return fmt.Errorf("failed to get order: %w", err)
+	}
+	if order.Status != domain.OrderStatusPending {
+		return nil
+	}
+
+	cancelled, err := s.statuses.Apply(order, domain.OrderStatusCancelled, domain.StatusActorSystem, 0, "payment_failed", nil)
+	if err != nil {
+		return fmt.Errorf("failed to cancel order: %w", err)
+	}
+	if !cancelled {
+		return nil
+	}
+
+	event := domain.NewOrderEvent("order_cancelled", order, map[string]interface{}{
+		"reason":         "payment_failed",
+		"payment_reason": reason,
+	})
+	s.async.Submit(context.Background(), "publish_order_cancelled", func(context.Context) error {
+		return s.eventPublisher.PublishOrderEvent(event)
+	})
+	s.logger.Info("order cancelled after failed payment",
+		zap.Uint("order_id", order.ID),
+		zap.String("order_number", order.OrderNumber),
+		zap.String("reason", reason),
+	)
+	return nil
+}
+
+// postPaymentJournal records the payment of an order in the accounting journal
+// Posted once per order on the worker pool with retries (see domain.NewPaymentJournalEntry)
+func (s *OrderService) postPaymentJournal(order *domain.Order, event *domain.PaymentEvent) {
+	entry := domain.NewPaymentJournalEntry(order, event)
+	s.async.Submit(context.Background(), "post_payment_journal", func(context.Context) error {
+		if _, err := s.orderRepo.PostJournalEntry(entry); err != nil {
+			return fmt.Errorf("failed to post payment journal entry: %w", err)
+		}
+		return nil
+	})
+}
@@ -1,13 +1,19 @@
 package service
 
 import (
+	"context"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"order-service/internal/domain"
 	"order-service/internal/repository/postgres"
+	"order-service/pkg/money"
+	"sort"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 // OrderService handles business logic for orders
@@ -17,17 +23,51 @@ type OrderService struct {
 	cartRepo       domain.CartRepository
 	productClient  OrderProductServiceClient
 	eventPublisher domain.OrderEventPublisher
+	notifications  domain.NotificationEventPublisher
+	settings       SettingsReader
+	async          AsyncRunner
+	orderNumbers   domain.OrderNumberGenerator
+	shops          OrderShopClient
+	statuses       *OrderStatusService
+	vouchers       *VoucherService
 	logger         *zap.Logger
 }
 
+// AsyncRunner runs side effects (event publishing) on a bounded worker pool
+// with retries (implemented by pkg/taskqueue)
+type AsyncRunner interface {
+	Submit(ctx context.Context, name string, fn func(ctx context.Context) error)
+}
+
 // OrderProductServiceClient defines interface to communicate with Product Service
 // NOTE: OrderService needs FULL product data for validation (Stock, IsActive)
 type OrderProductServiceClient interface {
 	// GetProductItem fetches single product item details (SKU-level)
-	GetProductItem(productItemID uint) (*OrderProductItemDTO, error)
+	GetProductItem(ctx context.Context, productItemID uint) (*OrderProductItemDTO, error)
 
 	// GetProductItems fetches multiple product items in batch (for performance)
-	GetProductItems(productItemIDs []uint) (map[uint]*OrderProductItemDTO, error)
+	GetProductItems(ctx context.Context, productItemIDs []uint) (map[uint]*OrderProductItemDTO, error)
+
+	// IssueDigitalCodes issues the codes of an order's digital items (idempotent per order number)
+	// and returns all codes of the order by product item ID
+	IssueDigitalCodes(ctx context.Context, orderNumber string, quantities map[uint]int) (map[uint][]string, error)
+
+	// GetDigitalCodes returns the codes issued to an order by product item ID
+	GetDigitalCodes(ctx context.Context, orderNumber string) (map[uint][]string, error)
+
+	OrderStockClient
+}
+
+// OrderShopClient fetches shop info from Identity Service for order snapshots
+type OrderShopClient interface {
+	GetShop(ctx context.Context, shopID uint) (*OrderShopDTO, error)
+}
+
+// OrderShopDTO is the shop info snapshotted on a shop_order
+type OrderShopDTO struct {
+	ID      uint   `json:"id"`
+	Name    string `json:"name"`
+	LogoURL string `json:"logo_url"`
 }
 
 // OrderProductItemDTO represents FULL product item data from Product Service
@@ -42,14 +82,28 @@ type OrderProductItemDTO struct {
 	Stock       int     `json:"stock"`        // Available stock (REQUIRED for validation)
 	ImageURL    string  `json:"image_url"`    // Product image
 	IsActive    bool    `json:"is_active"`    // Product active status (REQUIRED for validation)
+
+	MaxPurchaseQuantity int    `json:"max_purchase_quantity"` // Per-customer limit of the product (0 = no limit)
+	VariationLabel      string `json:"variation_label"`       // Resolved variation options, e.g. "Size L, Màu Đen"
+	IsDigital           bool   `json:"is_digital"`            // DIGITAL product: fulfilled with codes after payment, not shipped
 }
 
+// maxOrderNumberAttempts bounds retries when a generated order number already exists
+const maxOrderNumberAttempts = 3
+
 // NewOrderService creates a new order service
 func NewOrderService(
 	orderRepo *postgres.OrderRepository,
 	cartRepo domain.CartRepository,
 	productClient OrderProductServiceClient,
 	eventPublisher domain.OrderEventPublisher,
+	notifications domain.NotificationEventPublisher,
+	settings SettingsReader,
+	async AsyncRunner,
+	orderNumbers domain.OrderNumberGenerator,
+	shops OrderShopClient,
+	statuses *OrderStatusService,
+	vouchers *VoucherService,
 	logger *zap.Logger,
 ) *OrderService {
 	return &OrderService{
@@ -57,6 +111,13 @@ func NewOrderService(
 		cartRepo:       cartRepo,
 		productClient:  productClient,
 		eventPublisher: eventPublisher,
+		notifications:  notifications,
+		settings:       settings,
+		async:          async,
+		orderNumbers:   orderNumbers,
+		shops:          shops,
+		statuses:       statuses,
+		vouchers:       vouchers,
 		logger:         logger,
 	}
 }
@@ -68,19 +129,21 @@ type CreateOrderRequest struct {
 
 	// Shipping information
 	ShippingName       string `json:"shipping_name" binding:"required"`
-	ShippingPhone      string `json:"shipping_phone" binding:"required"`
+	ShippingPhone      string `json:"shipping_phone" binding:"required,vnphone"`
 	ShippingAddress    string `json:"shipping_address" binding:"required"`
 	ShippingCity       string `json:"shipping_city" binding:"required"`
 	ShippingProvince   string `json:"shipping_province,omitempty"`
 	ShippingPostalCode string `json:"shipping_postal_code,omitempty"`
 	ShippingCountry    string `json:"shipping_country,omitempty"`
-	ShippingAddressID  *uint  `json:"shipping_address_id,omitempty"` // THÊM MỚI - Reference address table
+	ShippingAddressID  *uint  `json:"shipping_address_id,omitempty"` // THÊM MỚI - Reference address table (not needed if every item is digital)
 
 	// Financial (theo db-diagram.db)
-	ShippingFee      float64 `json:"shipping_fee,omitempty"`
-	ShippingDiscount float64 `json:"shipping_discount,omitempty"` // Mã freeship
-	VoucherDiscount  float64 `json:"voucher_discount,omitempty"`  // Mã giảm giá
-	PaymentMethod    string  `json:"payment_method,omitempty"`
+	ShippingFee   float64 `json:"shipping_fee,omitempty"`
+	PaymentMethod string  `json:"payment_method,omitempty"` // Default COD; digital items need an online method
+
+	// Voucher codes: platform vouchers are split across the shops, shop vouchers only
+	// reduce the order of their shop. Discounts are computed server-side
+	VoucherCodes []string `json:"voucher_codes,omitempty" binding:"max=10,dive,max=40"`
 }
 
 // CreateOrderResponse represents the response after creating orders
@@ -88,6 +151,32 @@ type CreateOrderRequest struct {
 type CreateOrderResponse struct {
 	Orders       []*domain.Order `json:"orders"`        // Multiple shop_orders (1 per shop)
 	OrderNumbers []string        `json:"order_numbers"` // Order numbers for each shop_order
+
+	CheckoutID string                      `json:"checkout_id"`
+	Status     domain.CheckoutStatus       `json:"status"`
+	Shops      []domain.ShopCheckoutResult `json:"shops"`
+}
+
+// validatePurchaseLimits checks the selected quantity of each product against its
+// max purchase quantity
+func validatePurchaseLimits(items []*domain.CartItem, productItems map[uint]*OrderProductItemDTO) error {
+	totals := make(map[uint]int)
+	for _, item := range items {
+		totals[productItems[item.ProductItemID].ProductID] += item.Quantity
+	}
+
+	for _, item := range items {
+		sku := productItems[item.ProductItemID]
+		if sku.MaxPurchaseQuantity > 0 && totals[sku.ProductID] > sku.MaxPurchaseQuantity {
+			return &domain.PurchaseLimitError{
+				ProductID:   sku.ProductID,
+				ProductName: sku.ProductName,
+				Limit:       sku.MaxPurchaseQuantity,
+				Requested:   totals[sku.ProductID],
+			}
+		}
+	}
+	return nil
 }
 
 // CreateOrder creates orders from the cart with MARKETPLACE logic (REFACTORED - SENIOR LEVEL)
@@ -96,19 +185,15 @@ type CreateOrderResponse struct {
 // 2. Filter SELECTED items only
 // 3. Load SKU snapshots from Product Service & validate (price, stock, active status)
 // 4. Group by shop_id
-// 5. For each shop: calculate financials using server-side rules & snapshot prices
-// 6. Create shop_orders in DB
-// 7. Publish events (SYNC for MVP, TODO: outbox pattern)
+// 5. For each shop: calculate financials using server-side rules, snapshot prices & validated vouchers
+// 6. Reserve stock per shop, create shop_orders in DB (one transaction per shop; on failure roll back the others, release the stock)
+// 7. Publish events (async worker pool, Kafka writes retried then parked in the Redis buffer)
 // 8. Clear cart (SYNC)
 // Returns CreateOrderResponse with multiple shop_orders
-func (s *OrderService) CreateOrder(req *CreateOrderRequest) (*CreateOrderResponse, error) {
+func (s *OrderService) CreateOrder(ctx context.Context, req *CreateOrderRequest) (*CreateOrderResponse, error) {
 	// Validate required fields
 	if req.UserID == nil {
-		return nil, errors.New("user_id is required")
-	}
-
-	if req.ShippingAddressID == nil {
-		return nil, errors.New("shipping_address_id is required")
+		return nil, domain.Validation("user_id is required")
 	}
 
 	userID := *req.UserID
@@ -137,7 +222,7 @@ func (s *OrderService) CreateOrder(req *CreateOrderRequest) (*CreateOrderRespons
 	}
 
 	// Batch load product items
-	productItems, err := s.productClient.GetProductItems(productItemIDs)
+	productItems, err := s.productClient.GetProductItems(ctx, productItemIDs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load product items: %w", err)
 	}
@@ -146,25 +231,56 @@ func (s *OrderService) CreateOrder(req *CreateOrderRequest) (*CreateOrderRespons
 	for _, item := range selectedItems {
 		sku, exists := productItems[item.ProductItemID]
 		if !exists {
-			return nil, fmt.Errorf("product item %d not found", item.ProductItemID)
+			return nil, domain.NotFound("product item %d not found", item.ProductItemID)
 		}
 
 		// Validate SKU status
 		if !sku.IsActive {
-			return nil, fmt.Errorf("product %s is not available", sku.ProductName)
+			return nil, domain.Conflict("product %s is not available", sku.ProductName)
 		}
 
 		// Validate stock
 		if sku.Stock <= 0 {
-			return nil, fmt.Errorf("product %s is out of stock", sku.ProductName)
+			return nil, domain.Conflict("product %s is out of stock", sku.ProductName)
 		}
 
 		if item.Quantity > sku.Stock {
-			return nil, fmt.Errorf("insufficient stock for %s (requested: %d, available: %d)",
+			return nil, domain.Conflict("insufficient stock for %s (requested: %d, available: %d)",
 				sku.ProductName, item.Quantity, sku.Stock)
 		}
 	}
 
+	// Re-validate per-product purchase limits (summed over all SKUs of the product;
+	// the limit may have been set or lowered after the items were added to cart)
+	if err := validatePurchaseLimits(selectedItems, productItems); err != nil {
+		return nil, err
+	}
+
+	// Digital items are not shipped and are fulfilled after payment, so they need no
+	// address but cannot be paid cash on delivery
+	hasPhysical, hasDigital := false, false
+	for _, item := range selectedItems {
+		if productItems[item.ProductItemID].IsDigital {
+			hasDigital = true
+		} else {
+			hasPhysical = true
+		}
+	}
+	if hasPhysical && req.ShippingAddressID == nil {
+		return nil, domain.Validation("shipping_address_id is required")
+	}
+	paymentMethod := req.PaymentMethod
+	if paymentMethod == "" {
+		paymentMethod = "COD"
+	}
+	if hasDigital && strings.EqualFold(paymentMethod, "COD") {
+		return nil, domain.ErrDigitalCashOnDelivery
+	}
+	var shippingAddressID uint
+	if req.ShippingAddressID != nil {
+		shippingAddressID = *req.ShippingAddressID
+	}
+
 	// STEP 4: Group selected items by shop_id
 	itemsByShop := make(map[uint][]*domain.CartItem)
 	for _, item := range selectedItems {
@@ -181,105 +297,188 @@ func (s *OrderService) CreateOrder(req *CreateOrderRequest) (*CreateOrderRespons
 	}
 
 	if len(itemsByShop) == 0 {
-		return nil, errors.New("no valid items to checkout")
+		return nil, domain.Validation("no valid items to checkout")
 	}
 
-	// STEP 5: Create shop_order for each shop
-	createdOrders := make([]*domain.Order, 0, len(itemsByShop))
-	orderNumbers := make([]string, 0, len(itemsByShop))
-
-	for shopID, shopItems := range itemsByShop {
-		// Calculate merchandise subtotal using SKU snapshot prices (B1 fix - server-side pricing)
-		merchandiseSubtotal := float64(0)
-		for _, item := range shopItems {
+	// STEP 5: Create shop_order for each shop (in shop_id order so results are stable)
+	shopIDs := make([]uint, 0, len(itemsByShop))
+	for shopID := range itemsByShop {
+		shopIDs = append(shopIDs, shopID)
+	}
+	sort.Slice(shopIDs, func(i, j int) bool { return shopIDs[i] < shopIDs[j] })
+
+	// Calculate merchandise subtotals using SKU snapshot prices (B1 fix - server-side pricing)
+	// then allocate the discounts of shop/platform vouchers across the shops (B3/B4 fix)
+	allocations := make([]*shopAllocation, 0, len(shopIDs))
+	for _, shopID := range shopIDs {
+		merchandiseSubtotal := money.Zero(checkoutCurrency)
+		shippingFee := money.Zero(checkoutCurrency) // Shops that only sell digital items ship nothing
+		for _, item := range itemsByShop[shopID] {
 			sku := productItems[item.ProductItemID]
 			// Use price from Product Service, NOT from cart
-			lineTotal := sku.Price * float64(item.Quantity)
-			merchandiseSubtotal += lineTotal
+			price, err := money.FromFloat(sku.Price, checkoutCurrency)
+			if err != nil {
+				return nil, fmt.Errorf("invalid price of product item %d: %w", item.ProductItemID, err)
+			}
+			lineTotal, err := price.Mul(int64(item.Quantity))
+			if err == nil {
+				merchandiseSubtotal, err = merchandiseSubtotal.Add(lineTotal)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("invalid amount of product item %d: %w", item.ProductItemID, err)
+			}
+			if !sku.IsDigital {
+				shippingFee = flatShippingFee
+			}
 		}
+		allocations = append(allocations, &shopAllocation{
+			ShopID:      shopID,
+			Subtotal:    merchandiseSubtotal,
+			ShippingFee: shippingFee,
+		})
+	}
+	vouchers, err := s.vouchers.ResolveCheckout(ctx, userID, req.VoucherCodes, allocations)
+	if err != nil {
+		return nil, err
+	}
+	if err := allocateCheckoutDiscounts(allocations, vouchers.ShopDiscounts, vouchers.PlatformVoucher, vouchers.PlatformShipping); err != nil {
+		return nil, fmt.Errorf("failed to allocate voucher discounts: %w", err)
+	}
 
-		// Calculate shipping & discounts (B3/B4 fix - server-side rules, MVP: simple flat rate)
-		// TODO: Call ShippingService for accurate per-shop shipping fee
-		// TODO: Call PromotionService for voucher validation & discount calculation
-		shippingFee := 30000.0  // MVP: flat 30k VND per shop
-		shippingDiscount := 0.0 // MVP: no freeship
-		voucherDiscount := 0.0  // MVP: no voucher
-
-		// Final amount
-		finalAmount := merchandiseSubtotal + shippingFee - shippingDiscount - voucherDiscount
-		if finalAmount < 0 {
-			finalAmount = 0
+	// Platform fee: % of merchandise (admin setting order.platform_fee_percent, default 5%),
+	// rounded half to even to whole VND. Read once per checkout so all shop orders use the
+	// same fee. Final amounts and shop earnings are computed here too, before any stock is held
+	platformFeePercent := s.settings.GetFloat("order", "platform_fee_percent", 5)
+	platformFees := make([]money.Money, len(allocations))
+	finalAmounts := make([]money.Money, len(allocations))
+	earningAmounts := make([]money.Money, len(allocations))
+	for i, allocation := range allocations {
+		fee, err := allocation.Subtotal.Percent(platformFeePercent)
+		if err != nil {
+			return nil, fmt.Errorf("invalid platform fee percent %v: %w", platformFeePercent, err)
+		}
+		finalAmount, err := allocation.finalAmount()
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount of shop %d: %w", allocation.ShopID, err)
+		}
+		earningAmount, err := finalAmount.Sub(fee)
+		if err != nil {
+			return nil, fmt.Errorf("invalid amount of shop %d: %w", allocation.ShopID, err)
 		}
+		platformFees[i] = fee
+		finalAmounts[i] = finalAmount
+		earningAmounts[i] = earningAmount.NonNegative()
+	}
+
+	checkoutID := newCheckoutID()
 
-		// Platform fee: 5% of merchandise
-		platformFee := merchandiseSubtotal * 0.05
+	// STEP 6a: Hold the stock of every shop before any shop_order exists, so two
+	// checkouts cannot both sell the last units (deducted once the order is paid)
+	if err := s.reserveCheckoutStock(ctx, checkoutID, shopIDs, itemsByShop); err != nil {
+		return nil, err
+	}
 
-		// Shop earning
-		earningAmount := finalAmount - platformFee
-		if earningAmount < 0 {
-			earningAmount = 0
+	// STEP 6b: Take a use of each voucher (checked against its limits under a row lock)
+	if err := s.vouchers.Redeem(ctx, checkoutID, vouchers); err != nil {
+		s.releaseCheckoutStock(checkoutID, shopIDs)
+		if errors.Is(err, domain.ErrVoucherUsedUp) || errors.Is(err, domain.ErrVoucherNotFound) {
+			return nil, err
 		}
+		return nil, fmt.Errorf("failed to redeem vouchers: %w", err)
+	}
 
-		// Generate order number
-		orderNumber := s.generateOrderNumber()
+	createdOrders := make([]*domain.Order, 0, len(itemsByShop))
+	orderNumbers := make([]string, 0, len(itemsByShop))
+	results := make([]domain.ShopCheckoutResult, 0, len(itemsByShop))
+
+	for i, shopID := range shopIDs {
+		shopItems := itemsByShop[shopID]
+		merchandiseSubtotal := allocations[i].Subtotal
+		shippingFee := allocations[i].ShippingFee
+		shippingDiscount := allocations[i].ShippingDiscount
+		voucherDiscount := allocations[i].VoucherDiscount
+		finalAmount := finalAmounts[i]
+		platformFee := platformFees[i]
+		earningAmount := earningAmounts[i] // Shop earning
 
 		// Create Order aggregate
 		order := &domain.Order{
-			OrderNumber:       orderNumber,
+			CheckoutID:        checkoutID,
 			UserID:            userID,
 			ShopID:            shopID,
-			ShippingAddressID: *req.ShippingAddressID,
+			ShippingAddressID: shippingAddressID,
 			Status:            domain.OrderStatusPending,
 
 			// Financial snapshot
-			MerchandiseSubtotal: merchandiseSubtotal,
-			ShippingFee:         shippingFee,
-			ShippingDiscount:    shippingDiscount,
-			VoucherDiscount:     voucherDiscount,
-			FinalAmount:         finalAmount,
-			PlatformFee:         platformFee,
-			EarningAmount:       earningAmount,
-
-			PaymentMethod: req.PaymentMethod,
+			MerchandiseSubtotal: merchandiseSubtotal.Float64(),
+			ShippingFee:         shippingFee.Float64(),
+			ShippingDiscount:    shippingDiscount.Float64(),
+			VoucherDiscount:     voucherDiscount.Float64(),
+			FinalAmount:         finalAmount.Float64(),
+			PlatformFee:         platformFee.Float64(),
+			EarningAmount:       earningAmount.Float64(),
+
+			PaymentMethod: paymentMethod,
 			OrderedAt:     time.Now(),
 
 			Items: make([]domain.OrderItem, 0, len(shopItems)),
 		}
 
-		// Set default payment method if not provided
-		if order.PaymentMethod == "" {
-			order.PaymentMethod = "COD"
-		}
+		// Snapshot shop name/logo (best effort - the order is still valid without them)
+		s.snapshotShop(ctx, order)
 
-		// Create OrderItems with snapshot price
+		// Create OrderItems with snapshot price and product info
 		for _, item := range shopItems {
 			sku := productItems[item.ProductItemID]
 
 			orderItem := domain.OrderItem{
 				ProductItemID:   item.ProductItemID,
+				ProductID:       sku.ProductID,
 				Quantity:        item.Quantity,
 				PriceAtPurchase: sku.Price, // Snapshot price from Product Service
+				ProductName:     sku.ProductName,
+				SKUCode:         sku.SKU,
+				ImageURL:        sku.ImageURL,
+				VariationLabel:  sku.VariationLabel,
+				FulfillmentType: domain.FulfillmentPhysical,
+			}
+			if sku.IsDigital {
+				orderItem.FulfillmentType = domain.FulfillmentDigital
 			}
 			order.Items = append(order.Items, orderItem)
 		}
 
 		// STEP 6: Save shop_order to database
-		if err := s.orderRepo.Create(order); err != nil {
+		if err := s.createShopOrder(ctx, order); err != nil {
 			s.logger.Error("failed to create shop_order",
+				zap.String("checkout_id", checkoutID),
 				zap.Uint("shop_id", shopID),
 				zap.Error(err))
-			// For MVP: fail fast if any shop order fails
-			// TODO: Consider partial success handling
-			return nil, fmt.Errorf("failed to create order for shop %d: %w", shopID, err)
+
+			results = append(results, domain.ShopCheckoutResult{ShopID: shopID, Error: err.Error()})
+			for _, skipped := range shopIDs[i+1:] {
+				results = append(results, domain.ShopCheckoutResult{ShopID: skipped, Error: "not attempted: checkout aborted"})
+			}
+			s.releaseCheckoutStock(checkoutID, shopIDs)
+			s.vouchers.Release(checkoutID, vouchers)
+			return nil, s.rollbackCheckout(checkoutID, createdOrders, results,
+				fmt.Errorf("failed to create order for shop %d: %w", shopID, err))
 		}
 
 		createdOrders = append(createdOrders, order)
-		orderNumbers = append(orderNumbers, orderNumber)
+		orderNumbers = append(orderNumbers, order.OrderNumber)
+		results = append(results, domain.ShopCheckoutResult{
+			ShopID:      shopID,
+			OrderID:     order.ID,
+			OrderNumber: order.OrderNumber,
+			Success:     true,
+		})
 
 		s.logger.Info("shop_order created",
 			zap.Uint("order_id", order.ID),
+			zap.String("checkout_id", checkoutID),
 			zap.Uint("shop_id", shopID),
-			zap.String("order_number", orderNumber),
+			zap.String("order_number", order.OrderNumber),
 			zap.Float64("final_amount", order.FinalAmount),
 			zap.Float64("platform_fee", order.PlatformFee),
 			zap.Float64("earning_amount", order.EarningAmount),
@@ -290,30 +489,25 @@ func (s *OrderService) CreateOrder(req *CreateOrderRequest) (*CreateOrderRespons
 		return nil, errors.New("failed to create any orders")
 	}
 
-	// STEP 7: Publish OrderCreated events (B7 fix - SYNC for MVP, no goroutine)
-	// TODO: Implement outbox pattern for reliable event delivery
+	// STEP 7: Publish OrderCreated events on the bounded worker pool
+	// The pool retries and logs failures; the order itself is already committed. Order events
+	// are not written to an outbox (product-service's outbox lives in its own database):
+	// the publisher parks events Kafka rejects in a Redis buffer and redelivers them, so only
+	// a crash between the commit and the publish loses one. payment-service does not depend on
+	// the event: it loads the order from order-service (GetOrder) when the buyer pays
 	for _, order := range createdOrders {
-		event := &domain.OrderEvent{
-			EventType: "order_created",
-			OrderID:   order.ID,
-			OrderData: order,
-			Timestamp: time.Now(),
-		}
+		event := domain.NewOrderEvent("order_created", order, nil)
 
-		if err := s.eventPublisher.PublishOrderEvent(event); err != nil {
-			s.logger.Error("failed to publish order_created event",
-				zap.Uint("order_id", order.ID),
-				zap.Uint("shop_id", order.ShopID),
-				zap.Error(err),
-			)
-			// For MVP: log error but don't fail the order
-			// TODO: Retry with outbox pattern
-		} else {
+		s.async.Submit(context.Background(), "publish_order_created", func(context.Context) error {
+			if err := s.eventPublisher.PublishOrderEvent(event); err != nil {
+				return err
+			}
 			s.logger.Info("order_created event published",
-				zap.Uint("order_id", order.ID),
+				zap.Uint("order_id", event.OrderID),
 				zap.Uint("shop_id", order.ShopID),
 			)
-		}
+			return nil
+		})
 	}
 
 	// STEP 8: Clear cart (B7 fix - SYNC, handle error)
@@ -328,27 +522,212 @@ func (s *OrderService) CreateOrder(req *CreateOrderRequest) (*CreateOrderRespons
 	return &CreateOrderResponse{
 		Orders:       createdOrders,
 		OrderNumbers: orderNumbers,
+		CheckoutID:   checkoutID,
+		Status:       domain.CheckoutStatusCompleted,
+		Shops:        results,
 	}, nil
 }
 
-// GetOrder retrieves an order by ID
-func (s *OrderService) GetOrder(orderID uint) (*domain.Order, error) {
+// snapshotShop copies the shop's name and logo from Identity Service onto the order
+func (s *OrderService) snapshotShop(ctx context.Context, order *domain.Order) {
+	if s.shops == nil {
+		return
+	}
+
+	shop, err := s.shops.GetShop(ctx, order.ShopID)
+	if err != nil {
+		s.logger.Warn("failed to load shop for order snapshot",
+			zap.Uint("shop_id", order.ShopID),
+			zap.Error(err),
+		)
+		return
+	}
+
+	order.ShopName = shop.Name
+	order.ShopLogoURL = shop.LogoURL
+}
+
+// createShopOrder saves a shop_order with a new order number, retrying with another
+// number if it collides
+func (s *OrderService) createShopOrder(ctx context.Context, order *domain.Order) error {
+	order.OrderNumber = s.generateOrderNumber(ctx)
+	err := s.orderRepo.Create(order)
+	for attempt := 1; errors.Is(err, domain.ErrDuplicateOrderNumber) && attempt < maxOrderNumberAttempts; attempt++ {
+		s.logger.Warn("order number collision, retrying",
+			zap.String("order_number", order.OrderNumber),
+			zap.Int("attempt", attempt),
+		)
+		order.ID = 0
+		order.OrderNumber = s.generateOrderNumber(ctx)
+		err = s.orderRepo.Create(order)
+	}
+	return err
+}
+
+// rollbackCheckout undoes the shop_orders of a checkout after a sibling failed
+// Nothing has been published for them yet, so they are deleted; an order that cannot
+// be deleted is cancelled instead and an order_cancelled compensation event is
+// published so consumers can release anything they tied to it (checkout is PARTIAL)
+func (s *OrderService) rollbackCheckout(checkoutID string, created []*domain.Order, results []domain.ShopCheckoutResult, cause error) error {
+	status := domain.CheckoutStatusFailed
+
+	// created[i] is results[i]: results are appended in the same shop order
+	for i, order := range created {
+		result := &results[i]
+		result.Success = false
+
+		err := s.orderRepo.Delete(order)
+		if err == nil {
+			result.RolledBack = true
+			continue
+		}
+
+		status = domain.CheckoutStatusPartial
+		s.logger.Error("failed to roll back shop_order, cancelling",
+			zap.String("checkout_id", checkoutID),
+			zap.Uint("order_id", order.ID),
+			zap.Error(err),
+		)
+
+		// Apply sets the new status and an updated_at newer than the order_created snapshot
+		if _, err := s.statuses.Apply(order, domain.OrderStatusCancelled, domain.StatusActorSystem, 0, "checkout_rollback", nil); err != nil {
+			s.logger.Error("failed to cancel shop_order of failed checkout",
+				zap.String("checkout_id", checkoutID),
+				zap.Uint("order_id", order.ID),
+				zap.Error(err),
+			)
+			result.Error = "rollback failed: " + err.Error()
+		} else {
+			result.Cancelled = true
+		}
+
+		event := domain.NewOrderEvent("order_cancelled", order, map[string]interface{}{
+			"reason":      "checkout_rollback",
+			"checkout_id": checkoutID,
+		})
+		s.async.Submit(context.Background(), "publish_order_cancelled", func(context.Context) error {
+			return s.eventPublisher.PublishOrderEvent(event)
+		})
+	}
+
+	s.logger.Warn("checkout failed",
+		zap.String("checkout_id", checkoutID),
+		zap.String("status", string(status)),
+		zap.Int("rolled_back", len(created)),
+		zap.Error(cause),
+	)
+
+	return &domain.CheckoutError{
+		CheckoutID: checkoutID,
+		Status:     status,
+		Shops:      results,
+		Err:        cause,
+	}
+}
+
+// newCheckoutID returns an ID shared by the shop_orders of one checkout:
+// CHK-YYYYMMDD- + 16 hex chars
+func newCheckoutID() string {
+	buf := make([]byte, 8)
+	rand.Read(buf) // never fails since Go 1.24
+	return fmt.Sprintf("CHK-%s-%x", time.Now().Format("20060102"), buf)
+}
+
+// GetOrder retrieves an order by ID with its status and adjustment history and current totals
+// Issued digital codes are included only when viewerID is the buyer
+func (s *OrderService) GetOrder(ctx context.Context, orderID, viewerID uint) (*domain.Order, error) {
 	order, err := s.orderRepo.GetByID(orderID)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NotFound("order not found")
+		}
 		return nil, fmt.Errorf("failed to get order: %w", err)
 	}
+	if err := s.attachHistory(order); err != nil {
+		return nil, err
+	}
+	s.attachDigitalCodes(ctx, order, viewerID)
 	return order, nil
 }
 
-// GetOrderByOrderNumber retrieves an order by order number
-func (s *OrderService) GetOrderByOrderNumber(orderNumber string) (*domain.Order, error) {
+// GetOrderByOrderNumber retrieves an order by order number with its status and adjustment
+// history and current totals
+// Issued digital codes are included only when viewerID is the buyer
+func (s *OrderService) GetOrderByOrderNumber(ctx context.Context, orderNumber string, viewerID uint) (*domain.Order, error) {
 	order, err := s.orderRepo.GetByOrderNumber(orderNumber)
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NotFound("order not found")
+		}
 		return nil, fmt.Errorf("failed to get order: %w", err)
 	}
+	if err := s.attachHistory(order); err != nil {
+		return nil, err
+	}
+	s.attachDigitalCodes(ctx, order, viewerID)
 	return order, nil
 }
 
+// attachHistory loads the order's status changes and adjustments and derives its current totals
+func (s *OrderService) attachHistory(order *domain.Order) error {
+	history, err := s.orderRepo.ListStatusHistory(order.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get order status history: %w", err)
+	}
+	order.StatusHistory = history
+	return s.attachAdjustments(order)
+}
+
+// CountOpenOrdersByProductItem counts unfinished orders containing a SKU
+// Used by product-service before it discontinues (deletes) a SKU
+func (s *OrderService) CountOpenOrdersByProductItem(productItemID uint) (int64, error) {
+	count, err := s.orderRepo.CountOpenByProductItem(productItemID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count open orders: %w", err)
+	}
+	return count, nil
+}
+
+// PurchaseVerification tells whether a buyer received a product, and in which order
+type PurchaseVerification struct {
+	Purchased   bool   `json:"purchased"`
+	OrderID     uint   `json:"order_id,omitempty"`
+	OrderNumber string `json:"order_number,omitempty"`
+	ShopID      uint   `json:"shop_id,omitempty"`
+}
+
+// VerifyPurchase checks that the user has a delivered order containing the product
+// Used by product-service before it accepts a review (verified purchase)
+func (s *OrderService) VerifyPurchase(userID, productID uint) (*PurchaseVerification, error) {
+	order, err := s.orderRepo.LatestDeliveredWithProduct(userID, productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &PurchaseVerification{}, nil
+		}
+		return nil, fmt.Errorf("failed to verify purchase: %w", err)
+	}
+	return &PurchaseVerification{
+		Purchased:   true,
+		OrderID:     order.ID,
+		OrderNumber: order.OrderNumber,
+		ShopID:      order.ShopID,
+	}, nil
+}
+
+// ShopGMV returns the GMV per shop of the orders placed from the first to the last day
+// (YYYY-MM-DD, included, at most one year); used by identity-service for the shop export
+func (s *OrderService) ShopGMV(from, to string) ([]domain.ShopGMV, error) {
+	start, end, err := JournalPeriod(from, to)
+	if err != nil {
+		return nil, err
+	}
+	gmv, err := s.orderRepo.SumGMVByShop(start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum shop GMV: %w", err)
+	}
+	return gmv, nil
+}
+
 // ListOrders retrieves orders for a user or session
 func (s *OrderService) ListOrders(userID *uint, sessionID string, limit, offset int) ([]*domain.Order, int64, error) {
 	var orders []*domain.Order
@@ -360,7 +739,7 @@ func (s *OrderService) ListOrders(userID *uint, sessionID string, limit, offset
 	} else if sessionID != "" {
 		orders, total, err = s.orderRepo.GetBySessionID(sessionID, limit, offset)
 	} else {
-		return nil, 0, errors.New("user_id or session_id is required")
+		return nil, 0, domain.Validation("user_id or session_id is required")
 	}
 
 	if err != nil {
@@ -370,12 +749,22 @@ func (s *OrderService) ListOrders(userID *uint, sessionID string, limit, offset
 	return orders, total, nil
 }
 
-// generateOrderNumber generates a unique order number
-// Format: ORD-YYYYMMDD-HHMMSS-XXXX (where XXXX is a random 4-digit number)
-func (s *OrderService) generateOrderNumber() string {
-	now := time.Now()
-	timestamp := now.Format("20060102-150405")
-	// Simple random number (in production, use crypto/rand)
-	random := now.Nanosecond() % 10000
-	return fmt.Sprintf("ORD-%s-%04d", timestamp, random)
+// generateOrderNumber returns the next order number from the Redis sequence
+// If Redis is unavailable it falls back to ORD-YYYYMMDD-R + 10 random base32 chars
+// (50 bits of crypto randomness); the unique index plus retry covers the rest
+func (s *OrderService) generateOrderNumber(ctx context.Context) string {
+	number, err := s.orderNumbers.Next(ctx)
+	if err == nil {
+		return number
+	}
+
+	s.logger.Warn("order sequence unavailable, using random order number", zap.Error(err))
+
+	const alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ" // Crockford base32
+	buf := make([]byte, 10)
+	rand.Read(buf) // never fails since Go 1.24
+	for i := range buf {
+		buf[i] = alphabet[buf[i]%32]
+	}
+	return fmt.Sprintf("ORD-%s-R%s", time.Now().Format("20060102"), buf)
 }
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"order-service/internal/domain"
+	"order-service/internal/repository/postgres"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// OrderStatusShopClient resolves shop owners from Identity Service (seller status changes)
+type OrderStatusShopClient interface {
+	GetShopOwner(ctx context.Context, shopID uint) (uint, error)
+}
+
+// OrderStatusActor is the user changing an order's status (X-User-Id / X-User-Role from API Gateway)
+type OrderStatusActor struct {
+	UserID uint
+	Role   string
+}
+
+// ChangeOrderStatusRequest is the body of PUT /orders/:id/status
+type ChangeOrderStatusRequest struct {
+	Status string `json:"status" binding:"required"` // confirmed, shipped, delivered or cancelled
+	Reason string `json:"reason,omitempty" binding:"max=255"`
+}
+
+// OrderStatusService moves orders through the status state machine (see domain.Order.CheckTransition)
+// Every change is recorded in order_status_history and published as an order_status_changed
+// event, whether requested through the API or made by the system (payment, fulfillment).
+// The order's stock reservation is settled with the change (see settleStock)
+type OrderStatusService struct {
+	orderRepo      *postgres.OrderRepository
+	shops          OrderStatusShopClient
+	stock          OrderStockClient
+	eventPublisher domain.OrderEventPublisher
+	async          AsyncRunner
+	logger         *zap.Logger
+}
+
+// NewOrderStatusService creates a new order status service
+func NewOrderStatusService(
+	orderRepo *postgres.OrderRepository,
+	shops OrderStatusShopClient,
+	stock OrderStockClient,
+	eventPublisher domain.OrderEventPublisher,
+	async AsyncRunner,
+	logger *zap.Logger,
+) *OrderStatusService {
+	return &OrderStatusService{
+		orderRepo:      orderRepo,
+		shops:          shops,
+		stock:          stock,
+		eventPublisher: eventPublisher,
+		async:          async,
+		logger:         logger,
+	}
+}
+
+// ChangeStatus moves an order to a new status on behalf of its buyer, its seller or an admin
+// The buyer can only cancel an unpaid order; the seller confirms, ships, delivers
+// or cancels an unpaid order; paid is only set by the payment confirmation
+func (s *OrderStatusService) ChangeStatus(ctx context.Context, actor OrderStatusActor, orderID uint, req *ChangeOrderStatusRequest) (*domain.Order, error) {
+	to := domain.OrderStatus(strings.ToLower(strings.TrimSpace(req.Status)))
+	if !slices.Contains(domain.OrderStatuses, to) {
+		return nil, domain.ErrInvalidOrderStatus
+	}
+
+	order, err := s.orderRepo.GetByID(orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NotFound("order not found")
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	role, err := s.actorRole(ctx, actor, order)
+	if err != nil {
+		return nil, err
+	}
+	if to == domain.OrderStatusPaid || (role == domain.StatusActorBuyer && to != domain.OrderStatusCancelled) {
+		return nil, domain.ErrStatusChangeForbidden
+	}
+	if err := order.CheckTransition(to); err != nil {
+		return nil, err
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	changed, err := s.Apply(order, to, role, actor.UserID, reason, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !changed {
+		// Paid, cancelled or moved on by someone else meanwhile
+		return nil, domain.ErrInvalidStatusTransition
+	}
+
+	// Lifecycle events, as the system changes publish them (search-service sales use order_cancelled)
+	switch to {
+	case domain.OrderStatusCancelled:
+		event := domain.NewOrderEvent("order_cancelled", order, map[string]interface{}{
+			"reason": "cancelled_by_" + role,
+			"note":   reason,
+		})
+		s.async.Submit(context.Background(), "publish_order_cancelled", func(context.Context) error {
+			return s.eventPublisher.PublishOrderEvent(event)
+		})
+	case domain.OrderStatusDelivered:
+		event := domain.NewOrderEvent("order_delivered", order, nil)
+		s.async.Submit(context.Background(), "publish_order_delivered", func(context.Context) error {
+			return s.eventPublisher.PublishOrderEvent(event)
+		})
+	}
+	return order, nil
+}
+
+// Apply moves the order from its current status to status to, records the change and
+// publishes order_status_changed. updates are extra columns written with the status
+// (e.g. paid_at). The state machine is not checked here: callers check it first
+// Reports false (nothing written) if the order's status changed concurrently
+func (s *OrderStatusService) Apply(order *domain.Order, to domain.OrderStatus, actor string, changedBy uint, reason string, updates map[string]interface{}) (bool, error) {
+	change := &domain.OrderStatusHistory{
+		FromStatus: order.Status,
+		ToStatus:   to,
+		Actor:      actor,
+		ChangedBy:  changedBy,
+		Reason:     reason,
+	}
+	changed, err := s.orderRepo.TransitionStatus(order, change, updates)
+	if err != nil {
+		return false, fmt.Errorf("failed to change order status: %w", err)
+	}
+	if !changed {
+		return false, nil
+	}
+
+	order.Status = to
+	order.UpdatedAt = change.CreatedAt
+	s.logger.Info("order status changed",
+		zap.Uint("order_id", order.ID),
+		zap.String("order_number", order.OrderNumber),
+		zap.String("from", string(change.FromStatus)),
+		zap.String("to", string(change.ToStatus)),
+		zap.String("actor", actor),
+		zap.Uint("changed_by", changedBy),
+	)
+	s.settleStock(order, change.FromStatus, to)
+
+	event := domain.NewOrderEvent("order_status_changed", order, domain.OrderStatusChangedMetadata{
+		FromStatus: change.FromStatus,
+		ToStatus:   change.ToStatus,
+		Actor:      actor,
+		ChangedBy:  changedBy,
+		Reason:     reason,
+	})
+	s.async.Submit(context.Background(), "publish_order_status_changed", func(context.Context) error {
+		return s.eventPublisher.PublishOrderEvent(event)
+	})
+	return true, nil
+}
+
+// actorRole returns which side the actor is on; "order not found" if the actor has no access
+func (s *OrderStatusService) actorRole(ctx context.Context, actor OrderStatusActor, order *domain.Order) (string, error) {
+	switch {
+	case actor.Role == "ADMIN":
+		return domain.StatusActorAdmin, nil
+	case actor.UserID == order.UserID:
+		return domain.StatusActorBuyer, nil
+	}
+
+	ownerID, err := s.shops.GetShopOwner(ctx, order.ShopID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get shop: %w", err)
+	}
+	if ownerID == 0 || ownerID != actor.UserID {
+		return "", domain.NotFound("order not found")
+	}
+	return domain.StatusActorSeller, nil
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"order-service/internal/domain"
+
+	"go.uber.org/zap"
+)
+
+// OrderStockClient holds and takes stock in Product Service (implemented by OrderProductClientAdapter)
+// Stock is reserved per shop_order at checkout, deducted once the order is paid (or
+// confirmed for cash on delivery) and released if the order is not created or is cancelled.
+// Reservations not deducted or released expire on their own in product-service
+type OrderStockClient interface {
+	// ReserveStock holds the quantities by product item ID under reservationID
+	// Returns domain.ErrInsufficientStock if any item is short (nothing stays reserved)
+	ReserveStock(ctx context.Context, reservationID string, quantities map[uint]int) error
+
+	// DeductStock permanently takes the quantities and releases the reservation
+	// (idempotent per reservation and item)
+	DeductStock(ctx context.Context, reservationID string, quantities map[uint]int) error
+
+	// ReleaseStock gives the reserved quantities back (idempotent)
+	ReleaseStock(ctx context.Context, reservationID string) error
+}
+
+// stockReservationID is the reservation of one shop's items of a checkout
+// It is known before the shop_order (and its order number) exists, so stock is held
+// before anything is written
+func stockReservationID(checkoutID string, shopID uint) string {
+	return fmt.Sprintf("%s-S%d", checkoutID, shopID)
+}
+
+// orderReservationID returns the reservation of an order's stock, empty for orders
+// created before checkouts reserved stock
+func orderReservationID(order *domain.Order) string {
+	if order.CheckoutID == "" {
+		return ""
+	}
+	return stockReservationID(order.CheckoutID, order.ShopID)
+}
+
+// stockQuantities sums cart items by product item ID
+func stockQuantities(items []*domain.CartItem) map[uint]int {
+	quantities := make(map[uint]int, len(items))
+	for _, item := range items {
+		quantities[item.ProductItemID] += item.Quantity
+	}
+	return quantities
+}
+
+// physicalQuantities sums the order's shipped items by product item ID
+// Digital items are not deducted: their stock follows the codes left, which issuing takes
+func physicalQuantities(order *domain.Order) map[uint]int {
+	quantities := make(map[uint]int, len(order.Items))
+	for i := range order.Items {
+		if !order.Items[i].IsDigital() {
+			quantities[order.Items[i].ProductItemID] += order.Items[i].Quantity
+		}
+	}
+	return quantities
+}
+
+// reserveCheckoutStock reserves the items of every shop of a checkout before its
+// shop_orders are created. If a shop's items cannot be reserved, the shops reserved
+// before it are released and nothing is held
+func (s *OrderService) reserveCheckoutStock(ctx context.Context, checkoutID string, shopIDs []uint, itemsByShop map[uint][]*domain.CartItem) error {
+	for i, shopID := range shopIDs {
+		err := s.productClient.ReserveStock(ctx, stockReservationID(checkoutID, shopID), stockQuantities(itemsByShop[shopID]))
+		if err == nil {
+			continue
+		}
+
+		s.logger.Warn("failed to reserve checkout stock",
+			zap.String("checkout_id", checkoutID),
+			zap.Uint("shop_id", shopID),
+			zap.Error(err),
+		)
+		s.releaseCheckoutStock(checkoutID, shopIDs[:i])
+		if errors.Is(err, domain.ErrInsufficientStock) {
+			return err
+		}
+		return fmt.Errorf("failed to reserve stock: %w", err)
+	}
+	return nil
+}
+
+// releaseCheckoutStock releases the reservations of the given shops of a checkout
+// Released on the worker pool with retries; a release that never succeeds still expires
+func (s *OrderService) releaseCheckoutStock(checkoutID string, shopIDs []uint) {
+	for _, shopID := range shopIDs {
+		reservationID := stockReservationID(checkoutID, shopID)
+		s.async.Submit(context.Background(), "release_stock", func(ctx context.Context) error {
+			return s.productClient.ReleaseStock(ctx, reservationID)
+		})
+	}
+}
+
+// settleStock deducts or releases the order's reservation after its status changed
+// The stock is deducted once the order is paid, or confirmed unpaid for cash on delivery,
+// and released when the order is cancelled
+func (s *OrderStatusService) settleStock(order *domain.Order, from, to domain.OrderStatus) {
+	reservationID := orderReservationID(order)
+	if reservationID == "" || s.stock == nil {
+		return
+	}
+
+	switch {
+	case to == domain.OrderStatusCancelled:
+		s.async.Submit(context.Background(), "release_stock", func(ctx context.Context) error {
+			return s.stock.ReleaseStock(ctx, reservationID)
+		})
+	case from == domain.OrderStatusPending && (to == domain.OrderStatusPaid || to == domain.OrderStatusConfirmed):
+		quantities := physicalQuantities(order)
+		s.async.Submit(context.Background(), "deduct_stock", func(ctx context.Context) error {
+			if len(quantities) == 0 {
+				return s.stock.ReleaseStock(ctx, reservationID)
+			}
+			err := s.stock.DeductStock(ctx, reservationID, quantities)
+			if errors.Is(err, domain.ErrInsufficientStock) {
+				// Retrying cannot help: the reservation expired and the stock was sold meanwhile
+				s.logger.Error("stock short for paid order",
+					zap.Uint("order_id", order.ID),
+					zap.String("order_number", order.OrderNumber),
+					zap.String("reservation_id", reservationID),
+				)
+				return nil
+			}
+			return err
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"order-service/internal/repository/postgres"
+
+	"go.uber.org/zap"
+)
+
+// JobTypeOrderPartitions creates the upcoming monthly partitions of the order tables (no payload)
+const JobTypeOrderPartitions = "order:partitions"
+
+// PartitionService keeps the monthly partitions of shop_order and order_line ahead of
+// new orders: an order placed in a month without a partition cannot be stored
+type PartitionService struct {
+	orderRepo   *postgres.OrderRepository
+	monthsAhead int
+	logger      *zap.Logger
+}
+
+// NewPartitionService creates a new partition service
+func NewPartitionService(orderRepo *postgres.OrderRepository, monthsAhead int, logger *zap.Logger) *PartitionService {
+	return &PartitionService{
+		orderRepo:   orderRepo,
+		monthsAhead: monthsAhead,
+		logger:      logger,
+	}
+}
+
+// HandlePartitions is the job handler for JobTypeOrderPartitions
+// Safe to run repeatedly: existing partitions are kept
+func (s *PartitionService) HandlePartitions(ctx context.Context, _ []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := s.orderRepo.EnsureOrderPartitions(s.monthsAhead); err != nil {
+		return fmt.Errorf("failed to create order partitions: %w", err)
+	}
+	s.logger.Debug("order partitions ensured", zap.Int("months_ahead", s.monthsAhead))
+	return nil
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"order-service/internal/domain"
+	"order-service/internal/repository/postgres"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// JobTypePayoutBatch creates the daily payout batches (payload: PayoutBatchPayload)
+const JobTypePayoutBatch = "payout:daily_batch"
+
+// PayoutBatchPayload selects the day to batch; empty means yesterday
+type PayoutBatchPayload struct {
+	Date string `json:"date,omitempty"` // YYYY-MM-DD
+}
+
+// PayoutService batches shop earnings for payout
+type PayoutService struct {
+	orderRepo  *postgres.OrderRepository
+	payoutRepo *postgres.PayoutRepository
+	logger     *zap.Logger
+}
+
+// NewPayoutService creates a new payout service
+func NewPayoutService(orderRepo *postgres.OrderRepository, payoutRepo *postgres.PayoutRepository, logger *zap.Logger) *PayoutService {
+	return &PayoutService{
+		orderRepo:  orderRepo,
+		payoutRepo: payoutRepo,
+		logger:     logger,
+	}
+}
+
+// HandleDailyBatch is the job handler for JobTypePayoutBatch
+// Safe to run repeatedly: existing batches for the same shop and day are skipped
+func (s *PayoutService) HandleDailyBatch(ctx context.Context, payload []byte) error {
+	var p PayoutBatchPayload
+	if len(payload) > 0 && string(payload) != "null" {
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return fmt.Errorf("invalid payout batch payload: %w", err)
+		}
+	}
+
+	day := time.Now().AddDate(0, 0, -1)
+	if p.Date != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", p.Date, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid payout date: %w", err)
+		}
+		day = parsed
+	}
+
+	_, err := s.CreateBatches(ctx, day)
+	return err
+}
+
+// CreateBatches creates one payout batch per shop for orders delivered on the given day
+// The shop's pending payout adjustments are applied to its batch; a shop without
+// deliveries that day keeps them for its next batch
+func (s *PayoutService) CreateBatches(ctx context.Context, day time.Time) (int, error) {
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	end := start.AddDate(0, 0, 1)
+
+	earnings, err := s.orderRepo.SumEarningsByShop(start, end)
+	if err != nil {
+		return 0, fmt.Errorf("failed to aggregate earnings: %w", err)
+	}
+
+	created := 0
+	for _, e := range earnings {
+		if err := ctx.Err(); err != nil {
+			return created, err
+		}
+
+		// Pending adjustments (e.g. the seller's share of dispute refunds) go into this batch
+		adjustments, err := s.payoutRepo.ListPendingAdjustments(e.ShopID)
+		if err != nil {
+			return created, fmt.Errorf("failed to load payout adjustments for shop %d: %w", e.ShopID, err)
+		}
+		adjustmentIDs := make([]uint, 0, len(adjustments))
+		adjustmentAmount := 0.0
+		for _, a := range adjustments {
+			adjustmentIDs = append(adjustmentIDs, a.ID)
+			adjustmentAmount += a.Amount
+		}
+
+		batch := &domain.PayoutBatch{
+			ShopID:           e.ShopID,
+			PeriodStart:      start,
+			PeriodEnd:        end,
+			OrderCount:       e.OrderCount,
+			TotalAmount:      e.TotalAmount,
+			PlatformFee:      e.PlatformFee,
+			EarningAmount:    e.EarningAmount + adjustmentAmount,
+			AdjustmentAmount: adjustmentAmount,
+			Status:           domain.PayoutStatusPending,
+		}
+		ok, err := s.payoutRepo.CreateWithAdjustments(batch, adjustmentIDs)
+		if err != nil {
+			return created, fmt.Errorf("failed to create payout batch for shop %d: %w", e.ShopID, err)
+		}
+		if ok {
+			created++
+		}
+	}
+
+	s.logger.Info("payout batches created",
+		zap.Time("period_start", start),
+		zap.Int("shops", len(earnings)),
+		zap.Int("created", created),
+	)
+	return created, nil
+}
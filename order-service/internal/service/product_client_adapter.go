@@ -1,6 +1,11 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"net/http"
+
+	"order-service/internal/domain"
 	"order-service/pkg/product_client"
 )
 
@@ -12,62 +17,36 @@ type CartProductClientAdapter struct {
 
 // GetProductItem fetches single product item details (SKU-level) - for CartService display
 // Returns display-only DTO without validation fields
-func (a *CartProductClientAdapter) GetProductItem(productItemID uint) (*ProductItemDTO, error) {
-	items, err := a.Client.GetProductItems([]uint{productItemID})
+func (a *CartProductClientAdapter) GetProductItem(ctx context.Context, productItemID uint) (*ProductItemDTO, error) {
+	items, err := a.GetProductItems(ctx, []uint{productItemID})
 	if err != nil {
 		return nil, err
 	}
-
-	item, exists := items[productItemID]
-	if !exists {
-		return nil, nil
-	}
-
-	var productName string
-	var shopID uint
-	if item.Product != nil {
-		productName = item.Product.Name
-		shopID = item.Product.ShopID
-	}
-
-	return &ProductItemDTO{
-		ID:          item.ID,
-		SKUCode:     item.SKUCode,
-		QtyInStock:  item.QtyInStock,
-		ProductName: productName,
-		Price:       item.Price,
-		ImageURL:    item.ImageURL,
-		Status:      item.Status,
-		ShopID:      shopID,
-	}, nil
+	return items[productItemID], nil
 }
 
 // GetProductItems fetches multiple product items in batch - for CartService display
 // Returns display-only DTOs without validation fields
-func (a *CartProductClientAdapter) GetProductItems(productItemIDs []uint) (map[uint]*ProductItemDTO, error) {
-	items, err := a.Client.GetProductItems(productItemIDs)
+func (a *CartProductClientAdapter) GetProductItems(ctx context.Context, productItemIDs []uint) (map[uint]*ProductItemDTO, error) {
+	items, err := a.Client.GetProductItems(ctx, productItemIDs)
 	if err != nil {
 		return nil, err
 	}
 
-	result := make(map[uint]*ProductItemDTO)
+	result := make(map[uint]*ProductItemDTO, len(items))
 	for id, item := range items {
-		var productName string
-		var shopID uint
-		if item.Product != nil {
-			productName = item.Product.Name
-			shopID = item.Product.ShopID
-		}
-
 		result[id] = &ProductItemDTO{
 			ID:          item.ID,
+			ProductID:   item.ProductID,
 			SKUCode:     item.SKUCode,
 			QtyInStock:  item.QtyInStock,
-			ProductName: productName,
+			ProductName: item.ProductName(),
 			Price:       item.Price,
 			ImageURL:    item.ImageURL,
 			Status:      item.Status,
-			ShopID:      shopID,
+			ShopID:      item.ShopID(),
+
+			MaxPurchaseQuantity: item.MaxPurchaseQuantity(),
 		}
 	}
 
@@ -82,66 +61,108 @@ type OrderProductClientAdapter struct {
 
 // GetProductItem fetches single product item details (SKU-level) - for OrderService validation
 // Returns full DTO with validation fields (Stock, IsActive)
-func (a *OrderProductClientAdapter) GetProductItem(productItemID uint) (*OrderProductItemDTO, error) {
-	items, err := a.Client.GetProductItems([]uint{productItemID})
+func (a *OrderProductClientAdapter) GetProductItem(ctx context.Context, productItemID uint) (*OrderProductItemDTO, error) {
+	items, err := a.GetProductItems(ctx, []uint{productItemID})
 	if err != nil {
 		return nil, err
 	}
-
-	item, exists := items[productItemID]
-	if !exists {
-		return nil, nil
-	}
-
-	var productName string
-	var shopID uint
-	if item.Product != nil {
-		productName = item.Product.Name
-		shopID = item.Product.ShopID
-	}
-
-	return &OrderProductItemDTO{
-		ID:          item.ID,
-		ProductID:   item.ProductID,
-		ShopID:      shopID,
-		ProductName: productName,
-		SKU:         item.SKUCode,
-		Price:       item.Price,
-		Stock:       item.QtyInStock,
-		ImageURL:    item.ImageURL,
-		IsActive:    item.Status == "active",
-	}, nil
+	return items[productItemID], nil
 }
 
 // GetProductItems fetches multiple product items in batch - for OrderService validation
 // Returns full DTOs with validation fields
-func (a *OrderProductClientAdapter) GetProductItems(productItemIDs []uint) (map[uint]*OrderProductItemDTO, error) {
-	items, err := a.Client.GetProductItems(productItemIDs)
+func (a *OrderProductClientAdapter) GetProductItems(ctx context.Context, productItemIDs []uint) (map[uint]*OrderProductItemDTO, error) {
+	items, err := a.Client.GetProductItems(ctx, productItemIDs)
 	if err != nil {
 		return nil, err
 	}
 
-	result := make(map[uint]*OrderProductItemDTO)
+	result := make(map[uint]*OrderProductItemDTO, len(items))
 	for id, item := range items {
-		var productName string
-		var shopID uint
-		if item.Product != nil {
-			productName = item.Product.Name
-			shopID = item.Product.ShopID
-		}
-
 		result[id] = &OrderProductItemDTO{
 			ID:          item.ID,
 			ProductID:   item.ProductID,
-			ShopID:      shopID,
-			ProductName: productName,
+			ShopID:      item.ShopID(),
+			ProductName: item.ProductName(),
 			SKU:         item.SKUCode,
 			Price:       item.Price,
 			Stock:       item.QtyInStock,
 			ImageURL:    item.ImageURL,
-			IsActive:    item.Status == "active",
+			IsActive:    item.IsActive(),
+
+			MaxPurchaseQuantity: item.MaxPurchaseQuantity(),
+			VariationLabel:      item.VariationLabel(),
+			IsDigital:           item.IsDigital(),
 		}
 	}
 
 	return result, nil
 }
+
+// IssueDigitalCodes issues the codes of an order's digital items - for OrderService fulfillment
+func (a *OrderProductClientAdapter) IssueDigitalCodes(ctx context.Context, orderNumber string, quantities map[uint]int) (map[uint][]string, error) {
+	items := make([]product_client.DigitalCodeItem, 0, len(quantities))
+	for productItemID, quantity := range quantities {
+		items = append(items, product_client.DigitalCodeItem{ProductItemID: productItemID, Quantity: quantity})
+	}
+
+	codes, err := a.Client.IssueDigitalCodes(ctx, orderNumber, items)
+	if err != nil {
+		return nil, err
+	}
+	return groupDigitalCodes(codes), nil
+}
+
+// GetDigitalCodes returns the codes issued to an order - for the order detail
+func (a *OrderProductClientAdapter) GetDigitalCodes(ctx context.Context, orderNumber string) (map[uint][]string, error) {
+	codes, err := a.Client.GetDigitalCodes(ctx, orderNumber)
+	if err != nil {
+		return nil, err
+	}
+	return groupDigitalCodes(codes), nil
+}
+
+// ReserveStock holds stock for a checkout - for OrderService checkout
+// Returns domain.ErrInsufficientStock if any item is short (nothing stays reserved)
+func (a *OrderProductClientAdapter) ReserveStock(ctx context.Context, reservationID string, quantities map[uint]int) error {
+	err := a.Client.ReserveStock(ctx, reservationID, stockItems(quantities))
+	var apiErr *product_client.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+		return domain.ErrInsufficientStock
+	}
+	return err
+}
+
+// DeductStock takes the stock of a paid order - for order status changes
+// Returns domain.ErrInsufficientStock if an item is short (e.g. its reservation expired)
+func (a *OrderProductClientAdapter) DeductStock(ctx context.Context, reservationID string, quantities map[uint]int) error {
+	err := a.Client.DeductStock(ctx, reservationID, stockItems(quantities))
+	var apiErr *product_client.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+		return domain.ErrInsufficientStock
+	}
+	return err
+}
+
+// ReleaseStock gives reserved stock back - for failed checkouts and cancelled orders
+func (a *OrderProductClientAdapter) ReleaseStock(ctx context.Context, reservationID string) error {
+	return a.Client.ReleaseStock(ctx, reservationID)
+}
+
+// stockItems converts quantities by product item ID to stock request items
+func stockItems(quantities map[uint]int) []product_client.StockItem {
+	items := make([]product_client.StockItem, 0, len(quantities))
+	for productItemID, quantity := range quantities {
+		items = append(items, product_client.StockItem{ProductItemID: productItemID, Quantity: quantity})
+	}
+	return items
+}
+
+// groupDigitalCodes groups codes by product item ID, keeping their order
+func groupDigitalCodes(codes []product_client.DigitalCode) map[uint][]string {
+	result := make(map[uint][]string)
+	for _, code := range codes {
+		result[code.ProductItemID] = append(result[code.ProductItemID], code.Code)
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"order-service/internal/repository/postgres"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// JobTypeOrderRetention anonymizes buyer PII on old finished orders (no payload)
+const JobTypeOrderRetention = "order:retention"
+
+// retentionBatchSize bounds the orders updated per statement
+const retentionBatchSize = 500
+
+// RetentionService applies the order data-retention policy
+// Windows are admin settings (scope "order"): retention_enabled, retention_days
+type RetentionService struct {
+	orderRepo *postgres.OrderRepository
+	settings  SettingsReader
+	logger    *zap.Logger
+}
+
+// NewRetentionService creates a new retention service
+func NewRetentionService(orderRepo *postgres.OrderRepository, settings SettingsReader, logger *zap.Logger) *RetentionService {
+	return &RetentionService{
+		orderRepo: orderRepo,
+		settings:  settings,
+		logger:    logger,
+	}
+}
+
+// HandleRetention is the job handler for JobTypeOrderRetention
+func (s *RetentionService) HandleRetention(ctx context.Context, _ []byte) error {
+	if !s.settings.GetBool("order", "retention_enabled", false) {
+		return nil
+	}
+
+	days := s.settings.GetInt("order", "retention_days", 730)
+	if days <= 0 {
+		s.logger.Warn("order retention skipped, retention_days must be positive", zap.Int("retention_days", days))
+		return nil
+	}
+
+	_, err := s.AnonymizeOlderThan(ctx, time.Now().AddDate(0, 0, -days))
+	return err
+}
+
+// AnonymizeOlderThan removes buyer PII from finished orders placed before cutoff,
+// archived ones included
+// Financial fields, shop and product snapshots are kept for reporting and payouts
+func (s *RetentionService) AnonymizeOlderThan(ctx context.Context, cutoff time.Time) (int64, error) {
+	var total int64
+	for _, anonymize := range []func(time.Time, int) (int64, error){
+		s.orderRepo.AnonymizeFinishedBefore,
+		s.orderRepo.AnonymizeArchivedBefore,
+	} {
+		for {
+			if err := ctx.Err(); err != nil {
+				return total, err
+			}
+
+			n, err := anonymize(cutoff, retentionBatchSize)
+			if err != nil {
+				return total, fmt.Errorf("failed to anonymize orders: %w", err)
+			}
+			total += n
+			if n < retentionBatchSize {
+				break
+			}
+		}
+	}
+
+	if total > 0 {
+		s.logger.Info("orders anonymized",
+			zap.Int64("count", total),
+			zap.Time("cutoff", cutoff),
+		)
+	}
+	return total, nil
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+
+	"order-service/pkg/shop_client"
+)
+
+// ==================== OrderShopClientAdapter for OrderService ====================
+
+type OrderShopClientAdapter struct {
+	Client *shop_client.ShopClient
+}
+
+// GetShop fetches shop info for the shop snapshot on new orders
+func (a *OrderShopClientAdapter) GetShop(ctx context.Context, shopID uint) (*OrderShopDTO, error) {
+	shop, err := a.Client.GetShop(ctx, shopID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OrderShopDTO{
+		ID:      shop.ID,
+		Name:    shop.Name,
+		LogoURL: shop.LogoURL,
+	}, nil
+}
+
+// GetShopOwner returns the user who owns the shop (seller access to disputes)
+func (a *OrderShopClientAdapter) GetShopOwner(ctx context.Context, shopID uint) (uint, error) {
+	shop, err := a.Client.GetShop(ctx, shopID)
+	if err != nil {
+		return 0, err
+	}
+	return shop.OwnerUserID, nil
+}
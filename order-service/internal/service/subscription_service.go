@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"order-service/internal/domain"
+	"order-service/pkg/product_client"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// MaxSubscriptionsPerUser caps the product subscriptions of one user
+const MaxSubscriptionsPerUser = 100
+
+// SubscriptionProductClient reads current prices and stock (implemented by product_client.ProductClient)
+type SubscriptionProductClient interface {
+	GetProduct(ctx context.Context, productID uint) (*product_client.Product, error)
+	GetProductItemsByProduct(ctx context.Context, productID uint) ([]*product_client.ProductItem, error)
+}
+
+// SubscribeRequest represents the request to subscribe to a product
+type SubscribeRequest struct {
+	ProductID     uint                    `json:"product_id" binding:"required"`
+	ProductItemID uint                    `json:"product_item_id,omitempty"` // 0 = any SKU of the product
+	Type          domain.SubscriptionType `json:"type" binding:"required"`
+	TargetPrice   float64                 `json:"target_price,omitempty"` // Required for price_drop
+}
+
+// SubscriptionService manages price-drop and back-in-stock subscriptions and
+// evaluates them on product events
+// Each subscription keeps the state it last notified; a notification is sent only
+// when that state changes (price fell below the last notified one, product came back
+// in stock) and is re-armed when the condition no longer holds. Events carry a dedupe
+// key per notification, so redelivered product events never notify twice
+type SubscriptionService struct {
+	repo          domain.SubscriptionRepository
+	productClient SubscriptionProductClient
+	publisher     domain.NotificationEventPublisher
+	logger        *zap.Logger
+}
+
+// NewSubscriptionService creates a new subscription service
+func NewSubscriptionService(
+	repo domain.SubscriptionRepository,
+	productClient SubscriptionProductClient,
+	publisher domain.NotificationEventPublisher,
+	logger *zap.Logger,
+) *SubscriptionService {
+	return &SubscriptionService{
+		repo:          repo,
+		productClient: productClient,
+		publisher:     publisher,
+		logger:        logger,
+	}
+}
+
+// Subscribe creates or updates the user's subscription to a product
+// A price_drop subscription whose target is already met notifies right away
+func (s *SubscriptionService) Subscribe(ctx context.Context, userID uint, req *SubscribeRequest) (*domain.ProductSubscription, error) {
+	switch req.Type {
+	case domain.SubscriptionPriceDrop:
+		if req.TargetPrice <= 0 {
+			return nil, domain.ErrInvalidTargetPrice
+		}
+	case domain.SubscriptionBackInStock:
+		req.TargetPrice = 0
+	default:
+		return nil, domain.ErrInvalidSubscriptionType
+	}
+
+	existing, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subscriptions: %w", err)
+	}
+	if len(existing) >= MaxSubscriptionsPerUser && !hasSubscription(existing, req) {
+		return nil, domain.ErrSubscriptionLimit
+	}
+
+	if _, err := s.productClient.GetProduct(ctx, req.ProductID); err != nil {
+		if errors.Is(err, product_client.ErrNotFound) {
+			return nil, domain.ErrSubscriptionProductNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch product: %w", err)
+	}
+	items, err := s.productClient.GetProductItemsByProduct(ctx, req.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch product items: %w", err)
+	}
+	if req.ProductItemID != 0 && !containsItem(items, req.ProductItemID) {
+		return nil, domain.ErrInvalidProductItem
+	}
+
+	sub := &domain.ProductSubscription{
+		UserID:        userID,
+		ProductID:     req.ProductID,
+		ProductItemID: req.ProductItemID,
+		Type:          req.Type,
+		TargetPrice:   req.TargetPrice,
+	}
+	// Back in stock counts from the current availability: subscribing to an available product
+	// notifies after it sells out and is restocked
+	_, _, sub.InStock = bestOffer(sub, items)
+
+	if err := s.repo.Upsert(ctx, sub); err != nil {
+		return nil, fmt.Errorf("failed to save subscription: %w", err)
+	}
+
+	if sub.Type == domain.SubscriptionPriceDrop {
+		if err := s.check(ctx, sub, items); err != nil {
+			s.logger.Warn("failed to check new price drop subscription",
+				zap.Uint("subscription_id", sub.ID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	s.logger.Info("product subscription saved",
+		zap.Uint("user_id", userID),
+		zap.Uint("product_id", sub.ProductID),
+		zap.String("type", string(sub.Type)),
+	)
+	return sub, nil
+}
+
+// ListSubscriptions returns the user's subscriptions, newest first
+func (s *SubscriptionService) ListSubscriptions(ctx context.Context, userID uint) ([]*domain.ProductSubscription, error) {
+	subs, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subscriptions: %w", err)
+	}
+	return subs, nil
+}
+
+// Unsubscribe deletes one of the user's subscriptions
+func (s *SubscriptionService) Unsubscribe(ctx context.Context, userID, subscriptionID uint) error {
+	return s.repo.Delete(ctx, userID, subscriptionID)
+}
+
+// HandleProductUpdated re-evaluates a product's subscriptions on product_updated
+func (s *SubscriptionService) HandleProductUpdated(ctx context.Context, productID uint) error {
+	return s.evaluateProduct(ctx, productID)
+}
+
+// HandleProductItemUpdated re-evaluates a product's subscriptions when a SKU's price or stock changed
+func (s *SubscriptionService) HandleProductItemUpdated(ctx context.Context, productID uint, change *domain.ProductItemChange) error {
+	// Stock moving between two positive levels at the same price (orders, restocks of an
+	// available SKU) cannot change any subscription - skip the lookups
+	if change.Price == change.PreviousPrice && change.QtyInStock > 0 && change.PreviousQtyInStock > 0 {
+		return nil
+	}
+	return s.evaluateProduct(ctx, productID)
+}
+
+// evaluateProduct checks all subscriptions of a product against its current SKUs
+func (s *SubscriptionService) evaluateProduct(ctx context.Context, productID uint) error {
+	subs, err := s.repo.ListByProduct(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("failed to load subscriptions: %w", err)
+	}
+	if len(subs) == 0 {
+		return nil
+	}
+
+	items, err := s.productClient.GetProductItemsByProduct(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch product items: %w", err)
+	}
+
+	var failed int
+	for _, sub := range subs {
+		if err := s.check(ctx, sub, items); err != nil {
+			failed++
+			s.logger.Error("failed to evaluate product subscription",
+				zap.Uint("subscription_id", sub.ID),
+				zap.Uint("product_id", productID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to evaluate %d of %d subscriptions", failed, len(subs))
+	}
+	return nil
+}
+
+// check compares one subscription with the current SKUs, notifies if its condition
+// is newly met and saves the new state
+func (s *SubscriptionService) check(ctx context.Context, sub *domain.ProductSubscription, items []*product_client.ProductItem) error {
+	price, stock, available := bestOffer(sub, items)
+	expectedCount := sub.NotifyCount
+	notify := false
+
+	switch sub.Type {
+	case domain.SubscriptionPriceDrop:
+		switch {
+		case !available:
+			return nil // No price to compare until something can be bought
+		case price > sub.TargetPrice:
+			if sub.NotifiedPrice == nil {
+				return nil
+			}
+			sub.NotifiedPrice = nil // Re-arm: the next drop below target notifies again
+		case sub.NotifiedPrice == nil || price < *sub.NotifiedPrice:
+			sub.NotifiedPrice = &price
+			notify = true
+		default:
+			return nil
+		}
+
+	case domain.SubscriptionBackInStock:
+		if available == sub.InStock {
+			return nil
+		}
+		sub.InStock = available
+		notify = available // Selling out only re-arms
+
+	default:
+		return nil
+	}
+
+	if notify {
+		now := time.Now()
+		sub.NotifyCount++
+		sub.LastNotifiedAt = &now
+		// Published before the state is saved: if saving fails the event is
+		// redelivered with the same dedupe key, so it is never lost nor doubled
+		if err := s.publish(ctx, sub, price, stock); err != nil {
+			return err
+		}
+	}
+
+	saved, err := s.repo.SaveState(ctx, sub, expectedCount)
+	if err != nil {
+		return fmt.Errorf("failed to save subscription state: %w", err)
+	}
+	if !saved {
+		// Changed concurrently (re-subscribed or evaluated by another event) - that run wins
+		s.logger.Debug("subscription state changed concurrently", zap.Uint("subscription_id", sub.ID))
+	}
+	return nil
+}
+
+// publish emits the notification event of a subscription
+func (s *SubscriptionService) publish(ctx context.Context, sub *domain.ProductSubscription, price float64, stock int) error {
+	event := &domain.NotificationEvent{
+		EventType:      domain.EventNotificationPriceDrop,
+		DedupeKey:      domain.NotificationDedupeKey(sub.ID, sub.NotifyCount),
+		UserID:         sub.UserID,
+		SubscriptionID: sub.ID,
+		ProductID:      sub.ProductID,
+		ProductItemID:  sub.ProductItemID,
+		Price:          price,
+		TargetPrice:    sub.TargetPrice,
+		QtyInStock:     stock,
+		Timestamp:      time.Now(),
+	}
+	if sub.Type == domain.SubscriptionBackInStock {
+		event.EventType = domain.EventNotificationBackInStock
+	}
+
+	// Name is display only - notify without it if Product Service cannot tell
+	if product, err := s.productClient.GetProduct(ctx, sub.ProductID); err == nil {
+		event.ProductName = product.Name
+	}
+
+	if err := s.publisher.PublishNotificationEvent(event); err != nil {
+		return fmt.Errorf("failed to publish %s: %w", event.EventType, err)
+	}
+
+	s.logger.Info("product subscription notified",
+		zap.Uint("subscription_id", sub.ID),
+		zap.Uint("user_id", sub.UserID),
+		zap.String("event_type", event.EventType),
+		zap.String("dedupe_key", event.DedupeKey),
+	)
+	return nil
+}
+
+// bestOffer returns the lowest price and total stock of the subscription's SKUs that can be
+// bought now; available is false if none can
+func bestOffer(sub *domain.ProductSubscription, items []*product_client.ProductItem) (price float64, stock int, available bool) {
+	for _, item := range items {
+		if sub.ProductItemID != 0 && item.ID != sub.ProductItemID {
+			continue
+		}
+		if !item.IsActive() || item.QtyInStock <= 0 {
+			continue
+		}
+		if !available || item.Price < price {
+			price = item.Price
+		}
+		stock += item.QtyInStock
+		available = true
+	}
+	return price, stock, available
+}
+
+// hasSubscription reports whether subs already holds the subscription req would update
+func hasSubscription(subs []*domain.ProductSubscription, req *SubscribeRequest) bool {
+	for _, sub := range subs {
+		if sub.ProductID == req.ProductID && sub.ProductItemID == req.ProductItemID && sub.Type == req.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// containsItem reports whether the product's SKUs include productItemID
+func containsItem(items []*product_client.ProductItem, productItemID uint) bool {
+	for _, item := range items {
+		if item.ID == productItemID {
+			return true
+		}
+	}
+	return false
+}
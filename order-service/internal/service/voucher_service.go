@@ -0,0 +1,423 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"order-service/internal/domain"
+	"order-service/internal/repository/postgres"
+	"order-service/pkg/money"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// VoucherShopClient resolves shop owners from Identity Service (seller vouchers)
+type VoucherShopClient interface {
+	GetShopOwner(ctx context.Context, shopID uint) (uint, error)
+}
+
+// VoucherActor is the user managing vouchers (X-User-Id / X-User-Role from API Gateway)
+type VoucherActor struct {
+	UserID uint
+	Role   string
+}
+
+// IsAdmin reports whether the actor is an admin
+func (a VoucherActor) IsAdmin() bool {
+	return a.Role == "ADMIN"
+}
+
+// ErrPlatformVoucherForbidden is returned when a seller manages platform vouchers
+var ErrPlatformVoucherForbidden = domain.Forbidden("only admins can manage platform vouchers")
+
+// CreateVoucherRequest is the body of POST /vouchers
+// Without shop_id the voucher is a platform voucher (admins only)
+type CreateVoucherRequest struct {
+	Code          string     `json:"code" binding:"required,min=3,max=40,alphanum"`
+	ShopID        *uint      `json:"shop_id,omitempty"`
+	Type          string     `json:"type" binding:"required"`   // percent or fixed
+	Target        string     `json:"target,omitempty"`          // merchandise (default) or shipping
+	Value         float64    `json:"value" binding:"required"`  // Percent or VND
+	MaxDiscount   float64    `json:"max_discount,omitempty"`    // Cap of percent vouchers
+	MinOrderValue float64    `json:"min_order_value,omitempty"` // Merchandise subtotal required
+	UsageLimit    int        `json:"usage_limit,omitempty"`     // 0 = unlimited
+	PerUserLimit  int        `json:"per_user_limit,omitempty"`  // 0 = unlimited
+	StartsAt      *time.Time `json:"starts_at,omitempty"`       // Default now
+	EndsAt        *time.Time `json:"ends_at,omitempty"`         // Default no end
+	IsActive      *bool      `json:"is_active,omitempty"`       // Default true
+}
+
+// UpdateVoucherRequest is the body of PUT /vouchers/:id; omitted fields are kept
+// The code and shop of a voucher cannot change
+type UpdateVoucherRequest struct {
+	Type          *string    `json:"type,omitempty"`
+	Target        *string    `json:"target,omitempty"`
+	Value         *float64   `json:"value,omitempty"`
+	MaxDiscount   *float64   `json:"max_discount,omitempty"`
+	MinOrderValue *float64   `json:"min_order_value,omitempty"`
+	UsageLimit    *int       `json:"usage_limit,omitempty"`
+	PerUserLimit  *int       `json:"per_user_limit,omitempty"`
+	StartsAt      *time.Time `json:"starts_at,omitempty"`
+	EndsAt        *time.Time `json:"ends_at,omitempty"`
+	IsActive      *bool      `json:"is_active,omitempty"`
+}
+
+// VoucherService manages vouchers (admins: platform and any shop's, sellers: their shop's)
+// and applies them to checkouts
+type VoucherService struct {
+	voucherRepo *postgres.VoucherRepository
+	shops       VoucherShopClient
+	async       AsyncRunner
+	logger      *zap.Logger
+}
+
+// NewVoucherService creates a new voucher service
+func NewVoucherService(voucherRepo *postgres.VoucherRepository, shops VoucherShopClient, async AsyncRunner, logger *zap.Logger) *VoucherService {
+	return &VoucherService{
+		voucherRepo: voucherRepo,
+		shops:       shops,
+		async:       async,
+		logger:      logger,
+	}
+}
+
+// Create creates a platform voucher (admin) or a voucher of the actor's shop
+func (s *VoucherService) Create(ctx context.Context, actor VoucherActor, req *CreateVoucherRequest) (*domain.Voucher, error) {
+	if err := s.checkAccess(ctx, actor, req.ShopID); err != nil {
+		return nil, err
+	}
+
+	voucher := &domain.Voucher{
+		Code:          domain.NormalizeVoucherCode(req.Code),
+		ShopID:        req.ShopID,
+		Type:          domain.VoucherType(req.Type),
+		Target:        domain.VoucherTarget(req.Target),
+		Value:         req.Value,
+		MaxDiscount:   req.MaxDiscount,
+		MinOrderValue: req.MinOrderValue,
+		UsageLimit:    req.UsageLimit,
+		PerUserLimit:  req.PerUserLimit,
+		StartsAt:      time.Now(),
+		EndsAt:        req.EndsAt,
+		IsActive:      true,
+		CreatedBy:     actor.UserID,
+	}
+	if voucher.Target == "" {
+		voucher.Target = domain.VoucherTargetMerchandise
+	}
+	if req.StartsAt != nil {
+		voucher.StartsAt = *req.StartsAt
+	}
+	if req.IsActive != nil {
+		voucher.IsActive = *req.IsActive
+	}
+	if !voucher.Valid() {
+		return nil, domain.ErrInvalidVoucher
+	}
+
+	if err := s.voucherRepo.Create(ctx, voucher); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("voucher created",
+		zap.Uint("voucher_id", voucher.ID),
+		zap.String("code", voucher.Code),
+		zap.Bool("platform", voucher.IsPlatform()),
+		zap.Uint("created_by", actor.UserID),
+	)
+	return voucher, nil
+}
+
+// Get returns a voucher the actor manages
+func (s *VoucherService) Get(ctx context.Context, actor VoucherActor, id uint) (*domain.Voucher, error) {
+	voucher, err := s.voucherRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAccess(ctx, actor, voucher.ShopID); err != nil {
+		return nil, domain.ErrVoucherNotFound
+	}
+	return voucher, nil
+}
+
+// List lists the vouchers of a shop (its owner or an admin), or with no shop all
+// vouchers (admin) - only platform vouchers if platformOnly
+func (s *VoucherService) List(ctx context.Context, actor VoucherActor, shopID *uint, platformOnly bool, page, limit int) ([]*domain.Voucher, int64, error) {
+	if shopID == nil && !actor.IsAdmin() {
+		return nil, 0, domain.Validation("shop_id is required")
+	}
+	if err := s.checkAccess(ctx, actor, shopID); err != nil {
+		return nil, 0, err
+	}
+	page, limit = disputePage(page, limit)
+	return s.voucherRepo.List(ctx, domain.VoucherFilter{ShopID: shopID, Platform: platformOnly}, page, limit)
+}
+
+// Update changes the settings of a voucher the actor manages
+func (s *VoucherService) Update(ctx context.Context, actor VoucherActor, id uint, req *UpdateVoucherRequest) (*domain.Voucher, error) {
+	voucher, err := s.Get(ctx, actor, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Type != nil {
+		voucher.Type = domain.VoucherType(*req.Type)
+	}
+	if req.Target != nil {
+		voucher.Target = domain.VoucherTarget(*req.Target)
+	}
+	if req.Value != nil {
+		voucher.Value = *req.Value
+	}
+	if req.MaxDiscount != nil {
+		voucher.MaxDiscount = *req.MaxDiscount
+	}
+	if req.MinOrderValue != nil {
+		voucher.MinOrderValue = *req.MinOrderValue
+	}
+	if req.UsageLimit != nil {
+		voucher.UsageLimit = *req.UsageLimit
+	}
+	if req.PerUserLimit != nil {
+		voucher.PerUserLimit = *req.PerUserLimit
+	}
+	if req.StartsAt != nil {
+		voucher.StartsAt = *req.StartsAt
+	}
+	if req.EndsAt != nil {
+		voucher.EndsAt = req.EndsAt
+	}
+	if req.IsActive != nil {
+		voucher.IsActive = *req.IsActive
+	}
+	if !voucher.Valid() {
+		return nil, domain.ErrInvalidVoucher
+	}
+
+	if err := s.voucherRepo.Update(ctx, voucher); err != nil {
+		return nil, fmt.Errorf("failed to update voucher: %w", err)
+	}
+	s.logger.Info("voucher updated", zap.Uint("voucher_id", voucher.ID), zap.Uint("updated_by", actor.UserID))
+	return voucher, nil
+}
+
+// Delete deletes a voucher the actor manages if it was never redeemed
+func (s *VoucherService) Delete(ctx context.Context, actor VoucherActor, id uint) error {
+	if _, err := s.Get(ctx, actor, id); err != nil {
+		return err
+	}
+	if err := s.voucherRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Info("voucher deleted", zap.Uint("voucher_id", id), zap.Uint("deleted_by", actor.UserID))
+	return nil
+}
+
+// checkAccess allows admins everything and sellers their own shop's vouchers
+func (s *VoucherService) checkAccess(ctx context.Context, actor VoucherActor, shopID *uint) error {
+	if actor.IsAdmin() {
+		return nil
+	}
+	if shopID == nil {
+		return ErrPlatformVoucherForbidden
+	}
+
+	ownerID, err := s.shops.GetShopOwner(ctx, *shopID)
+	if err != nil {
+		return fmt.Errorf("failed to get shop: %w", err)
+	}
+	if ownerID == 0 || ownerID != actor.UserID {
+		return domain.Forbidden("not the owner of shop %d", *shopID)
+	}
+	return nil
+}
+
+// checkoutVouchers are the discounts of the vouchers applied to a checkout
+type checkoutVouchers struct {
+	ShopDiscounts    []ShopDiscount
+	PlatformVoucher  money.Money
+	PlatformShipping money.Money
+	Redemptions      []*domain.VoucherRedemption // CheckoutID is set when they are redeemed
+}
+
+// ResolveCheckout validates the voucher codes of a checkout and computes their discounts
+// from the server-side subtotals and shipping fees of its shops. At most one voucher of
+// each target per shop and one of each target for the platform can be used
+// Usage limits are only checked by Redeem
+func (s *VoucherService) ResolveCheckout(ctx context.Context, userID uint, codes []string, shops []*shopAllocation) (*checkoutVouchers, error) {
+	result := &checkoutVouchers{
+		PlatformVoucher:  money.Zero(checkoutCurrency),
+		PlatformShipping: money.Zero(checkoutCurrency),
+	}
+	if len(codes) == 0 {
+		return result, nil
+	}
+
+	normalized := make([]string, 0, len(codes))
+	seen := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		code = domain.NormalizeVoucherCode(code)
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		normalized = append(normalized, code)
+	}
+
+	vouchers, err := s.voucherRepo.GetByCodes(ctx, normalized)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vouchers: %w", err)
+	}
+	byCode := make(map[string]*domain.Voucher, len(vouchers))
+	for _, v := range vouchers {
+		byCode[v.Code] = v
+	}
+
+	byShop := make(map[uint]*shopAllocation, len(shops))
+	checkoutSubtotal := money.Zero(checkoutCurrency)
+	checkoutShipping := money.Zero(checkoutCurrency)
+	for _, shop := range shops {
+		byShop[shop.ShopID] = shop
+		if checkoutSubtotal, err = checkoutSubtotal.Add(shop.Subtotal); err != nil {
+			return nil, fmt.Errorf("invalid checkout subtotal: %w", err)
+		}
+		if checkoutShipping, err = checkoutShipping.Add(shop.ShippingFee); err != nil {
+			return nil, fmt.Errorf("invalid checkout shipping fee: %w", err)
+		}
+	}
+
+	type slot struct {
+		shopID uint // 0 = platform
+		target domain.VoucherTarget
+	}
+	used := make(map[slot]bool)
+	shopDiscounts := make(map[uint]*ShopDiscount)
+	now := time.Now()
+
+	for _, code := range normalized {
+		voucher, ok := byCode[code]
+		if !ok {
+			return nil, domain.NotFound("voucher %s not found", code)
+		}
+		if !voucher.IsRedeemableAt(now) {
+			return nil, fmt.Errorf("%w: %s is not active", domain.ErrVoucherNotApplicable, code)
+		}
+
+		key := slot{target: voucher.Target}
+		subtotal, base := checkoutSubtotal, checkoutSubtotal
+		if !voucher.IsPlatform() {
+			shop, ok := byShop[*voucher.ShopID]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s is for a shop not in this checkout", domain.ErrVoucherNotApplicable, code)
+			}
+			key.shopID = shop.ShopID
+			subtotal, base = shop.Subtotal, shop.Subtotal
+			if voucher.Target == domain.VoucherTargetShipping {
+				base = shop.ShippingFee
+			}
+		} else if voucher.Target == domain.VoucherTargetShipping {
+			base = checkoutShipping
+		}
+		if used[key] {
+			return nil, domain.ErrDuplicateVoucher
+		}
+		used[key] = true
+
+		minOrderValue, err := money.FromFloat(voucher.MinOrderValue, checkoutCurrency)
+		if err != nil {
+			return nil, fmt.Errorf("invalid minimum order value of voucher %s: %w", code, err)
+		}
+		if cmp, err := subtotal.Cmp(minOrderValue); err != nil {
+			return nil, fmt.Errorf("invalid minimum order value of voucher %s: %w", code, err)
+		} else if cmp < 0 {
+			return nil, fmt.Errorf("%w: %s needs an order of at least %.0f", domain.ErrVoucherNotApplicable, code, voucher.MinOrderValue)
+		}
+		discount, err := voucherDiscount(voucher, base)
+		if err != nil {
+			return nil, fmt.Errorf("invalid discount of voucher %s: %w", code, err)
+		}
+		if !discount.IsPositive() {
+			return nil, fmt.Errorf("%w: %s has nothing to discount", domain.ErrVoucherNotApplicable, code)
+		}
+
+		switch {
+		case voucher.IsPlatform() && voucher.Target == domain.VoucherTargetShipping:
+			result.PlatformShipping = discount
+		case voucher.IsPlatform():
+			result.PlatformVoucher = discount
+		default:
+			shopDiscount, ok := shopDiscounts[key.shopID]
+			if !ok {
+				shopDiscount = &ShopDiscount{ShopID: key.shopID}
+				shopDiscounts[key.shopID] = shopDiscount
+			}
+			if voucher.Target == domain.VoucherTargetShipping {
+				shopDiscount.ShippingDiscount = discount
+			} else {
+				shopDiscount.VoucherDiscount = discount
+			}
+		}
+
+		result.Redemptions = append(result.Redemptions, &domain.VoucherRedemption{
+			VoucherID: voucher.ID,
+			UserID:    userID,
+			Code:      voucher.Code,
+			Discount:  discount.Float64(),
+		})
+	}
+
+	for _, shop := range shops {
+		if discount, ok := shopDiscounts[shop.ShopID]; ok {
+			result.ShopDiscounts = append(result.ShopDiscounts, *discount)
+		}
+	}
+	return result, nil
+}
+
+// voucherDiscount computes a voucher's discount of base, never more than base
+func voucherDiscount(voucher *domain.Voucher, base money.Money) (money.Money, error) {
+	if voucher.Type != domain.VoucherTypePercent {
+		discount, err := money.FromFloat(voucher.Value, checkoutCurrency)
+		if err != nil {
+			return money.Money{}, err
+		}
+		return discount.Min(base)
+	}
+
+	discount, err := base.Percent(voucher.Value)
+	if err != nil {
+		return money.Money{}, err
+	}
+	if voucher.MaxDiscount > 0 {
+		maxDiscount, err := money.FromFloat(voucher.MaxDiscount, checkoutCurrency)
+		if err != nil {
+			return money.Money{}, err
+		}
+		if discount, err = discount.Min(maxDiscount); err != nil {
+			return money.Money{}, err
+		}
+	}
+	return discount.Min(base)
+}
+
+// Redeem counts the checkout's vouchers against their usage limits
+// Returns domain.ErrVoucherUsedUp (nothing redeemed) if one has no use left
+func (s *VoucherService) Redeem(ctx context.Context, checkoutID string, vouchers *checkoutVouchers) error {
+	if len(vouchers.Redemptions) == 0 {
+		return nil
+	}
+	for _, redemption := range vouchers.Redemptions {
+		redemption.CheckoutID = checkoutID
+	}
+	return s.voucherRepo.Redeem(ctx, vouchers.Redemptions)
+}
+
+// Release gives the uses of a failed checkout's vouchers back, on the worker pool with retries
+func (s *VoucherService) Release(checkoutID string, vouchers *checkoutVouchers) {
+	if len(vouchers.Redemptions) == 0 {
+		return
+	}
+	s.async.Submit(context.Background(), "release_vouchers", func(ctx context.Context) error {
+		return s.voucherRepo.ReleaseCheckout(ctx, checkoutID)
+	})
+}
@@ -0,0 +1,109 @@
+package audience_client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"order-service/pkg/serviceauth"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AudienceClient resolves the recipients of admin announcements with Identity Service
+// (internal endpoints /api/v1/internal/audience, signed service calls)
+type AudienceClient struct {
+	baseURL    string
+	httpClient *http.Client
+	signer     *serviceauth.Signer
+}
+
+// NewAudienceClient creates a new audience client; signer signs the calls (nil = unsigned)
+func NewAudienceClient(baseURL string, timeout time.Duration, signer *serviceauth.Signer) *AudienceClient {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &AudienceClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		signer: signer,
+	}
+}
+
+type userIDsResponse struct {
+	UserIDs []uint `json:"user_ids"`
+}
+
+// Users returns the IDs of the next batch of users of a segment (buyers, inactive_users)
+// after afterID, ordered by ID; an empty batch ends the segment
+func (c *AudienceClient) Users(ctx context.Context, segment string, inactiveDays int, afterID uint, limit int) ([]uint, error) {
+	query := url.Values{}
+	query.Set("segment", segment)
+	query.Set("after_id", strconv.FormatUint(uint64(afterID), 10))
+	query.Set("limit", strconv.Itoa(limit))
+	if inactiveDays > 0 {
+		query.Set("inactive_days", strconv.Itoa(inactiveDays))
+	}
+
+	var response userIDsResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/internal/audience/users?"+query.Encode(), nil, &response); err != nil {
+		return nil, err
+	}
+	return response.UserIDs, nil
+}
+
+// ShopOwners returns the IDs of the users owning the active shops among shopIDs, ordered by ID
+func (c *AudienceClient) ShopOwners(ctx context.Context, shopIDs []uint) ([]uint, error) {
+	if len(shopIDs) == 0 {
+		return nil, nil
+	}
+	body, err := json.Marshal(map[string]interface{}{"shop_ids": shopIDs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode shop IDs: %w", err)
+	}
+
+	var response userIDsResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/internal/audience/shop-owners", body, &response); err != nil {
+		return nil, err
+	}
+	return response.UserIDs, nil
+}
+
+// do sends a request and decodes the JSON body into out
+func (c *AudienceClient) do(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build identity service request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	c.signer.Sign(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call identity service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("identity service returned error: %d - %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode identity service response: %w", err)
+	}
+	return nil
+}
@@ -2,9 +2,12 @@ package database
 
 import (
 	"fmt"
+	"log"
 	"order-service/config"
 	"sync"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -35,10 +38,16 @@ func GetDB(cfg *config.DatabaseConfig) (*gorm.DB, error) {
 			return
 		}
 
-		// Set connection pool settings
+		// Set connection pool settings (validated by config.Validate)
 		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
 		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
 		sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+		sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+		// Pool usage (open/in use/idle connections, waits) on /metrics
+		if err2 = prometheus.Register(collectors.NewDBStatsCollector(sqlDB, cfg.DBName)); err2 != nil {
+			log.Printf("Failed to register database pool metrics: %v", err2)
+		}
 
 		// Test connection
 		if err2 = sqlDB.Ping(); err2 != nil {
@@ -0,0 +1,132 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Stats summarizes a namespace for the admin endpoint
+type Stats struct {
+	Namespace string `json:"namespace"`
+	Scheduled int64  `json:"scheduled"`
+	Active    int64  `json:"active"`
+	Dead      int64  `json:"dead"`
+	Enqueued  int64  `json:"enqueued"`
+	Processed int64  `json:"processed"`
+	Failed    int64  `json:"failed"`
+}
+
+// Inspector exposes job status for admin tooling
+type Inspector struct {
+	*Client
+}
+
+// NewInspector creates an inspector for a namespace
+func NewInspector(client *redis.Client, namespace string) *Inspector {
+	return &Inspector{Client: NewClient(client, namespace)}
+}
+
+// Stats returns queue sizes and lifetime counters
+func (i *Inspector) Stats(ctx context.Context) (*Stats, error) {
+	pipe := i.client.Pipeline()
+	scheduled := pipe.ZCard(ctx, i.scheduledKey())
+	active := pipe.ZCard(ctx, i.activeKey())
+	dead := pipe.ZCard(ctx, i.deadKey())
+	counters := pipe.HGetAll(ctx, i.statsKey())
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get job stats: %w", err)
+	}
+
+	c := counters.Val()
+	return &Stats{
+		Namespace: i.ns,
+		Scheduled: scheduled.Val(),
+		Active:    active.Val(),
+		Dead:      dead.Val(),
+		Enqueued:  parseCounter(c["enqueued"]),
+		Processed: parseCounter(c["processed"]),
+		Failed:    parseCounter(c["failed"]),
+	}, nil
+}
+
+// ListJobs returns jobs in a state (scheduled, active, dead), oldest first
+func (i *Inspector) ListJobs(ctx context.Context, state string, limit int) ([]*Job, error) {
+	var key string
+	switch state {
+	case StateScheduled:
+		key = i.scheduledKey()
+	case StateActive:
+		key = i.activeKey()
+	case StateDead:
+		key = i.deadKey()
+	default:
+		return nil, fmt.Errorf("invalid job state: %s", state)
+	}
+
+	ids, err := i.client.ZRange(ctx, key, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	jobs := make([]*Job, 0, len(ids))
+	for _, id := range ids {
+		job, err := i.getJob(ctx, id)
+		if err != nil {
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// GetJob returns a job by ID
+func (i *Inspector) GetJob(ctx context.Context, id string) (*Job, error) {
+	return i.getJob(ctx, id)
+}
+
+// RetryDead moves a dead job back to scheduled with a fresh retry budget
+func (i *Inspector) RetryDead(ctx context.Context, id string) (*Job, error) {
+	removed, err := i.client.ZRem(ctx, i.deadKey(), id).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retry job: %w", err)
+	}
+	if removed == 0 {
+		return nil, ErrJobNotFound
+	}
+
+	job, err := i.getJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	job.State = StateScheduled
+	job.Attempts = 0
+	job.RunAt = time.Now()
+	if err := i.saveJob(ctx, job, 0); err != nil {
+		return nil, fmt.Errorf("failed to save job: %w", err)
+	}
+	if err := i.client.ZAdd(ctx, i.scheduledKey(), redis.Z{Score: float64(job.RunAt.UnixMilli()), Member: id}).Err(); err != nil {
+		return nil, fmt.Errorf("failed to schedule job: %w", err)
+	}
+	return job, nil
+}
+
+// DeleteDead discards a dead job
+func (i *Inspector) DeleteDead(ctx context.Context, id string) error {
+	removed, err := i.client.ZRem(ctx, i.deadKey(), id).Result()
+	if err != nil {
+		return fmt.Errorf("failed to delete job: %w", err)
+	}
+	if removed == 0 {
+		return ErrJobNotFound
+	}
+	return i.client.Del(ctx, i.jobKey(id)).Err()
+}
+
+func parseCounter(v string) int64 {
+	n, _ := strconv.ParseInt(v, 10, 64)
+	return n
+}
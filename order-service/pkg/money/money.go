@@ -0,0 +1,402 @@
+// Package money represents amounts as whole minor units (đồng, cents) of a currency,
+// so sums and splits are exact instead of accumulating float64 errors.
+//
+// Rounding to the minor unit is banker's rounding (half to even); splitting an amount
+// (Allocate) uses the largest remainder method so the shares always add up to it.
+// The package only uses the standard library, so services can carry the same copy
+// (like pkg/serviceauth).
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Currency string
+
+// Currencies used by the platform (ISO 4217)
+const (
+	VND Currency = "VND"
+	USD Currency = "USD"
+	EUR Currency = "EUR"
+)
+
+// minorDigits are the decimals of the minor unit of currencies without two
+var minorDigits = map[Currency]int{
+	VND:   0,
+	"JPY": 0,
+	"KRW": 0,
+}
+
+// Digits returns the number of decimals of the currency's minor unit (2 unless known otherwise)
+func (c Currency) Digits() int {
+	if digits, ok := minorDigits[c]; ok {
+		return digits
+	}
+	return 2
+}
+
+// valid reports whether c looks like an ISO 4217 code
+func (c Currency) valid() bool {
+	if len(c) != 3 {
+		return false
+	}
+	for _, r := range c {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	ErrInvalidAmount    = errors.New("money: invalid amount")
+	ErrInvalidCurrency  = errors.New("money: invalid currency")
+	ErrOutOfRange       = errors.New("money: amount out of range")
+	ErrCurrencyMismatch = errors.New("money: currency mismatch")
+)
+
+// Money is an amount of a currency, kept in minor units
+// The zero Money is zero of no currency and can be added to or compared with any Money
+type Money struct {
+	minor    int64
+	currency Currency
+}
+
+// New returns minor units of the currency (e.g. New(1250, USD) is 12.50 USD)
+func New(minor int64, currency Currency) Money {
+	return Money{minor: minor, currency: currency}
+}
+
+// Zero returns zero of the currency
+func Zero(currency Currency) Money {
+	return Money{currency: currency}
+}
+
+// FromFloat converts an amount in major units, rounded half to even to the minor unit
+// The float's shortest decimal form is rounded, so FromFloat(2.675, USD) is 2.68 USD
+// even though 2.675 is stored as 2.67499...
+// Returns ErrInvalidAmount if the amount is not finite, ErrOutOfRange if it is too large
+func FromFloat(amount float64, currency Currency) (Money, error) {
+	if math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return Money{}, ErrInvalidAmount
+	}
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(amount, 'f', -1, 64))
+	minor := roundHalfEven(r.Mul(r, scale(currency)))
+	if !minor.IsInt64() {
+		return Money{}, ErrOutOfRange
+	}
+	return Money{minor: minor.Int64(), currency: currency}, nil
+}
+
+// Parse converts a decimal amount in major units ("12.5", "-3", "130000"), rounded half
+// to even to the minor unit; returns ErrInvalidAmount if it is not a plain decimal,
+// ErrOutOfRange if it is too large
+func Parse(amount string, currency Currency) (Money, error) {
+	amount = strings.TrimSpace(amount)
+	if !isDecimal(amount) {
+		return Money{}, ErrInvalidAmount
+	}
+	r, _ := new(big.Rat).SetString(amount)
+	minor := roundHalfEven(r.Mul(r, scale(currency)))
+	if !minor.IsInt64() {
+		return Money{}, ErrOutOfRange
+	}
+	return Money{minor: minor.Int64(), currency: currency}, nil
+}
+
+// Minor returns the amount in minor units
+func (m Money) Minor() int64 {
+	return m.minor
+}
+
+// Currency returns the currency (empty for the zero Money)
+func (m Money) Currency() Currency {
+	return m.currency
+}
+
+// Float64 returns the amount in major units, for storage in decimal columns and JSON
+// fields that predate this package. Do arithmetic on Money, not on the result
+func (m Money) Float64() float64 {
+	f, _ := strconv.ParseFloat(m.decimal(), 64)
+	return f
+}
+
+// String formats the amount with its currency, e.g. "12.50 USD"
+func (m Money) String() string {
+	if m.currency == "" {
+		return m.decimal()
+	}
+	return m.decimal() + " " + string(m.currency)
+}
+
+// decimal formats the amount in major units with the currency's decimals
+func (m Money) decimal() string {
+	digits := m.currency.Digits()
+	s := strconv.FormatInt(m.minor, 10)
+	if digits == 0 {
+		return s
+	}
+
+	sign := ""
+	if m.minor < 0 {
+		sign, s = "-", s[1:]
+	}
+	if len(s) <= digits {
+		s = strings.Repeat("0", digits-len(s)+1) + s
+	}
+	return sign + s[:len(s)-digits] + "." + s[len(s)-digits:]
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.minor == 0
+}
+
+// IsPositive reports whether the amount is above zero
+func (m Money) IsPositive() bool {
+	return m.minor > 0
+}
+
+// IsNegative reports whether the amount is below zero
+func (m Money) IsNegative() bool {
+	return m.minor < 0
+}
+
+// Cmp compares two amounts: -1 if m < o, 0 if equal, +1 if m > o
+// Returns ErrCurrencyMismatch if the currencies differ
+func (m Money) Cmp(o Money) (int, error) {
+	if _, err := sameCurrency(m, o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.minor < o.minor:
+		return -1, nil
+	case m.minor > o.minor:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Add returns m + o
+// Returns ErrCurrencyMismatch if the currencies differ, ErrOutOfRange if the sum overflows
+func (m Money) Add(o Money) (Money, error) {
+	currency, err := sameCurrency(m, o)
+	if err != nil {
+		return Money{}, err
+	}
+	sum := m.minor + o.minor
+	if (o.minor > 0 && sum < m.minor) || (o.minor < 0 && sum > m.minor) {
+		return Money{}, ErrOutOfRange
+	}
+	return Money{minor: sum, currency: currency}, nil
+}
+
+// Sub returns m - o
+// Returns ErrCurrencyMismatch if the currencies differ, ErrOutOfRange if the difference overflows
+func (m Money) Sub(o Money) (Money, error) {
+	currency, err := sameCurrency(m, o)
+	if err != nil {
+		return Money{}, err
+	}
+	diff := m.minor - o.minor
+	if (o.minor > 0 && diff > m.minor) || (o.minor < 0 && diff < m.minor) {
+		return Money{}, ErrOutOfRange
+	}
+	return Money{minor: diff, currency: currency}, nil
+}
+
+// Neg returns -m
+func (m Money) Neg() Money {
+	return Money{minor: -m.minor, currency: m.currency}
+}
+
+// Mul returns m times a whole quantity (e.g. unit price x quantity)
+// Returns ErrOutOfRange if the product overflows
+func (m Money) Mul(quantity int64) (Money, error) {
+	if m.minor == 0 || quantity == 0 {
+		return Zero(m.currency), nil
+	}
+	product := m.minor * quantity
+	if product/quantity != m.minor || (m.minor == -1 && quantity == math.MinInt64) || (quantity == -1 && m.minor == math.MinInt64) {
+		return Money{}, ErrOutOfRange
+	}
+	return Money{minor: product, currency: m.currency}, nil
+}
+
+// Percent returns percent % of m rounded half to even to the minor unit,
+// e.g. a platform fee of 2.5 %
+// Returns ErrInvalidAmount if percent is not finite, ErrOutOfRange if the result is too large
+func (m Money) Percent(percent float64) (Money, error) {
+	if math.IsNaN(percent) || math.IsInf(percent, 0) {
+		return Money{}, ErrInvalidAmount
+	}
+	r, _ := new(big.Rat).SetString(strconv.FormatFloat(percent, 'f', -1, 64))
+	r.Mul(r, new(big.Rat).SetInt64(m.minor))
+	r.Quo(r, big.NewRat(100, 1))
+	minor := roundHalfEven(r)
+	if !minor.IsInt64() {
+		return Money{}, ErrOutOfRange
+	}
+	return Money{minor: minor.Int64(), currency: m.currency}, nil
+}
+
+// Min returns the smaller amount; returns ErrCurrencyMismatch if the currencies differ
+func (m Money) Min(o Money) (Money, error) {
+	currency, err := sameCurrency(m, o)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{minor: min(m.minor, o.minor), currency: currency}, nil
+}
+
+// Max returns the larger amount; returns ErrCurrencyMismatch if the currencies differ
+func (m Money) Max(o Money) (Money, error) {
+	currency, err := sameCurrency(m, o)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{minor: max(m.minor, o.minor), currency: currency}, nil
+}
+
+// NonNegative returns m, or zero if m is negative
+func (m Money) NonNegative() Money {
+	if m.minor < 0 {
+		return Zero(m.currency)
+	}
+	return m
+}
+
+// Allocate splits m by weight: shares[i] is proportional to weights[i] and the shares
+// add up to m exactly. Non-positive weights get nothing (all of them: nothing is split)
+// Shares are rounded toward zero and the minor units left over go one each to the
+// largest remainders (ties to the earlier index), so no share is off by a unit or more
+func (m Money) Allocate(weights []int64) []Money {
+	shares := make([]Money, len(weights))
+	for i := range shares {
+		shares[i] = Zero(m.currency)
+	}
+
+	sum := new(big.Int)
+	for _, w := range weights {
+		if w > 0 {
+			sum.Add(sum, big.NewInt(w))
+		}
+	}
+	if sum.Sign() == 0 || m.minor == 0 {
+		return shares
+	}
+
+	type remainder struct {
+		index int
+		rest  *big.Int // Of total * weight / sum, in units of 1/sum
+	}
+	total := big.NewInt(m.minor)
+	total.Abs(total)
+	remainders := make([]remainder, 0, len(weights))
+	allocated := int64(0)
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		q, r := new(big.Int).QuoRem(new(big.Int).Mul(total, big.NewInt(w)), sum, new(big.Int))
+		shares[i].minor = q.Int64()
+		allocated += shares[i].minor
+		remainders = append(remainders, remainder{index: i, rest: r})
+	}
+
+	sort.SliceStable(remainders, func(i, j int) bool { return remainders[i].rest.Cmp(remainders[j].rest) > 0 })
+	left := total.Int64() - allocated // < len(remainders): each share lost less than a unit
+	for _, r := range remainders[:left] {
+		shares[r.index].minor++
+	}
+
+	if m.minor < 0 {
+		for i := range shares {
+			shares[i].minor = -shares[i].minor
+		}
+	}
+	return shares
+}
+
+// jsonMoney is the JSON form: {"amount": 12.50, "currency": "USD"} (amount in major units)
+type jsonMoney struct {
+	Amount   json.Number `json:"amount"`
+	Currency Currency    `json:"currency"`
+}
+
+// MarshalJSON encodes the amount as a decimal number with the currency's decimals
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonMoney{Amount: json.Number(m.decimal()), Currency: m.currency})
+}
+
+// UnmarshalJSON decodes {"amount": ..., "currency": ...}; the amount may be a number or a
+// decimal string and is rounded half to even to the currency's minor unit
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var v jsonMoney
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if !v.Currency.valid() {
+		return ErrInvalidCurrency
+	}
+	parsed, err := Parse(v.Amount.String(), v.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// isDecimal reports whether s is a plain decimal number: optional sign, digits and at
+// most one decimal point (no exponent, fraction or base prefix)
+func isDecimal(s string) bool {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" && fracPart == "" {
+		return false
+	}
+	return strings.Trim(intPart, "0123456789") == "" && strings.Trim(fracPart, "0123456789") == ""
+}
+
+// scale returns 10^digits of the currency's minor unit
+func scale(currency Currency) *big.Rat {
+	return new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(currency.Digits())), nil))
+}
+
+// roundHalfEven rounds r to an integer, halves to the even neighbour
+func roundHalfEven(r *big.Rat) *big.Int {
+	q, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	twice := new(big.Int).Abs(rem)
+	twice.Lsh(twice, 1)
+
+	switch c := twice.Cmp(r.Denom()); {
+	case c > 0, c == 0 && q.Bit(0) == 1:
+		if r.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+// sameCurrency returns the currency of the two amounts; the zero Money takes the other's
+// Returns ErrCurrencyMismatch (naming both currencies) if they differ
+func sameCurrency(a, b Money) (Currency, error) {
+	switch {
+	case a.currency == b.currency:
+		return a.currency, nil
+	case a.currency == "" && a.minor == 0:
+		return b.currency, nil
+	case b.currency == "" && b.minor == 0:
+		return a.currency, nil
+	}
+	return "", fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, a.currency, b.currency)
+}
@@ -0,0 +1,390 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFromFloatRoundsHalfToEven(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency Currency
+		want     int64
+	}{
+		{2.675, USD, 268}, // shortest decimal form, not the binary 2.67499...
+		{2.665, USD, 266},
+		{0.125, USD, 12},
+		{0.135, USD, 14},
+		{-0.125, USD, -12},
+		{-0.135, USD, -14},
+		{12.5, USD, 1250},
+		{1.5, VND, 2},
+		{2.5, VND, 2},
+		{-2.5, VND, -2},
+		{-3.5, VND, -4},
+		{130000, VND, 130000},
+		{0, EUR, 0},
+	}
+	for _, tt := range tests {
+		got, err := FromFloat(tt.amount, tt.currency)
+		if err != nil {
+			t.Fatalf("FromFloat(%v, %s): %v", tt.amount, tt.currency, err)
+		}
+		if got.Minor() != tt.want || got.Currency() != tt.currency {
+			t.Errorf("FromFloat(%v, %s) = %v, want %d minor units", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestFromFloatRejectsInvalidAmounts(t *testing.T) {
+	tests := []struct {
+		amount float64
+		want   error
+	}{
+		{math.NaN(), ErrInvalidAmount},
+		{math.Inf(1), ErrInvalidAmount},
+		{math.Inf(-1), ErrInvalidAmount},
+		{1e300, ErrOutOfRange},
+		{-1e300, ErrOutOfRange},
+	}
+	for _, tt := range tests {
+		if _, err := FromFloat(tt.amount, USD); !errors.Is(err, tt.want) {
+			t.Errorf("FromFloat(%v) error = %v, want %v", tt.amount, err, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		amount   string
+		currency Currency
+		want     int64
+	}{
+		{"12.5", USD, 1250},
+		{"12.345", USD, 1234}, // half to even
+		{"12.355", USD, 1236},
+		{"-12.345", USD, -1234},
+		{"-0.005", USD, 0},
+		{"+3", USD, 300},
+		{" 7.10 ", EUR, 710},
+		{".5", USD, 50},
+		{"5.", USD, 500},
+		{"130000", VND, 130000},
+		{"0.5", VND, 0},
+		{"1.5", VND, 2},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.amount, tt.currency)
+		if err != nil {
+			t.Fatalf("Parse(%q, %s): %v", tt.amount, tt.currency, err)
+		}
+		if got.Minor() != tt.want {
+			t.Errorf("Parse(%q, %s) = %d, want %d", tt.amount, tt.currency, got.Minor(), tt.want)
+		}
+	}
+
+	invalid := []string{"", ".", "-", "abc", "1e3", "1/2", "0x10", "1.2.3", "1,5", "--1"}
+	for _, amount := range invalid {
+		if _, err := Parse(amount, USD); !errors.Is(err, ErrInvalidAmount) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalidAmount", amount, err)
+		}
+	}
+	if _, err := Parse("999999999999999999999", VND); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Parse of an amount beyond int64 error = %v, want ErrOutOfRange", err)
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		m    Money
+		want string
+	}{
+		{New(1250, USD), "12.50 USD"},
+		{New(5, USD), "0.05 USD"},
+		{New(-5, USD), "-0.05 USD"},
+		{New(-1250, EUR), "-12.50 EUR"},
+		{New(130000, VND), "130000 VND"},
+		{Money{}, "0.00"},
+	}
+	for _, tt := range tests {
+		if got := tt.m.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestPercent(t *testing.T) {
+	tests := []struct {
+		m       Money
+		percent float64
+		want    int64
+	}{
+		{New(1000, VND), 2.5, 25},
+		{New(12345, VND), 5, 617},   // 617.25
+		{New(12350, VND), 5, 618},   // 617.5, half to even
+		{New(12370, VND), 5, 618},   // 618.5, half to even
+		{New(-12350, VND), 5, -618}, // symmetric for negative amounts
+		{New(999, USD), 0, 0},
+		{New(999, USD), 100, 999},
+		{New(1000, USD), -10, -100},
+		{New(1, USD), 0.1, 0},
+	}
+	for _, tt := range tests {
+		got, err := tt.m.Percent(tt.percent)
+		if err != nil {
+			t.Fatalf("%v.Percent(%v): %v", tt.m, tt.percent, err)
+		}
+		if got.Minor() != tt.want || got.Currency() != tt.m.Currency() {
+			t.Errorf("%v.Percent(%v) = %v, want %d minor units", tt.m, tt.percent, got, tt.want)
+		}
+	}
+
+	if _, err := New(100, USD).Percent(math.NaN()); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("Percent(NaN) error = %v, want ErrInvalidAmount", err)
+	}
+	if _, err := New(100, USD).Percent(math.Inf(1)); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("Percent(+Inf) error = %v, want ErrInvalidAmount", err)
+	}
+	if _, err := New(math.MaxInt64, USD).Percent(1e6); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Percent beyond int64 error = %v, want ErrOutOfRange", err)
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       Money
+		weights []int64
+		want    []int64
+	}{
+		{"even split, leftover to the earlier index", New(100, VND), []int64{1, 1, 1}, []int64{34, 33, 33}},
+		{"leftover to the largest remainder", New(10, VND), []int64{1, 2, 3}, []int64{2, 3, 5}},
+		{"negative amount mirrors the positive split", New(-100, VND), []int64{1, 1, 1}, []int64{-34, -33, -33}},
+		{"zero and negative weights get nothing", New(90, VND), []int64{0, 1, -4, 2}, []int64{0, 30, 0, 60}},
+		{"no positive weight splits nothing", New(90, VND), []int64{0, -1}, []int64{0, 0}},
+		{"zero amount", New(0, VND), []int64{1, 2}, []int64{0, 0}},
+		{"single weight takes all", New(1999, USD), []int64{7}, []int64{1999}},
+		{"no weights", New(50, VND), nil, []int64{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares := tt.m.Allocate(tt.weights)
+			if len(shares) != len(tt.want) {
+				t.Fatalf("got %d shares, want %d", len(shares), len(tt.want))
+			}
+			for i, share := range shares {
+				if share.Minor() != tt.want[i] || share.Currency() != tt.m.Currency() {
+					t.Errorf("share %d = %v, want %d minor units", i, share, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// TestAllocateInvariants checks random splits, negative amounts and non-positive weights
+// included: the shares add up to the amount (when a weight is positive), carry its sign
+// and each is within a unit of its exact share
+func TestAllocateInvariants(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for run := 0; run < 5000; run++ {
+		amount := rng.Int63n(20_000_000) - 10_000_000
+		weights := make([]int64, 1+rng.Intn(10))
+		sumWeights := int64(0)
+		for i := range weights {
+			weights[i] = rng.Int63n(1000) - 200 // zero and negative weights too
+			if weights[i] > 0 {
+				sumWeights += weights[i]
+			}
+		}
+
+		shares := New(amount, VND).Allocate(weights)
+
+		total := int64(0)
+		for i, share := range shares {
+			s := share.Minor()
+			total += s
+			if weights[i] <= 0 {
+				if s != 0 {
+					t.Fatalf("run %d: weight %d got share %d", run, weights[i], s)
+				}
+				continue
+			}
+			if (amount > 0 && s < 0) || (amount < 0 && s > 0) {
+				t.Fatalf("run %d: share %d has not the sign of %d", run, s, amount)
+			}
+			exact := float64(amount) * float64(weights[i]) / float64(sumWeights)
+			if math.Abs(float64(s)-exact) >= 1 {
+				t.Fatalf("run %d: share %d is not within a unit of %.3f", run, s, exact)
+			}
+		}
+
+		want := amount
+		if sumWeights == 0 {
+			want = 0
+		}
+		if total != want {
+			t.Fatalf("run %d: shares add up to %d, want %d (weights %v)", run, total, want, weights)
+		}
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		m    Money
+		json string
+	}{
+		{New(1250, USD), `{"amount":12.50,"currency":"USD"}`},
+		{New(-5, EUR), `{"amount":-0.05,"currency":"EUR"}`},
+		{New(130000, VND), `{"amount":130000,"currency":"VND"}`},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.m)
+		if err != nil {
+			t.Fatalf("Marshal(%v): %v", tt.m, err)
+		}
+		if string(data) != tt.json {
+			t.Errorf("Marshal(%v) = %s, want %s", tt.m, data, tt.json)
+		}
+
+		var decoded Money
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", data, err)
+		}
+		if decoded != tt.m {
+			t.Errorf("round trip of %v gave %v", tt.m, decoded)
+		}
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	var m Money
+	if err := json.Unmarshal([]byte(`{"amount":"12.345","currency":"USD"}`), &m); err != nil {
+		t.Fatalf("Unmarshal of a string amount: %v", err)
+	}
+	if m != New(1234, USD) {
+		t.Errorf("string amount decoded to %v, want 12.34 USD (half to even)", m)
+	}
+
+	invalid := []struct {
+		json string
+		want error
+	}{
+		{`{"amount":1,"currency":"usd"}`, ErrInvalidCurrency},
+		{`{"amount":1,"currency":""}`, ErrInvalidCurrency},
+		{`{"amount":1,"currency":"US1"}`, ErrInvalidCurrency},
+		{`{"amount":1e3,"currency":"USD"}`, ErrInvalidAmount},
+		{`{"amount":"1e3","currency":"USD"}`, ErrInvalidAmount},
+		{`{"amount":"99999999999999999999","currency":"VND"}`, ErrOutOfRange},
+	}
+	for _, tt := range invalid {
+		if err := json.Unmarshal([]byte(tt.json), &m); !errors.Is(err, tt.want) {
+			t.Errorf("Unmarshal(%s) error = %v, want %v", tt.json, err, tt.want)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a, b := New(1250, USD), New(-300, USD)
+	tests := []struct {
+		name string
+		op   func() (Money, error)
+		want Money
+	}{
+		{"Add", func() (Money, error) { return a.Add(b) }, New(950, USD)},
+		{"Sub", func() (Money, error) { return a.Sub(b) }, New(1550, USD)},
+		{"Mul", func() (Money, error) { return a.Mul(3) }, New(3750, USD)},
+		{"Mul by zero", func() (Money, error) { return a.Mul(0) }, Zero(USD)},
+		{"Min", func() (Money, error) { return a.Min(b) }, b},
+		{"Max", func() (Money, error) { return a.Max(b) }, a},
+	}
+	for _, tt := range tests {
+		if got, err := tt.op(); err != nil || got != tt.want {
+			t.Errorf("%s = %v, %v; want %v", tt.name, got, err, tt.want)
+		}
+	}
+	if got := b.Neg(); got != New(300, USD) {
+		t.Errorf("Neg = %v", got)
+	}
+	if got := b.NonNegative(); got != Zero(USD) {
+		t.Errorf("NonNegative = %v", got)
+	}
+	for _, tt := range []struct {
+		a, b Money
+		want int
+	}{{a, b, 1}, {b, a, -1}, {a, a, 0}} {
+		if got, err := tt.a.Cmp(tt.b); err != nil || got != tt.want {
+			t.Errorf("Cmp(%v, %v) = %d, %v; want %d", tt.a, tt.b, got, err, tt.want)
+		}
+	}
+}
+
+func TestArithmeticOverflow(t *testing.T) {
+	maxVND, minVND := New(math.MaxInt64, VND), New(math.MinInt64, VND)
+	ops := map[string]func() (Money, error){
+		"Add past max":      func() (Money, error) { return maxVND.Add(New(1, VND)) },
+		"Add past min":      func() (Money, error) { return minVND.Add(New(-1, VND)) },
+		"Sub past max":      func() (Money, error) { return maxVND.Sub(New(-1, VND)) },
+		"Sub past min":      func() (Money, error) { return minVND.Sub(New(1, VND)) },
+		"Mul past max":      func() (Money, error) { return New(math.MaxInt64/2+1, VND).Mul(2) },
+		"Mul past min":      func() (Money, error) { return New(math.MinInt64/2-1, VND).Mul(2) },
+		"Mul min by -1":     func() (Money, error) { return minVND.Mul(-1) },
+		"Mul -1 by min":     func() (Money, error) { return New(-1, VND).Mul(math.MinInt64) },
+		"Mul large squares": func() (Money, error) { return New(1<<32, VND).Mul(1 << 32) },
+	}
+	for name, op := range ops {
+		if got, err := op(); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("%s = %v, %v; want ErrOutOfRange", name, got, err)
+		}
+	}
+
+	// The edges themselves are still representable
+	if got, err := New(math.MaxInt64-1, VND).Add(New(1, VND)); err != nil || got != maxVND {
+		t.Errorf("(max - 1) + 1 = %v, %v", got, err)
+	}
+	if got, err := New(math.MinInt64+1, VND).Sub(New(1, VND)); err != nil || got != minVND {
+		t.Errorf("(min + 1) - 1 = %v, %v", got, err)
+	}
+	if got, err := New(math.MinInt64/2, VND).Mul(2); err != nil || got != minVND {
+		t.Errorf("min/2 * 2 = %v, %v", got, err)
+	}
+}
+
+func TestZeroMoneyTakesTheOtherCurrency(t *testing.T) {
+	var zero Money
+	if got, err := zero.Add(New(100, EUR)); err != nil || got != New(100, EUR) {
+		t.Errorf("zero + 1.00 EUR = %v, %v", got, err)
+	}
+	if got, err := New(100, EUR).Sub(zero); err != nil || got != New(100, EUR) {
+		t.Errorf("1.00 EUR - zero = %v, %v", got, err)
+	}
+	if got, err := zero.Min(New(-1, EUR)); err != nil || got != New(-1, EUR) {
+		t.Errorf("min(zero, -0.01 EUR) = %v, %v", got, err)
+	}
+}
+
+func TestCurrencyMismatch(t *testing.T) {
+	usd, eur := New(100, USD), New(100, EUR)
+	ops := map[string]func() error{
+		"Add": func() error { _, err := usd.Add(eur); return err },
+		"Sub": func() error { _, err := usd.Sub(eur); return err },
+		"Cmp": func() error { _, err := usd.Cmp(eur); return err },
+		"Min": func() error { _, err := usd.Min(eur); return err },
+		"Max": func() error { _, err := usd.Max(eur); return err },
+		// A non-zero amount without currency does not adopt one either
+		"Add no currency": func() error { _, err := New(1, "").Add(eur); return err },
+	}
+	for name, op := range ops {
+		if err := op(); !errors.Is(err, ErrCurrencyMismatch) {
+			t.Errorf("%s of different currencies: err = %v, want ErrCurrencyMismatch", name, err)
+		}
+	}
+}
+
+func TestCurrencyDigits(t *testing.T) {
+	if VND.Digits() != 0 || Currency("JPY").Digits() != 0 || USD.Digits() != 2 || EUR.Digits() != 2 {
+		t.Errorf("unexpected minor unit digits")
+	}
+}
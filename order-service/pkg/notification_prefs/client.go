@@ -0,0 +1,78 @@
+// Package notification_prefs reads the notification preferences users manage in
+// identity-service (GET/PUT /users/me/notification-preferences).
+//
+// identity-service mirrors each user's full preference matrix to Redis:
+//
+//	notification_preferences:{user_id} -> Hash {"{category}:{channel}": "1" | "0"}
+//
+// A missing hash or field means the user never changed it, so the defaults below apply
+// (they must match identity-service's domain.DefaultNotificationPreferences).
+package notification_prefs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const keyPrefix = "notification_preferences:"
+
+// Notification categories
+const (
+	CategoryOrderUpdates = "order_updates"
+	CategoryPromotions   = "promotions"
+	CategoryChat         = "chat"
+	CategoryPriceAlerts  = "price_alerts"
+)
+
+// Channels in delivery order
+var Channels = []string{"email", "sms", "push"}
+
+// defaults applies to every category/channel a user has not set
+var defaults = map[string]map[string]bool{
+	CategoryOrderUpdates: {"email": true, "sms": false, "push": true},
+	CategoryPromotions:   {"email": false, "sms": false, "push": false},
+	CategoryChat:         {"email": false, "sms": false, "push": true},
+	CategoryPriceAlerts:  {"email": true, "sms": false, "push": true},
+}
+
+// Client reads notification preferences from Redis
+type Client struct {
+	client *redis.Client
+	logger *zap.Logger
+}
+
+// NewClient creates a new notification preference client
+func NewClient(client *redis.Client, logger *zap.Logger) *Client {
+	return &Client{client: client, logger: logger}
+}
+
+// EnabledChannels returns the channels on which the user wants notifications of category
+// (empty = the user opted out). Falls back to the defaults if Redis is unavailable
+func (c *Client) EnabledChannels(ctx context.Context, userID uint, category string) []string {
+	fields := make([]string, len(Channels))
+	for i, channel := range Channels {
+		fields[i] = category + ":" + channel
+	}
+
+	values, err := c.client.HMGet(ctx, fmt.Sprintf("%s%d", keyPrefix, userID), fields...).Result()
+	if err != nil {
+		c.logger.Warn("failed to read notification preferences, using defaults",
+			zap.Uint("user_id", userID), zap.Error(err))
+		values = make([]interface{}, len(Channels))
+	}
+
+	var enabled []string
+	for i, channel := range Channels {
+		on := defaults[category][channel]
+		if v, ok := values[i].(string); ok {
+			on = v == "1"
+		}
+		if on {
+			enabled = append(enabled, channel)
+		}
+	}
+	return enabled
+}
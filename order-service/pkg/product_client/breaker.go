@@ -0,0 +1,94 @@
+package product_client
+
+import (
+	"sync"
+	"time"
+)
+
+// Circuit breaker states
+const (
+	stateClosed   = "closed"
+	stateOpen     = "open"
+	stateHalfOpen = "half_open"
+)
+
+// breaker is a consecutive-failure circuit breaker: after threshold failures it
+// rejects calls for cooldown, then lets one probe through (half-open) and closes
+// again if the probe succeeds
+type breaker struct {
+	mu        sync.Mutex
+	state     string
+	failures  int
+	openedAt  time.Time
+	threshold int
+	cooldown  time.Duration
+}
+
+func newBreaker(threshold int, cooldown time.Duration) *breaker {
+	return &breaker{state: stateClosed, threshold: threshold, cooldown: cooldown}
+}
+
+// allow reports whether a call may proceed
+func (b *breaker) allow() bool {
+	if b.threshold <= 0 {
+		return true // disabled
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case stateOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = stateHalfOpen
+		return true
+	case stateHalfOpen:
+		return false // a probe is already in flight
+	default:
+		return true
+	}
+}
+
+// success closes the circuit
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = stateClosed
+	b.failures = 0
+}
+
+// failure counts a failed call and reports whether the circuit just opened
+func (b *breaker) failure() bool {
+	if b.threshold <= 0 {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == stateHalfOpen || (b.state == stateClosed && b.failures >= b.threshold) {
+		b.state = stateOpen
+		b.openedAt = time.Now()
+		return true
+	}
+	return false
+}
+
+// abandon hands back a half-open probe that ended without a verdict (caller canceled)
+func (b *breaker) abandon() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == stateHalfOpen {
+		b.state = stateOpen // cooldown already passed, so the next call probes again
+	}
+}
+
+// current returns the breaker state (closed, open, half_open)
+func (b *breaker) current() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
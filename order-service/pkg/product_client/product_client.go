@@ -1,27 +1,87 @@
+// Package product_client is the typed client for Product Service.
+//
+// All calls take a context, retry transient failures (network errors, 5xx, 429)
+// with linear backoff and go through a circuit breaker, so a slow or failing
+// product-service degrades cart/checkout quickly instead of piling up requests.
 package product_client
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
+	"net/url"
+	"order-service/pkg/serviceauth"
+	"strconv"
+	"strings"
 	"time"
+
+	"go.uber.org/zap"
 )
 
+// ErrCircuitOpen is returned without calling product-service while the circuit is open
+var ErrCircuitOpen = errors.New("product service circuit open")
+
+// ErrNotFound is returned when product-service answers 404
+var ErrNotFound = errors.New("not found in product service")
+
+// APIError is a non-2xx response from product-service
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("product service returned error: %d - %s", e.StatusCode, e.Body)
+}
+
+// retryable reports whether the request may succeed if sent again
+func (e *APIError) retryable() bool {
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusTooManyRequests
+}
+
+// Options configures a ProductClient
+type Options struct {
+	BaseURL          string
+	Timeout          time.Duration       // per attempt
+	MaxRetries       int                 // retries after the first attempt
+	RetryBackoff     time.Duration       // delay before retry n is n*RetryBackoff
+	BreakerThreshold int                 // consecutive failures that open the circuit (0 disables it)
+	BreakerCooldown  time.Duration       // how long the circuit stays open before a probe
+	Signer           *serviceauth.Signer // signs requests for internal endpoints (nil = unsigned)
+}
+
 // ProductClient handles communication with Product Service
 type ProductClient struct {
-	baseURL    string
+	opts       Options
 	httpClient *http.Client
+	breaker    *breaker
+	logger     *zap.Logger
 }
 
 // NewProductClient creates a new product client
-func NewProductClient(baseURL string) *ProductClient {
+func NewProductClient(opts Options, logger *zap.Logger) *ProductClient {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = 100 * time.Millisecond
+	}
+	if opts.BreakerCooldown <= 0 {
+		opts.BreakerCooldown = 30 * time.Second
+	}
+	opts.BaseURL = strings.TrimRight(opts.BaseURL, "/")
+
 	return &ProductClient{
-		baseURL: baseURL,
+		opts: opts,
 		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout: opts.Timeout,
 		},
+		breaker: newBreaker(opts.BreakerThreshold, opts.BreakerCooldown),
+		logger:  logger,
 	}
 }
 
@@ -30,7 +90,10 @@ type Product struct {
 	ID        uint    `json:"id"`
 	ShopID    uint    `json:"shop_id"` // Required for marketplace
 	Name      string  `json:"name"`
+	Slug      string  `json:"slug"`
 	BasePrice float64 `json:"base_price"`
+	Status    string  `json:"status"`
+	IsActive  bool    `json:"is_active"`
 }
 
 // ProductItem represents SKU information from Product Service
@@ -43,173 +106,324 @@ type ProductItem struct {
 	QtyInStock int     `json:"qty_in_stock"`
 	Status     string  `json:"status"`
 
-	// Nested product info (if product-service returns it)
-	Product *struct {
-		ID     uint   `json:"id"`
-		ShopID uint   `json:"shop_id"`
-		Name   string `json:"name"`
-	} `json:"product,omitempty"`
+	// Nested product info (batch endpoint)
+	Product *ProductSummary `json:"product,omitempty"`
+
+	// Resolved variation options (batch endpoint)
+	Variations []Variation `json:"variations,omitempty"`
 }
 
-// GetProductByID retrieves product information by ID
-func (c *ProductClient) GetProductByID(productID uint) (*Product, error) {
-	return c.GetProductByIDInternal(productID)
+// Variation is a variation option of a SKU (e.g. Size L)
+type Variation struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
 }
 
-// GetProductByIDInternal is the internal implementation
-func (c *ProductClient) GetProductByIDInternal(productID uint) (*Product, error) {
-	url := fmt.Sprintf("%s/api/v1/products/%d", c.baseURL, productID)
+// ProductSummary is the product info nested in batch product item responses
+type ProductSummary struct {
+	ID                  uint   `json:"id"`
+	ShopID              uint   `json:"shop_id"`
+	Name                string `json:"name"`
+	MaxPurchaseQuantity int    `json:"max_purchase_quantity"` // 0 = no limit
+	ProductType         string `json:"product_type"`          // PHYSICAL, DIGITAL
+}
 
-	resp, err := c.httpClient.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to call product service: %w", err)
+// IsActive reports whether the SKU can be sold (product-service statuses are upper case)
+func (i *ProductItem) IsActive() bool {
+	return strings.EqualFold(i.Status, "ACTIVE")
+}
+
+// ProductName returns the nested product name (empty if not loaded)
+func (i *ProductItem) ProductName() string {
+	if i.Product == nil {
+		return ""
 	}
-	defer resp.Body.Close()
+	return i.Product.Name
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("product service returned error: %d - %s", resp.StatusCode, string(body))
+// ShopID returns the owning shop from the nested product (0 if not loaded)
+func (i *ProductItem) ShopID() uint {
+	if i.Product == nil {
+		return 0
 	}
+	return i.Product.ShopID
+}
 
-	var product Product
-	if err := json.NewDecoder(resp.Body).Decode(&product); err != nil {
-		return nil, fmt.Errorf("failed to decode product response: %w", err)
+// MaxPurchaseQuantity returns the per-customer limit of the product (0 = no limit or not loaded)
+func (i *ProductItem) MaxPurchaseQuantity() int {
+	if i.Product == nil {
+		return 0
+	}
+	return i.Product.MaxPurchaseQuantity
+}
+
+// IsDigital reports whether the SKU belongs to a DIGITAL product (fulfilled with codes, not shipped)
+func (i *ProductItem) IsDigital() bool {
+	return i.Product != nil && strings.EqualFold(i.Product.ProductType, "DIGITAL")
+}
+
+// VariationLabel returns the variation options as one label, e.g. "Size L, Màu Đen"
+func (i *ProductItem) VariationLabel() string {
+	parts := make([]string, 0, len(i.Variations))
+	for _, v := range i.Variations {
+		parts = append(parts, strings.TrimSpace(v.Name+" "+v.Value))
 	}
+	return strings.Join(parts, ", ")
+}
 
+// BreakerState returns the circuit breaker state (closed, open, half_open)
+func (c *ProductClient) BreakerState() string {
+	return c.breaker.current()
+}
+
+// GetProduct retrieves product information by ID
+func (c *ProductClient) GetProduct(ctx context.Context, productID uint) (*Product, error) {
+	var product Product
+	if err := c.get(ctx, fmt.Sprintf("/api/v1/products/%d", productID), &product); err != nil {
+		return nil, err
+	}
 	return &product, nil
 }
 
-// GetProductItemByID retrieves product item (SKU) information by ID
-func (c *ProductClient) GetProductItemByID(productItemID uint) (*ProductItem, error) {
-	// Note: Product Service doesn't have direct GET /product-items/:id endpoint
-	// We need to get it through product_id first, then find the item
-	// For now, return error - will need to implement proper endpoint
-	return nil, fmt.Errorf("get product item by ID not yet implemented - need product_id")
+// GetProductItemsByProduct retrieves all SKUs of a product
+func (c *ProductClient) GetProductItemsByProduct(ctx context.Context, productID uint) ([]*ProductItem, error) {
+	var response struct {
+		Items []*ProductItem `json:"items"`
+		Count int            `json:"count"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("/api/v1/products/%d/items", productID), &response); err != nil {
+		return nil, err
+	}
+	return response.Items, nil
 }
 
-// GetProductItemByProductID retrieves product items for a product
-func (c *ProductClient) GetProductItemByProductID(productID uint) ([]*ProductItem, error) {
-	url := fmt.Sprintf("%s/api/v1/products/%d/items", c.baseURL, productID)
+// GetProductItemBySKU retrieves a SKU by its code
+func (c *ProductClient) GetProductItemBySKU(ctx context.Context, skuCode string) (*ProductItem, error) {
+	var item ProductItem
+	if err := c.get(ctx, "/api/v1/product-items/"+url.PathEscape(skuCode), &item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
 
-	resp, err := c.httpClient.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to call product service: %w", err)
+// GetProductItems retrieves multiple SKUs (with nested product info) in one call:
+// GET /api/v1/product-items/batch?ids=1,2,3
+// SKUs that do not exist (or are discontinued) are missing from the result
+func (c *ProductClient) GetProductItems(ctx context.Context, productItemIDs []uint) (map[uint]*ProductItem, error) {
+	result := make(map[uint]*ProductItem, len(productItemIDs))
+	if len(productItemIDs) == 0 {
+		return result, nil
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("product service returned error: %d - %s", resp.StatusCode, string(body))
+	ids := make([]string, len(productItemIDs))
+	for i, id := range productItemIDs {
+		ids[i] = strconv.FormatUint(uint64(id), 10)
 	}
 
 	var response struct {
 		Items []*ProductItem `json:"items"`
-		Count int            `json:"count"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode product items response: %w", err)
+	if err := c.get(ctx, "/api/v1/product-items/batch?ids="+strings.Join(ids, ","), &response); err != nil {
+		return nil, err
 	}
 
-	return response.Items, nil
+	for _, item := range response.Items {
+		result[item.ID] = item
+	}
+
+	c.logger.Debug("fetched product items from product service",
+		zap.Int("requested", len(productItemIDs)),
+		zap.Int("received", len(result)),
+	)
+	return result, nil
+}
+
+// GetCategoryShopIDs returns the IDs of the shops selling an active product in the category
+// or its subcategories: GET /api/v1/categories/:id/shops (ErrNotFound for an unknown category)
+func (c *ProductClient) GetCategoryShopIDs(ctx context.Context, categoryID uint) ([]uint, error) {
+	var response struct {
+		ShopIDs []uint `json:"shop_ids"`
+	}
+	if err := c.get(ctx, fmt.Sprintf("/api/v1/categories/%d/shops", categoryID), &response); err != nil {
+		return nil, err
+	}
+	return response.ShopIDs, nil
+}
+
+// DigitalCodeItem is an order line to fulfill with digital codes
+type DigitalCodeItem struct {
+	ProductItemID uint `json:"product_item_id"`
+	Quantity      int  `json:"quantity"`
 }
 
-// GetProductItemBySKUCode retrieves product item by SKU code
-func (c *ProductClient) GetProductItemBySKUCode(skuCode string) (*ProductItem, error) {
-	url := fmt.Sprintf("%s/api/v1/product-items/%s", c.baseURL, skuCode)
+// DigitalCode is a code issued to an order
+type DigitalCode struct {
+	ProductItemID uint       `json:"product_item_id"`
+	Code          string     `json:"code"`
+	IssuedAt      *time.Time `json:"issued_at,omitempty"`
+}
 
-	resp, err := c.httpClient.Get(url)
+// IssueDigitalCodes issues codes for the digital lines of a paid order:
+// POST /api/v1/product-items/issue-codes
+// Idempotent per order number, so it is safe to retry; returns all codes of the order
+func (c *ProductClient) IssueDigitalCodes(ctx context.Context, orderNumber string, items []DigitalCodeItem) ([]DigitalCode, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"order_id": orderNumber,
+		"items":    items,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to call product service: %w", err)
+		return nil, fmt.Errorf("failed to encode digital code request: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("product service returned error: %d - %s", resp.StatusCode, string(body))
+	var response struct {
+		Codes []DigitalCode `json:"codes"`
 	}
+	if err := c.call(ctx, http.MethodPost, "/api/v1/product-items/issue-codes", body, &response); err != nil {
+		return nil, err
+	}
+	return response.Codes, nil
+}
 
-	var item ProductItem
-	if err := json.NewDecoder(resp.Body).Decode(&item); err != nil {
-		return nil, fmt.Errorf("failed to decode product item response: %w", err)
+// GetDigitalCodes retrieves the codes issued to an order:
+// GET /api/v1/product-items/issued-codes?order_id=
+func (c *ProductClient) GetDigitalCodes(ctx context.Context, orderNumber string) ([]DigitalCode, error) {
+	var response struct {
+		Codes []DigitalCode `json:"codes"`
 	}
+	if err := c.get(ctx, "/api/v1/product-items/issued-codes?order_id="+url.QueryEscape(orderNumber), &response); err != nil {
+		return nil, err
+	}
+	return response.Codes, nil
+}
 
-	return &item, nil
+// StockItem is a quantity of a SKU to reserve or deduct
+type StockItem struct {
+	ProductItemID uint `json:"product_item_id"`
+	Quantity      int  `json:"quantity"`
 }
 
-// GetProductsByIDs retrieves multiple products by IDs (batch)
-func (c *ProductClient) GetProductsByIDs(productIDs []uint) (map[uint]*Product, error) {
-	products := make(map[uint]*Product)
+// ReserveStock holds stock for an order until it is deducted, released or expires:
+// POST /api/v1/product-items/reserve-stock
+// Idempotent per (reservation, item); answers 409 (APIError) if any item is short,
+// in which case nothing stays reserved
+func (c *ProductClient) ReserveStock(ctx context.Context, reservationID string, items []StockItem) error {
+	return c.stockCall(ctx, "/api/v1/product-items/reserve-stock", map[string]interface{}{
+		"order_id": reservationID,
+		"items":    items,
+	})
+}
 
-	// For now, fetch sequentially (can optimize with batch endpoint later)
-	for _, id := range productIDs {
-		product, err := c.GetProductByID(id)
-		if err != nil {
-			// Log error but continue with other products
-			continue
-		}
-		products[id] = product
-	}
+// DeductStock permanently takes the stock of a paid order and releases its reservation:
+// POST /api/v1/product-items/deduct-stock
+// Idempotent per (reservation, item), so it is safe to retry
+func (c *ProductClient) DeductStock(ctx context.Context, reservationID string, items []StockItem) error {
+	return c.stockCall(ctx, "/api/v1/product-items/deduct-stock", map[string]interface{}{
+		"order_id": reservationID,
+		"items":    items,
+	})
+}
 
-	return products, nil
+// ReleaseStock gives an order's reserved stock back: POST /api/v1/product-items/release-stock
+// Idempotent: releasing an unknown or already released reservation succeeds
+func (c *ProductClient) ReleaseStock(ctx context.Context, reservationID string) error {
+	return c.stockCall(ctx, "/api/v1/product-items/release-stock", map[string]interface{}{
+		"order_id": reservationID,
+	})
 }
 
-// GetProductItems retrieves multiple product items by IDs (batch) for cart
-// This should call a batch endpoint in product-service: GET /api/v1/product-items/batch?ids=1,2,3
-func (c *ProductClient) GetProductItems(productItemIDs []uint) (map[uint]*ProductItem, error) {
-	if len(productItemIDs) == 0 {
-		return make(map[uint]*ProductItem), nil
+// stockCall sends a stock request; the response body is not used
+func (c *ProductClient) stockCall(ctx context.Context, path string, request map[string]interface{}) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to encode stock request: %w", err)
 	}
+	var response json.RawMessage
+	return c.call(ctx, http.MethodPost, path, body, &response)
+}
 
-	// Build URL with query params: /api/v1/product-items/batch?ids=1,2,3
-	url := fmt.Sprintf("%s/api/v1/product-items/batch?ids=", c.baseURL)
-	for i, id := range productItemIDs {
-		if i > 0 {
-			url += ","
+// get performs a GET with retries and circuit breaking and decodes the JSON body into out
+func (c *ProductClient) get(ctx context.Context, path string, out interface{}) error {
+	return c.call(ctx, http.MethodGet, path, nil, out)
+}
+
+// call sends a request with retries and circuit breaking and decodes the JSON body into out
+// Only idempotent requests may be sent with a body, since failed attempts are retried
+func (c *ProductClient) call(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	var err error
+	for attempt := 0; attempt <= c.opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(attempt) * c.opts.RetryBackoff):
+			}
 		}
-		url += fmt.Sprintf("%d", id)
-	}
 
-	log.Println("[DEBUG] Calling product-service:", url)
+		if !c.breaker.allow() {
+			return ErrCircuitOpen
+		}
 
-	resp, err := c.httpClient.Get(url)
-	if err != nil {
-		log.Println("[ERROR] Failed to call product service:", err)
-		return nil, fmt.Errorf("failed to call product service: %w", err)
-	}
-	defer resp.Body.Close()
+		err = c.do(ctx, method, path, body, out)
+
+		var apiErr *APIError
+		switch {
+		case err == nil, errors.Is(err, ErrNotFound), errors.As(err, &apiErr) && !apiErr.retryable():
+			// product-service answered - it is healthy even if the answer is an error
+			c.breaker.success()
+			return err
+		case ctx.Err() != nil:
+			c.breaker.abandon()
+			return err
+		}
 
-	log.Println("[DEBUG] Response status:", resp.StatusCode)
+		if c.breaker.failure() {
+			c.logger.Warn("product service circuit opened",
+				zap.String("path", path),
+				zap.Duration("cooldown", c.opts.BreakerCooldown),
+				zap.Error(err),
+			)
+		}
+		c.logger.Debug("product service call failed",
+			zap.String("path", path),
+			zap.Int("attempt", attempt+1),
+			zap.Error(err),
+		)
+	}
+	return err
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		log.Println("[ERROR] Product service error:", string(body))
-		return nil, fmt.Errorf("product service batch endpoint returned error: %d - %s", resp.StatusCode, string(body))
+// do sends one request
+func (c *ProductClient) do(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.opts.BaseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build product service request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
 	}
+	c.opts.Signer.Sign(req)
 
-	// Read body for debugging
-	bodyBytes, err := io.ReadAll(resp.Body)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		log.Println("[ERROR] Failed to read response body:", err)
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return fmt.Errorf("failed to call product service: %w", err)
 	}
-	log.Println("[DEBUG] Product-service response:", string(bodyBytes))
+	defer resp.Body.Close()
 
-	var response struct {
-		Items []*ProductItem `json:"items"`
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
 	}
-	if err := json.Unmarshal(bodyBytes, &response); err != nil {
-		log.Println("[ERROR] Failed to decode response:", err)
-		return nil, fmt.Errorf("failed to decode batch product items response: %w", err)
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &APIError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
-	log.Printf("[DEBUG] Decoded %d items\n", len(response.Items))
-
-	// Convert to map
-	result := make(map[uint]*ProductItem)
-	for _, item := range response.Items {
-		log.Printf("[DEBUG] Item ID=%d, Product=%+v\n", item.ID, item.Product)
-		result[item.ID] = item
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode product service response: %w", err)
 	}
-
-	return result, nil
+	return nil
 }
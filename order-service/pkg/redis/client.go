@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -30,8 +31,17 @@ func GetClient(cfg *config.RedisConfig) (*redis.Client, error) {
 			DB:           cfg.DB,
 			PoolSize:     cfg.PoolSize,
 			MinIdleConns: cfg.MinIdleConns,
+			DialTimeout:  cfg.DialTimeout,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+			PoolTimeout:  cfg.PoolTimeout,
 		})
 
+		// Pool usage (hits/misses/timeouts, connections) on /metrics
+		if regErr := prometheus.Register(newPoolCollector(clientInstance)); regErr != nil {
+			log.Printf("Failed to register Redis pool metrics: %v", regErr)
+		}
+
 		// Test connection
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
@@ -0,0 +1,45 @@
+package redis
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	poolHitsDesc     = prometheus.NewDesc("redis_pool_hits_total", "Times a free connection was found in the pool.", nil, nil)
+	poolMissesDesc   = prometheus.NewDesc("redis_pool_misses_total", "Times a free connection was not found in the pool.", nil, nil)
+	poolTimeoutsDesc = prometheus.NewDesc("redis_pool_timeouts_total", "Times waiting for a connection exceeded pool_timeout.", nil, nil)
+	poolTotalDesc    = prometheus.NewDesc("redis_pool_connections", "Connections in the pool.", nil, nil)
+	poolIdleDesc     = prometheus.NewDesc("redis_pool_idle_connections", "Idle connections in the pool.", nil, nil)
+	poolStaleDesc    = prometheus.NewDesc("redis_pool_stale_connections_total", "Stale connections removed from the pool.", nil, nil)
+)
+
+// poolCollector exports the connection pool statistics of a Redis client
+type poolCollector struct {
+	client *redis.Client
+}
+
+func newPoolCollector(client *redis.Client) *poolCollector {
+	return &poolCollector{client: client}
+}
+
+// Describe implements prometheus.Collector
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolHitsDesc
+	ch <- poolMissesDesc
+	ch <- poolTimeoutsDesc
+	ch <- poolTotalDesc
+	ch <- poolIdleDesc
+	ch <- poolStaleDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.client.PoolStats()
+	ch <- prometheus.MustNewConstMetric(poolHitsDesc, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(poolMissesDesc, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(poolTimeoutsDesc, prometheus.CounterValue, float64(stats.Timeouts))
+	ch <- prometheus.MustNewConstMetric(poolTotalDesc, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(poolIdleDesc, prometheus.GaugeValue, float64(stats.IdleConns))
+	ch <- prometheus.MustNewConstMetric(poolStaleDesc, prometheus.CounterValue, float64(stats.StaleConns))
+}
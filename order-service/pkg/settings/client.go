@@ -0,0 +1,132 @@
+package settings
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Redis contract with identity-service (admin global configuration)
+const (
+	settingsKeyPrefix      = "settings:"        // settings:{scope} -> Hash {key: value}
+	SettingsChangedChannel = "settings:changed" // Pub/Sub channel for change events
+)
+
+// changedEvent is the payload published by identity-service when a setting changes
+type changedEvent struct {
+	Scope string `json:"scope"`
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Client reads admin-managed settings from Redis
+// Values are kept in a local map and refreshed on change events,
+// so hot paths (checkout, add to cart) don't hit Redis every time
+type Client struct {
+	client *redis.Client
+	logger *zap.Logger
+	mu     sync.RWMutex
+	values map[string]string // "{scope}:{key}" -> value
+}
+
+// NewClient creates a new settings client
+func NewClient(client *redis.Client, logger *zap.Logger) *Client {
+	return &Client{
+		client: client,
+		logger: logger,
+		values: make(map[string]string),
+	}
+}
+
+// Start subscribes to setting change events until ctx is cancelled
+// Should be run in a goroutine
+func (c *Client) Start(ctx context.Context) {
+	pubsub := c.client.Subscribe(ctx, SettingsChangedChannel)
+	defer pubsub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-pubsub.Channel():
+			if !ok {
+				return
+			}
+			var event changedEvent
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				c.logger.Warn("invalid setting change event", zap.Error(err))
+				continue
+			}
+			c.mu.Lock()
+			c.values[cacheKey(event.Scope, event.Key)] = event.Value
+			c.mu.Unlock()
+			c.logger.Info("setting changed",
+				zap.String("scope", event.Scope),
+				zap.String("key", event.Key),
+				zap.String("value", event.Value),
+			)
+		}
+	}
+}
+
+// GetString returns a setting value, or def if it is not configured
+func (c *Client) GetString(scope, key, def string) string {
+	k := cacheKey(scope, key)
+
+	c.mu.RLock()
+	value, ok := c.values[k]
+	c.mu.RUnlock()
+	if ok {
+		return value
+	}
+
+	// Not loaded yet - read from Redis hash
+	value, err := c.client.HGet(context.Background(), settingsKeyPrefix+scope, key).Result()
+	if err != nil {
+		if err != redis.Nil {
+			c.logger.Warn("failed to read setting, using default", zap.String("scope", scope), zap.String("key", key), zap.Error(err))
+		}
+		return def
+	}
+
+	c.mu.Lock()
+	c.values[k] = value
+	c.mu.Unlock()
+	return value
+}
+
+// GetInt returns an integer setting, or def if missing/invalid
+func (c *Client) GetInt(scope, key string, def int) int {
+	value, err := strconv.Atoi(c.GetString(scope, key, strconv.Itoa(def)))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// GetFloat returns a float setting, or def if missing/invalid
+func (c *Client) GetFloat(scope, key string, def float64) float64 {
+	value, err := strconv.ParseFloat(c.GetString(scope, key, ""), 64)
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// GetBool returns a boolean setting, or def if missing/invalid
+func (c *Client) GetBool(scope, key string, def bool) bool {
+	value, err := strconv.ParseBool(c.GetString(scope, key, strconv.FormatBool(def)))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+func cacheKey(scope, key string) string {
+	return fmt.Sprintf("%s:%s", scope, key)
+}
@@ -0,0 +1,68 @@
+package shop_client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ShopClient handles communication with Identity Service (shops)
+type ShopClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewShopClient creates a new shop client
+func NewShopClient(baseURL string, timeout time.Duration) *ShopClient {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &ShopClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// Shop is the public shop info from Identity Service
+type Shop struct {
+	ID          uint   `json:"id"`
+	OwnerUserID uint   `json:"owner_user_id"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	LogoURL     string `json:"logo_url"`
+	Status      string `json:"status"`
+}
+
+// GetShop retrieves a shop by ID
+func (c *ShopClient) GetShop(ctx context.Context, shopID uint) (*Shop, error) {
+	url := fmt.Sprintf("%s/api/v1/shops/%d", c.baseURL, shopID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build identity service request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call identity service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("identity service returned error: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var shop Shop
+	if err := json.NewDecoder(resp.Body).Decode(&shop); err != nil {
+		return nil, fmt.Errorf("failed to decode shop: %w", err)
+	}
+	return &shop, nil
+}
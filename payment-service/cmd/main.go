@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"payment-service/config"
+	"payment-service/internal/domain"
+	"payment-service/internal/handler"
+	"payment-service/internal/repository/kafka"
+	"payment-service/internal/repository/postgres"
+	"payment-service/internal/router"
+	"payment-service/internal/service"
+	"payment-service/pkg/database"
+	"payment-service/pkg/logger"
+	"payment-service/pkg/money"
+	"payment-service/pkg/order_client"
+	"payment-service/pkg/provider"
+	"payment-service/pkg/serviceauth"
+	"payment-service/pkg/shutdown"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func main() {
+	log.Println("🚀 Starting Payment Service...")
+
+	// Load configuration
+	cfg, err := config.LoadConfig("./config")
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Initialize logger
+	appLogger, err := logger.NewLogger(&cfg.Logging)
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer appLogger.Sync()
+
+	appLogger.Info("Starting Payment Service...")
+
+	// Set Gin mode based on config
+	gin.SetMode(cfg.Server.Mode)
+
+	// Initialize database connection (Singleton)
+	db, err := database.GetDB(&cfg.Database)
+	if err != nil {
+		appLogger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+	defer database.CloseDB()
+
+	// Run database migrations (decimal amounts move to minor units first)
+	if err := postgres.MigratePaymentIntentAmounts(db); err != nil {
+		appLogger.Fatal("Failed to migrate payment intent amounts", zap.Error(err))
+	}
+	if err := db.AutoMigrate(&domain.PaymentIntent{}); err != nil {
+		appLogger.Fatal("Failed to run migrations", zap.Error(err))
+	}
+	appLogger.Info("Database migrations completed")
+
+	// Initialize Kafka event publisher (one topic per event type)
+	appLogger.Info("Initializing Kafka event publisher",
+		zap.Strings("brokers", cfg.Kafka.Brokers),
+		zap.String("topic_prefix", cfg.Kafka.TopicPrefix),
+	)
+	eventPublisher := kafka.NewEventPublisher(kafka.PublisherOptions{
+		Brokers:      cfg.Kafka.Brokers,
+		Topics:       kafka.Topics{Prefix: cfg.Kafka.TopicPrefix, Overrides: cfg.Kafka.Topics},
+		WriteTimeout: cfg.Kafka.WriteTimeout,
+		RequiredAcks: cfg.Kafka.RequiredAcks,
+		MaxRetries:   cfg.Kafka.MaxRetries,
+		RetryBackoff: cfg.Kafka.RetryBackoff,
+	})
+
+	// Shutdown coordinator drains the service in order on exit
+	coordinator := shutdown.NewCoordinator(appLogger)
+
+	// Initialize Order Service client (calls are signed as payment_service)
+	orderClient := order_client.NewOrderClient(
+		cfg.OrderService.BaseURL,
+		cfg.OrderService.Timeout,
+		serviceauth.NewSigner(cfg.InternalAuth.ServiceName, cfg.InternalAuth.Secret),
+	)
+
+	// Initialize repository, service and handler
+	intentRepo := postgres.NewPaymentIntentRepository(db)
+	paymentService := service.NewPaymentService(
+		intentRepo,
+		&service.OrderClientAdapter{Client: orderClient},
+		provider.NewSandboxProvider(),
+		eventPublisher,
+		service.PaymentOptions{
+			Currency:      money.Currency(cfg.Payment.Currency),
+			IntentTTL:     cfg.Payment.IntentTTL,
+			MaxAttempts:   cfg.Payment.MaxAttempts,
+			SweepInterval: cfg.Payment.SweepInterval,
+		},
+		appLogger,
+	)
+	paymentHandler := handler.NewPaymentHandler(paymentService, appLogger)
+
+	// Expire stale intents and resend events that could not be published
+	sweeperCtx, stopSweeper := context.WithCancel(context.Background())
+	sweeperDone := make(chan struct{})
+	go func() {
+		paymentService.Start(sweeperCtx)
+		close(sweeperDone)
+	}()
+
+	// Setup router
+	router := router.SetupRouter(paymentHandler)
+
+	// Create HTTP server
+	srv := &http.Server{
+		Addr:         fmt.Sprintf(":%d", cfg.Server.Port),
+		Handler:      router,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+	}
+
+	// Start server in a goroutine
+	go func() {
+		appLogger.Info("Server starting", zap.Int("port", cfg.Server.Port))
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			appLogger.Fatal("Failed to start server", zap.Error(err))
+		}
+	}()
+
+	// Wait for interrupt signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	appLogger.Info("Shutting down server...")
+
+	// Drain in order: stop accepting requests (in-flight confirmations finish publishing),
+	// stop the sweeper, then close the Kafka writer
+	coordinator.OnShutdown("http server", srv.Shutdown)
+	coordinator.OnShutdown("payment sweeper", func(ctx context.Context) error {
+		stopSweeper()
+		select {
+		case <-sweeperDone:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	coordinator.OnShutdown("kafka publisher", func(context.Context) error {
+		return eventPublisher.Close()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	coordinator.Shutdown(ctx)
+
+	appLogger.Info("Server exited gracefully")
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Config holds all configuration for the Payment Service
+type Config struct {
+	Server   ServerConfig
+	Database DatabaseConfig
+	Kafka    KafkaConfig
+	Logging  LoggingConfig
+	Payment  PaymentConfig
+
+	OrderService OrderServiceConfig `mapstructure:"order_service"`
+	InternalAuth InternalAuthConfig `mapstructure:"internal_auth"`
+}
+
+// PaymentConfig holds the payment intent lifecycle configuration
+type PaymentConfig struct {
+	Currency      string        `mapstructure:"currency"`       // ISO 4217 code of intent amounts
+	IntentTTL     time.Duration `mapstructure:"intent_ttl"`     // Unconfirmed intents fail after this (payment_failed, reason expired)
+	MaxAttempts   int           `mapstructure:"max_attempts"`   // Declined confirmations before the intent fails
+	SweepInterval time.Duration `mapstructure:"sweep_interval"` // How often expired intents are failed and unpublished events resent
+}
+
+// OrderServiceConfig holds Order Service client configuration (order amount and status)
+type OrderServiceConfig struct {
+	BaseURL string        `mapstructure:"base_url"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// InternalAuthConfig holds service-to-service authentication with signed service tokens
+type InternalAuthConfig struct {
+	ServiceName string `mapstructure:"service_name"` // Name this service signs its outgoing calls with
+	Secret      string `mapstructure:"secret"`       // Signing secret of this service
+}
+
+// KafkaConfig holds Kafka configuration
+type KafkaConfig struct {
+	Brokers      []string          `mapstructure:"brokers"`
+	TopicPrefix  string            `mapstructure:"topic_prefix"` // One topic per event type: <prefix>payment.succeeded, ...
+	Topics       map[string]string `mapstructure:"topics"`       // Event type -> topic override
+	WriteTimeout time.Duration     `mapstructure:"write_timeout"`
+	RequiredAcks int               `mapstructure:"required_acks"`
+	MaxRetries   int               `mapstructure:"max_retries"`
+	RetryBackoff time.Duration     `mapstructure:"retry_backoff"`
+}
+
+// ServerConfig holds HTTP server configuration
+type ServerConfig struct {
+	Port         int
+	Mode         string
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+}
+
+// DatabaseConfig holds PostgreSQL connection configuration
+type DatabaseConfig struct {
+	Host     string
+	Port     int
+	User     string
+	Password string
+	DBName   string
+	SSLMode  string
+
+	// Connection pool (database/sql)
+	MaxOpenConns    int           `mapstructure:"max_open_conns"`
+	MaxIdleConns    int           `mapstructure:"max_idle_conns"`     // At most MaxOpenConns
+	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`  // Recycle connections (e.g. after a failover)
+	ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time"` // Close idle connections after a traffic peak
+}
+
+// LoggingConfig holds logging configuration
+type LoggingConfig struct {
+	Level            string
+	Encoding         string
+	OutputPaths      []string
+	ErrorOutputPaths []string
+}
+
+// LoadConfig reads configuration from config.yaml and environment variables
+func LoadConfig(configPath string) (*Config, error) {
+	viper.SetConfigName("config")
+	viper.SetConfigType("yaml")
+	viper.AddConfigPath(configPath)
+	viper.AddConfigPath(".")
+	viper.AddConfigPath("./config")
+
+	// Enable environment variable support
+	viper.AutomaticEnv()
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.SetEnvPrefix("")
+
+	// Set defaults
+	setDefaults()
+
+	// Read config file (optional - env vars will override)
+	if err := viper.ReadInConfig(); err != nil {
+		log.Printf("Warning: Could not read config file: %v. Using defaults and environment variables.", err)
+	} else {
+		log.Printf("Loaded config from: %s", viper.ConfigFileUsed())
+	}
+
+	config := &Config{}
+
+	// Unmarshal configuration into struct
+	if err := viper.Unmarshal(config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	// Fail fast on settings that would only break under load (pools, timeouts)
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return config, nil
+}
+
+// setDefaults sets default values for configuration
+func setDefaults() {
+	// Server defaults
+	viper.SetDefault("server.port", 8006)
+	viper.SetDefault("server.mode", "debug")
+	viper.SetDefault("server.read_timeout", "30s")
+	viper.SetDefault("server.write_timeout", "30s")
+
+	// Database defaults
+	viper.SetDefault("database.host", "localhost")
+	viper.SetDefault("database.port", 5433)
+	viper.SetDefault("database.user", "postgres")
+	viper.SetDefault("database.password", "postgres")
+	viper.SetDefault("database.dbname", "payment_service")
+	viper.SetDefault("database.sslmode", "disable")
+	viper.SetDefault("database.max_open_conns", 25)
+	viper.SetDefault("database.max_idle_conns", 5)
+	viper.SetDefault("database.conn_max_lifetime", "5m")
+	viper.SetDefault("database.conn_max_idle_time", "1m")
+
+	// Kafka defaults
+	viper.SetDefault("kafka.brokers", []string{"localhost:9092"})
+	viper.SetDefault("kafka.topic_prefix", "")
+	viper.SetDefault("kafka.write_timeout", "10s")
+	viper.SetDefault("kafka.required_acks", -1)
+	viper.SetDefault("kafka.max_retries", 3)
+	viper.SetDefault("kafka.retry_backoff", "200ms")
+
+	// Payment intent defaults
+	viper.SetDefault("payment.currency", "VND")
+	viper.SetDefault("payment.intent_ttl", "30m")
+	viper.SetDefault("payment.max_attempts", 3)
+	viper.SetDefault("payment.sweep_interval", "30s")
+
+	// Logging defaults
+	viper.SetDefault("logging.level", "info")
+	viper.SetDefault("logging.encoding", "json")
+	viper.SetDefault("logging.output_paths", []string{"stdout"})
+	viper.SetDefault("logging.error_output_paths", []string{"stderr"})
+
+	// Order Service defaults
+	viper.SetDefault("order_service.base_url", "http://localhost:8083")
+	viper.SetDefault("order_service.timeout", "5s")
+
+	// Service-to-service authentication defaults
+	viper.SetDefault("internal_auth.service_name", "payment_service")
+}
+
+// GetDSN returns the PostgreSQL Data Source Name
+func (c *DatabaseConfig) GetDSN() string {
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		c.Host, c.Port, c.User, c.Password, c.DBName, c.SSLMode)
+}
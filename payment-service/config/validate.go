@@ -0,0 +1,62 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Validate checks the database pool and payment lifecycle settings, so a bad value
+// fails at startup instead of under load or on the first payment
+func (c *Config) Validate() error {
+	return errors.Join(
+		c.Database.Validate(),
+		c.Payment.Validate(),
+		c.InternalAuth.Validate(),
+	)
+}
+
+// Validate checks the PostgreSQL connection and pool settings
+func (c *DatabaseConfig) Validate() error {
+	var errs []error
+	if c.Host == "" || c.DBName == "" {
+		errs = append(errs, errors.New("database: host and dbname are required"))
+	}
+	if c.MaxOpenConns <= 0 {
+		errs = append(errs, fmt.Errorf("database: max_open_conns must be positive, got %d", c.MaxOpenConns))
+	}
+	if c.MaxIdleConns < 0 || c.MaxIdleConns > c.MaxOpenConns {
+		errs = append(errs, fmt.Errorf("database: max_idle_conns must be between 0 and max_open_conns (%d), got %d", c.MaxOpenConns, c.MaxIdleConns))
+	}
+	if c.ConnMaxLifetime < 0 || c.ConnMaxIdleTime < 0 {
+		errs = append(errs, errors.New("database: conn_max_lifetime and conn_max_idle_time must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks the payment intent lifecycle settings
+func (c *PaymentConfig) Validate() error {
+	var errs []error
+	if len(c.Currency) != 3 {
+		errs = append(errs, fmt.Errorf("payment: currency must be an ISO 4217 code, got %q", c.Currency))
+	}
+	if c.IntentTTL <= 0 || c.SweepInterval <= 0 {
+		errs = append(errs, errors.New("payment: intent_ttl and sweep_interval must be positive"))
+	}
+	if c.MaxAttempts <= 0 {
+		errs = append(errs, fmt.Errorf("payment: max_attempts must be positive, got %d", c.MaxAttempts))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks the signing of calls to order-service, which rejects unsigned calls;
+// the secret comes from the environment (INTERNAL_AUTH_SECRET), never from config.yaml
+func (c *InternalAuthConfig) Validate() error {
+	var errs []error
+	if c.ServiceName == "" {
+		errs = append(errs, errors.New("internal_auth: service_name is required"))
+	}
+	if c.Secret == "" {
+		errs = append(errs, errors.New("internal_auth: secret is required (set INTERNAL_AUTH_SECRET)"))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestLoadConfigSecretsFromEnv checks that config.yaml ships no signing secret: order-service
+// rejects unsigned calls, so payment-service reads its secret from the environment and does
+// not start without it
+func TestLoadConfigSecretsFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string // INTERNAL_AUTH_SECRET
+		wantErr string
+	}{
+		{"secret set", "own-secret", ""},
+		{"secret missing", "", "INTERNAL_AUTH_SECRET"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("INTERNAL_AUTH_SECRET", tt.secret)
+
+			cfg, err := LoadConfig(".")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to mention %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadConfig: %v", err)
+			}
+			if cfg.InternalAuth.Secret != tt.secret {
+				t.Errorf("secret = %q, want it from INTERNAL_AUTH_SECRET", cfg.InternalAuth.Secret)
+			}
+			if cfg.InternalAuth.ServiceName != "payment_service" {
+				t.Errorf("service name = %q, want payment_service (the key order-service trusts)", cfg.InternalAuth.ServiceName)
+			}
+		})
+	}
+}
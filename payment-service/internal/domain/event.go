@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Payment event types (one Kafka topic each), consumed by order-service
+const (
+	EventPaymentSucceeded = "payment_succeeded" // The order is paid
+	EventPaymentFailed    = "payment_failed"    // The intent failed for good (declined too often or expired)
+)
+
+// PaymentEvent is published when a payment intent reaches succeeded or failed
+// Consumers may see an event twice (at-least-once) and dedupe by payment_intent_id
+type PaymentEvent struct {
+	EventType       string    `json:"event_type"`
+	PaymentIntentID uint      `json:"payment_intent_id"`
+	OrderID         uint      `json:"order_id"`
+	OrderNumber     string    `json:"order_number"`
+	UserID          uint      `json:"user_id"`
+	AmountMinor     int64     `json:"amount_minor"` // In minor units of Currency
+	Currency        string    `json:"currency"`
+	PaymentMethod   string    `json:"payment_method"`
+	ProviderRef     string    `json:"provider_ref,omitempty"`
+	Reason          string    `json:"reason,omitempty"` // payment_failed: last decline reason or "expired"
+	Timestamp       time.Time `json:"timestamp"`
+}
+
+// NewPaymentEvent builds the event of a succeeded or failed intent
+func NewPaymentEvent(intent *PaymentIntent) *PaymentEvent {
+	event := &PaymentEvent{
+		EventType:       EventPaymentFailed,
+		PaymentIntentID: intent.ID,
+		OrderID:         intent.OrderID,
+		OrderNumber:     intent.OrderNumber,
+		UserID:          intent.UserID,
+		AmountMinor:     intent.AmountMinor,
+		Currency:        intent.Currency,
+		PaymentMethod:   intent.PaymentMethod,
+		ProviderRef:     intent.ProviderRef,
+		Timestamp:       intent.UpdatedAt,
+	}
+	if intent.Status == PaymentIntentSucceeded {
+		event.EventType = EventPaymentSucceeded
+	} else {
+		event.Reason = intent.FailureReason
+	}
+	return event
+}
+
+// EventPublisher publishes payment events
+// This abstraction allows us to swap Kafka for other message brokers if needed
+type EventPublisher interface {
+	PublishPaymentEvent(ctx context.Context, event *PaymentEvent) error
+	Close() error // Close releases resources (e.g., Kafka connections)
+}
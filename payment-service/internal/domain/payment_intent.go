@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"context"
+	"payment-service/pkg/money"
+	"time"
+)
+
+type PaymentIntentStatus string
+
+// Payment intent lifecycle: REQUIRES_CONFIRMATION -> PROCESSING -> SUCCEEDED, or back to
+// REQUIRES_CONFIRMATION when declined, or FAILED after max_attempts declines
+// (an unconfirmed intent may also be CANCELLED by the buyer, or FAILED once expired)
+const (
+	PaymentIntentRequiresConfirmation PaymentIntentStatus = "requires_confirmation" // Created or declined, waiting for a (new) payment token
+	PaymentIntentProcessing           PaymentIntentStatus = "processing"            // A confirmation is charging the provider
+	PaymentIntentSucceeded            PaymentIntentStatus = "succeeded"             // Charged; payment_succeeded is published
+	PaymentIntentFailed               PaymentIntentStatus = "failed"                // Declined too often or expired; payment_failed is published
+	PaymentIntentCancelled            PaymentIntentStatus = "cancelled"             // Abandoned by the buyer, nothing is published
+)
+
+// Payment intent errors
+var (
+	ErrPaymentIntentNotFound  = NotFound("payment intent not found")
+	ErrOrderNotFound          = NotFound("order not found")
+	ErrOrderNotPayable        = Conflict("order cannot be paid in its current status")
+	ErrOrderAlreadyPaid       = Conflict("order is already paid")
+	ErrOpenPaymentIntent      = Conflict("order already has a payment intent waiting for confirmation")
+	ErrIntentNotConfirmable   = Conflict("payment intent cannot be confirmed in its current status")
+	ErrIntentNotCancellable   = Conflict("payment intent cannot be cancelled in its current status")
+	ErrPaymentIntentExpired   = Conflict("payment intent expired, create a new one")
+	ErrCashOnDelivery         = Validation("cash on delivery orders are paid on delivery, not online")
+	ErrPaymentTokenRequired   = Validation("payment_token is required")
+	ErrNotPaymentIntentHolder = Forbidden("payment intent belongs to another user")
+)
+
+// PaymentIntent is the payment of one order (shop_order of order-service)
+// The amount is taken from the order when the intent is created and kept in minor units
+// of its currency (see Amount). An order has at most
+// one intent waiting for confirmation (partial unique index); a failed or cancelled
+// intent can be followed by a new one
+type PaymentIntent struct {
+	ID uint `json:"id" gorm:"primaryKey"`
+
+	OrderID     uint   `json:"order_id" gorm:"not null;index;uniqueIndex:idx_payment_intents_open,where:status = 'requires_confirmation' OR status = 'processing'"`
+	OrderNumber string `json:"order_number" gorm:"size:50;not null"`
+	UserID      uint   `json:"user_id" gorm:"index;not null"`
+
+	AmountMinor   int64               `json:"amount_minor" gorm:"not null"` // e.g. 250000 for 250000 VND, 1250 for 12.50 USD
+	Currency      string              `json:"currency" gorm:"size:3;not null"`
+	PaymentMethod string              `json:"payment_method" gorm:"size:50;not null"`
+	Status        PaymentIntentStatus `json:"status" gorm:"type:varchar(30);index;not null"`
+
+	Attempts      int    `json:"attempts" gorm:"not null;default:0"`                         // Confirmations charged (approved or declined)
+	FailureReason string `json:"failure_reason,omitempty" gorm:"size:100"`                   // Last decline reason, or "expired"
+	ProviderRef   string `json:"provider_ref,omitempty" gorm:"column:provider_ref;size:100"` // Charge reference of the provider
+
+	ExpiresAt   time.Time  `json:"expires_at" gorm:"index;not null"`
+	SucceededAt *time.Time `json:"succeeded_at,omitempty"`
+	FailedAt    *time.Time `json:"failed_at,omitempty"`
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+
+	// Set once the payment_succeeded/payment_failed event is written to Kafka; final
+	// intents without it are published again by the sweeper (at-least-once)
+	EventPublishedAt *time.Time `json:"-" gorm:"index"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for PaymentIntent
+func (PaymentIntent) TableName() string {
+	return "payment_intents"
+}
+
+// Amount returns the amount charged for the intent
+func (p *PaymentIntent) Amount() money.Money {
+	return money.New(p.AmountMinor, money.Currency(p.Currency))
+}
+
+// IsExpiredAt reports whether an unconfirmed intent can no longer be confirmed
+func (p *PaymentIntent) IsExpiredAt(now time.Time) bool {
+	return p.Status == PaymentIntentRequiresConfirmation && !now.Before(p.ExpiresAt)
+}
+
+// PaymentIntentRepository stores payment intents
+// Transition moves an intent between statuses only if it is still in from, so concurrent
+// confirmations and the sweeper never both act on the same intent
+type PaymentIntentRepository interface {
+	Create(ctx context.Context, intent *PaymentIntent) error // ErrOpenPaymentIntent if the order has one waiting
+	GetByID(ctx context.Context, id uint) (*PaymentIntent, error)
+	ListByOrder(ctx context.Context, orderID uint) ([]*PaymentIntent, error)
+	HasSucceeded(ctx context.Context, orderID uint) (bool, error)
+	Transition(ctx context.Context, id uint, from PaymentIntentStatus, updates map[string]interface{}) (bool, error)
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]*PaymentIntent, error)
+	ListUnpublished(ctx context.Context, limit int) ([]*PaymentIntent, error)
+	MarkPublished(ctx context.Context, id uint, at time.Time) error
+}
+
+// ChargeResult is the answer of the payment provider to a charge
+type ChargeResult struct {
+	Approved      bool
+	Reference     string // Provider charge reference (approved charges)
+	DeclineReason string // e.g. card_declined, insufficient_funds
+}
+
+// PaymentProvider charges payment tokens (implemented by pkg/provider)
+// An error means the provider could not be reached: nothing was charged
+type PaymentProvider interface {
+	Charge(ctx context.Context, intent *PaymentIntent, paymentToken string) (*ChargeResult, error)
+}
+
+// Order is the order as seen by payment-service (from order-service)
+// FinalAmount is in major units as order-service sends it; CreateIntent converts it
+type Order struct {
+	ID            uint    `json:"id"`
+	OrderNumber   string  `json:"order_number"`
+	UserID        uint    `json:"user_id"`
+	Status        string  `json:"status"`
+	FinalAmount   float64 `json:"final_amount"`
+	PaymentMethod string  `json:"payment_method"`
+}
+
+// OrderReader loads orders from order-service (service.OrderClientAdapter)
+// Returns ErrOrderNotFound if the order does not exist
+type OrderReader interface {
+	GetOrder(ctx context.Context, orderID uint) (*Order, error)
+}
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"net/http"
+	"payment-service/internal/domain"
+	"payment-service/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// PaymentHandler handles HTTP requests for payment intents
+type PaymentHandler struct {
+	paymentService *service.PaymentService
+	logger         *zap.Logger
+}
+
+// NewPaymentHandler creates a new payment handler
+func NewPaymentHandler(paymentService *service.PaymentService, logger *zap.Logger) *PaymentHandler {
+	return &PaymentHandler{
+		paymentService: paymentService,
+		logger:         logger,
+	}
+}
+
+// CreateIntentRequest is the body of POST /payments/intents
+type CreateIntentRequest struct {
+	OrderID uint `json:"order_id" binding:"required"`
+}
+
+// ConfirmIntentRequest is the body of POST /payments/intents/:id/confirm
+type ConfirmIntentRequest struct {
+	PaymentToken string `json:"payment_token" binding:"required"`
+}
+
+// CreateIntent handles POST /payments/intents
+// @Summary Create a payment intent
+// @Description Start paying one of the current user's pending orders online. The amount is the order's final amount; the intent expires after payment.intent_ttl
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param request body CreateIntentRequest true "Create Intent Request"
+// @Success 201 {object} domain.PaymentIntent "Intent created"
+// @Failure 400 {object} map[string]string "Invalid request payload or cash on delivery order"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Order not found"
+// @Failure 409 {object} map[string]string "Order not payable, already paid or has an open intent"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /payments/intents [post]
+func (h *PaymentHandler) CreateIntent(c *gin.Context) {
+	userID, ok := requestUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CreateIntentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	intent, err := h.paymentService.CreateIntent(c.Request.Context(), req.OrderID, userID)
+	if err != nil {
+		respondError(c, h.logger, "failed to create payment intent", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, intent)
+}
+
+// GetIntent handles GET /payments/intents/:id
+// @Summary Get a payment intent
+// @Tags Payments
+// @Produce json
+// @Param id path int true "Payment Intent ID"
+// @Success 200 {object} domain.PaymentIntent "Intent"
+// @Failure 400 {object} map[string]string "Invalid payment intent ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Intent of another user"
+// @Failure 404 {object} map[string]string "Intent not found"
+// @Router /payments/intents/{id} [get]
+func (h *PaymentHandler) GetIntent(c *gin.Context) {
+	userID, ok := requestUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := intentID(c)
+	if !ok {
+		return
+	}
+
+	intent, err := h.paymentService.GetIntent(c.Request.Context(), id, userID)
+	if err != nil {
+		respondError(c, h.logger, "failed to get payment intent", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, intent)
+}
+
+// ConfirmIntent handles POST /payments/intents/:id/confirm
+// @Summary Confirm a payment intent
+// @Description Charge the payment token. A declined charge answers 402 with the intent: it can be confirmed again with another token until payment.max_attempts declines, then it fails and the order is cancelled
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param id path int true "Payment Intent ID"
+// @Param request body ConfirmIntentRequest true "Confirm Intent Request"
+// @Success 200 {object} domain.PaymentIntent "Payment succeeded"
+// @Failure 400 {object} map[string]string "Invalid request payload"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 402 {object} map[string]interface{} "Payment declined"
+// @Failure 403 {object} map[string]string "Intent of another user"
+// @Failure 404 {object} map[string]string "Intent not found"
+// @Failure 409 {object} map[string]string "Intent not confirmable or expired"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /payments/intents/{id}/confirm [post]
+func (h *PaymentHandler) ConfirmIntent(c *gin.Context) {
+	userID, ok := requestUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := intentID(c)
+	if !ok {
+		return
+	}
+
+	var req ConfirmIntentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	intent, err := h.paymentService.ConfirmIntent(c.Request.Context(), id, userID, req.PaymentToken)
+	if err != nil {
+		respondError(c, h.logger, "failed to confirm payment intent", err)
+		return
+	}
+
+	if intent.Status != domain.PaymentIntentSucceeded {
+		c.JSON(http.StatusPaymentRequired, gin.H{"error": "payment declined: " + intent.FailureReason, "payment_intent": intent})
+		return
+	}
+	c.JSON(http.StatusOK, intent)
+}
+
+// CancelIntent handles POST /payments/intents/:id/cancel
+// @Summary Cancel a payment intent
+// @Description Abandon an unconfirmed intent; the order stays pending and a new intent can be created
+// @Tags Payments
+// @Produce json
+// @Param id path int true "Payment Intent ID"
+// @Success 200 {object} domain.PaymentIntent "Intent cancelled"
+// @Failure 400 {object} map[string]string "Invalid payment intent ID"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Intent of another user"
+// @Failure 404 {object} map[string]string "Intent not found"
+// @Failure 409 {object} map[string]string "Intent not cancellable"
+// @Router /payments/intents/{id}/cancel [post]
+func (h *PaymentHandler) CancelIntent(c *gin.Context) {
+	userID, ok := requestUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := intentID(c)
+	if !ok {
+		return
+	}
+
+	intent, err := h.paymentService.CancelIntent(c.Request.Context(), id, userID)
+	if err != nil {
+		respondError(c, h.logger, "failed to cancel payment intent", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, intent)
+}
+
+func intentID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid payment intent ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// HealthCheck handles GET /health
+func (h *PaymentHandler) HealthCheck(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "service": "payment-service"})
+}
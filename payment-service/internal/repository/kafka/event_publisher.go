@@ -0,0 +1,113 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"payment-service/internal/domain"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// PublisherOptions configures the Kafka event publisher
+type PublisherOptions struct {
+	Brokers      []string
+	Topics       Topics
+	WriteTimeout time.Duration
+	RequiredAcks int           // 0: no ack, 1: leader ack, -1: all replicas ack
+	MaxRetries   int           // write retries before PublishPaymentEvent gives up
+	RetryBackoff time.Duration // delay before retry n is n*RetryBackoff
+}
+
+// eventPublisher implements the EventPublisher interface
+// This is the infrastructure layer - it knows HOW to publish events to Kafka
+// Unlike order-service there is no local buffer: an event that cannot be written stays
+// unpublished on its intent (event_published_at) and is resent by the sweeper
+type eventPublisher struct {
+	writer *kafka.Writer
+	opts   PublisherOptions
+}
+
+// NewEventPublisher creates a new Kafka event publisher
+// Each event type is written to its own topic (see Topics); messages are keyed and
+// hash-partitioned by order ID so events of one order stay in order
+func NewEventPublisher(opts PublisherOptions) domain.EventPublisher {
+	// Convert int to kafka.RequiredAcks
+	var kafkaAcks kafka.RequiredAcks
+	switch opts.RequiredAcks {
+	case -1:
+		kafkaAcks = kafka.RequireAll
+	case 0:
+		kafkaAcks = kafka.RequireNone
+	case 1:
+		kafkaAcks = kafka.RequireOne
+	default:
+		kafkaAcks = kafka.RequireOne
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = 200 * time.Millisecond
+	}
+
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(opts.Brokers...),
+		Balancer:     &kafka.Hash{}, // same key -> same partition
+		WriteTimeout: opts.WriteTimeout,
+		RequiredAcks: kafkaAcks,
+		Async:        false, // Synchronous writes for reliability
+	}
+
+	return &eventPublisher{
+		writer: writer,
+		opts:   opts,
+	}
+}
+
+// PublishPaymentEvent publishes a payment event to Kafka
+// Returns an error if the event could not be written after all retries
+func (p *eventPublisher) PublishPaymentEvent(ctx context.Context, event *domain.PaymentEvent) error {
+	eventJSON, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	message := kafka.Message{
+		Topic: p.opts.Topics.For(event.EventType),
+		Key:   []byte(fmt.Sprintf("%d", event.OrderID)),
+		Value: eventJSON,
+		Headers: []kafka.Header{
+			{Key: "event_type", Value: []byte(event.EventType)},
+			{Key: "timestamp", Value: []byte(event.Timestamp.Format(time.RFC3339))},
+		},
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(attempt) * p.opts.RetryBackoff):
+			}
+		}
+
+		writeCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		err = p.writer.WriteMessages(writeCtx, message)
+		cancel()
+		if err == nil || attempt >= p.opts.MaxRetries {
+			break
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s event: %w", event.EventType, err)
+	}
+	return nil
+}
+
+// Close closes the Kafka writer connection
+// This should be called during graceful shutdown
+func (p *eventPublisher) Close() error {
+	if p.writer != nil {
+		return p.writer.Close()
+	}
+	return nil
+}
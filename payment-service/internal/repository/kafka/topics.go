@@ -0,0 +1,19 @@
+package kafka
+
+import "strings"
+
+// Topics maps event types to Kafka topics (one topic per event type)
+// By default "payment_succeeded" goes to Prefix + "payment.succeeded"; Overrides
+// replaces the name of individual event types
+type Topics struct {
+	Prefix    string
+	Overrides map[string]string // event type -> topic
+}
+
+// For returns the topic of an event type
+func (t Topics) For(eventType string) string {
+	if topic, ok := t.Overrides[eventType]; ok && topic != "" {
+		return topic
+	}
+	return t.Prefix + strings.Replace(eventType, "_", ".", 1)
+}
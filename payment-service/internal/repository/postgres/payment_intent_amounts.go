@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"fmt"
+	"payment-service/internal/domain"
+	"payment-service/pkg/money"
+
+	"gorm.io/gorm"
+)
+
+// MigratePaymentIntentAmounts moves intent amounts from the decimal amount column to
+// amount_minor (minor units of the intent's currency). Run it before AutoMigrate, which
+// cannot add the NOT NULL column to a table that has rows. Fresh and already migrated
+// databases are left alone
+func MigratePaymentIntentAmounts(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		migrator := tx.Migrator()
+		if !migrator.HasTable(&domain.PaymentIntent{}) || !migrator.HasColumn(&domain.PaymentIntent{}, "amount") {
+			return nil
+		}
+		if err := tx.Exec("LOCK TABLE payment_intents IN ACCESS EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("ALTER TABLE payment_intents ADD COLUMN IF NOT EXISTS amount_minor bigint").Error; err != nil {
+			return fmt.Errorf("failed to add amount_minor: %w", err)
+		}
+
+		// The decimal is read as text and parsed, so no amount goes through a float
+		var rows []struct {
+			ID       uint
+			Amount   string
+			Currency string
+		}
+		if err := tx.Raw("SELECT id, amount::text AS amount, currency FROM payment_intents WHERE amount_minor IS NULL").
+			Scan(&rows).Error; err != nil {
+			return err
+		}
+		for _, row := range rows {
+			amount, err := money.Parse(row.Amount, money.Currency(row.Currency))
+			if err != nil {
+				return fmt.Errorf("invalid amount %s of payment intent %d: %w", row.Amount, row.ID, err)
+			}
+			if err := tx.Exec("UPDATE payment_intents SET amount_minor = ? WHERE id = ?", amount.Minor(), row.ID).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Exec("ALTER TABLE payment_intents ALTER COLUMN amount_minor SET NOT NULL").Error; err != nil {
+			return err
+		}
+		return tx.Exec("ALTER TABLE payment_intents DROP COLUMN amount").Error
+	})
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"payment-service/internal/domain"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// PaymentIntentRepository handles database operations for payment intents
+// This is the infrastructure layer - it knows HOW to persist data
+type PaymentIntentRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentIntentRepository creates a new payment intent repository
+func NewPaymentIntentRepository(db *gorm.DB) domain.PaymentIntentRepository {
+	return &PaymentIntentRepository{db: db}
+}
+
+// Create inserts a new intent
+// Returns domain.ErrOpenPaymentIntent if the order already has an intent waiting
+func (r *PaymentIntentRepository) Create(ctx context.Context, intent *domain.PaymentIntent) error {
+	err := r.db.WithContext(ctx).Create(intent).Error
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "idx_payment_intents_open" {
+		return domain.ErrOpenPaymentIntent
+	}
+	return err
+}
+
+// GetByID retrieves an intent by ID; returns domain.ErrPaymentIntentNotFound if missing
+func (r *PaymentIntentRepository) GetByID(ctx context.Context, id uint) (*domain.PaymentIntent, error) {
+	var intent domain.PaymentIntent
+	err := r.db.WithContext(ctx).First(&intent, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrPaymentIntentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &intent, nil
+}
+
+// ListByOrder returns the intents of an order, newest first
+func (r *PaymentIntentRepository) ListByOrder(ctx context.Context, orderID uint) ([]*domain.PaymentIntent, error) {
+	var intents []*domain.PaymentIntent
+	err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Order("id DESC").Find(&intents).Error
+	return intents, err
+}
+
+// HasSucceeded reports whether an intent of the order succeeded
+func (r *PaymentIntentRepository) HasSucceeded(ctx context.Context, orderID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.PaymentIntent{}).
+		Where("order_id = ? AND status = ?", orderID, domain.PaymentIntentSucceeded).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Transition applies updates (including the new status) to an intent still in status from
+// Returns false if the intent was in another status (nothing is updated)
+func (r *PaymentIntentRepository) Transition(ctx context.Context, id uint, from domain.PaymentIntentStatus, updates map[string]interface{}) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&domain.PaymentIntent{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(updates)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListExpired returns up to limit unconfirmed intents whose expiry is before now
+func (r *PaymentIntentRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]*domain.PaymentIntent, error) {
+	var intents []*domain.PaymentIntent
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND expires_at <= ?", domain.PaymentIntentRequiresConfirmation, now).
+		Order("id").
+		Limit(limit).
+		Find(&intents).Error
+	return intents, err
+}
+
+// ListUnpublished returns up to limit succeeded or failed intents whose event was not published
+func (r *PaymentIntentRepository) ListUnpublished(ctx context.Context, limit int) ([]*domain.PaymentIntent, error) {
+	var intents []*domain.PaymentIntent
+	err := r.db.WithContext(ctx).
+		Where("status IN ? AND event_published_at IS NULL",
+			[]domain.PaymentIntentStatus{domain.PaymentIntentSucceeded, domain.PaymentIntentFailed}).
+		Order("id").
+		Limit(limit).
+		Find(&intents).Error
+	return intents, err
+}
+
+// MarkPublished records that the event of an intent was published
+func (r *PaymentIntentRepository) MarkPublished(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&domain.PaymentIntent{}).
+		Where("id = ?", id).
+		UpdateColumn("event_published_at", at).Error
+}
@@ -0,0 +1,154 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"payment-service/internal/domain"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gormpostgres "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Run with: TEST_DATABASE_DSN=postgres://... go test -tags integration ./internal/repository/postgres
+// (docker compose -f docker-compose.test.yml up -d starts one)
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+	db, err := gorm.Open(gormpostgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	if err := MigratePaymentIntentAmounts(db); err != nil {
+		t.Fatalf("failed to migrate payment intent amounts: %v", err)
+	}
+	if err := db.AutoMigrate(&domain.PaymentIntent{}); err != nil {
+		t.Fatalf("failed to migrate payment_intents: %v", err)
+	}
+	return db
+}
+
+func newIntent(orderID uint) *domain.PaymentIntent {
+	return &domain.PaymentIntent{
+		OrderID:       orderID,
+		OrderNumber:   fmt.Sprintf("ORD-IT-%d", orderID),
+		UserID:        1,
+		AmountMinor:   250000,
+		Currency:      "VND",
+		PaymentMethod: "CARD",
+		Status:        domain.PaymentIntentRequiresConfirmation,
+		ExpiresAt:     time.Now().Add(30 * time.Minute),
+	}
+}
+
+// race runs fn from n goroutines released at once and counts the calls that returned true
+func race(t *testing.T, n int, fn func() (bool, error)) int64 {
+	t.Helper()
+	var (
+		won   atomic.Int64
+		wg    sync.WaitGroup
+		start = make(chan struct{})
+		errs  = make(chan error, n)
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			ok, err := fn()
+			if err != nil {
+				errs <- err
+				return
+			}
+			if ok {
+				won.Add(1)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return won.Load()
+}
+
+// TestCreateIntentConcurrentOnePerOrder double-submits the pay button from many requests:
+// the partial unique index lets only one intent wait for confirmation per order
+func TestCreateIntentConcurrentOnePerOrder(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewPaymentIntentRepository(db)
+	ctx := context.Background()
+	orderID := uint(time.Now().UnixNano() % 1_000_000_000)
+	t.Cleanup(func() { db.Where("order_id = ?", orderID).Delete(&domain.PaymentIntent{}) })
+
+	created := race(t, 20, func() (bool, error) {
+		err := repo.Create(ctx, newIntent(orderID))
+		if errors.Is(err, domain.ErrOpenPaymentIntent) {
+			return false, nil
+		}
+		return err == nil, err
+	})
+	if created != 1 {
+		t.Fatalf("%d intents created for one order, want 1", created)
+	}
+
+	// Once it is given up, the order can be paid with a new intent
+	intents, err := repo.ListByOrder(ctx, orderID)
+	if err != nil || len(intents) != 1 {
+		t.Fatalf("list intents: %v (%d intents)", err, len(intents))
+	}
+	if ok, err := repo.Transition(ctx, intents[0].ID, domain.PaymentIntentRequiresConfirmation, map[string]interface{}{
+		"status": domain.PaymentIntentCancelled,
+	}); err != nil || !ok {
+		t.Fatalf("cancel intent: %v (applied %v)", err, ok)
+	}
+	if err := repo.Create(ctx, newIntent(orderID)); err != nil {
+		t.Errorf("new intent after cancellation: %v", err)
+	}
+}
+
+// TestTransitionConcurrentConfirmOnce confirms one intent from many requests at once:
+// only one moves it to processing, so the provider is charged once
+func TestTransitionConcurrentConfirmOnce(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewPaymentIntentRepository(db)
+	ctx := context.Background()
+	orderID := uint(time.Now().UnixNano()%1_000_000_000) + 1
+	t.Cleanup(func() { db.Where("order_id = ?", orderID).Delete(&domain.PaymentIntent{}) })
+
+	intent := newIntent(orderID)
+	if err := repo.Create(ctx, intent); err != nil {
+		t.Fatalf("create intent: %v", err)
+	}
+
+	claimed := race(t, 20, func() (bool, error) {
+		return repo.Transition(ctx, intent.ID, domain.PaymentIntentRequiresConfirmation, map[string]interface{}{
+			"status":   domain.PaymentIntentProcessing,
+			"attempts": gorm.Expr("attempts + 1"),
+		})
+	})
+	if claimed != 1 {
+		t.Fatalf("%d confirmations claimed the intent, want 1", claimed)
+	}
+
+	stored, err := repo.GetByID(ctx, intent.ID)
+	if err != nil {
+		t.Fatalf("reload intent: %v", err)
+	}
+	if stored.Status != domain.PaymentIntentProcessing || stored.Attempts != 1 {
+		t.Errorf("intent = %s after %d attempts, want processing after 1", stored.Status, stored.Attempts)
+	}
+}
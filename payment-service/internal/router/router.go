@@ -0,0 +1,34 @@
+package router
+
+import (
+	"payment-service/internal/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupRouter configures all API routes
+// This is the transport layer - it defines the HTTP API surface
+// NOTE: CORS and authentication are handled by API Gateway (X-User-Id) - this service
+// should only receive internal requests
+func SetupRouter(paymentHandler *handler.PaymentHandler) *gin.Engine {
+	router := gin.New()
+	router.Use(gin.Recovery(), gin.Logger())
+
+	// Health check endpoint
+	router.GET("/health", paymentHandler.HealthCheck)
+
+	// API v1 routes
+	v1 := router.Group("/api/v1")
+	{
+		// Payment intent routes
+		intents := v1.Group("/payments/intents")
+		{
+			intents.POST("", paymentHandler.CreateIntent)              // Create intent for an order
+			intents.GET("/:id", paymentHandler.GetIntent)              // Get intent
+			intents.POST("/:id/confirm", paymentHandler.ConfirmIntent) // Charge a payment token
+			intents.POST("/:id/cancel", paymentHandler.CancelIntent)   // Abandon an unconfirmed intent
+		}
+	}
+
+	return router
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"payment-service/internal/domain"
+	"payment-service/pkg/order_client"
+)
+
+// ==================== OrderClientAdapter for PaymentService ====================
+
+type OrderClientAdapter struct {
+	Client *order_client.OrderClient
+}
+
+// GetOrder fetches the order an intent is created for
+func (a *OrderClientAdapter) GetOrder(ctx context.Context, orderID uint) (*domain.Order, error) {
+	order, err := a.Client.GetOrder(ctx, orderID)
+	if errors.Is(err, order_client.ErrNotFound) {
+		return nil, domain.ErrOrderNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Order{
+		ID:            order.ID,
+		OrderNumber:   order.OrderNumber,
+		UserID:        order.UserID,
+		Status:        order.Status,
+		FinalAmount:   order.FinalAmount,
+		PaymentMethod: order.PaymentMethod,
+	}, nil
+}
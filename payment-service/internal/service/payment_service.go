@@ -0,0 +1,368 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"payment-service/internal/domain"
+	"payment-service/pkg/money"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// sweepBatchSize bounds the intents expired or republished per sweep
+const sweepBatchSize = 100
+
+// PaymentOptions configures the payment service (see config.PaymentConfig)
+type PaymentOptions struct {
+	Currency      money.Currency
+	IntentTTL     time.Duration // an intent not confirmed within the TTL fails as "expired"
+	MaxAttempts   int           // declined confirmations before the intent fails
+	SweepInterval time.Duration // how often Start expires intents and resends events
+}
+
+// PaymentService handles the lifecycle of payment intents
+// An intent is created for a pending online order, confirmed with a payment token and
+// ends succeeded or failed; both outcomes are published for order-service
+type PaymentService struct {
+	intentRepo domain.PaymentIntentRepository
+	orders     domain.OrderReader
+	provider   domain.PaymentProvider
+	publisher  domain.EventPublisher
+	opts       PaymentOptions
+	logger     *zap.Logger
+}
+
+// NewPaymentService creates a new payment service
+func NewPaymentService(
+	intentRepo domain.PaymentIntentRepository,
+	orders domain.OrderReader,
+	provider domain.PaymentProvider,
+	publisher domain.EventPublisher,
+	opts PaymentOptions,
+	logger *zap.Logger,
+) *PaymentService {
+	if opts.SweepInterval <= 0 {
+		opts.SweepInterval = 30 * time.Second
+	}
+	return &PaymentService{
+		intentRepo: intentRepo,
+		orders:     orders,
+		provider:   provider,
+		publisher:  publisher,
+		opts:       opts,
+		logger:     logger,
+	}
+}
+
+// CreateIntent creates a payment intent for an order of the user
+// The order must be pending and paid online; the amount is the order's final amount,
+// rounded half to even to the currency's minor unit
+func (s *PaymentService) CreateIntent(ctx context.Context, orderID, userID uint) (*domain.PaymentIntent, error) {
+	order, err := s.orders.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		// Do not reveal other users' orders
+		return nil, domain.ErrOrderNotFound
+	}
+
+	switch order.Status {
+	case "pending":
+	case "paid", "confirmed", "processing", "shipped", "delivered":
+		return nil, domain.ErrOrderAlreadyPaid
+	default:
+		return nil, domain.ErrOrderNotPayable
+	}
+	if strings.EqualFold(order.PaymentMethod, "COD") {
+		return nil, domain.ErrCashOnDelivery
+	}
+
+	paid, err := s.intentRepo.HasSucceeded(ctx, order.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check payments of order: %w", err)
+	}
+	if paid {
+		return nil, domain.ErrOrderAlreadyPaid
+	}
+
+	amount, err := money.FromFloat(order.FinalAmount, s.opts.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid final amount of order %d: %w", order.ID, err)
+	}
+
+	intent := &domain.PaymentIntent{
+		OrderID:       order.ID,
+		OrderNumber:   order.OrderNumber,
+		UserID:        userID,
+		AmountMinor:   amount.Minor(),
+		Currency:      string(s.opts.Currency),
+		PaymentMethod: order.PaymentMethod,
+		Status:        domain.PaymentIntentRequiresConfirmation,
+		ExpiresAt:     time.Now().Add(s.opts.IntentTTL),
+	}
+	if err := s.intentRepo.Create(ctx, intent); err != nil {
+		if errors.Is(err, domain.ErrOpenPaymentIntent) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create payment intent: %w", err)
+	}
+
+	s.logger.Info("payment intent created",
+		zap.Uint("payment_intent_id", intent.ID),
+		zap.Uint("order_id", intent.OrderID),
+		zap.Stringer("amount", intent.Amount()),
+	)
+	return intent, nil
+}
+
+// GetIntent retrieves an intent of the user
+func (s *PaymentService) GetIntent(ctx context.Context, id, userID uint) (*domain.PaymentIntent, error) {
+	intent, err := s.intentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if intent.UserID != userID {
+		return nil, domain.ErrNotPaymentIntentHolder
+	}
+	return intent, nil
+}
+
+// ConfirmIntent charges the payment token for an intent of the user
+// Returns the intent in its new status: succeeded, requires_confirmation after a decline
+// (the buyer may retry with another token) or failed after MaxAttempts declines.
+// If the provider cannot be reached nothing is charged and the intent can be confirmed again
+func (s *PaymentService) ConfirmIntent(ctx context.Context, id, userID uint, paymentToken string) (*domain.PaymentIntent, error) {
+	if strings.TrimSpace(paymentToken) == "" {
+		return nil, domain.ErrPaymentTokenRequired
+	}
+
+	intent, err := s.GetIntent(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if intent.Status != domain.PaymentIntentRequiresConfirmation {
+		return nil, domain.ErrIntentNotConfirmable
+	}
+
+	now := time.Now()
+	if intent.IsExpiredAt(now) {
+		if err := s.expire(ctx, intent, now); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrPaymentIntentExpired
+	}
+
+	// Claim the intent: a concurrent confirmation or the sweeper loses the race here
+	ok, err := s.intentRepo.Transition(ctx, intent.ID, domain.PaymentIntentRequiresConfirmation, map[string]interface{}{
+		"status": domain.PaymentIntentProcessing,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update payment intent: %w", err)
+	}
+	if !ok {
+		return nil, domain.ErrIntentNotConfirmable
+	}
+	intent.Status = domain.PaymentIntentProcessing
+
+	result, chargeErr := s.provider.Charge(ctx, intent, paymentToken)
+	if chargeErr != nil {
+		// Nothing was charged: give the intent back to the buyer
+		if _, err := s.intentRepo.Transition(context.WithoutCancel(ctx), intent.ID, domain.PaymentIntentProcessing, map[string]interface{}{
+			"status": domain.PaymentIntentRequiresConfirmation,
+		}); err != nil {
+			s.logger.Error("failed to release payment intent", zap.Uint("payment_intent_id", intent.ID), zap.Error(err))
+		}
+		return nil, fmt.Errorf("payment provider unavailable: %w", chargeErr)
+	}
+
+	// The charge happened: record its outcome even if the request was canceled meanwhile
+	ctx = context.WithoutCancel(ctx)
+	now = time.Now()
+	intent.Attempts++
+	updates := map[string]interface{}{"attempts": intent.Attempts}
+	switch {
+	case result.Approved:
+		intent.Status = domain.PaymentIntentSucceeded
+		intent.ProviderRef = result.Reference
+		intent.FailureReason = ""
+		intent.SucceededAt = &now
+		updates["provider_ref"] = intent.ProviderRef
+		updates["failure_reason"] = ""
+		updates["succeeded_at"] = now
+	case intent.Attempts >= s.opts.MaxAttempts:
+		intent.Status = domain.PaymentIntentFailed
+		intent.FailureReason = result.DeclineReason
+		intent.FailedAt = &now
+		updates["failure_reason"] = intent.FailureReason
+		updates["failed_at"] = now
+	default:
+		intent.Status = domain.PaymentIntentRequiresConfirmation
+		intent.FailureReason = result.DeclineReason
+		updates["failure_reason"] = intent.FailureReason
+	}
+	updates["status"] = intent.Status
+	intent.UpdatedAt = now
+
+	if _, err := s.intentRepo.Transition(ctx, intent.ID, domain.PaymentIntentProcessing, updates); err != nil {
+		s.logger.Error("failed to record charge outcome",
+			zap.Uint("payment_intent_id", intent.ID),
+			zap.String("status", string(intent.Status)),
+			zap.String("provider_ref", intent.ProviderRef),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("failed to record payment: %w", err)
+	}
+
+	s.logger.Info("payment intent confirmed",
+		zap.Uint("payment_intent_id", intent.ID),
+		zap.Uint("order_id", intent.OrderID),
+		zap.String("status", string(intent.Status)),
+		zap.Int("attempts", intent.Attempts),
+	)
+
+	if intent.Status != domain.PaymentIntentRequiresConfirmation {
+		s.publish(ctx, intent)
+	}
+	return intent, nil
+}
+
+// CancelIntent cancels an unconfirmed intent of the user; nothing is published and the
+// order stays pending (a new intent can be created)
+func (s *PaymentService) CancelIntent(ctx context.Context, id, userID uint) (*domain.PaymentIntent, error) {
+	intent, err := s.GetIntent(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if intent.Status != domain.PaymentIntentRequiresConfirmation {
+		return nil, domain.ErrIntentNotCancellable
+	}
+
+	now := time.Now()
+	ok, err := s.intentRepo.Transition(ctx, intent.ID, domain.PaymentIntentRequiresConfirmation, map[string]interface{}{
+		"status":       domain.PaymentIntentCancelled,
+		"cancelled_at": now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel payment intent: %w", err)
+	}
+	if !ok {
+		return nil, domain.ErrIntentNotCancellable
+	}
+
+	intent.Status = domain.PaymentIntentCancelled
+	intent.CancelledAt = &now
+	intent.UpdatedAt = now
+	return intent, nil
+}
+
+// Start expires stale intents and resends unpublished events every SweepInterval
+// until ctx is canceled
+func (s *PaymentService) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.opts.SweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if n, err := s.ExpireStale(ctx); err != nil {
+				s.logger.Warn("payment intent expiry stopped", zap.Int("expired", n), zap.Error(err))
+			} else if n > 0 {
+				s.logger.Info("payment intents expired", zap.Int("expired", n))
+			}
+			if err := s.RepublishEvents(ctx); err != nil {
+				s.logger.Warn("payment event redelivery stopped", zap.Error(err))
+			}
+		}
+	}
+}
+
+// ExpireStale fails intents not confirmed within IntentTTL
+func (s *PaymentService) ExpireStale(ctx context.Context) (int, error) {
+	now := time.Now()
+	intents, err := s.intentRepo.ListExpired(ctx, now, sweepBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired payment intents: %w", err)
+	}
+
+	expired := 0
+	for _, intent := range intents {
+		if err := ctx.Err(); err != nil {
+			return expired, err
+		}
+		if err := s.expire(ctx, intent, now); err != nil {
+			return expired, err
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// RepublishEvents publishes the events of final intents whose event was not written yet
+func (s *PaymentService) RepublishEvents(ctx context.Context) error {
+	intents, err := s.intentRepo.ListUnpublished(ctx, sweepBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to list unpublished payment intents: %w", err)
+	}
+	for _, intent := range intents {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !s.publish(ctx, intent) {
+			// Kafka is still unavailable: keep the rest for the next sweep
+			return nil
+		}
+	}
+	return nil
+}
+
+// expire fails an unconfirmed intent as "expired" and publishes payment_failed
+// An intent that left requires_confirmation meanwhile is left alone
+func (s *PaymentService) expire(ctx context.Context, intent *domain.PaymentIntent, now time.Time) error {
+	ok, err := s.intentRepo.Transition(ctx, intent.ID, domain.PaymentIntentRequiresConfirmation, map[string]interface{}{
+		"status":         domain.PaymentIntentFailed,
+		"failure_reason": "expired",
+		"failed_at":      now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to expire payment intent: %w", err)
+	}
+	if !ok {
+		return nil
+	}
+
+	intent.Status = domain.PaymentIntentFailed
+	intent.FailureReason = "expired"
+	intent.FailedAt = &now
+	intent.UpdatedAt = now
+	s.publish(ctx, intent)
+	return nil
+}
+
+// publish writes the event of a succeeded or failed intent and marks it published
+// A failed write is logged; the sweeper resends the event later
+func (s *PaymentService) publish(ctx context.Context, intent *domain.PaymentIntent) bool {
+	event := domain.NewPaymentEvent(intent)
+	if err := s.publisher.PublishPaymentEvent(ctx, event); err != nil {
+		s.logger.Warn("failed to publish payment event, will retry",
+			zap.String("event_type", event.EventType),
+			zap.Uint("payment_intent_id", intent.ID),
+			zap.Error(err),
+		)
+		return false
+	}
+
+	if err := s.intentRepo.MarkPublished(ctx, intent.ID, time.Now()); err != nil {
+		// The event is sent again by the next sweep; consumers dedupe
+		s.logger.Warn("failed to mark payment event published",
+			zap.Uint("payment_intent_id", intent.ID),
+			zap.Error(err),
+		)
+	}
+	return true
+}
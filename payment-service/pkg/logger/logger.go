@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"payment-service/config"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// level is shared by every logger built here so it can be changed at runtime
+var level = zap.NewAtomicLevel()
+
+// Level returns the adjustable level of loggers created by NewLogger
+func Level() zap.AtomicLevel {
+	return level
+}
+
+// NewLogger creates a new Zap logger based on configuration
+// Zap provides structured logging with high performance
+func NewLogger(cfg *config.LoggingConfig) (*zap.Logger, error) {
+	// Parse log level
+	var lvl zapcore.Level
+	if err := lvl.UnmarshalText([]byte(cfg.Level)); err != nil {
+		lvl = zapcore.InfoLevel // Default to info
+	}
+	level.SetLevel(lvl)
+
+	// Configure encoder
+	var encoderConfig zapcore.EncoderConfig
+	if cfg.Encoding == "json" {
+		encoderConfig = zap.NewProductionEncoderConfig()
+	} else {
+		encoderConfig = zap.NewDevelopmentEncoderConfig()
+		encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	}
+
+	encoderConfig.TimeKey = "timestamp"
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	// Build logger config
+	zapConfig := zap.Config{
+		Level:            level,
+		Development:      cfg.Encoding == "console",
+		Encoding:         cfg.Encoding,
+		EncoderConfig:    encoderConfig,
+		OutputPaths:      cfg.OutputPaths,
+		ErrorOutputPaths: cfg.ErrorOutputPaths,
+	}
+
+	logger, err := zapConfig.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return logger, nil
+}
@@ -0,0 +1,80 @@
+package order_client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"payment-service/pkg/serviceauth"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned when order-service does not know the order
+var ErrNotFound = errors.New("order not found")
+
+// OrderClient handles communication with Order Service
+type OrderClient struct {
+	baseURL    string
+	httpClient *http.Client
+	signer     *serviceauth.Signer
+}
+
+// NewOrderClient creates a new order client
+// Requests are signed with signer (nil = unsigned)
+func NewOrderClient(baseURL string, timeout time.Duration, signer *serviceauth.Signer) *OrderClient {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &OrderClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		signer: signer,
+	}
+}
+
+// Order is the order info from Order Service
+type Order struct {
+	ID            uint    `json:"id"`
+	OrderNumber   string  `json:"order_number"`
+	UserID        uint    `json:"user_id"`
+	Status        string  `json:"status"`
+	FinalAmount   float64 `json:"final_amount"`
+	PaymentMethod string  `json:"payment_method"`
+}
+
+// GetOrder retrieves an order by ID
+func (c *OrderClient) GetOrder(ctx context.Context, orderID uint) (*Order, error) {
+	url := fmt.Sprintf("%s/api/v1/orders/%d", c.baseURL, orderID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build order service request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	c.signer.Sign(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call order service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("order service returned error: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var order Order
+	if err := json.NewDecoder(resp.Body).Decode(&order); err != nil {
+		return nil, fmt.Errorf("failed to decode order: %w", err)
+	}
+	return &order, nil
+}
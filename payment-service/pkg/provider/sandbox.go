@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"payment-service/internal/domain"
+)
+
+// Sandbox tokens that are declined; any other token is approved
+var sandboxDeclines = map[string]string{
+	"tok_declined":           "card_declined",
+	"tok_insufficient_funds": "insufficient_funds",
+	"tok_expired_card":       "expired_card",
+}
+
+// SandboxProvider is a payment provider that charges nothing
+// It lets the payment flow run end to end without a real gateway: the outcome
+// of a charge depends only on the payment token
+type SandboxProvider struct{}
+
+// NewSandboxProvider creates a sandbox provider
+func NewSandboxProvider() *SandboxProvider {
+	return &SandboxProvider{}
+}
+
+// Charge approves or declines the intent based on the payment token
+func (p *SandboxProvider) Charge(ctx context.Context, intent *domain.PaymentIntent, paymentToken string) (*domain.ChargeResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if reason, ok := sandboxDeclines[paymentToken]; ok {
+		return &domain.ChargeResult{DeclineReason: reason}, nil
+	}
+
+	ref := make([]byte, 12)
+	if _, err := rand.Read(ref); err != nil {
+		return nil, fmt.Errorf("failed to generate charge reference: %w", err)
+	}
+	return &domain.ChargeResult{Approved: true, Reference: "sbx_" + hex.EncodeToString(ref)}, nil
+}
@@ -10,16 +10,28 @@ import (
 	"product-service/config"
 	"product-service/internal/domain"
 	"product-service/internal/handler"
+	"product-service/internal/middleware"
 	"product-service/internal/repository/elasticsearch"
 	"product-service/internal/repository/kafka"
 	"product-service/internal/repository/postgres"
 	"product-service/internal/repository/redis"
 	"product-service/internal/router"
 	"product-service/internal/service"
+	"product-service/pkg/codevault"
 	"product-service/pkg/database"
 	esClient "product-service/pkg/elasticsearch"
+	"product-service/pkg/errorreport"
+	"product-service/pkg/featureflag"
+	"product-service/pkg/jobs"
 	"product-service/pkg/logger"
+	"product-service/pkg/order_client"
 	redisClient "product-service/pkg/redis"
+	"product-service/pkg/serviceauth"
+	"product-service/pkg/settings"
+	"product-service/pkg/shop_client"
+	"product-service/pkg/shutdown"
+	"product-service/pkg/taskqueue"
+	"product-service/pkg/validation"
 	"syscall"
 	"time"
 
@@ -138,15 +150,11 @@ func migrateProductsTable(db *gorm.DB, logger *zap.Logger) error {
 }
 
 func main() {
-	fmt.Fprintf(os.Stderr, "🚀🚀🚀 PRODUCT SERVICE MAIN() STARTED! 🚀🚀🚀\n")
-	log.Printf("🚀 PRODUCT SERVICE MAIN() STARTED!")
-
 	// Load configuration
 	cfg, err := config.LoadConfig("./config")
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
-	fmt.Fprintf(os.Stderr, "✅ Config loaded - Topic: %s, Brokers: %v\n", cfg.Kafka.TopicProductUpdated, cfg.Kafka.Brokers)
 
 	// Initialize logger
 	appLogger, err := logger.NewLogger(&cfg.Logging)
@@ -155,7 +163,18 @@ func main() {
 	}
 	defer appLogger.Sync()
 
-	appLogger.Info("Starting Product Service...")
+	appLogger.Info("Starting Product Service...", zap.String("log_level", logger.Level().String()))
+
+	// Report recovered panics to Sentry (disabled without a DSN)
+	if err := errorreport.Init(&cfg.Sentry, "product-service"); err != nil {
+		appLogger.Warn("Failed to initialize error reporting, panics are only logged", zap.Error(err))
+	}
+	defer errorreport.Flush(2 * time.Second)
+
+	// Field-level binding errors and the vnphone/slug rules
+	if err := validation.Register(); err != nil {
+		appLogger.Fatal("Failed to register request validators", zap.Error(err))
+	}
 
 	// Set Gin mode based on config
 	gin.SetMode(cfg.Server.Mode)
@@ -167,6 +186,11 @@ func main() {
 	}
 	defer database.CloseDB()
 
+	// Per-table query latency metrics (/metrics) and slow query logging
+	if err := db.Use(database.NewQueryMetrics(cfg.Database.SlowQueryThreshold, appLogger)); err != nil {
+		appLogger.Fatal("Failed to register query metrics", zap.Error(err))
+	}
+
 	// Run database migrations
 	// NOTE: Special handling for shop_id column - must add nullable first, then update data, then set NOT NULL
 	if err := migrateProductsTable(db, appLogger); err != nil {
@@ -182,6 +206,20 @@ func main() {
 		&domain.SKUConfiguration{},
 		&domain.CategoryAttribute{},
 		&domain.ProductAttributeValue{},
+		&domain.ProductSlugHistory{},
+		&domain.Campaign{},
+		&domain.Banner{},
+		&domain.InventoryDiscrepancy{},
+		&domain.SizeChart{},
+		&domain.ProductMeasurement{},
+		&domain.DigitalCode{},
+		&domain.ShopCollection{},
+		&domain.ShopCollectionProduct{},
+		&domain.CatalogQualityIssue{},
+		&domain.ShopQualityScore{},
+		&domain.OutboxEvent{},
+		&domain.ProductSalesDaily{},
+		&domain.Review{},
 	); err != nil {
 		appLogger.Fatal("Failed to run migrations", zap.Error(err))
 	}
@@ -206,27 +244,44 @@ func main() {
 	}
 
 	// Initialize Kafka event publisher
-	log.Printf("🔧 Initializing Kafka event publisher - brokers: %v, topic: %s", cfg.Kafka.Brokers, cfg.Kafka.TopicProductUpdated)
 	appLogger.Info("Initializing Kafka event publisher",
 		zap.Strings("brokers", cfg.Kafka.Brokers),
-		zap.String("topic", cfg.Kafka.TopicProductUpdated),
-	)
-	eventPublisher := kafka.NewEventPublisher(
-		cfg.Kafka.Brokers,
-		cfg.Kafka.TopicProductUpdated,
-		cfg.Kafka.WriteTimeout,
-		cfg.Kafka.RequiredAcks,
+		zap.String("topic_prefix", cfg.Kafka.TopicPrefix),
 	)
+	eventPublisher := kafka.NewEventPublisher(kafka.PublisherOptions{
+		Brokers:       cfg.Kafka.Brokers,
+		Topics:        kafka.Topics{Prefix: cfg.Kafka.TopicPrefix, Overrides: cfg.Kafka.Topics},
+		WriteTimeout:  cfg.Kafka.WriteTimeout,
+		RequiredAcks:  cfg.Kafka.RequiredAcks,
+		MaxRetries:    cfg.Kafka.MaxRetries,
+		RetryBackoff:  cfg.Kafka.RetryBackoff,
+		BufferKey:     "events:buffer:product",
+		FlushInterval: cfg.Kafka.FlushInterval,
+	}, redisClientInstance, appLogger)
 	if eventPublisher == nil {
-		log.Printf("❌❌❌ Failed to create Kafka event publisher - eventPublisher is nil")
 		appLogger.Fatal("Failed to create Kafka event publisher")
 	}
-	log.Printf("✅✅✅ Kafka event publisher initialized successfully")
-	appLogger.Info("✅ Kafka event publisher initialized successfully")
-	defer eventPublisher.Close()
+	// Redeliver events buffered during broker outages
+	publisherCtx, stopPublisher := context.WithCancel(context.Background())
+	defer stopPublisher()
+	go eventPublisher.Start(publisherCtx)
+	appLogger.Info("Kafka event publisher initialized")
+
+	// Shutdown coordinator drains the service in order on exit
+	coordinator := shutdown.NewCoordinator(appLogger)
+
+	// Bounded worker pool for request side effects (cache, index, publish)
+	taskPool := taskqueue.NewPool(taskqueue.Options{
+		Workers:    cfg.Async.Workers,
+		QueueSize:  cfg.Async.QueueSize,
+		MaxRetries: cfg.Async.MaxRetries,
+		Backoff:    cfg.Async.RetryBackoff,
+		Timeout:    cfg.Async.TaskTimeout,
+	}, appLogger)
 
 	// Initialize repositories (Infrastructure Layer)
 	productRepo := postgres.NewProductRepository(db)
+	productSlugHistoryRepo := postgres.NewProductSlugHistoryRepository(db)
 	categoryRepo := postgres.NewCategoryRepository(db)
 	variationRepo := postgres.NewVariationRepository(db)
 	variationOptRepo := postgres.NewVariationOptionRepository(db)
@@ -236,18 +291,72 @@ func main() {
 	productAttrRepo := postgres.NewProductAttributeValueRepository(db)
 	searchRepo := elasticsearch.NewProductSearchRepository(esClientInstance, cfg.Elasticsearch.IndexName)
 	cacheRepo := redis.NewCacheRepository(redisClientInstance)
+	bannerRepo := postgres.NewBannerRepository(db)
+	campaignRepo := postgres.NewCampaignRepository(db)
+	discrepancyRepo := postgres.NewInventoryDiscrepancyRepository(db)
+	sizeGuideRepo := postgres.NewSizeGuideRepository(db)
+	digitalCodeRepo := postgres.NewDigitalCodeRepository(db)
+	shopCollectionRepo := postgres.NewShopCollectionRepository(db)
+	catalogQualityRepo := postgres.NewCatalogQualityRepository(db)
+	catalogIndexer := elasticsearch.NewCatalogIndexer(esClientInstance)
+	outboxRepo := postgres.NewOutboxRepository(db)
+	productSalesRepo := postgres.NewProductSalesRepository(db)
+	reviewRepo := postgres.NewReviewRepository(db)
+	transactor := postgres.NewTransactor(db)
+
+	// Admin-managed settings and feature flags (written by identity-service, shared Redis)
+	settingsClient := settings.NewClient(redisClientInstance, appLogger)
+	flagClient := featureflag.NewClient(redisClientInstance, appLogger)
+	settingsCtx, stopSettings := context.WithCancel(context.Background())
+	defer stopSettings()
+	go settingsClient.Start(settingsCtx)
+	go flagClient.Start(settingsCtx)
+
+	// Order Service client (open-order checks before a SKU is discontinued)
+	orderClient := order_client.NewOrderClient(cfg.OrderService.BaseURL, cfg.OrderService.Timeout, serviceauth.NewSigner(cfg.InternalAuth.ServiceName, cfg.InternalAuth.Secret))
+
+	// Identity Service client (ships-from location of shops for search documents and events)
+	shopLocator := service.NewCachedShopLocator(
+		shop_client.NewShopClient(cfg.IdentityService.BaseURL, cfg.IdentityService.Timeout),
+		cfg.IdentityService.LocationCacheTTL,
+	)
+
+	// Outbox relay: publishes the product events committed with product changes
+	outboxRelay := service.NewOutboxRelay(outboxRepo, eventPublisher, shopLocator, service.OutboxOptions{
+		PollInterval: cfg.Outbox.PollInterval,
+		BatchSize:    cfg.Outbox.BatchSize,
+		MaxBackoff:   cfg.Outbox.MaxBackoff,
+		Retention:    cfg.Outbox.Retention,
+	}, appLogger)
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	relayDone := make(chan struct{})
+	go func() {
+		outboxRelay.Start(relayCtx)
+		close(relayDone)
+	}()
+
+	// Background jobs (Redis-backed, namespace "product")
+	jobWorker := jobs.NewWorker(redisClientInstance, "product", 5, appLogger)
 
 	// Initialize services (Business Logic Layer)
-	fmt.Fprintf(os.Stderr, "🔧 Creating ProductService with eventPublisher: %p\n", eventPublisher)
 	productService := service.NewProductService(
 		productRepo,
+		productSlugHistoryRepo,
 		searchRepo,
 		cacheRepo,
 		categoryRepo,
-		eventPublisher,
+		outboxRepo,
+		transactor,
+		flagClient,
+		shopLocator,
+		taskPool,
 		appLogger,
 	)
-	fmt.Fprintf(os.Stderr, "✅ ProductService created - eventPublisher injected: %p\n", eventPublisher)
+
+	// Generate slugs for products created before slugs existed
+	if _, err := productService.BackfillSlugs(context.Background()); err != nil {
+		appLogger.Warn("Failed to backfill product slugs", zap.Error(err))
+	}
 	categoryService := service.NewCategoryService(
 		categoryRepo,
 		appLogger,
@@ -258,6 +367,9 @@ func main() {
 		variationOptRepo,
 		skuConfigRepo,
 		productRepo,
+		orderClient,
+		eventPublisher,
+		taskPool,
 		appLogger,
 	)
 	attributeService := service.NewAttributeService(
@@ -267,23 +379,156 @@ func main() {
 		productRepo,
 		appLogger,
 	)
+	contentService := service.NewContentService(
+		bannerRepo,
+		campaignRepo,
+		cacheRepo,
+		appLogger,
+	)
+	salesRankingService := service.NewSalesRankingService(
+		productRepo,
+		categoryRepo,
+		productSalesRepo,
+		searchRepo,
+		redisClientInstance,
+		taskPool,
+		appLogger,
+	)
 	stockService := service.NewStockService(
 		productItemRepo,
 		redisClientInstance,
+		settingsClient,
+		jobWorker.Client,
+		eventPublisher,
+		taskPool,
+		salesRankingService,
 		appLogger,
 	)
+	feedService := service.NewFeedService(productRepo, redisClientInstance, appLogger)
+	sizeGuideService := service.NewSizeGuideService(sizeGuideRepo, categoryRepo, productRepo, appLogger)
+
+	// Digital code pools are encrypted at rest; without a key digital products cannot be fulfilled
+	codeVault, err := codevault.New(cfg.DigitalCodes.EncryptionKey)
+	if err != nil {
+		appLogger.Warn("Digital code uploads and issuing disabled", zap.Error(err))
+		codeVault = nil
+	}
+	digitalCodeService := service.NewDigitalCodeService(digitalCodeRepo, productItemRepo, productRepo, stockService, codeVault, appLogger)
+	shopCollectionService := service.NewShopCollectionService(shopCollectionRepo, productRepo, appLogger)
+	reviewService := service.NewReviewService(reviewRepo, productRepo, productService, orderClient, outboxRepo, appLogger)
+	productImportService := service.NewProductImportService(
+		productService,
+		productItemService,
+		attributeService,
+		categoryRepo,
+		categoryAttrRepo,
+		redisClientInstance,
+		jobWorker.Client,
+		service.ImportOptions{
+			MappingFile:  cfg.Import.MappingFile,
+			MaxProducts:  cfg.Import.MaxProducts,
+			FetchTimeout: cfg.Import.FetchTimeout,
+		},
+		appLogger,
+	)
+	catalogQualityService := service.NewCatalogQualityService(
+		productRepo,
+		productItemRepo,
+		productAttrRepo,
+		categoryAttrRepo,
+		catalogQualityRepo,
+		redisClientInstance,
+		jobWorker.Client,
+		service.QualityOptions{
+			PlaceholderPatterns: cfg.Quality.PlaceholderPatterns,
+			PriceLowRatio:       cfg.Quality.PriceLowRatio,
+			PriceHighRatio:      cfg.Quality.PriceHighRatio,
+			MinCategorySample:   cfg.Quality.MinCategorySample,
+		},
+		appLogger,
+	)
+	inventorySheetService := service.NewInventorySheetService(productRepo, productItemRepo, redisClientInstance, eventPublisher, taskPool, appLogger)
+	adminProductService := service.NewAdminProductService(productRepo, searchRepo, productService, appLogger)
+	recentlyViewedService := service.NewRecentlyViewedService(redisClientInstance, productService, eventPublisher, taskPool, appLogger)
+	reconcileService := service.NewInventoryReconcileService(
+		productRepo,
+		productItemRepo,
+		discrepancyRepo,
+		redisClientInstance,
+		catalogIndexer,
+		service.NewCatalogDocumentBuilder(productItemRepo, productAttrRepo, categoryAttrRepo),
+		jobWorker.Client,
+		service.ReconcileOptions{
+			Hour:       cfg.Reconcile.Hour,
+			HealSearch: cfg.Reconcile.HealSearch,
+			IndexName:  cfg.Elasticsearch.IndexName,
+		},
+		appLogger,
+	)
+
+	// Register job handlers and periodic jobs, then start processing
+	jobWorker.Register(service.JobTypeReservationExpiry, stockService.HandleReservationExpiry)
+	jobWorker.Register(service.JobTypeReservationSweep, stockService.HandleReservationSweep)
+	jobWorker.Every("reservation_sweep", service.ReservationSweepInterval, service.JobTypeReservationSweep, nil)
+	jobWorker.Register(service.JobTypeFeedGenerate, feedService.HandleGenerateFeed)
+	jobWorker.Every("product_feed", time.Hour, service.JobTypeFeedGenerate, nil)
+	jobWorker.Register(service.JobTypeInventoryReconcile, reconcileService.HandleReconcile)
+	jobWorker.Register(service.JobTypeProductImport, productImportService.HandleImport)
+	jobWorker.Register(service.JobTypeCatalogQuality, catalogQualityService.HandleQualityCheck)
+	jobWorker.Every("catalog_quality", cfg.Quality.Interval, service.JobTypeCatalogQuality, nil)
+	jobWorker.Register(service.JobTypeSalesFlush, salesRankingService.HandleFlush)
+	jobWorker.Every("sales_flush", service.SalesFlushInterval, service.JobTypeSalesFlush, nil)
+	if err := reconcileService.ScheduleNext(context.Background()); err != nil {
+		appLogger.Warn("Failed to schedule inventory reconciliation", zap.Error(err))
+	}
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	jobsDone := make(chan struct{})
+	go func() {
+		jobWorker.Start(jobsCtx)
+		close(jobsDone)
+	}()
 
 	// Initialize handlers (Transport Layer)
-	fmt.Fprintf(os.Stderr, "🔧 Creating handlers...\n")
-	productHandler := handler.NewProductHandler(productService, appLogger)
+	productHandler := handler.NewProductHandler(productService, sizeGuideService, appLogger)
 	categoryHandler := handler.NewCategoryHandler(categoryService, appLogger)
 	skuHandler := handler.NewSKUHandler(productItemService, appLogger)
 	attrHandler := handler.NewAttributeHandler(attributeService, appLogger)
 	stockHandler := handler.NewStockHandler(stockService, appLogger)
-	fmt.Fprintf(os.Stderr, "✅ Handlers created - ProductHandler: %p, eventPublisher in service: %p\n", productHandler, productService)
+	variationHandler := handler.NewVariationHandler(variationRepo, variationOptRepo, appLogger)
+	contentHandler := handler.NewContentHandler(contentService, appLogger)
+	feedHandler := handler.NewFeedHandler(feedService, appLogger)
+	jobHandler := handler.NewJobHandler(jobs.NewInspector(redisClientInstance, "product"), appLogger)
+	logLevelHandler := handler.NewLogLevelHandler(logger.Level(), appLogger)
+	taskHandler := handler.NewTaskHandler(taskPool, eventPublisher, outboxRelay, appLogger)
+	inventoryHandler := handler.NewInventoryHandler(reconcileService, appLogger)
+	recentlyViewedHandler := handler.NewRecentlyViewedHandler(recentlyViewedService, appLogger)
+	sizeGuideHandler := handler.NewSizeGuideHandler(sizeGuideService, productService, appLogger)
+	digitalCodeHandler := handler.NewDigitalCodeHandler(digitalCodeService, appLogger)
+	shopCollectionHandler := handler.NewShopCollectionHandler(shopCollectionService, appLogger)
+	bestsellerHandler := handler.NewBestsellerHandler(salesRankingService, appLogger)
+	reviewHandler := handler.NewReviewHandler(reviewService, appLogger)
+	productImportHandler := handler.NewProductImportHandler(productImportService, appLogger)
+	catalogQualityHandler := handler.NewCatalogQualityHandler(catalogQualityService, appLogger)
+	adminProductHandler := handler.NewAdminProductHandler(adminProductService, appLogger)
+	inventorySheetHandler := handler.NewInventorySheetHandler(inventorySheetService, appLogger)
+
+	// Seller API quotas (hourly/daily per shop tier, counted in Redis)
+	quotaService := service.NewQuotaService(redisClientInstance, &cfg.Quota)
+	quotaHandler := handler.NewQuotaHandler(quotaService, appLogger)
+	var quotaConsumer middleware.QuotaConsumer
+	if cfg.Quota.Enabled {
+		quotaConsumer = quotaService
+	}
+
+	// Internal endpoints only accept calls signed by trusted services (disabled = unchecked)
+	var serviceAuth *serviceauth.Verifier
+	if cfg.InternalAuth.Enabled {
+		serviceAuth = serviceauth.NewVerifier(cfg.InternalAuth.TrustedServices, cfg.InternalAuth.MaxAge)
+	}
 
 	// Setup router
-	router := router.SetupRouter(productHandler, categoryHandler, skuHandler, attrHandler, stockHandler)
+	router := router.SetupRouter(productHandler, categoryHandler, skuHandler, attrHandler, stockHandler, variationHandler, contentHandler, feedHandler, jobHandler, logLevelHandler, taskHandler, inventoryHandler, recentlyViewedHandler, sizeGuideHandler, digitalCodeHandler, shopCollectionHandler, bestsellerHandler, reviewHandler, productImportHandler, catalogQualityHandler, adminProductHandler, inventorySheetHandler, quotaHandler,
+		middleware.Recovery(appLogger), middleware.FaultInjection(&cfg.FaultInjection, "product-service", appLogger), middleware.RequestLogger(appLogger), middleware.WriteRateLimit(&cfg.RateLimit, redisClientInstance, appLogger), middleware.Quota(quotaConsumer, domain.QuotaProductCreate, appLogger), middleware.Quota(quotaConsumer, domain.QuotaStockSync, appLogger), serviceAuth)
 
 	// Create HTTP server with timeouts
 	srv := &http.Server{
@@ -298,14 +543,11 @@ func main() {
 		defer func() {
 			if r := recover(); r != nil {
 				appLogger.Error("Server goroutine panicked", zap.Any("panic", r))
-				log.Printf("Server goroutine panicked: %v", r)
 			}
 		}()
 		appLogger.Info("Server starting", zap.Int("port", cfg.Server.Port))
-		log.Printf("Server starting on port %d", cfg.Server.Port)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			appLogger.Error("Server error", zap.Error(err))
-			log.Printf("Server error: %v", err)
 			// Don't use Fatal here - it will exit the entire program
 			// Instead, log the error and let the main goroutine handle shutdown
 		}
@@ -321,32 +563,51 @@ func main() {
 	resp, err := http.DefaultClient.Do(testReq)
 	if err != nil {
 		appLogger.Warn("Server health check failed (may be starting)", zap.Error(err))
-		log.Printf("Server health check failed: %v", err)
 	} else {
 		resp.Body.Close()
 		appLogger.Info("Server is responding", zap.Int("port", cfg.Server.Port))
-		log.Printf("Server is responding on port %d", cfg.Server.Port)
 	}
 
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	appLogger.Info("Product Service is ready and waiting for requests", zap.Int("port", cfg.Server.Port))
-	log.Printf("Product Service is ready and waiting for requests on port %d", cfg.Server.Port)
 	<-quit
 
 	appLogger.Info("Shutting down server...")
 
-	// Graceful shutdown with timeout
+	// Drain in order: stop accepting requests, stop background jobs, wait for
+	// async cache/index/publish work, stop the outbox relay, then flush and close
+	// the Kafka writer
+	coordinator.OnShutdown("http server", srv.Shutdown)
+	coordinator.OnShutdown("background jobs", func(ctx context.Context) error {
+		stopJobs()
+		select {
+		case <-jobsDone:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	coordinator.OnShutdown("async tasks", taskPool.Shutdown)
+	coordinator.OnShutdown("outbox relay", func(ctx context.Context) error {
+		stopRelay()
+		select {
+		case <-relayDone:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	coordinator.OnShutdown("kafka publisher", func(context.Context) error {
+		stopPublisher()
+		return eventPublisher.Close()
+	})
+
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
+	coordinator.Shutdown(ctx)
 
-	// Shutdown HTTP server
-	if err := srv.Shutdown(ctx); err != nil {
-		appLogger.Error("Server forced to shutdown", zap.Error(err))
-	}
-
-	// Close all connections
-	// Note: Kafka publisher and Redis/ES clients are closed via defer
+	// Redis and database connections are closed via defer
 	appLogger.Info("Server exited gracefully")
 }
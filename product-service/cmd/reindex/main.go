@@ -0,0 +1,215 @@
+// Command reindex rebuilds the product search index from Postgres.
+//
+// It streams all products (with SKUs and attribute values) in ID order, bulk-writes them into
+// a new Elasticsearch index and records a checkpoint after every batch, so an interrupted run
+// can continue with -resume. Used for disaster recovery and mapping migrations.
+//
+//	go run ./cmd/reindex                              # new index products_<timestamp>
+//	go run ./cmd/reindex -alias products_current      # ...and point the alias at it when done
+//	go run ./cmd/reindex -resume                      # continue from reindex.checkpoint.json
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"product-service/config"
+	"product-service/internal/repository/elasticsearch"
+	"product-service/internal/repository/postgres"
+	"product-service/internal/service"
+	"product-service/pkg/database"
+	esClient "product-service/pkg/elasticsearch"
+	"syscall"
+	"time"
+)
+
+// checkpoint is persisted after every batch
+type checkpoint struct {
+	Index     string    `json:"index"`
+	LastID    uint      `json:"last_id"`
+	Indexed   int       `json:"indexed"`
+	Failed    int       `json:"failed"`
+	StartedAt time.Time `json:"started_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func main() {
+	index := flag.String("index", "", "target index name (default: <index_name>_<timestamp>)")
+	alias := flag.String("alias", "", "alias to point at the new index when finished (optional)")
+	batchSize := flag.Int("batch", 500, "products per batch")
+	replicas := flag.Int("replicas", 1, "number of replicas after loading")
+	resume := flag.Bool("resume", false, "resume from the checkpoint file")
+	checkpointPath := flag.String("checkpoint", "reindex.checkpoint.json", "checkpoint file path")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig("./config")
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := database.GetDB(&cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer database.CloseDB()
+
+	es, err := esClient.GetClient(&cfg.Elasticsearch)
+	if err != nil {
+		log.Fatalf("Failed to connect to Elasticsearch: %v", err)
+	}
+
+	productRepo := postgres.NewProductRepository(db)
+	builder := service.NewCatalogDocumentBuilder(
+		postgres.NewProductItemRepository(db),
+		postgres.NewProductAttributeValueRepository(db),
+		postgres.NewCategoryAttributeRepository(db),
+	)
+	indexer := elasticsearch.NewCatalogIndexer(es)
+
+	// Stop cleanly between batches on Ctrl+C (the checkpoint stays consistent)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Start a new run or continue the previous one
+	var cp checkpoint
+	if *resume {
+		if err := loadCheckpoint(*checkpointPath, &cp); err != nil {
+			log.Fatalf("Failed to load checkpoint: %v", err)
+		}
+		if *index != "" && *index != cp.Index {
+			log.Fatalf("Checkpoint is for index %q, not %q", cp.Index, *index)
+		}
+		log.Printf("Resuming reindex into %q after product ID %d (%d already indexed)", cp.Index, cp.LastID, cp.Indexed)
+	} else {
+		cp = checkpoint{Index: *index, StartedAt: time.Now()}
+		if cp.Index == "" {
+			cp.Index = fmt.Sprintf("%s_%s", cfg.Elasticsearch.IndexName, time.Now().Format("20060102150405"))
+		}
+		exists, err := indexer.IndexExists(ctx, cp.Index)
+		if err != nil {
+			log.Fatalf("Failed to check index: %v", err)
+		}
+		if exists {
+			log.Fatalf("Index %q already exists (use -resume or pick another -index)", cp.Index)
+		}
+		if err := indexer.CreateIndex(ctx, cp.Index); err != nil {
+			log.Fatalf("Failed to create index: %v", err)
+		}
+		log.Printf("Created index %q", cp.Index)
+	}
+
+	total, err := productRepo.Count(ctx)
+	if err != nil {
+		log.Fatalf("Failed to count products: %v", err)
+	}
+
+	started := time.Now()
+	indexedThisRun := 0
+
+	for {
+		if ctx.Err() != nil {
+			log.Printf("Interrupted - run again with -resume to continue from product ID %d", cp.LastID)
+			os.Exit(1)
+		}
+
+		products, err := productRepo.ListAfterID(ctx, cp.LastID, *batchSize)
+		if err != nil {
+			log.Fatalf("Failed to load products after ID %d: %v", cp.LastID, err)
+		}
+		if len(products) == 0 {
+			break
+		}
+
+		docs, err := builder.Build(ctx, products)
+		if err != nil {
+			log.Fatalf("Failed to build documents: %v", err)
+		}
+
+		failed, err := indexer.BulkIndex(ctx, cp.Index, docs)
+		if err != nil {
+			log.Fatalf("Bulk indexing failed after product ID %d: %v", cp.LastID, err)
+		}
+
+		cp.LastID = products[len(products)-1].ID
+		cp.Indexed += len(docs) - failed
+		cp.Failed += failed
+		cp.UpdatedAt = time.Now()
+		indexedThisRun += len(docs)
+		if err := saveCheckpoint(*checkpointPath, &cp); err != nil {
+			log.Fatalf("Failed to save checkpoint: %v", err)
+		}
+
+		logProgress(&cp, total, indexedThisRun, started)
+	}
+
+	if err := indexer.FinalizeIndex(context.Background(), cp.Index, *replicas); err != nil {
+		log.Fatalf("Failed to finalize index: %v", err)
+	}
+
+	if *alias != "" {
+		if err := indexer.SwapAlias(context.Background(), *alias, cp.Index); err != nil {
+			log.Fatalf("Failed to point alias %q at %q: %v", *alias, cp.Index, err)
+		}
+		log.Printf("Alias %q now points to %q", *alias, cp.Index)
+	}
+
+	// Run finished - the checkpoint is no longer needed
+	_ = os.Remove(*checkpointPath)
+
+	log.Printf("Reindex completed: index=%s indexed=%d failed=%d duration=%s",
+		cp.Index, cp.Indexed, cp.Failed, time.Since(cp.StartedAt).Round(time.Second))
+	if cp.Failed > 0 {
+		os.Exit(2)
+	}
+}
+
+// logProgress prints indexed/total, throughput and ETA
+func logProgress(cp *checkpoint, total int64, indexedThisRun int, started time.Time) {
+	done := cp.Indexed + cp.Failed
+	pct := 100.0
+	if total > 0 {
+		pct = float64(done) * 100 / float64(total)
+	}
+
+	elapsed := time.Since(started).Seconds()
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(indexedThisRun) / elapsed
+	}
+	eta := "-"
+	if rate > 0 && int64(done) < total {
+		eta = (time.Duration(float64(total-int64(done))/rate) * time.Second).Round(time.Second).String()
+	}
+
+	log.Printf("Progress: %d/%d (%.1f%%) failed=%d last_id=%d rate=%.0f docs/s eta=%s",
+		done, total, pct, cp.Failed, cp.LastID, rate, eta)
+}
+
+func loadCheckpoint(path string, cp *checkpoint) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("no checkpoint at %s", path)
+		}
+		return err
+	}
+	return json.Unmarshal(data, cp)
+}
+
+// saveCheckpoint writes atomically (temp file + rename)
+func saveCheckpoint(path string, cp *checkpoint) error {
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
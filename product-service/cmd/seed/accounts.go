@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// seedEmailDomain marks seed accounts; reset only ever deletes users with this email domain
+const seedEmailDomain = "@seed.example.com"
+
+type userFixture struct {
+	Username string
+	FullName string
+	Phone    string
+}
+
+type sellerFixture struct {
+	userFixture
+	ShopName        string
+	ShopSlug        string
+	ShopDescription string
+}
+
+var adminFixture = userFixture{Username: "seed_admin", FullName: "Quản Trị Viên", Phone: "0900000001"}
+
+var buyerFixtures = []userFixture{
+	{Username: "seed_buyer_001", FullName: "Nguyễn Văn An", Phone: "0901000001"},
+	{Username: "seed_buyer_002", FullName: "Trần Thị Bình", Phone: "0901000002"},
+	{Username: "seed_buyer_003", FullName: "Lê Minh Châu", Phone: "0901000003"},
+}
+
+// sellerFixtures own the shops referenced by productFixture.Shop (by index)
+var sellerFixtures = []sellerFixture{
+	{
+		userFixture:     userFixture{Username: "seed_seller_001", FullName: "Phạm Thu Hà", Phone: "0902000001"},
+		ShopName:        "Seed Fashion Store",
+		ShopSlug:        "seed-fashion-store",
+		ShopDescription: "Thời trang, giày dép, túi ví và mỹ phẩm",
+	},
+	{
+		userFixture:     userFixture{Username: "seed_seller_002", FullName: "Hoàng Quốc Long", Phone: "0902000002"},
+		ShopName:        "Seed Tech Store",
+		ShopSlug:        "seed-tech-store",
+		ShopDescription: "Điện thoại, laptop, thiết bị điện tử và gia dụng",
+	},
+}
+
+// identityUser mirrors the identity-service "user" table
+type identityUser struct {
+	ID           uint
+	Username     string
+	Email        string
+	PasswordHash string
+	PhoneNumber  string
+	FullName     string
+	Role         string
+	Status       string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (identityUser) TableName() string {
+	return "user"
+}
+
+// identityShop mirrors the identity-service "shop" table
+type identityShop struct {
+	ID          uint
+	OwnerUserID uint
+	Name        string
+	Slug        string
+	Description string
+	LogoURL     string
+	Status      string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (identityShop) TableName() string {
+	return "shop"
+}
+
+// identityAddress mirrors the identity-service "address" table
+type identityAddress struct {
+	ID            uint
+	UserID        uint
+	RecipientName string
+	PhoneNumber   string
+	AddressLine   string
+	City          string
+	District      string
+	Ward          string
+	IsDefault     bool
+	Label         string
+}
+
+func (identityAddress) TableName() string {
+	return "address"
+}
+
+// seedUsers upserts the admin and the buyers (by email); every buyer gets a default HOME address
+func (s *seeder) seedUsers(ctx context.Context) error {
+	hash, err := s.passwordHash()
+	if err != nil {
+		return err
+	}
+
+	admin := &identityUser{Username: adminFixture.Username, FullName: adminFixture.FullName, PhoneNumber: adminFixture.Phone, Role: "ADMIN"}
+	if err := s.upsertUser(ctx, admin, hash); err != nil {
+		return err
+	}
+
+	buyers := append([]userFixture(nil), buyerFixtures...)
+	if s.opts.faker {
+		for i := 1; i <= s.count("users"); i++ {
+			buyers = append(buyers, userFixture{
+				Username: fmt.Sprintf("seed_buyer_%03d", len(buyerFixtures)+i),
+				FullName: s.fakeFullName(),
+				Phone:    s.fakePhone(),
+			})
+		}
+	}
+
+	for _, b := range buyers {
+		user := &identityUser{Username: b.Username, FullName: b.FullName, PhoneNumber: b.Phone, Role: "BUYER"}
+		if err := s.upsertUser(ctx, user, hash); err != nil {
+			return err
+		}
+
+		line, city, district := s.fakeAddress()
+		address := identityAddress{
+			RecipientName: b.FullName,
+			PhoneNumber:   b.Phone,
+			AddressLine:   line,
+			City:          city,
+			District:      district,
+			IsDefault:     true,
+		}
+		err := s.identity.WithContext(ctx).
+			Where(identityAddress{UserID: user.ID, Label: "HOME"}).
+			Assign(address).
+			FirstOrCreate(&identityAddress{}).Error
+		if err != nil {
+			return fmt.Errorf("address of %s: %w", b.Username, err)
+		}
+	}
+
+	log.Printf("Users: admin %s and %d buyers (password %q)", adminFixture.Username+seedEmailDomain, len(buyers), s.opts.password)
+	return nil
+}
+
+// seedShops upserts the sellers (by email) and their shops (by owner)
+func (s *seeder) seedShops(ctx context.Context) error {
+	hash, err := s.passwordHash()
+	if err != nil {
+		return err
+	}
+
+	sellers := append([]sellerFixture(nil), sellerFixtures...)
+	if s.opts.faker {
+		for i := 1; i <= s.count("shops"); i++ {
+			n := len(sellerFixtures) + i
+			sellers = append(sellers, sellerFixture{
+				userFixture: userFixture{
+					Username: fmt.Sprintf("seed_seller_%03d", n),
+					FullName: s.fakeFullName(),
+					Phone:    s.fakePhone(),
+				},
+				ShopName:        fmt.Sprintf("%s Shop %03d", s.pick(fakeBrands), n),
+				ShopSlug:        fmt.Sprintf("seed-shop-%03d", n),
+				ShopDescription: "Gian hàng mẫu cho môi trường thử nghiệm",
+			})
+		}
+	}
+
+	for _, f := range sellers {
+		user := &identityUser{Username: f.Username, FullName: f.FullName, PhoneNumber: f.Phone, Role: "SELLER"}
+		if err := s.upsertUser(ctx, user, hash); err != nil {
+			return err
+		}
+
+		shop := &identityShop{
+			OwnerUserID: user.ID,
+			Name:        f.ShopName,
+			Slug:        f.ShopSlug,
+			Description: f.ShopDescription,
+			LogoURL:     placeholderImage,
+			Status:      "ACTIVE",
+		}
+		err := s.identity.WithContext(ctx).
+			Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "owner_user_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"name", "slug", "description", "logo_url", "status", "updated_at"}),
+			}).
+			Create(shop).Error
+		if err != nil {
+			return fmt.Errorf("shop of %s: %w", f.Username, err)
+		}
+	}
+
+	log.Printf("Shops: %d sellers with a shop (password %q)", len(sellers), s.opts.password)
+	return nil
+}
+
+// upsertUser creates the user or resets the existing one with the same email
+func (s *seeder) upsertUser(ctx context.Context, user *identityUser, passwordHash string) error {
+	user.Email = user.Username + seedEmailDomain
+	user.PasswordHash = passwordHash
+	user.Status = "ACTIVE"
+
+	err := s.identity.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "email"}},
+			DoUpdates: clause.AssignmentColumns([]string{"username", "password_hash", "phone_number", "full_name", "role", "status", "updated_at"}),
+		}).
+		Create(user).Error
+	if err != nil {
+		return fmt.Errorf("user %s: %w", user.Username, err)
+	}
+	return nil
+}
+
+// passwordHash hashes -password once for all users (bcrypt is deliberately slow)
+func (s *seeder) passwordHash() (string, error) {
+	if s.hashedPassword == "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(s.opts.password), bcrypt.DefaultCost)
+		if err != nil {
+			return "", err
+		}
+		s.hashedPassword = string(hash)
+	}
+	return s.hashedPassword, nil
+}
+
+// seedShopIDs returns the shops of the seed sellers, fixture sellers first
+func (s *seeder) seedShopIDs(ctx context.Context) ([]uint, error) {
+	var ids []uint
+	err := s.identity.WithContext(ctx).
+		Model(&identityShop{}).
+		Joins(`JOIN "user" u ON u.id = shop.owner_user_id`).
+		Where("u.email LIKE ?", "%"+seedEmailDomain).
+		Order("u.username").
+		Pluck("shop.id", &ids).Error
+	return ids, err
+}
+
+// resetUsers deletes the seed admin and buyers with their addresses (sellers belong to shops)
+func (s *seeder) resetUsers(ctx context.Context) error {
+	return s.deleteSeedUsers(ctx, "role <> 'SELLER'")
+}
+
+// resetShops deletes the seed sellers with their shops and addresses
+func (s *seeder) resetShops(ctx context.Context) error {
+	return s.deleteSeedUsers(ctx, "role = 'SELLER'")
+}
+
+func (s *seeder) deleteSeedUsers(ctx context.Context, roleFilter string) error {
+	users := `SELECT id FROM "user" WHERE email LIKE @email AND ` + roleFilter
+	args := map[string]interface{}{"email": "%" + seedEmailDomain}
+
+	return s.identity.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, stmt := range []string{
+			"DELETE FROM shop WHERE owner_user_id IN (" + users + ")",
+			"DELETE FROM address WHERE user_id IN (" + users + ")",
+		} {
+			if err := tx.Exec(stmt, args).Error; err != nil {
+				return err
+			}
+		}
+		result := tx.Exec(`DELETE FROM "user" WHERE id IN (`+users+`)`, args)
+		if result.Error != nil {
+			return result.Error
+		}
+		log.Printf("Deleted %d users", result.RowsAffected)
+		return nil
+	})
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// cartFixtures are the carts of the fixture buyers (by index into buyerFixtures)
+var cartFixtures = map[int][]orderLineFixture{
+	0: {{"AOTHUN-NAM-M-TRANG", 1}, {"GIAY-40-DEN", 1}},
+	1: {{"IP15PM-512-BLACK", 1}},
+	2: {{"ANESSA-SPF50", 2}, {"3CE-VELVET-001", 1}},
+}
+
+// seedCarts replaces the carts of the fixture buyers and, with -faker, of as many generated
+// buyers as the command's count. Carts are written through the order-service cart API
+func (s *seeder) seedCarts(ctx context.Context) error {
+	buyers, err := s.seedBuyers(ctx)
+	if err != nil {
+		return err
+	}
+	if len(buyers) < len(buyerFixtures) {
+		return fmt.Errorf("found %d seed buyers, run the users command first", len(buyers))
+	}
+	skus, err := s.seedSKUs(ctx)
+	if err != nil {
+		return err
+	}
+	skusByCode := make(map[string]seedSKU, len(skus))
+	for _, sku := range skus {
+		skusByCode[sku.SKUCode] = sku
+	}
+
+	for i := range buyerFixtures {
+		if err := s.carts.clear(ctx, buyers[i].UserID); err != nil {
+			return err
+		}
+		for _, l := range cartFixtures[i] {
+			sku, ok := skusByCode[l.SKU]
+			if !ok {
+				return fmt.Errorf("cart of %s: SKU %s not found, run the products command first", buyerFixtures[i].Username, l.SKU)
+			}
+			if err := s.carts.addItem(ctx, buyers[i].UserID, sku.ProductItemID, l.Quantity); err != nil {
+				return fmt.Errorf("cart of %s: %w", buyerFixtures[i].Username, err)
+			}
+		}
+	}
+
+	generated := 0
+	if s.opts.faker {
+		for _, buyer := range buyers[len(buyerFixtures):] {
+			if generated == s.count("carts") {
+				break
+			}
+			if err := s.carts.clear(ctx, buyer.UserID); err != nil {
+				return err
+			}
+			for n := 1 + s.rnd.Intn(4); n > 0; n-- {
+				sku := skus[s.rnd.Intn(len(skus))]
+				// Random SKUs may be out of stock; a smaller cart is fine
+				if err := s.carts.addItem(ctx, buyer.UserID, sku.ProductItemID, 1+s.rnd.Intn(2)); err != nil {
+					log.Printf("Skipped %s in the cart of user %d: %v", sku.SKUCode, buyer.UserID, err)
+				}
+			}
+			generated++
+		}
+	}
+
+	log.Printf("Carts: %d fixtures, %d generated", len(buyerFixtures), generated)
+	return nil
+}
+
+// resetCarts empties the carts of all seed buyers
+func (s *seeder) resetCarts(ctx context.Context) error {
+	buyers, err := s.seedBuyers(ctx)
+	if err != nil {
+		return err
+	}
+	for _, buyer := range buyers {
+		if err := s.carts.clear(ctx, buyer.UserID); err != nil {
+			return err
+		}
+	}
+	log.Printf("Emptied %d carts", len(buyers))
+	return nil
+}
+
+// cartClient calls the order-service cart API as a signed-in user (X-User-Id, normally
+// set by the API Gateway)
+type cartClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func newCartClient(baseURL string, timeout time.Duration) *cartClient {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &cartClient{
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *cartClient) clear(ctx context.Context, userID uint) error {
+	return c.do(ctx, http.MethodDelete, "/api/v1/cart", userID, nil)
+}
+
+func (c *cartClient) addItem(ctx context.Context, userID, productItemID uint, quantity int) error {
+	return c.do(ctx, http.MethodPost, "/api/v1/cart/items", userID, map[string]interface{}{
+		"product_item_id": productItemID,
+		"quantity":        quantity,
+	})
+}
+
+func (c *cartClient) do(ctx context.Context, method, path string, userID uint, body interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build cart request: %w", err)
+	}
+	req.Header.Set("X-User-Id", strconv.FormatUint(uint64(userID), 10))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call order service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("order service returned error: %d - %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
@@ -0,0 +1,433 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"product-service/internal/domain"
+	"strings"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// generatedProductPrefix marks the slugs of -faker products (and scopes their reset)
+const generatedProductPrefix = "seed-product-"
+
+// seedCategories upserts the category tree (by slug) and the category attributes
+func (s *seeder) seedCategories(ctx context.Context) error {
+	created, updated := 0, 0
+	count := func(isNew bool) {
+		if isNew {
+			created++
+		} else {
+			updated++
+		}
+	}
+
+	for _, root := range categoryFixtures {
+		parent, isNew, err := s.upsertCategory(ctx, &domain.Category{
+			Name:        root.Name,
+			Slug:        root.Slug,
+			Description: root.Description,
+			IsActive:    true,
+		})
+		if err != nil {
+			return err
+		}
+		count(isNew)
+
+		for _, child := range root.Children {
+			_, isNew, err := s.upsertCategory(ctx, &domain.Category{
+				Name:     child.Name,
+				Slug:     child.Slug,
+				ParentID: &parent.ID,
+				IsActive: true,
+			})
+			if err != nil {
+				return err
+			}
+			count(isNew)
+		}
+	}
+	log.Printf("Categories: %d created, %d updated", created, updated)
+
+	for _, attr := range attributeFixtures {
+		if err := s.upsertCategoryAttribute(ctx, attr); err != nil {
+			return err
+		}
+	}
+	log.Printf("Category attributes: %d upserted", len(attributeFixtures))
+	return nil
+}
+
+// upsertCategory creates the category or updates the existing one with the same slug
+func (s *seeder) upsertCategory(ctx context.Context, want *domain.Category) (*domain.Category, bool, error) {
+	existing, err := s.categoryRepo.GetBySlug(ctx, want.Slug)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if err := s.categoryRepo.Create(ctx, want); err != nil {
+			return nil, false, fmt.Errorf("create category %s: %w", want.Slug, err)
+		}
+		return want, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	existing.Name = want.Name
+	existing.Description = want.Description
+	existing.ParentID = want.ParentID
+	existing.IsActive = want.IsActive
+	if err := s.categoryRepo.Update(ctx, existing); err != nil {
+		return nil, false, fmt.Errorf("update category %s: %w", want.Slug, err)
+	}
+	return existing, false, nil
+}
+
+// upsertCategoryAttribute matches attributes by category and name
+func (s *seeder) upsertCategoryAttribute(ctx context.Context, f attributeFixture) error {
+	category, err := s.categoryRepo.GetBySlug(ctx, f.Category)
+	if err != nil {
+		return fmt.Errorf("category %s: %w", f.Category, err)
+	}
+	attrs, err := s.categoryAttrRepo.GetByCategoryID(ctx, category.ID)
+	if err != nil {
+		return err
+	}
+
+	attr := &domain.CategoryAttribute{CategoryID: category.ID, AttributeName: f.Name}
+	for _, existing := range attrs {
+		if existing.AttributeName == f.Name {
+			attr = existing
+			break
+		}
+	}
+	attr.InputType = f.InputType
+	attr.IsMandatory = f.IsMandatory
+	attr.IsFilterable = f.IsFilterable
+
+	if attr.ID == 0 {
+		return s.categoryAttrRepo.Create(ctx, attr)
+	}
+	return s.categoryAttrRepo.Update(ctx, attr)
+}
+
+// seedProducts upserts the fixture products (and -faker products) with their variations,
+// SKUs and attribute values. Products are spread over the seed shops
+func (s *seeder) seedProducts(ctx context.Context) error {
+	shopIDs, err := s.seedShopIDs(ctx)
+	if err != nil {
+		return err
+	}
+	if len(shopIDs) == 0 {
+		log.Println("No seed shops found (run the shops command first), using shop 1")
+		shopIDs = []uint{1}
+	}
+
+	fixtures := productFixtures
+	if s.opts.faker {
+		fixtures = append(append([]productFixture(nil), fixtures...), s.fakeProducts(s.count("products"))...)
+	}
+
+	categoryIDs := make(map[string]uint)
+	created, updated := 0, 0
+	for _, f := range fixtures {
+		categoryID, ok := categoryIDs[f.Category]
+		if !ok {
+			category, err := s.categoryRepo.GetBySlug(ctx, f.Category)
+			if err != nil {
+				return fmt.Errorf("category %s of product %s (run the categories command first): %w", f.Category, f.Slug, err)
+			}
+			categoryID = category.ID
+			categoryIDs[f.Category] = categoryID
+		}
+
+		isNew, err := s.upsertProduct(ctx, f, shopIDs[f.Shop%len(shopIDs)], categoryID)
+		if err != nil {
+			return fmt.Errorf("product %s: %w", f.Slug, err)
+		}
+		if isNew {
+			created++
+		} else {
+			updated++
+		}
+	}
+
+	log.Printf("Products: %d created, %d updated", created, updated)
+	log.Println("Run go run ./cmd/reindex to refresh the search index")
+	return nil
+}
+
+func (s *seeder) upsertProduct(ctx context.Context, f productFixture, shopID, categoryID uint) (bool, error) {
+	product, err := s.productRepo.GetBySlug(ctx, f.Slug)
+	isNew := errors.Is(err, gorm.ErrRecordNotFound)
+	if err != nil && !isNew {
+		return false, err
+	}
+	if isNew {
+		product = &domain.Product{Slug: f.Slug}
+	}
+
+	images, _ := json.Marshal([]string{placeholderImage})
+	product.ShopID = shopID
+	product.Name = f.Name
+	product.Description = f.Description
+	product.BasePrice = f.BasePrice
+	product.CategoryID = &categoryID
+	product.Status = "ACTIVE"
+	product.Images = datatypes.JSON(images)
+	product.IsActive = true
+
+	if isNew {
+		err = s.productRepo.Create(ctx, product)
+	} else {
+		err = s.productRepo.Update(ctx, product)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	options, err := s.upsertVariations(ctx, product.ID, f.Variations)
+	if err != nil {
+		return false, err
+	}
+
+	skus := f.SKUs
+	if len(skus) == 0 {
+		skus = []skuFixture{{Code: strings.ToUpper(f.Slug), Price: f.BasePrice, Stock: 100}}
+	}
+	for _, sku := range skus {
+		if err := s.upsertSKU(ctx, product.ID, sku, options); err != nil {
+			return false, fmt.Errorf("sku %s: %w", sku.Code, err)
+		}
+	}
+
+	if err := s.upsertAttributeValues(ctx, product.ID, categoryID, f.Attributes); err != nil {
+		return false, err
+	}
+	return isNew, nil
+}
+
+// upsertVariations matches variations by name and options by value. The result is indexed
+// like the fixture: options[i][value] is the option of the i-th variation
+func (s *seeder) upsertVariations(ctx context.Context, productID uint, fixtures []variationFixture) ([]map[string]uint, error) {
+	existing, err := s.variationRepo.GetByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	options := make([]map[string]uint, len(fixtures))
+	for i, f := range fixtures {
+		var variation *domain.Variation
+		for _, v := range existing {
+			if v.Name == f.Name {
+				variation = v
+				break
+			}
+		}
+		if variation == nil {
+			variation = &domain.Variation{ProductID: productID, Name: f.Name}
+			if err := s.variationRepo.Create(ctx, variation); err != nil {
+				return nil, err
+			}
+		}
+
+		current, err := s.variationOptRepo.GetByVariationID(ctx, variation.ID)
+		if err != nil {
+			return nil, err
+		}
+		options[i] = make(map[string]uint, len(f.Values))
+		for _, opt := range current {
+			options[i][opt.Value] = opt.ID
+		}
+		for _, value := range f.Values {
+			if _, ok := options[i][value]; ok {
+				continue
+			}
+			opt := &domain.VariationOption{VariationID: variation.ID, Value: value}
+			if err := s.variationOptRepo.Create(ctx, opt); err != nil {
+				return nil, err
+			}
+			options[i][value] = opt.ID
+		}
+	}
+	return options, nil
+}
+
+// upsertSKU matches the SKU by code, resets its price and stock and links its variation options
+func (s *seeder) upsertSKU(ctx context.Context, productID uint, f skuFixture, options []map[string]uint) error {
+	status := "ACTIVE"
+	if f.Stock == 0 {
+		status = "OUT_OF_STOCK"
+	}
+
+	item, err := s.productItemRepo.GetBySKUCode(ctx, f.Code)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		item = &domain.ProductItem{
+			ProductID:  productID,
+			SKUCode:    f.Code,
+			ImageURL:   placeholderImage,
+			Price:      f.Price,
+			QtyInStock: f.Stock,
+			Status:     status,
+		}
+		if err := s.productItemRepo.Create(ctx, item); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	default:
+		item.ProductID = productID
+		item.ImageURL = placeholderImage
+		item.Price = f.Price
+		item.QtyInStock = f.Stock
+		item.Status = status
+		if err := s.productItemRepo.Update(ctx, item); err != nil {
+			return err
+		}
+	}
+
+	want := make(map[uint]bool, len(f.Options))
+	for i, value := range f.Options {
+		if i >= len(options) {
+			return fmt.Errorf("option %q has no variation", value)
+		}
+		optionID, ok := options[i][value]
+		if !ok {
+			return fmt.Errorf("unknown option %q", value)
+		}
+		want[optionID] = true
+	}
+
+	current, err := s.skuConfigRepo.GetByProductItemID(ctx, item.ID)
+	if err != nil {
+		return err
+	}
+	same := len(current) == len(want)
+	for _, c := range current {
+		same = same && want[c.VariationOptionID]
+	}
+	if same {
+		return nil
+	}
+
+	if err := s.skuConfigRepo.DeleteByProductItemID(ctx, item.ID); err != nil {
+		return err
+	}
+	if len(want) == 0 {
+		return nil
+	}
+	configs := make([]*domain.SKUConfiguration, 0, len(want))
+	for optionID := range want {
+		configs = append(configs, &domain.SKUConfiguration{ProductItemID: item.ID, VariationOptionID: optionID})
+	}
+	return s.skuConfigRepo.CreateBatch(ctx, configs)
+}
+
+// upsertAttributeValues sets the product's values of its category's attributes
+func (s *seeder) upsertAttributeValues(ctx context.Context, productID, categoryID uint, values map[string]string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	attrs, err := s.categoryAttrRepo.GetByCategoryID(ctx, categoryID)
+	if err != nil {
+		return err
+	}
+	current, err := s.productAttrRepo.GetByProductID(ctx, productID)
+	if err != nil {
+		return err
+	}
+
+	for _, attr := range attrs {
+		value, ok := values[attr.AttributeName]
+		if !ok {
+			continue
+		}
+
+		var existing *domain.ProductAttributeValue
+		for _, v := range current {
+			if v.AttributeID == attr.ID {
+				existing = v
+				break
+			}
+		}
+		if existing == nil {
+			err = s.productAttrRepo.Create(ctx, &domain.ProductAttributeValue{ProductID: productID, AttributeID: attr.ID, Value: value})
+		} else if existing.Value != value {
+			existing.Value = value
+			err = s.productAttrRepo.Update(ctx, existing)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resetProducts deletes the fixture and generated products with everything hanging off them
+func (s *seeder) resetProducts(ctx context.Context) error {
+	slugs := make([]string, 0, len(productFixtures))
+	for _, f := range productFixtures {
+		slugs = append(slugs, f.Slug)
+	}
+	products := "SELECT id FROM products WHERE slug IN @slugs OR slug LIKE @generated"
+	statements := []string{
+		"DELETE FROM sku_configuration WHERE product_item_id IN (SELECT id FROM product_item WHERE product_id IN (" + products + "))",
+		"DELETE FROM variation_option WHERE variation_id IN (SELECT id FROM variation WHERE product_id IN (" + products + "))",
+		"DELETE FROM variation WHERE product_id IN (" + products + ")",
+		"DELETE FROM product_attribute_value WHERE product_id IN (" + products + ")",
+		"DELETE FROM product_slug_history WHERE product_id IN (" + products + ")",
+		"DELETE FROM product_item WHERE product_id IN (" + products + ")",
+		"DELETE FROM products WHERE id IN (" + products + ")",
+	}
+	args := map[string]interface{}{"slugs": slugs, "generated": generatedProductPrefix + "%"}
+
+	return s.catalog.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, stmt := range statements {
+			result := tx.Exec(stmt, args)
+			if result.Error != nil {
+				return result.Error
+			}
+			if strings.HasPrefix(stmt, "DELETE FROM products ") {
+				log.Printf("Deleted %d products", result.RowsAffected)
+			}
+		}
+		return nil
+	})
+}
+
+// resetCategories deletes the fixture categories that no product or other category uses anymore
+// (leaves first, then roots), together with their attributes
+func (s *seeder) resetCategories(ctx context.Context) error {
+	var leaves, roots []string
+	for _, root := range categoryFixtures {
+		roots = append(roots, root.Slug)
+		for _, child := range root.Children {
+			leaves = append(leaves, child.Slug)
+		}
+	}
+
+	unused := "SELECT c.id FROM categories c WHERE c.slug IN @slugs" +
+		" AND NOT EXISTS (SELECT 1 FROM products p WHERE p.category_id = c.id)" +
+		" AND NOT EXISTS (SELECT 1 FROM categories cc WHERE cc.parent_id = c.id)"
+
+	return s.catalog.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var deleted int64
+		for _, slugs := range [][]string{leaves, roots} {
+			args := map[string]interface{}{"slugs": slugs}
+			if err := tx.Exec("DELETE FROM category_attribute WHERE category_id IN ("+unused+")", args).Error; err != nil {
+				return err
+			}
+			result := tx.Exec("DELETE FROM categories WHERE id IN ("+unused+")", args)
+			if result.Error != nil {
+				return result.Error
+			}
+			deleted += result.RowsAffected
+		}
+		log.Printf("Deleted %d categories", deleted)
+		return nil
+	})
+}
@@ -0,0 +1,393 @@
+package main
+
+// categoryFixture is a root category with its leaf categories
+type categoryFixture struct {
+	Name        string
+	Slug        string
+	Description string
+	Children    []childCategoryFixture
+}
+
+type childCategoryFixture struct {
+	Name string
+	Slug string
+}
+
+// productFixture is a product with its variations and SKUs. Products without SKUs get
+// one default SKU (the slug in upper case) so that every product can be ordered
+type productFixture struct {
+	Slug        string
+	Name        string
+	Description string
+	Category    string // leaf category slug
+	Shop        int    // index into sellerFixtures
+	BasePrice   float64
+	Variations  []variationFixture
+	SKUs        []skuFixture
+	Attributes  map[string]string // category attribute name -> value
+}
+
+type variationFixture struct {
+	Name   string
+	Values []string
+}
+
+type skuFixture struct {
+	Code    string
+	Options []string // one value per variation, in variation order
+	Price   float64
+	Stock   int
+}
+
+// attributeFixture is a category attribute; Category is a leaf category slug
+type attributeFixture struct {
+	Category     string
+	Name         string
+	InputType    string
+	IsMandatory  bool
+	IsFilterable bool
+}
+
+const placeholderImage = "https://placehold.co/400x400"
+
+var categoryFixtures = []categoryFixture{
+	{
+		Name: "Thời Trang Nam", Slug: "thoi-trang-nam",
+		Description: "Quần áo và phụ kiện dành cho nam giới",
+		Children: []childCategoryFixture{
+			{"Áo Thun Nam", "ao-thun-nam"},
+			{"Áo Sơ Mi Nam", "ao-so-mi-nam"},
+			{"Áo Khoác Nam", "ao-khoac-nam"},
+			{"Quần Jeans Nam", "quan-jeans-nam"},
+			{"Quần Short Nam", "quan-short-nam"},
+			{"Áo Polo Nam", "ao-polo-nam"},
+			{"Quần Tây Nam", "quan-tay-nam"},
+			{"Áo Vest Nam", "ao-vest-nam"},
+		},
+	},
+	{
+		Name: "Thời Trang Nữ", Slug: "thoi-trang-nu",
+		Description: "Quần áo và phụ kiện dành cho nữ giới",
+		Children: []childCategoryFixture{
+			{"Váy Đầm", "vay-dam"},
+			{"Áo Kiểu Nữ", "ao-kieu-nu"},
+			{"Áo Thun Nữ", "ao-thun-nu"},
+			{"Quần Jeans Nữ", "quan-jeans-nu"},
+			{"Chân Váy", "chan-vay"},
+			{"Áo Khoác Nữ", "ao-khoac-nu"},
+			{"Đồ Mặc Nhà", "do-mac-nha"},
+			{"Đồ Bộ Nữ", "do-bo-nu"},
+		},
+	},
+	{
+		Name: "Điện Thoại & Phụ Kiện", Slug: "dien-thoai-phu-kien",
+		Description: "Điện thoại di động, máy tính bảng và phụ kiện",
+		Children: []childCategoryFixture{
+			{"Điện Thoại", "dien-thoai"},
+			{"Máy Tính Bảng", "may-tinh-bang"},
+			{"Ốp Lưng Điện Thoại", "op-lung-dien-thoai"},
+			{"Cáp Sạc", "cap-sac"},
+			{"Tai Nghe", "tai-nghe"},
+			{"Sạc Dự Phòng", "sac-du-phong"},
+			{"Miếng Dán Màn Hình", "mieng-dan-man-hinh"},
+		},
+	},
+	{
+		Name: "Mẹ & Bé", Slug: "me-va-be",
+		Description: "Sản phẩm cho mẹ và bé",
+	},
+	{
+		Name: "Thiết Bị Điện Tử", Slug: "thiet-bi-dien-tu",
+		Description: "Laptop, máy tính, camera, thiết bị âm thanh",
+		Children: []childCategoryFixture{
+			{"Laptop", "laptop"},
+			{"Máy Tính Để Bàn", "may-tinh-de-ban"},
+			{"Camera", "camera"},
+			{"Tai Nghe - Loa", "tai-nghe-loa"},
+			{"Thiết Bị Mạng", "thiet-bi-mang"},
+			{"Linh Kiện Máy Tính", "linh-kien-may-tinh"},
+		},
+	},
+	{
+		Name: "Nhà Cửa & Đời Sống", Slug: "nha-cua-doi-song",
+		Description: "Đồ gia dụng, nội thất, trang trí nhà cửa",
+		Children: []childCategoryFixture{
+			{"Chăn Ga Gối", "chan-ga-goi"},
+			{"Nội Thất", "noi-that"},
+			{"Đồ Dùng Nhà Bếp", "do-dung-nha-bep"},
+			{"Trang Trí Nhà Cửa", "trang-tri-nha-cua"},
+			{"Đèn", "den"},
+		},
+	},
+	{
+		Name: "Sắc Đẹp", Slug: "sac-dep",
+		Description: "Mỹ phẩm, chăm sóc da, trang điểm",
+		Children: []childCategoryFixture{
+			{"Chăm Sóc Da Mặt", "cham-soc-da-mat"},
+			{"Trang Điểm", "trang-diem"},
+			{"Dưỡng Thể", "duong-the"},
+			{"Nước Hoa", "nuoc-hoa"},
+			{"Chăm Sóc Tóc", "cham-soc-toc"},
+		},
+	},
+	{
+		Name: "Sức Khỏe", Slug: "suc-khoe",
+		Description: "Thực phẩm chức năng, thiết bị y tế",
+	},
+	{
+		Name: "Giày Dép Nam", Slug: "giay-dep-nam",
+		Description: "Giày thể thao, giày tây, dép nam",
+		Children: []childCategoryFixture{
+			{"Giày Thể Thao Nam", "giay-the-thao-nam"},
+			{"Giày Tây Nam", "giay-tay-nam"},
+			{"Dép Nam", "dep-nam"},
+			{"Giày Boots Nam", "giay-boots-nam"},
+		},
+	},
+	{
+		Name: "Giày Dép Nữ", Slug: "giay-dep-nu",
+		Description: "Giày cao gót, giày thể thao, sandal nữ",
+	},
+	{
+		Name: "Túi Ví Nam", Slug: "tui-vi-nam",
+		Description: "Balo, cặp da, ví nam",
+		Children: []childCategoryFixture{
+			{"Balo Nam", "balo-nam"},
+			{"Túi Đeo Chéo Nam", "tui-deo-cheo-nam"},
+			{"Ví Nam", "vi-nam"},
+			{"Cặp Laptop", "cap-laptop"},
+		},
+	},
+	{
+		Name: "Túi Ví Nữ", Slug: "tui-vi-nu",
+		Description: "Túi xách, ví nữ, clutch",
+	},
+	{
+		Name: "Đồng Hồ", Slug: "dong-ho",
+		Description: "Đồng hồ nam, nữ, trẻ em",
+	},
+	{
+		Name: "Thể Thao & Du Lịch", Slug: "the-thao-du-lich",
+		Description: "Dụng cụ thể thao, đồ du lịch",
+	},
+	{
+		Name: "Ô Tô & Xe Máy & Xe Đạp", Slug: "o-to-xe-may-xe-dap",
+		Description: "Phụ kiện và thiết bị cho xe",
+	},
+}
+
+var attributeFixtures = []attributeFixture{
+	{Category: "dien-thoai", Name: "Thương Hiệu", InputType: "text", IsMandatory: true, IsFilterable: true},
+	{Category: "dien-thoai", Name: "Màn Hình", InputType: "text", IsFilterable: true},
+	{Category: "dien-thoai", Name: "Chip", InputType: "text"},
+	{Category: "dien-thoai", Name: "RAM", InputType: "text", IsFilterable: true},
+	{Category: "dien-thoai", Name: "Pin", InputType: "text"},
+	{Category: "dien-thoai", Name: "Bảo Hành", InputType: "text"},
+	{Category: "laptop", Name: "Thương Hiệu", InputType: "text", IsMandatory: true, IsFilterable: true},
+	{Category: "laptop", Name: "CPU", InputType: "text", IsFilterable: true},
+	{Category: "laptop", Name: "RAM", InputType: "text", IsFilterable: true},
+	{Category: "laptop", Name: "Ổ Cứng", InputType: "text", IsFilterable: true},
+	{Category: "ao-thun-nam", Name: "Chất Liệu", InputType: "text", IsFilterable: true},
+	{Category: "ao-thun-nam", Name: "Xuất Xứ", InputType: "text"},
+	{Category: "giay-the-thao-nam", Name: "Chất Liệu", InputType: "text", IsFilterable: true},
+	{Category: "giay-the-thao-nam", Name: "Xuất Xứ", InputType: "text"},
+}
+
+var productFixtures = []productFixture{
+	// Thời Trang Nam
+	{
+		Slug: "aothun-nam-001", Category: "ao-thun-nam", Shop: 0, BasePrice: 159000,
+		Name:        "Áo Thun Nam Cotton Compact Form Rộng Unisex",
+		Description: "Áo thun nam cotton 100%, form rộng thoải mái, nhiều màu",
+		Variations: []variationFixture{
+			{Name: "Kích Thước", Values: []string{"M", "L", "XL"}},
+			{Name: "Màu Sắc", Values: []string{"Trắng", "Đen", "Xám"}},
+		},
+		SKUs: []skuFixture{
+			{"AOTHUN-NAM-M-TRANG", []string{"M", "Trắng"}, 129000, 50},
+			{"AOTHUN-NAM-M-DEN", []string{"M", "Đen"}, 129000, 45},
+			{"AOTHUN-NAM-L-TRANG", []string{"L", "Trắng"}, 129000, 60},
+			{"AOTHUN-NAM-L-DEN", []string{"L", "Đen"}, 129000, 55},
+			{"AOTHUN-NAM-L-XAM", []string{"L", "Xám"}, 129000, 40},
+			{"AOTHUN-NAM-XL-TRANG", []string{"XL", "Trắng"}, 139000, 35},
+			{"AOTHUN-NAM-XL-DEN", []string{"XL", "Đen"}, 139000, 30},
+		},
+		Attributes: map[string]string{"Chất Liệu": "100% Cotton", "Xuất Xứ": "Việt Nam"},
+	},
+	{
+		Slug: "aothun-nam-002", Category: "ao-thun-nam", Shop: 0, BasePrice: 199000,
+		Name:        "Áo Thun Nam Polo Trơn Cao Cấp",
+		Description: "Áo thun polo nam, chất liệu cotton mềm mại, không xù lông",
+		Attributes:  map[string]string{"Chất Liệu": "Cotton", "Xuất Xứ": "Việt Nam"},
+	},
+	{
+		Slug: "aothun-nam-003", Category: "ao-thun-nam", Shop: 0, BasePrice: 229000,
+		Name:        "Áo Thun Nam Tay Lỡ Form Rộng Streetwear",
+		Description: "Áo thun oversize phong cách Hàn Quốc, chất liệu cotton 4 chiều",
+	},
+	{
+		Slug: "aosomi-nam-001", Category: "ao-so-mi-nam", Shop: 0, BasePrice: 299000,
+		Name:        "Áo Sơ Mi Nam Dài Tay Công Sở",
+		Description: "Áo sơ mi nam dài tay, chống nhăn, phù hợp đi làm",
+	},
+	{
+		Slug: "aosomi-nam-002", Category: "ao-so-mi-nam", Shop: 0, BasePrice: 249000,
+		Name:        "Áo Sơ Mi Nam Ngắn Tay Trẻ Trung",
+		Description: "Áo sơ mi nam ngắn tay, form fitted hiện đại",
+	},
+	{
+		Slug: "khoac-nam-001", Category: "ao-khoac-nam", Shop: 0, BasePrice: 599000,
+		Name:        "Áo Khoác Nam Bomber Jacket 2 Lớp Chống Nước",
+		Description: "Áo khoác bomber 2 lớp, chống nước, nhiều màu sắc",
+	},
+	{
+		Slug: "khoac-nam-002", Category: "ao-khoac-nam", Shop: 0, BasePrice: 449000,
+		Name:        "Áo Khoác Nam Dù Nhẹ Chống Tia UV",
+		Description: "Áo khoác dù siêu nhẹ, chống tia UV, gấp gọn tiện lợi",
+	},
+	{
+		Slug: "khoac-nam-003", Category: "ao-khoac-nam", Shop: 0, BasePrice: 499000,
+		Name:        "Áo Khoác Nam Hoodie Nỉ Ngoại Có Mũ",
+		Description: "Áo hoodie nỉ ngoại dày dặn, giữ ấm tốt",
+	},
+	{
+		Slug: "jean-nam-001", Category: "quan-jeans-nam", Shop: 0, BasePrice: 399000,
+		Name:        "Quần Jeans Nam Ống Rộng Suông Baggy",
+		Description: "Quần jean nam ống rộng, chất liệu denim cao cấp",
+	},
+	{
+		Slug: "jean-nam-002", Category: "quan-jeans-nam", Shop: 0, BasePrice: 429000,
+		Name:        "Quần Jeans Nam Ống Đứng Slimfit",
+		Description: "Quần jean nam ống đứng, form slimfit ôm vừa vặn",
+	},
+	{
+		Slug: "short-nam-001", Category: "quan-short-nam", Shop: 0, BasePrice: 229000,
+		Name:        "Quần Short Nam Kaki Túi Hộp Thể Thao",
+		Description: "Quần short kaki nam, túi hộp tiện dụng, thoáng mát",
+	},
+	{
+		Slug: "short-nam-002", Category: "quan-short-nam", Shop: 0, BasePrice: 279000,
+		Name:        "Quần Short Nam Jeans Rách Cá Tính",
+		Description: "Quần short jeans rách, phong cách năng động trẻ trung",
+	},
+	// Thời Trang Nữ
+	{
+		Slug: "vay-nu-001", Category: "vay-dam", Shop: 0, BasePrice: 249000,
+		Name:        "Váy Babydoll Hoa Nhí Tay Bồng",
+		Description: "Váy babydoll dáng xòe, họa tiết hoa nhí xinh xắn",
+	},
+	{
+		Slug: "aokieu-nu-001", Category: "ao-kieu-nu", Shop: 0, BasePrice: 199000,
+		Name:        "Áo Kiểu Nữ Dài Tay Công Sở",
+		Description: "Áo kiểu nữ dài tay, chất liệu lụa mềm mại",
+	},
+	// Điện Thoại
+	{
+		Slug: "iphone15pm-256", Category: "dien-thoai", Shop: 1, BasePrice: 33990000,
+		Name:        "iPhone 15 Pro Max 256GB Chính Hãng VN/A",
+		Description: "iPhone 15 Pro Max - Chip A17 Pro, Camera 48MP, Màn hình 6.7 inch",
+		Variations: []variationFixture{
+			{Name: "Bộ Nhớ", Values: []string{"256GB", "512GB", "1TB"}},
+			{Name: "Màu Sắc", Values: []string{"Titan Tự Nhiên", "Titan Xanh", "Titan Đen"}},
+		},
+		SKUs: []skuFixture{
+			{"IP15PM-256-NATURAL", []string{"256GB", "Titan Tự Nhiên"}, 29990000, 10},
+			{"IP15PM-256-BLUE", []string{"256GB", "Titan Xanh"}, 29990000, 8},
+			{"IP15PM-512-NATURAL", []string{"512GB", "Titan Tự Nhiên"}, 34990000, 5},
+			{"IP15PM-512-BLACK", []string{"512GB", "Titan Đen"}, 34990000, 6},
+			{"IP15PM-1TB-BLUE", []string{"1TB", "Titan Xanh"}, 39990000, 3},
+		},
+		Attributes: map[string]string{
+			"Thương Hiệu": "Apple", "Màn Hình": "6.7 inch", "Chip": "A17 Pro",
+			"RAM": "8GB", "Pin": "4422 mAh", "Bảo Hành": "12 tháng",
+		},
+	},
+	{
+		Slug: "samsung-s24u-256", Category: "dien-thoai", Shop: 1, BasePrice: 31990000,
+		Name:        "Samsung Galaxy S24 Ultra 12GB/256GB",
+		Description: "Galaxy S24 Ultra - Snapdragon 8 Gen 3, Camera 200MP, S Pen",
+		Attributes: map[string]string{
+			"Thương Hiệu": "Samsung", "Màn Hình": "6.8 inch", "Chip": "Snapdragon 8 Gen 3",
+			"RAM": "12GB", "Pin": "5000 mAh", "Bảo Hành": "12 tháng",
+		},
+	},
+	{
+		Slug: "xiaomi-rn13p-256", Category: "dien-thoai", Shop: 1, BasePrice: 8990000,
+		Name:        "Xiaomi Redmi Note 13 Pro 8GB/256GB",
+		Description: "Redmi Note 13 Pro - Camera 200MP, Màn hình AMOLED 120Hz",
+		Attributes: map[string]string{
+			"Thương Hiệu": "Xiaomi", "Màn Hình": "6.67 inch", "Chip": "Helio G99 Ultra",
+			"RAM": "8GB", "Pin": "5000 mAh", "Bảo Hành": "18 tháng",
+		},
+	},
+	// Thiết Bị Điện Tử
+	{
+		Slug: "dell-ins15-3520", Category: "laptop", Shop: 1, BasePrice: 16990000,
+		Name:        "Laptop Dell Inspiron 15 3520 i5-1235U/8GB/512GB",
+		Description: "Dell Inspiron 15 - Intel Core i5 Gen 12, RAM 8GB, SSD 512GB",
+		Attributes:  map[string]string{"Thương Hiệu": "Dell", "CPU": "Intel Core i5-1235U", "RAM": "8GB", "Ổ Cứng": "512GB SSD"},
+	},
+	{
+		Slug: "sony-wh1000xm5", Category: "tai-nghe-loa", Shop: 1, BasePrice: 9990000,
+		Name:        "Tai Nghe Bluetooth Sony WH-1000XM5",
+		Description: "Tai nghe chống ồn chủ động hàng đầu, pin 30 giờ",
+	},
+	// Giày Dép Nam
+	{
+		Slug: "giay-nam-001", Category: "giay-the-thao-nam", Shop: 0, BasePrice: 399000,
+		Name:        "Giày Sneaker Nam Thể Thao Cổ Thấp",
+		Description: "Giày sneaker nam, đế cao su, êm ái thoáng khí",
+		Variations: []variationFixture{
+			{Name: "Kích Thước", Values: []string{"39", "40", "41", "42"}},
+			{Name: "Màu Sắc", Values: []string{"Trắng", "Đen", "Đỏ"}},
+		},
+		SKUs: []skuFixture{
+			{"GIAY-39-TRANG", []string{"39", "Trắng"}, 399000, 25},
+			{"GIAY-40-TRANG", []string{"40", "Trắng"}, 399000, 30},
+			{"GIAY-40-DEN", []string{"40", "Đen"}, 399000, 28},
+			{"GIAY-41-TRANG", []string{"41", "Trắng"}, 399000, 35},
+			{"GIAY-41-DEN", []string{"41", "Đen"}, 399000, 32},
+			{"GIAY-41-DO", []string{"41", "Đỏ"}, 399000, 20},
+			{"GIAY-42-DEN", []string{"42", "Đen"}, 399000, 22},
+		},
+		Attributes: map[string]string{"Chất Liệu": "Da tổng hợp & Lưới", "Xuất Xứ": "Việt Nam"},
+	},
+	{
+		Slug: "dep-nam-001", Category: "dep-nam", Shop: 0, BasePrice: 129000,
+		Name:        "Dép Quai Ngang Nam Nữ Unisex",
+		Description: "Dép quai ngang đế êm, chống trơn trượt",
+	},
+	// Túi Ví Nam
+	{
+		Slug: "balo-nam-001", Category: "balo-nam", Shop: 0, BasePrice: 449000,
+		Name:        "Balo Laptop 15.6 inch Chống Nước",
+		Description: "Balo laptop đa ngăn, chống nước, chống sốc",
+	},
+	{
+		Slug: "vi-nam-001", Category: "vi-nam", Shop: 0, BasePrice: 259000,
+		Name:        "Ví Da Nam Cao Cấp Đựng Thẻ ATM",
+		Description: "Ví da bò thật, nhiều ngăn đựng thẻ tiện lợi",
+	},
+	// Sắc Đẹp
+	{
+		Slug: "anessa-spf50", Category: "cham-soc-da-mat", Shop: 0, BasePrice: 599000,
+		Name:        "Kem Chống Nắng Anessa SPF50+ PA++++",
+		Description: "Kem chống nắng Nhật Bản, chống nước, lâu trôi",
+	},
+	{
+		Slug: "3ce-velvet-001", Category: "trang-diem", Shop: 0, BasePrice: 329000,
+		Name:        "Son Kem Lì 3CE Velvet Lip Tint",
+		Description: "Son kem lì Hàn Quốc, lên màu chuẩn, bền màu",
+	},
+	// Nhà Cửa & Đời Sống
+	{
+		Slug: "sharp-rc18", Category: "do-dung-nha-bep", Shop: 1, BasePrice: 1690000,
+		Name:        "Nồi Cơm Điện Tử Sharp 1.8L",
+		Description: "Nồi cơm điện tử công nghệ Nhật, lòng chống dính",
+	},
+	{
+		Slug: "xiaomi-led-001", Category: "den", Shop: 1, BasePrice: 499000,
+		Name:        "Đèn LED Thông Minh Xiaomi",
+		Description: "Đèn LED điều khiển qua app, 16 triệu màu",
+	},
+}
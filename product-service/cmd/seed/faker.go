@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+)
+
+// Word lists of the -faker generator. Generated records take their key (slug, email,
+// order number) from their position, so re-running with a bigger -count only adds records
+var (
+	fakeProductNouns = []string{
+		"Áo Thun", "Áo Sơ Mi", "Áo Khoác", "Quần Jeans", "Quần Short", "Váy", "Giày Sneaker",
+		"Balo", "Ví Da", "Tai Nghe", "Sạc Dự Phòng", "Ốp Lưng", "Đèn Ngủ", "Bình Giữ Nhiệt",
+		"Nồi Chiên Không Dầu", "Kem Dưỡng Da", "Son Môi", "Đồng Hồ", "Mũ Lưỡi Trai", "Túi Tote",
+	}
+	fakeProductAdjectives = []string{
+		"Cao Cấp", "Basic", "Form Rộng", "Chống Nước", "Siêu Nhẹ", "Thời Trang", "Unisex",
+		"Phong Cách Hàn Quốc", "Chính Hãng", "Giá Rẻ", "Mini", "Đa Năng", "Thể Thao", "Vintage",
+	}
+	fakeBrands = []string{
+		"Local Brand", "Coolmate", "Yame", "Routine", "Anker", "Baseus", "Xiaomi", "Lock&Lock",
+		"Sunhouse", "Innisfree", "Casio", "Owen",
+	}
+	fakeColors = []string{"Trắng", "Đen", "Xám", "Xanh Navy", "Đỏ", "Be", "Hồng", "Xanh Lá"}
+
+	fakeLastNames   = []string{"Nguyễn", "Trần", "Lê", "Phạm", "Hoàng", "Huỳnh", "Phan", "Vũ", "Võ", "Đặng", "Bùi", "Đỗ"}
+	fakeMiddleNames = []string{"Văn", "Thị", "Minh", "Thanh", "Ngọc", "Quốc", "Gia", "Hoài", "Đức", "Thu"}
+	fakeFirstNames  = []string{"An", "Bình", "Châu", "Dũng", "Giang", "Hà", "Hải", "Hùng", "Khang", "Lan", "Linh", "Long", "Mai", "Nam", "Phúc", "Quân", "Tâm", "Trang", "Tú", "Vy"}
+
+	fakeStreets = []string{"Lê Lợi", "Nguyễn Huệ", "Trần Hưng Đạo", "Hai Bà Trưng", "Lý Thường Kiệt", "Điện Biên Phủ", "Cách Mạng Tháng 8", "Nguyễn Trãi"}
+	fakeCities  = []struct {
+		City      string
+		Districts []string
+	}{
+		{"Hồ Chí Minh", []string{"Quận 1", "Quận 3", "Quận 7", "Bình Thạnh", "Phú Nhuận", "Thủ Đức"}},
+		{"Hà Nội", []string{"Ba Đình", "Hoàn Kiếm", "Đống Đa", "Cầu Giấy", "Hai Bà Trưng", "Thanh Xuân"}},
+		{"Đà Nẵng", []string{"Hải Châu", "Thanh Khê", "Sơn Trà", "Ngũ Hành Sơn"}},
+	}
+)
+
+// commandRand returns the generator of one command, so a command generates the same data
+// whether it runs alone or as part of "all"
+func commandRand(seed int64, command string) *rand.Rand {
+	h := fnv.New64a()
+	h.Write([]byte(command))
+	return rand.New(rand.NewSource(seed ^ int64(h.Sum64())))
+}
+
+func (s *seeder) pick(words []string) string {
+	return words[s.rnd.Intn(len(words))]
+}
+
+// fakeProducts generates n products in random leaf categories, each with one to three colours
+func (s *seeder) fakeProducts(n int) []productFixture {
+	var leaves []string
+	for _, root := range categoryFixtures {
+		for _, child := range root.Children {
+			leaves = append(leaves, child.Slug)
+		}
+	}
+
+	products := make([]productFixture, 0, n)
+	for i := 1; i <= n; i++ {
+		name := fmt.Sprintf("%s %s %s", s.pick(fakeProductNouns), s.pick(fakeProductAdjectives), s.pick(fakeBrands))
+		price := float64(50+s.rnd.Intn(1950)) * 1000
+
+		colors := s.rnd.Perm(len(fakeColors))[:1+s.rnd.Intn(3)]
+		variation := variationFixture{Name: "Màu Sắc"}
+		var skus []skuFixture
+		for j, c := range colors {
+			variation.Values = append(variation.Values, fakeColors[c])
+			skus = append(skus, skuFixture{
+				Code:    fmt.Sprintf("SEED-P%05d-%d", i, j+1),
+				Options: []string{fakeColors[c]},
+				Price:   price,
+				Stock:   s.rnd.Intn(200),
+			})
+		}
+
+		products = append(products, productFixture{
+			Slug:        fmt.Sprintf("%s%05d", generatedProductPrefix, i),
+			Name:        name,
+			Description: fmt.Sprintf("%s - sản phẩm mẫu cho môi trường thử nghiệm", name),
+			Category:    leaves[s.rnd.Intn(len(leaves))],
+			Shop:        s.rnd.Intn(1 << 16), // reduced modulo the number of shops
+			BasePrice:   price,
+			Variations:  []variationFixture{variation},
+			SKUs:        skus,
+		})
+	}
+	return products
+}
+
+func (s *seeder) fakeFullName() string {
+	return fmt.Sprintf("%s %s %s", s.pick(fakeLastNames), s.pick(fakeMiddleNames), s.pick(fakeFirstNames))
+}
+
+func (s *seeder) fakePhone() string {
+	return fmt.Sprintf("09%08d", s.rnd.Intn(100000000))
+}
+
+func (s *seeder) fakeAddress() (line, city, district string) {
+	c := fakeCities[s.rnd.Intn(len(fakeCities))]
+	return fmt.Sprintf("%d %s", 1+s.rnd.Intn(300), s.pick(fakeStreets)), c.City, c.Districts[s.rnd.Intn(len(c.Districts))]
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// seedOrderPrefix marks seed order numbers; reset only ever deletes orders with this prefix
+const seedOrderPrefix = "SEED-"
+
+// Amounts like order-service computes them with its default settings
+const (
+	seedShippingFee        = 30000
+	seedPlatformFeePercent = 5
+)
+
+// orderFixture is an order of a fixture buyer; all its SKUs must belong to one shop
+type orderFixture struct {
+	Number  string
+	Buyer   int // index into buyerFixtures
+	Status  string
+	DaysAgo int
+	Lines   []orderLineFixture
+}
+
+type orderLineFixture struct {
+	SKU      string
+	Quantity int
+}
+
+var orderFixtures = []orderFixture{
+	{Number: "SEED-F0001", Buyer: 0, Status: "delivered", DaysAgo: 20, Lines: []orderLineFixture{{"AOTHUN-NAM-L-DEN", 2}}},
+	{Number: "SEED-F0002", Buyer: 0, Status: "paid", DaysAgo: 1, Lines: []orderLineFixture{{"IP15PM-256-BLUE", 1}}},
+	{Number: "SEED-F0003", Buyer: 1, Status: "delivered", DaysAgo: 12, Lines: []orderLineFixture{{"GIAY-41-TRANG", 1}, {"DEP-NAM-001", 1}}},
+	{Number: "SEED-F0004", Buyer: 1, Status: "shipped", DaysAgo: 3, Lines: []orderLineFixture{{"SONY-WH1000XM5", 1}, {"XIAOMI-LED-001", 2}}},
+	{Number: "SEED-F0005", Buyer: 2, Status: "pending", DaysAgo: 0, Lines: []orderLineFixture{{"VAY-NU-001", 1}}},
+	{Number: "SEED-F0006", Buyer: 2, Status: "cancelled", DaysAgo: 7, Lines: []orderLineFixture{{"DELL-INS15-3520", 1}}},
+}
+
+var fakeOrderStatuses = []string{"pending", "paid", "confirmed", "shipped", "delivered", "delivered", "delivered", "cancelled"}
+
+// orderRow mirrors the order-service "shop_order" table
+type orderRow struct {
+	ID                  uint
+	OrderNumber         string
+	CheckoutID          string
+	UserID              uint
+	ShopID              uint
+	ShopName            string
+	ShopLogoURL         string
+	ShippingAddressID   uint
+	Status              string
+	MerchandiseSubtotal float64
+	ShippingFee         float64
+	ShippingDiscount    float64
+	VoucherDiscount     float64
+	FinalAmount         float64
+	PlatformFee         float64
+	EarningAmount       float64
+	PaymentMethod       string
+	PaidAt              *time.Time
+	OrderedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+func (orderRow) TableName() string {
+	return "shop_order"
+}
+
+// orderLineRow mirrors the order-service "order_line" table
+type orderLineRow struct {
+	ID              uint
+	OrderID         uint
+	ProductItemID   uint
+	ProductID       uint
+	Quantity        int
+	PriceAtPurchase float64
+	ProductName     string
+	SKUCode         string
+	ImageURL        string
+	FulfillmentType string
+	CreatedAt       time.Time
+}
+
+func (orderLineRow) TableName() string {
+	return "order_line"
+}
+
+// seedSKU is a SKU of a seed shop with the snapshot an order line needs
+type seedSKU struct {
+	ProductItemID uint
+	ProductID     uint
+	ShopID        uint
+	SKUCode       string
+	ProductName   string
+	ImageURL      string
+	Price         float64
+}
+
+type seedBuyer struct {
+	UserID    uint
+	AddressID uint
+}
+
+type orderSeedLine struct {
+	sku      seedSKU
+	quantity int
+}
+
+// seedOrders upserts the fixture orders (and -faker orders) by order number. Stock and
+// sold counts are not touched: seed orders are history, not checkouts
+func (s *seeder) seedOrders(ctx context.Context) error {
+	buyers, err := s.seedBuyers(ctx)
+	if err != nil {
+		return err
+	}
+	if len(buyers) < len(buyerFixtures) {
+		return fmt.Errorf("found %d seed buyers, run the users command first", len(buyers))
+	}
+
+	var shops []identityShop
+	err = s.identity.WithContext(ctx).
+		Joins(`JOIN "user" u ON u.id = shop.owner_user_id`).
+		Where("u.email LIKE ?", "%"+seedEmailDomain).
+		Find(&shops).Error
+	if err != nil {
+		return err
+	}
+	shopsByID := make(map[uint]identityShop, len(shops))
+	for _, shop := range shops {
+		shopsByID[shop.ID] = shop
+	}
+
+	skus, err := s.seedSKUs(ctx)
+	if err != nil {
+		return err
+	}
+	skusByCode := make(map[string]seedSKU, len(skus))
+	skusByShop := make(map[uint][]seedSKU)
+	for _, sku := range skus {
+		skusByCode[sku.SKUCode] = sku
+		skusByShop[sku.ShopID] = append(skusByShop[sku.ShopID], sku)
+	}
+
+	now := time.Now()
+	for _, f := range orderFixtures {
+		lines := make([]orderSeedLine, 0, len(f.Lines))
+		for _, l := range f.Lines {
+			sku, ok := skusByCode[l.SKU]
+			if !ok {
+				return fmt.Errorf("order %s: SKU %s not found, run the products command first", f.Number, l.SKU)
+			}
+			lines = append(lines, orderSeedLine{sku: sku, quantity: l.Quantity})
+		}
+		orderedAt := now.AddDate(0, 0, -f.DaysAgo)
+		if err := s.upsertOrder(ctx, f.Number, buyers[f.Buyer], shopsByID[lines[0].sku.ShopID], f.Status, orderedAt, lines); err != nil {
+			return err
+		}
+	}
+
+	generated := 0
+	if s.opts.faker {
+		for i := 1; i <= s.count("orders"); i++ {
+			first := skus[s.rnd.Intn(len(skus))]
+			shopSKUs := skusByShop[first.ShopID]
+			lines := []orderSeedLine{{sku: first, quantity: 1 + s.rnd.Intn(3)}}
+			if extra := shopSKUs[s.rnd.Intn(len(shopSKUs))]; extra.ProductItemID != first.ProductItemID && s.rnd.Intn(2) == 0 {
+				lines = append(lines, orderSeedLine{sku: extra, quantity: 1})
+			}
+
+			buyer := buyers[s.rnd.Intn(len(buyers))]
+			status := s.pick(fakeOrderStatuses)
+			orderedAt := now.Add(-time.Duration(s.rnd.Intn(90*24)) * time.Hour)
+			number := fmt.Sprintf("%sG%05d", seedOrderPrefix, i)
+			if err := s.upsertOrder(ctx, number, buyer, shopsByID[first.ShopID], status, orderedAt, lines); err != nil {
+				return err
+			}
+			generated++
+		}
+	}
+
+	log.Printf("Orders: %d fixtures, %d generated", len(orderFixtures), generated)
+	return nil
+}
+
+// seedBuyers returns the seed buyers with their HOME address, fixture buyers first
+func (s *seeder) seedBuyers(ctx context.Context) ([]seedBuyer, error) {
+	var buyers []seedBuyer
+	err := s.identity.WithContext(ctx).
+		Table("address a").
+		Select("u.id AS user_id, a.id AS address_id").
+		Joins(`JOIN "user" u ON u.id = a.user_id`).
+		Where("u.email LIKE ? AND u.role = 'BUYER' AND a.label = 'HOME'", "%"+seedEmailDomain).
+		Order("u.username").
+		Scan(&buyers).Error
+	return buyers, err
+}
+
+// seedSKUs returns the SKUs of the seed shops' products
+func (s *seeder) seedSKUs(ctx context.Context) ([]seedSKU, error) {
+	shopIDs, err := s.seedShopIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var skus []seedSKU
+	err = s.catalog.WithContext(ctx).
+		Table("product_item pi").
+		Select("pi.id AS product_item_id, pi.product_id, p.shop_id, pi.sku_code, p.name AS product_name, pi.image_url, pi.price").
+		Joins("JOIN products p ON p.id = pi.product_id").
+		Where("pi.deleted_at IS NULL AND p.shop_id IN ?", shopIDs).
+		Order("pi.id").
+		Scan(&skus).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(skus) == 0 {
+		return nil, fmt.Errorf("no SKUs of seed shops found, run the shops and products commands first")
+	}
+	return skus, nil
+}
+
+// upsertOrder writes the order header (matched by order number) and replaces its lines
+func (s *seeder) upsertOrder(ctx context.Context, number string, buyer seedBuyer, shop identityShop, status string, orderedAt time.Time, lines []orderSeedLine) error {
+	var subtotal float64
+	for _, l := range lines {
+		subtotal += l.sku.Price * float64(l.quantity)
+	}
+	platformFee := math.Round(subtotal * seedPlatformFeePercent / 100)
+	final := subtotal + seedShippingFee
+
+	var paidAt *time.Time
+	if status != "pending" && status != "cancelled" {
+		t := orderedAt.Add(10 * time.Minute)
+		paidAt = &t
+	}
+
+	order := &orderRow{
+		OrderNumber:         number,
+		CheckoutID:          "seed-" + number,
+		UserID:              buyer.UserID,
+		ShopID:              shop.ID,
+		ShopName:            shop.Name,
+		ShopLogoURL:         shop.LogoURL,
+		ShippingAddressID:   buyer.AddressID,
+		Status:              status,
+		MerchandiseSubtotal: subtotal,
+		ShippingFee:         seedShippingFee,
+		FinalAmount:         final,
+		PlatformFee:         platformFee,
+		EarningAmount:       final - platformFee,
+		PaymentMethod:       "COD",
+		PaidAt:              paidAt,
+		OrderedAt:           orderedAt,
+	}
+
+	return s.orders.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "order_number"}},
+			UpdateAll: true,
+		}).Create(order).Error
+		if err != nil {
+			return fmt.Errorf("order %s: %w", number, err)
+		}
+
+		if err := tx.Where("order_id = ?", order.ID).Delete(&orderLineRow{}).Error; err != nil {
+			return err
+		}
+		rows := make([]*orderLineRow, 0, len(lines))
+		for _, l := range lines {
+			rows = append(rows, &orderLineRow{
+				OrderID:         order.ID,
+				ProductItemID:   l.sku.ProductItemID,
+				ProductID:       l.sku.ProductID,
+				Quantity:        l.quantity,
+				PriceAtPurchase: l.sku.Price,
+				ProductName:     l.sku.ProductName,
+				SKUCode:         l.sku.SKUCode,
+				ImageURL:        l.sku.ImageURL,
+				FulfillmentType: "PHYSICAL",
+			})
+		}
+		return tx.Create(rows).Error
+	})
+}
+
+// resetOrders deletes the seed orders and their lines
+func (s *seeder) resetOrders(ctx context.Context) error {
+	return s.orders.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		orders := tx.Model(&orderRow{}).Select("id").Where("order_number LIKE ?", seedOrderPrefix+"%")
+		if err := tx.Where("order_id IN (?)", orders).Delete(&orderLineRow{}).Error; err != nil {
+			return err
+		}
+		result := tx.Where("order_number LIKE ?", seedOrderPrefix+"%").Delete(&orderRow{})
+		if result.Error != nil {
+			return result.Error
+		}
+		log.Printf("Deleted %d orders", result.RowsAffected)
+		return nil
+	})
+}
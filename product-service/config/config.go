@@ -12,12 +12,25 @@ import (
 // Config holds all configuration for the application
 // This is the single source of truth for configuration
 type Config struct {
-	Server        ServerConfig
-	Database      DatabaseConfig
-	Redis         RedisConfig
-	Kafka         KafkaConfig
-	Elasticsearch ElasticsearchConfig
-	Logging       LoggingConfig
+	Server          ServerConfig
+	Database        DatabaseConfig
+	Redis           RedisConfig
+	Kafka           KafkaConfig
+	Elasticsearch   ElasticsearchConfig
+	Logging         LoggingConfig
+	RateLimit       RateLimitConfig `mapstructure:"rate_limit"`
+	Quota           QuotaConfig     `mapstructure:"quota"`
+	Async           AsyncConfig
+	Outbox          OutboxConfig `mapstructure:"outbox"`
+	Reconcile       ReconcileConfig
+	OrderService    OrderServiceConfig    `mapstructure:"order_service"`
+	IdentityService IdentityServiceConfig `mapstructure:"identity_service"`
+	DigitalCodes    DigitalCodesConfig    `mapstructure:"digital_codes"`
+	Import          ImportConfig          `mapstructure:"import"`
+	Quality         QualityConfig         `mapstructure:"quality"`
+	InternalAuth    InternalAuthConfig    `mapstructure:"internal_auth"`
+	Sentry          SentryConfig          `mapstructure:"sentry"`
+	FaultInjection  FaultInjectionConfig  `mapstructure:"fault_injection"`
 }
 
 // ServerConfig holds HTTP server configuration
@@ -30,43 +43,184 @@ type ServerConfig struct {
 
 // DatabaseConfig holds PostgreSQL connection configuration
 type DatabaseConfig struct {
-	Host            string
-	Port            int
-	User            string
-	Password        string
-	DBName          string
-	SSLMode         string
-	MaxOpenConns    int
-	MaxIdleConns    int
-	ConnMaxLifetime time.Duration
+	Host     string
+	Port     int
+	User     string
+	Password string
+	DBName   string
+	SSLMode  string
+
+	// Connection pool (database/sql)
+	MaxOpenConns    int           `mapstructure:"max_open_conns"`
+	MaxIdleConns    int           `mapstructure:"max_idle_conns"`     // At most MaxOpenConns
+	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`  // Recycle connections (e.g. after a failover)
+	ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time"` // Close idle connections after a traffic peak
+
+	SlowQueryThreshold time.Duration `mapstructure:"slow_query_threshold"` // Statements slower than this are logged; 0 disables
 }
 
 // RedisConfig holds Redis connection configuration
 type RedisConfig struct {
-	Host         string
-	Port         int
-	Password     string
-	DB           int
-	PoolSize     int
-	MinIdleConns int
+	Host     string
+	Port     int
+	Password string
+	DB       int
+
+	// Connection pool and timeouts (go-redis)
+	PoolSize     int           `mapstructure:"pool_size"`
+	MinIdleConns int           `mapstructure:"min_idle_conns"` // At most PoolSize
+	DialTimeout  time.Duration `mapstructure:"dial_timeout"`
+	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
+	WriteTimeout time.Duration `mapstructure:"write_timeout"`
+	PoolTimeout  time.Duration `mapstructure:"pool_timeout"` // Wait for a free connection when the pool is exhausted
 }
 
 // KafkaConfig holds Kafka producer/consumer configuration
 type KafkaConfig struct {
-	Brokers            []string      `mapstructure:"brokers"`
-	TopicProductUpdated string       `mapstructure:"topic_product_updated"`
-	WriteTimeout       time.Duration `mapstructure:"write_timeout"`
-	ReadTimeout        time.Duration `mapstructure:"read_timeout"`
-	RequiredAcks       int           `mapstructure:"required_acks"`
+	Brokers      []string          `mapstructure:"brokers"`
+	TopicPrefix  string            `mapstructure:"topic_prefix"` // e.g. "staging." -> staging.product.created
+	Topics       map[string]string `mapstructure:"topics"`       // event type -> topic override
+	WriteTimeout time.Duration     `mapstructure:"write_timeout"`
+	ReadTimeout  time.Duration     `mapstructure:"read_timeout"`
+	RequiredAcks int               `mapstructure:"required_acks"`
+
+	// Publisher retry and Redis buffer (events that still fail are redelivered later)
+	MaxRetries    int           `mapstructure:"max_retries"`
+	RetryBackoff  time.Duration `mapstructure:"retry_backoff"`
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
 }
 
 // ElasticsearchConfig holds Elasticsearch connection configuration
 type ElasticsearchConfig struct {
-	Addresses  []string
-	Username   string
-	Password   string
-	IndexName  string
-	Timeout    time.Duration
+	Addresses []string
+	Username  string
+	Password  string
+	IndexName string `mapstructure:"index_name"`
+
+	// Transport
+	Timeout      time.Duration `mapstructure:"timeout"`       // Wait for the response headers of one request
+	DialTimeout  time.Duration `mapstructure:"dial_timeout"`  // Open a connection to a node
+	MaxRetries   int           `mapstructure:"max_retries"`   // Retries on network errors and 502/503/504; 0 disables
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"` // Doubled after every retry
+}
+
+// RateLimitConfig holds per-shop limits for write endpoints (product/SKU/stock)
+type RateLimitConfig struct {
+	Enabled                bool `mapstructure:"enabled"`
+	WriteRequestsPerMinute int  `mapstructure:"write_requests_per_minute"`
+}
+
+// QuotaConfig holds the hourly/daily quotas of seller write APIs per shop tier, counted
+// in Redis. Admins can change a tier's limits and a seller's tier at runtime
+type QuotaConfig struct {
+	Enabled     bool                       `mapstructure:"enabled"`
+	DefaultTier string                     `mapstructure:"default_tier"` // tier of sellers without an assignment
+	Tiers       map[string]QuotaTierConfig `mapstructure:"tiers"`
+}
+
+// QuotaTierConfig holds the limits of one tier
+type QuotaTierConfig struct {
+	ProductCreate QuotaLimitConfig `mapstructure:"product_create"`
+	StockSync     QuotaLimitConfig `mapstructure:"stock_sync"`
+}
+
+// QuotaLimitConfig caps an operation per hour and per day; 0 means unlimited
+type QuotaLimitConfig struct {
+	Hourly int64 `mapstructure:"hourly"`
+	Daily  int64 `mapstructure:"daily"`
+}
+
+// AsyncConfig sizes the worker pool for request side effects (cache, index, publish)
+type AsyncConfig struct {
+	Workers      int           `mapstructure:"workers"`
+	QueueSize    int           `mapstructure:"queue_size"`
+	MaxRetries   int           `mapstructure:"max_retries"`
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"`
+	TaskTimeout  time.Duration `mapstructure:"task_timeout"`
+}
+
+// OutboxConfig controls the relay of the product event outbox to Kafka
+type OutboxConfig struct {
+	PollInterval time.Duration `mapstructure:"poll_interval"` // wait between relays once the outbox is drained
+	BatchSize    int           `mapstructure:"batch_size"`    // events relayed per transaction
+	MaxBackoff   time.Duration `mapstructure:"max_backoff"`   // cap of the doubling delay after failed relays
+	Retention    time.Duration `mapstructure:"retention"`     // relayed events are deleted after this
+}
+
+// ReconcileConfig controls the nightly inventory reconciliation (Postgres vs Redis vs Elasticsearch)
+type ReconcileConfig struct {
+	Hour       int  `mapstructure:"hour"`        // local hour of the nightly run
+	HealSearch bool `mapstructure:"heal_search"` // re-index products whose search stock is wrong
+}
+
+// OrderServiceConfig holds Order Service client configuration
+type OrderServiceConfig struct {
+	BaseURL string        `mapstructure:"base_url"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// IdentityServiceConfig holds Identity Service client configuration (shop locations
+// copied onto search documents and product events)
+type IdentityServiceConfig struct {
+	BaseURL          string        `mapstructure:"base_url"`
+	Timeout          time.Duration `mapstructure:"timeout"`
+	LocationCacheTTL time.Duration `mapstructure:"location_cache_ttl"` // how long a shop location is reused
+}
+
+// InternalAuthConfig holds service-to-service authentication with signed service tokens
+type InternalAuthConfig struct {
+	Enabled         bool              `mapstructure:"enabled"`          // false = internal endpoints accept unsigned calls
+	ServiceName     string            `mapstructure:"service_name"`     // Name this service signs its outgoing calls with
+	Secret          string            `mapstructure:"secret"`           // Signing secret of this service
+	MaxAge          time.Duration     `mapstructure:"max_age"`          // Lifetime of accepted tokens
+	TrustedServices map[string]string `mapstructure:"trusted_services"` // Calling service -> its signing secret
+}
+
+// DigitalCodesConfig holds the encryption of digital product code pools
+type DigitalCodesConfig struct {
+	EncryptionKey string `mapstructure:"encryption_key"` // base64 32-byte AES key; empty disables digital code uploads and issuing
+}
+
+// ImportConfig holds the marketplace product import tool configuration
+type ImportConfig struct {
+	MappingFile  string        `mapstructure:"mapping_file"`  // JSON category/attribute mapping per source platform
+	MaxProducts  int           `mapstructure:"max_products"`  // max products per import
+	FetchTimeout time.Duration `mapstructure:"fetch_timeout"` // download timeout for export_url
+}
+
+// QualityConfig controls the periodic catalog quality check
+type QualityConfig struct {
+	Interval            time.Duration `mapstructure:"interval"`             // how often the check runs
+	PlaceholderPatterns []string      `mapstructure:"placeholder_patterns"` // image URL substrings that mark a placeholder
+	PriceLowRatio       float64       `mapstructure:"price_low_ratio"`      // below category median * ratio is an anomaly
+	PriceHighRatio      float64       `mapstructure:"price_high_ratio"`     // above category median * ratio is an anomaly
+	MinCategorySample   int           `mapstructure:"min_category_sample"`  // listed products needed for a category median
+}
+
+// SentryConfig holds the reporting of recovered panics to Sentry
+type SentryConfig struct {
+	DSN         string  `mapstructure:"dsn"`         // empty disables reporting (panics are only logged)
+	Environment string  `mapstructure:"environment"` // e.g. production, staging
+	Release     string  `mapstructure:"release"`     // deployed version, groups issues by release
+	SampleRate  float64 `mapstructure:"sample_rate"` // share of panics reported, 0-1
+}
+
+// FaultInjectionConfig holds the opt-in injection of latency and errors, used to check
+// timeouts, retries and circuit breakers of the callers. Refused in release mode
+type FaultInjectionConfig struct {
+	Enabled bool              `mapstructure:"enabled"`
+	Headers bool              `mapstructure:"headers"` // honor X-Fault-* request headers naming this service
+	Rules   []FaultRuleConfig `mapstructure:"rules"`
+}
+
+// FaultRuleConfig injects faults into the requests matching a path prefix (and method)
+type FaultRuleConfig struct {
+	PathPrefix  string        `mapstructure:"path_prefix"`
+	Method      string        `mapstructure:"method"`       // empty matches any method
+	Latency     time.Duration `mapstructure:"latency"`      // added before the request is handled
+	Jitter      time.Duration `mapstructure:"jitter"`       // random extra latency, up to this much
+	ErrorRate   float64       `mapstructure:"error_rate"`   // share of matching requests answered with ErrorStatus, 0-1
+	ErrorStatus int           `mapstructure:"error_status"` // default 503
 }
 
 // LoggingConfig holds logging configuration
@@ -107,6 +261,11 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	// Fail fast on settings that would only break under load (pools, timeouts)
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
 	return config, nil
 }
 
@@ -129,6 +288,8 @@ func setDefaults() {
 	viper.SetDefault("database.max_open_conns", 25)
 	viper.SetDefault("database.max_idle_conns", 5)
 	viper.SetDefault("database.conn_max_lifetime", "5m")
+	viper.SetDefault("database.conn_max_idle_time", "1m")
+	viper.SetDefault("database.slow_query_threshold", "200ms")
 
 	// Redis defaults
 	viper.SetDefault("redis.host", "localhost")
@@ -137,13 +298,20 @@ func setDefaults() {
 	viper.SetDefault("redis.db", 0)
 	viper.SetDefault("redis.pool_size", 10)
 	viper.SetDefault("redis.min_idle_conns", 5)
+	viper.SetDefault("redis.dial_timeout", "5s")
+	viper.SetDefault("redis.read_timeout", "3s")
+	viper.SetDefault("redis.write_timeout", "3s")
+	viper.SetDefault("redis.pool_timeout", "4s")
 
 	// Kafka defaults
 	viper.SetDefault("kafka.brokers", []string{"localhost:9092"})
-	viper.SetDefault("kafka.topic_product_updated", "product_updated")
+	viper.SetDefault("kafka.topic_prefix", "")
 	viper.SetDefault("kafka.write_timeout", "10s")
 	viper.SetDefault("kafka.read_timeout", "10s")
 	viper.SetDefault("kafka.required_acks", 1)
+	viper.SetDefault("kafka.max_retries", 3)
+	viper.SetDefault("kafka.retry_backoff", "200ms")
+	viper.SetDefault("kafka.flush_interval", "10s")
 
 	// Elasticsearch defaults
 	viper.SetDefault("elasticsearch.addresses", []string{"http://localhost:9200"})
@@ -151,6 +319,79 @@ func setDefaults() {
 	viper.SetDefault("elasticsearch.password", "")
 	viper.SetDefault("elasticsearch.index_name", "products")
 	viper.SetDefault("elasticsearch.timeout", "30s")
+	viper.SetDefault("elasticsearch.dial_timeout", "5s")
+	viper.SetDefault("elasticsearch.max_retries", 3)
+	viper.SetDefault("elasticsearch.retry_backoff", "100ms")
+
+	// Rate limit defaults
+	viper.SetDefault("rate_limit.enabled", true)
+	viper.SetDefault("rate_limit.write_requests_per_minute", 120)
+
+	// Seller quota defaults
+	viper.SetDefault("quota.enabled", true)
+	viper.SetDefault("quota.default_tier", "standard")
+	viper.SetDefault("quota.tiers", map[string]interface{}{
+		"standard": map[string]interface{}{
+			"product_create": map[string]interface{}{"hourly": 50, "daily": 200},
+			"stock_sync":     map[string]interface{}{"hourly": 500, "daily": 5000},
+		},
+		"premium": map[string]interface{}{
+			"product_create": map[string]interface{}{"hourly": 200, "daily": 1000},
+			"stock_sync":     map[string]interface{}{"hourly": 2000, "daily": 20000},
+		},
+	})
+
+	// Async side effect pool defaults
+	viper.SetDefault("async.workers", 8)
+	viper.SetDefault("async.queue_size", 1000)
+	viper.SetDefault("async.max_retries", 3)
+	viper.SetDefault("async.retry_backoff", "200ms")
+	viper.SetDefault("async.task_timeout", "10s")
+
+	// Event outbox relay defaults
+	viper.SetDefault("outbox.poll_interval", "1s")
+	viper.SetDefault("outbox.batch_size", 100)
+	viper.SetDefault("outbox.max_backoff", "30s")
+	viper.SetDefault("outbox.retention", "168h")
+
+	// Inventory reconciliation defaults
+	viper.SetDefault("reconcile.hour", 3)
+	viper.SetDefault("reconcile.heal_search", false)
+
+	// Order Service client defaults
+	viper.SetDefault("order_service.base_url", "http://localhost:8083")
+	viper.SetDefault("order_service.timeout", "5s")
+	viper.SetDefault("identity_service.base_url", "http://localhost:8081")
+	viper.SetDefault("identity_service.timeout", "5s")
+	viper.SetDefault("identity_service.location_cache_ttl", "10m")
+
+	viper.SetDefault("internal_auth.enabled", true)
+	viper.SetDefault("internal_auth.service_name", "product_service")
+	viper.SetDefault("internal_auth.max_age", "5m")
+
+	// Digital code pool defaults (set DIGITAL_CODES_ENCRYPTION_KEY in every environment)
+	viper.SetDefault("digital_codes.encryption_key", "")
+
+	// Product import tool defaults
+	viper.SetDefault("import.mapping_file", "./config/import_mapping.json")
+	viper.SetDefault("import.max_products", 1000)
+	viper.SetDefault("import.fetch_timeout", "30s")
+
+	// Catalog quality check defaults
+	viper.SetDefault("quality.interval", "24h")
+	viper.SetDefault("quality.placeholder_patterns", []string{"placeholder", "no-image", "noimage", "no_image", "default-product"})
+	viper.SetDefault("quality.price_low_ratio", 0.2)
+	viper.SetDefault("quality.price_high_ratio", 5.0)
+	viper.SetDefault("quality.min_category_sample", 5)
+
+	// Panic reporting defaults (set SENTRY_DSN to enable)
+	viper.SetDefault("sentry.dsn", "")
+	viper.SetDefault("sentry.environment", "development")
+	viper.SetDefault("sentry.sample_rate", 1.0)
+
+	// Fault injection is for resilience testing only
+	viper.SetDefault("fault_injection.enabled", false)
+	viper.SetDefault("fault_injection.headers", false)
 
 	// Logging defaults
 	viper.SetDefault("logging.level", "info")
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Banner placements on the storefront
+const (
+	PlacementHomeHero     = "HOME_HERO"     // Main carousel on homepage
+	PlacementHomeSidebar  = "HOME_SIDEBAR"  // Small banners next to the carousel
+	PlacementHomePopup    = "HOME_POPUP"    // Popup shown when opening homepage
+	PlacementCategoryTop  = "CATEGORY_TOP"  // Top of category pages
+	PlacementCheckoutSide = "CHECKOUT_SIDE" // Promotion banner on checkout page
+)
+
+// ValidPlacements lists all supported banner placements
+var ValidPlacements = []string{
+	PlacementHomeHero,
+	PlacementHomeSidebar,
+	PlacementHomePopup,
+	PlacementCategoryTop,
+	PlacementCheckoutSide,
+}
+
+// Campaign groups banners of one marketing event (e.g. "Sale 12.12")
+// Banners of an inactive/expired campaign are hidden even if the banner itself is active
+type Campaign struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	Name        string     `gorm:"size:150;not null" json:"name"`
+	Description string     `gorm:"type:text" json:"description"`
+	StartAt     *time.Time `json:"start_at,omitempty"` // Nil = start immediately
+	EndAt       *time.Time `json:"end_at,omitempty"`   // Nil = no end
+	IsActive    bool       `gorm:"default:true" json:"is_active"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Campaign) TableName() string {
+	return "campaigns"
+}
+
+// Banner represents a homepage/marketing banner
+type Banner struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	CampaignID *uint      `gorm:"index" json:"campaign_id,omitempty"`
+	Campaign   *Campaign  `gorm:"foreignKey:CampaignID" json:"campaign,omitempty"`
+	Title      string     `gorm:"size:200;not null" json:"title"`
+	ImageURL   string     `gorm:"column:image_url;size:500;not null" json:"image_url"`
+	LinkURL    string     `gorm:"column:link_url;size:500" json:"link_url"`
+	Placement  string     `gorm:"size:30;index;not null" json:"placement"` // HOME_HERO, HOME_SIDEBAR, ...
+	Priority   int        `gorm:"default:0" json:"priority"`               // Higher = shown first
+	StartAt    *time.Time `json:"start_at,omitempty"`                      // Nil = start immediately
+	EndAt      *time.Time `json:"end_at,omitempty"`                        // Nil = no end
+	IsActive   bool       `gorm:"default:true" json:"is_active"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Banner) TableName() string {
+	return "banners"
+}
+
+// CampaignRepository defines the interface for campaign data access
+type CampaignRepository interface {
+	Create(ctx context.Context, campaign *Campaign) error
+	Update(ctx context.Context, campaign *Campaign) error
+	GetByID(ctx context.Context, id uint) (*Campaign, error)
+	GetAll(ctx context.Context) ([]*Campaign, error)
+	GetActive(ctx context.Context, now time.Time) ([]*Campaign, error)
+	Delete(ctx context.Context, id uint) error
+}
+
+// BannerRepository defines the interface for banner data access
+type BannerRepository interface {
+	Create(ctx context.Context, banner *Banner) error
+	Update(ctx context.Context, banner *Banner) error
+	GetByID(ctx context.Context, id uint) (*Banner, error)
+	List(ctx context.Context, placement string, campaignID *uint) ([]*Banner, error)
+	GetActive(ctx context.Context, now time.Time) ([]*Banner, error) // Active by schedule and campaign, ordered by priority
+	Delete(ctx context.Context, id uint) error
+}
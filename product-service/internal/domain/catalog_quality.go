@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Issue kinds found by the catalog quality checker
+const (
+	QualityPlaceholderImage = "PLACEHOLDER_IMAGE" // product or SKU has no image or a placeholder image
+	QualityMissingAttribute = "MISSING_ATTRIBUTE" // mandatory attribute of the category has no value
+	QualityZeroStockActive  = "ZERO_STOCK_ACTIVE" // ACTIVE SKU with qty_in_stock = 0
+	QualityPriceAnomaly     = "PRICE_ANOMALY"     // base price far from the category median
+)
+
+// CatalogQualityIssue is one problem of a listed product found by a quality run
+// (all rows of a run share RunID)
+type CatalogQualityIssue struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	RunID         string    `gorm:"size:64;index:idx_catalog_quality_run_shop,priority:1;not null" json:"run_id"`
+	ShopID        uint      `gorm:"index:idx_catalog_quality_run_shop,priority:2;not null" json:"shop_id"`
+	Kind          string    `gorm:"size:32;index;not null" json:"kind"`
+	ProductID     uint      `gorm:"index;not null" json:"product_id"`
+	ProductItemID *uint     `json:"product_item_id,omitempty"` // nil for product-level checks
+	Detail        string    `gorm:"size:255" json:"detail"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (CatalogQualityIssue) TableName() string {
+	return "catalog_quality_issues"
+}
+
+// ShopQualityScore is the quality score of one shop in a quality run
+// Score is the share of the shop's listed products without issues (0-100)
+type ShopQualityScore struct {
+	ID                 uint      `gorm:"primaryKey" json:"-"`
+	RunID              string    `gorm:"size:64;uniqueIndex:idx_shop_quality_run_shop,priority:1;not null" json:"run_id"`
+	ShopID             uint      `gorm:"uniqueIndex:idx_shop_quality_run_shop,priority:2;not null" json:"shop_id"`
+	Products           int       `json:"products"`
+	ProductsWithIssues int       `json:"products_with_issues"`
+	Issues             int       `json:"issues"`
+	Score              float64   `gorm:"type:decimal(5,2);index" json:"score"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ShopQualityScore) TableName() string {
+	return "shop_quality_scores"
+}
+
+// CatalogQualityRepository defines the interface for catalog quality results
+type CatalogQualityRepository interface {
+	CreateIssues(ctx context.Context, issues []*CatalogQualityIssue) error
+	CreateScores(ctx context.Context, scores []*ShopQualityScore) error
+	// ListIssues returns issues of a run; shopID 0 and kind "" match all
+	ListIssues(ctx context.Context, runID string, shopID uint, kind string, limit int) ([]*CatalogQualityIssue, error)
+	// ListScores returns a page of shop scores of a run, lowest score first
+	ListScores(ctx context.Context, runID string, page, limit int) ([]*ShopQualityScore, int64, error)
+	GetScore(ctx context.Context, runID string, shopID uint) (*ShopQualityScore, error)
+	DeleteRunsBefore(ctx context.Context, before time.Time) error // Drop results of old runs
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"time"
 )
 
@@ -30,11 +31,12 @@ func (Category) TableName() string {
 // CategoryRepository defines the interface for category data access
 // This is part of the domain layer - it defines WHAT we need, not HOW
 type CategoryRepository interface {
-	Create(category *Category) error
-	Update(category *Category) error
-	GetByID(id uint) (*Category, error)
-	GetBySlug(slug string) (*Category, error)
-	GetAll() ([]*Category, error)
-	GetChildren(parentID uint) ([]*Category, error)
-	Delete(id uint) error
+	Create(ctx context.Context, category *Category) error
+	Update(ctx context.Context, category *Category) error
+	GetByID(ctx context.Context, id uint) (*Category, error)
+	GetByIDs(ctx context.Context, ids []uint) ([]*Category, error)
+	GetBySlug(ctx context.Context, slug string) (*Category, error)
+	GetAll(ctx context.Context) ([]*Category, error)
+	GetChildren(ctx context.Context, parentID uint) ([]*Category, error)
+	Delete(ctx context.Context, id uint) error
 }
@@ -1,5 +1,7 @@
 package domain
 
+import "context"
+
 // CategoryAttribute defines an attribute that products in a category must/can have
 // Example: Category "Điện thoại" has attributes: "RAM", "Màn hình", "Pin"
 // Following db-diagram.db schema (SOURCE OF TRUTH)
@@ -19,11 +21,11 @@ func (CategoryAttribute) TableName() string {
 
 // CategoryAttributeRepository defines the interface for category attribute data access
 type CategoryAttributeRepository interface {
-	Create(attr *CategoryAttribute) error
-	Update(attr *CategoryAttribute) error
-	GetByID(id uint) (*CategoryAttribute, error)
-	GetByCategoryID(categoryID uint) ([]*CategoryAttribute, error)
-	GetFilterablesByCategoryID(categoryID uint) ([]*CategoryAttribute, error) // Chỉ lấy attributes có thể filter
-	Delete(id uint) error
+	Create(ctx context.Context, attr *CategoryAttribute) error
+	Update(ctx context.Context, attr *CategoryAttribute) error
+	GetByID(ctx context.Context, id uint) (*CategoryAttribute, error)
+	GetByCategoryID(ctx context.Context, categoryID uint) ([]*CategoryAttribute, error)
+	GetFilterablesByCategoryID(ctx context.Context, categoryID uint) ([]*CategoryAttribute, error) // Chỉ lấy attributes có thể filter
+	Delete(ctx context.Context, id uint) error
 }
 
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Digital code statuses
+const (
+	DigitalCodeStatusAvailable = "AVAILABLE"
+	DigitalCodeStatusIssued    = "ISSUED"
+)
+
+// ErrInsufficientDigitalCodes is returned when a SKU's code pool cannot cover an order
+var ErrInsufficientDigitalCodes = Conflict("not enough digital codes available")
+
+// DigitalCode is one code of a DIGITAL SKU's code pool (voucher, game key, e-gift card)
+// Sellers upload the pool; each paid order line is fulfilled with codes from it
+// The code is stored encrypted; CodeHash (keyed HMAC) rejects duplicate uploads without decrypting
+type DigitalCode struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	ProductItemID uint       `gorm:"not null;index:idx_digital_code_item_status,priority:1" json:"product_item_id"`
+	CodeEncrypted string     `gorm:"type:text;not null" json:"-"`
+	CodeHash      string     `gorm:"size:64;uniqueIndex;not null" json:"-"`
+	Status        string     `gorm:"size:20;not null;default:'AVAILABLE';index:idx_digital_code_item_status,priority:2" json:"status"` // AVAILABLE, ISSUED
+	OrderID       string     `gorm:"size:50;index" json:"order_id,omitempty"`                                                          // Order number the code was issued to
+	IssuedAt      *time.Time `json:"issued_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (DigitalCode) TableName() string {
+	return "digital_code"
+}
+
+// DigitalCodeStats counts the code pool of a SKU
+type DigitalCodeStats struct {
+	ProductItemID uint  `json:"product_item_id"`
+	Available     int64 `json:"available"`
+	Issued        int64 `json:"issued"`
+}
+
+// DigitalCodeRepository defines the interface for digital code pool data access
+type DigitalCodeRepository interface {
+	// CreateBatch inserts codes, skipping those whose hash already exists; returns how many were inserted
+	CreateBatch(ctx context.Context, codes []*DigitalCode) (int, error)
+	CountByStatus(ctx context.Context, productItemID uint) (*DigitalCodeStats, error)
+	ListByOrder(ctx context.Context, orderID string) ([]*DigitalCode, error)
+
+	// IssueForOrder makes sure the order holds quantities[product_item_id] codes of each SKU,
+	// issuing only what it does not hold yet (safe to retry), all or nothing;
+	// ErrInsufficientDigitalCodes if a pool is too small. Returns every code issued to the order
+	IssueForOrder(ctx context.Context, orderID string, quantities map[uint]int) ([]*DigitalCode, error)
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Kinds of business rule violations. Services return errors of one of these kinds
+// (see NotFound, Conflict, Validation, Forbidden) and handlers map the kind to the
+// HTTP status with errors.Is: 404, 409, 400 and 403. Any other error is a failure
+// of the service itself (database, broker...) and answered with 500
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrValidation = errors.New("validation failed")
+	ErrForbidden  = errors.New("forbidden")
+)
+
+// Error is a business rule violation: Message is returned to the client, Kind selects the status
+type Error struct {
+	Kind    error
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap lets errors.Is match the kind
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// NotFound returns an ErrNotFound error: the resource does not exist (404)
+func NotFound(format string, args ...any) error {
+	return &Error{Kind: ErrNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+// Conflict returns an ErrConflict error: the request clashes with the current state (409)
+func Conflict(format string, args ...any) error {
+	return &Error{Kind: ErrConflict, Message: fmt.Sprintf(format, args...)}
+}
+
+// Validation returns an ErrValidation error: the request itself is invalid (400)
+func Validation(format string, args ...any) error {
+	return &Error{Kind: ErrValidation, Message: fmt.Sprintf(format, args...)}
+}
+
+// Forbidden returns an ErrForbidden error: the caller may not do this (403)
+func Forbidden(format string, args ...any) error {
+	return &Error{Kind: ErrForbidden, Message: fmt.Sprintf(format, args...)}
+}
+
+// ErrVersionConflict is returned when an update is based on a stale version of a
+// row (optimistic locking) - another request changed it first
+var ErrVersionConflict = Conflict("resource was modified by another request, reload and retry")
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"encoding/json"
 	"time"
 )
@@ -24,7 +25,31 @@ func (e *ProductEvent) ToJSON() ([]byte, error) {
 // EventPublisher defines the interface for publishing domain events
 // This abstraction allows us to swap Kafka for other message brokers if needed
 type EventPublisher interface {
-	PublishProductEvent(event *ProductEvent) error
+	PublishProductEvent(ctx context.Context, event *ProductEvent) error
 	Close() error // Close releases resources (e.g., Kafka connections)
 }
 
+// BufferedEventPublisher is an EventPublisher that parks events it cannot deliver
+// (after retries) in a durable buffer and redelivers them later (at-least-once)
+type BufferedEventPublisher interface {
+	EventPublisher
+	Start(ctx context.Context)                     // Flushes the buffer periodically until ctx is canceled
+	Flush(ctx context.Context) (int, error)        // Redelivers buffered events now, returns how many were sent
+	Stats(ctx context.Context) EventPublisherStats // Delivery counters and buffer lag
+}
+
+// EventPublisherStats is a snapshot of publisher counters
+type EventPublisherStats struct {
+	Published    int64      `json:"published"`     // Events written to Kafka (directly or from the buffer)
+	Retried      int64      `json:"retried"`       // Write attempts that were retried
+	Buffered     int64      `json:"buffered"`      // Events parked in the buffer
+	Flushed      int64      `json:"flushed"`       // Buffered events delivered later
+	Failed       int64      `json:"failed"`        // Events that could neither be written nor buffered
+	BufferLength int64      `json:"buffer_length"` // Events waiting in the buffer
+	LagSeconds   float64    `json:"lag_seconds"`   // Age of the oldest buffered event
+	LastError    string     `json:"last_error,omitempty"`
+	LastErrorAt  *time.Time `json:"last_error_at,omitempty"`
+
+	Outbox *OutboxStats `json:"outbox,omitempty"` // Set by the admin endpoint, not by the publisher
+}
+
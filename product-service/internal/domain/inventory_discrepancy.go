@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Discrepancy kinds found by the inventory reconciler
+const (
+	DiscrepancyReservedDrift = "RESERVED_DRIFT"        // Redis reserved counter != sum of live reservations
+	DiscrepancyOverReserved  = "OVER_RESERVED"         // reserved units exceed qty_in_stock
+	DiscrepancySearchStock   = "SEARCH_STOCK_MISMATCH" // ES total_stock != sum of SKU qty_in_stock
+	DiscrepancySearchMissing = "SEARCH_MISSING"        // product document (or its total_stock) is missing from the search index
+)
+
+// InventoryDiscrepancy is one mismatch between Postgres, Redis and Elasticsearch
+// found by a reconciliation run (all rows of a run share RunID)
+type InventoryDiscrepancy struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	RunID         string    `gorm:"size:64;index;not null" json:"run_id"`
+	Kind          string    `gorm:"size:32;index;not null" json:"kind"`
+	ProductID     uint      `gorm:"index;not null" json:"product_id"` // 0 for Redis keys of SKUs no longer in Postgres
+	ProductItemID *uint     `json:"product_item_id,omitempty"`        // nil for product-level (search) checks
+	PostgresQty   int       `json:"postgres_qty"`
+	RedisReserved *int      `json:"redis_reserved,omitempty"`
+	ExpectedQty   *int      `json:"expected_qty,omitempty"` // reserved units per live reservations
+	SearchQty     *int      `json:"search_qty,omitempty"`
+	Healed        bool      `gorm:"default:false" json:"healed"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (InventoryDiscrepancy) TableName() string {
+	return "inventory_discrepancies"
+}
+
+// InventoryDiscrepancyRepository defines the interface for reconciliation results
+type InventoryDiscrepancyRepository interface {
+	CreateBatch(ctx context.Context, discrepancies []*InventoryDiscrepancy) error
+	ListByRun(ctx context.Context, runID string, limit int) ([]*InventoryDiscrepancy, error)
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// OutboxEvent is a product event waiting to be relayed to Kafka (transactional outbox)
+// It is written in the transaction that changes the product, so a committed change always
+// gets its event, even if the process dies right after the commit. Events are relayed in
+// ID order; the product row is written before its event row, so the events of one product
+// are numbered in commit order
+type OutboxEvent struct {
+	ID          uint64         `gorm:"primaryKey;index:idx_product_outbox_pending,where:published_at IS NULL" json:"id"`
+	AggregateID uint           `gorm:"column:aggregate_id;not null" json:"aggregate_id"` // Product ID (Kafka key)
+	EventType   string         `gorm:"column:event_type;size:50;not null" json:"event_type"`
+	Payload     datatypes.JSON `gorm:"type:jsonb;not null" json:"payload"` // ProductEvent JSON
+	Attempts    int            `gorm:"not null;default:0" json:"attempts"` // Failed relay attempts
+	LastError   string         `gorm:"column:last_error;type:text" json:"last_error,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	PublishedAt *time.Time     `gorm:"column:published_at;index" json:"published_at,omitempty"` // Nil until relayed
+}
+
+// TableName specifies the table name for GORM
+func (OutboxEvent) TableName() string {
+	return "product_outbox"
+}
+
+// NewOutboxEvent serializes a product event for the outbox
+func NewOutboxEvent(event *ProductEvent) (*OutboxEvent, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+	return &OutboxEvent{
+		AggregateID: event.ProductID,
+		EventType:   event.EventType,
+		Payload:     datatypes.JSON(payload),
+	}, nil
+}
+
+// ProductEvent deserializes the relayed event
+func (e *OutboxEvent) ProductEvent() (*ProductEvent, error) {
+	var event ProductEvent
+	if err := json.Unmarshal(e.Payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal outbox event %d: %w", e.ID, err)
+	}
+	return &event, nil
+}
+
+// OutboxStats is a snapshot of the outbox backlog and relay counters
+type OutboxStats struct {
+	Pending      int64      `json:"pending"`       // Events not relayed yet
+	LagSeconds   float64    `json:"lag_seconds"`   // Age of the oldest pending event
+	Relayed      int64      `json:"relayed"`       // Events relayed by this instance
+	Failed       int64      `json:"failed"`        // Relay attempts that failed (the event is retried)
+	HeadAttempts int        `json:"head_attempts"` // Failed attempts of the oldest pending event
+	LastError    string     `json:"last_error,omitempty"`
+	LastErrorAt  *time.Time `json:"last_error_at,omitempty"`
+}
+
+// OutboxRepository stores outbox events and relays them in order
+type OutboxRepository interface {
+	// Add writes an event; it joins the transaction of ctx (see Transactor)
+	Add(ctx context.Context, event *OutboxEvent) error
+	// Relay passes up to limit pending events to publish, oldest first, and marks them
+	// published. It stops at the first event publish fails on, recording the failure, and
+	// returns that error. Only one relay runs at a time across instances; the others relay
+	// nothing. Returns how many events were published
+	Relay(ctx context.Context, limit int, publish func(ctx context.Context, event *OutboxEvent) error) (int, error)
+	// DeletePublishedBefore removes the events relayed before t
+	DeletePublishedBefore(ctx context.Context, t time.Time) (int64, error)
+	// Backlog returns the pending events count and the oldest pending event (nil when none)
+	Backlog(ctx context.Context) (int64, *OutboxEvent, error)
+}
+
+// Transactor runs fn in a database transaction. Repositories called with the ctx passed
+// to fn join the transaction; a nested WithinTx joins the outer one
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
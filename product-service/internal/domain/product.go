@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"time"
 
 	"gorm.io/datatypes"
@@ -13,18 +14,89 @@ import (
 // IMPORTANT: Price/Stock/SKU nằm ở ProductItem (SKU), không nằm ở Product
 type Product struct {
 	ID          uint           `gorm:"primaryKey" json:"id"`
-	ShopID      uint           `gorm:"index;not null" json:"shop_id"` // Product thuộc shop (theo db-diagram.db)
+	ShopID      uint           `gorm:"index;not null;index:idx_products_shop_status,priority:1" json:"shop_id"` // Product thuộc shop (theo db-diagram.db)
 	Name        string         `gorm:"not null" json:"name"`
+	Slug        string         `gorm:"size:255;index" json:"slug"` // URL slug, auto-generated from Name (unique per product)
 	Description string         `json:"description"`
-	BasePrice   float64        `gorm:"column:base_price;type:decimal(15,2);not null" json:"base_price"` // Giá gốc - giá tham chiếu
-	CategoryID  *uint          `gorm:"index" json:"category_id,omitempty"`                              // Foreign key to categories (chỉ leaf category)
+	BasePrice   float64        `gorm:"column:base_price;type:decimal(15,2);not null" json:"base_price"`                         // Giá gốc - giá tham chiếu
+	CategoryID  *uint          `gorm:"index;index:idx_products_category_status_active,priority:1" json:"category_id,omitempty"` // Foreign key to categories (chỉ leaf category)
 	Category    *Category      `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
-	Status      string         `gorm:"default:'ACTIVE'" json:"status"`                // ACTIVE, INACTIVE
-	Images      datatypes.JSON `gorm:"type:jsonb" json:"images"`                      // JSON array of image URLs
-	IsActive    bool           `gorm:"default:true" json:"is_active"`                 // Boolean theo db-diagram.db
-	SoldCount   int            `gorm:"column:sold_count;default:0" json:"sold_count"` // Số lượng đã bán (theo db-diagram.db)
+	Status      string         `gorm:"default:'ACTIVE';index:idx_products_category_status_active,priority:2;index:idx_products_shop_status,priority:2" json:"status"` // ACTIVE, INACTIVE
+	Images      datatypes.JSON `gorm:"type:jsonb" json:"images"`                                                                                                      // JSON array of image URLs
+	IsActive    bool           `gorm:"default:true;index:idx_products_category_status_active,priority:3" json:"is_active"`                                            // Boolean theo db-diagram.db
+	SoldCount   int            `gorm:"column:sold_count;default:0" json:"sold_count"`                                                                                 // Số lượng đã bán (theo db-diagram.db)
+	Version     int            `gorm:"not null;default:1" json:"version"`                                                                                             // Optimistic lock, bumped on every update
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
+	Items       []*ProductItem `gorm:"foreignKey:ProductID" json:"items,omitempty"` // SKUs, only loaded with WithItems()
+
+	// Max units one customer may have in cart/order across all SKUs (0 = no limit), e.g. flash sale
+	MaxPurchaseQuantity int `gorm:"column:max_purchase_quantity;not null;default:0" json:"max_purchase_quantity"`
+
+	// PHYSICAL products are shipped; DIGITAL products are fulfilled with codes from the SKU's code pool
+	ProductType string `gorm:"column:product_type;size:20;not null;default:'PHYSICAL'" json:"product_type"`
+
+	// Rating snapshot of published reviews, maintained by the review service (never by sellers)
+	RatingAvg   float64 `gorm:"column:rating_avg;type:decimal(3,2);not null;default:0" json:"rating_avg"`
+	RatingCount int     `gorm:"column:rating_count;not null;default:0" json:"rating_count"`
+
+	// Ships-from location of the shop (owned by identity-service), only set on search
+	// documents and product events
+	ShipsFromProvince string    `gorm:"-" json:"ships_from_province,omitempty"`
+	ShopLocation      *GeoPoint `gorm:"-" json:"shop_location,omitempty"`
+}
+
+// Product statuses (ARCHIVED products are hidden everywhere except the admin search)
+const (
+	ProductStatusActive   = "ACTIVE"
+	ProductStatusInactive = "INACTIVE"
+	ProductStatusArchived = "ARCHIVED"
+)
+
+// Product types
+const (
+	ProductTypePhysical = "PHYSICAL"
+	ProductTypeDigital  = "DIGITAL"
+)
+
+// ProductListOptions controls how much data product listing queries load
+type ProductListOptions struct {
+	ListingColumns  bool // Only columns needed by product cards (no description, first image only)
+	PreloadCategory bool
+	PreloadItems    bool
+	SkipCount       bool // Total already known (cached): the COUNT(*) query is not run and 0 is returned
+}
+
+// ProductListOption configures a listing query
+type ProductListOption func(*ProductListOptions)
+
+// WithListingColumns selects only listing columns instead of full rows
+func WithListingColumns() ProductListOption {
+	return func(o *ProductListOptions) { o.ListingColumns = true }
+}
+
+// WithCategory preloads the product category
+func WithCategory() ProductListOption {
+	return func(o *ProductListOptions) { o.PreloadCategory = true }
+}
+
+// WithItems preloads the product SKUs
+func WithItems() ProductListOption {
+	return func(o *ProductListOptions) { o.PreloadItems = true }
+}
+
+// WithoutCount skips the COUNT(*) of a paginated listing
+func WithoutCount() ProductListOption {
+	return func(o *ProductListOptions) { o.SkipCount = true }
+}
+
+// NewProductListOptions applies options over the defaults (full rows, no preloads)
+func NewProductListOptions(opts ...ProductListOption) ProductListOptions {
+	var o ProductListOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
 }
 
 // TableName specifies the table name for GORM
@@ -36,21 +108,43 @@ func (Product) TableName() string {
 // This is part of the domain layer - it defines WHAT we need, not HOW
 // The implementation will be in the repository layer (infrastructure)
 type ProductRepository interface {
-	Create(product *Product) error
-	Update(product *Product) error
-	GetByID(id uint) (*Product, error)
-	GetAll() ([]*Product, error)
-	ListProducts(filters map[string]interface{}, page, limit int) ([]*Product, int64, error)
-	GetProductsByCategory(categoryID uint, page, limit int) ([]*Product, int64, error)
-	GetProductsByCategoryIDs(categoryIDs []uint, page, limit int) ([]*Product, int64, error)
-	GetProductsByShopID(shopID uint, page, limit int) ([]*Product, int64, error) // THÊM MỚI - Get products by shop
-	Delete(id uint) error
+	Create(ctx context.Context, product *Product) error
+	Update(ctx context.Context, product *Product) error // Fails with ErrVersionConflict if product.Version is stale
+	GetByID(ctx context.Context, id uint) (*Product, error)
+	GetByIDs(ctx context.Context, ids []uint) ([]*Product, error) // Missing IDs are skipped, order not guaranteed
+	GetBySlug(ctx context.Context, slug string) (*Product, error)
+	ExistsBySlug(ctx context.Context, slug string, excludeID uint) (bool, error) // excludeID = 0 checks all products
+	GetAll(ctx context.Context) ([]*Product, error)
+	ListAfterID(ctx context.Context, afterID uint, limit int) ([]*Product, error) // Keyset pagination by ID (for full scans, e.g. reindex)
+	Count(ctx context.Context) (int64, error)
+	ListProducts(ctx context.Context, filters map[string]interface{}, page, limit int, opts ...ProductListOption) ([]*Product, int64, error)
+	GetProductsByCategory(ctx context.Context, categoryID uint, page, limit int, opts ...ProductListOption) ([]*Product, int64, error)
+	GetProductsByCategoryIDs(ctx context.Context, categoryIDs []uint, page, limit int, opts ...ProductListOption) ([]*Product, int64, error)
+	GetProductsByShopID(ctx context.Context, shopID uint, page, limit int, opts ...ProductListOption) ([]*Product, int64, error) // THÊM MỚI - Get products by shop
+	ShopIDsByCategoryIDs(ctx context.Context, categoryIDs []uint) ([]uint, error)                                                // Shops with an ACTIVE product in the categories
+	Delete(ctx context.Context, id uint) error
+	UpdateRating(ctx context.Context, id uint, avg float64, count int) error // Rating snapshot only, does not bump Version
+}
+
+// AdminProductSearch is an admin search across all shops and statuses
+type AdminProductSearch struct {
+	Query      string   // Matches product name or SKU code
+	SKU        string   // Exact SKU code
+	ShopID     uint     // 0 = all shops
+	CategoryID uint     // 0 = all categories
+	Statuses   []string // Empty = any status, including INACTIVE and ARCHIVED
+	Page       int
+	Limit      int
 }
 
 // ProductSearchRepository defines the interface for product search operations
 // Separated from ProductRepository to follow Interface Segregation Principle
 type ProductSearchRepository interface {
-	IndexProduct(product *Product) error
-	SearchProducts(query string, filters map[string]interface{}) ([]*Product, error)
-	DeleteFromIndex(id uint) error
+	IndexProduct(ctx context.Context, product *Product) error
+	SearchProducts(ctx context.Context, query string, filters map[string]interface{}) ([]*Product, error)
+	DeleteFromIndex(ctx context.Context, id uint) error
+	// AdminSearch returns the IDs of matching products (best match first) and the total
+	AdminSearch(ctx context.Context, search *AdminProductSearch) ([]uint, int64, error)
+	// UpdateSalesRanks sets the bestseller rank (1 = best) of indexed products; 0 clears it
+	UpdateSalesRanks(ctx context.Context, ranks map[uint]int) error
 }
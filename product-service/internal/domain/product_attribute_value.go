@@ -1,5 +1,7 @@
 package domain
 
+import "context"
+
 // ProductAttributeValue stores the value of an attribute for a specific product
 // Example: Product iPhone 15 has RAM = "8GB", Màn hình = "6.1 inch"
 // Following db-diagram.db schema (SOURCE OF TRUTH)
@@ -18,14 +20,15 @@ func (ProductAttributeValue) TableName() string {
 
 // ProductAttributeValueRepository defines the interface for product attribute value data access
 type ProductAttributeValueRepository interface {
-	Create(value *ProductAttributeValue) error
-	CreateBatch(values []*ProductAttributeValue) error // Bulk insert
-	Update(value *ProductAttributeValue) error
-	GetByID(id uint) (*ProductAttributeValue, error)
-	GetByProductID(productID uint) ([]*ProductAttributeValue, error)
-	GetByAttributeID(attributeID uint) ([]*ProductAttributeValue, error)
-	SearchByAttributeValue(attributeID uint, value string) ([]*ProductAttributeValue, error) // Search products by attribute
-	Delete(id uint) error
-	DeleteByProductID(productID uint) error // Delete all attributes for a product
+	Create(ctx context.Context, value *ProductAttributeValue) error
+	CreateBatch(ctx context.Context, values []*ProductAttributeValue) error // Bulk insert
+	Update(ctx context.Context, value *ProductAttributeValue) error
+	GetByID(ctx context.Context, id uint) (*ProductAttributeValue, error)
+	GetByProductID(ctx context.Context, productID uint) ([]*ProductAttributeValue, error)
+	GetByProductIDs(ctx context.Context, productIDs []uint) ([]*ProductAttributeValue, error) // Batch fetch for many products
+	GetByAttributeID(ctx context.Context, attributeID uint) ([]*ProductAttributeValue, error)
+	SearchByAttributeValue(ctx context.Context, attributeID uint, value string) ([]*ProductAttributeValue, error) // Search products by attribute
+	Delete(ctx context.Context, id uint) error
+	DeleteByProductID(ctx context.Context, productID uint) error // Delete all attributes for a product
 }
 
@@ -0,0 +1,101 @@
+package domain
+
+import "time"
+
+// ProductDocument is the denormalized search document for a product
+// Built from Postgres (product + SKUs + attribute values) by the catalog reindex
+type ProductDocument struct {
+	ID           uint                  `json:"id"`
+	ShopID       uint                  `json:"shop_id"`
+	Name         string                `json:"name"`
+	Slug         string                `json:"slug"`
+	Description  string                `json:"description"`
+	BasePrice    float64               `json:"base_price"`
+	MinPrice     float64               `json:"min_price"` // Lowest active SKU price (BasePrice if no SKU)
+	MaxPrice     float64               `json:"max_price"`
+	TotalStock   int                   `json:"total_stock"`
+	CategoryID   *uint                 `json:"category_id,omitempty"`
+	CategoryName string                `json:"category,omitempty"`
+	Status       string                `json:"status"`
+	IsActive     bool                  `json:"is_active"`
+	SoldCount    int                   `json:"sold_count"`
+	RatingAvg    float64               `json:"rating_avg"`
+	RatingCount  int                   `json:"rating_count"`
+	Items        []ProductDocumentItem `json:"items"`
+	Attributes   []ProductDocumentAttr `json:"attributes"`
+	CreatedAt    time.Time             `json:"created_at"`
+	UpdatedAt    time.Time             `json:"updated_at"`
+}
+
+// ProductDocumentItem is a SKU inside a ProductDocument
+type ProductDocumentItem struct {
+	ID         uint    `json:"id"`
+	SKUCode    string  `json:"sku_code"`
+	Price      float64 `json:"price"`
+	QtyInStock int     `json:"qty_in_stock"`
+	Status     string  `json:"status"`
+}
+
+// ProductDocumentAttr is an attribute value inside a ProductDocument
+type ProductDocumentAttr struct {
+	AttributeID uint   `json:"attribute_id"`
+	Name        string `json:"name"`
+	Value       string `json:"value"`
+}
+
+// NewProductDocument builds a search document from a product and its SKUs and attributes
+// attrNames maps attribute_id -> attribute name (unknown IDs are indexed without a name)
+func NewProductDocument(p *Product, items []*ProductItem, attrs []*ProductAttributeValue, attrNames map[uint]string) *ProductDocument {
+	doc := &ProductDocument{
+		ID:          p.ID,
+		ShopID:      p.ShopID,
+		Name:        p.Name,
+		Slug:        p.Slug,
+		Description: p.Description,
+		BasePrice:   p.BasePrice,
+		MinPrice:    p.BasePrice,
+		MaxPrice:    p.BasePrice,
+		CategoryID:  p.CategoryID,
+		Status:      p.Status,
+		IsActive:    p.IsActive,
+		SoldCount:   p.SoldCount,
+		RatingAvg:   p.RatingAvg,
+		RatingCount: p.RatingCount,
+		Items:       make([]ProductDocumentItem, 0, len(items)),
+		Attributes:  make([]ProductDocumentAttr, 0, len(attrs)),
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+	if p.Category != nil {
+		doc.CategoryName = p.Category.Name
+	}
+
+	first := true
+	for _, item := range items {
+		doc.Items = append(doc.Items, ProductDocumentItem{
+			ID:         item.ID,
+			SKUCode:    item.SKUCode,
+			Price:      item.Price,
+			QtyInStock: item.QtyInStock,
+			Status:     item.Status,
+		})
+		doc.TotalStock += item.QtyInStock
+		if first || item.Price < doc.MinPrice {
+			doc.MinPrice = item.Price
+		}
+		if first || item.Price > doc.MaxPrice {
+			doc.MaxPrice = item.Price
+		}
+		first = false
+	}
+
+	for _, attr := range attrs {
+		doc.Attributes = append(doc.Attributes, ProductDocumentAttr{
+			AttributeID: attr.AttributeID,
+			Name:        attrNames[attr.AttributeID],
+			Value:       attr.Value,
+		})
+	}
+
+	return doc
+}
@@ -1,16 +1,32 @@
 package domain
 
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// SKU statuses (DISCONTINUED is set when a SKU is soft-deleted)
+const (
+	ProductItemStatusActive       = "ACTIVE"
+	ProductItemStatusOutOfStock   = "OUT_OF_STOCK"
+	ProductItemStatusDisabled     = "DISABLED"
+	ProductItemStatusDiscontinued = "DISCONTINUED"
+)
+
 // ProductItem represents a SKU - a specific variation combination with its own price and stock
 // Example: Product "T-Shirt" -> ProductItem "T-Shirt Size M Color Red" (SKU: TS-M-RED-001)
 // Following db-diagram.db schema (SOURCE OF TRUTH)
 type ProductItem struct {
-	ID         uint    `gorm:"primaryKey" json:"id"`
-	ProductID  uint    `gorm:"index;not null" json:"product_id"`
-	SKUCode    string  `gorm:"column:sku_code;size:50;uniqueIndex;not null" json:"sku_code"`
-	ImageURL   string  `gorm:"column:image_url;size:255" json:"image_url"`
-	Price      float64 `gorm:"type:decimal(15,2);not null" json:"price"`
-	QtyInStock int     `gorm:"column:qty_in_stock;default:0" json:"qty_in_stock"`
-	Status     string  `gorm:"size:20;default:'ACTIVE'" json:"status"`
+	ID         uint           `gorm:"primaryKey" json:"id"`
+	ProductID  uint           `gorm:"index;not null" json:"product_id"`
+	SKUCode    string         `gorm:"column:sku_code;size:50;uniqueIndex;not null" json:"sku_code"`
+	ImageURL   string         `gorm:"column:image_url;size:255" json:"image_url"`
+	Price      float64        `gorm:"type:decimal(15,2);not null" json:"price"`
+	QtyInStock int            `gorm:"column:qty_in_stock;default:0" json:"qty_in_stock"`
+	Status     string         `gorm:"size:20;default:'ACTIVE'" json:"status"`
+	Version    int            `gorm:"not null;default:1" json:"version"` // Optimistic lock, bumped on every update
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"` // Soft delete - order lines keep referencing the SKU
 }
 
 // TableName specifies the table name for GORM
@@ -20,11 +36,16 @@ func (ProductItem) TableName() string {
 
 // ProductItemRepository defines the interface for product item (SKU) data access
 type ProductItemRepository interface {
-	Create(item *ProductItem) error
-	Update(item *ProductItem) error
-	GetByID(id uint) (*ProductItem, error)
-	GetBySKUCode(skuCode string) (*ProductItem, error)
-	GetByProductID(productID uint) ([]*ProductItem, error)
-	Delete(id uint) error
-	UpdateStock(id uint, quantity int) error // Atomic stock update
+	Create(ctx context.Context, item *ProductItem) error
+	Update(ctx context.Context, item *ProductItem) error // Fails with ErrVersionConflict if item.Version is stale
+	GetByID(ctx context.Context, id uint) (*ProductItem, error)
+	GetBySKUCode(ctx context.Context, skuCode string) (*ProductItem, error)
+	GetByIDWithDeleted(ctx context.Context, id uint) (*ProductItem, error) // Includes soft-deleted SKUs (for restore)
+	ExistsBySKUCode(ctx context.Context, skuCode string) (bool, error)     // Includes soft-deleted SKUs (the code stays taken)
+	GetByProductID(ctx context.Context, productID uint) ([]*ProductItem, error)
+	GetByProductIDs(ctx context.Context, productIDs []uint) ([]*ProductItem, error)     // Batch fetch for many products
+	Delete(ctx context.Context, id uint) error                                          // Soft delete, marks the SKU DISCONTINUED
+	Restore(ctx context.Context, id uint) error                                         // Undo Delete; status becomes ACTIVE or OUT_OF_STOCK from stock
+	UpdateStock(ctx context.Context, id uint, quantity int) error                       // Atomic stock update (bumps the version)
+	DeductStock(ctx context.Context, id uint, quantity int) (*ProductItem, bool, error) // Conditional decrement; false when less than quantity is in stock
 }
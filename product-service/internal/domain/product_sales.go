@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ProductSalesDaily is the units of a product sold on one day (UTC), flushed from the
+// Redis sales counters. It is the durable sales history behind products.sold_count
+type ProductSalesDaily struct {
+	ProductID  uint      `gorm:"primaryKey" json:"product_id"`
+	Day        time.Time `gorm:"primaryKey;type:date" json:"day"`
+	ShopID     uint      `gorm:"index;not null" json:"shop_id"`
+	CategoryID *uint     `gorm:"index" json:"category_id,omitempty"`
+	Units      int64     `gorm:"not null;default:0" json:"units"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ProductSalesDaily) TableName() string {
+	return "product_sales_daily"
+}
+
+// ProductSalesRepository stores the daily sales of products
+type ProductSalesRepository interface {
+	// UpsertDaily saves the day totals of products; a total never goes down, so a
+	// counter that was lost in Redis cannot shrink a day that was already flushed
+	UpsertDaily(ctx context.Context, rows []*ProductSalesDaily) error
+	// RefreshSoldCounts sets products.sold_count to the all-time units of the products
+	RefreshSoldCounts(ctx context.Context, productIDs []uint) error
+}
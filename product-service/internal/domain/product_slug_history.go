@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ProductSlugHistory stores previous slugs of a product
+// When a product is renamed its old slug is kept here so old URLs can be redirected
+type ProductSlugHistory struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ProductID uint      `gorm:"index;not null" json:"product_id"`
+	Slug      string    `gorm:"size:255;uniqueIndex;not null" json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ProductSlugHistory) TableName() string {
+	return "product_slug_history"
+}
+
+// ProductSlugHistoryRepository defines the interface for product slug history data access
+type ProductSlugHistoryRepository interface {
+	Create(ctx context.Context, history *ProductSlugHistory) error
+	GetBySlug(ctx context.Context, slug string) (*ProductSlugHistory, error)
+	DeleteBySlug(ctx context.Context, slug string) error
+}
@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+// QuotaOperation is a seller write API metered by the hourly/daily quotas
+type QuotaOperation string
+
+const (
+	QuotaProductCreate QuotaOperation = "product_create" // product creation and product import jobs
+	QuotaStockSync     QuotaOperation = "stock_sync"     // SKU stock updates, inventory patches, inventory sheet commits
+)
+
+// QuotaOperations lists every metered operation
+var QuotaOperations = []QuotaOperation{QuotaProductCreate, QuotaStockSync}
+
+// QuotaLimit caps an operation per hour and per calendar day; 0 means unlimited
+type QuotaLimit struct {
+	Hourly int64 `json:"hourly"`
+	Daily  int64 `json:"daily"`
+}
+
+// QuotaTier is a named set of limits sellers are assigned to (e.g. standard, premium)
+type QuotaTier struct {
+	Name    string                        `json:"name"`
+	Default bool                          `json:"default"` // tier of sellers without an assignment
+	Limits  map[QuotaOperation]QuotaLimit `json:"limits"`
+}
+
+// QuotaUsage is how much of an operation's quota a seller used in the current hour and day
+type QuotaUsage struct {
+	Operation   QuotaOperation `json:"operation"`
+	Limit       QuotaLimit     `json:"limit"`
+	HourlyUsed  int64          `json:"hourly_used"`
+	DailyUsed   int64          `json:"daily_used"`
+	HourlyReset time.Time      `json:"hourly_reset"`
+	DailyReset  time.Time      `json:"daily_reset"`
+}
+
+// QuotaDecision is the outcome of metering one request; a rejected request is not counted
+type QuotaDecision struct {
+	Tier    string
+	Allowed bool
+	QuotaUsage
+}
+
+// SellerQuota is the tier and current usage of one seller
+type SellerQuota struct {
+	Seller string       `json:"seller"`
+	Tier   string       `json:"tier"`
+	Usage  []QuotaUsage `json:"usage"`
+}
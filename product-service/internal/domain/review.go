@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Review is a buyer's rating of a product they received (verified purchase)
+// A buyer reviews a product once; editing the review replaces its rating and comment
+type Review struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	ProductID uint           `gorm:"not null;uniqueIndex:idx_reviews_product_user,priority:1" json:"product_id"`
+	ShopID    uint           `gorm:"not null;index" json:"shop_id"` // Shop of the product, for shop listings and aggregates
+	UserID    uint           `gorm:"not null;uniqueIndex:idx_reviews_product_user,priority:2;index" json:"user_id"`
+	OrderID   uint           `gorm:"not null" json:"order_id"` // Delivered order that verified the purchase
+	Rating    int            `gorm:"not null" json:"rating"`   // 1-5 stars
+	Comment   string         `gorm:"size:2000" json:"comment,omitempty"`
+	Images    datatypes.JSON `gorm:"type:jsonb" json:"images,omitempty"` // JSON array of image URLs
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Review) TableName() string {
+	return "reviews"
+}
+
+// ErrReviewExists is returned when the buyer already reviewed the product
+var ErrReviewExists = Conflict("product already reviewed, edit the existing review instead")
+
+// Review sort orders
+const (
+	ReviewSortNewest     = "newest"
+	ReviewSortRatingDesc = "rating_desc"
+	ReviewSortRatingAsc  = "rating_asc"
+)
+
+// ReviewFilter selects and orders a page of reviews
+type ReviewFilter struct {
+	Rating int    // Only reviews with this many stars (0 = all)
+	Sort   string // One of the ReviewSort values (default newest)
+	Page   int
+	Limit  int
+}
+
+// RatingSummary is the rating aggregate of the reviews of a product or a shop
+type RatingSummary struct {
+	RatingAvg   float64     `json:"rating_avg"` // 0 = no reviews
+	RatingCount int         `json:"rating_count"`
+	Stars       map[int]int `json:"stars"` // Reviews per star (1-5)
+}
+
+// ShopRating is the rating aggregate of one shop, as listed for all shops at once
+type ShopRating struct {
+	ShopID      uint    `json:"shop_id"`
+	RatingAvg   float64 `json:"rating_avg"`
+	RatingCount int     `json:"rating_count"`
+}
+
+// ShopRatingEvent is the metadata of a shop_rating_updated product event
+type ShopRatingEvent struct {
+	ShopID      uint    `json:"shop_id"`
+	RatingAvg   float64 `json:"rating_avg"`
+	RatingCount int     `json:"rating_count"`
+}
+
+// ReviewRepository defines the interface for review data access
+type ReviewRepository interface {
+	Create(ctx context.Context, review *Review) error // ErrReviewExists if the buyer already reviewed the product
+	Update(ctx context.Context, review *Review) error // Rating, comment and images only
+	Delete(ctx context.Context, id uint) error
+	GetByID(ctx context.Context, id uint) (*Review, error)
+	ListByProduct(ctx context.Context, productID uint, filter ReviewFilter) ([]*Review, int64, error)
+	ListByShop(ctx context.Context, shopID uint, filter ReviewFilter) ([]*Review, int64, error)
+	ProductSummary(ctx context.Context, productID uint) (*RatingSummary, error)
+	ShopSummary(ctx context.Context, shopID uint) (*RatingSummary, error)
+	ShopRatings(ctx context.Context) ([]ShopRating, error) // Every shop with at least one review
+}
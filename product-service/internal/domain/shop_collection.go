@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ShopCollection is a seller-defined category of one shop ("Hàng mới về", "Sale cuối tuần")
+// Independent of marketplace categories: it only drives the shop's storefront navigation
+type ShopCollection struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	ShopID      uint      `gorm:"not null;index:idx_shop_collection_shop_position,priority:1" json:"shop_id"`
+	Name        string    `gorm:"size:100;not null" json:"name"`
+	Description string    `gorm:"type:text" json:"description,omitempty"`
+	Position    int       `gorm:"not null;default:0;index:idx_shop_collection_shop_position,priority:2" json:"position"` // Storefront order, ascending
+	IsVisible   bool      `gorm:"not null;default:true" json:"is_visible"`                                               // Hidden collections are kept but not shown
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	ProductCount int64 `gorm:"-" json:"product_count"` // Active products, filled by listings
+}
+
+// TableName specifies the table name for GORM
+func (ShopCollection) TableName() string {
+	return "shop_collection"
+}
+
+// ShopCollectionProduct assigns a product to a shop collection
+type ShopCollectionProduct struct {
+	CollectionID uint      `gorm:"primaryKey;autoIncrement:false" json:"collection_id"`
+	ProductID    uint      `gorm:"primaryKey;autoIncrement:false;index" json:"product_id"`
+	Position     int       `gorm:"not null;default:0" json:"position"` // Order inside the collection, ascending
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ShopCollectionProduct) TableName() string {
+	return "shop_collection_product"
+}
+
+// ShopCollectionRepository defines the interface for shop collection data access
+type ShopCollectionRepository interface {
+	Create(ctx context.Context, collection *ShopCollection) error
+	Update(ctx context.Context, collection *ShopCollection) error
+	Delete(ctx context.Context, id uint) error // Also removes its product assignments
+	GetByID(ctx context.Context, id uint) (*ShopCollection, error)
+	ListByShop(ctx context.Context, shopID uint, visibleOnly bool) ([]*ShopCollection, error) // Ordered by position
+	CountByShop(ctx context.Context, shopID uint) (int64, error)
+	MaxPosition(ctx context.Context, shopID uint) (int, error) // -1 if the shop has no collections
+
+	// Reorder sets the positions of the shop's collections to their index in ids
+	Reorder(ctx context.Context, shopID uint, ids []uint) error
+
+	// SetProducts replaces the collection's products, ordered as productIDs
+	SetProducts(ctx context.Context, collectionID uint, productIDs []uint) error
+	// ListProductIDs returns the collection's ACTIVE products in collection order
+	ListProductIDs(ctx context.Context, collectionID uint, page, limit int) ([]uint, int64, error)
+	// CountActiveProducts counts the ACTIVE products of each collection
+	CountActiveProducts(ctx context.Context, collectionIDs []uint) (map[uint]int64, error)
+}
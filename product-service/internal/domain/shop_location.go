@@ -0,0 +1,13 @@
+package domain
+
+// GeoPoint is a latitude/longitude pair (Elasticsearch geo_point object format)
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// ShopLocation is where a shop ships from, owned by identity-service
+type ShopLocation struct {
+	Province string    // Province code, e.g. "ho-chi-minh"; empty if the shop did not set one
+	Point    *GeoPoint // nil if the shop did not set coordinates
+}
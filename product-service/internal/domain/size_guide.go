@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// SizeChart is the size chart template of a category (fashion, shoes...)
+// It defines which measurements a size guide shows and their reference values per size;
+// subcategories without a chart use the nearest ancestor's
+// Example: Category "Áo thun" - measurements ["Ngực", "Dài áo", "Vai"], sizes S/M/L/XL
+type SizeChart struct {
+	ID           uint                         `gorm:"primaryKey" json:"id"`
+	CategoryID   uint                         `gorm:"uniqueIndex;not null" json:"category_id"`
+	Name         string                       `gorm:"size:100;not null" json:"name"`
+	Unit         string                       `gorm:"size:10;not null;default:'cm'" json:"unit"` // cm, inch
+	Measurements datatypes.JSONSlice[string]  `gorm:"type:jsonb;not null" json:"measurements"`   // Column names, in display order
+	Sizes        datatypes.JSONSlice[SizeRow] `gorm:"type:jsonb;not null" json:"sizes"`          // Reference values per size
+	Notes        string                       `gorm:"type:text" json:"notes,omitempty"`          // How to measure
+	CreatedAt    time.Time                    `json:"created_at"`
+	UpdatedAt    time.Time                    `json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (SizeChart) TableName() string {
+	return "size_chart"
+}
+
+// SizeRow is one size of a size chart or of a product's measurements
+// Values are keyed by measurement name and kept as text so ranges ("86-90") fit
+type SizeRow struct {
+	Size   string            `json:"size"` // S, M, L, 38, 39...
+	Values map[string]string `json:"values"`
+}
+
+// ProductMeasurement is the seller's measurement table of one product
+// It overrides the category chart's reference values on the product page
+type ProductMeasurement struct {
+	ProductID uint                         `gorm:"primaryKey;autoIncrement:false" json:"product_id"`
+	Unit      string                       `gorm:"size:10;not null;default:'cm'" json:"unit"`
+	Sizes     datatypes.JSONSlice[SizeRow] `gorm:"type:jsonb;not null" json:"sizes"`
+	Notes     string                       `gorm:"type:text" json:"notes,omitempty"` // Fit advice, e.g. "Form rộng, chọn nhỏ hơn 1 size"
+	UpdatedAt time.Time                    `json:"updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ProductMeasurement) TableName() string {
+	return "product_measurement"
+}
+
+// SizeGuide is what the product page renders: the category template and the
+// product's own measurements (either may be missing)
+type SizeGuide struct {
+	Chart        *SizeChart          `json:"chart,omitempty"`
+	Measurements *ProductMeasurement `json:"measurements,omitempty"`
+}
+
+// SizeGuideRepository defines the interface for size chart and product measurement data access
+type SizeGuideRepository interface {
+	GetChartByCategoryID(ctx context.Context, categoryID uint) (*SizeChart, error) // gorm.ErrRecordNotFound if none
+	GetChartsByCategoryIDs(ctx context.Context, categoryIDs []uint) ([]*SizeChart, error)
+	UpsertChart(ctx context.Context, chart *SizeChart) error
+	DeleteChart(ctx context.Context, categoryID uint) error
+
+	GetMeasurement(ctx context.Context, productID uint) (*ProductMeasurement, error) // gorm.ErrRecordNotFound if none
+	UpsertMeasurement(ctx context.Context, m *ProductMeasurement) error
+	DeleteMeasurement(ctx context.Context, productID uint) error
+}
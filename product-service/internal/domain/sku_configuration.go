@@ -1,5 +1,7 @@
 package domain
 
+import "context"
+
 // SKUConfiguration links a ProductItem (SKU) with VariationOptions
 // Example: SKU "TS-M-RED-001" = Size M (option_id=1) + Color Red (option_id=5)
 // This is a many-to-many relationship with composite primary key
@@ -16,11 +18,11 @@ func (SKUConfiguration) TableName() string {
 
 // SKUConfigurationRepository defines the interface for SKU configuration data access
 type SKUConfigurationRepository interface {
-	Create(config *SKUConfiguration) error
-	CreateBatch(configs []*SKUConfiguration) error // Bulk insert for multiple options
-	GetByProductItemID(productItemID uint) ([]*SKUConfiguration, error)
-	GetByVariationOptionID(optionID uint) ([]*SKUConfiguration, error)
-	Delete(productItemID uint, variationOptionID uint) error
-	DeleteByProductItemID(productItemID uint) error // Delete all configs for a SKU
+	Create(ctx context.Context, config *SKUConfiguration) error
+	CreateBatch(ctx context.Context, configs []*SKUConfiguration) error // Bulk insert for multiple options
+	GetByProductItemID(ctx context.Context, productItemID uint) ([]*SKUConfiguration, error)
+	GetByVariationOptionID(ctx context.Context, optionID uint) ([]*SKUConfiguration, error)
+	Delete(ctx context.Context, productItemID uint, variationOptionID uint) error
+	DeleteByProductItemID(ctx context.Context, productItemID uint) error // Delete all configs for a SKU
 }
 
@@ -1,5 +1,7 @@
 package domain
 
+import "context"
+
 // Variation represents a type of product variation (e.g. Size, Color, Storage)
 // Following db-diagram.db schema (SOURCE OF TRUTH)
 type Variation struct {
@@ -15,10 +17,10 @@ func (Variation) TableName() string {
 
 // VariationRepository defines the interface for variation data access
 type VariationRepository interface {
-	Create(variation *Variation) error
-	Update(variation *Variation) error
-	GetByID(id uint) (*Variation, error)
-	GetByProductID(productID uint) ([]*Variation, error)
-	Delete(id uint) error
+	Create(ctx context.Context, variation *Variation) error
+	Update(ctx context.Context, variation *Variation) error
+	GetByID(ctx context.Context, id uint) (*Variation, error)
+	GetByProductID(ctx context.Context, productID uint) ([]*Variation, error)
+	Delete(ctx context.Context, id uint) error
 }
 
@@ -1,5 +1,7 @@
 package domain
 
+import "context"
+
 // VariationOption represents a value for a variation (e.g. "M", "L", "Red", "Blue")
 // Following db-diagram.db schema (SOURCE OF TRUTH)
 type VariationOption struct {
@@ -15,10 +17,10 @@ func (VariationOption) TableName() string {
 
 // VariationOptionRepository defines the interface for variation option data access
 type VariationOptionRepository interface {
-	Create(option *VariationOption) error
-	Update(option *VariationOption) error
-	GetByID(id uint) (*VariationOption, error)
-	GetByVariationID(variationID uint) ([]*VariationOption, error)
-	Delete(id uint) error
+	Create(ctx context.Context, option *VariationOption) error
+	Update(ctx context.Context, option *VariationOption) error
+	GetByID(ctx context.Context, id uint) (*VariationOption, error)
+	GetByVariationID(ctx context.Context, variationID uint) ([]*VariationOption, error)
+	Delete(ctx context.Context, id uint) error
 }
 
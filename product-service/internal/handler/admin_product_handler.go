@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+	"product-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AdminProductHandler handles admin HTTP requests for product search and moderation
+type AdminProductHandler struct {
+	adminService *service.AdminProductService
+	logger       *zap.Logger
+}
+
+// NewAdminProductHandler creates a new admin product handler
+func NewAdminProductHandler(adminService *service.AdminProductService, logger *zap.Logger) *AdminProductHandler {
+	return &AdminProductHandler{
+		adminService: adminService,
+		logger:       logger,
+	}
+}
+
+// SearchProducts handles GET /admin/products/search
+// @Summary Search products across all shops (admin)
+// @Description Elasticsearch search by name or SKU with shop, category and status filters; INACTIVE and ARCHIVED products are included
+// @Tags Admin
+// @Produce json
+// @Param q query string false "Product name or SKU code"
+// @Param sku query string false "Exact SKU code"
+// @Param shop_id query int false "Shop ID"
+// @Param category_id query int false "Category ID"
+// @Param status query string false "Comma-separated statuses (ACTIVE,INACTIVE,ARCHIVED)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{} "Products with pagination"
+// @Failure 400 {object} map[string]string "Invalid filters"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/products/search [get]
+func (h *AdminProductHandler) SearchProducts(c *gin.Context) {
+	var req service.AdminProductSearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	products, total, err := h.adminService.SearchProducts(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, h.logger, "failed to search products", err)
+		return
+	}
+
+	page, limit := req.Page, req.Limit
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"products": products,
+		"total":    total,
+		"page":     page,
+		"limit":    limit,
+	})
+}
+
+// ModerateProducts handles POST /admin/products/moderate
+// @Summary Bulk moderate products (admin)
+// @Description Activates, deactivates or archives the given product_ids, or every result of filter (same filters as the admin search, at most 500 products)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body service.ModerateProductsRequest true "Action and target products"
+// @Success 200 {object} service.ModerationResult
+// @Failure 400 {object} map[string]string "Invalid moderation request"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/products/moderate [post]
+func (h *AdminProductHandler) ModerateProducts(c *gin.Context) {
+	var req service.ModerateProductsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := h.adminService.Moderate(c.Request.Context(), &req, c.GetHeader("X-User-Id"))
+	if err != nil {
+		respondError(c, h.logger, "failed to moderate products", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
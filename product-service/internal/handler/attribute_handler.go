@@ -44,17 +44,16 @@ func (h *AttributeHandler) CreateCategoryAttribute(c *gin.Context) {
 
 	var req service.CreateCategoryAttributeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	// Set category_id from path
 	req.CategoryID = uint(categoryID)
 
-	attr, err := h.attributeService.CreateCategoryAttribute(&req)
+	attr, err := h.attributeService.CreateCategoryAttribute(c.Request.Context(), &req)
 	if err != nil {
-		h.logger.Error("failed to create category attribute", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to create category attribute", err)
 		return
 	}
 
@@ -78,10 +77,9 @@ func (h *AttributeHandler) GetCategoryAttributes(c *gin.Context) {
 		return
 	}
 
-	attrs, err := h.attributeService.GetCategoryAttributes(uint(categoryID))
+	attrs, err := h.attributeService.GetCategoryAttributes(c.Request.Context(), uint(categoryID))
 	if err != nil {
-		h.logger.Error("failed to get category attributes", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get attributes"})
+		respondError(c, h.logger, "failed to get attributes", err)
 		return
 	}
 
@@ -112,13 +110,12 @@ func (h *AttributeHandler) SetProductAttributes(c *gin.Context) {
 
 	var req service.SetProductAttributesRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
-	if err := h.attributeService.SetProductAttributes(uint(productID), &req); err != nil {
-		h.logger.Error("failed to set product attributes", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.attributeService.SetProductAttributes(c.Request.Context(), uint(productID), &req); err != nil {
+		respondError(c, h.logger, "failed to set product attributes", err)
 		return
 	}
 
@@ -142,10 +139,9 @@ func (h *AttributeHandler) GetProductAttributes(c *gin.Context) {
 		return
 	}
 
-	attrs, err := h.attributeService.GetProductAttributes(uint(productID))
+	attrs, err := h.attributeService.GetProductAttributes(c.Request.Context(), uint(productID))
 	if err != nil {
-		h.logger.Error("failed to get product attributes", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get attributes"})
+		respondError(c, h.logger, "failed to get attributes", err)
 		return
 	}
 
@@ -172,9 +168,8 @@ func (h *AttributeHandler) DeleteCategoryAttribute(c *gin.Context) {
 		return
 	}
 
-	if err := h.attributeService.DeleteCategoryAttribute(uint(attrID)); err != nil {
-		h.logger.Error("failed to delete category attribute", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete attribute"})
+	if err := h.attributeService.DeleteCategoryAttribute(c.Request.Context(), uint(attrID)); err != nil {
+		respondError(c, h.logger, "failed to delete attribute", err)
 		return
 	}
 
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+	"product-service/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// BestsellerHandler handles HTTP requests for category and shop bestseller lists
+type BestsellerHandler struct {
+	salesRankingService *service.SalesRankingService
+	logger              *zap.Logger
+}
+
+// NewBestsellerHandler creates a new bestseller handler
+func NewBestsellerHandler(salesRankingService *service.SalesRankingService, logger *zap.Logger) *BestsellerHandler {
+	return &BestsellerHandler{
+		salesRankingService: salesRankingService,
+		logger:              logger,
+	}
+}
+
+// GetCategoryBestsellers handles GET /categories/:id/bestsellers
+// @Summary Get the bestsellers of a category
+// @Description Listed products of the category and its subcategories ranked by units sold in the last 7 days
+// @Tags categories
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param limit query int false "Number of products (max 100)" default(20)
+// @Success 200 {object} map[string]interface{} "Bestsellers with rank and units sold"
+// @Failure 400 {object} map[string]string "Invalid category ID"
+// @Failure 404 {object} map[string]string "Category not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /categories/{id}/bestsellers [get]
+func (h *BestsellerHandler) GetCategoryBestsellers(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category ID"})
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	bestsellers, err := h.salesRankingService.CategoryBestsellers(c.Request.Context(), uint(categoryID), limit)
+	if err != nil {
+		respondError(c, h.logger, "failed to get category bestsellers", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"bestsellers": bestsellers,
+		"count":       len(bestsellers),
+	})
+}
+
+// GetShopBestsellers handles GET /shops/:id/bestsellers
+// @Summary Get the bestsellers of a shop
+// @Description Listed products of the shop ranked by units sold in the last 7 days
+// @Tags shops
+// @Produce json
+// @Param id path int true "Shop ID"
+// @Param limit query int false "Number of products (max 100)" default(20)
+// @Success 200 {object} map[string]interface{} "Bestsellers with rank and units sold"
+// @Failure 400 {object} map[string]string "Invalid shop ID"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /shops/{id}/bestsellers [get]
+func (h *BestsellerHandler) GetShopBestsellers(c *gin.Context) {
+	shopID, ok := parseShopID(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	bestsellers, err := h.salesRankingService.ShopBestsellers(c.Request.Context(), shopID, limit)
+	if err != nil {
+		respondError(c, h.logger, "failed to get shop bestsellers", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"bestsellers": bestsellers,
+		"count":       len(bestsellers),
+	})
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"net/http"
+	"product-service/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// CatalogQualityHandler handles admin HTTP requests for the catalog quality checker
+type CatalogQualityHandler struct {
+	qualityService *service.CatalogQualityService
+	logger         *zap.Logger
+}
+
+// NewCatalogQualityHandler creates a new catalog quality handler
+func NewCatalogQualityHandler(qualityService *service.CatalogQualityService, logger *zap.Logger) *CatalogQualityHandler {
+	return &CatalogQualityHandler{
+		qualityService: qualityService,
+		logger:         logger,
+	}
+}
+
+// GetReport handles GET /admin/catalog-quality
+// @Summary Last catalog quality report (admin)
+// @Description Report of the last run (placeholder images, missing mandatory attributes, zero-stock ACTIVE SKUs, price anomalies vs category median) with its issues
+// @Tags Admin
+// @Produce json
+// @Param shop_id query int false "Only issues of this shop"
+// @Param kind query string false "Only issues of this kind (PLACEHOLDER_IMAGE, MISSING_ATTRIBUTE, ZERO_STOCK_ACTIVE, PRICE_ANOMALY)"
+// @Param limit query int false "Max issues" default(100)
+// @Success 200 {object} map[string]interface{} "Report and issues"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Failure 404 {object} map[string]string "Not run yet"
+// @Router /admin/catalog-quality [get]
+func (h *CatalogQualityHandler) GetReport(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if limit < 1 || limit > 1000 {
+		limit = 100
+	}
+	shopID, _ := strconv.ParseUint(c.Query("shop_id"), 10, 32)
+
+	report, issues, err := h.qualityService.GetLastReport(c.Request.Context(), uint(shopID), c.Query("kind"), limit)
+	if err != nil {
+		respondError(c, h.logger, "failed to get catalog quality report", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"report": report, "issues": issues})
+}
+
+// ListShopScores handles GET /admin/catalog-quality/shops
+// @Summary Shop quality scores (admin)
+// @Description Per-shop scores of the last run (share of listed products without issues), lowest first
+// @Tags Admin
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{} "Scores with pagination"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Failure 404 {object} map[string]string "Not run yet"
+// @Router /admin/catalog-quality/shops [get]
+func (h *CatalogQualityHandler) ListShopScores(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	report, scores, total, err := h.qualityService.ListShopScores(c.Request.Context(), page, limit)
+	if err != nil {
+		respondError(c, h.logger, "failed to list shop quality scores", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"run_id":        report.RunID,
+		"average_score": report.AverageScore,
+		"scores":        scores,
+		"total":         total,
+		"page":          page,
+		"limit":         limit,
+	})
+}
+
+// GetShopScore handles GET /admin/catalog-quality/shops/:shop_id
+// @Summary Quality score of one shop (admin)
+// @Tags Admin
+// @Produce json
+// @Param shop_id path int true "Shop ID"
+// @Success 200 {object} domain.ShopQualityScore
+// @Failure 400 {object} map[string]string "Invalid shop ID"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Failure 404 {object} map[string]string "Not run yet or no score for the shop"
+// @Router /admin/catalog-quality/shops/{shop_id} [get]
+func (h *CatalogQualityHandler) GetShopScore(c *gin.Context) {
+	shopID, err := strconv.ParseUint(c.Param("shop_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid shop ID"})
+		return
+	}
+
+	score, err := h.qualityService.GetShopScore(c.Request.Context(), uint(shopID))
+	if err != nil {
+		respondError(c, h.logger, "failed to get shop quality score", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, score)
+}
+
+// RunCheck handles POST /admin/catalog-quality/run
+// @Summary Run the catalog quality check now (admin)
+// @Description Enqueues a quality check job; poll GET /admin/catalog-quality for the result
+// @Tags Admin
+// @Produce json
+// @Success 202 {object} jobs.Job "Enqueued job"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Router /admin/catalog-quality/run [post]
+func (h *CatalogQualityHandler) RunCheck(c *gin.Context) {
+	job, err := h.qualityService.RunNow(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, "failed to enqueue catalog quality check", err)
+		return
+	}
+
+	h.logger.Info("catalog quality check requested",
+		zap.String("job_id", job.ID),
+		zap.String("user_id", c.GetHeader("X-User-Id")),
+	)
+	c.JSON(http.StatusAccepted, job)
+}
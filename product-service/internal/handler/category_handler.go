@@ -5,6 +5,7 @@ import (
 	"product-service/internal/domain"
 	"product-service/internal/service"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -30,7 +31,7 @@ func NewCategoryHandler(categoryService *service.CategoryService, logger *zap.Lo
 // CreateCategoryRequest represents the request body for creating a category
 type CreateCategoryRequest struct {
 	Name        string `json:"name" binding:"required"`
-	Slug        string `json:"slug"`
+	Slug        string `json:"slug" binding:"omitempty,slug"`
 	ParentID    *uint  `json:"parent_id,omitempty"`
 	Description string `json:"description"`
 }
@@ -38,7 +39,7 @@ type CreateCategoryRequest struct {
 // UpdateCategoryRequest represents the request body for updating a category
 type UpdateCategoryRequest struct {
 	Name        string `json:"name"`
-	Slug        string `json:"slug"`
+	Slug        string `json:"slug" binding:"omitempty,slug"`
 	ParentID    *uint  `json:"parent_id,omitempty"`
 	Description string `json:"description"`
 }
@@ -58,7 +59,7 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 	var req CreateCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Warn("invalid request body", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -72,8 +73,7 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 
 	// Call service layer
 	if err := h.categoryService.CreateCategory(c.Request.Context(), category); err != nil {
-		h.logger.Error("failed to create category", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to create category", err)
 		return
 	}
 
@@ -105,14 +105,14 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 
 	var req UpdateCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	// Get existing category
 	category, err := h.categoryService.GetCategory(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+		respondError(c, h.logger, "failed to get category", err)
 		return
 	}
 
@@ -132,8 +132,7 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 
 	// Call service layer
 	if err := h.categoryService.UpdateCategory(c.Request.Context(), category); err != nil {
-		h.logger.Error("failed to update category", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to update category", err)
 		return
 	}
 
@@ -163,12 +162,56 @@ func (h *CategoryHandler) GetCategory(c *gin.Context) {
 
 	category, err := h.categoryService.GetCategory(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+		respondError(c, h.logger, "failed to get category", err)
 		return
 	}
 
 	// Use DTO to prevent domain leak
-	c.JSON(http.StatusOK, ToCategoryResponse(category))
+	writeConditionalJSON(c, category.UpdatedAt, ToCategoryResponse(category))
+}
+
+// GetCategoriesBatch handles GET /categories/batch
+// @Summary Get categories by IDs
+// @Description Get up to 100 categories in one request, in the order of ids (unknown IDs are skipped)
+// @Tags Categories
+// @Produce json
+// @Param ids query string true "Comma-separated category IDs" example(1,2,3)
+// @Success 200 {object} map[string]interface{} "Categories and count"
+// @Failure 400 {object} map[string]string "Invalid IDs"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /categories/batch [get]
+func (h *CategoryHandler) GetCategoriesBatch(c *gin.Context) {
+	idsParam := c.Query("ids")
+	if idsParam == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ids parameter is required"})
+		return
+	}
+
+	var ids []uint
+	for _, idStr := range splitByComma(idsParam) {
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id format: " + idStr})
+			return
+		}
+		ids = append(ids, uint(id))
+	}
+	if len(ids) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no valid ids provided"})
+		return
+	}
+
+	categories, err := h.categoryService.GetCategoriesByIDs(c.Request.Context(), ids)
+	if err != nil {
+		respondError(c, h.logger, "failed to fetch categories", err)
+		return
+	}
+
+	// Use DTO to prevent domain leak
+	c.JSON(http.StatusOK, gin.H{
+		"categories": ToCategoryResponses(categories),
+		"count":      len(categories),
+	})
 }
 
 // GetCategoryBySlug handles GET /categories/slug/:slug
@@ -191,12 +234,12 @@ func (h *CategoryHandler) GetCategoryBySlug(c *gin.Context) {
 
 	category, err := h.categoryService.GetCategoryBySlug(c.Request.Context(), slug)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+		respondError(c, h.logger, "failed to get category", err)
 		return
 	}
 
 	// Use DTO to prevent domain leak
-	c.JSON(http.StatusOK, ToCategoryResponse(category))
+	writeConditionalJSON(c, category.UpdatedAt, ToCategoryResponse(category))
 }
 
 // GetAllCategories handles GET /categories
@@ -210,13 +253,22 @@ func (h *CategoryHandler) GetCategoryBySlug(c *gin.Context) {
 func (h *CategoryHandler) GetAllCategories(c *gin.Context) {
 	categories, err := h.categoryService.GetAllCategories(c.Request.Context())
 	if err != nil {
-		h.logger.Error("failed to get all categories", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to get all categories", err)
 		return
 	}
 
 	// Use DTO to prevent domain leak
-	c.JSON(http.StatusOK, ToCategoryResponses(categories))
+	writeConditionalJSON(c, categoriesLastModified(categories), ToCategoryResponses(categories))
+}
+
+// categoriesLastModified is the latest change in a category list. A deleted category does
+// not move it, but the ETag changes and If-None-Match is checked first
+func categoriesLastModified(categories []*domain.Category) time.Time {
+	var t time.Time
+	for _, category := range categories {
+		t = latest(t, category.UpdatedAt)
+	}
+	return t
 }
 
 // GetCategoryChildren handles GET /categories/:id/children
@@ -238,13 +290,12 @@ func (h *CategoryHandler) GetCategoryChildren(c *gin.Context) {
 
 	children, err := h.categoryService.GetCategoryChildren(c.Request.Context(), uint(id))
 	if err != nil {
-		h.logger.Error("failed to get category children", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to get category children", err)
 		return
 	}
 
 	// Use DTO to prevent domain leak
-	c.JSON(http.StatusOK, ToCategoryResponses(children))
+	writeConditionalJSON(c, categoriesLastModified(children), ToCategoryResponses(children))
 }
 
 // DeleteCategory handles DELETE /categories/:id
@@ -266,8 +317,7 @@ func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 	}
 
 	if err := h.categoryService.DeleteCategory(c.Request.Context(), uint(id)); err != nil {
-		h.logger.Error("failed to delete category", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to delete category", err)
 		return
 	}
 
@@ -0,0 +1,332 @@
+package handler
+
+import (
+	"net/http"
+	"product-service/internal/domain"
+	"product-service/internal/service"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ContentHandler handles HTTP requests for homepage content (banners, campaigns)
+type ContentHandler struct {
+	contentService *service.ContentService
+	logger         *zap.Logger
+}
+
+// NewContentHandler creates a new content handler
+func NewContentHandler(contentService *service.ContentService, logger *zap.Logger) *ContentHandler {
+	return &ContentHandler{
+		contentService: contentService,
+		logger:         logger,
+	}
+}
+
+// BannerRequest represents the request body for creating/updating a banner
+type BannerRequest struct {
+	CampaignID *uint      `json:"campaign_id,omitempty"`
+	Title      string     `json:"title" binding:"required,max=200"`
+	ImageURL   string     `json:"image_url" binding:"required,max=500"`
+	LinkURL    string     `json:"link_url" binding:"max=500"`
+	Placement  string     `json:"placement" binding:"required"` // HOME_HERO, HOME_SIDEBAR, HOME_POPUP, CATEGORY_TOP, CHECKOUT_SIDE
+	Priority   int        `json:"priority"`
+	StartAt    *time.Time `json:"start_at,omitempty"`
+	EndAt      *time.Time `json:"end_at,omitempty"`
+	IsActive   *bool      `json:"is_active"`
+}
+
+// CampaignRequest represents the request body for creating/updating a campaign
+type CampaignRequest struct {
+	Name        string     `json:"name" binding:"required,max=150"`
+	Description string     `json:"description"`
+	StartAt     *time.Time `json:"start_at,omitempty"`
+	EndAt       *time.Time `json:"end_at,omitempty"`
+	IsActive    *bool      `json:"is_active"`
+}
+
+// GetHomeContent handles GET /content/home
+// @Summary Get homepage content
+// @Description Get active banners (grouped by placement) and running campaigns for the homepage
+// @Tags Content
+// @Produce json
+// @Success 200 {object} service.HomeContent "Homepage content"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /content/home [get]
+func (h *ContentHandler) GetHomeContent(c *gin.Context) {
+	content, err := h.contentService.GetHomeContent(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, "failed to get home content", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, content)
+}
+
+// ListBanners handles GET /admin/banners
+// @Summary List banners (admin)
+// @Description List all banners, optionally filtered by placement or campaign
+// @Tags Content
+// @Produce json
+// @Param placement query string false "Filter by placement"
+// @Param campaign_id query int false "Filter by campaign ID"
+// @Success 200 {object} map[string]interface{} "List of banners"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Router /admin/banners [get]
+func (h *ContentHandler) ListBanners(c *gin.Context) {
+	var campaignID *uint
+	if campaignIDStr := c.Query("campaign_id"); campaignIDStr != "" {
+		id, err := strconv.ParseUint(campaignIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign_id"})
+			return
+		}
+		cid := uint(id)
+		campaignID = &cid
+	}
+
+	banners, err := h.contentService.ListBanners(c.Request.Context(), c.Query("placement"), campaignID)
+	if err != nil {
+		respondError(c, h.logger, "failed to list banners", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"banners": banners})
+}
+
+// CreateBanner handles POST /admin/banners
+// @Summary Create banner (admin)
+// @Tags Content
+// @Accept json
+// @Produce json
+// @Param request body BannerRequest true "Banner"
+// @Success 201 {object} map[string]interface{} "Banner created"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Router /admin/banners [post]
+func (h *ContentHandler) CreateBanner(c *gin.Context) {
+	var req BannerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	banner := &domain.Banner{IsActive: true}
+	applyBannerRequest(banner, &req)
+
+	if err := h.contentService.CreateBanner(c.Request.Context(), banner); err != nil {
+		respondError(c, h.logger, "failed to create banner", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "banner created successfully",
+		"banner":  banner,
+	})
+}
+
+// UpdateBanner handles PUT /admin/banners/:id
+// @Summary Update banner (admin)
+// @Tags Content
+// @Accept json
+// @Produce json
+// @Param id path int true "Banner ID"
+// @Param request body BannerRequest true "Banner"
+// @Success 200 {object} map[string]interface{} "Banner updated"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Banner not found"
+// @Router /admin/banners/{id} [put]
+func (h *ContentHandler) UpdateBanner(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid banner ID"})
+		return
+	}
+
+	var req BannerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	banner, err := h.contentService.GetBanner(c.Request.Context(), uint(id))
+	if err != nil {
+		respondError(c, h.logger, "failed to get banner", err)
+		return
+	}
+	applyBannerRequest(banner, &req)
+
+	if err := h.contentService.UpdateBanner(c.Request.Context(), banner); err != nil {
+		respondError(c, h.logger, "failed to update banner", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "banner updated successfully",
+		"banner":  banner,
+	})
+}
+
+// DeleteBanner handles DELETE /admin/banners/:id
+// @Summary Delete banner (admin)
+// @Tags Content
+// @Produce json
+// @Param id path int true "Banner ID"
+// @Success 200 {object} map[string]string "Banner deleted"
+// @Failure 404 {object} map[string]string "Banner not found"
+// @Router /admin/banners/{id} [delete]
+func (h *ContentHandler) DeleteBanner(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid banner ID"})
+		return
+	}
+
+	if err := h.contentService.DeleteBanner(c.Request.Context(), uint(id)); err != nil {
+		respondError(c, h.logger, "failed to delete banner", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "banner deleted successfully"})
+}
+
+// ListCampaigns handles GET /admin/campaigns
+// @Summary List campaigns (admin)
+// @Tags Content
+// @Produce json
+// @Success 200 {object} map[string]interface{} "List of campaigns"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Router /admin/campaigns [get]
+func (h *ContentHandler) ListCampaigns(c *gin.Context) {
+	campaigns, err := h.contentService.ListCampaigns(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, "failed to list campaigns", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"campaigns": campaigns})
+}
+
+// CreateCampaign handles POST /admin/campaigns
+// @Summary Create campaign (admin)
+// @Tags Content
+// @Accept json
+// @Produce json
+// @Param request body CampaignRequest true "Campaign"
+// @Success 201 {object} map[string]interface{} "Campaign created"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Router /admin/campaigns [post]
+func (h *ContentHandler) CreateCampaign(c *gin.Context) {
+	var req CampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	campaign := &domain.Campaign{IsActive: true}
+	applyCampaignRequest(campaign, &req)
+
+	if err := h.contentService.CreateCampaign(c.Request.Context(), campaign); err != nil {
+		respondError(c, h.logger, "failed to create campaign", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "campaign created successfully",
+		"campaign": campaign,
+	})
+}
+
+// UpdateCampaign handles PUT /admin/campaigns/:id
+// @Summary Update campaign (admin)
+// @Tags Content
+// @Accept json
+// @Produce json
+// @Param id path int true "Campaign ID"
+// @Param request body CampaignRequest true "Campaign"
+// @Success 200 {object} map[string]interface{} "Campaign updated"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 404 {object} map[string]string "Campaign not found"
+// @Router /admin/campaigns/{id} [put]
+func (h *ContentHandler) UpdateCampaign(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign ID"})
+		return
+	}
+
+	var req CampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	campaign, err := h.contentService.GetCampaign(c.Request.Context(), uint(id))
+	if err != nil {
+		respondError(c, h.logger, "failed to get campaign", err)
+		return
+	}
+	applyCampaignRequest(campaign, &req)
+
+	if err := h.contentService.UpdateCampaign(c.Request.Context(), campaign); err != nil {
+		respondError(c, h.logger, "failed to update campaign", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "campaign updated successfully",
+		"campaign": campaign,
+	})
+}
+
+// DeleteCampaign handles DELETE /admin/campaigns/:id
+// @Summary Delete campaign (admin)
+// @Description Delete a campaign. Its banners are kept but detached from the campaign
+// @Tags Content
+// @Produce json
+// @Param id path int true "Campaign ID"
+// @Success 200 {object} map[string]string "Campaign deleted"
+// @Failure 404 {object} map[string]string "Campaign not found"
+// @Router /admin/campaigns/{id} [delete]
+func (h *ContentHandler) DeleteCampaign(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid campaign ID"})
+		return
+	}
+
+	if err := h.contentService.DeleteCampaign(c.Request.Context(), uint(id)); err != nil {
+		respondError(c, h.logger, "failed to delete campaign", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "campaign deleted successfully"})
+}
+
+// applyBannerRequest copies request fields to the banner entity
+func applyBannerRequest(banner *domain.Banner, req *BannerRequest) {
+	banner.CampaignID = req.CampaignID
+	banner.Title = req.Title
+	banner.ImageURL = req.ImageURL
+	banner.LinkURL = req.LinkURL
+	banner.Placement = req.Placement
+	banner.Priority = req.Priority
+	banner.StartAt = req.StartAt
+	banner.EndAt = req.EndAt
+	if req.IsActive != nil {
+		banner.IsActive = *req.IsActive
+	}
+}
+
+// applyCampaignRequest copies request fields to the campaign entity
+func applyCampaignRequest(campaign *domain.Campaign, req *CampaignRequest) {
+	campaign.Name = req.Name
+	campaign.Description = req.Description
+	campaign.StartAt = req.StartAt
+	campaign.EndAt = req.EndAt
+	if req.IsActive != nil {
+		campaign.IsActive = *req.IsActive
+	}
+}
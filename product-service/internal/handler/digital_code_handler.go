@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"product-service/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DigitalCodeHandler handles HTTP requests for the code pools of digital products
+type DigitalCodeHandler struct {
+	digitalCodeService *service.DigitalCodeService
+	logger             *zap.Logger
+}
+
+// NewDigitalCodeHandler creates a new digital code handler
+func NewDigitalCodeHandler(digitalCodeService *service.DigitalCodeService, logger *zap.Logger) *DigitalCodeHandler {
+	return &DigitalCodeHandler{
+		digitalCodeService: digitalCodeService,
+		logger:             logger,
+	}
+}
+
+// UploadCodes handles POST /product-items/:id/codes
+// @Summary Upload codes to a digital SKU (seller)
+// @Description Adds voucher/game key codes to the SKU's pool (encrypted at rest). Codes already in a pool are skipped. The SKU's stock becomes the number of available codes
+// @Tags digital-codes
+// @Accept json
+// @Produce json
+// @Param id path int true "Product Item ID"
+// @Param request body service.UploadDigitalCodesRequest true "Codes"
+// @Success 200 {object} service.UploadDigitalCodesResult
+// @Failure 400 {object} map[string]string "Invalid codes or not a digital product"
+// @Failure 404 {object} map[string]string "Product item not found"
+// @Failure 503 {object} map[string]string "Digital codes not configured"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /product-items/{id}/codes [post]
+func (h *DigitalCodeHandler) UploadCodes(c *gin.Context) {
+	productItemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid product item ID"})
+		return
+	}
+
+	var req service.UploadDigitalCodesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := h.digitalCodeService.UploadCodes(c.Request.Context(), uint(productItemID), &req)
+	if err != nil {
+		h.respondError(c, "failed to upload digital codes", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetCodeStats handles GET /product-items/:id/codes/stats
+// @Summary Count the codes of a digital SKU (seller)
+// @Tags digital-codes
+// @Produce json
+// @Param id path int true "Product Item ID"
+// @Success 200 {object} domain.DigitalCodeStats
+// @Failure 400 {object} map[string]string "Invalid product item ID or not a digital product"
+// @Failure 404 {object} map[string]string "Product item not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /product-items/{id}/codes/stats [get]
+func (h *DigitalCodeHandler) GetCodeStats(c *gin.Context) {
+	productItemID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid product item ID"})
+		return
+	}
+
+	stats, err := h.digitalCodeService.GetStats(c.Request.Context(), uint(productItemID))
+	if err != nil {
+		h.respondError(c, "failed to get digital code stats", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// IssueCodes handles POST /product-items/issue-codes
+// @Summary Issue codes to a paid order (internal)
+// @Description Called by order-service after payment. Idempotent per order: retries return the codes already issued and only issue what is missing
+// @Tags digital-codes
+// @Accept json
+// @Produce json
+// @Param request body service.IssueDigitalCodesRequest true "Order lines"
+// @Success 200 {object} map[string]interface{} "Issued codes"
+// @Failure 400 {object} map[string]string "Invalid request or not a digital product"
+// @Failure 404 {object} map[string]string "Product item not found"
+// @Failure 409 {object} map[string]string "Not enough codes available"
+// @Failure 503 {object} map[string]string "Digital codes not configured"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /product-items/issue-codes [post]
+func (h *DigitalCodeHandler) IssueCodes(c *gin.Context) {
+	var req service.IssueDigitalCodesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	codes, err := h.digitalCodeService.IssueCodes(c.Request.Context(), &req)
+	if err != nil {
+		h.respondError(c, "failed to issue digital codes", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"order_id": req.OrderID,
+		"codes":    codes,
+	})
+}
+
+// GetOrderCodes handles GET /product-items/issued-codes?order_id=
+// @Summary Get the codes issued to an order (internal)
+// @Description Called by order-service to show the codes on the order detail
+// @Tags digital-codes
+// @Produce json
+// @Param order_id query string true "Order number"
+// @Success 200 {object} map[string]interface{} "Issued codes"
+// @Failure 400 {object} map[string]string "Missing order_id"
+// @Failure 503 {object} map[string]string "Digital codes not configured"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /product-items/issued-codes [get]
+func (h *DigitalCodeHandler) GetOrderCodes(c *gin.Context) {
+	orderID := c.Query("order_id")
+	if orderID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order_id is required"})
+		return
+	}
+
+	codes, err := h.digitalCodeService.GetOrderCodes(c.Request.Context(), orderID)
+	if err != nil {
+		h.respondError(c, "failed to get digital codes", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"order_id": orderID,
+		"codes":    codes,
+	})
+}
+
+// respondError answers 503 while digital codes are not configured, other errors by their kind
+func (h *DigitalCodeHandler) respondError(c *gin.Context, msg string, err error) {
+	if errors.Is(err, service.ErrDigitalCodesDisabled) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	respondError(c, h.logger, msg, err)
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+	"product-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// FeedHandler handles HTTP requests for the generated product feed
+type FeedHandler struct {
+	feedService *service.FeedService
+	logger      *zap.Logger
+}
+
+// NewFeedHandler creates a new feed handler
+func NewFeedHandler(feedService *service.FeedService, logger *zap.Logger) *FeedHandler {
+	return &FeedHandler{
+		feedService: feedService,
+		logger:      logger,
+	}
+}
+
+// GetProductFeed handles GET /feeds/products
+// @Summary Get product feed
+// @Description Catalog feed of active products, regenerated hourly by a background job
+// @Tags Feeds
+// @Produce json
+// @Success 200 {object} service.ProductFeed "Product feed"
+// @Failure 404 {object} map[string]string "Feed not generated yet"
+// @Router /feeds/products [get]
+func (h *FeedHandler) GetProductFeed(c *gin.Context) {
+	data, err := h.feedService.GetFeed(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, "failed to get product feed", err)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
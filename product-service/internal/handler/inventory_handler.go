@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+	"product-service/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// InventoryHandler handles admin HTTP requests for inventory reconciliation
+type InventoryHandler struct {
+	reconcileService *service.InventoryReconcileService
+	logger           *zap.Logger
+}
+
+// NewInventoryHandler creates a new inventory handler
+func NewInventoryHandler(reconcileService *service.InventoryReconcileService, logger *zap.Logger) *InventoryHandler {
+	return &InventoryHandler{
+		reconcileService: reconcileService,
+		logger:           logger,
+	}
+}
+
+// GetReconciliation handles GET /admin/inventory/reconciliation
+// @Summary Last inventory reconciliation (admin)
+// @Description Report of the last run comparing Postgres stock, Redis reservations and the search index, with its discrepancies
+// @Tags Admin
+// @Produce json
+// @Param limit query int false "Max discrepancies" default(100)
+// @Success 200 {object} map[string]interface{} "Report and discrepancies"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Failure 404 {object} map[string]string "Not run yet"
+// @Router /admin/inventory/reconciliation [get]
+func (h *InventoryHandler) GetReconciliation(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if limit < 1 || limit > 1000 {
+		limit = 100
+	}
+
+	report, discrepancies, err := h.reconcileService.GetLastReport(c.Request.Context(), limit)
+	if err != nil {
+		respondError(c, h.logger, "failed to get reconciliation report", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"report": report, "discrepancies": discrepancies})
+}
+
+// RunReconciliation handles POST /admin/inventory/reconciliation/run
+// @Summary Run inventory reconciliation now (admin)
+// @Description Enqueues a reconciliation job; poll GET /admin/inventory/reconciliation for the result
+// @Tags Admin
+// @Produce json
+// @Success 202 {object} jobs.Job "Enqueued job"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Router /admin/inventory/reconciliation/run [post]
+func (h *InventoryHandler) RunReconciliation(c *gin.Context) {
+	job, err := h.reconcileService.RunNow(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, "failed to enqueue reconciliation", err)
+		return
+	}
+
+	h.logger.Info("inventory reconciliation requested",
+		zap.String("job_id", job.ID),
+		zap.String("user_id", c.GetHeader("X-User-Id")),
+	)
+	c.JSON(http.StatusAccepted, job)
+}
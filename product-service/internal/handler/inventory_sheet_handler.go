@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"product-service/internal/service"
+	"product-service/pkg/xlsx"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// maxInventorySheetSize bounds the uploaded .xlsx file
+const maxInventorySheetSize = 10 << 20
+
+const xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// InventorySheetHandler handles HTTP requests for the seller inventory Excel export/import
+type InventorySheetHandler struct {
+	sheetService *service.InventorySheetService
+	logger       *zap.Logger
+}
+
+// NewInventorySheetHandler creates a new inventory sheet handler
+func NewInventorySheetHandler(sheetService *service.InventorySheetService, logger *zap.Logger) *InventorySheetHandler {
+	return &InventorySheetHandler{
+		sheetService: sheetService,
+		logger:       logger,
+	}
+}
+
+// ExportInventory handles GET /seller/inventory/export
+// @Summary Export inventory to Excel
+// @Description Download an .xlsx sheet of every SKU of the shop with its current price and stock. Edit Price and Stock and upload it to POST /seller/inventory/import
+// @Tags inventory
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param shop_id query int true "Shop ID"
+// @Success 200 {file} file "Inventory sheet"
+// @Failure 400 {object} map[string]string "Invalid shop_id"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /seller/inventory/export [get]
+func (h *InventorySheetHandler) ExportInventory(c *gin.Context) {
+	shopID, ok := inventoryShopID(c)
+	if !ok {
+		return
+	}
+
+	rows, err := h.sheetService.ExportRows(c.Request.Context(), shopID)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInventorySheet) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to export inventory", zap.Uint("shop_id", shopID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to export inventory"})
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := xlsx.Write(&buf, "Inventory", rows); err != nil {
+		h.logger.Error("failed to write inventory sheet", zap.Uint("shop_id", shopID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to export inventory"})
+		return
+	}
+
+	filename := fmt.Sprintf("inventory-shop-%d-%s.xlsx", shopID, time.Now().Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, xlsxContentType, buf.Bytes())
+}
+
+// ImportInventory handles POST /seller/inventory/import
+// @Summary Upload edited inventory sheet
+// @Description Validate an edited inventory sheet (multipart field "file") and return the diff. Nothing is changed until POST /seller/inventory/import/{id}/commit (within 30 minutes)
+// @Tags inventory
+// @Accept multipart/form-data
+// @Produce json
+// @Param shop_id query int true "Shop ID"
+// @Param file formData file true "Inventory sheet (.xlsx)"
+// @Success 200 {object} service.InventoryImportPreview
+// @Failure 400 {object} map[string]string "Invalid sheet"
+// @Failure 413 {object} map[string]string "File too large"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /seller/inventory/import [post]
+func (h *InventorySheetHandler) ImportInventory(c *gin.Context) {
+	shopID, ok := inventoryShopID(c)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxInventorySheetSize+1<<20)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if fileHeader.Size > maxInventorySheetSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file too large"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read file"})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maxInventorySheetSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read file"})
+		return
+	}
+
+	rows, err := xlsx.Read(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	preview, err := h.sheetService.PreviewImport(c.Request.Context(), shopID, rows)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidInventorySheet) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to preview inventory import", zap.Uint("shop_id", shopID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to import inventory"})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+// CommitInventoryImport handles POST /seller/inventory/import/:id/commit
+// @Summary Apply an inventory import
+// @Description Apply the previewed price and stock changes. SKUs changed since the preview are reported as conflicts and left untouched
+// @Tags inventory
+// @Produce json
+// @Param shop_id query int true "Shop ID"
+// @Param id path string true "Import ID"
+// @Success 200 {object} service.InventoryImportResult
+// @Failure 404 {object} map[string]string "Import not found or expired"
+// @Failure 422 {object} map[string]string "Import has row errors"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /seller/inventory/import/{id}/commit [post]
+func (h *InventorySheetHandler) CommitInventoryImport(c *gin.Context) {
+	shopID, ok := inventoryShopID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.sheetService.CommitImport(c.Request.Context(), shopID, c.Param("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInventoryImportNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrInventoryImportInvalid):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("failed to commit inventory import", zap.Uint("shop_id", shopID), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to import inventory"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// inventoryShopID reads the shop_id query parameter and answers 400 if it is invalid
+func inventoryShopID(c *gin.Context) (uint, bool) {
+	shopID, err := strconv.ParseUint(c.Query("shop_id"), 10, 32)
+	if err != nil || shopID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid shop_id"})
+		return 0, false
+	}
+	return uint(shopID), true
+}
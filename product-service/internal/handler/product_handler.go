@@ -6,6 +6,8 @@ import (
 	"product-service/internal/domain"
 	"product-service/internal/service"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -16,39 +18,81 @@ import (
 // This is the transport layer - it knows HOW to handle HTTP (Gin framework)
 // It delegates business logic to the service layer
 type ProductHandler struct {
-	productService *service.ProductService
-	logger         *zap.Logger
+	productService   *service.ProductService
+	sizeGuideService *service.SizeGuideService
+	logger           *zap.Logger
 }
 
 // NewProductHandler creates a new product handler
 // Dependency injection: we inject the service
-func NewProductHandler(productService *service.ProductService, logger *zap.Logger) *ProductHandler {
+func NewProductHandler(productService *service.ProductService, sizeGuideService *service.SizeGuideService, logger *zap.Logger) *ProductHandler {
 	return &ProductHandler{
-		productService: productService,
-		logger:         logger,
+		productService:   productService,
+		sizeGuideService: sizeGuideService,
+		logger:           logger,
 	}
 }
 
+// ProductDetailResponse is the product detail payload: the product and its size guide
+type ProductDetailResponse struct {
+	*domain.Product
+	SizeGuide *domain.SizeGuide `json:"size_guide,omitempty"` // Category chart and product measurements (fashion)
+}
+
+// productDetail adds the size guide to a product; without it if it cannot be loaded
+func (h *ProductHandler) productDetail(c *gin.Context, product *domain.Product) *ProductDetailResponse {
+	guide, err := h.sizeGuideService.GetSizeGuide(c.Request.Context(), product)
+	if err != nil {
+		h.logger.Warn("failed to load size guide", zap.Uint("product_id", product.ID), zap.Error(err))
+	}
+	return &ProductDetailResponse{Product: product, SizeGuide: guide}
+}
+
+// lastModified is the latest change of the product or of its size guide
+func (d *ProductDetailResponse) lastModified() time.Time {
+	t := d.UpdatedAt
+	if d.SizeGuide != nil {
+		if d.SizeGuide.Chart != nil {
+			t = latest(t, d.SizeGuide.Chart.UpdatedAt)
+		}
+		if d.SizeGuide.Measurements != nil {
+			t = latest(t, d.SizeGuide.Measurements.UpdatedAt)
+		}
+	}
+	return t
+}
+
 // CreateProductRequest represents the request body for creating a product
 type CreateProductRequest struct {
 	Name        string   `json:"name" binding:"required"`
+	Slug        string   `json:"slug" binding:"omitempty,slug"` // Optional - auto-generated from name if empty
 	Description string   `json:"description"`
 	BasePrice   float64  `json:"base_price" binding:"required,min=0"`
 	CategoryID  *uint    `json:"category_id,omitempty"` // Must be leaf category
 	Status      string   `json:"status"`
 	Images      []string `json:"images"`
 	IsActive    bool     `json:"is_active"`
+
+	MaxPurchaseQuantity int `json:"max_purchase_quantity" binding:"min=0"` // 0 = no limit
+
+	ProductType string `json:"product_type" binding:"omitempty,oneof=PHYSICAL DIGITAL"` // Default PHYSICAL; DIGITAL SKUs are sold from uploaded code pools
 }
 
 // UpdateProductRequest represents the request body for updating a product
 type UpdateProductRequest struct {
 	Name        string   `json:"name"`
+	Slug        string   `json:"slug" binding:"omitempty,slug"` // Optional - regenerated from name if name changes
 	Description string   `json:"description"`
 	BasePrice   float64  `json:"base_price" binding:"min=0"`
 	CategoryID  *uint    `json:"category_id,omitempty"`
 	Status      string   `json:"status"`
 	Images      []string `json:"images"`
 	IsActive    *bool    `json:"is_active"`
+	Version     *int     `json:"version,omitempty"` // Version the client last read; stale versions get 409
+
+	MaxPurchaseQuantity *int `json:"max_purchase_quantity,omitempty" binding:"omitempty,min=0"` // 0 removes the limit
+
+	ProductType string `json:"product_type" binding:"omitempty,oneof=PHYSICAL DIGITAL"`
 }
 
 // ProductResponse represents the product response for Swagger
@@ -56,6 +100,7 @@ type ProductResponse struct {
 	ID          uint     `json:"id"`
 	ShopID      uint     `json:"shop_id"`
 	Name        string   `json:"name"`
+	Slug        string   `json:"slug"`
 	Description string   `json:"description"`
 	BasePrice   float64  `json:"base_price"`
 	CategoryID  *uint    `json:"category_id,omitempty"`
@@ -63,6 +108,11 @@ type ProductResponse struct {
 	Images      []string `json:"images"`
 	IsActive    bool     `json:"is_active"`
 	SoldCount   int      `json:"sold_count"`
+
+	MaxPurchaseQuantity int `json:"max_purchase_quantity"` // 0 = no limit
+	ProductType string `json:"product_type"` // PHYSICAL, DIGITAL
+	RatingAvg   float64 `json:"rating_avg"`   // Average of published reviews (0 = no reviews)
+	RatingCount int     `json:"rating_count"`
 	CreatedAt   string   `json:"created_at"`
 	UpdatedAt   string   `json:"updated_at"`
 }
@@ -91,17 +141,10 @@ type ProductCategoryResponse struct {
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /products [post]
 func (h *ProductHandler) CreateProduct(c *gin.Context) {
-	// CRITICAL: Log immediately when handler is called - use Zap logger with Sync
-	h.logger.Info("🎯🎯🎯 HANDLER CreateProduct CALLED!",
-		zap.String("method", c.Request.Method),
-		zap.String("path", c.Request.URL.Path),
-	)
-	_ = h.logger.Sync() // Force flush
-
 	var req CreateProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Warn("invalid request body", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
@@ -111,6 +154,11 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 		status = "ACTIVE"
 	}
 
+	productType := req.ProductType
+	if productType == "" {
+		productType = domain.ProductTypePhysical
+	}
+
 	// Convert images []string to datatypes.JSON
 	var imagesJSON datatypes.JSON
 	if len(req.Images) > 0 {
@@ -127,31 +175,28 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 	product := &domain.Product{
 		ShopID:      1, // TODO: Lấy từ auth context
 		Name:        req.Name,
+		Slug:        req.Slug,
 		Description: req.Description,
 		BasePrice:   req.BasePrice,
 		CategoryID:  req.CategoryID,
 		Status:      status,
 		Images:      imagesJSON,
 		IsActive:    req.IsActive,
+
+		MaxPurchaseQuantity: req.MaxPurchaseQuantity,
+		ProductType:         productType,
 	}
 
 	// Call service layer (business logic)
-	h.logger.Info("🔵🔵🔵 Handler: Calling productService.CreateProduct",
-		zap.String("product_name", product.Name),
-	)
-	_ = h.logger.Sync()
 	if err := h.productService.CreateProduct(c.Request.Context(), product); err != nil {
-		h.logger.Error("❌❌❌ Handler: Failed to create product", zap.Error(err))
-		_ = h.logger.Sync()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to create product", err)
 		return
 	}
 
-	h.logger.Info("✅✅✅ Handler: Product created successfully",
+	h.logger.Debug("product created",
 		zap.Uint("product_id", product.ID),
 		zap.String("product_name", product.Name),
 	)
-	_ = h.logger.Sync()
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "product created successfully",
 		"product": product,
@@ -169,6 +214,7 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 // @Success 200 {object} map[string]interface{} "Product updated successfully"
 // @Failure 400 {object} map[string]string "Invalid request payload or product ID"
 // @Failure 404 {object} map[string]string "Product not found"
+// @Failure 409 {object} map[string]string "Product was modified by another request"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /products/{id} [put]
 func (h *ProductHandler) UpdateProduct(c *gin.Context) {
@@ -180,14 +226,14 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 
 	var req UpdateProductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	// Get existing product
 	product, err := h.productService.GetProduct(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "product not found"})
+		respondError(c, h.logger, "failed to get product", err)
 		return
 	}
 
@@ -195,6 +241,9 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 	if req.Name != "" {
 		product.Name = req.Name
 	}
+	if req.Slug != "" {
+		product.Slug = req.Slug
+	}
 	if req.Description != "" {
 		product.Description = req.Description
 	}
@@ -216,11 +265,22 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 	if req.IsActive != nil {
 		product.IsActive = *req.IsActive
 	}
+	if req.MaxPurchaseQuantity != nil {
+		product.MaxPurchaseQuantity = *req.MaxPurchaseQuantity
+	}
+	if req.ProductType != "" {
+		product.ProductType = req.ProductType
+	}
+
+	// The loaded product may come from cache, so only an explicit version is checked
+	product.Version = 0
+	if req.Version != nil {
+		product.Version = *req.Version
+	}
 
 	// Call service layer
 	if err := h.productService.UpdateProduct(c.Request.Context(), product); err != nil {
-		h.logger.Error("failed to update product", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to update product", err)
 		return
 	}
 
@@ -236,7 +296,7 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 // @Tags Products
 // @Produce json
 // @Param id path int true "Product ID"
-// @Success 200 {object} handler.ProductResponse "Product details"
+// @Success 200 {object} handler.ProductDetailResponse "Product details with size guide"
 // @Failure 400 {object} map[string]string "Invalid product ID"
 // @Failure 404 {object} map[string]string "Product not found"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -250,19 +310,91 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 
 	product, err := h.productService.GetProduct(c.Request.Context(), uint(id))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "product not found"})
+		respondError(c, h.logger, "failed to get product", err)
 		return
 	}
 
-	c.JSON(http.StatusOK, product)
+	detail := h.productDetail(c, product)
+	writeConditionalJSON(c, detail.lastModified(), detail)
+}
+
+// GetProductsBatch handles GET /products/batch
+// @Summary Get products by IDs
+// @Description Get up to 100 products in one request, in the order of ids (unknown IDs are skipped)
+// @Tags Products
+// @Produce json
+// @Param ids query string true "Comma-separated product IDs" example(1,2,3)
+// @Success 200 {object} map[string]interface{} "Products and count"
+// @Failure 400 {object} map[string]string "Invalid IDs"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /products/batch [get]
+func (h *ProductHandler) GetProductsBatch(c *gin.Context) {
+	idsParam := c.Query("ids")
+	if idsParam == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ids parameter is required"})
+		return
+	}
+
+	var ids []uint
+	for _, idStr := range splitByComma(idsParam) {
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id format: " + idStr})
+			return
+		}
+		ids = append(ids, uint(id))
+	}
+	if len(ids) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no valid ids provided"})
+		return
+	}
+
+	products, err := h.productService.GetProductsByIDs(c.Request.Context(), ids)
+	if err != nil {
+		respondError(c, h.logger, "failed to fetch products", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"products": products,
+		"count":    len(products),
+	})
+}
+
+// GetProductBySlug handles GET /products/slug/:slug
+// @Summary Get a product by slug
+// @Description Get a product by its URL slug. Old slugs (before rename) redirect to the current slug with 301
+// @Tags Products
+// @Produce json
+// @Param slug path string true "Product slug"
+// @Success 200 {object} handler.ProductDetailResponse "Product details with size guide"
+// @Success 301 {string} string "Redirect to current slug"
+// @Failure 404 {object} map[string]string "Product not found"
+// @Router /products/slug/{slug} [get]
+func (h *ProductHandler) GetProductBySlug(c *gin.Context) {
+	slug := c.Param("slug")
+
+	product, redirected, err := h.productService.GetProductBySlug(c.Request.Context(), slug)
+	if err != nil {
+		respondError(c, h.logger, "failed to get product", err)
+		return
+	}
+
+	// Old slug - redirect so clients/SEO pick up the new URL
+	if redirected {
+		c.Redirect(http.StatusMovedPermanently, "/api/v1/products/slug/"+product.Slug)
+		return
+	}
+
+	detail := h.productDetail(c, product)
+	writeConditionalJSON(c, detail.lastModified(), detail)
 }
 
 // GetAllProducts handles GET /products (deprecated - use ListProducts instead)
 func (h *ProductHandler) GetAllProducts(c *gin.Context) {
 	products, err := h.productService.GetAllProducts(c.Request.Context())
 	if err != nil {
-		h.logger.Error("failed to get all products", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to get all products", err)
 		return
 	}
 
@@ -271,7 +403,7 @@ func (h *ProductHandler) GetAllProducts(c *gin.Context) {
 
 // ListProducts handles GET /products with pagination and filters
 // @Summary List products with pagination and filters
-// @Description Get a paginated list of products with optional filters (category_id, status, min_price, max_price, search)
+// @Description Get a paginated list of products with optional filters (category_id, status, min_price, max_price, min_rating, search)
 // @Tags Products
 // @Produce json
 // @Param page query int false "Page number" default(1)
@@ -280,7 +412,9 @@ func (h *ProductHandler) GetAllProducts(c *gin.Context) {
 // @Param status query string false "Filter by status (ACTIVE, INACTIVE)"
 // @Param min_price query number false "Minimum price"
 // @Param max_price query number false "Maximum price"
+// @Param min_rating query number false "Minimum average rating (1-5)"
 // @Param search query string false "Search in name and description"
+// @Param include query string false "Preload relations: category,items"
 // @Success 200 {object} map[string]interface{} "List of products with pagination"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /products [get]
@@ -309,14 +443,22 @@ func (h *ProductHandler) ListProducts(c *gin.Context) {
 			filters["max_price"] = price
 		}
 	}
+	if minRating := c.Query("min_rating"); minRating != "" {
+		if rating, err := strconv.ParseFloat(minRating, 64); err == nil && rating > 0 {
+			filters["min_rating"] = rating
+		}
+	}
 	if search := c.Query("search"); search != "" {
 		filters["search"] = search
 	}
+	// User from API Gateway (used for feature flag rollout)
+	if userID, err := strconv.ParseUint(c.GetHeader("X-User-Id"), 10, 32); err == nil {
+		filters["user_id"] = uint(userID)
+	}
 
-	products, total, err := h.productService.ListProducts(c.Request.Context(), filters, page, limit)
+	products, total, err := h.productService.ListProducts(c.Request.Context(), filters, page, limit, parseIncludes(c)...)
 	if err != nil {
-		h.logger.Error("failed to list products", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to list products", err)
 		return
 	}
 
@@ -336,6 +478,7 @@ func (h *ProductHandler) ListProducts(c *gin.Context) {
 // @Param id path int true "Category ID"
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(20)
+// @Param include query string false "Preload relations: category,items"
 // @Success 200 {object} map[string]interface{} "List of products with pagination"
 // @Failure 400 {object} map[string]string "Invalid category ID"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -351,10 +494,9 @@ func (h *ProductHandler) GetProductsByCategory(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 
-	products, total, err := h.productService.GetProductsByCategory(c.Request.Context(), uint(categoryID), page, limit)
+	products, total, err := h.productService.GetProductsByCategory(c.Request.Context(), uint(categoryID), page, limit, parseIncludes(c)...)
 	if err != nil {
-		h.logger.Error("failed to get products by category", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to get products by category", err)
 		return
 	}
 
@@ -366,6 +508,32 @@ func (h *ProductHandler) GetProductsByCategory(c *gin.Context) {
 	})
 }
 
+// GetCategoryShops handles GET /categories/:id/shops
+// @Summary Get the shops selling in a category (internal)
+// @Description IDs of the shops with an active product in the category or its subcategories (order-service, admin announcements to the sellers of a category)
+// @Tags Products
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} map[string]interface{} "shop_ids"
+// @Failure 400 {object} map[string]string "Invalid category ID"
+// @Failure 404 {object} map[string]string "Category not found"
+// @Router /categories/{id}/shops [get]
+func (h *ProductHandler) GetCategoryShops(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category ID"})
+		return
+	}
+
+	shopIDs, err := h.productService.ShopIDsByCategory(c.Request.Context(), uint(categoryID))
+	if err != nil {
+		respondError(c, h.logger, "failed to get shops by category", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"shop_ids": shopIDs})
+}
+
 // SearchProducts handles GET /products/search
 // @Summary Search products using Elasticsearch
 // @Description Search products by keyword and optional category filter using Elasticsearch
@@ -387,8 +555,7 @@ func (h *ProductHandler) SearchProducts(c *gin.Context) {
 
 	products, err := h.productService.SearchProducts(c.Request.Context(), query, filters)
 	if err != nil {
-		h.logger.Error("failed to search products", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to search products", err)
 		return
 	}
 
@@ -418,3 +585,17 @@ func (h *ProductHandler) UpdateInventory(c *gin.Context) {
 		"message": "Inventory management has moved to SKU level. Use PATCH /api/v1/stock/:product_item_id instead",
 	})
 }
+
+// parseIncludes maps ?include=category,items to listing preload options
+func parseIncludes(c *gin.Context) []domain.ProductListOption {
+	var opts []domain.ProductListOption
+	for _, include := range strings.Split(c.Query("include"), ",") {
+		switch strings.TrimSpace(include) {
+		case "category":
+			opts = append(opts, domain.WithCategory())
+		case "items":
+			opts = append(opts, domain.WithItems())
+		}
+	}
+	return opts
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+	"product-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ProductImportHandler handles HTTP requests for the marketplace product import tool
+type ProductImportHandler struct {
+	importService *service.ProductImportService
+	logger        *zap.Logger
+}
+
+// NewProductImportHandler creates a new product import handler
+func NewProductImportHandler(importService *service.ProductImportService, logger *zap.Logger) *ProductImportHandler {
+	return &ProductImportHandler{
+		importService: importService,
+		logger:        logger,
+	}
+}
+
+// StartImport handles POST /tools/product-import
+// @Summary Import products from another platform's export
+// @Description Imports products, SKUs and images from a JSON export (inline products or export_url) in the background. Source categories and attributes are mapped with the import mapping file; poll GET /tools/product-import/{id} for progress
+// @Tags tools
+// @Accept json
+// @Produce json
+// @Param request body service.ProductImportRequest true "Export and target shop"
+// @Success 202 {object} service.ProductImport
+// @Failure 400 {object} map[string]string "Invalid import"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /tools/product-import [post]
+func (h *ProductImportHandler) StartImport(c *gin.Context) {
+	var req service.ProductImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	imp, err := h.importService.StartImport(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, h.logger, "failed to start product import", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, imp)
+}
+
+// GetImport handles GET /tools/product-import/:id
+// @Summary Get product import progress
+// @Description State, counters, imported product IDs and issues of an import (kept for 7 days)
+// @Tags tools
+// @Produce json
+// @Param id path string true "Import ID"
+// @Success 200 {object} service.ProductImport
+// @Failure 404 {object} map[string]string "Import not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /tools/product-import/{id} [get]
+func (h *ProductImportHandler) GetImport(c *gin.Context) {
+	imp, err := h.importService.GetImport(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, h.logger, "failed to get product import", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, imp)
+}
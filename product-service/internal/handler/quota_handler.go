@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"net/http"
+	"product-service/internal/domain"
+	"product-service/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// QuotaHandler handles admin HTTP requests for the seller API quotas
+type QuotaHandler struct {
+	quotaService *service.QuotaService
+	logger       *zap.Logger
+}
+
+// NewQuotaHandler creates a new quota handler
+func NewQuotaHandler(quotaService *service.QuotaService, logger *zap.Logger) *QuotaHandler {
+	return &QuotaHandler{
+		quotaService: quotaService,
+		logger:       logger,
+	}
+}
+
+// QuotaLimitRequest caps an operation per hour and per day; 0 means unlimited
+type QuotaLimitRequest struct {
+	Hourly int64 `json:"hourly" binding:"min=0" example:"100"`
+	Daily  int64 `json:"daily" binding:"min=0" example:"500"`
+}
+
+// SetTierLimitsRequest represents the request body for changing a tier's limits;
+// operations left out keep their limits
+type SetTierLimitsRequest struct {
+	ProductCreate *QuotaLimitRequest `json:"product_create"`
+	StockSync     *QuotaLimitRequest `json:"stock_sync"`
+}
+
+// SetSellerTierRequest represents the request body for moving a seller to another tier
+type SetSellerTierRequest struct {
+	Tier string `json:"tier" binding:"required" example:"premium"`
+}
+
+// ListTiers handles GET /admin/quotas/tiers
+// @Summary Seller quota tiers (admin)
+// @Description Hourly/daily limits of product_create and stock_sync per tier (0 = unlimited), with admin overrides applied
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Tiers"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Router /admin/quotas/tiers [get]
+func (h *QuotaHandler) ListTiers(c *gin.Context) {
+	tiers, err := h.quotaService.ListTiers(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, "failed to list quota tiers", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tiers": tiers})
+}
+
+// SetTierLimits handles PUT /admin/quotas/tiers/:tier
+// @Summary Change a quota tier's limits (admin)
+// @Description Overrides the configured limits until changed again; applies to every seller of the tier immediately
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param tier path string true "Tier name"
+// @Param request body SetTierLimitsRequest true "New limits"
+// @Success 200 {object} domain.QuotaTier "Updated tier"
+// @Failure 400 {object} map[string]string "Invalid limits"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Failure 404 {object} map[string]string "Unknown tier"
+// @Router /admin/quotas/tiers/{tier} [put]
+func (h *QuotaHandler) SetTierLimits(c *gin.Context) {
+	var req SetTierLimitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	limits := make(map[domain.QuotaOperation]domain.QuotaLimit, len(domain.QuotaOperations))
+	if req.ProductCreate != nil {
+		limits[domain.QuotaProductCreate] = domain.QuotaLimit{Hourly: req.ProductCreate.Hourly, Daily: req.ProductCreate.Daily}
+	}
+	if req.StockSync != nil {
+		limits[domain.QuotaStockSync] = domain.QuotaLimit{Hourly: req.StockSync.Hourly, Daily: req.StockSync.Daily}
+	}
+
+	tier, err := h.quotaService.SetTierLimits(c.Request.Context(), c.Param("tier"), limits)
+	if err != nil {
+		respondError(c, h.logger, "failed to set quota tier limits", err)
+		return
+	}
+
+	h.logger.Warn("quota tier limits changed",
+		zap.String("tier", tier.Name),
+		zap.Any("limits", tier.Limits),
+		zap.String("user_id", c.GetHeader("X-User-Id")),
+	)
+	c.JSON(http.StatusOK, tier)
+}
+
+// GetSellerQuota handles GET /admin/quotas/sellers/:seller
+// @Summary Seller quota usage (admin)
+// @Description Tier of a seller and its usage of the current hour and day
+// @Tags Admin
+// @Produce json
+// @Param seller path string true "Seller: shop:<id> or user:<id>"
+// @Success 200 {object} domain.SellerQuota "Tier and usage"
+// @Failure 400 {object} map[string]string "Invalid seller"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Router /admin/quotas/sellers/{seller} [get]
+func (h *QuotaHandler) GetSellerQuota(c *gin.Context) {
+	quota, err := h.quotaService.SellerQuota(c.Request.Context(), c.Param("seller"))
+	if err != nil {
+		respondError(c, h.logger, "failed to get seller quota", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, quota)
+}
+
+// SetSellerTier handles PUT /admin/quotas/sellers/:seller/tier
+// @Summary Move a seller to another quota tier (admin)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param seller path string true "Seller: shop:<id> or user:<id>"
+// @Param request body SetSellerTierRequest true "New tier"
+// @Success 200 {object} domain.SellerQuota "Tier and usage"
+// @Failure 400 {object} map[string]string "Invalid seller or tier"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Router /admin/quotas/sellers/{seller}/tier [put]
+func (h *QuotaHandler) SetSellerTier(c *gin.Context) {
+	var req SetSellerTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	seller := c.Param("seller")
+	if err := h.quotaService.SetSellerTier(c.Request.Context(), seller, req.Tier); err != nil {
+		respondError(c, h.logger, "failed to set seller quota tier", err)
+		return
+	}
+	h.logger.Info("seller quota tier changed",
+		zap.String("seller", seller),
+		zap.String("tier", req.Tier),
+		zap.String("user_id", c.GetHeader("X-User-Id")),
+	)
+
+	quota, err := h.quotaService.SellerQuota(c.Request.Context(), seller)
+	if err != nil {
+		respondError(c, h.logger, "failed to get seller quota", err)
+		return
+	}
+	c.JSON(http.StatusOK, quota)
+}
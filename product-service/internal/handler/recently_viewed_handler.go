@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"product-service/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RecentlyViewedHandler handles HTTP requests for the user's recently viewed products
+// The user comes from the X-User-Id header set by the API Gateway after JWT validation;
+// before login the history is kept for the guest identity in X-Guest-Id
+type RecentlyViewedHandler struct {
+	recentlyViewedService *service.RecentlyViewedService
+	logger                *zap.Logger
+}
+
+// NewRecentlyViewedHandler creates a new recently viewed handler
+func NewRecentlyViewedHandler(recentlyViewedService *service.RecentlyViewedService, logger *zap.Logger) *RecentlyViewedHandler {
+	return &RecentlyViewedHandler{
+		recentlyViewedService: recentlyViewedService,
+		logger:                logger,
+	}
+}
+
+// RecordViewRequest represents the request body for recording a product view
+type RecordViewRequest struct {
+	ProductID uint `json:"product_id" binding:"required" example:"1"`
+}
+
+// GetRecentlyViewed handles GET /users/me/recently-viewed
+// @Summary Get recently viewed products
+// @Description Products the current user (or guest) viewed, newest first (at most 50)
+// @Tags Users
+// @Produce json
+// @Param limit query int false "Max products" default(50)
+// @Success 200 {object} map[string]interface{} "Products and count"
+// @Failure 401 {object} map[string]string "Missing user or guest"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /users/me/recently-viewed [get]
+func (h *RecentlyViewedHandler) GetRecentlyViewed(c *gin.Context) {
+	viewer, ok := requireViewer(c)
+	if !ok {
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	products, err := h.recentlyViewedService.List(c.Request.Context(), viewer, limit)
+	if err != nil {
+		h.logger.Error("failed to list recently viewed products", zap.Uint("user_id", viewer.UserID), zap.String("guest_id", viewer.GuestID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load recently viewed products"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"products": products,
+		"count":    len(products),
+	})
+}
+
+// RecordView handles POST /users/me/recently-viewed
+// @Summary Record a product view
+// @Description Called by the product page; moves the product to the front of the list and emits a product_viewed event
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param request body RecordViewRequest true "Viewed product"
+// @Success 204 "View recorded"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Failure 401 {object} map[string]string "Missing user or guest"
+// @Failure 404 {object} map[string]string "Product not found"
+// @Router /users/me/recently-viewed [post]
+func (h *RecentlyViewedHandler) RecordView(c *gin.Context) {
+	viewer, ok := requireViewer(c)
+	if !ok {
+		return
+	}
+
+	var req RecordViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	if err := h.recentlyViewedService.RecordView(c.Request.Context(), viewer, req.ProductID); err != nil {
+		if errors.Is(err, service.ErrViewedProductNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to record product view", zap.Uint("user_id", viewer.UserID), zap.String("guest_id", viewer.GuestID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to record product view"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ClearRecentlyViewed handles DELETE /users/me/recently-viewed
+// @Summary Clear recently viewed products
+// @Tags Users
+// @Success 204 "History cleared"
+// @Failure 401 {object} map[string]string "Missing user or guest"
+// @Router /users/me/recently-viewed [delete]
+func (h *RecentlyViewedHandler) ClearRecentlyViewed(c *gin.Context) {
+	viewer, ok := requireViewer(c)
+	if !ok {
+		return
+	}
+
+	if err := h.recentlyViewedService.Clear(c.Request.Context(), viewer); err != nil {
+		h.logger.Error("failed to clear recently viewed products", zap.Uint("user_id", viewer.UserID), zap.String("guest_id", viewer.GuestID), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to clear recently viewed products"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// requireViewer reads the user, or the guest before login, set by the API Gateway;
+// responds 401 when neither is present
+func requireViewer(c *gin.Context) (service.Viewer, bool) {
+	if userID, err := strconv.ParseUint(c.GetHeader("X-User-Id"), 10, 32); err == nil && userID != 0 {
+		return service.Viewer{UserID: uint(userID)}, true
+	}
+	if guestID := c.GetHeader("X-Guest-Id"); guestID != "" {
+		return service.Viewer{GuestID: guestID}, true
+	}
+	c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+	return service.Viewer{}, false
+}
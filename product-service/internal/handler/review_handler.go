@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"net/http"
+	"product-service/internal/domain"
+	"product-service/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ReviewHandler handles HTTP requests for product reviews and product/shop ratings
+// Writes need the buyer in the X-User-Id header set by the API Gateway after JWT validation
+type ReviewHandler struct {
+	reviewService *service.ReviewService
+	logger        *zap.Logger
+}
+
+// NewReviewHandler creates a new review handler
+func NewReviewHandler(reviewService *service.ReviewService, logger *zap.Logger) *ReviewHandler {
+	return &ReviewHandler{
+		reviewService: reviewService,
+		logger:        logger,
+	}
+}
+
+// CreateReview handles POST /reviews
+// @Summary Review a product
+// @Description Rate (1-5) and comment a product the current user received in a delivered order (verified purchase). One review per product and buyer
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param request body service.CreateReviewRequest true "Review"
+// @Success 201 {object} domain.Review "Review created"
+// @Failure 400 {object} map[string]string "Invalid review"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Product not received by the user"
+// @Failure 404 {object} map[string]string "Product not found"
+// @Failure 409 {object} map[string]string "Product already reviewed"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /reviews [post]
+func (h *ReviewHandler) CreateReview(c *gin.Context) {
+	actor, ok := reviewActor(c)
+	if !ok {
+		return
+	}
+
+	var req service.CreateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	review, err := h.reviewService.Create(c.Request.Context(), actor, &req)
+	if err != nil {
+		respondError(c, h.logger, "failed to create review", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, review)
+}
+
+// UpdateReview handles PUT /reviews/:id
+// @Summary Edit a review
+// @Description Change the rating, comment and images of the current user's review
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param id path int true "Review ID"
+// @Param request body service.UpdateReviewRequest true "Review"
+// @Success 200 {object} domain.Review "Review updated"
+// @Failure 400 {object} map[string]string "Invalid review"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Not the author"
+// @Failure 404 {object} map[string]string "Review not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /reviews/{id} [put]
+func (h *ReviewHandler) UpdateReview(c *gin.Context) {
+	actor, ok := reviewActor(c)
+	if !ok {
+		return
+	}
+	id, ok := reviewID(c)
+	if !ok {
+		return
+	}
+
+	var req service.UpdateReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	review, err := h.reviewService.Update(c.Request.Context(), actor, id, &req)
+	if err != nil {
+		respondError(c, h.logger, "failed to update review", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, review)
+}
+
+// DeleteReview handles DELETE /reviews/:id
+// @Summary Delete a review
+// @Description Delete the current user's review (admins may delete any review)
+// @Tags reviews
+// @Produce json
+// @Param id path int true "Review ID"
+// @Success 200 {object} map[string]string "Review deleted"
+// @Failure 401 {object} map[string]string "User not authenticated"
+// @Failure 403 {object} map[string]string "Not the author"
+// @Failure 404 {object} map[string]string "Review not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /reviews/{id} [delete]
+func (h *ReviewHandler) DeleteReview(c *gin.Context) {
+	actor, ok := reviewActor(c)
+	if !ok {
+		return
+	}
+	id, ok := reviewID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.reviewService.Delete(c.Request.Context(), actor, id); err != nil {
+		respondError(c, h.logger, "failed to delete review", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "review deleted"})
+}
+
+// GetProductReviews handles GET /products/:id/reviews
+// @Summary Get the reviews of a product
+// @Description Paginated reviews of a product with its rating summary (average, count, reviews per star)
+// @Tags reviews
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param rating query int false "Only reviews with this many stars (1-5)"
+// @Param sort query string false "newest, rating_desc or rating_asc" default(newest)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 50)" default(10)
+// @Success 200 {object} service.ReviewPage "Reviews and rating summary"
+// @Failure 400 {object} map[string]string "Invalid product ID"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /products/{id}/reviews [get]
+func (h *ReviewHandler) GetProductReviews(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid product ID"})
+		return
+	}
+
+	page, err := h.reviewService.ListByProduct(c.Request.Context(), uint(productID), reviewFilter(c))
+	if err != nil {
+		respondError(c, h.logger, "failed to get product reviews", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetShopReviews handles GET /shops/:id/reviews
+// @Summary Get the reviews of a shop
+// @Description Paginated reviews of all products of a shop with the shop rating summary
+// @Tags shops
+// @Produce json
+// @Param id path int true "Shop ID"
+// @Param rating query int false "Only reviews with this many stars (1-5)"
+// @Param sort query string false "newest, rating_desc or rating_asc" default(newest)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page (max 50)" default(10)
+// @Success 200 {object} service.ReviewPage "Reviews and rating summary"
+// @Failure 400 {object} map[string]string "Invalid shop ID"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /shops/{id}/reviews [get]
+func (h *ReviewHandler) GetShopReviews(c *gin.Context) {
+	shopID, ok := parseShopID(c)
+	if !ok {
+		return
+	}
+
+	page, err := h.reviewService.ListByShop(c.Request.Context(), shopID, reviewFilter(c))
+	if err != nil {
+		respondError(c, h.logger, "failed to get shop reviews", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetShopRating handles GET /shops/:id/rating
+// @Summary Get the rating of a shop
+// @Description Rating aggregate of all reviews of the shop's products (average, count, reviews per star)
+// @Tags shops
+// @Produce json
+// @Param id path int true "Shop ID"
+// @Success 200 {object} domain.RatingSummary "Shop rating"
+// @Failure 400 {object} map[string]string "Invalid shop ID"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /shops/{id}/rating [get]
+func (h *ReviewHandler) GetShopRating(c *gin.Context) {
+	shopID, ok := parseShopID(c)
+	if !ok {
+		return
+	}
+
+	summary, err := h.reviewService.ShopRating(c.Request.Context(), shopID)
+	if err != nil {
+		respondError(c, h.logger, "failed to get shop rating", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// ListShopRatings handles GET /shops/ratings
+// @Summary List the ratings of all shops
+// @Description Rating aggregate (average, count) of every shop with at least one review. Called by identity-service for the admin shop export
+// @Tags shops
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Shop ratings"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /shops/ratings [get]
+func (h *ReviewHandler) ListShopRatings(c *gin.Context) {
+	ratings, err := h.reviewService.ShopRatings(c.Request.Context())
+	if err != nil {
+		respondError(c, h.logger, "failed to list shop ratings", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"shops": ratings})
+}
+
+// reviewActor reads the user and role set by API Gateway; responds 401 without a user
+func reviewActor(c *gin.Context) (service.ReviewActor, bool) {
+	userID, err := strconv.ParseUint(c.GetHeader("X-User-Id"), 10, 32)
+	if err != nil || userID == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return service.ReviewActor{}, false
+	}
+	return service.ReviewActor{UserID: uint(userID), Role: c.GetHeader("X-User-Role")}, true
+}
+
+// reviewID parses the :id path parameter; responds 400 if invalid
+func reviewID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid review ID"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// reviewFilter reads the star filter, sort order and page of a review listing
+func reviewFilter(c *gin.Context) domain.ReviewFilter {
+	rating, _ := strconv.Atoi(c.Query("rating"))
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	return domain.ReviewFilter{
+		Rating: rating,
+		Sort:   c.DefaultQuery("sort", domain.ReviewSortNewest),
+		Page:   page,
+		Limit:  limit,
+	}
+}
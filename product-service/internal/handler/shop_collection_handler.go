@@ -0,0 +1,288 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"product-service/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ShopCollectionHandler handles HTTP requests for seller-defined shop collections
+type ShopCollectionHandler struct {
+	collectionService *service.ShopCollectionService
+	logger            *zap.Logger
+}
+
+// NewShopCollectionHandler creates a new shop collection handler
+func NewShopCollectionHandler(collectionService *service.ShopCollectionService, logger *zap.Logger) *ShopCollectionHandler {
+	return &ShopCollectionHandler{
+		collectionService: collectionService,
+		logger:            logger,
+	}
+}
+
+// ListCollections handles GET /shops/:id/collections
+// @Summary List the collections of a shop
+// @Description Seller-defined collections in storefront navigation order with their active product counts. Hidden collections are only included with include_hidden=true (seller view)
+// @Tags shop-collections
+// @Produce json
+// @Param id path int true "Shop ID"
+// @Param include_hidden query bool false "Include hidden collections"
+// @Success 200 {object} map[string]interface{} "Collections and count"
+// @Failure 400 {object} map[string]string "Invalid shop ID"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /shops/{id}/collections [get]
+func (h *ShopCollectionHandler) ListCollections(c *gin.Context) {
+	shopID, ok := parseShopID(c)
+	if !ok {
+		return
+	}
+
+	includeHidden := c.Query("include_hidden") == "true"
+	collections, err := h.collectionService.ListCollections(c.Request.Context(), shopID, includeHidden)
+	if err != nil {
+		h.respondError(c, "failed to list shop collections", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"collections": collections,
+		"count":       len(collections),
+	})
+}
+
+// CreateCollection handles POST /shops/:id/collections
+// @Summary Create a shop collection (seller)
+// @Description New collections are appended to the end of the navigation
+// @Tags shop-collections
+// @Accept json
+// @Produce json
+// @Param id path int true "Shop ID"
+// @Param request body service.ShopCollectionRequest true "Collection"
+// @Success 201 {object} domain.ShopCollection
+// @Failure 400 {object} map[string]string "Invalid collection"
+// @Failure 422 {object} map[string]string "Collection limit reached"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /shops/{id}/collections [post]
+func (h *ShopCollectionHandler) CreateCollection(c *gin.Context) {
+	shopID, ok := parseShopID(c)
+	if !ok {
+		return
+	}
+
+	var req service.ShopCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	collection, err := h.collectionService.CreateCollection(c.Request.Context(), shopID, &req)
+	if err != nil {
+		h.respondError(c, "failed to create shop collection", err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, collection)
+}
+
+// UpdateCollection handles PUT /shops/:id/collections/:collection_id
+// @Summary Update a shop collection (seller)
+// @Tags shop-collections
+// @Accept json
+// @Produce json
+// @Param id path int true "Shop ID"
+// @Param collection_id path int true "Collection ID"
+// @Param request body service.ShopCollectionRequest true "Collection"
+// @Success 200 {object} domain.ShopCollection
+// @Failure 400 {object} map[string]string "Invalid collection"
+// @Failure 404 {object} map[string]string "Collection not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /shops/{id}/collections/{collection_id} [put]
+func (h *ShopCollectionHandler) UpdateCollection(c *gin.Context) {
+	shopID, collectionID, ok := parseCollectionIDs(c)
+	if !ok {
+		return
+	}
+
+	var req service.ShopCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	collection, err := h.collectionService.UpdateCollection(c.Request.Context(), shopID, collectionID, &req)
+	if err != nil {
+		h.respondError(c, "failed to update shop collection", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, collection)
+}
+
+// DeleteCollection handles DELETE /shops/:id/collections/:collection_id
+// @Summary Delete a shop collection (seller)
+// @Description The collection's products stay in the shop
+// @Tags shop-collections
+// @Param id path int true "Shop ID"
+// @Param collection_id path int true "Collection ID"
+// @Success 204 "Collection deleted"
+// @Failure 400 {object} map[string]string "Invalid ID"
+// @Failure 404 {object} map[string]string "Collection not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /shops/{id}/collections/{collection_id} [delete]
+func (h *ShopCollectionHandler) DeleteCollection(c *gin.Context) {
+	shopID, collectionID, ok := parseCollectionIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.collectionService.DeleteCollection(c.Request.Context(), shopID, collectionID); err != nil {
+		h.respondError(c, "failed to delete shop collection", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ReorderCollections handles PUT /shops/:id/collections/order
+// @Summary Reorder the collections of a shop (seller)
+// @Description collection_ids must list every collection of the shop exactly once, in the new order
+// @Tags shop-collections
+// @Accept json
+// @Produce json
+// @Param id path int true "Shop ID"
+// @Param request body service.ReorderCollectionsRequest true "Collection order"
+// @Success 200 {object} map[string]interface{} "Collections in the new order"
+// @Failure 400 {object} map[string]string "Invalid order"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /shops/{id}/collections/order [put]
+func (h *ShopCollectionHandler) ReorderCollections(c *gin.Context) {
+	shopID, ok := parseShopID(c)
+	if !ok {
+		return
+	}
+
+	var req service.ReorderCollectionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	collections, err := h.collectionService.ReorderCollections(c.Request.Context(), shopID, &req)
+	if err != nil {
+		h.respondError(c, "failed to reorder shop collections", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"collections": collections,
+		"count":       len(collections),
+	})
+}
+
+// SetCollectionProducts handles PUT /shops/:id/collections/:collection_id/products
+// @Summary Assign products to a shop collection (seller)
+// @Description Replaces the collection's products; product_ids are shown in the given order and must belong to the shop
+// @Tags shop-collections
+// @Accept json
+// @Produce json
+// @Param id path int true "Shop ID"
+// @Param collection_id path int true "Collection ID"
+// @Param request body service.SetCollectionProductsRequest true "Products in display order"
+// @Success 200 {object} map[string]string "Products saved"
+// @Failure 400 {object} map[string]string "Invalid products"
+// @Failure 404 {object} map[string]string "Collection not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /shops/{id}/collections/{collection_id}/products [put]
+func (h *ShopCollectionHandler) SetCollectionProducts(c *gin.Context) {
+	shopID, collectionID, ok := parseCollectionIDs(c)
+	if !ok {
+		return
+	}
+
+	var req service.SetCollectionProductsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	if err := h.collectionService.SetCollectionProducts(c.Request.Context(), shopID, collectionID, &req); err != nil {
+		h.respondError(c, "failed to save collection products", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "collection products saved"})
+}
+
+// GetCollectionProducts handles GET /shops/:id/collections/:collection_id/products
+// @Summary Get the products of a shop collection
+// @Description Active products of a visible collection, in the seller's order
+// @Tags shop-collections
+// @Produce json
+// @Param id path int true "Shop ID"
+// @Param collection_id path int true "Collection ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{} "Products with pagination"
+// @Failure 400 {object} map[string]string "Invalid ID"
+// @Failure 404 {object} map[string]string "Collection not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /shops/{id}/collections/{collection_id}/products [get]
+func (h *ShopCollectionHandler) GetCollectionProducts(c *gin.Context) {
+	shopID, collectionID, ok := parseCollectionIDs(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	products, total, err := h.collectionService.GetCollectionProducts(c.Request.Context(), shopID, collectionID, page, limit)
+	if err != nil {
+		h.respondError(c, "failed to get collection products", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"products": products,
+		"total":    total,
+		"page":     page,
+		"limit":    limit,
+	})
+}
+
+// respondError answers 422 when the shop has too many collections, other errors by their kind
+func (h *ShopCollectionHandler) respondError(c *gin.Context, msg string, err error) {
+	if errors.Is(err, service.ErrCollectionLimit) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	respondError(c, h.logger, msg, err)
+}
+
+// parseShopID reads the :id shop path parameter and answers 400 if it is invalid
+func parseShopID(c *gin.Context) (uint, bool) {
+	shopID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid shop ID"})
+		return 0, false
+	}
+	return uint(shopID), true
+}
+
+// parseCollectionIDs reads the :id shop and :collection_id path parameters
+func parseCollectionIDs(c *gin.Context) (uint, uint, bool) {
+	shopID, ok := parseShopID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	collectionID, err := strconv.ParseUint(c.Param("collection_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid collection ID"})
+		return 0, 0, false
+	}
+	return shopID, uint(collectionID), true
+}
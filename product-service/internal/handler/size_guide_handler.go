@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"net/http"
+	"product-service/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SizeGuideHandler handles HTTP requests for category size charts and product measurements
+type SizeGuideHandler struct {
+	sizeGuideService *service.SizeGuideService
+	productService   *service.ProductService
+	logger           *zap.Logger
+}
+
+// NewSizeGuideHandler creates a new size guide handler
+func NewSizeGuideHandler(sizeGuideService *service.SizeGuideService, productService *service.ProductService, logger *zap.Logger) *SizeGuideHandler {
+	return &SizeGuideHandler{
+		sizeGuideService: sizeGuideService,
+		productService:   productService,
+		logger:           logger,
+	}
+}
+
+// SetCategorySizeChart handles PUT /categories/:id/size-chart
+// @Summary Create or replace a category size chart
+// @Description Size chart template for a category (and its subcategories without their own chart): measurement columns and reference values per size
+// @Tags size-guide
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param chart body service.SizeChartRequest true "Size chart"
+// @Success 200 {object} domain.SizeChart
+// @Failure 400 {object} map[string]string "Invalid size chart"
+// @Failure 404 {object} map[string]string "Category not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /categories/{id}/size-chart [put]
+func (h *SizeGuideHandler) SetCategorySizeChart(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category_id"})
+		return
+	}
+
+	var req service.SizeChartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	chart, err := h.sizeGuideService.SetCategoryChart(c.Request.Context(), uint(categoryID), &req)
+	if err != nil {
+		respondError(c, h.logger, "failed to save size chart", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, chart)
+}
+
+// GetCategorySizeChart handles GET /categories/:id/size-chart
+// @Summary Get the size chart of a category
+// @Description Returns the category's own chart or, if it has none, the nearest parent category's
+// @Tags size-guide
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} domain.SizeChart
+// @Failure 400 {object} map[string]string "Invalid category ID"
+// @Failure 404 {object} map[string]string "No size chart"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /categories/{id}/size-chart [get]
+func (h *SizeGuideHandler) GetCategorySizeChart(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category_id"})
+		return
+	}
+
+	chart, err := h.sizeGuideService.GetCategoryChart(c.Request.Context(), uint(categoryID))
+	if err != nil {
+		respondError(c, h.logger, "failed to get size chart", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, chart)
+}
+
+// DeleteCategorySizeChart handles DELETE /categories/:id/size-chart
+// @Summary Delete a category size chart
+// @Tags size-guide
+// @Param id path int true "Category ID"
+// @Success 204 "Size chart deleted"
+// @Failure 400 {object} map[string]string "Invalid category ID"
+// @Failure 404 {object} map[string]string "No size chart"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /categories/{id}/size-chart [delete]
+func (h *SizeGuideHandler) DeleteCategorySizeChart(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category_id"})
+		return
+	}
+
+	if err := h.sizeGuideService.DeleteCategoryChart(c.Request.Context(), uint(categoryID)); err != nil {
+		respondError(c, h.logger, "failed to delete size chart", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetProductSizeGuide handles GET /products/:id/size-guide
+// @Summary Get the size guide of a product
+// @Description Category size chart and the seller's measurements of the product (also included in product detail)
+// @Tags size-guide
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {object} domain.SizeGuide
+// @Failure 400 {object} map[string]string "Invalid product ID"
+// @Failure 404 {object} map[string]string "Product not found or no size guide"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /products/{id}/size-guide [get]
+func (h *SizeGuideHandler) GetProductSizeGuide(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid product ID"})
+		return
+	}
+
+	product, err := h.productService.GetProduct(c.Request.Context(), uint(productID))
+	if err != nil {
+		respondError(c, h.logger, "failed to get product", err)
+		return
+	}
+
+	guide, err := h.sizeGuideService.GetSizeGuide(c.Request.Context(), product)
+	if err != nil {
+		respondError(c, h.logger, "failed to get size guide", err)
+		return
+	}
+	if guide == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "product has no size guide"})
+		return
+	}
+
+	c.JSON(http.StatusOK, guide)
+}
+
+// SetProductMeasurements handles PUT /products/:id/measurements
+// @Summary Set the measurements of a product (seller)
+// @Description Replaces the product's measurement table; if its category has a size chart only the chart's measurements are accepted
+// @Tags size-guide
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param measurements body service.ProductMeasurementRequest true "Measurements per size"
+// @Success 200 {object} domain.ProductMeasurement
+// @Failure 400 {object} map[string]string "Invalid measurements"
+// @Failure 404 {object} map[string]string "Product not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /products/{id}/measurements [put]
+func (h *SizeGuideHandler) SetProductMeasurements(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid product ID"})
+		return
+	}
+
+	var req service.ProductMeasurementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	m, err := h.sizeGuideService.SetProductMeasurements(c.Request.Context(), uint(productID), &req)
+	if err != nil {
+		respondError(c, h.logger, "failed to save product measurements", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, m)
+}
+
+// DeleteProductMeasurements handles DELETE /products/:id/measurements
+// @Summary Delete the measurements of a product (seller)
+// @Tags size-guide
+// @Param id path int true "Product ID"
+// @Success 204 "Measurements deleted"
+// @Failure 400 {object} map[string]string "Invalid product ID"
+// @Failure 404 {object} map[string]string "No measurements"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /products/{id}/measurements [delete]
+func (h *SizeGuideHandler) DeleteProductMeasurements(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid product ID"})
+		return
+	}
+
+	if err := h.sizeGuideService.DeleteProductMeasurements(c.Request.Context(), uint(productID)); err != nil {
+		respondError(c, h.logger, "failed to delete product measurements", err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -56,17 +56,16 @@ func (h *SKUHandler) CreateProductItem(c *gin.Context) {
 
 	var req service.CreateProductItemRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	// Set product_id from path
 	req.ProductID = uint(productID)
 
-	item, err := h.productItemService.CreateProductItem(&req)
+	item, err := h.productItemService.CreateProductItem(c.Request.Context(), &req)
 	if err != nil {
-		h.logger.Error("failed to create product item", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to create product item", err)
 		return
 	}
 
@@ -90,10 +89,9 @@ func (h *SKUHandler) GetProductItems(c *gin.Context) {
 		return
 	}
 
-	items, err := h.productItemService.GetProductItemsWithVariations(uint(productID))
+	items, err := h.productItemService.GetProductItemsWithVariations(c.Request.Context(), uint(productID))
 	if err != nil {
-		h.logger.Error("failed to get product items", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get product items"})
+		respondError(c, h.logger, "failed to get product items", err)
 		return
 	}
 
@@ -122,9 +120,9 @@ func (h *SKUHandler) GetProductItem(c *gin.Context) {
 		return
 	}
 
-	item, err := h.productItemService.GetProductItem(uint(itemID))
+	item, err := h.productItemService.GetProductItem(c.Request.Context(), uint(itemID))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to get product item", err)
 		return
 	}
 
@@ -144,9 +142,9 @@ func (h *SKUHandler) GetProductItem(c *gin.Context) {
 func (h *SKUHandler) GetProductItemBySKU(c *gin.Context) {
 	skuCode := c.Param("sku_code")
 
-	item, err := h.productItemService.GetProductItemBySKU(skuCode)
+	item, err := h.productItemService.GetProductItemBySKU(c.Request.Context(), skuCode)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to get product item", err)
 		return
 	}
 
@@ -188,10 +186,9 @@ func (h *SKUHandler) GetProductItemsBatch(c *gin.Context) {
 	}
 
 	// Fetch items with product details
-	items, err := h.productItemService.GetProductItemsWithProduct(ids)
+	items, err := h.productItemService.GetProductItemsWithProduct(c.Request.Context(), ids)
 	if err != nil {
-		h.logger.Error("failed to get product items batch", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch product items"})
+		respondError(c, h.logger, "failed to fetch product items", err)
 		return
 	}
 
@@ -233,6 +230,7 @@ func splitByComma(s string) []string {
 // @Success 200 {object} domain.ProductItem
 // @Failure 400 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /products/{product_id}/items/{item_id} [put]
 func (h *SKUHandler) UpdateProductItem(c *gin.Context) {
@@ -244,14 +242,13 @@ func (h *SKUHandler) UpdateProductItem(c *gin.Context) {
 
 	var req service.UpdateProductItemRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
-	item, err := h.productItemService.UpdateProductItem(uint(itemID), &req)
+	item, err := h.productItemService.UpdateProductItem(c.Request.Context(), uint(itemID), &req)
 	if err != nil {
-		h.logger.Error("failed to update product item", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to update product item", err)
 		return
 	}
 
@@ -260,13 +257,15 @@ func (h *SKUHandler) UpdateProductItem(c *gin.Context) {
 
 // DeleteProductItem godoc
 // @Summary Delete a SKU
-// @Description Delete product item (SKU) and its configurations
+// @Description Soft-delete product item (SKU): it is marked DISCONTINUED and hidden, and can be restored. Rejected while open orders contain it
 // @Tags skus
 // @Produce json
 // @Param product_id path int true "Product ID"
 // @Param item_id path int true "Product Item ID"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /products/{product_id}/items/{item_id} [delete]
 func (h *SKUHandler) DeleteProductItem(c *gin.Context) {
@@ -276,11 +275,39 @@ func (h *SKUHandler) DeleteProductItem(c *gin.Context) {
 		return
 	}
 
-	if err := h.productItemService.DeleteProductItem(uint(itemID)); err != nil {
-		h.logger.Error("failed to delete product item", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete product item"})
+	if err := h.productItemService.DeleteProductItem(c.Request.Context(), uint(itemID)); err != nil {
+		respondError(c, h.logger, "failed to delete product item", err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "product item deleted successfully"})
 }
+
+// RestoreProductItem godoc
+// @Summary Restore a deleted SKU
+// @Description Undo a SKU soft delete; status becomes ACTIVE or OUT_OF_STOCK depending on stock
+// @Tags skus
+// @Produce json
+// @Param product_id path int true "Product ID"
+// @Param item_id path int true "Product Item ID"
+// @Success 200 {object} domain.ProductItem
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /products/{product_id}/items/{item_id}/restore [post]
+func (h *SKUHandler) RestoreProductItem(c *gin.Context) {
+	itemID, err := strconv.ParseUint(c.Param("item_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid item_id"})
+		return
+	}
+
+	item, err := h.productItemService.RestoreProductItem(c.Request.Context(), uint(itemID))
+	if err != nil {
+		respondError(c, h.logger, "failed to restore product item", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
@@ -26,7 +26,7 @@ func NewStockHandler(stockService *service.StockService, logger *zap.Logger) *St
 
 // GetStock godoc
 // @Summary Get stock for a product item
-// @Description Get current stock quantity for a product item (SKU)
+// @Description Get on-hand, reserved and available stock for a product item (SKU)
 // @Tags stock
 // @Produce json
 // @Param id path int true "Product Item ID"
@@ -43,14 +43,20 @@ func (h *StockHandler) GetStock(c *gin.Context) {
 
 	stock, err := h.stockService.GetStock(c.Request.Context(), uint(productItemID))
 	if err != nil {
-		h.logger.Error("failed to get stock", zap.Error(err))
-		c.JSON(http.StatusNotFound, gin.H{"error": "product item not found"})
+		respondError(c, h.logger, "failed to get stock", err)
 		return
 	}
 
+	reserved, err := h.stockService.GetReservedStock(c.Request.Context(), uint(productItemID))
+	if err != nil {
+		h.logger.Warn("failed to get reserved stock", zap.Error(err))
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"product_item_id": productItemID,
 		"stock":           stock,
+		"reserved":        reserved,
+		"available":       max(stock-reserved, 0),
 	})
 }
 
@@ -67,14 +73,13 @@ func (h *StockHandler) GetStock(c *gin.Context) {
 func (h *StockHandler) CheckStock(c *gin.Context) {
 	var req domain.StockCheckRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	resp, err := h.stockService.CheckStock(c.Request.Context(), &req)
 	if err != nil {
-		h.logger.Error("failed to check stock", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check stock"})
+		respondError(c, h.logger, "failed to check stock", err)
 		return
 	}
 
@@ -95,13 +100,12 @@ func (h *StockHandler) CheckStock(c *gin.Context) {
 func (h *StockHandler) ReserveStock(c *gin.Context) {
 	var req domain.StockReserveRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	if err := h.stockService.ReserveStock(c.Request.Context(), &req); err != nil {
-		h.logger.Error("failed to reserve stock", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to reserve stock", err)
 		return
 	}
 
@@ -125,13 +129,12 @@ func (h *StockHandler) ReserveStock(c *gin.Context) {
 func (h *StockHandler) DeductStock(c *gin.Context) {
 	var req domain.StockDeductRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	if err := h.stockService.DeductStock(c.Request.Context(), &req); err != nil {
-		h.logger.Error("failed to deduct stock", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to deduct stock", err)
 		return
 	}
 
@@ -155,13 +158,12 @@ func (h *StockHandler) DeductStock(c *gin.Context) {
 func (h *StockHandler) ReleaseStock(c *gin.Context) {
 	var req domain.StockReleaseRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	if err := h.stockService.ReleaseStock(c.Request.Context(), &req); err != nil {
-		h.logger.Error("failed to release stock", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to release stock"})
+		respondError(c, h.logger, "failed to release stock", err)
 		return
 	}
 
@@ -181,6 +183,7 @@ func (h *StockHandler) ReleaseStock(c *gin.Context) {
 // @Param request body map[string]int true "Stock update request {new_stock: 100}"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /product-items/{id}/stock [put]
 func (h *StockHandler) UpdateStock(c *gin.Context) {
@@ -194,13 +197,12 @@ func (h *StockHandler) UpdateStock(c *gin.Context) {
 		NewStock int `json:"new_stock" binding:"required,min=0"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
 		return
 	}
 
 	if err := h.stockService.UpdateStock(c.Request.Context(), uint(productItemID), req.NewStock); err != nil {
-		h.logger.Error("failed to update stock", zap.Error(err))
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondError(c, h.logger, "failed to update stock", err)
 		return
 	}
 
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"product-service/internal/domain"
+	"product-service/pkg/taskqueue"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// TaskHandler handles admin HTTP requests for the async side effect pool and
+// the event publisher it feeds
+type TaskHandler struct {
+	pool      *taskqueue.Pool
+	publisher domain.BufferedEventPublisher
+	outbox    OutboxStatsProvider
+	logger    *zap.Logger
+}
+
+// OutboxStatsProvider reports the product event outbox (implemented by service.OutboxRelay)
+type OutboxStatsProvider interface {
+	Stats(ctx context.Context) domain.OutboxStats
+}
+
+// NewTaskHandler creates a new task handler
+func NewTaskHandler(pool *taskqueue.Pool, publisher domain.BufferedEventPublisher, outbox OutboxStatsProvider, logger *zap.Logger) *TaskHandler {
+	return &TaskHandler{
+		pool:      pool,
+		publisher: publisher,
+		outbox:    outbox,
+		logger:    logger,
+	}
+}
+
+// GetStats handles GET /admin/tasks/product
+// @Summary Get async task pool stats (admin)
+// @Description Worker/queue sizes and counters for cache, index and publish side effects
+// @Tags Jobs
+// @Produce json
+// @Success 200 {object} taskqueue.Stats "Pool stats"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Router /admin/tasks/product [get]
+func (h *TaskHandler) GetStats(c *gin.Context) {
+	c.JSON(http.StatusOK, h.pool.Stats())
+}
+
+// GetEventStats handles GET /admin/events/product
+// @Summary Get Kafka event publisher stats (admin)
+// @Description Published/retried/buffered counters, buffer length and lag of the oldest buffered event, and the outbox backlog (events committed but not relayed yet)
+// @Tags Jobs
+// @Produce json
+// @Success 200 {object} domain.EventPublisherStats "Publisher stats"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Router /admin/events/product [get]
+func (h *TaskHandler) GetEventStats(c *gin.Context) {
+	stats := h.publisher.Stats(c.Request.Context())
+	outbox := h.outbox.Stats(c.Request.Context())
+	stats.Outbox = &outbox
+	c.JSON(http.StatusOK, stats)
+}
+
+// FlushEvents handles POST /admin/events/product/flush
+// @Summary Redeliver buffered Kafka events now (admin)
+// @Description Writes buffered events in order until the buffer is empty or Kafka fails again
+// @Tags Jobs
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Flushed count and publisher stats"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Failure 502 {object} map[string]interface{} "Kafka still failing"
+// @Router /admin/events/product/flush [post]
+func (h *TaskHandler) FlushEvents(c *gin.Context) {
+	flushed, err := h.publisher.Flush(c.Request.Context())
+	if err != nil {
+		h.logger.Warn("manual event flush failed", zap.Int("flushed", flushed), zap.Error(err))
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error(), "flushed": flushed})
+		return
+	}
+
+	h.logger.Info("manual event flush",
+		zap.Int("flushed", flushed),
+		zap.String("user_id", c.GetHeader("X-User-Id")),
+	)
+	c.JSON(http.StatusOK, gin.H{"flushed": flushed, "stats": h.publisher.Stats(c.Request.Context())})
+}
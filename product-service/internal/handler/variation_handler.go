@@ -54,16 +54,16 @@ func (h *VariationHandler) GetProductVariations(c *gin.Context) {
 	}
 
 	// Get all variations for product
-	variations, err := h.variationRepo.GetByProductID(uint(productID))
+	variations, err := h.variationRepo.GetByProductID(c.Request.Context(), uint(productID))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get variations"})
+		respondError(c, h.logger, "failed to get variations", err)
 		return
 	}
 
 	// Build response with options
 	var response []VariationWithOptions
 	for _, v := range variations {
-		options, err := h.variationOptRepo.GetByVariationID(v.ID)
+		options, err := h.variationOptRepo.GetByVariationID(c.Request.Context(), v.ID)
 		if err != nil {
 			h.logger.Error("Failed to get variation options",
 				zap.Uint("variation_id", v.ID),
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"product-service/internal/domain"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// QuotaConsumer meters a seller request against its quota (service.QuotaService)
+type QuotaConsumer interface {
+	Consume(ctx context.Context, seller string, op domain.QuotaOperation) (*domain.QuotaDecision, error)
+}
+
+// Quota enforces the hourly/daily quota of a seller write API. The seller is the
+// rate limit subject (X-Shop-Id, X-User-Id, client IP); every response carries
+// X-Quota-Tier and X-Quota-{Hourly,Daily}-{Limit,Remaining} for limited windows,
+// requests over the quota get 429 with Retry-After. Redis errors fail open.
+// A nil consumer (quota.enabled false) disables the check
+func Quota(consumer QuotaConsumer, op domain.QuotaOperation, logger *zap.Logger) gin.HandlerFunc {
+	if consumer == nil {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	return func(c *gin.Context) {
+		seller := rateLimitSubject(c)
+		decision, err := consumer.Consume(c.Request.Context(), seller, op)
+		if err != nil {
+			logger.Warn("quota check failed, allowing request", zap.String("seller", seller), zap.String("operation", string(op)), zap.Error(err))
+			c.Next()
+			return
+		}
+
+		c.Header("X-Quota-Tier", decision.Tier)
+		setQuotaHeaders(c, "Hourly", decision.Limit.Hourly, decision.HourlyUsed)
+		setQuotaHeaders(c, "Daily", decision.Limit.Daily, decision.DailyUsed)
+
+		if !decision.Allowed {
+			// Wait for the window that is used up (the day when both are)
+			reset := decision.HourlyReset
+			if decision.Limit.Daily > 0 && decision.DailyUsed >= decision.Limit.Daily {
+				reset = decision.DailyReset
+			}
+			retryAfter := int(time.Until(reset).Seconds()) + 1
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			logger.Warn("seller quota exceeded",
+				zap.String("seller", seller),
+				zap.String("tier", decision.Tier),
+				zap.String("operation", string(op)),
+				zap.Int64("hourly_used", decision.HourlyUsed),
+				zap.Int64("daily_used", decision.DailyUsed),
+			)
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":       "quota exceeded for " + string(op) + ", upgrade the shop tier or retry later",
+				"tier":        decision.Tier,
+				"limit":       decision.Limit,
+				"retry_after": retryAfter,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// setQuotaHeaders reports one window of the quota; unlimited windows are left out
+func setQuotaHeaders(c *gin.Context, window string, limit, used int64) {
+	if limit <= 0 {
+		return
+	}
+	c.Header("X-Quota-"+window+"-Limit", strconv.FormatInt(limit, 10))
+	c.Header("X-Quota-"+window+"-Remaining", strconv.FormatInt(max(limit-used, 0), 10))
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"product-service/config"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const rateLimitWindow = time.Minute
+
+// WriteRateLimit limits write requests per shop with a fixed one-minute window in Redis
+// (shared by all product-service instances, independent of the gateway limiter)
+//
+// The shop is taken from X-Shop-Id, falling back to X-User-Id (each seller owns one shop)
+// and finally the client IP. Every response carries X-RateLimit-Limit/Remaining/Reset;
+// rejected requests get 429 with Retry-After. Redis errors fail open.
+func WriteRateLimit(cfg *config.RateLimitConfig, client *redis.Client, logger *zap.Logger) gin.HandlerFunc {
+	if !cfg.Enabled || cfg.WriteRequestsPerMinute <= 0 {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	limit := cfg.WriteRequestsPerMinute
+
+	return func(c *gin.Context) {
+		subject := rateLimitSubject(c)
+		now := time.Now()
+		windowStart := now.Truncate(rateLimitWindow)
+		reset := windowStart.Add(rateLimitWindow)
+		key := fmt.Sprintf("ratelimit:write:%s:%d", subject, windowStart.Unix())
+
+		ctx := c.Request.Context()
+		pipe := client.TxPipeline()
+		incr := pipe.Incr(ctx, key)
+		pipe.ExpireNX(ctx, key, rateLimitWindow+5*time.Second)
+		if _, err := pipe.Exec(ctx); err != nil {
+			logger.Warn("rate limit check failed, allowing request", zap.String("subject", subject), zap.Error(err))
+			c.Next()
+			return
+		}
+
+		count := int(incr.Val())
+		remaining := limit - count
+		if remaining < 0 {
+			remaining = 0
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+
+		if count > limit {
+			retryAfter := int(reset.Sub(now).Seconds()) + 1
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			logger.Warn("write rate limit exceeded",
+				zap.String("subject", subject),
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.FullPath()),
+			)
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":       "write rate limit exceeded, please slow down",
+				"limit":       limit,
+				"retry_after": retryAfter,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// rateLimitSubject identifies the shop a write request belongs to
+func rateLimitSubject(c *gin.Context) string {
+	if shopID := c.GetHeader("X-Shop-Id"); shopID != "" {
+		return "shop:" + shopID
+	}
+	if userID := c.GetHeader("X-User-Id"); userID != "" {
+		return "user:" + userID
+	}
+	return "ip:" + c.ClientIP()
+}
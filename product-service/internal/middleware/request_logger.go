@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RequestLogger logs every request at debug level, so it only shows up
+// when logging.level is debug (or the level is raised at runtime).
+// Requests made by an admin impersonating a user (X-Impersonator-Id, set by the
+// gateway) are always logged at info level with the admin and the user
+func RequestLogger(logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		if impersonatorID := c.GetHeader("X-Impersonator-Id"); impersonatorID != "" {
+			logger.Info("impersonated request handled",
+				zap.String("impersonator_id", impersonatorID),
+				zap.String("user_id", c.GetHeader("X-User-Id")),
+				zap.String("impersonation_id", c.GetHeader("X-Impersonation-Id")),
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.Request.URL.Path),
+				zap.Int("status", c.Writer.Status()),
+			)
+			return
+		}
+
+		if ce := logger.Check(zap.DebugLevel, "request handled"); ce != nil {
+			ce.Write(
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.Request.URL.Path),
+				zap.Int("status", c.Writer.Status()),
+				zap.Duration("latency", time.Since(start)),
+			)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"product-service/internal/domain"
+	"strings"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+)
+
+// catalogIndexMapping is the mapping for full catalog indexes (domain.ProductDocument)
+const catalogIndexMapping = `{
+	"settings": {
+		"number_of_replicas": 0,
+		"refresh_interval": "-1"
+	},
+	"mappings": {
+		"properties": {
+			"id": { "type": "long" },
+			"shop_id": { "type": "long" },
+			"name": { "type": "text", "analyzer": "standard", "fields": { "keyword": { "type": "keyword", "ignore_above": 256 } } },
+			"slug": { "type": "keyword" },
+			"description": { "type": "text", "analyzer": "standard" },
+			"base_price": { "type": "scaled_float", "scaling_factor": 100 },
+			"min_price": { "type": "scaled_float", "scaling_factor": 100 },
+			"max_price": { "type": "scaled_float", "scaling_factor": 100 },
+			"total_stock": { "type": "integer" },
+			"category_id": { "type": "long" },
+			"category": { "type": "keyword" },
+			"status": { "type": "keyword" },
+			"is_active": { "type": "boolean" },
+			"sold_count": { "type": "integer" },
+			"sales_rank": { "type": "integer" },
+			"rating_avg": { "type": "half_float" },
+			"rating_count": { "type": "integer" },
+			"shop_rating_avg": { "type": "half_float" },
+			"shop_rating_count": { "type": "integer" },
+			"items": {
+				"type": "nested",
+				"properties": {
+					"id": { "type": "long" },
+					"sku_code": { "type": "keyword" },
+					"price": { "type": "scaled_float", "scaling_factor": 100 },
+					"qty_in_stock": { "type": "integer" },
+					"status": { "type": "keyword" }
+				}
+			},
+			"attributes": {
+				"type": "nested",
+				"properties": {
+					"attribute_id": { "type": "long" },
+					"name": { "type": "keyword" },
+					"value": { "type": "keyword" }
+				}
+			},
+			"created_at": { "type": "date" },
+			"updated_at": { "type": "date" }
+		}
+	}
+}`
+
+// CatalogIndexer writes full catalog indexes in bulk (used by cmd/reindex)
+type CatalogIndexer struct {
+	client *elasticsearch.Client
+}
+
+// NewCatalogIndexer creates a new catalog indexer
+func NewCatalogIndexer(client *elasticsearch.Client) *CatalogIndexer {
+	return &CatalogIndexer{client: client}
+}
+
+// IndexExists reports whether an index exists
+func (i *CatalogIndexer) IndexExists(ctx context.Context, index string) (bool, error) {
+	res, err := i.client.Indices.Exists([]string{index}, i.client.Indices.Exists.WithContext(ctx))
+	if err != nil {
+		return false, fmt.Errorf("failed to check index existence: %w", err)
+	}
+	defer res.Body.Close()
+	return res.StatusCode == 200, nil
+}
+
+// CreateIndex creates an index with the catalog mapping
+// Refresh and replicas are disabled for bulk loading; call FinalizeIndex when done
+func (i *CatalogIndexer) CreateIndex(ctx context.Context, index string) error {
+	req := esapi.IndicesCreateRequest{
+		Index: index,
+		Body:  strings.NewReader(catalogIndexMapping),
+	}
+	res, err := req.Do(ctx, i.client)
+	if err != nil {
+		return fmt.Errorf("failed to create index: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch error creating index: %s", res.String())
+	}
+	return nil
+}
+
+// BulkIndex writes documents with one bulk request
+// Returns the number of documents that failed (per-item errors), plus a request-level error
+func (i *CatalogIndexer) BulkIndex(ctx context.Context, index string, docs []*domain.ProductDocument) (int, error) {
+	if len(docs) == 0 {
+		return 0, nil
+	}
+
+	var body bytes.Buffer
+	for _, doc := range docs {
+		meta := fmt.Sprintf(`{"index":{"_index":%q,"_id":"%d"}}`, index, doc.ID)
+		body.WriteString(meta)
+		body.WriteByte('\n')
+
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal product %d: %w", doc.ID, err)
+		}
+		body.Write(data)
+		body.WriteByte('\n')
+	}
+
+	req := esapi.BulkRequest{Body: &body}
+	res, err := req.Do(ctx, i.client)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute bulk request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return 0, fmt.Errorf("elasticsearch bulk error: %s", res.String())
+	}
+
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("failed to decode bulk response: %w", err)
+	}
+	if !result.Errors {
+		return 0, nil
+	}
+
+	failed := 0
+	for _, item := range result.Items {
+		for _, op := range item {
+			if op.Status >= 300 {
+				failed++
+			}
+		}
+	}
+	return failed, nil
+}
+
+// FinalizeIndex restores normal refresh/replica settings and refreshes the index
+func (i *CatalogIndexer) FinalizeIndex(ctx context.Context, index string, replicas int) error {
+	settings := fmt.Sprintf(`{"index":{"refresh_interval":"1s","number_of_replicas":%d}}`, replicas)
+	res, err := i.client.Indices.PutSettings(strings.NewReader(settings),
+		i.client.Indices.PutSettings.WithIndex(index),
+		i.client.Indices.PutSettings.WithContext(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update index settings: %w", err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch error updating settings: %s", res.String())
+	}
+
+	refresh, err := i.client.Indices.Refresh(i.client.Indices.Refresh.WithIndex(index), i.client.Indices.Refresh.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to refresh index: %w", err)
+	}
+	defer refresh.Body.Close()
+	return nil
+}
+
+// SwapAlias atomically points alias to index (removing it from any other index)
+func (i *CatalogIndexer) SwapAlias(ctx context.Context, alias, index string) error {
+	actions := fmt.Sprintf(`{"actions":[{"remove":{"index":"*","alias":%q}},{"add":{"index":%q,"alias":%q}}]}`, alias, index, alias)
+	res, err := i.client.Indices.UpdateAliases(strings.NewReader(actions), i.client.Indices.UpdateAliases.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to update alias: %w", err)
+	}
+	defer res.Body.Close()
+
+	// "remove" fails with 404 when the alias does not exist yet; retry with add only
+	if res.StatusCode == 404 {
+		addOnly := fmt.Sprintf(`{"actions":[{"add":{"index":%q,"alias":%q}}]}`, index, alias)
+		res2, err := i.client.Indices.UpdateAliases(strings.NewReader(addOnly), i.client.Indices.UpdateAliases.WithContext(ctx))
+		if err != nil {
+			return fmt.Errorf("failed to add alias: %w", err)
+		}
+		defer res2.Body.Close()
+		if res2.IsError() {
+			return fmt.Errorf("elasticsearch error adding alias: %s", res2.String())
+		}
+		return nil
+	}
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch error updating alias: %s", res.String())
+	}
+	return nil
+}
+
+// GetStockTotals reads total_stock for the given products with one _mget request
+// Products whose document is missing (or has no total_stock) are absent from the result
+func (i *CatalogIndexer) GetStockTotals(ctx context.Context, index string, ids []uint) (map[uint]int, error) {
+	totals := make(map[uint]int, len(ids))
+	if len(ids) == 0 {
+		return totals, nil
+	}
+
+	docIDs := make([]string, len(ids))
+	for n, id := range ids {
+		docIDs[n] = fmt.Sprintf("%d", id)
+	}
+	body, err := json.Marshal(map[string]interface{}{"ids": docIDs})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal mget request: %w", err)
+	}
+
+	req := esapi.MgetRequest{
+		Index:          index,
+		Body:           bytes.NewReader(body),
+		SourceIncludes: []string{"total_stock"},
+	}
+	res, err := req.Do(ctx, i.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute mget request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, fmt.Errorf("elasticsearch mget error: %s", res.String())
+	}
+
+	var result struct {
+		Docs []struct {
+			ID     string `json:"_id"`
+			Found  bool   `json:"found"`
+			Source struct {
+				TotalStock *int `json:"total_stock"`
+			} `json:"_source"`
+		} `json:"docs"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode mget response: %w", err)
+	}
+
+	for _, doc := range result.Docs {
+		if !doc.Found || doc.Source.TotalStock == nil {
+			continue
+		}
+		var id uint
+		if _, err := fmt.Sscanf(doc.ID, "%d", &id); err != nil {
+			continue
+		}
+		totals[id] = *doc.Source.TotalStock
+	}
+	return totals, nil
+}
@@ -29,8 +29,7 @@ func NewProductSearchRepository(client *elasticsearch.Client, indexName string)
 
 // IndexProduct indexes a product document in Elasticsearch
 // This enables fast full-text search and filtering
-func (r *productSearchRepository) IndexProduct(product *domain.Product) error {
-	ctx := context.Background()
+func (r *productSearchRepository) IndexProduct(ctx context.Context, product *domain.Product) error {
 
 	// Convert product to JSON
 	productJSON, err := json.Marshal(product)
@@ -62,8 +61,7 @@ func (r *productSearchRepository) IndexProduct(product *domain.Product) error {
 
 // SearchProducts performs a search query with filters
 // This is a simplified implementation - in production, you'd want more sophisticated queries
-func (r *productSearchRepository) SearchProducts(query string, filters map[string]interface{}) ([]*domain.Product, error) {
-	ctx := context.Background()
+func (r *productSearchRepository) SearchProducts(ctx context.Context, query string, filters map[string]interface{}) ([]*domain.Product, error) {
 
 	// Build the search query
 	// In production, you'd use a more sophisticated query builder
@@ -149,8 +147,7 @@ func (r *productSearchRepository) SearchProducts(query string, filters map[strin
 }
 
 // DeleteFromIndex removes a product from the Elasticsearch index
-func (r *productSearchRepository) DeleteFromIndex(id uint) error {
-	ctx := context.Background()
+func (r *productSearchRepository) DeleteFromIndex(ctx context.Context, id uint) error {
 
 	req := esapi.DeleteRequest{
 		Index:      r.indexName,
@@ -171,3 +168,154 @@ func (r *productSearchRepository) DeleteFromIndex(id uint) error {
 	return nil
 }
 
+// UpdateSalesRanks writes sales_rank onto the product documents with partial updates,
+// so the rest of the documents is kept; products that are not indexed are skipped
+func (r *productSearchRepository) UpdateSalesRanks(ctx context.Context, ranks map[uint]int) error {
+	if len(ranks) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	for id, rank := range ranks {
+		fmt.Fprintf(&body, `{"update":{"_id":"%d"}}`+"\n", id)
+		if rank > 0 {
+			fmt.Fprintf(&body, `{"doc":{"sales_rank":%d}}`+"\n", rank)
+		} else {
+			body.WriteString(`{"doc":{"sales_rank":null}}` + "\n")
+		}
+	}
+
+	req := esapi.BulkRequest{
+		Index: r.indexName,
+		Body:  &body,
+	}
+	res, err := req.Do(ctx, r.client)
+	if err != nil {
+		return fmt.Errorf("failed to update sales ranks: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch error: %s", res.String())
+	}
+
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode bulk response: %w", err)
+	}
+	if !result.Errors {
+		return nil
+	}
+	failed := 0
+	for _, item := range result.Items {
+		for _, op := range item {
+			if op.Status >= 300 && op.Status != 404 { // 404 = product not indexed
+				failed++
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to update sales ranks: %d item(s) failed", failed)
+	}
+	return nil
+}
+
+// AdminSearch searches all products regardless of status or shop (admin moderation)
+// Only IDs are read from the index; callers load the rows from Postgres
+func (r *productSearchRepository) AdminSearch(ctx context.Context, search *domain.AdminProductSearch) ([]uint, int64, error) {
+	filters := []map[string]interface{}{}
+	if search.ShopID > 0 {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{"shop_id": search.ShopID}})
+	}
+	if search.CategoryID > 0 {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{"category_id": search.CategoryID}})
+	}
+	if len(search.Statuses) > 0 {
+		filters = append(filters, map[string]interface{}{"terms": map[string]interface{}{"status": search.Statuses}})
+	}
+	if search.SKU != "" {
+		filters = append(filters, skuQuery(search.SKU))
+	}
+
+	boolQuery := map[string]interface{}{"filter": filters}
+	if search.Query != "" {
+		// Name (full text) or SKU code (exact or prefix)
+		boolQuery["should"] = []map[string]interface{}{
+			{"match": map[string]interface{}{"name": map[string]interface{}{"query": search.Query, "operator": "and"}}},
+			skuQuery(search.Query),
+			{"nested": map[string]interface{}{
+				"path":  "items",
+				"query": map[string]interface{}{"prefix": map[string]interface{}{"items.sku_code": search.Query}},
+			}},
+		}
+		boolQuery["minimum_should_match"] = 1
+	}
+
+	body := map[string]interface{}{
+		"query":            map[string]interface{}{"bool": boolQuery},
+		"from":             (search.Page - 1) * search.Limit,
+		"size":             search.Limit,
+		"_source":          false,
+		"track_total_hits": true,
+		"sort": []interface{}{
+			"_score",
+			map[string]interface{}{"id": map[string]interface{}{"order": "desc"}},
+		},
+	}
+	queryJSON, err := json.Marshal(body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to marshal admin search query: %w", err)
+	}
+
+	res, err := r.client.Search(
+		r.client.Search.WithContext(ctx),
+		r.client.Search.WithIndex(r.indexName),
+		r.client.Search.WithBody(bytes.NewReader(queryJSON)),
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search products: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, 0, fmt.Errorf("elasticsearch error: %s", res.String())
+	}
+
+	var result struct {
+		Hits struct {
+			Total struct {
+				Value int64 `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				ID string `json:"_id"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode search response: %w", err)
+	}
+
+	ids := make([]uint, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		var id uint
+		if _, err := fmt.Sscanf(hit.ID, "%d", &id); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids, result.Hits.Total.Value, nil
+}
+
+// skuQuery matches products having a SKU with exactly this code
+func skuQuery(sku string) map[string]interface{} {
+	return map[string]interface{}{
+		"nested": map[string]interface{}{
+			"path":  "items",
+			"query": map[string]interface{}{"term": map[string]interface{}{"items.sku_code": sku}},
+		},
+	}
+}
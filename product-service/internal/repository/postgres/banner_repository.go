@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"product-service/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// bannerRepository implements the BannerRepository interface
+// This is the infrastructure layer - it knows HOW to interact with PostgreSQL
+type bannerRepository struct {
+	db *gorm.DB
+}
+
+// NewBannerRepository creates a new PostgreSQL banner repository
+func NewBannerRepository(db *gorm.DB) domain.BannerRepository {
+	return &bannerRepository{db: db}
+}
+
+// Create inserts a new banner
+func (r *bannerRepository) Create(ctx context.Context, banner *domain.Banner) error {
+	return r.db.WithContext(ctx).Create(banner).Error
+}
+
+// Update updates an existing banner
+func (r *bannerRepository) Update(ctx context.Context, banner *domain.Banner) error {
+	return r.db.WithContext(ctx).Omit("Campaign").Save(banner).Error
+}
+
+// GetByID retrieves a banner by its ID
+func (r *bannerRepository) GetByID(ctx context.Context, id uint) (*domain.Banner, error) {
+	var banner domain.Banner
+	err := r.db.WithContext(ctx).First(&banner, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &banner, nil
+}
+
+// List retrieves banners for admin, optionally filtered by placement/campaign
+func (r *bannerRepository) List(ctx context.Context, placement string, campaignID *uint) ([]*domain.Banner, error) {
+	var banners []*domain.Banner
+	query := r.db.WithContext(ctx).Model(&domain.Banner{})
+	if placement != "" {
+		query = query.Where("placement = ?", placement)
+	}
+	if campaignID != nil {
+		query = query.Where("campaign_id = ?", *campaignID)
+	}
+	if err := query.Order("placement, priority DESC, id").Find(&banners).Error; err != nil {
+		return nil, err
+	}
+	return banners, nil
+}
+
+// GetActive retrieves banners visible at the given time
+// Banner must be active and in schedule; if it belongs to a campaign, the campaign must be too
+func (r *bannerRepository) GetActive(ctx context.Context, now time.Time) ([]*domain.Banner, error) {
+	var banners []*domain.Banner
+	err := r.db.WithContext(ctx).
+		Joins("LEFT JOIN campaigns ON campaigns.id = banners.campaign_id").
+		Where("banners.is_active = ?", true).
+		Where("banners.start_at IS NULL OR banners.start_at <= ?", now).
+		Where("banners.end_at IS NULL OR banners.end_at > ?", now).
+		Where("banners.campaign_id IS NULL OR (campaigns.is_active = ? AND (campaigns.start_at IS NULL OR campaigns.start_at <= ?) AND (campaigns.end_at IS NULL OR campaigns.end_at > ?))", true, now, now).
+		Order("banners.priority DESC, banners.id").
+		Find(&banners).Error
+	if err != nil {
+		return nil, err
+	}
+	return banners, nil
+}
+
+// Delete removes a banner
+func (r *bannerRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.Banner{}, id).Error
+}
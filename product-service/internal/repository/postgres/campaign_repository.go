@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"product-service/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// campaignRepository implements the CampaignRepository interface
+// This is the infrastructure layer - it knows HOW to interact with PostgreSQL
+type campaignRepository struct {
+	db *gorm.DB
+}
+
+// NewCampaignRepository creates a new PostgreSQL campaign repository
+func NewCampaignRepository(db *gorm.DB) domain.CampaignRepository {
+	return &campaignRepository{db: db}
+}
+
+// Create inserts a new campaign
+func (r *campaignRepository) Create(ctx context.Context, campaign *domain.Campaign) error {
+	return r.db.WithContext(ctx).Create(campaign).Error
+}
+
+// Update updates an existing campaign
+func (r *campaignRepository) Update(ctx context.Context, campaign *domain.Campaign) error {
+	return r.db.WithContext(ctx).Save(campaign).Error
+}
+
+// GetByID retrieves a campaign by its ID
+func (r *campaignRepository) GetByID(ctx context.Context, id uint) (*domain.Campaign, error) {
+	var campaign domain.Campaign
+	err := r.db.WithContext(ctx).First(&campaign, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+// GetAll retrieves all campaigns (newest first)
+func (r *campaignRepository) GetAll(ctx context.Context) ([]*domain.Campaign, error) {
+	var campaigns []*domain.Campaign
+	if err := r.db.WithContext(ctx).Order("id DESC").Find(&campaigns).Error; err != nil {
+		return nil, err
+	}
+	return campaigns, nil
+}
+
+// GetActive retrieves campaigns running at the given time
+func (r *campaignRepository) GetActive(ctx context.Context, now time.Time) ([]*domain.Campaign, error) {
+	var campaigns []*domain.Campaign
+	err := r.db.WithContext(ctx).
+		Where("is_active = ?", true).
+		Where("start_at IS NULL OR start_at <= ?", now).
+		Where("end_at IS NULL OR end_at > ?", now).
+		Order("id DESC").
+		Find(&campaigns).Error
+	if err != nil {
+		return nil, err
+	}
+	return campaigns, nil
+}
+
+// Delete removes a campaign and detaches its banners
+func (r *campaignRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.Banner{}).Where("campaign_id = ?", id).Update("campaign_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.Campaign{}, id).Error
+	})
+}
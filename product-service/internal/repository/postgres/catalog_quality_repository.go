@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"product-service/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// catalogQualityRepository implements the CatalogQualityRepository interface
+type catalogQualityRepository struct {
+	db *gorm.DB
+}
+
+// NewCatalogQualityRepository creates a new PostgreSQL catalog quality repository
+func NewCatalogQualityRepository(db *gorm.DB) domain.CatalogQualityRepository {
+	return &catalogQualityRepository{db: db}
+}
+
+// CreateIssues inserts the issues of a quality run
+func (r *catalogQualityRepository) CreateIssues(ctx context.Context, issues []*domain.CatalogQualityIssue) error {
+	if len(issues) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).CreateInBatches(issues, 200).Error
+}
+
+// CreateScores inserts the shop scores of a quality run
+func (r *catalogQualityRepository) CreateScores(ctx context.Context, scores []*domain.ShopQualityScore) error {
+	if len(scores) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).CreateInBatches(scores, 200).Error
+}
+
+// ListIssues returns issues of one run ordered by shop, kind and product
+func (r *catalogQualityRepository) ListIssues(ctx context.Context, runID string, shopID uint, kind string, limit int) ([]*domain.CatalogQualityIssue, error) {
+	query := r.db.WithContext(ctx).Where("run_id = ?", runID)
+	if shopID > 0 {
+		query = query.Where("shop_id = ?", shopID)
+	}
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+
+	var issues []*domain.CatalogQualityIssue
+	err := query.Order("shop_id, kind, product_id").Limit(limit).Find(&issues).Error
+	return issues, err
+}
+
+// ListScores returns a page of shop scores of one run, worst shops first
+func (r *catalogQualityRepository) ListScores(ctx context.Context, runID string, page, limit int) ([]*domain.ShopQualityScore, int64, error) {
+	query := r.db.WithContext(ctx).Model(&domain.ShopQualityScore{}).Where("run_id = ?", runID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var scores []*domain.ShopQualityScore
+	err := query.Order("score ASC, shop_id ASC").Offset((page - 1) * limit).Limit(limit).Find(&scores).Error
+	return scores, total, err
+}
+
+// GetScore retrieves the score of one shop in a run
+func (r *catalogQualityRepository) GetScore(ctx context.Context, runID string, shopID uint) (*domain.ShopQualityScore, error) {
+	var score domain.ShopQualityScore
+	if err := r.db.WithContext(ctx).Where("run_id = ? AND shop_id = ?", runID, shopID).First(&score).Error; err != nil {
+		return nil, err
+	}
+	return &score, nil
+}
+
+// DeleteRunsBefore removes issues and scores created before the given time
+func (r *catalogQualityRepository) DeleteRunsBefore(ctx context.Context, before time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("created_at < ?", before).Delete(&domain.CatalogQualityIssue{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("created_at < ?", before).Delete(&domain.ShopQualityScore{}).Error
+	})
+}
@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"product-service/internal/domain"
 
 	"gorm.io/gorm"
@@ -17,19 +18,19 @@ func NewCategoryAttributeRepository(db *gorm.DB) domain.CategoryAttributeReposit
 }
 
 // Create inserts a new category attribute into the database
-func (r *categoryAttributeRepository) Create(attr *domain.CategoryAttribute) error {
-	return r.db.Create(attr).Error
+func (r *categoryAttributeRepository) Create(ctx context.Context, attr *domain.CategoryAttribute) error {
+	return r.db.WithContext(ctx).Create(attr).Error
 }
 
 // Update updates an existing category attribute
-func (r *categoryAttributeRepository) Update(attr *domain.CategoryAttribute) error {
-	return r.db.Save(attr).Error
+func (r *categoryAttributeRepository) Update(ctx context.Context, attr *domain.CategoryAttribute) error {
+	return r.db.WithContext(ctx).Save(attr).Error
 }
 
 // GetByID retrieves a category attribute by its ID
-func (r *categoryAttributeRepository) GetByID(id uint) (*domain.CategoryAttribute, error) {
+func (r *categoryAttributeRepository) GetByID(ctx context.Context, id uint) (*domain.CategoryAttribute, error) {
 	var attr domain.CategoryAttribute
-	err := r.db.First(&attr, id).Error
+	err := r.db.WithContext(ctx).First(&attr, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -37,9 +38,9 @@ func (r *categoryAttributeRepository) GetByID(id uint) (*domain.CategoryAttribut
 }
 
 // GetByCategoryID retrieves all attributes for a category
-func (r *categoryAttributeRepository) GetByCategoryID(categoryID uint) ([]*domain.CategoryAttribute, error) {
+func (r *categoryAttributeRepository) GetByCategoryID(ctx context.Context, categoryID uint) ([]*domain.CategoryAttribute, error) {
 	var attrs []*domain.CategoryAttribute
-	err := r.db.Where("category_id = ?", categoryID).Find(&attrs).Error
+	err := r.db.WithContext(ctx).Where("category_id = ?", categoryID).Find(&attrs).Error
 	if err != nil {
 		return nil, err
 	}
@@ -47,9 +48,9 @@ func (r *categoryAttributeRepository) GetByCategoryID(categoryID uint) ([]*domai
 }
 
 // GetFilterablesByCategoryID retrieves only filterable attributes for a category
-func (r *categoryAttributeRepository) GetFilterablesByCategoryID(categoryID uint) ([]*domain.CategoryAttribute, error) {
+func (r *categoryAttributeRepository) GetFilterablesByCategoryID(ctx context.Context, categoryID uint) ([]*domain.CategoryAttribute, error) {
 	var attrs []*domain.CategoryAttribute
-	err := r.db.Where("category_id = ? AND is_filterable = ?", categoryID, true).Find(&attrs).Error
+	err := r.db.WithContext(ctx).Where("category_id = ? AND is_filterable = ?", categoryID, true).Find(&attrs).Error
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +58,7 @@ func (r *categoryAttributeRepository) GetFilterablesByCategoryID(categoryID uint
 }
 
 // Delete deletes a category attribute
-func (r *categoryAttributeRepository) Delete(id uint) error {
-	return r.db.Delete(&domain.CategoryAttribute{}, id).Error
+func (r *categoryAttributeRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.CategoryAttribute{}, id).Error
 }
 
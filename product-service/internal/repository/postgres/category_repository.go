@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"product-service/internal/domain"
 
 	"gorm.io/gorm"
@@ -19,29 +20,39 @@ func NewCategoryRepository(db *gorm.DB) domain.CategoryRepository {
 }
 
 // Create inserts a new category into the database
-func (r *categoryRepository) Create(category *domain.Category) error {
-	return r.db.Create(category).Error
+func (r *categoryRepository) Create(ctx context.Context, category *domain.Category) error {
+	return r.db.WithContext(ctx).Create(category).Error
 }
 
 // Update updates an existing category
-func (r *categoryRepository) Update(category *domain.Category) error {
-	return r.db.Save(category).Error
+func (r *categoryRepository) Update(ctx context.Context, category *domain.Category) error {
+	return r.db.WithContext(ctx).Save(category).Error
 }
 
 // GetByID retrieves a category by its ID (NO Preload to avoid N+1)
-func (r *categoryRepository) GetByID(id uint) (*domain.Category, error) {
+func (r *categoryRepository) GetByID(ctx context.Context, id uint) (*domain.Category, error) {
 	var category domain.Category
-	err := r.db.First(&category, id).Error
+	err := r.db.WithContext(ctx).First(&category, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &category, nil
 }
 
+// GetByIDs retrieves the categories with the given IDs (missing IDs are skipped)
+func (r *categoryRepository) GetByIDs(ctx context.Context, ids []uint) ([]*domain.Category, error) {
+	var categories []*domain.Category
+	if len(ids) == 0 {
+		return categories, nil
+	}
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&categories).Error
+	return categories, err
+}
+
 // GetBySlug retrieves a category by its slug (NO Preload)
-func (r *categoryRepository) GetBySlug(slug string) (*domain.Category, error) {
+func (r *categoryRepository) GetBySlug(ctx context.Context, slug string) (*domain.Category, error) {
 	var category domain.Category
-	err := r.db.Where("slug = ?", slug).First(&category).Error
+	err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&category).Error
 	if err != nil {
 		return nil, err
 	}
@@ -49,9 +60,9 @@ func (r *categoryRepository) GetBySlug(slug string) (*domain.Category, error) {
 }
 
 // GetAll retrieves all categories
-func (r *categoryRepository) GetAll() ([]*domain.Category, error) {
+func (r *categoryRepository) GetAll(ctx context.Context) ([]*domain.Category, error) {
 	var categories []*domain.Category
-	err := r.db.Find(&categories).Error
+	err := r.db.WithContext(ctx).Find(&categories).Error
 	if err != nil {
 		return nil, err
 	}
@@ -59,9 +70,9 @@ func (r *categoryRepository) GetAll() ([]*domain.Category, error) {
 }
 
 // GetChildren retrieves all child categories of a parent category
-func (r *categoryRepository) GetChildren(parentID uint) ([]*domain.Category, error) {
+func (r *categoryRepository) GetChildren(ctx context.Context, parentID uint) ([]*domain.Category, error) {
 	var categories []*domain.Category
-	err := r.db.Where("parent_id = ?", parentID).Find(&categories).Error
+	err := r.db.WithContext(ctx).Where("parent_id = ?", parentID).Find(&categories).Error
 	if err != nil {
 		return nil, err
 	}
@@ -70,6 +81,6 @@ func (r *categoryRepository) GetChildren(parentID uint) ([]*domain.Category, err
 
 // Delete deletes a category (hard delete)
 // Note: In production, you might want to check if category has products before deleting
-func (r *categoryRepository) Delete(id uint) error {
-	return r.db.Delete(&domain.Category{}, id).Error
+func (r *categoryRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.Category{}, id).Error
 }
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"product-service/internal/domain"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// digitalCodeRepository implements the DigitalCodeRepository interface
+type digitalCodeRepository struct {
+	db *gorm.DB
+}
+
+// NewDigitalCodeRepository creates a new PostgreSQL digital code repository
+func NewDigitalCodeRepository(db *gorm.DB) domain.DigitalCodeRepository {
+	return &digitalCodeRepository{db: db}
+}
+
+// CreateBatch inserts codes, skipping duplicates (unique code_hash)
+func (r *digitalCodeRepository) CreateBatch(ctx context.Context, codes []*domain.DigitalCode) (int, error) {
+	if len(codes) == 0 {
+		return 0, nil
+	}
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "code_hash"}}, DoNothing: true}).
+		CreateInBatches(codes, 500)
+	return int(result.RowsAffected), result.Error
+}
+
+// CountByStatus counts the available and issued codes of a SKU
+func (r *digitalCodeRepository) CountByStatus(ctx context.Context, productItemID uint) (*domain.DigitalCodeStats, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&domain.DigitalCode{}).
+		Select("status, COUNT(*) AS count").
+		Where("product_item_id = ?", productItemID).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &domain.DigitalCodeStats{ProductItemID: productItemID}
+	for _, row := range rows {
+		switch row.Status {
+		case domain.DigitalCodeStatusAvailable:
+			stats.Available = row.Count
+		case domain.DigitalCodeStatusIssued:
+			stats.Issued = row.Count
+		}
+	}
+	return stats, nil
+}
+
+// ListByOrder retrieves the codes issued to an order
+func (r *digitalCodeRepository) ListByOrder(ctx context.Context, orderID string) ([]*domain.DigitalCode, error) {
+	var codes []*domain.DigitalCode
+	err := r.db.WithContext(ctx).
+		Where("order_id = ? AND status = ?", orderID, domain.DigitalCodeStatusIssued).
+		Order("product_item_id ASC, id ASC").
+		Find(&codes).Error
+	return codes, err
+}
+
+// IssueForOrder tops up the codes held by an order in one transaction
+// Available codes are claimed with FOR UPDATE SKIP LOCKED so concurrent orders
+// never receive the same code
+func (r *digitalCodeRepository) IssueForOrder(ctx context.Context, orderID string, quantities map[uint]int) ([]*domain.DigitalCode, error) {
+	itemIDs := make([]uint, 0, len(quantities))
+	for id := range quantities {
+		itemIDs = append(itemIDs, id)
+	}
+	sort.Slice(itemIDs, func(i, j int) bool { return itemIDs[i] < itemIDs[j] })
+
+	var issued []*domain.DigitalCode
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		for _, itemID := range itemIDs {
+			var held int64
+			if err := tx.Model(&domain.DigitalCode{}).
+				Where("order_id = ? AND product_item_id = ?", orderID, itemID).
+				Count(&held).Error; err != nil {
+				return err
+			}
+			missing := quantities[itemID] - int(held)
+			if missing <= 0 {
+				continue
+			}
+
+			var ids []uint
+			if err := tx.Model(&domain.DigitalCode{}).
+				Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+				Where("product_item_id = ? AND status = ?", itemID, domain.DigitalCodeStatusAvailable).
+				Order("id ASC").
+				Limit(missing).
+				Pluck("id", &ids).Error; err != nil {
+				return err
+			}
+			if len(ids) < missing {
+				return domain.ErrInsufficientDigitalCodes
+			}
+
+			if err := tx.Model(&domain.DigitalCode{}).
+				Where("id IN ?", ids).
+				Updates(map[string]interface{}{
+					"status":    domain.DigitalCodeStatusIssued,
+					"order_id":  orderID,
+					"issued_at": now,
+				}).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Where("order_id = ? AND status = ?", orderID, domain.DigitalCodeStatusIssued).
+			Order("product_item_id ASC, id ASC").
+			Find(&issued).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return issued, nil
+}
@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+	"product-service/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// inventoryDiscrepancyRepository implements the InventoryDiscrepancyRepository interface
+type inventoryDiscrepancyRepository struct {
+	db *gorm.DB
+}
+
+// NewInventoryDiscrepancyRepository creates a new PostgreSQL inventory discrepancy repository
+func NewInventoryDiscrepancyRepository(db *gorm.DB) domain.InventoryDiscrepancyRepository {
+	return &inventoryDiscrepancyRepository{db: db}
+}
+
+// CreateBatch inserts the discrepancies of a reconciliation run
+func (r *inventoryDiscrepancyRepository) CreateBatch(ctx context.Context, discrepancies []*domain.InventoryDiscrepancy) error {
+	if len(discrepancies) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).CreateInBatches(discrepancies, 200).Error
+}
+
+// ListByRun returns discrepancies of one run ordered by kind and product
+func (r *inventoryDiscrepancyRepository) ListByRun(ctx context.Context, runID string, limit int) ([]*domain.InventoryDiscrepancy, error) {
+	var discrepancies []*domain.InventoryDiscrepancy
+	err := r.db.WithContext(ctx).
+		Where("run_id = ?", runID).
+		Order("kind, product_id").
+		Limit(limit).
+		Find(&discrepancies).Error
+	return discrepancies, err
+}
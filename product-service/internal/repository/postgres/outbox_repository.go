@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"product-service/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// outboxRelayLock is the Postgres advisory lock key held while relaying ("outbox" in ASCII)
+const outboxRelayLock = 0x6f7574626f78
+
+// outboxRepository implements the OutboxRepository interface
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository creates a new PostgreSQL outbox repository
+func NewOutboxRepository(db *gorm.DB) domain.OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+// Add inserts an event, in the transaction of ctx if any
+func (r *outboxRepository) Add(ctx context.Context, event *domain.OutboxEvent) error {
+	return conn(ctx, r.db).Create(event).Error
+}
+
+// Relay publishes pending events in ID order inside a transaction holding an advisory
+// lock, so a single relay runs across replicas and the lock is released if it dies.
+// Publish marks and the failure of the last event are committed together
+func (r *outboxRepository) Relay(ctx context.Context, limit int, publish func(ctx context.Context, event *domain.OutboxEvent) error) (int, error) {
+	published := 0
+	var publishErr error
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", outboxRelayLock).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return nil // Another instance is relaying
+		}
+
+		var events []*domain.OutboxEvent
+		if err := tx.Where("published_at IS NULL").Order("id").Limit(limit).Find(&events).Error; err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if publishErr = publish(ctx, event); publishErr != nil {
+				return tx.Model(event).Updates(map[string]interface{}{
+					"attempts":   gorm.Expr("attempts + 1"),
+					"last_error": publishErr.Error(),
+				}).Error
+			}
+			if err := tx.Model(event).Update("published_at", time.Now()).Error; err != nil {
+				return err
+			}
+			published++
+		}
+		return nil
+	})
+	if err != nil {
+		// Nothing was committed: the published events are relayed again (at-least-once)
+		return 0, err
+	}
+	return published, publishErr
+}
+
+// DeletePublishedBefore removes the events relayed before t
+func (r *outboxRepository) DeletePublishedBefore(ctx context.Context, t time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("published_at < ?", t).Delete(&domain.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
+
+// Backlog counts the pending events and returns the oldest one
+func (r *outboxRepository) Backlog(ctx context.Context) (int64, *domain.OutboxEvent, error) {
+	var pending int64
+	if err := r.db.WithContext(ctx).Model(&domain.OutboxEvent{}).Where("published_at IS NULL").Count(&pending).Error; err != nil {
+		return 0, nil, err
+	}
+
+	var oldest domain.OutboxEvent
+	err := r.db.WithContext(ctx).Where("published_at IS NULL").Order("id").First(&oldest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return pending, nil, nil
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	return pending, &oldest, nil
+}
@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"product-service/internal/domain"
 
 	"gorm.io/gorm"
@@ -17,24 +18,24 @@ func NewProductAttributeValueRepository(db *gorm.DB) domain.ProductAttributeValu
 }
 
 // Create inserts a new product attribute value into the database
-func (r *productAttributeValueRepository) Create(value *domain.ProductAttributeValue) error {
-	return r.db.Create(value).Error
+func (r *productAttributeValueRepository) Create(ctx context.Context, value *domain.ProductAttributeValue) error {
+	return r.db.WithContext(ctx).Create(value).Error
 }
 
 // CreateBatch inserts multiple product attribute values in a single transaction
-func (r *productAttributeValueRepository) CreateBatch(values []*domain.ProductAttributeValue) error {
-	return r.db.Create(values).Error
+func (r *productAttributeValueRepository) CreateBatch(ctx context.Context, values []*domain.ProductAttributeValue) error {
+	return r.db.WithContext(ctx).Create(values).Error
 }
 
 // Update updates an existing product attribute value
-func (r *productAttributeValueRepository) Update(value *domain.ProductAttributeValue) error {
-	return r.db.Save(value).Error
+func (r *productAttributeValueRepository) Update(ctx context.Context, value *domain.ProductAttributeValue) error {
+	return r.db.WithContext(ctx).Save(value).Error
 }
 
 // GetByID retrieves a product attribute value by its ID
-func (r *productAttributeValueRepository) GetByID(id uint) (*domain.ProductAttributeValue, error) {
+func (r *productAttributeValueRepository) GetByID(ctx context.Context, id uint) (*domain.ProductAttributeValue, error) {
 	var value domain.ProductAttributeValue
-	err := r.db.First(&value, id).Error
+	err := r.db.WithContext(ctx).First(&value, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -42,9 +43,22 @@ func (r *productAttributeValueRepository) GetByID(id uint) (*domain.ProductAttri
 }
 
 // GetByProductID retrieves all attribute values for a product
-func (r *productAttributeValueRepository) GetByProductID(productID uint) ([]*domain.ProductAttributeValue, error) {
+func (r *productAttributeValueRepository) GetByProductID(ctx context.Context, productID uint) ([]*domain.ProductAttributeValue, error) {
 	var values []*domain.ProductAttributeValue
-	err := r.db.Where("product_id = ?", productID).Find(&values).Error
+	err := r.db.WithContext(ctx).Where("product_id = ?", productID).Find(&values).Error
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// GetByProductIDs retrieves all attribute values for a set of products
+func (r *productAttributeValueRepository) GetByProductIDs(ctx context.Context, productIDs []uint) ([]*domain.ProductAttributeValue, error) {
+	var values []*domain.ProductAttributeValue
+	if len(productIDs) == 0 {
+		return values, nil
+	}
+	err := r.db.WithContext(ctx).Where("product_id IN ?", productIDs).Find(&values).Error
 	if err != nil {
 		return nil, err
 	}
@@ -52,9 +66,9 @@ func (r *productAttributeValueRepository) GetByProductID(productID uint) ([]*dom
 }
 
 // GetByAttributeID retrieves all values for a specific attribute
-func (r *productAttributeValueRepository) GetByAttributeID(attributeID uint) ([]*domain.ProductAttributeValue, error) {
+func (r *productAttributeValueRepository) GetByAttributeID(ctx context.Context, attributeID uint) ([]*domain.ProductAttributeValue, error) {
 	var values []*domain.ProductAttributeValue
-	err := r.db.Where("attribute_id = ?", attributeID).Find(&values).Error
+	err := r.db.WithContext(ctx).Where("attribute_id = ?", attributeID).Find(&values).Error
 	if err != nil {
 		return nil, err
 	}
@@ -63,9 +77,9 @@ func (r *productAttributeValueRepository) GetByAttributeID(attributeID uint) ([]
 
 // SearchByAttributeValue searches for products by attribute value
 // This uses the compound index (attribute_id, value) for fast search
-func (r *productAttributeValueRepository) SearchByAttributeValue(attributeID uint, value string) ([]*domain.ProductAttributeValue, error) {
+func (r *productAttributeValueRepository) SearchByAttributeValue(ctx context.Context, attributeID uint, value string) ([]*domain.ProductAttributeValue, error) {
 	var values []*domain.ProductAttributeValue
-	err := r.db.Where("attribute_id = ? AND value = ?", attributeID, value).Find(&values).Error
+	err := r.db.WithContext(ctx).Where("attribute_id = ? AND value = ?", attributeID, value).Find(&values).Error
 	if err != nil {
 		return nil, err
 	}
@@ -73,12 +87,12 @@ func (r *productAttributeValueRepository) SearchByAttributeValue(attributeID uin
 }
 
 // Delete deletes a product attribute value
-func (r *productAttributeValueRepository) Delete(id uint) error {
-	return r.db.Delete(&domain.ProductAttributeValue{}, id).Error
+func (r *productAttributeValueRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.ProductAttributeValue{}, id).Error
 }
 
 // DeleteByProductID deletes all attribute values for a product
-func (r *productAttributeValueRepository) DeleteByProductID(productID uint) error {
-	return r.db.Where("product_id = ?", productID).Delete(&domain.ProductAttributeValue{}).Error
+func (r *productAttributeValueRepository) DeleteByProductID(ctx context.Context, productID uint) error {
+	return r.db.WithContext(ctx).Where("product_id = ?", productID).Delete(&domain.ProductAttributeValue{}).Error
 }
 
@@ -1,9 +1,11 @@
 package postgres
 
 import (
+	"context"
 	"product-service/internal/domain"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // productItemRepository implements the ProductItemRepository interface
@@ -17,19 +19,35 @@ func NewProductItemRepository(db *gorm.DB) domain.ProductItemRepository {
 }
 
 // Create inserts a new product item (SKU) into the database
-func (r *productItemRepository) Create(item *domain.ProductItem) error {
-	return r.db.Create(item).Error
+func (r *productItemRepository) Create(ctx context.Context, item *domain.ProductItem) error {
+	return r.db.WithContext(ctx).Create(item).Error
 }
 
-// Update updates an existing product item
-func (r *productItemRepository) Update(item *domain.ProductItem) error {
-	return r.db.Save(item).Error
+// Update updates an existing product item if its version is unchanged (optimistic locking)
+// The version is bumped on success; returns domain.ErrVersionConflict if another update won
+func (r *productItemRepository) Update(ctx context.Context, item *domain.ProductItem) error {
+	expected := item.Version
+	item.Version++
+
+	result := r.db.WithContext(ctx).
+		Model(item).
+		Where("version = ?", expected).
+		Select("*").
+		Updates(item)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = domain.ErrVersionConflict
+	}
+	if result.Error != nil {
+		item.Version = expected
+		return result.Error
+	}
+	return nil
 }
 
 // GetByID retrieves a product item by its ID
-func (r *productItemRepository) GetByID(id uint) (*domain.ProductItem, error) {
+func (r *productItemRepository) GetByID(ctx context.Context, id uint) (*domain.ProductItem, error) {
 	var item domain.ProductItem
-	err := r.db.First(&item, id).Error
+	err := r.db.WithContext(ctx).First(&item, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -37,32 +55,108 @@ func (r *productItemRepository) GetByID(id uint) (*domain.ProductItem, error) {
 }
 
 // GetBySKUCode retrieves a product item by its SKU code
-func (r *productItemRepository) GetBySKUCode(skuCode string) (*domain.ProductItem, error) {
+func (r *productItemRepository) GetBySKUCode(ctx context.Context, skuCode string) (*domain.ProductItem, error) {
+	var item domain.ProductItem
+	err := r.db.WithContext(ctx).Where("sku_code = ?", skuCode).First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// GetByIDWithDeleted retrieves a product item by ID, including soft-deleted ones
+func (r *productItemRepository) GetByIDWithDeleted(ctx context.Context, id uint) (*domain.ProductItem, error) {
 	var item domain.ProductItem
-	err := r.db.Where("sku_code = ?", skuCode).First(&item).Error
+	err := r.db.WithContext(ctx).Unscoped().First(&item, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &item, nil
 }
 
+// ExistsBySKUCode checks if a SKU code is used, including by soft-deleted SKUs
+func (r *productItemRepository) ExistsBySKUCode(ctx context.Context, skuCode string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Unscoped().Model(&domain.ProductItem{}).Where("sku_code = ?", skuCode).Count(&count).Error
+	return count > 0, err
+}
+
 // GetByProductID retrieves all product items (SKUs) for a product
-func (r *productItemRepository) GetByProductID(productID uint) ([]*domain.ProductItem, error) {
+func (r *productItemRepository) GetByProductID(ctx context.Context, productID uint) ([]*domain.ProductItem, error) {
 	var items []*domain.ProductItem
-	err := r.db.Where("product_id = ?", productID).Find(&items).Error
+	err := r.db.WithContext(ctx).Where("product_id = ?", productID).Find(&items).Error
 	if err != nil {
 		return nil, err
 	}
 	return items, nil
 }
 
-// Delete deletes a product item
-func (r *productItemRepository) Delete(id uint) error {
-	return r.db.Delete(&domain.ProductItem{}, id).Error
+// GetByProductIDs retrieves all product items (SKUs) for a set of products
+func (r *productItemRepository) GetByProductIDs(ctx context.Context, productIDs []uint) ([]*domain.ProductItem, error) {
+	var items []*domain.ProductItem
+	if len(productIDs) == 0 {
+		return items, nil
+	}
+	err := r.db.WithContext(ctx).Where("product_id IN ?", productIDs).Order("product_id, id").Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Delete soft-deletes a product item and marks it DISCONTINUED
+// The row stays so order lines and reports can still resolve the SKU
+func (r *productItemRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&domain.ProductItem{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"status":  domain.ProductItemStatusDiscontinued,
+			"version": gorm.Expr("version + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&domain.ProductItem{}, id).Error
+	})
+}
+
+// Restore undoes a soft delete; the status is derived from the current stock
+func (r *productItemRepository) Restore(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Unscoped().Model(&domain.ProductItem{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"status":     gorm.Expr("CASE WHEN qty_in_stock > 0 THEN ? ELSE ? END", domain.ProductItemStatusActive, domain.ProductItemStatusOutOfStock),
+		"version":    gorm.Expr("version + 1"),
+	}).Error
+}
+
+// DeductStock takes quantity units in a single UPDATE ... WHERE qty_in_stock >= quantity,
+// so concurrent deductions cannot read the same stock and oversell. The SKU becomes
+// OUT_OF_STOCK when it reaches 0. Returns the updated item, or false when the SKU does
+// not exist or has less than quantity in stock (nothing is changed then)
+func (r *productItemRepository) DeductStock(ctx context.Context, id uint, quantity int) (*domain.ProductItem, bool, error) {
+	var item domain.ProductItem
+	result := r.db.WithContext(ctx).
+		Model(&item).
+		Clauses(clause.Returning{}).
+		Where("id = ? AND qty_in_stock >= ?", id, quantity).
+		Updates(map[string]interface{}{
+			"qty_in_stock": gorm.Expr("qty_in_stock - ?", quantity),
+			"status":       gorm.Expr("CASE WHEN qty_in_stock = ? THEN ? ELSE status END", quantity, domain.ProductItemStatusOutOfStock),
+			"version":      gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return nil, false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, false, nil
+	}
+	return &item, true, nil
 }
 
 // UpdateStock updates the stock quantity atomically
-func (r *productItemRepository) UpdateStock(id uint, quantity int) error {
-	return r.db.Model(&domain.ProductItem{}).Where("id = ?", id).Update("qty_in_stock", quantity).Error
+func (r *productItemRepository) UpdateStock(ctx context.Context, id uint, quantity int) error {
+	return r.db.WithContext(ctx).Model(&domain.ProductItem{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"qty_in_stock": quantity,
+		"version":      gorm.Expr("version + 1"),
+	}).Error
 }
 
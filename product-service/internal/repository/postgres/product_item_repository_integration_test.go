@@ -0,0 +1,107 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"product-service/internal/domain"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gormpostgres "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Run with: TEST_DATABASE_DSN=postgres://... go test -tags integration ./internal/repository/postgres
+// (docker compose -f docker-compose.test.yml up -d starts one)
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+	db, err := gorm.Open(gormpostgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	if err := db.AutoMigrate(&domain.ProductItem{}); err != nil {
+		t.Fatalf("failed to migrate product_item: %v", err)
+	}
+	return db
+}
+
+// TestDeductStockConcurrentNoOversell hammers one SKU from many connections:
+// the conditional UPDATE must never let the stock go below zero
+func TestDeductStockConcurrentNoOversell(t *testing.T) {
+	db := openTestDB(t)
+	repo := NewProductItemRepository(db)
+	ctx := context.Background()
+
+	const (
+		initialStock = 50
+		buyers       = 40
+		perBuyer     = 4
+	)
+	item := &domain.ProductItem{
+		ProductID:  1,
+		SKUCode:    fmt.Sprintf("OVERSELL-%d", time.Now().UnixNano()),
+		Price:      10,
+		QtyInStock: initialStock,
+		Status:     domain.ProductItemStatusActive,
+		Version:    1,
+	}
+	if err := db.Create(item).Error; err != nil {
+		t.Fatalf("failed to create item: %v", err)
+	}
+	t.Cleanup(func() { db.Unscoped().Delete(&domain.ProductItem{}, item.ID) })
+
+	var (
+		deducted atomic.Int64
+		negative atomic.Bool
+		wg       sync.WaitGroup
+	)
+	for b := 0; b < buyers; b++ {
+		wg.Add(1)
+		go func(b int) {
+			defer wg.Done()
+			for i := 0; i < perBuyer; i++ {
+				quantity := 1 + (b+i)%3
+				updated, ok, err := repo.DeductStock(ctx, item.ID, quantity)
+				if err != nil {
+					t.Errorf("deduct stock: %v", err)
+					return
+				}
+				if !ok {
+					continue
+				}
+				deducted.Add(int64(quantity))
+				if updated.QtyInStock < 0 {
+					negative.Store(true)
+				}
+			}
+		}(b)
+	}
+	wg.Wait()
+
+	final, err := repo.GetByID(ctx, item.ID)
+	if err != nil {
+		t.Fatalf("failed to reload item: %v", err)
+	}
+	if negative.Load() || final.QtyInStock < 0 {
+		t.Fatalf("stock went negative: %d", final.QtyInStock)
+	}
+	if got := deducted.Load(); got > initialStock {
+		t.Fatalf("deducted %d units, only %d were in stock", got, initialStock)
+	}
+	if want := initialStock - int(deducted.Load()); final.QtyInStock != want {
+		t.Errorf("stock left = %d, want %d", final.QtyInStock, want)
+	}
+	if final.QtyInStock == 0 && final.Status != domain.ProductItemStatusOutOfStock {
+		t.Errorf("status = %s, want %s once sold out", final.Status, domain.ProductItemStatusOutOfStock)
+	}
+}
@@ -1,9 +1,11 @@
 package postgres
 
 import (
+	"context"
 	"product-service/internal/domain"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // productRepository implements the ProductRepository interface
@@ -19,42 +21,153 @@ func NewProductRepository(db *gorm.DB) domain.ProductRepository {
 }
 
 // Create inserts a new product into the database
-func (r *productRepository) Create(product *domain.Product) error {
-	return r.db.Create(product).Error
+func (r *productRepository) Create(ctx context.Context, product *domain.Product) error {
+	return conn(ctx, r.db).Create(product).Error
 }
 
-// Update updates an existing product
-func (r *productRepository) Update(product *domain.Product) error {
-	return r.db.Save(product).Error
+// Update updates an existing product if its version is unchanged (optimistic locking)
+// The version is bumped on success; returns domain.ErrVersionConflict if another update won
+func (r *productRepository) Update(ctx context.Context, product *domain.Product) error {
+	expected := product.Version
+	product.Version++
+
+	result := conn(ctx, r.db).
+		Model(product).
+		Where("version = ?", expected).
+		Select("*").
+		Omit(clause.Associations, "created_at", "rating_avg", "rating_count", "sold_count"). // Rating and sales have their own writers
+		Updates(product)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = domain.ErrVersionConflict
+	}
+	if result.Error != nil {
+		product.Version = expected
+		return result.Error
+	}
+	return nil
+}
+
+// UpdateRating writes the rating snapshot without touching the optimistic lock version,
+// so review activity never conflicts with seller edits
+func (r *productRepository) UpdateRating(ctx context.Context, id uint, avg float64, count int) error {
+	result := conn(ctx, r.db).
+		Model(&domain.Product{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"rating_avg": avg, "rating_count": count})
+	if result.Error == nil && result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
 }
 
 // GetByID retrieves a product by its ID
-func (r *productRepository) GetByID(id uint) (*domain.Product, error) {
+func (r *productRepository) GetByID(ctx context.Context, id uint) (*domain.Product, error) {
 	var product domain.Product
-	err := r.db.First(&product, id).Error
+	err := conn(ctx, r.db).First(&product, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &product, nil
 }
 
+// GetByIDs retrieves the products with the given IDs (missing IDs are skipped)
+func (r *productRepository) GetByIDs(ctx context.Context, ids []uint) ([]*domain.Product, error) {
+	var products []*domain.Product
+	if len(ids) == 0 {
+		return products, nil
+	}
+	err := conn(ctx, r.db).Where("id IN ?", ids).Find(&products).Error
+	return products, err
+}
+
+// GetBySlug retrieves a product by its current slug
+func (r *productRepository) GetBySlug(ctx context.Context, slug string) (*domain.Product, error) {
+	var product domain.Product
+	err := conn(ctx, r.db).Where("slug = ?", slug).First(&product).Error
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// ExistsBySlug checks if a slug is already used by another product
+func (r *productRepository) ExistsBySlug(ctx context.Context, slug string, excludeID uint) (bool, error) {
+	var count int64
+	query := conn(ctx, r.db).Model(&domain.Product{}).Where("slug = ?", slug)
+	if excludeID > 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 // GetAll retrieves all products
-func (r *productRepository) GetAll() ([]*domain.Product, error) {
+func (r *productRepository) GetAll(ctx context.Context) ([]*domain.Product, error) {
 	var products []*domain.Product
-	err := r.db.Find(&products).Error
+	err := conn(ctx, r.db).Find(&products).Error
 	if err != nil {
 		return nil, err
 	}
 	return products, nil
 }
 
+// ListAfterID retrieves the next page of products ordered by ID (keyset pagination)
+// Stable for full-table scans, unlike OFFSET which slows down on deep pages
+func (r *productRepository) ListAfterID(ctx context.Context, afterID uint, limit int) ([]*domain.Product, error) {
+	var products []*domain.Product
+	err := conn(ctx, r.db).Preload("Category").Where("id > ?", afterID).Order("id ASC").Limit(limit).Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// Count returns the total number of products
+func (r *productRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&domain.Product{}).Count(&count).Error
+	return count, err
+}
+
+// listingColumns are the columns needed to render product cards
+// images is reduced to its first element (thumbnail) to avoid shipping the full JSON array
+var listingColumns = []string{
+	"id", "shop_id", "name", "slug", "base_price", "category_id", "status", "is_active", "sold_count", "rating_avg", "rating_count", "created_at", "updated_at",
+	"COALESCE(jsonb_path_query_array(images, '$[0]'), '[]'::jsonb) AS images",
+}
+
+// applyListOptions adds column selection and preloads to a listing query
+func applyListOptions(query *gorm.DB, opts []domain.ProductListOption) *gorm.DB {
+	o := domain.NewProductListOptions(opts...)
+	if o.ListingColumns {
+		query = query.Select(listingColumns)
+	}
+	if o.PreloadCategory {
+		query = query.Preload("Category")
+	}
+	if o.PreloadItems {
+		query = query.Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") })
+	}
+	return query
+}
+
+// countListing runs the COUNT(*) of a listing query unless the caller already knows the total
+func countListing(query *gorm.DB, opts []domain.ProductListOption, total *int64) error {
+	if domain.NewProductListOptions(opts...).SkipCount {
+		return nil
+	}
+	return query.Count(total).Error
+}
+
 // ListProducts retrieves products with pagination and filters
-func (r *productRepository) ListProducts(filters map[string]interface{}, page, limit int) ([]*domain.Product, int64, error) {
+func (r *productRepository) ListProducts(ctx context.Context, filters map[string]interface{}, page, limit int, opts ...domain.ProductListOption) ([]*domain.Product, int64, error) {
 	var products []*domain.Product
 	var total int64
 
 	// Build query with filters
-	query := r.db.Model(&domain.Product{})
+	query := conn(ctx, r.db).Model(&domain.Product{})
 
 	// Apply filters
 	if categoryID, ok := filters["category_id"]; ok {
@@ -64,23 +177,31 @@ func (r *productRepository) ListProducts(filters map[string]interface{}, page, l
 		query = query.Where("status = ?", status)
 	}
 	if minPrice, ok := filters["min_price"]; ok {
-		query = query.Where("price >= ?", minPrice)
+		query = query.Where("base_price >= ?", minPrice)
 	}
 	if maxPrice, ok := filters["max_price"]; ok {
-		query = query.Where("price <= ?", maxPrice)
+		query = query.Where("base_price <= ?", maxPrice)
+	}
+	if minRating, ok := filters["min_rating"]; ok {
+		query = query.Where("rating_avg >= ?", minRating)
 	}
 	if search, ok := filters["search"]; ok {
 		query = query.Where("name ILIKE ? OR description ILIKE ?", "%"+search.(string)+"%", "%"+search.(string)+"%")
 	}
 
 	// Count total (before pagination)
-	if err := query.Count(&total).Error; err != nil {
+	if err := countListing(query, opts, &total); err != nil {
 		return nil, 0, err
 	}
 
+	// Apply ordering (popular = best sellers first)
+	if ranking, ok := filters["ranking"]; ok && ranking == "popular" {
+		query = query.Order("sold_count DESC").Order("created_at DESC")
+	}
+
 	// Apply pagination
 	offset := (page - 1) * limit
-	if err := query.Offset(offset).Limit(limit).Find(&products).Error; err != nil {
+	if err := applyListOptions(query, opts).Offset(offset).Limit(limit).Find(&products).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -88,18 +209,18 @@ func (r *productRepository) ListProducts(filters map[string]interface{}, page, l
 }
 
 // GetProductsByCategory retrieves products by category ID with pagination
-func (r *productRepository) GetProductsByCategory(categoryID uint, page, limit int) ([]*domain.Product, int64, error) {
+func (r *productRepository) GetProductsByCategory(ctx context.Context, categoryID uint, page, limit int, opts ...domain.ProductListOption) ([]*domain.Product, int64, error) {
 	var products []*domain.Product
 	var total int64
 
 	// Count total
-	if err := r.db.Model(&domain.Product{}).Where("category_id = ?", categoryID).Count(&total).Error; err != nil {
+	if err := conn(ctx, r.db).Model(&domain.Product{}).Where("category_id = ?", categoryID).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// Get products with pagination
 	offset := (page - 1) * limit
-	if err := r.db.Where("category_id = ?", categoryID).Offset(offset).Limit(limit).Find(&products).Error; err != nil {
+	if err := applyListOptions(conn(ctx, r.db), opts).Where("category_id = ?", categoryID).Offset(offset).Limit(limit).Find(&products).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -108,43 +229,53 @@ func (r *productRepository) GetProductsByCategory(categoryID uint, page, limit i
 
 // GetProductsByCategoryIDs retrieves products by multiple category IDs with pagination
 // Used for fetching products from parent category + all children
-func (r *productRepository) GetProductsByCategoryIDs(categoryIDs []uint, page, limit int) ([]*domain.Product, int64, error) {
+func (r *productRepository) GetProductsByCategoryIDs(ctx context.Context, categoryIDs []uint, page, limit int, opts ...domain.ProductListOption) ([]*domain.Product, int64, error) {
 	var products []*domain.Product
 	var total int64
 
 	// Count total
-	if err := r.db.Model(&domain.Product{}).Where("category_id IN ?", categoryIDs).Count(&total).Error; err != nil {
+	if err := countListing(conn(ctx, r.db).Model(&domain.Product{}).Where("category_id IN ?", categoryIDs), opts, &total); err != nil {
 		return nil, 0, err
 	}
 
 	// Get products with pagination
 	offset := (page - 1) * limit
-	if err := r.db.Where("category_id IN ?", categoryIDs).Offset(offset).Limit(limit).Find(&products).Error; err != nil {
+	if err := applyListOptions(conn(ctx, r.db), opts).Where("category_id IN ?", categoryIDs).Offset(offset).Limit(limit).Find(&products).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return products, total, nil
 }
 
+// ShopIDsByCategoryIDs returns the IDs of the shops selling an ACTIVE product in the categories
+func (r *productRepository) ShopIDsByCategoryIDs(ctx context.Context, categoryIDs []uint) ([]uint, error) {
+	var shopIDs []uint
+	err := conn(ctx, r.db).Model(&domain.Product{}).
+		Where("category_id IN ? AND status = ?", categoryIDs, domain.ProductStatusActive).
+		Distinct().Order("shop_id").Pluck("shop_id", &shopIDs).Error
+	return shopIDs, err
+}
+
 // Delete soft deletes a product (or hard delete based on your business logic)
-func (r *productRepository) Delete(id uint) error {
-	return r.db.Delete(&domain.Product{}, id).Error
+func (r *productRepository) Delete(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&domain.Product{}, id).Error
 }
 
 // GetProductsByShopID retrieves products by shop ID with pagination
-func (r *productRepository) GetProductsByShopID(shopID uint, page, limit int) ([]*domain.Product, int64, error) {
+func (r *productRepository) GetProductsByShopID(ctx context.Context, shopID uint, page, limit int, opts ...domain.ProductListOption) ([]*domain.Product, int64, error) {
 	var products []*domain.Product
 	var total int64
 
 	offset := (page - 1) * limit
 
 	// Count total
-	if err := r.db.Model(&domain.Product{}).Where("shop_id = ?", shopID).Count(&total).Error; err != nil {
+	if err := conn(ctx, r.db).Model(&domain.Product{}).Where("shop_id = ?", shopID).Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	// Get paginated results with preloaded Category
-	if err := r.db.Preload("Category").Where("shop_id = ?", shopID).
+	// Get paginated results (Category is always preloaded for shop listings)
+	opts = append(opts, domain.WithCategory())
+	if err := applyListOptions(conn(ctx, r.db), opts).Where("shop_id = ?", shopID).
 		Offset(offset).Limit(limit).Find(&products).Error; err != nil {
 		return nil, 0, err
 	}
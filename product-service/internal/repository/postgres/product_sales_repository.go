@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"product-service/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// productSalesRepository implements the ProductSalesRepository interface
+type productSalesRepository struct {
+	db *gorm.DB
+}
+
+// NewProductSalesRepository creates a new PostgreSQL product sales repository
+func NewProductSalesRepository(db *gorm.DB) domain.ProductSalesRepository {
+	return &productSalesRepository{db: db}
+}
+
+// UpsertDaily inserts the day totals or raises the stored ones (never lowers them)
+func (r *productSalesRepository) UpsertDaily(ctx context.Context, rows []*domain.ProductSalesDaily) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "product_id"}, {Name: "day"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"units":       gorm.Expr("GREATEST(product_sales_daily.units, excluded.units)"),
+			"shop_id":     gorm.Expr("excluded.shop_id"),
+			"category_id": gorm.Expr("excluded.category_id"),
+			"updated_at":  gorm.Expr("excluded.updated_at"),
+		}),
+	}).CreateInBatches(rows, 500).Error
+}
+
+// RefreshSoldCounts recomputes sold_count from the daily totals (without bumping the version)
+func (r *productSalesRepository) RefreshSoldCounts(ctx context.Context, productIDs []uint) error {
+	if len(productIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Exec(`
+		UPDATE products SET sold_count = totals.units
+		FROM (
+			SELECT product_id, SUM(units) AS units
+			FROM product_sales_daily
+			WHERE product_id IN ?
+			GROUP BY product_id
+		) AS totals
+		WHERE products.id = totals.product_id`, productIDs).Error
+}
@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+	"product-service/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// productSlugHistoryRepository implements the ProductSlugHistoryRepository interface
+type productSlugHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewProductSlugHistoryRepository creates a new PostgreSQL product slug history repository
+func NewProductSlugHistoryRepository(db *gorm.DB) domain.ProductSlugHistoryRepository {
+	return &productSlugHistoryRepository{db: db}
+}
+
+// Create inserts a new slug history record
+func (r *productSlugHistoryRepository) Create(ctx context.Context, history *domain.ProductSlugHistory) error {
+	return r.db.WithContext(ctx).Create(history).Error
+}
+
+// GetBySlug retrieves a slug history record by old slug
+func (r *productSlugHistoryRepository) GetBySlug(ctx context.Context, slug string) (*domain.ProductSlugHistory, error) {
+	var history domain.ProductSlugHistory
+	err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&history).Error
+	if err != nil {
+		return nil, err
+	}
+	return &history, nil
+}
+
+// DeleteBySlug removes a slug history record (used when a product reclaims an old slug)
+func (r *productSlugHistoryRepository) DeleteBySlug(ctx context.Context, slug string) error {
+	return r.db.WithContext(ctx).Where("slug = ?", slug).Delete(&domain.ProductSlugHistory{}).Error
+}
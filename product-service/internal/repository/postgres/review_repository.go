@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"math"
+	"product-service/internal/domain"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// reviewRepository implements the ReviewRepository interface
+type reviewRepository struct {
+	db *gorm.DB
+}
+
+// NewReviewRepository creates a new PostgreSQL review repository
+func NewReviewRepository(db *gorm.DB) domain.ReviewRepository {
+	return &reviewRepository{db: db}
+}
+
+// Create inserts a review; returns domain.ErrReviewExists if the buyer already reviewed the product
+func (r *reviewRepository) Create(ctx context.Context, review *domain.Review) error {
+	err := r.db.WithContext(ctx).Create(review).Error
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return domain.ErrReviewExists
+	}
+	return err
+}
+
+// Update saves the editable fields of a review
+func (r *reviewRepository) Update(ctx context.Context, review *domain.Review) error {
+	return r.db.WithContext(ctx).
+		Model(review).
+		Select("rating", "comment", "images", "updated_at").
+		Updates(review).Error
+}
+
+// Delete removes a review
+func (r *reviewRepository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&domain.Review{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetByID retrieves a review by ID
+func (r *reviewRepository) GetByID(ctx context.Context, id uint) (*domain.Review, error) {
+	var review domain.Review
+	if err := r.db.WithContext(ctx).First(&review, id).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// ListByProduct retrieves a page of the reviews of a product
+func (r *reviewRepository) ListByProduct(ctx context.Context, productID uint, filter domain.ReviewFilter) ([]*domain.Review, int64, error) {
+	return r.list(ctx, r.db.WithContext(ctx).Where("product_id = ?", productID), filter)
+}
+
+// ListByShop retrieves a page of the reviews of all products of a shop
+func (r *reviewRepository) ListByShop(ctx context.Context, shopID uint, filter domain.ReviewFilter) ([]*domain.Review, int64, error) {
+	return r.list(ctx, r.db.WithContext(ctx).Where("shop_id = ?", shopID), filter)
+}
+
+// list applies the star filter, counts the matches and loads the requested page
+func (r *reviewRepository) list(ctx context.Context, query *gorm.DB, filter domain.ReviewFilter) ([]*domain.Review, int64, error) {
+	query = query.Model(&domain.Review{})
+	if filter.Rating > 0 {
+		query = query.Where("rating = ?", filter.Rating)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	switch filter.Sort {
+	case domain.ReviewSortRatingDesc:
+		query = query.Order("rating DESC, id DESC")
+	case domain.ReviewSortRatingAsc:
+		query = query.Order("rating ASC, id DESC")
+	default:
+		query = query.Order("created_at DESC, id DESC")
+	}
+
+	var reviews []*domain.Review
+	offset := (filter.Page - 1) * filter.Limit
+	if err := query.Offset(offset).Limit(filter.Limit).Find(&reviews).Error; err != nil {
+		return nil, 0, err
+	}
+	return reviews, total, nil
+}
+
+// ProductSummary aggregates the reviews of a product
+func (r *reviewRepository) ProductSummary(ctx context.Context, productID uint) (*domain.RatingSummary, error) {
+	return r.summary(r.db.WithContext(ctx).Where("product_id = ?", productID))
+}
+
+// ShopSummary aggregates the reviews of all products of a shop
+func (r *reviewRepository) ShopSummary(ctx context.Context, shopID uint) (*domain.RatingSummary, error) {
+	return r.summary(r.db.WithContext(ctx).Where("shop_id = ?", shopID))
+}
+
+// ShopRatings aggregates the reviews of every shop that has any, averages rounded as in summary
+func (r *reviewRepository) ShopRatings(ctx context.Context) ([]domain.ShopRating, error) {
+	var rows []struct {
+		ShopID uint
+		Total  int
+		Count  int
+	}
+	err := r.db.WithContext(ctx).
+		Model(&domain.Review{}).
+		Select("shop_id, SUM(rating) AS total, COUNT(*) AS count").
+		Group("shop_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ratings := make([]domain.ShopRating, 0, len(rows))
+	for _, row := range rows {
+		ratings = append(ratings, domain.ShopRating{
+			ShopID:      row.ShopID,
+			RatingAvg:   math.Round(float64(row.Total)/float64(row.Count)*100) / 100,
+			RatingCount: row.Count,
+		})
+	}
+	return ratings, nil
+}
+
+// summary counts the reviews per star and derives the average from the counts
+func (r *reviewRepository) summary(query *gorm.DB) (*domain.RatingSummary, error) {
+	var rows []struct {
+		Rating int
+		Count  int
+	}
+	err := query.Model(&domain.Review{}).
+		Select("rating, COUNT(*) AS count").
+		Group("rating").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &domain.RatingSummary{Stars: map[int]int{1: 0, 2: 0, 3: 0, 4: 0, 5: 0}}
+	total := 0
+	for _, row := range rows {
+		summary.Stars[row.Rating] = row.Count
+		summary.RatingCount += row.Count
+		total += row.Rating * row.Count
+	}
+	if summary.RatingCount > 0 {
+		summary.RatingAvg = math.Round(float64(total)/float64(summary.RatingCount)*100) / 100
+	}
+	return summary, nil
+}
@@ -0,0 +1,174 @@
+package postgres
+
+import (
+	"context"
+	"product-service/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// shopCollectionRepository implements the ShopCollectionRepository interface
+type shopCollectionRepository struct {
+	db *gorm.DB
+}
+
+// NewShopCollectionRepository creates a new PostgreSQL shop collection repository
+func NewShopCollectionRepository(db *gorm.DB) domain.ShopCollectionRepository {
+	return &shopCollectionRepository{db: db}
+}
+
+// activeCollectionProducts joins collection assignments with the products a storefront may show
+func (r *shopCollectionRepository) activeCollectionProducts(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Table("shop_collection_product").
+		Joins("JOIN products ON products.id = shop_collection_product.product_id").
+		Where("products.status = ? AND products.is_active = ?", "ACTIVE", true)
+}
+
+// Create creates a new shop collection
+func (r *shopCollectionRepository) Create(ctx context.Context, collection *domain.ShopCollection) error {
+	return r.db.WithContext(ctx).Create(collection).Error
+}
+
+// Update saves the editable fields of a shop collection
+func (r *shopCollectionRepository) Update(ctx context.Context, collection *domain.ShopCollection) error {
+	return r.db.WithContext(ctx).
+		Model(collection).
+		Select("name", "description", "is_visible", "updated_at").
+		Updates(collection).Error
+}
+
+// Delete removes a shop collection and its product assignments
+func (r *shopCollectionRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id = ?", id).Delete(&domain.ShopCollectionProduct{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&domain.ShopCollection{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+// GetByID retrieves a shop collection by ID
+func (r *shopCollectionRepository) GetByID(ctx context.Context, id uint) (*domain.ShopCollection, error) {
+	var collection domain.ShopCollection
+	if err := r.db.WithContext(ctx).First(&collection, id).Error; err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
+
+// ListByShop retrieves the collections of a shop in storefront order
+func (r *shopCollectionRepository) ListByShop(ctx context.Context, shopID uint, visibleOnly bool) ([]*domain.ShopCollection, error) {
+	var collections []*domain.ShopCollection
+	query := r.db.WithContext(ctx).Where("shop_id = ?", shopID)
+	if visibleOnly {
+		query = query.Where("is_visible = ?", true)
+	}
+	err := query.Order("position ASC, id ASC").Find(&collections).Error
+	return collections, err
+}
+
+// CountByShop counts the collections of a shop
+func (r *shopCollectionRepository) CountByShop(ctx context.Context, shopID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.ShopCollection{}).Where("shop_id = ?", shopID).Count(&count).Error
+	return count, err
+}
+
+// MaxPosition returns the highest collection position of a shop, -1 if it has none
+func (r *shopCollectionRepository) MaxPosition(ctx context.Context, shopID uint) (int, error) {
+	var position int
+	err := r.db.WithContext(ctx).
+		Model(&domain.ShopCollection{}).
+		Select("COALESCE(MAX(position), -1)").
+		Where("shop_id = ?", shopID).
+		Scan(&position).Error
+	return position, err
+}
+
+// Reorder sets each collection's position to its index in ids (in one transaction)
+func (r *shopCollectionRepository) Reorder(ctx context.Context, shopID uint, ids []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for position, id := range ids {
+			if err := tx.Model(&domain.ShopCollection{}).
+				Where("id = ? AND shop_id = ?", id, shopID).
+				Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetProducts replaces the products of a collection (in one transaction)
+func (r *shopCollectionRepository) SetProducts(ctx context.Context, collectionID uint, productIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id = ?", collectionID).Delete(&domain.ShopCollectionProduct{}).Error; err != nil {
+			return err
+		}
+		if len(productIDs) == 0 {
+			return nil
+		}
+
+		rows := make([]domain.ShopCollectionProduct, len(productIDs))
+		for i, productID := range productIDs {
+			rows[i] = domain.ShopCollectionProduct{
+				CollectionID: collectionID,
+				ProductID:    productID,
+				Position:     i,
+			}
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// ListProductIDs returns a page of the collection's ACTIVE products in collection order
+func (r *shopCollectionRepository) ListProductIDs(ctx context.Context, collectionID uint, page, limit int) ([]uint, int64, error) {
+	var total int64
+	if err := r.activeCollectionProducts(ctx).
+		Where("shop_collection_product.collection_id = ?", collectionID).
+		Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var ids []uint
+	err := r.activeCollectionProducts(ctx).
+		Where("shop_collection_product.collection_id = ?", collectionID).
+		Order("shop_collection_product.position ASC, shop_collection_product.product_id ASC").
+		Offset((page-1)*limit).
+		Limit(limit).
+		Pluck("shop_collection_product.product_id", &ids).Error
+	return ids, total, err
+}
+
+// CountActiveProducts counts the ACTIVE products of each collection (missing = 0)
+func (r *shopCollectionRepository) CountActiveProducts(ctx context.Context, collectionIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(collectionIDs))
+	if len(collectionIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		CollectionID uint
+		Count        int64
+	}
+	err := r.activeCollectionProducts(ctx).
+		Select("shop_collection_product.collection_id, COUNT(*) AS count").
+		Where("shop_collection_product.collection_id IN ?", collectionIDs).
+		Group("shop_collection_product.collection_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.CollectionID] = row.Count
+	}
+	return counts, nil
+}
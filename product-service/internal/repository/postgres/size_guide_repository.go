@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"context"
+	"product-service/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// sizeGuideRepository implements the SizeGuideRepository interface
+type sizeGuideRepository struct {
+	db *gorm.DB
+}
+
+// NewSizeGuideRepository creates a new PostgreSQL size guide repository
+func NewSizeGuideRepository(db *gorm.DB) domain.SizeGuideRepository {
+	return &sizeGuideRepository{db: db}
+}
+
+// GetChartByCategoryID retrieves the size chart of a category
+func (r *sizeGuideRepository) GetChartByCategoryID(ctx context.Context, categoryID uint) (*domain.SizeChart, error) {
+	var chart domain.SizeChart
+	if err := r.db.WithContext(ctx).Where("category_id = ?", categoryID).First(&chart).Error; err != nil {
+		return nil, err
+	}
+	return &chart, nil
+}
+
+// GetChartsByCategoryIDs retrieves the size charts of several categories (missing ones are skipped)
+func (r *sizeGuideRepository) GetChartsByCategoryIDs(ctx context.Context, categoryIDs []uint) ([]*domain.SizeChart, error) {
+	var charts []*domain.SizeChart
+	if len(categoryIDs) == 0 {
+		return charts, nil
+	}
+	err := r.db.WithContext(ctx).Where("category_id IN ?", categoryIDs).Find(&charts).Error
+	return charts, err
+}
+
+// UpsertChart creates the category's size chart or replaces it
+func (r *sizeGuideRepository) UpsertChart(ctx context.Context, chart *domain.SizeChart) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "category_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "unit", "measurements", "sizes", "notes", "updated_at"}),
+	}, clause.Returning{}).Create(chart).Error
+}
+
+// DeleteChart removes the category's size chart
+func (r *sizeGuideRepository) DeleteChart(ctx context.Context, categoryID uint) error {
+	result := r.db.WithContext(ctx).Where("category_id = ?", categoryID).Delete(&domain.SizeChart{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetMeasurement retrieves the measurement table of a product
+func (r *sizeGuideRepository) GetMeasurement(ctx context.Context, productID uint) (*domain.ProductMeasurement, error) {
+	var m domain.ProductMeasurement
+	if err := r.db.WithContext(ctx).First(&m, "product_id = ?", productID).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// UpsertMeasurement creates the product's measurement table or replaces it
+func (r *sizeGuideRepository) UpsertMeasurement(ctx context.Context, m *domain.ProductMeasurement) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "product_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"unit", "sizes", "notes", "updated_at"}),
+	}).Create(m).Error
+}
+
+// DeleteMeasurement removes the product's measurement table
+func (r *sizeGuideRepository) DeleteMeasurement(ctx context.Context, productID uint) error {
+	result := r.db.WithContext(ctx).Where("product_id = ?", productID).Delete(&domain.ProductMeasurement{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
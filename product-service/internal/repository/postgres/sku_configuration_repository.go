@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"product-service/internal/domain"
 
 	"gorm.io/gorm"
@@ -17,19 +18,19 @@ func NewSKUConfigurationRepository(db *gorm.DB) domain.SKUConfigurationRepositor
 }
 
 // Create inserts a new SKU configuration into the database
-func (r *skuConfigurationRepository) Create(config *domain.SKUConfiguration) error {
-	return r.db.Create(config).Error
+func (r *skuConfigurationRepository) Create(ctx context.Context, config *domain.SKUConfiguration) error {
+	return r.db.WithContext(ctx).Create(config).Error
 }
 
 // CreateBatch inserts multiple SKU configurations in a single transaction
-func (r *skuConfigurationRepository) CreateBatch(configs []*domain.SKUConfiguration) error {
-	return r.db.Create(configs).Error
+func (r *skuConfigurationRepository) CreateBatch(ctx context.Context, configs []*domain.SKUConfiguration) error {
+	return r.db.WithContext(ctx).Create(configs).Error
 }
 
 // GetByProductItemID retrieves all configurations for a product item (SKU)
-func (r *skuConfigurationRepository) GetByProductItemID(productItemID uint) ([]*domain.SKUConfiguration, error) {
+func (r *skuConfigurationRepository) GetByProductItemID(ctx context.Context, productItemID uint) ([]*domain.SKUConfiguration, error) {
 	var configs []*domain.SKUConfiguration
-	err := r.db.Where("product_item_id = ?", productItemID).Find(&configs).Error
+	err := r.db.WithContext(ctx).Where("product_item_id = ?", productItemID).Find(&configs).Error
 	if err != nil {
 		return nil, err
 	}
@@ -37,9 +38,9 @@ func (r *skuConfigurationRepository) GetByProductItemID(productItemID uint) ([]*
 }
 
 // GetByVariationOptionID retrieves all configurations for a variation option
-func (r *skuConfigurationRepository) GetByVariationOptionID(optionID uint) ([]*domain.SKUConfiguration, error) {
+func (r *skuConfigurationRepository) GetByVariationOptionID(ctx context.Context, optionID uint) ([]*domain.SKUConfiguration, error) {
 	var configs []*domain.SKUConfiguration
-	err := r.db.Where("variation_option_id = ?", optionID).Find(&configs).Error
+	err := r.db.WithContext(ctx).Where("variation_option_id = ?", optionID).Find(&configs).Error
 	if err != nil {
 		return nil, err
 	}
@@ -47,13 +48,13 @@ func (r *skuConfigurationRepository) GetByVariationOptionID(optionID uint) ([]*d
 }
 
 // Delete deletes a specific SKU configuration
-func (r *skuConfigurationRepository) Delete(productItemID uint, variationOptionID uint) error {
-	return r.db.Where("product_item_id = ? AND variation_option_id = ?", productItemID, variationOptionID).
+func (r *skuConfigurationRepository) Delete(ctx context.Context, productItemID uint, variationOptionID uint) error {
+	return r.db.WithContext(ctx).Where("product_item_id = ? AND variation_option_id = ?", productItemID, variationOptionID).
 		Delete(&domain.SKUConfiguration{}).Error
 }
 
 // DeleteByProductItemID deletes all configurations for a product item (SKU)
-func (r *skuConfigurationRepository) DeleteByProductItemID(productItemID uint) error {
-	return r.db.Where("product_item_id = ?", productItemID).Delete(&domain.SKUConfiguration{}).Error
+func (r *skuConfigurationRepository) DeleteByProductItemID(ctx context.Context, productItemID uint) error {
+	return r.db.WithContext(ctx).Where("product_item_id = ?", productItemID).Delete(&domain.SKUConfiguration{}).Error
 }
 
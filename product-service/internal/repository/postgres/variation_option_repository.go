@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"product-service/internal/domain"
 
 	"gorm.io/gorm"
@@ -17,19 +18,19 @@ func NewVariationOptionRepository(db *gorm.DB) domain.VariationOptionRepository
 }
 
 // Create inserts a new variation option into the database
-func (r *variationOptionRepository) Create(option *domain.VariationOption) error {
-	return r.db.Create(option).Error
+func (r *variationOptionRepository) Create(ctx context.Context, option *domain.VariationOption) error {
+	return r.db.WithContext(ctx).Create(option).Error
 }
 
 // Update updates an existing variation option
-func (r *variationOptionRepository) Update(option *domain.VariationOption) error {
-	return r.db.Save(option).Error
+func (r *variationOptionRepository) Update(ctx context.Context, option *domain.VariationOption) error {
+	return r.db.WithContext(ctx).Save(option).Error
 }
 
 // GetByID retrieves a variation option by its ID
-func (r *variationOptionRepository) GetByID(id uint) (*domain.VariationOption, error) {
+func (r *variationOptionRepository) GetByID(ctx context.Context, id uint) (*domain.VariationOption, error) {
 	var option domain.VariationOption
-	err := r.db.First(&option, id).Error
+	err := r.db.WithContext(ctx).First(&option, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -37,9 +38,9 @@ func (r *variationOptionRepository) GetByID(id uint) (*domain.VariationOption, e
 }
 
 // GetByVariationID retrieves all options for a variation
-func (r *variationOptionRepository) GetByVariationID(variationID uint) ([]*domain.VariationOption, error) {
+func (r *variationOptionRepository) GetByVariationID(ctx context.Context, variationID uint) ([]*domain.VariationOption, error) {
 	var options []*domain.VariationOption
-	err := r.db.Where("variation_id = ?", variationID).Find(&options).Error
+	err := r.db.WithContext(ctx).Where("variation_id = ?", variationID).Find(&options).Error
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +48,7 @@ func (r *variationOptionRepository) GetByVariationID(variationID uint) ([]*domai
 }
 
 // Delete deletes a variation option
-func (r *variationOptionRepository) Delete(id uint) error {
-	return r.db.Delete(&domain.VariationOption{}, id).Error
+func (r *variationOptionRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.VariationOption{}, id).Error
 }
 
@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"product-service/internal/domain"
 
 	"gorm.io/gorm"
@@ -17,19 +18,19 @@ func NewVariationRepository(db *gorm.DB) domain.VariationRepository {
 }
 
 // Create inserts a new variation into the database
-func (r *variationRepository) Create(variation *domain.Variation) error {
-	return r.db.Create(variation).Error
+func (r *variationRepository) Create(ctx context.Context, variation *domain.Variation) error {
+	return r.db.WithContext(ctx).Create(variation).Error
 }
 
 // Update updates an existing variation
-func (r *variationRepository) Update(variation *domain.Variation) error {
-	return r.db.Save(variation).Error
+func (r *variationRepository) Update(ctx context.Context, variation *domain.Variation) error {
+	return r.db.WithContext(ctx).Save(variation).Error
 }
 
 // GetByID retrieves a variation by its ID
-func (r *variationRepository) GetByID(id uint) (*domain.Variation, error) {
+func (r *variationRepository) GetByID(ctx context.Context, id uint) (*domain.Variation, error) {
 	var variation domain.Variation
-	err := r.db.First(&variation, id).Error
+	err := r.db.WithContext(ctx).First(&variation, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -37,9 +38,9 @@ func (r *variationRepository) GetByID(id uint) (*domain.Variation, error) {
 }
 
 // GetByProductID retrieves all variations for a product
-func (r *variationRepository) GetByProductID(productID uint) ([]*domain.Variation, error) {
+func (r *variationRepository) GetByProductID(ctx context.Context, productID uint) ([]*domain.Variation, error) {
 	var variations []*domain.Variation
-	err := r.db.Where("product_id = ?", productID).Find(&variations).Error
+	err := r.db.WithContext(ctx).Where("product_id = ?", productID).Find(&variations).Error
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +48,7 @@ func (r *variationRepository) GetByProductID(productID uint) ([]*domain.Variatio
 }
 
 // Delete deletes a variation
-func (r *variationRepository) Delete(id uint) error {
-	return r.db.Delete(&domain.Variation{}, id).Error
+func (r *variationRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.Variation{}, id).Error
 }
 
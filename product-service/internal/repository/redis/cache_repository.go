@@ -72,6 +72,24 @@ func (r *cacheRepository) DeleteProduct(ctx context.Context, id uint) error {
 	return r.client.Del(ctx, key).Err()
 }
 
+// SetCount caches a listing total under key with a TTL
+func (r *cacheRepository) SetCount(ctx context.Context, key string, count int64, ttl time.Duration) error {
+	return r.client.Set(ctx, key, count, ttl).Err()
+}
+
+// GetCount retrieves a cached listing total
+// Returns found = false on cache miss
+func (r *cacheRepository) GetCount(ctx context.Context, key string) (int64, bool, error) {
+	count, err := r.client.Get(ctx, key).Int64()
+	if err == redis.Nil {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to get count from cache: %w", err)
+	}
+	return count, true, nil
+}
+
 // AcquireLock acquires a distributed lock using Redis
 // This is useful for preventing race conditions (e.g., inventory updates)
 // Returns true if lock was acquired, false if already locked
@@ -107,3 +125,7 @@ func (r *cacheRepository) Set(ctx context.Context, key string, value interface{}
 	return r.client.Set(ctx, key, value, ttl).Err()
 }
 
+// Delete removes a raw key from Redis (generic helper)
+func (r *cacheRepository) Delete(ctx context.Context, key string) error {
+	return r.client.Del(ctx, key).Err()
+}
@@ -1,78 +1,132 @@
 package router
 
 import (
-	"fmt"
-	"log"
-	"os"
+	"net/http"
 	"product-service/internal/handler"
-	"time"
+	"product-service/pkg/requestid"
+	"product-service/pkg/serviceauth"
+	"slices"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-// RequestLogger middleware logs all incoming requests
-func RequestLogger() gin.HandlerFunc {
+// RequireAdmin middleware only allows requests from ADMIN users
+// Role comes from X-User-Role header, set by API Gateway after JWT validation
+func RequireAdmin() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		start := time.Now()
-		path := c.Request.URL.Path
-		method := c.Request.Method
-
-		// Log request
-		fmt.Fprintf(os.Stderr, "📥📥📥 REQUEST RECEIVED: %s %s\n", method, path)
-		log.Printf("📥 REQUEST RECEIVED: %s %s", method, path)
+		if c.GetHeader("X-User-Role") != "ADMIN" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin permission required"})
+			return
+		}
+		c.Next()
+	}
+}
 
-		// Process request
+// RequireService middleware only allows internal calls signed by one of the given services
+// (X-Service-Token, see pkg/serviceauth); a nil verifier disables the check
+func RequireService(verifier *serviceauth.Verifier, services ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if verifier == nil {
+			c.Next()
+			return
+		}
+		caller, err := verifier.Verify(c.GetHeader(serviceauth.Header))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "service authentication required"})
+			return
+		}
+		if !slices.Contains(services, caller) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "service not allowed"})
+			return
+		}
+		c.Set("calling_service", caller)
 		c.Next()
+	}
+}
 
-		// Log response
-		latency := time.Since(start)
-		status := c.Writer.Status()
-		fmt.Fprintf(os.Stderr, "📤📤📤 RESPONSE: %s %s - Status: %d - Latency: %v\n", method, path, status, latency)
-		log.Printf("📤 RESPONSE: %s %s - Status: %d - Latency: %v", method, path, status, latency)
+// RequestID middleware keeps the request ID (X-Request-Id, set by API Gateway) in the request
+// context for logs and query metrics; requests that bypassed the gateway get a new one
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestid.Header)
+		if id == "" {
+			id = requestid.New()
+		}
+		c.Request = c.Request.WithContext(requestid.NewContext(c.Request.Context(), id))
+		c.Header(requestid.Header, id)
+		c.Next()
 	}
 }
 
 // SetupRouter configures all API routes
 // This is the transport layer - it defines the HTTP API surface
-func SetupRouter(productHandler *handler.ProductHandler, categoryHandler *handler.CategoryHandler, skuHandler *handler.SKUHandler, attrHandler *handler.AttributeHandler, stockHandler *handler.StockHandler, variationHandler *handler.VariationHandler) *gin.Engine {
-	router := gin.Default()
+func SetupRouter(productHandler *handler.ProductHandler, categoryHandler *handler.CategoryHandler, skuHandler *handler.SKUHandler, attrHandler *handler.AttributeHandler, stockHandler *handler.StockHandler, variationHandler *handler.VariationHandler, contentHandler *handler.ContentHandler, feedHandler *handler.FeedHandler, jobHandler *handler.JobHandler, logLevelHandler *handler.LogLevelHandler, taskHandler *handler.TaskHandler, inventoryHandler *handler.InventoryHandler, recentlyViewedHandler *handler.RecentlyViewedHandler, sizeGuideHandler *handler.SizeGuideHandler, digitalCodeHandler *handler.DigitalCodeHandler, shopCollectionHandler *handler.ShopCollectionHandler, bestsellerHandler *handler.BestsellerHandler, reviewHandler *handler.ReviewHandler, productImportHandler *handler.ProductImportHandler, catalogQualityHandler *handler.CatalogQualityHandler, adminProductHandler *handler.AdminProductHandler, inventorySheetHandler *handler.InventorySheetHandler, quotaHandler *handler.QuotaHandler, recovery gin.HandlerFunc, faults gin.HandlerFunc, requestLogger gin.HandlerFunc, writeLimit gin.HandlerFunc, productQuota gin.HandlerFunc, stockQuota gin.HandlerFunc, serviceAuth *serviceauth.Verifier) *gin.Engine {
+	router := gin.New()
+
+	// Panic recovery first so it also covers the other middleware (replaces gin.Recovery)
+	router.Use(recovery, gin.Logger())
+
+	// Add request ID and request logging middleware
+	router.Use(RequestID(), requestLogger)
 
-	// Add request logging middleware
-	router.Use(RequestLogger())
+	// Opt-in latency/error injection for resilience testing (no-op unless enabled)
+	router.Use(faults)
 
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
+	// Prometheus metrics (OpenMetrics format carries the request ID exemplars)
+	router.GET("/metrics", gin.WrapH(promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{EnableOpenMetrics: true})))
+
+	// Internal endpoints called by order-service during checkout and fulfillment
+	fromOrderService := RequireService(serviceAuth, "order_service")
+	// Shop ratings of the admin shop export
+	fromIdentityService := RequireService(serviceAuth, "identity_service")
+
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 	{
 		// Product routes
+		// Write endpoints are rate limited per shop (writeLimit); stock reserve/deduct/release
+		// are called by order-service during checkout and are not limited (signed service calls only).
+		// Product creation and stock sync also count against the seller's hourly/daily quota
 		products := v1.Group("/products")
 		{
 			products.GET("", productHandler.ListProducts) // List products with pagination and filters
-			products.POST("", productHandler.CreateProduct)
-			products.GET("/search", productHandler.SearchProducts) // Search (must be before /:id)
+			products.POST("", writeLimit, productQuota, productHandler.CreateProduct)
+			products.GET("/search", productHandler.SearchProducts)       // Search (must be before /:id)
+			products.GET("/slug/:slug", productHandler.GetProductBySlug) // Lookup by slug (must be before /:id)
+			products.GET("/batch", productHandler.GetProductsBatch)      // Batch fetch by IDs (must be before /:id)
 
 			// Product detail routes - MUST be first (before nested routes)
 			products.GET("/:id", productHandler.GetProduct)
-			products.PUT("/:id", productHandler.UpdateProduct)
-			products.PATCH("/:id/inventory", productHandler.UpdateInventory)
+			products.PUT("/:id", writeLimit, productHandler.UpdateProduct)
+			products.PATCH("/:id/inventory", writeLimit, stockQuota, productHandler.UpdateInventory)
+			products.GET("/:id/reviews", reviewHandler.GetProductReviews) // Reviews with rating summary
 
 			// SKU routes (Product Items) - Use /:id/items (nested under product)
-			products.GET("/:id/items", skuHandler.GetProductItems)               // List all SKUs for a product
-			products.POST("/:id/items", skuHandler.CreateProductItem)            // Create new SKU
-			products.GET("/:id/items/:item_id", skuHandler.GetProductItem)       // Get specific SKU
-			products.PUT("/:id/items/:item_id", skuHandler.UpdateProductItem)    // Update SKU
-			products.DELETE("/:id/items/:item_id", skuHandler.DeleteProductItem) // Delete SKU
+			products.GET("/:id/items", skuHandler.GetProductItems)                                  // List all SKUs for a product
+			products.POST("/:id/items", writeLimit, skuHandler.CreateProductItem)                   // Create new SKU
+			products.GET("/:id/items/:item_id", skuHandler.GetProductItem)                          // Get specific SKU
+			products.PUT("/:id/items/:item_id", writeLimit, skuHandler.UpdateProductItem)           // Update SKU
+			products.DELETE("/:id/items/:item_id", writeLimit, skuHandler.DeleteProductItem)        // Delete SKU (soft, DISCONTINUED)
+			products.POST("/:id/items/:item_id/restore", writeLimit, skuHandler.RestoreProductItem) // Restore deleted SKU
 
 			// Variation routes - Use /:id/variations (for variation selector UI)
 			products.GET("/:id/variations", variationHandler.GetProductVariations) // Get variations with options
 
 			// Product attributes (EAV) - Use /:id/attributes
-			products.POST("/:id/attributes", attrHandler.SetProductAttributes)
+			products.POST("/:id/attributes", writeLimit, attrHandler.SetProductAttributes)
 			products.GET("/:id/attributes", attrHandler.GetProductAttributes)
+
+			// Size guide - category chart + seller measurements (also embedded in product detail)
+			products.GET("/:id/size-guide", sizeGuideHandler.GetProductSizeGuide)
+			products.PUT("/:id/measurements", writeLimit, sizeGuideHandler.SetProductMeasurements)
+			products.DELETE("/:id/measurements", writeLimit, sizeGuideHandler.DeleteProductMeasurements)
 		}
 
 		// Category routes
@@ -81,9 +135,12 @@ func SetupRouter(productHandler *handler.ProductHandler, categoryHandler *handle
 			categories.GET("", categoryHandler.GetAllCategories)
 			categories.POST("", categoryHandler.CreateCategory)
 			categories.GET("/slug/:slug", categoryHandler.GetCategoryBySlug) // Must be before /:id
+			categories.GET("/batch", categoryHandler.GetCategoriesBatch)     // Must be before /:id
 			categories.GET("/:id", categoryHandler.GetCategory)
 			categories.GET("/:id/children", categoryHandler.GetCategoryChildren)
 			categories.GET("/:id/products", productHandler.GetProductsByCategory) // Products by category
+			categories.GET("/:id/bestsellers", bestsellerHandler.GetCategoryBestsellers)
+			categories.GET("/:id/shops", fromOrderService, productHandler.GetCategoryShops) // Sellers of the category (order-service announcements)
 			categories.PUT("/:id", categoryHandler.UpdateCategory)
 			categories.DELETE("/:id", categoryHandler.DeleteCategory)
 
@@ -91,21 +148,136 @@ func SetupRouter(productHandler *handler.ProductHandler, categoryHandler *handle
 			categories.POST("/:id/attributes", attrHandler.CreateCategoryAttribute)
 			categories.GET("/:id/attributes", attrHandler.GetCategoryAttributes)
 			categories.DELETE("/:id/attributes/:attr_id", attrHandler.DeleteCategoryAttribute)
+
+			// Size chart template (inherited by subcategories without their own)
+			categories.GET("/:id/size-chart", sizeGuideHandler.GetCategorySizeChart)
+			categories.PUT("/:id/size-chart", sizeGuideHandler.SetCategorySizeChart)
+			categories.DELETE("/:id/size-chart", sizeGuideHandler.DeleteCategorySizeChart)
 		}
 
 		// Product item routes (standalone)
-		v1.GET("/product-items/batch", skuHandler.GetProductItemsBatch) // Batch fetch (MUST be before :id route)
-		v1.GET("/product-items/:id", skuHandler.GetProductItemBySKU)    // Get by SKU code
+		v1.GET("/product-items/batch", skuHandler.GetProductItemsBatch)                           // Batch fetch (MUST be before :id route)
+		v1.GET("/product-items/issued-codes", fromOrderService, digitalCodeHandler.GetOrderCodes) // Codes issued to an order (order-service)
+		v1.GET("/product-items/:id", skuHandler.GetProductItemBySKU)                              // Get by SKU code
 
 		// Stock management routes
 		productItems := v1.Group("/product-items")
 		{
-			productItems.GET("/:id/stock", stockHandler.GetStock)          // Get stock
-			productItems.PUT("/:id/stock", stockHandler.UpdateStock)       // Update stock (shop owner)
-			productItems.POST("/check-stock", stockHandler.CheckStock)     // Check stock availability
-			productItems.POST("/reserve-stock", stockHandler.ReserveStock) // Reserve stock (checkout)
-			productItems.POST("/deduct-stock", stockHandler.DeductStock)   // Deduct stock (payment confirmed)
-			productItems.POST("/release-stock", stockHandler.ReleaseStock) // Release reservation (cancel/failed)
+			productItems.GET("/:id/stock", stockHandler.GetStock)                            // Get stock
+			productItems.PUT("/:id/stock", writeLimit, stockQuota, stockHandler.UpdateStock) // Update stock (shop owner)
+			productItems.POST("/check-stock", stockHandler.CheckStock)                       // Check stock availability
+			productItems.POST("/reserve-stock", fromOrderService, stockHandler.ReserveStock) // Reserve stock (checkout)
+			productItems.POST("/deduct-stock", fromOrderService, stockHandler.DeductStock)   // Deduct stock (payment confirmed)
+			productItems.POST("/release-stock", fromOrderService, stockHandler.ReleaseStock) // Release reservation (cancel/failed)
+		}
+
+		// Digital product code pools (seller upload; issuing is called by order-service after payment)
+		productItems.POST("/:id/codes", writeLimit, digitalCodeHandler.UploadCodes)
+		productItems.GET("/:id/codes/stats", digitalCodeHandler.GetCodeStats)
+		productItems.POST("/issue-codes", fromOrderService, digitalCodeHandler.IssueCodes)
+
+		// Bestsellers of a shop over the rolling sales window
+		v1.GET("/shops/:id/bestsellers", bestsellerHandler.GetShopBestsellers)
+
+		// Reviews of received products (X-User-Id from API Gateway) and shop ratings
+		reviews := v1.Group("/reviews")
+		{
+			reviews.POST("", writeLimit, reviewHandler.CreateReview)
+			reviews.PUT("/:id", writeLimit, reviewHandler.UpdateReview)
+			reviews.DELETE("/:id", reviewHandler.DeleteReview)
+		}
+		v1.GET("/shops/:id/reviews", reviewHandler.GetShopReviews)
+		v1.GET("/shops/:id/rating", reviewHandler.GetShopRating)
+		v1.GET("/shops/ratings", fromIdentityService, reviewHandler.ListShopRatings) // All shops (identity-service)
+
+		// Seller-defined shop collections (storefront navigation, independent of categories)
+		shopCollections := v1.Group("/shops/:id/collections")
+		{
+			shopCollections.GET("", shopCollectionHandler.ListCollections) // ?include_hidden=true for the seller
+			shopCollections.POST("", writeLimit, shopCollectionHandler.CreateCollection)
+			shopCollections.PUT("/order", writeLimit, shopCollectionHandler.ReorderCollections) // Must be before /:collection_id
+			shopCollections.PUT("/:collection_id", writeLimit, shopCollectionHandler.UpdateCollection)
+			shopCollections.DELETE("/:collection_id", writeLimit, shopCollectionHandler.DeleteCollection)
+			shopCollections.GET("/:collection_id/products", shopCollectionHandler.GetCollectionProducts)
+			shopCollections.PUT("/:collection_id/products", writeLimit, shopCollectionHandler.SetCollectionProducts)
+		}
+
+		// Marketplace migration tool: import another platform's export in the background
+		tools := v1.Group("/tools")
+		{
+			tools.POST("/product-import", writeLimit, productQuota, productImportHandler.StartImport)
+			tools.GET("/product-import/:id", productImportHandler.GetImport) // Progress and result
+		}
+
+		// Seller inventory Excel export / import (upload = validated diff, commit applies it)
+		sellerInventory := v1.Group("/seller/inventory")
+		{
+			sellerInventory.GET("/export", inventorySheetHandler.ExportInventory)
+			sellerInventory.POST("/import", writeLimit, inventorySheetHandler.ImportInventory)
+			sellerInventory.POST("/import/:id/commit", writeLimit, stockQuota, inventorySheetHandler.CommitInventoryImport)
+		}
+
+		// Recently viewed products of the current user (X-User-Id from API Gateway)
+		recentlyViewed := v1.Group("/users/me/recently-viewed")
+		{
+			recentlyViewed.GET("", recentlyViewedHandler.GetRecentlyViewed)
+			recentlyViewed.POST("", recentlyViewedHandler.RecordView)
+			recentlyViewed.DELETE("", recentlyViewedHandler.ClearRecentlyViewed)
+		}
+
+		// Homepage content (public, cached in Redis)
+		v1.GET("/content/home", contentHandler.GetHomeContent)
+
+		// Product feed (generated by background job)
+		v1.GET("/feeds/products", feedHandler.GetProductFeed)
+
+		// Admin content management (banners, campaigns)
+		admin := v1.Group("/admin")
+		admin.Use(RequireAdmin())
+		{
+			admin.GET("/banners", contentHandler.ListBanners)
+			admin.POST("/banners", contentHandler.CreateBanner)
+			admin.PUT("/banners/:id", contentHandler.UpdateBanner)
+			admin.DELETE("/banners/:id", contentHandler.DeleteBanner)
+
+			admin.GET("/campaigns", contentHandler.ListCampaigns)
+			admin.POST("/campaigns", contentHandler.CreateCampaign)
+			admin.PUT("/campaigns/:id", contentHandler.UpdateCampaign)
+			admin.DELETE("/campaigns/:id", contentHandler.DeleteCampaign)
+
+			// Background jobs (namespaced per service behind the gateway)
+			admin.GET("/jobs/product", jobHandler.GetStats)
+			admin.GET("/jobs/product/list", jobHandler.ListJobs)
+			admin.GET("/jobs/product/:id", jobHandler.GetJob)
+			admin.POST("/jobs/product/:id/retry", jobHandler.RetryJob)
+			admin.DELETE("/jobs/product/:id", jobHandler.DeleteJob)
+			admin.GET("/tasks/product", taskHandler.GetStats)
+			admin.GET("/events/product", taskHandler.GetEventStats)
+			admin.POST("/events/product/flush", taskHandler.FlushEvents)
+
+			// Runtime log level
+			admin.GET("/log-level/product", logLevelHandler.GetLogLevel)
+			admin.PUT("/log-level/product", logLevelHandler.SetLogLevel)
+
+			// Inventory reconciliation (Postgres vs Redis reservations vs search index)
+			admin.GET("/inventory/reconciliation", inventoryHandler.GetReconciliation)
+			admin.POST("/inventory/reconciliation/run", inventoryHandler.RunReconciliation)
+
+			// Catalog quality (placeholder images, missing attributes, zero-stock SKUs, price anomalies)
+			admin.GET("/catalog-quality", catalogQualityHandler.GetReport)
+			admin.GET("/catalog-quality/shops", catalogQualityHandler.ListShopScores)
+			admin.GET("/catalog-quality/shops/:shop_id", catalogQualityHandler.GetShopScore)
+			admin.POST("/catalog-quality/run", catalogQualityHandler.RunCheck)
+
+			// Product search across all shops and statuses, bulk moderation of the results
+			admin.GET("/products/search", adminProductHandler.SearchProducts)
+			admin.POST("/products/moderate", adminProductHandler.ModerateProducts)
+
+			// Seller API quotas: tier limits and seller tiers (seller = shop:<id> or user:<id>)
+			admin.GET("/quotas/tiers", quotaHandler.ListTiers)
+			admin.PUT("/quotas/tiers/:tier", quotaHandler.SetTierLimits)
+			admin.GET("/quotas/sellers/:seller", quotaHandler.GetSellerQuota)
+			admin.PUT("/quotas/sellers/:seller/tier", quotaHandler.SetSellerTier)
 		}
 	}
 
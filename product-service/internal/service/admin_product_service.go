@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-service/internal/domain"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Admin product search and moderation limits
+const (
+	maxAdminSearchWindow = 10000 // Elasticsearch max_result_window
+	maxModerationBatch   = 500
+)
+
+// Moderation actions
+const (
+	ModerationActivate   = "activate"
+	ModerationDeactivate = "deactivate"
+	ModerationArchive    = "archive"
+)
+
+// ErrInvalidModeration is returned for an unknown action or an empty/oversized target set
+var ErrInvalidModeration = domain.Validation("invalid moderation request")
+
+// ErrSearchWindowExceeded is returned when paging past the search index result window
+var ErrSearchWindowExceeded = domain.Validation("search results cannot be paged this far")
+
+// AdminProductSearchRequest represents the admin search filters (query string)
+type AdminProductSearchRequest struct {
+	Query      string `form:"q" json:"q"`                     // Product name or SKU code
+	SKU        string `form:"sku" json:"sku"`                 // Exact SKU code
+	ShopID     uint   `form:"shop_id" json:"shop_id"`         // 0 = all shops
+	CategoryID uint   `form:"category_id" json:"category_id"` // 0 = all categories
+	Status     string `form:"status" json:"status"`           // Comma-separated: ACTIVE,INACTIVE,ARCHIVED (empty = all)
+	Page       int    `form:"page" json:"-"`
+	Limit      int    `form:"limit" json:"-"`
+}
+
+// ModerateProductsRequest applies one action to explicit products or to every result of a search
+type ModerateProductsRequest struct {
+	Action     string                     `json:"action" binding:"required,oneof=activate deactivate archive"`
+	Reason     string                     `json:"reason" binding:"max=255"`
+	ProductIDs []uint                     `json:"product_ids"`
+	Filter     *AdminProductSearchRequest `json:"filter"` // Used when product_ids is empty; at most 500 results
+}
+
+// ModerationResult reports what a bulk moderation did
+type ModerationResult struct {
+	Action    string             `json:"action"`
+	Matched   int                `json:"matched"`
+	Updated   []uint             `json:"updated"`
+	Unchanged []uint             `json:"unchanged"` // Already in the target state
+	Failed    []ModerationFailed `json:"failed"`
+}
+
+// ModerationFailed is a product the action could not be applied to
+type ModerationFailed struct {
+	ProductID uint   `json:"product_id"`
+	Error     string `json:"error"`
+}
+
+// AdminProductService serves the admin product search (Elasticsearch) and bulk moderation
+type AdminProductService struct {
+	productRepo    domain.ProductRepository
+	searchRepo     domain.ProductSearchRepository
+	productService *ProductService
+	logger         *zap.Logger
+}
+
+// NewAdminProductService creates a new admin product service
+func NewAdminProductService(
+	productRepo domain.ProductRepository,
+	searchRepo domain.ProductSearchRepository,
+	productService *ProductService,
+	logger *zap.Logger,
+) *AdminProductService {
+	return &AdminProductService{
+		productRepo:    productRepo,
+		searchRepo:     searchRepo,
+		productService: productService,
+		logger:         logger,
+	}
+}
+
+// SearchProducts finds products across all shops and statuses
+// Matching comes from the search index; the returned rows are read from Postgres,
+// so statuses are current even if the index lags behind
+func (s *AdminProductService) SearchProducts(ctx context.Context, req *AdminProductSearchRequest) ([]*domain.Product, int64, error) {
+	search := newAdminProductSearch(req)
+	if search.Page*search.Limit > maxAdminSearchWindow {
+		return nil, 0, fmt.Errorf("%w: only the first %d results are reachable, narrow the filters", ErrSearchWindowExceeded, maxAdminSearchWindow)
+	}
+
+	ids, total, err := s.searchRepo.AdminSearch(ctx, search)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search products: %w", err)
+	}
+	products, err := s.loadInOrder(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	return products, total, nil
+}
+
+// Moderate applies an action to the given products or to all results of the filter
+// Each product goes through ProductService.UpdateProduct, so cache, index and
+// product_updated events follow; one failing product does not stop the others
+func (s *AdminProductService) Moderate(ctx context.Context, req *ModerateProductsRequest, adminID string) (*ModerationResult, error) {
+	status, isActive, err := moderationTarget(req.Action)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := req.ProductIDs
+	if len(ids) == 0 {
+		if req.Filter == nil {
+			return nil, fmt.Errorf("%w: send product_ids or filter", ErrInvalidModeration)
+		}
+		search := newAdminProductSearch(req.Filter)
+		search.Page = 1
+		search.Limit = maxModerationBatch + 1
+		if ids, _, err = s.searchRepo.AdminSearch(ctx, search); err != nil {
+			return nil, fmt.Errorf("failed to search products: %w", err)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: no products match", ErrInvalidModeration)
+	}
+	if len(ids) > maxModerationBatch {
+		return nil, fmt.Errorf("%w: more than %d products, narrow the filter", ErrInvalidModeration, maxModerationBatch)
+	}
+
+	products, err := s.loadInOrder(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ModerationResult{
+		Action:    req.Action,
+		Matched:   len(products),
+		Updated:   []uint{},
+		Unchanged: []uint{},
+		Failed:    []ModerationFailed{},
+	}
+	for _, product := range products {
+		if product.Status == status && product.IsActive == isActive {
+			result.Unchanged = append(result.Unchanged, product.ID)
+			continue
+		}
+		product.Status = status
+		product.IsActive = isActive
+		if err := s.productService.UpdateProduct(ctx, product); err != nil {
+			result.Failed = append(result.Failed, ModerationFailed{ProductID: product.ID, Error: err.Error()})
+			continue
+		}
+		result.Updated = append(result.Updated, product.ID)
+	}
+
+	s.logger.Info("products moderated",
+		zap.String("action", req.Action),
+		zap.String("reason", req.Reason),
+		zap.String("admin_id", adminID),
+		zap.Int("updated", len(result.Updated)),
+		zap.Int("unchanged", len(result.Unchanged)),
+		zap.Int("failed", len(result.Failed)),
+	)
+	return result, nil
+}
+
+// loadInOrder reads products from Postgres keeping the order of ids (missing IDs are skipped)
+func (s *AdminProductService) loadInOrder(ctx context.Context, ids []uint) ([]*domain.Product, error) {
+	products, err := s.productRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+	byID := make(map[uint]*domain.Product, len(products))
+	for _, product := range products {
+		byID[product.ID] = product
+	}
+	ordered := make([]*domain.Product, 0, len(ids))
+	for _, id := range ids {
+		if product, ok := byID[id]; ok {
+			ordered = append(ordered, product)
+		}
+	}
+	return ordered, nil
+}
+
+// moderationTarget returns the status and is_active an action sets
+func moderationTarget(action string) (string, bool, error) {
+	switch action {
+	case ModerationActivate:
+		return domain.ProductStatusActive, true, nil
+	case ModerationDeactivate:
+		return domain.ProductStatusInactive, false, nil
+	case ModerationArchive:
+		return domain.ProductStatusArchived, false, nil
+	default:
+		return "", false, fmt.Errorf("%w: unknown action %q", ErrInvalidModeration, action)
+	}
+}
+
+// newAdminProductSearch converts request filters with default paging
+func newAdminProductSearch(req *AdminProductSearchRequest) *domain.AdminProductSearch {
+	search := &domain.AdminProductSearch{
+		Query:      strings.TrimSpace(req.Query),
+		SKU:        strings.TrimSpace(req.SKU),
+		ShopID:     req.ShopID,
+		CategoryID: req.CategoryID,
+		Page:       req.Page,
+		Limit:      req.Limit,
+	}
+	for _, status := range strings.Split(req.Status, ",") {
+		if status = strings.ToUpper(strings.TrimSpace(status)); status != "" {
+			search.Statuses = append(search.Statuses, status)
+		}
+	}
+	if search.Page < 1 {
+		search.Page = 1
+	}
+	if search.Limit < 1 || search.Limit > 100 {
+		search.Limit = 20
+	}
+	return search
+}
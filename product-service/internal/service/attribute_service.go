@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"product-service/internal/domain"
@@ -50,12 +51,12 @@ type SetProductAttributesRequest struct {
 }
 
 // CreateCategoryAttribute creates a new attribute for a category
-func (s *AttributeService) CreateCategoryAttribute(req *CreateCategoryAttributeRequest) (*domain.CategoryAttribute, error) {
+func (s *AttributeService) CreateCategoryAttribute(ctx context.Context, req *CreateCategoryAttributeRequest) (*domain.CategoryAttribute, error) {
 	// Validate category exists
-	_, err := s.categoryRepo.GetByID(req.CategoryID)
+	_, err := s.categoryRepo.GetByID(ctx, req.CategoryID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("category not found")
+			return nil, domain.NotFound("category not found")
 		}
 		return nil, fmt.Errorf("failed to get category: %w", err)
 	}
@@ -65,7 +66,7 @@ func (s *AttributeService) CreateCategoryAttribute(req *CreateCategoryAttributeR
 		"text": true, "number": true, "select": true, "checkbox": true,
 	}
 	if !validInputTypes[req.InputType] {
-		return nil, errors.New("invalid input_type: must be text, number, select, or checkbox")
+		return nil, domain.Validation("invalid input_type: must be text, number, select, or checkbox")
 	}
 
 	attr := &domain.CategoryAttribute{
@@ -76,7 +77,7 @@ func (s *AttributeService) CreateCategoryAttribute(req *CreateCategoryAttributeR
 		IsFilterable:  req.IsFilterable,
 	}
 
-	if err := s.categoryAttrRepo.Create(attr); err != nil {
+	if err := s.categoryAttrRepo.Create(ctx, attr); err != nil {
 		s.logger.Error("failed to create category attribute", zap.Error(err))
 		return nil, fmt.Errorf("failed to create category attribute: %w", err)
 	}
@@ -87,8 +88,8 @@ func (s *AttributeService) CreateCategoryAttribute(req *CreateCategoryAttributeR
 }
 
 // GetCategoryAttributes retrieves all attributes for a category
-func (s *AttributeService) GetCategoryAttributes(categoryID uint) ([]*domain.CategoryAttribute, error) {
-	attrs, err := s.categoryAttrRepo.GetByCategoryID(categoryID)
+func (s *AttributeService) GetCategoryAttributes(ctx context.Context, categoryID uint) ([]*domain.CategoryAttribute, error) {
+	attrs, err := s.categoryAttrRepo.GetByCategoryID(ctx, categoryID)
 	if err != nil {
 		s.logger.Error("failed to get category attributes", zap.Error(err))
 		return nil, fmt.Errorf("failed to get category attributes: %w", err)
@@ -103,22 +104,22 @@ func (s *AttributeService) GetCategoryAttributes(categoryID uint) ([]*domain.Cat
 // 3. Check mandatory attributes are provided
 // 4. Delete old attribute values
 // 5. Create new attribute values
-func (s *AttributeService) SetProductAttributes(productID uint, req *SetProductAttributesRequest) error {
+func (s *AttributeService) SetProductAttributes(ctx context.Context, productID uint, req *SetProductAttributesRequest) error {
 	// 1. Get product and its category
-	product, err := s.productRepo.GetByID(productID)
+	product, err := s.productRepo.GetByID(ctx, productID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("product not found")
+			return domain.NotFound("product not found")
 		}
 		return fmt.Errorf("failed to get product: %w", err)
 	}
 
 	if product.CategoryID == nil {
-		return errors.New("product must have a category to set attributes")
+		return domain.Validation("product must have a category to set attributes")
 	}
 
 	// 2. Get category attributes
-	categoryAttrs, err := s.categoryAttrRepo.GetByCategoryID(*product.CategoryID)
+	categoryAttrs, err := s.categoryAttrRepo.GetByCategoryID(ctx, *product.CategoryID)
 	if err != nil {
 		return fmt.Errorf("failed to get category attributes: %w", err)
 	}
@@ -136,19 +137,19 @@ func (s *AttributeService) SetProductAttributes(productID uint, req *SetProductA
 	// 3. Validate provided attributes
 	for attrID := range req.Attributes {
 		if _, exists := validAttrIDs[attrID]; !exists {
-			return fmt.Errorf("attribute_id %d does not belong to product's category", attrID)
+			return domain.Validation("attribute_id %d does not belong to product's category", attrID)
 		}
 	}
 
 	// 4. Check mandatory attributes are provided
 	for attrID := range mandatoryAttrIDs {
 		if _, provided := req.Attributes[attrID]; !provided {
-			return fmt.Errorf("mandatory attribute_id %d is missing", attrID)
+			return domain.Validation("mandatory attribute_id %d is missing", attrID)
 		}
 	}
 
 	// 5. Delete old attribute values
-	if err := s.productAttrRepo.DeleteByProductID(productID); err != nil {
+	if err := s.productAttrRepo.DeleteByProductID(ctx, productID); err != nil {
 		s.logger.Error("failed to delete old product attributes", zap.Error(err))
 		return fmt.Errorf("failed to delete old attributes: %w", err)
 	}
@@ -164,7 +165,7 @@ func (s *AttributeService) SetProductAttributes(productID uint, req *SetProductA
 	}
 
 	if len(values) > 0 {
-		if err := s.productAttrRepo.CreateBatch(values); err != nil {
+		if err := s.productAttrRepo.CreateBatch(ctx, values); err != nil {
 			s.logger.Error("failed to create product attributes", zap.Error(err))
 			return fmt.Errorf("failed to create product attributes: %w", err)
 		}
@@ -177,9 +178,9 @@ func (s *AttributeService) SetProductAttributes(productID uint, req *SetProductA
 
 // GetProductAttributes retrieves all attributes for a product
 // Returns map[attribute_name]value for easy display
-func (s *AttributeService) GetProductAttributes(productID uint) (map[string]string, error) {
+func (s *AttributeService) GetProductAttributes(ctx context.Context, productID uint) (map[string]string, error) {
 	// Get product attribute values
-	values, err := s.productAttrRepo.GetByProductID(productID)
+	values, err := s.productAttrRepo.GetByProductID(ctx, productID)
 	if err != nil {
 		s.logger.Error("failed to get product attributes", zap.Error(err))
 		return nil, fmt.Errorf("failed to get product attributes: %w", err)
@@ -188,7 +189,7 @@ func (s *AttributeService) GetProductAttributes(productID uint) (map[string]stri
 	// Get attribute names
 	result := make(map[string]string)
 	for _, val := range values {
-		attr, err := s.categoryAttrRepo.GetByID(val.AttributeID)
+		attr, err := s.categoryAttrRepo.GetByID(ctx, val.AttributeID)
 		if err != nil {
 			s.logger.Warn("failed to get attribute name", zap.Uint("attr_id", val.AttributeID))
 			continue
@@ -200,11 +201,11 @@ func (s *AttributeService) GetProductAttributes(productID uint) (map[string]stri
 }
 
 // UpdateCategoryAttribute updates a category attribute
-func (s *AttributeService) UpdateCategoryAttribute(id uint, name, inputType string, isMandatory, isFilterable bool) (*domain.CategoryAttribute, error) {
-	attr, err := s.categoryAttrRepo.GetByID(id)
+func (s *AttributeService) UpdateCategoryAttribute(ctx context.Context, id uint, name, inputType string, isMandatory, isFilterable bool) (*domain.CategoryAttribute, error) {
+	attr, err := s.categoryAttrRepo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("category attribute not found")
+			return nil, domain.NotFound("category attribute not found")
 		}
 		return nil, fmt.Errorf("failed to get category attribute: %w", err)
 	}
@@ -219,14 +220,14 @@ func (s *AttributeService) UpdateCategoryAttribute(id uint, name, inputType stri
 			"text": true, "number": true, "select": true, "checkbox": true,
 		}
 		if !validInputTypes[inputType] {
-			return nil, errors.New("invalid input_type")
+			return nil, domain.Validation("invalid input_type")
 		}
 		attr.InputType = inputType
 	}
 	attr.IsMandatory = isMandatory
 	attr.IsFilterable = isFilterable
 
-	if err := s.categoryAttrRepo.Update(attr); err != nil {
+	if err := s.categoryAttrRepo.Update(ctx, attr); err != nil {
 		s.logger.Error("failed to update category attribute", zap.Error(err))
 		return nil, fmt.Errorf("failed to update category attribute: %w", err)
 	}
@@ -237,8 +238,8 @@ func (s *AttributeService) UpdateCategoryAttribute(id uint, name, inputType stri
 }
 
 // DeleteCategoryAttribute deletes a category attribute
-func (s *AttributeService) DeleteCategoryAttribute(id uint) error {
-	if err := s.categoryAttrRepo.Delete(id); err != nil {
+func (s *AttributeService) DeleteCategoryAttribute(ctx context.Context, id uint) error {
+	if err := s.categoryAttrRepo.Delete(ctx, id); err != nil {
 		s.logger.Error("failed to delete category attribute", zap.Error(err))
 		return fmt.Errorf("failed to delete category attribute: %w", err)
 	}
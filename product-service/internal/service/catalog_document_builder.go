@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-service/internal/domain"
+)
+
+// CatalogDocumentBuilder builds denormalized search documents (product + SKUs + attributes)
+// Used by the catalog reindex and by the inventory reconciler when it heals the index
+type CatalogDocumentBuilder struct {
+	productItemRepo  domain.ProductItemRepository
+	productAttrRepo  domain.ProductAttributeValueRepository
+	categoryAttrRepo domain.CategoryAttributeRepository
+	attrNames        map[uint]string // attribute_id -> name, cached across batches
+}
+
+// NewCatalogDocumentBuilder creates a new document builder
+func NewCatalogDocumentBuilder(
+	productItemRepo domain.ProductItemRepository,
+	productAttrRepo domain.ProductAttributeValueRepository,
+	categoryAttrRepo domain.CategoryAttributeRepository,
+) *CatalogDocumentBuilder {
+	return &CatalogDocumentBuilder{
+		productItemRepo:  productItemRepo,
+		productAttrRepo:  productAttrRepo,
+		categoryAttrRepo: categoryAttrRepo,
+		attrNames:        make(map[uint]string),
+	}
+}
+
+// Build loads SKUs and attributes for a batch of products in two queries
+// Not safe for concurrent use (the attribute name cache is unguarded)
+func (b *CatalogDocumentBuilder) Build(ctx context.Context, products []*domain.Product) ([]*domain.ProductDocument, error) {
+	ids := make([]uint, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+
+	items, err := b.productItemRepo.GetByProductIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load product items: %w", err)
+	}
+	itemsByProduct := make(map[uint][]*domain.ProductItem)
+	for _, item := range items {
+		itemsByProduct[item.ProductID] = append(itemsByProduct[item.ProductID], item)
+	}
+
+	attrs, err := b.productAttrRepo.GetByProductIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load attribute values: %w", err)
+	}
+	attrsByProduct := make(map[uint][]*domain.ProductAttributeValue)
+	for _, attr := range attrs {
+		attrsByProduct[attr.ProductID] = append(attrsByProduct[attr.ProductID], attr)
+		if _, ok := b.attrNames[attr.AttributeID]; !ok {
+			name := ""
+			if ca, err := b.categoryAttrRepo.GetByID(ctx, attr.AttributeID); err == nil {
+				name = ca.AttributeName
+			}
+			b.attrNames[attr.AttributeID] = name
+		}
+	}
+
+	docs := make([]*domain.ProductDocument, 0, len(products))
+	for _, p := range products {
+		docs = append(docs, domain.NewProductDocument(p, itemsByProduct[p.ID], attrsByProduct[p.ID], b.attrNames))
+	}
+	return docs, nil
+}
@@ -0,0 +1,526 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"product-service/internal/domain"
+	"product-service/pkg/jobs"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Catalog quality check runs periodically as a background job and on demand from the admin API
+const (
+	JobTypeCatalogQuality   = "catalog:quality_check"
+	catalogQualityReportKey = "catalog:quality:last"
+	qualityBatchSize        = 500
+	qualityJobTimeout       = 30 * time.Minute
+	qualityRetention        = 30 * 24 * time.Hour
+)
+
+// ErrQualityNotRun is returned when no quality check has finished yet
+var ErrQualityNotRun = domain.NotFound("catalog quality check has not run yet")
+
+// ErrQualityRunning is returned when a quality check is already in progress on this instance
+var ErrQualityRunning = domain.Conflict("catalog quality check already running")
+
+// ErrShopScoreNotFound is returned when the last run has no score for a shop (no listed products)
+var ErrShopScoreNotFound = domain.NotFound("shop has no quality score")
+
+// QualityOptions configures the catalog quality checks
+type QualityOptions struct {
+	PlaceholderPatterns []string // case-insensitive substrings of placeholder image URLs
+	PriceLowRatio       float64  // price below median * ratio is an anomaly
+	PriceHighRatio      float64  // price above median * ratio is an anomaly
+	MinCategorySample   int      // categories with fewer priced products have no median
+}
+
+// QualityReport summarizes one quality run
+type QualityReport struct {
+	RunID              string         `json:"run_id"`
+	StartedAt          time.Time      `json:"started_at"`
+	FinishedAt         time.Time      `json:"finished_at"`
+	Products           int            `json:"products"`
+	ProductsWithIssues int            `json:"products_with_issues"`
+	Issues             int            `json:"issues"`
+	ByKind             map[string]int `json:"by_kind"`
+	Shops              int            `json:"shops"`
+	AverageScore       float64        `json:"average_score"`
+}
+
+// CatalogQualityService scans listed products (ACTIVE and is_active) for quality
+// issues and scores each shop by the share of its products without issues
+type CatalogQualityService struct {
+	productRepo      domain.ProductRepository
+	productItemRepo  domain.ProductItemRepository
+	productAttrRepo  domain.ProductAttributeValueRepository
+	categoryAttrRepo domain.CategoryAttributeRepository
+	qualityRepo      domain.CatalogQualityRepository
+	redisClient      *redis.Client
+	jobs             JobEnqueuer
+	opts             QualityOptions
+	running          sync.Mutex
+	logger           *zap.Logger
+}
+
+// NewCatalogQualityService creates a new catalog quality service
+func NewCatalogQualityService(
+	productRepo domain.ProductRepository,
+	productItemRepo domain.ProductItemRepository,
+	productAttrRepo domain.ProductAttributeValueRepository,
+	categoryAttrRepo domain.CategoryAttributeRepository,
+	qualityRepo domain.CatalogQualityRepository,
+	redisClient *redis.Client,
+	jobEnqueuer JobEnqueuer,
+	opts QualityOptions,
+	logger *zap.Logger,
+) *CatalogQualityService {
+	if opts.PriceLowRatio <= 0 {
+		opts.PriceLowRatio = 0.2
+	}
+	if opts.PriceHighRatio <= 0 {
+		opts.PriceHighRatio = 5
+	}
+	if opts.MinCategorySample <= 0 {
+		opts.MinCategorySample = 5
+	}
+	for i, pattern := range opts.PlaceholderPatterns {
+		opts.PlaceholderPatterns[i] = strings.ToLower(pattern)
+	}
+	return &CatalogQualityService{
+		productRepo:      productRepo,
+		productItemRepo:  productItemRepo,
+		productAttrRepo:  productAttrRepo,
+		categoryAttrRepo: categoryAttrRepo,
+		qualityRepo:      qualityRepo,
+		redisClient:      redisClient,
+		jobs:             jobEnqueuer,
+		opts:             opts,
+		logger:           logger,
+	}
+}
+
+// RunNow enqueues an immediate quality check (admin trigger)
+func (s *CatalogQualityService) RunNow(ctx context.Context) (*jobs.Job, error) {
+	job, err := s.jobs.Enqueue(ctx, JobTypeCatalogQuality, nil, jobs.Timeout(qualityJobTimeout), jobs.MaxRetries(0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue catalog quality check: %w", err)
+	}
+	return job, nil
+}
+
+// HandleQualityCheck is the job handler for JobTypeCatalogQuality
+func (s *CatalogQualityService) HandleQualityCheck(ctx context.Context, _ []byte) error {
+	_, err := s.Check(ctx)
+	if errors.Is(err, ErrQualityRunning) {
+		return nil
+	}
+	return err
+}
+
+// shopTally accumulates the results of one shop during a run
+type shopTally struct {
+	products           int
+	productsWithIssues int
+	issues             int
+}
+
+// Check runs all quality checks over the listed catalog and stores the issues,
+// the shop scores and the report. Results of runs older than 30 days are dropped
+func (s *CatalogQualityService) Check(ctx context.Context) (*QualityReport, error) {
+	if !s.running.TryLock() {
+		return nil, ErrQualityRunning
+	}
+	defer s.running.Unlock()
+
+	report := &QualityReport{
+		RunID:     time.Now().UTC().Format("20060102T150405Z"),
+		StartedAt: time.Now(),
+		ByKind:    make(map[string]int),
+	}
+
+	// Pass 1: category price medians (needs the whole catalog before any product is judged)
+	medians, err := s.categoryMedians(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pass 2: per-product checks
+	var issues []*domain.CatalogQualityIssue
+	shops := make(map[uint]*shopTally)
+	mandatory := make(map[uint][]*domain.CategoryAttribute)
+
+	err = s.eachListedBatch(ctx, func(products []*domain.Product) error {
+		batch, err := s.checkBatch(ctx, products, medians, mandatory)
+		if err != nil {
+			return err
+		}
+
+		byProduct := make(map[uint]int)
+		for _, issue := range batch {
+			byProduct[issue.ProductID]++
+		}
+		for _, p := range products {
+			tally := shops[p.ShopID]
+			if tally == nil {
+				tally = &shopTally{}
+				shops[p.ShopID] = tally
+			}
+			tally.products++
+			if n := byProduct[p.ID]; n > 0 {
+				tally.productsWithIssues++
+				tally.issues += n
+			}
+		}
+		report.Products += len(products)
+		issues = append(issues, batch...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, issue := range issues {
+		issue.RunID = report.RunID
+		report.ByKind[issue.Kind]++
+	}
+	report.Issues = len(issues)
+
+	scores := make([]*domain.ShopQualityScore, 0, len(shops))
+	var scoreSum float64
+	for shopID, tally := range shops {
+		score := &domain.ShopQualityScore{
+			RunID:              report.RunID,
+			ShopID:             shopID,
+			Products:           tally.products,
+			ProductsWithIssues: tally.productsWithIssues,
+			Issues:             tally.issues,
+			Score:              qualityScore(tally.products, tally.productsWithIssues),
+		}
+		scores = append(scores, score)
+		scoreSum += score.Score
+		report.ProductsWithIssues += tally.productsWithIssues
+	}
+	report.Shops = len(scores)
+	if len(scores) > 0 {
+		report.AverageScore = math.Round(scoreSum/float64(len(scores))*100) / 100
+	}
+	report.FinishedAt = time.Now()
+
+	if err := s.qualityRepo.DeleteRunsBefore(ctx, time.Now().Add(-qualityRetention)); err != nil {
+		s.logger.Warn("failed to delete old catalog quality runs", zap.Error(err))
+	}
+	if err := s.qualityRepo.CreateIssues(ctx, issues); err != nil {
+		return nil, fmt.Errorf("failed to save quality issues: %w", err)
+	}
+	if err := s.qualityRepo.CreateScores(ctx, scores); err != nil {
+		return nil, fmt.Errorf("failed to save shop quality scores: %w", err)
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal report: %w", err)
+	}
+	if err := s.redisClient.Set(ctx, catalogQualityReportKey, data, 0).Err(); err != nil {
+		return nil, fmt.Errorf("failed to store report: %w", err)
+	}
+
+	s.logger.Info("catalog quality check completed",
+		zap.String("run_id", report.RunID),
+		zap.Int("products", report.Products),
+		zap.Int("issues", report.Issues),
+		zap.Any("by_kind", report.ByKind),
+		zap.Float64("average_score", report.AverageScore),
+		zap.Duration("duration", report.FinishedAt.Sub(report.StartedAt)),
+	)
+	return report, nil
+}
+
+// eachListedBatch calls fn with each page of listed products (ACTIVE and is_active)
+func (s *CatalogQualityService) eachListedBatch(ctx context.Context, fn func([]*domain.Product) error) error {
+	var lastID uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		products, err := s.productRepo.ListAfterID(ctx, lastID, qualityBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to load products after ID %d: %w", lastID, err)
+		}
+		if len(products) == 0 {
+			return nil
+		}
+		lastID = products[len(products)-1].ID
+
+		listed := products[:0]
+		for _, p := range products {
+			if p.Status == "ACTIVE" && p.IsActive {
+				listed = append(listed, p)
+			}
+		}
+		if len(listed) == 0 {
+			continue
+		}
+		if err := fn(listed); err != nil {
+			return err
+		}
+	}
+}
+
+// categoryMedians returns the median base price of each category with enough listed products
+func (s *CatalogQualityService) categoryMedians(ctx context.Context) (map[uint]float64, error) {
+	prices := make(map[uint][]float64)
+	err := s.eachListedBatch(ctx, func(products []*domain.Product) error {
+		for _, p := range products {
+			if p.CategoryID != nil && p.BasePrice > 0 {
+				prices[*p.CategoryID] = append(prices[*p.CategoryID], p.BasePrice)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	medians := make(map[uint]float64, len(prices))
+	for categoryID, values := range prices {
+		if len(values) < s.opts.MinCategorySample {
+			continue
+		}
+		sort.Float64s(values)
+		mid := len(values) / 2
+		if len(values)%2 == 0 {
+			medians[categoryID] = (values[mid-1] + values[mid]) / 2
+		} else {
+			medians[categoryID] = values[mid]
+		}
+	}
+	return medians, nil
+}
+
+// checkBatch runs the product and SKU checks on one page of listed products
+func (s *CatalogQualityService) checkBatch(
+	ctx context.Context,
+	products []*domain.Product,
+	medians map[uint]float64,
+	mandatory map[uint][]*domain.CategoryAttribute,
+) ([]*domain.CatalogQualityIssue, error) {
+	ids := make([]uint, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+
+	items, err := s.productItemRepo.GetByProductIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load product items: %w", err)
+	}
+	itemsByProduct := make(map[uint][]*domain.ProductItem)
+	for _, item := range items {
+		itemsByProduct[item.ProductID] = append(itemsByProduct[item.ProductID], item)
+	}
+
+	values, err := s.productAttrRepo.GetByProductIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load product attributes: %w", err)
+	}
+	filled := make(map[uint]map[uint]bool)
+	for _, v := range values {
+		if strings.TrimSpace(v.Value) == "" {
+			continue
+		}
+		if filled[v.ProductID] == nil {
+			filled[v.ProductID] = make(map[uint]bool)
+		}
+		filled[v.ProductID][v.AttributeID] = true
+	}
+
+	var issues []*domain.CatalogQualityIssue
+	for _, p := range products {
+		newIssue := func(kind, detail string, itemID *uint) {
+			issues = append(issues, &domain.CatalogQualityIssue{
+				ShopID:        p.ShopID,
+				Kind:          kind,
+				ProductID:     p.ID,
+				ProductItemID: itemID,
+				Detail:        truncateDetail(detail),
+			})
+		}
+
+		// Placeholder images
+		var images []string
+		if len(p.Images) > 0 {
+			_ = json.Unmarshal(p.Images, &images)
+		}
+		if len(images) == 0 {
+			newIssue(domain.QualityPlaceholderImage, "product has no images", nil)
+		} else {
+			for _, image := range images {
+				if s.isPlaceholder(image) {
+					newIssue(domain.QualityPlaceholderImage, "placeholder image: "+image, nil)
+					break
+				}
+			}
+		}
+
+		for _, item := range itemsByProduct[p.ID] {
+			itemID := item.ID
+			if item.ImageURL != "" && s.isPlaceholder(item.ImageURL) {
+				newIssue(domain.QualityPlaceholderImage, fmt.Sprintf("SKU %s placeholder image: %s", item.SKUCode, item.ImageURL), &itemID)
+			}
+			// Zero-stock ACTIVE SKUs (should be OUT_OF_STOCK)
+			if item.Status == domain.ProductItemStatusActive && item.QtyInStock <= 0 {
+				newIssue(domain.QualityZeroStockActive, fmt.Sprintf("SKU %s is ACTIVE with no stock", item.SKUCode), &itemID)
+			}
+		}
+
+		if p.CategoryID == nil {
+			continue
+		}
+
+		// Missing mandatory attributes
+		attrs, err := s.mandatoryAttributes(ctx, mandatory, *p.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		var missing []string
+		for _, attr := range attrs {
+			if !filled[p.ID][attr.ID] {
+				missing = append(missing, attr.AttributeName)
+			}
+		}
+		if len(missing) > 0 {
+			newIssue(domain.QualityMissingAttribute, "missing: "+strings.Join(missing, ", "), nil)
+		}
+
+		// Price anomalies vs the category median
+		if median, ok := medians[*p.CategoryID]; ok && p.BasePrice > 0 {
+			if p.BasePrice < median*s.opts.PriceLowRatio || p.BasePrice > median*s.opts.PriceHighRatio {
+				newIssue(domain.QualityPriceAnomaly, fmt.Sprintf("base price %.2f vs category median %.2f", p.BasePrice, median), nil)
+			}
+		}
+	}
+	return issues, nil
+}
+
+// mandatoryAttributes returns the mandatory attributes of a category (cached per run)
+func (s *CatalogQualityService) mandatoryAttributes(ctx context.Context, cache map[uint][]*domain.CategoryAttribute, categoryID uint) ([]*domain.CategoryAttribute, error) {
+	if attrs, ok := cache[categoryID]; ok {
+		return attrs, nil
+	}
+
+	all, err := s.categoryAttrRepo.GetByCategoryID(ctx, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attributes of category %d: %w", categoryID, err)
+	}
+	attrs := make([]*domain.CategoryAttribute, 0, len(all))
+	for _, attr := range all {
+		if attr.IsMandatory {
+			attrs = append(attrs, attr)
+		}
+	}
+	cache[categoryID] = attrs
+	return attrs, nil
+}
+
+func (s *CatalogQualityService) isPlaceholder(imageURL string) bool {
+	lower := strings.ToLower(imageURL)
+	for _, pattern := range s.opts.PlaceholderPatterns {
+		if pattern != "" && strings.Contains(lower, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetLastReport returns the report of the last finished run and its issues
+// shopID 0 and kind "" return issues of all shops and kinds
+func (s *CatalogQualityService) GetLastReport(ctx context.Context, shopID uint, kind string, limit int) (*QualityReport, []*domain.CatalogQualityIssue, error) {
+	report, err := s.lastReport(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	issues, err := s.qualityRepo.ListIssues(ctx, report.RunID, shopID, kind, limit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list quality issues: %w", err)
+	}
+	return report, issues, nil
+}
+
+// ListShopScores returns a page of shop scores of the last run, lowest first
+func (s *CatalogQualityService) ListShopScores(ctx context.Context, page, limit int) (*QualityReport, []*domain.ShopQualityScore, int64, error) {
+	report, err := s.lastReport(ctx)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	scores, total, err := s.qualityRepo.ListScores(ctx, report.RunID, page, limit)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to list shop quality scores: %w", err)
+	}
+	return report, scores, total, nil
+}
+
+// GetShopScore returns the score of one shop in the last run
+func (s *CatalogQualityService) GetShopScore(ctx context.Context, shopID uint) (*domain.ShopQualityScore, error) {
+	report, err := s.lastReport(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	score, err := s.qualityRepo.GetScore(ctx, report.RunID, shopID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrShopScoreNotFound
+		}
+		return nil, fmt.Errorf("failed to get shop quality score: %w", err)
+	}
+	return score, nil
+}
+
+func (s *CatalogQualityService) lastReport(ctx context.Context) (*QualityReport, error) {
+	data, err := s.redisClient.Get(ctx, catalogQualityReportKey).Bytes()
+	if err == redis.Nil {
+		return nil, ErrQualityNotRun
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quality report: %w", err)
+	}
+
+	var report QualityReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to decode quality report: %w", err)
+	}
+	return &report, nil
+}
+
+// qualityScore is the percentage of products without issues, rounded to 2 decimals
+func qualityScore(products, withIssues int) float64 {
+	if products == 0 {
+		return 100
+	}
+	return math.Round(float64(products-withIssues)/float64(products)*10000) / 100
+}
+
+// truncateDetail cuts a detail to the column size without splitting a UTF-8 character
+func truncateDetail(detail string) string {
+	const maxLen = 255
+	if len(detail) <= maxLen {
+		return detail
+	}
+	cut := maxLen
+	for cut > 0 && !utf8.RuneStart(detail[cut]) {
+		cut--
+	}
+	return detail[:cut]
+}
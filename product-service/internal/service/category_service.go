@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 // CategoryService contains the business logic for category operations
@@ -32,7 +33,7 @@ func NewCategoryService(
 func (s *CategoryService) CreateCategory(ctx context.Context, category *domain.Category) error {
 	// Business logic validation
 	if category.Name == "" {
-		return errors.New("category name is required")
+		return domain.Validation("category name is required")
 	}
 
 	// Generate slug from name if not provided
@@ -41,24 +42,24 @@ func (s *CategoryService) CreateCategory(ctx context.Context, category *domain.C
 	}
 
 	// Check if slug already exists
-	existing, err := s.categoryRepo.GetBySlug(category.Slug)
+	existing, err := s.categoryRepo.GetBySlug(ctx, category.Slug)
 	if err == nil && existing != nil {
-		return errors.New("category with this slug already exists")
+		return domain.Conflict("category with this slug already exists")
 	}
 
 	// Validate parent_id if provided
 	if category.ParentID != nil {
-		parent, err := s.categoryRepo.GetByID(*category.ParentID)
+		parent, err := s.categoryRepo.GetByID(ctx, *category.ParentID)
 		if err != nil {
-			return errors.New("parent category not found")
+			return domain.NotFound("parent category not found")
 		}
 		if parent == nil {
-			return errors.New("parent category not found")
+			return domain.NotFound("parent category not found")
 		}
 	}
 
 	// Create category
-	if err := s.categoryRepo.Create(category); err != nil {
+	if err := s.categoryRepo.Create(ctx, category); err != nil {
 		s.logger.Error("failed to create category in database", zap.Error(err))
 		return fmt.Errorf("failed to create category: %w", err)
 	}
@@ -70,9 +71,12 @@ func (s *CategoryService) CreateCategory(ctx context.Context, category *domain.C
 // UpdateCategory updates an existing category
 func (s *CategoryService) UpdateCategory(ctx context.Context, category *domain.Category) error {
 	// Validate category exists
-	existing, err := s.categoryRepo.GetByID(category.ID)
+	existing, err := s.categoryRepo.GetByID(ctx, category.ID)
 	if err != nil {
-		return errors.New("category not found")
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.NotFound("category not found")
+		}
+		return fmt.Errorf("failed to get category: %w", err)
 	}
 
 	// Generate slug from name if name changed and slug not provided
@@ -82,20 +86,20 @@ func (s *CategoryService) UpdateCategory(ctx context.Context, category *domain.C
 
 	// Check if slug already exists (excluding current category)
 	if category.Slug != existing.Slug {
-		existingBySlug, err := s.categoryRepo.GetBySlug(category.Slug)
+		existingBySlug, err := s.categoryRepo.GetBySlug(ctx, category.Slug)
 		if err == nil && existingBySlug != nil && existingBySlug.ID != category.ID {
-			return errors.New("category with this slug already exists")
+			return domain.Conflict("category with this slug already exists")
 		}
 	}
 
 	// Validate parent_id if provided (prevent circular reference)
 	if category.ParentID != nil {
 		if *category.ParentID == category.ID {
-			return errors.New("category cannot be its own parent")
+			return domain.Validation("category cannot be its own parent")
 		}
-		parent, err := s.categoryRepo.GetByID(*category.ParentID)
+		parent, err := s.categoryRepo.GetByID(ctx, *category.ParentID)
 		if err != nil || parent == nil {
-			return errors.New("parent category not found")
+			return domain.NotFound("parent category not found")
 		}
 	}
 
@@ -103,7 +107,7 @@ func (s *CategoryService) UpdateCategory(ctx context.Context, category *domain.C
 	category.CreatedAt = existing.CreatedAt
 
 	// Update category
-	if err := s.categoryRepo.Update(category); err != nil {
+	if err := s.categoryRepo.Update(ctx, category); err != nil {
 		s.logger.Error("failed to update category in database", zap.Error(err))
 		return fmt.Errorf("failed to update category: %w", err)
 	}
@@ -114,25 +118,63 @@ func (s *CategoryService) UpdateCategory(ctx context.Context, category *domain.C
 
 // GetCategory retrieves a category by ID
 func (s *CategoryService) GetCategory(ctx context.Context, id uint) (*domain.Category, error) {
-	category, err := s.categoryRepo.GetByID(id)
+	category, err := s.categoryRepo.GetByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("category not found: %w", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NotFound("category not found")
+		}
+		return nil, fmt.Errorf("failed to get category: %w", err)
 	}
 	return category, nil
 }
 
+// MaxBatchCategories bounds the IDs of one batch lookup
+const MaxBatchCategories = 100
+
+// ErrCategoryBatchTooLarge is returned when a batch lookup asks for more than MaxBatchCategories
+var ErrCategoryBatchTooLarge = domain.Validation("at most %d categories per batch", MaxBatchCategories)
+
+// GetCategoriesByIDs retrieves categories in the order of ids with one query;
+// unknown and duplicate IDs are skipped
+func (s *CategoryService) GetCategoriesByIDs(ctx context.Context, ids []uint) ([]*domain.Category, error) {
+	if len(ids) > MaxBatchCategories {
+		return nil, ErrCategoryBatchTooLarge
+	}
+
+	found, err := s.categoryRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get categories: %w", err)
+	}
+	byID := make(map[uint]*domain.Category, len(found))
+	for _, c := range found {
+		byID[c.ID] = c
+	}
+
+	categories := make([]*domain.Category, 0, len(found))
+	for _, id := range ids {
+		if c, ok := byID[id]; ok {
+			categories = append(categories, c)
+			delete(byID, id)
+		}
+	}
+	return categories, nil
+}
+
 // GetCategoryBySlug retrieves a category by slug
 func (s *CategoryService) GetCategoryBySlug(ctx context.Context, slug string) (*domain.Category, error) {
-	category, err := s.categoryRepo.GetBySlug(slug)
+	category, err := s.categoryRepo.GetBySlug(ctx, slug)
 	if err != nil {
-		return nil, fmt.Errorf("category not found: %w", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NotFound("category not found")
+		}
+		return nil, fmt.Errorf("failed to get category: %w", err)
 	}
 	return category, nil
 }
 
 // GetAllCategories retrieves all categories
 func (s *CategoryService) GetAllCategories(ctx context.Context) ([]*domain.Category, error) {
-	categories, err := s.categoryRepo.GetAll()
+	categories, err := s.categoryRepo.GetAll(ctx)
 	if err != nil {
 		s.logger.Error("failed to get all categories", zap.Error(err))
 		return nil, fmt.Errorf("failed to get all categories: %w", err)
@@ -142,7 +184,7 @@ func (s *CategoryService) GetAllCategories(ctx context.Context) ([]*domain.Categ
 
 // GetCategoryChildren retrieves child categories of a parent category
 func (s *CategoryService) GetCategoryChildren(ctx context.Context, parentID uint) ([]*domain.Category, error) {
-	categories, err := s.categoryRepo.GetChildren(parentID)
+	categories, err := s.categoryRepo.GetChildren(ctx, parentID)
 	if err != nil {
 		s.logger.Error("failed to get category children", zap.Error(err))
 		return nil, fmt.Errorf("failed to get category children: %w", err)
@@ -153,19 +195,22 @@ func (s *CategoryService) GetCategoryChildren(ctx context.Context, parentID uint
 // DeleteCategory deletes a category
 func (s *CategoryService) DeleteCategory(ctx context.Context, id uint) error {
 	// Check if category exists
-	_, err := s.categoryRepo.GetByID(id)
+	_, err := s.categoryRepo.GetByID(ctx, id)
 	if err != nil {
-		return errors.New("category not found")
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.NotFound("category not found")
+		}
+		return fmt.Errorf("failed to get category: %w", err)
 	}
 
 	// Check if category has children
-	children, err := s.categoryRepo.GetChildren(id)
+	children, err := s.categoryRepo.GetChildren(ctx, id)
 	if err == nil && len(children) > 0 {
-		return errors.New("cannot delete category with children")
+		return domain.Conflict("cannot delete category with children")
 	}
 
 	// Delete category
-	if err := s.categoryRepo.Delete(id); err != nil {
+	if err := s.categoryRepo.Delete(ctx, id); err != nil {
 		s.logger.Error("failed to delete category", zap.Error(err))
 		return fmt.Errorf("failed to delete category: %w", err)
 	}
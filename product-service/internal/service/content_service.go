@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"product-service/internal/domain"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// homeContentCacheKey is the Redis key of the cached homepage payload
+	homeContentCacheKey = "content:home"
+	// homeContentCacheTTL is kept short so scheduled banners go live without manual invalidation
+	homeContentCacheTTL = 5 * time.Minute
+)
+
+// ContentCache defines raw cache operations needed by ContentService (implemented by Redis cacheRepository)
+type ContentCache interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// HomeContent is the public homepage payload
+// Banners are grouped by placement so the frontend can render each slot directly
+type HomeContent struct {
+	Banners     map[string][]*domain.Banner `json:"banners"`
+	Campaigns   []*domain.Campaign          `json:"campaigns"`
+	GeneratedAt time.Time                   `json:"generated_at"`
+}
+
+// ContentService contains the business logic for banners/campaigns (CMS-lite)
+// Marketing manages homepage content through admin endpoints - no deploy needed
+type ContentService struct {
+	bannerRepo   domain.BannerRepository
+	campaignRepo domain.CampaignRepository
+	cache        ContentCache
+	logger       *zap.Logger
+}
+
+// NewContentService creates a new content service with all dependencies
+func NewContentService(
+	bannerRepo domain.BannerRepository,
+	campaignRepo domain.CampaignRepository,
+	cache ContentCache,
+	logger *zap.Logger,
+) *ContentService {
+	return &ContentService{
+		bannerRepo:   bannerRepo,
+		campaignRepo: campaignRepo,
+		cache:        cache,
+		logger:       logger,
+	}
+}
+
+// GetHomeContent returns active banners and campaigns for the homepage (cache-aside)
+func (s *ContentService) GetHomeContent(ctx context.Context) (*HomeContent, error) {
+	// 1. Try cache first
+	if cached, err := s.cache.Get(ctx, homeContentCacheKey); err == nil && cached != "" {
+		var content HomeContent
+		if err := json.Unmarshal([]byte(cached), &content); err == nil {
+			return &content, nil
+		}
+	}
+
+	// 2. Cache miss - build from database
+	now := time.Now()
+	banners, err := s.bannerRepo.GetActive(ctx, now)
+	if err != nil {
+		s.logger.Error("failed to get active banners", zap.Error(err))
+		return nil, fmt.Errorf("failed to get active banners: %w", err)
+	}
+	campaigns, err := s.campaignRepo.GetActive(ctx, now)
+	if err != nil {
+		s.logger.Error("failed to get active campaigns", zap.Error(err))
+		return nil, fmt.Errorf("failed to get active campaigns: %w", err)
+	}
+
+	content := &HomeContent{
+		Banners:     make(map[string][]*domain.Banner),
+		Campaigns:   campaigns,
+		GeneratedAt: now,
+	}
+	for _, banner := range banners {
+		content.Banners[banner.Placement] = append(content.Banners[banner.Placement], banner)
+	}
+
+	// 3. Populate cache
+	if data, err := json.Marshal(content); err == nil {
+		if err := s.cache.Set(ctx, homeContentCacheKey, data, homeContentCacheTTL); err != nil {
+			s.logger.Warn("failed to cache home content", zap.Error(err))
+		}
+	}
+
+	return content, nil
+}
+
+// ListBanners lists banners for admin
+func (s *ContentService) ListBanners(ctx context.Context, placement string, campaignID *uint) ([]*domain.Banner, error) {
+	banners, err := s.bannerRepo.List(ctx, placement, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list banners: %w", err)
+	}
+	return banners, nil
+}
+
+// GetBanner retrieves a banner by ID
+func (s *ContentService) GetBanner(ctx context.Context, id uint) (*domain.Banner, error) {
+	banner, err := s.bannerRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, domain.NotFound("banner not found")
+	}
+	return banner, nil
+}
+
+// CreateBanner creates a new banner
+func (s *ContentService) CreateBanner(ctx context.Context, banner *domain.Banner) error {
+	if err := s.validateBanner(ctx, banner); err != nil {
+		return err
+	}
+
+	if err := s.bannerRepo.Create(ctx, banner); err != nil {
+		s.logger.Error("failed to create banner", zap.Error(err))
+		return fmt.Errorf("failed to create banner: %w", err)
+	}
+
+	s.logger.Info("banner created", zap.Uint("banner_id", banner.ID), zap.String("placement", banner.Placement))
+	s.invalidateHomeContent(ctx)
+	return nil
+}
+
+// UpdateBanner updates an existing banner
+func (s *ContentService) UpdateBanner(ctx context.Context, banner *domain.Banner) error {
+	if err := s.validateBanner(ctx, banner); err != nil {
+		return err
+	}
+
+	if err := s.bannerRepo.Update(ctx, banner); err != nil {
+		s.logger.Error("failed to update banner", zap.Error(err))
+		return fmt.Errorf("failed to update banner: %w", err)
+	}
+
+	s.logger.Info("banner updated", zap.Uint("banner_id", banner.ID))
+	s.invalidateHomeContent(ctx)
+	return nil
+}
+
+// DeleteBanner deletes a banner
+func (s *ContentService) DeleteBanner(ctx context.Context, id uint) error {
+	if _, err := s.bannerRepo.GetByID(ctx, id); err != nil {
+		return domain.NotFound("banner not found")
+	}
+
+	if err := s.bannerRepo.Delete(ctx, id); err != nil {
+		s.logger.Error("failed to delete banner", zap.Error(err))
+		return fmt.Errorf("failed to delete banner: %w", err)
+	}
+
+	s.logger.Info("banner deleted", zap.Uint("banner_id", id))
+	s.invalidateHomeContent(ctx)
+	return nil
+}
+
+// ListCampaigns lists all campaigns for admin
+func (s *ContentService) ListCampaigns(ctx context.Context) ([]*domain.Campaign, error) {
+	campaigns, err := s.campaignRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+	return campaigns, nil
+}
+
+// GetCampaign retrieves a campaign by ID
+func (s *ContentService) GetCampaign(ctx context.Context, id uint) (*domain.Campaign, error) {
+	campaign, err := s.campaignRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, domain.NotFound("campaign not found")
+	}
+	return campaign, nil
+}
+
+// CreateCampaign creates a new campaign
+func (s *ContentService) CreateCampaign(ctx context.Context, campaign *domain.Campaign) error {
+	if err := validateSchedule(campaign.StartAt, campaign.EndAt); err != nil {
+		return err
+	}
+
+	if err := s.campaignRepo.Create(ctx, campaign); err != nil {
+		s.logger.Error("failed to create campaign", zap.Error(err))
+		return fmt.Errorf("failed to create campaign: %w", err)
+	}
+
+	s.logger.Info("campaign created", zap.Uint("campaign_id", campaign.ID))
+	s.invalidateHomeContent(ctx)
+	return nil
+}
+
+// UpdateCampaign updates an existing campaign
+func (s *ContentService) UpdateCampaign(ctx context.Context, campaign *domain.Campaign) error {
+	if err := validateSchedule(campaign.StartAt, campaign.EndAt); err != nil {
+		return err
+	}
+
+	if err := s.campaignRepo.Update(ctx, campaign); err != nil {
+		s.logger.Error("failed to update campaign", zap.Error(err))
+		return fmt.Errorf("failed to update campaign: %w", err)
+	}
+
+	s.logger.Info("campaign updated", zap.Uint("campaign_id", campaign.ID))
+	s.invalidateHomeContent(ctx)
+	return nil
+}
+
+// DeleteCampaign deletes a campaign (its banners are kept but detached)
+func (s *ContentService) DeleteCampaign(ctx context.Context, id uint) error {
+	if _, err := s.campaignRepo.GetByID(ctx, id); err != nil {
+		return domain.NotFound("campaign not found")
+	}
+
+	if err := s.campaignRepo.Delete(ctx, id); err != nil {
+		s.logger.Error("failed to delete campaign", zap.Error(err))
+		return fmt.Errorf("failed to delete campaign: %w", err)
+	}
+
+	s.logger.Info("campaign deleted", zap.Uint("campaign_id", id))
+	s.invalidateHomeContent(ctx)
+	return nil
+}
+
+// validateBanner validates banner business rules
+func (s *ContentService) validateBanner(ctx context.Context, banner *domain.Banner) error {
+	if banner.Title == "" {
+		return domain.Validation("title is required")
+	}
+	if banner.ImageURL == "" {
+		return domain.Validation("image_url is required")
+	}
+
+	validPlacement := false
+	for _, p := range domain.ValidPlacements {
+		if banner.Placement == p {
+			validPlacement = true
+			break
+		}
+	}
+	if !validPlacement {
+		return domain.Validation("invalid placement: %s", banner.Placement)
+	}
+
+	if banner.CampaignID != nil {
+		if _, err := s.campaignRepo.GetByID(ctx, *banner.CampaignID); err != nil {
+			return domain.NotFound("campaign not found")
+		}
+	}
+
+	return validateSchedule(banner.StartAt, banner.EndAt)
+}
+
+// validateSchedule checks that end time is after start time
+func validateSchedule(startAt, endAt *time.Time) error {
+	if startAt != nil && endAt != nil && !endAt.After(*startAt) {
+		return domain.Validation("end_at must be after start_at")
+	}
+	return nil
+}
+
+// invalidateHomeContent drops the cached homepage so changes show up immediately
+func (s *ContentService) invalidateHomeContent(ctx context.Context) {
+	if err := s.cache.Delete(ctx, homeContentCacheKey); err != nil {
+		s.logger.Warn("failed to invalidate home content cache", zap.Error(err))
+	}
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-service/internal/domain"
+	"product-service/pkg/codevault"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Digital code limits
+const (
+	maxDigitalCodesPerUpload = 1000
+	maxDigitalCodeLength     = 255
+)
+
+// Digital code errors
+var (
+	ErrDigitalCodesDisabled    = errors.New("digital codes are not configured")
+	ErrDigitalCodeItemNotFound = domain.NotFound("product item not found")
+	ErrNotDigitalProduct       = domain.Validation("product item is not a digital product")
+	ErrInvalidDigitalCodes     = domain.Validation("invalid digital codes")
+)
+
+// DigitalCodeService manages the code pools of DIGITAL SKUs and issues codes to paid orders
+// The SKU's qty_in_stock follows the number of available codes
+type DigitalCodeService struct {
+	codeRepo        domain.DigitalCodeRepository
+	productItemRepo domain.ProductItemRepository
+	productRepo     domain.ProductRepository
+	stockService    *StockService
+	vault           *codevault.Vault // nil when no encryption key is configured
+	logger          *zap.Logger
+}
+
+// NewDigitalCodeService creates a new digital code service
+func NewDigitalCodeService(
+	codeRepo domain.DigitalCodeRepository,
+	productItemRepo domain.ProductItemRepository,
+	productRepo domain.ProductRepository,
+	stockService *StockService,
+	vault *codevault.Vault,
+	logger *zap.Logger,
+) *DigitalCodeService {
+	return &DigitalCodeService{
+		codeRepo:        codeRepo,
+		productItemRepo: productItemRepo,
+		productRepo:     productRepo,
+		stockService:    stockService,
+		vault:           vault,
+		logger:          logger,
+	}
+}
+
+// UploadDigitalCodesRequest represents a seller's upload of codes to a SKU's pool
+type UploadDigitalCodesRequest struct {
+	Codes []string `json:"codes" binding:"required,min=1"`
+}
+
+// UploadDigitalCodesResult reports an upload
+type UploadDigitalCodesResult struct {
+	Added      int                      `json:"added"`
+	Duplicates int                      `json:"duplicates"` // Already in a pool or repeated in the upload
+	Stats      *domain.DigitalCodeStats `json:"stats"`
+}
+
+// IssueDigitalCodesRequest represents order-service's request to fulfill a paid order
+type IssueDigitalCodesRequest struct {
+	OrderID string                  `json:"order_id" binding:"required"` // Order number
+	Items   []IssueDigitalCodesItem `json:"items" binding:"required,min=1,dive"`
+}
+
+// IssueDigitalCodesItem is one order line to fulfill
+type IssueDigitalCodesItem struct {
+	ProductItemID uint `json:"product_item_id" binding:"required"`
+	Quantity      int  `json:"quantity" binding:"required,min=1"`
+}
+
+// IssuedDigitalCode is a decrypted code issued to an order
+type IssuedDigitalCode struct {
+	ProductItemID uint       `json:"product_item_id"`
+	Code          string     `json:"code"`
+	IssuedAt      *time.Time `json:"issued_at,omitempty"`
+}
+
+// UploadCodes adds codes to the pool of a DIGITAL SKU and raises its stock accordingly
+func (s *DigitalCodeService) UploadCodes(ctx context.Context, productItemID uint, req *UploadDigitalCodesRequest) (*UploadDigitalCodesResult, error) {
+	if s.vault == nil {
+		return nil, ErrDigitalCodesDisabled
+	}
+	if len(req.Codes) > maxDigitalCodesPerUpload {
+		return nil, fmt.Errorf("%w: at most %d codes per upload", ErrInvalidDigitalCodes, maxDigitalCodesPerUpload)
+	}
+	if err := s.requireDigitalItem(ctx, productItemID); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(req.Codes))
+	codes := make([]*domain.DigitalCode, 0, len(req.Codes))
+	for _, code := range req.Codes {
+		code = strings.TrimSpace(code)
+		if code == "" {
+			return nil, fmt.Errorf("%w: code is empty", ErrInvalidDigitalCodes)
+		}
+		if len(code) > maxDigitalCodeLength {
+			return nil, fmt.Errorf("%w: codes are at most %d characters", ErrInvalidDigitalCodes, maxDigitalCodeLength)
+		}
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+
+		encrypted, err := s.vault.Encrypt(code)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, &domain.DigitalCode{
+			ProductItemID: productItemID,
+			CodeEncrypted: encrypted,
+			CodeHash:      s.vault.Hash(code),
+			Status:        domain.DigitalCodeStatusAvailable,
+		})
+	}
+
+	added, err := s.codeRepo.CreateBatch(ctx, codes)
+	if err != nil {
+		s.logger.Error("failed to save digital codes", zap.Uint("product_item_id", productItemID), zap.Error(err))
+		return nil, fmt.Errorf("failed to save digital codes: %w", err)
+	}
+
+	stats, err := s.syncStock(ctx, productItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("digital codes uploaded",
+		zap.Uint("product_item_id", productItemID),
+		zap.Int("added", added),
+		zap.Int64("available", stats.Available),
+	)
+	return &UploadDigitalCodesResult{
+		Added:      added,
+		Duplicates: len(req.Codes) - added,
+		Stats:      stats,
+	}, nil
+}
+
+// GetStats returns the code pool counts of a SKU
+func (s *DigitalCodeService) GetStats(ctx context.Context, productItemID uint) (*domain.DigitalCodeStats, error) {
+	if err := s.requireDigitalItem(ctx, productItemID); err != nil {
+		return nil, err
+	}
+	return s.codeRepo.CountByStatus(ctx, productItemID)
+}
+
+// IssueCodes fulfills the digital lines of a paid order. Retrying with the same
+// order returns the codes already issued and only issues what is still missing
+func (s *DigitalCodeService) IssueCodes(ctx context.Context, req *IssueDigitalCodesRequest) ([]IssuedDigitalCode, error) {
+	if s.vault == nil {
+		return nil, ErrDigitalCodesDisabled
+	}
+
+	quantities := make(map[uint]int, len(req.Items))
+	for _, item := range req.Items {
+		quantities[item.ProductItemID] += item.Quantity
+	}
+	for productItemID := range quantities {
+		if err := s.requireDigitalItem(ctx, productItemID); err != nil {
+			return nil, err
+		}
+	}
+
+	codes, err := s.codeRepo.IssueForOrder(ctx, req.OrderID, quantities)
+	if err != nil {
+		if errors.Is(err, domain.ErrInsufficientDigitalCodes) {
+			s.logger.Warn("digital code pool exhausted", zap.String("order_id", req.OrderID))
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to issue digital codes: %w", err)
+	}
+
+	// Stock follows the pool; the codes are issued either way, so a failed sync is only logged
+	for productItemID := range quantities {
+		if _, err := s.syncStock(ctx, productItemID); err != nil {
+			s.logger.Warn("failed to sync stock after issuing digital codes",
+				zap.Uint("product_item_id", productItemID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	s.logger.Info("digital codes issued", zap.String("order_id", req.OrderID), zap.Int("codes", len(codes)))
+	return s.decrypt(codes)
+}
+
+// GetOrderCodes returns the codes issued to an order (empty if none)
+func (s *DigitalCodeService) GetOrderCodes(ctx context.Context, orderID string) ([]IssuedDigitalCode, error) {
+	if s.vault == nil {
+		return nil, ErrDigitalCodesDisabled
+	}
+	codes, err := s.codeRepo.ListByOrder(ctx, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get digital codes: %w", err)
+	}
+	return s.decrypt(codes)
+}
+
+// requireDigitalItem checks that a SKU exists and belongs to a DIGITAL product
+func (s *DigitalCodeService) requireDigitalItem(ctx context.Context, productItemID uint) error {
+	item, err := s.productItemRepo.GetByID(ctx, productItemID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrDigitalCodeItemNotFound
+		}
+		return fmt.Errorf("failed to get product item: %w", err)
+	}
+	product, err := s.productRepo.GetByID(ctx, item.ProductID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrDigitalCodeItemNotFound
+		}
+		return fmt.Errorf("failed to get product: %w", err)
+	}
+	if product.ProductType != domain.ProductTypeDigital {
+		return ErrNotDigitalProduct
+	}
+	return nil
+}
+
+// syncStock sets the SKU's stock to its number of available codes
+func (s *DigitalCodeService) syncStock(ctx context.Context, productItemID uint) (*domain.DigitalCodeStats, error) {
+	stats, err := s.codeRepo.CountByStatus(ctx, productItemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count digital codes: %w", err)
+	}
+	if err := s.stockService.UpdateStock(ctx, productItemID, int(stats.Available)); err != nil {
+		return nil, fmt.Errorf("failed to sync stock with digital codes: %w", err)
+	}
+	return stats, nil
+}
+
+// decrypt opens issued codes
+func (s *DigitalCodeService) decrypt(codes []*domain.DigitalCode) ([]IssuedDigitalCode, error) {
+	result := make([]IssuedDigitalCode, 0, len(codes))
+	for _, code := range codes {
+		plain, err := s.vault.Decrypt(code.CodeEncrypted)
+		if err != nil {
+			s.logger.Error("failed to decrypt digital code", zap.Uint("code_id", code.ID), zap.Error(err))
+			return nil, err
+		}
+		result = append(result, IssuedDigitalCode{
+			ProductItemID: code.ProductItemID,
+			Code:          plain,
+			IssuedAt:      code.IssuedAt,
+		})
+	}
+	return result, nil
+}
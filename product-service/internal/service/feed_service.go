@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"product-service/internal/domain"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Product feed is pre-generated by a background job and served as-is
+const (
+	productFeedKey      = "feed:products"
+	feedPageSize        = 500
+	JobTypeFeedGenerate = "catalog:feed_generate"
+)
+
+// ErrFeedNotGenerated is returned when the feed job has not run yet
+var ErrFeedNotGenerated = domain.NotFound("product feed not generated yet")
+
+// FeedItem is one product in the catalog feed (marketing / comparison sites)
+type FeedItem struct {
+	ID         uint    `json:"id"`
+	Name       string  `json:"name"`
+	Slug       string  `json:"slug"`
+	BasePrice  float64 `json:"base_price"`
+	CategoryID *uint   `json:"category_id,omitempty"`
+	ShopID     uint    `json:"shop_id"`
+	SoldCount  int     `json:"sold_count"`
+}
+
+// ProductFeed is the generated feed document
+type ProductFeed struct {
+	GeneratedAt time.Time  `json:"generated_at"`
+	Total       int        `json:"total"`
+	Items       []FeedItem `json:"items"`
+}
+
+// FeedService generates and serves the product feed
+type FeedService struct {
+	productRepo domain.ProductRepository
+	redisClient *redis.Client
+	logger      *zap.Logger
+}
+
+// NewFeedService creates a new feed service
+func NewFeedService(productRepo domain.ProductRepository, redisClient *redis.Client, logger *zap.Logger) *FeedService {
+	return &FeedService{
+		productRepo: productRepo,
+		redisClient: redisClient,
+		logger:      logger,
+	}
+}
+
+// HandleGenerateFeed is the job handler for JobTypeFeedGenerate
+func (s *FeedService) HandleGenerateFeed(ctx context.Context, _ []byte) error {
+	return s.GenerateFeed(ctx)
+}
+
+// GenerateFeed builds the feed from all active products and stores it in Redis
+func (s *FeedService) GenerateFeed(ctx context.Context) error {
+	feed := &ProductFeed{GeneratedAt: time.Now(), Items: []FeedItem{}}
+
+	for page := 1; ; page++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		products, total, err := s.productRepo.ListProducts(ctx, map[string]interface{}{"status": "ACTIVE"}, page, feedPageSize, domain.WithListingColumns())
+		if err != nil {
+			return fmt.Errorf("failed to list products: %w", err)
+		}
+		for _, p := range products {
+			feed.Items = append(feed.Items, FeedItem{
+				ID:         p.ID,
+				Name:       p.Name,
+				Slug:       p.Slug,
+				BasePrice:  p.BasePrice,
+				CategoryID: p.CategoryID,
+				ShopID:     p.ShopID,
+				SoldCount:  p.SoldCount,
+			})
+		}
+		if len(products) < feedPageSize || int64(page*feedPageSize) >= total {
+			break
+		}
+	}
+	feed.Total = len(feed.Items)
+
+	data, err := json.Marshal(feed)
+	if err != nil {
+		return fmt.Errorf("failed to marshal feed: %w", err)
+	}
+	if err := s.redisClient.Set(ctx, productFeedKey, data, 0).Err(); err != nil {
+		return fmt.Errorf("failed to store feed: %w", err)
+	}
+
+	s.logger.Info("product feed generated", zap.Int("total", feed.Total))
+	return nil
+}
+
+// GetFeed returns the last generated feed (raw JSON)
+func (s *FeedService) GetFeed(ctx context.Context) ([]byte, error) {
+	data, err := s.redisClient.Get(ctx, productFeedKey).Bytes()
+	if err == redis.Nil {
+		return nil, ErrFeedNotGenerated
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,452 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"product-service/internal/domain"
+	"product-service/pkg/jobs"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Inventory reconciliation runs nightly as a background job and on demand from the admin API
+const (
+	JobTypeInventoryReconcile = "stock:inventory_reconcile"
+	reconcileReportKey        = "stock:reconcile:last"
+	reconcileBatchSize        = 500
+	reconcileScanCount        = 500
+	reconcileJobTimeout       = 30 * time.Minute
+)
+
+// ErrReconcileNotRun is returned when no reconciliation has finished yet
+var ErrReconcileNotRun = domain.NotFound("inventory reconciliation has not run yet")
+
+// ErrReconcileRunning is returned when a reconciliation is already in progress on this instance
+var ErrReconcileRunning = domain.Conflict("inventory reconciliation already running")
+
+// SearchStockIndex reads and rewrites stock data in the search index (implemented by elasticsearch.CatalogIndexer)
+type SearchStockIndex interface {
+	GetStockTotals(ctx context.Context, index string, ids []uint) (map[uint]int, error)
+	BulkIndex(ctx context.Context, index string, docs []*domain.ProductDocument) (int, error)
+}
+
+// ReconcileOptions configures the inventory reconciler
+type ReconcileOptions struct {
+	Hour       int    // local hour of the nightly run (0-23)
+	HealSearch bool   // re-index products whose search stock is wrong or missing
+	IndexName  string // live search index (or alias)
+}
+
+// ReconcileReport summarizes one reconciliation run
+type ReconcileReport struct {
+	RunID         string         `json:"run_id"`
+	StartedAt     time.Time      `json:"started_at"`
+	FinishedAt    time.Time      `json:"finished_at"`
+	Products      int            `json:"products"`
+	Items         int            `json:"items"`
+	Reservations  int            `json:"reservations"`
+	Discrepancies int            `json:"discrepancies"`
+	ByKind        map[string]int `json:"by_kind"`
+	Healed        int            `json:"healed"`
+}
+
+// InventoryReconcileService compares stock across Postgres (qty_in_stock), Redis
+// (reserved counters vs live reservation keys) and Elasticsearch (total_stock).
+// Counts are sampled without locking, so a checkout racing the scan can show up
+// as drift once; drift that persists across runs is the real signal
+type InventoryReconcileService struct {
+	productRepo     domain.ProductRepository
+	productItemRepo domain.ProductItemRepository
+	discrepancyRepo domain.InventoryDiscrepancyRepository
+	redisClient     *redis.Client
+	search          SearchStockIndex
+	builder         *CatalogDocumentBuilder
+	jobs            JobEnqueuer
+	opts            ReconcileOptions
+	running         sync.Mutex
+	logger          *zap.Logger
+}
+
+// NewInventoryReconcileService creates a new inventory reconcile service
+func NewInventoryReconcileService(
+	productRepo domain.ProductRepository,
+	productItemRepo domain.ProductItemRepository,
+	discrepancyRepo domain.InventoryDiscrepancyRepository,
+	redisClient *redis.Client,
+	search SearchStockIndex,
+	builder *CatalogDocumentBuilder,
+	jobEnqueuer JobEnqueuer,
+	opts ReconcileOptions,
+	logger *zap.Logger,
+) *InventoryReconcileService {
+	if opts.Hour < 0 || opts.Hour > 23 {
+		opts.Hour = 3
+	}
+	return &InventoryReconcileService{
+		productRepo:     productRepo,
+		productItemRepo: productItemRepo,
+		discrepancyRepo: discrepancyRepo,
+		redisClient:     redisClient,
+		search:          search,
+		builder:         builder,
+		jobs:            jobEnqueuer,
+		opts:            opts,
+		logger:          logger,
+	}
+}
+
+// ScheduleNext enqueues the next nightly run; the job ID is per day, so calling
+// this from several instances (or after every run) schedules it only once
+func (s *InventoryReconcileService) ScheduleNext(ctx context.Context) error {
+	now := time.Now()
+	next := time.Date(now.Year(), now.Month(), now.Day(), s.opts.Hour, 0, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+
+	_, err := s.jobs.Enqueue(ctx, JobTypeInventoryReconcile, nil,
+		jobs.WithID("inventory_reconcile:"+next.Format("20060102")),
+		jobs.ProcessAt(next),
+		jobs.Timeout(reconcileJobTimeout),
+		jobs.MaxRetries(2),
+	)
+	if err != nil && !errors.Is(err, jobs.ErrDuplicateJob) {
+		return fmt.Errorf("failed to schedule inventory reconciliation: %w", err)
+	}
+	return nil
+}
+
+// RunNow enqueues an immediate reconciliation (admin trigger)
+func (s *InventoryReconcileService) RunNow(ctx context.Context) (*jobs.Job, error) {
+	job, err := s.jobs.Enqueue(ctx, JobTypeInventoryReconcile, nil, jobs.Timeout(reconcileJobTimeout), jobs.MaxRetries(0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue inventory reconciliation: %w", err)
+	}
+	return job, nil
+}
+
+// HandleReconcile is the job handler for JobTypeInventoryReconcile
+func (s *InventoryReconcileService) HandleReconcile(ctx context.Context, _ []byte) error {
+	defer func() {
+		if err := s.ScheduleNext(context.WithoutCancel(ctx)); err != nil {
+			s.logger.Warn("failed to schedule next inventory reconciliation", zap.Error(err))
+		}
+	}()
+
+	_, err := s.Reconcile(ctx)
+	if errors.Is(err, ErrReconcileRunning) {
+		return nil
+	}
+	return err
+}
+
+// Reconcile runs one full comparison, stores the discrepancies and the report,
+// and (when enabled) re-indexes products whose search stock is wrong
+func (s *InventoryReconcileService) Reconcile(ctx context.Context) (*ReconcileReport, error) {
+	if !s.running.TryLock() {
+		return nil, ErrReconcileRunning
+	}
+	defer s.running.Unlock()
+
+	report := &ReconcileReport{
+		RunID:     time.Now().UTC().Format("20060102T150405Z"),
+		StartedAt: time.Now(),
+		ByKind:    make(map[string]int),
+	}
+
+	counters, err := s.scanReservedCounters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	expected, reservations, err := s.scanReservations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.Reservations = reservations
+
+	var discrepancies []*domain.InventoryDiscrepancy
+	seen := make(map[uint]bool)
+
+	var lastID uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		products, err := s.productRepo.ListAfterID(ctx, lastID, reconcileBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load products after ID %d: %w", lastID, err)
+		}
+		if len(products) == 0 {
+			break
+		}
+		lastID = products[len(products)-1].ID
+
+		batch, err := s.reconcileBatch(ctx, report, products, counters, expected, seen)
+		if err != nil {
+			return nil, err
+		}
+		discrepancies = append(discrepancies, batch...)
+	}
+
+	// Counters or reservations for SKUs that no longer exist in Postgres
+	for id := range counters {
+		if !seen[id] {
+			discrepancies = append(discrepancies, newReservedDrift(id, 0, counters[id], expected[id]))
+			seen[id] = true
+		}
+	}
+	for id := range expected {
+		if !seen[id] {
+			discrepancies = append(discrepancies, newReservedDrift(id, 0, counters[id], expected[id]))
+		}
+	}
+
+	for _, d := range discrepancies {
+		d.RunID = report.RunID
+		report.ByKind[d.Kind]++
+		if d.Healed {
+			report.Healed++
+		}
+	}
+	report.Discrepancies = len(discrepancies)
+	report.FinishedAt = time.Now()
+
+	if err := s.discrepancyRepo.CreateBatch(ctx, discrepancies); err != nil {
+		return nil, fmt.Errorf("failed to save discrepancies: %w", err)
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal report: %w", err)
+	}
+	if err := s.redisClient.Set(ctx, reconcileReportKey, data, 0).Err(); err != nil {
+		return nil, fmt.Errorf("failed to store report: %w", err)
+	}
+
+	fields := []zap.Field{
+		zap.String("run_id", report.RunID),
+		zap.Int("products", report.Products),
+		zap.Int("items", report.Items),
+		zap.Int("discrepancies", report.Discrepancies),
+		zap.Any("by_kind", report.ByKind),
+		zap.Int("healed", report.Healed),
+		zap.Duration("duration", report.FinishedAt.Sub(report.StartedAt)),
+	}
+	if report.Discrepancies > 0 {
+		s.logger.Warn("inventory reconciliation found discrepancies", fields...)
+	} else {
+		s.logger.Info("inventory reconciliation completed", fields...)
+	}
+
+	return report, nil
+}
+
+// reconcileBatch checks one page of products and their SKUs
+func (s *InventoryReconcileService) reconcileBatch(
+	ctx context.Context,
+	report *ReconcileReport,
+	products []*domain.Product,
+	counters, expected map[uint]int,
+	seen map[uint]bool,
+) ([]*domain.InventoryDiscrepancy, error) {
+	ids := make([]uint, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+
+	items, err := s.productItemRepo.GetByProductIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load product items: %w", err)
+	}
+	searchTotals, err := s.search.GetStockTotals(ctx, s.opts.IndexName, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read search stock: %w", err)
+	}
+
+	var discrepancies []*domain.InventoryDiscrepancy
+	totals := make(map[uint]int, len(products))
+	for _, item := range items {
+		seen[item.ID] = true
+		totals[item.ProductID] += item.QtyInStock
+
+		reserved, want := counters[item.ID], expected[item.ID]
+		if reserved != want {
+			d := newReservedDrift(item.ID, item.QtyInStock, reserved, want)
+			d.ProductID = item.ProductID
+			discrepancies = append(discrepancies, d)
+		}
+		if reserved > item.QtyInStock {
+			itemID := item.ID
+			discrepancies = append(discrepancies, &domain.InventoryDiscrepancy{
+				Kind:          domain.DiscrepancyOverReserved,
+				ProductID:     item.ProductID,
+				ProductItemID: &itemID,
+				PostgresQty:   item.QtyInStock,
+				RedisReserved: &reserved,
+			})
+		}
+	}
+	report.Products += len(products)
+	report.Items += len(items)
+
+	var stale []*domain.Product
+	var searchFindings []*domain.InventoryDiscrepancy
+	for _, p := range products {
+		indexed, ok := searchTotals[p.ID]
+		switch {
+		case !ok:
+			searchFindings = append(searchFindings, &domain.InventoryDiscrepancy{
+				Kind:        domain.DiscrepancySearchMissing,
+				ProductID:   p.ID,
+				PostgresQty: totals[p.ID],
+			})
+		case indexed != totals[p.ID]:
+			searchQty := indexed
+			searchFindings = append(searchFindings, &domain.InventoryDiscrepancy{
+				Kind:        domain.DiscrepancySearchStock,
+				ProductID:   p.ID,
+				PostgresQty: totals[p.ID],
+				SearchQty:   &searchQty,
+			})
+		default:
+			continue
+		}
+		stale = append(stale, p)
+	}
+
+	if s.opts.HealSearch && len(stale) > 0 {
+		if s.healSearch(ctx, stale) {
+			for _, d := range searchFindings {
+				d.Healed = true
+			}
+		}
+	}
+
+	return append(discrepancies, searchFindings...), nil
+}
+
+// healSearch re-indexes products from Postgres; reports whether every document was written
+func (s *InventoryReconcileService) healSearch(ctx context.Context, products []*domain.Product) bool {
+	docs, err := s.builder.Build(ctx, products)
+	if err != nil {
+		s.logger.Error("failed to build search documents for healing", zap.Error(err))
+		return false
+	}
+	failed, err := s.search.BulkIndex(ctx, s.opts.IndexName, docs)
+	if err != nil {
+		s.logger.Error("failed to re-index products", zap.Int("count", len(docs)), zap.Error(err))
+		return false
+	}
+	if failed > 0 {
+		s.logger.Warn("some products could not be re-indexed", zap.Int("count", len(docs)), zap.Int("failed", failed))
+		return false
+	}
+	return true
+}
+
+// scanReservedCounters reads every stock:reserved:{id} counter
+func (s *InventoryReconcileService) scanReservedCounters(ctx context.Context) (map[uint]int, error) {
+	counters := make(map[uint]int)
+	err := s.scanValues(ctx, "stock:reserved:*", func(key, value string) {
+		id, err := strconv.ParseUint(strings.TrimPrefix(key, "stock:reserved:"), 10, 64)
+		if err != nil {
+			return
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			s.logger.Warn("invalid reserved counter", zap.String("key", key), zap.String("value", value))
+			return
+		}
+		counters[uint(id)] = n
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan reserved counters: %w", err)
+	}
+	return counters, nil
+}
+
+// scanReservations sums live reservation keys per SKU (what the counters should hold)
+func (s *InventoryReconcileService) scanReservations(ctx context.Context) (map[uint]int, int, error) {
+	expected := make(map[uint]int)
+	count := 0
+	err := s.scanValues(ctx, "stock:reservation:*", func(key, value string) {
+		var reservation domain.StockReservation
+		if err := json.Unmarshal([]byte(value), &reservation); err != nil {
+			s.logger.Warn("invalid reservation data", zap.String("key", key), zap.Error(err))
+			return
+		}
+		expected[reservation.ProductItemID] += reservation.Quantity
+		count++
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to scan reservations: %w", err)
+	}
+	return expected, count, nil
+}
+
+// scanValues walks keys matching pattern with SCAN and loads them with MGET per page
+// Keys that expire between SCAN and MGET are skipped
+func (s *InventoryReconcileService) scanValues(ctx context.Context, pattern string, fn func(key, value string)) error {
+	var cursor uint64
+	for {
+		keys, next, err := s.redisClient.Scan(ctx, cursor, pattern, reconcileScanCount).Result()
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			values, err := s.redisClient.MGet(ctx, keys...).Result()
+			if err != nil {
+				return err
+			}
+			for i, v := range values {
+				if raw, ok := v.(string); ok {
+					fn(keys[i], raw)
+				}
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
+
+// GetLastReport returns the report of the last finished run and its discrepancies
+func (s *InventoryReconcileService) GetLastReport(ctx context.Context, limit int) (*ReconcileReport, []*domain.InventoryDiscrepancy, error) {
+	data, err := s.redisClient.Get(ctx, reconcileReportKey).Bytes()
+	if err == redis.Nil {
+		return nil, nil, ErrReconcileNotRun
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get reconcile report: %w", err)
+	}
+
+	var report ReconcileReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode reconcile report: %w", err)
+	}
+
+	discrepancies, err := s.discrepancyRepo.ListByRun(ctx, report.RunID, limit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list discrepancies: %w", err)
+	}
+	return &report, discrepancies, nil
+}
+
+// newReservedDrift records a reserved counter that does not match the live reservations
+func newReservedDrift(productItemID uint, qtyInStock, reserved, expected int) *domain.InventoryDiscrepancy {
+	return &domain.InventoryDiscrepancy{
+		Kind:          domain.DiscrepancyReservedDrift,
+		ProductItemID: &productItemID,
+		PostgresQty:   qtyInStock,
+		RedisReserved: &reserved,
+		ExpectedQty:   &expected,
+	}
+}
@@ -0,0 +1,404 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"product-service/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxInventorySheetProducts bounds the products of one export / import
+	maxInventorySheetProducts = 2000
+	// maxInventoryRowErrors caps the row errors reported by a preview
+	maxInventoryRowErrors = 100
+
+	inventoryImportKeyPrefix = "inventory_import:"
+	inventoryImportTTL       = 30 * time.Minute
+)
+
+// Inventory sheet columns; import finds them by header, so sellers may reorder or add columns
+const (
+	inventoryColProductID   = "Product ID"
+	inventoryColProductName = "Product Name"
+	inventoryColSKUID       = "SKU ID"
+	inventoryColSKUCode     = "SKU Code"
+	inventoryColStatus      = "Status"
+	inventoryColPrice       = "Price"
+	inventoryColStock       = "Stock"
+)
+
+var inventorySheetHeader = []any{
+	inventoryColProductID,
+	inventoryColProductName,
+	inventoryColSKUID,
+	inventoryColSKUCode,
+	inventoryColStatus,
+	inventoryColPrice,
+	inventoryColStock,
+}
+
+var (
+	ErrInvalidInventorySheet   = domain.Validation("invalid inventory sheet")
+	ErrInventoryImportNotFound = domain.NotFound("inventory import not found or expired")
+	ErrInventoryImportInvalid  = errors.New("inventory import has row errors; fix them and upload the sheet again")
+)
+
+// InventoryChange is one SKU whose price or stock the sheet changes
+type InventoryChange struct {
+	Row           int     `json:"row"`
+	ProductItemID uint    `json:"product_item_id"`
+	ProductID     uint    `json:"product_id"`
+	ProductName   string  `json:"product_name"`
+	SKUCode       string  `json:"sku_code"`
+	Version       int     `json:"version"` // SKU version at preview time; changed SKUs are not overwritten
+	OldPrice      float64 `json:"old_price"`
+	NewPrice      float64 `json:"new_price"`
+	OldStock      int     `json:"old_stock"`
+	NewStock      int     `json:"new_stock"`
+}
+
+// InventoryRowError is a sheet row that cannot be applied
+type InventoryRowError struct {
+	Row     int    `json:"row"`
+	SKUCode string `json:"sku_code,omitempty"`
+	Message string `json:"message"`
+}
+
+// InventoryImportPreview is the validated diff of an uploaded sheet, kept for 30 minutes
+// until it is committed
+type InventoryImportPreview struct {
+	ID         string              `json:"id"`
+	ShopID     uint                `json:"shop_id"`
+	Rows       int                 `json:"rows"`      // SKU rows in the sheet
+	Unchanged  int                 `json:"unchanged"` // Rows matching the current price and stock
+	Changes    []InventoryChange   `json:"changes"`
+	Errors     []InventoryRowError `json:"errors"` // Capped; commit is refused while there are errors
+	ErrorCount int                 `json:"error_count"`
+	ExpiresAt  time.Time           `json:"expires_at"`
+}
+
+// InventoryImportResult is the outcome of committing a preview
+type InventoryImportResult struct {
+	ID        string              `json:"id"`
+	Applied   int                 `json:"applied"`
+	Conflicts []InventoryRowError `json:"conflicts"` // SKUs changed since the preview, left untouched
+}
+
+// InventorySheetService exports a shop's SKUs with price and stock as an Excel sheet and
+// applies edited sheets in two steps: upload (validate + diff preview) and commit
+type InventorySheetService struct {
+	productRepo     domain.ProductRepository
+	productItemRepo domain.ProductItemRepository
+	redisClient     *redis.Client
+	events          productItemEvents
+	logger          *zap.Logger
+}
+
+// NewInventorySheetService creates a new inventory sheet service
+func NewInventorySheetService(
+	productRepo domain.ProductRepository,
+	productItemRepo domain.ProductItemRepository,
+	redisClient *redis.Client,
+	eventPublisher domain.EventPublisher,
+	async AsyncRunner,
+	logger *zap.Logger,
+) *InventorySheetService {
+	return &InventorySheetService{
+		productRepo:     productRepo,
+		productItemRepo: productItemRepo,
+		redisClient:     redisClient,
+		events:          productItemEvents{publisher: eventPublisher, async: async},
+		logger:          logger,
+	}
+}
+
+// ExportRows returns the sheet rows (header first) of every SKU of the shop
+func (s *InventorySheetService) ExportRows(ctx context.Context, shopID uint) ([][]any, error) {
+	products, err := s.shopProducts(ctx, shopID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := [][]any{inventorySheetHeader}
+	for _, p := range products {
+		for _, item := range p.Items {
+			rows = append(rows, []any{p.ID, p.Name, item.ID, item.SKUCode, item.Status, item.Price, item.QtyInStock})
+		}
+	}
+	return rows, nil
+}
+
+// PreviewImport validates the edited sheet against the shop's SKUs and stores the diff
+// Rows are matched by SKU code; only Price and Stock are applied
+func (s *InventorySheetService) PreviewImport(ctx context.Context, shopID uint, rows [][]string) (*InventoryImportPreview, error) {
+	header, start := inventoryHeader(rows)
+	if header == nil {
+		return nil, fmt.Errorf("%w: header row with %q, %q and %q columns not found",
+			ErrInvalidInventorySheet, inventoryColSKUCode, inventoryColPrice, inventoryColStock)
+	}
+
+	products, err := s.shopProducts(ctx, shopID)
+	if err != nil {
+		return nil, err
+	}
+	type shopSKU struct {
+		product *domain.Product
+		item    *domain.ProductItem
+	}
+	skus := make(map[string]shopSKU)
+	for _, p := range products {
+		for _, item := range p.Items {
+			skus[item.SKUCode] = shopSKU{product: p, item: item}
+		}
+	}
+
+	preview := &InventoryImportPreview{
+		ID:        newImportID(),
+		ShopID:    shopID,
+		Changes:   []InventoryChange{},
+		Errors:    []InventoryRowError{},
+		ExpiresAt: time.Now().Add(inventoryImportTTL),
+	}
+	addError := func(row int, skuCode, message string) {
+		preview.ErrorCount++
+		if len(preview.Errors) < maxInventoryRowErrors {
+			preview.Errors = append(preview.Errors, InventoryRowError{Row: row, SKUCode: skuCode, Message: message})
+		}
+	}
+
+	seen := make(map[string]int)
+	for i := start; i < len(rows); i++ {
+		rowNumber := i + 1
+		cell := func(col string) string {
+			j := header[col]
+			if j < len(rows[i]) {
+				return strings.TrimSpace(rows[i][j])
+			}
+			return ""
+		}
+
+		skuCode := cell(inventoryColSKUCode)
+		price, stock := cell(inventoryColPrice), cell(inventoryColStock)
+		if skuCode == "" && price == "" && stock == "" {
+			continue // Blank row
+		}
+		preview.Rows++
+
+		if skuCode == "" {
+			addError(rowNumber, "", "SKU code is required")
+			continue
+		}
+		if first, ok := seen[skuCode]; ok {
+			addError(rowNumber, skuCode, fmt.Sprintf("duplicate of row %d", first))
+			continue
+		}
+		seen[skuCode] = rowNumber
+
+		sku, ok := skus[skuCode]
+		if !ok {
+			addError(rowNumber, skuCode, "SKU not found in this shop")
+			continue
+		}
+
+		newPrice, err := parseSheetPrice(price)
+		if err != nil {
+			addError(rowNumber, skuCode, err.Error())
+			continue
+		}
+		newStock, err := parseSheetStock(stock)
+		if err != nil {
+			addError(rowNumber, skuCode, err.Error())
+			continue
+		}
+
+		if newPrice == sku.item.Price && newStock == sku.item.QtyInStock {
+			preview.Unchanged++
+			continue
+		}
+		preview.Changes = append(preview.Changes, InventoryChange{
+			Row:           rowNumber,
+			ProductItemID: sku.item.ID,
+			ProductID:     sku.product.ID,
+			ProductName:   sku.product.Name,
+			SKUCode:       skuCode,
+			Version:       sku.item.Version,
+			OldPrice:      sku.item.Price,
+			NewPrice:      newPrice,
+			OldStock:      sku.item.QtyInStock,
+			NewStock:      newStock,
+		})
+	}
+
+	data, err := json.Marshal(preview)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal inventory import: %w", err)
+	}
+	if err := s.redisClient.Set(ctx, inventoryImportKeyPrefix+preview.ID, data, inventoryImportTTL).Err(); err != nil {
+		return nil, fmt.Errorf("failed to save inventory import: %w", err)
+	}
+
+	s.logger.Info("inventory import previewed",
+		zap.String("import_id", preview.ID),
+		zap.Uint("shop_id", shopID),
+		zap.Int("rows", preview.Rows),
+		zap.Int("changes", len(preview.Changes)),
+		zap.Int("errors", preview.ErrorCount),
+	)
+	return preview, nil
+}
+
+// CommitImport applies a previewed import once. SKUs changed since the preview
+// (orders, other edits) are reported as conflicts and left untouched
+func (s *InventorySheetService) CommitImport(ctx context.Context, shopID uint, id string) (*InventoryImportResult, error) {
+	key := inventoryImportKeyPrefix + id
+	data, err := s.redisClient.Get(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrInventoryImportNotFound
+		}
+		return nil, fmt.Errorf("failed to load inventory import: %w", err)
+	}
+	var preview InventoryImportPreview
+	if err := json.Unmarshal(data, &preview); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal inventory import: %w", err)
+	}
+	if preview.ShopID != shopID {
+		return nil, ErrInventoryImportNotFound
+	}
+	if preview.ErrorCount > 0 {
+		return nil, ErrInventoryImportInvalid
+	}
+
+	// Single use: whoever deletes the preview applies it
+	deleted, err := s.redisClient.Del(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim inventory import: %w", err)
+	}
+	if deleted == 0 {
+		return nil, ErrInventoryImportNotFound
+	}
+
+	result := &InventoryImportResult{ID: id, Conflicts: []InventoryRowError{}}
+	for _, change := range preview.Changes {
+		if err := s.applyChange(ctx, change); err != nil {
+			if !errors.Is(err, domain.ErrVersionConflict) && !errors.Is(err, ErrProductItemNotFound) {
+				s.logger.Error("failed to apply inventory change",
+					zap.String("import_id", id),
+					zap.Uint("product_item_id", change.ProductItemID),
+					zap.Error(err),
+				)
+			}
+			result.Conflicts = append(result.Conflicts, InventoryRowError{Row: change.Row, SKUCode: change.SKUCode, Message: err.Error()})
+			continue
+		}
+		result.Applied++
+	}
+
+	s.logger.Info("inventory import committed",
+		zap.String("import_id", id),
+		zap.Uint("shop_id", shopID),
+		zap.Int("applied", result.Applied),
+		zap.Int("conflicts", len(result.Conflicts)),
+	)
+	return result, nil
+}
+
+// applyChange writes one change if the SKU is still at the previewed version
+// Status follows stock like UpdateStock (0 = OUT_OF_STOCK, restocked = ACTIVE)
+func (s *InventorySheetService) applyChange(ctx context.Context, change InventoryChange) error {
+	item, err := s.productItemRepo.GetByID(ctx, change.ProductItemID)
+	if err != nil {
+		return ErrProductItemNotFound
+	}
+	if item.Version != change.Version {
+		return domain.ErrVersionConflict
+	}
+
+	previousPrice, previousStock := item.Price, item.QtyInStock
+	item.Price = change.NewPrice
+	item.QtyInStock = change.NewStock
+	if item.QtyInStock == 0 && item.Status == domain.ProductItemStatusActive {
+		item.Status = domain.ProductItemStatusOutOfStock
+	} else if item.QtyInStock > 0 && item.Status == domain.ProductItemStatusOutOfStock {
+		item.Status = domain.ProductItemStatusActive
+	}
+	if err := s.productItemRepo.Update(ctx, item); err != nil {
+		return err
+	}
+
+	s.events.publishChange(ctx, item, previousPrice, previousStock)
+	return nil
+}
+
+// shopProducts loads the shop's products with their (not deleted) SKUs
+func (s *InventorySheetService) shopProducts(ctx context.Context, shopID uint) ([]*domain.Product, error) {
+	products, total, err := s.productRepo.GetProductsByShopID(ctx, shopID, 1, maxInventorySheetProducts, domain.WithItems())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load shop products: %w", err)
+	}
+	if total > maxInventorySheetProducts {
+		return nil, fmt.Errorf("%w: shops with more than %d products are not supported", ErrInvalidInventorySheet, maxInventorySheetProducts)
+	}
+	return products, nil
+}
+
+// inventoryHeader finds the header row (first row with the required columns) and maps
+// column names to indexes; returns the index of the first data row
+func inventoryHeader(rows [][]string) (map[string]int, int) {
+	for i, row := range rows {
+		header := make(map[string]int)
+		for j, name := range row {
+			for _, col := range inventorySheetHeader {
+				if strings.EqualFold(strings.TrimSpace(name), col.(string)) {
+					header[col.(string)] = j
+				}
+			}
+		}
+		_, hasSKU := header[inventoryColSKUCode]
+		_, hasPrice := header[inventoryColPrice]
+		_, hasStock := header[inventoryColStock]
+		if hasSKU && hasPrice && hasStock {
+			return header, i + 1
+		}
+	}
+	return nil, 0
+}
+
+// parseSheetPrice parses a price cell (> 0, rounded to 2 decimals)
+func parseSheetPrice(raw string) (float64, error) {
+	if raw == "" {
+		return 0, errors.New("price is required")
+	}
+	price, err := strconv.ParseFloat(strings.ReplaceAll(raw, ",", ""), 64)
+	if err != nil || math.IsNaN(price) || math.IsInf(price, 0) {
+		return 0, fmt.Errorf("invalid price %q", raw)
+	}
+	if price <= 0 {
+		return 0, errors.New("price must be greater than 0")
+	}
+	return math.Round(price*100) / 100, nil
+}
+
+// parseSheetStock parses a stock cell (whole number >= 0)
+func parseSheetStock(raw string) (int, error) {
+	if raw == "" {
+		return 0, errors.New("stock is required")
+	}
+	stock, err := strconv.ParseFloat(strings.ReplaceAll(raw, ",", ""), 64)
+	if err != nil || stock != math.Trunc(stock) || stock > math.MaxInt32 {
+		return 0, fmt.Errorf("invalid stock %q", raw)
+	}
+	if stock < 0 {
+		return 0, errors.New("stock cannot be negative")
+	}
+	return int(stock), nil
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-service/internal/domain"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// OutboxOptions configures the outbox relay
+type OutboxOptions struct {
+	PollInterval time.Duration
+	BatchSize    int
+	MaxBackoff   time.Duration
+	Retention    time.Duration
+}
+
+// outboxCleanupInterval is how often relayed events past the retention are deleted
+const outboxCleanupInterval = time.Hour
+
+// OutboxRelay drains the product event outbox to Kafka: events are published in outbox
+// order, each at least once. A failing event blocks the ones behind it (their order
+// matters) and is retried with a doubling delay
+type OutboxRelay struct {
+	outbox    domain.OutboxRepository
+	publisher domain.EventPublisher
+	shops     ShopLocator
+	opts      OutboxOptions
+	logger    *zap.Logger
+
+	relayed atomic.Int64
+	failed  atomic.Int64
+
+	errMu       sync.Mutex
+	lastError   string
+	lastErrorAt time.Time
+}
+
+// NewOutboxRelay creates a new outbox relay
+func NewOutboxRelay(
+	outbox domain.OutboxRepository,
+	publisher domain.EventPublisher,
+	shops ShopLocator,
+	opts OutboxOptions,
+	logger *zap.Logger,
+) *OutboxRelay {
+	return &OutboxRelay{
+		outbox:    outbox,
+		publisher: publisher,
+		shops:     shops,
+		opts:      opts,
+		logger:    logger,
+	}
+}
+
+// Start relays the outbox until ctx is canceled: right away while full batches come out,
+// every PollInterval once drained, after a doubling delay while relaying fails
+func (r *OutboxRelay) Start(ctx context.Context) {
+	delay := r.opts.PollInterval
+	lastCleanup := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		n, err := r.RelayOnce(ctx)
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return
+			}
+			r.logger.Warn("outbox relay failed, retrying", zap.Int("relayed", n), zap.Duration("retry_in", delay), zap.Error(err))
+			delay = min(max(delay*2, r.opts.PollInterval), r.opts.MaxBackoff)
+		case n == r.opts.BatchSize:
+			delay = 0
+		default:
+			delay = r.opts.PollInterval
+		}
+
+		if time.Since(lastCleanup) >= outboxCleanupInterval {
+			lastCleanup = time.Now()
+			r.cleanup(ctx)
+		}
+	}
+}
+
+// RelayOnce publishes one batch of pending events, returns how many were published
+func (r *OutboxRelay) RelayOnce(ctx context.Context) (int, error) {
+	n, err := r.outbox.Relay(ctx, r.opts.BatchSize, r.publish)
+	r.relayed.Add(int64(n))
+	if err != nil {
+		r.failed.Add(1)
+		r.recordError(err)
+	}
+	return n, err
+}
+
+// publish sends one outbox event; product data gets the ships-from location of its shop,
+// looked up now rather than in the transaction that wrote the event
+func (r *OutboxRelay) publish(ctx context.Context, outboxEvent *domain.OutboxEvent) error {
+	event, err := outboxEvent.ProductEvent()
+	if err != nil {
+		return err
+	}
+	if event.ProductData != nil {
+		event.ProductData = withShopLocation(ctx, r.shops, r.logger, event.ProductData)
+	}
+
+	if err := r.publisher.PublishProductEvent(ctx, event); err != nil {
+		return fmt.Errorf("failed to publish outbox event %d: %w", outboxEvent.ID, err)
+	}
+	r.logger.Debug("outbox event published to kafka",
+		zap.Uint64("outbox_id", outboxEvent.ID),
+		zap.Uint("product_id", event.ProductID),
+		zap.String("event_type", event.EventType),
+	)
+	return nil
+}
+
+// cleanup deletes the events relayed more than Retention ago
+func (r *OutboxRelay) cleanup(ctx context.Context) {
+	deleted, err := r.outbox.DeletePublishedBefore(ctx, time.Now().Add(-r.opts.Retention))
+	if err != nil {
+		r.logger.Warn("failed to delete relayed outbox events", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		r.logger.Info("relayed outbox events deleted", zap.Int64("deleted", deleted))
+	}
+}
+
+// Stats returns the outbox backlog and the relay counters of this instance
+func (r *OutboxRelay) Stats(ctx context.Context) domain.OutboxStats {
+	stats := domain.OutboxStats{
+		Relayed: r.relayed.Load(),
+		Failed:  r.failed.Load(),
+	}
+
+	pending, oldest, err := r.outbox.Backlog(ctx)
+	if err != nil {
+		r.logger.Warn("failed to read outbox backlog", zap.Error(err))
+	}
+	stats.Pending = pending
+	if oldest != nil {
+		stats.LagSeconds = time.Since(oldest.CreatedAt).Seconds()
+		stats.HeadAttempts = oldest.Attempts
+	}
+
+	r.errMu.Lock()
+	if r.lastError != "" {
+		at := r.lastErrorAt
+		stats.LastError = r.lastError
+		stats.LastErrorAt = &at
+	}
+	r.errMu.Unlock()
+
+	return stats
+}
+
+func (r *OutboxRelay) recordError(err error) {
+	r.errMu.Lock()
+	r.lastError = err.Error()
+	r.lastErrorAt = time.Now()
+	r.errMu.Unlock()
+}
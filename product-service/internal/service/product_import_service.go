@@ -0,0 +1,647 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"product-service/internal/domain"
+	"product-service/pkg/jobs"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+)
+
+// Product import moves a catalog exported from another marketplace into a shop.
+// The export is kept in Redis and imported by a background job one product at a
+// time; progress is saved after every product, so a retried job resumes where it stopped
+const (
+	JobTypeProductImport    = "catalog:product_import"
+	productImportKeyPrefix  = "import:product:"
+	productImportTTL        = 7 * 24 * time.Hour
+	productImportJobTimeout = 30 * time.Minute
+	maxImportIssues         = 200
+	maxImportExportBytes    = 20 << 20
+	maxSKUCodeLength        = 50
+)
+
+// Import states
+const (
+	ImportStatePending   = "PENDING"
+	ImportStateRunning   = "RUNNING"
+	ImportStateCompleted = "COMPLETED"
+	ImportStateFailed    = "FAILED"
+)
+
+// Product import errors
+var (
+	ErrImportNotFound = domain.NotFound("product import not found")
+	ErrInvalidImport  = domain.Validation("invalid product import")
+)
+
+// ImportOptions configures the product import tool
+type ImportOptions struct {
+	MappingFile  string        // JSON category/attribute mapping, read when an import starts
+	MaxProducts  int           // Max products per import
+	FetchTimeout time.Duration // Timeout for downloading an export_url
+}
+
+// ImportMapping maps the categories and attributes of source platforms to this catalog
+type ImportMapping struct {
+	Sources map[string]*SourceMapping `json:"sources"` // Keyed by source platform, e.g. "shopee"
+}
+
+// SourceMapping is the mapping of one source platform
+type SourceMapping struct {
+	DefaultCategoryID uint              `json:"default_category_id"` // Used for unmapped categories (0 = such products fail)
+	Categories        map[string]uint   `json:"categories"`          // Source category ID or path -> category ID
+	Attributes        map[string]string `json:"attributes"`          // Source attribute name -> attribute name in the category
+}
+
+// ExportedProduct is one product of a source platform export
+type ExportedProduct struct {
+	ExternalID  string            `json:"external_id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Category    string            `json:"category"` // Source category ID or path, resolved with the mapping
+	Price       float64           `json:"price"`    // Defaults to the lowest item price
+	Images      []string          `json:"images"`   // Image URLs, imported as-is
+	Attributes  map[string]string `json:"attributes"`
+	Items       []ExportedItem    `json:"items"` // No items = one SKU at the product price
+}
+
+// ExportedItem is one SKU of an exported product
+type ExportedItem struct {
+	SKU      string  `json:"sku"` // Generated from the external ID when empty
+	Price    float64 `json:"price"`
+	Stock    int     `json:"stock"`
+	ImageURL string  `json:"image_url"`
+}
+
+// ProductExport is the document served at an export_url
+type ProductExport struct {
+	Products []ExportedProduct `json:"products"`
+}
+
+// ProductImportRequest represents the request to start a product import
+// The export is either sent inline (products) or downloaded from export_url
+type ProductImportRequest struct {
+	ShopID    uint              `json:"shop_id" binding:"required"`
+	Source    string            `json:"source" binding:"required"` // Source platform key in the mapping file
+	ExportURL string            `json:"export_url"`
+	Products  []ExportedProduct `json:"products"`
+	Activate  bool              `json:"activate"` // Publish right away; by default products are imported INACTIVE for review
+}
+
+// ProductImport is the progress and result of one import
+type ProductImport struct {
+	ID         string        `json:"id"`
+	ShopID     uint          `json:"shop_id"`
+	Source     string        `json:"source"`
+	ExportURL  string        `json:"export_url,omitempty"`
+	Activate   bool          `json:"activate"`
+	State      string        `json:"state"`
+	Total      int           `json:"total"`
+	Processed  int           `json:"processed"`
+	Imported   int           `json:"imported"`
+	Failed     int           `json:"failed"`
+	ProductIDs []uint        `json:"product_ids"`
+	Issues     []ImportIssue `json:"issues"` // Failed products and partial problems, capped
+	Error      string        `json:"error,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+	StartedAt  *time.Time    `json:"started_at,omitempty"`
+	FinishedAt *time.Time    `json:"finished_at,omitempty"`
+}
+
+// ImportIssue describes a product that failed, or a problem with an imported product (ProductID set)
+type ImportIssue struct {
+	Index      int    `json:"index"`
+	ExternalID string `json:"external_id,omitempty"`
+	ProductID  uint   `json:"product_id,omitempty"`
+	Message    string `json:"message"`
+}
+
+// productImportJob is the payload of JobTypeProductImport
+type productImportJob struct {
+	ImportID string `json:"import_id"`
+}
+
+// ProductImportService imports products, SKUs and images from other platforms' exports
+type ProductImportService struct {
+	productService   *ProductService
+	itemService      *ProductItemService
+	attributeService *AttributeService
+	categoryRepo     domain.CategoryRepository
+	categoryAttrRepo domain.CategoryAttributeRepository
+	redisClient      *redis.Client
+	jobs             JobEnqueuer
+	httpClient       *http.Client
+	opts             ImportOptions
+	logger           *zap.Logger
+}
+
+// NewProductImportService creates a new product import service
+func NewProductImportService(
+	productService *ProductService,
+	itemService *ProductItemService,
+	attributeService *AttributeService,
+	categoryRepo domain.CategoryRepository,
+	categoryAttrRepo domain.CategoryAttributeRepository,
+	redisClient *redis.Client,
+	jobEnqueuer JobEnqueuer,
+	opts ImportOptions,
+	logger *zap.Logger,
+) *ProductImportService {
+	if opts.MaxProducts <= 0 {
+		opts.MaxProducts = 1000
+	}
+	if opts.FetchTimeout <= 0 {
+		opts.FetchTimeout = 30 * time.Second
+	}
+	return &ProductImportService{
+		productService:   productService,
+		itemService:      itemService,
+		attributeService: attributeService,
+		categoryRepo:     categoryRepo,
+		categoryAttrRepo: categoryAttrRepo,
+		redisClient:      redisClient,
+		jobs:             jobEnqueuer,
+		httpClient:       &http.Client{Timeout: opts.FetchTimeout},
+		opts:             opts,
+		logger:           logger,
+	}
+}
+
+// StartImport validates the request, stores the export and enqueues the import job
+func (s *ProductImportService) StartImport(ctx context.Context, req *ProductImportRequest) (*ProductImport, error) {
+	source := strings.ToLower(strings.TrimSpace(req.Source))
+	exportURL := strings.TrimSpace(req.ExportURL)
+	if (exportURL == "") == (len(req.Products) == 0) {
+		return nil, fmt.Errorf("%w: send either products or export_url", ErrInvalidImport)
+	}
+	if exportURL != "" && !isHTTPURL(exportURL) {
+		return nil, fmt.Errorf("%w: export_url must be an http(s) URL", ErrInvalidImport)
+	}
+	if len(req.Products) > s.opts.MaxProducts {
+		return nil, fmt.Errorf("%w: at most %d products per import", ErrInvalidImport, s.opts.MaxProducts)
+	}
+
+	// Fail fast on an unknown source; the job reads the file again so edits apply
+	mapping, err := s.loadMapping()
+	if err != nil {
+		return nil, err
+	}
+	if mapping.Sources[source] == nil {
+		return nil, fmt.Errorf("%w: source %q is not in the mapping file", ErrInvalidImport, source)
+	}
+
+	imp := &ProductImport{
+		ID:         newImportID(),
+		ShopID:     req.ShopID,
+		Source:     source,
+		ExportURL:  exportURL,
+		Activate:   req.Activate,
+		State:      ImportStatePending,
+		Total:      len(req.Products),
+		ProductIDs: []uint{},
+		Issues:     []ImportIssue{},
+		CreatedAt:  time.Now(),
+	}
+	if len(req.Products) > 0 {
+		if err := s.saveExport(ctx, imp.ID, req.Products); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.saveImport(ctx, imp); err != nil {
+		return nil, err
+	}
+
+	_, err = s.jobs.Enqueue(ctx, JobTypeProductImport, productImportJob{ImportID: imp.ID},
+		jobs.WithID("product_import:"+imp.ID),
+		jobs.Timeout(productImportJobTimeout),
+		jobs.MaxRetries(3),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to enqueue product import: %w", err)
+	}
+
+	s.logger.Info("product import started",
+		zap.String("import_id", imp.ID),
+		zap.Uint("shop_id", imp.ShopID),
+		zap.String("source", imp.Source),
+		zap.Int("products", imp.Total),
+	)
+	return imp, nil
+}
+
+// GetImport returns the progress of an import
+func (s *ProductImportService) GetImport(ctx context.Context, id string) (*ProductImport, error) {
+	data, err := s.redisClient.Get(ctx, productImportKeyPrefix+id).Bytes()
+	if err == redis.Nil {
+		return nil, ErrImportNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product import: %w", err)
+	}
+
+	var imp ProductImport
+	if err := json.Unmarshal(data, &imp); err != nil {
+		return nil, fmt.Errorf("failed to decode product import: %w", err)
+	}
+	return &imp, nil
+}
+
+// HandleImport is the job handler for JobTypeProductImport
+// Problems with the export itself fail the import; storage errors are returned
+// so the job is retried and resumes after the last saved product
+func (s *ProductImportService) HandleImport(ctx context.Context, payload []byte) error {
+	var job productImportJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return fmt.Errorf("failed to decode product import job: %w", err)
+	}
+	imp, err := s.GetImport(ctx, job.ImportID)
+	if err != nil {
+		if errors.Is(err, ErrImportNotFound) {
+			s.logger.Warn("product import expired before it ran", zap.String("import_id", job.ImportID))
+			return nil
+		}
+		return err
+	}
+	if imp.State == ImportStateCompleted || imp.State == ImportStateFailed {
+		return nil
+	}
+
+	mapping, err := s.loadMapping()
+	if err != nil {
+		return s.failImport(ctx, imp, err)
+	}
+	sourceMapping := mapping.Sources[imp.Source]
+	if sourceMapping == nil {
+		return s.failImport(ctx, imp, fmt.Errorf("source %q was removed from the mapping file", imp.Source))
+	}
+
+	products, err := s.loadExport(ctx, imp)
+	if err != nil {
+		return s.failImport(ctx, imp, err)
+	}
+
+	if imp.State == ImportStatePending {
+		startedAt := time.Now()
+		imp.State = ImportStateRunning
+		imp.StartedAt = &startedAt
+		imp.Total = len(products)
+		if err := s.saveImport(ctx, imp); err != nil {
+			return err
+		}
+	}
+
+	attrs := make(map[uint]map[string]uint) // category -> lower-case attribute name -> attribute ID
+	for i := imp.Processed; i < len(products); i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		productID, problems, err := s.importProduct(ctx, imp, sourceMapping, attrs, i, &products[i])
+		if err != nil {
+			imp.Failed++
+			imp.addIssue(ImportIssue{Index: i, ExternalID: products[i].ExternalID, Message: err.Error()})
+		} else {
+			imp.Imported++
+			imp.ProductIDs = append(imp.ProductIDs, productID)
+			for _, problem := range problems {
+				imp.addIssue(ImportIssue{Index: i, ExternalID: products[i].ExternalID, ProductID: productID, Message: problem})
+			}
+		}
+		imp.Processed = i + 1
+		if err := s.saveImport(ctx, imp); err != nil {
+			return err
+		}
+	}
+
+	finishedAt := time.Now()
+	imp.State = ImportStateCompleted
+	imp.FinishedAt = &finishedAt
+	if err := s.saveImport(ctx, imp); err != nil {
+		return err
+	}
+	s.redisClient.Del(ctx, productImportKeyPrefix+imp.ID+":data")
+
+	s.logger.Info("product import completed",
+		zap.String("import_id", imp.ID),
+		zap.Int("imported", imp.Imported),
+		zap.Int("failed", imp.Failed),
+	)
+	return nil
+}
+
+// importProduct creates one product with its SKUs and attributes
+// An error means nothing was imported; problems are partial failures of an imported product
+func (s *ProductImportService) importProduct(
+	ctx context.Context,
+	imp *ProductImport,
+	mapping *SourceMapping,
+	attrs map[uint]map[string]uint,
+	index int,
+	exported *ExportedProduct,
+) (uint, []string, error) {
+	name := strings.TrimSpace(exported.Name)
+	if name == "" {
+		return 0, nil, errors.New("name is empty")
+	}
+
+	categoryID, ok := mapping.Categories[strings.TrimSpace(exported.Category)]
+	if !ok {
+		categoryID = mapping.DefaultCategoryID
+	}
+	if categoryID == 0 {
+		return 0, nil, fmt.Errorf("category %q is not mapped", exported.Category)
+	}
+	categoryAttrs, err := s.categoryAttributes(ctx, attrs, categoryID)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var problems []string
+	images := make([]string, 0, len(exported.Images))
+	for _, image := range exported.Images {
+		if isHTTPURL(image) {
+			images = append(images, image)
+		} else {
+			problems = append(problems, fmt.Sprintf("image %q skipped: not an http(s) URL", image))
+		}
+	}
+
+	items := exported.Items
+	if len(items) == 0 {
+		items = []ExportedItem{{Price: exported.Price}}
+	}
+	basePrice := exported.Price
+	if basePrice <= 0 {
+		for _, item := range items {
+			if item.Price > 0 && (basePrice <= 0 || item.Price < basePrice) {
+				basePrice = item.Price
+			}
+		}
+	}
+	if basePrice <= 0 {
+		return 0, nil, errors.New("price is missing")
+	}
+
+	var imagesJSON datatypes.JSON
+	if len(images) > 0 {
+		data, err := json.Marshal(images)
+		if err != nil {
+			return 0, nil, fmt.Errorf("invalid images: %w", err)
+		}
+		imagesJSON = datatypes.JSON(data)
+	}
+
+	status := "INACTIVE"
+	if imp.Activate {
+		status = "ACTIVE"
+	}
+	product := &domain.Product{
+		ShopID:      imp.ShopID,
+		Name:        name,
+		Description: exported.Description,
+		BasePrice:   basePrice,
+		CategoryID:  &categoryID,
+		Status:      status,
+		Images:      imagesJSON,
+		IsActive:    imp.Activate,
+		ProductType: domain.ProductTypePhysical,
+	}
+	if err := s.productService.CreateProduct(ctx, product); err != nil {
+		return 0, nil, err
+	}
+
+	for j, item := range items {
+		price := item.Price
+		if price <= 0 {
+			price = basePrice
+		}
+		imageURL := item.ImageURL
+		if !isHTTPURL(imageURL) && len(images) > 0 {
+			imageURL = images[0]
+		}
+		sku := strings.TrimSpace(item.SKU)
+		if sku == "" {
+			sku = importSKUCode(imp, exported.ExternalID, index, j)
+		}
+
+		_, err := s.itemService.CreateProductItem(ctx, &CreateProductItemRequest{
+			ProductID:  product.ID,
+			SKUCode:    sku,
+			ImageURL:   imageURL,
+			Price:      price,
+			QtyInStock: max(item.Stock, 0),
+		})
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("SKU %q: %v", sku, err))
+		}
+	}
+
+	values := make(map[uint]string)
+	var unmapped []string
+	for sourceName, value := range exported.Attributes {
+		targetName := sourceName
+		if mapped, ok := mapping.Attributes[sourceName]; ok {
+			targetName = mapped
+		}
+		if attrID, ok := categoryAttrs[strings.ToLower(strings.TrimSpace(targetName))]; ok {
+			values[attrID] = value
+		} else {
+			unmapped = append(unmapped, sourceName)
+		}
+	}
+	if len(unmapped) > 0 {
+		problems = append(problems, fmt.Sprintf("attributes skipped (not in the category): %s", strings.Join(unmapped, ", ")))
+	}
+	if len(values) > 0 {
+		if err := s.attributeService.SetProductAttributes(ctx, product.ID, &SetProductAttributesRequest{Attributes: values}); err != nil {
+			problems = append(problems, fmt.Sprintf("attributes: %v", err))
+		}
+	}
+
+	return product.ID, problems, nil
+}
+
+// categoryAttributes returns the attribute IDs of a category by lower-case name (cached per import)
+func (s *ProductImportService) categoryAttributes(ctx context.Context, cache map[uint]map[string]uint, categoryID uint) (map[string]uint, error) {
+	if byName, ok := cache[categoryID]; ok {
+		return byName, nil
+	}
+	if _, err := s.categoryRepo.GetByID(ctx, categoryID); err != nil {
+		return nil, fmt.Errorf("mapped category %d not found", categoryID)
+	}
+
+	categoryAttrs, err := s.categoryAttrRepo.GetByCategoryID(ctx, categoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get category attributes: %w", err)
+	}
+	byName := make(map[string]uint, len(categoryAttrs))
+	for _, attr := range categoryAttrs {
+		byName[strings.ToLower(attr.AttributeName)] = attr.ID
+	}
+	cache[categoryID] = byName
+	return byName, nil
+}
+
+// loadExport returns the products of an import, downloading export_url on the first run
+func (s *ProductImportService) loadExport(ctx context.Context, imp *ProductImport) ([]ExportedProduct, error) {
+	data, err := s.redisClient.Get(ctx, productImportKeyPrefix+imp.ID+":data").Bytes()
+	if err == nil {
+		var products []ExportedProduct
+		if err := json.Unmarshal(data, &products); err != nil {
+			return nil, fmt.Errorf("failed to decode stored export: %w", err)
+		}
+		return products, nil
+	}
+	if err != redis.Nil {
+		return nil, fmt.Errorf("failed to get stored export: %w", err)
+	}
+	if imp.ExportURL == "" {
+		return nil, errors.New("stored export expired")
+	}
+
+	products, err := s.fetchExport(ctx, imp.ExportURL)
+	if err != nil {
+		return nil, err
+	}
+	if len(products) > s.opts.MaxProducts {
+		return nil, fmt.Errorf("export has %d products, at most %d per import", len(products), s.opts.MaxProducts)
+	}
+	if err := s.saveExport(ctx, imp.ID, products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// fetchExport downloads and decodes a ProductExport document
+func (s *ProductImportService) fetchExport(ctx context.Context, exportURL string) ([]ExportedProduct, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, exportURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid export_url: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download export: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download export: status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImportExportBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export: %w", err)
+	}
+	if len(data) > maxImportExportBytes {
+		return nil, fmt.Errorf("export is larger than %d bytes", maxImportExportBytes)
+	}
+
+	var export ProductExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("invalid export document: %w", err)
+	}
+	if len(export.Products) == 0 {
+		return nil, errors.New("export has no products")
+	}
+	return export.Products, nil
+}
+
+// loadMapping reads the category/attribute mapping file
+func (s *ProductImportService) loadMapping() (*ImportMapping, error) {
+	data, err := os.ReadFile(s.opts.MappingFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read import mapping file: %w", err)
+	}
+
+	var mapping ImportMapping
+	if err := json.Unmarshal(data, &mapping); err != nil {
+		return nil, fmt.Errorf("invalid import mapping file: %w", err)
+	}
+	// Source keys are matched case-insensitively
+	sources := make(map[string]*SourceMapping, len(mapping.Sources))
+	for name, source := range mapping.Sources {
+		sources[strings.ToLower(name)] = source
+	}
+	mapping.Sources = sources
+	return &mapping, nil
+}
+
+// failImport marks an import FAILED; the job itself succeeds (retrying would not help)
+func (s *ProductImportService) failImport(ctx context.Context, imp *ProductImport, cause error) error {
+	finishedAt := time.Now()
+	imp.State = ImportStateFailed
+	imp.Error = cause.Error()
+	imp.FinishedAt = &finishedAt
+
+	s.logger.Warn("product import failed", zap.String("import_id", imp.ID), zap.Error(cause))
+	return s.saveImport(ctx, imp)
+}
+
+func (s *ProductImportService) saveImport(ctx context.Context, imp *ProductImport) error {
+	data, err := json.Marshal(imp)
+	if err != nil {
+		return fmt.Errorf("failed to marshal product import: %w", err)
+	}
+	if err := s.redisClient.Set(ctx, productImportKeyPrefix+imp.ID, data, productImportTTL).Err(); err != nil {
+		return fmt.Errorf("failed to save product import: %w", err)
+	}
+	return nil
+}
+
+func (s *ProductImportService) saveExport(ctx context.Context, id string, products []ExportedProduct) error {
+	data, err := json.Marshal(products)
+	if err != nil {
+		return fmt.Errorf("failed to marshal export: %w", err)
+	}
+	if err := s.redisClient.Set(ctx, productImportKeyPrefix+id+":data", data, productImportTTL).Err(); err != nil {
+		return fmt.Errorf("failed to store export: %w", err)
+	}
+	return nil
+}
+
+// addIssue records an issue until the cap is reached
+func (imp *ProductImport) addIssue(issue ImportIssue) {
+	if len(imp.Issues) < maxImportIssues {
+		imp.Issues = append(imp.Issues, issue)
+	}
+}
+
+// importSKUCode generates a SKU code for an exported item without one
+func importSKUCode(imp *ProductImport, externalID string, index, item int) string {
+	ref := strings.TrimSpace(externalID)
+	if ref == "" {
+		ref = fmt.Sprintf("%d", index+1)
+	}
+	code := fmt.Sprintf("IMP-%d-%s-%d", imp.ShopID, ref, item+1)
+	if len(code) > maxSKUCodeLength {
+		// Keep the unique import prefix and item suffix
+		code = fmt.Sprintf("IMP-%s-%d-%d", imp.ID, index+1, item+1)
+	}
+	return code
+}
+
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func newImportID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"product-service/internal/domain"
+	"time"
+)
+
+// EventProductItemUpdated is published when the price or stock of a SKU changes
+// (topic product.item_updated, keyed by product ID). Order Service evaluates
+// price-drop and back-in-stock subscriptions on it
+const EventProductItemUpdated = "product_item_updated"
+
+// ProductItemChange is the metadata of EventProductItemUpdated
+type ProductItemChange struct {
+	ProductItemID      uint    `json:"product_item_id"`
+	Price              float64 `json:"price"`
+	PreviousPrice      float64 `json:"previous_price"`
+	QtyInStock         int     `json:"qty_in_stock"`
+	PreviousQtyInStock int     `json:"previous_qty_in_stock"`
+	Status             string  `json:"status"`
+}
+
+// productItemEvents publishes SKU price / stock changes in the background
+type productItemEvents struct {
+	publisher domain.EventPublisher
+	async     AsyncRunner
+}
+
+// publishChange emits EventProductItemUpdated if the price or stock of item differs from before
+func (e productItemEvents) publishChange(ctx context.Context, item *domain.ProductItem, previousPrice float64, previousStock int) {
+	if e.publisher == nil || (item.Price == previousPrice && item.QtyInStock == previousStock) {
+		return
+	}
+
+	event := &domain.ProductEvent{
+		EventType: EventProductItemUpdated,
+		ProductID: item.ProductID,
+		Timestamp: time.Now(),
+		Metadata: ProductItemChange{
+			ProductItemID:      item.ID,
+			Price:              item.Price,
+			PreviousPrice:      previousPrice,
+			QtyInStock:         item.QtyInStock,
+			PreviousQtyInStock: previousStock,
+			Status:             item.Status,
+		},
+	}
+	e.async.Submit(ctx, "publish_"+EventProductItemUpdated, func(ctx context.Context) error {
+		return e.publisher.PublishProductEvent(ctx, event)
+	})
+}
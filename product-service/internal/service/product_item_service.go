@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"product-service/internal/domain"
@@ -16,9 +17,25 @@ type ProductItemService struct {
 	variationOptRepo domain.VariationOptionRepository
 	skuConfigRepo    domain.SKUConfigurationRepository
 	productRepo      domain.ProductRepository
+	orders           OpenOrderCounter
+	events           productItemEvents
 	logger           *zap.Logger
 }
 
+// OpenOrderCounter counts unfinished orders containing a SKU (implemented by pkg/order_client)
+type OpenOrderCounter interface {
+	CountOpenOrders(ctx context.Context, productItemID uint) (int64, error)
+}
+
+// ErrProductItemNotFound is returned when a SKU does not exist (or is deleted)
+var ErrProductItemNotFound = domain.NotFound("product item not found")
+
+// ErrProductItemHasOpenOrders is returned when a SKU in unfinished orders is deleted
+var ErrProductItemHasOpenOrders = domain.Conflict("product item is in open orders and cannot be deleted")
+
+// ErrProductItemNotDeleted is returned when restoring a SKU that is not deleted
+var ErrProductItemNotDeleted = domain.Conflict("product item is not deleted")
+
 // NewProductItemService creates a new product item service
 func NewProductItemService(
 	productItemRepo domain.ProductItemRepository,
@@ -26,6 +43,9 @@ func NewProductItemService(
 	variationOptRepo domain.VariationOptionRepository,
 	skuConfigRepo domain.SKUConfigurationRepository,
 	productRepo domain.ProductRepository,
+	orders OpenOrderCounter,
+	eventPublisher domain.EventPublisher,
+	async AsyncRunner,
 	logger *zap.Logger,
 ) *ProductItemService {
 	return &ProductItemService{
@@ -34,6 +54,8 @@ func NewProductItemService(
 		variationOptRepo: variationOptRepo,
 		skuConfigRepo:    skuConfigRepo,
 		productRepo:      productRepo,
+		orders:           orders,
+		events:           productItemEvents{publisher: eventPublisher, async: async},
 		logger:           logger,
 	}
 }
@@ -54,6 +76,7 @@ type UpdateProductItemRequest struct {
 	Price      float64 `json:"price" binding:"omitempty,min=0"`
 	QtyInStock int     `json:"qty_in_stock"`
 	Status     string  `json:"status"`
+	Version    *int    `json:"version,omitempty"` // Version the client last read; stale versions are rejected
 }
 
 // CreateProductItem creates a new product item (SKU) with variation options
@@ -64,25 +87,28 @@ type UpdateProductItemRequest struct {
 // 4. Check duplicate combination (same variation options already exist)
 // 5. Create product item
 // 6. Create SKU configurations (link SKU with variation options)
-func (s *ProductItemService) CreateProductItem(req *CreateProductItemRequest) (*domain.ProductItem, error) {
+func (s *ProductItemService) CreateProductItem(ctx context.Context, req *CreateProductItemRequest) (*domain.ProductItem, error) {
 	// 1. Validate product exists
-	_, err := s.productRepo.GetByID(req.ProductID)
+	_, err := s.productRepo.GetByID(ctx, req.ProductID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("product not found")
+			return nil, domain.NotFound("product not found")
 		}
 		return nil, fmt.Errorf("failed to get product: %w", err)
 	}
 
-	// 2. Check if SKU code already exists
-	existing, err := s.productItemRepo.GetBySKUCode(req.SKUCode)
-	if err == nil && existing != nil {
-		return nil, errors.New("SKU code already exists")
+	// 2. Check if SKU code already exists (discontinued SKUs keep their code)
+	exists, err := s.productItemRepo.ExistsBySKUCode(ctx, req.SKUCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check SKU code: %w", err)
+	}
+	if exists {
+		return nil, domain.Conflict("SKU code already exists")
 	}
 
 	// 3. Validate variation options belong to product's variations
 	if len(req.VariationOptions) > 0 {
-		productVariations, err := s.variationRepo.GetByProductID(req.ProductID)
+		productVariations, err := s.variationRepo.GetByProductID(ctx, req.ProductID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get product variations: %w", err)
 		}
@@ -95,12 +121,12 @@ func (s *ProductItemService) CreateProductItem(req *CreateProductItemRequest) (*
 
 		// Validate each variation option belongs to product's variations
 		for _, optionID := range req.VariationOptions {
-			option, err := s.variationOptRepo.GetByID(optionID)
+			option, err := s.variationOptRepo.GetByID(ctx, optionID)
 			if err != nil {
-				return nil, fmt.Errorf("variation option %d not found", optionID)
+				return nil, domain.NotFound("variation option %d not found", optionID)
 			}
 			if !variationIDs[option.VariationID] {
-				return nil, fmt.Errorf("variation option %d does not belong to product %d", optionID, req.ProductID)
+				return nil, domain.Validation("variation option %d does not belong to product %d", optionID, req.ProductID)
 			}
 		}
 
@@ -118,7 +144,7 @@ func (s *ProductItemService) CreateProductItem(req *CreateProductItemRequest) (*
 		Status:     "ACTIVE",
 	}
 
-	if err := s.productItemRepo.Create(item); err != nil {
+	if err := s.productItemRepo.Create(ctx, item); err != nil {
 		s.logger.Error("failed to create product item", zap.Error(err))
 		return nil, fmt.Errorf("failed to create product item: %w", err)
 	}
@@ -135,9 +161,9 @@ func (s *ProductItemService) CreateProductItem(req *CreateProductItemRequest) (*
 			})
 		}
 
-		if err := s.skuConfigRepo.CreateBatch(configs); err != nil {
+		if err := s.skuConfigRepo.CreateBatch(ctx, configs); err != nil {
 			// Rollback: delete the product item if SKU configuration fails
-			s.productItemRepo.Delete(item.ID)
+			s.productItemRepo.Delete(ctx, item.ID)
 			s.logger.Error("failed to create SKU configurations", zap.Error(err))
 			return nil, fmt.Errorf("failed to create SKU configurations: %w", err)
 		}
@@ -149,15 +175,20 @@ func (s *ProductItemService) CreateProductItem(req *CreateProductItemRequest) (*
 }
 
 // UpdateProductItem updates an existing product item
-func (s *ProductItemService) UpdateProductItem(id uint, req *UpdateProductItemRequest) (*domain.ProductItem, error) {
+func (s *ProductItemService) UpdateProductItem(ctx context.Context, id uint, req *UpdateProductItemRequest) (*domain.ProductItem, error) {
 	// Get existing item
-	item, err := s.productItemRepo.GetByID(id)
+	item, err := s.productItemRepo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("product item not found")
+			return nil, ErrProductItemNotFound
 		}
 		return nil, fmt.Errorf("failed to get product item: %w", err)
 	}
+	if req.Version != nil && *req.Version != item.Version {
+		return nil, domain.ErrVersionConflict
+	}
+
+	previousPrice, previousStock := item.Price, item.QtyInStock
 
 	// Update fields
 	if req.ImageURL != "" {
@@ -172,27 +203,28 @@ func (s *ProductItemService) UpdateProductItem(id uint, req *UpdateProductItemRe
 	if req.Status != "" {
 		// Validate status
 		if req.Status != "ACTIVE" && req.Status != "OUT_OF_STOCK" && req.Status != "DISABLED" {
-			return nil, errors.New("invalid status")
+			return nil, domain.Validation("invalid status")
 		}
 		item.Status = req.Status
 	}
 
-	if err := s.productItemRepo.Update(item); err != nil {
+	if err := s.productItemRepo.Update(ctx, item); err != nil {
 		s.logger.Error("failed to update product item", zap.Error(err))
 		return nil, fmt.Errorf("failed to update product item: %w", err)
 	}
 
 	s.logger.Info("product item updated", zap.Uint("product_item_id", item.ID))
+	s.events.publishChange(ctx, item, previousPrice, previousStock)
 
 	return item, nil
 }
 
 // GetProductItem retrieves a product item by ID
-func (s *ProductItemService) GetProductItem(id uint) (*domain.ProductItem, error) {
-	item, err := s.productItemRepo.GetByID(id)
+func (s *ProductItemService) GetProductItem(ctx context.Context, id uint) (*domain.ProductItem, error) {
+	item, err := s.productItemRepo.GetByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("product item not found")
+			return nil, ErrProductItemNotFound
 		}
 		return nil, fmt.Errorf("failed to get product item: %w", err)
 	}
@@ -200,11 +232,11 @@ func (s *ProductItemService) GetProductItem(id uint) (*domain.ProductItem, error
 }
 
 // GetProductItemBySKU retrieves a product item by SKU code
-func (s *ProductItemService) GetProductItemBySKU(skuCode string) (*domain.ProductItem, error) {
-	item, err := s.productItemRepo.GetBySKUCode(skuCode)
+func (s *ProductItemService) GetProductItemBySKU(ctx context.Context, skuCode string) (*domain.ProductItem, error) {
+	item, err := s.productItemRepo.GetBySKUCode(ctx, skuCode)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("product item not found")
+			return nil, ErrProductItemNotFound
 		}
 		return nil, fmt.Errorf("failed to get product item: %w", err)
 	}
@@ -212,8 +244,8 @@ func (s *ProductItemService) GetProductItemBySKU(skuCode string) (*domain.Produc
 }
 
 // GetProductItems retrieves all product items (SKUs) for a product
-func (s *ProductItemService) GetProductItems(productID uint) ([]*domain.ProductItem, error) {
-	items, err := s.productItemRepo.GetByProductID(productID)
+func (s *ProductItemService) GetProductItems(ctx context.Context, productID uint) ([]*domain.ProductItem, error) {
+	items, err := s.productItemRepo.GetByProductID(ctx, productID)
 	if err != nil {
 		s.logger.Error("failed to get product items", zap.Error(err))
 		return nil, fmt.Errorf("failed to get product items: %w", err)
@@ -235,9 +267,9 @@ type ProductItemWithVariations struct {
 
 // GetProductItemsWithVariations retrieves product items with their variation option IDs
 // This is used for variation selector UI (Shopee-style)
-func (s *ProductItemService) GetProductItemsWithVariations(productID uint) ([]*ProductItemWithVariations, error) {
+func (s *ProductItemService) GetProductItemsWithVariations(ctx context.Context, productID uint) ([]*ProductItemWithVariations, error) {
 	// Get all product items
-	items, err := s.productItemRepo.GetByProductID(productID)
+	items, err := s.productItemRepo.GetByProductID(ctx, productID)
 	if err != nil {
 		return nil, err
 	}
@@ -245,7 +277,7 @@ func (s *ProductItemService) GetProductItemsWithVariations(productID uint) ([]*P
 	result := make([]*ProductItemWithVariations, 0, len(items))
 	for _, item := range items {
 		// Get SKU configurations (variation options)
-		configs, err := s.skuConfigRepo.GetByProductItemID(item.ID)
+		configs, err := s.skuConfigRepo.GetByProductItemID(ctx, item.ID)
 		if err != nil {
 			s.logger.Warn("Failed to get SKU configurations",
 				zap.Uint("product_item_id", item.ID),
@@ -284,31 +316,43 @@ type ProductItemWithProduct struct {
 	QtyInStock int     `json:"qty_in_stock"`
 	Status     string  `json:"status"`
 	Product    *struct {
-		ID     uint   `json:"id"`
-		ShopID uint   `json:"shop_id"`
-		Name   string `json:"name"`
+		ID                  uint   `json:"id"`
+		ShopID              uint   `json:"shop_id"`
+		Name                string `json:"name"`
+		MaxPurchaseQuantity int    `json:"max_purchase_quantity"` // 0 = no limit
+		ProductType         string `json:"product_type"`          // PHYSICAL, DIGITAL
 	} `json:"product"`
+
+	// Resolved variation options of the SKU (e.g. Size L, Màu Đen) for order snapshots
+	Variations []SKUVariation `json:"variations"`
+}
+
+// SKUVariation is one resolved variation option of a SKU
+type SKUVariation struct {
+	Name  string `json:"name"`  // Variation name ("Size", "Màu")
+	Value string `json:"value"` // Option value ("L", "Đen")
 }
 
 // GetProductItemsWithProduct retrieves multiple product items by IDs with product details
 // Used by order-service/cart-service for batch fetching
-func (s *ProductItemService) GetProductItemsWithProduct(ids []uint) ([]*ProductItemWithProduct, error) {
+func (s *ProductItemService) GetProductItemsWithProduct(ctx context.Context, ids []uint) ([]*ProductItemWithProduct, error) {
 	if len(ids) == 0 {
 		return []*ProductItemWithProduct{}, nil
 	}
 
 	result := make([]*ProductItemWithProduct, 0, len(ids))
+	variationNames := make(map[uint]string) // variation_id -> name, shared by the batch
 
 	for _, id := range ids {
 		// Get product item
-		item, err := s.productItemRepo.GetByID(id)
+		item, err := s.productItemRepo.GetByID(ctx, id)
 		if err != nil {
 			s.logger.Warn("product item not found", zap.Uint("id", id), zap.Error(err))
 			continue // Skip missing items instead of failing entire batch
 		}
 
 		// Get product info
-		product, err := s.productRepo.GetByID(item.ProductID)
+		product, err := s.productRepo.GetByID(ctx, item.ProductID)
 		if err != nil {
 			s.logger.Warn("product not found for item", zap.Uint("product_id", item.ProductID), zap.Error(err))
 			continue
@@ -324,14 +368,19 @@ func (s *ProductItemService) GetProductItemsWithProduct(ids []uint) ([]*ProductI
 			QtyInStock: item.QtyInStock,
 			Status:     item.Status,
 			Product: &struct {
-				ID     uint   `json:"id"`
-				ShopID uint   `json:"shop_id"`
-				Name   string `json:"name"`
+				ID                  uint   `json:"id"`
+				ShopID              uint   `json:"shop_id"`
+				Name                string `json:"name"`
+				MaxPurchaseQuantity int    `json:"max_purchase_quantity"` // 0 = no limit
+				ProductType         string `json:"product_type"`          // PHYSICAL, DIGITAL
 			}{
-				ID:     product.ID,
-				ShopID: product.ShopID,
-				Name:   product.Name,
+				ID:                  product.ID,
+				ShopID:              product.ShopID,
+				Name:                product.Name,
+				MaxPurchaseQuantity: product.MaxPurchaseQuantity,
+				ProductType:         product.ProductType,
 			},
+			Variations: s.resolveVariations(ctx, item.ID, variationNames),
 		}
 
 		result = append(result, itemWithProduct)
@@ -340,21 +389,92 @@ func (s *ProductItemService) GetProductItemsWithProduct(ids []uint) ([]*ProductI
 	return result, nil
 }
 
-// DeleteProductItem deletes a product item and its SKU configurations
-func (s *ProductItemService) DeleteProductItem(id uint) error {
-	// Delete SKU configurations first (foreign key constraint)
-	if err := s.skuConfigRepo.DeleteByProductItemID(id); err != nil {
-		s.logger.Error("failed to delete SKU configurations", zap.Error(err))
-		return fmt.Errorf("failed to delete SKU configurations: %w", err)
+// resolveVariations returns the variation name/value pairs of a SKU
+// Lookup failures only drop the affected option (labels are informational)
+func (s *ProductItemService) resolveVariations(ctx context.Context, productItemID uint, names map[uint]string) []SKUVariation {
+	configs, err := s.skuConfigRepo.GetByProductItemID(ctx, productItemID)
+	if err != nil {
+		s.logger.Warn("Failed to get SKU configurations",
+			zap.Uint("product_item_id", productItemID),
+			zap.Error(err))
+		return []SKUVariation{}
 	}
 
-	// Delete product item
-	if err := s.productItemRepo.Delete(id); err != nil {
+	variations := make([]SKUVariation, 0, len(configs))
+	for _, config := range configs {
+		option, err := s.variationOptRepo.GetByID(ctx, config.VariationOptionID)
+		if err != nil {
+			s.logger.Warn("variation option not found", zap.Uint("variation_option_id", config.VariationOptionID), zap.Error(err))
+			continue
+		}
+
+		name, ok := names[option.VariationID]
+		if !ok {
+			if variation, err := s.variationRepo.GetByID(ctx, option.VariationID); err == nil {
+				name = variation.Name
+			}
+			names[option.VariationID] = name
+		}
+
+		variations = append(variations, SKUVariation{Name: name, Value: option.Value})
+	}
+	return variations
+}
+
+// DeleteProductItem soft-deletes a SKU (status DISCONTINUED)
+// Blocked while unfinished orders contain the SKU; SKU configurations are kept so
+// the SKU can be restored with its variation options
+func (s *ProductItemService) DeleteProductItem(ctx context.Context, id uint) error {
+	if _, err := s.productItemRepo.GetByID(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrProductItemNotFound
+		}
+		return fmt.Errorf("failed to get product item: %w", err)
+	}
+
+	// Fail closed: if open orders cannot be checked, do not delete
+	openOrders, err := s.orders.CountOpenOrders(ctx, id)
+	if err != nil {
+		s.logger.Error("failed to check open orders", zap.Uint("product_item_id", id), zap.Error(err))
+		return fmt.Errorf("failed to check open orders: %w", err)
+	}
+	if openOrders > 0 {
+		s.logger.Info("product item delete blocked by open orders",
+			zap.Uint("product_item_id", id),
+			zap.Int64("open_orders", openOrders),
+		)
+		return ErrProductItemHasOpenOrders
+	}
+
+	if err := s.productItemRepo.Delete(ctx, id); err != nil {
 		s.logger.Error("failed to delete product item", zap.Error(err))
 		return fmt.Errorf("failed to delete product item: %w", err)
 	}
 
-	s.logger.Info("product item deleted", zap.Uint("product_item_id", id))
+	s.logger.Info("product item discontinued", zap.Uint("product_item_id", id))
 
 	return nil
 }
+
+// RestoreProductItem undoes a soft delete
+func (s *ProductItemService) RestoreProductItem(ctx context.Context, id uint) (*domain.ProductItem, error) {
+	item, err := s.productItemRepo.GetByIDWithDeleted(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductItemNotFound
+		}
+		return nil, fmt.Errorf("failed to get product item: %w", err)
+	}
+	if !item.DeletedAt.Valid {
+		return nil, ErrProductItemNotDeleted
+	}
+
+	if err := s.productItemRepo.Restore(ctx, id); err != nil {
+		s.logger.Error("failed to restore product item", zap.Error(err))
+		return nil, fmt.Errorf("failed to restore product item: %w", err)
+	}
+
+	s.logger.Info("product item restored", zap.Uint("product_item_id", id))
+
+	return s.GetProductItem(ctx, id)
+}
@@ -2,34 +2,60 @@ package service
 
 import (
 	"context"
+	"crypto/sha1"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"log"
-	"os"
+	"math"
 	"product-service/internal/domain"
+	"product-service/pkg/slug"
+	"sort"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 // ProductService contains the business logic for product operations
 // This is the service layer - it orchestrates between repositories
 // Following Clean Architecture: business logic is independent of infrastructure
 type ProductService struct {
-	productRepo    domain.ProductRepository
-	searchRepo     domain.ProductSearchRepository
-	cacheRepo      CacheRepository
-	categoryRepo   domain.CategoryRepository
-	eventPublisher domain.EventPublisher
-	logger         *zap.Logger
+	productRepo     domain.ProductRepository
+	slugHistoryRepo domain.ProductSlugHistoryRepository
+	searchRepo      domain.ProductSearchRepository
+	cacheRepo       CacheRepository
+	categoryRepo    domain.CategoryRepository
+	outbox          domain.OutboxRepository
+	tx              domain.Transactor
+	flags           FeatureFlagChecker
+	shops           ShopLocator
+	async           AsyncRunner
+	logger          *zap.Logger
 }
 
+// FeatureFlagChecker evaluates feature flags (implemented by pkg/featureflag)
+type FeatureFlagChecker interface {
+	IsEnabled(key string, userID, shopID uint) bool
+}
+
+// AsyncRunner runs request side effects (cache, index, publish) on a bounded
+// worker pool with retries (implemented by pkg/taskqueue)
+type AsyncRunner interface {
+	Submit(ctx context.Context, name string, fn func(ctx context.Context) error)
+}
+
+// flagNewRanking enables popularity-based ordering of product listings (gradual rollout)
+const flagNewRanking = "new_search_ranking"
+
 // CacheRepository defines cache operations (abstraction for Redis)
 // This interface allows us to swap Redis for other caching solutions if needed
 type CacheRepository interface {
 	SetProduct(ctx context.Context, product *domain.Product, ttl time.Duration) error
 	GetProduct(ctx context.Context, id uint) (*domain.Product, error)
 	DeleteProduct(ctx context.Context, id uint) error
+	SetCount(ctx context.Context, key string, count int64, ttl time.Duration) error
+	GetCount(ctx context.Context, key string) (int64, bool, error)
 	AcquireLock(ctx context.Context, lockKey string, ttl time.Duration) (bool, error)
 	ReleaseLock(ctx context.Context, lockKey string) error
 }
@@ -38,19 +64,29 @@ type CacheRepository interface {
 // Dependency injection: we inject all repositories and external services
 func NewProductService(
 	productRepo domain.ProductRepository,
+	slugHistoryRepo domain.ProductSlugHistoryRepository,
 	searchRepo domain.ProductSearchRepository,
 	cacheRepo CacheRepository,
 	categoryRepo domain.CategoryRepository,
-	eventPublisher domain.EventPublisher,
+	outbox domain.OutboxRepository,
+	tx domain.Transactor,
+	flags FeatureFlagChecker,
+	shops ShopLocator,
+	async AsyncRunner,
 	logger *zap.Logger,
 ) *ProductService {
 	return &ProductService{
-		productRepo:    productRepo,
-		searchRepo:     searchRepo,
-		cacheRepo:      cacheRepo,
-		categoryRepo:   categoryRepo,
-		eventPublisher: eventPublisher,
-		logger:         logger,
+		productRepo:     productRepo,
+		slugHistoryRepo: slugHistoryRepo,
+		searchRepo:      searchRepo,
+		cacheRepo:       cacheRepo,
+		categoryRepo:    categoryRepo,
+		outbox:          outbox,
+		tx:              tx,
+		flags:           flags,
+		shops:           shops,
+		async:           async,
+		logger:          logger,
 	}
 }
 
@@ -59,107 +95,46 @@ func NewProductService(
 // 1. Save to PostgreSQL (source of truth)
 // 2. Update Redis cache (fast reads)
 // 3. Index to Elasticsearch (search capability)
-// 4. Publish event to Kafka (event-driven architecture)
+// 4. Publish event to Kafka (event-driven architecture) via the transactional outbox
 func (s *ProductService) CreateProduct(ctx context.Context, product *domain.Product) error {
 	// Business logic validation
 	if product.Name == "" {
-		return errors.New("name is required")
+		return domain.Validation("name is required")
 	}
 	if product.BasePrice < 0 {
-		return errors.New("base price cannot be negative")
+		return domain.Validation("base price cannot be negative")
 	}
 
-	// 1. Save to PostgreSQL (source of truth)
-	fmt.Fprintf(os.Stderr, "🟢🟢🟢 Service: About to create product in DB - Name: %s\n", product.Name)
-	log.Printf("🟢 Service: About to create product in DB - Name: %s", product.Name)
-	if err := s.productRepo.Create(product); err != nil {
-		fmt.Fprintf(os.Stderr, "❌❌❌ Service: Failed to create product in DB: %v\n", err)
-		log.Printf("❌ Service: Failed to create product in DB: %v", err)
-		s.logger.Error("failed to create product in database", zap.Error(err))
-		return fmt.Errorf("failed to create product: %w", err)
+	// Generate unique slug (from provided slug or from name)
+	source := product.Slug
+	if source == "" {
+		source = product.Name
 	}
+	uniqueSlug, err := s.generateUniqueSlug(ctx, source, 0)
+	if err != nil {
+		return err
+	}
+	product.Slug = uniqueSlug
 
-	fmt.Fprintf(os.Stderr, "✅✅✅ Service: Product created in DB - ID: %d, Name: %s\n", product.ID, product.Name)
-	log.Printf("✅ Service: Product created in DB - ID: %d, Name: %s", product.ID, product.Name)
-	s.logger.Info("product created in database", zap.Uint("product_id", product.ID))
-	_ = s.logger.Sync()
-
-	// 2. Update Redis cache (async - don't block on cache)
-	go func() {
-		cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-
-		if err := s.cacheRepo.SetProduct(cacheCtx, product, 1*time.Hour); err != nil {
-			s.logger.Warn("failed to cache product", zap.Error(err))
-		}
-	}()
-
-	// 3. Index to Elasticsearch (async - search is eventually consistent)
-	go func() {
-		if err := s.searchRepo.IndexProduct(product); err != nil {
-			s.logger.Warn("failed to index product in elasticsearch", zap.Error(err))
-		} else {
-			s.logger.Info("product indexed in elasticsearch", zap.Uint("product_id", product.ID))
-		}
-	}()
-
-	// 4. Publish event to Kafka (async - event-driven communication)
-	// CRITICAL: Log BEFORE starting goroutine to confirm we reach this point
-	s.logger.Info("🔵🔵🔵 ABOUT TO START EVENT PUBLISHING GOROUTINE",
-		zap.Uint("product_id", product.ID),
-		zap.String("product_name", product.Name),
-		zap.Bool("eventPublisher_nil", s.eventPublisher == nil),
-	)
-	_ = s.logger.Sync()
-
-	go func() {
-		// CRITICAL: Use Zap logger with Sync to ensure logs are flushed immediately
-		s.logger.Info("🚀🚀🚀 EVENT PUBLISHING GOROUTINE CALLED!",
-			zap.Uint("product_id", product.ID),
-			zap.String("product_name", product.Name),
-		)
-		_ = s.logger.Sync() // Force flush logs immediately
-
-		// Check if eventPublisher is nil
-		if s.eventPublisher == nil {
-			s.logger.Error("❌❌❌ Event publisher is nil - cannot publish event",
-				zap.Uint("product_id", product.ID),
-				zap.String("product_name", product.Name),
-			)
-			_ = s.logger.Sync()
-			return
-		}
+	// New products have no reviews yet
+	product.RatingAvg = 0
+	product.RatingCount = 0
 
-		event := &domain.ProductEvent{
-			EventType:   "product_created",
-			ProductID:   product.ID,
-			ProductData: product,
-			Timestamp:   time.Now(),
+	// 1. Save to PostgreSQL (source of truth) with its event
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.productRepo.Create(ctx, product); err != nil {
+			return err
 		}
+		return s.addEvent(ctx, product, "product_created")
+	})
+	if err != nil {
+		s.logger.Error("failed to create product in database", zap.Error(err))
+		return fmt.Errorf("failed to create product: %w", err)
+	}
+	s.logger.Info("product created in database", zap.Uint("product_id", product.ID))
 
-		s.logger.Info("📤 Publishing product event to Kafka",
-			zap.Uint("product_id", product.ID),
-			zap.String("event_type", event.EventType),
-			zap.String("product_name", product.Name),
-		)
-		_ = s.logger.Sync()
-
-		if err := s.eventPublisher.PublishProductEvent(event); err != nil {
-			s.logger.Error("❌❌❌ Failed to publish product event to Kafka",
-				zap.Uint("product_id", event.ProductID),
-				zap.String("event_type", event.EventType),
-				zap.Error(err),
-			)
-			_ = s.logger.Sync()
-		} else {
-			s.logger.Info("✅✅✅ Product event published to Kafka successfully",
-				zap.Uint("product_id", event.ProductID),
-				zap.String("event_type", event.EventType),
-				zap.String("product_name", product.Name),
-			)
-			_ = s.logger.Sync()
-		}
-	}()
+	// 2-3. Cache and index in the background (bounded worker pool with retries)
+	s.syncSideEffects(ctx, product)
 
 	return nil
 }
@@ -167,54 +142,162 @@ func (s *ProductService) CreateProduct(ctx context.Context, product *domain.Prod
 // UpdateProduct updates an existing product
 func (s *ProductService) UpdateProduct(ctx context.Context, product *domain.Product) error {
 	// Validate product exists
-	existing, err := s.productRepo.GetByID(product.ID)
+	existing, err := s.productRepo.GetByID(ctx, product.ID)
 	if err != nil {
-		return errors.New("product not found")
+		return domain.NotFound("product not found")
 	}
 
-	// Business logic: preserve created_at
+	// Business logic: preserve created_at and the rating snapshot (owned by the review service)
 	product.CreatedAt = existing.CreatedAt
+	product.RatingAvg = existing.RatingAvg
+	product.RatingCount = existing.RatingCount
+
+	// Optimistic locking: version 0 means the caller did not send one (only races
+	// with this write are caught); otherwise it must match the stored version
+	if product.Version == 0 {
+		product.Version = existing.Version
+	}
+	if product.Version != existing.Version {
+		return domain.ErrVersionConflict
+	}
+
+	// Slug handling:
+	// - explicit slug (different from current) -> normalize + make unique
+	// - name changed without explicit slug -> regenerate from name
+	// - otherwise keep current slug
+	switch {
+	case product.Slug != "" && product.Slug != existing.Slug:
+		newSlug, err := s.generateUniqueSlug(ctx, product.Slug, product.ID)
+		if err != nil {
+			return err
+		}
+		product.Slug = newSlug
+	case product.Name != existing.Name || existing.Slug == "":
+		newSlug, err := s.generateUniqueSlug(ctx, product.Name, product.ID)
+		if err != nil {
+			return err
+		}
+		product.Slug = newSlug
+	default:
+		product.Slug = existing.Slug
+	}
 
-	// 1. Update in PostgreSQL
-	if err := s.productRepo.Update(product); err != nil {
+	// 1. Update in PostgreSQL with its event
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.productRepo.Update(ctx, product); err != nil {
+			return err
+		}
+		return s.addEvent(ctx, product, "product_updated")
+	})
+	if err != nil {
 		s.logger.Error("failed to update product in database", zap.Error(err))
 		return fmt.Errorf("failed to update product: %w", err)
 	}
 
+	// Keep old slug for redirects
+	if existing.Slug != "" && existing.Slug != product.Slug {
+		s.recordSlugChange(ctx, product.ID, existing.Slug, product.Slug)
+	}
+
 	s.logger.Info("product updated in database", zap.Uint("product_id", product.ID))
 
-	// 2. Update cache
-	go func() {
-		cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
+	// 2-3. Update cache and search index
+	s.syncSideEffects(ctx, product)
 
-		if err := s.cacheRepo.SetProduct(cacheCtx, product, 1*time.Hour); err != nil {
-			s.logger.Warn("failed to update product cache", zap.Error(err))
-		}
-	}()
+	return nil
+}
 
-	// 3. Update Elasticsearch index
-	go func() {
-		if err := s.searchRepo.IndexProduct(product); err != nil {
-			s.logger.Warn("failed to update product in elasticsearch", zap.Error(err))
-		}
-	}()
-
-	// 4. Publish update event
-	go func() {
-		event := &domain.ProductEvent{
-			EventType:   "product_updated",
-			ProductID:   product.ID,
-			ProductData: product,
-			Timestamp:   time.Now(),
+// Rating snapshot errors
+var (
+	ErrInvalidRating         = domain.Validation("rating average must be between 1 and 5 (0 without ratings) and count not negative")
+	ErrRatingProductNotFound = domain.NotFound("product not found")
+)
+
+// UpdateRatingSnapshot stores the rating aggregate of a product's published reviews,
+// computed by ReviewService, and propagates it to cache, search and consumers
+func (s *ProductService) UpdateRatingSnapshot(ctx context.Context, id uint, avg float64, count int) (*domain.Product, error) {
+	if count < 0 || avg < 0 || avg > 5 || (count == 0 && avg != 0) || (count > 0 && avg < 1) {
+		return nil, ErrInvalidRating
+	}
+	avg = math.Round(avg*100) / 100
+
+	var product *domain.Product
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.productRepo.UpdateRating(ctx, id, avg, count); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRatingProductNotFound
+			}
+			return fmt.Errorf("failed to update product rating: %w", err)
 		}
 
-		if err := s.eventPublisher.PublishProductEvent(event); err != nil {
-			s.logger.Warn("failed to publish product update event", zap.Error(err))
+		var err error
+		product, err = s.productRepo.GetByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get product: %w", err)
 		}
-	}()
+		return s.addEvent(ctx, product, "product_updated")
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	return nil
+	s.logger.Info("product rating updated",
+		zap.Uint("product_id", id),
+		zap.Float64("rating_avg", avg),
+		zap.Int("rating_count", count),
+	)
+
+	s.syncSideEffects(ctx, product)
+
+	return product, nil
+}
+
+// addEvent writes the event of a product change to the outbox, in the transaction of ctx.
+// The outbox relay publishes it to Kafka once committed
+func (s *ProductService) addEvent(ctx context.Context, product *domain.Product, eventType string) error {
+	event, err := domain.NewOutboxEvent(&domain.ProductEvent{
+		EventType:   eventType,
+		ProductID:   product.ID,
+		ProductData: product,
+		Timestamp:   time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	return s.outbox.Add(ctx, event)
+}
+
+// syncSideEffects refreshes the cache and search index for a saved product.
+// Failures are retried and logged by the pool; search is eventually consistent
+func (s *ProductService) syncSideEffects(ctx context.Context, product *domain.Product) {
+	s.async.Submit(ctx, "cache_product", func(ctx context.Context) error {
+		return s.cacheRepo.SetProduct(ctx, product, 1*time.Hour)
+	})
+
+	// Search documents and events carry the shop location, the cached product does not
+	s.async.Submit(ctx, "index_product", func(ctx context.Context) error {
+		return s.searchRepo.IndexProduct(ctx, withShopLocation(ctx, s.shops, s.logger, product))
+	})
+}
+
+// withShopLocation returns a copy of the product with the ships-from location of its shop.
+// The product is returned as is when the shop cannot be looked up (search then misses the
+// location until the next update)
+func withShopLocation(ctx context.Context, shops ShopLocator, logger *zap.Logger, product *domain.Product) *domain.Product {
+	location, err := shops.ShopLocation(ctx, product.ShopID)
+	if err != nil {
+		logger.Warn("failed to get shop location, indexing product without it",
+			zap.Uint("product_id", product.ID),
+			zap.Uint("shop_id", product.ShopID),
+			zap.Error(err),
+		)
+		return product
+	}
+
+	document := *product
+	document.ShipsFromProvince = location.Province
+	document.ShopLocation = location.Point
+	return &document
 }
 
 // GetProduct retrieves a product by ID with cache-first strategy
@@ -228,27 +311,163 @@ func (s *ProductService) GetProduct(ctx context.Context, id uint) (*domain.Produ
 	}
 
 	// 2. Cache miss - get from database (slow path)
-	product, err = s.productRepo.GetByID(id)
+	product, err = s.productRepo.GetByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("product not found: %w", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NotFound("product not found")
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
 	}
 
 	// 3. Populate cache for next time (async)
-	go func() {
-		cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
+	s.async.Submit(ctx, "cache_product", func(ctx context.Context) error {
+		return s.cacheRepo.SetProduct(ctx, product, 1*time.Hour)
+	})
+
+	return product, nil
+}
+
+// MaxBatchProducts bounds the IDs of one batch lookup
+const MaxBatchProducts = 100
+
+// ErrBatchTooLarge is returned when a batch lookup asks for more than MaxBatchProducts
+var ErrBatchTooLarge = domain.Validation("at most %d products per batch", MaxBatchProducts)
+
+// GetProductsByIDs retrieves several products in the order of ids
+// Unknown IDs are skipped; duplicates are returned once
+func (s *ProductService) GetProductsByIDs(ctx context.Context, ids []uint) ([]*domain.Product, error) {
+	if len(ids) > MaxBatchProducts {
+		return nil, ErrBatchTooLarge
+	}
+
+	found, err := s.productRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+	byID := make(map[uint]*domain.Product, len(found))
+	for _, p := range found {
+		byID[p.ID] = p
+	}
 
-		if err := s.cacheRepo.SetProduct(cacheCtx, product, 1*time.Hour); err != nil {
-			s.logger.Warn("failed to cache product", zap.Error(err))
+	products := make([]*domain.Product, 0, len(found))
+	for _, id := range ids {
+		if p, ok := byID[id]; ok {
+			products = append(products, p)
+			delete(byID, id)
 		}
-	}()
+	}
+	return products, nil
+}
 
-	return product, nil
+// GetProductBySlug retrieves a product by slug
+// Returns redirected = true when the slug is an old one (product was renamed),
+// so the caller can redirect to the current slug
+func (s *ProductService) GetProductBySlug(ctx context.Context, slugValue string) (*domain.Product, bool, error) {
+	product, err := s.productRepo.GetBySlug(ctx, slugValue)
+	if err == nil {
+		return product, false, nil
+	}
+
+	// Not a current slug - look up slug history
+	history, err := s.slugHistoryRepo.GetBySlug(ctx, slugValue)
+	if err != nil {
+		return nil, false, domain.NotFound("product not found")
+	}
+
+	product, err = s.GetProduct(ctx, history.ProductID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return product, true, nil
+}
+
+// BackfillSlugs generates slugs for products created before slugs existed
+// Called once at startup - products that already have a slug are skipped
+func (s *ProductService) BackfillSlugs(ctx context.Context) (int, error) {
+	products, err := s.productRepo.GetAll(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get products: %w", err)
+	}
+
+	updated := 0
+	for _, product := range products {
+		if product.Slug != "" {
+			continue
+		}
+
+		newSlug, err := s.generateUniqueSlug(ctx, product.Name, product.ID)
+		if err != nil {
+			return updated, err
+		}
+		product.Slug = newSlug
+
+		if err := s.productRepo.Update(ctx, product); err != nil {
+			return updated, fmt.Errorf("failed to update product slug: %w", err)
+		}
+		updated++
+	}
+
+	if updated > 0 {
+		s.logger.Info("product slugs backfilled", zap.Int("count", updated))
+	}
+
+	return updated, nil
+}
+
+// generateUniqueSlug builds a slug from source and appends -2, -3, ... until it is unique
+// A slug is taken if another product uses it, or it is an old slug of another product
+func (s *ProductService) generateUniqueSlug(ctx context.Context, source string, productID uint) (string, error) {
+	base := slug.Make(source)
+	if base == "" {
+		base = "san-pham" // Name had no usable characters
+	}
+
+	for n := 1; n <= 1000; n++ {
+		candidate := slug.WithSuffix(base, n)
+
+		exists, err := s.productRepo.ExistsBySlug(ctx, candidate, productID)
+		if err != nil {
+			return "", fmt.Errorf("failed to check slug: %w", err)
+		}
+		if exists {
+			continue
+		}
+
+		history, err := s.slugHistoryRepo.GetBySlug(ctx, candidate)
+		if err == nil && history != nil && history.ProductID != productID {
+			continue
+		}
+
+		return candidate, nil
+	}
+
+	return "", errors.New("could not generate unique slug")
+}
+
+// recordSlugChange stores the old slug in history so old URLs keep working
+// Failures are logged only - product update already succeeded
+func (s *ProductService) recordSlugChange(ctx context.Context, productID uint, oldSlug, newSlug string) {
+	// Product reclaimed one of its own old slugs - it is current again
+	if err := s.slugHistoryRepo.DeleteBySlug(ctx, newSlug); err != nil {
+		s.logger.Warn("failed to clean slug history", zap.String("slug", newSlug), zap.Error(err))
+	}
+
+	if err := s.slugHistoryRepo.Create(ctx, &domain.ProductSlugHistory{
+		ProductID: productID,
+		Slug:      oldSlug,
+	}); err != nil {
+		s.logger.Warn("failed to record slug history",
+			zap.Uint("product_id", productID),
+			zap.String("old_slug", oldSlug),
+			zap.Error(err),
+		)
+	}
 }
 
 // GetAllProducts retrieves all products
 func (s *ProductService) GetAllProducts(ctx context.Context) ([]*domain.Product, error) {
-	products, err := s.productRepo.GetAll()
+	products, err := s.productRepo.GetAll(ctx)
 	if err != nil {
 		s.logger.Error("failed to get all products", zap.Error(err))
 		return nil, fmt.Errorf("failed to get all products: %w", err)
@@ -258,7 +477,8 @@ func (s *ProductService) GetAllProducts(ctx context.Context) ([]*domain.Product,
 }
 
 // ListProducts retrieves products with pagination and filters
-func (s *ProductService) ListProducts(ctx context.Context, filters map[string]interface{}, page, limit int) ([]*domain.Product, int64, error) {
+// Listings select only card columns; callers can add preloads (WithCategory, WithItems)
+func (s *ProductService) ListProducts(ctx context.Context, filters map[string]interface{}, page, limit int, opts ...domain.ProductListOption) ([]*domain.Product, int64, error) {
 	// Set defaults
 	if page < 1 {
 		page = 1
@@ -270,7 +490,16 @@ func (s *ProductService) ListProducts(ctx context.Context, filters map[string]in
 		limit = 100 // Max limit
 	}
 
-	products, total, err := s.productRepo.ListProducts(filters, page, limit)
+	// New ranking is rolled out per user via feature flag
+	userID, _ := filters["user_id"].(uint)
+	if s.flags.IsEnabled(flagNewRanking, userID, 0) {
+		filters["ranking"] = "popular"
+	}
+
+	opts = append([]domain.ProductListOption{domain.WithListingColumns()}, opts...)
+	products, total, err := s.listWithCachedCount(ctx, listingCountKey("list", filters), opts, func(opts ...domain.ProductListOption) ([]*domain.Product, int64, error) {
+		return s.productRepo.ListProducts(ctx, filters, page, limit, opts...)
+	})
 	if err != nil {
 		s.logger.Error("failed to list products", zap.Error(err))
 		return nil, 0, fmt.Errorf("failed to list products: %w", err)
@@ -279,9 +508,67 @@ func (s *ProductService) ListProducts(ctx context.Context, filters map[string]in
 	return products, total, nil
 }
 
+// listingCountTTL bounds how stale the total of a listing can be: COUNT(*) over large
+// categories dominates listing latency, and an approximate total is fine for pagination
+const listingCountTTL = 1 * time.Minute
+
+// listingCountKey builds the cache key of a listing total from its normalized filters
+// Filters that do not change the total (ordering, user) are left out
+func listingCountKey(scope string, filters map[string]interface{}) string {
+	keys := make([]string, 0, len(filters))
+	for k := range filters {
+		if k == "ranking" || k == "user_id" {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, k := range keys {
+		v := fmt.Sprint(filters[k])
+		if k == "search" {
+			v = strings.ToLower(strings.TrimSpace(v)) // matched with ILIKE
+		}
+		fmt.Fprintf(&b, "%s=%s;", k, v)
+	}
+	sum := sha1.Sum([]byte(b.String()))
+	return fmt.Sprintf("product_count:%s:%s", scope, hex.EncodeToString(sum[:]))
+}
+
+// listWithCachedCount runs a paginated listing, reusing the cached total of its filters so
+// only the page query hits Postgres; a fresh total is cached in the background
+func (s *ProductService) listWithCachedCount(
+	ctx context.Context,
+	key string,
+	opts []domain.ProductListOption,
+	list func(opts ...domain.ProductListOption) ([]*domain.Product, int64, error),
+) ([]*domain.Product, int64, error) {
+	cached, found, err := s.cacheRepo.GetCount(ctx, key)
+	if err != nil {
+		s.logger.Warn("failed to get listing count from cache", zap.Error(err))
+	}
+	if found {
+		opts = append(opts, domain.WithoutCount())
+	}
+
+	products, total, err := list(opts...)
+	if err != nil {
+		return nil, 0, err
+	}
+	if found {
+		return products, cached, nil
+	}
+
+	s.async.Submit(ctx, "cache_listing_count", func(ctx context.Context) error {
+		return s.cacheRepo.SetCount(ctx, key, total, listingCountTTL)
+	})
+	return products, total, nil
+}
+
 // GetProductsByCategory retrieves products by category ID with pagination
 // If category is a parent (has children), it will fetch products from all child categories too
-func (s *ProductService) GetProductsByCategory(ctx context.Context, categoryID uint, page, limit int) ([]*domain.Product, int64, error) {
+func (s *ProductService) GetProductsByCategory(ctx context.Context, categoryID uint, page, limit int, opts ...domain.ProductListOption) ([]*domain.Product, int64, error) {
 	// Set defaults
 	if page < 1 {
 		page = 1
@@ -294,12 +581,51 @@ func (s *ProductService) GetProductsByCategory(ctx context.Context, categoryID u
 	}
 
 	// Build category IDs array (include category and its children recursively)
+	categoryIDs := s.categoryTreeIDs(ctx, categoryID)
+
+	s.logger.Info("fetching products for category tree",
+		zap.Uint("root_category_id", categoryID),
+		zap.Int("total_categories", len(categoryIDs)),
+		zap.Uints("category_ids", categoryIDs))
+
+	opts = append([]domain.ProductListOption{domain.WithListingColumns()}, opts...)
+	countKey := listingCountKey("category", map[string]interface{}{"category_id": categoryID})
+	products, total, err := s.listWithCachedCount(ctx, countKey, opts, func(opts ...domain.ProductListOption) ([]*domain.Product, int64, error) {
+		return s.productRepo.GetProductsByCategoryIDs(ctx, categoryIDs, page, limit, opts...)
+	})
+	if err != nil {
+		s.logger.Error("failed to get products by category", zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to get products by category: %w", err)
+	}
+
+	return products, total, nil
+}
+
+// ShopIDsByCategory returns the IDs of the shops selling an active product in the category
+// or one of its subcategories (the sellers of a category, e.g. for admin announcements)
+func (s *ProductService) ShopIDsByCategory(ctx context.Context, categoryID uint) ([]uint, error) {
+	if _, err := s.categoryRepo.GetByID(ctx, categoryID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.NotFound("category not found")
+		}
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+
+	shopIDs, err := s.productRepo.ShopIDsByCategoryIDs(ctx, s.categoryTreeIDs(ctx, categoryID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shops by category: %w", err)
+	}
+	return shopIDs, nil
+}
+
+// categoryTreeIDs returns the category ID followed by the IDs of all its descendants
+func (s *ProductService) categoryTreeIDs(ctx context.Context, categoryID uint) []uint {
 	categoryIDs := []uint{categoryID}
 
 	// Recursive helper to get all descendants
 	var getAllDescendants func(parentID uint)
 	getAllDescendants = func(parentID uint) {
-		children, err := s.categoryRepo.GetChildren(parentID)
+		children, err := s.categoryRepo.GetChildren(ctx, parentID)
 		if err == nil && len(children) > 0 {
 			s.logger.Debug("found children for category",
 				zap.Uint("parent_id", parentID),
@@ -314,24 +640,12 @@ func (s *ProductService) GetProductsByCategory(ctx context.Context, categoryID u
 
 	// Get all descendants of this category
 	getAllDescendants(categoryID)
-
-	s.logger.Info("fetching products for category tree",
-		zap.Uint("root_category_id", categoryID),
-		zap.Int("total_categories", len(categoryIDs)),
-		zap.Uints("category_ids", categoryIDs))
-
-	products, total, err := s.productRepo.GetProductsByCategoryIDs(categoryIDs, page, limit)
-	if err != nil {
-		s.logger.Error("failed to get products by category", zap.Error(err))
-		return nil, 0, fmt.Errorf("failed to get products by category: %w", err)
-	}
-
-	return products, total, nil
+	return categoryIDs
 }
 
 // SearchProducts searches products using Elasticsearch
 func (s *ProductService) SearchProducts(ctx context.Context, query string, filters map[string]interface{}) ([]*domain.Product, error) {
-	products, err := s.searchRepo.SearchProducts(query, filters)
+	products, err := s.searchRepo.SearchProducts(ctx, query, filters)
 	if err != nil {
 		s.logger.Error("failed to search products", zap.Error(err))
 		return nil, fmt.Errorf("failed to search products: %w", err)
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-service/config"
+	"product-service/internal/domain"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Seller quotas live in Redis: the tier assignments and the limit overrides set by
+// admins (on top of config quota.tiers) and one counter per seller, operation and window
+const (
+	quotaSellerTiersKey = "quota:seller_tiers"     // hash seller -> tier
+	quotaTierLimitsKey  = "quota:tier_limits:%s"   // hash "<operation>:hourly|daily" -> limit
+	quotaHourlyCountKey = "quota:count:%s:%s:h:%d" // operation, seller, hour start (unix)
+	quotaDailyCountKey  = "quota:count:%s:%s:d:%d" // operation, seller, day start (unix)
+	quotaWindowHour     = time.Hour
+	quotaCounterGrace   = time.Minute // counters outlive their window a little
+)
+
+// sellerPattern matches the seller keys admins address: shop:<id> or user:<id>
+var sellerPattern = regexp.MustCompile(`^(shop|user):[0-9]+$`)
+
+// consumeQuotaScript counts one request unless either window is used up:
+// KEYS[1] hourly counter, KEYS[2] daily counter
+// ARGV[1] hourly limit, ARGV[2] daily limit (0 = unlimited), ARGV[3] hourly TTL, ARGV[4] daily TTL (seconds)
+// Returns {allowed (0/1), hourly used, daily used}
+var consumeQuotaScript = redis.NewScript(`
+local hourly = tonumber(redis.call('GET', KEYS[1]) or '0')
+local daily = tonumber(redis.call('GET', KEYS[2]) or '0')
+local hourlyLimit = tonumber(ARGV[1])
+local dailyLimit = tonumber(ARGV[2])
+if (hourlyLimit > 0 and hourly >= hourlyLimit) or (dailyLimit > 0 and daily >= dailyLimit) then
+	return {0, hourly, daily}
+end
+hourly = redis.call('INCR', KEYS[1])
+if hourly == 1 then
+	redis.call('EXPIRE', KEYS[1], ARGV[3])
+end
+daily = redis.call('INCR', KEYS[2])
+if daily == 1 then
+	redis.call('EXPIRE', KEYS[2], ARGV[4])
+end
+return {1, hourly, daily}
+`)
+
+// QuotaService meters seller write APIs against the hourly/daily limits of their tier
+type QuotaService struct {
+	redisClient *redis.Client
+	cfg         *config.QuotaConfig
+	now         func() time.Time
+}
+
+// NewQuotaService creates a new quota service
+func NewQuotaService(redisClient *redis.Client, cfg *config.QuotaConfig) *QuotaService {
+	return &QuotaService{
+		redisClient: redisClient,
+		cfg:         cfg,
+		now:         time.Now,
+	}
+}
+
+// Consume counts one request of the seller against the operation's quota. A request
+// over the hourly or daily limit is rejected (Allowed false) and not counted
+func (s *QuotaService) Consume(ctx context.Context, seller string, op domain.QuotaOperation) (*domain.QuotaDecision, error) {
+	tier, err := s.sellerTier(ctx, seller)
+	if err != nil {
+		return nil, err
+	}
+	limits, err := s.tierLimits(ctx, tier)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	usage := s.newUsage(op, limits[op], now)
+	res, err := consumeQuotaScript.Run(ctx, s.redisClient,
+		[]string{
+			fmt.Sprintf(quotaHourlyCountKey, op, seller, usage.HourlyReset.Add(-quotaWindowHour).Unix()),
+			fmt.Sprintf(quotaDailyCountKey, op, seller, dayStart(now).Unix()),
+		},
+		usage.Limit.Hourly, usage.Limit.Daily,
+		int64((usage.HourlyReset.Sub(now) + quotaCounterGrace).Seconds()),
+		int64((usage.DailyReset.Sub(now) + quotaCounterGrace).Seconds()),
+	).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("consume quota: %w", err)
+	}
+
+	usage.HourlyUsed, usage.DailyUsed = res[1], res[2]
+	return &domain.QuotaDecision{Tier: tier, Allowed: res[0] == 1, QuotaUsage: usage}, nil
+}
+
+// SellerQuota returns the tier of a seller and its usage of every operation
+func (s *QuotaService) SellerQuota(ctx context.Context, seller string) (*domain.SellerQuota, error) {
+	if !sellerPattern.MatchString(seller) {
+		return nil, domain.Validation("seller must be shop:<id> or user:<id>")
+	}
+	tier, err := s.sellerTier(ctx, seller)
+	if err != nil {
+		return nil, err
+	}
+	limits, err := s.tierLimits(ctx, tier)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	usages := make([]domain.QuotaUsage, 0, len(domain.QuotaOperations))
+	pipe := s.redisClient.Pipeline()
+	hourly := make([]*redis.StringCmd, len(domain.QuotaOperations))
+	daily := make([]*redis.StringCmd, len(domain.QuotaOperations))
+	for i, op := range domain.QuotaOperations {
+		usage := s.newUsage(op, limits[op], now)
+		usages = append(usages, usage)
+		hourly[i] = pipe.Get(ctx, fmt.Sprintf(quotaHourlyCountKey, op, seller, usage.HourlyReset.Add(-quotaWindowHour).Unix()))
+		daily[i] = pipe.Get(ctx, fmt.Sprintf(quotaDailyCountKey, op, seller, dayStart(now).Unix()))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("read quota usage: %w", err)
+	}
+	for i := range usages {
+		usages[i].HourlyUsed, _ = hourly[i].Int64()
+		usages[i].DailyUsed, _ = daily[i].Int64()
+	}
+
+	return &domain.SellerQuota{Seller: seller, Tier: tier, Usage: usages}, nil
+}
+
+// SetSellerTier assigns a seller to a tier; the default tier removes the assignment
+func (s *QuotaService) SetSellerTier(ctx context.Context, seller, tier string) error {
+	if !sellerPattern.MatchString(seller) {
+		return domain.Validation("seller must be shop:<id> or user:<id>")
+	}
+	if _, ok := s.cfg.Tiers[tier]; !ok {
+		return domain.Validation("unknown quota tier %q", tier)
+	}
+	if tier == s.cfg.DefaultTier {
+		return s.redisClient.HDel(ctx, quotaSellerTiersKey, seller).Err()
+	}
+	return s.redisClient.HSet(ctx, quotaSellerTiersKey, seller, tier).Err()
+}
+
+// ListTiers returns every configured tier with its effective limits
+func (s *QuotaService) ListTiers(ctx context.Context) ([]*domain.QuotaTier, error) {
+	names := make([]string, 0, len(s.cfg.Tiers))
+	for name := range s.cfg.Tiers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tiers := make([]*domain.QuotaTier, 0, len(names))
+	for _, name := range names {
+		limits, err := s.tierLimits(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		tiers = append(tiers, &domain.QuotaTier{Name: name, Default: name == s.cfg.DefaultTier, Limits: limits})
+	}
+	return tiers, nil
+}
+
+// SetTierLimits overrides the limits of some operations of a tier (others keep theirs)
+func (s *QuotaService) SetTierLimits(ctx context.Context, tier string, limits map[domain.QuotaOperation]domain.QuotaLimit) (*domain.QuotaTier, error) {
+	if _, ok := s.cfg.Tiers[tier]; !ok {
+		return nil, domain.NotFound("quota tier %q not found", tier)
+	}
+	fields := make(map[string]interface{}, 2*len(limits))
+	for op, limit := range limits {
+		if limit.Hourly < 0 || limit.Daily < 0 {
+			return nil, domain.Validation("%s limits must not be negative", op)
+		}
+		fields[string(op)+":hourly"] = limit.Hourly
+		fields[string(op)+":daily"] = limit.Daily
+	}
+	if len(fields) > 0 {
+		if err := s.redisClient.HSet(ctx, fmt.Sprintf(quotaTierLimitsKey, tier), fields).Err(); err != nil {
+			return nil, fmt.Errorf("set tier limits: %w", err)
+		}
+	}
+
+	effective, err := s.tierLimits(ctx, tier)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.QuotaTier{Name: tier, Default: tier == s.cfg.DefaultTier, Limits: effective}, nil
+}
+
+// sellerTier returns the tier assigned to the seller, or the default tier
+func (s *QuotaService) sellerTier(ctx context.Context, seller string) (string, error) {
+	tier, err := s.redisClient.HGet(ctx, quotaSellerTiersKey, seller).Result()
+	if err == redis.Nil {
+		return s.cfg.DefaultTier, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("get seller tier: %w", err)
+	}
+	if _, ok := s.cfg.Tiers[tier]; !ok {
+		// Tier removed from the config since it was assigned
+		return s.cfg.DefaultTier, nil
+	}
+	return tier, nil
+}
+
+// tierLimits returns the limits of a tier: config quota.tiers with the admin overrides applied
+func (s *QuotaService) tierLimits(ctx context.Context, tier string) (map[domain.QuotaOperation]domain.QuotaLimit, error) {
+	configured := s.cfg.Tiers[tier]
+	limits := map[domain.QuotaOperation]domain.QuotaLimit{
+		domain.QuotaProductCreate: {Hourly: configured.ProductCreate.Hourly, Daily: configured.ProductCreate.Daily},
+		domain.QuotaStockSync:     {Hourly: configured.StockSync.Hourly, Daily: configured.StockSync.Daily},
+	}
+
+	overrides, err := s.redisClient.HGetAll(ctx, fmt.Sprintf(quotaTierLimitsKey, tier)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("get tier limits: %w", err)
+	}
+	for op, limit := range limits {
+		if v, err := strconv.ParseInt(overrides[string(op)+":hourly"], 10, 64); err == nil {
+			limit.Hourly = v
+		}
+		if v, err := strconv.ParseInt(overrides[string(op)+":daily"], 10, 64); err == nil {
+			limit.Daily = v
+		}
+		limits[op] = limit
+	}
+	return limits, nil
+}
+
+func (s *QuotaService) newUsage(op domain.QuotaOperation, limit domain.QuotaLimit, now time.Time) domain.QuotaUsage {
+	return domain.QuotaUsage{
+		Operation:   op,
+		Limit:       limit,
+		HourlyReset: now.Truncate(quotaWindowHour).Add(quotaWindowHour),
+		DailyReset:  dayStart(now).AddDate(0, 0, 1),
+	}
+}
+
+// dayStart returns local midnight of the day of t; daily quotas follow the local calendar
+func dayStart(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
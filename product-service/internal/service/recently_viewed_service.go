@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"product-service/internal/domain"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Recently viewed products are kept per user (or guest) in a capped Redis list (newest first)
+const (
+	recentlyViewedUserKey  = "recently_viewed:user:%d"
+	recentlyViewedGuestKey = "recently_viewed:guest:%s"
+	recentlyViewedLimit    = 50
+	recentlyViewedTTL      = 90 * 24 * time.Hour // Dropped after 90 days without views
+
+	// EventProductViewed is published for the recommendation pipeline (topic product.viewed)
+	EventProductViewed = "product_viewed"
+)
+
+// ErrViewedProductNotFound is returned when a view is recorded for an unknown product
+var ErrViewedProductNotFound = domain.NotFound("product not found")
+
+// Viewer is whose history is used: the signed-in user or, before login,
+// the guest identity issued by the API Gateway (X-Guest-Id)
+type Viewer struct {
+	UserID  uint
+	GuestID string
+}
+
+// key returns the Redis list of the viewer
+func (v Viewer) key() string {
+	if v.UserID != 0 {
+		return fmt.Sprintf(recentlyViewedUserKey, v.UserID)
+	}
+	return fmt.Sprintf(recentlyViewedGuestKey, v.GuestID)
+}
+
+// RecentlyViewedService tracks the products a user or guest looked at
+type RecentlyViewedService struct {
+	redisClient    *redis.Client
+	productService *ProductService
+	eventPublisher domain.EventPublisher
+	async          AsyncRunner
+	logger         *zap.Logger
+}
+
+// NewRecentlyViewedService creates a new recently viewed service
+func NewRecentlyViewedService(
+	redisClient *redis.Client,
+	productService *ProductService,
+	eventPublisher domain.EventPublisher,
+	async AsyncRunner,
+	logger *zap.Logger,
+) *RecentlyViewedService {
+	return &RecentlyViewedService{
+		redisClient:    redisClient,
+		productService: productService,
+		eventPublisher: eventPublisher,
+		async:          async,
+		logger:         logger,
+	}
+}
+
+// RecordView moves the product to the front of the viewer's list and emits a view event
+func (s *RecentlyViewedService) RecordView(ctx context.Context, viewer Viewer, productID uint) error {
+	if _, err := s.productService.GetProduct(ctx, productID); err != nil {
+		return ErrViewedProductNotFound
+	}
+
+	key := viewer.key()
+	member := strconv.FormatUint(uint64(productID), 10)
+
+	pipe := s.redisClient.TxPipeline()
+	pipe.LRem(ctx, key, 0, member)
+	pipe.LPush(ctx, key, member)
+	pipe.LTrim(ctx, key, 0, recentlyViewedLimit-1)
+	pipe.Expire(ctx, key, recentlyViewedTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record product view: %w", err)
+	}
+
+	event := &domain.ProductEvent{
+		EventType: EventProductViewed,
+		ProductID: productID,
+		Timestamp: time.Now(),
+		Metadata:  viewerMetadata(viewer),
+	}
+	s.async.Submit(ctx, "publish_"+EventProductViewed, func(ctx context.Context) error {
+		return s.eventPublisher.PublishProductEvent(ctx, event)
+	})
+
+	return nil
+}
+
+// List returns the viewer's recently viewed products, newest first
+// Products deleted since they were viewed are left out
+func (s *RecentlyViewedService) List(ctx context.Context, viewer Viewer, limit int) ([]*domain.Product, error) {
+	if limit <= 0 || limit > recentlyViewedLimit {
+		limit = recentlyViewedLimit
+	}
+
+	members, err := s.redisClient.LRange(ctx, viewer.key(), 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recently viewed products: %w", err)
+	}
+
+	ids := make([]uint, 0, len(members))
+	for _, m := range members {
+		if id, err := strconv.ParseUint(m, 10, 32); err == nil {
+			ids = append(ids, uint(id))
+		}
+	}
+	if len(ids) == 0 {
+		return []*domain.Product{}, nil
+	}
+
+	return s.productService.GetProductsByIDs(ctx, ids)
+}
+
+// Clear removes the viewer's history
+func (s *RecentlyViewedService) Clear(ctx context.Context, viewer Viewer) error {
+	if err := s.redisClient.Del(ctx, viewer.key()).Err(); err != nil {
+		return fmt.Errorf("failed to clear recently viewed products: %w", err)
+	}
+	return nil
+}
+
+// viewerMetadata identifies the viewer in product_viewed events
+func viewerMetadata(viewer Viewer) map[string]interface{} {
+	if viewer.UserID != 0 {
+		return map[string]interface{}{"user_id": viewer.UserID}
+	}
+	return map[string]interface{}{"guest_id": viewer.GuestID}
+}
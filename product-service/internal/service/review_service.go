@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"product-service/internal/domain"
+	"product-service/pkg/order_client"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Review limits
+const (
+	maxReviewImages    = 5
+	maxReviewPageLimit = 50
+)
+
+// Review errors
+var (
+	ErrReviewNotFound        = domain.NotFound("review not found")
+	ErrReviewProductNotFound = domain.NotFound("product not found")
+	ErrNotPurchased          = domain.Forbidden("only buyers who received the product can review it")
+	ErrNotReviewAuthor       = domain.Forbidden("only the author can change this review")
+	ErrInvalidReview         = domain.Validation("invalid review")
+)
+
+// ReviewPurchaseVerifier finds the delivered order a buyer received a product in
+// (implemented by pkg/order_client); nil purchase = never received
+type ReviewPurchaseVerifier interface {
+	VerifyPurchase(ctx context.Context, userID, productID uint) (*order_client.Purchase, error)
+}
+
+// ReviewService manages buyer reviews of products and the rating aggregates of products and shops
+// Product aggregates are stored on the product (rating snapshot); shop aggregates are computed on
+// read and published as shop_rating_updated events for search-service
+type ReviewService struct {
+	reviewRepo     domain.ReviewRepository
+	productRepo    domain.ProductRepository
+	productService *ProductService
+	purchases      ReviewPurchaseVerifier
+	outbox         domain.OutboxRepository
+	logger         *zap.Logger
+}
+
+// NewReviewService creates a new review service
+func NewReviewService(
+	reviewRepo domain.ReviewRepository,
+	productRepo domain.ProductRepository,
+	productService *ProductService,
+	purchases ReviewPurchaseVerifier,
+	outbox domain.OutboxRepository,
+	logger *zap.Logger,
+) *ReviewService {
+	return &ReviewService{
+		reviewRepo:     reviewRepo,
+		productRepo:    productRepo,
+		productService: productService,
+		purchases:      purchases,
+		outbox:         outbox,
+		logger:         logger,
+	}
+}
+
+// ReviewActor is the user changing a review, as set by API Gateway
+type ReviewActor struct {
+	UserID uint
+	Role   string
+}
+
+// CreateReviewRequest represents the request to review a received product
+type CreateReviewRequest struct {
+	ProductID uint     `json:"product_id" binding:"required"`
+	Rating    int      `json:"rating" binding:"required,min=1,max=5"`
+	Comment   string   `json:"comment" binding:"max=2000"`
+	Images    []string `json:"images"` // Image URLs, at most 5
+}
+
+// UpdateReviewRequest represents the request to edit a review
+type UpdateReviewRequest struct {
+	Rating  int      `json:"rating" binding:"required,min=1,max=5"`
+	Comment string   `json:"comment" binding:"max=2000"`
+	Images  []string `json:"images"`
+}
+
+// ReviewPage is a page of reviews with the rating summary they belong to
+type ReviewPage struct {
+	Reviews []*domain.Review      `json:"reviews"`
+	Total   int64                 `json:"total"`
+	Page    int                   `json:"page"`
+	Limit   int                   `json:"limit"`
+	Summary *domain.RatingSummary `json:"summary"`
+}
+
+// Create reviews a product the actor received in a delivered order (verified purchase)
+func (s *ReviewService) Create(ctx context.Context, actor ReviewActor, req *CreateReviewRequest) (*domain.Review, error) {
+	images, err := reviewImages(req.Images)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.productRepo.GetByID(ctx, req.ProductID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewProductNotFound
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	purchase, err := s.purchases.VerifyPurchase(ctx, actor.UserID, product.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify purchase: %w", err)
+	}
+	if purchase == nil {
+		return nil, ErrNotPurchased
+	}
+
+	review := &domain.Review{
+		ProductID: product.ID,
+		ShopID:    product.ShopID,
+		UserID:    actor.UserID,
+		OrderID:   purchase.OrderID,
+		Rating:    req.Rating,
+		Comment:   strings.TrimSpace(req.Comment),
+		Images:    images,
+	}
+	if err := s.reviewRepo.Create(ctx, review); err != nil {
+		if errors.Is(err, domain.ErrReviewExists) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create review: %w", err)
+	}
+
+	s.logger.Info("review created",
+		zap.Uint("review_id", review.ID),
+		zap.Uint("product_id", review.ProductID),
+		zap.Uint("user_id", review.UserID),
+		zap.Int("rating", review.Rating),
+	)
+
+	s.refreshRatings(ctx, review.ProductID, review.ShopID)
+	return review, nil
+}
+
+// Update edits the rating, comment and images of the actor's own review
+func (s *ReviewService) Update(ctx context.Context, actor ReviewActor, id uint, req *UpdateReviewRequest) (*domain.Review, error) {
+	review, err := s.getReview(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if review.UserID != actor.UserID {
+		return nil, ErrNotReviewAuthor
+	}
+
+	images, err := reviewImages(req.Images)
+	if err != nil {
+		return nil, err
+	}
+	ratingChanged := review.Rating != req.Rating
+	review.Rating = req.Rating
+	review.Comment = strings.TrimSpace(req.Comment)
+	review.Images = images
+
+	if err := s.reviewRepo.Update(ctx, review); err != nil {
+		return nil, fmt.Errorf("failed to update review: %w", err)
+	}
+
+	if ratingChanged {
+		s.refreshRatings(ctx, review.ProductID, review.ShopID)
+	}
+	return review, nil
+}
+
+// Delete removes a review; the author or an admin (moderation) may delete it
+func (s *ReviewService) Delete(ctx context.Context, actor ReviewActor, id uint) error {
+	review, err := s.getReview(ctx, id)
+	if err != nil {
+		return err
+	}
+	if review.UserID != actor.UserID && actor.Role != "ADMIN" {
+		return ErrNotReviewAuthor
+	}
+
+	if err := s.reviewRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrReviewNotFound
+		}
+		return fmt.Errorf("failed to delete review: %w", err)
+	}
+
+	s.logger.Info("review deleted",
+		zap.Uint("review_id", id),
+		zap.Uint("product_id", review.ProductID),
+		zap.Uint("deleted_by", actor.UserID),
+	)
+
+	s.refreshRatings(ctx, review.ProductID, review.ShopID)
+	return nil
+}
+
+// ListByProduct returns a page of the reviews of a product with its rating summary
+func (s *ReviewService) ListByProduct(ctx context.Context, productID uint, filter domain.ReviewFilter) (*ReviewPage, error) {
+	filter = normalizeReviewFilter(filter)
+	reviews, total, err := s.reviewRepo.ListByProduct(ctx, productID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product reviews: %w", err)
+	}
+	summary, err := s.reviewRepo.ProductSummary(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize product reviews: %w", err)
+	}
+	return &ReviewPage{Reviews: reviews, Total: total, Page: filter.Page, Limit: filter.Limit, Summary: summary}, nil
+}
+
+// ListByShop returns a page of the reviews of a shop's products with the shop rating summary
+func (s *ReviewService) ListByShop(ctx context.Context, shopID uint, filter domain.ReviewFilter) (*ReviewPage, error) {
+	filter = normalizeReviewFilter(filter)
+	reviews, total, err := s.reviewRepo.ListByShop(ctx, shopID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shop reviews: %w", err)
+	}
+	summary, err := s.reviewRepo.ShopSummary(ctx, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize shop reviews: %w", err)
+	}
+	return &ReviewPage{Reviews: reviews, Total: total, Page: filter.Page, Limit: filter.Limit, Summary: summary}, nil
+}
+
+// ShopRating returns the rating aggregate of all reviews of a shop's products
+func (s *ReviewService) ShopRating(ctx context.Context, shopID uint) (*domain.RatingSummary, error) {
+	summary, err := s.reviewRepo.ShopSummary(ctx, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize shop reviews: %w", err)
+	}
+	return summary, nil
+}
+
+// ShopRatings returns the rating aggregates of all shops with reviews (admin shop export of identity-service)
+func (s *ReviewService) ShopRatings(ctx context.Context) ([]domain.ShopRating, error) {
+	ratings, err := s.reviewRepo.ShopRatings(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate shop ratings: %w", err)
+	}
+	return ratings, nil
+}
+
+// refreshRatings recomputes the product and shop aggregates after a review change. The review
+// is already saved, so failures are only logged: the aggregates are recomputed from all reviews
+// with the next change
+func (s *ReviewService) refreshRatings(ctx context.Context, productID, shopID uint) {
+	summary, err := s.reviewRepo.ProductSummary(ctx, productID)
+	if err == nil {
+		_, err = s.productService.UpdateRatingSnapshot(ctx, productID, summary.RatingAvg, summary.RatingCount)
+	}
+	if err != nil {
+		s.logger.Error("failed to refresh product rating", zap.Uint("product_id", productID), zap.Error(err))
+	}
+
+	if err := s.publishShopRating(ctx, productID, shopID); err != nil {
+		s.logger.Error("failed to refresh shop rating", zap.Uint("shop_id", shopID), zap.Error(err))
+	}
+}
+
+// publishShopRating writes a shop_rating_updated event with the shop aggregate to the outbox,
+// keyed by the reviewed product; search-service copies it onto every product of the shop
+func (s *ReviewService) publishShopRating(ctx context.Context, productID, shopID uint) error {
+	summary, err := s.reviewRepo.ShopSummary(ctx, shopID)
+	if err != nil {
+		return fmt.Errorf("failed to summarize shop reviews: %w", err)
+	}
+	event, err := domain.NewOutboxEvent(&domain.ProductEvent{
+		EventType: "shop_rating_updated",
+		ProductID: productID,
+		Timestamp: time.Now(),
+		Metadata: &domain.ShopRatingEvent{
+			ShopID:      shopID,
+			RatingAvg:   summary.RatingAvg,
+			RatingCount: summary.RatingCount,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return s.outbox.Add(ctx, event)
+}
+
+// getReview loads a review, mapping a missing row to ErrReviewNotFound
+func (s *ReviewService) getReview(ctx context.Context, id uint) (*domain.Review, error) {
+	review, err := s.reviewRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	return review, nil
+}
+
+// reviewImages validates the image URLs of a review and encodes them as JSON (nil when none)
+func reviewImages(urls []string) (datatypes.JSON, error) {
+	if len(urls) == 0 {
+		return nil, nil
+	}
+	if len(urls) > maxReviewImages {
+		return nil, fmt.Errorf("%w: at most %d images", ErrInvalidReview, maxReviewImages)
+	}
+	for _, url := range urls {
+		if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+			return nil, fmt.Errorf("%w: image %q is not a URL", ErrInvalidReview, url)
+		}
+	}
+	encoded, err := json.Marshal(urls)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode review images: %w", err)
+	}
+	return datatypes.JSON(encoded), nil
+}
+
+// normalizeReviewFilter applies the default page, limit and sort order
+func normalizeReviewFilter(filter domain.ReviewFilter) domain.ReviewFilter {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.Limit < 1 || filter.Limit > maxReviewPageLimit {
+		filter.Limit = 10
+	}
+	if filter.Rating < 0 || filter.Rating > 5 {
+		filter.Rating = 0
+	}
+	switch filter.Sort {
+	case domain.ReviewSortNewest, domain.ReviewSortRatingDesc, domain.ReviewSortRatingAsc:
+	default:
+		filter.Sort = domain.ReviewSortNewest
+	}
+	return filter
+}
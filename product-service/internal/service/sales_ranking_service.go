@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-service/internal/domain"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Sales are counted per day (UTC) in Redis sorted sets (member = product ID, score =
+// units): one set for all products, one per shop and one per category, where a sale
+// also counts for the parent categories. Rankings are the union of the last
+// salesWindowDays sets; the flush job copies the day totals to Postgres (sold_count)
+// and publishes the overall rank to the search index (sales_rank)
+const (
+	JobTypeSalesFlush  = "sales:flush"
+	SalesFlushInterval = 10 * time.Minute
+
+	salesWindowDays     = 7
+	salesDayTTL         = (salesWindowDays + 2) * 24 * time.Hour // Yesterday is still flushed after midnight
+	salesDayKey         = "sales:%s:products"                    // day (20060102)
+	salesShopDayKey     = "sales:%s:shop:%d"                     // day, shop ID
+	salesCategoryDayKey = "sales:%s:category:%d"                 // day, category ID
+	salesRollingKey     = "sales:rolling:%s"                     // scope: "products", "shop:<id>", "category:<id>"
+	salesRollingTTL     = time.Minute
+	salesRankedKey      = "sales:ranked" // Products given a sales_rank by the last flush
+	salesRankedProducts = 1000           // Products ranked in the search index
+	salesFlushBatchSize = 500
+)
+
+// ErrBestsellerCategoryNotFound is returned for bestsellers of an unknown category
+var ErrBestsellerCategoryNotFound = domain.NotFound("category not found")
+
+// Bestseller is a listed product and the units it sold in the rolling window
+type Bestseller struct {
+	Rank    int             `json:"rank"`
+	Sold    int64           `json:"sold"`
+	Product *domain.Product `json:"product"`
+}
+
+// SalesRankingService keeps the rolling sales counters behind the bestseller lists
+type SalesRankingService struct {
+	productRepo  domain.ProductRepository
+	categoryRepo domain.CategoryRepository
+	salesRepo    domain.ProductSalesRepository
+	searchRepo   domain.ProductSearchRepository
+	redisClient  *redis.Client
+	async        AsyncRunner
+	logger       *zap.Logger
+}
+
+// NewSalesRankingService creates a new sales ranking service
+func NewSalesRankingService(
+	productRepo domain.ProductRepository,
+	categoryRepo domain.CategoryRepository,
+	salesRepo domain.ProductSalesRepository,
+	searchRepo domain.ProductSearchRepository,
+	redisClient *redis.Client,
+	async AsyncRunner,
+	logger *zap.Logger,
+) *SalesRankingService {
+	return &SalesRankingService{
+		productRepo:  productRepo,
+		categoryRepo: categoryRepo,
+		salesRepo:    salesRepo,
+		searchRepo:   searchRepo,
+		redisClient:  redisClient,
+		async:        async,
+		logger:       logger,
+	}
+}
+
+// salesDay is the day bucket of a sale
+func salesDay(t time.Time) string {
+	return t.UTC().Format("20060102")
+}
+
+// windowDays returns the day buckets of the rolling window ending today
+func windowDays(now time.Time) []string {
+	days := make([]string, salesWindowDays)
+	for i := range days {
+		days[i] = salesDay(now.AddDate(0, 0, -i))
+	}
+	return days
+}
+
+// RecordSale counts units of a product as sold now, on the worker pool
+// Called once per deducted order item, so retries of a deduction are not counted twice
+func (s *SalesRankingService) RecordSale(productID uint, quantity int) {
+	if productID == 0 || quantity <= 0 {
+		return
+	}
+	soldAt := time.Now()
+	s.async.Submit(context.Background(), "record_sale", func(ctx context.Context) error {
+		return s.recordSale(ctx, productID, quantity, soldAt)
+	})
+}
+
+func (s *SalesRankingService) recordSale(ctx context.Context, productID uint, quantity int, soldAt time.Time) error {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.logger.Warn("sale of unknown product not counted", zap.Uint("product_id", productID))
+			return nil
+		}
+		return fmt.Errorf("failed to get product: %w", err)
+	}
+	categoryIDs, err := s.categoryChain(ctx, product.CategoryID)
+	if err != nil {
+		return err
+	}
+
+	day := salesDay(soldAt)
+	keys := []string{
+		fmt.Sprintf(salesDayKey, day),
+		fmt.Sprintf(salesShopDayKey, day, product.ShopID),
+	}
+	for _, id := range categoryIDs {
+		keys = append(keys, fmt.Sprintf(salesCategoryDayKey, day, id))
+	}
+
+	// One transaction so a retry never counts the sale in some sets twice
+	member := strconv.FormatUint(uint64(productID), 10)
+	_, err = s.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.ZIncrBy(ctx, key, float64(quantity), member)
+			pipe.Expire(ctx, key, salesDayTTL)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to count sale: %w", err)
+	}
+	return nil
+}
+
+// categoryChain returns the category and its ancestors (empty without a category)
+func (s *SalesRankingService) categoryChain(ctx context.Context, categoryID *uint) ([]uint, error) {
+	var ids []uint
+	for next := categoryID; next != nil && len(ids) < maxCategoryDepth; {
+		category, err := s.categoryRepo.GetByID(ctx, *next)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				break
+			}
+			return nil, fmt.Errorf("failed to get category: %w", err)
+		}
+		ids = append(ids, category.ID)
+		next = category.ParentID
+	}
+	return ids, nil
+}
+
+// CategoryBestsellers returns the best-selling listed products of a category and its
+// subcategories over the last salesWindowDays days
+func (s *SalesRankingService) CategoryBestsellers(ctx context.Context, categoryID uint, limit int) ([]Bestseller, error) {
+	if _, err := s.categoryRepo.GetByID(ctx, categoryID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBestsellerCategoryNotFound
+		}
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+	return s.bestsellers(ctx, fmt.Sprintf("category:%d", categoryID), func(day string) string {
+		return fmt.Sprintf(salesCategoryDayKey, day, categoryID)
+	}, limit)
+}
+
+// ShopBestsellers returns the best-selling listed products of a shop over the last
+// salesWindowDays days (empty for a shop without sales)
+func (s *SalesRankingService) ShopBestsellers(ctx context.Context, shopID uint, limit int) ([]Bestseller, error) {
+	return s.bestsellers(ctx, fmt.Sprintf("shop:%d", shopID), func(day string) string {
+		return fmt.Sprintf(salesShopDayKey, day, shopID)
+	}, limit)
+}
+
+// bestsellers ranks the listed products of a scope; unlisted products are skipped
+// (the ranks stay consecutive)
+func (s *SalesRankingService) bestsellers(ctx context.Context, scope string, dayKey func(day string) string, limit int) ([]Bestseller, error) {
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	// Read extra entries to make up for products that are no longer listed
+	scores, err := s.rolling(ctx, scope, dayKey, int64(limit*2))
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, 0, len(scores))
+	for _, z := range scores {
+		if id, ok := memberID(z.Member); ok {
+			ids = append(ids, id)
+		}
+	}
+	products, err := s.productRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get products: %w", err)
+	}
+	byID := make(map[uint]*domain.Product, len(products))
+	for _, product := range products {
+		byID[product.ID] = product
+	}
+
+	bestsellers := make([]Bestseller, 0, limit)
+	for _, z := range scores {
+		id, _ := memberID(z.Member)
+		product, ok := byID[id]
+		if !ok || product.Status != domain.ProductStatusActive || !product.IsActive {
+			continue
+		}
+		bestsellers = append(bestsellers, Bestseller{
+			Rank:    len(bestsellers) + 1,
+			Sold:    int64(z.Score),
+			Product: product,
+		})
+		if len(bestsellers) == limit {
+			break
+		}
+	}
+	return bestsellers, nil
+}
+
+// rolling returns the n best-selling products of a scope over the window, best first
+// The union of the day sets is cached for salesRollingTTL
+func (s *SalesRankingService) rolling(ctx context.Context, scope string, dayKey func(day string) string, n int64) ([]redis.Z, error) {
+	key := fmt.Sprintf(salesRollingKey, scope)
+	exists, err := s.redisClient.Exists(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sales ranking: %w", err)
+	}
+	if exists == 0 {
+		days := windowDays(time.Now())
+		keys := make([]string, len(days))
+		for i, day := range days {
+			keys[i] = dayKey(day)
+		}
+		_, err := s.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.ZUnionStore(ctx, key, &redis.ZStore{Keys: keys})
+			pipe.Expire(ctx, key, salesRollingTTL)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute sales ranking: %w", err)
+		}
+	}
+
+	scores, err := s.redisClient.ZRevRangeWithScores(ctx, key, 0, n-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sales ranking: %w", err)
+	}
+	return scores, nil
+}
+
+// memberID parses a product ID member of a sales set
+func memberID(member interface{}) (uint, bool) {
+	str, ok := member.(string)
+	if !ok {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(str, 10, 32)
+	if err != nil || id == 0 {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// HandleFlush is the job handler for JobTypeSalesFlush
+func (s *SalesRankingService) HandleFlush(ctx context.Context, _ []byte) error {
+	return s.Flush(ctx)
+}
+
+// Flush copies the sales of yesterday and today to Postgres, refreshes sold_count of
+// the products sold and republishes the search ranks. Every run writes all ranks, so a
+// rebuilt search index gets them back on the next flush
+func (s *SalesRankingService) Flush(ctx context.Context) error {
+	now := time.Now()
+	sold := make(map[uint]bool)
+	for _, day := range []time.Time{now.AddDate(0, 0, -1), now} {
+		ids, err := s.flushDay(ctx, day)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			sold[id] = true
+		}
+	}
+
+	ids := make([]uint, 0, len(sold))
+	for id := range sold {
+		ids = append(ids, id)
+	}
+	for start := 0; start < len(ids); start += salesFlushBatchSize {
+		end := min(start+salesFlushBatchSize, len(ids))
+		if err := s.salesRepo.RefreshSoldCounts(ctx, ids[start:end]); err != nil {
+			return fmt.Errorf("failed to refresh sold counts: %w", err)
+		}
+	}
+
+	ranked, err := s.publishRanks(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("sales flushed", zap.Int("products_sold", len(ids)), zap.Int("products_ranked", ranked))
+	return nil
+}
+
+// flushDay saves the day totals of one day bucket and returns the products in it
+func (s *SalesRankingService) flushDay(ctx context.Context, day time.Time) ([]uint, error) {
+	scores, err := s.redisClient.ZRangeWithScores(ctx, fmt.Sprintf(salesDayKey, salesDay(day)), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read day sales: %w", err)
+	}
+
+	units := make(map[uint]int64, len(scores))
+	ids := make([]uint, 0, len(scores))
+	for _, z := range scores {
+		if id, ok := memberID(z.Member); ok {
+			units[id] = int64(z.Score)
+			ids = append(ids, id)
+		}
+	}
+
+	y, m, d := day.UTC().Date()
+	date := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	for start := 0; start < len(ids); start += salesFlushBatchSize {
+		end := min(start+salesFlushBatchSize, len(ids))
+		products, err := s.productRepo.GetByIDs(ctx, ids[start:end])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get products: %w", err)
+		}
+
+		rows := make([]*domain.ProductSalesDaily, 0, len(products))
+		for _, product := range products {
+			rows = append(rows, &domain.ProductSalesDaily{
+				ProductID:  product.ID,
+				Day:        date,
+				ShopID:     product.ShopID,
+				CategoryID: product.CategoryID,
+				Units:      units[product.ID],
+				UpdatedAt:  time.Now(),
+			})
+		}
+		if err := s.salesRepo.UpsertDaily(ctx, rows); err != nil {
+			return nil, fmt.Errorf("failed to save day sales: %w", err)
+		}
+	}
+	return ids, nil
+}
+
+// publishRanks writes the overall rank of the salesRankedProducts best sellers to the
+// search index and clears the rank of products that dropped out since the last flush
+func (s *SalesRankingService) publishRanks(ctx context.Context) (int, error) {
+	scores, err := s.rolling(ctx, "products", func(day string) string {
+		return fmt.Sprintf(salesDayKey, day)
+	}, salesRankedProducts)
+	if err != nil {
+		return 0, err
+	}
+
+	ranks := make(map[uint]int, len(scores))
+	members := make([]interface{}, 0, len(scores))
+	for _, z := range scores {
+		if id, ok := memberID(z.Member); ok {
+			ranks[id] = len(ranks) + 1
+			members = append(members, z.Member)
+		}
+	}
+	ranked := len(ranks)
+
+	previous, err := s.redisClient.SMembers(ctx, salesRankedKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read ranked products: %w", err)
+	}
+	for _, member := range previous {
+		if id, ok := memberID(member); ok {
+			if _, still := ranks[id]; !still {
+				ranks[id] = 0
+			}
+		}
+	}
+
+	if err := s.searchRepo.UpdateSalesRanks(ctx, ranks); err != nil {
+		return 0, err
+	}
+
+	_, err = s.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, salesRankedKey)
+		if len(members) > 0 {
+			pipe.SAdd(ctx, salesRankedKey, members...)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to save ranked products: %w", err)
+	}
+	return ranked, nil
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-service/internal/domain"
+	"strings"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Shop collection limits
+const (
+	maxCollectionsPerShop    = 50
+	maxProductsPerCollection = 500
+)
+
+// Shop collection errors
+var (
+	ErrCollectionNotFound = domain.NotFound("shop collection not found")
+	ErrInvalidCollection  = domain.Validation("invalid shop collection")
+	ErrCollectionLimit    = errors.New("too many shop collections")
+	ErrProductNotInShop   = domain.Validation("product does not belong to the shop")
+)
+
+// ShopCollectionService manages seller-defined shop collections for storefront navigation
+type ShopCollectionService struct {
+	collectionRepo domain.ShopCollectionRepository
+	productRepo    domain.ProductRepository
+	logger         *zap.Logger
+}
+
+// NewShopCollectionService creates a new shop collection service
+func NewShopCollectionService(
+	collectionRepo domain.ShopCollectionRepository,
+	productRepo domain.ProductRepository,
+	logger *zap.Logger,
+) *ShopCollectionService {
+	return &ShopCollectionService{
+		collectionRepo: collectionRepo,
+		productRepo:    productRepo,
+		logger:         logger,
+	}
+}
+
+// ShopCollectionRequest represents the request to create or update a shop collection
+type ShopCollectionRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description"`
+	IsVisible   *bool  `json:"is_visible"` // Default true
+}
+
+// ReorderCollectionsRequest lists all collections of a shop in their new order
+type ReorderCollectionsRequest struct {
+	CollectionIDs []uint `json:"collection_ids" binding:"required"`
+}
+
+// SetCollectionProductsRequest lists the products of a collection in display order
+type SetCollectionProductsRequest struct {
+	ProductIDs []uint `json:"product_ids" binding:"required"`
+}
+
+// CreateCollection creates a collection at the end of the shop's navigation
+func (s *ShopCollectionService) CreateCollection(ctx context.Context, shopID uint, req *ShopCollectionRequest) (*domain.ShopCollection, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is empty", ErrInvalidCollection)
+	}
+
+	count, err := s.collectionRepo.CountByShop(ctx, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count shop collections: %w", err)
+	}
+	if count >= maxCollectionsPerShop {
+		return nil, fmt.Errorf("%w: at most %d per shop", ErrCollectionLimit, maxCollectionsPerShop)
+	}
+
+	last, err := s.collectionRepo.MaxPosition(ctx, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection positions: %w", err)
+	}
+
+	collection := &domain.ShopCollection{
+		ShopID:      shopID,
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		Position:    last + 1,
+		IsVisible:   req.IsVisible == nil || *req.IsVisible,
+	}
+	if err := s.collectionRepo.Create(ctx, collection); err != nil {
+		s.logger.Error("failed to create shop collection", zap.Uint("shop_id", shopID), zap.Error(err))
+		return nil, fmt.Errorf("failed to create shop collection: %w", err)
+	}
+
+	s.logger.Info("shop collection created", zap.Uint("shop_id", shopID), zap.Uint("collection_id", collection.ID))
+	return collection, nil
+}
+
+// UpdateCollection changes the name, description and visibility of a collection
+func (s *ShopCollectionService) UpdateCollection(ctx context.Context, shopID, collectionID uint, req *ShopCollectionRequest) (*domain.ShopCollection, error) {
+	collection, err := s.getShopCollection(ctx, shopID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is empty", ErrInvalidCollection)
+	}
+	collection.Name = name
+	collection.Description = strings.TrimSpace(req.Description)
+	if req.IsVisible != nil {
+		collection.IsVisible = *req.IsVisible
+	}
+
+	if err := s.collectionRepo.Update(ctx, collection); err != nil {
+		return nil, fmt.Errorf("failed to update shop collection: %w", err)
+	}
+	return collection, nil
+}
+
+// DeleteCollection removes a collection; its products stay in the shop
+func (s *ShopCollectionService) DeleteCollection(ctx context.Context, shopID, collectionID uint) error {
+	if _, err := s.getShopCollection(ctx, shopID, collectionID); err != nil {
+		return err
+	}
+	if err := s.collectionRepo.Delete(ctx, collectionID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCollectionNotFound
+		}
+		return fmt.Errorf("failed to delete shop collection: %w", err)
+	}
+
+	s.logger.Info("shop collection deleted", zap.Uint("shop_id", shopID), zap.Uint("collection_id", collectionID))
+	return nil
+}
+
+// ReorderCollections sets the navigation order; the request must list every collection of the shop once
+func (s *ShopCollectionService) ReorderCollections(ctx context.Context, shopID uint, req *ReorderCollectionsRequest) ([]*domain.ShopCollection, error) {
+	collections, err := s.collectionRepo.ListByShop(ctx, shopID, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shop collections: %w", err)
+	}
+
+	owned := make(map[uint]bool, len(collections))
+	for _, collection := range collections {
+		owned[collection.ID] = true
+	}
+	if len(req.CollectionIDs) != len(collections) {
+		return nil, fmt.Errorf("%w: collection_ids must list all %d collections of the shop", ErrInvalidCollection, len(collections))
+	}
+	seen := make(map[uint]bool, len(req.CollectionIDs))
+	for _, id := range req.CollectionIDs {
+		if !owned[id] || seen[id] {
+			return nil, fmt.Errorf("%w: collection %d is unknown or repeated", ErrInvalidCollection, id)
+		}
+		seen[id] = true
+	}
+
+	if err := s.collectionRepo.Reorder(ctx, shopID, req.CollectionIDs); err != nil {
+		return nil, fmt.Errorf("failed to reorder shop collections: %w", err)
+	}
+	return s.ListCollections(ctx, shopID, true)
+}
+
+// SetCollectionProducts replaces the products of a collection, in the given order
+// Every product must belong to the collection's shop
+func (s *ShopCollectionService) SetCollectionProducts(ctx context.Context, shopID, collectionID uint, req *SetCollectionProductsRequest) error {
+	if _, err := s.getShopCollection(ctx, shopID, collectionID); err != nil {
+		return err
+	}
+	if len(req.ProductIDs) > maxProductsPerCollection {
+		return fmt.Errorf("%w: at most %d products per collection", ErrInvalidCollection, maxProductsPerCollection)
+	}
+
+	// Drop repeats, keeping the first position
+	seen := make(map[uint]bool, len(req.ProductIDs))
+	productIDs := make([]uint, 0, len(req.ProductIDs))
+	for _, id := range req.ProductIDs {
+		if !seen[id] {
+			seen[id] = true
+			productIDs = append(productIDs, id)
+		}
+	}
+
+	products, err := s.productRepo.GetByIDs(ctx, productIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get products: %w", err)
+	}
+	shopOf := make(map[uint]uint, len(products))
+	for _, product := range products {
+		shopOf[product.ID] = product.ShopID
+	}
+	for _, id := range productIDs {
+		if shopOf[id] != shopID {
+			return fmt.Errorf("%w: product %d", ErrProductNotInShop, id)
+		}
+	}
+
+	if err := s.collectionRepo.SetProducts(ctx, collectionID, productIDs); err != nil {
+		return fmt.Errorf("failed to save collection products: %w", err)
+	}
+
+	s.logger.Info("shop collection products saved",
+		zap.Uint("collection_id", collectionID),
+		zap.Int("products", len(productIDs)),
+	)
+	return nil
+}
+
+// ListCollections returns the shop's collections in navigation order with their
+// active product counts; storefronts only get visible collections
+func (s *ShopCollectionService) ListCollections(ctx context.Context, shopID uint, includeHidden bool) ([]*domain.ShopCollection, error) {
+	collections, err := s.collectionRepo.ListByShop(ctx, shopID, !includeHidden)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shop collections: %w", err)
+	}
+
+	ids := make([]uint, len(collections))
+	for i, collection := range collections {
+		ids[i] = collection.ID
+	}
+	counts, err := s.collectionRepo.CountActiveProducts(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count collection products: %w", err)
+	}
+	for _, collection := range collections {
+		collection.ProductCount = counts[collection.ID]
+	}
+	return collections, nil
+}
+
+// GetCollectionProducts returns a page of the collection's active products in collection order
+func (s *ShopCollectionService) GetCollectionProducts(ctx context.Context, shopID, collectionID uint, page, limit int) ([]*domain.Product, int64, error) {
+	collection, err := s.getShopCollection(ctx, shopID, collectionID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !collection.IsVisible {
+		return nil, 0, ErrCollectionNotFound
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	ids, total, err := s.collectionRepo.ListProductIDs(ctx, collectionID, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list collection products: %w", err)
+	}
+	products, err := s.productRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get products: %w", err)
+	}
+
+	// GetByIDs does not keep the order
+	byID := make(map[uint]*domain.Product, len(products))
+	for _, product := range products {
+		byID[product.ID] = product
+	}
+	ordered := make([]*domain.Product, 0, len(ids))
+	for _, id := range ids {
+		if product, ok := byID[id]; ok {
+			ordered = append(ordered, product)
+		}
+	}
+	return ordered, total, nil
+}
+
+// getShopCollection loads a collection and checks it belongs to the shop
+func (s *ShopCollectionService) getShopCollection(ctx context.Context, shopID, collectionID uint) (*domain.ShopCollection, error) {
+	collection, err := s.collectionRepo.GetByID(ctx, collectionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCollectionNotFound
+		}
+		return nil, fmt.Errorf("failed to get shop collection: %w", err)
+	}
+	if collection.ShopID != shopID {
+		return nil, ErrCollectionNotFound
+	}
+	return collection, nil
+}
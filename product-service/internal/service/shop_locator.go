@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"product-service/internal/domain"
+	"product-service/pkg/shop_client"
+	"sync"
+	"time"
+)
+
+// ShopLocator resolves where a shop ships from (implemented by CachedShopLocator)
+type ShopLocator interface {
+	ShopLocation(ctx context.Context, shopID uint) (*domain.ShopLocation, error)
+}
+
+// CachedShopLocator reads shop locations from identity-service and keeps them for ttl,
+// so indexing the products of a shop does not call identity-service for every product.
+// A changed location reaches the search index with the next update of each product
+type CachedShopLocator struct {
+	client *shop_client.ShopClient
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	entries map[uint]cachedShopLocation
+}
+
+type cachedShopLocation struct {
+	location  *domain.ShopLocation
+	expiresAt time.Time
+}
+
+// NewCachedShopLocator creates a shop locator over the identity-service shop client
+func NewCachedShopLocator(client *shop_client.ShopClient, ttl time.Duration) *CachedShopLocator {
+	return &CachedShopLocator{
+		client:  client,
+		ttl:     ttl,
+		entries: make(map[uint]cachedShopLocation),
+	}
+}
+
+// ShopLocation returns the ships-from location of the shop; errors are not cached
+func (l *CachedShopLocator) ShopLocation(ctx context.Context, shopID uint) (*domain.ShopLocation, error) {
+	now := time.Now()
+	l.mu.Lock()
+	entry, ok := l.entries[shopID]
+	l.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.location, nil
+	}
+
+	shop, err := l.client.GetShop(ctx, shopID)
+	if err != nil {
+		return nil, err
+	}
+	location := &domain.ShopLocation{Province: shop.Province}
+	if shop.Latitude != nil && shop.Longitude != nil {
+		location.Point = &domain.GeoPoint{Lat: *shop.Latitude, Lon: *shop.Longitude}
+	}
+
+	l.mu.Lock()
+	for id, e := range l.entries {
+		// Drop expired entries on the way so shops that stopped selling do not pile up
+		if !now.Before(e.expiresAt) {
+			delete(l.entries, id)
+		}
+	}
+	l.entries[shopID] = cachedShopLocation{location: location, expiresAt: now.Add(l.ttl)}
+	l.mu.Unlock()
+
+	return location, nil
+}
@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"product-service/internal/domain"
+	"strings"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Size guide limits
+const (
+	maxSizeGuideMeasurements = 20
+	maxSizeGuideSizes        = 30
+	maxCategoryDepth         = 10 // Guards the ancestor walk against parent cycles
+)
+
+// Size guide errors
+var (
+	ErrSizeChartNotFound         = domain.NotFound("size chart not found")
+	ErrMeasurementNotFound       = domain.NotFound("product measurements not found")
+	ErrSizeGuideCategoryNotFound = domain.NotFound("category not found")
+	ErrSizeGuideProductNotFound  = domain.NotFound("product not found")
+	ErrInvalidSizeGuide          = domain.Validation("invalid size guide")
+)
+
+// SizeGuideService manages category size chart templates and per-product measurements
+type SizeGuideService struct {
+	sizeGuideRepo domain.SizeGuideRepository
+	categoryRepo  domain.CategoryRepository
+	productRepo   domain.ProductRepository
+	logger        *zap.Logger
+}
+
+// NewSizeGuideService creates a new size guide service
+func NewSizeGuideService(
+	sizeGuideRepo domain.SizeGuideRepository,
+	categoryRepo domain.CategoryRepository,
+	productRepo domain.ProductRepository,
+	logger *zap.Logger,
+) *SizeGuideService {
+	return &SizeGuideService{
+		sizeGuideRepo: sizeGuideRepo,
+		categoryRepo:  categoryRepo,
+		productRepo:   productRepo,
+		logger:        logger,
+	}
+}
+
+// SizeChartRequest represents the request to create or replace a category size chart
+type SizeChartRequest struct {
+	Name         string           `json:"name" binding:"required,max=100"`
+	Unit         string           `json:"unit"` // cm (default) or inch
+	Measurements []string         `json:"measurements" binding:"required,min=1"`
+	Sizes        []domain.SizeRow `json:"sizes" binding:"required,min=1"`
+	Notes        string           `json:"notes"`
+}
+
+// ProductMeasurementRequest represents the request to set a product's measurements
+type ProductMeasurementRequest struct {
+	Unit  string           `json:"unit"` // cm (default) or inch
+	Sizes []domain.SizeRow `json:"sizes" binding:"required,min=1"`
+	Notes string           `json:"notes"`
+}
+
+// SetCategoryChart creates or replaces the size chart template of a category
+func (s *SizeGuideService) SetCategoryChart(ctx context.Context, categoryID uint, req *SizeChartRequest) (*domain.SizeChart, error) {
+	if _, err := s.categoryRepo.GetByID(ctx, categoryID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSizeGuideCategoryNotFound
+		}
+		return nil, fmt.Errorf("failed to get category: %w", err)
+	}
+
+	unit, err := normalizeUnit(req.Unit)
+	if err != nil {
+		return nil, err
+	}
+	measurements, err := normalizeMeasurements(req.Measurements)
+	if err != nil {
+		return nil, err
+	}
+	sizes, err := normalizeSizes(req.Sizes, measurements)
+	if err != nil {
+		return nil, err
+	}
+
+	chart := &domain.SizeChart{
+		CategoryID:   categoryID,
+		Name:         strings.TrimSpace(req.Name),
+		Unit:         unit,
+		Measurements: measurements,
+		Sizes:        sizes,
+		Notes:        strings.TrimSpace(req.Notes),
+	}
+	if err := s.sizeGuideRepo.UpsertChart(ctx, chart); err != nil {
+		s.logger.Error("failed to save size chart", zap.Uint("category_id", categoryID), zap.Error(err))
+		return nil, fmt.Errorf("failed to save size chart: %w", err)
+	}
+
+	s.logger.Info("size chart saved", zap.Uint("category_id", categoryID), zap.Int("sizes", len(sizes)))
+	return chart, nil
+}
+
+// GetCategoryChart returns the size chart a category uses: its own or the nearest ancestor's
+func (s *SizeGuideService) GetCategoryChart(ctx context.Context, categoryID uint) (*domain.SizeChart, error) {
+	chart, err := s.resolveChart(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	if chart == nil {
+		return nil, ErrSizeChartNotFound
+	}
+	return chart, nil
+}
+
+// DeleteCategoryChart removes a category's own size chart
+func (s *SizeGuideService) DeleteCategoryChart(ctx context.Context, categoryID uint) error {
+	if err := s.sizeGuideRepo.DeleteChart(ctx, categoryID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSizeChartNotFound
+		}
+		return fmt.Errorf("failed to delete size chart: %w", err)
+	}
+	s.logger.Info("size chart deleted", zap.Uint("category_id", categoryID))
+	return nil
+}
+
+// SetProductMeasurements creates or replaces a product's measurement table
+// When the product's category has a size chart, only its measurements may be used
+func (s *SizeGuideService) SetProductMeasurements(ctx context.Context, productID uint, req *ProductMeasurementRequest) (*domain.ProductMeasurement, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSizeGuideProductNotFound
+		}
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	unit, err := normalizeUnit(req.Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	var allowed []string // nil = any measurement
+	if product.CategoryID != nil {
+		chart, err := s.resolveChart(ctx, *product.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		if chart != nil {
+			allowed = chart.Measurements
+		}
+	}
+	sizes, err := normalizeSizes(req.Sizes, allowed)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &domain.ProductMeasurement{
+		ProductID: productID,
+		Unit:      unit,
+		Sizes:     sizes,
+		Notes:     strings.TrimSpace(req.Notes),
+	}
+	if err := s.sizeGuideRepo.UpsertMeasurement(ctx, m); err != nil {
+		s.logger.Error("failed to save product measurements", zap.Uint("product_id", productID), zap.Error(err))
+		return nil, fmt.Errorf("failed to save product measurements: %w", err)
+	}
+
+	s.logger.Info("product measurements saved", zap.Uint("product_id", productID), zap.Int("sizes", len(sizes)))
+	return m, nil
+}
+
+// DeleteProductMeasurements removes a product's measurement table
+func (s *SizeGuideService) DeleteProductMeasurements(ctx context.Context, productID uint) error {
+	if err := s.sizeGuideRepo.DeleteMeasurement(ctx, productID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrMeasurementNotFound
+		}
+		return fmt.Errorf("failed to delete product measurements: %w", err)
+	}
+	return nil
+}
+
+// GetSizeGuide returns the size guide of a product, nil if it has neither a
+// category chart nor measurements
+func (s *SizeGuideService) GetSizeGuide(ctx context.Context, product *domain.Product) (*domain.SizeGuide, error) {
+	guide := &domain.SizeGuide{}
+
+	if product.CategoryID != nil {
+		chart, err := s.resolveChart(ctx, *product.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		guide.Chart = chart
+	}
+
+	m, err := s.sizeGuideRepo.GetMeasurement(ctx, product.ID)
+	switch {
+	case err == nil:
+		guide.Measurements = m
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, fmt.Errorf("failed to get product measurements: %w", err)
+	}
+
+	if guide.Chart == nil && guide.Measurements == nil {
+		return nil, nil
+	}
+	return guide, nil
+}
+
+// resolveChart finds the chart of a category or its nearest ancestor, nil if there is none
+func (s *SizeGuideService) resolveChart(ctx context.Context, categoryID uint) (*domain.SizeChart, error) {
+	// Category path, own category first
+	path := []uint{categoryID}
+	current := categoryID
+	for depth := 0; depth < maxCategoryDepth; depth++ {
+		category, err := s.categoryRepo.GetByID(ctx, current)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				break
+			}
+			return nil, fmt.Errorf("failed to get category: %w", err)
+		}
+		if category.ParentID == nil {
+			break
+		}
+		current = *category.ParentID
+		path = append(path, current)
+	}
+
+	charts, err := s.sizeGuideRepo.GetChartsByCategoryIDs(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get size charts: %w", err)
+	}
+	byCategory := make(map[uint]*domain.SizeChart, len(charts))
+	for _, chart := range charts {
+		byCategory[chart.CategoryID] = chart
+	}
+	for _, id := range path {
+		if chart, ok := byCategory[id]; ok {
+			return chart, nil
+		}
+	}
+	return nil, nil
+}
+
+// normalizeUnit defaults the unit to cm and accepts cm or inch
+func normalizeUnit(unit string) (string, error) {
+	switch unit = strings.ToLower(strings.TrimSpace(unit)); unit {
+	case "":
+		return "cm", nil
+	case "cm", "inch":
+		return unit, nil
+	default:
+		return "", fmt.Errorf("%w: unit must be cm or inch", ErrInvalidSizeGuide)
+	}
+}
+
+// normalizeMeasurements trims measurement names and rejects empty or duplicate ones
+func normalizeMeasurements(names []string) ([]string, error) {
+	if len(names) > maxSizeGuideMeasurements {
+		return nil, fmt.Errorf("%w: at most %d measurements", ErrInvalidSizeGuide, maxSizeGuideMeasurements)
+	}
+	seen := make(map[string]bool, len(names))
+	result := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: measurement name is empty", ErrInvalidSizeGuide)
+		}
+		if seen[strings.ToLower(name)] {
+			return nil, fmt.Errorf("%w: duplicate measurement %q", ErrInvalidSizeGuide, name)
+		}
+		seen[strings.ToLower(name)] = true
+		result = append(result, name)
+	}
+	return result, nil
+}
+
+// normalizeSizes trims sizes and values and rejects empty or duplicate sizes and,
+// if allowed is set, values of other measurements
+func normalizeSizes(rows []domain.SizeRow, allowed []string) ([]domain.SizeRow, error) {
+	if len(rows) > maxSizeGuideSizes {
+		return nil, fmt.Errorf("%w: at most %d sizes", ErrInvalidSizeGuide, maxSizeGuideSizes)
+	}
+	allowedSet := make(map[string]bool, len(allowed))
+	for _, name := range allowed {
+		allowedSet[name] = true
+	}
+
+	seen := make(map[string]bool, len(rows))
+	result := make([]domain.SizeRow, 0, len(rows))
+	for _, row := range rows {
+		size := strings.TrimSpace(row.Size)
+		if size == "" {
+			return nil, fmt.Errorf("%w: size is empty", ErrInvalidSizeGuide)
+		}
+		if seen[strings.ToUpper(size)] {
+			return nil, fmt.Errorf("%w: duplicate size %q", ErrInvalidSizeGuide, size)
+		}
+		seen[strings.ToUpper(size)] = true
+
+		values := make(map[string]string, len(row.Values))
+		for name, value := range row.Values {
+			name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			if allowed != nil && !allowedSet[name] {
+				return nil, fmt.Errorf("%w: size %q has unknown measurement %q", ErrInvalidSizeGuide, size, name)
+			}
+			values[name] = value
+		}
+		result = append(result, domain.SizeRow{Size: size, Values: values})
+	}
+	return result, nil
+}
@@ -6,40 +6,153 @@ import (
 	"errors"
 	"fmt"
 	"product-service/internal/domain"
+	"product-service/pkg/jobs"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
 // StockService handles stock management operations
-// This service prevents overselling with Redis distributed locks
+// Overselling is prevented by atomic Redis scripts for reservations and by
+// conditional UPDATEs for deductions
 type StockService struct {
 	productItemRepo domain.ProductItemRepository
 	redisClient     *redis.Client
+	settings        SettingsReader
+	jobs            JobEnqueuer
+	events          productItemEvents
+	sales           SalesRecorder
 	logger          *zap.Logger
 }
 
+// SalesRecorder counts units sold for the bestseller rankings (implemented by SalesRankingService)
+type SalesRecorder interface {
+	RecordSale(productID uint, quantity int)
+}
+
+// SettingsReader reads admin-managed runtime settings (implemented by pkg/settings)
+type SettingsReader interface {
+	GetInt(scope, key string, def int) int
+}
+
+// JobEnqueuer schedules background jobs (implemented by pkg/jobs)
+type JobEnqueuer interface {
+	Enqueue(ctx context.Context, jobType string, payload interface{}, opts ...jobs.Option) (*jobs.Job, error)
+	Cancel(ctx context.Context, id string) error
+}
+
+// Reservation job types: the expiry job releases an order's reservations once its hold
+// time is over, the periodic sweep releases any the expiry job missed (see reservationDeadlinesKey)
+const (
+	JobTypeReservationExpiry = "stock:reservation_expiry"
+	JobTypeReservationSweep  = "stock:reservation_sweep"
+	ReservationSweepInterval = time.Minute
+)
+
+// ReservationExpiryPayload is the payload of JobTypeReservationExpiry
+type ReservationExpiryPayload struct {
+	OrderID string `json:"order_id"`
+}
+
 // NewStockService creates a new stock service
 func NewStockService(
 	productItemRepo domain.ProductItemRepository,
 	redisClient *redis.Client,
+	settings SettingsReader,
+	jobEnqueuer JobEnqueuer,
+	eventPublisher domain.EventPublisher,
+	async AsyncRunner,
+	sales SalesRecorder,
 	logger *zap.Logger,
 ) *StockService {
 	return &StockService{
 		productItemRepo: productItemRepo,
 		redisClient:     redisClient,
+		settings:        settings,
+		jobs:            jobEnqueuer,
+		events:          productItemEvents{publisher: eventPublisher, async: async},
+		sales:           sales,
 		logger:          logger,
 	}
 }
 
+// reservationTTL returns how long reservations are held (admin setting product.reservation_ttl_minutes, default 15)
+func (s *StockService) reservationTTL() time.Duration {
+	minutes := s.settings.GetInt("product", "reservation_ttl_minutes", 15)
+	if minutes <= 0 {
+		minutes = 15
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// reservationDeadlinesKey is the Redis sorted set of orders holding reservations, scored by
+// their expiry (unix seconds). Reservation keys carry no TTL: a key vanishing on its own
+// would leave its units counted in stock:reserved:<item> forever. Reservations end only
+// through releaseReservations, on release, deduction, the expiry job or the sweep
+const reservationDeadlinesKey = "stock:reservation_deadlines"
+
+// reservationSweepBatch bounds the orders released per sweep run
+const reservationSweepBatch = 500
+
+// reserveScript atomically reserves one item of an order:
+// KEYS[1] reserved counter, KEYS[2] reservation key, KEYS[3] order reservation set,
+// KEYS[4] reservation deadlines
+// ARGV[1] quantity, ARGV[2] on-hand stock, ARGV[3] reservation JSON, ARGV[4] expiry
+// (unix seconds), ARGV[5] order ID
+// Returns {1, available} on success (also when already reserved), {0, available} when short
+var reserveScript = redis.NewScript(`
+local qty = tonumber(ARGV[1])
+local reserved = tonumber(redis.call('GET', KEYS[1]) or '0')
+local available = tonumber(ARGV[2]) - reserved
+if redis.call('EXISTS', KEYS[2]) == 1 then
+	return {1, available}
+end
+if qty > available then
+	return {0, available}
+end
+redis.call('INCRBY', KEYS[1], qty)
+redis.call('SET', KEYS[2], ARGV[3])
+redis.call('SADD', KEYS[3], KEYS[2])
+redis.call('ZADD', KEYS[4], ARGV[4], ARGV[5])
+return {1, available - qty}
+`)
+
+// unreserveScript releases one reservation key exactly once:
+// KEYS[1] reserved counter, KEYS[2] reservation key, ARGV[1] quantity
+var unreserveScript = redis.NewScript(`
+if redis.call('DEL', KEYS[2]) == 0 then
+	return 0
+end
+if redis.call('DECRBY', KEYS[1], ARGV[1]) <= 0 then
+	redis.call('DEL', KEYS[1])
+end
+return 1
+`)
+
+// reservedCounterKey is the Redis counter of units currently reserved for a product item
+func reservedCounterKey(productItemID uint) string {
+	return fmt.Sprintf("stock:reserved:%d", productItemID)
+}
+
+// reservedQuantity returns how many units of a product item are held by open reservations
+func (s *StockService) reservedQuantity(ctx context.Context, productItemID uint) (int, error) {
+	reserved, err := s.redisClient.Get(ctx, reservedCounterKey(productItemID)).Int()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return reserved, err
+}
+
 // CheckStock checks if stock is available for given items
+// Available stock is on-hand (qty_in_stock) minus units held by active reservations
 func (s *StockService) CheckStock(ctx context.Context, req *domain.StockCheckRequest) (*domain.StockCheckResponse, error) {
 	unavailableItems := []domain.UnavailableStockItem{}
 
 	for _, item := range req.Items {
 		// Get product item
-		productItem, err := s.productItemRepo.GetByID(item.ProductItemID)
+		productItem, err := s.productItemRepo.GetByID(ctx, item.ProductItemID)
 		if err != nil {
 			s.logger.Error("failed to get product item", zap.Uint("product_item_id", item.ProductItemID), zap.Error(err))
 			unavailableItems = append(unavailableItems, domain.UnavailableStockItem{
@@ -50,12 +163,18 @@ func (s *StockService) CheckStock(ctx context.Context, req *domain.StockCheckReq
 			continue
 		}
 
+		reserved, err := s.reservedQuantity(ctx, item.ProductItemID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read reserved stock: %w", err)
+		}
+
 		// Check if enough stock
-		if productItem.QtyInStock < item.Quantity {
+		available := productItem.QtyInStock - reserved
+		if available < item.Quantity {
 			unavailableItems = append(unavailableItems, domain.UnavailableStockItem{
 				ProductItemID: item.ProductItemID,
 				Requested:     item.Quantity,
-				Available:     productItem.QtyInStock,
+				Available:     max(available, 0),
 			})
 		}
 	}
@@ -67,74 +186,169 @@ func (s *StockService) CheckStock(ctx context.Context, req *domain.StockCheckReq
 }
 
 // ReserveStock temporarily reserves stock for an order (stores in Redis)
-// This prevents overselling during checkout flow
+// Each item is checked against on-hand minus reserved and reserved in one atomic
+// script, so concurrent checkouts cannot both take the last units.
+// If any item is short, items reserved earlier in the call are released again
 func (s *StockService) ReserveStock(ctx context.Context, req *domain.StockReserveRequest) error {
 	// Validate order_id
 	if req.OrderID == "" {
-		return errors.New("order_id is required")
+		return domain.Validation("order_id is required")
 	}
 
-	// Check stock availability first
-	checkReq := &domain.StockCheckRequest{Items: []domain.StockCheckItem{}}
-	for _, item := range req.Items {
-		checkReq.Items = append(checkReq.Items, domain.StockCheckItem{
-			ProductItemID: item.ProductItemID,
-			Quantity:      item.Quantity,
-		})
-	}
-
-	checkResp, err := s.CheckStock(ctx, checkReq)
-	if err != nil {
-		return fmt.Errorf("failed to check stock: %w", err)
-	}
-	if !checkResp.Available {
-		return fmt.Errorf("insufficient stock: %v", checkResp.UnavailableItems)
-	}
+	// Reserve each item in Redis (with TTL from settings, default 15 minutes)
+	ttl := s.reservationTTL()
+	expiresAt := time.Now().Add(ttl)
+	setKey := reservationSetKey(req.OrderID)
+	unavailableItems := []domain.UnavailableStockItem{}
 
-	// Reserve each item in Redis (with TTL = 15 minutes)
-	expiresAt := time.Now().Add(15 * time.Minute)
 	for _, item := range req.Items {
+		productItem, err := s.productItemRepo.GetByID(ctx, item.ProductItemID)
+		if err != nil {
+			unavailableItems = append(unavailableItems, domain.UnavailableStockItem{
+				ProductItemID: item.ProductItemID,
+				Requested:     item.Quantity,
+				Available:     0,
+			})
+			continue
+		}
+
 		reservation := &domain.StockReservation{
 			OrderID:       req.OrderID,
 			ProductItemID: item.ProductItemID,
 			Quantity:      item.Quantity,
 			ExpiresAt:     expiresAt,
 		}
-
-		// Store in Redis
-		key := fmt.Sprintf("stock:reservation:%s:%d", req.OrderID, item.ProductItemID)
 		data, err := json.Marshal(reservation)
 		if err != nil {
 			s.logger.Error("failed to marshal reservation", zap.Error(err))
 			continue
 		}
 
-		if err := s.redisClient.Set(ctx, key, data, 15*time.Minute).Err(); err != nil {
+		key := reservationKey(req.OrderID, item.ProductItemID)
+		res, err := reserveScript.Run(ctx, s.redisClient,
+			[]string{reservedCounterKey(item.ProductItemID), key, setKey, reservationDeadlinesKey},
+			item.Quantity, productItem.QtyInStock, data, expiresAt.Unix(), req.OrderID,
+		).Int64Slice()
+		if err != nil {
 			s.logger.Error("failed to store reservation", zap.String("key", key), zap.Error(err))
+			_ = s.releaseReservations(ctx, req.OrderID)
 			return fmt.Errorf("failed to reserve stock: %w", err)
 		}
+		if res[0] == 0 {
+			unavailableItems = append(unavailableItems, domain.UnavailableStockItem{
+				ProductItemID: item.ProductItemID,
+				Requested:     item.Quantity,
+				Available:     max(int(res[1]), 0),
+			})
+			continue
+		}
 
 		s.logger.Info("stock reserved",
 			zap.String("order_id", req.OrderID),
 			zap.Uint("product_item_id", item.ProductItemID),
 			zap.Int("quantity", item.Quantity),
+			zap.Int64("available_after", res[1]),
 		)
 	}
 
+	if len(unavailableItems) > 0 {
+		if err := s.releaseReservations(ctx, req.OrderID); err != nil {
+			s.logger.Warn("failed to roll back partial reservation", zap.String("order_id", req.OrderID), zap.Error(err))
+		}
+		return domain.Conflict("insufficient stock: %v", unavailableItems)
+	}
+
+	// Schedule the release at expiry (the sweep releases it if this job is lost or late)
+	if _, err := s.jobs.Enqueue(ctx, JobTypeReservationExpiry,
+		ReservationExpiryPayload{OrderID: req.OrderID},
+		jobs.WithID(reservationExpiryJobID(req.OrderID)),
+		jobs.ProcessAt(expiresAt),
+	); err != nil && !errors.Is(err, jobs.ErrDuplicateJob) {
+		s.logger.Warn("failed to schedule reservation expiry", zap.String("order_id", req.OrderID), zap.Error(err))
+	}
+
 	return nil
 }
 
+// HandleReservationExpiry is the job handler for JobTypeReservationExpiry
+func (s *StockService) HandleReservationExpiry(ctx context.Context, payload []byte) error {
+	var p ReservationExpiryPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return fmt.Errorf("invalid reservation expiry payload: %w", err)
+	}
+
+	s.logger.Info("reservation hold expired, releasing stock", zap.String("order_id", p.OrderID))
+	return s.releaseReservations(ctx, p.OrderID)
+}
+
+// HandleReservationSweep is the periodic job handler for JobTypeReservationSweep
+// It releases the reservations of orders past their expiry whose expiry job did not run
+// (enqueue failed, job lost or dead) or is running late
+func (s *StockService) HandleReservationSweep(ctx context.Context, _ []byte) error {
+	orderIDs, err := s.redisClient.ZRangeByScore(ctx, reservationDeadlinesKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   fmt.Sprintf("%d", time.Now().Unix()),
+		Count: reservationSweepBatch,
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("failed to find expired reservations: %w", err)
+	}
+
+	for _, orderID := range orderIDs {
+		if err := s.releaseReservations(ctx, orderID); err != nil {
+			return err
+		}
+	}
+	if len(orderIDs) > 0 {
+		s.logger.Warn("released reservations missed by their expiry job", zap.Int("orders", len(orderIDs)))
+	}
+	return nil
+}
+
+func reservationExpiryJobID(orderID string) string {
+	return "reservation_expiry:" + orderID
+}
+
+// reservationKey is the Redis key holding one reserved item of an order
+func reservationKey(orderID string, productItemID uint) string {
+	return fmt.Sprintf("stock:reservation:%s:%d", orderID, productItemID)
+}
+
+// reservationSetKey is the Redis set listing an order's reservation keys
+func reservationSetKey(orderID string) string {
+	return fmt.Sprintf("stock:reservations:%s", orderID)
+}
+
 // DeductStock permanently deducts stock from product_item.qty_in_stock
-// This should be called after payment is confirmed
+// This should be called after payment is confirmed. Idempotent per (order, item): each
+// deduction is marked in Redis first, so a retried call does not take the stock twice
+// Deducted units count as sold for the bestseller rankings
 func (s *StockService) DeductStock(ctx context.Context, req *domain.StockDeductRequest) error {
 	// Validate order_id
 	if req.OrderID == "" {
-		return errors.New("order_id is required")
+		return domain.Validation("order_id is required")
 	}
 
-	// Deduct each item with distributed lock
+	// Deduct each item with a conditional update (see deductStock)
 	for _, item := range req.Items {
-		if err := s.deductStockWithLock(ctx, item.ProductItemID, item.Quantity); err != nil {
+		key := deductionKey(req.OrderID, item.ProductItemID)
+		claimed, err := s.redisClient.SetNX(ctx, key, item.Quantity, deductionMarkerTTL).Result()
+		if err != nil {
+			return fmt.Errorf("failed to mark stock deduction: %w", err)
+		}
+		if !claimed {
+			s.logger.Info("stock already deducted for order",
+				zap.String("order_id", req.OrderID),
+				zap.Uint("product_item_id", item.ProductItemID),
+			)
+			continue
+		}
+
+		if err := s.deductStock(ctx, item.ProductItemID, item.Quantity); err != nil {
+			// Not deducted - let a retry take it
+			if delErr := s.redisClient.Del(ctx, key).Err(); delErr != nil {
+				s.logger.Warn("failed to clear stock deduction mark", zap.String("key", key), zap.Error(delErr))
+			}
 			s.logger.Error("failed to deduct stock",
 				zap.Uint("product_item_id", item.ProductItemID),
 				zap.Int("quantity", item.Quantity),
@@ -153,70 +367,42 @@ func (s *StockService) DeductStock(ctx context.Context, req *domain.StockDeductR
 	return nil
 }
 
-// deductStockWithLock deducts stock with Redis distributed lock to prevent race condition
-func (s *StockService) deductStockWithLock(ctx context.Context, productItemID uint, quantity int) error {
-	lockKey := fmt.Sprintf("stock:lock:%d", productItemID)
-	lockValue := fmt.Sprintf("%d-%d", time.Now().UnixNano(), productItemID)
-	lockTTL := 30 * time.Second
-
-	// Acquire lock with retry (max 3 attempts)
-	var locked bool
-	for i := 0; i < 3; i++ {
-		locked, err := s.redisClient.SetNX(ctx, lockKey, lockValue, lockTTL).Result()
-		if err != nil {
-			s.logger.Error("failed to acquire lock", zap.String("key", lockKey), zap.Error(err))
-			time.Sleep(100 * time.Millisecond)
-			continue
-		}
-		if locked {
-			break
-		}
-		// Lock already held by another process, wait and retry
-		time.Sleep(100 * time.Millisecond)
-	}
+// deductionMarkerTTL is how long a deduction is remembered; retries of an order's
+// deduction happen within minutes, so this only bounds the number of marker keys
+const deductionMarkerTTL = 7 * 24 * time.Hour
 
-	if !locked {
-		return errors.New("failed to acquire lock after retries")
-	}
-
-	// Ensure lock is released even if error occurs
-	defer func() {
-		// Release lock
-		if err := s.redisClient.Del(ctx, lockKey).Err(); err != nil {
-			s.logger.Warn("failed to release lock", zap.String("key", lockKey), zap.Error(err))
-		}
-	}()
+// deductionKey is the Redis key marking an order's item as deducted
+func deductionKey(orderID string, productItemID uint) string {
+	return fmt.Sprintf("stock:deducted:%s:%d", orderID, productItemID)
+}
 
-	// Get current stock
-	productItem, err := s.productItemRepo.GetByID(productItemID)
+// deductStock takes quantity units of a product item in one conditional UPDATE:
+// the check and the decrement cannot interleave with another deduction, so no
+// lock is needed and two payments can never both take the last unit
+func (s *StockService) deductStock(ctx context.Context, productItemID uint, quantity int) error {
+	productItem, deducted, err := s.productItemRepo.DeductStock(ctx, productItemID, quantity)
 	if err != nil {
-		return fmt.Errorf("product item not found: %w", err)
-	}
-
-	// Check if enough stock
-	if productItem.QtyInStock < quantity {
-		return fmt.Errorf("insufficient stock: requested %d, available %d", quantity, productItem.QtyInStock)
-	}
-
-	// Deduct stock (atomic operation)
-	newStock := productItem.QtyInStock - quantity
-	if err := s.productItemRepo.UpdateStock(productItemID, newStock); err != nil {
 		return fmt.Errorf("failed to update stock: %w", err)
 	}
-
-	// Update status if out of stock
-	if newStock == 0 {
-		productItem.Status = "OUT_OF_STOCK"
-		if err := s.productItemRepo.Update(productItem); err != nil {
-			s.logger.Warn("failed to update status to OUT_OF_STOCK", zap.Uint("product_item_id", productItemID), zap.Error(err))
+	if !deducted {
+		// Nothing changed - tell a missing SKU from a short one
+		current, err := s.productItemRepo.GetByID(ctx, productItemID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.NotFound("product item not found")
+			}
+			return fmt.Errorf("failed to get product item: %w", err)
 		}
+		return domain.Conflict("insufficient stock: requested %d, available %d", quantity, current.QtyInStock)
 	}
 
 	s.logger.Info("stock deducted",
 		zap.Uint("product_item_id", productItemID),
 		zap.Int("quantity", quantity),
-		zap.Int("new_stock", newStock),
+		zap.Int("new_stock", productItem.QtyInStock),
 	)
+	s.events.publishChange(ctx, productItem, productItem.Price, productItem.QtyInStock+quantity)
+	s.sales.RecordSale(productItem.ProductID, quantity)
 
 	return nil
 }
@@ -226,31 +412,73 @@ func (s *StockService) deductStockWithLock(ctx context.Context, productItemID ui
 func (s *StockService) ReleaseStock(ctx context.Context, req *domain.StockReleaseRequest) error {
 	// Validate order_id
 	if req.OrderID == "" {
-		return errors.New("order_id is required")
+		return domain.Validation("order_id is required")
 	}
 
-	// Find and delete all reservations for this order
-	pattern := fmt.Sprintf("stock:reservation:%s:*", req.OrderID)
-	keys, err := s.redisClient.Keys(ctx, pattern).Result()
+	// Released before expiry - the scheduled expiry job is no longer needed
+	if err := s.jobs.Cancel(ctx, reservationExpiryJobID(req.OrderID)); err != nil {
+		s.logger.Warn("failed to cancel reservation expiry job", zap.String("order_id", req.OrderID), zap.Error(err))
+	}
+
+	return s.releaseReservations(ctx, req.OrderID)
+}
+
+// releaseReservations deletes all reservation keys of an order and gives their
+// quantities back to the reserved counters
+// Keys come from the order's reservation set, so this is O(items) instead of a KEYS scan
+func (s *StockService) releaseReservations(ctx context.Context, orderID string) error {
+	setKey := reservationSetKey(orderID)
+	keys, err := s.redisClient.SMembers(ctx, setKey).Result()
 	if err != nil {
-		s.logger.Error("failed to find reservations", zap.String("order_id", req.OrderID), zap.Error(err))
+		s.logger.Error("failed to find reservations", zap.String("order_id", orderID), zap.Error(err))
 		return fmt.Errorf("failed to find reservations: %w", err)
 	}
 
 	if len(keys) == 0 {
-		s.logger.Warn("no reservations found for order", zap.String("order_id", req.OrderID))
-		return nil // No reservations to release
+		s.logger.Warn("no reservations found for order", zap.String("order_id", orderID))
+		return s.redisClient.ZRem(ctx, reservationDeadlinesKey, orderID).Err() // No reservations to release
+	}
+
+	values, err := s.redisClient.MGet(ctx, keys...).Result()
+	if err != nil {
+		return fmt.Errorf("failed to load reservations: %w", err)
+	}
+
+	released := 0
+	for i, v := range values {
+		raw, ok := v.(string)
+		if !ok {
+			continue // already released
+		}
+		var reservation domain.StockReservation
+		if err := json.Unmarshal([]byte(raw), &reservation); err != nil {
+			s.logger.Warn("invalid reservation data", zap.String("key", keys[i]), zap.Error(err))
+			continue
+		}
+
+		n, err := unreserveScript.Run(ctx, s.redisClient,
+			[]string{reservedCounterKey(reservation.ProductItemID), keys[i]},
+			reservation.Quantity,
+		).Int()
+		if err != nil {
+			s.logger.Error("failed to release reservation", zap.String("key", keys[i]), zap.Error(err))
+			return fmt.Errorf("failed to release reservations: %w", err)
+		}
+		released += n
 	}
 
-	// Delete all reservation keys
-	if err := s.redisClient.Del(ctx, keys...).Err(); err != nil {
-		s.logger.Error("failed to delete reservations", zap.String("order_id", req.OrderID), zap.Error(err))
-		return fmt.Errorf("failed to release reservations: %w", err)
+	// The order leaves the deadlines only once its keys are gone, so a failed release
+	// is retried by the sweep
+	if err := s.redisClient.Del(ctx, setKey).Err(); err != nil {
+		s.logger.Warn("failed to delete reservation set", zap.String("order_id", orderID), zap.Error(err))
+	}
+	if err := s.redisClient.ZRem(ctx, reservationDeadlinesKey, orderID).Err(); err != nil {
+		s.logger.Warn("failed to clear reservation deadline", zap.String("order_id", orderID), zap.Error(err))
 	}
 
 	s.logger.Info("stock reservations released",
-		zap.String("order_id", req.OrderID),
-		zap.Int("count", len(keys)),
+		zap.String("order_id", orderID),
+		zap.Int("count", released),
 	)
 
 	return nil
@@ -258,24 +486,35 @@ func (s *StockService) ReleaseStock(ctx context.Context, req *domain.StockReleas
 
 // GetStock retrieves current stock for a product item
 func (s *StockService) GetStock(ctx context.Context, productItemID uint) (int, error) {
-	productItem, err := s.productItemRepo.GetByID(productItemID)
+	productItem, err := s.productItemRepo.GetByID(ctx, productItemID)
 	if err != nil {
-		return 0, fmt.Errorf("product item not found: %w", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, domain.NotFound("product item not found")
+		}
+		return 0, fmt.Errorf("failed to get product item: %w", err)
 	}
 
 	return productItem.QtyInStock, nil
 }
 
+// GetReservedStock returns units of a product item held by active reservations
+func (s *StockService) GetReservedStock(ctx context.Context, productItemID uint) (int, error) {
+	return s.reservedQuantity(ctx, productItemID)
+}
+
 // UpdateStock updates the stock quantity for a product item
 // This is for shop owners to update their stock
 func (s *StockService) UpdateStock(ctx context.Context, productItemID uint, newStock int) error {
 	if newStock < 0 {
-		return errors.New("stock cannot be negative")
+		return domain.Validation("stock cannot be negative")
 	}
 
-	productItem, err := s.productItemRepo.GetByID(productItemID)
+	productItem, err := s.productItemRepo.GetByID(ctx, productItemID)
 	if err != nil {
-		return fmt.Errorf("product item not found: %w", err)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.NotFound("product item not found")
+		}
+		return fmt.Errorf("failed to get product item: %w", err)
 	}
 
 	// Update stock with lock
@@ -287,28 +526,25 @@ func (s *StockService) UpdateStock(ctx context.Context, productItemID uint, newS
 	}
 	defer s.redisClient.Del(ctx, lockKey)
 
-	// Update stock
-	if err := s.productItemRepo.UpdateStock(productItemID, newStock); err != nil {
-		return fmt.Errorf("failed to update stock: %w", err)
-	}
+	previousStock := productItem.QtyInStock
 
-	// Update status based on stock
+	// Update stock and status together; fails with domain.ErrVersionConflict if the
+	// SKU changed since it was read (e.g. a concurrent seller edit)
+	productItem.QtyInStock = newStock
 	if newStock == 0 && productItem.Status != "OUT_OF_STOCK" {
 		productItem.Status = "OUT_OF_STOCK"
-		if err := s.productItemRepo.Update(productItem); err != nil {
-			s.logger.Warn("failed to update status", zap.Error(err))
-		}
 	} else if newStock > 0 && productItem.Status == "OUT_OF_STOCK" {
 		productItem.Status = "ACTIVE"
-		if err := s.productItemRepo.Update(productItem); err != nil {
-			s.logger.Warn("failed to update status", zap.Error(err))
-		}
+	}
+	if err := s.productItemRepo.Update(ctx, productItem); err != nil {
+		return fmt.Errorf("failed to update stock: %w", err)
 	}
 
 	s.logger.Info("stock updated",
 		zap.Uint("product_item_id", productItemID),
 		zap.Int("new_stock", newStock),
 	)
+	s.events.publishChange(ctx, productItem, productItem.Price, previousStock)
 
 	return nil
 }
@@ -0,0 +1,357 @@
+//go:build integration
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"product-service/internal/domain"
+	"product-service/pkg/jobs"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Run with: TEST_REDIS_ADDR=localhost:6379 go test -tags integration ./internal/service
+// (docker compose -f docker-compose.test.yml up -d starts one)
+func openTestRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	addr := os.Getenv("TEST_REDIS_ADDR")
+	if addr == "" {
+		t.Skip("TEST_REDIS_ADDR is not set")
+	}
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+type defaultSettings struct{}
+
+func (defaultSettings) GetInt(scope, key string, def int) int { return def }
+
+// fakeJobs records the expiry jobs scheduled and cancelled by the stock service
+type fakeJobs struct {
+	mu        sync.Mutex
+	scheduled map[string]bool
+}
+
+func (j *fakeJobs) Enqueue(ctx context.Context, jobType string, payload interface{}, opts ...jobs.Option) (*jobs.Job, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	orderID := payload.(ReservationExpiryPayload).OrderID
+	j.scheduled[reservationExpiryJobID(orderID)] = true
+	return &jobs.Job{}, nil
+}
+
+func (j *fakeJobs) Cancel(ctx context.Context, id string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	delete(j.scheduled, id)
+	return nil
+}
+
+func (j *fakeJobs) isScheduled(orderID string) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.scheduled[reservationExpiryJobID(orderID)]
+}
+
+// reservationFixture is a stock service on a real Redis with in-memory SKUs; item IDs and
+// order IDs are unique per test so runs do not see each other's counters
+type reservationFixture struct {
+	service *StockService
+	repo    *fakeProductItemRepo
+	jobs    *fakeJobs
+	redis   *redis.Client
+	prefix  string
+}
+
+func newReservationFixture(t *testing.T, stock ...int) (*reservationFixture, []uint) {
+	t.Helper()
+	client := openTestRedis(t)
+	base := uint(time.Now().UnixNano()%1_000_000_000) * 10
+	repo := &fakeProductItemRepo{items: map[uint]*domain.ProductItem{}}
+	ids := make([]uint, len(stock))
+	for i, qty := range stock {
+		ids[i] = base + uint(i)
+		repo.items[ids[i]] = &domain.ProductItem{ID: ids[i], ProductID: 1, QtyInStock: qty, Status: domain.ProductItemStatusActive, Version: 1}
+	}
+	f := &reservationFixture{
+		repo:   repo,
+		jobs:   &fakeJobs{scheduled: map[string]bool{}},
+		redis:  client,
+		prefix: fmt.Sprintf("it-%d", base),
+	}
+	f.service = &StockService{
+		productItemRepo: repo,
+		redisClient:     client,
+		settings:        defaultSettings{},
+		jobs:            f.jobs,
+		sales:           &fakeSalesRecorder{},
+		logger:          zap.NewNop(),
+	}
+	t.Cleanup(func() {
+		ctx := context.Background()
+		for _, id := range ids {
+			client.Del(ctx, reservedCounterKey(id))
+		}
+		keys, _ := client.Keys(ctx, "stock:*"+f.prefix+"*").Result()
+		if len(keys) > 0 {
+			client.Del(ctx, keys...)
+		}
+		members, _ := client.ZRange(ctx, reservationDeadlinesKey, 0, -1).Result()
+		for _, m := range members {
+			if strings.HasPrefix(m, f.prefix) {
+				client.ZRem(ctx, reservationDeadlinesKey, m)
+			}
+		}
+	})
+	return f, ids
+}
+
+func (f *reservationFixture) orderID(name string) string {
+	return f.prefix + "-" + name
+}
+
+func (f *reservationFixture) reserve(t *testing.T, orderID string, items map[uint]int) error {
+	t.Helper()
+	req := &domain.StockReserveRequest{OrderID: orderID}
+	for id, qty := range items {
+		req.Items = append(req.Items, domain.StockReserveItem{ProductItemID: id, Quantity: qty})
+	}
+	return f.service.ReserveStock(context.Background(), req)
+}
+
+func (f *reservationFixture) reserved(t *testing.T, id uint) int {
+	t.Helper()
+	n, err := f.service.GetReservedStock(context.Background(), id)
+	if err != nil {
+		t.Fatalf("reserved stock of %d: %v", id, err)
+	}
+	return n
+}
+
+// assertReleased checks that nothing of the order is left in Redis
+func (f *reservationFixture) assertReleased(t *testing.T, orderID string) {
+	t.Helper()
+	ctx := context.Background()
+	if n, _ := f.redis.Exists(ctx, reservationSetKey(orderID)).Result(); n != 0 {
+		t.Errorf("reservation set of %s still exists", orderID)
+	}
+	if _, err := f.redis.ZScore(ctx, reservationDeadlinesKey, orderID).Result(); err != redis.Nil {
+		t.Errorf("deadline of %s still tracked (err = %v)", orderID, err)
+	}
+}
+
+// TestReservationLifecycle follows a reservation through each way it can end and checks
+// the reserved counters go back to what the other orders hold
+func TestReservationLifecycle(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reserve then confirm", func(t *testing.T) {
+		f, ids := newReservationFixture(t, 10, 5)
+		order := f.orderID("confirm")
+		if err := f.reserve(t, order, map[uint]int{ids[0]: 3, ids[1]: 2}); err != nil {
+			t.Fatalf("reserve: %v", err)
+		}
+		if got := f.reserved(t, ids[0]); got != 3 {
+			t.Fatalf("reserved = %d, want 3", got)
+		}
+		if !f.jobs.isScheduled(order) {
+			t.Fatal("expiry job not scheduled")
+		}
+
+		err := f.service.DeductStock(ctx, &domain.StockDeductRequest{OrderID: order, Items: []domain.StockDeductItem{
+			{ProductItemID: ids[0], Quantity: 3},
+			{ProductItemID: ids[1], Quantity: 2},
+		}})
+		if err != nil {
+			t.Fatalf("deduct: %v", err)
+		}
+		for i, want := range []int{7, 3} {
+			item, _ := f.repo.GetByID(ctx, ids[i])
+			if item.QtyInStock != want {
+				t.Errorf("item %d stock = %d, want %d", i, item.QtyInStock, want)
+			}
+			if got := f.reserved(t, ids[i]); got != 0 {
+				t.Errorf("item %d reserved = %d after confirmation, want 0", i, got)
+			}
+		}
+		if f.jobs.isScheduled(order) {
+			t.Error("expiry job still scheduled after confirmation")
+		}
+		f.assertReleased(t, order)
+	})
+
+	t.Run("reserve then release", func(t *testing.T) {
+		f, ids := newReservationFixture(t, 10)
+		order, other := f.orderID("release"), f.orderID("other")
+		if err := f.reserve(t, order, map[uint]int{ids[0]: 4}); err != nil {
+			t.Fatalf("reserve: %v", err)
+		}
+		if err := f.reserve(t, other, map[uint]int{ids[0]: 1}); err != nil {
+			t.Fatalf("reserve other order: %v", err)
+		}
+
+		if err := f.service.ReleaseStock(ctx, &domain.StockReleaseRequest{OrderID: order}); err != nil {
+			t.Fatalf("release: %v", err)
+		}
+		if got := f.reserved(t, ids[0]); got != 1 {
+			t.Errorf("reserved = %d, want the other order's 1", got)
+		}
+		if item, _ := f.repo.GetByID(ctx, ids[0]); item.QtyInStock != 10 {
+			t.Errorf("stock = %d, a release must not deduct", item.QtyInStock)
+		}
+		if f.jobs.isScheduled(order) {
+			t.Error("expiry job still scheduled after release")
+		}
+		f.assertReleased(t, order)
+	})
+
+	t.Run("reserve then expire", func(t *testing.T) {
+		f, ids := newReservationFixture(t, 10)
+		order := f.orderID("expire")
+		if err := f.reserve(t, order, map[uint]int{ids[0]: 6}); err != nil {
+			t.Fatalf("reserve: %v", err)
+		}
+
+		payload, _ := json.Marshal(ReservationExpiryPayload{OrderID: order})
+		if err := f.service.HandleReservationExpiry(ctx, payload); err != nil {
+			t.Fatalf("expiry job: %v", err)
+		}
+		if got := f.reserved(t, ids[0]); got != 0 {
+			t.Errorf("reserved = %d after expiry, want 0", got)
+		}
+		f.assertReleased(t, order)
+		if err := f.reserve(t, f.orderID("next"), map[uint]int{ids[0]: 10}); err != nil {
+			t.Errorf("expired units not available again: %v", err)
+		}
+	})
+
+	t.Run("expiry job missed, swept", func(t *testing.T) {
+		f, ids := newReservationFixture(t, 10)
+		overdue, live := f.orderID("overdue"), f.orderID("live")
+		if err := f.reserve(t, overdue, map[uint]int{ids[0]: 2}); err != nil {
+			t.Fatalf("reserve: %v", err)
+		}
+		if err := f.reserve(t, live, map[uint]int{ids[0]: 3}); err != nil {
+			t.Fatalf("reserve live order: %v", err)
+		}
+		// The expiry job of overdue never ran: move its deadline into the past
+		past := float64(time.Now().Add(-time.Minute).Unix())
+		if err := f.redis.ZAdd(ctx, reservationDeadlinesKey, redis.Z{Score: past, Member: overdue}).Err(); err != nil {
+			t.Fatalf("backdate deadline: %v", err)
+		}
+
+		if err := f.service.HandleReservationSweep(ctx, nil); err != nil {
+			t.Fatalf("sweep: %v", err)
+		}
+		if got := f.reserved(t, ids[0]); got != 3 {
+			t.Errorf("reserved = %d, want only the live order's 3", got)
+		}
+		f.assertReleased(t, overdue)
+		if ttl, _ := f.redis.TTL(ctx, reservationKey(live, ids[0])).Result(); ttl != -1 {
+			t.Errorf("live reservation key TTL = %s, want none (released only by the job or sweep)", ttl)
+		}
+	})
+
+	t.Run("double release is idempotent", func(t *testing.T) {
+		f, ids := newReservationFixture(t, 10)
+		order, other := f.orderID("twice"), f.orderID("other")
+		if err := f.reserve(t, order, map[uint]int{ids[0]: 4}); err != nil {
+			t.Fatalf("reserve: %v", err)
+		}
+		if err := f.reserve(t, other, map[uint]int{ids[0]: 5}); err != nil {
+			t.Fatalf("reserve other order: %v", err)
+		}
+
+		payload, _ := json.Marshal(ReservationExpiryPayload{OrderID: order})
+		for i, release := range []func() error{
+			func() error { return f.service.ReleaseStock(ctx, &domain.StockReleaseRequest{OrderID: order}) },
+			func() error { return f.service.ReleaseStock(ctx, &domain.StockReleaseRequest{OrderID: order}) },
+			func() error { return f.service.HandleReservationExpiry(ctx, payload) },
+		} {
+			if err := release(); err != nil {
+				t.Fatalf("release %d: %v", i+1, err)
+			}
+			if got := f.reserved(t, ids[0]); got != 5 {
+				t.Fatalf("reserved = %d after release %d, want the other order's 5", got, i+1)
+			}
+		}
+	})
+}
+
+// TestReserveStockConcurrentNoOverbooking races many checkouts for one unit each of a SKU
+// with little stock: exactly the stock is reserved and the reserved counter never exceeds it
+func TestReserveStockConcurrentNoOverbooking(t *testing.T) {
+	const (
+		stock  = 20
+		buyers = 100
+	)
+	f, ids := newReservationFixture(t, stock)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Watch the counter while the checkouts run
+	var peak atomic.Int64
+	watched := make(chan struct{})
+	go func() {
+		defer close(watched)
+		for ctx.Err() == nil {
+			n, err := f.redis.Get(ctx, reservedCounterKey(ids[0])).Int64()
+			if err == nil && n > peak.Load() {
+				peak.Store(n)
+			}
+		}
+	}()
+
+	var (
+		succeeded atomic.Int64
+		wg        sync.WaitGroup
+		start     = make(chan struct{})
+		errs      = make(chan error, buyers)
+	)
+	for b := 0; b < buyers; b++ {
+		wg.Add(1)
+		go func(b int) {
+			defer wg.Done()
+			<-start
+			err := f.reserve(t, f.orderID(fmt.Sprintf("buyer-%d", b)), map[uint]int{ids[0]: 1})
+			switch {
+			case err == nil:
+				succeeded.Add(1)
+			case !errors.Is(err, domain.ErrConflict):
+				errs <- err
+			}
+		}(b)
+	}
+	close(start)
+	wg.Wait()
+	cancel()
+	<-watched
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("unexpected reservation error: %v", err)
+	}
+	if got := succeeded.Load(); got != stock {
+		t.Errorf("%d checkouts reserved a unit, want exactly the %d in stock", got, stock)
+	}
+	if got := f.reserved(t, ids[0]); got != stock {
+		t.Errorf("reserved = %d, want %d", got, stock)
+	}
+	if got := peak.Load(); got > stock {
+		t.Errorf("reserved counter reached %d, only %d in stock", got, stock)
+	}
+}
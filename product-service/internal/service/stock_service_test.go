@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"product-service/internal/domain"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// fakeProductItemRepo keeps SKUs in memory; DeductStock mirrors the conditional
+// UPDATE ... WHERE qty_in_stock >= ? of the postgres repository (row lock = mutex)
+type fakeProductItemRepo struct {
+	domain.ProductItemRepository
+
+	mu    sync.Mutex
+	items map[uint]*domain.ProductItem
+}
+
+func (r *fakeProductItemRepo) GetByID(ctx context.Context, id uint) (*domain.ProductItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.items[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *item
+	return &copied, nil
+}
+
+func (r *fakeProductItemRepo) DeductStock(ctx context.Context, id uint, quantity int) (*domain.ProductItem, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.items[id]
+	if !ok || item.QtyInStock < quantity {
+		return nil, false, nil
+	}
+	item.QtyInStock -= quantity
+	if item.QtyInStock == 0 {
+		item.Status = domain.ProductItemStatusOutOfStock
+	}
+	item.Version++
+	copied := *item
+	return &copied, true, nil
+}
+
+type fakeSalesRecorder struct {
+	sold atomic.Int64
+}
+
+func (r *fakeSalesRecorder) RecordSale(productID uint, quantity int) {
+	r.sold.Add(int64(quantity))
+}
+
+// TestDeductStockConcurrentNoOversell runs many buyers against one SKU at once:
+// the stock deducted never exceeds what was on hand and never goes negative
+func TestDeductStockConcurrentNoOversell(t *testing.T) {
+	const (
+		itemID       = uint(7)
+		initialStock = 100
+		buyers       = 64
+		perBuyer     = 5
+	)
+	quantities := []int{1, 2, 3, 5}
+
+	repo := &fakeProductItemRepo{items: map[uint]*domain.ProductItem{
+		itemID: {ID: itemID, ProductID: 1, QtyInStock: initialStock, Status: domain.ProductItemStatusActive, Version: 1},
+	}}
+	sales := &fakeSalesRecorder{}
+	s := &StockService{productItemRepo: repo, sales: sales, logger: zap.NewNop()}
+
+	var (
+		deducted  atomic.Int64
+		conflicts atomic.Int64
+		wg        sync.WaitGroup
+		errs      = make(chan error, buyers*perBuyer)
+	)
+	for b := 0; b < buyers; b++ {
+		wg.Add(1)
+		go func(b int) {
+			defer wg.Done()
+			for i := 0; i < perBuyer; i++ {
+				quantity := quantities[(b+i)%len(quantities)]
+				err := s.deductStock(context.Background(), itemID, quantity)
+				switch {
+				case err == nil:
+					deducted.Add(int64(quantity))
+				case errors.Is(err, domain.ErrConflict):
+					conflicts.Add(1)
+				default:
+					errs <- err
+				}
+				if current, _ := repo.GetByID(context.Background(), itemID); current.QtyInStock < 0 {
+					errs <- errors.New("stock went negative")
+				}
+			}
+		}(b)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("unexpected deduction error: %v", err)
+	}
+
+	item, _ := repo.GetByID(context.Background(), itemID)
+	if got := deducted.Load(); got > initialStock {
+		t.Fatalf("deducted %d units, only %d were in stock", got, initialStock)
+	}
+	if item.QtyInStock < 0 {
+		t.Fatalf("stock went negative: %d", item.QtyInStock)
+	}
+	if want := initialStock - int(deducted.Load()); item.QtyInStock != want {
+		t.Errorf("stock left = %d, want %d", item.QtyInStock, want)
+	}
+	// Demand (880 units) far exceeds the stock: once it runs low, only requests
+	// larger than what is left fail, so at most the largest quantity stays unsold
+	if item.QtyInStock >= quantities[len(quantities)-1] {
+		t.Errorf("stock left = %d although buyers kept asking", item.QtyInStock)
+	}
+	if conflicts.Load() == 0 {
+		t.Error("expected some deductions to fail with insufficient stock")
+	}
+	if sales.sold.Load() != deducted.Load() {
+		t.Errorf("recorded sales = %d, want %d", sales.sold.Load(), deducted.Load())
+	}
+}
+
+func TestDeductStockErrors(t *testing.T) {
+	repo := &fakeProductItemRepo{items: map[uint]*domain.ProductItem{
+		1: {ID: 1, ProductID: 1, QtyInStock: 2, Status: domain.ProductItemStatusActive},
+	}}
+	s := &StockService{productItemRepo: repo, sales: &fakeSalesRecorder{}, logger: zap.NewNop()}
+
+	tests := []struct {
+		name     string
+		id       uint
+		quantity int
+		want     error
+	}{
+		{"more than in stock", 1, 3, domain.ErrConflict},
+		{"unknown SKU", 2, 1, domain.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.deductStock(context.Background(), tt.id, tt.quantity)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if item, _ := repo.GetByID(context.Background(), 1); item.QtyInStock != 2 {
+		t.Errorf("failed deductions changed the stock to %d", item.QtyInStock)
+	}
+}
@@ -0,0 +1,23 @@
+-- =====================================================
+-- MIGRATION: Composite indexes for product listings
+-- Date: 2026-10-16
+-- Reason: Listing queries filter by category + status + is_active
+--         and by shop + status; single-column indexes force extra heap filtering
+-- =====================================================
+
+-- CONCURRENTLY avoids locking products for writes (cannot run inside a transaction)
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_products_category_status_active
+    ON products (category_id, status, is_active);
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_products_shop_status
+    ON products (shop_id, status);
+
+-- Verify
+-- \d products
+
+-- =====================================================
+-- ROLLBACK (if needed)
+-- =====================================================
+-- DROP INDEX CONCURRENTLY IF EXISTS idx_products_category_status_active;
+-- DROP INDEX CONCURRENTLY IF EXISTS idx_products_shop_status;
+-- =====================================================
@@ -0,0 +1,92 @@
+// Package codevault encrypts digital product codes (vouchers, game keys, gift
+// cards) at rest.
+//
+// Codes are sealed with AES-256-GCM under a key from configuration; a keyed
+// HMAC-SHA256 of the normalized code is stored next to it so duplicates can be
+// detected without decrypting the pool.
+package codevault
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoKey is returned by New when no encryption key is configured
+var ErrNoKey = errors.New("digital code encryption key is not configured")
+
+// Vault seals and opens digital codes
+type Vault struct {
+	aead    cipher.AEAD
+	hashKey []byte
+}
+
+// New creates a vault from a base64-encoded 32-byte key
+func New(encodedKey string) (*Vault, error) {
+	if strings.TrimSpace(encodedKey) == "" {
+		return nil, ErrNoKey
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encodedKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid digital code encryption key: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("invalid digital code encryption key: want 32 bytes, got %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	// Separate key for hashing so the hash never reveals anything about the cipher key
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("codevault:hash"))
+
+	return &Vault{aead: aead, hashKey: mac.Sum(nil)}, nil
+}
+
+// Encrypt seals a code; the result is base64(nonce || ciphertext)
+func (v *Vault) Encrypt(code string) (string, error) {
+	nonce := make([]byte, v.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := v.aead.Seal(nonce, nonce, []byte(code), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a code sealed by Encrypt
+func (v *Vault) Decrypt(encrypted string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted code: %w", err)
+	}
+	if len(sealed) < v.aead.NonceSize() {
+		return "", errors.New("invalid encrypted code: too short")
+	}
+	nonce, ciphertext := sealed[:v.aead.NonceSize()], sealed[v.aead.NonceSize():]
+	plain, err := v.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt code: %w", err)
+	}
+	return string(plain), nil
+}
+
+// Hash returns the hex HMAC of a code, identical for identical codes
+func (v *Vault) Hash(code string) string {
+	mac := hmac.New(sha256.New, v.hashKey)
+	mac.Write([]byte(code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"product-service/config"
 	"strings"
 	"sync"
@@ -11,6 +13,7 @@ import (
 
 	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 var (
@@ -28,9 +31,14 @@ func GetClient(cfg *config.ElasticsearchConfig) (*elasticsearch.Client, error) {
 	once.Do(func() {
 		// Configure Elasticsearch client
 		esConfig := elasticsearch.Config{
-			Addresses: cfg.Addresses,
-			Username:  cfg.Username,
-			Password:  cfg.Password,
+			Addresses:     cfg.Addresses,
+			Username:      cfg.Username,
+			Password:      cfg.Password,
+			Transport:     newTransport(cfg),
+			MaxRetries:    cfg.MaxRetries,
+			DisableRetry:  cfg.MaxRetries == 0, // the client treats 0 as "default" (3)
+			RetryBackoff:  retryBackoff(cfg.RetryBackoff),
+			EnableMetrics: true,
 		}
 
 		clientInstance, err = elasticsearch.NewClient(esConfig)
@@ -39,11 +47,17 @@ func GetClient(cfg *config.ElasticsearchConfig) (*elasticsearch.Client, error) {
 			return
 		}
 
+		// Requests and failures on /metrics
+		if regErr := prometheus.Register(newClientCollector(clientInstance)); regErr != nil {
+			log.Printf("Failed to register Elasticsearch metrics: %v", regErr)
+		}
+
 		// Test connection
 		ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
 		defer cancel()
 
-		res, err := clientInstance.Info(clientInstance.Info.WithContext(ctx))
+		var res *esapi.Response
+		res, err = clientInstance.Info(clientInstance.Info.WithContext(ctx))
 		if err != nil {
 			log.Printf("Failed to connect to Elasticsearch: %v", err)
 			return
@@ -66,6 +80,25 @@ func GetClient(cfg *config.ElasticsearchConfig) (*elasticsearch.Client, error) {
 	return clientInstance, nil
 }
 
+// newTransport bounds connecting to a node and waiting for a response, so a hung
+// node fails the request (and triggers a retry) instead of blocking it
+func newTransport(cfg *config.ElasticsearchConfig) http.RoundTripper {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: cfg.DialTimeout, KeepAlive: 30 * time.Second}).DialContext
+	transport.ResponseHeaderTimeout = cfg.Timeout
+	return transport
+}
+
+// retryBackoff doubles the wait after every failed attempt
+func retryBackoff(base time.Duration) func(attempt int) time.Duration {
+	if base <= 0 {
+		return nil
+	}
+	return func(attempt int) time.Duration {
+		return base * time.Duration(1<<(attempt-1))
+	}
+}
+
 // EnsureIndex creates the Elasticsearch index if it doesn't exist
 // This should be called at application startup
 func EnsureIndex(client *elasticsearch.Client, indexName string) error {
@@ -81,6 +114,7 @@ func EnsureIndex(client *elasticsearch.Client, indexName string) error {
 
 	if exists.StatusCode == 200 {
 		log.Printf("Index '%s' already exists", indexName)
+		ensureLocationMapping(ctx, client, indexName)
 		return nil
 	}
 
@@ -98,7 +132,10 @@ func EnsureIndex(client *elasticsearch.Client, indexName string) error {
 				"stock": { "type": "integer" },
 				"is_active": { "type": "boolean" },
 				"created_at": { "type": "date" },
-				"updated_at": { "type": "date" }
+				"updated_at": { "type": "date" },
+				"shop_id": { "type": "long" },
+				"ships_from_province": { "type": "keyword" },
+				"shop_location": { "type": "geo_point" }
 			}
 		}
 	}`
@@ -122,3 +159,28 @@ func EnsureIndex(client *elasticsearch.Client, indexName string) error {
 	return nil
 }
 
+// locationMapping holds the ships-from fields, added to indexes created before them
+const locationMapping = `{
+	"properties": {
+		"shop_id": { "type": "long" },
+		"ships_from_province": { "type": "keyword" },
+		"shop_location": { "type": "geo_point" }
+	}
+}`
+
+// ensureLocationMapping adds the ships-from fields to an existing index. It fails when a
+// product with a location was indexed before (shop_location was mapped dynamically as an
+// object); the index then has to be rebuilt for nearest-first sorting
+func ensureLocationMapping(ctx context.Context, client *elasticsearch.Client, indexName string) {
+	res, err := client.Indices.PutMapping([]string{indexName}, strings.NewReader(locationMapping), client.Indices.PutMapping.WithContext(ctx))
+	if err != nil {
+		log.Printf("Failed to add location mapping to index '%s': %v", indexName, err)
+		return
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		log.Printf("Failed to add location mapping to index '%s': %s", indexName, res.String())
+	}
+}
+
@@ -0,0 +1,36 @@
+package elasticsearch
+
+import (
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	requestsDesc = prometheus.NewDesc("elasticsearch_requests_total", "Requests sent to Elasticsearch, retries included.", nil, nil)
+	failuresDesc = prometheus.NewDesc("elasticsearch_failures_total", "Requests to Elasticsearch that failed with a network error.", nil, nil)
+)
+
+// clientCollector exports the transport metrics of an Elasticsearch client
+type clientCollector struct {
+	client *elasticsearch.Client
+}
+
+func newClientCollector(client *elasticsearch.Client) *clientCollector {
+	return &clientCollector{client: client}
+}
+
+// Describe implements prometheus.Collector
+func (c *clientCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- requestsDesc
+	ch <- failuresDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *clientCollector) Collect(ch chan<- prometheus.Metric) {
+	metrics, err := c.client.Metrics()
+	if err != nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(requestsDesc, prometheus.CounterValue, float64(metrics.Requests))
+	ch <- prometheus.MustNewConstMetric(failuresDesc, prometheus.CounterValue, float64(metrics.Failures))
+}
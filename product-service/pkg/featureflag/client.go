@@ -0,0 +1,161 @@
+package featureflag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Redis contract with identity-service (feature flag admin API)
+const (
+	featureFlagsKey            = "feature_flags"         // Hash {flag_key: flag JSON}
+	FeatureFlagsChangedChannel = "feature_flags:changed" // Pub/Sub channel, payload = flag key
+)
+
+// flag mirrors identity-service domain.FeatureFlag (only fields needed for evaluation)
+type flag struct {
+	Key               string `json:"key"`
+	Enabled           bool   `json:"enabled"`
+	RolloutPercentage int    `json:"rollout_percentage"`
+	AllowedUserIDs    []uint `json:"allowed_user_ids"`
+	AllowedShopIDs    []uint `json:"allowed_shop_ids"`
+}
+
+// Client evaluates feature flags locally from a Redis-backed in-memory copy
+// Flags are loaded on Start and reloaded when identity-service publishes a change
+// Unknown flags are treated as disabled
+type Client struct {
+	client *redis.Client
+	logger *zap.Logger
+	mu     sync.RWMutex
+	flags  map[string]*flag
+}
+
+// NewClient creates a new feature flag client
+func NewClient(client *redis.Client, logger *zap.Logger) *Client {
+	return &Client{
+		client: client,
+		logger: logger,
+		flags:  make(map[string]*flag),
+	}
+}
+
+// Start loads all flags and listens for changes until ctx is cancelled
+// Should be run in a goroutine
+func (c *Client) Start(ctx context.Context) {
+	pubsub := c.client.Subscribe(ctx, FeatureFlagsChangedChannel)
+	defer pubsub.Close()
+
+	if err := c.loadAll(ctx); err != nil {
+		c.logger.Warn("failed to load feature flags", zap.Error(err))
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-pubsub.Channel():
+			if !ok {
+				return
+			}
+			c.reload(ctx, msg.Payload)
+		}
+	}
+}
+
+// IsEnabled evaluates a flag for a user and/or shop (0 = unknown)
+// Must stay in sync with identity-service domain.FeatureFlag.IsEnabledFor
+func (c *Client) IsEnabled(key string, userID, shopID uint) bool {
+	c.mu.RLock()
+	f, ok := c.flags[key]
+	c.mu.RUnlock()
+	if !ok || !f.Enabled {
+		return false
+	}
+
+	if shopID != 0 {
+		for _, id := range f.AllowedShopIDs {
+			if id == shopID {
+				return true
+			}
+		}
+	}
+	if userID != 0 {
+		for _, id := range f.AllowedUserIDs {
+			if id == userID {
+				return true
+			}
+		}
+	}
+	if f.RolloutPercentage >= 100 {
+		return true
+	}
+	if f.RolloutPercentage <= 0 || userID == 0 {
+		return false
+	}
+	return rolloutBucket(key, userID) < f.RolloutPercentage
+}
+
+// loadAll loads every flag from the Redis hash
+func (c *Client) loadAll(ctx context.Context) error {
+	values, err := c.client.HGetAll(ctx, featureFlagsKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get feature flags: %w", err)
+	}
+
+	flags := make(map[string]*flag, len(values))
+	for key, value := range values {
+		var f flag
+		if err := json.Unmarshal([]byte(value), &f); err != nil {
+			c.logger.Warn("invalid feature flag in cache", zap.String("key", key), zap.Error(err))
+			continue
+		}
+		flags[key] = &f
+	}
+
+	c.mu.Lock()
+	c.flags = flags
+	c.mu.Unlock()
+
+	c.logger.Info("feature flags loaded", zap.Int("count", len(flags)))
+	return nil
+}
+
+// reload refreshes one flag after a change event (deleted flags are removed)
+func (c *Client) reload(ctx context.Context, key string) {
+	value, err := c.client.HGet(ctx, featureFlagsKey, key).Result()
+	if err == redis.Nil {
+		c.mu.Lock()
+		delete(c.flags, key)
+		c.mu.Unlock()
+		c.logger.Info("feature flag removed", zap.String("key", key))
+		return
+	}
+	if err != nil {
+		c.logger.Warn("failed to reload feature flag", zap.String("key", key), zap.Error(err))
+		return
+	}
+
+	var f flag
+	if err := json.Unmarshal([]byte(value), &f); err != nil {
+		c.logger.Warn("invalid feature flag in cache", zap.String("key", key), zap.Error(err))
+		return
+	}
+
+	c.mu.Lock()
+	c.flags[key] = &f
+	c.mu.Unlock()
+	c.logger.Info("feature flag reloaded", zap.String("key", key), zap.Bool("enabled", f.Enabled))
+}
+
+// rolloutBucket maps (flag, user) to a stable bucket 0-99 (fnv32a of "key:userID")
+func rolloutBucket(key string, userID uint) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(fmt.Sprintf("%s:%d", key, userID)))
+	return int(h.Sum32() % 100)
+}
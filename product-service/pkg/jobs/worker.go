@@ -0,0 +1,262 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// HandlerFunc processes one job; returning an error schedules a retry
+type HandlerFunc func(ctx context.Context, payload []byte) error
+
+// claimScript atomically moves due jobs from scheduled to active with a lease deadline
+// KEYS[1] = scheduled, KEYS[2] = active; ARGV[1] = now (ms), ARGV[2] = batch size, ARGV[3] = lease (ms)
+var claimScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, id in ipairs(ids) do
+	redis.call('ZREM', KEYS[1], id)
+	redis.call('ZADD', KEYS[2], ARGV[1] + ARGV[3], id)
+end
+return ids
+`)
+
+// requeueScript moves jobs whose lease expired (worker crashed) back to scheduled
+// KEYS[1] = active, KEYS[2] = scheduled; ARGV[1] = now (ms)
+var requeueScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+for _, id in ipairs(ids) do
+	redis.call('ZREM', KEYS[1], id)
+	redis.call('ZADD', KEYS[2], ARGV[1], id)
+end
+return #ids
+`)
+
+type periodicJob struct {
+	name     string
+	interval time.Duration
+	jobType  string
+	payload  interface{}
+}
+
+// Worker polls a namespace for due jobs and runs registered handlers
+// Multiple instances can run concurrently; claims are atomic
+type Worker struct {
+	*Client
+	logger       *zap.Logger
+	concurrency  int
+	pollInterval time.Duration
+	handlers     map[string]HandlerFunc
+	periodic     []periodicJob
+	wg           sync.WaitGroup
+}
+
+// NewWorker creates a worker for a namespace
+func NewWorker(client *redis.Client, namespace string, concurrency int, logger *zap.Logger) *Worker {
+	if concurrency <= 0 {
+		concurrency = 5
+	}
+	return &Worker{
+		Client:       NewClient(client, namespace),
+		logger:       logger,
+		concurrency:  concurrency,
+		pollInterval: time.Second,
+		handlers:     make(map[string]HandlerFunc),
+	}
+}
+
+// Register binds a handler to a job type (must be called before Start)
+func (w *Worker) Register(jobType string, handler HandlerFunc) {
+	w.handlers[jobType] = handler
+}
+
+// Every enqueues a job on a fixed interval; only one instance enqueues per tick
+func (w *Worker) Every(name string, interval time.Duration, jobType string, payload interface{}) {
+	w.periodic = append(w.periodic, periodicJob{name: name, interval: interval, jobType: jobType, payload: payload})
+}
+
+// Start processes jobs until ctx is cancelled, then waits for in-flight jobs
+// Should be run in a goroutine
+func (w *Worker) Start(ctx context.Context) {
+	for _, p := range w.periodic {
+		go w.runPeriodic(ctx, p)
+	}
+
+	sem := make(chan struct{}, w.concurrency)
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	w.logger.Info("job worker started", zap.String("namespace", w.ns), zap.Int("concurrency", w.concurrency))
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.wg.Wait()
+			w.logger.Info("job worker stopped", zap.String("namespace", w.ns))
+			return
+		case <-ticker.C:
+			w.requeueExpired(ctx)
+
+			free := w.concurrency - len(sem)
+			if free <= 0 {
+				continue
+			}
+			ids, err := w.claim(ctx, free)
+			if err != nil {
+				if ctx.Err() == nil {
+					w.logger.Warn("failed to claim jobs", zap.String("namespace", w.ns), zap.Error(err))
+				}
+				continue
+			}
+			for _, id := range ids {
+				sem <- struct{}{}
+				w.wg.Add(1)
+				go func(id string) {
+					defer func() {
+						<-sem
+						w.wg.Done()
+					}()
+					w.process(ctx, id)
+				}(id)
+			}
+		}
+	}
+}
+
+// claim takes up to n due jobs
+func (w *Worker) claim(ctx context.Context, n int) ([]string, error) {
+	now := time.Now().UnixMilli()
+	res, err := claimScript.Run(ctx, w.client, []string{w.scheduledKey(), w.activeKey()},
+		now, n, defaultTimeout.Milliseconds()).StringSlice()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// requeueExpired retries jobs abandoned by crashed workers
+func (w *Worker) requeueExpired(ctx context.Context) {
+	n, err := requeueScript.Run(ctx, w.client, []string{w.activeKey(), w.scheduledKey()}, time.Now().UnixMilli()).Int()
+	if err != nil && err != redis.Nil {
+		return
+	}
+	if n > 0 {
+		w.logger.Warn("requeued jobs with expired lease", zap.String("namespace", w.ns), zap.Int("count", n))
+	}
+}
+
+// process runs a single claimed job and records the outcome
+func (w *Worker) process(ctx context.Context, id string) {
+	job, err := w.getJob(ctx, id)
+	if err != nil {
+		w.logger.Warn("claimed job missing", zap.String("job_id", id), zap.Error(err))
+		w.client.ZRem(ctx, w.activeKey(), id)
+		return
+	}
+
+	// Extend the lease to the job's own timeout
+	w.client.ZAdd(ctx, w.activeKey(), redis.Z{Score: float64(time.Now().Add(job.Timeout).UnixMilli()), Member: id})
+
+	job.State = StateActive
+	job.Attempts++
+	_ = w.saveJob(ctx, job, 0)
+
+	err = w.run(ctx, job)
+
+	// Use a fresh context so outcome is recorded even during shutdown
+	saveCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err == nil {
+		job.State = StateCompleted
+		job.LastError = ""
+		_ = w.saveJob(saveCtx, job, completedJobTTL)
+		pipe := w.client.TxPipeline()
+		pipe.ZRem(saveCtx, w.activeKey(), id)
+		pipe.HIncrBy(saveCtx, w.statsKey(), "processed", 1)
+		_, _ = pipe.Exec(saveCtx)
+		w.logger.Debug("job completed", zap.String("job_id", id), zap.String("type", job.Type))
+		return
+	}
+
+	job.LastError = err.Error()
+	pipe := w.client.TxPipeline()
+	pipe.ZRem(saveCtx, w.activeKey(), id)
+	pipe.HIncrBy(saveCtx, w.statsKey(), "failed", 1)
+
+	if job.Attempts > job.MaxRetries {
+		job.State = StateDead
+		pipe.ZAdd(saveCtx, w.deadKey(), redis.Z{Score: float64(time.Now().UnixMilli()), Member: id})
+		pipe.HIncrBy(saveCtx, w.statsKey(), "dead", 1)
+		w.logger.Error("job moved to dead set",
+			zap.String("job_id", id),
+			zap.String("type", job.Type),
+			zap.Int("attempts", job.Attempts),
+			zap.Error(err),
+		)
+	} else {
+		job.State = StateScheduled
+		job.RunAt = time.Now().Add(retryDelay(job.Attempts))
+		pipe.ZAdd(saveCtx, w.scheduledKey(), redis.Z{Score: float64(job.RunAt.UnixMilli()), Member: id})
+		w.logger.Warn("job failed, retry scheduled",
+			zap.String("job_id", id),
+			zap.String("type", job.Type),
+			zap.Int("attempts", job.Attempts),
+			zap.Time("retry_at", job.RunAt),
+			zap.Error(err),
+		)
+	}
+	_ = w.saveJob(saveCtx, job, 0)
+	_, _ = pipe.Exec(saveCtx)
+}
+
+// run invokes the handler with timeout and panic protection
+func (w *Worker) run(ctx context.Context, job *Job) (err error) {
+	handler, ok := w.handlers[job.Type]
+	if !ok {
+		return fmt.Errorf("no handler registered for job type %q", job.Type)
+	}
+
+	jobCtx, cancel := context.WithTimeout(ctx, job.Timeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return handler(jobCtx, job.Payload)
+}
+
+// runPeriodic enqueues a periodic job; a Redis lock ensures one enqueue per interval across instances
+func (w *Worker) runPeriodic(ctx context.Context, p periodicJob) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			acquired, err := w.client.SetNX(ctx, w.periodicKey(p.name), time.Now().Unix(), p.interval).Result()
+			if err != nil || !acquired {
+				continue
+			}
+			if _, err := w.Enqueue(ctx, p.jobType, p.payload); err != nil {
+				w.logger.Warn("failed to enqueue periodic job", zap.String("name", p.name), zap.Error(err))
+			}
+		}
+	}
+}
+
+// retryDelay is a quadratic backoff: 10s, 40s, 90s, ... capped at 1 hour
+func retryDelay(attempt int) time.Duration {
+	d := time.Duration(attempt*attempt) * 10 * time.Second
+	if d > time.Hour {
+		return time.Hour
+	}
+	return d
+}
@@ -0,0 +1,106 @@
+package order_client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"product-service/pkg/serviceauth"
+	"time"
+)
+
+// OrderClient handles communication with Order Service
+type OrderClient struct {
+	baseURL    string
+	httpClient *http.Client
+	signer     *serviceauth.Signer
+}
+
+// NewOrderClient creates a new order client; signer signs calls to internal order-service endpoints
+func NewOrderClient(baseURL string, timeout time.Duration, signer *serviceauth.Signer) *OrderClient {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &OrderClient{
+		baseURL: baseURL,
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+		signer: signer,
+	}
+}
+
+// CountOpenOrders returns how many unfinished orders (pending, paid, processing,
+// shipped) contain the product item
+func (c *OrderClient) CountOpenOrders(ctx context.Context, productItemID uint) (int64, error) {
+	url := fmt.Sprintf("%s/api/v1/orders/product-items/%d/open-count", c.baseURL, productItemID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to build order service request: %w", err)
+	}
+	c.signer.Sign(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call order service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("order service returned error: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var response struct {
+		OpenOrders int64 `json:"open_orders"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return 0, fmt.Errorf("failed to decode open order count: %w", err)
+	}
+
+	return response.OpenOrders, nil
+}
+
+// Purchase is a delivered order of a buyer containing a product
+type Purchase struct {
+	OrderID     uint   `json:"order_id"`
+	OrderNumber string `json:"order_number"`
+	ShopID      uint   `json:"shop_id"`
+}
+
+// VerifyPurchase returns the buyer's latest delivered order containing the product,
+// or nil if the buyer never received it
+func (c *OrderClient) VerifyPurchase(ctx context.Context, userID, productID uint) (*Purchase, error) {
+	url := fmt.Sprintf("%s/api/v1/orders/purchases/verify?user_id=%d&product_id=%d", c.baseURL, userID, productID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build order service request: %w", err)
+	}
+	c.signer.Sign(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call order service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("order service returned error: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var response struct {
+		Purchased bool `json:"purchased"`
+		Purchase
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode purchase verification: %w", err)
+	}
+	if !response.Purchased {
+		return nil, nil
+	}
+	return &response.Purchase, nil
+}
@@ -0,0 +1,43 @@
+// Package requestid carries the ID of the request being handled through its context.
+//
+// The API Gateway assigns every incoming request an ID and forwards it as:
+//
+//	X-Request-Id: <id>
+//
+// Services keep it in the request context so logs and metrics produced while handling
+// the request (e.g. slow query logs, see pkg/database) can be correlated across services.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Header carries the request ID
+const Header = "X-Request-Id"
+
+type contextKey struct{}
+
+// New generates a random request ID, for requests that did not come through the gateway
+func New() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// NewContext returns a copy of ctx carrying the request ID
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID carried by ctx, or "" if there is none
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -0,0 +1,55 @@
+package shutdown
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Coordinator drains the service on exit by running shutdown hooks in the order
+// they were registered (e.g. stop HTTP server -> stop job worker -> drain async
+// task pool -> flush Kafka)
+type Coordinator struct {
+	mu     sync.Mutex
+	hooks  []hook
+	logger *zap.Logger
+}
+
+type hook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// NewCoordinator creates a new shutdown coordinator
+func NewCoordinator(logger *zap.Logger) *Coordinator {
+	return &Coordinator{logger: logger}
+}
+
+// OnShutdown registers a hook; hooks run in registration order
+func (c *Coordinator) OnShutdown(name string, fn func(ctx context.Context) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hooks = append(c.hooks, hook{name: name, fn: fn})
+}
+
+// Shutdown runs all hooks in order within ctx. A failing hook is logged and
+// does not stop the remaining ones, so resources are still released
+func (c *Coordinator) Shutdown(ctx context.Context) {
+	c.mu.Lock()
+	hooks := c.hooks
+	c.mu.Unlock()
+
+	for _, h := range hooks {
+		start := time.Now()
+		if err := h.fn(ctx); err != nil {
+			c.logger.Error("shutdown step failed", zap.String("step", h.name), zap.Error(err))
+			continue
+		}
+		c.logger.Info("shutdown step completed",
+			zap.String("step", h.name),
+			zap.Duration("took", time.Since(start)),
+		)
+	}
+}
@@ -0,0 +1,190 @@
+package taskqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var errPanic = errors.New("task panicked")
+
+// Options configures a Pool
+type Options struct {
+	Workers    int           // concurrent workers
+	QueueSize  int           // buffered tasks before Submit applies backpressure
+	MaxRetries int           // retries after the first attempt
+	Backoff    time.Duration // delay before retry n is n*Backoff
+	Timeout    time.Duration // per-attempt timeout
+}
+
+// Stats is a snapshot of pool counters
+type Stats struct {
+	Workers   int   `json:"workers"`
+	QueueSize int   `json:"queue_size"`
+	Queued    int   `json:"queued"`
+	Running   int64 `json:"running"`
+	Submitted int64 `json:"submitted"`
+	Inline    int64 `json:"inline"` // ran in the caller because the queue was full or closed
+	Succeeded int64 `json:"succeeded"`
+	Retried   int64 `json:"retried"`
+	Failed    int64 `json:"failed"`
+}
+
+type task struct {
+	name string
+	ctx  context.Context
+	fn   func(ctx context.Context) error
+}
+
+// Pool runs fire-and-forget side effects (cache writes, search indexing, event
+// publishing) on a fixed number of workers with retries, instead of one goroutine
+// per request. When the queue is full the task runs in the caller's goroutine,
+// so load turns into backpressure rather than unbounded goroutines or dropped work
+type Pool struct {
+	opts   Options
+	queue  chan task
+	wg     sync.WaitGroup
+	mu     sync.RWMutex
+	closed bool
+	logger *zap.Logger
+
+	running   atomic.Int64
+	submitted atomic.Int64
+	inline    atomic.Int64
+	succeeded atomic.Int64
+	retried   atomic.Int64
+	failed    atomic.Int64
+}
+
+// NewPool creates a pool and starts its workers
+func NewPool(opts Options, logger *zap.Logger) *Pool {
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 100
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+
+	p := &Pool{
+		opts:   opts,
+		queue:  make(chan task, opts.QueueSize),
+		logger: logger,
+	}
+	for i := 0; i < opts.Workers; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+	return p
+}
+
+// Submit queues fn; ctx values are kept but its cancellation is not, since the
+// task usually outlives the request that submitted it
+func (p *Pool) Submit(ctx context.Context, name string, fn func(ctx context.Context) error) {
+	t := task{name: name, ctx: context.WithoutCancel(ctx), fn: fn}
+	p.submitted.Add(1)
+
+	p.mu.RLock()
+	if !p.closed {
+		select {
+		case p.queue <- t:
+			p.mu.RUnlock()
+			return
+		default:
+		}
+	}
+	p.mu.RUnlock()
+
+	p.inline.Add(1)
+	p.run(t)
+}
+
+// Stats returns current counters
+func (p *Pool) Stats() Stats {
+	return Stats{
+		Workers:   p.opts.Workers,
+		QueueSize: p.opts.QueueSize,
+		Queued:    len(p.queue),
+		Running:   p.running.Load(),
+		Submitted: p.submitted.Load(),
+		Inline:    p.inline.Load(),
+		Succeeded: p.succeeded.Load(),
+		Retried:   p.retried.Load(),
+		Failed:    p.failed.Load(),
+	}
+}
+
+// Shutdown stops accepting queued work and waits for workers to drain the queue
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for t := range p.queue {
+		p.run(t)
+	}
+}
+
+// run executes a task with retries; panics are recovered so a bad task can't kill a worker
+func (p *Pool) run(t task) {
+	p.running.Add(1)
+	defer p.running.Add(-1)
+
+	var err error
+	for attempt := 0; attempt <= p.opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			p.retried.Add(1)
+			time.Sleep(time.Duration(attempt) * p.opts.Backoff)
+		}
+		if err = p.attempt(t); err == nil {
+			p.succeeded.Add(1)
+			return
+		}
+	}
+
+	p.failed.Add(1)
+	p.logger.Warn("async task failed",
+		zap.String("task", t.name),
+		zap.Int("attempts", p.opts.MaxRetries+1),
+		zap.Error(err),
+	)
+}
+
+func (p *Pool) attempt(t task) (err error) {
+	ctx, cancel := context.WithTimeout(t.ctx, p.opts.Timeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			p.logger.Error("async task panicked", zap.String("task", t.name), zap.Any("panic", r))
+			err = errPanic
+		}
+	}()
+
+	return t.fn(ctx)
+}
@@ -0,0 +1,39 @@
+#!/bin/sh
+# Checks that the packages each Go service keeps its own copy of are identical in every
+# service, apart from the imports of the service's own module ("order-service/config" vs
+# "product-service/config", which gofmt sorts differently). Run from the repository root;
+# exits 1 listing the copies that drifted
+set -eu
+
+SERVICES="api-gateway identity-service order-service product-service search-service"
+
+# Files copied into every service (path inside the service)
+SHARED="
+pkg/errorreport/errorreport.go
+pkg/validation/validation.go
+internal/middleware/fault_injection.go
+"
+
+tmp=$(mktemp -d)
+trap 'rm -rf "$tmp"' EXIT
+
+status=0
+for file in $SHARED; do
+	reference=""
+	for svc in $SERVICES; do
+		if [ ! -f "$svc/$file" ]; then
+			echo "missing: $svc/$file"
+			status=1
+			continue
+		fi
+		grep -v "^[[:space:]]*\"$svc/" "$svc/$file" >"$tmp/$svc"
+		if [ -z "$reference" ]; then
+			reference=$svc
+		elif ! diff -u "$tmp/$reference" "$tmp/$svc" >"$tmp/diff"; then
+			echo "drifted: $svc/$file differs from $reference/$file"
+			cat "$tmp/diff"
+			status=1
+		fi
+	done
+done
+exit $status
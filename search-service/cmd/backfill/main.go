@@ -0,0 +1,121 @@
+// Command backfill bootstraps a search index by replaying the product topics.
+//
+// It reads product.created, product.updated and product.deleted (in that order) from the
+// beginning with its own consumer group and applies them like the live consumer. The topics
+// should be compacted (keyed by product ID), so the replay only holds the latest event of each
+// product. The consumer group is derived from the target index and offsets are committed as it
+// goes, so an interrupted run continues when started again with the same -index.
+// An alternative to the REST/Postgres reindex of product-service.
+//
+//	go run ./cmd/backfill                           # into <index_name>_<timestamp>
+//	go run ./cmd/backfill -alias products_current   # ...and point the alias at it when done
+//	go run ./cmd/backfill -index products_v2        # start or resume a named run
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"search-service/config"
+	"search-service/internal/repository/elasticsearch"
+	"search-service/internal/repository/kafka"
+	esClient "search-service/pkg/elasticsearch"
+	"search-service/pkg/logger"
+	"syscall"
+	"time"
+)
+
+func main() {
+	index := flag.String("index", "", "target index name (default: <index_name>_<timestamp>)")
+	alias := flag.String("alias", "", "alias to point at the index when finished (optional)")
+	group := flag.String("group", "", "consumer group (default: <kafka.backfill.consumer_group>-<index>)")
+	offsetReset := flag.String("offset-reset", "", "earliest or latest (default: kafka.backfill.offset_reset)")
+	idle := flag.Duration("idle", 0, "finish a topic once caught up and idle this long (default: kafka.backfill.idle_timeout)")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig("./config")
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	appLogger, err := logger.NewLogger(&cfg.Logging)
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer appLogger.Sync()
+
+	if *index == "" {
+		*index = fmt.Sprintf("%s_%s", cfg.Elasticsearch.IndexName, time.Now().Format("20060102150405"))
+	}
+
+	// One group per target index: a new index replays everything, the same index resumes
+	opts := kafka.BackfillOptions{
+		Brokers:       cfg.Kafka.Brokers,
+		ConsumerGroup: fmt.Sprintf("%s-%s", cfg.Kafka.Backfill.ConsumerGroup, *index),
+		OffsetReset:   cfg.Kafka.Backfill.OffsetReset,
+		IdleTimeout:   cfg.Kafka.Backfill.IdleTimeout,
+		MinBytes:      cfg.Kafka.MinBytes,
+		MaxBytes:      cfg.Kafka.MaxBytes,
+	}
+	if *group != "" {
+		opts.ConsumerGroup = *group
+	}
+	if *offsetReset != "" {
+		opts.OffsetReset = *offsetReset
+	}
+	if *idle > 0 {
+		opts.IdleTimeout = *idle
+	}
+
+	// Order matters: updates overwrite creations, deletions win over both
+	productTopics := kafka.Topics{Prefix: cfg.Kafka.TopicPrefix, Overrides: cfg.Kafka.Topics}
+	opts.Topics = []string{
+		productTopics.For("product_created"),
+		productTopics.For("product_updated"),
+		productTopics.For("product_deleted"),
+	}
+
+	es, err := esClient.GetClient(&cfg.Elasticsearch)
+	if err != nil {
+		log.Fatalf("Failed to connect to Elasticsearch: %v", err)
+	}
+	if err := esClient.EnsureIndex(es, *index); err != nil {
+		log.Fatalf("Failed to create index %q: %v", *index, err)
+	}
+
+	searchRepo := elasticsearch.NewSearchRepository(es, *index)
+	backfill, err := kafka.NewBackfillConsumer(opts, searchRepo, appLogger)
+	if err != nil {
+		log.Fatalf("Invalid backfill options: %v", err)
+	}
+
+	// Stop on Ctrl+C; committed offsets let the next run continue
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("Backfilling index %q from %v (group=%s, offset_reset=%s)",
+		*index, opts.Topics, opts.ConsumerGroup, opts.OffsetReset)
+
+	result, err := backfill.Run(ctx)
+	if err != nil {
+		log.Printf("Backfill stopped after %d messages: %v", result.Applied+result.Failed, err)
+		log.Printf("Run again with -index %s to continue", *index)
+		os.Exit(1)
+	}
+
+	if *alias != "" {
+		if err := esClient.SwapAlias(context.Background(), es, *alias, *index); err != nil {
+			log.Fatalf("Failed to point alias %q at %q: %v", *alias, *index, err)
+		}
+		log.Printf("Alias %q now points to %q", *alias, *index)
+	}
+
+	log.Printf("Backfill completed: index=%s applied=%d failed=%d per_topic=%v duration=%s",
+		*index, result.Applied, result.Failed, result.Topics, result.Duration.Round(time.Second))
+	if result.Failed > 0 {
+		os.Exit(2)
+	}
+}
@@ -9,13 +9,21 @@ import (
 	"os/signal"
 	"runtime/debug"
 	"search-service/config"
+	"search-service/internal/domain"
 	"search-service/internal/handler"
+	"search-service/internal/middleware"
 	"search-service/internal/repository/elasticsearch"
 	"search-service/internal/repository/kafka"
+	redisRepo "search-service/internal/repository/redis"
 	"search-service/internal/router"
 	"search-service/internal/service"
+	"search-service/pkg/category_client"
 	esClient "search-service/pkg/elasticsearch"
+	"search-service/pkg/errorreport"
 	"search-service/pkg/logger"
+	redisClient "search-service/pkg/redis"
+	"search-service/pkg/shop_client"
+	"search-service/pkg/validation"
 	"syscall"
 	"time"
 
@@ -42,10 +50,19 @@ func main() {
 	}
 	log.Println("✅ Config loaded")
 
+	// Product events arrive on one topic per event type
+	productTopics := kafka.Topics{Prefix: cfg.Kafka.TopicPrefix, Overrides: cfg.Kafka.Topics}
+	consumedTopics := []string{
+		productTopics.For("product_created"),
+		productTopics.For("product_updated"),
+		productTopics.For("product_deleted"),
+		productTopics.For("shop_rating_updated"),
+	}
+
 	// Debug: Print config values
-	log.Printf("Config loaded - ES Index: %s, Kafka Topic: %s, Brokers: %v",
+	log.Printf("Config loaded - ES Index: %s, Kafka Topics: %v, Brokers: %v",
 		cfg.Elasticsearch.IndexName,
-		cfg.Kafka.TopicProductUpdated,
+		consumedTopics,
 		cfg.Kafka.Brokers,
 	)
 
@@ -60,10 +77,21 @@ func main() {
 
 	appLogger.Info("Starting Search Service...",
 		zap.String("elasticsearch_index", cfg.Elasticsearch.IndexName),
-		zap.String("kafka_topic", cfg.Kafka.TopicProductUpdated),
+		zap.Strings("kafka_topics", consumedTopics),
 		zap.Strings("kafka_brokers", cfg.Kafka.Brokers),
 	)
 
+	// Report recovered panics to Sentry (disabled without a DSN)
+	if err := errorreport.Init(&cfg.Sentry, "search-service"); err != nil {
+		appLogger.Warn("Failed to initialize error reporting, panics are only logged", zap.Error(err))
+	}
+	defer errorreport.Flush(2 * time.Second)
+
+	// Field-level binding errors and the vnphone/slug rules
+	if err := validation.Register(); err != nil {
+		appLogger.Fatal("Failed to register request validators", zap.Error(err))
+	}
+
 	// Set Gin mode based on config
 	gin.SetMode(cfg.Server.Mode)
 
@@ -71,9 +99,6 @@ func main() {
 	if cfg.Elasticsearch.IndexName == "" {
 		appLogger.Fatal("Elasticsearch index name is empty")
 	}
-	if cfg.Kafka.TopicProductUpdated == "" {
-		appLogger.Fatal("Kafka topic is empty")
-	}
 
 	// Initialize Elasticsearch client
 	appLogger.Info("Initializing Elasticsearch client...")
@@ -98,34 +123,112 @@ func main() {
 	log.Println("✅ Search repository initialized")
 	appLogger.Info("✅ Search repository initialized")
 
+	// Search result cache; searches go straight to Elasticsearch without it
+	var (
+		searchCache      domain.SearchCache
+		searchCacheStats domain.SearchCacheStatsProvider
+	)
+	if cfg.SearchCache.Enabled {
+		redisClientInstance, err := redisClient.GetClient(&cfg.Redis)
+		if err != nil {
+			appLogger.Warn("Redis unavailable, search cache disabled", zap.Error(err))
+		} else {
+			cache := redisRepo.NewSearchCache(redisClientInstance, &cfg.SearchCache)
+			searchCache, searchCacheStats = cache, cache
+			appLogger.Info("✅ Search cache enabled",
+				zap.Duration("ttl", cfg.SearchCache.TTL),
+				zap.Int64("min_hits", cfg.SearchCache.MinHits),
+			)
+		}
+	}
+
 	// Initialize service (Business Logic Layer)
 	log.Println("Initializing services...")
 	appLogger.Info("Initializing services...")
+	// Rollover and retention of the time-series indices (before the curation indexes, so
+	// a managed audit index is created behind its alias)
+	if cfg.Lifecycle.Enabled {
+		for i := range cfg.Lifecycle.Indices {
+			index := &cfg.Lifecycle.Indices[i]
+			mappings := ""
+			switch index.Name {
+			case cfg.Curation.AuditIndexName:
+				mappings = esClient.CurationAuditMappings
+			case cfg.Experiments.FeedbackIndex:
+				mappings = esClient.SearchFeedbackMappings
+			}
+			if err := esClient.EnsureTimeSeriesIndex(esClientInstance, index, mappings); err != nil {
+				appLogger.Warn("Failed to ensure index lifecycle", zap.String("index", index.Name), zap.Error(err))
+				continue
+			}
+			appLogger.Info("✅ Index lifecycle ready",
+				zap.String("index", index.Name),
+				zap.String("rollover_max_size", index.RolloverMaxSize),
+				zap.String("rollover_max_age", index.RolloverMaxAge),
+				zap.String("delete_after", index.DeleteAfter),
+			)
+		}
+	}
+
+	// Admin search curations, reloaded in the background (stopped on shutdown)
+	if err := esClient.EnsureCurationIndexes(esClientInstance, cfg.Curation.IndexName, cfg.Curation.AuditIndexName); err != nil {
+		appLogger.Warn("Failed to ensure curation indexes", zap.Error(err))
+	}
+	curationRepo := elasticsearch.NewCurationRepository(esClientInstance, cfg.Curation.IndexName, cfg.Curation.AuditIndexName)
+	curationService := service.NewCurationService(curationRepo, cfg.Curation.RefreshInterval, appLogger)
+	curationCtx, stopCurations := context.WithCancel(context.Background())
+	defer stopCurations()
+	go curationService.Start(curationCtx)
+
+	// Ranking experiments (visitors bucketed into variants) and their impression/click feedback
+	if err := esClient.EnsureFeedbackIndex(esClientInstance, cfg.Experiments.FeedbackIndex); err != nil {
+		appLogger.Warn("Failed to ensure search feedback index", zap.Error(err))
+	}
+	feedbackRepo := elasticsearch.NewFeedbackRepository(esClientInstance, cfg.Experiments.FeedbackIndex)
+	experimentService := service.NewExperimentService(rankingExperiments(cfg.Experiments.Ranking), feedbackRepo, appLogger)
+
 	searchService := service.NewSearchService(
 		searchRepo,
+		searchCache,
+		curationService,
+		experimentService,
 		appLogger,
 	)
 	log.Println("✅ Search service initialized")
 	appLogger.Info("✅ Search service initialized")
 
+	// Universal search: shops from identity-service, categories from product-service
+	searchAllService := service.NewSearchAllService(
+		searchService,
+		&service.ShopSearchAdapter{Client: shop_client.NewShopClient(cfg.SearchAll.IdentityURL, cfg.SearchAll.Timeout)},
+		service.NewCategorySearchAdapter(category_client.NewCategoryClient(cfg.SearchAll.ProductURL, cfg.SearchAll.Timeout), cfg.SearchAll.CategoryCacheTTL),
+		cfg.SearchAll.Timeout,
+		appLogger,
+	)
+
 	// Initialize handlers (Transport Layer)
 	log.Println("Initializing handlers...")
 	appLogger.Info("Initializing handlers...")
-	searchHandler := handler.NewSearchHandler(searchService, appLogger)
+	searchHandler := handler.NewSearchHandler(searchService, searchAllService, appLogger)
+	curationHandler := handler.NewCurationHandler(curationService, appLogger)
+	experimentHandler := handler.NewExperimentHandler(experimentService, appLogger)
+	logLevelHandler := handler.NewLogLevelHandler(logger.Level(), appLogger)
+	consumerMetrics := kafka.NewConsumerMetrics()
+	healthHandler := handler.NewHealthHandler(consumerMetrics, searchCacheStats, cfg.Kafka.MaxLag, appLogger)
 	log.Println("✅ Search handler initialized")
 	appLogger.Info("✅ Search handler initialized")
 
 	// Setup router
 	log.Println("Setting up router...")
 	appLogger.Info("Setting up router...")
-	router := router.SetupRouter(searchHandler)
+	router := router.SetupRouter(searchHandler, curationHandler, experimentHandler, logLevelHandler, healthHandler, middleware.Recovery(appLogger), middleware.FaultInjection(&cfg.FaultInjection, "search-service", appLogger))
 	log.Println("✅ Router setup complete")
 	appLogger.Info("✅ Router setup complete")
 
 	// Initialize Kafka consumer
 	log.Println("Initializing Kafka consumer...")
 	appLogger.Info("Initializing Kafka consumer...",
-		zap.String("topic", cfg.Kafka.TopicProductUpdated),
+		zap.Strings("topics", consumedTopics),
 		zap.Strings("brokers", cfg.Kafka.Brokers),
 		zap.String("consumer_group", cfg.Kafka.ConsumerGroup),
 	)
@@ -146,12 +249,14 @@ func main() {
 		appLogger.Info("Creating Kafka event consumer...")
 		eventConsumer = kafka.NewEventConsumer(
 			cfg.Kafka.Brokers,
-			cfg.Kafka.TopicProductUpdated,
+			consumedTopics,
 			cfg.Kafka.ConsumerGroup,
 			cfg.Kafka.ReadTimeout,
 			cfg.Kafka.MinBytes,
 			cfg.Kafka.MaxBytes,
 			searchRepo,
+			searchCache,
+			consumerMetrics,
 			appLogger,
 		)
 		log.Println("✅ Kafka event consumer created")
@@ -206,6 +311,35 @@ func main() {
 		appLogger.Info("✅ Kafka consumer cleaned up")
 	}()
 
+	// Order sales projection (sold_count / sold_30d on product documents)
+	salesCtx, stopSales := context.WithCancel(context.Background())
+	defer stopSales()
+	if cfg.Sales.Enabled {
+		if err := esClient.EnsureSalesIndex(esClientInstance, cfg.Sales.IndexName); err != nil {
+			appLogger.Warn("Failed to ensure sales index", zap.Error(err))
+		}
+		salesRepo := elasticsearch.NewSalesRepository(esClientInstance, cfg.Sales.IndexName, cfg.Elasticsearch.IndexName)
+
+		eventTopics := kafka.Topics{Prefix: cfg.Kafka.TopicPrefix, Overrides: cfg.Kafka.Topics}
+		orderTopics := []string{
+			eventTopics.For("order_created"),
+			eventTopics.For("order_cancelled"),
+		}
+		orderConsumer, err := kafka.NewOrderEventConsumer(cfg.Kafka.Brokers, orderTopics, cfg.Sales.ConsumerGroup, salesRepo, appLogger)
+		if err != nil {
+			appLogger.Error("Sales projection disabled", zap.Error(err))
+		} else {
+			defer orderConsumer.Close()
+			go func() {
+				if err := orderConsumer.Start(salesCtx); err != nil && err != context.Canceled {
+					appLogger.Error("Order event consumer stopped", zap.Error(err))
+				}
+			}()
+			go orderConsumer.StartWindowRefresh(salesCtx, cfg.Sales.RefreshInterval)
+			appLogger.Info("✅ Sales projection started", zap.Strings("topics", orderTopics))
+		}
+	}
+
 	// Create HTTP server with timeouts
 	log.Println("Creating HTTP server...")
 	appLogger.Info("Creating HTTP server...")
@@ -263,7 +397,7 @@ func main() {
 	log.Println("✅✅✅ Search Service is ready ✅✅✅")
 	appLogger.Info("✅✅✅ Search Service is ready ✅✅✅",
 		zap.Int("port", cfg.Server.Port),
-		zap.String("kafka_topic", cfg.Kafka.TopicProductUpdated),
+		zap.Strings("kafka_topics", consumedTopics),
 		zap.Strings("elasticsearch_addresses", cfg.Elasticsearch.Addresses),
 	)
 
@@ -290,14 +424,40 @@ func main() {
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer shutdownCancel()
 
-	// Cancel Kafka consumer context
+	// Cancel Kafka consumer contexts
 	cancel()
+	stopSales()
 
 	// Shutdown HTTP server
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		appLogger.Error("Server forced to shutdown", zap.Error(err))
 	}
+	if err := redisClient.CloseClient(); err != nil {
+		appLogger.Warn("Failed to close Redis client", zap.Error(err))
+	}
 
 	appLogger.Info("Server exited")
 }
 
+// rankingExperiments converts the configured ranking experiments; control variants keep
+// the default ranking (nil Ranking)
+func rankingExperiments(cfgs []config.RankingExperimentConfig) []*domain.Experiment {
+	experiments := make([]*domain.Experiment, 0, len(cfgs))
+	for _, c := range cfgs {
+		experiment := &domain.Experiment{ID: c.ID, Name: c.Name, Enabled: c.Enabled}
+		for _, v := range c.Variants {
+			variant := domain.RankingVariant{Name: v.Name, Weight: v.Weight}
+			if !v.Control {
+				variant.Ranking = &domain.RankingParams{
+					NameBoost:        v.NameBoost,
+					DescriptionBoost: v.DescriptionBoost,
+					SalesFactor:      v.SalesFactor,
+					RatingFactor:     v.RatingFactor,
+				}
+			}
+			experiment.Variants = append(experiment.Variants, variant)
+		}
+		experiments = append(experiments, experiment)
+	}
+	return experiments
+}
@@ -15,7 +15,17 @@ type Config struct {
 	Server        ServerConfig
 	Kafka         KafkaConfig
 	Elasticsearch ElasticsearchConfig
+	Redis         RedisConfig
 	Logging       LoggingConfig
+
+	Sales          SalesConfig
+	SearchCache    SearchCacheConfig    `mapstructure:"search_cache"`
+	SearchAll      SearchAllConfig      `mapstructure:"search_all"`
+	Curation       CurationConfig       `mapstructure:"curation"`
+	Experiments    ExperimentsConfig    `mapstructure:"experiments"`
+	Lifecycle      LifecycleConfig      `mapstructure:"lifecycle"`
+	Sentry         SentryConfig         `mapstructure:"sentry"`
+	FaultInjection FaultInjectionConfig `mapstructure:"fault_injection"`
 }
 
 // ServerConfig holds HTTP server configuration
@@ -29,11 +39,30 @@ type ServerConfig struct {
 // KafkaConfig holds Kafka consumer configuration
 type KafkaConfig struct {
 	Brokers            []string
-	TopicProductUpdated string
+	TopicPrefix        string            // One topic per event type: <prefix>product.created, ...
+	Topics             map[string]string // Event type -> topic override
 	ConsumerGroup      string
 	ReadTimeout        time.Duration
 	MinBytes           int
 	MaxBytes           int
+	MaxLag             int64 // /readyz reports not ready above this total lag (messages)
+
+	Backfill BackfillConfig
+}
+
+// BackfillConfig holds the settings of the compacted-topic backfill (cmd/backfill)
+type BackfillConfig struct {
+	ConsumerGroup string        // Group prefix (the target index is appended); never the live consumer's
+	OffsetReset   string        // earliest | latest (only used when the group has no offsets)
+	IdleTimeout   time.Duration // A topic is done once caught up and idle this long
+}
+
+// SalesConfig holds the order sales projection settings (sold_count / sold_30d on products)
+type SalesConfig struct {
+	Enabled         bool
+	IndexName       string        // Sold order lines index
+	ConsumerGroup   string        // Consumes <topic_prefix>order.created and order.cancelled
+	RefreshInterval time.Duration // Recompute all counters so the 30-day window decays
 }
 
 // ElasticsearchConfig holds Elasticsearch connection configuration
@@ -41,8 +70,130 @@ type ElasticsearchConfig struct {
 	Addresses []string
 	Username  string
 	Password  string
-	IndexName string
-	Timeout   time.Duration
+	IndexName string `mapstructure:"index_name"`
+
+	// Transport
+	Timeout      time.Duration `mapstructure:"timeout"`       // Wait for the response headers of one request
+	DialTimeout  time.Duration `mapstructure:"dial_timeout"`  // Open a connection to a node
+	MaxRetries   int           `mapstructure:"max_retries"`   // Retries on network errors and 502/503/504; 0 disables
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"` // Doubled after every retry
+}
+
+// RedisConfig holds Redis connection configuration (search result cache)
+type RedisConfig struct {
+	Host     string
+	Port     int
+	Password string
+	DB       int
+
+	// Connection pool and timeouts (go-redis)
+	PoolSize     int           `mapstructure:"pool_size"`
+	MinIdleConns int           `mapstructure:"min_idle_conns"` // At most PoolSize
+	DialTimeout  time.Duration `mapstructure:"dial_timeout"`
+	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
+	WriteTimeout time.Duration `mapstructure:"write_timeout"`
+	PoolTimeout  time.Duration `mapstructure:"pool_timeout"` // Wait for a free connection when the pool is exhausted
+}
+
+// GetAddress returns the Redis address
+func (c *RedisConfig) GetAddress() string {
+	return fmt.Sprintf("%s:%d", c.Host, c.Port)
+}
+
+// SearchCacheConfig holds the Redis cache of popular searches' first pages. Entries
+// are dropped when product events touch their category, TTL bounds what events miss
+type SearchCacheConfig struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	TTL              time.Duration `mapstructure:"ttl"`
+	MinHits          int64         `mapstructure:"min_hits"`          // a query is cached once it was searched this often...
+	PopularityWindow time.Duration `mapstructure:"popularity_window"` // ...within this window
+}
+
+// SearchAllConfig holds the universal search (products, shops and categories in one call).
+// Shops are searched in identity-service, categories are read from product-service
+type SearchAllConfig struct {
+	Timeout          time.Duration `mapstructure:"timeout"`            // a group not answered in time is returned as unavailable
+	CategoryCacheTTL time.Duration `mapstructure:"category_cache_ttl"` // how long the category list is reused
+	IdentityURL      string        `mapstructure:"identity_url"`       // identity-service base URL
+	ProductURL       string        `mapstructure:"product_url"`        // product-service base URL
+}
+
+// CurationConfig holds the admin search curations (pinned products, boosted shops), stored
+// in their own Elasticsearch indexes and held in memory by every instance
+type CurationConfig struct {
+	IndexName       string        `mapstructure:"index_name"`
+	AuditIndexName  string        `mapstructure:"audit_index_name"`
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"` // changes made on another instance apply within this
+}
+
+// ExperimentsConfig holds the search ranking A/B experiments. Visitors of text searches
+// ranked by relevance are bucketed into the variants of the enabled experiment; clients
+// log impressions and clicks to the feedback index, reported as CTR per variant
+type ExperimentsConfig struct {
+	FeedbackIndex string                    `mapstructure:"feedback_index"` // time-series index of impressions and clicks
+	Ranking       []RankingExperimentConfig `mapstructure:"ranking"`
+}
+
+// RankingExperimentConfig holds one ranking experiment; at most one is enabled
+type RankingExperimentConfig struct {
+	ID       string                 `mapstructure:"id"` // stored with the feedback, never reuse it for another experiment
+	Name     string                 `mapstructure:"name"`
+	Enabled  bool                   `mapstructure:"enabled"`
+	Variants []RankingVariantConfig `mapstructure:"variants"`
+}
+
+// RankingVariantConfig holds one variant: the control keeps the default ranking, the
+// others set the text field weights and the sales/rating signals (0 = signal off)
+type RankingVariantConfig struct {
+	Name             string  `mapstructure:"name"`
+	Weight           int     `mapstructure:"weight"` // share of visitors, relative to the other variants
+	Control          bool    `mapstructure:"control"`
+	NameBoost        float64 `mapstructure:"name_boost"`
+	DescriptionBoost float64 `mapstructure:"description_boost"`
+	SalesFactor      float64 `mapstructure:"sales_factor"`
+	RatingFactor     float64 `mapstructure:"rating_factor"`
+}
+
+// LifecycleConfig holds the index lifecycle (ILM) of time-series indices: each is written
+// through an alias, rolled over to a new index by size or age, and its old indices deleted
+type LifecycleConfig struct {
+	Enabled bool                   `mapstructure:"enabled"`
+	Indices []LifecycleIndexConfig `mapstructure:"indices"`
+}
+
+// LifecycleIndexConfig holds the rollover and retention of one time-series index.
+// Sizes and ages use Elasticsearch units, e.g. "5gb", "30d"
+type LifecycleIndexConfig struct {
+	Name            string `mapstructure:"name"`              // write alias; the indices are <name>-000001, <name>-000002, ...
+	RolloverMaxSize string `mapstructure:"rollover_max_size"` // primary shard size
+	RolloverMaxAge  string `mapstructure:"rollover_max_age"`  // since the index was created
+	DeleteAfter     string `mapstructure:"delete_after"`      // since the index was rolled over; empty keeps indices forever
+}
+
+// SentryConfig holds the reporting of recovered panics to Sentry
+type SentryConfig struct {
+	DSN         string  `mapstructure:"dsn"`         // empty disables reporting (panics are only logged)
+	Environment string  `mapstructure:"environment"` // e.g. production, staging
+	Release     string  `mapstructure:"release"`     // deployed version, groups issues by release
+	SampleRate  float64 `mapstructure:"sample_rate"` // share of panics reported, 0-1
+}
+
+// FaultInjectionConfig holds the opt-in injection of latency and errors, used to check
+// timeouts, retries and circuit breakers of the callers. Refused in release mode
+type FaultInjectionConfig struct {
+	Enabled bool              `mapstructure:"enabled"`
+	Headers bool              `mapstructure:"headers"` // honor X-Fault-* request headers naming this service
+	Rules   []FaultRuleConfig `mapstructure:"rules"`
+}
+
+// FaultRuleConfig injects faults into the requests matching a path prefix (and method)
+type FaultRuleConfig struct {
+	PathPrefix  string        `mapstructure:"path_prefix"`
+	Method      string        `mapstructure:"method"`       // empty matches any method
+	Latency     time.Duration `mapstructure:"latency"`      // added before the request is handled
+	Jitter      time.Duration `mapstructure:"jitter"`       // random extra latency, up to this much
+	ErrorRate   float64       `mapstructure:"error_rate"`   // share of matching requests answered with ErrorStatus, 0-1
+	ErrorStatus int           `mapstructure:"error_status"` // default 503
 }
 
 // LoggingConfig holds logging configuration
@@ -79,9 +230,9 @@ func LoadConfig(configPath string) (*Config, error) {
 	config := &Config{}
 
 	// Debug: Check viper values before unmarshal
-	log.Printf("Viper values - ES index_name: %s, Kafka topic_product_updated: %s",
+	log.Printf("Viper values - ES index_name: %s, Kafka topic_prefix: %q",
 		viper.GetString("elasticsearch.index_name"),
-		viper.GetString("kafka.topic_product_updated"),
+		viper.GetString("kafka.topic_prefix"),
 	)
 
 	// Unmarshal configuration into struct
@@ -93,20 +244,49 @@ func LoadConfig(configPath string) (*Config, error) {
 	if config.Elasticsearch.IndexName == "" {
 		config.Elasticsearch.IndexName = viper.GetString("elasticsearch.index_name")
 	}
-	if config.Kafka.TopicProductUpdated == "" {
-		config.Kafka.TopicProductUpdated = viper.GetString("kafka.topic_product_updated")
+	if config.Kafka.TopicPrefix == "" {
+		config.Kafka.TopicPrefix = viper.GetString("kafka.topic_prefix")
+	}
+	if len(config.Kafka.Topics) == 0 {
+		config.Kafka.Topics = viper.GetStringMapString("kafka.topics")
 	}
 	if config.Kafka.ConsumerGroup == "" {
 		config.Kafka.ConsumerGroup = viper.GetString("kafka.consumer_group")
 	}
+	if config.Kafka.MaxLag == 0 {
+		config.Kafka.MaxLag = viper.GetInt64("kafka.max_lag")
+	}
+	if config.Kafka.Backfill.ConsumerGroup == "" {
+		config.Kafka.Backfill.ConsumerGroup = viper.GetString("kafka.backfill.consumer_group")
+	}
+	if config.Kafka.Backfill.OffsetReset == "" {
+		config.Kafka.Backfill.OffsetReset = viper.GetString("kafka.backfill.offset_reset")
+	}
+	if config.Sales.IndexName == "" {
+		config.Sales.IndexName = viper.GetString("sales.index_name")
+	}
+	if config.Sales.ConsumerGroup == "" {
+		config.Sales.ConsumerGroup = viper.GetString("sales.consumer_group")
+	}
+	if config.Sales.RefreshInterval == 0 {
+		config.Sales.RefreshInterval = viper.GetDuration("sales.refresh_interval")
+	}
+	if config.Kafka.Backfill.IdleTimeout == 0 {
+		config.Kafka.Backfill.IdleTimeout = viper.GetDuration("kafka.backfill.idle_timeout")
+	}
 
 	// Debug: Check if values were loaded
-	log.Printf("After unmarshal - ES Index: %s, Kafka Topic: %s, ConsumerGroup: %s",
+	log.Printf("After unmarshal - ES Index: %s, Kafka topic prefix: %q, ConsumerGroup: %s",
 		config.Elasticsearch.IndexName,
-		config.Kafka.TopicProductUpdated,
+		config.Kafka.TopicPrefix,
 		config.Kafka.ConsumerGroup,
 	)
 
+	// Fail fast on settings that would only break under load (timeouts, retries)
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
 	return config, nil
 }
 
@@ -121,11 +301,15 @@ func setDefaults() {
 
 	// Kafka defaults
 	viper.SetDefault("kafka.brokers", []string{"localhost:9092"})
-	viper.SetDefault("kafka.topic_product_updated", "product_updated")
+	viper.SetDefault("kafka.topic_prefix", "")
 	viper.SetDefault("kafka.consumer_group", "search-service")
 	viper.SetDefault("kafka.read_timeout", "10s")
 	viper.SetDefault("kafka.min_bytes", 1024)
 	viper.SetDefault("kafka.max_bytes", 10485760) // 10MB
+	viper.SetDefault("kafka.max_lag", 1000)
+	viper.SetDefault("kafka.backfill.consumer_group", "search-service-backfill")
+	viper.SetDefault("kafka.backfill.offset_reset", "earliest")
+	viper.SetDefault("kafka.backfill.idle_timeout", "10s")
 
 	// Elasticsearch defaults
 	viper.SetDefault("elasticsearch.addresses", []string{"http://localhost:9200"})
@@ -133,6 +317,74 @@ func setDefaults() {
 	viper.SetDefault("elasticsearch.password", "")
 	viper.SetDefault("elasticsearch.index_name", "products")
 	viper.SetDefault("elasticsearch.timeout", "30s")
+	viper.SetDefault("elasticsearch.dial_timeout", "5s")
+	viper.SetDefault("elasticsearch.max_retries", 3)
+	viper.SetDefault("elasticsearch.retry_backoff", "100ms")
+
+	// Redis defaults
+	viper.SetDefault("redis.host", "localhost")
+	viper.SetDefault("redis.port", 6379)
+	viper.SetDefault("redis.password", "")
+	viper.SetDefault("redis.db", 0)
+	viper.SetDefault("redis.pool_size", 10)
+	viper.SetDefault("redis.min_idle_conns", 2)
+	viper.SetDefault("redis.dial_timeout", "5s")
+	viper.SetDefault("redis.read_timeout", "1s")
+	viper.SetDefault("redis.write_timeout", "1s")
+	viper.SetDefault("redis.pool_timeout", "2s")
+
+	// Search result cache defaults
+	viper.SetDefault("search_cache.enabled", true)
+	viper.SetDefault("search_cache.ttl", "2m")
+	viper.SetDefault("search_cache.min_hits", 3)
+	viper.SetDefault("search_cache.popularity_window", "10m")
+
+	// Universal search defaults
+	viper.SetDefault("search_all.timeout", "800ms")
+	viper.SetDefault("search_all.category_cache_ttl", "5m")
+	viper.SetDefault("search_all.identity_url", "http://localhost:8081")
+	viper.SetDefault("search_all.product_url", "http://localhost:8080")
+
+	// Search curation defaults
+	viper.SetDefault("curation.index_name", "search_curations")
+	viper.SetDefault("curation.audit_index_name", "search_curation_audit")
+	viper.SetDefault("curation.refresh_interval", "30s")
+
+	// Ranking experiment defaults (no experiment runs until one is configured)
+	viper.SetDefault("experiments.feedback_index", "search_feedback")
+
+	// Index lifecycle defaults (the curation audit trail and search feedback are the
+	// time-series indices kept here)
+	viper.SetDefault("lifecycle.enabled", true)
+	viper.SetDefault("lifecycle.indices", []map[string]interface{}{
+		{
+			"name":              "search_curation_audit",
+			"rollover_max_size": "5gb",
+			"rollover_max_age":  "90d",
+			"delete_after":      "730d",
+		},
+		{
+			"name":              "search_feedback",
+			"rollover_max_size": "10gb",
+			"rollover_max_age":  "30d",
+			"delete_after":      "180d",
+		},
+	})
+
+	// Sales projection defaults
+	viper.SetDefault("sales.enabled", true)
+	viper.SetDefault("sales.index_name", "order_sales")
+	viper.SetDefault("sales.consumer_group", "search-service-sales")
+	viper.SetDefault("sales.refresh_interval", "1h")
+
+	// Panic reporting defaults (set SENTRY_DSN to enable)
+	viper.SetDefault("sentry.dsn", "")
+	viper.SetDefault("sentry.environment", "development")
+	viper.SetDefault("sentry.sample_rate", 1.0)
+
+	// Fault injection is for resilience testing only
+	viper.SetDefault("fault_injection.enabled", false)
+	viper.SetDefault("fault_injection.headers", false)
 
 	// Logging defaults
 	viper.SetDefault("logging.level", "info")
@@ -0,0 +1,203 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// Elasticsearch byte size ("5gb") and time ("30d") units
+var (
+	esByteSize = regexp.MustCompile(`^[0-9]+(b|kb|mb|gb|tb)$`)
+	esTime     = regexp.MustCompile(`^[0-9]+(d|h|m|s)$`)
+	// Index names: lowercase, no leading '-', '_' or '+'
+	esIndexName = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*$`)
+)
+
+// Validate checks the Elasticsearch transport and Redis pool settings, so a bad value
+// fails at startup instead of as hung or failed searches under load
+func (c *Config) Validate() error {
+	var redisErr error
+	if c.SearchCache.Enabled {
+		redisErr = errors.Join(c.Redis.Validate(), c.SearchCache.Validate())
+	}
+	return errors.Join(
+		c.Elasticsearch.Validate(),
+		redisErr,
+		c.SearchAll.Validate(),
+		c.Curation.Validate(),
+		c.Experiments.Validate(),
+		c.Lifecycle.Validate(),
+		c.FaultInjection.Validate(c.Server.Mode),
+	)
+}
+
+// Validate checks the Elasticsearch addresses and transport settings
+func (c *ElasticsearchConfig) Validate() error {
+	var errs []error
+	if len(c.Addresses) == 0 {
+		errs = append(errs, errors.New("elasticsearch: at least one address is required"))
+	}
+	if c.IndexName == "" {
+		errs = append(errs, errors.New("elasticsearch: index_name is required"))
+	}
+	if c.Timeout <= 0 || c.DialTimeout <= 0 {
+		errs = append(errs, errors.New("elasticsearch: timeout and dial_timeout must be positive"))
+	}
+	if c.MaxRetries < 0 || c.RetryBackoff < 0 {
+		errs = append(errs, errors.New("elasticsearch: max_retries and retry_backoff must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks the Redis pool and timeout settings
+func (c *RedisConfig) Validate() error {
+	var errs []error
+	if c.Host == "" {
+		errs = append(errs, errors.New("redis: host is required"))
+	}
+	if c.PoolSize <= 0 {
+		errs = append(errs, fmt.Errorf("redis: pool_size must be positive, got %d", c.PoolSize))
+	}
+	if c.MinIdleConns < 0 || c.MinIdleConns > c.PoolSize {
+		errs = append(errs, fmt.Errorf("redis: min_idle_conns must be between 0 and pool_size (%d), got %d", c.PoolSize, c.MinIdleConns))
+	}
+	if c.DialTimeout <= 0 || c.ReadTimeout <= 0 || c.WriteTimeout <= 0 || c.PoolTimeout <= 0 {
+		errs = append(errs, errors.New("redis: dial_timeout, read_timeout, write_timeout and pool_timeout must be positive"))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks the cache lifetimes
+func (c *SearchCacheConfig) Validate() error {
+	var errs []error
+	if c.TTL <= 0 || c.PopularityWindow <= 0 {
+		errs = append(errs, errors.New("search_cache: ttl and popularity_window must be positive"))
+	}
+	if c.MinHits < 1 {
+		errs = append(errs, fmt.Errorf("search_cache: min_hits must be at least 1, got %d", c.MinHits))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks the universal search timeout and backing service URLs
+func (c *SearchAllConfig) Validate() error {
+	var errs []error
+	if c.Timeout <= 0 || c.CategoryCacheTTL <= 0 {
+		errs = append(errs, errors.New("search_all: timeout and category_cache_ttl must be positive"))
+	}
+	if c.IdentityURL == "" || c.ProductURL == "" {
+		errs = append(errs, errors.New("search_all: identity_url and product_url are required"))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks the curation indexes and reload interval
+func (c *CurationConfig) Validate() error {
+	var errs []error
+	if c.IndexName == "" || c.AuditIndexName == "" {
+		errs = append(errs, errors.New("curation: index_name and audit_index_name are required"))
+	}
+	if c.RefreshInterval <= 0 {
+		errs = append(errs, errors.New("curation: refresh_interval must be positive"))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks the feedback index and the experiments: unique IDs, at most one enabled,
+// at least two variants with unique names and positive weights, and usable rankings
+func (c *ExperimentsConfig) Validate() error {
+	var errs []error
+	if !esIndexName.MatchString(c.FeedbackIndex) {
+		errs = append(errs, fmt.Errorf("experiments: feedback_index must be a lowercase index name, got %q", c.FeedbackIndex))
+	}
+	ids := make(map[string]bool, len(c.Ranking))
+	enabled := 0
+	for i, experiment := range c.Ranking {
+		if experiment.ID == "" {
+			errs = append(errs, fmt.Errorf("experiments: ranking[%d].id is required", i))
+		} else if ids[experiment.ID] {
+			errs = append(errs, fmt.Errorf("experiments: ranking[%d].id %q is listed twice", i, experiment.ID))
+		}
+		ids[experiment.ID] = true
+		if experiment.Enabled {
+			enabled++
+		}
+		if len(experiment.Variants) < 2 {
+			errs = append(errs, fmt.Errorf("experiments: ranking[%d] needs at least two variants", i))
+		}
+		names := make(map[string]bool, len(experiment.Variants))
+		for j, variant := range experiment.Variants {
+			if variant.Name == "" || names[variant.Name] {
+				errs = append(errs, fmt.Errorf("experiments: ranking[%d].variants[%d].name must be set and unique, got %q", i, j, variant.Name))
+			}
+			names[variant.Name] = true
+			if variant.Weight <= 0 {
+				errs = append(errs, fmt.Errorf("experiments: ranking[%d].variants[%d].weight must be positive, got %d", i, j, variant.Weight))
+			}
+			if !variant.Control && (variant.NameBoost <= 0 || variant.DescriptionBoost < 0 || variant.SalesFactor < 0 || variant.RatingFactor < 0) {
+				errs = append(errs, fmt.Errorf("experiments: ranking[%d].variants[%d] needs a positive name_boost and no negative boost or factor", i, j))
+			}
+		}
+	}
+	if enabled > 1 {
+		errs = append(errs, fmt.Errorf("experiments: at most one ranking experiment can be enabled, got %d", enabled))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks the lifecycle index names, rollover conditions and retention
+func (c *LifecycleConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	var errs []error
+	seen := make(map[string]bool, len(c.Indices))
+	for i, index := range c.Indices {
+		if !esIndexName.MatchString(index.Name) {
+			errs = append(errs, fmt.Errorf("lifecycle: indices[%d].name must be a lowercase index name, got %q", i, index.Name))
+		} else if seen[index.Name] {
+			errs = append(errs, fmt.Errorf("lifecycle: indices[%d].name %q is listed twice", i, index.Name))
+		}
+		seen[index.Name] = true
+		if index.RolloverMaxSize == "" && index.RolloverMaxAge == "" {
+			errs = append(errs, fmt.Errorf("lifecycle: indices[%d] needs rollover_max_size or rollover_max_age", i))
+		}
+		if index.RolloverMaxSize != "" && !esByteSize.MatchString(index.RolloverMaxSize) {
+			errs = append(errs, fmt.Errorf("lifecycle: indices[%d].rollover_max_size must be like 5gb, got %q", i, index.RolloverMaxSize))
+		}
+		if index.RolloverMaxAge != "" && !esTime.MatchString(index.RolloverMaxAge) {
+			errs = append(errs, fmt.Errorf("lifecycle: indices[%d].rollover_max_age must be like 30d, got %q", i, index.RolloverMaxAge))
+		}
+		if index.DeleteAfter != "" && !esTime.MatchString(index.DeleteAfter) {
+			errs = append(errs, fmt.Errorf("lifecycle: indices[%d].delete_after must be like 90d, got %q", i, index.DeleteAfter))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks the fault injection rules; injecting faults in release mode is refused
+func (c *FaultInjectionConfig) Validate(serverMode string) error {
+	if !c.Enabled {
+		return nil
+	}
+	if serverMode == "release" {
+		return errors.New("fault_injection: must not be enabled when server.mode is release")
+	}
+	var errs []error
+	for i, rule := range c.Rules {
+		if rule.PathPrefix == "" {
+			errs = append(errs, fmt.Errorf("fault_injection: rules[%d].path_prefix is required", i))
+		}
+		if rule.Latency < 0 || rule.Jitter < 0 {
+			errs = append(errs, fmt.Errorf("fault_injection: rules[%d] latency and jitter must not be negative", i))
+		}
+		if rule.ErrorRate < 0 || rule.ErrorRate > 1 {
+			errs = append(errs, fmt.Errorf("fault_injection: rules[%d].error_rate must be between 0 and 1, got %g", i, rule.ErrorRate))
+		}
+		if rule.ErrorStatus != 0 && (rule.ErrorStatus < 400 || rule.ErrorStatus > 599) {
+			errs = append(errs, fmt.Errorf("fault_injection: rules[%d].error_status must be a 4xx or 5xx status, got %d", i, rule.ErrorStatus))
+		}
+	}
+	return errors.Join(errs...)
+}
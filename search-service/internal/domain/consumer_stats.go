@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// ConsumerStats is a snapshot of the Kafka consumer metrics
+type ConsumerStats struct {
+	Running           bool           `json:"running"`
+	Processed         int64          `json:"processed"`           // Messages handled (including failed ones)
+	Errors            int64          `json:"errors"`              // Messages that failed to decode or index
+	MessagesPerSecond float64        `json:"messages_per_second"` // Average over the last minute
+	LatencySeconds    float64        `json:"latency_seconds_sum"` // Total processing time (with Processed: average latency)
+	AvgLatencyMs      float64        `json:"avg_latency_ms"`
+	MaxLatencyMs      float64        `json:"max_latency_ms"`
+	TotalLag          int64          `json:"total_lag"` // Sum of partition lags
+	Partitions        []PartitionLag `json:"partitions"`
+	LastMessageAt     *time.Time     `json:"last_message_at,omitempty"`
+}
+
+// PartitionLag is how far the consumer is behind on one topic partition
+// (as of the last message read from it)
+type PartitionLag struct {
+	Topic         string `json:"topic"`
+	Partition     int    `json:"partition"`
+	Offset        int64  `json:"offset"`
+	HighWaterMark int64  `json:"high_water_mark"`
+	Lag           int64  `json:"lag"`
+}
+
+// ConsumerStatsProvider exposes consumer metrics (implemented by kafka.ConsumerMetrics)
+type ConsumerStatsProvider interface {
+	ConsumerStats() ConsumerStats
+}
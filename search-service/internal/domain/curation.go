@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Curation limits
+const (
+	MaxPinnedProducts = 20  // per curation
+	MaxPinnedTotal    = 100 // per search, all active curations together (Elasticsearch pinned query limit)
+	DefaultShopBoost  = 2.0 // score multiplier when a curation boosts shops without a boost
+	MaxShopBoost      = 10.0
+)
+
+// Curation audit actions
+const (
+	CurationActionCreated = "created"
+	CurationActionUpdated = "updated"
+	CurationActionDeleted = "deleted"
+)
+
+var (
+	// ErrCurationNotFound is returned for an unknown curation ID
+	ErrCurationNotFound = errors.New("curation not found")
+	// ErrCurationTargetRequired is returned for a curation without keyword and campaign
+	ErrCurationTargetRequired = errors.New("keyword or campaign_id is required")
+	// ErrCurationEmpty is returned for a curation that neither pins products nor boosts shops
+	ErrCurationEmpty = errors.New("pinned_product_ids or boosted_shop_ids is required")
+	// ErrTooManyPinned is returned for a curation pinning more than MaxPinnedProducts
+	ErrTooManyPinned = errors.New("too many pinned products")
+	// ErrInvalidShopBoost is returned for a shop boost outside (1, MaxShopBoost]
+	ErrInvalidShopBoost = errors.New("shop_boost must be above 1 and at most 10")
+	// ErrInvalidSchedule is returned when end_at is not after start_at
+	ErrInvalidSchedule = errors.New("end_at must be after start_at")
+)
+
+// Curation is an admin rule for the searches of a keyword and/or a campaign (product-service
+// campaign, e.g. a banner linking to /search?campaign_id=3): pinned products are listed
+// first in the given order, products of boosted shops rank higher. Only applied to searches
+// ordered by relevance, between StartAt and EndAt
+type Curation struct {
+	ID               string     `json:"id"`
+	Keyword          string     `json:"keyword,omitempty"`            // matches the query ignoring case, spacing and diacritics
+	CampaignID       *uint      `json:"campaign_id,omitempty"`        // matches the campaign_id search parameter
+	PinnedProductIDs []uint     `json:"pinned_product_ids,omitempty"` // shown first, in this order (if they match the filters)
+	BoostedShopIDs   []uint     `json:"boosted_shop_ids,omitempty"`
+	ShopBoost        float64    `json:"shop_boost,omitempty"` // relevance multiplier of the boosted shops' products
+	StartAt          *time.Time `json:"start_at,omitempty"`   // Nil = start immediately
+	EndAt            *time.Time `json:"end_at,omitempty"`     // Nil = no end
+	Note             string     `json:"note,omitempty"`       // why, e.g. "Flash sale 11.11"
+	CreatedBy        uint       `json:"created_by"`
+	UpdatedBy        uint       `json:"updated_by"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// IsActiveAt reports whether the curation is scheduled at t
+func (c *Curation) IsActiveAt(t time.Time) bool {
+	if c.StartAt != nil && t.Before(*c.StartAt) {
+		return false
+	}
+	if c.EndAt != nil && !t.Before(*c.EndAt) {
+		return false
+	}
+	return true
+}
+
+// CurationAudit records every change of a curation (who, when, before -> after)
+type CurationAudit struct {
+	CurationID string    `json:"curation_id"`
+	Action     string    `json:"action"`           // created, updated, deleted
+	Before     *Curation `json:"before,omitempty"` // nil when created
+	After      *Curation `json:"after,omitempty"`  // nil when deleted
+	ChangedBy  uint      `json:"changed_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// SearchCuration is what the active curations matching a search apply to its query
+type SearchCuration struct {
+	PinnedProductIDs []uint      `json:"pinned_product_ids,omitempty"`
+	ShopBoosts       []ShopBoost `json:"shop_boosts,omitempty"`
+}
+
+// ShopBoost multiplies the relevance of the products of shops
+type ShopBoost struct {
+	ShopIDs []uint  `json:"shop_ids"`
+	Boost   float64 `json:"boost"`
+}
+
+// CurationRepository stores curations and their audit trail (implemented in Elasticsearch)
+type CurationRepository interface {
+	Create(ctx context.Context, curation *Curation) error // sets curation.ID
+	Update(ctx context.Context, curation *Curation) error
+	Delete(ctx context.Context, id string) error
+	Get(ctx context.Context, id string) (*Curation, error)
+	List(ctx context.Context) ([]*Curation, error) // newest first
+	CreateAudit(ctx context.Context, audit *CurationAudit) error
+	ListAudit(ctx context.Context, curationID string, page, limit int) ([]*CurationAudit, int64, error) // newest first
+}
+
+// SearchCurator returns what the active curations apply to a search, nil when none matches
+// (implemented by service.CurationService)
+type SearchCurator interface {
+	CurationFor(req *SearchRequest, now time.Time) *SearchCuration
+}
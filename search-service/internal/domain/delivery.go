@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Region is a part of Vietnam; delivery takes longer the more regions a parcel crosses
+type Region int
+
+const (
+	RegionNorth Region = iota
+	RegionCentral
+	RegionSouth
+)
+
+// Province is a province-level unit of Vietnam (since the July 2025 merger). Code is the
+// slug of the name, as stored on shops by identity-service
+type Province struct {
+	Code   string
+	Name   string
+	Region Region
+}
+
+var provinces = []Province{
+	{"ha-noi", "Hà Nội", RegionNorth},
+	{"hai-phong", "Hải Phòng", RegionNorth},
+	{"lai-chau", "Lai Châu", RegionNorth},
+	{"dien-bien", "Điện Biên", RegionNorth},
+	{"son-la", "Sơn La", RegionNorth},
+	{"lang-son", "Lạng Sơn", RegionNorth},
+	{"cao-bang", "Cao Bằng", RegionNorth},
+	{"quang-ninh", "Quảng Ninh", RegionNorth},
+	{"tuyen-quang", "Tuyên Quang", RegionNorth},
+	{"lao-cai", "Lào Cai", RegionNorth},
+	{"thai-nguyen", "Thái Nguyên", RegionNorth},
+	{"phu-tho", "Phú Thọ", RegionNorth},
+	{"bac-ninh", "Bắc Ninh", RegionNorth},
+	{"hung-yen", "Hưng Yên", RegionNorth},
+	{"ninh-binh", "Ninh Bình", RegionNorth},
+	{"thanh-hoa", "Thanh Hóa", RegionCentral},
+	{"nghe-an", "Nghệ An", RegionCentral},
+	{"ha-tinh", "Hà Tĩnh", RegionCentral},
+	{"quang-tri", "Quảng Trị", RegionCentral},
+	{"hue", "Huế", RegionCentral},
+	{"da-nang", "Đà Nẵng", RegionCentral},
+	{"quang-ngai", "Quảng Ngãi", RegionCentral},
+	{"gia-lai", "Gia Lai", RegionCentral},
+	{"khanh-hoa", "Khánh Hòa", RegionCentral},
+	{"dak-lak", "Đắk Lắk", RegionCentral},
+	{"lam-dong", "Lâm Đồng", RegionCentral},
+	{"ho-chi-minh", "Hồ Chí Minh", RegionSouth},
+	{"dong-nai", "Đồng Nai", RegionSouth},
+	{"tay-ninh", "Tây Ninh", RegionSouth},
+	{"can-tho", "Cần Thơ", RegionSouth},
+	{"vinh-long", "Vĩnh Long", RegionSouth},
+	{"dong-thap", "Đồng Tháp", RegionSouth},
+	{"ca-mau", "Cà Mau", RegionSouth},
+	{"an-giang", "An Giang", RegionSouth},
+}
+
+var provincesByCode = func() map[string]*Province {
+	byCode := make(map[string]*Province, len(provinces))
+	for i := range provinces {
+		byCode[provinces[i].Code] = &provinces[i]
+	}
+	return byCode
+}()
+
+// LookupProvince resolves a province by code ("ho-chi-minh"), name ("Hồ Chí Minh", any
+// case) or name without diacritics ("Ho Chi Minh")
+func LookupProvince(value string) (*Province, bool) {
+	value = strings.TrimSpace(value)
+	if p, ok := provincesByCode[strings.ReplaceAll(strings.ToLower(value), " ", "-")]; ok {
+		return p, true
+	}
+	for i := range provinces {
+		if strings.EqualFold(provinces[i].Name, value) {
+			return &provinces[i], true
+		}
+	}
+	return nil, false
+}
+
+// Delivery bands, from the shop's province to the buyer's
+const (
+	DeliveryBandSameProvince = "same_province"
+	DeliveryBandSameRegion   = "same_region"
+	DeliveryBandNearRegion   = "near_region" // neighbouring regions (north-central, central-south)
+	DeliveryBandFarRegion    = "far_region"  // north-south
+)
+
+// DeliveryEstimate is the expected delivery time of a product to the buyer's province
+type DeliveryEstimate struct {
+	Band    string `json:"band"`
+	MinDays int    `json:"min_days"`
+	MaxDays int    `json:"max_days"`
+	Label   string `json:"label"` // e.g. "Giao trong 1-2 ngày"
+}
+
+// EstimateDelivery returns the delivery band from a province to another (codes), nil when
+// either is unknown
+func EstimateDelivery(from, to string) *DeliveryEstimate {
+	origin, ok := provincesByCode[from]
+	if !ok {
+		return nil
+	}
+	destination, ok := provincesByCode[to]
+	if !ok {
+		return nil
+	}
+
+	estimate := &DeliveryEstimate{}
+	switch {
+	case origin.Code == destination.Code:
+		estimate.Band, estimate.MinDays, estimate.MaxDays = DeliveryBandSameProvince, 1, 2
+	case origin.Region == destination.Region:
+		estimate.Band, estimate.MinDays, estimate.MaxDays = DeliveryBandSameRegion, 2, 3
+	case origin.Region-destination.Region == 1 || destination.Region-origin.Region == 1:
+		estimate.Band, estimate.MinDays, estimate.MaxDays = DeliveryBandNearRegion, 3, 5
+	default:
+		estimate.Band, estimate.MinDays, estimate.MaxDays = DeliveryBandFarRegion, 4, 6
+	}
+	estimate.Label = fmt.Sprintf("Giao trong %d-%d ngày", estimate.MinDays, estimate.MaxDays)
+	return estimate
+}
+
+// ProvincesDeliveringWithin returns the codes of the provinces that deliver to the buyer's
+// province within maxDays (by the upper bound of their band)
+func ProvincesDeliveringWithin(buyerProvince string, maxDays int) []string {
+	codes := make([]string, 0, len(provinces))
+	for _, p := range provinces {
+		if estimate := EstimateDelivery(p.Code, buyerProvince); estimate != nil && estimate.MaxDays <= maxDays {
+			codes = append(codes, p.Code)
+		}
+	}
+	return codes
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Search feedback event types
+const (
+	FeedbackImpression = "impression" // a result page was shown
+	FeedbackClick      = "click"      // a product of a result page was opened
+)
+
+var (
+	// ErrExperimentNotFound is returned for an unknown ranking experiment ID
+	ErrExperimentNotFound = errors.New("experiment not found")
+	// ErrVariantMismatch is returned for feedback naming another variant than the visitor's
+	ErrVariantMismatch = errors.New("variant does not match the visitor's assignment")
+	// ErrVisitorRequired is returned for feedback without a user, guest or visitor_id
+	ErrVisitorRequired = errors.New("user, guest or visitor_id is required")
+	// ErrInvalidFeedback is returned for feedback that does not fit its type
+	ErrInvalidFeedback = errors.New("invalid feedback")
+)
+
+// RankingParams tune the relevance ranking of a text search: field weights of the text
+// match and how much recent sales and rating add to the score. The zero value of a
+// factor leaves the signal out
+type RankingParams struct {
+	NameBoost        float64 `json:"name_boost"`        // weight of the name match (default ranking: 3)
+	DescriptionBoost float64 `json:"description_boost"` // weight of the description match (default ranking: 2)
+	SalesFactor      float64 `json:"sales_factor"`      // adds log1p(factor * sold_30d)
+	RatingFactor     float64 `json:"rating_factor"`     // adds factor * rating_avg
+}
+
+// RankingVariant is one arm of a ranking experiment; a variant without Ranking is the
+// control and keeps the default ranking
+type RankingVariant struct {
+	Name    string         `json:"name"`
+	Weight  int            `json:"weight"` // share of the experiment's visitors, relative to the other variants
+	Ranking *RankingParams `json:"ranking,omitempty"`
+}
+
+// Experiment splits the visitors of text searches ranked by relevance between ranking
+// variants, so their click-through rates can be compared
+type Experiment struct {
+	ID       string           `json:"id"`
+	Name     string           `json:"name"`
+	Enabled  bool             `json:"enabled"` // only one experiment runs at a time
+	Variants []RankingVariant `json:"variants"`
+}
+
+// ExperimentAssignment tags a search result with the experiment variant that ranked it;
+// clients send it back with their feedback
+type ExperimentAssignment struct {
+	Experiment string `json:"experiment"`
+	Variant    string `json:"variant"`
+}
+
+// SearchFeedback is an impression or click logged by a client for an experiment variant
+type SearchFeedback struct {
+	Type       string    `json:"type"` // FeedbackImpression or FeedbackClick
+	Experiment string    `json:"experiment"`
+	Variant    string    `json:"variant"`
+	Visitor    string    `json:"visitor"` // user:<id>, guest:<id> or the client's visitor ID
+	Query      string    `json:"query,omitempty"`
+	ProductIDs []uint    `json:"product_ids,omitempty"` // impression: products shown; click: the product opened
+	Position   int       `json:"position,omitempty"`    // click: 1-based rank of the product in the results
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// VariantStats are the feedback counters of a variant over a period
+type VariantStats struct {
+	Variant     string  `json:"variant"`
+	Impressions int64   `json:"impressions"`
+	Clicks      int64   `json:"clicks"`
+	CTR         float64 `json:"ctr"` // clicks / impressions, 0 without impressions
+}
+
+// ExperimentReport compares the variants of an experiment over a period
+type ExperimentReport struct {
+	Experiment *Experiment    `json:"experiment"`
+	From       time.Time      `json:"from"`
+	To         time.Time      `json:"to"`
+	Variants   []VariantStats `json:"variants"` // in the experiment's order, variants without feedback included
+}
+
+// FeedbackRepository stores search feedback and counts it per variant (implemented in Elasticsearch)
+type FeedbackRepository interface {
+	Record(ctx context.Context, feedback *SearchFeedback) error
+	// CountByVariant counts the impressions and clicks of each variant of the experiment
+	// logged in [from, to)
+	CountByVariant(ctx context.Context, experimentID string, from, to time.Time) (map[string]VariantStats, error)
+}
+
+// RankingExperimenter assigns the visitor of a search to a ranking variant, nil when no
+// experiment runs (implemented by service.ExperimentService)
+type RankingExperimenter interface {
+	RankingFor(visitor string) (*ExperimentAssignment, *RankingParams)
+}
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SalesWindow is the period of the recent sales counter (Product.Sold30d)
+const SalesWindow = 30 * 24 * time.Hour
+
+// OrderEvent is the part of order-service's OrderEvent (schema v2) used by the sales projection
+type OrderEvent struct {
+	EventType     string         `json:"event_type"` // order_created, order_cancelled
+	SchemaVersion int            `json:"schema_version"`
+	OrderID       uint           `json:"order_id"`
+	Order         *OrderSnapshot `json:"order"`
+	Timestamp     time.Time      `json:"timestamp"`
+}
+
+// OrderSnapshot is the order payload of an OrderEvent
+type OrderSnapshot struct {
+	ID            uint        `json:"id"`
+	Status        string      `json:"status"`
+	PaymentMethod string      `json:"payment_method"`
+	Items         []OrderLine `json:"items"`
+	OrderedAt     time.Time   `json:"ordered_at"`
+	UpdatedAt     time.Time   `json:"updated_at"` // Orders the events of one order across topics
+}
+
+// OrderLine is an order item of an OrderSnapshot
+type OrderLine struct {
+	ProductID     uint `json:"product_id"`
+	ProductItemID uint `json:"product_item_id"`
+	Quantity      int  `json:"quantity"`
+}
+
+// CountsAsSale reports whether the order's units count as sold
+// Paid orders (and later stages) count; COD orders count once placed since they are paid
+// on delivery. Cancelled orders never count
+func (o *OrderSnapshot) CountsAsSale() bool {
+	switch o.Status {
+	case "paid", "confirmed", "processing", "shipped", "delivered":
+		return true
+	case "pending":
+		return strings.EqualFold(o.PaymentMethod, "COD")
+	default:
+		return false
+	}
+}
+
+// ProductIDs returns the distinct products of the order
+func (o *OrderSnapshot) ProductIDs() []uint {
+	seen := make(map[uint]bool, len(o.Items))
+	ids := make([]uint, 0, len(o.Items))
+	for _, item := range o.Items {
+		if item.ProductID == 0 || seen[item.ProductID] {
+			continue
+		}
+		seen[item.ProductID] = true
+		ids = append(ids, item.ProductID)
+	}
+	return ids
+}
+
+// SalesRepository stores order lines and projects the sold ones onto product documents
+type SalesRepository interface {
+	// SaveOrderLines stores the lines of an order as sold or not (CountsAsSale)
+	// Idempotent per order line; a snapshot older than the stored one is ignored
+	SaveOrderLines(ctx context.Context, order *OrderSnapshot) error
+	RefreshProducts(ctx context.Context, productIDs []uint) error // Recompute sold_count / sold_30d
+	RefreshAll(ctx context.Context) (int, error)                  // Every product with sales (window decay)
+}
+
+// SoldLabel formats a sold count for product cards, e.g. "Đã bán 1,2k"
+func SoldLabel(sold int64) string {
+	switch {
+	case sold <= 0:
+		return ""
+	case sold < 1000:
+		return fmt.Sprintf("Đã bán %d", sold)
+	case sold < 1000000:
+		return "Đã bán " + compactCount(sold, 1000) + "k"
+	default:
+		return "Đã bán " + compactCount(sold, 1000000) + "tr"
+	}
+}
+
+// compactCount divides n by unit with one (truncated) decimal and a decimal comma: 1250/1000 -> "1,2"
+func compactCount(n, unit int64) string {
+	whole, tenth := n/unit, (n%unit)*10/unit
+	if tenth == 0 {
+		return fmt.Sprintf("%d", whole)
+	}
+	return fmt.Sprintf("%d,%d", whole, tenth)
+}
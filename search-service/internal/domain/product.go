@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"errors"
 	"time"
 )
 
@@ -19,32 +20,103 @@ type Product struct {
 	IsActive    bool      `json:"is_active"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Sales projection from order events (written separately, kept on product updates)
+	SoldCount int64  `json:"sold_count,omitempty"`
+	Sold30d   int64  `json:"sold_30d,omitempty"`
+	SoldLabel string `json:"sold_label,omitempty"` // e.g. "Đã bán 1,2k", set on search results
+
+	// Bestseller rank over product-service's rolling sales window (1 = best, 0 = not
+	// ranked), written by product-service and kept on product updates
+	SalesRank int `json:"sales_rank,omitempty"`
+
+	// Rating snapshot of published reviews (from product events)
+	RatingAvg   float64 `json:"rating_avg"`
+	RatingCount int     `json:"rating_count"`
+
+	// Rating aggregate of all reviews of the shop (from shop_rating_updated events,
+	// written onto every product of the shop and kept on product updates)
+	ShopRatingAvg   float64 `json:"shop_rating_avg,omitempty"`
+	ShopRatingCount int     `json:"shop_rating_count,omitempty"`
+
+	// Ships-from location of the shop (from product events)
+	ShopID            uint      `json:"shop_id,omitempty"`
+	ShipsFromProvince string    `json:"ships_from_province,omitempty"` // province code, e.g. "ho-chi-minh"
+	ShopLocation      *GeoPoint `json:"shop_location,omitempty"`
+
+	// Set on search results only: distance to the buyer (nearest sort) and delivery
+	// estimate to the buyer's province
+	DistanceKm *float64          `json:"distance_km,omitempty"`
+	Delivery   *DeliveryEstimate `json:"delivery,omitempty"`
+}
+
+// GeoPoint is a latitude/longitude pair (Elasticsearch geo_point object format)
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
 }
 
 // ProductEvent represents a domain event for product changes from Kafka
 // Events are used for inter-service communication
 type ProductEvent struct {
-	EventType   string    `json:"event_type"`   // e.g., "product_created", "product_updated", "product_deleted"
+	EventType   string    `json:"event_type"`   // e.g., "product_created", "product_updated", "product_deleted", "shop_rating_updated"
 	ProductID   uint      `json:"product_id"`
 	ProductData *Product  `json:"product_data"`
 	Timestamp   time.Time `json:"timestamp"`
 	Metadata    interface{} `json:"metadata,omitempty"`
 }
 
+// ShopRatingEvent is the metadata of a shop_rating_updated event: the rating aggregate of
+// all reviews of a shop, recomputed by product-service when a review changes
+type ShopRatingEvent struct {
+	ShopID      uint    `json:"shop_id"`
+	RatingAvg   float64 `json:"rating_avg"`
+	RatingCount int     `json:"rating_count"`
+}
+
 // SearchFilters represents search filters
 type SearchFilters struct {
 	CategoryID *uint    `json:"category_id,omitempty"`
 	MinPrice   *float64 `json:"min_price,omitempty"`
 	MaxPrice   *float64 `json:"max_price,omitempty"`
+	MinRating  *float64 `json:"min_rating,omitempty"`
 	Status     *string  `json:"status,omitempty"`
+
+	ShipsFrom       []string `json:"ships_from,omitempty"`        // province codes the shop ships from
+	MaxDeliveryDays *int     `json:"max_delivery_days,omitempty"` // needs SearchRequest.BuyerProvince
 }
 
+// SortBestSelling sorts by bestseller rank, then sales of the last 30 days, then all-time
+// sales (products without sales last)
+const SortBestSelling = "best_selling"
+
+// SortNearest sorts by distance from SearchRequest.BuyerLocation to the shop (always
+// ascending, products without a shop location last)
+const SortNearest = "nearest"
+
 // SearchSort represents sort options
 type SearchSort struct {
-	Field string `json:"field"` // "price", "name", "created_at"
+	Field string `json:"field"` // "price", "name", "created_at", "sold_count", "rating_avg", SortBestSelling, SortNearest
 	Order string `json:"order"` // "asc", "desc"
 }
 
+// MaxSearchOffset caps page/limit pagination (page * limit); deeper results are read
+// with the cursor, which Elasticsearch serves with search_after at constant cost
+const MaxSearchOffset = 1000
+
+var (
+	// ErrSearchTooDeep is returned for a page beyond MaxSearchOffset
+	ErrSearchTooDeep = errors.New("page is too deep, continue with next_cursor")
+	// ErrInvalidCursor is returned for a cursor that is malformed or from another sort
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrUnknownProvince is returned for a ships_from or buyer_province that is not a province
+	ErrUnknownProvince = errors.New("unknown province")
+	// ErrBuyerLocationRequired is returned for the nearest sort without valid buyer coordinates
+	ErrBuyerLocationRequired = errors.New("sorting by nearest needs a valid buyer lat and lon")
+	// ErrBuyerProvinceRequired is returned for max_delivery_days without buyer_province
+	ErrBuyerProvinceRequired = errors.New("max_delivery_days needs buyer_province")
+)
+
 // SearchRequest represents a search request
 type SearchRequest struct {
 	Query   string         `json:"query"`
@@ -52,14 +124,33 @@ type SearchRequest struct {
 	Sort    *SearchSort    `json:"sort,omitempty"`
 	Page    int            `json:"page"`
 	Limit   int            `json:"limit"`
+	Cursor  string         `json:"cursor,omitempty"` // next_cursor of the previous page; Page is then ignored
+
+	// Buyer for delivery estimates (province code) and the nearest sort
+	BuyerProvince string    `json:"buyer_province,omitempty"`
+	BuyerLocation *GeoPoint `json:"buyer_location,omitempty"`
+
+	// Campaign landing searches (banner links) get the curations of the campaign
+	CampaignID *uint `json:"campaign_id,omitempty"`
+	// Set by the service from the active curations; part of the cache key, so a curation
+	// that changes or starts never serves results cached without it
+	Curation *SearchCuration `json:"curation,omitempty"`
+
+	// Visitor (user:<id>, guest:<id> or the client's visitor ID) assigned to ranking
+	// experiments; not part of the cache key, the variant's Ranking is
+	Visitor string         `json:"-"`
+	Ranking *RankingParams `json:"ranking,omitempty"` // Set by the service from the visitor's experiment variant
 }
 
 // SearchResult represents search results with pagination
 type SearchResult struct {
-	Products []*Product `json:"products"`
-	Total    int64      `json:"total"`
-	Page     int        `json:"page"`
-	Limit    int        `json:"limit"`
+	Products   []*Product `json:"products"`
+	Total      int64      `json:"total"`
+	Page       int        `json:"page"`
+	Limit      int        `json:"limit"`
+	NextCursor string     `json:"next_cursor,omitempty"` // Pass as cursor for the next page; empty on the last page
+
+	Experiment *ExperimentAssignment `json:"experiment,omitempty"` // Ranking variant of the results, sent back with feedback
 }
 
 // SearchRepository defines the interface for search operations
@@ -69,6 +160,7 @@ type SearchRepository interface {
 	UpdateProduct(product *Product) error
 	DeleteProduct(id uint) error
 	SearchProducts(req *SearchRequest) (*SearchResult, error)
+	UpdateShopRating(shopID uint, avg float64, count int) error // On every product of the shop
 }
 
 
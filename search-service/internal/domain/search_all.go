@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+// Groups of the universal search (search dropdown): products from the index, shops from
+// identity-service, categories from product-service
+const (
+	SearchGroupProducts   = "products"
+	SearchGroupShops      = "shops"
+	SearchGroupCategories = "categories"
+)
+
+// SearchGroups lists every group in response order
+var SearchGroups = []string{SearchGroupProducts, SearchGroupShops, SearchGroupCategories}
+
+// Items per group of the universal search
+const (
+	DefaultSearchAllLimit = 5
+	MaxSearchAllLimit     = 20
+)
+
+var (
+	// ErrSearchQueryRequired is returned for a universal search without text
+	ErrSearchQueryRequired = errors.New("q is required")
+	// ErrUnknownSearchGroup is returned for a type that is not a group
+	ErrUnknownSearchGroup = errors.New("type must be products, shops or categories")
+	// ErrSearchGroupRequired is returned for a cursor without the type of its group
+	ErrSearchGroupRequired = errors.New("cursor needs the type of its group")
+)
+
+// SearchAllRequest represents a universal search request
+type SearchAllRequest struct {
+	Query  string `json:"query"`
+	Type   string `json:"type,omitempty"`   // Only this group ("view more"); all groups when empty
+	Cursor string `json:"cursor,omitempty"` // next_cursor of the group named by Type
+	Limit  int    `json:"limit"`            // Items per group
+}
+
+// SearchGroup is one group of universal search results
+type SearchGroup struct {
+	Items       interface{} `json:"items"` // []*Product, []*ShopHit or []*CategoryHit
+	Total       int64       `json:"total"`
+	NextCursor  string      `json:"next_cursor,omitempty"` // Pass as cursor with type=<group> for more; empty on the last page
+	Unavailable bool        `json:"unavailable,omitempty"` // The group could not be searched in time (items empty)
+}
+
+// SearchAllResult represents universal search results grouped by type; with a type only
+// that group is set
+type SearchAllResult struct {
+	Query      string       `json:"query"`
+	Products   *SearchGroup `json:"products,omitempty"`
+	Shops      *SearchGroup `json:"shops,omitempty"`
+	Categories *SearchGroup `json:"categories,omitempty"`
+}
+
+// ShopHit is a shop matching a universal search
+type ShopHit struct {
+	ID         uint    `json:"id"`
+	Name       string  `json:"name"`
+	Slug       string  `json:"slug"`
+	LogoURL    string  `json:"logo_url,omitempty"`
+	IsOfficial bool    `json:"is_official"`
+	Rating     float64 `json:"rating"`
+	Province   string  `json:"province,omitempty"`
+}
+
+// CategoryHit is a category matching a universal search
+type CategoryHit struct {
+	ID       uint   `json:"id"`
+	ParentID *uint  `json:"parent_id,omitempty"`
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+	ImageURL string `json:"image_url,omitempty"`
+}
+
+// ShopSearcher searches shops by name (implemented over identity-service)
+type ShopSearcher interface {
+	SearchShops(ctx context.Context, query, cursor string, limit int) (*SearchGroup, error)
+}
+
+// CategorySearcher searches categories by name (implemented over product-service)
+type CategorySearcher interface {
+	SearchCategories(ctx context.Context, query, cursor string, limit int) (*SearchGroup, error)
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "context"
+
+// SearchCache keeps the first page of popular searches so identical queries don't all
+// reach Elasticsearch (implemented in repository/redis)
+type SearchCache interface {
+	// Get returns the cached result of a page-1 request, nil on a miss
+	Get(ctx context.Context, req *SearchRequest) (*SearchResult, error)
+	// Store caches the result once the request has become popular
+	Store(ctx context.Context, req *SearchRequest, result *SearchResult) error
+	// InvalidateCategories drops the cached searches that can list products of the
+	// categories: those filtered on one of them and the unfiltered ones
+	InvalidateCategories(ctx context.Context, categoryIDs []uint) error
+	// InvalidateAll drops every cached search
+	InvalidateAll(ctx context.Context) error
+}
+
+// SearchCacheStats counts the lookups and invalidations of the search cache
+type SearchCacheStats struct {
+	Hits          int64   `json:"hits"`
+	Misses        int64   `json:"misses"`
+	HitRate       float64 `json:"hit_rate"`      // Hits / (Hits + Misses), 0 before the first lookup
+	Stores        int64   `json:"stores"`        // Results written (popular queries only)
+	Invalidations int64   `json:"invalidations"` // Cached results dropped by product events
+	Errors        int64   `json:"errors"`        // Failed Redis calls (the search then goes to Elasticsearch)
+}
+
+// SearchCacheStatsProvider exposes search cache metrics (implemented by redis.SearchCache)
+type SearchCacheStatsProvider interface {
+	SearchCacheStats() SearchCacheStats
+}
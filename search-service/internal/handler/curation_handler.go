@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"search-service/internal/domain"
+	"search-service/internal/service"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// CurationHandler handles admin HTTP requests for search curations
+type CurationHandler struct {
+	curationService *service.CurationService
+	logger          *zap.Logger
+}
+
+// NewCurationHandler creates a new curation handler
+func NewCurationHandler(curationService *service.CurationService, logger *zap.Logger) *CurationHandler {
+	return &CurationHandler{
+		curationService: curationService,
+		logger:          logger,
+	}
+}
+
+// CurationRequest represents the request body for creating or replacing a curation
+type CurationRequest struct {
+	Keyword          string     `json:"keyword" binding:"max=100" example:"áo thun"`
+	CampaignID       *uint      `json:"campaign_id" binding:"omitempty,min=1"`
+	PinnedProductIDs []uint     `json:"pinned_product_ids" example:"12,7"`
+	BoostedShopIDs   []uint     `json:"boosted_shop_ids" example:"3"`
+	ShopBoost        float64    `json:"shop_boost" binding:"omitempty,gt=1,lte=10" example:"2"`
+	StartAt          *time.Time `json:"start_at"`
+	EndAt            *time.Time `json:"end_at"`
+	Note             string     `json:"note" binding:"max=500"`
+}
+
+func (r *CurationRequest) toCuration() *domain.Curation {
+	return &domain.Curation{
+		Keyword:          r.Keyword,
+		CampaignID:       r.CampaignID,
+		PinnedProductIDs: r.PinnedProductIDs,
+		BoostedShopIDs:   r.BoostedShopIDs,
+		ShopBoost:        r.ShopBoost,
+		StartAt:          r.StartAt,
+		EndAt:            r.EndAt,
+		Note:             r.Note,
+	}
+}
+
+// ListCurations handles GET /admin/search/curations
+// @Summary List search curations (admin)
+// @Description Every curation, newest first, with active=true when it currently applies
+// @Tags Admin
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Curations"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Router /admin/search/curations [get]
+func (h *CurationHandler) ListCurations(c *gin.Context) {
+	curations, err := h.curationService.ListCurations(c.Request.Context())
+	if err != nil {
+		h.respondError(c, "failed to list curations", err)
+		return
+	}
+
+	now := time.Now()
+	items := make([]gin.H, 0, len(curations))
+	for _, curation := range curations {
+		items = append(items, gin.H{"curation": curation, "active": curation.IsActiveAt(now)})
+	}
+	c.JSON(http.StatusOK, gin.H{"curations": items, "total": len(items)})
+}
+
+// GetCuration handles GET /admin/search/curations/:id
+// @Summary Get a search curation (admin)
+// @Tags Admin
+// @Produce json
+// @Param id path string true "Curation ID"
+// @Success 200 {object} domain.Curation "Curation"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Failure 404 {object} map[string]string "Curation not found"
+// @Router /admin/search/curations/{id} [get]
+func (h *CurationHandler) GetCuration(c *gin.Context) {
+	curation, err := h.curationService.GetCuration(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondError(c, "failed to get curation", err)
+		return
+	}
+	c.JSON(http.StatusOK, curation)
+}
+
+// CreateCuration handles POST /admin/search/curations
+// @Summary Create a search curation (admin)
+// @Description Pin products (shown first, in order) and/or boost shops (relevance multiplied by shop_boost, default 2) for the searches of a keyword (ignoring case and diacritics) and/or a campaign_id, between start_at and end_at. Only searches ranked by relevance are curated
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param request body CurationRequest true "Curation"
+// @Success 201 {object} domain.Curation "Created curation"
+// @Failure 400 {object} map[string]string "Invalid curation"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Router /admin/search/curations [post]
+func (h *CurationHandler) CreateCuration(c *gin.Context) {
+	var req CurationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	curation, err := h.curationService.CreateCuration(c.Request.Context(), req.toCuration(), adminUserID(c))
+	if err != nil {
+		h.respondError(c, "failed to create curation", err)
+		return
+	}
+	c.JSON(http.StatusCreated, curation)
+}
+
+// UpdateCuration handles PUT /admin/search/curations/:id
+// @Summary Replace a search curation (admin)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Curation ID"
+// @Param request body CurationRequest true "Curation"
+// @Success 200 {object} domain.Curation "Updated curation"
+// @Failure 400 {object} map[string]string "Invalid curation"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Failure 404 {object} map[string]string "Curation not found"
+// @Router /admin/search/curations/{id} [put]
+func (h *CurationHandler) UpdateCuration(c *gin.Context) {
+	var req CurationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	curation, err := h.curationService.UpdateCuration(c.Request.Context(), c.Param("id"), req.toCuration(), adminUserID(c))
+	if err != nil {
+		h.respondError(c, "failed to update curation", err)
+		return
+	}
+	c.JSON(http.StatusOK, curation)
+}
+
+// DeleteCuration handles DELETE /admin/search/curations/:id
+// @Summary Delete a search curation (admin)
+// @Description The audit trail of the curation is kept
+// @Tags Admin
+// @Param id path string true "Curation ID"
+// @Success 204 "Deleted"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Failure 404 {object} map[string]string "Curation not found"
+// @Router /admin/search/curations/{id} [delete]
+func (h *CurationHandler) DeleteCuration(c *gin.Context) {
+	if err := h.curationService.DeleteCuration(c.Request.Context(), c.Param("id"), adminUserID(c)); err != nil {
+		h.respondError(c, "failed to delete curation", err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetCurationAudit handles GET /admin/search/curations/:id/audit
+// @Summary Get search curation audit (admin)
+// @Description Change history of a curation (who, when, before and after), newest first; kept after deletion
+// @Tags Admin
+// @Produce json
+// @Param id path string true "Curation ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} map[string]interface{} "Audit entries"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Router /admin/search/curations/{id}/audit [get]
+func (h *CurationHandler) GetCurationAudit(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	audits, total, err := h.curationService.GetCurationAudit(c.Request.Context(), c.Param("id"), page, limit)
+	if err != nil {
+		h.respondError(c, "failed to get curation audit", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"audits": audits,
+		"total":  total,
+		"page":   page,
+		"limit":  limit,
+	})
+}
+
+// respondError maps curation errors to 404/400, anything else to 500
+func (h *CurationHandler) respondError(c *gin.Context, msg string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrCurationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrCurationTargetRequired) || errors.Is(err, domain.ErrCurationEmpty) ||
+		errors.Is(err, domain.ErrTooManyPinned) || errors.Is(err, domain.ErrInvalidShopBoost) ||
+		errors.Is(err, domain.ErrInvalidSchedule):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error(msg, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+	}
+}
+
+// adminUserID returns the admin's user ID set by the API Gateway (0 when missing)
+func adminUserID(c *gin.Context) uint {
+	id, _ := strconv.ParseUint(c.GetHeader("X-User-Id"), 10, 64)
+	return uint(id)
+}
@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"net/http"
+	"search-service/pkg/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondBindError answers a request that failed binding with 400: one entry per
+// rejected field when the binding tags failed, the decoding error otherwise
+func respondBindError(c *gin.Context, err error) {
+	if fields := validation.Errors(err); fields != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "validation failed", "fields": fields})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
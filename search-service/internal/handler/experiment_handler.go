@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"search-service/internal/domain"
+	"search-service/internal/service"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ExperimentHandler handles HTTP requests for search ranking experiments: feedback logged
+// by clients and the admin reports
+type ExperimentHandler struct {
+	experimentService *service.ExperimentService
+	logger            *zap.Logger
+}
+
+// NewExperimentHandler creates a new experiment handler
+func NewExperimentHandler(experimentService *service.ExperimentService, logger *zap.Logger) *ExperimentHandler {
+	return &ExperimentHandler{
+		experimentService: experimentService,
+		logger:            logger,
+	}
+}
+
+// RecordFeedback handles POST /search/feedback
+// @Summary Log search feedback
+// @Description Log an impression (a result page was shown) or a click (a product of it was opened) for the experiment and variant the search result was tagged with. The visitor is the signed-in user, else the guest, else visitor_id, as for the search
+// @Tags Search
+// @Accept json
+// @Produce json
+// @Param visitor_id query string false "Client visitor ID, when neither signed in nor a guest"
+// @Param request body service.FeedbackRequest true "Feedback"
+// @Success 202 "Feedback recorded"
+// @Failure 400 {object} map[string]string "Invalid feedback, no visitor or not the visitor's variant"
+// @Failure 404 {object} map[string]string "Experiment not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /search/feedback [post]
+func (h *ExperimentHandler) RecordFeedback(c *gin.Context) {
+	var req service.FeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	if err := h.experimentService.RecordFeedback(c.Request.Context(), searchVisitor(c), &req); err != nil {
+		h.respondError(c, "failed to record search feedback", err)
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// ListExperiments handles GET /admin/search/experiments
+// @Summary List ranking experiments (admin)
+// @Description Every configured ranking experiment with its per-variant impressions, clicks and CTR over the last days
+// @Tags Admin
+// @Produce json
+// @Param days query int false "Report period in days (1-90)" default(7)
+// @Success 200 {object} map[string]interface{} "Experiment reports"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/search/experiments [get]
+func (h *ExperimentHandler) ListExperiments(c *gin.Context) {
+	days := reportDays(c)
+	experiments := h.experimentService.ListExperiments()
+	reports := make([]*domain.ExperimentReport, 0, len(experiments))
+	for _, experiment := range experiments {
+		report, err := h.experimentService.Report(c.Request.Context(), experiment.ID, days)
+		if err != nil {
+			h.respondError(c, "failed to report experiment", err)
+			return
+		}
+		reports = append(reports, report)
+	}
+	c.JSON(http.StatusOK, gin.H{"experiments": reports, "total": len(reports)})
+}
+
+// GetExperimentReport handles GET /admin/search/experiments/:id
+// @Summary Get a ranking experiment report (admin)
+// @Description Impressions, clicks and click-through rate of each variant over the last days
+// @Tags Admin
+// @Produce json
+// @Param id path string true "Experiment ID"
+// @Param days query int false "Report period in days (1-90)" default(7)
+// @Success 200 {object} domain.ExperimentReport "Experiment report"
+// @Failure 403 {object} map[string]string "Admin only"
+// @Failure 404 {object} map[string]string "Experiment not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/search/experiments/{id} [get]
+func (h *ExperimentHandler) GetExperimentReport(c *gin.Context) {
+	report, err := h.experimentService.Report(c.Request.Context(), c.Param("id"), reportDays(c))
+	if err != nil {
+		h.respondError(c, "failed to report experiment", err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// respondError maps experiment errors to 404/400, anything else to 500
+func (h *ExperimentHandler) respondError(c *gin.Context, msg string, err error) {
+	switch {
+	case errors.Is(err, domain.ErrExperimentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrVariantMismatch) || errors.Is(err, domain.ErrVisitorRequired) ||
+		errors.Is(err, domain.ErrInvalidFeedback):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		h.logger.Error(msg, zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": msg})
+	}
+}
+
+// searchVisitor identifies the visitor of a search for experiment bucketing: the user or
+// guest set by the API Gateway, else the client's visitor_id ("" when none)
+func searchVisitor(c *gin.Context) string {
+	if userID := c.GetHeader("X-User-Id"); userID != "" {
+		return "user:" + userID
+	}
+	if guestID := c.GetHeader("X-Guest-Id"); guestID != "" {
+		return "guest:" + guestID
+	}
+	if visitorID := c.Query("visitor_id"); visitorID != "" && len(visitorID) <= 64 {
+		return "visitor:" + visitorID
+	}
+	return ""
+}
+
+// reportDays reads the report period; the service falls back to 7 days when out of range
+func reportDays(c *gin.Context) int {
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "7"))
+	return days
+}
@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"search-service/internal/domain"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// HealthHandler serves liveness, readiness, Kafka consumer and search cache metrics
+type HealthHandler struct {
+	consumer domain.ConsumerStatsProvider
+	cache    domain.SearchCacheStatsProvider // nil when search_cache is disabled
+	maxLag   int64
+	logger   *zap.Logger
+}
+
+// NewHealthHandler creates a new health handler
+// maxLag is the total consumer lag above which /readyz reports not ready (<= 0 disables it)
+func NewHealthHandler(consumer domain.ConsumerStatsProvider, cache domain.SearchCacheStatsProvider, maxLag int64, logger *zap.Logger) *HealthHandler {
+	return &HealthHandler{
+		consumer: consumer,
+		cache:    cache,
+		maxLag:   maxLag,
+		logger:   logger,
+	}
+}
+
+// HealthCheck handles GET /health
+// Always 200 while the process is up; includes the consumer and cache metrics
+func (h *HealthHandler) HealthCheck(c *gin.Context) {
+	body := gin.H{
+		"status":   "ok",
+		"service":  "search-service",
+		"consumer": h.consumer.ConsumerStats(),
+	}
+	if h.cache != nil {
+		body["search_cache"] = h.cache.SearchCacheStats()
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+// Ready handles GET /readyz
+// 503 when the consumer is not running or its total lag exceeds the threshold
+func (h *HealthHandler) Ready(c *gin.Context) {
+	stats := h.consumer.ConsumerStats()
+
+	status, reason := "ready", ""
+	switch {
+	case !stats.Running:
+		status, reason = "not_ready", "kafka consumer is not running"
+	case h.maxLag > 0 && stats.TotalLag > h.maxLag:
+		status, reason = "lagging", fmt.Sprintf("kafka consumer lag %d exceeds %d", stats.TotalLag, h.maxLag)
+	}
+
+	body := gin.H{
+		"status":    status,
+		"service":   "search-service",
+		"total_lag": stats.TotalLag,
+		"max_lag":   h.maxLag,
+	}
+	if reason == "" {
+		c.JSON(http.StatusOK, body)
+		return
+	}
+
+	body["reason"] = reason
+	h.logger.Warn("search-service not ready", zap.String("reason", reason))
+	c.JSON(http.StatusServiceUnavailable, body)
+}
+
+// Metrics handles GET /metrics in the Prometheus text exposition format
+func (h *HealthHandler) Metrics(c *gin.Context) {
+	stats := h.consumer.ConsumerStats()
+
+	var b strings.Builder
+	metric := func(name, kind, help string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	}
+
+	running := 0
+	if stats.Running {
+		running = 1
+	}
+	metric("search_consumer_up", "gauge", "Whether the Kafka consumer loop is running.")
+	fmt.Fprintf(&b, "search_consumer_up %d\n", running)
+
+	metric("search_consumer_messages_total", "counter", "Kafka messages processed.")
+	fmt.Fprintf(&b, "search_consumer_messages_total %d\n", stats.Processed)
+
+	metric("search_consumer_errors_total", "counter", "Kafka messages that failed to process.")
+	fmt.Fprintf(&b, "search_consumer_errors_total %d\n", stats.Errors)
+
+	metric("search_consumer_messages_per_second", "gauge", "Messages processed per second over the last minute.")
+	fmt.Fprintf(&b, "search_consumer_messages_per_second %g\n", stats.MessagesPerSecond)
+
+	metric("search_consumer_processing_seconds", "summary", "Time spent processing Kafka messages.")
+	fmt.Fprintf(&b, "search_consumer_processing_seconds_sum %g\n", stats.LatencySeconds)
+	fmt.Fprintf(&b, "search_consumer_processing_seconds_count %d\n", stats.Processed)
+
+	metric("search_consumer_processing_seconds_max", "gauge", "Slowest Kafka message processing time.")
+	fmt.Fprintf(&b, "search_consumer_processing_seconds_max %g\n", stats.MaxLatencyMs/1000)
+
+	metric("search_consumer_lag", "gauge", "Messages behind the partition high water mark.")
+	for _, p := range stats.Partitions {
+		fmt.Fprintf(&b, "search_consumer_lag{topic=%q,partition=\"%d\"} %d\n", p.Topic, p.Partition, p.Lag)
+	}
+
+	metric("search_consumer_lag_total", "gauge", "Total lag over all partitions.")
+	fmt.Fprintf(&b, "search_consumer_lag_total %d\n", stats.TotalLag)
+
+	if h.cache != nil {
+		cache := h.cache.SearchCacheStats()
+
+		metric("search_cache_hits_total", "counter", "Searches answered from the result cache.")
+		fmt.Fprintf(&b, "search_cache_hits_total %d\n", cache.Hits)
+
+		metric("search_cache_misses_total", "counter", "Cacheable searches sent to Elasticsearch.")
+		fmt.Fprintf(&b, "search_cache_misses_total %d\n", cache.Misses)
+
+		metric("search_cache_hit_ratio", "gauge", "Share of cacheable searches answered from the cache.")
+		fmt.Fprintf(&b, "search_cache_hit_ratio %g\n", cache.HitRate)
+
+		metric("search_cache_stores_total", "counter", "Search results written to the cache.")
+		fmt.Fprintf(&b, "search_cache_stores_total %d\n", cache.Stores)
+
+		metric("search_cache_invalidations_total", "counter", "Cached search results dropped by product events.")
+		fmt.Fprintf(&b, "search_cache_invalidations_total %d\n", cache.Invalidations)
+
+		metric("search_cache_errors_total", "counter", "Failed search cache calls to Redis.")
+		fmt.Fprintf(&b, "search_cache_errors_total %d\n", cache.Errors)
+	}
+
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(b.String()))
+}
@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"search-service/internal/domain"
 	"search-service/internal/service"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -14,35 +16,46 @@ import (
 // This is the transport layer - it knows HOW to handle HTTP (Gin framework)
 // It delegates business logic to the service layer
 type SearchHandler struct {
-	searchService *service.SearchService
-	logger        *zap.Logger
+	searchService    *service.SearchService
+	searchAllService *service.SearchAllService
+	logger           *zap.Logger
 }
 
 // NewSearchHandler creates a new search handler
 // Dependency injection: we inject the service
-func NewSearchHandler(searchService *service.SearchService, logger *zap.Logger) *SearchHandler {
+func NewSearchHandler(searchService *service.SearchService, searchAllService *service.SearchAllService, logger *zap.Logger) *SearchHandler {
 	return &SearchHandler{
-		searchService: searchService,
-		logger:        logger,
+		searchService:    searchService,
+		searchAllService: searchAllService,
+		logger:           logger,
 	}
 }
 
 // SearchProducts handles GET /search
 // @Summary Search products
-// @Description Search products by keyword with filters (category, price range) and sort options
+// @Description Search products by keyword with filters (category, price range, minimum rating, ships-from province, delivery time) and sort options; with buyer_province every product gets a delivery estimate (same province 1-2 days, same region 2-3, neighbouring region 3-5, north-south 4-6)
 // @Tags Search
 // @Produce json
 // @Param q query string false "Search keyword"
 // @Param category_id query int false "Filter by category ID"
 // @Param min_price query number false "Minimum price"
 // @Param max_price query number false "Maximum price"
+// @Param min_rating query number false "Minimum average rating (1-5)"
 // @Param status query string false "Filter by status (ACTIVE, INACTIVE)"
-// @Param sort_field query string false "Sort field (price, name, created_at)" default(created_at)
+// @Param ships_from query string false "Ships-from provinces, comma-separated codes or names (e.g. ho-chi-minh, Hồ Chí Minh)"
+// @Param max_delivery_days query int false "Only products delivered to buyer_province within this many days"
+// @Param buyer_province query string false "Buyer province (code or name) for delivery estimates"
+// @Param lat query number false "Buyer latitude (sort_field=nearest)"
+// @Param lon query number false "Buyer longitude (sort_field=nearest)"
+// @Param campaign_id query int false "Campaign landing search: applies the campaign's curations (pinned products, boosted shops)"
+// @Param sort_field query string false "Sort field (price, name, created_at, sold_count, rating_avg, best_selling, nearest)" default(created_at)
 // @Param sort_order query string false "Sort order (asc, desc)" default(desc)
-// @Param page query int false "Page number" default(1)
+// @Param page query int false "Page number, up to 1000 results deep (page * limit)" default(1)
 // @Param limit query int false "Items per page" default(20)
+// @Param cursor query string false "next_cursor of the previous page (any depth); page is ignored"
+// @Param visitor_id query string false "Client visitor ID for ranking experiments, when neither signed in nor a guest; the result names the experiment variant that ranked it"
 // @Success 200 {object} domain.SearchResult "Search results"
-// @Failure 400 {object} map[string]string "Invalid request parameters"
+// @Failure 400 {object} map[string]string "Page too deep, invalid cursor, unknown province or missing buyer location"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /search [get]
 func (h *SearchHandler) SearchProducts(c *gin.Context) {
@@ -83,6 +96,15 @@ func (h *SearchHandler) SearchProducts(c *gin.Context) {
 		}
 	}
 
+	if minRatingStr := c.Query("min_rating"); minRatingStr != "" {
+		if minRating, err := strconv.ParseFloat(minRatingStr, 64); err == nil && minRating > 0 {
+			if filters == nil {
+				filters = &domain.SearchFilters{}
+			}
+			filters.MinRating = &minRating
+		}
+	}
+
 	if status := c.Query("status"); status != "" {
 		if filters == nil {
 			filters = &domain.SearchFilters{}
@@ -90,6 +112,46 @@ func (h *SearchHandler) SearchProducts(c *gin.Context) {
 		filters.Status = &status
 	}
 
+	var shipsFrom []string
+	for _, value := range c.QueryArray("ships_from") {
+		for _, province := range strings.Split(value, ",") {
+			if province = strings.TrimSpace(province); province != "" {
+				shipsFrom = append(shipsFrom, province)
+			}
+		}
+	}
+	if len(shipsFrom) > 0 {
+		if filters == nil {
+			filters = &domain.SearchFilters{}
+		}
+		filters.ShipsFrom = shipsFrom
+	}
+
+	if maxDaysStr := c.Query("max_delivery_days"); maxDaysStr != "" {
+		if maxDays, err := strconv.Atoi(maxDaysStr); err == nil && maxDays > 0 {
+			if filters == nil {
+				filters = &domain.SearchFilters{}
+			}
+			filters.MaxDeliveryDays = &maxDays
+		}
+	}
+
+	// Parse buyer location
+	var buyerLocation *domain.GeoPoint
+	lat, latErr := strconv.ParseFloat(c.Query("lat"), 64)
+	lon, lonErr := strconv.ParseFloat(c.Query("lon"), 64)
+	if latErr == nil && lonErr == nil {
+		buyerLocation = &domain.GeoPoint{Lat: lat, Lon: lon}
+	}
+
+	var campaignID *uint
+	if campaignIDStr := c.Query("campaign_id"); campaignIDStr != "" {
+		if id, err := strconv.ParseUint(campaignIDStr, 10, 32); err == nil && id > 0 {
+			campaignIDUint := uint(id)
+			campaignID = &campaignIDUint
+		}
+	}
+
 	// Parse sort
 	var sort *domain.SearchSort
 	if sortField := c.Query("sort_field"); sortField != "" {
@@ -106,10 +168,23 @@ func (h *SearchHandler) SearchProducts(c *gin.Context) {
 		Sort:    sort,
 		Page:    page,
 		Limit:   limit,
+		Cursor:  c.Query("cursor"),
+
+		BuyerProvince: strings.TrimSpace(c.Query("buyer_province")),
+		BuyerLocation: buyerLocation,
+		CampaignID:    campaignID,
+
+		Visitor: searchVisitor(c),
 	}
 
 	// Call service layer
 	result, err := h.searchService.SearchProducts(c.Request.Context(), searchReq)
+	if errors.Is(err, domain.ErrSearchTooDeep) || errors.Is(err, domain.ErrInvalidCursor) ||
+		errors.Is(err, domain.ErrUnknownProvince) || errors.Is(err, domain.ErrBuyerLocationRequired) ||
+		errors.Is(err, domain.ErrBuyerProvinceRequired) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		h.logger.Error("failed to search products", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -119,10 +194,39 @@ func (h *SearchHandler) SearchProducts(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
-// HealthCheck handles GET /health
-func (h *SearchHandler) HealthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"status": "ok", "service": "search-service"})
-}
-
-
+// SearchAll handles GET /search/all
+// @Summary Universal search
+// @Description Search products, shops and categories in parallel for the search dropdown; each group has its total and a next_cursor to load more with type=<group>. A group that cannot be searched in time is returned empty with unavailable=true
+// @Tags Search
+// @Produce json
+// @Param q query string true "Search keyword"
+// @Param type query string false "Only this group, to load more (products, shops, categories)"
+// @Param cursor query string false "next_cursor of the group named by type"
+// @Param limit query int false "Items per group (max 20)" default(5)
+// @Success 200 {object} domain.SearchAllResult "Grouped results"
+// @Failure 400 {object} map[string]string "Missing q, unknown type or invalid cursor"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /search/all [get]
+func (h *SearchHandler) SearchAll(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	result, err := h.searchAllService.SearchAll(c.Request.Context(), &domain.SearchAllRequest{
+		Query:  c.Query("q"),
+		Type:   c.Query("type"),
+		Cursor: c.Query("cursor"),
+		Limit:  limit,
+	})
+	if errors.Is(err, domain.ErrSearchQueryRequired) || errors.Is(err, domain.ErrUnknownSearchGroup) ||
+		errors.Is(err, domain.ErrSearchGroupRequired) || errors.Is(err, domain.ErrInvalidCursor) ||
+		errors.Is(err, domain.ErrSearchTooDeep) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to search all", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
+	c.JSON(http.StatusOK, result)
+}
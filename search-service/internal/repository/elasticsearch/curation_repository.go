@@ -0,0 +1,236 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"search-service/internal/domain"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+)
+
+// maxCurations is the most curations listed (and applied); admins curate a few hundred
+// keywords and campaigns at most
+const maxCurations = 1000
+
+// curationRepository implements the CurationRepository interface
+// Curations and their audit trail live in their own indexes; writes wait for a refresh so
+// the reload that follows an admin change sees it
+type curationRepository struct {
+	client     *elasticsearch.Client
+	index      string
+	auditIndex string
+}
+
+// NewCurationRepository creates a new Elasticsearch curation repository
+func NewCurationRepository(client *elasticsearch.Client, index, auditIndex string) domain.CurationRepository {
+	return &curationRepository{
+		client:     client,
+		index:      index,
+		auditIndex: auditIndex,
+	}
+}
+
+// Create stores a new curation; Elasticsearch generates its ID
+func (r *curationRepository) Create(ctx context.Context, curation *domain.Curation) error {
+	id, err := r.put(ctx, r.index, "", curation)
+	if err != nil {
+		return err
+	}
+	curation.ID = id
+	return nil
+}
+
+// Update replaces a stored curation
+func (r *curationRepository) Update(ctx context.Context, curation *domain.Curation) error {
+	_, err := r.put(ctx, r.index, curation.ID, curation)
+	return err
+}
+
+// Delete removes a curation (its audit trail is kept)
+func (r *curationRepository) Delete(ctx context.Context, id string) error {
+	req := esapi.DeleteRequest{
+		Index:      r.index,
+		DocumentID: id,
+		Refresh:    "wait_for",
+	}
+
+	res, err := req.Do(ctx, r.client)
+	if err != nil {
+		return fmt.Errorf("failed to delete curation: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return domain.ErrCurationNotFound
+	}
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch error: %s", res.String())
+	}
+	return nil
+}
+
+// Get returns a curation by ID
+func (r *curationRepository) Get(ctx context.Context, id string) (*domain.Curation, error) {
+	req := esapi.GetRequest{
+		Index:      r.index,
+		DocumentID: id,
+	}
+
+	res, err := req.Do(ctx, r.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get curation: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, domain.ErrCurationNotFound
+	}
+	if res.IsError() {
+		return nil, fmt.Errorf("elasticsearch error: %s", res.String())
+	}
+
+	var doc struct {
+		ID     string          `json:"_id"`
+		Source domain.Curation `json:"_source"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode curation: %w", err)
+	}
+	doc.Source.ID = doc.ID
+	return &doc.Source, nil
+}
+
+// List returns every curation, newest first
+func (r *curationRepository) List(ctx context.Context) ([]*domain.Curation, error) {
+	query := map[string]interface{}{
+		"size":  maxCurations,
+		"query": map[string]interface{}{"match_all": map[string]interface{}{}},
+		"sort": []map[string]interface{}{
+			{"created_at": map[string]interface{}{"order": "desc"}},
+		},
+	}
+
+	var result struct {
+		Hits struct {
+			Hits []struct {
+				ID     string          `json:"_id"`
+				Source domain.Curation `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := r.search(ctx, r.index, query, &result); err != nil {
+		return nil, err
+	}
+
+	curations := make([]*domain.Curation, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		curation := hit.Source
+		curation.ID = hit.ID
+		curations = append(curations, &curation)
+	}
+	return curations, nil
+}
+
+// CreateAudit appends an entry to the audit trail
+func (r *curationRepository) CreateAudit(ctx context.Context, audit *domain.CurationAudit) error {
+	_, err := r.put(ctx, r.auditIndex, "", audit)
+	return err
+}
+
+// ListAudit returns the audit trail of a curation with pagination, newest first
+func (r *curationRepository) ListAudit(ctx context.Context, curationID string, page, limit int) ([]*domain.CurationAudit, int64, error) {
+	query := map[string]interface{}{
+		"from": (page - 1) * limit,
+		"size": limit,
+		"query": map[string]interface{}{
+			"term": map[string]interface{}{"curation_id": curationID},
+		},
+		"sort": []map[string]interface{}{
+			{"created_at": map[string]interface{}{"order": "desc"}},
+		},
+	}
+
+	var result struct {
+		Hits struct {
+			Total struct {
+				Value int64 `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				Source domain.CurationAudit `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := r.search(ctx, r.auditIndex, query, &result); err != nil {
+		return nil, 0, err
+	}
+
+	audits := make([]*domain.CurationAudit, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		audit := hit.Source
+		audits = append(audits, &audit)
+	}
+	return audits, result.Hits.Total.Value, nil
+}
+
+// put indexes a document (ID generated when empty) and returns its ID
+func (r *curationRepository) put(ctx context.Context, index, id string, doc interface{}) (string, error) {
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal document: %w", err)
+	}
+
+	req := esapi.IndexRequest{
+		Index:      index,
+		DocumentID: id,
+		Body:       bytes.NewReader(body),
+		Refresh:    "wait_for",
+	}
+
+	res, err := req.Do(ctx, r.client)
+	if err != nil {
+		return "", fmt.Errorf("failed to index document: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return "", fmt.Errorf("elasticsearch error: %s", res.String())
+	}
+
+	var indexed struct {
+		ID string `json:"_id"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&indexed); err != nil {
+		return "", fmt.Errorf("failed to decode index response: %w", err)
+	}
+	return indexed.ID, nil
+}
+
+func (r *curationRepository) search(ctx context.Context, index string, query map[string]interface{}, result interface{}) error {
+	queryJSON, err := json.Marshal(query)
+	if err != nil {
+		return fmt.Errorf("failed to marshal query: %w", err)
+	}
+
+	res, err := r.client.Search(
+		r.client.Search.WithContext(ctx),
+		r.client.Search.WithIndex(index),
+		r.client.Search.WithBody(bytes.NewReader(queryJSON)),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to search %s: %w", index, err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch error: %s", res.String())
+	}
+
+	if err := json.NewDecoder(res.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode search response: %w", err)
+	}
+	return nil
+}
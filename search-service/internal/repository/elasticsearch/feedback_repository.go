@@ -0,0 +1,134 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"search-service/internal/domain"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+)
+
+// feedbackRepository implements the FeedbackRepository interface
+// Feedback is written to a time-series index (through its write alias when managed by the
+// index lifecycle) without waiting for a refresh; reports tolerate the refresh delay
+type feedbackRepository struct {
+	client *elasticsearch.Client
+	index  string
+}
+
+// NewFeedbackRepository creates a new Elasticsearch search feedback repository
+func NewFeedbackRepository(client *elasticsearch.Client, index string) domain.FeedbackRepository {
+	return &feedbackRepository{
+		client: client,
+		index:  index,
+	}
+}
+
+// Record stores one impression or click
+func (r *feedbackRepository) Record(ctx context.Context, feedback *domain.SearchFeedback) error {
+	body, err := json.Marshal(feedback)
+	if err != nil {
+		return fmt.Errorf("failed to marshal feedback: %w", err)
+	}
+
+	req := esapi.IndexRequest{
+		Index: r.index,
+		Body:  bytes.NewReader(body),
+	}
+
+	res, err := req.Do(ctx, r.client)
+	if err != nil {
+		return fmt.Errorf("failed to index feedback: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch error: %s", res.String())
+	}
+	return nil
+}
+
+// CountByVariant counts the feedback of the experiment per variant and type in [from, to)
+func (r *feedbackRepository) CountByVariant(ctx context.Context, experimentID string, from, to time.Time) (map[string]domain.VariantStats, error) {
+	query := map[string]interface{}{
+		"size": 0,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []map[string]interface{}{
+					{"term": map[string]interface{}{"experiment": experimentID}},
+					{"range": map[string]interface{}{"created_at": map[string]interface{}{
+						"gte": from.Format(time.RFC3339),
+						"lt":  to.Format(time.RFC3339),
+					}}},
+				},
+			},
+		},
+		"aggs": map[string]interface{}{
+			"variants": map[string]interface{}{
+				"terms": map[string]interface{}{"field": "variant", "size": 100},
+				"aggs": map[string]interface{}{
+					"types": map[string]interface{}{
+						"terms": map[string]interface{}{"field": "type", "size": 10},
+					},
+				},
+			},
+		},
+	}
+
+	body, err := json.Marshal(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal feedback query: %w", err)
+	}
+
+	res, err := r.client.Search(
+		r.client.Search.WithContext(ctx),
+		r.client.Search.WithIndex(r.index),
+		r.client.Search.WithBody(bytes.NewReader(body)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count feedback: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, fmt.Errorf("elasticsearch error: %s", res.String())
+	}
+
+	var result struct {
+		Aggregations struct {
+			Variants struct {
+				Buckets []struct {
+					Key   string `json:"key"`
+					Types struct {
+						Buckets []struct {
+							Key      string `json:"key"`
+							DocCount int64  `json:"doc_count"`
+						} `json:"buckets"`
+					} `json:"types"`
+				} `json:"buckets"`
+			} `json:"variants"`
+		} `json:"aggregations"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode feedback counts: %w", err)
+	}
+
+	counts := make(map[string]domain.VariantStats, len(result.Aggregations.Variants.Buckets))
+	for _, variant := range result.Aggregations.Variants.Buckets {
+		stats := domain.VariantStats{Variant: variant.Key}
+		for _, typ := range variant.Types.Buckets {
+			switch typ.Key {
+			case domain.FeedbackImpression:
+				stats.Impressions = typ.DocCount
+			case domain.FeedbackClick:
+				stats.Clicks = typ.DocCount
+			}
+		}
+		counts[variant.Key] = stats
+	}
+	return counts, nil
+}
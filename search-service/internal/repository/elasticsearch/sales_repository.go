@@ -0,0 +1,319 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"search-service/internal/domain"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+)
+
+// refreshAllPageSize is the number of products per composite aggregation page
+const refreshAllPageSize = 500
+
+// saleLine is one sold order line in the sales index
+type saleLine struct {
+	OrderID       uint      `json:"order_id"`
+	ProductID     uint      `json:"product_id"`
+	ProductItemID uint      `json:"product_item_id"`
+	Quantity      int       `json:"quantity"`
+	Cancelled     bool      `json:"cancelled"` // Kept (not deleted) so a late order_created can't revive it
+	OrderedAt     time.Time `json:"ordered_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// salesRepository implements the SalesRepository interface
+// Order lines live in their own index, one document per order line versioned by the order's
+// updated_at: replays are idempotent and events read out of order (order.created and
+// order.cancelled are separate topics) never overwrite a newer state. Totals of the lines
+// that are not cancelled are copied onto the product documents as sold_count / sold_30d
+type salesRepository struct {
+	client       *elasticsearch.Client
+	salesIndex   string
+	productIndex string
+}
+
+// NewSalesRepository creates a new Elasticsearch sales repository
+func NewSalesRepository(client *elasticsearch.Client, salesIndex, productIndex string) domain.SalesRepository {
+	return &salesRepository{
+		client:       client,
+		salesIndex:   salesIndex,
+		productIndex: productIndex,
+	}
+}
+
+// SaveOrderLines indexes the lines of an order (same IDs on replay)
+func (r *salesRepository) SaveOrderLines(ctx context.Context, order *domain.OrderSnapshot) error {
+	cancelled := !order.CountsAsSale()
+	version := order.UpdatedAt.UnixMilli()
+
+	var body bytes.Buffer
+	for _, item := range order.Items {
+		if item.ProductID == 0 || item.Quantity <= 0 {
+			continue
+		}
+		line := saleLine{
+			OrderID:       order.ID,
+			ProductID:     item.ProductID,
+			ProductItemID: item.ProductItemID,
+			Quantity:      item.Quantity,
+			Cancelled:     cancelled,
+			OrderedAt:     order.OrderedAt,
+			UpdatedAt:     order.UpdatedAt,
+		}
+		doc, err := json.Marshal(line)
+		if err != nil {
+			return fmt.Errorf("failed to marshal order line: %w", err)
+		}
+		fmt.Fprintf(&body, `{"index":{"_id":"%d-%d","version":%d,"version_type":"external_gte"}}`+"\n",
+			order.ID, item.ProductItemID, version)
+		body.Write(doc)
+		body.WriteByte('\n')
+	}
+	if body.Len() == 0 {
+		return nil
+	}
+
+	// wait_for: the refresh that follows must see these lines
+	// 409 = a newer snapshot of the order is already stored
+	return r.bulk(ctx, r.salesIndex, &body, "wait_for", http.StatusConflict)
+}
+
+// RefreshProducts recomputes the sales counters of the given products
+// Products without (remaining) sales are reset to 0
+func (r *salesRepository) RefreshProducts(ctx context.Context, productIDs []uint) error {
+	if len(productIDs) == 0 {
+		return nil
+	}
+
+	query := map[string]interface{}{
+		"size": 0,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": []map[string]interface{}{
+					{"terms": map[string]interface{}{"product_id": productIDs}},
+					{"term": map[string]interface{}{"cancelled": false}},
+				},
+			},
+		},
+		"aggs": map[string]interface{}{
+			"products": map[string]interface{}{
+				"terms": map[string]interface{}{"field": "product_id", "size": len(productIDs)},
+				"aggs":  salesAggs(),
+			},
+		},
+	}
+
+	var result struct {
+		Aggregations struct {
+			Products struct {
+				Buckets []salesBucket `json:"buckets"`
+			} `json:"products"`
+		} `json:"aggregations"`
+	}
+	if err := r.search(ctx, query, &result); err != nil {
+		return err
+	}
+
+	sales := make(map[uint]domain.Product, len(productIDs))
+	for _, id := range productIDs {
+		sales[id] = domain.Product{ID: id}
+	}
+	for _, b := range result.Aggregations.Products.Buckets {
+		sales[b.productID()] = b.product()
+	}
+	return r.updateProducts(ctx, sales)
+}
+
+// RefreshAll recomputes the counters of every product with sales, so sold_30d decays as
+// sales leave the window. Returns the number of products updated
+func (r *salesRepository) RefreshAll(ctx context.Context) (int, error) {
+	updated := 0
+	var after map[string]interface{}
+
+	for {
+		composite := map[string]interface{}{
+			"size":    refreshAllPageSize,
+			"sources": []map[string]interface{}{{"product_id": map[string]interface{}{"terms": map[string]interface{}{"field": "product_id"}}}},
+		}
+		if after != nil {
+			composite["after"] = after
+		}
+		query := map[string]interface{}{
+			"size":  0,
+			"query": map[string]interface{}{"term": map[string]interface{}{"cancelled": false}},
+			"aggs": map[string]interface{}{
+				"products": map[string]interface{}{
+					"composite": composite,
+					"aggs":      salesAggs(),
+				},
+			},
+		}
+
+		var result struct {
+			Aggregations struct {
+				Products struct {
+					AfterKey map[string]interface{} `json:"after_key"`
+					Buckets  []salesBucket          `json:"buckets"`
+				} `json:"products"`
+			} `json:"aggregations"`
+		}
+		if err := r.search(ctx, query, &result); err != nil {
+			return updated, err
+		}
+
+		page := result.Aggregations.Products
+		if len(page.Buckets) == 0 {
+			return updated, nil
+		}
+
+		sales := make(map[uint]domain.Product, len(page.Buckets))
+		for _, b := range page.Buckets {
+			sales[b.productID()] = b.product()
+		}
+		if err := r.updateProducts(ctx, sales); err != nil {
+			return updated, err
+		}
+		updated += len(sales)
+
+		if page.AfterKey == nil || len(page.Buckets) < refreshAllPageSize {
+			return updated, nil
+		}
+		after = page.AfterKey
+	}
+}
+
+// salesAggs are the per-product sub-aggregations: all-time and in-window units
+func salesAggs() map[string]interface{} {
+	since := time.Now().Add(-domain.SalesWindow).Format(time.RFC3339)
+	return map[string]interface{}{
+		"sold": map[string]interface{}{"sum": map[string]interface{}{"field": "quantity"}},
+		"recent": map[string]interface{}{
+			"filter": map[string]interface{}{"range": map[string]interface{}{"ordered_at": map[string]interface{}{"gte": since}}},
+			"aggs":   map[string]interface{}{"sold": map[string]interface{}{"sum": map[string]interface{}{"field": "quantity"}}},
+		},
+	}
+}
+
+// salesBucket is a terms or composite bucket with salesAggs
+type salesBucket struct {
+	Key  json.RawMessage `json:"key"` // number (terms) or {"product_id": number} (composite)
+	Sold struct {
+		Value float64 `json:"value"`
+	} `json:"sold"`
+	Recent struct {
+		Sold struct {
+			Value float64 `json:"value"`
+		} `json:"sold"`
+	} `json:"recent"`
+}
+
+func (b salesBucket) productID() uint {
+	var id float64
+	if err := json.Unmarshal(b.Key, &id); err == nil {
+		return uint(id)
+	}
+	var composite struct {
+		ProductID float64 `json:"product_id"`
+	}
+	_ = json.Unmarshal(b.Key, &composite)
+	return uint(composite.ProductID)
+}
+
+func (b salesBucket) product() domain.Product {
+	return domain.Product{
+		ID:        b.productID(),
+		SoldCount: int64(b.Sold.Value),
+		Sold30d:   int64(b.Recent.Sold.Value),
+	}
+}
+
+// updateProducts writes sold_count / sold_30d onto product documents
+// Products that are not indexed yet are skipped (RefreshAll catches up later)
+func (r *salesRepository) updateProducts(ctx context.Context, sales map[uint]domain.Product) error {
+	var body bytes.Buffer
+	for id, p := range sales {
+		fmt.Fprintf(&body, `{"update":{"_id":"%d"}}`+"\n", id)
+		fmt.Fprintf(&body, `{"doc":{"sold_count":%d,"sold_30d":%d}}`+"\n", p.SoldCount, p.Sold30d)
+	}
+	// 404 = product not indexed yet
+	return r.bulk(ctx, r.productIndex, &body, "false", http.StatusNotFound)
+}
+
+func (r *salesRepository) search(ctx context.Context, query map[string]interface{}, out interface{}) error {
+	queryJSON, err := json.Marshal(query)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sales query: %w", err)
+	}
+
+	res, err := r.client.Search(
+		r.client.Search.WithContext(ctx),
+		r.client.Search.WithIndex(r.salesIndex),
+		r.client.Search.WithBody(bytes.NewReader(queryJSON)),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to aggregate sales: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch error: %s", res.String())
+	}
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode sales aggregation: %w", err)
+	}
+	return nil
+}
+
+// bulkItemResult is the per-action result of a bulk response
+type bulkItemResult struct {
+	Status int `json:"status"`
+}
+
+// bulk runs a bulk request; item errors fail it unless their status is ignoreStatus
+func (r *salesRepository) bulk(ctx context.Context, index string, body *bytes.Buffer, refresh string, ignoreStatus int) error {
+	req := esapi.BulkRequest{
+		Index:   index,
+		Body:    body,
+		Refresh: refresh,
+	}
+
+	res, err := req.Do(ctx, r.client)
+	if err != nil {
+		return fmt.Errorf("failed to execute bulk request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch error: %s", res.String())
+	}
+
+	var result struct {
+		Errors bool                        `json:"errors"`
+		Items  []map[string]bulkItemResult `json:"items"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode bulk response: %w", err)
+	}
+	if !result.Errors {
+		return nil
+	}
+
+	failed := 0
+	for _, item := range result.Items {
+		for _, op := range item {
+			if op.Status >= 300 && op.Status != ignoreStatus {
+				failed++
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("bulk request to %s: %d item(s) failed", index, failed)
+	}
+	return nil
+}
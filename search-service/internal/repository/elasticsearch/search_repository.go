@@ -3,8 +3,11 @@ package elasticsearch
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"hash/crc32"
+	"math"
 	"search-service/internal/domain"
 	"strings"
 
@@ -38,11 +41,12 @@ func (r *searchRepository) IndexProduct(product *domain.Product) error {
 		return fmt.Errorf("failed to marshal product: %w", err)
 	}
 
-	// Create index request
-	req := esapi.IndexRequest{
+	// Partial update (upsert) so the sales projection fields survive product updates
+	body := fmt.Sprintf(`{"doc":%s,"doc_as_upsert":true}`, productJSON)
+	req := esapi.UpdateRequest{
 		Index:      r.indexName,
 		DocumentID: fmt.Sprintf("%d", product.ID),
-		Body:       bytes.NewReader(productJSON),
+		Body:       strings.NewReader(body),
 		Refresh:    "true", // Make the document immediately searchable
 	}
 
@@ -88,6 +92,46 @@ func (r *searchRepository) DeleteProduct(id uint) error {
 	return nil
 }
 
+// UpdateShopRating writes the shop rating aggregate onto every product document of the shop
+func (r *searchRepository) UpdateShopRating(shopID uint, avg float64, count int) error {
+	ctx := context.Background()
+
+	body, err := json.Marshal(map[string]interface{}{
+		"query": map[string]interface{}{
+			"term": map[string]interface{}{"shop_id": shopID},
+		},
+		"script": map[string]interface{}{
+			"source": "ctx._source.shop_rating_avg = params.avg; ctx._source.shop_rating_count = params.count",
+			"lang":   "painless",
+			"params": map[string]interface{}{"avg": avg, "count": count},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal shop rating update: %w", err)
+	}
+
+	conflicts := "proceed" // A product updated meanwhile keeps its newer version; the next event catches up
+	refresh := true
+	req := esapi.UpdateByQueryRequest{
+		Index:     []string{r.indexName},
+		Body:      bytes.NewReader(body),
+		Conflicts: conflicts,
+		Refresh:   &refresh,
+	}
+
+	res, err := req.Do(ctx, r.client)
+	if err != nil {
+		return fmt.Errorf("failed to update shop rating: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch error: %s", res.String())
+	}
+
+	return nil
+}
+
 // SearchProducts performs a search query with filters, sort, and pagination
 func (r *searchRepository) SearchProducts(req *domain.SearchRequest) (*domain.SearchResult, error) {
 	ctx := context.Background()
@@ -121,10 +165,14 @@ func (r *searchRepository) SearchProducts(req *domain.SearchRequest) (*domain.Se
 
 	// Add text search if query is provided
 	if strings.TrimSpace(req.Query) != "" {
+		fields := []string{"name^3", "description^2", "sku"}
+		if req.Ranking != nil {
+			fields = rankingFields(req.Ranking)
+		}
 		mustClauses = append(mustClauses, map[string]interface{}{
 			"multi_match": map[string]interface{}{
 				"query":  req.Query,
-				"fields": []string{"name^3", "description^2", "sku"},
+				"fields": fields,
 				"type":   "best_fields",
 				"fuzziness": "AUTO",
 			},
@@ -156,6 +204,14 @@ func (r *searchRepository) SearchProducts(req *domain.SearchRequest) (*domain.Se
 			})
 		}
 
+		if req.Filters.MinRating != nil {
+			filterClauses = append(filterClauses, map[string]interface{}{
+				"range": map[string]interface{}{
+					"rating_avg": map[string]interface{}{"gte": *req.Filters.MinRating},
+				},
+			})
+		}
+
 		if req.Filters.Status != nil {
 			filterClauses = append(filterClauses, map[string]interface{}{
 				"term": map[string]interface{}{
@@ -163,12 +219,41 @@ func (r *searchRepository) SearchProducts(req *domain.SearchRequest) (*domain.Se
 				},
 			})
 		}
+
+		if len(req.Filters.ShipsFrom) > 0 {
+			filterClauses = append(filterClauses, map[string]interface{}{
+				"terms": map[string]interface{}{
+					"ships_from_province": req.Filters.ShipsFrom,
+				},
+			})
+		}
+
+		// Delivery time only depends on the two provinces, so the filter is the list of
+		// provinces fast enough for the buyer
+		if req.Filters.MaxDeliveryDays != nil && req.BuyerProvince != "" {
+			filterClauses = append(filterClauses, map[string]interface{}{
+				"terms": map[string]interface{}{
+					"ships_from_province": domain.ProvincesDeliveringWithin(req.BuyerProvince, *req.Filters.MaxDeliveryDays),
+				},
+			})
+		}
+	}
+
+	// Ranking experiment variant: recent sales and rating add to the text score
+	if req.Ranking != nil && len(mustClauses) > 0 {
+		mustClauses = rankingSignals(req.Ranking, mustClauses)
 	}
 
 	// Update clauses
 	boolQuery["must"] = mustClauses
 	boolQuery["filter"] = filterClauses
 
+	// Admin curation: boosted shops multiply the relevance of their products and pinned
+	// products come first, in order; the filters still apply to both
+	if req.Curation != nil {
+		query["query"] = curatedQuery(req.Curation, mustClauses, filterClauses)
+	}
+
 	// Add sort
 	if req.Sort != nil {
 		sortField := req.Sort.Field
@@ -181,12 +266,31 @@ func (r *searchRepository) SearchProducts(req *domain.SearchRequest) (*domain.Se
 			sortOrder = "desc"
 		}
 
-		query["sort"] = []map[string]interface{}{
-			{
-				sortField: map[string]interface{}{
-					"order": sortOrder,
+		if sortsByDistance(req) {
+			// Products of shops without a location sort last
+			query["sort"] = []map[string]interface{}{
+				{"_geo_distance": map[string]interface{}{
+					"shop_location":   req.BuyerLocation,
+					"order":           "asc",
+					"unit":            "km",
+					"ignore_unmapped": true,
+				}},
+			}
+		} else if sortField == domain.SortBestSelling {
+			// Ranked bestsellers first, then recent and all-time sales (products without sales last)
+			query["sort"] = []map[string]interface{}{
+				{"sales_rank": map[string]interface{}{"order": "asc", "missing": "_last", "unmapped_type": "integer"}},
+				{"sold_30d": map[string]interface{}{"order": "desc", "unmapped_type": "long"}},
+				{"sold_count": map[string]interface{}{"order": "desc", "unmapped_type": "long"}},
+			}
+		} else {
+			query["sort"] = []map[string]interface{}{
+				{
+					sortField: map[string]interface{}{
+						"order": sortOrder,
+					},
 				},
-			},
+			}
 		}
 	} else {
 		// Default sort by relevance
@@ -198,7 +302,7 @@ func (r *searchRepository) SearchProducts(req *domain.SearchRequest) (*domain.Se
 			},
 		}
 
-		// If no query, sort by created_at desc
+		// If no query, sort by created_at desc (after the curation's pins and boosts)
 		if strings.TrimSpace(req.Query) == "" {
 			query["sort"] = []map[string]interface{}{
 				{
@@ -207,9 +311,35 @@ func (r *searchRepository) SearchProducts(req *domain.SearchRequest) (*domain.Se
 					},
 				},
 			}
+			if req.Curation != nil {
+				query["sort"] = append([]map[string]interface{}{
+					{"_score": map[string]interface{}{"order": "desc"}},
+				}, query["sort"].([]map[string]interface{})...)
+			}
 		}
 	}
 
+	// Product ID breaks ties so the order is total and search_after never skips or
+	// repeats a product between pages
+	sortClauses := append(query["sort"].([]map[string]interface{}), map[string]interface{}{
+		"id": map[string]interface{}{"order": "asc"},
+	})
+	query["sort"] = sortClauses
+	sortKey, err := cursorSortKey(sortClauses)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cursor pagination: continue after the last hit of the previous page
+	if req.Cursor != "" {
+		after, err := decodeCursor(req.Cursor, sortKey)
+		if err != nil {
+			return nil, err
+		}
+		query["from"] = 0
+		query["search_after"] = after
+	}
+
 	// Convert to JSON
 	queryJSON, err := json.Marshal(query)
 	if err != nil {
@@ -251,27 +381,177 @@ func (r *searchRepository) SearchProducts(req *domain.SearchRequest) (*domain.Se
 
 	// Extract products from hits
 	products := make([]*domain.Product, 0)
+	var lastSort []interface{}
+	hitCount := 0
 	if hits, ok := result["hits"].(map[string]interface{}); ok {
 		if hitsArray, ok := hits["hits"].([]interface{}); ok {
+			hitCount = len(hitsArray)
 			for _, hit := range hitsArray {
 				hitMap := hit.(map[string]interface{})
 				source := hitMap["_source"].(map[string]interface{})
+				lastSort, _ = hitMap["sort"].([]interface{})
 
 				// Convert to Product struct
 				productJSON, _ := json.Marshal(source)
 				var product domain.Product
 				if err := json.Unmarshal(productJSON, &product); err == nil {
+					// The distance is the first sort value ("Infinity" without a shop location)
+					if sortsByDistance(req) && len(lastSort) > 0 {
+						if km, ok := lastSort[0].(float64); ok {
+							km = math.Round(km*10) / 10
+							product.DistanceKm = &km
+						}
+					}
 					products = append(products, &product)
 				}
 			}
 		}
 	}
 
+	// A full page may be followed by more results
+	var nextCursor string
+	if hitCount == req.Limit && lastSort != nil {
+		if nextCursor, err = encodeCursor(sortKey, lastSort); err != nil {
+			return nil, err
+		}
+	}
+
 	return &domain.SearchResult{
-		Products: products,
-		Total:    total,
-		Page:     req.Page,
-		Limit:    req.Limit,
+		Products:   products,
+		Total:      total,
+		Page:       req.Page,
+		Limit:      req.Limit,
+		NextCursor: nextCursor,
 	}, nil
 }
 
+// curatedQuery wraps the text query: function_score multiplies the score of the boosted
+// shops' products (the highest boost when several apply), pinned ranks the pinned products
+// above every other result
+func curatedQuery(curation *domain.SearchCuration, must, filter []map[string]interface{}) map[string]interface{} {
+	organic := map[string]interface{}{"match_all": map[string]interface{}{}}
+	if len(must) > 0 {
+		organic = map[string]interface{}{"bool": map[string]interface{}{"must": must}}
+	}
+
+	if len(curation.ShopBoosts) > 0 {
+		functions := make([]map[string]interface{}, 0, len(curation.ShopBoosts))
+		for _, boost := range curation.ShopBoosts {
+			functions = append(functions, map[string]interface{}{
+				"filter": map[string]interface{}{"terms": map[string]interface{}{"shop_id": boost.ShopIDs}},
+				"weight": boost.Boost,
+			})
+		}
+		organic = map[string]interface{}{
+			"function_score": map[string]interface{}{
+				"query":      organic,
+				"functions":  functions,
+				"score_mode": "max",
+				"boost_mode": "multiply",
+			},
+		}
+	}
+
+	if len(curation.PinnedProductIDs) > 0 {
+		ids := make([]string, 0, len(curation.PinnedProductIDs))
+		for _, id := range curation.PinnedProductIDs {
+			ids = append(ids, fmt.Sprintf("%d", id))
+		}
+		organic = map[string]interface{}{
+			"pinned": map[string]interface{}{"ids": ids, "organic": organic},
+		}
+	}
+
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"must":   []map[string]interface{}{organic},
+			"filter": filter,
+		},
+	}
+}
+
+// rankingFields weights the text match fields as the experiment variant says
+func rankingFields(ranking *domain.RankingParams) []string {
+	fields := []string{fmt.Sprintf("name^%g", ranking.NameBoost)}
+	if ranking.DescriptionBoost > 0 {
+		fields = append(fields, fmt.Sprintf("description^%g", ranking.DescriptionBoost))
+	}
+	return append(fields, "sku")
+}
+
+// rankingSignals wraps the text query in a function_score adding the variant's sales and
+// rating signals to its score; products without sales or rating get nothing added
+func rankingSignals(ranking *domain.RankingParams, must []map[string]interface{}) []map[string]interface{} {
+	var functions []map[string]interface{}
+	if ranking.SalesFactor > 0 {
+		functions = append(functions, map[string]interface{}{
+			"field_value_factor": map[string]interface{}{
+				"field":    "sold_30d",
+				"factor":   ranking.SalesFactor,
+				"modifier": "log1p",
+				"missing":  0,
+			},
+		})
+	}
+	if ranking.RatingFactor > 0 {
+		functions = append(functions, map[string]interface{}{
+			"field_value_factor": map[string]interface{}{
+				"field":   "rating_avg",
+				"factor":  ranking.RatingFactor,
+				"missing": 0,
+			},
+		})
+	}
+	if len(functions) == 0 {
+		return must
+	}
+
+	return []map[string]interface{}{{
+		"function_score": map[string]interface{}{
+			"query":      map[string]interface{}{"bool": map[string]interface{}{"must": must}},
+			"functions":  functions,
+			"score_mode": "sum",
+			"boost_mode": "sum",
+		},
+	}}
+}
+
+func sortsByDistance(req *domain.SearchRequest) bool {
+	return req.Sort != nil && req.Sort.Field == domain.SortNearest && req.BuyerLocation != nil
+}
+
+// searchCursor is the position after the last hit of a page: its sort values, and a
+// checksum of the sort so a cursor cannot be replayed against another sort
+type searchCursor struct {
+	Sort  uint32        `json:"s"`
+	After []interface{} `json:"a"`
+}
+
+func cursorSortKey(sort []map[string]interface{}) (uint32, error) {
+	sortJSON, err := json.Marshal(sort)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal sort: %w", err)
+	}
+	return crc32.ChecksumIEEE(sortJSON), nil
+}
+
+func encodeCursor(sortKey uint32, after []interface{}) (string, error) {
+	cursorJSON, err := json.Marshal(searchCursor{Sort: sortKey, After: after})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(cursorJSON), nil
+}
+
+func decodeCursor(cursor string, sortKey uint32) ([]interface{}, error) {
+	cursorJSON, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, domain.ErrInvalidCursor
+	}
+	var c searchCursor
+	if err := json.Unmarshal(cursorJSON, &c); err != nil || c.Sort != sortKey || len(c.After) == 0 {
+		return nil, domain.ErrInvalidCursor
+	}
+	return c.After, nil
+}
+
@@ -0,0 +1,185 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"search-service/internal/domain"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+// BackfillOptions configures a backfill run
+type BackfillOptions struct {
+	Brokers       []string
+	Topics        []string      // Replayed one after another, in this order
+	ConsumerGroup string        // Separate from the live consumer; committed offsets let a run resume
+	OffsetReset   string        // "earliest" or "latest": where to start when the group has no offsets
+	IdleTimeout   time.Duration // A topic is done once caught up and idle this long
+	MinBytes      int
+	MaxBytes      int
+}
+
+// BackfillResult summarizes a backfill run
+type BackfillResult struct {
+	Topics   map[string]int64 `json:"topics"` // Messages replayed per topic
+	Applied  int64            `json:"applied"`
+	Failed   int64            `json:"failed"`
+	Duration time.Duration    `json:"duration"`
+}
+
+// BackfillConsumer bootstraps a search index by replaying product topics
+// Topics are expected to be compacted and keyed by product ID (as product-service
+// publishes them), so a replay yields the latest event of every product. Replaying
+// created, then updated, then deleted leaves each document in its final state
+type BackfillConsumer struct {
+	opts     BackfillOptions
+	consumer *EventConsumer // applies events exactly like the live consumer
+	logger   *zap.Logger
+}
+
+// NewBackfillConsumer creates a backfill consumer writing to searchRepo
+func NewBackfillConsumer(opts BackfillOptions, searchRepo domain.SearchRepository, logger *zap.Logger) (*BackfillConsumer, error) {
+	if len(opts.Brokers) == 0 {
+		return nil, errors.New("kafka brokers list is empty")
+	}
+	if len(opts.Topics) == 0 {
+		return nil, errors.New("no topics to backfill")
+	}
+	if opts.ConsumerGroup == "" {
+		return nil, errors.New("backfill consumer group is empty")
+	}
+	if _, err := startOffset(opts.OffsetReset); err != nil {
+		return nil, err
+	}
+	if opts.IdleTimeout <= 0 {
+		opts.IdleTimeout = 10 * time.Second
+	}
+
+	return &BackfillConsumer{
+		opts: opts,
+		consumer: &EventConsumer{
+			searchRepo: searchRepo,
+			logger:     logger,
+			metrics:    NewConsumerMetrics(),
+		},
+		logger: logger,
+	}, nil
+}
+
+// Run replays every topic until it is caught up, committing offsets as it goes
+// Canceling ctx stops the run; running again with the same group continues it
+func (b *BackfillConsumer) Run(ctx context.Context) (*BackfillResult, error) {
+	started := time.Now()
+	result := &BackfillResult{Topics: make(map[string]int64, len(b.opts.Topics))}
+
+	for _, topic := range b.opts.Topics {
+		if err := b.replayTopic(ctx, topic, result); err != nil {
+			result.Duration = time.Since(started)
+			return result, err
+		}
+	}
+
+	result.Duration = time.Since(started)
+	return result, nil
+}
+
+// replayTopic reads one topic until every partition reached its high water mark
+// and no message arrived for IdleTimeout
+func (b *BackfillConsumer) replayTopic(ctx context.Context, topic string, result *BackfillResult) error {
+	offset, _ := startOffset(b.opts.OffsetReset)
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:        b.opts.Brokers,
+		GroupID:        b.opts.ConsumerGroup,
+		GroupTopics:    []string{topic},
+		StartOffset:    offset,
+		MinBytes:       b.opts.MinBytes,
+		MaxBytes:       b.opts.MaxBytes,
+		CommitInterval: time.Second,
+		ReadBackoffMin: 100 * time.Millisecond,
+		ReadBackoffMax: 1 * time.Second,
+	})
+	defer reader.Close()
+
+	b.logger.Info("Backfill: replaying topic",
+		zap.String("topic", topic),
+		zap.String("consumer_group", b.opts.ConsumerGroup),
+		zap.String("offset_reset", b.opts.OffsetReset),
+	)
+
+	lag := make(map[int]int64) // partition -> messages behind the high water mark
+	lastProgress := time.Now()
+
+	for {
+		readCtx, cancel := context.WithTimeout(ctx, b.opts.IdleTimeout)
+		message, err := reader.FetchMessage(readCtx)
+		cancel()
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if errors.Is(err, context.DeadlineExceeded) {
+				if behind := totalLag(lag); behind > 0 {
+					b.logger.Info("Backfill: waiting for remaining messages",
+						zap.String("topic", topic),
+						zap.Int64("lag", behind),
+					)
+					continue
+				}
+				b.logger.Info("Backfill: topic caught up",
+					zap.String("topic", topic),
+					zap.Int64("messages", result.Topics[topic]),
+				)
+				return nil
+			}
+			return fmt.Errorf("failed to read from %s: %w", topic, err)
+		}
+
+		if err := b.consumer.processMessage(message); err != nil {
+			result.Failed++
+		} else {
+			result.Applied++
+		}
+		result.Topics[topic]++
+
+		if err := reader.CommitMessages(ctx, message); err != nil {
+			return fmt.Errorf("failed to commit offset %d of %s/%d: %w", message.Offset, topic, message.Partition, err)
+		}
+
+		lag[message.Partition] = message.HighWaterMark - message.Offset - 1
+		if time.Since(lastProgress) >= 10*time.Second {
+			lastProgress = time.Now()
+			b.logger.Info("Backfill: progress",
+				zap.String("topic", topic),
+				zap.Int64("messages", result.Topics[topic]),
+				zap.Int64("lag", totalLag(lag)),
+			)
+		}
+	}
+}
+
+// startOffset maps an offset reset policy to the kafka-go start offset
+func startOffset(reset string) (int64, error) {
+	switch strings.ToLower(reset) {
+	case "", "earliest":
+		return kafka.FirstOffset, nil
+	case "latest":
+		return kafka.LastOffset, nil
+	default:
+		return 0, fmt.Errorf("invalid offset reset %q (want earliest or latest)", reset)
+	}
+}
+
+func totalLag(lag map[int]int64) int64 {
+	var total int64
+	for _, l := range lag {
+		if l > 0 {
+			total += l
+		}
+	}
+	return total
+}
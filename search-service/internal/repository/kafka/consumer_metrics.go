@@ -0,0 +1,133 @@
+package kafka
+
+import (
+	"fmt"
+	"search-service/internal/domain"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// rateWindow is the number of one-second buckets used for messages/sec
+const rateWindow = 60
+
+// ConsumerMetrics records lag, throughput, latency and errors of the event consumer
+type ConsumerMetrics struct {
+	mu sync.Mutex
+
+	running       bool
+	processed     int64
+	errors        int64
+	latencySum    time.Duration
+	latencyMax    time.Duration
+	lastMessageAt time.Time
+	partitions    map[string]*domain.PartitionLag
+
+	buckets     [rateWindow]int64 // messages per second
+	bucketStart [rateWindow]int64 // unix second each bucket counts
+}
+
+// NewConsumerMetrics creates an empty metrics recorder
+func NewConsumerMetrics() *ConsumerMetrics {
+	return &ConsumerMetrics{partitions: make(map[string]*domain.PartitionLag)}
+}
+
+// setRunning marks the consumer loop as started or stopped
+func (m *ConsumerMetrics) setRunning(running bool) {
+	m.mu.Lock()
+	m.running = running
+	m.mu.Unlock()
+}
+
+// observeLag records the partition position of a message that was just read
+func (m *ConsumerMetrics) observeLag(message kafka.Message) {
+	lag := message.HighWaterMark - message.Offset - 1
+	if lag < 0 {
+		lag = 0
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := fmt.Sprintf("%s/%d", message.Topic, message.Partition)
+	p, ok := m.partitions[key]
+	if !ok {
+		p = &domain.PartitionLag{Topic: message.Topic, Partition: message.Partition}
+		m.partitions[key] = p
+	}
+	p.Offset = message.Offset
+	p.HighWaterMark = message.HighWaterMark
+	p.Lag = lag
+}
+
+// observeProcessed records a handled message
+func (m *ConsumerMetrics) observeProcessed(latency time.Duration, err error) {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.processed++
+	if err != nil {
+		m.errors++
+	}
+	m.latencySum += latency
+	if latency > m.latencyMax {
+		m.latencyMax = latency
+	}
+	m.lastMessageAt = now
+
+	sec := now.Unix()
+	i := sec % rateWindow
+	if m.bucketStart[i] != sec {
+		m.bucketStart[i] = sec
+		m.buckets[i] = 0
+	}
+	m.buckets[i]++
+}
+
+// ConsumerStats returns a snapshot of the metrics
+func (m *ConsumerMetrics) ConsumerStats() domain.ConsumerStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := domain.ConsumerStats{
+		Running:        m.running,
+		Processed:      m.processed,
+		Errors:         m.errors,
+		LatencySeconds: m.latencySum.Seconds(),
+		MaxLatencyMs:   float64(m.latencyMax) / float64(time.Millisecond),
+		Partitions:     make([]domain.PartitionLag, 0, len(m.partitions)),
+	}
+	if m.processed > 0 {
+		stats.AvgLatencyMs = float64(m.latencySum) / float64(time.Millisecond) / float64(m.processed)
+	}
+	if !m.lastMessageAt.IsZero() {
+		at := m.lastMessageAt
+		stats.LastMessageAt = &at
+	}
+
+	now := time.Now().Unix()
+	var recent int64
+	for i := range m.buckets {
+		if now-m.bucketStart[i] < rateWindow {
+			recent += m.buckets[i]
+		}
+	}
+	stats.MessagesPerSecond = float64(recent) / rateWindow
+
+	for _, p := range m.partitions {
+		stats.Partitions = append(stats.Partitions, *p)
+		stats.TotalLag += p.Lag
+	}
+	sort.Slice(stats.Partitions, func(i, j int) bool {
+		if stats.Partitions[i].Topic != stats.Partitions[j].Topic {
+			return stats.Partitions[i].Topic < stats.Partitions[j].Topic
+		}
+		return stats.Partitions[i].Partition < stats.Partitions[j].Partition
+	})
+
+	return stats
+}
@@ -3,6 +3,7 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"search-service/internal/domain"
 	"time"
@@ -16,18 +17,24 @@ import (
 type EventConsumer struct {
 	reader      *kafka.Reader
 	searchRepo  domain.SearchRepository
+	cache       domain.SearchCache // nil when search_cache is disabled
 	logger      *zap.Logger
+
+	metrics *ConsumerMetrics
 }
 
 // NewEventConsumer creates a new Kafka event consumer
+// topics are read with one consumer group (one topic per product event type)
 func NewEventConsumer(
 	brokers []string,
-	topic string,
+	topics []string,
 	consumerGroup string,
 	readTimeout time.Duration,
 	minBytes int,
 	maxBytes int,
 	searchRepo domain.SearchRepository,
+	cache domain.SearchCache,
+	metrics *ConsumerMetrics,
 	logger *zap.Logger,
 ) *EventConsumer {
 	// Validate inputs
@@ -35,9 +42,9 @@ func NewEventConsumer(
 		logger.Error("Kafka brokers list is empty")
 		panic("Kafka brokers list is empty")
 	}
-	if topic == "" {
-		logger.Error("Kafka topic is empty")
-		panic("Kafka topic is empty")
+	if len(topics) == 0 {
+		logger.Error("Kafka topics list is empty")
+		panic("Kafka topics list is empty")
 	}
 	if consumerGroup == "" {
 		logger.Error("Kafka consumer group is empty")
@@ -46,13 +53,13 @@ func NewEventConsumer(
 
 	logger.Info("Creating Kafka reader",
 		zap.Strings("brokers", brokers),
-		zap.String("topic", topic),
+		zap.Strings("topics", topics),
 		zap.String("consumer_group", consumerGroup),
 	)
 
 	reader := kafka.NewReader(kafka.ReaderConfig{
 		Brokers:        brokers,
-		Topic:          topic,
+		GroupTopics:    topics,
 		GroupID:        consumerGroup,
 		MinBytes:       minBytes,
 		MaxBytes:       maxBytes,
@@ -65,7 +72,9 @@ func NewEventConsumer(
 	return &EventConsumer{
 		reader:     reader,
 		searchRepo: searchRepo,
+		cache:      cache,
 		logger:     logger,
+		metrics:    metrics,
 	}
 }
 
@@ -75,7 +84,7 @@ func (c *EventConsumer) Start(ctx context.Context) error {
 	// Use both logger and log for maximum visibility
 	log.Printf("🚀🚀🚀 Kafka consumer Start() method called! 🚀🚀🚀\n")
 	c.logger.Info("🚀 Starting Kafka consumer",
-		zap.String("topic", c.reader.Config().Topic),
+		zap.Strings("topics", c.reader.Config().GroupTopics),
 		zap.String("consumer_group", c.reader.Config().GroupID),
 		zap.Strings("brokers", c.reader.Config().Brokers),
 	)
@@ -83,6 +92,9 @@ func (c *EventConsumer) Start(ctx context.Context) error {
 	log.Printf("✅ Kafka consumer entering main loop - ready to receive messages\n")
 	c.logger.Info("✅ Kafka consumer entering main loop - ready to receive messages")
 
+	c.metrics.setRunning(true)
+	defer c.metrics.setRunning(false)
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -114,15 +126,21 @@ func (c *EventConsumer) Start(ctx context.Context) error {
 				zap.Int64("offset", message.Offset),
 				zap.Int("message_size", len(message.Value)),
 			)
+			c.metrics.observeLag(message)
 
 			// Process message in goroutine to avoid blocking
-			go c.processMessage(message)
+			go func(message kafka.Message) {
+				start := time.Now()
+				err := c.processMessage(message)
+				c.metrics.observeProcessed(time.Since(start), err)
+			}(message)
 		}
 	}
 }
 
 // processMessage processes a single Kafka message
-func (c *EventConsumer) processMessage(message kafka.Message) {
+// The returned error is only counted in the consumer metrics (it is logged here)
+func (c *EventConsumer) processMessage(message kafka.Message) error {
 	c.logger.Debug("Received message",
 		zap.String("topic", message.Topic),
 		zap.Int("partition", message.Partition),
@@ -133,7 +151,7 @@ func (c *EventConsumer) processMessage(message kafka.Message) {
 	var event domain.ProductEvent
 	if err := json.Unmarshal(message.Value, &event); err != nil {
 		c.logger.Error("Failed to unmarshal event", zap.Error(err))
-		return
+		return fmt.Errorf("failed to unmarshal event: %w", err)
 	}
 
 	// Handle event based on type
@@ -141,7 +159,7 @@ func (c *EventConsumer) processMessage(message kafka.Message) {
 	case "product_created", "product_updated":
 		if event.ProductData == nil {
 			c.logger.Warn("Product data is nil in event", zap.String("event_type", event.EventType))
-			return
+			return fmt.Errorf("product data is nil in %s event", event.EventType)
 		}
 
 		// Index or update product in Elasticsearch
@@ -153,7 +171,7 @@ func (c *EventConsumer) processMessage(message kafka.Message) {
 				zap.String("event_type", event.EventType),
 				zap.Error(err),
 			)
-			return
+			return fmt.Errorf("failed to index product %d: %w", event.ProductID, err)
 		}
 
 		log.Printf("✅✅✅ Product indexed successfully: ID=%d, Name=%s\n", event.ProductID, event.ProductData.Name)
@@ -161,6 +179,7 @@ func (c *EventConsumer) processMessage(message kafka.Message) {
 			zap.Uint("product_id", event.ProductID),
 			zap.String("event_type", event.EventType),
 		)
+		c.invalidateSearches(&event)
 
 	case "product_deleted":
 		// Delete product from Elasticsearch
@@ -169,16 +188,72 @@ func (c *EventConsumer) processMessage(message kafka.Message) {
 				zap.Uint("product_id", event.ProductID),
 				zap.Error(err),
 			)
-			return
+			return fmt.Errorf("failed to delete product %d: %w", event.ProductID, err)
 		}
 
 		c.logger.Info("Product deleted from index",
 			zap.Uint("product_id", event.ProductID),
 		)
+		c.invalidateSearches(&event)
+
+	case "shop_rating_updated":
+		// Copy the shop's rating aggregate onto all of its products
+		var rating domain.ShopRatingEvent
+		metadata, err := json.Marshal(event.Metadata)
+		if err == nil {
+			err = json.Unmarshal(metadata, &rating)
+		}
+		if err != nil || rating.ShopID == 0 {
+			c.logger.Warn("Invalid shop rating in event", zap.Uint("product_id", event.ProductID), zap.Error(err))
+			return fmt.Errorf("invalid shop rating in %s event", event.EventType)
+		}
+
+		if err := c.searchRepo.UpdateShopRating(rating.ShopID, rating.RatingAvg, rating.RatingCount); err != nil {
+			c.logger.Error("Failed to update shop rating in index",
+				zap.Uint("shop_id", rating.ShopID),
+				zap.Error(err),
+			)
+			return fmt.Errorf("failed to update rating of shop %d: %w", rating.ShopID, err)
+		}
+
+		c.logger.Info("Shop rating updated in index",
+			zap.Uint("shop_id", rating.ShopID),
+			zap.Float64("rating_avg", rating.RatingAvg),
+			zap.Int("rating_count", rating.RatingCount),
+		)
 
 	default:
 		c.logger.Warn("Unknown event type", zap.String("event_type", event.EventType))
 	}
+	return nil
+}
+
+// invalidateSearches drops the cached searches that can list the product: those of its
+// category and the unfiltered ones, or all of them when the event has no product data.
+// A failure is only logged, the entries then expire with their TTL
+func (c *EventConsumer) invalidateSearches(event *domain.ProductEvent) {
+	if c.cache == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var err error
+	switch {
+	case event.ProductData == nil:
+		err = c.cache.InvalidateAll(ctx)
+	case event.ProductData.CategoryID != nil:
+		err = c.cache.InvalidateCategories(ctx, []uint{*event.ProductData.CategoryID})
+	default:
+		err = c.cache.InvalidateCategories(ctx, nil)
+	}
+	if err != nil {
+		c.logger.Warn("Failed to invalidate cached searches",
+			zap.Uint("product_id", event.ProductID),
+			zap.Error(err),
+		)
+	}
 }
 
 // Close closes the Kafka reader connection
@@ -0,0 +1,154 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"search-service/internal/domain"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+// OrderEventConsumer projects order events onto product sales counters
+// (sold_count and sold_30d on the product documents, see domain.SalesRepository)
+// Each event carries the full order snapshot, so the projection converges whatever order
+// the created / cancelled topics are read in
+type OrderEventConsumer struct {
+	reader    *kafka.Reader
+	salesRepo domain.SalesRepository
+	logger    *zap.Logger
+}
+
+// NewOrderEventConsumer creates a consumer for the given order topics
+func NewOrderEventConsumer(
+	brokers []string,
+	topics []string,
+	consumerGroup string,
+	salesRepo domain.SalesRepository,
+	logger *zap.Logger,
+) (*OrderEventConsumer, error) {
+	if len(brokers) == 0 {
+		return nil, errors.New("kafka brokers list is empty")
+	}
+	if len(topics) == 0 {
+		return nil, errors.New("kafka order topics list is empty")
+	}
+	if consumerGroup == "" {
+		return nil, errors.New("kafka sales consumer group is empty")
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:        brokers,
+		GroupTopics:    topics,
+		GroupID:        consumerGroup,
+		CommitInterval: time.Second,
+		ReadBackoffMin: 100 * time.Millisecond,
+		ReadBackoffMax: 1 * time.Second,
+	})
+
+	return &OrderEventConsumer{
+		reader:    reader,
+		salesRepo: salesRepo,
+		logger:    logger,
+	}, nil
+}
+
+// Start consumes order events until ctx is canceled
+func (c *OrderEventConsumer) Start(ctx context.Context) error {
+	c.logger.Info("Starting order event consumer (sales projection)",
+		zap.Strings("topics", c.reader.Config().GroupTopics),
+		zap.String("consumer_group", c.reader.Config().GroupID),
+	)
+
+	for {
+		message, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				c.logger.Info("Stopping order event consumer")
+				return ctx.Err()
+			}
+			c.logger.Error("Failed to read order event from Kafka", zap.Error(err))
+			time.Sleep(1 * time.Second) // Backoff on error
+			continue
+		}
+
+		if err := c.processMessage(ctx, message); err != nil {
+			c.logger.Error("Failed to project order event",
+				zap.String("topic", message.Topic),
+				zap.Int64("offset", message.Offset),
+				zap.Error(err),
+			)
+		}
+
+		if err := c.reader.CommitMessages(ctx, message); err != nil && ctx.Err() == nil {
+			c.logger.Warn("Failed to commit order event offset", zap.Error(err))
+		}
+	}
+}
+
+// processMessage applies one order event to the sales projection
+func (c *OrderEventConsumer) processMessage(ctx context.Context, message kafka.Message) error {
+	var event domain.OrderEvent
+	if err := json.Unmarshal(message.Value, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal order event: %w", err)
+	}
+	if event.Order == nil {
+		// v1 events carried the persistence model, without product IDs
+		c.logger.Debug("Skipping order event without payload",
+			zap.Uint("order_id", event.OrderID),
+			zap.Int("schema_version", event.SchemaVersion),
+		)
+		return nil
+	}
+
+	order := event.Order
+	if err := c.salesRepo.SaveOrderLines(ctx, order); err != nil {
+		return fmt.Errorf("failed to save sales of order %d: %w", order.ID, err)
+	}
+
+	if err := c.salesRepo.RefreshProducts(ctx, order.ProductIDs()); err != nil {
+		return fmt.Errorf("failed to refresh product sales: %w", err)
+	}
+
+	c.logger.Debug("Order event projected",
+		zap.String("event_type", event.EventType),
+		zap.Uint("order_id", order.ID),
+		zap.Bool("counts_as_sale", order.CountsAsSale()),
+	)
+	return nil
+}
+
+// StartWindowRefresh recomputes all sales counters every interval so sold_30d decays
+// even for products without new orders
+func (c *OrderEventConsumer) StartWindowRefresh(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := c.salesRepo.RefreshAll(ctx)
+			if err != nil {
+				c.logger.Error("Failed to refresh product sales", zap.Error(err))
+				continue
+			}
+			c.logger.Info("Product sales refreshed", zap.Int("products", n))
+		}
+	}
+}
+
+// Close closes the Kafka reader connection
+func (c *OrderEventConsumer) Close() error {
+	if c.reader != nil {
+		return c.reader.Close()
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package redis
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"search-service/config"
+	"search-service/internal/domain"
+	"strings"
+	"sync/atomic"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Cached results are indexed by the category they are filtered on, so a product event
+// only drops the searches that can list the product
+const (
+	searchCacheKey      = "search:cache:%s"      // request hash -> result JSON
+	searchPopularityKey = "search:popularity:%s" // request hash -> searches within the popularity window
+	searchCategorySet   = "search:cache:cat:%d"  // cached keys filtered on a category
+	searchAnySet        = "search:cache:cat:any" // cached keys without a category filter
+	searchAllSet        = "search:cache:keys"    // every cached key
+)
+
+// invalidateScript deletes the keys listed in the given sets, then the sets
+// Returns how many cached keys were listed
+var invalidateScript = redis.NewScript(`
+local n = 0
+for _, set in ipairs(KEYS) do
+	local keys = redis.call('SMEMBERS', set)
+	for i = 1, #keys, 500 do
+		redis.call('DEL', unpack(keys, i, math.min(i + 499, #keys)))
+	end
+	n = n + #keys
+	redis.call('DEL', set)
+end
+return n
+`)
+
+// SearchCache caches the first page of popular searches in Redis (domain.SearchCache)
+type SearchCache struct {
+	client *redis.Client
+	cfg    *config.SearchCacheConfig
+
+	hits          atomic.Int64
+	misses        atomic.Int64
+	stores        atomic.Int64
+	invalidations atomic.Int64
+	errors        atomic.Int64
+}
+
+// NewSearchCache creates a new Redis search cache
+func NewSearchCache(client *redis.Client, cfg *config.SearchCacheConfig) *SearchCache {
+	return &SearchCache{client: client, cfg: cfg}
+}
+
+// Get returns the cached result of the request, nil on a miss
+func (c *SearchCache) Get(ctx context.Context, req *domain.SearchRequest) (*domain.SearchResult, error) {
+	data, err := c.client.Get(ctx, fmt.Sprintf(searchCacheKey, requestHash(req))).Bytes()
+	if err == redis.Nil {
+		c.misses.Add(1)
+		return nil, nil
+	}
+	if err != nil {
+		c.misses.Add(1)
+		c.errors.Add(1)
+		return nil, fmt.Errorf("failed to get cached search: %w", err)
+	}
+
+	var result domain.SearchResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		c.misses.Add(1)
+		c.errors.Add(1)
+		return nil, fmt.Errorf("failed to unmarshal cached search: %w", err)
+	}
+	c.hits.Add(1)
+	return &result, nil
+}
+
+// Store counts the search and caches its result once it was searched MinHits times
+// within the popularity window
+func (c *SearchCache) Store(ctx context.Context, req *domain.SearchRequest, result *domain.SearchResult) error {
+	hash := requestHash(req)
+
+	popularityKey := fmt.Sprintf(searchPopularityKey, hash)
+	pipe := c.client.TxPipeline()
+	searches := pipe.Incr(ctx, popularityKey)
+	pipe.ExpireNX(ctx, popularityKey, c.cfg.PopularityWindow)
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.errors.Add(1)
+		return fmt.Errorf("failed to count search: %w", err)
+	}
+	if searches.Val() < c.cfg.MinHits {
+		return nil
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal search result: %w", err)
+	}
+
+	key := fmt.Sprintf(searchCacheKey, hash)
+	set := searchAnySet
+	if req.Filters != nil && req.Filters.CategoryID != nil {
+		set = fmt.Sprintf(searchCategorySet, *req.Filters.CategoryID)
+	}
+	pipe = c.client.TxPipeline()
+	pipe.Set(ctx, key, data, c.cfg.TTL)
+	pipe.SAdd(ctx, set, key)
+	pipe.Expire(ctx, set, c.cfg.TTL)
+	pipe.SAdd(ctx, searchAllSet, key)
+	pipe.Expire(ctx, searchAllSet, c.cfg.TTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		c.errors.Add(1)
+		return fmt.Errorf("failed to cache search result: %w", err)
+	}
+	c.stores.Add(1)
+	return nil
+}
+
+// InvalidateCategories drops the searches filtered on one of the categories and the
+// unfiltered ones
+func (c *SearchCache) InvalidateCategories(ctx context.Context, categoryIDs []uint) error {
+	sets := make([]string, 0, len(categoryIDs)+1)
+	sets = append(sets, searchAnySet)
+	for _, id := range categoryIDs {
+		sets = append(sets, fmt.Sprintf(searchCategorySet, id))
+	}
+	return c.invalidate(ctx, sets)
+}
+
+// InvalidateAll drops every cached search (the category sets then list missing keys
+// until they expire, which is harmless)
+func (c *SearchCache) InvalidateAll(ctx context.Context) error {
+	return c.invalidate(ctx, []string{searchAllSet, searchAnySet})
+}
+
+func (c *SearchCache) invalidate(ctx context.Context, sets []string) error {
+	dropped, err := invalidateScript.Run(ctx, c.client, sets).Int64()
+	if err != nil {
+		c.errors.Add(1)
+		return fmt.Errorf("failed to invalidate cached searches: %w", err)
+	}
+	c.invalidations.Add(dropped)
+	return nil
+}
+
+// SearchCacheStats returns a snapshot of the cache counters
+func (c *SearchCache) SearchCacheStats() domain.SearchCacheStats {
+	stats := domain.SearchCacheStats{
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Stores:        c.stores.Load(),
+		Invalidations: c.invalidations.Load(),
+		Errors:        c.errors.Load(),
+	}
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(lookups)
+	}
+	return stats
+}
+
+// requestHash identifies a search: the query is normalized (case, spacing) so trivially
+// different spellings share an entry
+func requestHash(req *domain.SearchRequest) string {
+	normalized := *req
+	normalized.Query = strings.Join(strings.Fields(strings.ToLower(req.Query)), " ")
+	data, _ := json.Marshal(normalized)
+	sum := sha1.Sum(data)
+	return hex.EncodeToString(sum[:])
+}
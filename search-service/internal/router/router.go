@@ -1,24 +1,68 @@
 package router
 
 import (
+	"net/http"
 	"search-service/internal/handler"
 
 	"github.com/gin-gonic/gin"
 )
 
+// RequireAdmin middleware only allows requests from ADMIN users
+// Role comes from X-User-Role header, set by API Gateway after JWT validation
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("X-User-Role") != "ADMIN" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin permission required"})
+			return
+		}
+		c.Next()
+	}
+}
+
 // SetupRouter configures all API routes
 // This is the transport layer - it defines the HTTP API surface
-func SetupRouter(searchHandler *handler.SearchHandler) *gin.Engine {
-	router := gin.Default()
+func SetupRouter(searchHandler *handler.SearchHandler, curationHandler *handler.CurationHandler, experimentHandler *handler.ExperimentHandler, logLevelHandler *handler.LogLevelHandler, healthHandler *handler.HealthHandler, recovery gin.HandlerFunc, faults gin.HandlerFunc) *gin.Engine {
+	router := gin.New()
+
+	// Panic recovery first so it also covers the other middleware (replaces gin.Recovery)
+	router.Use(recovery, gin.Logger())
+
+	// Opt-in latency/error injection for resilience testing (no-op unless enabled)
+	router.Use(faults)
 
-	// Health check endpoint
-	router.GET("/health", searchHandler.HealthCheck)
+	// Health check, readiness and consumer metrics endpoints
+	router.GET("/health", healthHandler.HealthCheck)
+	router.GET("/readyz", healthHandler.Ready)
+	router.GET("/metrics", healthHandler.Metrics)
 
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 	{
 		// Search routes
 		v1.GET("/search", searchHandler.SearchProducts)
+		v1.GET("/search/all", searchHandler.SearchAll) // Products, shops and categories grouped (search dropdown)
+
+		// Impressions and clicks of ranking experiment variants
+		v1.POST("/search/feedback", experimentHandler.RecordFeedback)
+
+		// Admin: runtime log level, search curations (pinned products, boosted shops),
+		// ranking experiment reports
+		admin := v1.Group("/admin")
+		admin.Use(RequireAdmin())
+		{
+			admin.GET("/log-level/search", logLevelHandler.GetLogLevel)
+			admin.PUT("/log-level/search", logLevelHandler.SetLogLevel)
+
+			admin.GET("/search/curations", curationHandler.ListCurations)
+			admin.POST("/search/curations", curationHandler.CreateCuration)
+			admin.GET("/search/curations/:id", curationHandler.GetCuration)
+			admin.PUT("/search/curations/:id", curationHandler.UpdateCuration)
+			admin.DELETE("/search/curations/:id", curationHandler.DeleteCuration)
+			admin.GET("/search/curations/:id/audit", curationHandler.GetCurationAudit)
+
+			admin.GET("/search/experiments", experimentHandler.ListExperiments)
+			admin.GET("/search/experiments/:id", experimentHandler.GetExperimentReport)
+		}
 	}
 
 	return router
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"search-service/internal/domain"
+	"search-service/pkg/category_client"
+	"search-service/pkg/shop_client"
+	"search-service/pkg/slug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ==================== ShopSearchAdapter for SearchAllService ====================
+
+// ShopSearchAdapter searches shops in identity-service
+type ShopSearchAdapter struct {
+	Client *shop_client.ShopClient
+}
+
+// SearchShops returns a page of active shops matching the query
+func (a *ShopSearchAdapter) SearchShops(ctx context.Context, query, cursor string, limit int) (*domain.SearchGroup, error) {
+	result, err := a.Client.SearchShops(ctx, query, cursor, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	shops := make([]*domain.ShopHit, 0, len(result.Shops))
+	for _, shop := range result.Shops {
+		shops = append(shops, &domain.ShopHit{
+			ID:         shop.ID,
+			Name:       shop.Name,
+			Slug:       shop.Slug,
+			LogoURL:    shop.LogoURL,
+			IsOfficial: shop.IsOfficial,
+			Rating:     shop.Rating,
+			Province:   shop.Province,
+		})
+	}
+	return &domain.SearchGroup{Items: shops, Total: result.Total, NextCursor: result.NextCursor}, nil
+}
+
+// ==================== CategorySearchAdapter for SearchAllService ====================
+
+// CategorySearchAdapter matches category names in memory: the category tree is small, so
+// it is read from product-service at most once per ttl instead of once per search
+type CategorySearchAdapter struct {
+	client *category_client.CategoryClient
+	ttl    time.Duration
+
+	mu         sync.Mutex
+	categories []indexedCategory
+	expiresAt  time.Time
+}
+
+type indexedCategory struct {
+	category *category_client.Category
+	slug     string // name without diacritics, e.g. "ao-thun"
+}
+
+// NewCategorySearchAdapter creates a category searcher over the product-service category client
+func NewCategorySearchAdapter(client *category_client.CategoryClient, ttl time.Duration) *CategorySearchAdapter {
+	return &CategorySearchAdapter{client: client, ttl: ttl}
+}
+
+// SearchCategories returns a page of active categories whose name contains the query,
+// ignoring case and diacritics; names starting with it first, then shorter names.
+// The cursor is the offset of the next page
+func (a *CategorySearchAdapter) SearchCategories(ctx context.Context, query, cursor string, limit int) (*domain.SearchGroup, error) {
+	offset := 0
+	if cursor != "" {
+		var err error
+		if offset, err = strconv.Atoi(cursor); err != nil || offset < 0 {
+			return nil, domain.ErrInvalidCursor
+		}
+	}
+
+	categories, err := a.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	querySlug := slug.Make(query)
+	if querySlug == "" {
+		return &domain.SearchGroup{Items: []*domain.CategoryHit{}}, nil
+	}
+	var matches []indexedCategory
+	for _, c := range categories {
+		if c.category.IsActive && strings.Contains(c.slug, querySlug) {
+			matches = append(matches, c)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		iPrefix, jPrefix := strings.HasPrefix(matches[i].slug, querySlug), strings.HasPrefix(matches[j].slug, querySlug)
+		if iPrefix != jPrefix {
+			return iPrefix
+		}
+		return len(matches[i].slug) < len(matches[j].slug)
+	})
+
+	group := &domain.SearchGroup{Total: int64(len(matches))}
+	page := matches[min(offset, len(matches)):min(offset+limit, len(matches))]
+	hits := make([]*domain.CategoryHit, 0, len(page))
+	for _, c := range page {
+		hits = append(hits, &domain.CategoryHit{
+			ID:       c.category.ID,
+			ParentID: c.category.ParentID,
+			Name:     c.category.Name,
+			Slug:     c.category.Slug,
+			ImageURL: c.category.ImageURL,
+		})
+	}
+	group.Items = hits
+	if next := offset + limit; next < len(matches) {
+		group.NextCursor = strconv.Itoa(next)
+	}
+	return group, nil
+}
+
+// list returns the cached categories, refreshed once expired. The stale list is kept when
+// product-service cannot be reached
+func (a *CategorySearchAdapter) list(ctx context.Context) ([]indexedCategory, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.categories != nil && time.Now().Before(a.expiresAt) {
+		return a.categories, nil
+	}
+
+	categories, err := a.client.ListCategories(ctx)
+	if err != nil {
+		if a.categories != nil {
+			return a.categories, nil
+		}
+		return nil, err
+	}
+
+	indexed := make([]indexedCategory, 0, len(categories))
+	for _, c := range categories {
+		indexed = append(indexed, indexedCategory{category: c, slug: slug.Make(c.Name)})
+	}
+	a.categories = indexed
+	a.expiresAt = time.Now().Add(a.ttl)
+	return a.categories, nil
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"search-service/internal/domain"
+	"search-service/pkg/slug"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// CurationService manages the admin search curations (pinned products and boosted shops
+// per keyword or campaign) and matches them to searches. Every curation is held in memory,
+// reloaded after each change and every refresh interval so changes made on another instance
+// apply within it; schedules are checked per search
+type CurationService struct {
+	repo            domain.CurationRepository
+	refreshInterval time.Duration
+	logger          *zap.Logger
+
+	mu        sync.RWMutex
+	curations []loadedCuration // newest first
+}
+
+type loadedCuration struct {
+	curation *domain.Curation
+	keyword  string // slug of the keyword ("ao-thun"), empty for a campaign-only curation
+}
+
+// NewCurationService creates a new search curation service
+func NewCurationService(repo domain.CurationRepository, refreshInterval time.Duration, logger *zap.Logger) *CurationService {
+	return &CurationService{
+		repo:            repo,
+		refreshInterval: refreshInterval,
+		logger:          logger,
+	}
+}
+
+// Start loads the curations and reloads them every refresh interval until ctx is cancelled
+func (s *CurationService) Start(ctx context.Context) {
+	if err := s.Reload(ctx); err != nil {
+		s.logger.Warn("failed to load search curations", zap.Error(err))
+	}
+
+	ticker := time.NewTicker(s.refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Reload(ctx); err != nil {
+				s.logger.Warn("failed to reload search curations, keeping the loaded ones", zap.Error(err))
+			}
+		}
+	}
+}
+
+// Reload replaces the in-memory curations with the stored ones
+func (s *CurationService) Reload(ctx context.Context) error {
+	curations, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	loaded := make([]loadedCuration, 0, len(curations))
+	for _, c := range curations {
+		loaded = append(loaded, loadedCuration{curation: c, keyword: slug.Make(c.Keyword)})
+	}
+
+	s.mu.Lock()
+	s.curations = loaded
+	s.mu.Unlock()
+	return nil
+}
+
+// CurationFor merges the curations active at now that match the search: pinned products of
+// newer curations come first, each curation's shops keep their own boost
+func (s *CurationService) CurationFor(req *domain.SearchRequest, now time.Time) *domain.SearchCuration {
+	s.mu.RLock()
+	curations := s.curations
+	s.mu.RUnlock()
+	if len(curations) == 0 {
+		return nil
+	}
+
+	query := slug.Make(req.Query)
+	var result *domain.SearchCuration
+	pinned := make(map[uint]bool)
+	for _, c := range curations {
+		if !c.matches(query, req.CampaignID) || !c.curation.IsActiveAt(now) {
+			continue
+		}
+		if result == nil {
+			result = &domain.SearchCuration{}
+		}
+		for _, id := range c.curation.PinnedProductIDs {
+			if !pinned[id] && len(result.PinnedProductIDs) < domain.MaxPinnedTotal {
+				pinned[id] = true
+				result.PinnedProductIDs = append(result.PinnedProductIDs, id)
+			}
+		}
+		if len(c.curation.BoostedShopIDs) > 0 {
+			result.ShopBoosts = append(result.ShopBoosts, domain.ShopBoost{
+				ShopIDs: c.curation.BoostedShopIDs,
+				Boost:   c.curation.ShopBoost,
+			})
+		}
+	}
+	return result
+}
+
+// matches reports whether the curation targets the search: its keyword (if any) equals
+// the query and its campaign (if any) is the search's
+func (c loadedCuration) matches(query string, campaignID *uint) bool {
+	if c.keyword != "" && c.keyword != query {
+		return false
+	}
+	if c.curation.CampaignID != nil && (campaignID == nil || *campaignID != *c.curation.CampaignID) {
+		return false
+	}
+	return true
+}
+
+// ListCurations returns every curation, newest first (scheduled and expired ones included)
+func (s *CurationService) ListCurations(ctx context.Context) ([]*domain.Curation, error) {
+	curations, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list curations: %w", err)
+	}
+	return curations, nil
+}
+
+// GetCuration returns a curation by ID
+func (s *CurationService) GetCuration(ctx context.Context, id string) (*domain.Curation, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// CreateCuration validates and stores a new curation
+func (s *CurationService) CreateCuration(ctx context.Context, curation *domain.Curation, adminUserID uint) (*domain.Curation, error) {
+	if err := normalizeCuration(curation); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	curation.ID = ""
+	curation.CreatedBy, curation.UpdatedBy = adminUserID, adminUserID
+	curation.CreatedAt, curation.UpdatedAt = now, now
+	if err := s.repo.Create(ctx, curation); err != nil {
+		s.logger.Error("failed to create curation", zap.Error(err))
+		return nil, fmt.Errorf("failed to create curation: %w", err)
+	}
+
+	s.afterChange(ctx, domain.CurationActionCreated, curation.ID, nil, curation, adminUserID)
+	return curation, nil
+}
+
+// UpdateCuration validates and replaces a curation
+func (s *CurationService) UpdateCuration(ctx context.Context, id string, curation *domain.Curation, adminUserID uint) (*domain.Curation, error) {
+	if err := normalizeCuration(curation); err != nil {
+		return nil, err
+	}
+
+	before, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	curation.ID = id
+	curation.CreatedBy, curation.CreatedAt = before.CreatedBy, before.CreatedAt
+	curation.UpdatedBy, curation.UpdatedAt = adminUserID, time.Now()
+	if err := s.repo.Update(ctx, curation); err != nil {
+		s.logger.Error("failed to update curation", zap.String("curation_id", id), zap.Error(err))
+		return nil, fmt.Errorf("failed to update curation: %w", err)
+	}
+
+	s.afterChange(ctx, domain.CurationActionUpdated, id, before, curation, adminUserID)
+	return curation, nil
+}
+
+// DeleteCuration removes a curation; its audit trail is kept
+func (s *CurationService) DeleteCuration(ctx context.Context, id string, adminUserID uint) error {
+	before, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.afterChange(ctx, domain.CurationActionDeleted, id, before, nil, adminUserID)
+	return nil
+}
+
+// GetCurationAudit retrieves the change history of a curation (also once deleted) with pagination
+func (s *CurationService) GetCurationAudit(ctx context.Context, id string, page, limit int) ([]*domain.CurationAudit, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	audits, total, err := s.repo.ListAudit(ctx, id, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get curation audit: %w", err)
+	}
+	return audits, total, nil
+}
+
+// afterChange writes the audit trail and reloads the curations so this instance applies the
+// change at once (the others within the refresh interval)
+func (s *CurationService) afterChange(ctx context.Context, action, id string, before, after *domain.Curation, adminUserID uint) {
+	if err := s.repo.CreateAudit(ctx, &domain.CurationAudit{
+		CurationID: id,
+		Action:     action,
+		Before:     before,
+		After:      after,
+		ChangedBy:  adminUserID,
+		CreatedAt:  time.Now(),
+	}); err != nil {
+		s.logger.Error("failed to write curation audit", zap.String("curation_id", id), zap.Error(err))
+	}
+
+	if err := s.Reload(ctx); err != nil {
+		s.logger.Warn("failed to reload search curations", zap.Error(err))
+	}
+
+	s.logger.Info("search curation changed",
+		zap.String("action", action),
+		zap.String("curation_id", id),
+		zap.Uint("changed_by", adminUserID),
+	)
+}
+
+// normalizeCuration trims the keyword and checks the targets, pins, boost and schedule
+func normalizeCuration(c *domain.Curation) error {
+	c.Keyword = strings.Join(strings.Fields(c.Keyword), " ")
+	if slug.Make(c.Keyword) == "" {
+		c.Keyword = ""
+	}
+	if c.Keyword == "" && c.CampaignID == nil {
+		return domain.ErrCurationTargetRequired
+	}
+
+	c.PinnedProductIDs = distinctIDs(c.PinnedProductIDs)
+	c.BoostedShopIDs = distinctIDs(c.BoostedShopIDs)
+	if len(c.PinnedProductIDs) == 0 && len(c.BoostedShopIDs) == 0 {
+		return domain.ErrCurationEmpty
+	}
+	if len(c.PinnedProductIDs) > domain.MaxPinnedProducts {
+		return fmt.Errorf("%w: at most %d", domain.ErrTooManyPinned, domain.MaxPinnedProducts)
+	}
+
+	switch {
+	case len(c.BoostedShopIDs) == 0:
+		c.ShopBoost = 0
+	case c.ShopBoost == 0:
+		c.ShopBoost = domain.DefaultShopBoost
+	case c.ShopBoost <= 1 || c.ShopBoost > domain.MaxShopBoost:
+		return domain.ErrInvalidShopBoost
+	}
+
+	if c.StartAt != nil && c.EndAt != nil && !c.EndAt.After(*c.StartAt) {
+		return domain.ErrInvalidSchedule
+	}
+	return nil
+}
+
+// distinctIDs drops zero and repeated IDs, keeping the order
+func distinctIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	distinct := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if id != 0 && !seen[id] {
+			seen[id] = true
+			distinct = append(distinct, id)
+		}
+	}
+	return distinct
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"search-service/internal/domain"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// maxReportDays bounds the period of an experiment report
+const maxReportDays = 90
+
+// ExperimentService runs the search ranking experiments: it buckets visitors into the
+// variants of the enabled experiment, records the impressions and clicks clients log for
+// them and reports the click-through rate of each variant. Experiments come from the
+// configuration, so changing one is a deploy
+type ExperimentService struct {
+	experiments []*domain.Experiment
+	feedback    domain.FeedbackRepository
+	logger      *zap.Logger
+}
+
+// NewExperimentService creates a new ranking experiment service
+func NewExperimentService(experiments []*domain.Experiment, feedback domain.FeedbackRepository, logger *zap.Logger) *ExperimentService {
+	return &ExperimentService{
+		experiments: experiments,
+		feedback:    feedback,
+		logger:      logger,
+	}
+}
+
+// FeedbackRequest is an impression or click logged by a client for the experiment variant
+// its search result was tagged with
+type FeedbackRequest struct {
+	Type       string `json:"type" binding:"required,oneof=impression click"`
+	Experiment string `json:"experiment" binding:"required"`
+	Variant    string `json:"variant" binding:"required"`
+	Query      string `json:"query" binding:"max=200"`
+	ProductIDs []uint `json:"product_ids" binding:"max=100"`     // impression: products shown; click: the product opened
+	Position   int    `json:"position" binding:"omitempty,min=1"` // click: 1-based rank of the product
+}
+
+// RankingFor assigns the visitor to a variant of the enabled experiment. Assignment is a
+// hash of the experiment and visitor, so a visitor keeps their variant across searches and
+// instances. Returns nil without a visitor or an enabled experiment
+func (s *ExperimentService) RankingFor(visitor string) (*domain.ExperimentAssignment, *domain.RankingParams) {
+	if visitor == "" {
+		return nil, nil
+	}
+	experiment := s.enabled()
+	if experiment == nil {
+		return nil, nil
+	}
+	variant := assignVariant(experiment, visitor)
+	return &domain.ExperimentAssignment{Experiment: experiment.ID, Variant: variant.Name}, variant.Ranking
+}
+
+// RecordFeedback logs an impression or click of the visitor. The variant must be the one
+// the visitor is assigned to, so clients cannot inflate another variant's counters
+func (s *ExperimentService) RecordFeedback(ctx context.Context, visitor string, req *FeedbackRequest) error {
+	if visitor == "" {
+		return domain.ErrVisitorRequired
+	}
+	experiment := s.find(req.Experiment)
+	if experiment == nil {
+		return domain.ErrExperimentNotFound
+	}
+	if assignVariant(experiment, visitor).Name != req.Variant {
+		return domain.ErrVariantMismatch
+	}
+	if req.Type == domain.FeedbackClick && len(req.ProductIDs) != 1 {
+		return fmt.Errorf("%w: a click names the one product opened", domain.ErrInvalidFeedback)
+	}
+
+	feedback := &domain.SearchFeedback{
+		Type:       req.Type,
+		Experiment: experiment.ID,
+		Variant:    req.Variant,
+		Visitor:    visitor,
+		Query:      strings.TrimSpace(req.Query),
+		ProductIDs: req.ProductIDs,
+		Position:   req.Position,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if err := s.feedback.Record(ctx, feedback); err != nil {
+		return fmt.Errorf("failed to record search feedback: %w", err)
+	}
+	return nil
+}
+
+// ListExperiments returns the configured experiments
+func (s *ExperimentService) ListExperiments() []*domain.Experiment {
+	return s.experiments
+}
+
+// Report counts the impressions and clicks of each variant over the last days (1-90)
+func (s *ExperimentService) Report(ctx context.Context, experimentID string, days int) (*domain.ExperimentReport, error) {
+	experiment := s.find(experimentID)
+	if experiment == nil {
+		return nil, domain.ErrExperimentNotFound
+	}
+	if days < 1 || days > maxReportDays {
+		days = 7
+	}
+
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -days)
+	counts, err := s.feedback.CountByVariant(ctx, experiment.ID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count search feedback: %w", err)
+	}
+
+	report := &domain.ExperimentReport{Experiment: experiment, From: from, To: to}
+	for _, variant := range experiment.Variants {
+		stats := counts[variant.Name]
+		stats.Variant = variant.Name
+		if stats.Impressions > 0 {
+			stats.CTR = float64(stats.Clicks) / float64(stats.Impressions)
+		}
+		report.Variants = append(report.Variants, stats)
+	}
+	return report, nil
+}
+
+// enabled returns the running experiment, nil when none
+func (s *ExperimentService) enabled() *domain.Experiment {
+	for _, experiment := range s.experiments {
+		if experiment.Enabled {
+			return experiment
+		}
+	}
+	return nil
+}
+
+// find returns the experiment with the ID (enabled or not, so stopped experiments can
+// still be reported), nil when unknown
+func (s *ExperimentService) find(id string) *domain.Experiment {
+	for _, experiment := range s.experiments {
+		if experiment.ID == id {
+			return experiment
+		}
+	}
+	return nil
+}
+
+// assignVariant buckets the visitor by the FNV hash of experiment ID and visitor over the
+// variants' cumulative weights; salting with the experiment ID reshuffles visitors between
+// experiments
+func assignVariant(experiment *domain.Experiment, visitor string) *domain.RankingVariant {
+	total := 0
+	for _, variant := range experiment.Variants {
+		total += variant.Weight
+	}
+	if total <= 0 {
+		return &experiment.Variants[0] // Weights are checked by the configuration
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(experiment.ID + ":" + visitor))
+	bucket := int(h.Sum32() % uint32(total))
+	for i := range experiment.Variants {
+		bucket -= experiment.Variants[i].Weight
+		if bucket < 0 {
+			return &experiment.Variants[i]
+		}
+	}
+	return &experiment.Variants[len(experiment.Variants)-1]
+}
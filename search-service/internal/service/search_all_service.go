@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"search-service/internal/domain"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SearchAllService runs the universal search: products, shops and categories are searched
+// in parallel and returned grouped, for the search dropdown
+type SearchAllService struct {
+	searchService *SearchService
+	shops         domain.ShopSearcher
+	categories    domain.CategorySearcher
+	timeout       time.Duration
+	logger        *zap.Logger
+}
+
+// NewSearchAllService creates a new universal search service
+func NewSearchAllService(
+	searchService *SearchService,
+	shops domain.ShopSearcher,
+	categories domain.CategorySearcher,
+	timeout time.Duration,
+	logger *zap.Logger,
+) *SearchAllService {
+	return &SearchAllService{
+		searchService: searchService,
+		shops:         shops,
+		categories:    categories,
+		timeout:       timeout,
+		logger:        logger,
+	}
+}
+
+// groupOutcome is the result of searching one group
+type groupOutcome struct {
+	group  string
+	result *domain.SearchGroup
+	err    error
+}
+
+// SearchAll searches every group, or only req.Type to load more of it. A group that fails
+// or does not answer within the timeout is returned as unavailable so the others still
+// show; when a single group is requested its error is returned instead
+func (s *SearchAllService) SearchAll(ctx context.Context, req *domain.SearchAllRequest) (*domain.SearchAllResult, error) {
+	req.Query = strings.TrimSpace(req.Query)
+	if req.Query == "" {
+		return nil, domain.ErrSearchQueryRequired
+	}
+	if req.Type != "" && !slices.Contains(domain.SearchGroups, req.Type) {
+		return nil, domain.ErrUnknownSearchGroup
+	}
+	if req.Cursor != "" && req.Type == "" {
+		return nil, domain.ErrSearchGroupRequired
+	}
+	if req.Limit < 1 {
+		req.Limit = domain.DefaultSearchAllLimit
+	}
+	if req.Limit > domain.MaxSearchAllLimit {
+		req.Limit = domain.MaxSearchAllLimit
+	}
+
+	groups := domain.SearchGroups
+	if req.Type != "" {
+		groups = []string{req.Type}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	outcomes := make(chan groupOutcome, len(groups))
+	for _, group := range groups {
+		go func() {
+			result, err := s.searchGroup(ctx, group, req)
+			outcomes <- groupOutcome{group: group, result: result, err: err}
+		}()
+	}
+
+	results := make(map[string]*domain.SearchGroup, len(groups))
+collect:
+	for pending := len(groups); pending > 0; pending-- {
+		select {
+		case outcome := <-outcomes:
+			if outcome.err != nil {
+				if req.Type != "" {
+					return nil, outcome.err
+				}
+				s.logger.Warn("universal search group failed",
+					zap.String("group", outcome.group),
+					zap.String("query", req.Query),
+					zap.Error(outcome.err),
+				)
+				continue
+			}
+			results[outcome.group] = outcome.result
+		case <-ctx.Done():
+			if req.Type != "" {
+				return nil, fmt.Errorf("failed to search %s: %w", req.Type, ctx.Err())
+			}
+			s.logger.Warn("universal search timed out",
+				zap.String("query", req.Query),
+				zap.Int("pending_groups", pending),
+			)
+			break collect
+		}
+	}
+
+	result := &domain.SearchAllResult{Query: req.Query}
+	for _, group := range groups {
+		found, ok := results[group]
+		if !ok {
+			found = &domain.SearchGroup{Items: []struct{}{}, Unavailable: true}
+		}
+		switch group {
+		case domain.SearchGroupProducts:
+			result.Products = found
+		case domain.SearchGroupShops:
+			result.Shops = found
+		case domain.SearchGroupCategories:
+			result.Categories = found
+		}
+	}
+	return result, nil
+}
+
+func (s *SearchAllService) searchGroup(ctx context.Context, group string, req *domain.SearchAllRequest) (*domain.SearchGroup, error) {
+	switch group {
+	case domain.SearchGroupProducts:
+		// Same request as GET /search?q= so the cursor also pages the full results page
+		products, err := s.searchService.SearchProducts(ctx, &domain.SearchRequest{
+			Query:  req.Query,
+			Page:   1,
+			Limit:  req.Limit,
+			Cursor: req.Cursor,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &domain.SearchGroup{Items: products.Products, Total: products.Total, NextCursor: products.NextCursor}, nil
+	case domain.SearchGroupShops:
+		return s.shops.SearchShops(ctx, req.Query, req.Cursor, req.Limit)
+	default:
+		return s.categories.SearchCategories(ctx, req.Query, req.Cursor, req.Limit)
+	}
+}
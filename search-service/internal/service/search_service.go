@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"search-service/internal/domain"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -13,6 +15,9 @@ import (
 // Following Clean Architecture: business logic is independent of infrastructure
 type SearchService struct {
 	searchRepo domain.SearchRepository
+	cache      domain.SearchCache         // nil when search_cache is disabled
+	curator    domain.SearchCurator       // admin pinned products and boosted shops
+	ranking    domain.RankingExperimenter // ranking variants of A/B experiments
 	logger     *zap.Logger
 }
 
@@ -20,10 +25,16 @@ type SearchService struct {
 // Dependency injection: we inject all repositories and external services
 func NewSearchService(
 	searchRepo domain.SearchRepository,
+	cache domain.SearchCache,
+	curator domain.SearchCurator,
+	ranking domain.RankingExperimenter,
 	logger *zap.Logger,
 ) *SearchService {
 	return &SearchService{
 		searchRepo: searchRepo,
+		cache:      cache,
+		curator:    curator,
+		ranking:    ranking,
 		logger:     logger,
 	}
 }
@@ -45,6 +56,40 @@ func (s *SearchService) SearchProducts(ctx context.Context, req *domain.SearchRe
 	if req.Limit > 100 {
 		req.Limit = 100 // Max limit
 	}
+	if req.Cursor == "" && req.Page*req.Limit > domain.MaxSearchOffset {
+		return nil, domain.ErrSearchTooDeep
+	}
+	if err := normalizeLocation(req); err != nil {
+		return nil, err
+	}
+
+	// Admin curations only reorder results ranked by relevance
+	req.Curation = nil
+	if s.curator != nil && ordersByRelevance(req) {
+		req.Curation = s.curator.CurationFor(req, time.Now())
+	}
+
+	// Ranking experiments compare relevance rankings, so they only apply to text searches
+	// ranked by relevance; the variant's ranking is part of the cache key
+	var assignment *domain.ExperimentAssignment
+	req.Ranking = nil
+	if s.ranking != nil && ordersByRelevance(req) && strings.TrimSpace(req.Query) != "" {
+		assignment, req.Ranking = s.ranking.RankingFor(req.Visitor)
+	}
+
+	// First pages of popular searches come from the cache; a cache error falls
+	// through to Elasticsearch
+	cacheable := s.cache != nil && req.Page == 1 && req.Cursor == ""
+	if cacheable {
+		cached, err := s.cache.Get(ctx, req)
+		if err != nil {
+			s.logger.Warn("search cache lookup failed", zap.Error(err))
+		}
+		if cached != nil {
+			cached.Experiment = assignment
+			return cached, nil
+		}
+	}
 
 	// Perform search
 	result, err := s.searchRepo.SearchProducts(req)
@@ -56,6 +101,22 @@ func (s *SearchService) SearchProducts(ctx context.Context, req *domain.SearchRe
 		return nil, fmt.Errorf("failed to search products: %w", err)
 	}
 
+	// "Đã bán 1,2k" and "Giao trong 1-2 ngày" for product cards
+	for _, product := range result.Products {
+		product.SoldLabel = domain.SoldLabel(product.SoldCount)
+		if req.BuyerProvince != "" {
+			product.Delivery = domain.EstimateDelivery(product.ShipsFromProvince, req.BuyerProvince)
+		}
+	}
+
+	result.Experiment = assignment
+
+	if cacheable {
+		if err := s.cache.Store(ctx, req, result); err != nil {
+			s.logger.Warn("failed to cache search result", zap.Error(err))
+		}
+	}
+
 	s.logger.Info("search completed",
 		zap.String("query", req.Query),
 		zap.Int64("total", result.Total),
@@ -66,5 +127,40 @@ func (s *SearchService) SearchProducts(ctx context.Context, req *domain.SearchRe
 	return result, nil
 }
 
+// normalizeLocation resolves the provinces of the request to their codes (so a name and a
+// code share cache entries) and checks the buyer location the filters and sort need
+func normalizeLocation(req *domain.SearchRequest) error {
+	if req.BuyerProvince != "" {
+		province, ok := domain.LookupProvince(req.BuyerProvince)
+		if !ok {
+			return fmt.Errorf("%w: %q", domain.ErrUnknownProvince, req.BuyerProvince)
+		}
+		req.BuyerProvince = province.Code
+	}
 
+	if req.Filters != nil {
+		for i, value := range req.Filters.ShipsFrom {
+			province, ok := domain.LookupProvince(value)
+			if !ok {
+				return fmt.Errorf("%w: %q", domain.ErrUnknownProvince, value)
+			}
+			req.Filters.ShipsFrom[i] = province.Code
+		}
+		if req.Filters.MaxDeliveryDays != nil && req.BuyerProvince == "" {
+			return domain.ErrBuyerProvinceRequired
+		}
+	}
 
+	if req.Sort != nil && req.Sort.Field == domain.SortNearest {
+		location := req.BuyerLocation
+		if location == nil || location.Lat < -90 || location.Lat > 90 || location.Lon < -180 || location.Lon > 180 {
+			return domain.ErrBuyerLocationRequired
+		}
+	}
+	return nil
+}
+
+// ordersByRelevance reports whether the results are ranked by score (no explicit sort)
+func ordersByRelevance(req *domain.SearchRequest) bool {
+	return req.Sort == nil || req.Sort.Field == "" || req.Sort.Field == "_score"
+}
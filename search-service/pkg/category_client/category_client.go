@@ -0,0 +1,68 @@
+package category_client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CategoryClient handles communication with Product Service (categories)
+type CategoryClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewCategoryClient creates a new category client
+func NewCategoryClient(baseURL string, timeout time.Duration) *CategoryClient {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &CategoryClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// Category is the public category info from Product Service
+type Category struct {
+	ID       uint   `json:"id"`
+	ParentID *uint  `json:"parent_id"`
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+	ImageURL string `json:"image_url"`
+	IsActive bool   `json:"is_active"`
+}
+
+// ListCategories retrieves every category
+func (c *CategoryClient) ListCategories(ctx context.Context) ([]*Category, error) {
+	url := fmt.Sprintf("%s/api/v1/categories", c.baseURL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build product service request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call product service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("product service returned error: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var categories []*Category
+	if err := json.NewDecoder(resp.Body).Decode(&categories); err != nil {
+		return nil, fmt.Errorf("failed to decode categories: %w", err)
+	}
+	return categories, nil
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"search-service/config"
 	"strings"
 	"sync"
@@ -28,9 +30,13 @@ func GetClient(cfg *config.ElasticsearchConfig) (*elasticsearch.Client, error) {
 	once.Do(func() {
 		// Configure Elasticsearch client
 		esConfig := elasticsearch.Config{
-			Addresses: cfg.Addresses,
-			Username:  cfg.Username,
-			Password:  cfg.Password,
+			Addresses:    cfg.Addresses,
+			Username:     cfg.Username,
+			Password:     cfg.Password,
+			Transport:    newTransport(cfg),
+			MaxRetries:   cfg.MaxRetries,
+			DisableRetry: cfg.MaxRetries == 0, // the client treats 0 as "default" (3)
+			RetryBackoff: retryBackoff(cfg.RetryBackoff),
 		}
 
 		clientInstance, err = elasticsearch.NewClient(esConfig)
@@ -43,7 +49,8 @@ func GetClient(cfg *config.ElasticsearchConfig) (*elasticsearch.Client, error) {
 		ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
 		defer cancel()
 
-		res, err := clientInstance.Info(clientInstance.Info.WithContext(ctx))
+		var res *esapi.Response
+		res, err = clientInstance.Info(clientInstance.Info.WithContext(ctx))
 		if err != nil {
 			log.Printf("Failed to connect to Elasticsearch: %v", err)
 			return
@@ -66,6 +73,25 @@ func GetClient(cfg *config.ElasticsearchConfig) (*elasticsearch.Client, error) {
 	return clientInstance, nil
 }
 
+// newTransport bounds connecting to a node and waiting for a response, so a hung
+// node fails the request (and triggers a retry) instead of blocking it
+func newTransport(cfg *config.ElasticsearchConfig) http.RoundTripper {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: cfg.DialTimeout, KeepAlive: 30 * time.Second}).DialContext
+	transport.ResponseHeaderTimeout = cfg.Timeout
+	return transport
+}
+
+// retryBackoff doubles the wait after every failed attempt
+func retryBackoff(base time.Duration) func(attempt int) time.Duration {
+	if base <= 0 {
+		return nil
+	}
+	return func(attempt int) time.Duration {
+		return base * time.Duration(1<<(attempt-1))
+	}
+}
+
 // EnsureIndex creates the Elasticsearch index if it doesn't exist
 // This should be called at application startup
 func EnsureIndex(client *elasticsearch.Client, indexName string) error {
@@ -81,6 +107,7 @@ func EnsureIndex(client *elasticsearch.Client, indexName string) error {
 
 	if exists.StatusCode == 200 {
 		log.Printf("Index '%s' already exists", indexName)
+		ensureLocationMapping(ctx, client, indexName)
 		return nil
 	}
 
@@ -99,6 +126,84 @@ func EnsureIndex(client *elasticsearch.Client, indexName string) error {
 				"status": { "type": "keyword" },
 				"is_active": { "type": "boolean" },
 				"created_at": { "type": "date" },
+				"updated_at": { "type": "date" },
+				"sold_count": { "type": "long" },
+				"sold_30d": { "type": "long" },
+				"shop_id": { "type": "long" },
+				"ships_from_province": { "type": "keyword" },
+				"shop_location": { "type": "geo_point" }
+			}
+		}
+	}`
+
+	req := esapi.IndicesCreateRequest{
+		Index: indexName,
+		Body:  strings.NewReader(mapping),
+	}
+
+	res, err := req.Do(ctx, client)
+	if err != nil {
+		return fmt.Errorf("failed to create index: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch error creating index: %s", res.String())
+	}
+
+	log.Printf("Index '%s' created successfully", indexName)
+	return nil
+}
+
+// locationMapping holds the ships-from fields, added to indexes created before them
+const locationMapping = `{
+	"properties": {
+		"shop_id": { "type": "long" },
+		"ships_from_province": { "type": "keyword" },
+		"shop_location": { "type": "geo_point" }
+	}
+}`
+
+// ensureLocationMapping adds the ships-from fields to an existing index. It fails when a
+// product with a location was indexed before (shop_location was mapped dynamically as an
+// object); the index then has to be rebuilt for nearest-first sorting
+func ensureLocationMapping(ctx context.Context, client *elasticsearch.Client, indexName string) {
+	res, err := client.Indices.PutMapping([]string{indexName}, strings.NewReader(locationMapping), client.Indices.PutMapping.WithContext(ctx))
+	if err != nil {
+		log.Printf("Failed to add location mapping to index '%s': %v", indexName, err)
+		return
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		log.Printf("Failed to add location mapping to index '%s': %s", indexName, res.String())
+	}
+}
+
+// EnsureSalesIndex creates the order sales lines index if it doesn't exist
+func EnsureSalesIndex(client *elasticsearch.Client, indexName string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	exists, err := client.Indices.Exists([]string{indexName}, client.Indices.Exists.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to check index existence: %w", err)
+	}
+	defer exists.Body.Close()
+
+	if exists.StatusCode == 200 {
+		return nil
+	}
+
+	mapping := `{
+		"mappings": {
+			"properties": {
+				"order_id": { "type": "long" },
+				"product_id": { "type": "long" },
+				"product_item_id": { "type": "long" },
+				"quantity": { "type": "integer" },
+				"cancelled": { "type": "boolean" },
+				"ordered_at": { "type": "date" },
 				"updated_at": { "type": "date" }
 			}
 		}
@@ -123,5 +228,126 @@ func EnsureIndex(client *elasticsearch.Client, indexName string) error {
 	return nil
 }
 
+// CurationAuditMappings are the mappings of the curation audit index (also applied by
+// its lifecycle template). Audit snapshots are stored, not indexed
+const CurationAuditMappings = `{
+	"properties": {
+		"curation_id": { "type": "keyword" },
+		"action": { "type": "keyword" },
+		"before": { "type": "object", "enabled": false },
+		"after": { "type": "object", "enabled": false },
+		"changed_by": { "type": "long" },
+		"created_at": { "type": "date" }
+	}
+}`
 
+// EnsureCurationIndexes creates the search curations index and its audit index if they
+// don't exist (an audit alias created by EnsureTimeSeriesIndex counts as existing)
+func EnsureCurationIndexes(client *elasticsearch.Client, curationIndex, auditIndex string) error {
+	curationMapping := `{
+		"mappings": {
+			"properties": {
+				"keyword": { "type": "keyword" },
+				"campaign_id": { "type": "long" },
+				"pinned_product_ids": { "type": "long" },
+				"boosted_shop_ids": { "type": "long" },
+				"shop_boost": { "type": "float" },
+				"start_at": { "type": "date" },
+				"end_at": { "type": "date" },
+				"note": { "type": "text", "index": false },
+				"created_by": { "type": "long" },
+				"updated_by": { "type": "long" },
+				"created_at": { "type": "date" },
+				"updated_at": { "type": "date" }
+			}
+		}
+	}`
+	auditMapping := `{"mappings": ` + CurationAuditMappings + `}`
 
+	if err := createIndexIfMissing(client, curationIndex, curationMapping); err != nil {
+		return err
+	}
+	return createIndexIfMissing(client, auditIndex, auditMapping)
+}
+
+// SearchFeedbackMappings are the mappings of the search feedback index (also applied by its
+// lifecycle template)
+const SearchFeedbackMappings = `{
+	"properties": {
+		"type": { "type": "keyword" },
+		"experiment": { "type": "keyword" },
+		"variant": { "type": "keyword" },
+		"visitor": { "type": "keyword" },
+		"query": { "type": "keyword", "ignore_above": 200 },
+		"product_ids": { "type": "long" },
+		"position": { "type": "integer" },
+		"created_at": { "type": "date" }
+	}
+}`
+
+// EnsureFeedbackIndex creates the search feedback index if it doesn't exist (an alias
+// created by EnsureTimeSeriesIndex counts as existing)
+func EnsureFeedbackIndex(client *elasticsearch.Client, index string) error {
+	return createIndexIfMissing(client, index, `{"mappings": `+SearchFeedbackMappings+`}`)
+}
+
+func createIndexIfMissing(client *elasticsearch.Client, indexName, mapping string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	exists, err := client.Indices.Exists([]string{indexName}, client.Indices.Exists.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to check index existence: %w", err)
+	}
+	defer exists.Body.Close()
+
+	if exists.StatusCode == 200 {
+		return nil
+	}
+
+	req := esapi.IndicesCreateRequest{
+		Index: indexName,
+		Body:  strings.NewReader(mapping),
+	}
+
+	res, err := req.Do(ctx, client)
+	if err != nil {
+		return fmt.Errorf("failed to create index: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch error creating index %s: %s", indexName, res.String())
+	}
+
+	log.Printf("Index '%s' created successfully", indexName)
+	return nil
+}
+
+// SwapAlias points alias at index only (used after bootstrapping a new index)
+func SwapAlias(ctx context.Context, client *elasticsearch.Client, alias, index string) error {
+	actions := fmt.Sprintf(`{"actions":[{"remove":{"index":"*","alias":%q}},{"add":{"index":%q,"alias":%q}}]}`, alias, index, alias)
+	res, err := client.Indices.UpdateAliases(strings.NewReader(actions), client.Indices.UpdateAliases.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to update alias: %w", err)
+	}
+	defer res.Body.Close()
+
+	// "remove" fails with 404 when the alias does not exist yet; retry with add only
+	if res.StatusCode == 404 {
+		addOnly := fmt.Sprintf(`{"actions":[{"add":{"index":%q,"alias":%q}}]}`, index, alias)
+		res2, err := client.Indices.UpdateAliases(strings.NewReader(addOnly), client.Indices.UpdateAliases.WithContext(ctx))
+		if err != nil {
+			return fmt.Errorf("failed to add alias: %w", err)
+		}
+		defer res2.Body.Close()
+		if res2.IsError() {
+			return fmt.Errorf("elasticsearch error adding alias: %s", res2.String())
+		}
+		return nil
+	}
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch error updating alias: %s", res.String())
+	}
+	return nil
+}
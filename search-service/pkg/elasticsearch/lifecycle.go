@@ -0,0 +1,154 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"search-service/config"
+	"strings"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+)
+
+// EnsureTimeSeriesIndex puts the ILM policy and index template of a time-series index and
+// creates its first index behind the write alias if the alias doesn't exist. Writes and
+// searches go through the alias (cfg.Name); Elasticsearch rolls it over and deletes old
+// indices. mappings is the mappings object of the indices, empty for dynamic mapping.
+// Policy and template are updated on every start, so configuration changes apply to the
+// next rolled-over index
+func EnsureTimeSeriesIndex(client *elasticsearch.Client, cfg *config.LifecycleIndexConfig, mappings string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	policyName := cfg.Name + "-policy"
+	if err := putLifecyclePolicy(ctx, client, policyName, cfg); err != nil {
+		return err
+	}
+	if err := putTimeSeriesTemplate(ctx, client, cfg.Name, policyName, mappings); err != nil {
+		return err
+	}
+
+	aliasExists, err := client.Indices.ExistsAlias([]string{cfg.Name}, client.Indices.ExistsAlias.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to check alias existence: %w", err)
+	}
+	defer aliasExists.Body.Close()
+	if aliasExists.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	// An index named like the alias was created before the index was managed
+	indexExists, err := client.Indices.Exists([]string{cfg.Name}, client.Indices.Exists.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to check index existence: %w", err)
+	}
+	defer indexExists.Body.Close()
+	if indexExists.StatusCode == http.StatusOK {
+		return fmt.Errorf("index '%s' exists without lifecycle; reindex it into '%s-000001' and delete it to manage it", cfg.Name, cfg.Name)
+	}
+
+	firstIndex := cfg.Name + "-000001"
+	body := fmt.Sprintf(`{"aliases":{%q:{"is_write_index":true}}}`, cfg.Name)
+	req := esapi.IndicesCreateRequest{
+		Index: firstIndex,
+		Body:  strings.NewReader(body),
+	}
+
+	res, err := req.Do(ctx, client)
+	if err != nil {
+		return fmt.Errorf("failed to create index: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch error creating index %s: %s", firstIndex, res.String())
+	}
+
+	log.Printf("Index '%s' created successfully behind alias '%s'", firstIndex, cfg.Name)
+	return nil
+}
+
+// putLifecyclePolicy rolls the write index over by size and/or age, and deletes indices
+// cfg.DeleteAfter after their rollover
+func putLifecyclePolicy(ctx context.Context, client *elasticsearch.Client, policyName string, cfg *config.LifecycleIndexConfig) error {
+	rollover := map[string]interface{}{}
+	if cfg.RolloverMaxSize != "" {
+		rollover["max_primary_shard_size"] = cfg.RolloverMaxSize
+	}
+	if cfg.RolloverMaxAge != "" {
+		rollover["max_age"] = cfg.RolloverMaxAge
+	}
+
+	phases := map[string]interface{}{
+		"hot": map[string]interface{}{
+			"actions": map[string]interface{}{"rollover": rollover},
+		},
+	}
+	if cfg.DeleteAfter != "" {
+		phases["delete"] = map[string]interface{}{
+			"min_age": cfg.DeleteAfter,
+			"actions": map[string]interface{}{"delete": map[string]interface{}{}},
+		}
+	}
+
+	policy, err := json.Marshal(map[string]interface{}{
+		"policy": map[string]interface{}{"phases": phases},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal lifecycle policy: %w", err)
+	}
+
+	res, err := client.ILM.PutLifecycle(policyName,
+		client.ILM.PutLifecycle.WithBody(bytes.NewReader(policy)),
+		client.ILM.PutLifecycle.WithContext(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to put lifecycle policy: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch error putting lifecycle policy %s: %s", policyName, res.String())
+	}
+	return nil
+}
+
+// putTimeSeriesTemplate applies the policy and mappings to every index of the alias
+func putTimeSeriesTemplate(ctx context.Context, client *elasticsearch.Client, alias, policyName, mappings string) error {
+	template := map[string]interface{}{
+		"settings": map[string]interface{}{
+			"index.lifecycle.name":           policyName,
+			"index.lifecycle.rollover_alias": alias,
+		},
+	}
+	if mappings != "" {
+		template["mappings"] = json.RawMessage(mappings)
+	}
+
+	body, err := json.Marshal(map[string]interface{}{
+		"index_patterns": []string{alias + "-*"},
+		"priority":       200, // above the built-in templates matching the same names
+		"template":       template,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal index template: %w", err)
+	}
+
+	res, err := client.Indices.PutIndexTemplate(alias, bytes.NewReader(body),
+		client.Indices.PutIndexTemplate.WithContext(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to put index template: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch error putting index template %s: %s", alias, res.String())
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package shop_client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ShopClient handles communication with Identity Service (shops)
+type ShopClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewShopClient creates a new shop client
+func NewShopClient(baseURL string, timeout time.Duration) *ShopClient {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &ShopClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// Shop is the public shop info from Identity Service
+type Shop struct {
+	ID         uint    `json:"id"`
+	Name       string  `json:"name"`
+	Slug       string  `json:"slug"`
+	LogoURL    string  `json:"logo_url"`
+	IsOfficial bool    `json:"is_official"`
+	Rating     float64 `json:"rating"`
+	Province   string  `json:"province"`
+}
+
+// SearchResult is one page of shops matching a search
+type SearchResult struct {
+	Shops      []*Shop `json:"shops"`
+	Total      int64   `json:"total"`
+	NextCursor string  `json:"next_cursor"`
+}
+
+// SearchShops finds active shops by name; cursor is the next_cursor of the previous page
+func (c *ShopClient) SearchShops(ctx context.Context, query, cursor string, limit int) (*SearchResult, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("limit", strconv.Itoa(limit))
+	if cursor != "" {
+		params.Set("cursor", cursor)
+	}
+	endpoint := fmt.Sprintf("%s/api/v1/shops/search?%s", c.baseURL, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build identity service request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call identity service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("identity service returned error: %d - %s", resp.StatusCode, string(body))
+	}
+
+	var result SearchResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode shops: %w", err)
+	}
+	return &result, nil
+}
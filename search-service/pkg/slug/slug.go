@@ -0,0 +1,41 @@
+package slug
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Make generates a URL-friendly slug from a (Vietnamese) name, the same way identity-service
+// and product-service slug shops and categories
+// Example: "Cửa Hàng Điện Tử Minh Anh" -> "cua-hang-dien-tu-minh-anh"
+// Diacritics are stripped by decomposing to NFD and dropping combining marks,
+// "đ"/"Đ" are mapped manually because they are not composed characters
+func Make(name string) string {
+	decomposed := norm.NFD.String(strings.ToLower(strings.TrimSpace(name)))
+
+	var result strings.Builder
+	lastDash := true // Avoid leading dash
+	for _, r := range decomposed {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Combining mark (dấu) - drop it
+			continue
+		case r == 'đ' || r == 'Đ':
+			result.WriteRune('d')
+			lastDash = false
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
+			result.WriteRune(r)
+			lastDash = false
+		default:
+			// Spaces, underscores, punctuation -> single dash
+			if !lastDash {
+				result.WriteRune('-')
+				lastDash = true
+			}
+		}
+	}
+
+	return strings.TrimSuffix(result.String(), "-")
+}